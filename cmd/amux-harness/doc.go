@@ -10,7 +10,9 @@
 // frames), -warmup (warmup frames to ignore), -width, -height, -keymap-hints,
 // -dump-frame (write the final rendered view as raw ANSI bytes to a path — the
 // exact frame an agent sees; `cat`/diff it to inspect, or feed it into a golden),
-// -assert-min-visible (fail if the final frame has fewer than N visible glyphs).
+// -assert-min-visible (fail if the final frame has fewer than N visible glyphs),
+// -assert-max-allocs (fail if Render averages more than N heap allocations per
+// measured frame). Every run also reports allocs_per_frame and bytes_per_frame.
 //
 // Set AMUX_PPROF=1/true, a port, or a listen address to start net/http/pprof
 // (default 127.0.0.1:6060 for 1/true). Fetch CPU profiles from the pprof
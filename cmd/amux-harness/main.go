@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"os"
 	"regexp"
+	"runtime"
 	"sort"
 	"time"
 	"unicode"
@@ -59,6 +60,7 @@ func main() {
 	showKeymapHints := flag.Bool("keymap-hints", false, "render keymap hints")
 	overlay := flag.String("overlay", "", "render an overlay over the base pane: dialog, settings, prefix, error, or input (empty renders base pane only)")
 	minVisible := flag.Int("assert-min-visible", 0, "fail (exit 1) if the final rendered frame has fewer than this many visible glyphs; 0 disables. Guards against renders that produce empty/garbage frames without crashing.")
+	maxAllocs := flag.Float64("assert-max-allocs", 0, "fail (exit 1) if measured frames average more than this many heap allocations in Render; 0 disables. Guards the cached chrome paths against regressing to per-frame style rebuilds.")
 	dumpFrame := flag.String("dump-frame", "", "write the final rendered view (full ANSI) to this path; empty disables. Lets callers diff/golden the exact frame an agent sees.")
 	flag.Parse()
 
@@ -91,12 +93,21 @@ func main() {
 
 	var lastVisible int
 	var lastContent string
+	var allocs renderAllocs
+	var before, after runtime.MemStats
 	for i := 0; i < totalFrames; i++ {
 		h.Step(i)
+		measured := i >= *warmup
+		if measured {
+			runtime.ReadMemStats(&before)
+		}
 		start := time.Now()
 		view := h.Render()
-		if i >= *warmup {
-			durations = append(durations, time.Since(start))
+		elapsed := time.Since(start)
+		if measured {
+			runtime.ReadMemStats(&after)
+			allocs.add(&before, &after)
+			durations = append(durations, elapsed)
 			lastVisible = visibleRuneCount(view.Content)
 			lastContent = view.Content
 		}
@@ -115,15 +126,49 @@ func main() {
 		os.Exit(1)
 	}
 
+	if *maxAllocs > 0 && allocs.perFrame() > *maxAllocs {
+		fmt.Fprintf(os.Stderr, "harness: render averaged %.1f allocs/frame, want <= %.1f\n",
+			allocs.perFrame(), *maxAllocs)
+		os.Exit(1)
+	}
+
 	total := time.Since(startAll)
 	s := summarize(durations)
 	fmt.Printf("mode=%s tabs=%d frames=%d warmup=%d size=%dx%d hot_tabs=%d payload=%dB newline_every=%d\n",
 		*mode, *tabs, *frames, *warmup, *width, *height, *hotTabs, *payloadBytes, *newlineEvery)
 	fmt.Printf("total=%s avg=%s p50=%s p95=%s p99=%s min=%s max=%s fps=%.2f\n",
 		total, s.avg, s.p50, s.p95, s.p99, s.min, s.max, fps(durations))
+	fmt.Printf("allocs_per_frame=%.1f bytes_per_frame=%.0f\n", allocs.perFrame(), allocs.bytesPerFrame())
 	perf.Flush("harness")
 }
 
+// renderAllocs accumulates heap allocations made inside measured Render calls.
+type renderAllocs struct {
+	frames  uint64
+	mallocs uint64
+	bytes   uint64
+}
+
+func (r *renderAllocs) add(before, after *runtime.MemStats) {
+	r.frames++
+	r.mallocs += after.Mallocs - before.Mallocs
+	r.bytes += after.TotalAlloc - before.TotalAlloc
+}
+
+func (r renderAllocs) perFrame() float64 {
+	if r.frames == 0 {
+		return 0
+	}
+	return float64(r.mallocs) / float64(r.frames)
+}
+
+func (r renderAllocs) bytesPerFrame() float64 {
+	if r.frames == 0 {
+		return 0
+	}
+	return float64(r.bytes) / float64(r.frames)
+}
+
 func writeDumpFrame(path, content string) error {
 	return os.WriteFile(path, []byte(content), 0o600)
 }
@@ -0,0 +1,230 @@
+//go:build !windows
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/andyrewlee/amux/internal/config"
+	"github.com/andyrewlee/amux/internal/data"
+	"github.com/andyrewlee/amux/internal/pty"
+	"github.com/andyrewlee/amux/internal/sessions"
+	"github.com/andyrewlee/amux/internal/tmux"
+	"github.com/andyrewlee/amux/internal/validation"
+)
+
+const agentUsage = "usage: amux agent list [--workspace <name|path>] [--json] | amux agent launch <workspace> [--assistant <name>] [--prompt <text>] [--json] | amux agent stop <name> [--dry-run] [--yes] [--verbose] [--json]"
+
+// Test seams for starting and stopping agent sessions.
+var (
+	startDetachedAgent = func(cfg *config.Config, ws *data.Workspace, assistant, session, prompt string, tags tmux.SessionTags) error {
+		return pty.NewAgentManager(cfg).StartDetachedAgent(ws, pty.AgentType(assistant), session, prompt, tags)
+	}
+	killSession = tmux.KillSession
+)
+
+// runAgent lists, launches, or stops agents and returns the process exit
+// code. Agents run in tmux sessions like the ones amux starts for agent
+// tabs, and amux picks them up as tabs of their worktrees.
+func runAgent(args []string, out io.Writer) int {
+	if len(args) == 0 {
+		fmt.Fprintln(os.Stderr, agentUsage)
+		return 2
+	}
+	switch args[0] {
+	case "ls", "list":
+		return runAgentList(args[1:], out)
+	case "launch":
+		return runAgentLaunch(args[1:], out)
+	case "stop":
+		return runAgentStop(args[1:], out)
+	}
+	fmt.Fprintln(os.Stderr, agentUsage)
+	return 2
+}
+
+func runAgentList(args []string, out io.Writer) int {
+	fs := flag.NewFlagSet("agent list", flag.ContinueOnError)
+	fs.SetOutput(os.Stderr)
+	asJSON := fs.Bool("json", false, "print the agents as JSON")
+	workspace := fs.String("workspace", "", "only list the agents in this worktree")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() != 0 {
+		fmt.Fprintln(os.Stderr, agentUsage)
+		return 2
+	}
+	cfg, err := config.DefaultConfig()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	store := data.NewWorkspaceStore(cfg.Paths.MetadataRoot)
+	var wsID string
+	if *workspace != "" {
+		ws, err := findWorkspace(store, nil, *workspace)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		wsID = string(ws.ID())
+	}
+	list, err := sessions.List(tmux.DefaultOptions(), store)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	agents := agentSessions(list, wsID)
+	if *asJSON {
+		err = writeSessionsJSON(out, agents)
+	} else if len(agents) == 0 {
+		_, err = fmt.Fprintln(out, "No agents running.")
+	} else {
+		err = writeSessions(out, agents)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	return 0
+}
+
+// agentSessions keeps the agent sessions in list, only those of the
+// workspace wsID when it is set.
+func agentSessions(list []sessions.Session, wsID string) []sessions.Session {
+	var agents []sessions.Session
+	for _, s := range list {
+		if s.Type == "agent" && (wsID == "" || s.WorkspaceID == wsID) {
+			agents = append(agents, s)
+		}
+	}
+	return agents
+}
+
+func runAgentLaunch(args []string, out io.Writer) int {
+	fs := flag.NewFlagSet("agent launch", flag.ContinueOnError)
+	fs.SetOutput(os.Stderr)
+	asJSON := fs.Bool("json", false, "print the launched agent as JSON")
+	assistant := fs.String("assistant", "", "assistant to launch (default: the worktree's, then the configured default)")
+	prompt := fs.String("prompt", "", "prompt to start the agent with")
+	name, ok := parseNamed(fs, args, agentUsage)
+	if !ok {
+		return 2
+	}
+	cfg, err := config.DefaultConfig()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	store := data.NewWorkspaceStore(cfg.Paths.MetadataRoot)
+	ws, err := findWorkspace(store, nil, name)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	s, err := launchAgent(cfg, store, ws, *assistant, *prompt, time.Now())
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	if *asJSON {
+		err = writeSessionJSON(out, s)
+	} else {
+		_, err = fmt.Fprintf(out, "Launched %s in %s as %s.\n", s.Assistant, ws.Name, s.Name)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	return 0
+}
+
+// launchAgent starts assistant in a detached tmux session tagged as an agent
+// tab of ws, and returns the session as `amux agent list` shows it.
+func launchAgent(cfg *config.Config, store *data.WorkspaceStore, ws *data.Workspace, assistant, prompt string, now time.Time) (sessions.Session, error) {
+	if assistant == "" {
+		assistant = ws.Assistant
+	}
+	if assistant == "" {
+		assistant = cfg.ResolvedDefaultAssistant()
+	}
+	if err := validation.ValidateAssistant(assistant); err != nil {
+		return sessions.Session{}, err
+	}
+	if !cfg.IsAssistantKnown(assistant) {
+		return sessions.Session{}, fmt.Errorf("unknown assistant %q", assistant)
+	}
+	tabID := "cli-" + strconv.FormatInt(now.UnixNano(), 36)
+	session := tmux.SessionName("amux", string(ws.ID()), tabID)
+	tags := tmux.SessionTags{
+		WorkspaceID: string(ws.ID()),
+		TabID:       tabID,
+		Type:        "agent",
+		Assistant:   assistant,
+		CreatedAt:   now.Unix(),
+	}
+	if err := startDetachedAgent(cfg, ws, assistant, session, prompt, tags); err != nil {
+		return sessions.Session{}, err
+	}
+	fallback := sessions.Session{
+		Name: session, TmuxSession: session, Type: "agent", Assistant: assistant,
+		WorkspaceID: string(ws.ID()), CreatedAt: time.Unix(now.Unix(), 0),
+	}
+	list, err := sessions.List(tmux.DefaultOptions(), store)
+	if err != nil {
+		return fallback, nil
+	}
+	if s, err := sessions.Find(list, session); err == nil {
+		return s, nil
+	}
+	return fallback, nil
+}
+
+func runAgentStop(args []string, out io.Writer) int {
+	fs := flag.NewFlagSet("agent stop", flag.ContinueOnError)
+	fs.SetOutput(os.Stderr)
+	flags := addPlanFlags(fs)
+	name, ok := parseNamed(fs, args, agentUsage)
+	if !ok {
+		return 2
+	}
+	list, err := listSessions()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	s, err := sessions.Find(list, name)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v; `amux agent list` lists them\n", err)
+		return 1
+	}
+	if s.Type != "agent" {
+		fmt.Fprintf(os.Stderr, "%s is a %s session, not an agent\n", s.Name, s.Type)
+		return 1
+	}
+	plan := &destructivePlan{Command: "agent stop"}
+	plan.add("kill_session", s.TmuxSession)
+	if proceed, err := flags.run(out, plan); !proceed {
+		return planExitCode(err)
+	}
+	if err := killSession(s.TmuxSession, tmux.DefaultOptions()); err != nil {
+		fmt.Fprintf(os.Stderr, "stop %s: %v\n", s.Name, err)
+		return 1
+	}
+	if *flags.json {
+		err = writeSessionJSON(out, s)
+	} else {
+		_, err = fmt.Fprintf(out, "Stopped %s.\n", s.Name)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	return 0
+}
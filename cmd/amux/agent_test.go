@@ -0,0 +1,64 @@
+//go:build !windows
+
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/andyrewlee/amux/internal/config"
+	"github.com/andyrewlee/amux/internal/data"
+	"github.com/andyrewlee/amux/internal/sessions"
+	"github.com/andyrewlee/amux/internal/tmux"
+)
+
+func TestLaunchAgent(t *testing.T) {
+	t.Setenv("AMUX_TMUX_SERVER", "amux-test-agent-launch-"+strings.ReplaceAll(t.Name(), "/", "-"))
+	cfg := &config.Config{Assistants: map[string]config.AssistantConfig{"claude": {Command: "claude"}, "codex": {Command: "codex"}}}
+	store := data.NewWorkspaceStore(t.TempDir())
+	ws := data.NewWorkspace("feature", "feature", "main", "/repo", "/repo/feature")
+	ws.Assistant = "codex"
+
+	var gotSession, gotPrompt string
+	var gotTags tmux.SessionTags
+	orig := startDetachedAgent
+	startDetachedAgent = func(_ *config.Config, _ *data.Workspace, assistant, session, prompt string, tags tmux.SessionTags) error {
+		gotSession, gotPrompt, gotTags = session, prompt, tags
+		return nil
+	}
+	t.Cleanup(func() { startDetachedAgent = orig })
+
+	now := time.Unix(1700000000, 0)
+	s, err := launchAgent(cfg, store, ws, "", "fix the tests", now)
+	if err != nil {
+		t.Fatalf("launchAgent: %v", err)
+	}
+	if s.Assistant != "codex" || s.TmuxSession != gotSession || s.WorkspaceID != string(ws.ID()) || s.Type != "agent" {
+		t.Fatalf("session = %+v, want the worktree's assistant in %s", s, gotSession)
+	}
+	if gotPrompt != "fix the tests" || gotTags.Type != "agent" || gotTags.WorkspaceID != string(ws.ID()) || gotTags.CreatedAt != now.Unix() {
+		t.Fatalf("prompt = %q, tags = %+v", gotPrompt, gotTags)
+	}
+	if !strings.HasPrefix(gotSession, "amux-"+string(ws.ID())+"-cli-") {
+		t.Fatalf("session name = %q", gotSession)
+	}
+
+	if _, err := launchAgent(cfg, store, ws, "nope", "", now); err == nil {
+		t.Fatal("expected an unknown assistant to be refused")
+	}
+}
+
+func TestAgentSessions(t *testing.T) {
+	list := []sessions.Session{
+		{Name: "a", Type: "agent", WorkspaceID: "w1"},
+		{Name: "b", Type: "terminal", WorkspaceID: "w1"},
+		{Name: "c", Type: "agent", WorkspaceID: "w2"},
+	}
+	if got := agentSessions(list, ""); len(got) != 2 {
+		t.Fatalf("agents = %+v", got)
+	}
+	if got := agentSessions(list, "w2"); len(got) != 1 || got[0].Name != "c" {
+		t.Fatalf("w2 agents = %+v", got)
+	}
+}
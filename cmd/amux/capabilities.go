@@ -0,0 +1,152 @@
+//go:build !windows
+
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/andyrewlee/amux/internal/config"
+	"github.com/andyrewlee/amux/internal/data"
+	"github.com/andyrewlee/amux/internal/feed"
+	"github.com/andyrewlee/amux/internal/forge"
+	"github.com/andyrewlee/amux/internal/limits"
+	"github.com/andyrewlee/amux/internal/notify"
+	"github.com/andyrewlee/amux/internal/sandbox"
+)
+
+const capabilitiesUsage = "usage: amux capabilities"
+
+// capabilitiesSchemaVersion is bumped when a field of capabilities is renamed
+// or removed, or changes meaning. Adding a field, command, or name does not
+// bump it.
+const capabilitiesSchemaVersion = 1
+
+// capabilities is what `amux capabilities` prints, for integrations to check
+// what this amux supports instead of parsing help text.
+type capabilities struct {
+	SchemaVersion int                 `json:"schema_version"`
+	Version       string              `json:"version"`
+	GlobalFlags   []capabilityFlag    `json:"global_flags"`
+	Commands      []capabilityCommand `json:"commands"`
+	// Events lists the event names by where they appear: notification
+	// toggles, the activity feed, and worktree history.
+	Events map[string][]string `json:"events"`
+	// Providers lists the assistants configured and the backends amux
+	// knows for notifications, sandboxing, and resource limits.
+	Providers map[string][]string `json:"providers"`
+}
+
+type capabilityCommand struct {
+	Name  string           `json:"name"`
+	Args  []string         `json:"args,omitempty"`
+	Flags []capabilityFlag `json:"flags,omitempty"`
+	// JSON is whether the command can print JSON, with --json unless the
+	// command prints nothing else.
+	JSON bool `json:"json"`
+}
+
+type capabilityFlag struct {
+	Name string `json:"name"`
+	Type string `json:"type"` // bool, string, or int
+}
+
+var (
+	jsonFlag      = capabilityFlag{Name: "json", Type: "bool"}
+	workspaceFlag = capabilityFlag{Name: "workspace", Type: "string"}
+	// planCapabilityFlags are the flags of destructive commands; see addPlanFlags.
+	planCapabilityFlags = []capabilityFlag{
+		{Name: "dry-run", Type: "bool"}, {Name: "yes", Type: "bool"}, {Name: "verbose", Type: "bool"}, jsonFlag,
+	}
+)
+
+// capabilityCommands describes the subcommands main dispatches. Keep it in
+// step with their flag sets.
+var capabilityCommands = []capabilityCommand{
+	{Name: "agent launch", Args: []string{"workspace"}, Flags: []capabilityFlag{{Name: "assistant", Type: "string"}, {Name: "prompt", Type: "string"}, jsonFlag}, JSON: true},
+	{Name: "agent list", Flags: []capabilityFlag{workspaceFlag, jsonFlag}, JSON: true},
+	{Name: "agent stop", Args: []string{"name"}, Flags: planCapabilityFlags, JSON: true},
+	{Name: "capabilities", JSON: true},
+	{Name: "changelog", Args: []string{"repo"}, Flags: []capabilityFlag{{Name: "write", Type: "bool"}}},
+	{Name: "doctor", Flags: []capabilityFlag{{Name: "repair", Type: "bool"}}},
+	{Name: "editor serve"},
+	{Name: "editor socket"},
+	{Name: "import", Args: []string{"format", "file"}, Flags: []capabilityFlag{
+		{Name: "root", Type: "string"}, {Name: "force", Type: "bool"}, {Name: "dry-run", Type: "bool"}, jsonFlag,
+	}, JSON: true},
+	{Name: "llm"},
+	{Name: "logs", Flags: []capabilityFlag{{Name: "audit", Type: "bool"}, {Name: "n", Type: "int"}}},
+	{Name: "schedule history", Flags: []capabilityFlag{{Name: "n", Type: "int"}}},
+	{Name: "schedule list"},
+	{Name: "schedule run"},
+	{Name: "session attach", Args: []string{"name"}},
+	{Name: "session ls", Flags: []capabilityFlag{jsonFlag}, JSON: true},
+	{Name: "share", Args: []string{"session"}, Flags: []capabilityFlag{{Name: "addr", Type: "string"}, {Name: "cert", Type: "string"}, {Name: "key", Type: "string"}, {Name: "write", Type: "bool"}}},
+	{Name: "status", Flags: []capabilityFlag{{Name: "report", Type: "string"}}},
+	{Name: "sync"},
+	{Name: "tab list", Flags: []capabilityFlag{workspaceFlag, jsonFlag}, JSON: true},
+	{Name: "toggle", Flags: []capabilityFlag{jsonFlag}, JSON: true},
+	{Name: "workspace activate", Args: []string{"workspace"}},
+	{Name: "workspace delete", Args: []string{"workspace"}, Flags: planCapabilityFlags, JSON: true},
+	{Name: "workspace history", Args: []string{"workspace"}, Flags: []capabilityFlag{jsonFlag}, JSON: true},
+}
+
+// runCapabilities prints the capabilities as JSON and returns the process
+// exit code.
+func runCapabilities(args []string, out io.Writer) int {
+	fs := flag.NewFlagSet("capabilities", flag.ContinueOnError)
+	fs.SetOutput(os.Stderr)
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() != 0 {
+		fmt.Fprintln(os.Stderr, capabilitiesUsage)
+		return 2
+	}
+	cfg, err := config.DefaultConfig()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	enc := json.NewEncoder(out)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(buildCapabilities(cfg)); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	return 0
+}
+
+func buildCapabilities(cfg *config.Config) capabilities {
+	assistants := cfg.AssistantNames()
+	if assistants == nil {
+		assistants = []string{}
+	}
+	return capabilities{
+		SchemaVersion: capabilitiesSchemaVersion,
+		Version:       version,
+		GlobalFlags:   []capabilityFlag{{Name: "profile", Type: "string"}, {Name: "version", Type: "bool"}},
+		Commands:      capabilityCommands,
+		Events: map[string][]string{
+			"notify": {string(notify.EventAttention), string(notify.EventDone), string(notify.EventExit)},
+			"feed": {
+				string(feed.KindOutput), string(feed.KindDone), string(feed.KindAttention),
+				string(feed.KindExit), string(feed.KindGit), string(feed.KindTask),
+			},
+			"history": {
+				data.HistoryCreated, data.HistoryAgentLaunched, data.HistoryAgentExited,
+				data.HistoryCommit, data.HistoryPROpened, data.HistoryDeleted,
+			},
+		},
+		Providers: map[string][]string{
+			"assistants": assistants,
+			"notify":     {string(notify.Bell), string(notify.Terminal), "desktop"},
+			"sandbox":    {string(sandbox.SandboxExec), string(sandbox.Bubblewrap)},
+			"limits":     {string(limits.Rlimit), string(limits.Systemd)},
+			"forge":      {string(forge.GitHub), string(forge.GitLab), string(forge.Bitbucket)},
+		},
+	}
+}
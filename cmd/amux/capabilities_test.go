@@ -0,0 +1,49 @@
+//go:build !windows
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/andyrewlee/amux/internal/config"
+)
+
+func TestBuildCapabilities(t *testing.T) {
+	cfg := &config.Config{Assistants: map[string]config.AssistantConfig{"claude": {Command: "claude"}}}
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(buildCapabilities(cfg)); err != nil {
+		t.Fatal(err)
+	}
+	var got capabilities
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatal(err)
+	}
+	if got.SchemaVersion != capabilitiesSchemaVersion {
+		t.Fatalf("schema_version = %d", got.SchemaVersion)
+	}
+	if !slices.Equal(got.Providers["assistants"], []string{"claude"}) {
+		t.Fatalf("assistants = %v", got.Providers["assistants"])
+	}
+	for _, key := range []string{"notify", "feed", "history"} {
+		if len(got.Events[key]) == 0 {
+			t.Errorf("no %s events", key)
+		}
+	}
+
+	// Every command is one main dispatches, and a command printing JSON on
+	// request takes --json.
+	usage := unsupportedInvocationMessage("x")
+	for _, c := range got.Commands {
+		top, _, _ := strings.Cut(c.Name, " ")
+		if !strings.Contains(usage, "`amux "+top) {
+			t.Errorf("%s is not a subcommand amux lists", c.Name)
+		}
+		if slices.Contains(c.Flags, jsonFlag) && !c.JSON {
+			t.Errorf("%s takes --json but is not marked as printing JSON", c.Name)
+		}
+	}
+}
@@ -0,0 +1,60 @@
+//go:build !windows
+
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/andyrewlee/amux/internal/changelog"
+	"github.com/andyrewlee/amux/internal/git"
+)
+
+const changelogUsage = "usage: amux changelog [--write] [repo]"
+
+// runChangelog prints a draft changelog entry for a repository's commits
+// since its last tag, or writes it to the repository's CHANGELOG.md, and
+// returns the process exit code.
+func runChangelog(args []string, out io.Writer) int {
+	fs := flag.NewFlagSet("changelog", flag.ContinueOnError)
+	fs.SetOutput(os.Stderr)
+	write := fs.Bool("write", false, "add the entry to CHANGELOG.md instead of printing it")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() > 1 {
+		fmt.Fprintln(os.Stderr, changelogUsage)
+		return 2
+	}
+	dir := "."
+	if fs.NArg() == 1 {
+		dir = fs.Arg(0)
+	}
+	ctx := context.Background()
+	root, err := git.RunGitCtx(ctx, dir, "rev-parse", "--show-toplevel")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s is not in a git repository\n", dir)
+		return 1
+	}
+	entry, err := changelog.Collect(ctx, root)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	if !*write {
+		if _, err := io.WriteString(out, entry.Markdown()); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		return 0
+	}
+	if err := changelog.Write(root, entry.Markdown()); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	fmt.Fprintf(out, "Updated %s\n", changelog.Filename)
+	return 0
+}
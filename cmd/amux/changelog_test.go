@@ -0,0 +1,37 @@
+//go:build !windows
+
+package main
+
+import (
+	"bytes"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/andyrewlee/amux/internal/testutil"
+)
+
+func TestRunChangelog(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	repo := testutil.InitRepo(t)
+	testutil.RunGit(t, repo, "commit", "--allow-empty", "-m", "feat(cli): draft changelogs")
+
+	var out bytes.Buffer
+	if code := runChangelog([]string{repo}, &out); code != 0 || !strings.Contains(out.String(), "- **cli:** draft changelogs") {
+		t.Fatalf("runChangelog = %d, %q", code, out.String())
+	}
+	out.Reset()
+	if code := runChangelog([]string{"--write", repo}, &out); code != 0 {
+		t.Fatalf("runChangelog --write = %d", code)
+	}
+	if written, err := os.ReadFile(filepath.Join(repo, "CHANGELOG.md")); err != nil || !strings.Contains(string(written), "draft changelogs") {
+		t.Fatalf("CHANGELOG.md = %q, %v", written, err)
+	}
+	if code := runChangelog([]string{"a", "b"}, &out); code != 2 {
+		t.Fatalf("runChangelog(a b) = %d, want 2", code)
+	}
+}
@@ -0,0 +1,158 @@
+//go:build !windows
+
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/andyrewlee/amux/internal/app"
+	"github.com/andyrewlee/amux/internal/config"
+	"github.com/andyrewlee/amux/internal/data"
+	"github.com/andyrewlee/amux/internal/git"
+	"github.com/andyrewlee/amux/internal/runtimes"
+)
+
+// doctorCheck is one line of `amux doctor` output.
+type doctorCheck struct {
+	level   string // "ok", "warn", or "fail"
+	message string
+}
+
+// runDoctor checks the environment amux runs in and returns the process
+// exit code: 1 when something amux needs is missing. With --repair it also
+// repairs damaged state files.
+func runDoctor(args []string, out io.Writer) int {
+	fs := flag.NewFlagSet("doctor", flag.ContinueOnError)
+	fs.SetOutput(os.Stderr)
+	repair := fs.Bool("repair", false, "repair damaged state files")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() != 0 {
+		fmt.Fprintln(os.Stderr, "usage: amux doctor [--repair]")
+		return 2
+	}
+	checks := []doctorCheck{
+		toolCheck("tmux", "amux runs every agent in tmux", "-V"),
+		toolCheck("git", "amux manages workspaces as git worktrees", "--version"),
+	}
+	checks = append(checks, nestingChecks(app.DetectNesting(os.Getenv, app.OuterTmuxPrefixes))...)
+	cfg, err := config.DefaultConfig()
+	if err != nil {
+		checks = append(checks, doctorCheck{level: "fail", message: "cannot locate amux state: " + err.Error()})
+	} else {
+		checks = append(checks, stateChecks(cfg.Paths.RegistryPath, cfg.Paths.MetadataRoot, *repair)...)
+	}
+	if dir, err := os.Getwd(); err == nil {
+		checks = append(checks, runtimeChecks(dir)...)
+	}
+	return writeDoctorReport(out, checks)
+}
+
+// stateChecks reports damaged or leftover state files, repairing them when
+// repair is set.
+func stateChecks(registryPath, metadataRoot string, repair bool) []doctorCheck {
+	check := data.CheckState
+	if repair {
+		check = data.RepairState
+	}
+	issues, err := check(registryPath, metadataRoot)
+	var checks []doctorCheck
+	for _, issue := range issues {
+		prefix := issue.Path + ": " + issue.Problem
+		switch {
+		case issue.Repair == "":
+			checks = append(checks, doctorCheck{level: "fail", message: prefix})
+		case !repair:
+			checks = append(checks, doctorCheck{level: "warn", message: fmt.Sprintf("%s; `amux doctor --repair` will %s", prefix, issue.Repair)})
+		case issue.RepairErr != nil:
+			checks = append(checks, doctorCheck{level: "fail", message: fmt.Sprintf("%s; could not %s: %v", prefix, issue.Repair, issue.RepairErr)})
+		default:
+			checks = append(checks, doctorCheck{level: "ok", message: fmt.Sprintf("%s; repaired: %s", prefix, issue.Repair)})
+		}
+	}
+	if err != nil {
+		checks = append(checks, doctorCheck{level: "fail", message: "checking state files: " + err.Error()})
+	}
+	if len(checks) == 0 {
+		checks = append(checks, doctorCheck{level: "ok", message: "state files are intact"})
+	}
+	return checks
+}
+
+// runtimeChecks reports whether the runtime versions pinned by the repository
+// containing dir are installed, since agents and scripts run with them
+// activated. Outside a repository there is nothing to check.
+func runtimeChecks(dir string) []doctorCheck {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	root, err := git.RunGitCtx(ctx, dir, "rev-parse", "--show-toplevel")
+	if err != nil {
+		return nil
+	}
+	var checks []doctorCheck
+	for _, pin := range runtimes.Resolve(root) {
+		name := fmt.Sprintf("%s %s (%s)", pin.Tool, pin.Version, pin.File)
+		if pin.Manager == runtimes.None {
+			checks = append(checks, doctorCheck{level: "warn", message: name + ": no version manager found to activate it (mise, asdf, nvm, or pyenv)"})
+			continue
+		}
+		installed, err := runtimes.Installed(ctx, root, pin)
+		switch {
+		case err != nil:
+			checks = append(checks, doctorCheck{level: "warn", message: fmt.Sprintf("%s: checking with %s: %v", name, pin.Manager, err)})
+		case !installed:
+			checks = append(checks, doctorCheck{level: "warn", message: fmt.Sprintf("%s is not installed; run `%s`", name, runtimes.InstallHint(pin))})
+		default:
+			checks = append(checks, doctorCheck{level: "ok", message: fmt.Sprintf("%s is installed with %s", name, pin.Manager)})
+		}
+	}
+	return checks
+}
+
+// toolCheck reports whether name is installed, with its version.
+func toolCheck(name, why string, versionArgs ...string) doctorCheck {
+	path, err := exec.LookPath(name)
+	if err != nil {
+		return doctorCheck{level: "fail", message: name + " not found: " + why}
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	version, err := exec.CommandContext(ctx, path, versionArgs...).Output()
+	if err != nil {
+		return doctorCheck{level: "warn", message: fmt.Sprintf("%s found at %s but failed to run: %v", name, path, err)}
+	}
+	return doctorCheck{level: "ok", message: strings.TrimSpace(string(version))}
+}
+
+// nestingChecks warns about an enclosing tmux or amux.
+func nestingChecks(n app.Nesting) []doctorCheck {
+	warnings := n.Warnings()
+	if len(warnings) == 0 {
+		return []doctorCheck{{level: "ok", message: "not nested in tmux or amux; the leader is C-Space"}}
+	}
+	checks := make([]doctorCheck, 0, len(warnings))
+	for _, warning := range warnings {
+		checks = append(checks, doctorCheck{level: "warn", message: warning})
+	}
+	return checks
+}
+
+// writeDoctorReport prints checks one per line and returns 1 if any failed.
+func writeDoctorReport(out io.Writer, checks []doctorCheck) int {
+	code := 0
+	for _, check := range checks {
+		fmt.Fprintf(out, "%-5s %s\n", check.level, check.message)
+		if check.level == "fail" {
+			code = 1
+		}
+	}
+	return code
+}
@@ -0,0 +1,100 @@
+//go:build !windows
+
+package main
+
+import (
+	"bytes"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/andyrewlee/amux/internal/app"
+	"github.com/andyrewlee/amux/internal/testutil"
+)
+
+func TestNestingChecks(t *testing.T) {
+	if checks := nestingChecks(app.Nesting{}); len(checks) != 1 || checks[0].level != "ok" {
+		t.Fatalf("no nesting: got %+v, want a single ok", checks)
+	}
+	checks := nestingChecks(app.Nesting{Amux: true, Tmux: true, TmuxPrefixes: []string{"C-Space"}})
+	if len(checks) != 2 {
+		t.Fatalf("got %+v, want a warning each for amux and tmux", checks)
+	}
+	for _, check := range checks {
+		if check.level != "warn" || !strings.Contains(check.message, `C-\`) {
+			t.Fatalf("check %+v should warn and name the alternate leader", check)
+		}
+	}
+}
+
+func TestWriteDoctorReport(t *testing.T) {
+	var out bytes.Buffer
+	code := writeDoctorReport(&out, []doctorCheck{
+		{level: "ok", message: "git version 2.45.0"},
+		{level: "warn", message: "running inside tmux"},
+	})
+	if code != 0 {
+		t.Fatalf("warnings alone should exit 0, got %d", code)
+	}
+	if want := "ok    git version 2.45.0\nwarn  running inside tmux\n"; out.String() != want {
+		t.Fatalf("report = %q, want %q", out.String(), want)
+	}
+	if code := writeDoctorReport(&bytes.Buffer{}, []doctorCheck{{level: "fail", message: "tmux not found"}}); code != 1 {
+		t.Fatalf("a failed check should exit 1, got %d", code)
+	}
+}
+
+func TestStateChecks(t *testing.T) {
+	dir := t.TempDir()
+	registryPath := filepath.Join(dir, "projects.json")
+	metadataRoot := filepath.Join(dir, "metadata")
+	if checks := stateChecks(registryPath, metadataRoot, false); len(checks) != 1 || checks[0].level != "ok" {
+		t.Fatalf("fresh state: got %+v, want a single ok", checks)
+	}
+
+	if err := os.WriteFile(registryPath, []byte("{broken"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	checks := stateChecks(registryPath, metadataRoot, false)
+	if len(checks) != 1 || checks[0].level != "warn" || !strings.Contains(checks[0].message, "amux doctor --repair") {
+		t.Fatalf("damaged registry: got %+v, want a warning pointing at --repair", checks)
+	}
+	checks = stateChecks(registryPath, metadataRoot, true)
+	if len(checks) != 1 || checks[0].level != "ok" || !strings.Contains(checks[0].message, "repaired") {
+		t.Fatalf("repair: got %+v", checks)
+	}
+	if checks := stateChecks(registryPath, metadataRoot, false); checks[0].message != "state files are intact" {
+		t.Fatalf("after repair: got %+v", checks)
+	}
+}
+
+func TestRuntimeChecks(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	if checks := runtimeChecks(t.TempDir()); len(checks) != 0 {
+		t.Fatalf("outside a repository: got %+v", checks)
+	}
+	repo := testutil.InitRepo(t)
+	bin := t.TempDir()
+	if err := os.WriteFile(filepath.Join(bin, "pyenv"), []byte("#!/bin/sh\n[ \"$2\" = 3.12.1 ] || exit 1\n"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", bin+string(os.PathListSeparator)+os.Getenv("PATH"))
+	t.Setenv("HOME", t.TempDir())
+	for version, want := range map[string]string{
+		"3.12.1": "ok    python 3.12.1 (.python-version) is installed with pyenv",
+		"3.13.0": "warn  python 3.13.0 (.python-version) is not installed; run `pyenv install 3.13.0`",
+	} {
+		if err := os.WriteFile(filepath.Join(repo, ".python-version"), []byte(version+"\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		var out bytes.Buffer
+		writeDoctorReport(&out, runtimeChecks(repo))
+		if strings.TrimSpace(out.String()) != want {
+			t.Fatalf("report = %q, want %q", out.String(), want)
+		}
+	}
+}
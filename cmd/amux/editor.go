@@ -0,0 +1,183 @@
+//go:build !windows
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/andyrewlee/amux/internal/app"
+	"github.com/andyrewlee/amux/internal/audit"
+	"github.com/andyrewlee/amux/internal/config"
+	"github.com/andyrewlee/amux/internal/data"
+	"github.com/andyrewlee/amux/internal/editorapi"
+	"github.com/andyrewlee/amux/internal/feed"
+	"github.com/andyrewlee/amux/internal/logging"
+	"github.com/andyrewlee/amux/internal/safego"
+	"github.com/andyrewlee/amux/internal/sessions"
+	"github.com/andyrewlee/amux/internal/tmux"
+)
+
+const editorUsage = "usage: amux editor serve | amux editor socket"
+
+// editorSubmitDelay separates pasted text from the Enter that submits it, so
+// an agent still reading the paste does not drop the Enter.
+const editorSubmitDelay = 150 * time.Millisecond
+
+// runEditor serves the editor API in the foreground, for when the TUI is not
+// running, or prints its socket path, and returns the process exit code.
+func runEditor(args []string, out io.Writer) int {
+	if len(args) != 1 || (args[0] != "serve" && args[0] != "socket") {
+		fmt.Fprintln(os.Stderr, editorUsage)
+		return 2
+	}
+	paths, err := config.DefaultPaths()
+	if err == nil {
+		err = paths.EnsureDirectories()
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	socket := editorapi.SocketPath(paths.Home)
+	if args[0] == "socket" {
+		fmt.Fprintln(out, socket)
+		return 0
+	}
+	srv, err := newEditorAPI(paths)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	ln, err := editorapi.Listen(socket)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "listen on %s: %v\n", socket, err)
+		return 1
+	}
+	fmt.Fprintf(os.Stderr, "Serving the editor API on %s. Press Ctrl+C to stop.\n", socket)
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	go func() {
+		<-ctx.Done()
+		_ = ln.Close()
+	}()
+	if err := srv.Serve(ln); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	return 0
+}
+
+// startEditorAPI serves the editor API while the TUI runs and returns the
+// server, to publish events on, and a function that stops it. A failure is
+// logged and the TUI runs without it, with a nil server.
+func startEditorAPI(paths *config.Paths) (*editorapi.Server, func()) {
+	srv, err := newEditorAPI(paths)
+	if err != nil {
+		logging.Warn("editor API: %v", err)
+		return nil, func() {}
+	}
+	ln, err := editorapi.Listen(editorapi.SocketPath(paths.Home))
+	if err != nil {
+		if errors.Is(err, editorapi.ErrServing) {
+			logging.Info("editor API: %v", err)
+		} else {
+			logging.Warn("editor API: %v", err)
+		}
+		return nil, func() {}
+	}
+	safego.Go("editor_api", func() {
+		if err := srv.Serve(ln); err != nil {
+			logging.Warn("editor API: %v", err)
+		}
+	})
+	return srv, func() { _ = ln.Close() }
+}
+
+// editorFeedListener publishes activity feed entries as editor API events.
+func editorFeedListener(srv *editorapi.Server) func(string, feed.Entry) {
+	return func(wsID string, e feed.Entry) {
+		srv.Publish(editorapi.Event{
+			Kind:        string(e.Kind),
+			WorkspaceID: wsID,
+			Workspace:   e.Workspace,
+			Text:        e.Text,
+			At:          e.At,
+		})
+	}
+}
+
+// editorAttentionListener reports the TUI's attention queue to the editor API.
+func editorAttentionListener(srv *editorapi.Server) func([]app.Attention) {
+	return func(queue []app.Attention) {
+		out := make([]editorapi.Attention, 0, len(queue))
+		for _, item := range queue {
+			out = append(out, editorapi.Attention{
+				WorkspaceID: item.WorkspaceID,
+				Workspace:   item.Workspace,
+				Project:     item.Project,
+				Root:        item.Root,
+				Reason:      item.Reason,
+				Since:       item.Since,
+			})
+		}
+		srv.SetAttention(out)
+	}
+}
+
+func newEditorAPI(paths *config.Paths) (*editorapi.Server, error) {
+	store := data.NewWorkspaceStore(paths.MetadataRoot)
+	opts := tmux.DefaultOptions()
+	auditLog := audit.Open(audit.Path(amuxLogDir()))
+	return editorapi.New(editorapi.Config{
+		Version:   version,
+		Worktrees: func() ([]*data.Workspace, error) { return loadWorkspaces(store) },
+		Sessions:  func() ([]sessions.Session, error) { return sessions.List(opts, store) },
+		Open:      openInEditor,
+		Activate: func(ws *data.Workspace, showTab bool) error {
+			if !instanceRunning(paths.Home) {
+				return errors.New("amux is not running")
+			}
+			if showTab {
+				return app.RequestShowTab(paths.Home, ws.ID())
+			}
+			return app.RequestActivate(paths.Home, ws.ID())
+		},
+		Send: func(agent sessions.Session, text string, submit bool) error {
+			// Unrecorded input is refused rather than sent.
+			if err := auditLog.Record(audit.SourceEditor, "", agent.TmuxSession, text); err != nil {
+				return err
+			}
+			if err := tmux.PasteText(agent.TmuxSession, text, opts); err != nil {
+				return err
+			}
+			if !submit {
+				return nil
+			}
+			time.Sleep(editorSubmitDelay)
+			return tmux.SendLiteral(agent.TmuxSession, "\r", opts)
+		},
+	})
+}
+
+// openInEditor starts editor on root without waiting for it to exit.
+func openInEditor(editor, root string) error {
+	path, err := exec.LookPath(editor)
+	if err != nil {
+		return fmt.Errorf("editor %s not found: %w", editor, err)
+	}
+	// #nosec G204 -- the editor runs as argv, without a shell, for the socket's owner.
+	cmd := exec.Command(path, root)
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	safego.Go("editor_open", func() { _ = cmd.Wait() })
+	return nil
+}
@@ -0,0 +1,198 @@
+//go:build !windows
+
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/andyrewlee/amux/internal/config"
+	"github.com/andyrewlee/amux/internal/data"
+	"github.com/andyrewlee/amux/internal/git"
+	"github.com/andyrewlee/amux/internal/muximport"
+	"github.com/andyrewlee/amux/internal/process"
+	"github.com/andyrewlee/amux/internal/validation"
+)
+
+const importUsage = "usage: amux import tmuxinator|zellij-layout <file> [--root dir] [--force] [--dry-run] [--json]"
+
+// importFormats are the session definitions amux import converts.
+var importFormats = map[string]func(src []byte, agents []string) (*muximport.Project, error){
+	"tmuxinator":    muximport.Tmuxinator,
+	"zellij-layout": muximport.Zellij,
+}
+
+// importResult is what an import did, or would do with --dry-run.
+type importResult struct {
+	Name   string              `json:"name"`
+	Format string              `json:"format"`
+	Root   string              `json:"root"`
+	Layout string              `json:"layout"`
+	Tabs   []process.LayoutTab `json:"tabs"`
+	// Replaced is whether the project already had a startup layout.
+	Replaced bool     `json:"replaced"`
+	Notes    []string `json:"notes"`
+	DryRun   bool     `json:"dry_run"`
+}
+
+// runImport converts a tmuxinator project or zellij layout into an amux
+// project and its startup layout, and returns the process exit code.
+func runImport(args []string, out io.Writer) int {
+	fs := flag.NewFlagSet("import", flag.ContinueOnError)
+	fs.SetOutput(os.Stderr)
+	root := fs.String("root", "", "the project's directory, instead of the one the file names")
+	force := fs.Bool("force", false, "replace the project's startup layout")
+	dryRun := fs.Bool("dry-run", false, "print what would be imported without changing anything")
+	asJSON := fs.Bool("json", false, "print the result as JSON")
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return 2
+		}
+		if fs.NArg() == 0 {
+			break
+		}
+		positional = append(positional, fs.Arg(0))
+		args = fs.Args()[1:]
+	}
+	if len(positional) != 2 || importFormats[positional[0]] == nil {
+		fmt.Fprintln(os.Stderr, importUsage)
+		return 2
+	}
+	cfg, err := config.DefaultConfig()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	res, err := planImport(positional[0], positional[1], *root, cfg.AssistantNames())
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	if res.Replaced && !*force && !*dryRun {
+		fmt.Fprintf(os.Stderr, "%s already exists; pass --force to replace it\n", res.Layout)
+		return 1
+	}
+	res.DryRun = *dryRun
+	if !*dryRun {
+		if err := applyImport(data.NewRegistry(cfg.Paths.RegistryPath), res); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+	}
+	if err := writeImportResult(out, res, *asJSON); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	return 0
+}
+
+// planImport reads file in format and works out the project it becomes.
+// root, when set, overrides the directory the file names, which must be a
+// git repository.
+func planImport(format, file, root string, agents []string) (*importResult, error) {
+	src, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	project, err := importFormats[format](src, agents)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", file, err)
+	}
+	if root == "" {
+		root = project.Root
+	}
+	if root == "" {
+		return nil, fmt.Errorf("%s does not name a project directory; pass --root", file)
+	}
+	root, err = resolveImportRoot(root)
+	if err != nil {
+		return nil, err
+	}
+	if err := validation.ValidateProjectPath(root); err != nil {
+		return nil, fmt.Errorf("%s: %w", root, err)
+	}
+	if !git.IsGitRepository(root) {
+		return nil, fmt.Errorf("%s is not a git repository", root)
+	}
+	name := project.Name
+	if name == "" {
+		name = strings.TrimSuffix(filepath.Base(file), filepath.Ext(file))
+	}
+	res := &importResult{
+		Name:   name,
+		Format: format,
+		Root:   root,
+		Layout: process.LayoutPath(root),
+		Tabs:   project.Tabs,
+		Notes:  project.Notes,
+	}
+	if res.Notes == nil {
+		res.Notes = []string{}
+	}
+	if _, err := os.Stat(res.Layout); err == nil {
+		res.Replaced = true
+	}
+	return res, nil
+}
+
+// resolveImportRoot expands a leading "~/" and makes root absolute, from
+// the current directory as the multiplexers do.
+func resolveImportRoot(root string) (string, error) {
+	if rest, ok := strings.CutPrefix(root, "~/"); ok || root == "~" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+		root = filepath.Join(home, rest)
+	}
+	return filepath.Abs(root)
+}
+
+// applyImport writes the project's startup layout and adds it to amux's
+// projects.
+func applyImport(registry *data.Registry, res *importResult) error {
+	if err := process.SaveLayout(res.Root, &process.Layout{Tabs: res.Tabs}); err != nil {
+		return fmt.Errorf("write %s: %w", res.Layout, err)
+	}
+	return registry.AddProject(res.Root)
+}
+
+func writeImportResult(out io.Writer, res *importResult, asJSON bool) error {
+	if asJSON {
+		enc := json.NewEncoder(out)
+		enc.SetIndent("", "  ")
+		return enc.Encode(res)
+	}
+	verb := "Imported"
+	if res.DryRun {
+		verb = "Would import"
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s %s from %s as %s, opening:\n", verb, res.Name, res.Format, res.Root)
+	for i, tab := range res.Tabs {
+		switch {
+		case tab.Agent != "":
+			fmt.Fprintf(&b, "  %d. agent %s\n", i+1, tab.Agent)
+		case tab.Command != "":
+			fmt.Fprintf(&b, "  %d. command %s\n", i+1, tab.Command)
+		default:
+			fmt.Fprintf(&b, "  %d. terminal\n", i+1)
+		}
+	}
+	layout := "Startup layout: " + res.Layout
+	if res.Replaced {
+		layout += " (replacing the existing one)"
+	}
+	b.WriteString(layout + "\n")
+	for _, note := range res.Notes {
+		fmt.Fprintf(&b, "Note: %s\n", note)
+	}
+	_, err := io.WriteString(out, b.String())
+	return err
+}
@@ -0,0 +1,90 @@
+//go:build !windows
+
+package main
+
+import (
+	"bytes"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/andyrewlee/amux/internal/data"
+	"github.com/andyrewlee/amux/internal/process"
+)
+
+func TestImport(t *testing.T) {
+	repo := t.TempDir()
+	if out, err := exec.Command("git", "init", "-q", repo).CombinedOutput(); err != nil {
+		t.Fatalf("git init: %v: %s", err, out)
+	}
+	file := filepath.Join(t.TempDir(), "api.yml")
+	project := "name: api\nroot: " + repo + "\non_project_start: make deps\nwindows:\n  - agent: claude\n  - server: npm run dev\n  - shell:\n"
+	if err := os.WriteFile(file, []byte(project), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	res, err := planImport("tmuxinator", file, "", []string{"claude"})
+	if err != nil {
+		t.Fatalf("planImport() error = %v", err)
+	}
+	if res.Name != "api" || res.Root != repo || res.Layout != process.LayoutPath(repo) || res.Replaced {
+		t.Fatalf("planImport() = %+v", res)
+	}
+	var out bytes.Buffer
+	res.DryRun = true
+	if err := writeImportResult(&out, res, false); err != nil {
+		t.Fatal(err)
+	}
+	want := "Would import api from tmuxinator as " + repo + ", opening:\n" +
+		"  1. agent claude\n  2. command npm run dev\n  3. terminal\n" +
+		"Startup layout: " + res.Layout + "\nNote: on_project_start is not imported\n"
+	if out.String() != want {
+		t.Fatalf("dry run output = %q, want %q", out.String(), want)
+	}
+
+	registry := data.NewRegistry(filepath.Join(t.TempDir(), "projects.json"))
+	if err := applyImport(registry, res); err != nil {
+		t.Fatalf("applyImport() error = %v", err)
+	}
+	projects, err := registry.Projects()
+	if err != nil || len(projects) != 1 || projects[0] != repo {
+		t.Fatalf("registered projects = %v, %v", projects, err)
+	}
+	layout, err := process.LoadLayout(&data.Workspace{Root: repo})
+	if err != nil || layout == nil || len(layout.Tabs) != 3 || layout.Tabs[1].Command != "npm run dev" {
+		t.Fatalf("saved layout = %+v, %v", layout, err)
+	}
+	if res, err = planImport("tmuxinator", file, "", nil); err != nil || !res.Replaced {
+		t.Fatalf("importing again should replace the layout: %+v, %v", res, err)
+	}
+}
+
+func TestImportRoot(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "dev.kdl")
+	if err := os.WriteFile(file, []byte("layout {\n  pane\n}\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := planImport("zellij-layout", file, "", nil); err == nil || !strings.Contains(err.Error(), "pass --root") {
+		t.Fatalf("planImport() without a root = %v", err)
+	}
+	notRepo := t.TempDir()
+	if _, err := planImport("zellij-layout", file, notRepo, nil); err == nil || !strings.Contains(err.Error(), notRepo) {
+		t.Fatalf("planImport() outside a repository = %v", err)
+	}
+
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	if got, err := resolveImportRoot("~/src/api"); err != nil || got != filepath.Join(home, "src/api") {
+		t.Fatalf("resolveImportRoot() = %q, %v", got, err)
+	}
+}
+
+func TestRunImportUsage(t *testing.T) {
+	for _, args := range [][]string{nil, {"tmux", "x.yml"}, {"tmuxinator"}, {"tmuxinator", "a", "b"}} {
+		if code := runImport(args, &bytes.Buffer{}); code != 2 {
+			t.Errorf("runImport(%q) = %d, want 2", args, code)
+		}
+	}
+}
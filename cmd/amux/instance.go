@@ -0,0 +1,89 @@
+//go:build !windows
+
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/andyrewlee/amux/internal/instancelock"
+)
+
+// takeOverTimeout bounds the wait for the other amux to save and quit.
+const takeOverTimeout = 10 * time.Second
+
+// instanceMode is how this TUI runs alongside another amux.
+type instanceMode int
+
+const (
+	instanceOwner instanceMode = iota
+	instanceReadOnly
+	instanceQuit
+)
+
+// takeOverInstance is a test seam over instancelock.TakeOver.
+var takeOverInstance = instancelock.TakeOver
+
+// claimInstance takes the instance lock in home. When another amux TUI holds
+// it, the user chooses on in and out whether to take over, open a read-only
+// view, or quit. The lock is nil unless the mode is instanceOwner. owner
+// describes the other amux.
+func claimInstance(home string, in io.Reader, out io.Writer) (lock *instancelock.Lock, mode instanceMode, owner string, err error) {
+	path := filepath.Join(home, "instance.lock")
+	lock, holder, err := instancelock.Acquire(path)
+	if !errors.Is(err, instancelock.ErrHeld) {
+		return lock, instanceOwner, "", err
+	}
+	owner = "another amux"
+	if holder.PID > 0 {
+		owner = fmt.Sprintf("amux (pid %d)", holder.PID)
+	}
+	since := ""
+	if !holder.StartedAt.IsZero() {
+		since = ", running since " + holder.StartedAt.Local().Format("Jan 2 15:04")
+	}
+	fmt.Fprintf(out, "%s%s is already open on this state.\n", capitalize(owner), since)
+	fmt.Fprintln(out, "  t  take over: it saves its tabs and quits, and this one opens them")
+	fmt.Fprintln(out, "  r  open a read-only second view of the same sessions")
+	fmt.Fprintln(out, "  q  quit")
+	reader := bufio.NewReader(in)
+	for {
+		fmt.Fprint(out, "Choice [t/r/q]: ")
+		line, readErr := reader.ReadString('\n')
+		switch strings.ToLower(strings.TrimSpace(line)) {
+		case "t", "take over":
+			fmt.Fprintf(out, "Asking %s to quit...\n", owner)
+			lock, err := takeOverInstance(path, holder, takeOverTimeout)
+			if err != nil {
+				return nil, instanceQuit, owner, fmt.Errorf("take over from %s: %w", owner, err)
+			}
+			return lock, instanceOwner, owner, nil
+		case "r", "read-only":
+			return nil, instanceReadOnly, owner, nil
+		case "q", "quit":
+			return nil, instanceQuit, owner, nil
+		}
+		if readErr != nil {
+			return nil, instanceQuit, owner, nil
+		}
+	}
+}
+
+// instanceRunning reports whether an amux TUI holds the instance lock in home.
+func instanceRunning(home string) bool {
+	lock, _, err := instancelock.Acquire(filepath.Join(home, "instance.lock"))
+	lock.Release()
+	return errors.Is(err, instancelock.ErrHeld)
+}
+
+func capitalize(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToUpper(s[:1]) + s[1:]
+}
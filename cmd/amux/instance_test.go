@@ -0,0 +1,68 @@
+//go:build !windows
+
+package main
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/andyrewlee/amux/internal/instancelock"
+)
+
+func TestClaimInstance(t *testing.T) {
+	home := t.TempDir()
+	lock, mode, _, err := claimInstance(home, strings.NewReader(""), &bytes.Buffer{})
+	if err != nil || lock == nil || mode != instanceOwner {
+		t.Fatalf("uncontended claim = %v, %v, %v", lock, mode, err)
+	}
+	defer lock.Release()
+
+	// The lock is now held, so a second claim asks what to do.
+	var out bytes.Buffer
+	second, mode, owner, err := claimInstance(home, strings.NewReader("x\nr\n"), &out)
+	if err != nil || second != nil || mode != instanceReadOnly {
+		t.Fatalf("read-only claim = %v, %v, %v", second, mode, err)
+	}
+	if !strings.Contains(owner, "pid") || strings.Count(out.String(), "Choice") != 2 {
+		t.Fatalf("owner %q, prompt %q; want the prompt repeated after a bad answer", owner, out.String())
+	}
+
+	if _, mode, _, _ := claimInstance(home, strings.NewReader(""), &bytes.Buffer{}); mode != instanceQuit {
+		t.Fatalf("no answer: mode = %v, want quit", mode)
+	}
+}
+
+func TestClaimInstanceTakeOver(t *testing.T) {
+	oldTake := takeOverInstance
+	t.Cleanup(func() { takeOverInstance = oldTake })
+	home := t.TempDir()
+	held, _, _, err := claimInstance(home, strings.NewReader(""), &bytes.Buffer{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var asked instancelock.Holder
+	takeOverInstance = func(path string, holder instancelock.Holder, _ time.Duration) (*instancelock.Lock, error) {
+		asked = holder
+		held.Release()
+		lock, _, err := instancelock.Acquire(path)
+		return lock, err
+	}
+
+	lock, mode, _, err := claimInstance(home, strings.NewReader("t\n"), &bytes.Buffer{})
+	if err != nil || lock == nil || mode != instanceOwner || asked.PID == 0 {
+		t.Fatalf("take over = %v, %v, %v (asked %+v)", lock, mode, err, asked)
+	}
+	lock.Release()
+
+	takeOverInstance = func(string, instancelock.Holder, time.Duration) (*instancelock.Lock, error) {
+		return nil, errors.New("still running")
+	}
+	held, _, _, _ = claimInstance(home, strings.NewReader(""), &bytes.Buffer{})
+	defer held.Release()
+	if _, mode, _, err := claimInstance(home, strings.NewReader("t\n"), &bytes.Buffer{}); err == nil || mode != instanceQuit {
+		t.Fatalf("failed take over = %v, %v; want an error", mode, err)
+	}
+}
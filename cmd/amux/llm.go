@@ -0,0 +1,80 @@
+//go:build !windows
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/andyrewlee/amux/internal/config"
+	"github.com/andyrewlee/amux/internal/llm"
+)
+
+const llmUsage = "usage: amux llm"
+
+// runLLM prints the language model amux's assistive features use, which of
+// them are enabled, and the tokens each has used, and returns the process
+// exit code.
+func runLLM(args []string, out io.Writer) int {
+	fs := flag.NewFlagSet("llm", flag.ContinueOnError)
+	fs.SetOutput(os.Stderr)
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() != 0 {
+		fmt.Fprintln(os.Stderr, llmUsage)
+		return 2
+	}
+	cfg, err := config.DefaultConfig()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	totals, err := llm.OpenLedger(filepath.Join(cfg.Paths.Home, llm.LedgerFile)).Totals()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "llm: reading usage: %v\n", err)
+		return 1
+	}
+	fmt.Fprint(out, llmSummary(cfg.LLM, totals))
+	return 0
+}
+
+// llmSummary describes the configured model, then each feature that is
+// enabled or has used tokens, one line each.
+func llmSummary(cfg llm.Config, totals map[llm.Feature]llm.Usage) string {
+	var b strings.Builder
+	if cfg.Provider == "" {
+		b.WriteString("no language model configured; set \"llm\" in config.json\n")
+	} else {
+		fmt.Fprintf(&b, "%s %s at %s\n", cfg.Provider, cfg.Model, cfg.Endpoint)
+	}
+	features := map[llm.Feature]bool{}
+	for f := range totals {
+		features[f] = true
+	}
+	for f, on := range cfg.Features {
+		if on {
+			features[f] = true
+		}
+	}
+	names := make([]string, 0, len(features))
+	for f := range features {
+		names = append(names, string(f))
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		f := llm.Feature(name)
+		state := "off"
+		if cfg.Enabled(f) {
+			state = "on"
+		}
+		u := totals[f]
+		fmt.Fprintf(&b, "  %-16s %-3s  %d requests, %d input tokens, %d output tokens\n", name, state, u.Requests, u.InputTokens, u.OutputTokens)
+	}
+	return b.String()
+}
@@ -0,0 +1,30 @@
+//go:build !windows
+
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/andyrewlee/amux/internal/llm"
+)
+
+func TestLLMSummary(t *testing.T) {
+	cfg := llm.Config{Provider: llm.Ollama, Model: "llama3", Features: map[llm.Feature]bool{llm.FeatureCommitMessage: true}}.WithDefaults()
+	got := llmSummary(cfg, map[llm.Feature]llm.Usage{
+		llm.FeatureCommitMessage: {Requests: 3, InputTokens: 900, OutputTokens: 30},
+		"summary":                {Requests: 1, InputTokens: 10, OutputTokens: 2},
+	})
+	for _, want := range []string{
+		"ollama llama3 at http://localhost:11434/v1",
+		"commit-message   on   3 requests, 900 input tokens, 30 output tokens",
+		"summary          off  1 requests",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("summary missing %q:\n%s", want, got)
+		}
+	}
+	if got := llmSummary(llm.Config{}, nil); !strings.Contains(got, "no language model configured") {
+		t.Errorf("summary without a model = %q", got)
+	}
+}
@@ -0,0 +1,85 @@
+//go:build !windows
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/andyrewlee/amux/internal/audit"
+)
+
+// runLogs prints the tail of the newest daily log, or of the audit log with
+// --audit, and returns the process exit code.
+func runLogs(args []string, out io.Writer) int {
+	fs := flag.NewFlagSet("logs", flag.ContinueOnError)
+	fs.SetOutput(os.Stderr)
+	showAudit := fs.Bool("audit", false, "show input injected into sessions instead of the daily log")
+	limit := fs.Int("n", 50, "number of lines to show; 0 shows everything")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() != 0 {
+		fmt.Fprintln(os.Stderr, "usage: amux logs [--audit] [-n lines]")
+		return 2
+	}
+	if err := printLogs(out, amuxLogDir(), *showAudit, *limit); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	return 0
+}
+
+func printLogs(out io.Writer, logDir string, showAudit bool, limit int) error {
+	if showAudit {
+		entries, err := audit.Read(audit.Path(logDir))
+		if err != nil {
+			return fmt.Errorf("read audit log: %w", err)
+		}
+		if len(entries) == 0 {
+			fmt.Fprintln(out, "No injected input recorded.")
+			return nil
+		}
+		if limit > 0 && len(entries) > limit {
+			entries = entries[len(entries)-limit:]
+		}
+		return audit.Write(out, entries)
+	}
+
+	path, err := newestDailyLog(logDir)
+	if err != nil {
+		return err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("read log: %w", err)
+	}
+	lines := strings.SplitAfter(string(data), "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	if limit > 0 && len(lines) > limit {
+		lines = lines[len(lines)-limit:]
+	}
+	fmt.Fprintf(out, "==> %s <==\n%s", path, strings.Join(lines, ""))
+	return nil
+}
+
+// newestDailyLog returns the most recent amux-YYYY-MM-DD.log in logDir; the
+// date in the name sorts chronologically.
+func newestDailyLog(logDir string) (string, error) {
+	matches, err := filepath.Glob(filepath.Join(logDir, "amux-*.log"))
+	if err != nil {
+		return "", err
+	}
+	if len(matches) == 0 {
+		return "", fmt.Errorf("no logs in %s", logDir)
+	}
+	sort.Strings(matches)
+	return matches[len(matches)-1], nil
+}
@@ -0,0 +1,62 @@
+//go:build !windows
+
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/andyrewlee/amux/internal/audit"
+)
+
+func TestPrintLogsShowsNewestDailyLogTail(t *testing.T) {
+	dir := t.TempDir()
+	for name, body := range map[string]string{
+		"amux-2026-01-01.log": "old\n",
+		"amux-2026-01-02.log": "one\ntwo\nthree\n",
+		audit.FileName:        "",
+	} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(body), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	var out bytes.Buffer
+	if err := printLogs(&out, dir, false, 2); err != nil {
+		t.Fatalf("printLogs() error = %v", err)
+	}
+	got := out.String()
+	if !strings.Contains(got, "amux-2026-01-02.log") || !strings.HasSuffix(got, "two\nthree\n") || strings.Contains(got, "one") {
+		t.Fatalf("printLogs() = %q, want the last two lines of the newest log", got)
+	}
+}
+
+func TestPrintLogsAudit(t *testing.T) {
+	dir := t.TempDir()
+	var out bytes.Buffer
+	if err := printLogs(&out, dir, true, 0); err != nil {
+		t.Fatalf("printLogs() error = %v", err)
+	}
+	if !strings.Contains(out.String(), "No injected input") {
+		t.Fatalf("printLogs() = %q, want an empty-log note", out.String())
+	}
+
+	if err := audit.Open(audit.Path(dir)).Record(audit.SourceShare, "10.0.0.2:5000", "amux-ws-tab", "ls\r"); err != nil {
+		t.Fatal(err)
+	}
+	out.Reset()
+	if err := printLogs(&out, dir, true, 0); err != nil {
+		t.Fatalf("printLogs() error = %v", err)
+	}
+	if !strings.Contains(out.String(), "share 10.0.0.2:5000  -> amux-ws-tab  3 bytes") {
+		t.Fatalf("printLogs() = %q, want the recorded entry", out.String())
+	}
+}
+
+func TestPrintLogsWithoutDailyLog(t *testing.T) {
+	if err := printLogs(&bytes.Buffer{}, t.TempDir(), false, 0); err == nil {
+		t.Fatal("printLogs() should fail when there are no logs")
+	}
+}
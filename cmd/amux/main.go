@@ -17,6 +17,9 @@ import (
 	"github.com/charmbracelet/x/term"
 
 	"github.com/andyrewlee/amux/internal/app"
+	"github.com/andyrewlee/amux/internal/config"
+	"github.com/andyrewlee/amux/internal/editorapi"
+	"github.com/andyrewlee/amux/internal/instancelock"
 	"github.com/andyrewlee/amux/internal/logging"
 	"github.com/andyrewlee/amux/internal/pprofhttp"
 	"github.com/andyrewlee/amux/internal/safego"
@@ -30,13 +33,66 @@ var (
 )
 
 func main() {
-	args := os.Args[1:]
+	args, err := applyProfileFlag(os.Args[1:])
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
 
 	if isVersionInvocation(args) {
 		fmt.Printf("amux %s (commit: %s, built: %s)\n", version, commit, date)
 		os.Exit(0)
 	}
 
+	if len(args) > 0 && args[0] == "share" {
+		os.Exit(runShare(args[1:]))
+	}
+	if len(args) > 0 && args[0] == "logs" {
+		os.Exit(runLogs(args[1:], os.Stdout))
+	}
+	if len(args) > 0 && args[0] == "schedule" {
+		os.Exit(runSchedule(args[1:], os.Stdout))
+	}
+	if len(args) > 0 && args[0] == "doctor" {
+		os.Exit(runDoctor(args[1:], os.Stdout))
+	}
+	if len(args) > 0 && args[0] == "workspace" {
+		os.Exit(runWorkspace(args[1:], os.Stdout))
+	}
+	if len(args) > 0 && args[0] == "session" {
+		os.Exit(runSession(args[1:], os.Stdout))
+	}
+	if len(args) > 0 && args[0] == "status" {
+		os.Exit(runStatus(args[1:], os.Stdout))
+	}
+	if len(args) > 0 && args[0] == "agent" {
+		os.Exit(runAgent(args[1:], os.Stdout))
+	}
+	if len(args) > 0 && args[0] == "tab" {
+		os.Exit(runTab(args[1:], os.Stdout))
+	}
+	if len(args) > 0 && args[0] == "sync" {
+		os.Exit(runSync(args[1:], os.Stdout))
+	}
+	if len(args) > 0 && args[0] == "editor" {
+		os.Exit(runEditor(args[1:], os.Stdout))
+	}
+	if len(args) > 0 && args[0] == "llm" {
+		os.Exit(runLLM(args[1:], os.Stdout))
+	}
+	if len(args) > 0 && args[0] == "changelog" {
+		os.Exit(runChangelog(args[1:], os.Stdout))
+	}
+	if len(args) > 0 && args[0] == "capabilities" {
+		os.Exit(runCapabilities(args[1:], os.Stdout))
+	}
+	if len(args) > 0 && args[0] == "import" {
+		os.Exit(runImport(args[1:], os.Stdout))
+	}
+	if len(args) > 0 && args[0] == "toggle" {
+		os.Exit(runToggle(args[1:], os.Stdout))
+	}
+
 	if len(args) > 0 {
 		fmt.Fprintln(os.Stderr, unsupportedInvocationMessage(args[0]))
 		os.Exit(2)
@@ -54,6 +110,33 @@ func main() {
 	runTUI()
 }
 
+// applyProfileFlag strips a leading --profile <name> from args and selects
+// the profile through config.ProfileEnvVar, which the subcommands, the TUI,
+// and the processes it starts all read.
+func applyProfileFlag(args []string) ([]string, error) {
+	if len(args) == 0 {
+		return args, nil
+	}
+	name, ok := strings.CutPrefix(args[0], "--profile=")
+	rest := args[1:]
+	if !ok {
+		if args[0] != "--profile" {
+			return args, nil
+		}
+		if len(args) < 2 {
+			return nil, fmt.Errorf("--profile needs a profile name")
+		}
+		name, rest = args[1], args[2:]
+	}
+	if err := config.ValidateProfileName(name); err != nil {
+		return nil, err
+	}
+	if err := os.Setenv(config.ProfileEnvVar, name); err != nil {
+		return nil, err
+	}
+	return rest, nil
+}
+
 func isVersionInvocation(args []string) bool {
 	return len(args) == 1 && (args[0] == "--version" || args[0] == "-v")
 }
@@ -66,17 +149,26 @@ func unsupportedInvocationMessage(arg string) string {
 	if arg == "tui" {
 		return "run `amux` directly to start the terminal UI."
 	}
-	return fmt.Sprintf("unexpected argument %q. Run `amux` to start the terminal UI or `amux --version`.", arg)
+	return fmt.Sprintf("unexpected argument %q. Run `amux` to start the terminal UI (`amux --profile <name>` for a named profile), `amux share <session>` to share a session, `amux logs` to read logs, `amux schedule` to run scheduled agent tasks, `amux doctor` to check the environment, `amux workspace history <name>` to show a worktree's timeline, `amux workspace delete <name>` to delete one, `amux status --report md` for a markdown status report, `amux session ls` to list tmux sessions, `amux agent list|launch|stop` and `amux tab list` to manage agents and tabs, `amux sync` to sync settings and projects across machines, `amux changelog` to draft a changelog entry, `amux llm` to show the language model's usage, `amux editor serve` to serve the editor API, `amux toggle` to show the agent most in need of attention, `amux import tmuxinator|zellij-layout <file>` to import a tmuxinator project or zellij layout, `amux capabilities` to describe the commands as JSON, or `amux --version`.", arg)
 }
 
 func nonInteractiveMessage() string {
 	return "amux starts an interactive terminal UI and requires stdin, stdout, and stderr to be TTYs."
 }
 
+// amuxLogDir returns the directory holding amux's daily logs and audit log,
+// kept per profile.
+func amuxLogDir() string {
+	if paths, err := config.DefaultPaths(); err == nil {
+		return filepath.Join(paths.Home, "logs")
+	}
+	home, _ := os.UserHomeDir()
+	return filepath.Join(home, ".amux", "logs")
+}
+
 func runTUI() {
 	// Initialize logging
-	home, _ := os.UserHomeDir()
-	logDir := filepath.Join(home, ".amux", "logs")
+	logDir := amuxLogDir()
 	logLevel := logging.LevelInfo
 	if lvl, ok := logging.ParseLevel(os.Getenv("AMUX_LOG_LEVEL")); ok {
 		logLevel = lvl
@@ -96,18 +188,44 @@ func runTUI() {
 
 	startSignalDebug()
 
+	lock, mode, owner := claimInstanceOrExit()
+	defer lock.Release()
+	var editorAPI *editorapi.Server
+	if mode == instanceOwner {
+		if paths, err := config.DefaultPaths(); err == nil {
+			syncOnStart(paths)
+			var stopEditorAPI func()
+			editorAPI, stopEditorAPI = startEditorAPI(paths)
+			defer stopEditorAPI()
+		}
+	}
+
 	a, err := app.New(version, commit, date)
 	if err != nil {
 		logging.Error("Failed to initialize app: %v", err)
 		fmt.Fprintf(os.Stderr, "Error initializing app: %v\n", err)
 		os.Exit(1)
 	}
+	if mode == instanceReadOnly {
+		a.SetReadOnly(owner)
+	}
+	if editorAPI != nil {
+		a.SetFeedListener(editorFeedListener(editorAPI))
+		a.SetAttentionListener(editorAttentionListener(editorAPI))
+	}
+	if lock != nil {
+		// Another amux taking over sends SIGTERM; save the tabs and quit.
+		takeover := make(chan os.Signal, 1)
+		signal.Notify(takeover, syscall.SIGTERM)
+		safego.Go("instance_takeover", func() {
+			<-takeover
+			a.QuitForTakeover()
+		})
+	}
 	startPprof()
 
-	p := tea.NewProgram(
-		a,
-		tea.WithFilter(mouseEventFilter),
-	)
+	opts := append([]tea.ProgramOption{tea.WithFilter(mouseEventFilter)}, a.ProgramOptions()...)
+	p := tea.NewProgram(a, opts...)
 	a.SetMsgSender(p.Send)
 
 	if _, err := p.Run(); err != nil {
@@ -123,6 +241,29 @@ func runTUI() {
 	logging.Info("amux shutdown complete")
 }
 
+// claimInstanceOrExit claims the instance lock, exiting when the user quits
+// or the lock cannot be taken.
+func claimInstanceOrExit() (*instancelock.Lock, instanceMode, string) {
+	paths, err := config.DefaultPaths()
+	if err == nil {
+		err = paths.EnsureDirectories()
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error initializing app: %v\n", err)
+		os.Exit(1)
+	}
+	lock, mode, owner, err := claimInstance(paths.Home, os.Stdin, os.Stdout)
+	if err != nil {
+		logging.Error("Instance lock: %v", err)
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	if mode == instanceQuit {
+		os.Exit(0)
+	}
+	return lock, mode, owner
+}
+
 var (
 	lastMouseMotionEvent   time.Time
 	lastMouseWheelEvent    time.Time
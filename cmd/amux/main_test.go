@@ -3,11 +3,15 @@
 package main
 
 import (
+	"os"
+	"slices"
 	"strings"
 	"testing"
 	"time"
 
 	tea "charm.land/bubbletea/v2"
+
+	"github.com/andyrewlee/amux/internal/config"
 )
 
 func resetMouseFilterState() {
@@ -112,3 +116,31 @@ func TestShouldLaunchTUIRequiresAllTTYStreams(t *testing.T) {
 		})
 	}
 }
+
+func TestApplyProfileFlag(t *testing.T) {
+	tests := []struct {
+		args    []string
+		want    []string
+		profile string
+		wantErr bool
+	}{
+		{args: []string{"status"}, want: []string{"status"}},
+		{args: []string{"--profile", "work"}, want: []string{}, profile: "work"},
+		{args: []string{"--profile=personal", "session", "ls"}, want: []string{"session", "ls"}, profile: "personal"},
+		{args: []string{"--profile"}, wantErr: true},
+		{args: []string{"--profile", "../x"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Setenv(config.ProfileEnvVar, "")
+		got, err := applyProfileFlag(tt.args)
+		if (err != nil) != tt.wantErr {
+			t.Fatalf("applyProfileFlag(%q) error = %v", tt.args, err)
+		}
+		if tt.wantErr {
+			continue
+		}
+		if !slices.Equal(got, tt.want) || os.Getenv(config.ProfileEnvVar) != tt.profile {
+			t.Fatalf("applyProfileFlag(%q) = %q, profile %q", tt.args, got, os.Getenv(config.ProfileEnvVar))
+		}
+	}
+}
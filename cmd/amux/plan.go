@@ -0,0 +1,124 @@
+//go:build !windows
+
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/charmbracelet/x/term"
+)
+
+// Test seams for confirming destructive commands on the terminal.
+var (
+	stdinIsTerminal           = func() bool { return term.IsTerminal(os.Stdin.Fd()) }
+	confirmInput    io.Reader = os.Stdin
+)
+
+// errNotConfirmed is returned when a destructive command is declined, or
+// cannot be confirmed because stdin is not a terminal.
+var errNotConfirmed = errors.New("not confirmed")
+
+// planStep is one thing a destructive command removes or stops.
+type planStep struct {
+	// Action is what happens to Target: kill_session, remove_worktree,
+	// delete_branch, remove_metadata, or keep_in_trash.
+	Action string `json:"action"`
+	Target string `json:"target"`
+}
+
+// destructivePlan is what a destructive command would do, printed by
+// --dry-run and shown before asking for confirmation.
+type destructivePlan struct {
+	Command string     `json:"command"`
+	DryRun  bool       `json:"dry_run"`
+	Steps   []planStep `json:"steps"`
+}
+
+func (p *destructivePlan) add(action, target string) {
+	p.Steps = append(p.Steps, planStep{Action: action, Target: target})
+}
+
+// planFlags are the flags every destructive command takes.
+type planFlags struct {
+	dryRun  *bool
+	yes     *bool
+	verbose *bool
+	json    *bool
+}
+
+func addPlanFlags(fs *flag.FlagSet) planFlags {
+	return planFlags{
+		dryRun:  fs.Bool("dry-run", false, "print what would be removed without removing anything"),
+		yes:     fs.Bool("yes", false, "do not ask for confirmation"),
+		verbose: fs.Bool("verbose", false, "print the plan before carrying it out"),
+		json:    fs.Bool("json", false, "print the result as JSON"),
+	}
+}
+
+// run prints the plan and returns false when it is a dry run; otherwise it
+// returns whether the plan may be carried out, asking on the terminal
+// unless --yes was passed. Without a terminal --yes is required.
+func (f planFlags) run(out io.Writer, plan *destructivePlan) (bool, error) {
+	if *f.dryRun {
+		plan.DryRun = true
+		return false, writePlan(out, plan, *f.json)
+	}
+	if *f.yes {
+		if *f.verbose && !*f.json {
+			return true, writePlan(out, plan, false)
+		}
+		return true, nil
+	}
+	if !stdinIsTerminal() {
+		return false, fmt.Errorf("%w: pass --yes to %s without a terminal", errNotConfirmed, plan.Command)
+	}
+	if err := writePlan(os.Stderr, plan, false); err != nil {
+		return false, err
+	}
+	fmt.Fprint(os.Stderr, "Continue? [y/N] ")
+	answer, _ := bufio.NewReader(confirmInput).ReadString('\n')
+	switch strings.ToLower(strings.TrimSpace(answer)) {
+	case "y", "yes":
+		return true, nil
+	}
+	return false, errNotConfirmed
+}
+
+// writePlan prints plan as JSON or as one line per step.
+func writePlan(out io.Writer, plan *destructivePlan, asJSON bool) error {
+	if asJSON {
+		enc := json.NewEncoder(out)
+		enc.SetIndent("", "  ")
+		return enc.Encode(plan)
+	}
+	verb := "will"
+	if plan.DryRun {
+		verb = "would"
+	}
+	if _, err := fmt.Fprintf(out, "amux %s %s:\n", plan.Command, verb); err != nil {
+		return err
+	}
+	for _, step := range plan.Steps {
+		if _, err := fmt.Fprintf(out, "  %-16s %s\n", strings.ReplaceAll(step.Action, "_", " "), step.Target); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// planExitCode is the exit code of a destructive command that did not carry
+// out its plan: 0 for a dry run, 1 when it was not confirmed.
+func planExitCode(err error) int {
+	if err == nil {
+		return 0
+	}
+	fmt.Fprintln(os.Stderr, err)
+	return 1
+}
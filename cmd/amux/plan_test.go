@@ -0,0 +1,67 @@
+//go:build !windows
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"strings"
+	"testing"
+)
+
+func TestPlanFlags(t *testing.T) {
+	parse := func(args ...string) planFlags {
+		t.Helper()
+		fs := flag.NewFlagSet("test", flag.ContinueOnError)
+		flags := addPlanFlags(fs)
+		if err := fs.Parse(args); err != nil {
+			t.Fatal(err)
+		}
+		return flags
+	}
+	newPlan := func() *destructivePlan {
+		plan := &destructivePlan{Command: "workspace delete"}
+		plan.add("remove_worktree", "/work/repo/feature")
+		plan.add("delete_branch", "feature")
+		return plan
+	}
+	origTerminal, origInput := stdinIsTerminal, confirmInput
+	t.Cleanup(func() { stdinIsTerminal, confirmInput = origTerminal, origInput })
+	terminal := false
+	stdinIsTerminal = func() bool { return terminal }
+
+	var out bytes.Buffer
+	if proceed, err := parse("--dry-run").run(&out, newPlan()); proceed || err != nil {
+		t.Fatalf("dry run = %v, %v; want the plan only", proceed, err)
+	}
+	if got := out.String(); !strings.Contains(got, "would:") || !strings.Contains(got, "delete branch    feature") {
+		t.Fatalf("dry run printed %q", got)
+	}
+	out.Reset()
+	if _, err := parse("--dry-run", "--json").run(&out, newPlan()); err != nil {
+		t.Fatal(err)
+	}
+	var got destructivePlan
+	if err := json.Unmarshal(out.Bytes(), &got); err != nil || !got.DryRun || len(got.Steps) != 2 || got.Steps[0].Action != "remove_worktree" {
+		t.Fatalf("JSON plan = %+v, %v", got, err)
+	}
+
+	if proceed, err := parse().run(&out, newPlan()); proceed || !errors.Is(err, errNotConfirmed) || !strings.Contains(err.Error(), "--yes") {
+		t.Fatalf("without a terminal = %v, %v; want --yes required", proceed, err)
+	}
+	if proceed, err := parse("--yes").run(&out, newPlan()); !proceed || err != nil {
+		t.Fatalf("--yes = %v, %v; want to proceed", proceed, err)
+	}
+
+	terminal = true
+	confirmInput = strings.NewReader("n\n")
+	if proceed, err := parse().run(&out, newPlan()); proceed || !errors.Is(err, errNotConfirmed) {
+		t.Fatalf("declined = %v, %v", proceed, err)
+	}
+	confirmInput = strings.NewReader("y\n")
+	if proceed, err := parse().run(&out, newPlan()); !proceed || err != nil {
+		t.Fatalf("confirmed = %v, %v", proceed, err)
+	}
+}
@@ -0,0 +1,159 @@
+//go:build !windows
+
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+	"time"
+
+	"github.com/andyrewlee/amux/internal/config"
+	"github.com/andyrewlee/amux/internal/logging"
+	"github.com/andyrewlee/amux/internal/schedule"
+)
+
+const scheduleUsage = "usage: amux schedule [run | list | history [-n runs]]"
+
+// runSchedule runs, lists, or shows the history of the scheduled agent tasks
+// in config.json and returns the process exit code.
+func runSchedule(args []string, out io.Writer) int {
+	action := "run"
+	if len(args) > 0 {
+		action, args = args[0], args[1:]
+	}
+	cfg, err := config.DefaultConfig()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	switch action {
+	case "history":
+		fs := flag.NewFlagSet("schedule history", flag.ContinueOnError)
+		fs.SetOutput(os.Stderr)
+		limit := fs.Int("n", 20, "number of runs to show; 0 shows everything")
+		if err := fs.Parse(args); err != nil {
+			return 2
+		}
+		if err := printScheduleHistory(out, schedule.HistoryPath(cfg.Paths.Home), *limit); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		return 0
+	case "run", "list":
+		if len(args) != 0 {
+			break
+		}
+		scheduler, err := schedule.New(schedule.Config{
+			Tasks:       cfg.Schedules,
+			Launcher:    schedule.NewAgentLauncher(cfg),
+			HistoryPath: schedule.HistoryPath(cfg.Paths.Home),
+			OnRun:       func(run schedule.Run) { reportScheduledRun(out, run) },
+		})
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		if action == "list" {
+			printScheduleList(out, scheduler.Entries(time.Now()))
+			return 0
+		}
+		if len(cfg.Schedules) == 0 {
+			fmt.Fprintf(os.Stderr, "no schedules in %s\n", cfg.Paths.ConfigPath)
+			return 1
+		}
+		return runScheduler(out, cfg, scheduler)
+	}
+	fmt.Fprintln(os.Stderr, scheduleUsage)
+	return 2
+}
+
+// runScheduler launches tasks until interrupted. Only one scheduler runs at
+// a time, so a task never launches twice.
+func runScheduler(out io.Writer, cfg *config.Config, scheduler *schedule.Scheduler) int {
+	lock, err := lockScheduler(filepath.Join(cfg.Paths.Home, "schedule.lock"))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	defer lock.Close()
+
+	if err := logging.Initialize(amuxLogDir(), logging.LevelInfo); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: could not initialize logging: %v\n", err)
+	}
+	defer logging.Close()
+
+	printScheduleList(out, scheduler.Entries(time.Now()))
+	fmt.Fprintln(out, "Press Ctrl+C to stop.")
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	if err := scheduler.Run(ctx); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	return 0
+}
+
+// lockScheduler takes the scheduler's lock file, failing at once if another
+// scheduler holds it. The lock lasts until the file is closed.
+func lockScheduler(path string) (*os.File, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return nil, err
+	}
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR, 0o600)
+	if err != nil {
+		return nil, err
+	}
+	if err := syscall.Flock(int(file.Fd()), syscall.LOCK_EX|syscall.LOCK_NB); err != nil {
+		_ = file.Close()
+		if errors.Is(err, syscall.EWOULDBLOCK) {
+			return nil, errors.New("amux schedule is already running")
+		}
+		return nil, err
+	}
+	return file, nil
+}
+
+func reportScheduledRun(out io.Writer, run schedule.Run) {
+	_ = schedule.WriteRuns(out, []schedule.Run{run})
+	if run.Failed() {
+		if err := schedule.NotifyFailure(run); err != nil {
+			logging.Warn("schedule: notifying about %s: %v", run.Task, err)
+		}
+	}
+}
+
+func printScheduleList(out io.Writer, entries []schedule.Entry) {
+	if len(entries) == 0 {
+		fmt.Fprintln(out, "No schedules configured.")
+		return
+	}
+	for _, e := range entries {
+		where := "fresh workspace"
+		if e.Task.Workspace != "" {
+			where = "workspace " + e.Task.Workspace
+		}
+		fmt.Fprintf(out, "%s  next %s  %s, %s\n", e.Task.Name, e.Next.Local().Format("2006-01-02 15:04"), e.Task.Project, where)
+	}
+}
+
+func printScheduleHistory(out io.Writer, path string, limit int) error {
+	runs, err := schedule.ReadRuns(path)
+	if err != nil {
+		return fmt.Errorf("read schedule history: %w", err)
+	}
+	if len(runs) == 0 {
+		fmt.Fprintln(out, "No scheduled runs yet.")
+		return nil
+	}
+	if limit > 0 && len(runs) > limit {
+		runs = runs[len(runs)-limit:]
+	}
+	return schedule.WriteRuns(out, runs)
+}
@@ -0,0 +1,62 @@
+//go:build !windows
+
+package main
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/andyrewlee/amux/internal/config"
+	"github.com/andyrewlee/amux/internal/schedule"
+)
+
+func TestLockSchedulerAllowsOneScheduler(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "schedule.lock")
+	first, err := lockScheduler(path)
+	if err != nil {
+		t.Fatalf("lockScheduler() error = %v", err)
+	}
+	if _, err := lockScheduler(path); err == nil || !strings.Contains(err.Error(), "already running") {
+		t.Fatalf("second lockScheduler() = %v, want already running", err)
+	}
+	first.Close()
+	again, err := lockScheduler(path)
+	if err != nil {
+		t.Fatalf("lockScheduler() after release error = %v", err)
+	}
+	again.Close()
+}
+
+func TestPrintScheduleHistory(t *testing.T) {
+	path := schedule.HistoryPath(t.TempDir())
+	var out bytes.Buffer
+	if err := printScheduleHistory(&out, path, 0); err != nil || !strings.Contains(out.String(), "No scheduled runs yet.") {
+		t.Fatalf("printScheduleHistory() = %q, %v", out.String(), err)
+	}
+	for _, task := range []string{"one", "two", "three"} {
+		if err := schedule.AppendRun(path, schedule.Run{Task: task, Started: time.Now()}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	out.Reset()
+	if err := printScheduleHistory(&out, path, 2); err != nil {
+		t.Fatal(err)
+	}
+	if got := out.String(); strings.Contains(got, "one") || !strings.Contains(got, "two") || !strings.Contains(got, "three") {
+		t.Fatalf("printScheduleHistory() = %q, want the last two runs", got)
+	}
+}
+
+func TestPrintScheduleList(t *testing.T) {
+	var out bytes.Buffer
+	printScheduleList(&out, []schedule.Entry{{
+		Task: config.ScheduledTask{Name: "deps", Project: "/src/api", Workspace: "main"},
+		Next: time.Date(2026, 10, 15, 3, 0, 0, 0, time.Local),
+	}})
+	if got := out.String(); got != "deps  next 2026-10-15 03:00  /src/api, workspace main\n" {
+		t.Fatalf("printScheduleList() = %q", got)
+	}
+}
@@ -0,0 +1,146 @@
+//go:build !windows
+
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"syscall"
+	"text/tabwriter"
+
+	"github.com/andyrewlee/amux/internal/config"
+	"github.com/andyrewlee/amux/internal/data"
+	"github.com/andyrewlee/amux/internal/sessions"
+	"github.com/andyrewlee/amux/internal/tmux"
+)
+
+const sessionUsage = "usage: amux session ls [--json] | amux session attach <name>"
+
+// execTmux replaces the process with the tmux command line argv.
+func execTmux(argv []string) error {
+	path, err := exec.LookPath(argv[0])
+	if err != nil {
+		return err
+	}
+	return syscall.Exec(path, argv, os.Environ())
+}
+
+// runSession lists the amux tmux sessions or attaches to one, and returns the
+// process exit code.
+func runSession(args []string, out io.Writer) int {
+	if len(args) == 0 {
+		fmt.Fprintln(os.Stderr, sessionUsage)
+		return 2
+	}
+	switch args[0] {
+	case "ls", "list":
+		return runSessionList(args[1:], out)
+	case "attach":
+		return runSessionAttach(args[1:])
+	}
+	fmt.Fprintln(os.Stderr, sessionUsage)
+	return 2
+}
+
+func runSessionList(args []string, out io.Writer) int {
+	fs := flag.NewFlagSet("session ls", flag.ContinueOnError)
+	fs.SetOutput(os.Stderr)
+	asJSON := fs.Bool("json", false, "print the sessions as JSON")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() != 0 {
+		fmt.Fprintln(os.Stderr, sessionUsage)
+		return 2
+	}
+	list, err := listSessions()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	if *asJSON {
+		err = writeSessionsJSON(out, list)
+	} else {
+		err = writeSessions(out, list)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	return 0
+}
+
+func runSessionAttach(args []string) int {
+	if len(args) != 1 {
+		fmt.Fprintln(os.Stderr, sessionUsage)
+		return 2
+	}
+	list, err := listSessions()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	s, err := sessions.Find(list, args[0])
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v; `amux session ls` lists them\n", err)
+		return 1
+	}
+	if os.Getenv("TMUX") != "" {
+		fmt.Fprintf(os.Stderr, "already inside tmux; run `TMUX= amux session attach %s` to nest it\n", args[0])
+		return 1
+	}
+	if err := execTmux(tmux.AttachArgs(s.TmuxSession, tmux.DefaultOptions())); err != nil {
+		fmt.Fprintf(os.Stderr, "attach %s: %v\n", s.Name, err)
+		return 1
+	}
+	return 0
+}
+
+// listSessions reads the sessions on the amux tmux server, named from the
+// saved workspaces.
+func listSessions() ([]sessions.Session, error) {
+	cfg, err := config.DefaultConfig()
+	if err != nil {
+		return nil, err
+	}
+	return sessions.List(tmux.DefaultOptions(), data.NewWorkspaceStore(cfg.Paths.MetadataRoot))
+}
+
+// writeSessions prints the sessions as a table.
+func writeSessions(out io.Writer, list []sessions.Session) error {
+	if len(list) == 0 {
+		_, err := fmt.Fprintln(out, "No amux sessions.")
+		return err
+	}
+	tw := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "NAME\tTYPE\tATTACHED\tTMUX SESSION")
+	for _, s := range list {
+		attached := "no"
+		if s.Attached {
+			attached = "yes"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", s.Name, s.Type, attached, s.TmuxSession)
+	}
+	return tw.Flush()
+}
+
+// writeSessionsJSON prints the sessions as a JSON array.
+func writeSessionsJSON(out io.Writer, list []sessions.Session) error {
+	if list == nil {
+		list = []sessions.Session{}
+	}
+	enc := json.NewEncoder(out)
+	enc.SetIndent("", "  ")
+	return enc.Encode(list)
+}
+
+// writeSessionJSON prints one session as a JSON object.
+func writeSessionJSON(out io.Writer, s sessions.Session) error {
+	enc := json.NewEncoder(out)
+	enc.SetIndent("", "  ")
+	return enc.Encode(s)
+}
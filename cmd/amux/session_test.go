@@ -0,0 +1,53 @@
+//go:build !windows
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/andyrewlee/amux/internal/sessions"
+)
+
+func TestWriteSessions(t *testing.T) {
+	list := []sessions.Session{
+		{Name: "amux/repo/feature/claude", TmuxSession: "amux-w1-a", Type: "agent", Attached: true},
+		{Name: "amux/repo/feature/terminal", TmuxSession: "amux-w1-t", Type: "terminal"},
+	}
+	var buf bytes.Buffer
+	if err := writeSessions(&buf, list); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 3 || !strings.HasPrefix(lines[0], "NAME") ||
+		!strings.Contains(lines[1], "amux/repo/feature/claude") || !strings.Contains(lines[1], "yes") {
+		t.Fatalf("table = %q", buf.String())
+	}
+
+	buf.Reset()
+	if err := writeSessionsJSON(&buf, list); err != nil {
+		t.Fatal(err)
+	}
+	var decoded []map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if decoded[0]["name"] != "amux/repo/feature/claude" || decoded[0]["tmux_session"] != "amux-w1-a" || decoded[0]["attached"] != true {
+		t.Fatalf("json = %v", decoded[0])
+	}
+
+	buf.Reset()
+	if err := writeSessionsJSON(&buf, nil); err != nil || strings.TrimSpace(buf.String()) != "[]" {
+		t.Fatalf("empty json = %q, %v", buf.String(), err)
+	}
+}
+
+func TestRunSessionUsage(t *testing.T) {
+	for _, args := range [][]string{nil, {"kill"}, {"attach"}, {"ls", "extra"}} {
+		if code := runSession(args, nil); code != 2 {
+			t.Errorf("runSession(%q) = %d, want 2", args, code)
+		}
+	}
+}
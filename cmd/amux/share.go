@@ -0,0 +1,160 @@
+//go:build !windows
+
+package main
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/andyrewlee/amux/internal/audit"
+	"github.com/andyrewlee/amux/internal/share"
+	"github.com/andyrewlee/amux/internal/tmux"
+)
+
+const shareUsage = "usage: amux share [--write] [--addr host:port [--cert file --key file]] <tmux-session>"
+
+// runShare serves one amux tmux session to browsers until interrupted and
+// returns the process exit code.
+func runShare(args []string) int {
+	fs := flag.NewFlagSet("share", flag.ContinueOnError)
+	fs.SetOutput(os.Stderr)
+	write := fs.Bool("write", false, "let approved viewers type into the session")
+	addr := fs.String("addr", "127.0.0.1:0", "listen address; a non-loopback address needs --cert and --key")
+	certFile := fs.String("cert", "", "TLS certificate file to serve HTTPS with")
+	keyFile := fs.String("key", "", "TLS private key file for --cert")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	opts := tmux.DefaultOptions()
+	if fs.NArg() != 1 {
+		fmt.Fprintln(os.Stderr, shareUsage)
+		if sessions, err := tmux.ListSessions(opts); err == nil && len(sessions) > 0 {
+			fmt.Fprintln(os.Stderr, "\nsessions:\n  "+strings.Join(sessions, "\n  "))
+		}
+		return 2
+	}
+	session := fs.Arg(0)
+	if state, err := tmux.SessionStateFor(session, opts); err != nil || !state.Exists {
+		fmt.Fprintf(os.Stderr, "no tmux session %q on the amux server\n", session)
+		return 1
+	}
+
+	auditLog := audit.Open(audit.Path(amuxLogDir()))
+	prompter := &sharePrompter{in: bufio.NewReader(os.Stdin), out: os.Stderr, session: session}
+	srv, err := share.New(share.Config{
+		Session:    session,
+		AllowInput: *write,
+		Capture:    func() (string, error) { return tmux.CapturePaneVisible(session, opts) },
+		Send: func(v share.Viewer, text string) error {
+			// Unrecorded input is refused rather than sent.
+			if err := auditLog.Record(audit.SourceShare, v.Remote, session, text); err != nil {
+				return err
+			}
+			return tmux.SendLiteral(session, text, opts)
+		},
+		Approve: prompter.approve,
+	})
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	ln, scheme, err := shareListener(*addr, *certFile, *keyFile)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	fmt.Fprintf(os.Stderr, "Sharing %s. Send this link; each viewer must be approved here:\n  %s\nPress Ctrl+C to stop.\n", session, srv.URL(scheme, ln.Addr().String()))
+
+	httpServer := &http.Server{Handler: srv, ReadHeaderTimeout: share.ReadHeaderTimeout}
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		defer cancel()
+		_ = httpServer.Shutdown(shutdownCtx)
+	}()
+	if err := httpServer.Serve(ln); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	return 0
+}
+
+// shareListener listens on addr and returns the scheme viewers reach it
+// over. With certFile and keyFile it serves HTTPS; without them it serves
+// plain HTTP, and only on loopback, where the link and keystrokes cannot be
+// read off the network.
+func shareListener(addr, certFile, keyFile string) (net.Listener, string, error) {
+	if (certFile == "") != (keyFile == "") {
+		return nil, "", errors.New("--cert and --key must be given together")
+	}
+	var tlsConfig *tls.Config
+	if certFile != "" {
+		cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+		if err != nil {
+			return nil, "", fmt.Errorf("load TLS certificate: %w", err)
+		}
+		tlsConfig = &tls.Config{Certificates: []tls.Certificate{cert}, MinVersion: tls.VersionTLS12}
+	} else if host, _, _ := net.SplitHostPort(addr); !isLoopbackHost(host) {
+		return nil, "", fmt.Errorf("refusing to serve plain HTTP on %s: pass --cert and --key, or reach a loopback address through an SSH tunnel", addr)
+	}
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, "", fmt.Errorf("listen on %s: %w", addr, err)
+	}
+	if tlsConfig == nil {
+		return ln, "http", nil
+	}
+	return tls.NewListener(ln, tlsConfig), "https", nil
+}
+
+func isLoopbackHost(host string) bool {
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// sharePrompter asks on the terminal whether each viewer may join, one
+// question at a time. Anything but an explicit yes declines.
+type sharePrompter struct {
+	mu      sync.Mutex
+	in      *bufio.Reader
+	out     io.Writer
+	session string
+}
+
+func (p *sharePrompter) approve(v share.Viewer) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	access := "view"
+	if v.Write {
+		access = "view and type into"
+	}
+	fmt.Fprintf(p.out, "%s wants to %s %s. Allow? [y/N] ", v.Remote, access, p.session)
+	answer, err := p.in.ReadString('\n')
+	if err != nil {
+		fmt.Fprintln(p.out)
+		return false
+	}
+	switch strings.ToLower(strings.TrimSpace(answer)) {
+	case "y", "yes":
+		return true
+	}
+	return false
+}
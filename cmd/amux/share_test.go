@@ -0,0 +1,114 @@
+//go:build !windows
+
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/andyrewlee/amux/internal/share"
+)
+
+func TestSharePrompterRequiresExplicitYes(t *testing.T) {
+	var out bytes.Buffer
+	p := &sharePrompter{in: bufio.NewReader(strings.NewReader("y\nsure\nYES\n")), out: &out, session: "amux-ws-tab"}
+
+	if !p.approve(share.Viewer{Remote: "10.0.0.2:5000", Write: true}) {
+		t.Fatal("y should approve")
+	}
+	if !strings.Contains(out.String(), "10.0.0.2:5000 wants to view and type into amux-ws-tab") {
+		t.Fatalf("prompt = %q, want viewer, access, and session", out.String())
+	}
+	if p.approve(share.Viewer{Remote: "10.0.0.3:5000"}) {
+		t.Fatal("an answer other than yes should decline")
+	}
+	if !p.approve(share.Viewer{Remote: "10.0.0.4:5000"}) {
+		t.Fatal("YES should approve")
+	}
+	if p.approve(share.Viewer{Remote: "10.0.0.5:5000"}) {
+		t.Fatal("closed input should decline")
+	}
+}
+
+func TestShareListenerRefusesPlainHTTPBeyondLoopback(t *testing.T) {
+	for _, addr := range []string{"0.0.0.0:0", ":0", "192.0.2.1:0"} {
+		if ln, _, err := shareListener(addr, "", ""); err == nil {
+			ln.Close()
+			t.Fatalf("shareListener(%q) served plain HTTP off loopback", addr)
+		}
+	}
+	if _, _, err := shareListener("127.0.0.1:0", "cert.pem", ""); err == nil {
+		t.Fatal("--cert without --key should be refused")
+	}
+	ln, scheme, err := shareListener("127.0.0.1:0", "", "")
+	if err != nil || scheme != "http" {
+		t.Fatalf("shareListener(loopback) = %q, %v; want plain HTTP", scheme, err)
+	}
+	ln.Close()
+}
+
+func TestShareListenerServesTLS(t *testing.T) {
+	certFile, keyFile := writeSelfSignedCert(t)
+	ln, scheme, err := shareListener("0.0.0.0:0", certFile, keyFile)
+	if err != nil || scheme != "https" {
+		t.Fatalf("shareListener() = %q, %v; want HTTPS", scheme, err)
+	}
+	defer ln.Close()
+	go func() {
+		if conn, err := ln.Accept(); err == nil {
+			_ = conn.(*tls.Conn).Handshake()
+			conn.Close()
+		}
+	}()
+	_, port, _ := net.SplitHostPort(ln.Addr().String())
+	conn, err := tls.Dial("tcp", net.JoinHostPort("127.0.0.1", port), &tls.Config{InsecureSkipVerify: true})
+	if err != nil {
+		t.Fatalf("TLS handshake: %v", err)
+	}
+	conn.Close()
+}
+
+// writeSelfSignedCert writes a throwaway certificate and key for 127.0.0.1.
+func writeSelfSignedCert(t *testing.T) (certFile, keyFile string) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		IPAddresses:  []net.IP{net.IPv4(127, 0, 0, 1)},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+	certFile, keyFile = filepath.Join(dir, "cert.pem"), filepath.Join(dir, "key.pem")
+	if err := os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return certFile, keyFile
+}
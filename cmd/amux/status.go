@@ -0,0 +1,90 @@
+//go:build !windows
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"time"
+
+	"github.com/andyrewlee/amux/internal/config"
+	"github.com/andyrewlee/amux/internal/data"
+	"github.com/andyrewlee/amux/internal/git"
+	"github.com/andyrewlee/amux/internal/report"
+)
+
+const statusUsage = "usage: amux status [--report md]"
+
+// runStatus prints a status report of every project's worktrees and returns
+// the process exit code. Markdown is the only format so far.
+func runStatus(args []string, out io.Writer) int {
+	fs := flag.NewFlagSet("status", flag.ContinueOnError)
+	fs.SetOutput(os.Stderr)
+	format := fs.String("report", "md", "report format (md)")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() != 0 || *format != "md" {
+		fmt.Fprintln(os.Stderr, statusUsage)
+		return 2
+	}
+	cfg, err := config.DefaultConfig()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	projects, err := loadProjects(data.NewRegistry(cfg.Paths.RegistryPath), data.NewWorkspaceStore(cfg.Paths.MetadataRoot))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	collected := report.Collect(projects, report.Options{IsAgent: cfg.IsAssistantKnown})
+	if _, err := io.WriteString(out, report.Markdown(collected, time.Now())); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	return 0
+}
+
+// loadProjects reads the registered projects and their saved workspaces,
+// primary checkout first, without touching any metadata.
+func loadProjects(registry *data.Registry, store *data.WorkspaceStore) ([]data.Project, error) {
+	paths, err := registry.Projects()
+	if err != nil {
+		return nil, fmt.Errorf("loading projects: %w", err)
+	}
+	var projects []data.Project
+	for _, path := range paths {
+		if !git.IsGitRepository(path) {
+			continue
+		}
+		project := data.NewProject(path)
+		stored, err := store.ListByRepo(path)
+		if err != nil {
+			return nil, fmt.Errorf("loading workspaces for %s: %w", path, err)
+		}
+		for _, ws := range stored {
+			project.Workspaces = append(project.Workspaces, *ws)
+		}
+		if !slices.ContainsFunc(project.Workspaces, data.Workspace.IsPrimaryCheckout) {
+			if branch, err := git.GetCurrentBranch(path); err == nil {
+				primary := data.NewWorkspace(project.Name, branch, "", path, path)
+				project.Workspaces = append(project.Workspaces, *primary)
+			}
+		}
+		slices.SortStableFunc(project.Workspaces, func(a, b data.Workspace) int {
+			switch {
+			case a.IsPrimaryCheckout() == b.IsPrimaryCheckout():
+				return 0
+			case a.IsPrimaryCheckout():
+				return -1
+			}
+			return 1
+		})
+		projects = append(projects, *project)
+	}
+	return projects, nil
+}
@@ -0,0 +1,51 @@
+//go:build !windows
+
+package main
+
+import (
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/andyrewlee/amux/internal/data"
+	"github.com/andyrewlee/amux/internal/testutil"
+)
+
+func TestLoadProjectsPutsPrimaryFirst(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	repo := testutil.InitRepo(t)
+	registry := data.NewRegistry(filepath.Join(t.TempDir(), "projects.json"))
+	if err := registry.AddProject(repo); err != nil {
+		t.Fatal(err)
+	}
+	if err := registry.AddProject(filepath.Join(t.TempDir(), "not-a-repo")); err != nil {
+		t.Fatal(err)
+	}
+	store := data.NewWorkspaceStore(t.TempDir())
+	if err := store.Save(data.NewWorkspace("feature", "feature", "main", repo, filepath.Join(t.TempDir(), "feature"))); err != nil {
+		t.Fatal(err)
+	}
+
+	projects, err := loadProjects(registry, store)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(projects) != 1 {
+		t.Fatalf("projects = %+v, want only the git repository", projects)
+	}
+	ws := projects[0].Workspaces
+	if len(ws) != 2 || !ws[0].IsPrimaryCheckout() || ws[1].Name != "feature" {
+		t.Fatalf("workspaces = %+v, want the primary checkout then feature", ws)
+	}
+}
+
+func TestRunStatusRejectsUnknownFormat(t *testing.T) {
+	if code := runStatus([]string{"--report", "html"}, nil); code != 2 {
+		t.Fatalf("runStatus(--report html) = %d, want 2", code)
+	}
+	if code := runStatus([]string{"extra"}, nil); code != 2 {
+		t.Fatalf("runStatus(extra) = %d, want 2", code)
+	}
+}
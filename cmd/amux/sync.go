@@ -0,0 +1,107 @@
+//go:build !windows
+
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/andyrewlee/amux/internal/config"
+	"github.com/andyrewlee/amux/internal/logging"
+	"github.com/andyrewlee/amux/internal/statesync"
+)
+
+const syncUsage = "usage: amux sync"
+
+// syncOnStartTimeout bounds the sync run before the TUI starts, so a slow
+// remote never holds up the launch for long.
+const syncOnStartTimeout = 15 * time.Second
+
+// runSync merges the settings and project list with the sync directory and
+// returns the process exit code.
+func runSync(args []string, out io.Writer) int {
+	fs := flag.NewFlagSet("sync", flag.ContinueOnError)
+	fs.SetOutput(os.Stderr)
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() != 0 {
+		fmt.Fprintln(os.Stderr, syncUsage)
+		return 2
+	}
+	paths, err := config.DefaultPaths()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	dir, ok := statesync.Dir(paths)
+	if !ok {
+		fmt.Fprintf(os.Stderr, "no sync directory at %s: clone a git repository there, link it to a synced folder, or set %s\n", dir, statesync.DirEnvVar)
+		return 1
+	}
+	res, err := statesync.Sync(context.Background(), paths, dir)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "sync: %v\n", err)
+		return 1
+	}
+	fmt.Fprint(out, syncSummary(res, paths.Home))
+	if res.GitErr != nil {
+		fmt.Fprintf(os.Stderr, "sync: git: %v\n", res.GitErr)
+		return 1
+	}
+	return 0
+}
+
+// syncSummary describes what a sync changed, one line each.
+func syncSummary(res statesync.Result, home string) string {
+	var b strings.Builder
+	kind := "directory"
+	if res.Git {
+		kind = "git repository"
+	}
+	fmt.Fprintf(&b, "synced with %s (%s)\n", res.Dir, kind)
+	for _, p := range res.ProjectsAdded {
+		fmt.Fprintf(&b, "  added project %s\n", p)
+	}
+	for _, p := range res.ProjectsRemoved {
+		fmt.Fprintf(&b, "  removed project %s\n", p)
+	}
+	if len(res.ConfigChanged) > 0 {
+		fmt.Fprintf(&b, "  updated config: %s\n", strings.Join(res.ConfigChanged, ", "))
+	}
+	for _, c := range res.Conflicts {
+		fmt.Fprintf(&b, "  conflict: %s changed on both machines; kept this machine's value\n", c.Key)
+	}
+	if len(res.Conflicts) > 0 {
+		fmt.Fprintf(&b, "  the other machine's config is in %s\n", filepath.Join(home, statesync.ConflictFile))
+	}
+	return b.String()
+}
+
+// syncOnStart runs a sync before the TUI loads its state, when a sync
+// directory is set up. Failures are logged and the TUI starts regardless.
+func syncOnStart(paths *config.Paths) {
+	dir, ok := statesync.Dir(paths)
+	if !ok {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), syncOnStartTimeout)
+	defer cancel()
+	res, err := statesync.Sync(ctx, paths, dir)
+	if err != nil {
+		logging.Warn("sync: %v", err)
+		return
+	}
+	if res.GitErr != nil {
+		logging.Warn("sync: git: %v", res.GitErr)
+	}
+	for _, line := range strings.Split(strings.TrimSpace(syncSummary(res, paths.Home)), "\n") {
+		logging.Info("sync: %s", strings.TrimSpace(line))
+	}
+}
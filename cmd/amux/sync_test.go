@@ -0,0 +1,31 @@
+//go:build !windows
+
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/andyrewlee/amux/internal/statesync"
+)
+
+func TestSyncSummary(t *testing.T) {
+	got := syncSummary(statesync.Result{
+		Dir:           "/home/me/.amux/sync",
+		Git:           true,
+		ProjectsAdded: []string{"/home/me/src/api"},
+		ConfigChanged: []string{"ui.theme", "schedules"},
+		Conflicts:     []statesync.Conflict{{Key: "ui.notify_on_done"}},
+	}, "/home/me/.amux")
+	for _, want := range []string{
+		"synced with /home/me/.amux/sync (git repository)",
+		"added project /home/me/src/api",
+		"updated config: ui.theme, schedules",
+		"conflict: ui.notify_on_done changed on both machines",
+		"/home/me/.amux/config.sync-conflict.json",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("summary missing %q:\n%s", want, got)
+		}
+	}
+}
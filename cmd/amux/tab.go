@@ -0,0 +1,163 @@
+//go:build !windows
+
+package main
+
+import (
+	"cmp"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/andyrewlee/amux/internal/config"
+	"github.com/andyrewlee/amux/internal/data"
+	"github.com/andyrewlee/amux/internal/sessions"
+	"github.com/andyrewlee/amux/internal/tmux"
+)
+
+const tabUsage = "usage: amux tab list [--workspace <name|path>] [--json]"
+
+// tabRecord is one tab as `amux tab list --json` prints it.
+type tabRecord struct {
+	// Name is the tab's session name, amux/<project>/<worktree>/<tab>.
+	Name        string `json:"name"`
+	Project     string `json:"project"`
+	Worktree    string `json:"worktree"`
+	WorkspaceID string `json:"workspace_id"`
+	Tab         string `json:"tab"`
+	Type        string `json:"type,omitempty"` // agent or terminal, when running
+	Assistant   string `json:"assistant,omitempty"`
+	TmuxSession string `json:"tmux_session,omitempty"`
+	// Running is whether the tab's tmux session is alive; a saved tab
+	// whose session ended is listed as not running.
+	Running  bool `json:"running"`
+	Attached bool `json:"attached"`
+}
+
+// runTab lists the tabs of every worktree and returns the process exit code.
+func runTab(args []string, out io.Writer) int {
+	if len(args) == 0 || (args[0] != "list" && args[0] != "ls") {
+		fmt.Fprintln(os.Stderr, tabUsage)
+		return 2
+	}
+	fs := flag.NewFlagSet("tab list", flag.ContinueOnError)
+	fs.SetOutput(os.Stderr)
+	asJSON := fs.Bool("json", false, "print the tabs as JSON")
+	workspace := fs.String("workspace", "", "only list the tabs of this worktree")
+	if err := fs.Parse(args[1:]); err != nil {
+		return 2
+	}
+	if fs.NArg() != 0 {
+		fmt.Fprintln(os.Stderr, tabUsage)
+		return 2
+	}
+	cfg, err := config.DefaultConfig()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	store := data.NewWorkspaceStore(cfg.Paths.MetadataRoot)
+	var workspaces []*data.Workspace
+	if *workspace != "" {
+		ws, err := findWorkspace(store, nil, *workspace)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		workspaces = []*data.Workspace{ws}
+	} else if workspaces, err = loadWorkspaces(store); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	list, err := sessions.List(tmux.DefaultOptions(), store)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	tabs := buildTabs(workspaces, list)
+	if *asJSON {
+		err = writeTabsJSON(out, tabs)
+	} else {
+		err = writeTabs(out, tabs)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	return 0
+}
+
+func loadWorkspaces(store *data.WorkspaceStore) ([]*data.Workspace, error) {
+	ids, err := store.List()
+	if err != nil {
+		return nil, fmt.Errorf("list workspaces: %w", err)
+	}
+	var out []*data.Workspace
+	for _, id := range ids {
+		if ws, err := store.Load(id); err == nil {
+			out = append(out, ws)
+		}
+	}
+	return out, nil
+}
+
+// buildTabs lists the running sessions of workspaces, then their saved tabs
+// whose sessions are gone, sorted by name.
+func buildTabs(workspaces []*data.Workspace, list []sessions.Session) []tabRecord {
+	var tabs []tabRecord
+	for _, ws := range workspaces {
+		wsID := string(ws.ID())
+		project := filepath.Base(ws.Repo)
+		live := make(map[string]bool)
+		for _, s := range list {
+			if s.WorkspaceID != wsID {
+				continue
+			}
+			live[s.TmuxSession] = true
+			tabs = append(tabs, tabRecord{
+				Name: s.Name, Project: project, Worktree: ws.Name, WorkspaceID: wsID, Tab: s.Tab,
+				Type: s.Type, Assistant: s.Assistant, TmuxSession: s.TmuxSession, Running: true, Attached: s.Attached,
+			})
+		}
+		for _, tab := range ws.OpenTabs {
+			if tab.SessionName != "" && live[tab.SessionName] {
+				continue
+			}
+			label := cmp.Or(strings.TrimSpace(tab.Name), tab.Assistant, "tab")
+			tabs = append(tabs, tabRecord{
+				Name: sessions.Name(project, ws.Name, label), Project: project, Worktree: ws.Name, WorkspaceID: wsID,
+				Tab: label, Assistant: tab.Assistant, TmuxSession: tab.SessionName,
+			})
+		}
+	}
+	slices.SortStableFunc(tabs, func(a, b tabRecord) int { return strings.Compare(a.Name, b.Name) })
+	return tabs
+}
+
+func writeTabs(out io.Writer, tabs []tabRecord) error {
+	if len(tabs) == 0 {
+		_, err := fmt.Fprintln(out, "No tabs.")
+		return err
+	}
+	tw := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "NAME\tTYPE\tRUNNING\tATTACHED")
+	yesNo := map[bool]string{true: "yes", false: "no"}
+	for _, t := range tabs {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", t.Name, cmp.Or(t.Type, "-"), yesNo[t.Running], yesNo[t.Attached])
+	}
+	return tw.Flush()
+}
+
+func writeTabsJSON(out io.Writer, tabs []tabRecord) error {
+	if tabs == nil {
+		tabs = []tabRecord{}
+	}
+	enc := json.NewEncoder(out)
+	enc.SetIndent("", "  ")
+	return enc.Encode(tabs)
+}
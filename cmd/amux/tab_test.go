@@ -0,0 +1,53 @@
+//go:build !windows
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/andyrewlee/amux/internal/data"
+	"github.com/andyrewlee/amux/internal/sessions"
+)
+
+func TestBuildTabs(t *testing.T) {
+	ws := data.NewWorkspace("feature", "feature", "main", "/src/api", "/src/api/feature")
+	ws.OpenTabs = []data.TabInfo{
+		{Assistant: "claude", SessionName: "amux-live"},
+		{Assistant: "codex", SessionName: "amux-gone"},
+	}
+	wsID := string(ws.ID())
+	list := []sessions.Session{
+		{Name: "amux/api/feature/claude", TmuxSession: "amux-live", Tab: "claude", Type: "agent", Assistant: "claude", WorkspaceID: wsID, Attached: true},
+		{Name: "amux-other", TmuxSession: "amux-other", WorkspaceID: "elsewhere"},
+	}
+	tabs := buildTabs([]*data.Workspace{ws}, list)
+	if len(tabs) != 2 {
+		t.Fatalf("tabs = %+v", tabs)
+	}
+	if live := tabs[0]; live.Name != "amux/api/feature/claude" || !live.Running || !live.Attached || live.Project != "api" {
+		t.Fatalf("live tab = %+v", live)
+	}
+	if gone := tabs[1]; gone.Name != "amux/api/feature/codex" || gone.Running || gone.TmuxSession != "amux-gone" {
+		t.Fatalf("saved tab = %+v", gone)
+	}
+
+	var buf bytes.Buffer
+	if err := writeTabsJSON(&buf, nil); err != nil || buf.String() != "[]\n" {
+		t.Fatalf("empty JSON = %q, %v", buf.String(), err)
+	}
+	buf.Reset()
+	if err := writeTabsJSON(&buf, tabs[:1]); err != nil {
+		t.Fatal(err)
+	}
+	var decoded []map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil {
+		t.Fatal(err)
+	}
+	for _, key := range []string{"name", "project", "worktree", "workspace_id", "tab", "type", "assistant", "tmux_session", "running", "attached"} {
+		if _, ok := decoded[0][key]; !ok {
+			t.Errorf("JSON tab has no %q", key)
+		}
+	}
+}
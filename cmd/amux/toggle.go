@@ -0,0 +1,76 @@
+//go:build !windows
+
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/andyrewlee/amux/internal/config"
+	"github.com/andyrewlee/amux/internal/editorapi"
+)
+
+const toggleUsage = "usage: amux toggle [--json]"
+
+// runToggle asks the running amux to show the agent most in need of
+// attention, for a global hotkey that also raises amux's terminal window,
+// and returns the process exit code.
+func runToggle(args []string, out io.Writer) int {
+	fs := flag.NewFlagSet("toggle", flag.ContinueOnError)
+	fs.SetOutput(os.Stderr)
+	asJSON := fs.Bool("json", false, "print the attention queue as JSON")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() != 0 {
+		fmt.Fprintln(os.Stderr, toggleUsage)
+		return 2
+	}
+	paths, err := config.DefaultPaths()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	if err := toggle(editorapi.SocketPath(paths.Home), out, *asJSON); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	return 0
+}
+
+// toggle reads the attention queue over the editor API on socket and shows
+// the first, most urgent, workspace's tab. With nothing waiting it changes
+// nothing, so the hotkey only brings amux to the front.
+func toggle(socket string, out io.Writer, asJSON bool) error {
+	var queue []editorapi.Attention
+	if err := editorapi.Call(socket, "agents.attention", nil, &queue); err != nil {
+		return fmt.Errorf("amux is not running, or is not serving %s: %w", socket, err)
+	}
+	if len(queue) > 0 {
+		params := map[string]any{"workspace": queue[0].WorkspaceID, "show_tab": true}
+		if err := editorapi.Call(socket, "worktrees.activate", params, nil); err != nil {
+			return err
+		}
+	}
+	if asJSON {
+		if queue == nil {
+			queue = []editorapi.Attention{}
+		}
+		enc := json.NewEncoder(out)
+		enc.SetIndent("", "  ")
+		return enc.Encode(queue)
+	}
+	var err error
+	switch len(queue) {
+	case 0:
+		_, err = fmt.Fprintln(out, "No agent needs attention.")
+	case 1:
+		_, err = fmt.Fprintf(out, "Showing %s (%s).\n", queue[0].Workspace, queue[0].Reason)
+	default:
+		_, err = fmt.Fprintf(out, "Showing %s (%s); %d more waiting.\n", queue[0].Workspace, queue[0].Reason, len(queue)-1)
+	}
+	return err
+}
@@ -0,0 +1,72 @@
+//go:build !windows
+
+package main
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/andyrewlee/amux/internal/data"
+	"github.com/andyrewlee/amux/internal/editorapi"
+	"github.com/andyrewlee/amux/internal/sessions"
+)
+
+func TestToggle(t *testing.T) {
+	// Socket paths are limited to about 100 bytes, more than t.TempDir can
+	// promise on some systems.
+	dir, err := os.MkdirTemp("", "amux-toggle")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = os.RemoveAll(dir) })
+	socket := editorapi.SocketPath(dir)
+
+	var out bytes.Buffer
+	if err := toggle(socket, &out, false); err == nil || !strings.Contains(err.Error(), "not running") {
+		t.Fatalf("toggle without amux = %v", err)
+	}
+
+	feature := data.NewWorkspace("feature", "feature", "main", "/src/api", "/work/api/feature")
+	other := data.NewWorkspace("other", "other", "main", "/src/api", "/work/api/other")
+	var shown []string
+	srv, err := editorapi.New(editorapi.Config{
+		Worktrees: func() ([]*data.Workspace, error) { return []*data.Workspace{feature, other}, nil },
+		Sessions:  func() ([]sessions.Session, error) { return nil, nil },
+		Open:      func(string, string) error { return nil },
+		Send:      func(sessions.Session, string, bool) error { return nil },
+		Activate: func(ws *data.Workspace, showTab bool) error {
+			if showTab {
+				shown = append(shown, ws.Name)
+			}
+			return nil
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ln, err := editorapi.Listen(socket)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = ln.Close() })
+	go func() { _ = srv.Serve(ln) }()
+
+	srv.SetAttention(nil)
+	if err := toggle(socket, &out, false); err != nil || out.String() != "No agent needs attention.\n" || len(shown) != 0 {
+		t.Fatalf("toggle with nothing waiting = %q, %v, shown %v", out.String(), err, shown)
+	}
+
+	srv.SetAttention([]editorapi.Attention{
+		{WorkspaceID: string(other.ID()), Workspace: "other", Reason: "mem limit"},
+		{WorkspaceID: string(feature.ID()), Workspace: "feature", Reason: "done"},
+	})
+	out.Reset()
+	if err := toggle(socket, &out, false); err != nil || out.String() != "Showing other (mem limit); 1 more waiting.\n" {
+		t.Fatalf("toggle = %q, %v", out.String(), err)
+	}
+	if len(shown) != 1 || shown[0] != "other" {
+		t.Fatalf("shown %v, want the most urgent workspace", shown)
+	}
+}
@@ -0,0 +1,172 @@
+//go:build !windows
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/andyrewlee/amux/internal/app"
+	"github.com/andyrewlee/amux/internal/config"
+	"github.com/andyrewlee/amux/internal/data"
+	"github.com/andyrewlee/amux/internal/timeline"
+)
+
+const workspaceUsage = "usage: amux workspace history <name|path> [--json] | amux workspace activate <name|path> | amux workspace delete <name|path> [--dry-run] [--yes] [--verbose] [--json]"
+
+// runWorkspace runs a workspace subcommand and returns the process exit code:
+// history prints a worktree's lifecycle timeline, activate switches the
+// running amux to a worktree, and delete deletes one.
+func runWorkspace(args []string, out io.Writer) int {
+	if len(args) == 0 {
+		fmt.Fprintln(os.Stderr, workspaceUsage)
+		return 2
+	}
+	switch args[0] {
+	case "history":
+		return runWorkspaceHistory(args[1:], out)
+	case "activate":
+		return runWorkspaceActivate(args[1:], out)
+	case "delete":
+		return runWorkspaceDelete(args[1:], out)
+	}
+	fmt.Fprintln(os.Stderr, workspaceUsage)
+	return 2
+}
+
+// parseNamed parses args as one name and fs's flags, accepting the flags
+// after the name too, as in `history feature --json`. It returns false,
+// after printing usage, when args do not fit.
+func parseNamed(fs *flag.FlagSet, args []string, usage string) (string, bool) {
+	var name string
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		name, args = args[0], args[1:]
+	}
+	if err := fs.Parse(args); err != nil {
+		return "", false
+	}
+	if name == "" && fs.NArg() == 1 {
+		name = fs.Arg(0)
+	} else if fs.NArg() != 0 || name == "" {
+		fmt.Fprintln(os.Stderr, usage)
+		return "", false
+	}
+	return name, true
+}
+
+func runWorkspaceHistory(args []string, out io.Writer) int {
+	fs := flag.NewFlagSet("workspace history", flag.ContinueOnError)
+	fs.SetOutput(os.Stderr)
+	asJSON := fs.Bool("json", false, "print the timeline as JSON")
+	name, ok := parseNamed(fs, args, workspaceUsage)
+	if !ok {
+		return 2
+	}
+	cfg, err := config.DefaultConfig()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	ws, err := findWorkspace(data.NewWorkspaceStore(cfg.Paths.MetadataRoot), data.NewWorkspaceTrash(cfg.Paths.TrashRoot), name)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	events := timeline.Build(ws)
+	if *asJSON {
+		err = timeline.WriteJSON(out, events)
+	} else if len(events) == 0 {
+		fmt.Fprintf(out, "No history recorded for %s.\n", ws.Name)
+	} else {
+		err = timeline.Write(out, events)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	return 0
+}
+
+// runWorkspaceActivate asks the running amux to switch to a worktree.
+func runWorkspaceActivate(args []string, out io.Writer) int {
+	fs := flag.NewFlagSet("workspace activate", flag.ContinueOnError)
+	fs.SetOutput(os.Stderr)
+	name, ok := parseNamed(fs, args, workspaceUsage)
+	if !ok {
+		return 2
+	}
+	cfg, err := config.DefaultConfig()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	ws, err := findWorkspace(data.NewWorkspaceStore(cfg.Paths.MetadataRoot), nil, name)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	if !instanceRunning(cfg.Paths.Home) {
+		fmt.Fprintln(os.Stderr, "amux is not running")
+		return 1
+	}
+	if err := app.RequestActivate(cfg.Paths.Home, ws.ID()); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	fmt.Fprintf(out, "Asked amux to activate %s.\n", ws.Name)
+	return 0
+}
+
+// findWorkspace returns the workspace named name, or rooted at the path name,
+// looking in trash, when given, if no live workspace matches so deleted
+// worktrees can still be reviewed.
+func findWorkspace(store *data.WorkspaceStore, trash *data.WorkspaceTrash, name string) (*data.Workspace, error) {
+	path := name
+	if abs, err := filepath.Abs(name); err == nil {
+		path = abs
+	}
+	matches := func(ws *data.Workspace) bool {
+		return ws.Name == name || filepath.Clean(ws.Root) == path
+	}
+
+	var found []*data.Workspace
+	ids, err := store.List()
+	if err != nil {
+		return nil, fmt.Errorf("list workspaces: %w", err)
+	}
+	for _, id := range ids {
+		ws, err := store.Load(id)
+		if err == nil && matches(ws) {
+			found = append(found, ws)
+		}
+	}
+	if len(found) == 0 && trash != nil {
+		trashed, err := trash.List()
+		if err != nil {
+			return nil, fmt.Errorf("list trash: %w", err)
+		}
+		// The trash is newest first, so a name reused after a delete
+		// resolves to the latest one.
+		for _, entry := range trashed {
+			if matches(&entry.Workspace) {
+				ws := entry.Workspace
+				return &ws, nil
+			}
+		}
+	}
+	if len(found) == 0 {
+		return nil, fmt.Errorf("no workspace named %q", name)
+	}
+	if len(found) > 1 {
+		roots := make([]string, len(found))
+		for i, ws := range found {
+			roots[i] = ws.Root
+		}
+		return nil, fmt.Errorf("%d workspaces are named %q; pass its path instead: %s", len(found), name, strings.Join(roots, ", "))
+	}
+	return found[0], nil
+}
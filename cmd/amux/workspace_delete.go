@@ -0,0 +1,144 @@
+//go:build !windows
+
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/andyrewlee/amux/internal/config"
+	"github.com/andyrewlee/amux/internal/data"
+	"github.com/andyrewlee/amux/internal/git"
+	"github.com/andyrewlee/amux/internal/sessions"
+	"github.com/andyrewlee/amux/internal/timeline"
+	"github.com/andyrewlee/amux/internal/tmux"
+)
+
+// listStoreSessions is a test seam for listing the tmux sessions of the
+// workspaces in store.
+var listStoreSessions = func(store *data.WorkspaceStore) ([]sessions.Session, error) {
+	return sessions.List(tmux.DefaultOptions(), store)
+}
+
+// runWorkspaceDelete deletes a worktree the way the dashboard does: its tmux
+// sessions, worktree, and branch are removed and its metadata moves to the
+// trash, along with its uncommitted changes, so it can be restored in amux.
+func runWorkspaceDelete(args []string, out io.Writer) int {
+	fs := flag.NewFlagSet("workspace delete", flag.ContinueOnError)
+	fs.SetOutput(os.Stderr)
+	flags := addPlanFlags(fs)
+	name, ok := parseNamed(fs, args, workspaceUsage)
+	if !ok {
+		return 2
+	}
+	cfg, err := config.DefaultConfig()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	store := data.NewWorkspaceStore(cfg.Paths.MetadataRoot)
+	ws, err := findWorkspace(store, nil, name)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	plan, tmuxSessions, err := workspaceDeletePlan(cfg.Paths, store, ws)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	if proceed, err := flags.run(out, plan); !proceed {
+		return planExitCode(err)
+	}
+	warning, err := deleteWorkspace(store, data.NewWorkspaceTrash(cfg.Paths.TrashRoot), ws, tmuxSessions)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	if warning != "" {
+		fmt.Fprintln(os.Stderr, warning)
+	}
+	if *flags.json {
+		err = writePlan(out, plan, true)
+	} else {
+		_, err = fmt.Fprintf(out, "Deleted %s. Press T in amux to restore it from the trash.\n", ws.Name)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	return 0
+}
+
+// workspaceDeletePlan lists what deleting ws removes, and the tmux sessions
+// to kill. Only worktrees amux created under its workspaces root can be
+// deleted; a project's own checkout cannot.
+func workspaceDeletePlan(paths *config.Paths, store *data.WorkspaceStore, ws *data.Workspace) (*destructivePlan, []string, error) {
+	rel, err := filepath.Rel(paths.WorkspacesRoot, ws.Root)
+	if err != nil || rel == "." || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return nil, nil, fmt.Errorf("%s is not a worktree amux created under %s", ws.Root, paths.WorkspacesRoot)
+	}
+	plan := &destructivePlan{Command: "workspace delete"}
+	list, err := listStoreSessions(store)
+	if err != nil {
+		return nil, nil, fmt.Errorf("list sessions: %w", err)
+	}
+	var tmuxSessions []string
+	for _, s := range list {
+		if s.WorkspaceID == string(ws.ID()) {
+			plan.add("kill_session", s.TmuxSession)
+			tmuxSessions = append(tmuxSessions, s.TmuxSession)
+		}
+	}
+	plan.add("remove_worktree", ws.Root)
+	if ws.Branch != "" {
+		plan.add("delete_branch", ws.Branch)
+	}
+	plan.add("remove_metadata", string(ws.ID()))
+	plan.add("keep_in_trash", paths.TrashRoot)
+	return plan, tmuxSessions, nil
+}
+
+// deleteWorkspace carries out a workspace delete plan. The worktree's HEAD,
+// uncommitted changes, and final timeline are read first so the trash entry
+// can restore it. A branch that cannot be deleted is left behind with a
+// warning, as the dashboard does.
+func deleteWorkspace(store *data.WorkspaceStore, trash *data.WorkspaceTrash, ws *data.Workspace, tmuxSessions []string) (string, error) {
+	head, _ := git.GetHeadCommit(ws.Root)
+	changes, _ := git.UncommittedChanges(context.Background(), ws.Root)
+	trashed := *ws
+	trashed.History = timeline.Merge(ws.History, timeline.Derived(ws))
+	trashed.RecordHistory(data.HistoryDeleted, "", time.Now())
+
+	if err := git.RemoveWorkspace(ws.Repo, ws.Root); err != nil {
+		return "", fmt.Errorf("remove worktree: %w", err)
+	}
+	var errs []error
+	for _, session := range tmuxSessions {
+		if err := killSession(session, tmux.DefaultOptions()); err != nil {
+			errs = append(errs, fmt.Errorf("kill session %s: %w", session, err))
+		}
+	}
+	var warning string
+	if ws.Branch != "" {
+		if err := git.DeleteBranch(ws.Repo, ws.Branch); err != nil {
+			warning = fmt.Sprintf("workspace deleted but branch %s was left behind: %v", ws.Branch, err)
+		}
+	}
+	if err := trash.Put(&trashed, head); err != nil {
+		errs = append(errs, fmt.Errorf("keep in trash: %w", err))
+	} else if err := trash.PutChanges(ws.ID(), changes); err != nil {
+		errs = append(errs, fmt.Errorf("keep changes in trash: %w", err))
+	}
+	if err := store.Delete(ws.ID()); err != nil {
+		errs = append(errs, fmt.Errorf("remove metadata: %w", err))
+	}
+	return warning, errors.Join(errs...)
+}
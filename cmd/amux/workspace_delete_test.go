@@ -0,0 +1,84 @@
+//go:build !windows
+
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/andyrewlee/amux/internal/config"
+	"github.com/andyrewlee/amux/internal/data"
+	"github.com/andyrewlee/amux/internal/sessions"
+	"github.com/andyrewlee/amux/internal/testutil"
+	"github.com/andyrewlee/amux/internal/tmux"
+)
+
+func TestWorkspaceDelete(t *testing.T) {
+	repo := testutil.InitRepo(t)
+	home := t.TempDir()
+	paths := &config.Paths{WorkspacesRoot: filepath.Join(home, "workspaces"), TrashRoot: filepath.Join(home, "trash")}
+	root := filepath.Join(paths.WorkspacesRoot, "repo", "feature")
+	testutil.RunGit(t, repo, "worktree", "add", "-b", "feature", root)
+	if err := os.WriteFile(filepath.Join(root, "notes.txt"), []byte("wip\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	store := data.NewWorkspaceStore(filepath.Join(home, "metadata"))
+	ws := data.NewWorkspace("feature", "feature", "main", repo, root)
+	if err := store.Save(ws); err != nil {
+		t.Fatal(err)
+	}
+
+	origList, origKill := listStoreSessions, killSession
+	t.Cleanup(func() { listStoreSessions, killSession = origList, origKill })
+	listStoreSessions = func(*data.WorkspaceStore) ([]sessions.Session, error) {
+		return []sessions.Session{
+			{TmuxSession: "amux-feature-agent", WorkspaceID: string(ws.ID())},
+			{TmuxSession: "amux-other", WorkspaceID: "other"},
+		}, nil
+	}
+	var killed []string
+	killSession = func(name string, _ tmux.Options) error {
+		killed = append(killed, name)
+		return nil
+	}
+
+	primary := data.NewWorkspace("repo", "main", "main", repo, repo)
+	if _, _, err := workspaceDeletePlan(paths, store, primary); err == nil {
+		t.Fatal("the project's own checkout must not be deletable")
+	}
+	plan, tmuxSessions, err := workspaceDeletePlan(paths, store, ws)
+	if err != nil {
+		t.Fatalf("workspaceDeletePlan() error = %v", err)
+	}
+	var actions []string
+	for _, step := range plan.Steps {
+		actions = append(actions, step.Action+" "+step.Target)
+	}
+	want := "kill_session amux-feature-agent|remove_worktree " + root + "|delete_branch feature|remove_metadata " + string(ws.ID()) + "|keep_in_trash " + paths.TrashRoot
+	if got := strings.Join(actions, "|"); got != want {
+		t.Fatalf("plan = %s, want %s", got, want)
+	}
+
+	trash := data.NewWorkspaceTrash(paths.TrashRoot)
+	if warning, err := deleteWorkspace(store, trash, ws, tmuxSessions); err != nil || warning != "" {
+		t.Fatalf("deleteWorkspace() = %q, %v", warning, err)
+	}
+	if _, err := os.Stat(root); !os.IsNotExist(err) {
+		t.Fatalf("worktree still exists: %v", err)
+	}
+	if branches := testutil.RunGit(t, repo, "branch", "--list", "feature"); branches != "" {
+		t.Fatalf("branch still exists: %q", branches)
+	}
+	if len(killed) != 1 || killed[0] != "amux-feature-agent" {
+		t.Fatalf("killed = %v, want only the workspace's session", killed)
+	}
+	if _, err := store.Load(ws.ID()); err == nil {
+		t.Fatal("metadata should be removed")
+	}
+	entry, err := trash.Get(ws.ID())
+	if err != nil || entry.HeadCommit == "" || !entry.HasChanges {
+		t.Fatalf("trash entry = %+v, %v; want the HEAD and the changes kept", entry, err)
+	}
+}
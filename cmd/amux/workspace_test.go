@@ -0,0 +1,49 @@
+//go:build !windows
+
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/andyrewlee/amux/internal/data"
+)
+
+func TestFindWorkspace(t *testing.T) {
+	store := data.NewWorkspaceStore(t.TempDir())
+	trash := data.NewWorkspaceTrash(t.TempDir())
+	save := func(name, root string) *data.Workspace {
+		t.Helper()
+		ws := data.NewWorkspace(name, name, "origin/main", "/repo", root)
+		ws.RecordHistory(data.HistoryCreated, "", time.Now())
+		if err := store.Save(ws); err != nil {
+			t.Fatal(err)
+		}
+		return ws
+	}
+	save("feature", "/work/repo/feature")
+	save("fix", "/work/repo/fix")
+	save("fix", "/work/other/fix")
+	deleted := data.NewWorkspace("old", "old", "origin/main", "/repo", "/work/repo/old")
+	deleted.RecordHistory(data.HistoryDeleted, "", time.Now())
+	if err := trash.Put(deleted, ""); err != nil {
+		t.Fatal(err)
+	}
+
+	if ws, err := findWorkspace(store, trash, "feature"); err != nil || ws.Root != "/work/repo/feature" || len(ws.History) != 1 {
+		t.Fatalf("findWorkspace(feature) = %+v, %v", ws, err)
+	}
+	if _, err := findWorkspace(store, trash, "fix"); err == nil || !strings.Contains(err.Error(), "/work/other/fix") {
+		t.Fatalf("ambiguous name error = %v, want the candidate paths", err)
+	}
+	if ws, err := findWorkspace(store, trash, "/work/other/fix"); err != nil || ws.Root != "/work/other/fix" {
+		t.Fatalf("findWorkspace(path) = %+v, %v", ws, err)
+	}
+	if ws, err := findWorkspace(store, trash, "old"); err != nil || ws.History[0].Kind != data.HistoryDeleted {
+		t.Fatalf("findWorkspace(deleted) = %+v, %v, want the trashed timeline", ws, err)
+	}
+	if _, err := findWorkspace(store, trash, "missing"); err == nil {
+		t.Fatal("expected an error for an unknown workspace")
+	}
+}
@@ -9,6 +9,7 @@ require (
 	charm.land/bubbletea/v2 v2.0.8
 	charm.land/lipgloss/v2 v2.0.5
 	github.com/atotto/clipboard v0.1.4
+	github.com/charmbracelet/colorprofile v0.4.3
 	// ultraviolet is Charm's untagged pre-release render engine. Its
 	// pseudo-version is driven by charm.land/bubbletea/v2 (MVS selects
 	// bubbletea's requirement). Do NOT bump it independently of bubbletea — a
@@ -25,7 +26,6 @@ require (
 )
 
 require (
-	github.com/charmbracelet/colorprofile v0.4.3 // indirect
 	github.com/charmbracelet/x/termios v0.1.1 // indirect
 	github.com/charmbracelet/x/windows v0.2.2 // indirect
 	github.com/clipperhouse/uax29/v2 v2.7.0 // indirect
@@ -0,0 +1,99 @@
+package app
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+
+	tea "charm.land/bubbletea/v2"
+
+	"github.com/andyrewlee/amux/internal/data"
+	"github.com/andyrewlee/amux/internal/fsatomic"
+	"github.com/andyrewlee/amux/internal/ui/common"
+)
+
+// activateRequestFile, in the amux home, asks the running amux to switch to
+// a workspace. `amux workspace activate` writes it.
+const activateRequestFile = "activate-request.json"
+
+// activateRequestInterval is how often the request file is checked.
+const activateRequestInterval = time.Second
+
+type activateRequest struct {
+	WorkspaceID string `json:"workspace_id"`
+	// ShowTab also shows the workspace's tab and focuses it, as when
+	// focus follows attention.
+	ShowTab bool      `json:"show_tab,omitempty"`
+	At      time.Time `json:"at"`
+}
+
+// activateRequestTick carries the request found on disk, if any.
+type activateRequestTick struct {
+	req activateRequest
+}
+
+// RequestActivate asks the amux running on the state in home to activate the
+// workspace with id.
+func RequestActivate(home string, id data.WorkspaceID) error {
+	return writeActivateRequest(home, activateRequest{WorkspaceID: string(id)})
+}
+
+// RequestShowTab asks the amux running on the state in home to activate the
+// workspace with id and focus its tab, for `amux toggle`.
+func RequestShowTab(home string, id data.WorkspaceID) error {
+	return writeActivateRequest(home, activateRequest{WorkspaceID: string(id), ShowTab: true})
+}
+
+func writeActivateRequest(home string, req activateRequest) error {
+	req.At = time.Now().UTC()
+	return fsatomic.WriteJSON(filepath.Join(home, activateRequestFile), req)
+}
+
+func (a *App) startActivateRequestTicker() tea.Cmd {
+	if a.config == nil || a.config.Paths == nil {
+		return nil
+	}
+	path := filepath.Join(a.config.Paths.Home, activateRequestFile)
+	return common.SafeTick(activateRequestInterval, func(time.Time) tea.Msg {
+		var req activateRequest
+		if raw, err := os.ReadFile(path); err == nil {
+			_ = json.Unmarshal(raw, &req)
+		}
+		return activateRequestTick{req: req}
+	})
+}
+
+func (a *App) handleActivateRequestTick(msg activateRequestTick) tea.Cmd {
+	return common.SafeBatch(a.takeActivateRequest(msg.req), a.startActivateRequestTicker())
+}
+
+// takeActivateRequest activates the requested workspace once per request.
+// Requests made before this amux started are ignored.
+func (a *App) takeActivateRequest(req activateRequest) tea.Cmd {
+	if req.WorkspaceID == "" || !req.At.After(a.activateHandledAt) {
+		return nil
+	}
+	a.activateHandledAt = req.At
+	if a.dashboard == nil {
+		return nil
+	}
+	if req.ShowTab && a.center != nil {
+		if ref, ok := a.workspaceTab(req.WorkspaceID); ok {
+			return a.showAgentTab(ref)
+		}
+	}
+	for _, entry := range a.dashboard.Workspaces() {
+		if string(entry.Workspace.ID()) != req.WorkspaceID {
+			continue
+		}
+		if a.activeWorkspace != nil && a.activeWorkspace.ID() == entry.Workspace.ID() {
+			return nil
+		}
+		return a.activateWorkspaceEntry(entry)
+	}
+	if a.toast == nil {
+		return nil
+	}
+	return a.toast.ShowWarning("Asked to activate a workspace amux does not list")
+}
@@ -0,0 +1,40 @@
+package app
+
+import (
+	"testing"
+	"time"
+
+	"github.com/andyrewlee/amux/internal/config"
+	"github.com/andyrewlee/amux/internal/messages"
+)
+
+func TestActivateRequest(t *testing.T) {
+	app, _, featWS := newAgentCycleTestApp(t)
+	home := t.TempDir()
+	app.config = &config.Config{Paths: &config.Paths{Home: home}}
+	app.activateHandledAt = time.Now().Add(-time.Minute)
+
+	if err := RequestActivate(home, featWS.ID()); err != nil {
+		t.Fatalf("RequestActivate: %v", err)
+	}
+	tick, ok := app.startActivateRequestTicker()().(activateRequestTick)
+	if !ok || tick.req.WorkspaceID != string(featWS.ID()) {
+		t.Fatalf("tick = %+v, want the request read back", tick)
+	}
+	cmd := app.takeActivateRequest(tick.req)
+	if cmd == nil {
+		t.Fatal("expected the request to activate the workspace")
+	}
+	if msg, ok := cmd().(messages.WorkspaceActivated); !ok || msg.Workspace.Root != featWS.Root {
+		t.Fatalf("activated %+v, want the feat workspace", msg)
+	}
+	if app.takeActivateRequest(tick.req) != nil {
+		t.Fatal("a request should be taken up once")
+	}
+
+	stale := activateRequest{WorkspaceID: string(featWS.ID()), At: time.Now().Add(-time.Hour)}
+	app.activateHandledAt = time.Now()
+	if app.takeActivateRequest(stale) != nil {
+		t.Fatal("a request from before amux started should be ignored")
+	}
+}
@@ -0,0 +1,124 @@
+package app
+
+import (
+	"fmt"
+	"time"
+
+	tea "charm.land/bubbletea/v2"
+
+	"github.com/andyrewlee/amux/internal/app/activity"
+	"github.com/andyrewlee/amux/internal/data"
+	"github.com/andyrewlee/amux/internal/feed"
+	"github.com/andyrewlee/amux/internal/messages"
+	"github.com/andyrewlee/amux/internal/ui/common"
+)
+
+// activityFeedState holds the activity feed and what it compares against to
+// find new entries.
+type activityFeedState struct {
+	feed feed.Feed
+	// agentStates is the last agent state per workspace, for the
+	// Working and Done edges.
+	agentStates map[string]activity.AgentState
+	// gitChanges is the last number of changed files per worktree root.
+	gitChanges map[string]int
+	// listener, when set, is told of each entry added.
+	listener func(wsID string, e feed.Entry)
+}
+
+// SetFeedListener has fn called with each entry added to the activity feed
+// and the ID of the workspace it is about. fn runs on the UI goroutine and
+// must not block. Call it before the program runs.
+func (a *App) SetFeedListener(fn func(wsID string, e feed.Entry)) {
+	a.activityFeed.listener = fn
+}
+
+// feedAdd appends an entry about the workspace wsID to the activity feed.
+func (a *App) feedAdd(kind feed.Kind, wsID, text string) {
+	e := feed.Entry{
+		At:        time.Now(),
+		Kind:      kind,
+		Workspace: a.workspaceLabel(wsID),
+		Text:      text,
+	}
+	a.activityFeed.feed.Add(e)
+	if a.activityFeed.listener != nil {
+		a.activityFeed.listener(wsID, e)
+	}
+}
+
+// feedAgentEdges adds an entry for each workspace whose agent started
+// producing output or finished since the last call.
+func (a *App) feedAgentEdges(states map[string]activity.AgentState) {
+	prev := a.activityFeed.agentStates
+	a.activityFeed.agentStates = states
+	for wsID, st := range states {
+		switch {
+		case st == activity.StateWorking && prev[wsID] != activity.StateWorking:
+			a.feedAdd(feed.KindOutput, wsID, "agent is producing output")
+		case st == activity.StateDone && prev[wsID] == activity.StateWorking:
+			a.feedAdd(feed.KindDone, wsID, "agent finished")
+		}
+	}
+}
+
+// feedGitStatus adds an entry when the number of changed files in a
+// worktree changes. The first status seen for a worktree only seeds it.
+func (a *App) feedGitStatus(msg messages.GitStatusResult) {
+	if msg.Err != nil || msg.Status == nil {
+		return
+	}
+	var ws *data.Workspace
+	a.eachWorkspaceUntil(func(candidate *data.Workspace, _ *data.Project) bool {
+		if rootsReferToSameWorkspace(candidate.Root, msg.Root) {
+			ws = candidate
+		}
+		return ws != nil
+	})
+	if ws == nil {
+		return
+	}
+	n := len(msg.Status.Staged) + len(msg.Status.Unstaged) + len(msg.Status.Untracked)
+	prev, seen := a.activityFeed.gitChanges[msg.Root]
+	if a.activityFeed.gitChanges == nil {
+		a.activityFeed.gitChanges = make(map[string]int)
+	}
+	a.activityFeed.gitChanges[msg.Root] = n
+	if !seen || prev == n {
+		return
+	}
+	text := "working tree clean"
+	switch {
+	case n == 1:
+		text = "1 file changed"
+	case n > 1:
+		text = fmt.Sprintf("%d files changed", n)
+	}
+	a.feedAdd(feed.KindGit, string(ws.ID()), text)
+}
+
+// showActivityFeed lists what happened across all worktrees, newest first,
+// marking what arrived since the feed was last opened.
+func (a *App) showActivityFeed() tea.Cmd {
+	f := &a.activityFeed.feed
+	entries := f.Entries()
+	if len(entries) == 0 {
+		return a.toast.ShowInfo("Nothing has happened yet")
+	}
+	lines := make([]string, len(entries))
+	for i, e := range entries {
+		mark := " "
+		if f.IsUnseen(e) {
+			mark = "•"
+		}
+		lines[i] = fmt.Sprintf("%s %s  %-9s  %s: %s", mark, e.At.Format("15:04"), e.Kind, e.Workspace, e.Text)
+	}
+	message := "Activity across all worktrees, newest first."
+	if n := f.Unseen(); n > 0 {
+		message = fmt.Sprintf("%d new since you last looked (•). ", n) + message
+	}
+	f.MarkSeen(time.Now())
+	a.dialog = common.NewListDialog(DialogActivityFeed, "Activity", message, lines)
+	a.presentDialog(a.dialog)
+	return nil
+}
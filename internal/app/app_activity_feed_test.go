@@ -0,0 +1,80 @@
+package app
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/andyrewlee/amux/internal/app/activity"
+	"github.com/andyrewlee/amux/internal/config"
+	"github.com/andyrewlee/amux/internal/feed"
+	"github.com/andyrewlee/amux/internal/git"
+	"github.com/andyrewlee/amux/internal/messages"
+	"github.com/andyrewlee/amux/internal/ui/common"
+)
+
+func TestFeedAgentEdges(t *testing.T) {
+	app, ws := newNotifyTestApp(config.UISettings{})
+	wsID := string(ws.ID())
+	var heard []string
+	app.SetFeedListener(func(id string, e feed.Entry) { heard = append(heard, id+" "+string(e.Kind)) })
+
+	app.feedAgentEdges(map[string]activity.AgentState{wsID: activity.StateWorking})
+	app.feedAgentEdges(map[string]activity.AgentState{wsID: activity.StateWorking})
+	app.feedAgentEdges(map[string]activity.AgentState{wsID: activity.StateDone})
+	app.feedAgentEdges(map[string]activity.AgentState{wsID: activity.StateIdle})
+
+	entries := app.activityFeed.feed.Entries()
+	if len(entries) != 2 || entries[0].Kind != feed.KindDone || entries[1].Kind != feed.KindOutput {
+		t.Fatalf("entries = %+v, want one output and one done entry", entries)
+	}
+	if entries[0].Workspace != "feature" {
+		t.Fatalf("entry workspace = %q, want the workspace name", entries[0].Workspace)
+	}
+	if len(heard) != 2 || heard[1] != wsID+" done" {
+		t.Fatalf("listener heard %v, want both entries with the workspace ID", heard)
+	}
+}
+
+func TestFeedGitStatus(t *testing.T) {
+	app, ws := newNotifyTestApp(config.UISettings{})
+	status := func(n int) messages.GitStatusResult {
+		st := &git.StatusResult{}
+		for range n {
+			st.Unstaged = append(st.Unstaged, git.Change{Path: "f.go"})
+		}
+		return messages.GitStatusResult{Root: ws.Root, Status: st}
+	}
+
+	app.feedGitStatus(status(0))
+	app.feedGitStatus(status(0))
+	if n := len(app.activityFeed.feed.Entries()); n != 0 {
+		t.Fatalf("%d entries after the first status, want it to only seed", n)
+	}
+	app.feedGitStatus(status(2))
+	app.feedGitStatus(messages.GitStatusResult{Root: "/elsewhere", Status: &git.StatusResult{}})
+	entries := app.activityFeed.feed.Entries()
+	if len(entries) != 1 || entries[0].Kind != feed.KindGit || entries[0].Text != "2 files changed" {
+		t.Fatalf("entries = %+v, want one git entry", entries)
+	}
+}
+
+func TestShowActivityFeedMarksSeen(t *testing.T) {
+	app, ws := newNotifyTestApp(config.UISettings{})
+	app.toast = common.NewToastModel()
+	if cmd := app.showActivityFeed(); cmd == nil || app.dialog != nil {
+		t.Fatal("an empty feed should show a toast, not a dialog")
+	}
+
+	app.feedAdd(feed.KindExit, string(ws.ID()), "claude exited")
+	app.showActivityFeed()
+	if app.dialog == nil || !app.dialog.Visible() {
+		t.Fatal("expected the activity dialog")
+	}
+	app.dialog.SetSize(160, 40)
+	if view := app.dialog.View(); !strings.Contains(view, "1 new") || !strings.Contains(view, "feature: claude exited") {
+		t.Fatalf("dialog should mark the new entry:\n%s", view)
+	}
+	if n := app.activityFeed.feed.Unseen(); n != 0 {
+		t.Fatalf("Unseen() = %d after opening the feed, want 0", n)
+	}
+}
@@ -0,0 +1,112 @@
+package app
+
+import (
+	tea "charm.land/bubbletea/v2"
+
+	"github.com/andyrewlee/amux/internal/messages"
+	"github.com/andyrewlee/amux/internal/ui/common"
+	"github.com/andyrewlee/amux/internal/ui/dashboard"
+)
+
+// agentTabRef is one center tab in cross-workspace cycling order.
+type agentTabRef struct {
+	entry dashboard.WorkspaceEntry
+	index int
+}
+
+func (r agentTabRef) workspaceID() string {
+	return string(r.entry.Workspace.ID())
+}
+
+// agentTabOrder lists every center tab across workspaces in dashboard order,
+// along with the position of the tab currently shown (-1 when none is).
+func (a *App) agentTabOrder() (refs []agentTabRef, current int) {
+	current = -1
+	if a.dashboard == nil || a.center == nil {
+		return nil, current
+	}
+	activeID := ""
+	if a.activeWorkspace != nil {
+		activeID = string(a.activeWorkspace.ID())
+	}
+	for _, entry := range a.dashboard.Workspaces() {
+		wsID := string(entry.Workspace.ID())
+		count, active := a.center.WorkspaceTabCount(wsID)
+		for i := 0; i < count; i++ {
+			if wsID == activeID && i == active {
+				current = len(refs)
+			}
+			refs = append(refs, agentTabRef{entry: entry, index: i})
+		}
+	}
+	return refs, current
+}
+
+// cycleAgentTab moves to the next (dir > 0) or previous tab across all
+// workspaces, switching workspace when the neighbor lives in another one.
+func (a *App) cycleAgentTab(dir int) tea.Cmd {
+	refs, current := a.agentTabOrder()
+	if len(refs) == 0 {
+		return nil
+	}
+	next := 0
+	switch {
+	case current >= 0:
+		next = (current + dir + len(refs)) % len(refs)
+	case dir < 0:
+		next = len(refs) - 1
+	}
+	if next == current {
+		return nil
+	}
+	return a.showAgentTab(refs[next])
+}
+
+// jumpToAttentionTab shows the next workspace, after the current one in
+// dashboard order, whose agent finished without the user looking at it.
+func (a *App) jumpToAttentionTab() tea.Cmd {
+	refs, current := a.agentTabOrder()
+	for step := 1; step <= len(refs); step++ {
+		i := (max(current, 0) + step) % len(refs)
+		ref := refs[i]
+		if !a.dashboard.NeedsAttention(ref.workspaceID()) {
+			continue
+		}
+		if _, active := a.center.WorkspaceTabCount(ref.workspaceID()); active != ref.index {
+			continue // land on the tab the workspace last showed
+		}
+		return a.showAgentTab(ref)
+	}
+	if a.toast == nil {
+		return nil
+	}
+	return a.toast.ShowInfo("No agents need attention")
+}
+
+// attentionPending reports whether any listed workspace needs attention.
+func (a *App) attentionPending() bool {
+	if a.dashboard == nil {
+		return false
+	}
+	for _, entry := range a.dashboard.Workspaces() {
+		if a.dashboard.NeedsAttention(string(entry.Workspace.ID())) {
+			return true
+		}
+	}
+	return false
+}
+
+// showAgentTab selects ref's tab and focuses the center pane, activating its
+// workspace first when it is not the one shown.
+func (a *App) showAgentTab(ref agentTabRef) tea.Cmd {
+	wsID := ref.workspaceID()
+	if a.activeWorkspace != nil && string(a.activeWorkspace.ID()) == wsID {
+		a.dashboard.AckDone(wsID)
+		cmd := a.center.SelectTab(ref.index)
+		return common.SafeBatch(cmd, a.persistActiveWorkspaceTabs(), a.focusPane(messages.PaneCenter))
+	}
+	if !a.center.SelectTabInWorkspace(wsID, ref.index) {
+		return nil
+	}
+	return a.activateWorkspaceEntry(ref.entry)
+}
@@ -0,0 +1,85 @@
+package app
+
+import (
+	"testing"
+
+	"github.com/andyrewlee/amux/internal/app/activity"
+	"github.com/andyrewlee/amux/internal/data"
+	"github.com/andyrewlee/amux/internal/messages"
+	"github.com/andyrewlee/amux/internal/ui/center"
+	"github.com/andyrewlee/amux/internal/ui/dashboard"
+)
+
+func newAgentCycleTestApp(t *testing.T) (*App, *data.Workspace, *data.Workspace) {
+	t.Helper()
+	project := data.NewProject("/repo")
+	project.Workspaces = []data.Workspace{
+		*data.NewWorkspace("repo", "main", "main", "/repo", "/repo"),
+		*data.NewWorkspace("feat", "feat", "main", "/repo", "/repo/feat"),
+	}
+	mainWS, featWS := &project.Workspaces[0], &project.Workspaces[1]
+
+	app := &App{
+		projects:        []data.Project{*project},
+		dashboard:       dashboard.New(),
+		center:          center.New(nil),
+		activeWorkspace: mainWS,
+	}
+	app.dashboard.SetProjects(app.projects)
+	app.center.SetWorkspace(mainWS)
+	app.center.AddTab(&center.Tab{ID: "main-0", Workspace: mainWS})
+	app.center.AddTab(&center.Tab{ID: "feat-0", Workspace: featWS})
+	app.center.AddTab(&center.Tab{ID: "feat-1", Workspace: featWS})
+	return app, mainWS, featWS
+}
+
+func TestCycleAgentTabCrossesWorkspaces(t *testing.T) {
+	app, mainWS, featWS := newAgentCycleTestApp(t)
+
+	cmd := app.cycleAgentTab(1)
+	if cmd == nil {
+		t.Fatal("expected next agent to switch workspace")
+	}
+	if msg, ok := cmd().(messages.WorkspaceActivated); !ok || msg.Workspace.Root != featWS.Root {
+		t.Fatalf("next agent activated %+v, want feat workspace", msg)
+	}
+	if _, active := app.center.WorkspaceTabCount(string(featWS.ID())); active != 0 {
+		t.Fatalf("feat active tab = %d, want 0", active)
+	}
+
+	// Previous from the first tab wraps to the last tab in dashboard order.
+	cmd = app.cycleAgentTab(-1)
+	if msg, ok := cmd().(messages.WorkspaceActivated); !ok || msg.Workspace.Root != featWS.Root {
+		t.Fatalf("prev agent activated %+v, want feat workspace", msg)
+	}
+	if _, active := app.center.WorkspaceTabCount(string(featWS.ID())); active != 1 {
+		t.Fatalf("feat active tab = %d, want 1", active)
+	}
+	if app.activeWorkspace != mainWS {
+		t.Fatal("activation is applied by the WorkspaceActivated handler, not directly")
+	}
+}
+
+func TestJumpToAttentionTabAcksWorkspace(t *testing.T) {
+	app, _, featWS := newAgentCycleTestApp(t)
+	featID := string(featWS.ID())
+
+	if app.attentionPending() {
+		t.Fatal("no workspace should need attention yet")
+	}
+	app.dashboard.SetAgentStates(map[string]activity.AgentState{featID: activity.StateDone})
+	if !app.attentionPending() {
+		t.Fatal("finished workspace should need attention")
+	}
+
+	cmd := app.jumpToAttentionTab()
+	if cmd == nil {
+		t.Fatal("expected a jump to the finished workspace")
+	}
+	if msg, ok := cmd().(messages.WorkspaceActivated); !ok || msg.Workspace.Root != featWS.Root {
+		t.Fatalf("attention jump activated %+v, want feat workspace", msg)
+	}
+	if app.dashboard.NeedsAttention(featID) {
+		t.Fatal("jumping to the workspace should acknowledge it")
+	}
+}
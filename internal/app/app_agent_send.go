@@ -0,0 +1,67 @@
+package app
+
+import (
+	tea "charm.land/bubbletea/v2"
+
+	"github.com/andyrewlee/amux/internal/audit"
+	"github.com/andyrewlee/amux/internal/data"
+	"github.com/andyrewlee/amux/internal/messages"
+	"github.com/andyrewlee/amux/internal/ui/common"
+)
+
+// isAgentTab reports whether a center tab's assistant is a configured agent,
+// as opposed to a viewer or command tab.
+func (a *App) isAgentTab(assistant string) bool {
+	if a.config == nil {
+		return false
+	}
+	_, ok := a.config.Assistants[assistant]
+	return ok
+}
+
+// sendToWorkspaceAgent pastes text into an agent tab of ws, which must be the
+// active workspace: the active center tab when it is an agent, else the
+// first agent tab. The text is pasted, not submitted, so it can be reviewed
+// before pressing Enter.
+func (a *App) sendToWorkspaceAgent(ws *data.Workspace, text string) tea.Cmd {
+	if ws == nil || a.activeWorkspace != ws {
+		return a.toast.ShowWarning("Open the workspace to send to its agent")
+	}
+	target := a.workspaceAgentTab(ws)
+	if target < 0 {
+		return a.toast.ShowWarning("No agent tab in this workspace")
+	}
+	selectCmd := a.center.SelectTab(target)
+	return common.SafeBatch(
+		selectCmd,
+		a.persistActiveWorkspaceTabs(),
+		a.focusPane(messages.PaneCenter),
+		a.startAuditedPaste(audit.SourceAmux, messages.PaneCenter, text),
+	)
+}
+
+// handleSendToAgent pastes text, such as notes from the sidebar scratchpad,
+// into the agent tab of the workspace it was written in.
+func (a *App) handleSendToAgent(msg messages.SendToAgent) tea.Cmd {
+	ws := msg.Workspace
+	// The sender may hold a workspace loaded before the last rescan.
+	if ws != nil && a.activeWorkspace != nil && a.activeWorkspace.ID() == ws.ID() {
+		ws = a.activeWorkspace
+	}
+	return a.sendToWorkspaceAgent(ws, msg.Text)
+}
+
+// workspaceAgentTab returns the index of the agent tab sendToWorkspaceAgent
+// pastes into, or -1 when ws has none.
+func (a *App) workspaceAgentTab(ws *data.Workspace) int {
+	tabs, active := a.center.GetTabsInfoForWorkspace(string(ws.ID()))
+	if active >= 0 && active < len(tabs) && a.isAgentTab(tabs[active].Assistant) {
+		return active
+	}
+	for i, tab := range tabs {
+		if a.isAgentTab(tab.Assistant) {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,49 @@
+package app
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/andyrewlee/amux/internal/audit"
+	"github.com/andyrewlee/amux/internal/config"
+	"github.com/andyrewlee/amux/internal/data"
+	"github.com/andyrewlee/amux/internal/ui/center"
+	"github.com/andyrewlee/amux/internal/ui/common"
+)
+
+// newAuditedPasteTestApp returns an app whose active workspace has one agent
+// tab, session "amux-feat-0", and whose audit log is at auditPath.
+func newAuditedPasteTestApp(t *testing.T) (app *App, ws *data.Workspace, auditPath string) {
+	t.Helper()
+	ws = data.NewWorkspace("feat", "feat", "main", "/repo", "/repo/feat")
+	auditPath = filepath.Join(t.TempDir(), audit.FileName)
+	app = &App{
+		toast:           common.NewToastModel(),
+		config:          &config.Config{Assistants: map[string]config.AssistantConfig{"claude": {}}},
+		center:          center.New(nil),
+		activeWorkspace: ws,
+		auditLog:        audit.Open(auditPath),
+	}
+	app.center.SetWorkspace(ws)
+	app.center.AddTab(&center.Tab{ID: "feat-0", Workspace: ws, Assistant: "claude", SessionName: "amux-feat-0"})
+	return app, ws, auditPath
+}
+
+func TestSendToWorkspaceAgentRecordsAudit(t *testing.T) {
+	app, ws, auditPath := newAuditedPasteTestApp(t)
+
+	if cmd := app.sendToWorkspaceAgent(ws, "review the diff"); cmd == nil {
+		t.Fatal("expected a paste command")
+	}
+
+	entries, err := audit.Read(auditPath)
+	if err != nil {
+		t.Fatalf("Read() error = %v", err)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("entries = %d, want 1", len(entries))
+	}
+	if e := entries[0]; e.Source != audit.SourceAmux || e.Target != "amux-feat-0" || e.Bytes != len("review the diff") {
+		t.Fatalf("entry = %+v", e)
+	}
+}
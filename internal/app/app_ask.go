@@ -0,0 +1,174 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	tea "charm.land/bubbletea/v2"
+
+	"github.com/andyrewlee/amux/internal/audit"
+	"github.com/andyrewlee/amux/internal/helpdocs"
+	"github.com/andyrewlee/amux/internal/llm"
+	"github.com/andyrewlee/amux/internal/messages"
+	"github.com/andyrewlee/amux/internal/ui/common"
+)
+
+const (
+	// askQuestionLimit bounds the length of a question.
+	askQuestionLimit = 200
+	// askResultLimit bounds the topics an answer lists.
+	askResultLimit = 8
+	// askTimeout bounds asking the language model.
+	askTimeout = time.Minute
+)
+
+const askOptionAgain = "Ask another question"
+
+// askState is the question being answered, the topics its answer lists, and
+// the dialog showing them, so a model's answer arriving after the dialog
+// closed is dropped.
+type askState struct {
+	question string
+	topics   []helpdocs.Topic
+	dialog   *common.Dialog
+}
+
+// askAnswered carries the language model's answer to question.
+type askAnswered struct {
+	question string
+	answer   string
+	err      error
+}
+
+// showAsk asks for a question about using amux.
+func (a *App) showAsk() tea.Cmd {
+	a.dialog = common.NewInputDialog(DialogAsk, "Ask amux", "e.g. why is my tab frozen?")
+	a.dialog.SetInputCharLimit(askQuestionLimit)
+	a.presentDialog(a.dialog)
+	return nil
+}
+
+// helpTopics are the built-in docs and the prefix key table, whose commands
+// answer questions by their descriptions.
+func (a *App) helpTopics() []helpdocs.Topic {
+	topics := append([]helpdocs.Topic(nil), helpdocs.Topics...)
+	for _, c := range a.prefixCommands() {
+		topics = append(topics, helpdocs.Topic{Title: c.Desc, Keys: strings.Join(c.Sequence, " "), Action: c.Action})
+	}
+	return topics
+}
+
+// handleAskDialog answers the question asked and carries out the topic
+// picked from the answer, reporting whether result was from one of its
+// dialogs.
+func (a *App) handleAskDialog(result common.DialogResult) (tea.Cmd, bool) {
+	switch result.ID {
+	case DialogAsk, DialogAskAnswer:
+	default:
+		return nil, false
+	}
+	if !result.Confirmed {
+		a.ask = askState{}
+		return nil, true
+	}
+	if result.ID == DialogAsk {
+		question := strings.TrimSpace(result.Value)
+		if question == "" {
+			return nil, true
+		}
+		return a.answerQuestion(question), true
+	}
+	topics := a.ask.topics
+	a.ask = askState{}
+	if result.Index < 0 || result.Index >= len(topics) {
+		return a.showAsk(), true
+	}
+	return a.runHelpTopic(topics[result.Index]), true
+}
+
+// answerQuestion shows the docs' answer to question with the topics that
+// match it, and asks the language model for a better one when that feature
+// is enabled.
+func (a *App) answerQuestion(question string) tea.Cmd {
+	all := a.helpTopics()
+	a.ask = askState{question: question, topics: helpdocs.Search(question, all, askResultLimit)}
+	answer := docsAnswer(a.ask.topics)
+	if !a.llm.Enabled(llm.FeatureAsk) {
+		a.presentAskAnswer(answer)
+		return nil
+	}
+	a.presentAskAnswer(answer + "\n\nAsking the model…")
+	model, leader := a.llm, a.prefixLabel()
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), askTimeout)
+		defer cancel()
+		answer, err := model.Complete(ctx, llm.Request{
+			Feature: llm.FeatureAsk,
+			System:  helpdocs.ModelInstructions(leader),
+			Prompt:  "Reference:\n" + helpdocs.Reference(all) + "\nQuestion: " + question,
+		})
+		return askAnswered{question: question, answer: answer, err: err}
+	}
+}
+
+// docsAnswer is the best matching topic's answer.
+func docsAnswer(topics []helpdocs.Topic) string {
+	if len(topics) == 0 {
+		return "Nothing in amux's docs matches that. Try other words, or press the leader key to browse the commands."
+	}
+	if t := topics[0]; t.Answer != "" {
+		return t.Answer
+	}
+	return fmt.Sprintf("prefix %s: %s.", topics[0].Keys, topics[0].Title)
+}
+
+// presentAskAnswer shows answer above the matching topics.
+func (a *App) presentAskAnswer(answer string) {
+	options := make([]string, 0, len(a.ask.topics)+1)
+	for _, t := range a.ask.topics {
+		options = append(options, t.Label())
+	}
+	options = append(options, askOptionAgain)
+	message := fmt.Sprintf("%q\n\n%s", a.ask.question, answer)
+	if len(a.ask.topics) > 0 {
+		message += "\n\nPick a command to run it, or a shell command to type it into the focused terminal."
+	}
+	a.dialog = common.NewListDialog(DialogAskAnswer, "Ask amux", message, options)
+	a.ask.dialog = a.dialog
+	a.presentDialog(a.dialog)
+}
+
+// handleAskAnswered replaces the docs' answer with the model's while the
+// answer is still shown, keeping the docs' answer when the model failed.
+func (a *App) handleAskAnswered(msg askAnswered) tea.Cmd {
+	s := a.ask
+	if s.dialog == nil || a.dialog != s.dialog || !s.dialog.Visible() || s.question != msg.question {
+		return nil
+	}
+	if msg.err != nil || msg.answer == "" {
+		a.presentAskAnswer(docsAnswer(s.topics))
+		if msg.err != nil {
+			return a.toast.ShowWarning("The model couldn't answer: " + msg.err.Error())
+		}
+		return nil
+	}
+	a.presentAskAnswer(msg.answer)
+	return nil
+}
+
+// runHelpTopic runs the command a topic points to, or types its shell
+// command into the focused terminal without running it.
+func (a *App) runHelpTopic(t helpdocs.Topic) tea.Cmd {
+	switch {
+	case t.Action != "":
+		return a.runPrefixAction(t.Action)
+	case t.Command != "":
+		if a.focusedPane == messages.PaneCenter || a.focusedPane == messages.PaneSidebarTerminal {
+			return a.startAuditedPaste(audit.SourceAsk, a.focusedPane, t.Command)
+		}
+		return a.toast.ShowInfo("Focus a terminal to type it there: " + t.Command)
+	}
+	return nil
+}
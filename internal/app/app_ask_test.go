@@ -0,0 +1,97 @@
+package app
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/andyrewlee/amux/internal/audit"
+	"github.com/andyrewlee/amux/internal/helpdocs"
+	"github.com/andyrewlee/amux/internal/llm"
+	"github.com/andyrewlee/amux/internal/messages"
+	"github.com/andyrewlee/amux/internal/ui/common"
+)
+
+func TestAskAmux(t *testing.T) {
+	h := newDialogHarness(t)
+	h.app.showAsk()
+	if cmd, _ := h.app.handleAskDialog(common.DialogResult{ID: DialogAsk, Confirmed: true, Value: "why is my tab frozen"}); cmd != nil {
+		t.Fatal("without a model the docs should answer on their own")
+	}
+	h.app.dialog.SetSize(160, 50)
+	view := dialogView(t, h.app.dialog)
+	for _, want := range []string{"prefix t w redraws the tab", "(prefix t w)", askOptionAgain} {
+		if !strings.Contains(view, want) {
+			t.Fatalf("answer missing %q, got %q", want, view)
+		}
+	}
+
+	h.app.handleAskDialog(common.DialogResult{ID: DialogAskAnswer, Confirmed: true, Index: len(h.app.ask.topics), Value: askOptionAgain})
+	if view := dialogView(t, h.app.dialog); !strings.Contains(view, "why is my tab frozen?") {
+		t.Fatalf("dialog = %q, want a new question asked for", view)
+	}
+}
+
+func TestAskAmuxWithModel(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"choices":[{"message":{"content":"The leader can't be rebound; amux moves it to C-\\ when nested."}}]}`))
+	}))
+	t.Cleanup(srv.Close)
+	h := newDialogHarness(t)
+	h.app.llm = llm.New(llm.Config{Provider: llm.Ollama, Endpoint: srv.URL, Model: "llama3", Features: map[llm.Feature]bool{llm.FeatureAsk: true}}, nil)
+
+	cmd, _ := h.app.handleAskDialog(common.DialogResult{ID: DialogAsk, Confirmed: true, Value: "how do I rebind the leader"})
+	if cmd == nil {
+		t.Fatal("expected the model to be asked")
+	}
+	answered, ok := cmd().(askAnswered)
+	if !ok || answered.err != nil {
+		t.Fatalf("answered = %+v", answered)
+	}
+	h.app.handleAskAnswered(answered)
+	h.app.dialog.SetSize(160, 50)
+	if view := dialogView(t, h.app.dialog); !strings.Contains(view, "amux moves it to C-\\ when nested") || !strings.Contains(view, "Leader key") {
+		t.Fatalf("dialog = %q, want the model's answer above the matching topics", view)
+	}
+
+	h.app.handleAskDialog(common.DialogResult{ID: DialogAskAnswer})
+	if h.app.handleAskAnswered(answered) != nil || h.app.ask.dialog != nil {
+		t.Fatal("an answer for a closed dialog should be dropped")
+	}
+}
+
+func TestHelpTopicActionsExist(t *testing.T) {
+	actions := map[string]bool{}
+	for _, c := range prefixCommandTable {
+		actions[c.Action] = true
+	}
+	for _, topic := range helpdocs.Topics {
+		if topic.Action != "" && !actions[topic.Action] {
+			t.Errorf("topic %q points to unknown prefix action %q", topic.Title, topic.Action)
+		}
+		if topic.Action == "" && topic.Command == "" {
+			t.Errorf("topic %q points to neither a key nor a command", topic.Title)
+		}
+	}
+}
+
+func TestHelpTopicCommandRecordsAudit(t *testing.T) {
+	app, _, auditPath := newAuditedPasteTestApp(t)
+	app.focusedPane = messages.PaneCenter
+
+	if cmd := app.runHelpTopic(helpdocs.Topic{Command: "amux doctor"}); cmd == nil {
+		t.Fatal("expected a paste command")
+	}
+
+	entries, err := audit.Read(auditPath)
+	if err != nil {
+		t.Fatalf("Read() error = %v", err)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("entries = %d, want 1", len(entries))
+	}
+	if e := entries[0]; e.Source != audit.SourceAsk || e.Target != "amux-feat-0" || e.Bytes != len("amux doctor") {
+		t.Fatalf("entry = %+v", e)
+	}
+}
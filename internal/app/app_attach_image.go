@@ -0,0 +1,69 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	tea "charm.land/bubbletea/v2"
+
+	"github.com/andyrewlee/amux/internal/attach"
+	"github.com/andyrewlee/amux/internal/data"
+	"github.com/andyrewlee/amux/internal/ui/common"
+)
+
+// imageAttached reports copying an image or screenshot into a worktree. path
+// is relative to the worktree root.
+type imageAttached struct {
+	workspace *data.Workspace
+	path      string
+	err       error
+}
+
+// showAttachImage asks for an image to give the active workspace's agent.
+// An empty path takes a screenshot instead.
+func (a *App) showAttachImage() tea.Cmd {
+	if a.activeWorkspace == nil {
+		return nil
+	}
+	a.dialog = common.NewInputDialog(DialogAttachImage, "Attach Image", "Image path or drop a file (empty takes a screenshot)")
+	a.dialogWorkspace = a.activeWorkspace
+	a.presentDialog(a.dialog)
+	return nil
+}
+
+// attachImage copies the image at src, or a new screenshot, into ws's
+// worktree off the UI goroutine, so sandboxed agents can read it too.
+func (a *App) attachImage(ws *data.Workspace, src string) tea.Cmd {
+	if ws == nil {
+		return nil
+	}
+	if strings.TrimSpace(src) == "" {
+		return func() tea.Msg {
+			path, err := attach.Screenshot(context.Background(), ws.Root)
+			return imageAttached{workspace: ws, path: path, err: err}
+		}
+	}
+	return func() tea.Msg {
+		path, err := attach.Import(ws.Root, src)
+		return imageAttached{workspace: ws, path: path, err: err}
+	}
+}
+
+// handleImageAttached pastes the attachment's path into the workspace's
+// agent tab, where the agent reads it as an image.
+func (a *App) handleImageAttached(msg imageAttached) tea.Cmd {
+	switch {
+	case errors.Is(msg.err, attach.ErrCanceled):
+		return nil
+	case errors.Is(msg.err, attach.ErrNoScreenshotTool):
+		return a.toast.ShowWarning("No screenshot tool found; enter an image path instead")
+	case msg.err != nil:
+		return common.ReportError(errorContext(errorServiceWorkspace, "attaching an image"), msg.err, "")
+	}
+	ws := msg.workspace
+	if ws != nil && a.activeWorkspace != nil && a.activeWorkspace.ID() == ws.ID() {
+		ws = a.activeWorkspace
+	}
+	return a.sendToWorkspaceAgent(ws, msg.path+" ")
+}
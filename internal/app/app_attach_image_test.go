@@ -0,0 +1,33 @@
+package app
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/andyrewlee/amux/internal/attach"
+	"github.com/andyrewlee/amux/internal/data"
+)
+
+func TestAttachImageCopiesIntoWorktree(t *testing.T) {
+	h := newDialogHarness(t)
+	ws := &data.Workspace{Name: "feature", Repo: "/repo", Root: t.TempDir()}
+	src := filepath.Join(t.TempDir(), "bug.png")
+	if err := os.WriteFile(src, []byte("png"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	msg := h.app.attachImage(ws, src)().(imageAttached)
+	if msg.err != nil || !strings.HasPrefix(msg.path, attach.Dir+string(filepath.Separator)) {
+		t.Fatalf("attached = %+v, want a path under %s", msg, attach.Dir)
+	}
+	if _, err := os.Stat(filepath.Join(ws.Root, msg.path)); err != nil {
+		t.Fatalf("attachment missing: %v", err)
+	}
+
+	// A canceled screenshot is not an error worth reporting.
+	if cmd := h.app.handleImageAttached(imageAttached{workspace: ws, err: attach.ErrCanceled}); cmd != nil {
+		t.Fatal("a canceled screenshot should do nothing")
+	}
+}
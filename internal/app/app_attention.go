@@ -0,0 +1,84 @@
+package app
+
+import (
+	"path/filepath"
+	"slices"
+	"sort"
+	"time"
+)
+
+// Attention is a workspace whose agent needs attention, as the attention
+// queue lists it for `amux toggle` and the editor API.
+type Attention struct {
+	WorkspaceID string
+	Workspace   string
+	Project     string
+	Root        string
+	// Reason is the dashboard's alert label, such as "mem limit", or
+	// "done" for an agent that finished.
+	Reason string
+	// Since is when amux first saw the workspace needing attention.
+	Since time.Time
+}
+
+// SetAttentionListener has fn called with the attention queue, most urgent
+// first, each time it changes. fn runs on the UI goroutine and must not
+// block. Call it before the program runs.
+func (a *App) SetAttentionListener(fn func([]Attention)) {
+	a.focusFollow.listener = fn
+}
+
+// attentionQueue lists the workspaces needing attention, most urgent first:
+// alerts, such as a resource limit, before finished agents, then the one
+// waiting longest.
+func (a *App) attentionQueue() []Attention {
+	if a.dashboard == nil {
+		return nil
+	}
+	var queue []Attention
+	for _, entry := range a.dashboard.Workspaces() {
+		wsID := string(entry.Workspace.ID())
+		since, ok := a.focusFollow.attention[wsID]
+		if !ok {
+			continue
+		}
+		reason := a.dashboard.Alert(wsID)
+		if reason == "" {
+			reason = "done"
+		}
+		item := Attention{
+			WorkspaceID: wsID,
+			Workspace:   entry.Workspace.Name,
+			Root:        entry.Workspace.Root,
+			Reason:      reason,
+			Since:       since,
+		}
+		if entry.Project != nil {
+			item.Project = filepath.Base(entry.Project.Path)
+		}
+		queue = append(queue, item)
+	}
+	sort.SliceStable(queue, func(i, j int) bool {
+		alertI, alertJ := a.dashboard.Alert(queue[i].WorkspaceID) != "", a.dashboard.Alert(queue[j].WorkspaceID) != ""
+		if alertI != alertJ {
+			return alertI
+		}
+		return queue[i].Since.Before(queue[j].Since)
+	})
+	return queue
+}
+
+// publishAttention tells the listener of the attention queue the first time
+// and whenever it changed.
+func (a *App) publishAttention() {
+	if a.focusFollow.listener == nil {
+		return
+	}
+	queue := a.attentionQueue()
+	if a.focusFollow.published && slices.Equal(queue, a.focusFollow.queue) {
+		return
+	}
+	a.focusFollow.queue = queue
+	a.focusFollow.published = true
+	a.focusFollow.listener(queue)
+}
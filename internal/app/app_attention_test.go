@@ -0,0 +1,70 @@
+package app
+
+import (
+	"testing"
+	"time"
+
+	"github.com/andyrewlee/amux/internal/app/activity"
+	"github.com/andyrewlee/amux/internal/config"
+	"github.com/andyrewlee/amux/internal/messages"
+)
+
+func TestAttentionQueue(t *testing.T) {
+	app, mainWS, featWS := newAgentCycleTestApp(t)
+	var published [][]Attention
+	app.SetAttentionListener(func(queue []Attention) { published = append(published, queue) })
+	featID, mainID := string(featWS.ID()), string(mainWS.ID())
+
+	app.followAttention()
+	if len(published) != 1 || len(published[0]) != 0 {
+		t.Fatalf("published %v, want the empty queue reported once", published)
+	}
+	app.followAttention()
+	if len(published) != 1 {
+		t.Fatal("an unchanged queue should not be reported again")
+	}
+
+	app.dashboard.SetAgentStates(map[string]activity.AgentState{featID: activity.StateDone})
+	app.followAttention()
+	// An alert outranks an agent that finished earlier.
+	app.dashboard.SetAlert(mainID, "mem limit")
+	app.followAttention()
+	if len(published) != 3 {
+		t.Fatalf("published %d queues, want 3", len(published))
+	}
+	queue := published[2]
+	if len(queue) != 2 || queue[0].WorkspaceID != mainID || queue[0].Reason != "mem limit" ||
+		queue[1].WorkspaceID != featID || queue[1].Reason != "done" || queue[1].Project != "repo" {
+		t.Fatalf("queue = %+v, want the alert first, then the finished agent", queue)
+	}
+	if !queue[1].Since.Equal(published[1][0].Since) {
+		t.Fatal("a workspace's wait should be timed from when it started needing attention")
+	}
+}
+
+func TestActivateRequestShowsTab(t *testing.T) {
+	app, _, featWS := newAgentCycleTestApp(t)
+	home := t.TempDir()
+	app.config = &config.Config{Paths: &config.Paths{Home: home}}
+	app.activateHandledAt = time.Now().Add(-time.Minute)
+	featID := string(featWS.ID())
+	app.center.SelectTabInWorkspace(featID, 1)
+
+	if err := RequestShowTab(home, featWS.ID()); err != nil {
+		t.Fatalf("RequestShowTab: %v", err)
+	}
+	tick := app.startActivateRequestTicker()().(activateRequestTick)
+	if !tick.req.ShowTab {
+		t.Fatalf("request = %+v, want show_tab", tick.req)
+	}
+	cmd := app.takeActivateRequest(tick.req)
+	if cmd == nil {
+		t.Fatal("expected the request to show the workspace's tab")
+	}
+	if msg, ok := cmd().(messages.WorkspaceActivated); !ok || msg.Workspace.Root != featWS.Root {
+		t.Fatalf("activated %+v, want the feat workspace", msg)
+	}
+	if _, active := app.center.WorkspaceTabCount(featID); active != 1 {
+		t.Fatalf("feat active tab = %d, want its current tab kept", active)
+	}
+}
@@ -0,0 +1,377 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	tea "charm.land/bubbletea/v2"
+
+	"github.com/andyrewlee/amux/internal/app/activity"
+	"github.com/andyrewlee/amux/internal/benchmark"
+	"github.com/andyrewlee/amux/internal/data"
+	"github.com/andyrewlee/amux/internal/feed"
+	"github.com/andyrewlee/amux/internal/git"
+	"github.com/andyrewlee/amux/internal/messages"
+	"github.com/andyrewlee/amux/internal/process"
+	"github.com/andyrewlee/amux/internal/testrun"
+	"github.com/andyrewlee/amux/internal/ui/common"
+	"github.com/andyrewlee/amux/internal/validation"
+)
+
+// Benchmark results panel options ahead of the runs.
+const (
+	benchmarkOptionExport = "Export JSON"
+	benchmarkOptionNew    = "New benchmark"
+)
+
+// benchmarkState holds the benchmark being set up and the last one started,
+// with its project and the workspace of each of its runs once created.
+type benchmarkState struct {
+	project *data.Project
+	prompt  string
+	accept  string
+
+	report        *benchmark.Report
+	reportProject *data.Project
+	workspaces    []*data.Workspace
+}
+
+// benchmarkLaunched reports giving a run's agent the task.
+type benchmarkLaunched struct {
+	report *benchmark.Report
+	index  int
+	err    error
+}
+
+// benchmarkChecked carries the acceptance command's result and the diff of
+// a run whose agent finished.
+type benchmarkChecked struct {
+	report *benchmark.Report
+	index  int
+	result testrun.Result
+	files  []git.ComparedFile
+	err    error
+}
+
+// showBenchmark shows the last benchmark's results, or starts one in project
+// when there are none.
+func (a *App) showBenchmark(project *data.Project) tea.Cmd {
+	if project == nil {
+		return nil
+	}
+	if a.benchmark.report == nil {
+		return a.startBenchmark(project)
+	}
+	a.benchmark.project = project
+	r := a.benchmark.report
+	task, _, _ := strings.Cut(r.Prompt, "\n")
+	message := fmt.Sprintf("%q, accepted by %s.", task, r.Accept)
+	if !r.Done() {
+		message += " Agents are still working; this updates as they finish."
+	}
+	// The header is indented to line up with the options below it.
+	message += " Pick a run to open its workspace.\n\n  " + r.Table()[0]
+	options := []string{benchmarkOptionExport}
+	if r.Done() {
+		options = append(options, benchmarkOptionNew)
+	}
+	options = append(options, r.Table()[1:]...)
+	a.dialog = common.NewListDialog(DialogBenchmark, "Benchmark", message, options)
+	a.presentDialog(a.dialog)
+	return nil
+}
+
+// startBenchmark asks for the task every agent is given.
+func (a *App) startBenchmark(project *data.Project) tea.Cmd {
+	if r := a.benchmark.report; r != nil && !r.Done() {
+		return a.toast.ShowWarning("A benchmark is still running; prefix B shows it")
+	}
+	a.benchmark.project, a.benchmark.prompt, a.benchmark.accept = project, "", ""
+	a.dialog = common.NewInputDialog(DialogBenchmarkTask, "Benchmark: Task", "Task given to every agent")
+	a.dialog.SetInputCharLimit(fanOutTaskLimit)
+	a.presentDialog(a.dialog)
+	return nil
+}
+
+// handleBenchmarkDialog steps through setting up a benchmark and acts on
+// its results panel, reporting whether result was from one of them.
+func (a *App) handleBenchmarkDialog(result common.DialogResult) (tea.Cmd, bool) {
+	switch result.ID {
+	case DialogBenchmarkTask, DialogBenchmarkAccept, DialogBenchmarkAgents, DialogBenchmark:
+	default:
+		return nil, false
+	}
+	if !result.Confirmed {
+		a.benchmark.prompt, a.benchmark.accept = "", ""
+		return nil, true
+	}
+	value := strings.TrimSpace(result.Value)
+	switch result.ID {
+	case DialogBenchmarkTask:
+		if value == "" || a.benchmark.project == nil {
+			return a.toast.ShowWarning("A benchmark needs a task"), true
+		}
+		a.benchmark.prompt = value
+		a.dialog = common.NewInputDialog(DialogBenchmarkAccept, "Benchmark: Acceptance",
+			"Command that passes in a worktree once the task is done")
+		a.dialog.SetInputValue(testrun.Detect(a.benchmark.project.Path))
+	case DialogBenchmarkAccept:
+		if value == "" {
+			a.benchmark.prompt = ""
+			return a.toast.ShowWarning("A benchmark needs an acceptance command"), true
+		}
+		a.benchmark.accept = value
+		a.dialog = common.NewInputDialog(DialogBenchmarkAgents, "Benchmark: Agents",
+			"Comma-separated; name an agent twice to run it twice")
+		a.dialog.SetInputValue(strings.Join(a.assistantNames(), ", "))
+	case DialogBenchmarkAgents:
+		return a.launchBenchmark(value), true
+	default:
+		return a.handleBenchmarkChoice(result.Value, result.Index), true
+	}
+	a.dialog.SetInputCharLimit(fanOutTaskLimit)
+	a.presentDialog(a.dialog)
+	return nil, true
+}
+
+// launchBenchmark creates a workspace per agent. Each agent is given the
+// task once its workspace's setup has run.
+func (a *App) launchBenchmark(value string) tea.Cmd {
+	b := &a.benchmark
+	project := b.project
+	if project == nil || b.prompt == "" || b.accept == "" {
+		return nil
+	}
+	var agents []string
+	for _, part := range strings.Split(value, ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			if !a.isKnownAssistant(part) {
+				return a.toast.ShowWarning("Unknown agent: " + part)
+			}
+			agents = append(agents, part)
+		}
+	}
+	switch {
+	case len(agents) == 0:
+		return a.toast.ShowWarning("A benchmark needs at least one agent")
+	case len(agents) > fanOutMaxRuns:
+		return a.toast.ShowWarning(fmt.Sprintf("%d agents is too many; benchmark at most %d", len(agents), fanOutMaxRuns))
+	}
+	taken := make(map[string]bool)
+	for _, ws := range project.Workspaces {
+		taken[ws.Name] = true
+	}
+	report := &benchmark.Report{
+		Task:    benchmark.Task{Prompt: b.prompt, Accept: b.accept},
+		Project: project.Name,
+		Created: time.Now(),
+	}
+	stem := "bench-" + fanOutStem(b.prompt)
+	var cmds []tea.Cmd
+	for _, agent := range agents {
+		name := uniqueName(stem+"-"+slugify(agent), taken)
+		if err := validation.ValidateWorkspaceName(name); err != nil {
+			cmds = append(cmds, a.toast.ShowWarning(fmt.Sprintf("Skipped %s: %v", agent, err)))
+			continue
+		}
+		taken[name] = true
+		report.Runs = append(report.Runs, benchmark.Run{Agent: agent, Workspace: name, Status: benchmark.Setup})
+		create := messages.CreateWorkspace{Project: project, Name: name, Assistant: agent}
+		cmds = append(cmds, func() tea.Msg { return create })
+	}
+	b.prompt, b.accept = "", ""
+	b.report, b.reportProject, b.workspaces = report, project, make([]*data.Workspace, len(report.Runs))
+	cmds = append(cmds, a.toast.ShowInfo(fmt.Sprintf("Benchmarking %d agents; prefix B shows the results", len(report.Runs))))
+	return common.SafeBatch(cmds...)
+}
+
+// benchmarkRunFor returns the index of the run creating ws, or -1.
+func (a *App) benchmarkRunFor(ws *data.Workspace) int {
+	b := &a.benchmark
+	if ws == nil || b.report == nil || data.NormalizePath(b.reportProject.Path) != data.NormalizePath(ws.Repo) {
+		return -1
+	}
+	for i, run := range b.report.Runs {
+		if run.Workspace == ws.Name {
+			return i
+		}
+	}
+	return -1
+}
+
+// handleBenchmarkSetupComplete gives the task to the agent of a benchmark
+// workspace whose setup finished. Setup skipped for untrusted scripts runs
+// again once they are trusted, so the run waits for that.
+func (a *App) handleBenchmarkSetupComplete(msg messages.WorkspaceSetupComplete) tea.Cmd {
+	i := a.benchmarkRunFor(msg.Workspace)
+	if i < 0 || a.benchmark.report.Runs[i].Status != benchmark.Setup {
+		return nil
+	}
+	run := &a.benchmark.report.Runs[i]
+	a.benchmark.workspaces[i] = msg.Workspace
+	if msg.Err != nil {
+		if errors.Is(msg.Err, process.ErrScriptsNotTrusted) {
+			return nil
+		}
+		run.Status, run.Error = benchmark.Errored, "setup failed"
+		return a.benchmarkFinished()
+	}
+	run.Status, run.Started = benchmark.Running, time.Now()
+	report := a.benchmark.report
+	center, ws, agent, prompt := a.center, msg.Workspace, run.Agent, report.Prompt
+	return func() tea.Msg {
+		_, err := center.StartDetachedAgent(ws, agent, prompt)
+		return benchmarkLaunched{report: report, index: i, err: err}
+	}
+}
+
+// handleBenchmarkCreateFailed records a run whose workspace could not be
+// created.
+func (a *App) handleBenchmarkCreateFailed(msg messages.WorkspaceCreateFailed) {
+	if i := a.benchmarkRunFor(msg.Workspace); i >= 0 && a.benchmark.report.Runs[i].Status == benchmark.Setup {
+		a.benchmark.report.Runs[i].Status, a.benchmark.report.Runs[i].Error = benchmark.Errored, "workspace not created"
+	}
+}
+
+func (a *App) handleBenchmarkLaunched(msg benchmarkLaunched) tea.Cmd {
+	if msg.report != a.benchmark.report || msg.err == nil {
+		return nil
+	}
+	run := &msg.report.Runs[msg.index]
+	run.Status, run.Error = benchmark.Errored, "agent did not start"
+	return common.SafeBatch(
+		common.ReportError(errorContext(errorServiceWorkspace, "starting benchmark agent"), msg.err, ""),
+		a.benchmarkFinished(),
+	)
+}
+
+// benchmarkDoneEdges checks each running benchmark agent that went from
+// working to done between two activity scans: the first time it stops, its
+// attempt is over.
+func (a *App) benchmarkDoneEdges(prev, next map[string]activity.AgentState) tea.Cmd {
+	report := a.benchmark.report
+	if report == nil {
+		return nil
+	}
+	var cmds []tea.Cmd
+	for i := range report.Runs {
+		run, ws := &report.Runs[i], a.benchmark.workspaces[i]
+		if run.Status != benchmark.Running || ws == nil {
+			continue
+		}
+		wsID := string(ws.ID())
+		if next[wsID] == activity.StateDone && prev[wsID] == activity.StateWorking {
+			run.Status, run.Finished = benchmark.Checking, time.Now()
+			cmds = append(cmds, a.checkBenchmarkRun(report, i, ws))
+		}
+	}
+	return common.SafeBatch(cmds...)
+}
+
+// checkBenchmarkRun runs the acceptance command in a run's worktree and
+// measures its diff, off the UI goroutine.
+func (a *App) checkBenchmarkRun(report *benchmark.Report, i int, ws *data.Workspace) tea.Cmd {
+	if a.workspaceService == nil || a.workspaceService.scripts == nil {
+		return nil
+	}
+	scripts := a.workspaceService.scripts
+	return func() tea.Msg {
+		msg := benchmarkChecked{report: report, index: i}
+		command, env, err := scripts.WorkspaceCommand(ws, report.Accept)
+		if err != nil {
+			msg.err = err
+			return msg
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), testRunTimeout)
+		defer cancel()
+		if msg.result, msg.err = testrun.Run(ctx, ws.Root, command, env); msg.err != nil {
+			return msg
+		}
+		msg.files, msg.err = git.DiffFromBase(ctx, ws.Root, ws.Base)
+		return msg
+	}
+}
+
+func (a *App) handleBenchmarkChecked(msg benchmarkChecked) tea.Cmd {
+	if msg.report != a.benchmark.report {
+		return nil
+	}
+	run := &msg.report.Runs[msg.index]
+	switch {
+	case msg.err != nil:
+		run.Status, run.Error = benchmark.Errored, msg.err.Error()
+	case msg.result.Passed():
+		run.Status = benchmark.Passed
+	default:
+		run.Status, run.ExitCode = benchmark.Failed, msg.result.ExitCode
+	}
+	for _, f := range msg.files {
+		run.Files, run.Added, run.Deleted = run.Files+1, run.Added+f.Added, run.Deleted+f.Deleted
+	}
+	if ws := a.benchmark.workspaces[msg.index]; ws != nil {
+		a.feedAdd(feed.KindTask, string(ws.ID()), "benchmark "+string(run.Status))
+	}
+	return a.benchmarkFinished()
+}
+
+// benchmarkFinished announces the benchmark once every run has a result.
+func (a *App) benchmarkFinished() tea.Cmd {
+	r := a.benchmark.report
+	if r == nil || !r.Done() {
+		return nil
+	}
+	passed := 0
+	for _, run := range r.Runs {
+		if run.Status == benchmark.Passed {
+			passed++
+		}
+	}
+	return a.toast.ShowInfo(fmt.Sprintf("Benchmark finished: %d of %d agents passed; prefix B compares them", passed, len(r.Runs)))
+}
+
+// handleBenchmarkChoice exports the results, starts a new benchmark, or
+// opens the chosen run's workspace.
+func (a *App) handleBenchmarkChoice(value string, index int) tea.Cmd {
+	r := a.benchmark.report
+	if r == nil {
+		return nil
+	}
+	switch value {
+	case benchmarkOptionExport:
+		return a.exportBenchmark()
+	case benchmarkOptionNew:
+		return a.startBenchmark(a.benchmark.project)
+	}
+	index--
+	if r.Done() {
+		index--
+	}
+	if index < 0 || index >= len(r.Runs) {
+		return nil
+	}
+	ws := a.benchmark.workspaces[index]
+	if ws == nil {
+		return a.toast.ShowInfo(r.Runs[index].Workspace + " has no workspace yet")
+	}
+	ws, project := a.findWorkspaceAndProjectByID(string(ws.ID()))
+	if ws == nil {
+		return a.toast.ShowWarning(r.Runs[index].Workspace + " is no longer in amux")
+	}
+	return func() tea.Msg { return messages.WorkspaceActivated{Project: project, Workspace: ws} }
+}
+
+// exportBenchmark writes the results as JSON under amux's home directory.
+func (a *App) exportBenchmark() tea.Cmd {
+	if a.config == nil || a.config.Paths == nil {
+		return nil
+	}
+	path, err := benchmark.Save(filepath.Join(a.config.Paths.Home, "benchmarks"), a.benchmark.report)
+	if err != nil {
+		return common.ReportError(errorContext(errorServiceWorkspace, "exporting benchmark"), err, "")
+	}
+	return a.toast.ShowInfo("Benchmark exported to " + path)
+}
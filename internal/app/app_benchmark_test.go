@@ -0,0 +1,86 @@
+package app
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/andyrewlee/amux/internal/app/activity"
+	"github.com/andyrewlee/amux/internal/benchmark"
+	"github.com/andyrewlee/amux/internal/config"
+	"github.com/andyrewlee/amux/internal/data"
+	"github.com/andyrewlee/amux/internal/messages"
+	"github.com/andyrewlee/amux/internal/process"
+	"github.com/andyrewlee/amux/internal/testutil"
+	"github.com/andyrewlee/amux/internal/ui/common"
+)
+
+func TestBenchmarkFlow(t *testing.T) {
+	h := newDialogHarness(t)
+	h.app.workspaceService = newWorkspaceService(nil, nil, process.NewScriptRunner(6200, 10), "")
+	h.app.config.Paths = &config.Paths{Home: t.TempDir()}
+	repo := testutil.InitRepo(t)
+	project := &data.Project{Name: "shop", Path: repo}
+	agent := h.app.assistantNames()[0]
+
+	h.app.showBenchmark(project)
+	if view := dialogView(t, h.app.dialog); !strings.Contains(view, "Benchmark: Task") {
+		t.Fatalf("dialog = %q, want the task prompt", view)
+	}
+	h.app.handleBenchmarkDialog(common.DialogResult{ID: DialogBenchmarkTask, Confirmed: true, Value: "Fix the thing"})
+	h.app.handleBenchmarkDialog(common.DialogResult{ID: DialogBenchmarkAccept, Confirmed: true, Value: "test -f done.txt"})
+	if cmd, _ := h.app.handleBenchmarkDialog(common.DialogResult{ID: DialogBenchmarkAgents, Confirmed: true, Value: agent + ", " + agent}); cmd == nil {
+		t.Fatal("expected workspace creation")
+	}
+	report := h.app.benchmark.report
+	if report == nil || len(report.Runs) != 2 || report.Runs[1].Workspace != "bench-fix-the-thing-"+agent+"-2" || report.Accept != "test -f done.txt" {
+		t.Fatalf("report = %+v, want a run per agent with unique workspaces", report)
+	}
+
+	ws := &data.Workspace{Name: report.Runs[0].Workspace, Repo: repo, Root: repo, Base: "HEAD"}
+	if h.app.handleBenchmarkSetupComplete(messages.WorkspaceSetupComplete{Workspace: ws}) == nil || report.Runs[0].Status != benchmark.Running {
+		t.Fatal("expected the agent to be given the task once setup finished")
+	}
+	other := &data.Workspace{Name: report.Runs[1].Workspace, Repo: repo, Root: filepath.Join(repo, "other")}
+	h.app.handleBenchmarkSetupComplete(messages.WorkspaceSetupComplete{Workspace: other, Err: errors.New("setup exited 1")})
+
+	if err := os.WriteFile(filepath.Join(repo, "done.txt"), []byte("done\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	wsID := string(ws.ID())
+	working := map[string]activity.AgentState{wsID: activity.StateWorking}
+	done := map[string]activity.AgentState{wsID: activity.StateDone}
+	if h.app.benchmarkDoneEdges(nil, done) != nil {
+		t.Fatal("an agent seen done without working first should not be checked")
+	}
+	cmd := h.app.benchmarkDoneEdges(working, done)
+	if cmd == nil || report.Runs[0].Status != benchmark.Checking {
+		t.Fatal("expected the acceptance command to run once the agent finished")
+	}
+	checked, ok := cmd().(benchmarkChecked)
+	if !ok || checked.err != nil {
+		t.Fatalf("check = %+v", checked)
+	}
+	if h.app.handleBenchmarkChecked(checked) == nil {
+		t.Fatal("a finished benchmark should be announced")
+	}
+	if run := report.Runs[0]; run.Status != benchmark.Passed || run.Files != 1 || run.Added != 1 {
+		t.Fatalf("run = %+v, want a pass with the new file as its diff", run)
+	}
+
+	h.app.showBenchmark(project)
+	h.app.dialog.SetSize(160, 40)
+	view := dialogView(t, h.app.dialog)
+	for _, want := range []string{"AGENT", agent + " #1  pass", "1 file +1 -0", agent + " #2  error: setup failed", benchmarkOptionExport} {
+		if !strings.Contains(view, want) {
+			t.Fatalf("results missing %q, got %q", want, view)
+		}
+	}
+	h.app.handleBenchmarkChoice(benchmarkOptionExport, 0)
+	exported, _ := filepath.Glob(filepath.Join(h.app.config.Paths.Home, "benchmarks", "shop-*.json"))
+	if len(exported) != 1 {
+		t.Fatalf("exported = %v, want the report as JSON", exported)
+	}
+}
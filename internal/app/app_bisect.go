@@ -0,0 +1,227 @@
+package app
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+
+	tea "charm.land/bubbletea/v2"
+
+	"github.com/andyrewlee/amux/internal/data"
+	"github.com/andyrewlee/amux/internal/git"
+	"github.com/andyrewlee/amux/internal/testrun"
+	"github.com/andyrewlee/amux/internal/ui/common"
+)
+
+// bisectVerdictFile is the file, in the bisect's temporary directory, the
+// agent writes its verdict on the checked-out commit to.
+const bisectVerdictFile = "verdict"
+
+// bisectState is a bisect of a workspace's history, run in a temporary
+// worktree so the workspace's checkout is never moved. Each commit is judged
+// by command's exit status or, when command is empty, by the workspace's
+// agent looking for bug.
+type bisectState struct {
+	workspace *data.Workspace
+	bad, good string
+	command   string
+	bug       string
+	// dir is the temporary directory holding the worktree, root, and the
+	// agent's verdict.
+	dir  string
+	root string
+	step git.BisectStep
+	// tested lists the commits judged so far, in order.
+	tested  []bisectTest
+	running bool
+	// waiting is set while step.Commit waits for the agent or the step
+	// dialog to judge it.
+	waiting bool
+	// shown is the commit the step dialog was last shown for.
+	shown string
+	// cancel stops the test command running on step.Commit.
+	cancel context.CancelFunc
+	// done is set once the result is shown.
+	done bool
+}
+
+// bisectTest is a commit judged during a bisect.
+type bisectTest struct {
+	commit, subject, verdict string
+}
+
+// bisectStarted reports creating the worktree and starting the bisect in it.
+type bisectStarted struct {
+	run       *bisectState
+	dir, root string
+	step      git.BisectStep
+	err       error
+}
+
+// showBisect starts setting up a bisect of ws, or shows the one in progress.
+func (a *App) showBisect(ws *data.Workspace) tea.Cmd {
+	if ws == nil {
+		return nil
+	}
+	if b := a.bisect; b != nil && !b.done {
+		if b.step.Commit == "" {
+			return a.toast.ShowInfo("Bisect of " + b.workspace.Name + " is starting")
+		}
+		return a.showBisectStep()
+	}
+	a.bisect = &bisectState{workspace: ws}
+	a.dialog = common.NewInputDialog(DialogBisectBad, "Bisect: Bad Commit", "A commit, tag, or branch that has the bug")
+	a.dialog.SetInputValue("HEAD")
+	a.dialogWorkspace = ws
+	a.presentDialog(a.dialog)
+	return nil
+}
+
+// handleBisectDialog steps through setting up a bisect and acts on its step
+// and result dialogs, reporting whether result was from one of them.
+func (a *App) handleBisectDialog(result common.DialogResult, ws *data.Workspace) (tea.Cmd, bool) {
+	switch result.ID {
+	case DialogBisectBad, DialogBisectGood, DialogBisectCommand, DialogBisectBug:
+	case DialogBisectStep:
+		return a.handleBisectStepChoice(result), true
+	case DialogBisectResult:
+		return a.handleBisectResultChoice(result, ws), true
+	default:
+		return nil, false
+	}
+	b := a.bisect
+	if b == nil || b.workspace != ws || b.running || b.step.Commit != "" {
+		return nil, true
+	}
+	if !result.Confirmed {
+		a.bisect = nil
+		return nil, true
+	}
+	value := strings.TrimSpace(result.Value)
+	switch result.ID {
+	case DialogBisectBad:
+		if value == "" {
+			a.bisect = nil
+			return a.toast.ShowWarning("A bisect needs a bad commit"), true
+		}
+		b.bad = value
+		a.dialog = common.NewInputDialog(DialogBisectGood, "Bisect: Good Commit", "A commit, tag, or branch without the bug")
+	case DialogBisectGood:
+		if value == "" {
+			a.bisect = nil
+			return a.toast.ShowWarning("A bisect needs a good commit"), true
+		}
+		b.good = value
+		a.dialog = common.NewInputDialog(DialogBisectCommand, "Bisect: Test Command",
+			"Exits 0 when good, 125 to skip; leave empty to have the agent judge")
+		a.dialog.SetInputValue(testrun.Detect(ws.Root))
+	case DialogBisectCommand:
+		if value != "" {
+			b.command = value
+			return a.startBisect(), true
+		}
+		if a.workspaceAgentTab(ws) < 0 {
+			a.bisect = nil
+			return a.toast.ShowWarning("No agent tab in " + ws.Name + " to judge commits; give a test command"), true
+		}
+		a.dialog = common.NewInputDialog(DialogBisectBug, "Bisect: Bug", "What the agent looks for in each commit")
+		a.dialog.SetInputCharLimit(fanOutTaskLimit)
+	case DialogBisectBug:
+		if value == "" {
+			a.bisect = nil
+			return a.toast.ShowWarning("The agent needs the bug to look for"), true
+		}
+		b.bug = value
+		return a.startBisect(), true
+	}
+	a.dialogWorkspace = ws
+	a.presentDialog(a.dialog)
+	return nil, true
+}
+
+// startBisect checks the bad commit out in a temporary worktree and starts
+// bisecting there, off the UI goroutine. The commits are resolved in the
+// workspace, so HEAD means its checkout rather than the repository's.
+func (a *App) startBisect() tea.Cmd {
+	b := a.bisect
+	b.running = true
+	ws, bad, good := b.workspace, b.bad, b.good
+	return func() tea.Msg {
+		started := bisectStarted{run: b}
+		ctx := context.Background()
+		badCommit, err := git.ResolveCommit(ctx, ws.Root, bad)
+		if err != nil {
+			started.err = err
+			return started
+		}
+		goodCommit, err := git.ResolveCommit(ctx, ws.Root, good)
+		if err != nil {
+			started.err = err
+			return started
+		}
+		if started.dir, started.err = os.MkdirTemp("", "amux-bisect-"); started.err != nil {
+			return started
+		}
+		root := filepath.Join(started.dir, filepath.Base(ws.Root))
+		if started.err = git.AddBisectWorktree(ctx, ws.Repo, root, badCommit); started.err != nil {
+			return started
+		}
+		started.root = root
+		started.step, started.err = git.StartBisect(ctx, root, badCommit, goodCommit)
+		return started
+	}
+}
+
+func (a *App) handleBisectStarted(msg bisectStarted) tea.Cmd {
+	b := a.bisect
+	if msg.run != b {
+		return removeBisect(msg.run.workspace, msg.dir, msg.root)
+	}
+	b.running = false
+	b.dir, b.root = msg.dir, msg.root
+	return a.bisectStepped(msg.step, msg.err)
+}
+
+// removeBisect removes a bisect's worktree and temporary directory, off the
+// UI goroutine.
+func removeBisect(ws *data.Workspace, dir, root string) tea.Cmd {
+	if dir == "" {
+		return nil
+	}
+	return func() tea.Msg {
+		cleanUpBisect(ws, dir, root)
+		return nil
+	}
+}
+
+func cleanUpBisect(ws *data.Workspace, dir, root string) {
+	if root != "" {
+		_ = git.RemoveBisectWorktree(context.Background(), ws.Repo, root)
+	}
+	_ = os.RemoveAll(dir)
+}
+
+// stopBisect stops the bisect in progress and removes its worktree, for
+// Shutdown.
+func (a *App) stopBisect() {
+	b := a.bisect
+	a.bisect = nil
+	if b == nil {
+		return
+	}
+	if b.cancel != nil {
+		b.cancel()
+	}
+	if b.dir != "" {
+		cleanUpBisect(b.workspace, b.dir, b.root)
+	}
+}
+
+// shortCommit abbreviates a commit hash for display.
+func shortCommit(commit string) string {
+	if len(commit) > 7 {
+		return commit[:7]
+	}
+	return commit
+}
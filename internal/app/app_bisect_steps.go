@@ -0,0 +1,330 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	tea "charm.land/bubbletea/v2"
+
+	"github.com/andyrewlee/amux/internal/data"
+	"github.com/andyrewlee/amux/internal/git"
+	"github.com/andyrewlee/amux/internal/testrun"
+	"github.com/andyrewlee/amux/internal/ui/common"
+)
+
+// bisectPollInterval is how often the agent's verdict file is checked.
+const bisectPollInterval = time.Second
+
+// Options of the bisect's step and result dialogs.
+const (
+	bisectOptionGood    = "Good"
+	bisectOptionBad     = "Bad"
+	bisectOptionSkip    = "Skip"
+	bisectOptionStop    = "Stop"
+	bisectOptionDone    = "Done"
+	bisectOptionExplain = "Ask agent to explain"
+)
+
+var bisectVerdicts = map[string]string{
+	bisectOptionGood: git.BisectGood,
+	bisectOptionBad:  git.BisectBad,
+	bisectOptionSkip: git.BisectSkip,
+}
+
+// bisectTested carries the test command's result on a commit.
+type bisectTested struct {
+	run    *bisectState
+	commit string
+	result testrun.Result
+	err    error
+}
+
+// bisectMarked reports marking a commit and checking out the next.
+type bisectMarked struct {
+	run  *bisectState
+	step git.BisectStep
+	err  error
+}
+
+// bisectVerdictPolled carries what the agent has written to the verdict
+// file for commit so far.
+type bisectVerdictPolled struct {
+	run     *bisectState
+	commit  string
+	verdict string
+}
+
+// bisectStepped moves on from where the bisect stands: it has the next
+// commit judged, or shows the result.
+func (a *App) bisectStepped(step git.BisectStep, err error) tea.Cmd {
+	b := a.bisect
+	b.step = step
+	switch {
+	case errors.Is(err, git.ErrBisectInconclusive), err == nil && step.Done:
+		return a.finishBisect()
+	case err != nil:
+		return a.abortBisect(err)
+	}
+	if b.command != "" {
+		return a.testBisectStep()
+	}
+	b.waiting = true
+	return common.SafeBatch(
+		a.sendToWorkspaceAgent(b.workspace, bisectStepPrompt(b)),
+		a.toast.ShowInfo(bisectProgress(b)+"; prefix g b to judge it yourself"),
+		pollBisectVerdict(b),
+	)
+}
+
+// testBisectStep runs the test command on the checked-out commit, off the
+// UI goroutine.
+func (a *App) testBisectStep() tea.Cmd {
+	b := a.bisect
+	if a.workspaceService == nil || a.workspaceService.scripts == nil {
+		return a.abortBisect(errors.New("scripts are unavailable"))
+	}
+	scripts := a.workspaceService.scripts
+	ws := *b.workspace
+	ws.Root = b.root
+	commit, command := b.step.Commit, b.command
+	ctx, cancel := context.WithTimeout(context.Background(), testRunTimeout)
+	b.running, b.cancel = true, cancel
+	test := func() tea.Msg {
+		defer cancel()
+		msg := bisectTested{run: b, commit: commit}
+		command, env, err := scripts.WorkspaceCommand(&ws, command)
+		if err != nil {
+			msg.err = err
+			return msg
+		}
+		msg.result, msg.err = testrun.Run(ctx, ws.Root, command, env)
+		return msg
+	}
+	return common.SafeBatch(test, a.toast.ShowInfo(bisectProgress(b)))
+}
+
+func (a *App) handleBisectTested(msg bisectTested) tea.Cmd {
+	b := a.bisect
+	if msg.run != b || msg.commit != b.step.Commit {
+		return nil
+	}
+	b.running, b.cancel = false, nil
+	if msg.err != nil {
+		return a.abortBisect(msg.err)
+	}
+	verdict, err := bisectVerdict(msg.result.ExitCode)
+	if err != nil {
+		return a.abortBisect(err)
+	}
+	return a.markBisect(verdict)
+}
+
+// bisectVerdict judges a commit by the test command's exit status, as git
+// bisect run does: 0 is good, 125 skips the commit, and other statuses up
+// to 127 are bad. Higher ones, from signals, stop the bisect.
+func bisectVerdict(code int) (string, error) {
+	switch {
+	case code == 0:
+		return git.BisectGood, nil
+	case code == 125:
+		return git.BisectSkip, nil
+	case code > 0 && code < 128:
+		return git.BisectBad, nil
+	}
+	return "", fmt.Errorf("the test command exited with status %d", code)
+}
+
+// pollBisectVerdict reads the agent's verdict on the checked-out commit
+// after a moment.
+func pollBisectVerdict(b *bisectState) tea.Cmd {
+	commit, path := b.step.Commit, filepath.Join(b.dir, bisectVerdictFile)
+	return common.SafeTick(bisectPollInterval, func(time.Time) tea.Msg {
+		raw, _ := os.ReadFile(path)
+		return bisectVerdictPolled{run: b, commit: commit, verdict: strings.ToLower(strings.TrimSpace(string(raw)))}
+	})
+}
+
+func (a *App) handleBisectVerdictPolled(msg bisectVerdictPolled) tea.Cmd {
+	b := a.bisect
+	if msg.run != b || !b.waiting || msg.commit != b.step.Commit {
+		return nil
+	}
+	switch msg.verdict {
+	case git.BisectGood, git.BisectBad, git.BisectSkip:
+		note := a.toast.ShowInfo(fmt.Sprintf("Bisect: the agent judged %s %s", shortCommit(msg.commit), msg.verdict))
+		return common.SafeBatch(note, a.markBisect(msg.verdict))
+	}
+	return pollBisectVerdict(b)
+}
+
+// markBisect records verdict on the checked-out commit and checks out the
+// next, off the UI goroutine.
+func (a *App) markBisect(verdict string) tea.Cmd {
+	b := a.bisect
+	b.waiting, b.running = false, true
+	b.tested = append(b.tested, bisectTest{commit: b.step.Commit, subject: b.step.Subject, verdict: verdict})
+	dir, root := b.dir, b.root
+	return func() tea.Msg {
+		_ = os.Remove(filepath.Join(dir, bisectVerdictFile))
+		step, err := git.MarkBisect(context.Background(), root, verdict)
+		return bisectMarked{run: b, step: step, err: err}
+	}
+}
+
+func (a *App) handleBisectMarked(msg bisectMarked) tea.Cmd {
+	if msg.run != a.bisect {
+		return nil
+	}
+	a.bisect.running = false
+	return a.bisectStepped(msg.step, msg.err)
+}
+
+// showBisectStep shows the commit being judged. While it waits for the
+// agent, a verdict can be picked instead; otherwise the bisect can only be
+// stopped.
+func (a *App) showBisectStep() tea.Cmd {
+	b := a.bisect
+	var message string
+	switch {
+	case b.waiting:
+		message = fmt.Sprintf("%s %s is checked out in %s for the agent to judge. Pick a verdict to go on without it.",
+			shortCommit(b.step.Commit), b.step.Subject, b.root)
+	case b.command != "":
+		message = fmt.Sprintf("Running %s on %s %s.", b.command, shortCommit(b.step.Commit), b.step.Subject)
+	default:
+		message = "Checking out the next commit."
+	}
+	message += fmt.Sprintf("\n\nTested %d commits; roughly %d steps left after this one.", len(b.tested), b.step.Steps)
+	b.shown = b.step.Commit
+	a.dialog = common.NewSelectDialog(DialogBisectStep, "Bisect "+b.workspace.Name, message, bisectStepOptions(b))
+	a.dialogWorkspace = b.workspace
+	a.presentDialog(a.dialog)
+	return nil
+}
+
+func bisectStepOptions(b *bisectState) []string {
+	if b.waiting {
+		return []string{bisectOptionGood, bisectOptionBad, bisectOptionSkip, bisectOptionStop}
+	}
+	return []string{bisectOptionStop}
+}
+
+// handleBisectStepChoice judges the commit the step dialog showed, or stops
+// the bisect.
+func (a *App) handleBisectStepChoice(result common.DialogResult) tea.Cmd {
+	b := a.bisect
+	if b == nil || b.done || !result.Confirmed {
+		return nil
+	}
+	if result.Value == bisectOptionStop {
+		return a.abortBisect(nil)
+	}
+	verdict, ok := bisectVerdicts[result.Value]
+	if !ok {
+		return nil
+	}
+	if !b.waiting || b.shown != b.step.Commit {
+		return a.toast.ShowInfo("The bisect has moved on; prefix g b shows where it stands")
+	}
+	return a.markBisect(verdict)
+}
+
+// abortBisect ends the bisect and removes its worktree, reporting err when
+// it failed.
+func (a *App) abortBisect(err error) tea.Cmd {
+	b := a.bisect
+	a.bisect = nil
+	if b.cancel != nil {
+		b.cancel()
+	}
+	cleanUp := removeBisect(b.workspace, b.dir, b.root)
+	if err == nil {
+		return common.SafeBatch(cleanUp, a.toast.ShowInfo("Bisect stopped"))
+	}
+	return common.SafeBatch(cleanUp, common.ReportError(errorContext(errorServiceWorkspace, "bisecting"), err, ""))
+}
+
+// finishBisect removes the worktree and shows the first bad commit with the
+// commits judged on the way to it.
+func (a *App) finishBisect() tea.Cmd {
+	b := a.bisect
+	b.done, b.waiting = true, false
+	cleanUp := removeBisect(b.workspace, b.dir, b.root)
+	b.dir, b.root = "", ""
+	options := []string{bisectOptionDone}
+	if b.step.Done {
+		options = append(options, bisectOptionExplain)
+	}
+	a.dialog = common.NewSelectDialog(DialogBisectResult, "Bisect Result", bisectSummary(b), options)
+	a.dialogWorkspace = b.workspace
+	a.presentDialog(a.dialog)
+	return cleanUp
+}
+
+func (a *App) handleBisectResultChoice(result common.DialogResult, ws *data.Workspace) tea.Cmd {
+	b := a.bisect
+	if b == nil || !b.done {
+		return nil
+	}
+	a.bisect = nil
+	if !result.Confirmed || result.Value != bisectOptionExplain {
+		return nil
+	}
+	return a.sendToWorkspaceAgent(ws, bisectExplainPrompt(b))
+}
+
+// bisectProgress is the toast shown as a commit starts being judged.
+func bisectProgress(b *bisectState) string {
+	return fmt.Sprintf("Bisect: judging %s, roughly %d steps left after it", shortCommit(b.step.Commit), b.step.Steps)
+}
+
+// bisectSummary names the first bad commit, or the commits it may be when
+// too many were skipped, and lists the commits judged.
+func bisectSummary(b *bisectState) string {
+	var s strings.Builder
+	if b.step.Done {
+		fmt.Fprintf(&s, "The first bad commit is %s %s.", shortCommit(b.step.Commit), b.step.Subject)
+	} else {
+		s.WriteString("Only skipped commits are left, so the first bad commit is one of:\n")
+		for _, commit := range b.step.Candidates {
+			fmt.Fprintf(&s, "\n%s", shortCommit(commit))
+		}
+	}
+	fmt.Fprintf(&s, "\n\nJudged %d commits:\n", len(b.tested))
+	for _, t := range b.tested {
+		mark := "·"
+		switch t.verdict {
+		case git.BisectGood:
+			mark = "✓"
+		case git.BisectBad:
+			mark = "✗"
+		}
+		fmt.Fprintf(&s, "\n%s %s %s  %s", mark, shortCommit(t.commit), t.subject, t.verdict)
+	}
+	return s.String()
+}
+
+// bisectStepPrompt asks the agent to judge the checked-out commit and write
+// its verdict where the bisect polls for it.
+func bisectStepPrompt(b *bisectState) string {
+	return fmt.Sprintf("I am bisecting to find the commit that introduced this bug:\n\n%s\n\n"+
+		"Commit %s (%s) is checked out in %s. Find out whether it has the bug without changing any files there, "+
+		"then write one word to %s: good if the bug is absent, bad if it is present, or skip if you cannot tell.\n",
+		b.bug, shortCommit(b.step.Commit), b.step.Subject, b.root, filepath.Join(b.dir, bisectVerdictFile))
+}
+
+// bisectExplainPrompt asks the workspace's agent how the first bad commit
+// caused the bug.
+func bisectExplainPrompt(b *bisectState) string {
+	bug := b.bug
+	if bug == "" {
+		bug = "failures of " + b.command
+	}
+	return fmt.Sprintf("Bisecting found that commit %s (%s) introduced %s. Explain how, using git show %s, and suggest a fix.\n",
+		shortCommit(b.step.Commit), b.step.Subject, bug, b.step.Commit)
+}
@@ -0,0 +1,149 @@
+package app
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	tea "charm.land/bubbletea/v2"
+
+	"github.com/andyrewlee/amux/internal/data"
+	"github.com/andyrewlee/amux/internal/git"
+	"github.com/andyrewlee/amux/internal/process"
+	"github.com/andyrewlee/amux/internal/testrun"
+	"github.com/andyrewlee/amux/internal/testutil"
+	"github.com/andyrewlee/amux/internal/ui/common"
+)
+
+func TestBisectFlow(t *testing.T) {
+	skipIfNoGit(t)
+	h := newDialogHarness(t)
+	h.app.workspaceService = newWorkspaceService(nil, nil, process.NewScriptRunner(6200, 10), "")
+	repo := testutil.InitRepo(t)
+	good := testutil.RunGit(t, repo, "rev-parse", "HEAD")
+	for i := 1; i <= 6; i++ {
+		content := "ok " + strconv.Itoa(i)
+		if i >= 3 {
+			content = "broken " + strconv.Itoa(i)
+		}
+		if err := os.WriteFile(filepath.Join(repo, "state"), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		testutil.RunGit(t, repo, "add", "state")
+		testutil.RunGit(t, repo, "commit", "-q", "-m", "change "+strconv.Itoa(i))
+	}
+	head := testutil.RunGit(t, repo, "rev-parse", "HEAD")
+	ws := &data.Workspace{Name: "shop", Repo: repo, Root: repo}
+
+	h.app.showBisect(ws)
+	if view := dialogView(t, h.app.dialog); !strings.Contains(view, "Bisect: Bad Commit") {
+		t.Fatalf("dialog = %q, want the bad commit prompt", view)
+	}
+	h.app.handleBisectDialog(common.DialogResult{ID: DialogBisectBad, Confirmed: true, Value: "HEAD"}, ws)
+	h.app.handleBisectDialog(common.DialogResult{ID: DialogBisectGood, Confirmed: true, Value: good}, ws)
+	start, _ := h.app.handleBisectDialog(common.DialogResult{ID: DialogBisectCommand, Confirmed: true, Value: "grep -q ok state"}, ws)
+	if start == nil {
+		t.Fatal("expected the bisect to start")
+	}
+	started, ok := start().(bisectStarted)
+	if !ok || started.err != nil {
+		t.Fatalf("start = %+v", started)
+	}
+	h.app.handleBisectStarted(started)
+	b := h.app.bisect
+	if b == nil || !b.running || b.step.Commit == "" || !strings.HasPrefix(b.root, b.dir) {
+		t.Fatalf("bisect = %+v, want the first commit being tested", b)
+	}
+
+	// Judge each commit as the test command would, from the worktree.
+	var cleanUp tea.Cmd
+	for steps := 0; !b.done; steps++ {
+		if steps > 4 {
+			t.Fatalf("bisect did not finish: %+v", b)
+		}
+		raw, err := os.ReadFile(filepath.Join(b.root, "state"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		code := 0
+		if strings.HasPrefix(string(raw), "broken") {
+			code = 1
+		}
+		mark := h.app.handleBisectTested(bisectTested{run: b, commit: b.step.Commit, result: testrun.Result{ExitCode: code}})
+		if mark == nil {
+			t.Fatal("expected the commit to be marked")
+		}
+		if next := h.app.handleBisectMarked(mark().(bisectMarked)); b.done {
+			cleanUp = next
+		}
+	}
+
+	view := dialogView(t, h.app.dialog)
+	if !strings.Contains(view, "The first bad commit is") || !strings.Contains(view, "change 3.") || !strings.Contains(view, bisectOptionExplain) {
+		t.Fatalf("result dialog = %q, want change 3", view)
+	}
+	if !strings.Contains(view, "✗") || !strings.Contains(view, "✓") {
+		t.Fatalf("result dialog = %q, want the commits judged", view)
+	}
+	if cleanUp == nil {
+		t.Fatal("expected the worktree to be removed")
+	}
+	cleanUp()
+	if _, err := os.Stat(started.dir); !os.IsNotExist(err) {
+		t.Fatalf("temporary directory still exists: %v", err)
+	}
+	if got := testutil.RunGit(t, repo, "rev-parse", "HEAD"); got != head {
+		t.Fatalf("the workspace's checkout moved to %s", got)
+	}
+	h.app.handleBisectDialog(common.DialogResult{ID: DialogBisectResult, Confirmed: true, Value: bisectOptionDone}, ws)
+	if h.app.bisect != nil {
+		t.Fatal("the bisect should end with its result")
+	}
+}
+
+func TestBisectAgentVerdict(t *testing.T) {
+	h := newDialogHarness(t)
+	ws := harnessWorkspace()
+	b := &bisectState{workspace: ws, bug: "login fails", dir: t.TempDir(), waiting: true,
+		step: git.BisectStep{Commit: "0123456789abcdef", Subject: "Rework login", Steps: 2}}
+	b.root = filepath.Join(b.dir, "ws")
+	h.app.bisect = b
+
+	prompt := bisectStepPrompt(b)
+	for _, want := range []string{"login fails", "0123456 (Rework login)", b.root, filepath.Join(b.dir, bisectVerdictFile)} {
+		if !strings.Contains(prompt, want) {
+			t.Fatalf("prompt = %q, want %q", prompt, want)
+		}
+	}
+
+	h.app.showBisectStep()
+	if view := dialogView(t, h.app.dialog); !strings.Contains(view, bisectOptionBad) || !strings.Contains(view, "Tested 0 commits") {
+		t.Fatalf("step dialog = %q, want the verdicts", view)
+	}
+	if cmd := h.app.handleBisectVerdictPolled(bisectVerdictPolled{run: b, commit: b.step.Commit}); cmd == nil || !b.waiting {
+		t.Fatal("expected polling to go on without a verdict")
+	}
+	if cmd := h.app.handleBisectVerdictPolled(bisectVerdictPolled{run: b, commit: b.step.Commit, verdict: git.BisectBad}); cmd == nil {
+		t.Fatal("expected the agent's verdict to be marked")
+	}
+	if b.waiting || !b.running || len(b.tested) != 1 || b.tested[0].verdict != git.BisectBad {
+		t.Fatalf("bisect = %+v, want the commit marked bad", b)
+	}
+
+	// The step dialog shown before the verdict no longer applies.
+	h.app.handleBisectStepChoice(common.DialogResult{ID: DialogBisectStep, Confirmed: true, Value: bisectOptionGood})
+	if len(b.tested) != 1 {
+		t.Fatalf("a stale step dialog marked %+v", b.tested)
+	}
+}
+
+func TestBisectVerdict(t *testing.T) {
+	for code, want := range map[int]string{0: git.BisectGood, 1: git.BisectBad, 125: git.BisectSkip, 127: git.BisectBad, 130: ""} {
+		got, err := bisectVerdict(code)
+		if got != want || (err != nil) != (want == "") {
+			t.Errorf("bisectVerdict(%d) = %q, %v; want %q", code, got, err, want)
+		}
+	}
+}
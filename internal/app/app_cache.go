@@ -110,6 +110,8 @@ type renderCacheState struct {
 	centerHelp           drawableCache
 	centerHelpGate       paneGate
 	centerBorders        borderCache
+	inputMode            drawableCache
+	statusBar            drawableCache
 }
 
 func newRenderCacheState() renderCacheState {
@@ -0,0 +1,134 @@
+package app
+
+import (
+	"context"
+	"time"
+
+	tea "charm.land/bubbletea/v2"
+
+	"github.com/andyrewlee/amux/internal/changelog"
+	"github.com/andyrewlee/amux/internal/data"
+	"github.com/andyrewlee/amux/internal/messages"
+	"github.com/andyrewlee/amux/internal/ui/common"
+	"github.com/andyrewlee/amux/internal/ui/sidebar"
+)
+
+// changelogTimeout bounds reading the history for a changelog entry.
+const changelogTimeout = 30 * time.Second
+
+// collectChangelog reads a repository's unreleased work; tests replace it to
+// avoid running git.
+var collectChangelog = changelog.Collect
+
+// The changelog dialog's options, in order.
+const (
+	changelogDraftOption = "Draft in Notes"
+	changelogWriteOption = "Write to " + changelog.Filename
+)
+
+// changelogDrafted carries the entry drafted for a workspace's notes.
+type changelogDrafted struct {
+	workspace *data.Workspace
+	entry     changelog.Entry
+	err       error
+}
+
+// changelogWritten reports writing a workspace's drafted entry to its
+// changelog.
+type changelogWritten struct {
+	workspace *data.Workspace
+	err       error
+}
+
+// showChangelog offers to draft a changelog entry for ws's unreleased work
+// into its notes, where it can be edited, and to write an entry drafted
+// there before to CHANGELOG.md.
+func (a *App) showChangelog(ws *data.Workspace) tea.Cmd {
+	options := []string{changelogDraftOption}
+	if _, ok := a.notesChangelog(ws); ok {
+		options = append(options, changelogWriteOption)
+	}
+	a.dialog = common.NewSelectDialog(DialogChangelog, "Changelog: "+ws.Name,
+		"The conventional commits and merged branches since the last tag", options)
+	a.dialogWorkspace = ws
+	a.presentDialog(a.dialog)
+	return nil
+}
+
+// notesChangelog returns the entry drafted into ws's notes, when they are
+// the ones the sidebar shows.
+func (a *App) notesChangelog(ws *data.Workspace) (string, bool) {
+	if a.sidebar == nil || !a.showsNotesOf(ws) {
+		return "", false
+	}
+	return changelog.FromNotes(a.sidebar.Scratchpad().Value())
+}
+
+// showsNotesOf reports whether the sidebar's notes are ws's.
+func (a *App) showsNotesOf(ws *data.Workspace) bool {
+	return ws != nil && a.activeWorkspace != nil && a.activeWorkspace.ID() == ws.ID()
+}
+
+// handleChangelogDialog handles the changelog dialog, reporting whether
+// result was from it.
+func (a *App) handleChangelogDialog(result common.DialogResult, ws *data.Workspace) (tea.Cmd, bool) {
+	if result.ID != DialogChangelog {
+		return nil, false
+	}
+	if !result.Confirmed || ws == nil {
+		return nil, true
+	}
+	if result.Value == changelogWriteOption {
+		return a.writeChangelog(ws), true
+	}
+	root := ws.Root
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), changelogTimeout)
+		defer cancel()
+		entry, err := collectChangelog(ctx, root)
+		return changelogDrafted{workspace: ws, entry: entry, err: err}
+	}, true
+}
+
+// handleChangelogDrafted puts the drafted entry into the workspace's notes,
+// replacing one drafted before, and shows them for editing.
+func (a *App) handleChangelogDrafted(msg changelogDrafted) tea.Cmd {
+	if msg.err != nil {
+		return common.ReportError(errorContext(errorServiceWorkspace, "drafting a changelog entry"), msg.err, "")
+	}
+	if a.sidebar == nil || !a.showsNotesOf(msg.workspace) {
+		return a.toast.ShowWarning("Switch back to " + msg.workspace.Name + " to draft its changelog")
+	}
+	notes := a.sidebar.Scratchpad()
+	save, ok := notes.SetValue(msg.workspace, changelog.ToNotes(notes.Value(), msg.entry.Markdown()))
+	if !ok {
+		return a.toast.ShowWarning("Notes are still loading; try again in a moment")
+	}
+	a.sidebar.SetActiveTab(sidebar.TabNotes)
+	info := "Edit the entry in Notes, then write it to " + changelog.Filename + " from the changelog command"
+	if msg.entry.Empty() {
+		info = "Nothing to add since " + msg.entry.Since
+	}
+	return common.SafeBatch(save, a.focusPane(messages.PaneSidebar), a.toast.ShowInfo(info))
+}
+
+// writeChangelog writes the entry drafted into ws's notes to the changelog
+// at its root, off the UI goroutine.
+func (a *App) writeChangelog(ws *data.Workspace) tea.Cmd {
+	entry, ok := a.notesChangelog(ws)
+	if !ok {
+		return a.toast.ShowWarning("Draft a changelog entry in Notes first")
+	}
+	root := ws.Root
+	return func() tea.Msg {
+		return changelogWritten{workspace: ws, err: changelog.Write(root, entry)}
+	}
+}
+
+// handleChangelogWritten reports writing the changelog.
+func (a *App) handleChangelogWritten(msg changelogWritten) tea.Cmd {
+	if msg.err != nil {
+		return common.ReportError(errorContext(errorServiceWorkspace, "writing the changelog"), msg.err, "")
+	}
+	return a.toast.ShowSuccess("Wrote the changelog entry to " + changelog.Filename + " in " + msg.workspace.Name)
+}
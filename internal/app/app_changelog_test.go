@@ -0,0 +1,56 @@
+package app
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/andyrewlee/amux/internal/changelog"
+	"github.com/andyrewlee/amux/internal/commitmsg"
+	"github.com/andyrewlee/amux/internal/config"
+	"github.com/andyrewlee/amux/internal/ui/common"
+	"github.com/andyrewlee/amux/internal/ui/sidebar"
+)
+
+func TestChangelogDraftsIntoNotesThenWrites(t *testing.T) {
+	app, ws := newNotifyTestApp(config.UISettings{})
+	ws.Root = t.TempDir()
+	app.toast = common.NewToastModel()
+	app.sidebar = sidebar.NewTabbedSidebar()
+	app.sidebar.SetWorkspace(ws)
+	app.activeWorkspace = ws
+	old := collectChangelog
+	t.Cleanup(func() { collectChangelog = old })
+	collectChangelog = func(context.Context, string) (changelog.Entry, error) {
+		return changelog.Entry{Since: "v1.0.0", Commits: []changelog.Commit{
+			{Hash: "abc1234", Header: commitmsg.Header{Type: "feat", Subject: "draft changelogs"}},
+		}}, nil
+	}
+
+	app.showChangelog(ws)
+	app.dialog.SetSize(160, 40)
+	if view := dialogView(t, app.dialog); strings.Contains(view, changelogWriteOption) {
+		t.Fatalf("writing should wait for a drafted entry:\n%s", view)
+	}
+	cmd := app.handleDialogResult(common.DialogResult{ID: DialogChangelog, Confirmed: true, Value: changelogDraftOption})
+	app.handleChangelogDrafted(cmd().(changelogDrafted))
+	if app.sidebar.ActiveTab() != sidebar.TabNotes || !strings.Contains(app.sidebar.Scratchpad().Value(), "- draft changelogs (abc1234)") {
+		t.Fatalf("tab %d, notes %q", app.sidebar.ActiveTab(), app.sidebar.Scratchpad().Value())
+	}
+
+	app.showChangelog(ws)
+	app.dialog.SetSize(160, 40)
+	if view := dialogView(t, app.dialog); !strings.Contains(view, changelogWriteOption) {
+		t.Fatalf("expected the write option once drafted:\n%s", view)
+	}
+	cmd = app.handleDialogResult(common.DialogResult{ID: DialogChangelog, Confirmed: true, Value: changelogWriteOption})
+	if written := cmd().(changelogWritten); written.err != nil {
+		t.Fatalf("write: %v", written.err)
+	}
+	got, err := os.ReadFile(filepath.Join(ws.Root, changelog.Filename))
+	if err != nil || !strings.Contains(string(got), "Changes since v1.0.0.") {
+		t.Fatalf("%s = %q, %v", changelog.Filename, got, err)
+	}
+}
@@ -0,0 +1,305 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	tea "charm.land/bubbletea/v2"
+
+	"github.com/andyrewlee/amux/internal/data"
+	"github.com/andyrewlee/amux/internal/feed"
+	"github.com/andyrewlee/amux/internal/git"
+	"github.com/andyrewlee/amux/internal/messages"
+	"github.com/andyrewlee/amux/internal/process"
+	"github.com/andyrewlee/amux/internal/ui/common"
+	"github.com/andyrewlee/amux/internal/ui/dashboard"
+)
+
+// checksRunTimeout bounds one run of a worktree's checks.
+const checksRunTimeout = 30 * time.Minute
+
+// checksState is the last check run of each workspace.
+type checksState struct {
+	running map[string]bool
+	runs    map[string]checksRun
+}
+
+// checksRun is one run of a workspace's checks. fingerprint identifies the
+// tree they ran against, or is "" when the tree changed during the run, so
+// the run is reused only while the tree stays the same.
+type checksRun struct {
+	fingerprint string
+	results     []process.CheckResult
+	at          time.Time
+}
+
+// failed names the checks that did not pass.
+func (r checksRun) failed() []string {
+	var names []string
+	for _, res := range r.results {
+		if !res.Passed() {
+			names = append(names, res.Name)
+		}
+	}
+	return names
+}
+
+// checksPlanned carries a workspace's checks and tree fingerprint, loaded
+// off the UI goroutine. gate is set when the run was started by a commit.
+type checksPlanned struct {
+	workspace   *data.Workspace
+	set         *process.CheckSet
+	fingerprint string
+	gate        bool
+	err         error
+}
+
+type checksFinished struct {
+	workspace *data.Workspace
+	run       checksRun
+	gate      bool
+}
+
+// Checks panel options ahead of the checks.
+const (
+	checksOptionRerun = "Run checks again"
+	checksOptionSend  = "Send failures to agent"
+)
+
+// commitAfterChecks opens the commit dialog for ws, first running its checks
+// when the project requires them to pass.
+func (a *App) commitAfterChecks(msg messages.ShowCommitWorkspaceDialog) tea.Cmd {
+	if msg.Workspace == nil || a.workspaceService == nil || a.workspaceService.scripts == nil {
+		return a.handleShowCommitWorkspaceDialog(msg)
+	}
+	return a.planChecks(msg.Workspace, "", true)
+}
+
+// showChecks opens ws's checks panel, or runs its checks when there is no
+// result to show yet.
+func (a *App) showChecks(ws *data.Workspace) tea.Cmd {
+	if ws == nil {
+		return nil
+	}
+	wsID := string(ws.ID())
+	if a.checks.running[wsID] {
+		return a.toast.ShowInfo("Checks are already running")
+	}
+	if _, ok := a.checks.runs[wsID]; !ok {
+		return a.planChecks(ws, "", false)
+	}
+	a.showChecksPanel(ws)
+	return nil
+}
+
+// planChecks loads ws's checks and fingerprints its tree off the UI
+// goroutine. With trustHash set, the repo's scripts are trusted first,
+// provided they still match the reviewed content.
+func (a *App) planChecks(ws *data.Workspace, trustHash string, gate bool) tea.Cmd {
+	if ws == nil || a.workspaceService == nil || a.workspaceService.scripts == nil {
+		return nil
+	}
+	scripts := a.workspaceService.scripts
+	return func() tea.Msg {
+		planned := checksPlanned{workspace: ws, gate: gate}
+		if trustHash != "" {
+			if err := scripts.TrustRepoScriptsIfHash(ws.Repo, trustHash); err != nil {
+				planned.err = err
+				return planned
+			}
+		}
+		planned.set, planned.err = scripts.Checks(ws)
+		if planned.err != nil || (gate && !planned.set.Required) {
+			return planned
+		}
+		// Without a fingerprint the checks still run; the result just
+		// cannot be reused.
+		planned.fingerprint, _ = git.TreeFingerprint(context.Background(), ws.Root)
+		return planned
+	}
+}
+
+// handleChecksPlanned runs the planned checks. A commit goes straight to the
+// commit dialog when the project does not require checks, and reuses the last
+// run when the tree has not changed since.
+func (a *App) handleChecksPlanned(msg checksPlanned) tea.Cmd {
+	ws := msg.workspace
+	if ws == nil {
+		return nil
+	}
+	required := msg.set != nil && msg.set.Required
+	if msg.gate && (!required || errors.Is(msg.err, process.ErrNoChecks)) {
+		return a.handleShowCommitWorkspaceDialog(messages.ShowCommitWorkspaceDialog{Workspace: ws})
+	}
+	var trustErr *process.ScriptsNotTrustedError
+	switch {
+	case errors.Is(msg.err, process.ErrNoChecks):
+		return a.toast.ShowWarning(`No checks: set "checks" in .amux/workspaces.json`)
+	case errors.As(msg.err, &trustErr):
+		a.showTrustAndRunDialog(ws, trustErr.ConfigHash, "run its checks", func(ws *data.Workspace, trustHash string) tea.Cmd {
+			return a.planChecks(ws, trustHash, msg.gate)
+		})
+		return nil
+	case msg.err != nil:
+		return common.ReportError(errorContext(errorServiceWorkspace, "loading checks"), msg.err, "")
+	}
+	wsID := string(ws.ID())
+	if a.checks.running[wsID] {
+		return a.toast.ShowInfo("Checks are already running")
+	}
+	if last, ok := a.checks.runs[wsID]; ok && msg.gate && msg.fingerprint != "" && last.fingerprint == msg.fingerprint {
+		return a.handleChecksFinished(checksFinished{workspace: ws, run: last, gate: true})
+	}
+	return a.runChecks(ws, msg.set, msg.fingerprint, msg.gate)
+}
+
+func (a *App) runChecks(ws *data.Workspace, set *process.CheckSet, fingerprint string, gate bool) tea.Cmd {
+	wsID := string(ws.ID())
+	if a.checks.running == nil {
+		a.checks.running = make(map[string]bool)
+	}
+	a.checks.running[wsID] = true
+	if a.dashboard != nil {
+		a.dashboard.SetChecks(wsID, &dashboard.ChecksBadge{Running: true})
+	}
+	scripts := a.workspaceService.scripts
+	run := func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), checksRunTimeout)
+		defer cancel()
+		results := scripts.RunChecks(ctx, ws.Root, set)
+		if after, err := git.TreeFingerprint(ctx, ws.Root); err != nil || after != fingerprint {
+			fingerprint = ""
+		}
+		return checksFinished{
+			workspace: ws,
+			run:       checksRun{fingerprint: fingerprint, results: results, at: time.Now()},
+			gate:      gate,
+		}
+	}
+	label := "Running checks..."
+	if gate {
+		label = "Running checks before commit..."
+	}
+	return common.SafeBatch(a.toast.ShowInfo(label), run)
+}
+
+// handleChecksFinished records a run, updates the dashboard badge, and
+// reports it. A commit proceeds only when every check passed.
+func (a *App) handleChecksFinished(msg checksFinished) tea.Cmd {
+	ws := msg.workspace
+	if ws == nil {
+		return nil
+	}
+	wsID := string(ws.ID())
+	delete(a.checks.running, wsID)
+	if a.checks.runs == nil {
+		a.checks.runs = make(map[string]checksRun)
+	}
+	a.checks.runs[wsID] = msg.run
+	failed := msg.run.failed()
+	if len(failed) == 0 {
+		a.feedAdd(feed.KindTask, wsID, "checks passed")
+	} else {
+		a.feedAdd(feed.KindTask, wsID, "checks failed: "+strings.Join(failed, ", "))
+	}
+	if a.dashboard != nil {
+		a.dashboard.SetChecks(wsID, &dashboard.ChecksBadge{Failed: failed})
+	}
+	if len(failed) == 0 {
+		if msg.gate {
+			return a.handleShowCommitWorkspaceDialog(messages.ShowCommitWorkspaceDialog{Workspace: ws})
+		}
+		return a.toast.ShowSuccess(fmt.Sprintf("Checks passed in %s", ws.Name))
+	}
+	text := fmt.Sprintf("Checks failed in %s: %s", ws.Name, strings.Join(failed, ", "))
+	toast := a.toast.ShowWarning(text)
+	if msg.gate {
+		toast = a.toast.ShowError("Commit blocked. " + text)
+	}
+	if a.activeWorkspace == ws && (a.dialog == nil || !a.dialog.Visible()) {
+		a.showChecksPanel(ws)
+	}
+	return toast
+}
+
+// showChecksPanel lists ws's last run: rerun and send-to-agent first, then
+// one entry per check that runs it again in a tab.
+func (a *App) showChecksPanel(ws *data.Workspace) {
+	run, ok := a.checks.runs[string(ws.ID())]
+	if !ok {
+		return
+	}
+	failed := run.failed()
+	message := fmt.Sprintf("%d of %d checks passed at %s.", len(run.results)-len(failed), len(run.results), run.at.Format("15:04"))
+	options := []string{checksOptionRerun}
+	if len(failed) > 0 {
+		options = append(options, checksOptionSend)
+	}
+	for _, res := range run.results {
+		options = append(options, checkLabel(res))
+	}
+	a.dialog = common.NewListDialog(DialogChecks, "Checks", message, options)
+	a.dialogWorkspace = ws
+	a.presentDialog(a.dialog)
+}
+
+func checkLabel(res process.CheckResult) string {
+	switch {
+	case res.Passed():
+		return fmt.Sprintf("✓ %s  %s", res.Name, res.Duration.Round(100*time.Millisecond))
+	case res.Err != nil:
+		return fmt.Sprintf("✗ %s  %v", res.Name, res.Err)
+	default:
+		return fmt.Sprintf("✗ %s  exit %d", res.Name, res.ExitCode)
+	}
+}
+
+// handleChecksPanelChoice acts on a checks panel selection.
+func (a *App) handleChecksPanelChoice(ws *data.Workspace, index int) tea.Cmd {
+	if ws == nil {
+		return nil
+	}
+	run, ok := a.checks.runs[string(ws.ID())]
+	if !ok || index < 0 {
+		return nil
+	}
+	failed := len(run.failed()) > 0
+	switch {
+	case index == 0:
+		return a.planChecks(ws, "", false)
+	case index == 1 && failed:
+		return a.sendToWorkspaceAgent(ws, checksFailurePrompt(run.results))
+	}
+	index--
+	if failed {
+		index--
+	}
+	if index >= len(run.results) {
+		return nil
+	}
+	res := run.results[index]
+	open := messages.RunCommand{Command: res.Command, Workspace: ws, Name: res.Name}
+	return common.SafeBatch(func() tea.Msg { return open }, a.focusPane(messages.PaneCenter))
+}
+
+// checksFailurePrompt asks an agent to fix the failed checks, with the end of
+// each one's output.
+func checksFailurePrompt(results []process.CheckResult) string {
+	var b strings.Builder
+	b.WriteString("These checks fail in this worktree. Please fix them.\n")
+	for _, res := range results {
+		if res.Passed() {
+			continue
+		}
+		lines := strings.Split(strings.TrimRight(res.Output, "\n"), "\n")
+		if len(lines) > testOutputPromptLines {
+			lines = lines[len(lines)-testOutputPromptLines:]
+		}
+		fmt.Fprintf(&b, "\n%s: `%s` exits with status %d. The end of its output:\n\n%s\n",
+			res.Name, res.Command, res.ExitCode, strings.Join(lines, "\n"))
+	}
+	return b.String()
+}
@@ -0,0 +1,105 @@
+package app
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	tea "charm.land/bubbletea/v2"
+
+	"github.com/andyrewlee/amux/internal/messages"
+	"github.com/andyrewlee/amux/internal/process"
+)
+
+func TestChecksGateCommit(t *testing.T) {
+	h := newDialogHarness(t)
+	ws := harnessWorkspace()
+	h.app.activeWorkspace = ws
+	required := &process.CheckSet{Required: true, Checks: []process.Check{{Name: "build", Command: "make"}}}
+
+	// A project that does not require checks commits straight away.
+	h.app.handleChecksPlanned(checksPlanned{workspace: ws, set: &process.CheckSet{}, gate: true})
+	if view := dialogView(t, h.app.dialog); !strings.Contains(view, "Commit changes") {
+		t.Fatalf("dialog = %q, want the commit dialog", view)
+	}
+	h.app.dialog = nil
+
+	// A failed run blocks the commit and opens the checks panel.
+	failed := checksRun{
+		fingerprint: "tree-1",
+		results: []process.CheckResult{
+			{Check: process.Check{Name: "build", Command: "make"}, ExitCode: 2, Output: "undefined: foo"},
+		},
+		at: time.Now(),
+	}
+	h.app.checks.running = map[string]bool{string(ws.ID()): true}
+	if cmd := h.app.handleChecksFinished(checksFinished{workspace: ws, run: failed, gate: true}); cmd == nil {
+		t.Fatal("expected a blocked-commit toast")
+	}
+	view := dialogView(t, h.app.dialog)
+	for _, want := range []string{"0 of 1 checks passed", checksOptionRerun, checksOptionSend, "✗ build  exit 2"} {
+		if !strings.Contains(view, want) {
+			t.Fatalf("checks panel missing %q, got %q", want, view)
+		}
+	}
+	h.app.dialog = nil
+
+	// The unchanged tree reuses the failed run instead of running again.
+	h.app.handleChecksPlanned(checksPlanned{workspace: ws, set: required, fingerprint: "tree-1", gate: true})
+	if h.app.checks.running[string(ws.ID())] {
+		t.Fatal("an unchanged tree should reuse the cached run")
+	}
+	if view := dialogView(t, h.app.dialog); strings.Contains(view, "Commit changes") {
+		t.Fatal("a cached failure should still block the commit")
+	}
+	h.app.dialog = nil
+
+	// A passing run opens the commit dialog.
+	passed := checksRun{
+		fingerprint: "tree-2",
+		results:     []process.CheckResult{{Check: process.Check{Name: "build", Command: "make"}}},
+		at:          time.Now(),
+	}
+	h.app.handleChecksFinished(checksFinished{workspace: ws, run: passed, gate: true})
+	if view := dialogView(t, h.app.dialog); !strings.Contains(view, "Commit changes") {
+		t.Fatalf("dialog = %q, want the commit dialog after checks pass", view)
+	}
+}
+
+func TestChecksPanelRunsCheckInTab(t *testing.T) {
+	h := newDialogHarness(t)
+	ws := harnessWorkspace()
+	h.app.checks.runs = map[string]checksRun{string(ws.ID()): {
+		results: []process.CheckResult{
+			{Check: process.Check{Name: "build", Command: "make"}},
+			{Check: process.Check{Name: "lint", Command: "make lint"}, ExitCode: 1},
+		},
+	}}
+
+	// Options: rerun, send, build, lint.
+	cmd := h.app.handleChecksPanelChoice(ws, 3)
+	if cmd == nil {
+		t.Fatal("expected the check to run in a tab")
+	}
+	msgs := []tea.Msg{cmd()}
+	if batch, ok := msgs[0].(tea.BatchMsg); ok {
+		msgs = msgs[:0]
+		for _, sub := range batch {
+			msgs = append(msgs, sub())
+		}
+	}
+	var run messages.RunCommand
+	for _, msg := range msgs {
+		if m, ok := msg.(messages.RunCommand); ok {
+			run = m
+		}
+	}
+	if run.Command != "make lint" || run.Name != "lint" || run.Workspace != ws {
+		t.Fatalf("run = %+v, want the lint check", run)
+	}
+
+	prompt := checksFailurePrompt(h.app.checks.runs[string(ws.ID())].results)
+	if !strings.Contains(prompt, "lint: `make lint` exits with status 1") || strings.Contains(prompt, "build:") {
+		t.Fatalf("prompt = %q, want only the failed check", prompt)
+	}
+}
@@ -0,0 +1,232 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	tea "charm.land/bubbletea/v2"
+
+	"github.com/andyrewlee/amux/internal/codeblock"
+	"github.com/andyrewlee/amux/internal/data"
+	"github.com/andyrewlee/amux/internal/git"
+	"github.com/andyrewlee/amux/internal/messages"
+	"github.com/andyrewlee/amux/internal/ui/common"
+)
+
+const (
+	codeBlockOptionCopy  = "Copy"
+	codeBlockOptionSave  = "Save to file..."
+	codeBlockOptionApply = "Apply as patch"
+	// codeBlockPreviewLines is how much of a block its action dialog shows.
+	codeBlockPreviewLines = 8
+)
+
+// codeBlockState holds the code blocks found in a terminal, newest first,
+// and the one being acted on.
+type codeBlockState struct {
+	workspace *data.Workspace
+	blocks    []codeblock.Block
+	current   codeblock.Block
+	// path is the worktree-relative file the current block is being saved
+	// to, kept while asking whether to replace it.
+	path string
+}
+
+// codeBlockSaved reports writing a code block to a file. exists is set when
+// the file was already there and nothing was written.
+type codeBlockSaved struct {
+	workspace *data.Workspace
+	path      string
+	exists    bool
+	err       error
+}
+
+// codeBlockApplied reports applying a code block to a worktree as a patch.
+type codeBlockApplied struct {
+	workspace *data.Workspace
+	err       error
+}
+
+// showCodeBlocks lists the code blocks in the focused terminal's scrollback,
+// newest first.
+func (a *App) showCodeBlocks() tea.Cmd {
+	var text string
+	var ok bool
+	switch a.focusedPane {
+	case messages.PaneCenter:
+		text, ok = a.center.ActiveTerminalText()
+	case messages.PaneSidebarTerminal:
+		text, ok = a.sidebarTerminal.ActiveTerminalText()
+	}
+	if !ok || a.activeWorkspace == nil {
+		return nil
+	}
+	found := codeblock.Extract(text)
+	if len(found) == 0 {
+		return a.toast.ShowInfo("No code blocks in this terminal")
+	}
+	blocks := make([]codeblock.Block, 0, len(found))
+	options := make([]string, 0, len(found))
+	for i := len(found) - 1; i >= 0; i-- {
+		b := found[i]
+		blocks = append(blocks, b)
+		options = append(options, codeBlockLabel(b))
+	}
+	a.codeBlocks = codeBlockState{workspace: a.activeWorkspace, blocks: blocks}
+	a.dialog = common.NewListDialog(DialogCodeBlocks, "Code Blocks",
+		"Code blocks in this terminal, newest first.", options)
+	a.presentDialog(a.dialog)
+	return nil
+}
+
+func codeBlockLabel(b codeblock.Block) string {
+	lang := b.Lang
+	if lang == "" {
+		lang = "text"
+	}
+	title := b.Title()
+	if len(title) > 60 {
+		title = title[:57] + "..."
+	}
+	lines := "1 line"
+	if n := b.Lines(); n != 1 {
+		lines = fmt.Sprintf("%d lines", n)
+	}
+	return fmt.Sprintf("%s  %s  (%s)", lang, title, lines)
+}
+
+// handleCodeBlockChoice offers what to do with the chosen block. Only a
+// block that looks like a unified diff can be applied.
+func (a *App) handleCodeBlockChoice(index int) tea.Cmd {
+	s := &a.codeBlocks
+	if index < 0 || index >= len(s.blocks) {
+		return nil
+	}
+	s.current = s.blocks[index]
+	options := []string{codeBlockOptionCopy, codeBlockOptionSave}
+	if s.current.IsPatch() {
+		options = append(options, codeBlockOptionApply)
+	}
+	a.dialog = common.NewListDialog(DialogCodeBlockAction, "Code Block", codeBlockPreview(s.current), options)
+	a.presentDialog(a.dialog)
+	return nil
+}
+
+func codeBlockPreview(b codeblock.Block) string {
+	lines := strings.Split(strings.TrimSuffix(b.Text, "\n"), "\n")
+	if len(lines) > codeBlockPreviewLines {
+		more := len(lines) - codeBlockPreviewLines
+		lines = append(lines[:codeBlockPreviewLines], fmt.Sprintf("... %d more", more))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func (a *App) handleCodeBlockAction(option string) tea.Cmd {
+	s := &a.codeBlocks
+	switch option {
+	case codeBlockOptionCopy:
+		text := s.current.Text
+		a.codeBlocks = codeBlockState{}
+		return func() tea.Msg {
+			if err := common.CopyToClipboard(text); err != nil {
+				return messages.Toast{Message: "Copy failed: " + err.Error(), Level: messages.ToastWarning}
+			}
+			return messages.Toast{Message: "Copied code block", Level: messages.ToastSuccess}
+		}
+	case codeBlockOptionSave:
+		a.dialog = common.NewInputDialog(DialogCodeBlockSave, "Save Code Block", "Path in the workspace")
+		a.presentDialog(a.dialog)
+		return nil
+	case codeBlockOptionApply:
+		ws, text := s.workspace, s.current.Text
+		a.codeBlocks = codeBlockState{}
+		if ws == nil {
+			return nil
+		}
+		return func() tea.Msg {
+			return codeBlockApplied{workspace: ws, err: git.ApplyPatch(context.Background(), ws.Root, text)}
+		}
+	}
+	return nil
+}
+
+// saveCodeBlock writes the current block to path in the workspace. An
+// existing file is only replaced once the user confirms.
+func (a *App) saveCodeBlock(path string, replace bool) tea.Cmd {
+	s := &a.codeBlocks
+	ws := s.workspace
+	if ws == nil {
+		return nil
+	}
+	path = filepath.Clean(strings.TrimSpace(path))
+	if path == "." || !filepath.IsLocal(path) {
+		return a.toast.ShowWarning("Save to a path inside the workspace")
+	}
+	s.path = path
+	text := s.current.Text
+	return func() tea.Msg {
+		return writeCodeBlock(ws, path, text, replace)
+	}
+}
+
+func writeCodeBlock(ws *data.Workspace, path, text string, replace bool) codeBlockSaved {
+	saved := codeBlockSaved{workspace: ws, path: path}
+	full := filepath.Join(ws.Root, path)
+	if err := os.MkdirAll(filepath.Dir(full), 0o755); err != nil {
+		saved.err = err
+		return saved
+	}
+	flags := os.O_WRONLY | os.O_CREATE | os.O_EXCL
+	if replace {
+		flags = os.O_WRONLY | os.O_CREATE | os.O_TRUNC
+	}
+	f, err := os.OpenFile(full, flags, 0o644)
+	if errors.Is(err, fs.ErrExist) {
+		saved.exists = true
+		return saved
+	}
+	if err != nil {
+		saved.err = err
+		return saved
+	}
+	_, err = f.WriteString(text)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	saved.err = err
+	return saved
+}
+
+// handleCodeBlockSaved reports the save, or asks before replacing a file
+// that already exists.
+func (a *App) handleCodeBlockSaved(msg codeBlockSaved) tea.Cmd {
+	if msg.workspace == nil || a.codeBlocks.workspace != msg.workspace {
+		return nil
+	}
+	if msg.exists {
+		a.dialog = common.NewConfirmDialog(DialogCodeBlockReplace, "Replace File",
+			fmt.Sprintf("%s already exists. Replace it with the code block?", msg.path))
+		a.presentDialog(a.dialog)
+		return nil
+	}
+	a.codeBlocks = codeBlockState{}
+	if msg.err != nil {
+		return common.ReportError(errorContext(errorServiceWorkspace, "saving a code block"), msg.err, "")
+	}
+	return a.toast.ShowSuccess("Saved " + msg.path)
+}
+
+func (a *App) handleCodeBlockApplied(msg codeBlockApplied) tea.Cmd {
+	if msg.workspace == nil {
+		return nil
+	}
+	if msg.err != nil {
+		return common.ReportError(errorContext(errorServiceWorkspace, "applying a code block"), msg.err, "")
+	}
+	return a.toast.ShowSuccess("Applied patch to " + msg.workspace.Name)
+}
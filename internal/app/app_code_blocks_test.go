@@ -0,0 +1,67 @@
+package app
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/andyrewlee/amux/internal/codeblock"
+	"github.com/andyrewlee/amux/internal/data"
+)
+
+func TestCodeBlockActions(t *testing.T) {
+	h := newDialogHarness(t)
+	ws := &data.Workspace{Name: "feature", Repo: "/repo", Root: t.TempDir()}
+	patch := codeblock.Block{Lang: "diff", Text: "--- a/x\n+++ b/x\n@@ -1 +1 @@\n-a\n+b\n"}
+	plain := codeblock.Block{Lang: "go", Text: "package main\n"}
+	h.app.codeBlocks = codeBlockState{workspace: ws, blocks: []codeblock.Block{plain, patch}}
+
+	// Only a diff can be applied.
+	h.app.handleCodeBlockChoice(0)
+	if view := dialogView(t, h.app.dialog); !strings.Contains(view, "package main") || strings.Contains(view, codeBlockOptionApply) {
+		t.Fatalf("plain block dialog = %q", view)
+	}
+	h.app.handleCodeBlockChoice(1)
+	if view := dialogView(t, h.app.dialog); !strings.Contains(view, codeBlockOptionApply) {
+		t.Fatalf("patch dialog = %q, want the apply option", view)
+	}
+
+	// Saving stays inside the workspace and asks before replacing a file.
+	h.app.handleCodeBlockChoice(0)
+	if cmd := h.app.saveCodeBlock("../outside.go", false); cmd != nil {
+		if _, ok := cmd().(codeBlockSaved); ok {
+			t.Fatal("a path outside the workspace should be refused")
+		}
+	}
+	path := filepath.Join(ws.Root, "cmd", "main.go")
+	saved := h.app.saveCodeBlock("cmd/main.go", false)().(codeBlockSaved)
+	h.app.handleCodeBlockSaved(saved)
+	if got, err := os.ReadFile(path); err != nil || string(got) != "package main\n" {
+		t.Fatalf("saved file = %q, %v", got, err)
+	}
+
+	h.app.codeBlocks = codeBlockState{workspace: ws, current: patch}
+	saved = h.app.saveCodeBlock("cmd/main.go", false)().(codeBlockSaved)
+	if !saved.exists {
+		t.Fatalf("saved = %+v, want the existing file reported", saved)
+	}
+	h.app.handleCodeBlockSaved(saved)
+	if view := dialogView(t, h.app.dialog); !strings.Contains(view, "cmd/main.go already exists") {
+		t.Fatalf("dialog = %q, want the replace prompt", view)
+	}
+	h.app.handleCodeBlockSaved(h.app.saveCodeBlock(h.app.codeBlocks.path, true)().(codeBlockSaved))
+	if got, _ := os.ReadFile(path); string(got) != patch.Text {
+		t.Fatalf("replaced file = %q", got)
+	}
+	if h.app.codeBlocks.workspace != nil {
+		t.Fatal("state should be cleared after saving")
+	}
+}
+
+func TestCodeBlockLabel(t *testing.T) {
+	got := codeBlockLabel(codeblock.Block{Text: "echo hi\n"})
+	if got != "text  echo hi  (1 line)" {
+		t.Fatalf("codeBlockLabel() = %q", got)
+	}
+}
@@ -0,0 +1,254 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	tea "charm.land/bubbletea/v2"
+
+	"github.com/andyrewlee/amux/internal/data"
+	"github.com/andyrewlee/amux/internal/lsp"
+	"github.com/andyrewlee/amux/internal/messages"
+	"github.com/andyrewlee/amux/internal/ui/common"
+)
+
+// codeNavTimeout bounds one language server lookup, including starting the
+// server and its first indexing of the worktree.
+const codeNavTimeout = time.Minute
+
+// maxSymbolResults bounds how many symbols the results dialog lists.
+const maxSymbolResults = 200
+
+// codeNavState holds the locations listed in the open results dialog.
+type codeNavState struct {
+	locations []lsp.Location
+}
+
+// codeNavLoaded carries the locations a symbol search or definition lookup
+// found. labels, when set, describe each location.
+type codeNavLoaded struct {
+	workspace *data.Workspace
+	what      string
+	locations []lsp.Location
+	labels    []string
+	err       error
+}
+
+// languageServers returns the app's language server manager, created on
+// first use so no server runs until code navigation is asked for.
+func (a *App) languageServers() *lsp.Manager {
+	if a.lsp == nil {
+		a.lsp = lsp.NewManager()
+	}
+	return a.lsp
+}
+
+// showSymbolSearchDialog asks for a symbol to find in the active workspace.
+func (a *App) showSymbolSearchDialog() tea.Cmd {
+	if a.activeWorkspace == nil {
+		return nil
+	}
+	a.dialog = common.NewInputDialog(DialogSymbolSearch, "Find Symbol", "Symbol name...")
+	a.dialogWorkspace = a.activeWorkspace
+	a.presentDialog(a.dialog)
+	return nil
+}
+
+// showDefinitionDialog asks for a file position whose definition to open.
+func (a *App) showDefinitionDialog() tea.Cmd {
+	if a.activeWorkspace == nil {
+		return nil
+	}
+	a.dialog = common.NewInputDialog(DialogDefinition, "Go to Definition", "path/to/file.go:line:column")
+	a.dialogWorkspace = a.activeWorkspace
+	a.presentDialog(a.dialog)
+	return nil
+}
+
+// searchSymbols asks ws's language server for symbols matching query.
+func (a *App) searchSymbols(ws *data.Workspace, query string) tea.Cmd {
+	query = strings.TrimSpace(query)
+	if ws == nil || query == "" {
+		return nil
+	}
+	manager := a.languageServers()
+	search := func() tea.Msg {
+		loaded := codeNavLoaded{workspace: ws, what: fmt.Sprintf("symbols matching %q", query)}
+		ctx, cancel := context.WithTimeout(context.Background(), codeNavTimeout)
+		defer cancel()
+		client, err := manager.Client(ctx, ws.Root)
+		if err != nil {
+			loaded.err = err
+			return loaded
+		}
+		symbols, err := client.WorkspaceSymbols(ctx, query)
+		if err != nil {
+			loaded.err = err
+			return loaded
+		}
+		if len(symbols) > maxSymbolResults {
+			symbols = symbols[:maxSymbolResults]
+		}
+		for _, s := range symbols {
+			loaded.locations = append(loaded.locations, s.Location)
+			loaded.labels = append(loaded.labels, symbolLabel(ws.Root, s))
+		}
+		return loaded
+	}
+	return common.SafeBatch(a.toast.ShowInfo("Searching symbols..."), search)
+}
+
+// findDefinition asks ws's language server where the identifier at target,
+// a "path:line[:column]" position relative to the worktree, is defined.
+func (a *App) findDefinition(ws *data.Workspace, target string) tea.Cmd {
+	if ws == nil || strings.TrimSpace(target) == "" {
+		return nil
+	}
+	path, pos, err := parsePosition(ws.Root, target)
+	if err != nil {
+		return a.toast.ShowWarning(err.Error())
+	}
+	manager := a.languageServers()
+	return func() tea.Msg {
+		loaded := codeNavLoaded{workspace: ws, what: "a definition at " + strings.TrimSpace(target)}
+		ctx, cancel := context.WithTimeout(context.Background(), codeNavTimeout)
+		defer cancel()
+		client, err := manager.Client(ctx, ws.Root)
+		if err != nil {
+			loaded.err = err
+			return loaded
+		}
+		loaded.locations, loaded.err = client.Definition(ctx, path, pos)
+		return loaded
+	}
+}
+
+// parsePosition reads "path:line[:column]" with a one-based line and column.
+func parsePosition(root, target string) (string, lsp.Position, error) {
+	parts := strings.Split(strings.TrimSpace(target), ":")
+	invalid := fmt.Errorf("expected path:line:column, got %q", target)
+	if len(parts) < 2 || len(parts) > 3 || parts[0] == "" {
+		return "", lsp.Position{}, invalid
+	}
+	line, err := strconv.Atoi(parts[1])
+	if err != nil || line < 1 {
+		return "", lsp.Position{}, invalid
+	}
+	column := 1
+	if len(parts) == 3 {
+		if column, err = strconv.Atoi(parts[2]); err != nil || column < 1 {
+			return "", lsp.Position{}, invalid
+		}
+	}
+	path := parts[0]
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(root, path)
+	}
+	return path, lsp.Position{Line: line - 1, Character: column - 1}, nil
+}
+
+// handleCodeNavLoaded opens a single result directly and lists several.
+func (a *App) handleCodeNavLoaded(msg codeNavLoaded) tea.Cmd {
+	ws := msg.workspace
+	if ws == nil {
+		return nil
+	}
+	switch {
+	case errors.Is(msg.err, lsp.ErrNoServer):
+		return a.toast.ShowWarning("No language server: install gopls or typescript-language-server")
+	case msg.err != nil:
+		return common.ReportError(errorContext(errorServiceWorkspace, "querying the language server"), msg.err, "")
+	case len(msg.locations) == 0:
+		return a.toast.ShowInfo("Found no " + msg.what)
+	case len(msg.locations) == 1:
+		return a.openLocation(ws, msg.locations[0])
+	}
+	labels := msg.labels
+	if len(labels) != len(msg.locations) {
+		labels = make([]string, len(msg.locations))
+		for i, l := range msg.locations {
+			labels[i] = locationLabel(ws.Root, l)
+		}
+	}
+	a.codeNav.locations = msg.locations
+	a.dialog = common.NewListDialog(DialogCodeNav, "Go to", fmt.Sprintf("%d %s:", len(labels), msg.what), labels)
+	a.dialogWorkspace = ws
+	a.presentDialog(a.dialog)
+	return nil
+}
+
+// handleCodeNavChoice opens the chosen location.
+func (a *App) handleCodeNavChoice(ws *data.Workspace, index int) tea.Cmd {
+	locations := a.codeNav.locations
+	a.codeNav.locations = nil
+	if ws == nil || index < 0 || index >= len(locations) {
+		return nil
+	}
+	return a.openLocation(ws, locations[index])
+}
+
+// openLocation opens a location's file in an editor tab at its line.
+func (a *App) openLocation(ws *data.Workspace, l lsp.Location) tea.Cmd {
+	path := l.Path()
+	if path == "" {
+		return a.toast.ShowWarning("Cannot open " + l.URI)
+	}
+	open := messages.OpenFileInVim{Path: path, Workspace: ws, Line: l.Range.Start.Line + 1}
+	return common.SafeBatch(func() tea.Msg { return open }, a.focusPane(messages.PaneCenter))
+}
+
+// symbolLabel describes a symbol result: its name, kind, and location.
+func symbolLabel(root string, s lsp.Symbol) string {
+	name := s.Name
+	if s.ContainerName != "" {
+		name = s.ContainerName + "." + name
+	}
+	if kind := symbolKindName(s.Kind); kind != "" {
+		name += " (" + kind + ")"
+	}
+	return name + "  " + locationLabel(root, s.Location)
+}
+
+// locationLabel formats a location as a path relative to root and a line.
+func locationLabel(root string, l lsp.Location) string {
+	path := l.Path()
+	if rel, err := filepath.Rel(root, path); err == nil && !strings.HasPrefix(rel, "..") {
+		path = rel
+	}
+	return fmt.Sprintf("%s:%d", path, l.Range.Start.Line+1)
+}
+
+// symbolKindName names the protocol's common symbol kinds.
+func symbolKindName(kind int) string {
+	switch kind {
+	case 5:
+		return "class"
+	case 6:
+		return "method"
+	case 7:
+		return "property"
+	case 8:
+		return "field"
+	case 10:
+		return "enum"
+	case 11:
+		return "interface"
+	case 12:
+		return "func"
+	case 13:
+		return "var"
+	case 14:
+		return "const"
+	case 23:
+		return "struct"
+	case 26:
+		return "type"
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,89 @@
+package app
+
+import (
+	"strings"
+	"testing"
+
+	tea "charm.land/bubbletea/v2"
+
+	"github.com/andyrewlee/amux/internal/lsp"
+	"github.com/andyrewlee/amux/internal/messages"
+)
+
+func TestCodeNavListsAndOpensLocations(t *testing.T) {
+	h := newDialogHarness(t)
+	ws := harnessWorkspace()
+	at := func(path string, line int) lsp.Location {
+		return lsp.Location{URI: "file://" + path, Range: lsp.Range{Start: lsp.Position{Line: line}}}
+	}
+	symbols := []lsp.Symbol{
+		{Name: "Add", Kind: 12, ContainerName: "calc", Location: at("/repo/primary/ws/calc/add.go", 4)},
+		{Name: "Adder", Kind: 23, Location: at("/repo/primary/ws/calc/adder.go", 0)},
+	}
+	loaded := codeNavLoaded{workspace: ws, what: `symbols matching "Add"`}
+	for _, s := range symbols {
+		loaded.locations = append(loaded.locations, s.Location)
+		loaded.labels = append(loaded.labels, symbolLabel(ws.Root, s))
+	}
+
+	h.app.handleCodeNavLoaded(loaded)
+	view := dialogView(t, h.app.dialog)
+	for _, want := range []string{"calc.Add (func)  calc/add.go:5", "Adder (struct)  calc/adder.go:1"} {
+		if !strings.Contains(view, want) {
+			t.Fatalf("results dialog missing %q, got %q", want, view)
+		}
+	}
+
+	open := findOpenFile(t, h.app.handleCodeNavChoice(ws, 0))
+	if open.Path != "/repo/primary/ws/calc/add.go" || open.Line != 5 || open.Workspace != ws {
+		t.Fatalf("open = %+v, want calc/add.go at line 5", open)
+	}
+	if h.app.codeNav.locations != nil {
+		t.Fatal("choosing a result should clear the listed locations")
+	}
+
+	// A single definition opens without a dialog.
+	h.app.dialog = nil
+	open = findOpenFile(t, h.app.handleCodeNavLoaded(codeNavLoaded{workspace: ws, locations: loaded.locations[1:]}))
+	if open.Line != 1 || h.app.dialog != nil {
+		t.Fatalf("open = %+v, dialog = %v; want adder.go opened directly", open, h.app.dialog)
+	}
+}
+
+func TestParsePosition(t *testing.T) {
+	path, pos, err := parsePosition("/repo/ws", "calc/add.go:12:7")
+	if err != nil || path != "/repo/ws/calc/add.go" || pos != (lsp.Position{Line: 11, Character: 6}) {
+		t.Fatalf("parsePosition() = %q, %+v, %v", path, pos, err)
+	}
+	if _, pos, err := parsePosition("/repo/ws", "main.go:3"); err != nil || pos != (lsp.Position{Line: 2}) {
+		t.Fatalf("parsePosition() without a column = %+v, %v", pos, err)
+	}
+	for _, bad := range []string{"main.go", "main.go:0", "main.go:x:1", ":3"} {
+		if _, _, err := parsePosition("/repo/ws", bad); err == nil {
+			t.Fatalf("parsePosition(%q) should fail", bad)
+		}
+	}
+}
+
+func findOpenFile(t *testing.T, cmd tea.Cmd) messages.OpenFileInVim {
+	t.Helper()
+	if cmd == nil {
+		t.Fatal("expected a command")
+	}
+	msgs := []tea.Msg{cmd()}
+	if batch, ok := msgs[0].(tea.BatchMsg); ok {
+		msgs = msgs[:0]
+		for _, sub := range batch {
+			if sub != nil {
+				msgs = append(msgs, sub())
+			}
+		}
+	}
+	for _, msg := range msgs {
+		if open, ok := msg.(messages.OpenFileInVim); ok {
+			return open
+		}
+	}
+	t.Fatalf("no OpenFileInVim in %+v", msgs)
+	return messages.OpenFileInVim{}
+}
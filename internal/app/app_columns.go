@@ -0,0 +1,163 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	tea "charm.land/bubbletea/v2"
+
+	"github.com/andyrewlee/amux/internal/data"
+	"github.com/andyrewlee/amux/internal/logging"
+	"github.com/andyrewlee/amux/internal/process"
+	"github.com/andyrewlee/amux/internal/statusbar"
+	"github.com/andyrewlee/amux/internal/ui/common"
+	"github.com/andyrewlee/amux/internal/ui/dashboard"
+)
+
+// columnsTickInterval is how often workspaces are checked for custom
+// dashboard columns that are due; each column reruns on its own interval.
+const columnsTickInterval = 10 * time.Second
+
+type columnsTick struct{}
+
+// columnsState caches each workspace's custom column values.
+type columnsState struct {
+	running map[string]bool
+	cells   map[string]map[string]columnCell
+}
+
+// columnCell is a column's last value in one workspace and when it ran.
+type columnCell struct {
+	dashboard.ColumnCell
+	at time.Time
+}
+
+// columnsRan carries the columns of ws's project and the values of those
+// that were due, run off the UI goroutine.
+type columnsRan struct {
+	wsID    string
+	columns []process.Column
+	ran     map[string]columnCell
+	err     error
+}
+
+func (a *App) startColumnsTicker() tea.Cmd {
+	return common.SafeTick(columnsTickInterval, func(time.Time) tea.Msg {
+		return columnsTick{}
+	})
+}
+
+// handleColumnsTick refreshes the columns of every workspace whose last
+// refresh has finished, and forgets the values of deleted workspaces.
+func (a *App) handleColumnsTick() tea.Cmd {
+	cmds := []tea.Cmd{a.startColumnsTicker()}
+	if a.workspaceService == nil || a.workspaceService.scripts == nil {
+		return common.SafeBatch(cmds...)
+	}
+	seen := make(map[string]bool)
+	a.eachWorkspace(func(ws *data.Workspace, _ *data.Project) {
+		wsID := string(ws.ID())
+		seen[wsID] = true
+		if !a.columns.running[wsID] {
+			cmds = append(cmds, a.refreshColumns(ws))
+		}
+	})
+	for wsID := range a.columns.cells {
+		if !seen[wsID] {
+			delete(a.columns.cells, wsID)
+		}
+	}
+	return common.SafeBatch(cmds...)
+}
+
+// refreshColumns loads ws's project columns and runs those due off the UI
+// goroutine. Columns from an untrusted repo do not run.
+func (a *App) refreshColumns(ws *data.Workspace) tea.Cmd {
+	wsID := string(ws.ID())
+	if a.columns.running == nil {
+		a.columns.running = make(map[string]bool)
+	}
+	a.columns.running[wsID] = true
+	last := make(map[string]time.Time)
+	for name, cell := range a.columns.cells[wsID] {
+		last[name] = cell.at
+	}
+	scripts := a.workspaceService.scripts
+	root := ws.Root
+	return func() tea.Msg {
+		msg := columnsRan{wsID: wsID, ran: make(map[string]columnCell)}
+		set, err := scripts.Columns(ws)
+		if err != nil {
+			msg.err = err
+			return msg
+		}
+		msg.columns = set.Columns
+		now := time.Now()
+		var mu sync.Mutex
+		var wg sync.WaitGroup
+		for _, column := range set.Columns {
+			if at, ok := last[column.Name]; ok && now.Sub(at) < column.RefreshInterval() {
+				continue
+			}
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				ctx, cancel := context.WithTimeout(context.Background(), column.RunTimeout())
+				defer cancel()
+				value, err := statusbar.RunCommand(ctx, column.Command, root, set.Env, dashboard.ColumnWidth)
+				if err != nil {
+					logging.Debug("Dashboard column %q in %s: %v", column.Name, root, err)
+				}
+				mu.Lock()
+				msg.ran[column.Name] = columnCell{
+					ColumnCell: dashboard.ColumnCell{Name: column.Name, Value: value, Failed: err != nil},
+					at:         now,
+				}
+				mu.Unlock()
+			}()
+		}
+		wg.Wait()
+		return msg
+	}
+}
+
+// handleColumnsRan caches the new values and shows the workspace's cells in
+// column order. A project without columns, or whose scripts are not
+// trusted, shows none.
+func (a *App) handleColumnsRan(msg columnsRan) {
+	delete(a.columns.running, msg.wsID)
+	var trustErr *process.ScriptsNotTrustedError
+	switch {
+	case errors.Is(msg.err, process.ErrNoColumns), errors.As(msg.err, &trustErr):
+		delete(a.columns.cells, msg.wsID)
+	case msg.err != nil:
+		logging.Debug("Dashboard columns for %s: %v", msg.wsID, msg.err)
+		return
+	default:
+		if a.columns.cells == nil {
+			a.columns.cells = make(map[string]map[string]columnCell)
+		}
+		prev := a.columns.cells[msg.wsID]
+		cells := make(map[string]columnCell, len(msg.columns))
+		for _, column := range msg.columns {
+			if cell, ok := msg.ran[column.Name]; ok {
+				cells[column.Name] = cell
+			} else if cell, ok := prev[column.Name]; ok {
+				cells[column.Name] = cell
+			}
+		}
+		a.columns.cells[msg.wsID] = cells
+	}
+	if a.dashboard == nil {
+		return
+	}
+	var shown []dashboard.ColumnCell
+	for _, column := range msg.columns {
+		if cell, ok := a.columns.cells[msg.wsID][column.Name]; ok {
+			shown = append(shown, cell.ColumnCell)
+		}
+	}
+	a.dashboard.SetColumns(msg.wsID, shown)
+}
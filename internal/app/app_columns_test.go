@@ -0,0 +1,49 @@
+package app
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/x/ansi"
+
+	"github.com/andyrewlee/amux/internal/data"
+	"github.com/andyrewlee/amux/internal/process"
+	"github.com/andyrewlee/amux/internal/ui/dashboard"
+)
+
+func TestDashboardColumns(t *testing.T) {
+	h := newDialogHarness(t)
+	ws := harnessWorkspace()
+	h.app.dashboard.SetProjects([]data.Project{{Name: "primary", Path: ws.Repo, Workspaces: []data.Workspace{*ws}}})
+	wsID := string(ws.ID())
+	columns := []process.Column{{Name: "version", Command: "make version"}, {Name: "ci", Command: "ci-status"}}
+	cell := func(name, value string, failed bool) columnCell {
+		return columnCell{ColumnCell: dashboard.ColumnCell{Name: name, Value: value, Failed: failed}, at: time.Now()}
+	}
+
+	h.app.columns.running = map[string]bool{wsID: true}
+	h.app.handleColumnsRan(columnsRan{wsID: wsID, columns: columns, ran: map[string]columnCell{
+		"version": cell("version", "1.4.2", false),
+		"ci":      cell("ci", "", true),
+	}})
+	if h.app.columns.running[wsID] {
+		t.Fatal("the workspace should be refreshable again")
+	}
+	if view := ansi.Strip(h.app.dashboard.View()); !strings.Contains(view, "1.4.2 ✗ ci") {
+		t.Fatalf("dashboard = %q, want the column cells", view)
+	}
+
+	// Columns that were not due keep their last value.
+	h.app.handleColumnsRan(columnsRan{wsID: wsID, columns: columns, ran: map[string]columnCell{
+		"ci": cell("ci", "passing", false),
+	}})
+	if view := ansi.Strip(h.app.dashboard.View()); !strings.Contains(view, "1.4.2 passing") {
+		t.Fatalf("dashboard = %q, want the cached value and the new one", view)
+	}
+
+	h.app.handleColumnsRan(columnsRan{wsID: wsID, err: &process.ScriptsNotTrustedError{Repo: ws.Repo}})
+	if view := ansi.Strip(h.app.dashboard.View()); strings.Contains(view, "1.4.2") || h.app.columns.cells[wsID] != nil {
+		t.Fatalf("dashboard = %q, want no cells once the repo is not trusted", view)
+	}
+}
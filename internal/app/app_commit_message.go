@@ -0,0 +1,227 @@
+package app
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	tea "charm.land/bubbletea/v2"
+
+	"github.com/andyrewlee/amux/internal/commitmsg"
+	"github.com/andyrewlee/amux/internal/data"
+	"github.com/andyrewlee/amux/internal/git"
+	"github.com/andyrewlee/amux/internal/llm"
+	"github.com/andyrewlee/amux/internal/messages"
+	"github.com/andyrewlee/amux/internal/process"
+	"github.com/andyrewlee/amux/internal/ui/common"
+	"github.com/andyrewlee/amux/internal/validation"
+)
+
+// commitDraftTimeout bounds drafting a message with the language model.
+const commitDraftTimeout = time.Minute
+
+// commitDraftState is the commit dialog a message is being drafted for, so a
+// draft arriving after the dialog closed, or after another one opened, is
+// dropped.
+type commitDraftState struct {
+	dialog    *common.Dialog
+	workspace *data.Workspace
+}
+
+// commitDrafted carries a drafted message for a workspace's commit dialog:
+// its agent's draft when it wrote one, else the language model's when that
+// feature is enabled, otherwise one from the template. conventional is set
+// when the project requires conventional commits.
+type commitDrafted struct {
+	workspace    *data.Workspace
+	message      string
+	fromAgent    bool
+	fromModel    bool
+	modelErr     error
+	conventional bool
+}
+
+// agentDraftRequested reports a workspace readied for its agent to draft a
+// commit message, with the scope its changes suggest.
+type agentDraftRequested struct {
+	workspace    *data.Workspace
+	conventional bool
+	scope        string
+	err          error
+}
+
+// handleShowCommitWorkspaceDialog shows the commit-message input dialog for a
+// workspace's changes. The message the user types is the confirmation gesture;
+// on confirm handleDialogResult stages and commits via git.CommitAll. Esc
+// cancels. Live validation mirrors the create-workspace dialog (sanitize, then
+// only flag a non-empty value); an empty message is refused by CommitAll.
+// The returned command drafts a message to prefill the dialog with.
+func (a *App) handleShowCommitWorkspaceDialog(msg messages.ShowCommitWorkspaceDialog) tea.Cmd {
+	a.dialogWorkspace = msg.Workspace
+	a.dialog = common.NewInputDialog(DialogCommitWorkspace, "Commit changes", "Commit message...")
+	a.dialog.SetInputValidate(commitMessageValidator(false))
+	a.presentDialog(a.dialog)
+	if msg.Workspace == nil {
+		return nil
+	}
+	a.commitDraft = commitDraftState{dialog: a.dialog, workspace: msg.Workspace}
+	return a.draftCommitMessage(msg.Workspace)
+}
+
+// commitMessageValidator checks a commit message as it is typed, requiring
+// the conventional format when conventional is set.
+func commitMessageValidator(conventional bool) common.InputValidateFunc {
+	return func(s string) string {
+		s = validation.SanitizeInput(s)
+		if s == "" {
+			return "" // Don't show an error for empty input; block on confirm.
+		}
+		// Defense-in-depth: the message is the argv value of -m so a leading '-'
+		// is never parsed as a flag, but keep the value shape consistent with
+		// ValidateBaseRef and warn the user before they commit.
+		if strings.HasPrefix(s, "-") {
+			return "commit message cannot start with '-'"
+		}
+		if conventional {
+			if err := commitmsg.Validate(s); err != nil {
+				return err.Error()
+			}
+		}
+		return ""
+	}
+}
+
+// conventionalCommits reports whether ws's project requires conventional
+// commit messages, by "conventional-commits" in .amux/workspaces.json or a
+// commitlint config. It reads files, so call it off the UI goroutine.
+func conventionalCommits(scripts *process.ScriptRunner, ws *data.Workspace) bool {
+	if scripts != nil {
+		if cfg, err := scripts.LoadConfig(ws.Repo); err == nil && cfg.ConventionalCommits {
+			return true
+		}
+	}
+	return commitmsg.HasCommitlint(ws.Root)
+}
+
+// scriptRunner returns the repo script runner, or nil before services start.
+func (a *App) scriptRunner() *process.ScriptRunner {
+	if a.workspaceService == nil {
+		return nil
+	}
+	return a.workspaceService.scripts
+}
+
+// draftCommitMessage drafts a message for ws's changes off the UI goroutine.
+// A draft that can't be read or made leaves the dialog empty.
+func (a *App) draftCommitMessage(ws *data.Workspace) tea.Cmd {
+	scripts, model := a.scriptRunner(), a.llm
+	return func() tea.Msg {
+		drafted := commitDrafted{workspace: ws, conventional: conventionalCommits(scripts, ws)}
+		if message, err := commitmsg.AgentDraft(ws.Root); err == nil && message != "" {
+			drafted.message, drafted.fromAgent = message, true
+			return drafted
+		}
+		status, err := git.GetStatusFast(ws.Root)
+		if err != nil {
+			return drafted
+		}
+		drafted.message = commitmsg.Draft(status.AllChanges())
+		if model.Enabled(llm.FeatureCommitMessage) {
+			if message, err := modelCommitDraft(model, ws.Root, status.AllChanges(), drafted.conventional); err != nil {
+				drafted.modelErr = err
+			} else if message != "" {
+				drafted.message, drafted.fromModel = message, true
+			}
+		}
+		return drafted
+	}
+}
+
+// modelCommitDraft asks the language model for a message for changes, given
+// the worktree's diff against HEAD and the names of its new files.
+func modelCommitDraft(model *llm.Client, root string, changes []git.Change, conventional bool) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), commitDraftTimeout)
+	defer cancel()
+	var paths, untracked []string
+	for _, c := range changes {
+		paths = append(paths, c.Path)
+		if c.Kind == git.ChangeUntracked {
+			untracked = append(untracked, c.Path)
+		}
+	}
+	// A repository without commits has no HEAD to diff against; its files
+	// are all untracked.
+	diff, _ := git.RunGitCtx(ctx, root, "diff", "HEAD")
+	reply, err := model.Complete(ctx, llm.Request{
+		Feature: llm.FeatureCommitMessage,
+		System:  commitmsg.ModelInstructions(conventional, commitmsg.Scope(paths)),
+		Prompt:  commitmsg.ModelPrompt(diff, untracked),
+	})
+	if err != nil {
+		return "", err
+	}
+	return commitmsg.ModelDraft(reply), nil
+}
+
+// handleCommitDrafted enforces the project's message format in the commit
+// dialog it was drafted for, and prefills the draft unless something was
+// typed already.
+func (a *App) handleCommitDrafted(msg commitDrafted) tea.Cmd {
+	d := a.commitDraft
+	if d.dialog == nil || a.dialog != d.dialog || !d.dialog.Visible() || d.workspace != msg.workspace {
+		return nil
+	}
+	if msg.conventional {
+		d.dialog.SetInputValidate(commitMessageValidator(true))
+	}
+	if d.dialog.InputValue() != "" || msg.message == "" {
+		return nil
+	}
+	d.dialog.SetInputValue(msg.message)
+	switch {
+	case msg.fromAgent:
+		return a.toast.ShowInfo("Prefilled with the agent's draft")
+	case msg.fromModel:
+		return a.toast.ShowInfo("Prefilled with the model's draft")
+	case msg.modelErr != nil:
+		return a.toast.ShowWarning("The model couldn't draft a message: " + msg.modelErr.Error())
+	}
+	return nil
+}
+
+// requestAgentCommitDraft asks ws's agent to draft a commit message for its
+// changes, to prefill the commit dialog with the next time it opens.
+func (a *App) requestAgentCommitDraft(ws *data.Workspace) tea.Cmd {
+	if ws == nil {
+		return nil
+	}
+	if a.workspaceAgentTab(ws) < 0 {
+		return a.toast.ShowWarning("No agent tab in this workspace")
+	}
+	scripts := a.scriptRunner()
+	return func() tea.Msg {
+		req := agentDraftRequested{workspace: ws, conventional: conventionalCommits(scripts, ws)}
+		if req.err = commitmsg.PrepareAgentDraft(ws.Root); req.err != nil {
+			return req
+		}
+		if status, err := git.GetStatusFast(ws.Root); err == nil {
+			var paths []string
+			for _, c := range status.AllChanges() {
+				paths = append(paths, c.Path)
+			}
+			req.scope = commitmsg.Scope(paths)
+		}
+		return req
+	}
+}
+
+// handleAgentDraftRequested pastes the drafting request into the agent tab.
+func (a *App) handleAgentDraftRequested(msg agentDraftRequested) tea.Cmd {
+	if msg.err != nil {
+		return common.ReportError(errorContext(errorServiceWorkspace, "preparing a commit message draft"), msg.err, "")
+	}
+	return common.SafeBatch(
+		a.sendToWorkspaceAgent(msg.workspace, commitmsg.AgentPrompt(msg.conventional, msg.scope)),
+		a.toast.ShowInfo("Once the agent has saved its draft, commit from the sidebar (c) to use it"),
+	)
+}
@@ -0,0 +1,103 @@
+package app
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	tea "charm.land/bubbletea/v2"
+
+	"github.com/andyrewlee/amux/internal/commitmsg"
+	"github.com/andyrewlee/amux/internal/data"
+	"github.com/andyrewlee/amux/internal/llm"
+	"github.com/andyrewlee/amux/internal/messages"
+	"github.com/andyrewlee/amux/internal/testutil"
+)
+
+func TestCommitDialogPrefillsAgentDraft(t *testing.T) {
+	h := newDialogHarness(t)
+	root := t.TempDir()
+	ws := &data.Workspace{Name: "feature", Repo: root, Root: root}
+	if err := commitmsg.PrepareAgentDraft(root); err != nil {
+		t.Fatal(err)
+	}
+	for name, content := range map[string]string{
+		commitmsg.DraftPath:    "feat(app): draft commit messages\n",
+		"commitlint.config.js": "module.exports = {}\n",
+	} {
+		if err := os.WriteFile(filepath.Join(root, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cmd := h.app.handleShowCommitWorkspaceDialog(messages.ShowCommitWorkspaceDialog{Workspace: ws})
+	if cmd == nil {
+		t.Fatal("expected a message to be drafted")
+	}
+	drafted, ok := cmd().(commitDrafted)
+	if !ok || !drafted.fromAgent || !drafted.conventional || drafted.message != "feat(app): draft commit messages" {
+		t.Fatalf("drafted = %+v, want the agent's conventional draft", drafted)
+	}
+	h.app.handleCommitDrafted(drafted)
+	if got := h.app.dialog.InputValue(); got != drafted.message {
+		t.Fatalf("commit dialog holds %q, want the draft", got)
+	}
+
+	// The project uses commitlint, so a message out of the format can't be
+	// confirmed.
+	h.app.dialog.SetInputValue("")
+	for _, r := range "wip" {
+		h.app.dialog, _ = h.app.dialog.Update(tea.KeyPressMsg{Code: r, Text: string(r)})
+	}
+	if view := dialogView(t, h.app.dialog); !strings.Contains(view, "not a conventional commit") {
+		t.Fatalf("dialog = %q, want the format error", view)
+	}
+	if _, cmd := h.app.dialog.Update(tea.KeyPressMsg{Code: tea.KeyEnter}); cmd != nil {
+		t.Fatal("expected Enter to be blocked for a non-conventional message")
+	}
+}
+
+func TestCommitDraftDroppedForAnotherDialog(t *testing.T) {
+	h := newDialogHarness(t)
+	ws := &data.Workspace{Name: "feature", Repo: "/repo", Root: "/repo/feature"}
+	h.app.handleShowCommitWorkspaceDialog(messages.ShowCommitWorkspaceDialog{Workspace: ws})
+	for _, r := range "fix typo" {
+		h.app.dialog, _ = h.app.dialog.Update(tea.KeyPressMsg{Code: r, Text: string(r)})
+	}
+	h.app.handleCommitDrafted(commitDrafted{workspace: ws, message: "fix(app): update 2 files"})
+	if got := h.app.dialog.InputValue(); got != "fix typo" {
+		t.Fatalf("commit dialog holds %q, want what was typed kept", got)
+	}
+
+	h.app.handleShowCommitWorkspaceDialog(messages.ShowCommitWorkspaceDialog{Workspace: ws})
+	h.app.handleCommitDrafted(commitDrafted{workspace: &data.Workspace{Name: "other"}, message: "docs: x"})
+	if got := h.app.dialog.InputValue(); got != "" {
+		t.Fatalf("commit dialog holds %q, want another workspace's draft dropped", got)
+	}
+}
+
+func TestCommitDialogPrefillsModelDraft(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"choices":[{"message":{"content":"\"fix(app): handle missing notes\""}}],"usage":{"prompt_tokens":40,"completion_tokens":8}}`))
+	}))
+	t.Cleanup(srv.Close)
+	h := newDialogHarness(t)
+	h.app.llm = llm.New(llm.Config{Provider: llm.Ollama, Endpoint: srv.URL, Model: "llama3", Features: map[llm.Feature]bool{llm.FeatureCommitMessage: true}}, nil)
+	repo := testutil.InitRepo(t)
+	if err := os.WriteFile(filepath.Join(repo, "notes.txt"), []byte("notes\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	ws := &data.Workspace{Name: "feature", Repo: repo, Root: repo}
+
+	drafted, ok := h.app.handleShowCommitWorkspaceDialog(messages.ShowCommitWorkspaceDialog{Workspace: ws})().(commitDrafted)
+	if !ok || !drafted.fromModel || drafted.message != "fix(app): handle missing notes" {
+		t.Fatalf("drafted = %+v, want the model's draft", drafted)
+	}
+	h.app.handleCommitDrafted(drafted)
+	if got := h.app.dialog.InputValue(); got != drafted.message {
+		t.Fatalf("commit dialog holds %q, want the model's draft", got)
+	}
+}
@@ -0,0 +1,170 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	tea "charm.land/bubbletea/v2"
+
+	"github.com/andyrewlee/amux/internal/data"
+	"github.com/andyrewlee/amux/internal/git"
+	"github.com/andyrewlee/amux/internal/messages"
+	"github.com/andyrewlee/amux/internal/ui/common"
+)
+
+// compareState holds the worktrees offered for comparison and the open
+// comparison's result.
+type compareState struct {
+	candidates []*data.Workspace
+	result     *git.Comparison
+}
+
+// comparisonLoaded carries the files that differ between two worktrees.
+type comparisonLoaded struct {
+	workspace *data.Workspace
+	other     *data.Workspace
+	result    *git.Comparison
+	err       error
+}
+
+// comparisonTaken reports copying changes from a comparison's other
+// worktree.
+type comparisonTaken struct {
+	workspace *data.Workspace
+	path      string
+	hunk      bool
+	err       error
+}
+
+// showCompareDialog asks which sibling worktree to compare ws with.
+func (a *App) showCompareDialog(ws *data.Workspace, project *data.Project) tea.Cmd {
+	if ws == nil || project == nil {
+		return nil
+	}
+	var candidates []*data.Workspace
+	var labels []string
+	for i := range project.Workspaces {
+		other := &project.Workspaces[i]
+		if other.Root == ws.Root {
+			continue
+		}
+		candidates = append(candidates, other)
+		labels = append(labels, fmt.Sprintf("%s  (%s)", other.Name, other.Branch))
+	}
+	if len(candidates) == 0 {
+		return a.toast.ShowInfo("No other workspace in " + project.Name + " to compare with")
+	}
+	a.compare.candidates = candidates
+	a.dialog = common.NewListDialog(DialogCompareWith, "Compare Worktrees", "Compare "+ws.Name+" with:", labels)
+	a.dialogWorkspace = ws
+	a.presentDialog(a.dialog)
+	return nil
+}
+
+// handleCompareWithChoice compares ws with the chosen worktree.
+func (a *App) handleCompareWithChoice(ws *data.Workspace, index int) tea.Cmd {
+	candidates := a.compare.candidates
+	a.compare.candidates = nil
+	if ws == nil || index < 0 || index >= len(candidates) {
+		return nil
+	}
+	other := candidates[index]
+	compare := func() tea.Msg {
+		result, err := git.CompareWorktrees(context.Background(), ws.Root, other.Root)
+		return comparisonLoaded{workspace: ws, other: other, result: result, err: err}
+	}
+	return common.SafeBatch(a.toast.ShowInfo("Comparing "+ws.Name+" with "+other.Name+"..."), compare)
+}
+
+// handleComparisonLoaded lists the files that differ.
+func (a *App) handleComparisonLoaded(msg comparisonLoaded) tea.Cmd {
+	switch {
+	case msg.workspace == nil || msg.other == nil:
+		return nil
+	case msg.err != nil:
+		return common.ReportError(errorContext(errorServiceWorkspace, "comparing worktrees"), msg.err, "")
+	case len(msg.result.Files) == 0:
+		return a.toast.ShowInfo(msg.workspace.Name + " and " + msg.other.Name + " have the same content")
+	}
+	a.compare.result = msg.result
+	title := "Compare " + msg.workspace.Name + " → " + msg.other.Name
+	a.dialog = common.NewListDialog(DialogComparison, title, comparisonSummary(msg.other.Name, msg.result.Files), comparisonLabels(msg.result.Files))
+	a.dialogWorkspace = msg.workspace
+	a.presentDialog(a.dialog)
+	return nil
+}
+
+// handleComparisonChoice opens the chosen file's differences in a diff tab.
+func (a *App) handleComparisonChoice(ws *data.Workspace, index int) tea.Cmd {
+	result := a.compare.result
+	a.compare.result = nil
+	if ws == nil || result == nil || index < 0 || index >= len(result.Files) {
+		return nil
+	}
+	open := messages.OpenDiff{
+		Change:    &git.Change{Path: result.Files[index].Path, Kind: git.ChangeModified},
+		Workspace: ws,
+		Compare:   result,
+	}
+	return func() tea.Msg { return open }
+}
+
+// comparisonSummary counts the differing files and lines; added lines are
+// the other worktree's.
+func comparisonSummary(other string, files []git.ComparedFile) string {
+	added, deleted := 0, 0
+	for _, f := range files {
+		added += f.Added
+		deleted += f.Deleted
+	}
+	return fmt.Sprintf("%d files differ, +%d -%d. Added lines are %s's.", len(files), added, deleted, other)
+}
+
+// comparisonLabels describes each differing file by its line counts.
+func comparisonLabels(files []git.ComparedFile) []string {
+	labels := make([]string, len(files))
+	for i, f := range files {
+		if f.Binary {
+			labels[i] = f.Path + "  binary"
+			continue
+		}
+		labels[i] = fmt.Sprintf("%s  +%d -%d", f.Path, f.Added, f.Deleted)
+	}
+	return labels
+}
+
+// handleTakeFromComparison copies a hunk or file from the compared worktree
+// into the workspace, off the UI goroutine.
+func (a *App) handleTakeFromComparison(msg messages.TakeFromComparison) tea.Cmd {
+	if msg.Workspace == nil || msg.Compare == nil || msg.Path == "" {
+		return nil
+	}
+	return func() tea.Msg {
+		taken := comparisonTaken{workspace: msg.Workspace, path: msg.Path, hunk: msg.Patch != ""}
+		if taken.hunk {
+			taken.err = git.TakeHunk(context.Background(), msg.Compare, msg.Path, msg.Patch)
+		} else {
+			taken.err = git.TakeFile(context.Background(), msg.Compare, msg.Path)
+		}
+		return taken
+	}
+}
+
+// handleComparisonTaken reports the copy. A conflict means the workspace's
+// file changed after the comparison, and nothing was written.
+func (a *App) handleComparisonTaken(msg comparisonTaken) tea.Cmd {
+	what := msg.path
+	if msg.hunk {
+		what = "the hunk of " + msg.path
+	}
+	switch {
+	case msg.workspace == nil:
+		return nil
+	case errors.Is(msg.err, git.ErrTakeConflict):
+		return a.toast.ShowWarning("Could not take " + what + ": it changed in " + msg.workspace.Name + " since the comparison. Compare again.")
+	case msg.err != nil:
+		return common.ReportError(errorContext(errorServiceWorkspace, "taking changes from a comparison"), msg.err, "")
+	}
+	return a.toast.ShowSuccess("Took " + what + " into " + msg.workspace.Name)
+}
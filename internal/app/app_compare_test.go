@@ -0,0 +1,75 @@
+package app
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/andyrewlee/amux/internal/data"
+	"github.com/andyrewlee/amux/internal/git"
+	"github.com/andyrewlee/amux/internal/messages"
+)
+
+func TestCompareWorktreesFlow(t *testing.T) {
+	h := newDialogHarness(t)
+	ws := harnessWorkspace()
+	project := &data.Project{Name: "primary", Path: ws.Repo, Workspaces: []data.Workspace{
+		*ws,
+		{Name: "attempt-b", Branch: "attempt-b", Repo: ws.Repo, Root: "/repo/primary/attempt-b"},
+	}}
+
+	h.app.showCompareDialog(ws, project)
+	view := dialogView(t, h.app.dialog)
+	if !strings.Contains(view, "attempt-b  (attempt-b)") || strings.Contains(view, "primary  (") {
+		t.Fatalf("dialog = %q, want only the sibling worktree", view)
+	}
+	if cmd := h.app.handleCompareWithChoice(ws, 0); cmd == nil {
+		t.Fatal("expected the comparison to start")
+	}
+
+	result := &git.Comparison{Files: []git.ComparedFile{
+		{Path: "calc.go", Added: 4, Deleted: 1},
+		{Path: "logo.png", Binary: true},
+	}}
+	other := &project.Workspaces[1]
+	h.app.handleComparisonLoaded(comparisonLoaded{workspace: ws, other: other, result: result})
+	view = dialogView(t, h.app.dialog)
+	for _, want := range []string{"2 files differ, +4 -1", "calc.go  +4 -1", "logo.png  binary"} {
+		if !strings.Contains(view, want) {
+			t.Fatalf("comparison dialog missing %q, got %q", want, view)
+		}
+	}
+
+	cmd := h.app.handleComparisonChoice(ws, 0)
+	if cmd == nil {
+		t.Fatal("expected the file's diff to open")
+	}
+	open, ok := cmd().(messages.OpenDiff)
+	if !ok || open.Change.Path != "calc.go" || open.Compare != result || open.Workspace != ws {
+		t.Fatalf("open = %+v, want calc.go compared", open)
+	}
+
+	// Identical worktrees need no dialog.
+	h.app.dialog = nil
+	h.app.handleComparisonLoaded(comparisonLoaded{workspace: ws, other: other, result: &git.Comparison{}})
+	if h.app.dialog != nil {
+		t.Fatal("identical worktrees should only show a toast")
+	}
+}
+
+func TestComparisonTakenToasts(t *testing.T) {
+	h := newDialogHarness(t)
+	ws := harnessWorkspace()
+	conflict := fmt.Errorf("calc.go %w: patch failed", git.ErrTakeConflict)
+	for _, msg := range []comparisonTaken{
+		{workspace: ws, path: "calc.go", hunk: true},
+		{workspace: ws, path: "calc.go", err: conflict},
+	} {
+		if cmd := h.app.handleComparisonTaken(msg); cmd == nil {
+			t.Fatalf("%+v: expected a toast", msg)
+		}
+	}
+	if cmd := h.app.handleTakeFromComparison(messages.TakeFromComparison{Workspace: ws, Path: "calc.go"}); cmd != nil {
+		t.Fatal("a take without a comparison should do nothing")
+	}
+}
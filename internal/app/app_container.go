@@ -0,0 +1,230 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	tea "charm.land/bubbletea/v2"
+
+	"github.com/andyrewlee/amux/internal/container"
+	"github.com/andyrewlee/amux/internal/data"
+	"github.com/andyrewlee/amux/internal/logging"
+	"github.com/andyrewlee/amux/internal/process"
+	"github.com/andyrewlee/amux/internal/ui/common"
+)
+
+// containerStartTimeout bounds building the image, starting the container,
+// and its post-create command.
+const containerStartTimeout = 20 * time.Minute
+
+// containerStopTimeout bounds removing a container.
+const containerStopTimeout = time.Minute
+
+// Seams for tests, which have no container runtime.
+var (
+	containerDetect = container.Detect
+	startContainer  = container.Start
+	stopContainer   = container.Stop
+)
+
+// containerState is the container start the confirm dialog is asking about.
+type containerState struct {
+	spec *container.Spec
+	opts container.Options
+	kind container.Kind
+}
+
+// containerSpecLoaded carries a workspace's container spec, read off the UI
+// goroutine.
+type containerSpecLoaded struct {
+	workspace *data.Workspace
+	spec      *container.Spec
+	opts      container.Options
+	kind      container.Kind
+	err       error
+}
+
+// containerStarted reports a workspace's container coming up.
+type containerStarted struct {
+	workspace *data.Workspace
+	ref       *data.Container
+	err       error
+}
+
+// containerStopped reports a workspace's container being removed.
+type containerStopped struct {
+	workspace *data.Workspace
+	ref       *data.Container
+	err       error
+}
+
+// toggleContainer offers to stop ws's container when it has one up, or reads
+// its spec to offer starting one.
+func (a *App) toggleContainer(ws *data.Workspace) tea.Cmd {
+	if ws.Container != nil {
+		a.dialog = common.NewConfirmDialog(DialogContainerStop, "Stop container",
+			fmt.Sprintf("Remove %s? Tabs running in it exit, and new tabs in %s run on the host.", ws.Container.Name, ws.Name))
+		a.dialogWorkspace = ws
+		a.presentDialog(a.dialog)
+		return nil
+	}
+	return a.loadContainerSpec(ws, "")
+}
+
+// loadContainerSpec reads ws's container spec. With trustHash set, the repo's
+// scripts are trusted first, provided they still match the reviewed content.
+func (a *App) loadContainerSpec(ws *data.Workspace, trustHash string) tea.Cmd {
+	scripts := a.scriptRunner()
+	if ws == nil || scripts == nil {
+		return nil
+	}
+	return func() tea.Msg {
+		msg := containerSpecLoaded{workspace: ws, kind: containerDetect()}
+		if trustHash != "" {
+			if msg.err = scripts.TrustRepoScriptsIfHash(ws.Repo, trustHash); msg.err != nil {
+				return msg
+			}
+		}
+		if msg.spec, msg.err = scripts.ContainerSpec(ws); msg.err == nil {
+			msg.opts = scripts.ContainerOptions(ws, msg.spec)
+		}
+		return msg
+	}
+}
+
+// handleContainerSpecLoaded asks before starting the container, showing what
+// it runs, since the spec comes from the repository.
+func (a *App) handleContainerSpecLoaded(msg containerSpecLoaded) tea.Cmd {
+	var trustErr *process.ScriptsNotTrustedError
+	switch {
+	case errors.Is(msg.err, container.ErrNoSpec):
+		return a.toast.ShowInfo("No devcontainer.json, and no container in .amux/workspaces.json")
+	case errors.As(msg.err, &trustErr):
+		a.showTrustAndRunDialog(msg.workspace, trustErr.ConfigHash, "start its container", a.loadContainerSpec)
+		return nil
+	case msg.err != nil:
+		return common.ReportError(errorContext(errorServiceWorkspace, "reading the container spec"), msg.err, "")
+	case msg.kind == container.None:
+		return a.toast.ShowWarning(container.ErrUnavailable.Error())
+	}
+	a.container = containerState{spec: msg.spec, opts: msg.opts, kind: msg.kind}
+	a.dialog = common.NewConfirmDialog(DialogContainerStart, "Start container", containerSummary(msg.spec, msg.opts))
+	a.dialogWorkspace = msg.workspace
+	a.presentDialog(a.dialog)
+	return nil
+}
+
+// containerSummary describes what starting a container runs and maps.
+func containerSummary(spec *container.Spec, opts container.Options) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "From %s: ", spec.Source)
+	if spec.Dockerfile != "" {
+		fmt.Fprintf(&b, "build %s", spec.Dockerfile)
+	} else {
+		fmt.Fprintf(&b, "run %s", spec.Image)
+	}
+	switch {
+	case opts.NoNetwork:
+		b.WriteString(", without a network")
+	case len(spec.Ports) > 0:
+		ports := make([]string, len(spec.Ports))
+		for i, p := range spec.Ports {
+			ports[i] = fmt.Sprintf("%d→%d", opts.PortStart+i, p)
+		}
+		fmt.Fprintf(&b, ", ports %s", strings.Join(ports, ", "))
+	case opts.PortStart > 0:
+		fmt.Fprintf(&b, ", ports %d-%d", opts.PortStart, opts.PortEnd)
+	}
+	if spec.PostCreate != "" {
+		fmt.Fprintf(&b, ", then run: %s", spec.PostCreate)
+	}
+	b.WriteString(". New agent and terminal tabs will run in it.")
+	return b.String()
+}
+
+// handleContainerDialog handles the container start and stop dialogs,
+// reporting whether result was one of them.
+func (a *App) handleContainerDialog(result common.DialogResult, ws *data.Workspace) (tea.Cmd, bool) {
+	if result.ID != DialogContainerStart && result.ID != DialogContainerStop {
+		return nil, false
+	}
+	state := a.container
+	a.container = containerState{}
+	if !result.Confirmed || ws == nil {
+		return nil, true
+	}
+	if result.ID == DialogContainerStop {
+		return a.removeContainer(ws), true
+	}
+	if state.spec == nil {
+		return nil, true
+	}
+	return common.SafeBatch(
+		a.toast.ShowInfo("Starting the container for "+ws.Name+"..."),
+		func() tea.Msg {
+			ctx, cancel := context.WithTimeout(context.Background(), containerStartTimeout)
+			defer cancel()
+			ref, err := startContainer(ctx, state.kind, state.spec, state.opts)
+			return containerStarted{workspace: ws, ref: ref, err: err}
+		},
+	), true
+}
+
+// handleContainerStarted records the container so new tabs run in it.
+func (a *App) handleContainerStarted(msg containerStarted) tea.Cmd {
+	if msg.err != nil {
+		return common.ReportError(errorContext(errorServiceWorkspace, "starting the container"), msg.err, "")
+	}
+	msg.workspace.Container = msg.ref
+	info := "Container up: new agent and terminal tabs in " + msg.workspace.Name + " run in it"
+	if len(msg.ref.Ports) > 0 {
+		info += " (ports " + strings.Join(msg.ref.Ports, ", ") + ")"
+	}
+	return common.SafeBatch(a.persistWorkspaceTabs(string(msg.workspace.ID())), a.toast.ShowSuccess(info))
+}
+
+// removeContainer removes ws's container off the UI goroutine.
+func (a *App) removeContainer(ws *data.Workspace) tea.Cmd {
+	ref := ws.Container
+	if ref == nil {
+		return nil
+	}
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), containerStopTimeout)
+		defer cancel()
+		return containerStopped{workspace: ws, ref: ref, err: stopContainer(ctx, ref)}
+	}
+}
+
+// discardContainer removes the container of a deleted workspace, logging a
+// failure since there is nothing left to report it on.
+func discardContainer(ws *data.Workspace) tea.Cmd {
+	ref := ws.Container
+	if ref == nil {
+		return nil
+	}
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), containerStopTimeout)
+		defer cancel()
+		if err := stopContainer(ctx, ref); err != nil {
+			logging.Warn("removing container of deleted workspace %s: %v", ws.Name, err)
+		}
+		return nil
+	}
+}
+
+// handleContainerStopped forgets the container, so new tabs run on the host.
+func (a *App) handleContainerStopped(msg containerStopped) tea.Cmd {
+	if msg.err != nil {
+		return common.ReportError(errorContext(errorServiceWorkspace, "stopping the container"), msg.err, "")
+	}
+	if msg.workspace.Container != msg.ref {
+		return nil
+	}
+	msg.workspace.Container = nil
+	return common.SafeBatch(a.persistWorkspaceTabs(string(msg.workspace.ID())),
+		a.toast.ShowInfo("Container stopped; new tabs in "+msg.workspace.Name+" run on the host"))
+}
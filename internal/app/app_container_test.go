@@ -0,0 +1,66 @@
+package app
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	tea "charm.land/bubbletea/v2"
+
+	"github.com/andyrewlee/amux/internal/config"
+	"github.com/andyrewlee/amux/internal/container"
+	"github.com/andyrewlee/amux/internal/data"
+	"github.com/andyrewlee/amux/internal/process"
+	"github.com/andyrewlee/amux/internal/ui/common"
+)
+
+func TestContainerStartAndStop(t *testing.T) {
+	app, ws := newNotifyTestApp(config.UISettings{})
+	ws.Repo, ws.Root = t.TempDir(), t.TempDir()
+	app.toast = common.NewToastModel()
+	app.workspaceService = newWorkspaceService(nil, nil, process.NewScriptRunner(6200, 10), "")
+	if err := os.WriteFile(filepath.Join(ws.Root, ".devcontainer.json"), []byte(`{"image": "node:22", "forwardPorts": [3000]}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	oldDetect, oldStart, oldStop := containerDetect, startContainer, stopContainer
+	t.Cleanup(func() { containerDetect, startContainer, stopContainer = oldDetect, oldStart, oldStop })
+	containerDetect = func() container.Kind { return container.Docker }
+	var started container.Options
+	startContainer = func(_ context.Context, kind container.Kind, spec *container.Spec, opts container.Options) (*data.Container, error) {
+		started = opts
+		return &data.Container{Runtime: string(kind), Name: opts.Name, Workdir: container.DefaultWorkdir, Ports: []string{"6200:3000"}}, nil
+	}
+	stopped := 0
+	stopContainer = func(context.Context, *data.Container) error { stopped++; return nil }
+
+	app.handleContainerSpecLoaded(app.toggleContainer(ws)().(containerSpecLoaded))
+	app.dialog.SetSize(160, 40)
+	if view := dialogView(t, app.dialog); !strings.Contains(view, "run node:22, ports 6200→3000") {
+		t.Fatalf("the start dialog should say what it runs:\n%s", view)
+	}
+	cmd := app.handleDialogResult(common.DialogResult{ID: DialogContainerStart, Confirmed: true})
+	msgs := []tea.Msg{cmd()}
+	if batch, ok := msgs[0].(tea.BatchMsg); ok {
+		msgs = msgs[:0]
+		for _, sub := range batch {
+			msgs = append(msgs, sub())
+		}
+	}
+	for _, msg := range msgs {
+		if msg, ok := msg.(containerStarted); ok {
+			app.handleContainerStarted(msg)
+		}
+	}
+	if ws.Container == nil || ws.Container.Name != container.Name(ws.ID()) || started.PortStart != 6200 {
+		t.Fatalf("Container = %+v, started with %+v", ws.Container, started)
+	}
+
+	app.toggleContainer(ws)
+	cmd = app.handleDialogResult(common.DialogResult{ID: DialogContainerStop, Confirmed: true})
+	app.handleContainerStopped(cmd().(containerStopped))
+	if ws.Container != nil || stopped != 1 {
+		t.Fatalf("Container = %+v after %d stops", ws.Container, stopped)
+	}
+}
@@ -0,0 +1,40 @@
+package app
+
+import (
+	"fmt"
+	"strings"
+
+	tea "charm.land/bubbletea/v2"
+
+	"github.com/andyrewlee/amux/internal/messages"
+	"github.com/andyrewlee/amux/internal/ui/common"
+)
+
+// copyLastOutput copies what the focused terminal printed since its last
+// command started (OSC 133 prompt marks) or since Enter was last pressed in
+// it, without going through copy mode.
+func (a *App) copyLastOutput() tea.Cmd {
+	var text string
+	var ok bool
+	switch a.focusedPane {
+	case messages.PaneCenter:
+		text, ok = a.center.ActiveLastOutput()
+	case messages.PaneSidebarTerminal:
+		text, ok = a.sidebarTerminal.ActiveLastOutput()
+	default:
+		return nil
+	}
+	if !ok {
+		return a.toast.ShowInfo("No output to copy yet: run a command in this terminal first")
+	}
+	if strings.TrimSpace(text) == "" {
+		return a.toast.ShowInfo("The last command printed nothing")
+	}
+	lines := strings.Count(text, "\n") + 1
+	return func() tea.Msg {
+		if err := common.CopyToClipboard(text); err != nil {
+			return messages.Toast{Message: "Copy failed: " + err.Error(), Level: messages.ToastWarning}
+		}
+		return messages.Toast{Message: fmt.Sprintf("Copied last output (%d lines)", lines), Level: messages.ToastSuccess}
+	}
+}
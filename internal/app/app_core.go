@@ -8,9 +8,14 @@ import (
 	tea "charm.land/bubbletea/v2"
 	"charm.land/lipgloss/v2"
 
+	"github.com/andyrewlee/amux/internal/audit"
 	"github.com/andyrewlee/amux/internal/config"
 	"github.com/andyrewlee/amux/internal/data"
 	"github.com/andyrewlee/amux/internal/git"
+	"github.com/andyrewlee/amux/internal/inputhistory"
+	"github.com/andyrewlee/amux/internal/limits"
+	"github.com/andyrewlee/amux/internal/llm"
+	"github.com/andyrewlee/amux/internal/lsp"
 	"github.com/andyrewlee/amux/internal/messages"
 	"github.com/andyrewlee/amux/internal/supervisor"
 	"github.com/andyrewlee/amux/internal/tmux"
@@ -18,6 +23,7 @@ import (
 	"github.com/andyrewlee/amux/internal/ui/common"
 	"github.com/andyrewlee/amux/internal/ui/dashboard"
 	"github.com/andyrewlee/amux/internal/ui/layout"
+	"github.com/andyrewlee/amux/internal/ui/ptyio"
 	"github.com/andyrewlee/amux/internal/ui/sidebar"
 	"github.com/andyrewlee/amux/internal/update"
 )
@@ -35,9 +41,72 @@ const (
 	// The dialog itself is built by common.NewAgentPicker and carries
 	// common.AgentPickerDialogID at runtime; handleDialogResult still matches
 	// DialogSelectAssistant alongside it so older callers keep routing.
-	DialogSelectAssistant = "select_assistant"
-	DialogQuit            = "quit"
-	DialogCleanupTmux     = "cleanup_tmux"
+	DialogSelectAssistant  = "select_assistant"
+	DialogQuit             = "quit"
+	DialogCleanupTmux      = "cleanup_tmux"
+	DialogOpenIn           = "open_in"
+	DialogTrash            = "trash"
+	DialogLargePaste       = "large_paste"
+	DialogPastePreview     = "paste_preview"
+	DialogTerminalSearch   = "terminal_search"
+	DialogEgress           = "egress"
+	DialogRunCommand       = "run_command"
+	DialogRelaunch         = "relaunch"
+	DialogStartupLayout    = "startup_layout"
+	DialogTrustAndRun      = "trust_and_run"
+	DialogTests            = "tests"
+	DialogChecks           = "checks"
+	DialogSymbolSearch     = "symbol_search"
+	DialogDefinition       = "definition"
+	DialogCodeNav          = "code_nav"
+	DialogHunkReview       = "hunk_review"
+	DialogHunkRedo         = "hunk_redo"
+	DialogDiffComment      = "diff_comment"
+	DialogCompareWith      = "compare_with"
+	DialogComparison       = "comparison"
+	DialogMergeQueue       = "merge_queue"
+	DialogMergeConflict    = "merge_conflict"
+	DialogMergeChecks      = "merge_checks"
+	DialogMergeSummary     = "merge_summary"
+	DialogFanOutTask       = "fan_out_task"
+	DialogFanOutVariations = "fan_out_variations"
+	DialogFanOutAgent      = "fan_out_agent"
+	DialogFanOutQueue      = "fan_out_queue"
+	DialogBenchmarkTask    = "benchmark_task"
+	DialogBenchmarkAccept  = "benchmark_accept"
+	DialogBenchmarkAgents  = "benchmark_agents"
+	DialogBenchmark        = "benchmark"
+	DialogAsk              = "ask"
+	DialogAskAnswer        = "ask_answer"
+	DialogCodeBlocks       = "code_blocks"
+	DialogCodeBlockAction  = "code_block_action"
+	DialogCodeBlockSave    = "code_block_save"
+	DialogCodeBlockReplace = "code_block_replace"
+	DialogAttachImage      = "attach_image"
+	DialogInputHistory     = "input_history"
+	DialogWorktreeHistory  = "worktree_history"
+	DialogActivityFeed     = "activity_feed"
+	DialogLeftoverSessions = "leftover_sessions"
+	DialogMonorepoTask     = "monorepo_task"
+	DialogMonorepoTargets  = "monorepo_targets"
+	DialogChangelog        = "changelog"
+	DialogContainerStart   = "container_start"
+	DialogContainerStop    = "container_stop"
+	DialogGPU              = "gpu"
+	DialogBisectBad        = "bisect_bad"
+	DialogBisectGood       = "bisect_good"
+	DialogBisectCommand    = "bisect_command"
+	DialogBisectBug        = "bisect_bug"
+	DialogBisectStep       = "bisect_step"
+	DialogBisectResult     = "bisect_result"
+	DialogStack            = "stack"
+	DialogStackNew         = "stack_new"
+)
+
+// Search panel IDs
+const (
+	SearchPanelFiles  = "search_files"
+	SearchPanelErrors = "recent_errors"
 )
 
 // prefixTimeoutMsg is sent when the prefix mode timer expires.
@@ -91,6 +160,8 @@ type App struct {
 	settingsThemeDirty          bool
 	// Theme that was active when the settings dialog opened, restored on Esc.
 	settingsThemeOriginal common.ThemeID
+	// Latency profile active when the settings dialog opened, restored on Esc.
+	settingsLatencyOriginal ptyio.LatencyProfile
 	// envDialog is the workspace environment-variable editor; envDialogWorkspace
 	// is the workspace it was opened for, read back in handleEnvDialogResult
 	// (mirroring dialogWorkspace's role for the generic Dialog, but tracked
@@ -98,6 +169,12 @@ type App struct {
 	// a.dialogWorkspace).
 	envDialog          *common.EnvDialog
 	envDialogWorkspace *data.Workspace
+	// searchPanel is the open search panel, told apart by its ID: the
+	// ripgrep search over the active worktree, whose state lives in
+	// fileSearch, or the recent errors list.
+	searchPanel  *common.SearchPanel
+	fileSearch   fileSearchState
+	recentErrors recentErrorsState
 
 	// Overlays
 	toast *common.ToastModel
@@ -106,6 +183,11 @@ type App struct {
 	dialogProject          *data.Project
 	dialogWorkspace        *data.Workspace
 	dialogTrustScriptsHash string
+	// dialogTrash is the trash snapshot the trash dialog's options index into.
+	dialogTrash trashView
+	// dialogTrustThen runs the action the trust-and-run dialog was raised
+	// for, once the repo's scripts are trusted (app_trust_prompt.go).
+	dialogTrustThen func(ws *data.Workspace, trustHash string) tea.Cmd
 	// Pending workspace creation context while selecting assistant.
 	pendingWorkspaceProject *data.Project
 	pendingWorkspaceName    string
@@ -115,6 +197,9 @@ type App struct {
 	// git.CommitAll); tests install a fake to assert the dialog→commit wiring
 	// without a real repo.
 	commitAllFn func(context.Context, string, string) error
+	// openInFn is the "open worktree in…" launch seam. Nil in production
+	// (falls back to process.StartDetached); tests install a fake.
+	openInFn func(dir, command string) error
 
 	// Git status management
 	fileWatcher     *git.FileWatcher
@@ -140,6 +225,18 @@ type App struct {
 	prefixActive   bool
 	prefixToken    int
 	prefixSequence []string
+	// nesting records an enclosing tmux/amux (app_nesting.go).
+	nesting Nesting
+	// notifications tracks agent events already notified (app_notify.go).
+	notifications notifyState
+	// terminalBadge is whether the tab attention badge is shown
+	// (app_terminal_title.go).
+	terminalBadge bool
+	// focusFollow moves focus to an agent needing attention
+	// (app_focus_follow.go).
+	focusFollow focusFollowState
+	// inputLocked pins focus to the focused terminal (app_input_lock.go).
+	inputLocked bool
 
 	// tmuxActivity holds tmux activity-scan bookkeeping (tokens, coalescing,
 	// shared-scan ownership, per-session hysteresis).
@@ -150,9 +247,106 @@ type App struct {
 	projectsLoaded  bool
 	tmuxInstallHint string
 	instanceID      string // Immutable after init; safe for read-only access from Cmd goroutines.
+	// readOnly marks a second view of state another amux owns
+	// (app_read_only.go).
+	readOnly      bool
+	readOnlyOwner string
+	// auditLog records text amux pastes into terminals on its own behalf
+	// (app_large_paste.go).
+	auditLog *audit.Log
+	// leftover holds the sessions a previous amux left running
+	// (app_tmux_leftover.go).
+	leftover leftoverState
+	// activateHandledAt is when the last activate request was taken up
+	// (app_activate_request.go).
+	activateHandledAt time.Time
 
 	// lifecycle holds workspace create/delete/persist bookkeeping.
 	lifecycle workspaceLifecycleState
+	// undo holds reversible destructive actions (app_undo.go).
+	undo undoState
+	// largePaste holds a paste awaiting confirmation or being chunk-written
+	// (app_large_paste.go).
+	largePaste largePasteState
+	// pendingLayout holds the startup layout awaiting confirmation
+	// (app_layout.go).
+	pendingLayout []data.Launch
+	// tests holds each workspace's last test run (app_test_panel.go).
+	tests testPanelState
+	// checks holds each workspace's last check run (app_checks.go).
+	checks checksState
+	// lsp runs a language server per worktree for code navigation
+	// (app_code_nav.go); created on first use.
+	lsp *lsp.Manager
+	// codeNav holds the locations listed in the code navigation dialog.
+	codeNav codeNavState
+	// hunkReview walks the active workspace's hunks (app_hunk_review.go).
+	hunkReview hunkReviewState
+	// reviewComments holds diff comments waiting to be sent to an agent
+	// (app_review_comments.go).
+	reviewComments reviewCommentsState
+	// compare holds the worktree comparison in progress (app_compare.go).
+	compare compareState
+	// mergeAssist lands a queue of workspace branches on an integration
+	// workspace (app_merge_assistant.go).
+	mergeAssist mergeAssistState
+	// fanOut sets up fan-outs and queues their workspaces for review
+	// (app_fanout.go).
+	fanOut fanOutState
+	// benchmark runs agents on the same task and compares them
+	// (app_benchmark.go).
+	benchmark benchmarkState
+	// bisect is the bisect in progress in a temporary worktree
+	// (app_bisect.go).
+	bisect *bisectState
+	// stack is the stack view and the restack or push it runs
+	// (app_stack.go).
+	stack *stackState
+	// ask answers questions about using amux (app_ask.go).
+	ask askState
+	// columns caches the dashboard's custom column values
+	// (app_columns.go).
+	columns columnsState
+	// statusBar holds the status bar widgets' segments (app_status_bar.go).
+	statusBar statusBarState
+	// monorepo scopes new workspaces in monorepos to the packages a task
+	// touches (app_monorepo.go).
+	monorepo monorepoState
+	// commitDraft is the commit dialog a message is being drafted for
+	// (app_commit_message.go).
+	commitDraft commitDraftState
+	// container is the dev container start being confirmed
+	// (app_container.go).
+	container containerState
+	// gpuPins holds the CUDA_VISIBLE_DEVICES value of each option in the GPU
+	// panel (app_gpu.go).
+	gpuPins []string
+	// devEnvChecked holds the worktree roots whose declared environment has
+	// been loaded or is loading (app_devenv.go).
+	devEnvChecked map[string]bool
+	// codeBlocks holds the code blocks found in a terminal and the one
+	// being copied, saved, or applied (app_code_blocks.go).
+	codeBlocks codeBlockState
+	// inputHistory is the list shown by the input history dialog
+	// (app_input_history.go).
+	inputHistory []inputhistory.Entry
+	// historyExits dedups the agent exits recorded in worktree timelines.
+	historyExits map[string]time.Time
+	activityFeed activityFeedState
+	// dictation is the running voice input command (app_dictation.go).
+	dictation dictationState
+	// llm drafts for the assistive features enabled in config.
+	llm *llm.Client
+	// lowBandwidth is set for SSH-friendly rendering (app_low_bandwidth.go).
+	lowBandwidth bool
+	// terminalSearchPane is the pane whose terminal the open search dialog
+	// targets.
+	terminalSearchPane messages.PaneType
+	// egress tracks agent network connections (app_egress.go); created on
+	// first use.
+	egress *egressState
+	// limitsMonitor tracks agents' resource-limit breaches (app_limits.go).
+	limitsMonitor *limits.Monitor
 
 	// Terminal capabilities
 	keyboardEnhancements tea.KeyboardEnhancementsMsg
@@ -0,0 +1,75 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	tea "charm.land/bubbletea/v2"
+
+	"github.com/andyrewlee/amux/internal/data"
+	"github.com/andyrewlee/amux/internal/devenv"
+	"github.com/andyrewlee/amux/internal/logging"
+)
+
+// devEnvCheckTimeout bounds loading a worktree's environment, which for a
+// flake can mean building it first.
+const devEnvCheckTimeout = 10 * time.Minute
+
+// Seams for tests, which have neither direnv nor nix.
+var (
+	devEnvDetect = devenv.Detect
+	devEnvCheck  = devenv.Check
+)
+
+// devEnvChecked reports whether a worktree's declared environment loaded.
+type devEnvChecked struct {
+	workspaceID string
+	root        string
+	kind        devenv.Kind
+	err         error
+}
+
+// checkDevEnv loads ws's declared environment (.envrc or flake.nix) in the
+// background the first time ws is activated, so a broken one is flagged on
+// the dashboard before its tabs fall back to running without it. Sandboxed
+// workspaces are skipped: their environment only ever loads inside the
+// sandbox, alongside the agent.
+func (a *App) checkDevEnv(ws *data.Workspace) tea.Cmd {
+	if ws == nil || ws.Container != nil || ws.Sandbox || ws.NoNetwork || a.devEnvChecked[ws.Root] {
+		return nil
+	}
+	kind := devEnvDetect(ws.Root)
+	if kind == devenv.None {
+		return nil
+	}
+	if a.devEnvChecked == nil {
+		a.devEnvChecked = make(map[string]bool)
+	}
+	a.devEnvChecked[ws.Root] = true
+	wsID, root := string(ws.ID()), ws.Root
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), devEnvCheckTimeout)
+		defer cancel()
+		return devEnvChecked{workspaceID: wsID, root: root, kind: kind, err: devEnvCheck(ctx, kind, root)}
+	}
+}
+
+// handleDevEnvChecked flags a workspace whose environment failed to load.
+// The check is run again the next time it is activated, after the user has
+// had a chance to fix it (for example with "direnv allow").
+func (a *App) handleDevEnvChecked(msg devEnvChecked) tea.Cmd {
+	if msg.err == nil {
+		logging.Info("Loaded %s environment for %s", msg.kind, msg.root)
+		return nil
+	}
+	delete(a.devEnvChecked, msg.root)
+	logging.Warn("devenv: %s: %v", msg.root, msg.err)
+	if a.dashboard != nil {
+		a.dashboard.SetAlert(msg.workspaceID, "env failed")
+	}
+	if a.toast == nil {
+		return nil
+	}
+	return a.toast.ShowWarning(fmt.Sprintf("Environment failed to load; tabs run without it. %v", msg.err))
+}
@@ -0,0 +1,51 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/andyrewlee/amux/internal/config"
+	"github.com/andyrewlee/amux/internal/devenv"
+	"github.com/andyrewlee/amux/internal/ui/common"
+	"github.com/andyrewlee/amux/internal/ui/dashboard"
+)
+
+func TestCheckDevEnv(t *testing.T) {
+	app, ws := newNotifyTestApp(config.UISettings{})
+	app.dashboard = dashboard.New()
+	app.toast = common.NewToastModel()
+	oldDetect, oldCheck := devEnvDetect, devEnvCheck
+	t.Cleanup(func() { devEnvDetect, devEnvCheck = oldDetect, oldCheck })
+	devEnvDetect = func(string) devenv.Kind { return devenv.Direnv }
+	checks := 0
+	devEnvCheck = func(context.Context, devenv.Kind, string) error {
+		checks++
+		return errors.New("direnv: error .envrc is blocked")
+	}
+
+	cmd := app.checkDevEnv(ws)
+	if cmd == nil || app.checkDevEnv(ws) != nil {
+		t.Fatal("the environment should be checked once while a check is pending")
+	}
+	if app.handleDevEnvChecked(cmd().(devEnvChecked)) == nil {
+		t.Fatal("a failed load should be reported")
+	}
+	wsID := string(ws.ID())
+	if !app.dashboard.NeedsAttention(wsID) {
+		t.Fatal("a failed load should flag the workspace")
+	}
+	if app.checkDevEnv(ws) == nil {
+		t.Fatal("a failed load should be checked again on the next activation")
+	}
+
+	devEnvCheck = func(context.Context, devenv.Kind, string) error { checks++; return nil }
+	ws.Root = "/repo/other"
+	if app.handleDevEnvChecked(app.checkDevEnv(ws)().(devEnvChecked)) != nil || app.checkDevEnv(ws) != nil || checks != 2 {
+		t.Fatalf("a loaded environment should be left alone (%d checks)", checks)
+	}
+	ws.Root, ws.Sandbox = "/repo/sandboxed", true
+	if app.checkDevEnv(ws) != nil {
+		t.Fatal("a sandboxed workspace's environment loads only inside the sandbox")
+	}
+}
@@ -26,6 +26,63 @@ var appDialogIDList = []string{
 	common.AgentPickerDialogID,
 	DialogQuit,
 	DialogCleanupTmux,
+	DialogOpenIn,
+	DialogTrash,
+	DialogLargePaste,
+	DialogPastePreview,
+	DialogTerminalSearch,
+	DialogEgress,
+	DialogRunCommand,
+	DialogRelaunch,
+	DialogStartupLayout,
+	DialogTrustAndRun,
+	DialogTests,
+	DialogChecks,
+	DialogSymbolSearch,
+	DialogDefinition,
+	DialogCodeNav,
+	DialogHunkReview,
+	DialogHunkRedo,
+	DialogDiffComment,
+	DialogCompareWith,
+	DialogComparison,
+	DialogMergeQueue,
+	DialogMergeConflict,
+	DialogMergeChecks,
+	DialogMergeSummary,
+	DialogFanOutTask,
+	DialogFanOutVariations,
+	DialogFanOutAgent,
+	DialogFanOutQueue,
+	DialogBenchmarkTask,
+	DialogBenchmarkAccept,
+	DialogBenchmarkAgents,
+	DialogBenchmark,
+	DialogAsk,
+	DialogAskAnswer,
+	DialogCodeBlocks,
+	DialogCodeBlockAction,
+	DialogCodeBlockSave,
+	DialogCodeBlockReplace,
+	DialogAttachImage,
+	DialogInputHistory,
+	DialogWorktreeHistory,
+	DialogActivityFeed,
+	DialogLeftoverSessions,
+	DialogMonorepoTask,
+	DialogMonorepoTargets,
+	DialogChangelog,
+	DialogContainerStart,
+	DialogContainerStop,
+	DialogGPU,
+	DialogBisectBad,
+	DialogBisectGood,
+	DialogBisectCommand,
+	DialogBisectBug,
+	DialogBisectStep,
+	DialogBisectResult,
+	DialogStack,
+	DialogStackNew,
 }
 
 // appDialogIDs is the set form of appDialogIDList, built once at init. Routing
@@ -0,0 +1,91 @@
+package app
+
+import (
+	tea "charm.land/bubbletea/v2"
+
+	"github.com/andyrewlee/amux/internal/dictation"
+	"github.com/andyrewlee/amux/internal/messages"
+	"github.com/andyrewlee/amux/internal/ui/common"
+)
+
+// dictationState is the running dictation, if any. id tells a finished
+// session's message apart from a newer session's.
+type dictationState struct {
+	session *dictation.Session
+	id      int
+}
+
+// dictationEnded reports that the dictation command exited.
+type dictationEnded struct {
+	id  int
+	err error
+}
+
+// toggleDictation starts dictating into the active workspace's agent tab,
+// or stops the running dictation. Stopping lets the command transcribe what
+// it heard before exiting, so pressing the binding twice works as
+// push-to-talk.
+func (a *App) toggleDictation() tea.Cmd {
+	if a.dictation.session != nil {
+		a.dictation.session.Stop()
+		return a.toast.ShowInfo("Dictation stopping...")
+	}
+	command := ""
+	if a.config != nil {
+		command = a.config.Dictation.Command
+	}
+	if command == "" {
+		return a.toast.ShowWarning(`No dictation command: set "dictation": {"command": ...} in config.json`)
+	}
+	ws := a.activeWorkspace
+	if ws == nil {
+		return nil
+	}
+	target := a.workspaceAgentTab(ws)
+	if target < 0 {
+		return a.toast.ShowWarning("No agent tab in this workspace")
+	}
+	selectCmd := a.center.SelectTab(target)
+	sink := a.center.ActivePasteSink()
+	if sink == nil {
+		return a.toast.ShowWarning("No terminal to dictate into")
+	}
+	session, err := dictation.Start(ws.Root, command, func(text string) {
+		sink("\x1b[200~" + text + "\x1b[201~")
+	})
+	if err != nil {
+		return common.ReportError(errorContext(errorServiceWorkspace, "starting dictation"), err, "")
+	}
+	a.dictation.id++
+	a.dictation.session = session
+	id := a.dictation.id
+	return common.SafeBatch(
+		selectCmd,
+		a.persistActiveWorkspaceTabs(),
+		a.focusPane(messages.PaneCenter),
+		a.toast.ShowInfo("Dictating into "+ws.Name+"; prefix t m to stop"),
+		func() tea.Msg {
+			return dictationEnded{id: id, err: session.Err()}
+		},
+	)
+}
+
+// handleDictationEnded clears the finished session and reports a command
+// that failed rather than being stopped.
+func (a *App) handleDictationEnded(msg dictationEnded) tea.Cmd {
+	if msg.id != a.dictation.id {
+		return nil
+	}
+	a.dictation.session = nil
+	if msg.err != nil {
+		return common.ReportError(errorContext(errorServiceWorkspace, "dictation"), msg.err, "")
+	}
+	return a.toast.ShowInfo("Dictation stopped")
+}
+
+// stopDictation stops a running dictation without waiting for it.
+func (a *App) stopDictation() {
+	if a.dictation.session != nil {
+		a.dictation.session.Stop()
+	}
+}
@@ -0,0 +1,25 @@
+package app
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestDictationNeedsCommandAndIgnoresStaleEnds(t *testing.T) {
+	h := newDialogHarness(t)
+	h.app.config.Dictation.Command = ""
+	if cmd := h.app.toggleDictation(); cmd == nil || h.app.dictation.session != nil {
+		t.Fatal("dictation without a command should only warn")
+	}
+	if h.app.prefixActionVisible("dictate") {
+		t.Fatal("dictate should be hidden until a command is configured")
+	}
+
+	h.app.dictation.id = 2
+	if cmd := h.app.handleDictationEnded(dictationEnded{id: 1, err: errors.New("old")}); cmd != nil {
+		t.Fatal("an earlier session's end should be ignored")
+	}
+	if cmd := h.app.handleDictationEnded(dictationEnded{id: 2}); cmd == nil {
+		t.Fatal("the current session's end should be reported")
+	}
+}
@@ -0,0 +1,221 @@
+package app
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	tea "charm.land/bubbletea/v2"
+
+	"github.com/andyrewlee/amux/internal/data"
+	"github.com/andyrewlee/amux/internal/egress"
+	"github.com/andyrewlee/amux/internal/logging"
+	"github.com/andyrewlee/amux/internal/sandbox"
+	"github.com/andyrewlee/amux/internal/ui/common"
+)
+
+// egressSampleInterval is how often agent connections are sampled.
+const egressSampleInterval = 15 * time.Second
+
+// egressState tracks the network destinations of agent sessions.
+type egressState struct {
+	tracker *egress.Tracker
+	names   *egress.Names
+	allow   *egress.Allowlist
+	// sessions maps each sampled agent session to its workspace and
+	// assistant, as of the last sample.
+	sessions map[string]egressSession
+}
+
+type egressSession struct {
+	workspaceID string
+	assistant   string
+}
+
+type egressTick struct{}
+
+type egressSampleResult struct {
+	sessions map[string]egressSession
+	observed map[string][]egress.Observation
+	at       time.Time
+}
+
+func (a *App) egressMonitor() *egressState {
+	if a.egress == nil {
+		a.egress = &egressState{
+			tracker: egress.NewTracker(),
+			names:   egress.NewNames(),
+			allow:   egress.ParseAllowlist(os.Getenv(egress.AllowEnvVar)),
+		}
+	}
+	return a.egress
+}
+
+func (a *App) startEgressTicker() tea.Cmd {
+	return common.SafeTick(egressSampleInterval, func(time.Time) tea.Msg {
+		return egressTick{}
+	})
+}
+
+// handleEgressTick samples agent connections off the UI goroutine. The next
+// tick is scheduled when the sample lands, so samples never overlap.
+func (a *App) handleEgressTick() tea.Cmd {
+	if a.tmuxService == nil || !a.tmuxAvailable {
+		return a.startEgressTicker()
+	}
+	svc := a.tmuxService
+	opts := a.tmuxOptions
+	state := a.egressMonitor()
+	names, allow := state.names, state.allow
+	return func() tea.Msg {
+		rows, err := svc.SessionsWithTags(map[string]string{"@amux_type": "agent"}, []string{"@amux_workspace", "@amux_assistant"}, opts)
+		if err != nil {
+			logging.Debug("egress: list agent sessions: %v", err)
+		}
+		result := egressSampleResult{
+			sessions: make(map[string]egressSession, len(rows)),
+			observed: make(map[string][]egress.Observation, len(rows)),
+			at:       time.Now(),
+		}
+		for _, row := range rows {
+			result.sessions[row.Name] = egressSession{workspaceID: row.Tags["@amux_workspace"], assistant: row.Tags["@amux_assistant"]}
+			pids, err := svc.SessionPanePIDs(row.Name, opts)
+			if err != nil || len(pids) == 0 {
+				continue
+			}
+			conns, err := egress.Sample(pids)
+			if err != nil {
+				logging.Debug("egress: sample %s: %v", row.Name, err)
+				continue
+			}
+			for _, conn := range conns {
+				name := names.Name(conn.Remote.Addr())
+				result.observed[row.Name] = append(result.observed[row.Name], egress.Observation{
+					Conn:    conn,
+					Name:    name,
+					Flagged: !allow.Allows(conn.Remote.Addr(), name),
+				})
+			}
+		}
+		return result
+	}
+}
+
+// handleEgressSampleResult records a sample and warns about destinations
+// outside AMUX_EGRESS_ALLOW the first time each is seen.
+func (a *App) handleEgressSampleResult(msg egressSampleResult) []tea.Cmd {
+	state := a.egressMonitor()
+	state.sessions = msg.sessions
+	live := make(map[string]bool, len(msg.sessions))
+	var flagged []string
+	for session, info := range msg.sessions {
+		live[session] = true
+		for _, dest := range state.tracker.Observe(session, msg.observed[session], msg.at) {
+			logging.Warn("egress: %s (%s) connected to unexpected %s via %s", info.assistant, session, dest.Label(), dest.Command)
+			flagged = append(flagged, info.assistant+" → "+dest.Label())
+		}
+	}
+	state.tracker.Forget(live)
+	cmds := []tea.Cmd{a.startEgressTicker()}
+	if len(flagged) > 0 && a.toast != nil {
+		text := "Unexpected connection: " + flagged[0]
+		if len(flagged) > 1 {
+			text = fmt.Sprintf("%s (+%d more, prefix E to review)", text, len(flagged)-1)
+		}
+		cmds = append(cmds, a.toast.ShowWarning(text))
+	}
+	return cmds
+}
+
+// egressLines describes what ws's agents have connected to, one destination
+// per line, flagged destinations first, then by host.
+func (a *App) egressLines(ws *data.Workspace, now time.Time) []string {
+	state := a.egressMonitor()
+	wsID := string(ws.ID())
+	type egressLine struct {
+		flagged bool
+		host    string
+		text    string
+	}
+	var entries []egressLine
+	for session, info := range state.sessions {
+		if info.workspaceID != wsID {
+			continue
+		}
+		for _, dest := range state.tracker.Destinations(session) {
+			mark := "  "
+			if dest.Flagged {
+				mark = "! "
+			}
+			entries = append(entries, egressLine{
+				flagged: dest.Flagged,
+				host:    dest.Label(),
+				text: fmt.Sprintf("%s%s · %s via %s · seen %s ago",
+					mark, info.assistant, dest.Label(), dest.Command, now.Sub(dest.LastSeen).Round(time.Second)),
+			})
+		}
+	}
+	// Sessions come from a map; sort so the list does not reshuffle.
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].flagged != entries[j].flagged {
+			return entries[i].flagged
+		}
+		if entries[i].host != entries[j].host {
+			return entries[i].host < entries[j].host
+		}
+		return entries[i].text < entries[j].text
+	})
+	lines := make([]string, 0, len(entries))
+	for _, e := range entries {
+		lines = append(lines, e.text)
+	}
+	return lines
+}
+
+// showEgressDialog lists the active workspace's agent connections and, when a
+// sandbox tool is installed, offers to block the network for its agents.
+func (a *App) showEgressDialog() tea.Cmd {
+	ws := a.activeWorkspace
+	if ws == nil {
+		return nil
+	}
+	lines := a.egressLines(ws, time.Now())
+	message := "Connections seen from this workspace's agents:"
+	if len(lines) == 0 {
+		lines = []string{"No connections seen yet"}
+	}
+	if a.egressMonitor().allow.Empty() {
+		message += fmt.Sprintf("\nSet %s to flag unexpected destinations.", egress.AllowEnvVar)
+	}
+	a.dialog = common.NewListDialog(DialogEgress, "Agent Network", message, lines)
+	a.dialogWorkspace = ws
+	switch {
+	case ws.Sandbox:
+		a.dialog.SetWarning("Network is blocked for new agents by this workspace's sandbox.")
+	case sandboxDetect() != sandbox.None:
+		a.dialog.SetToggle("Block network for new agents in this worktree", ws.NoNetwork)
+	}
+	a.presentDialog(a.dialog)
+	return nil
+}
+
+// applyNoNetworkChoice persists the network toggle from the egress dialog.
+func (a *App) applyNoNetworkChoice(ws *data.Workspace, enabled bool) tea.Cmd {
+	if ws == nil || ws.Sandbox || ws.NoNetwork == enabled || sandboxDetect() == sandbox.None {
+		return nil
+	}
+	if a.workspaceService != nil && a.workspaceService.store != nil {
+		if err := a.workspaceService.store.SetNoNetwork(ws.ID(), enabled); err != nil {
+			return common.ReportError(errorContext(errorServiceWorkspace, "saving network setting"), err, "")
+		}
+	}
+	ws.NoNetwork = enabled
+	if a.toast == nil {
+		return nil
+	}
+	if enabled {
+		return a.toast.ShowInfo("New agents in " + ws.Name + " start without network")
+	}
+	return a.toast.ShowInfo("New agents in " + ws.Name + " have network access")
+}
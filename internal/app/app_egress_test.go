@@ -0,0 +1,118 @@
+package app
+
+import (
+	"fmt"
+	"net/netip"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/andyrewlee/amux/internal/egress"
+	"github.com/andyrewlee/amux/internal/sandbox"
+	"github.com/andyrewlee/amux/internal/ui/common"
+)
+
+func TestEgressSampleFlagsAndListsDestinations(t *testing.T) {
+	stubSandboxDetect(t, sandbox.None)
+	h := newDialogHarness(t)
+	ws := harnessWorkspace()
+	h.app.activeWorkspace = ws
+	state := h.app.egressMonitor()
+	state.allow = egress.ParseAllowlist("10.0.0.0/8")
+
+	conn := func(addr string) egress.Conn {
+		return egress.Conn{PID: 42, Command: "node", Remote: netip.MustParseAddrPort(addr)}
+	}
+	obs := []egress.Observation{
+		{Conn: conn("10.1.2.3:443"), Name: "internal"},
+		{Conn: conn("203.0.113.9:443"), Name: "evil.example", Flagged: true},
+	}
+	msg := egressSampleResult{
+		sessions: map[string]egressSession{"amux-s1": {workspaceID: string(ws.ID()), assistant: "claude"}},
+		observed: map[string][]egress.Observation{"amux-s1": obs},
+		at:       time.Now(),
+	}
+	if cmds := h.app.handleEgressSampleResult(msg); len(cmds) != 2 {
+		t.Fatalf("expected the re-armed ticker plus a warning, got %d cmds", len(cmds))
+	}
+	if cmds := h.app.handleEgressSampleResult(msg); len(cmds) != 1 {
+		t.Fatalf("a destination should only be flagged once, got %d cmds", len(cmds))
+	}
+
+	h.app.showEgressDialog()
+	view := dialogView(t, h.app.dialog)
+	if !strings.Contains(view, "evil.example") || !strings.Contains(view, "internal") {
+		t.Fatalf("dialog should list both destinations, got %q", view)
+	}
+	if strings.Index(view, "evil.example") > strings.Index(view, "internal") {
+		t.Fatalf("flagged destinations should be listed first, got %q", view)
+	}
+	if strings.Contains(view, "Block network") {
+		t.Fatal("dialog should not offer blocking without a sandbox tool")
+	}
+
+	// Sessions that disappear drop out of the dialog.
+	h.app.handleEgressSampleResult(egressSampleResult{at: time.Now()})
+	h.app.showEgressDialog()
+	if view := dialogView(t, h.app.dialog); !strings.Contains(view, "No connections seen yet") {
+		t.Fatalf("expected the empty placeholder, got %q", view)
+	}
+}
+
+func TestEgressLinesSortFlaggedThenByHost(t *testing.T) {
+	h := newDialogHarness(t)
+	ws := harnessWorkspace()
+	conn := func(addr string) egress.Conn {
+		return egress.Conn{PID: 42, Command: "node", Remote: netip.MustParseAddrPort(addr)}
+	}
+	sessions := map[string]egressSession{}
+	observed := map[string][]egress.Observation{}
+	for i, o := range []egress.Observation{
+		{Conn: conn("10.0.0.3:443"), Name: "c.internal"},
+		{Conn: conn("203.0.113.2:443"), Name: "b.example", Flagged: true},
+		{Conn: conn("10.0.0.1:443"), Name: "a.internal"},
+		{Conn: conn("203.0.113.1:443"), Name: "a.example", Flagged: true},
+	} {
+		session := fmt.Sprintf("amux-s%d", i)
+		sessions[session] = egressSession{workspaceID: string(ws.ID()), assistant: "claude"}
+		observed[session] = []egress.Observation{o}
+	}
+	now := time.Now()
+	h.app.handleEgressSampleResult(egressSampleResult{sessions: sessions, observed: observed, at: now})
+
+	want := []string{"a.example", "b.example", "a.internal", "c.internal"}
+	for run := 0; run < 5; run++ {
+		lines := h.app.egressLines(ws, now)
+		if len(lines) != len(want) {
+			t.Fatalf("lines = %q, want %d", lines, len(want))
+		}
+		for i, host := range want {
+			if !strings.Contains(lines[i], host) {
+				t.Fatalf("lines = %q, want %s at %d", lines, host, i)
+			}
+		}
+	}
+}
+
+func TestEgressDialogNetworkToggleAppliesToWorkspace(t *testing.T) {
+	stubSandboxDetect(t, sandbox.Bubblewrap)
+	h := newDialogHarness(t)
+	ws := harnessWorkspace()
+	h.app.activeWorkspace = ws
+	h.app.workspaceService = nil
+
+	h.app.showEgressDialog()
+	if view := dialogView(t, h.app.dialog); !strings.Contains(view, "[ ] Block network") {
+		t.Fatalf("dialog should offer blocking the network, got %q", view)
+	}
+	h.app.handleDialogResult(common.DialogResult{ID: DialogEgress, Confirmed: true, Toggle: true})
+	if !ws.NoNetwork {
+		t.Fatal("confirming with the toggle on should block the network for the workspace")
+	}
+
+	ws.Sandbox = true
+	h.app.showEgressDialog()
+	if view := dialogView(t, h.app.dialog); strings.Contains(view, "Block network") {
+		t.Fatal("a sandboxed workspace already has no network; the toggle would be moot")
+	}
+}
@@ -0,0 +1,383 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+
+	tea "charm.land/bubbletea/v2"
+
+	"github.com/andyrewlee/amux/internal/data"
+	"github.com/andyrewlee/amux/internal/messages"
+	"github.com/andyrewlee/amux/internal/process"
+	"github.com/andyrewlee/amux/internal/ui/common"
+	"github.com/andyrewlee/amux/internal/validation"
+)
+
+const (
+	fanOutOptionNew = "New fan-out"
+	// fanOutPlaceholder marks where each variation goes in the task. A task
+	// without it gets the variation appended.
+	fanOutPlaceholder = "{variation}"
+	fanOutMaxRuns     = 12
+	fanOutTaskLimit   = 2000
+)
+
+// fanOutState holds the fan-out being set up and the review queue: every
+// fanned-out workspace not yet opened for review.
+type fanOutState struct {
+	project    *data.Project
+	task       string
+	variations []string
+	queue      []*fanOutRun
+}
+
+type fanOutStatus int
+
+const (
+	fanOutCreating fanOutStatus = iota
+	fanOutLaunching
+	fanOutRunning
+	fanOutFailed
+)
+
+// fanOutRun is one variation's workspace and agent.
+type fanOutRun struct {
+	project   *data.Project
+	name      string
+	variation string
+	assistant string
+	prompt    string
+	status    fanOutStatus
+	err       string
+	ws        *data.Workspace
+}
+
+// fanOutTargets carries the variations read from the project's top-level
+// directories.
+type fanOutTargets struct {
+	project    *data.Project
+	variations []string
+	err        error
+}
+
+// fanOutLaunched reports starting a run's agent.
+type fanOutLaunched struct {
+	run     *fanOutRun
+	session string
+	err     error
+}
+
+// showFanOut lists the review queue, or starts a new fan-out in project
+// when the queue is empty.
+func (a *App) showFanOut(project *data.Project) tea.Cmd {
+	if project == nil {
+		return nil
+	}
+	if len(a.fanOut.queue) == 0 {
+		return a.startFanOut(project)
+	}
+	a.fanOut.project = project
+	options := []string{fanOutOptionNew}
+	for _, run := range a.fanOut.queue {
+		options = append(options, fmt.Sprintf("%s  %s  [%s]", run.name, run.variation, run.statusText()))
+	}
+	a.dialog = common.NewListDialog(DialogFanOutQueue, "Fan-out Review",
+		"Workspaces from fan-outs, waiting for review. Pick one to open it and take it off the list.", options)
+	a.presentDialog(a.dialog)
+	return nil
+}
+
+func (r *fanOutRun) statusText() string {
+	switch r.status {
+	case fanOutLaunching:
+		return "starting agent"
+	case fanOutRunning:
+		return r.assistant + " running"
+	case fanOutFailed:
+		return "failed: " + r.err
+	default:
+		return "setting up"
+	}
+}
+
+// handleFanOutQueueChoice starts a new fan-out, or opens the chosen run's
+// workspace and takes it off the queue. A run whose workspace was never
+// created is just taken off.
+func (a *App) handleFanOutQueueChoice(index int) tea.Cmd {
+	if index == 0 {
+		return a.startFanOut(a.fanOut.project)
+	}
+	index--
+	if index < 0 || index >= len(a.fanOut.queue) {
+		return nil
+	}
+	run := a.fanOut.queue[index]
+	if run.ws == nil {
+		if run.status != fanOutFailed {
+			return a.toast.ShowInfo(run.name + " is still being set up")
+		}
+		a.fanOut.queue = slices.Delete(a.fanOut.queue, index, index+1)
+		return a.toast.ShowInfo("Removed " + run.name + " from the list")
+	}
+	a.fanOut.queue = slices.Delete(a.fanOut.queue, index, index+1)
+	ws, project := a.findWorkspaceAndProjectByID(string(run.ws.ID()))
+	if ws == nil {
+		return a.toast.ShowWarning(run.name + " is no longer in amux")
+	}
+	return func() tea.Msg { return messages.WorkspaceActivated{Project: project, Workspace: ws} }
+}
+
+// startFanOut asks for the task given to every agent.
+func (a *App) startFanOut(project *data.Project) tea.Cmd {
+	if project == nil {
+		return nil
+	}
+	a.fanOut.project, a.fanOut.task, a.fanOut.variations = project, "", nil
+	a.dialog = common.NewInputDialog(DialogFanOutTask, "Fan Out: Task", "Task for each agent; "+fanOutPlaceholder+" marks each variation")
+	a.dialog.SetInputCharLimit(fanOutTaskLimit)
+	a.presentDialog(a.dialog)
+	return nil
+}
+
+// handleFanOutTask asks for the variations to fan the task out over.
+func (a *App) handleFanOutTask(task string) tea.Cmd {
+	task = strings.TrimSpace(task)
+	if task == "" || a.fanOut.project == nil {
+		return a.toast.ShowWarning("A fan-out needs a task")
+	}
+	a.fanOut.task = task
+	a.dialog = common.NewInputDialog(DialogFanOutVariations, "Fan Out: Variations",
+		"Comma-separated; empty for one per top-level directory")
+	a.dialog.SetInputCharLimit(fanOutTaskLimit)
+	a.presentDialog(a.dialog)
+	return nil
+}
+
+// handleFanOutVariations picks the agent next, reading the project's
+// directories first when no variations were typed.
+func (a *App) handleFanOutVariations(value string) tea.Cmd {
+	project := a.fanOut.project
+	if project == nil {
+		return nil
+	}
+	if strings.TrimSpace(value) == "" {
+		return func() tea.Msg {
+			dirs, err := topLevelDirs(project.Path)
+			return fanOutTargets{project: project, variations: dirs, err: err}
+		}
+	}
+	return a.chooseFanOutAgent(splitVariations(value))
+}
+
+func (a *App) handleFanOutTargets(msg fanOutTargets) tea.Cmd {
+	if msg.project != a.fanOut.project || a.fanOut.task == "" {
+		return nil
+	}
+	if msg.err != nil {
+		return common.ReportError(errorContext(errorServiceWorkspace, "listing directories"), msg.err, "")
+	}
+	return a.chooseFanOutAgent(msg.variations)
+}
+
+func (a *App) chooseFanOutAgent(variations []string) tea.Cmd {
+	switch {
+	case len(variations) == 0:
+		return a.toast.ShowWarning("No variations to fan out over")
+	case len(variations) > fanOutMaxRuns:
+		return a.toast.ShowWarning(fmt.Sprintf("%d variations is too many; fan out over at most %d", len(variations), fanOutMaxRuns))
+	}
+	a.fanOut.variations = variations
+	msg := fmt.Sprintf("Create %d workspaces in %s (%s) and start this agent in each:",
+		len(variations), a.fanOut.project.Name, strings.Join(variations, ", "))
+	names := a.assistantNames()
+	a.dialog = common.NewListDialog(DialogFanOutAgent, "Fan Out: Agent", msg, names)
+	if i := slices.Index(names, a.config.ResolvedDefaultAssistant()); i >= 0 {
+		a.dialog.SetDefaultOption(i)
+	}
+	a.presentDialog(a.dialog)
+	return nil
+}
+
+// handleFanOutAgent creates a workspace per variation and queues each for
+// review. Agents start once their workspace's setup has run.
+func (a *App) handleFanOutAgent(assistant string) tea.Cmd {
+	f := &a.fanOut
+	if f.project == nil || f.task == "" || len(f.variations) == 0 || !a.isKnownAssistant(assistant) {
+		return nil
+	}
+	taken := make(map[string]bool)
+	for _, ws := range f.project.Workspaces {
+		taken[ws.Name] = true
+	}
+	for _, run := range f.queue {
+		taken[run.name] = true
+	}
+	stem := fanOutStem(f.task)
+	var cmds []tea.Cmd
+	for _, variation := range f.variations {
+		name := uniqueName(stem+"-"+slugify(variation), taken)
+		if err := validation.ValidateWorkspaceName(name); err != nil {
+			cmds = append(cmds, a.toast.ShowWarning(fmt.Sprintf("Skipped %s: %v", variation, err)))
+			continue
+		}
+		taken[name] = true
+		run := &fanOutRun{
+			project:   f.project,
+			name:      name,
+			variation: variation,
+			assistant: assistant,
+			prompt:    fanOutPrompt(f.task, variation),
+		}
+		f.queue = append(f.queue, run)
+		create := messages.CreateWorkspace{Project: f.project, Name: name, Assistant: assistant}
+		cmds = append(cmds, func() tea.Msg { return create })
+	}
+	started := len(f.variations)
+	f.task, f.variations = "", nil
+	cmds = append(cmds, a.toast.ShowInfo(fmt.Sprintf("Fanning out to %d workspaces; prefix F shows them", started)))
+	return common.SafeBatch(cmds...)
+}
+
+// fanOutRunFor returns the run creating ws.
+func (a *App) fanOutRunFor(ws *data.Workspace) *fanOutRun {
+	if ws == nil {
+		return nil
+	}
+	for _, run := range a.fanOut.queue {
+		if run.name == ws.Name && run.project != nil && data.NormalizePath(run.project.Path) == data.NormalizePath(ws.Repo) {
+			return run
+		}
+	}
+	return nil
+}
+
+// handleFanOutSetupComplete starts the agent of a fan-out workspace whose
+// setup finished. Setup skipped for untrusted scripts runs again once they
+// are trusted, so the run waits for that.
+func (a *App) handleFanOutSetupComplete(msg messages.WorkspaceSetupComplete) tea.Cmd {
+	run := a.fanOutRunFor(msg.Workspace)
+	if run == nil || run.status != fanOutCreating {
+		return nil
+	}
+	run.ws = msg.Workspace
+	if msg.Err != nil {
+		if !errors.Is(msg.Err, process.ErrScriptsNotTrusted) {
+			run.status, run.err = fanOutFailed, "setup failed"
+		}
+		return nil
+	}
+	run.status = fanOutLaunching
+	center, ws, assistant, prompt := a.center, msg.Workspace, run.assistant, run.prompt
+	return func() tea.Msg {
+		session, err := center.StartDetachedAgent(ws, assistant, prompt)
+		return fanOutLaunched{run: run, session: session, err: err}
+	}
+}
+
+// handleFanOutCreateFailed marks a fan-out run whose workspace could not be
+// created.
+func (a *App) handleFanOutCreateFailed(msg messages.WorkspaceCreateFailed) {
+	if run := a.fanOutRunFor(msg.Workspace); run != nil && run.status == fanOutCreating {
+		run.status, run.err = fanOutFailed, "workspace not created"
+	}
+}
+
+func (a *App) handleFanOutLaunched(msg fanOutLaunched) tea.Cmd {
+	if msg.run == nil {
+		return nil
+	}
+	if msg.err != nil {
+		msg.run.status, msg.run.err = fanOutFailed, "agent did not start"
+		return common.ReportError(errorContext(errorServiceWorkspace, "starting fan-out agent"), msg.err, "")
+	}
+	msg.run.status = fanOutRunning
+	return nil
+}
+
+// fanOutPrompt fills the variation into the task.
+func fanOutPrompt(task, variation string) string {
+	if strings.Contains(task, fanOutPlaceholder) {
+		return strings.ReplaceAll(task, fanOutPlaceholder, variation)
+	}
+	return task + "\n\nApply this to: " + variation
+}
+
+func splitVariations(value string) []string {
+	var variations []string
+	for _, part := range strings.Split(value, ",") {
+		if part = strings.TrimSpace(part); part != "" && !slices.Contains(variations, part) {
+			variations = append(variations, part)
+		}
+	}
+	return variations
+}
+
+// topLevelDirs lists the directories at the top of a project, skipping
+// hidden ones.
+func topLevelDirs(root string) ([]string, error) {
+	entries, err := os.ReadDir(root)
+	if err != nil {
+		return nil, err
+	}
+	var dirs []string
+	for _, entry := range entries {
+		if entry.IsDir() && !strings.HasPrefix(entry.Name(), ".") {
+			dirs = append(dirs, entry.Name())
+		}
+	}
+	return dirs, nil
+}
+
+// fanOutStem names a fan-out's workspaces after the first words of its task.
+func fanOutStem(task string) string {
+	task = strings.ReplaceAll(task, fanOutPlaceholder, "")
+	words := strings.Fields(slugWords(task))
+	if len(words) > 3 {
+		words = words[:3]
+	}
+	if len(words) == 0 {
+		return "fanout"
+	}
+	return strings.Join(words, "-")
+}
+
+// slugify lowercases s and joins its letters and digits with dashes.
+func slugify(s string) string {
+	slug := strings.Join(strings.Fields(slugWords(s)), "-")
+	if len(slug) > 30 {
+		slug = strings.TrimRight(slug[:30], "-")
+	}
+	if slug == "" {
+		return "x"
+	}
+	return slug
+}
+
+func slugWords(s string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9':
+			return r
+		case r >= 'A' && r <= 'Z':
+			return r + 'a' - 'A'
+		}
+		return ' '
+	}, s)
+}
+
+// uniqueName returns name, or name with the lowest numeric suffix that is
+// not taken.
+func uniqueName(name string, taken map[string]bool) string {
+	if !taken[name] {
+		return name
+	}
+	for i := 2; ; i++ {
+		if candidate := name + "-" + strconv.Itoa(i); !taken[candidate] {
+			return candidate
+		}
+	}
+}
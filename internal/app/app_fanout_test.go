@@ -0,0 +1,88 @@
+package app
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/andyrewlee/amux/internal/data"
+	"github.com/andyrewlee/amux/internal/messages"
+)
+
+func TestFanOutFlow(t *testing.T) {
+	h := newDialogHarness(t)
+	root := t.TempDir()
+	for _, dir := range []string{"api", "web", ".git"} {
+		if err := os.Mkdir(filepath.Join(root, dir), 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	project := &data.Project{Name: "shop", Path: root, Workspaces: []data.Workspace{{Name: "add-tests-for-web", Repo: root}}}
+
+	h.app.showFanOut(project)
+	if view := dialogView(t, h.app.dialog); !strings.Contains(view, "Fan Out: Task") {
+		t.Fatalf("dialog = %q, want the task prompt", view)
+	}
+	h.app.handleFanOutTask("Add tests for {variation}")
+	targets, ok := h.app.handleFanOutVariations("")().(fanOutTargets)
+	if !ok || strings.Join(targets.variations, ",") != "api,web" {
+		t.Fatalf("targets = %+v, want the visible top-level directories", targets)
+	}
+	h.app.handleFanOutTargets(targets)
+	if view := dialogView(t, h.app.dialog); !strings.Contains(view, "Create 2 workspaces in shop (api, web)") {
+		t.Fatalf("agent dialog = %q", view)
+	}
+
+	if cmd := h.app.handleFanOutAgent("claude"); cmd == nil {
+		t.Fatal("expected workspace creation")
+	}
+	queue := h.app.fanOut.queue
+	if len(queue) != 2 || queue[0].name != "add-tests-for-api" || queue[1].name != "add-tests-for-web-2" {
+		t.Fatalf("queue = %+v, want one run per directory with unique names", queue)
+	}
+	if queue[0].prompt != "Add tests for api" {
+		t.Fatalf("prompt = %q", queue[0].prompt)
+	}
+
+	created := &data.Workspace{Name: "add-tests-for-api", Repo: root, Root: filepath.Join(root, "ws")}
+	if cmd := h.app.handleFanOutSetupComplete(messages.WorkspaceSetupComplete{Workspace: created}); cmd == nil || queue[0].status != fanOutLaunching {
+		t.Fatal("expected the agent to start once setup finished")
+	}
+	h.app.handleFanOutLaunched(fanOutLaunched{run: queue[0], session: "amux-x"})
+	h.app.handleFanOutCreateFailed(messages.WorkspaceCreateFailed{
+		Workspace: &data.Workspace{Name: "add-tests-for-web-2", Repo: root},
+		Err:       errors.New("branch exists"),
+	})
+
+	h.app.showFanOut(project)
+	view := dialogView(t, h.app.dialog)
+	for _, want := range []string{"Fan-out Review", "add-tests-for-api  api  [claude running]", "add-tests-for-web-2  web  [failed: workspace not created]"} {
+		if !strings.Contains(view, want) {
+			t.Fatalf("queue dialog missing %q, got %q", want, view)
+		}
+	}
+	h.app.handleFanOutQueueChoice(2)
+	if len(h.app.fanOut.queue) != 1 {
+		t.Fatalf("queue = %+v, want the failed run removed", h.app.fanOut.queue)
+	}
+}
+
+func TestFanOutNaming(t *testing.T) {
+	if got := fanOutPrompt("Upgrade the SDK", "billing"); got != "Upgrade the SDK\n\nApply this to: billing" {
+		t.Fatalf("fanOutPrompt() = %q", got)
+	}
+	if got := fanOutStem("Port {variation} to the new HTTP client"); got != "port-to-the" {
+		t.Fatalf("fanOutStem() = %q", got)
+	}
+	if got := slugify("Dark Mode (v2)"); got != "dark-mode-v2" {
+		t.Fatalf("slugify() = %q", got)
+	}
+	if got := splitVariations(" a, b ,,a"); strings.Join(got, "|") != "a|b" {
+		t.Fatalf("splitVariations() = %q", got)
+	}
+	if got := uniqueName("x", map[string]bool{"x": true, "x-2": true}); got != "x-3" {
+		t.Fatalf("uniqueName() = %q", got)
+	}
+}
@@ -0,0 +1,91 @@
+package app
+
+import (
+	"sort"
+
+	tea "charm.land/bubbletea/v2"
+
+	"github.com/andyrewlee/amux/internal/data"
+	"github.com/andyrewlee/amux/internal/messages"
+	"github.com/andyrewlee/amux/internal/ui/common"
+)
+
+// orderFavorites marks the favorite projects and moves them, and each
+// project's favorite workspaces, ahead of the rest. Everything else keeps
+// its order, so the dashboard and the pickers built from a.projects list
+// favorites first.
+func orderFavorites(projects []data.Project, favorites map[string]bool) {
+	for i := range projects {
+		project := &projects[i]
+		project.Favorite = favorites[project.Path]
+		sort.SliceStable(project.Workspaces, func(a, b int) bool {
+			return project.Workspaces[a].Favorite && !project.Workspaces[b].Favorite
+		})
+	}
+	sort.SliceStable(projects, func(i, j int) bool {
+		return projects[i].Favorite && !projects[j].Favorite
+	})
+}
+
+// favoriteSaved reports saving a favorite mark toggled by toggleFavorite.
+type favoriteSaved struct {
+	workspace *data.Workspace
+	project   *data.Project
+	name      string
+	favorite  bool
+	err       error
+}
+
+// toggleFavorite marks or unmarks a workspace, or a project when the message
+// has no workspace, as a favorite, saving the mark off the update loop.
+func (a *App) toggleFavorite(msg messages.ToggleFavorite) tea.Cmd {
+	if a.workspaceService == nil {
+		return nil
+	}
+	switch {
+	case msg.Workspace != nil:
+		store := a.workspaceService.store
+		if store == nil {
+			return nil
+		}
+		ws := msg.Workspace
+		saved := favoriteSaved{workspace: ws, name: ws.Name, favorite: !ws.Favorite}
+		id := ws.ID()
+		return func() tea.Msg {
+			saved.err = store.SetFavorite(id, saved.favorite)
+			return saved
+		}
+	case msg.Project != nil:
+		registry := a.workspaceService.registry
+		if registry == nil {
+			return nil
+		}
+		project := msg.Project
+		saved := favoriteSaved{project: project, name: project.Name, favorite: !project.Favorite}
+		path := project.Path
+		return func() tea.Msg {
+			saved.err = registry.SetFavorite(path, saved.favorite)
+			return saved
+		}
+	}
+	return nil
+}
+
+// handleFavoriteSaved applies a saved favorite mark and reloads the projects
+// to reorder them.
+func (a *App) handleFavoriteSaved(msg favoriteSaved) tea.Cmd {
+	if msg.err != nil {
+		return common.ReportError(errorContext(errorServiceWorkspace, "saving favorite"), msg.err, "")
+	}
+	if msg.workspace != nil {
+		msg.workspace.Favorite = msg.favorite
+	}
+	if msg.project != nil {
+		msg.project.Favorite = msg.favorite
+	}
+	text := msg.name + " is no longer a favorite"
+	if msg.favorite {
+		text = common.Icons.Favorite + " " + msg.name + " is pinned to the top"
+	}
+	return common.SafeBatch(a.toast.ShowInfo(text), a.loadProjects())
+}
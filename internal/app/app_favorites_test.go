@@ -0,0 +1,83 @@
+package app
+
+import (
+	"path/filepath"
+	"testing"
+
+	tea "charm.land/bubbletea/v2"
+
+	"github.com/andyrewlee/amux/internal/data"
+	"github.com/andyrewlee/amux/internal/messages"
+	"github.com/andyrewlee/amux/internal/testutil"
+)
+
+func TestOrderFavorites(t *testing.T) {
+	projects := []data.Project{
+		{Name: "api", Path: "/repo/api", Workspaces: []data.Workspace{{Name: "one"}, {Name: "two", Favorite: true}, {Name: "three"}}},
+		{Name: "web", Path: "/repo/web"},
+		{Name: "docs", Path: "/repo/docs"},
+	}
+	orderFavorites(projects, map[string]bool{"/repo/docs": true})
+	if projects[0].Name != "docs" || !projects[0].Favorite || projects[1].Name != "api" || projects[2].Name != "web" {
+		t.Fatalf("projects = %s, %s, %s; want the favorite first", projects[0].Name, projects[1].Name, projects[2].Name)
+	}
+	if ws := projects[1].Workspaces; ws[0].Name != "two" || ws[1].Name != "one" || ws[2].Name != "three" {
+		t.Fatalf("workspaces = %s, %s, %s; want the favorite first", ws[0].Name, ws[1].Name, ws[2].Name)
+	}
+}
+
+func TestToggleFavorite(t *testing.T) {
+	h := newDialogHarness(t)
+	repo := testutil.InitRepo(t)
+	registry := data.NewRegistry(filepath.Join(t.TempDir(), "projects.json"))
+	if err := registry.AddProject(repo); err != nil {
+		t.Fatalf("AddProject() error = %v", err)
+	}
+	store := data.NewWorkspaceStore(t.TempDir())
+	ws := data.NewWorkspace("feature", "feature", "main", repo, filepath.Join(t.TempDir(), "feature"))
+	if err := store.Save(ws); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	h.app.workspaceService = newWorkspaceService(registry, store, nil, "")
+	toggle := func(msg messages.ToggleFavorite) tea.Cmd {
+		t.Helper()
+		cmd := h.app.toggleFavorite(msg)
+		if cmd == nil {
+			t.Fatal("expected the favorite to be saved")
+		}
+		saved, ok := cmd().(favoriteSaved)
+		if !ok || saved.err != nil {
+			t.Fatalf("save = %+v", saved)
+		}
+		return h.app.handleFavoriteSaved(saved)
+	}
+
+	if toggle(messages.ToggleFavorite{Workspace: ws}) == nil || !ws.Favorite {
+		t.Fatal("expected the workspace to become a favorite")
+	}
+	if stored, err := store.Load(ws.ID()); err != nil || !stored.Favorite {
+		t.Fatalf("stored workspace = %+v, %v; want a favorite", stored, err)
+	}
+	project := data.NewProject(repo)
+	toggle(messages.ToggleFavorite{Project: project})
+	if favorites, err := registry.Favorites(); err != nil || !favorites[repo] {
+		t.Fatalf("Favorites() = %v, %v; want the project", favorites, err)
+	}
+
+	toggle(messages.ToggleFavorite{Project: project})
+	toggle(messages.ToggleFavorite{Workspace: ws})
+	if stored, _ := store.Load(ws.ID()); stored.Favorite {
+		t.Fatal("toggling again should unmark the workspace")
+	}
+	if favorites, _ := registry.Favorites(); favorites[repo] {
+		t.Fatal("toggling again should unmark the project")
+	}
+}
+
+func TestToggleFavoriteRefusedReadOnly(t *testing.T) {
+	h := newDialogHarness(t)
+	h.app.SetReadOnly("amux (pid 1)")
+	if _, refused := h.app.refuseReadOnly(messages.ToggleFavorite{Workspace: &data.Workspace{}}); !refused {
+		t.Fatal("a read-only view should not change favorites")
+	}
+}
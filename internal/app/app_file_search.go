@@ -0,0 +1,228 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	tea "charm.land/bubbletea/v2"
+
+	"github.com/andyrewlee/amux/internal/data"
+	"github.com/andyrewlee/amux/internal/messages"
+	"github.com/andyrewlee/amux/internal/search"
+	"github.com/andyrewlee/amux/internal/ui/common"
+)
+
+// fileSearchDebounce is how long the query must stay unchanged before
+// ripgrep runs, so typing a word runs one search rather than one per key.
+const fileSearchDebounce = 150 * time.Millisecond
+
+// fileSearchTimeout bounds one ripgrep run.
+const fileSearchTimeout = 30 * time.Second
+
+// maxFileSearchResults bounds how many matches the search panel lists.
+const maxFileSearchResults = 500
+
+// fileSearchState holds the search panel's workspace and the matches it
+// lists. seq numbers each query so results for an older one are dropped.
+type fileSearchState struct {
+	workspace *data.Workspace
+	seq       int
+	cancel    context.CancelFunc
+	matches   []search.Match
+}
+
+// fileSearchDue fires once a query has rested for fileSearchDebounce.
+type fileSearchDue struct {
+	seq   int
+	query string
+}
+
+// fileSearchLoaded carries what ripgrep found for a query.
+type fileSearchLoaded struct {
+	seq    int
+	result search.Result
+	err    error
+}
+
+// showFileSearch opens the search panel over the active workspace.
+func (a *App) showFileSearch() tea.Cmd {
+	ws := a.activeWorkspace
+	if ws == nil {
+		return nil
+	}
+	a.stopFileSearch()
+	a.fileSearch = fileSearchState{workspace: ws}
+	a.searchPanel = common.NewSearchPanel(SearchPanelFiles, "Search Files", "Text to find in "+ws.Name+"...")
+	a.searchPanel.SetSize(a.width, a.height)
+	a.searchPanel.SetStatus("Type to search the worktree")
+	a.searchPanel.Show()
+	return nil
+}
+
+// searchPanelOpen reports whether the search panel with id is open.
+func (a *App) searchPanelOpen(id string) bool {
+	return a.searchPanel != nil && a.searchPanel.ID() == id
+}
+
+// stopFileSearch cancels a running search and drops any still to come.
+func (a *App) stopFileSearch() {
+	if a.fileSearch.cancel != nil {
+		a.fileSearch.cancel()
+		a.fileSearch.cancel = nil
+	}
+	a.fileSearch.seq++
+}
+
+// handleSearchQueryChanged hands an edited query to the open search panel's
+// feature.
+func (a *App) handleSearchQueryChanged(msg common.SearchQueryChanged) tea.Cmd {
+	if a.searchPanel == nil || a.searchPanel.ID() != msg.ID {
+		return nil
+	}
+	switch msg.ID {
+	case SearchPanelFiles:
+		return a.searchFiles(msg.Query)
+	case SearchPanelErrors:
+		a.filterRecentErrors(msg.Query)
+	}
+	return nil
+}
+
+// handleSearchPanelResult closes the search panel and acts on its result.
+func (a *App) handleSearchPanelResult(res common.SearchPanelResult) tea.Cmd {
+	panel := a.searchPanel
+	if panel == nil || panel.ID() != res.ID {
+		return nil
+	}
+	a.searchPanel = nil
+	switch res.ID {
+	case SearchPanelFiles:
+		return a.handleFileSearchResult(panel.Query(), res)
+	case SearchPanelErrors:
+		return a.handleRecentErrorsResult(res)
+	}
+	return nil
+}
+
+// searchFiles schedules a search for the edited query.
+func (a *App) searchFiles(query string) tea.Cmd {
+	a.stopFileSearch()
+	if strings.TrimSpace(query) == "" {
+		a.fileSearch.matches = nil
+		a.searchPanel.SetResults(nil, "Type to search the worktree")
+		return nil
+	}
+	a.searchPanel.SetStatus("Searching...")
+	seq := a.fileSearch.seq
+	return common.SafeTick(fileSearchDebounce, func(time.Time) tea.Msg {
+		return fileSearchDue{seq: seq, query: query}
+	})
+}
+
+// handleFileSearchDue runs ripgrep for a query that is still current.
+func (a *App) handleFileSearchDue(msg fileSearchDue) tea.Cmd {
+	ws := a.fileSearch.workspace
+	if !a.searchPanelOpen(SearchPanelFiles) || ws == nil || msg.seq != a.fileSearch.seq {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), fileSearchTimeout)
+	a.fileSearch.cancel = cancel
+	root := ws.Root
+	return func() tea.Msg {
+		defer cancel()
+		result, err := search.Run(ctx, root, msg.query, maxFileSearchResults)
+		return fileSearchLoaded{seq: msg.seq, result: result, err: err}
+	}
+}
+
+// handleFileSearchLoaded lists a search's matches in the panel.
+func (a *App) handleFileSearchLoaded(msg fileSearchLoaded) tea.Cmd {
+	if !a.searchPanelOpen(SearchPanelFiles) || msg.seq != a.fileSearch.seq {
+		return nil
+	}
+	a.fileSearch.cancel = nil
+	if msg.err != nil {
+		if errors.Is(msg.err, context.Canceled) {
+			return nil
+		}
+		a.fileSearch.matches = nil
+		a.searchPanel.SetResults(nil, msg.err.Error())
+		return nil
+	}
+	matches := msg.result.Matches
+	a.fileSearch.matches = matches
+	rows := make([]common.SearchPanelMatch, len(matches))
+	for i, m := range matches {
+		rows[i] = fileSearchRow(m)
+	}
+	a.searchPanel.SetResults(rows, fileSearchStatus(msg.result))
+	return nil
+}
+
+// handleFileSearchResult opens the selected match in an editor tab or sends
+// every match to the workspace's agent.
+func (a *App) handleFileSearchResult(query string, res common.SearchPanelResult) tea.Cmd {
+	ws := a.fileSearch.workspace
+	matches := a.fileSearch.matches
+	a.stopFileSearch()
+	a.fileSearch = fileSearchState{seq: a.fileSearch.seq}
+	if ws == nil {
+		return nil
+	}
+	switch res.Action {
+	case common.SearchPanelOpen:
+		if res.Index < 0 || res.Index >= len(matches) {
+			return nil
+		}
+		m := matches[res.Index]
+		open := messages.OpenFileInVim{Path: filepath.Join(ws.Root, m.Path), Workspace: ws, Line: m.Line}
+		return common.SafeBatch(func() tea.Msg { return open }, a.focusPane(messages.PaneCenter))
+	case common.SearchPanelSend:
+		if len(matches) == 0 {
+			return nil
+		}
+		return a.sendToWorkspaceAgent(ws, fileSearchPrompt(query, matches))
+	}
+	return nil
+}
+
+// fileSearchRow is a match's row in the search panel: its location and
+// line, with the lines around it as the preview.
+func fileSearchRow(m search.Match) common.SearchPanelMatch {
+	row := common.SearchPanelMatch{
+		Label: fmt.Sprintf("%s:%d  %s", m.Path, m.Line, strings.TrimSpace(m.Text)),
+	}
+	for _, l := range m.Context {
+		row.Preview = append(row.Preview, common.SearchPreviewLine{Number: l.Number, Text: l.Text, Match: l.Number == m.Line})
+	}
+	return row
+}
+
+// fileSearchStatus describes a search's results.
+func fileSearchStatus(result search.Result) string {
+	switch n := len(result.Matches); {
+	case n == 0:
+		return "No matches"
+	case result.Truncated:
+		return fmt.Sprintf("Showing the first %d matches", n)
+	case n == 1:
+		return "1 match"
+	default:
+		return fmt.Sprintf("%d matches", n)
+	}
+}
+
+// fileSearchPrompt lists matches for an agent, one "path:line: text" per
+// line under the query they matched.
+func fileSearchPrompt(query string, matches []search.Match) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Search results for %q in this worktree:\n", query)
+	for _, m := range matches {
+		fmt.Fprintf(&b, "%s:%d: %s\n", m.Path, m.Line, strings.TrimSpace(m.Text))
+	}
+	return b.String()
+}
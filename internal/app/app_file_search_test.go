@@ -0,0 +1,88 @@
+package app
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/andyrewlee/amux/internal/search"
+	"github.com/andyrewlee/amux/internal/ui/common"
+)
+
+func TestFileSearchListsAndOpensMatches(t *testing.T) {
+	h := newDialogHarness(t)
+	ws := harnessWorkspace()
+	h.app.activeWorkspace = ws
+	h.app.showFileSearch()
+	if h.app.searchPanel == nil || !h.app.overlayVisible() {
+		t.Fatal("the search panel should be open")
+	}
+
+	if cmd := h.app.handleSearchQueryChanged(common.SearchQueryChanged{ID: SearchPanelFiles, Query: "Add"}); cmd == nil {
+		t.Fatal("editing the query should schedule a search")
+	}
+	stale := h.app.fileSearch.seq
+	h.app.handleSearchQueryChanged(common.SearchQueryChanged{ID: SearchPanelFiles, Query: "Adder"})
+	if cmd := h.app.handleFileSearchDue(fileSearchDue{seq: stale, query: "Add"}); cmd != nil {
+		t.Fatal("a superseded query should not run")
+	}
+
+	result := search.Result{Matches: []search.Match{
+		{Path: "calc/add.go", Line: 5, Text: "\tfunc Adder() {}", Context: []search.Line{{Number: 4, Text: ""}, {Number: 5, Text: "\tfunc Adder() {}"}}},
+		{Path: "main.go", Line: 12, Text: "calc.Adder()"},
+	}}
+	h.app.handleFileSearchLoaded(fileSearchLoaded{seq: stale, result: search.Result{}})
+	if h.app.fileSearch.matches != nil {
+		t.Fatal("results for a superseded query should be dropped")
+	}
+	h.app.handleFileSearchLoaded(fileSearchLoaded{seq: h.app.fileSearch.seq, result: result})
+	view := h.app.searchPanel.View()
+	for _, want := range []string{"calc/add.go:5  func Adder() {}", "main.go:12", "2 matches"} {
+		if !strings.Contains(view, want) {
+			t.Fatalf("search panel missing %q, got %q", want, view)
+		}
+	}
+
+	open := findOpenFile(t, h.app.handleSearchPanelResult(common.SearchPanelResult{ID: SearchPanelFiles, Action: common.SearchPanelOpen, Index: 1}))
+	if open.Path != "/repo/primary/ws/main.go" || open.Line != 12 || open.Workspace != ws {
+		t.Fatalf("open = %+v, want main.go at line 12", open)
+	}
+	if h.app.searchPanel != nil || h.app.fileSearch.matches != nil {
+		t.Fatal("opening a match should close the panel")
+	}
+}
+
+func TestFileSearchStatus(t *testing.T) {
+	h := newDialogHarness(t)
+	h.app.activeWorkspace = harnessWorkspace()
+	h.app.showFileSearch()
+	h.app.handleFileSearchLoaded(fileSearchLoaded{seq: h.app.fileSearch.seq, err: search.ErrNoRipgrep})
+	if view := h.app.searchPanel.View(); !strings.Contains(view, "ripgrep (rg) is not installed") {
+		t.Fatalf("missing rg should be reported, got %q", view)
+	}
+	h.app.handleFileSearchLoaded(fileSearchLoaded{seq: h.app.fileSearch.seq, err: errors.New("rg: bad pattern")})
+	if view := h.app.searchPanel.View(); !strings.Contains(view, "rg: bad pattern") {
+		t.Fatalf("a failed search should be reported, got %q", view)
+	}
+
+	for _, tc := range []struct {
+		result search.Result
+		want   string
+	}{
+		{search.Result{}, "No matches"},
+		{search.Result{Matches: make([]search.Match, 1)}, "1 match"},
+		{search.Result{Matches: make([]search.Match, 3), Truncated: true}, "Showing the first 3 matches"},
+	} {
+		if got := fileSearchStatus(tc.result); got != tc.want {
+			t.Fatalf("fileSearchStatus() = %q, want %q", got, tc.want)
+		}
+	}
+}
+
+func TestFileSearchPrompt(t *testing.T) {
+	got := fileSearchPrompt("Adder", []search.Match{{Path: "calc/add.go", Line: 5, Text: "\tfunc Adder() {}"}})
+	want := "Search results for \"Adder\" in this worktree:\ncalc/add.go:5: func Adder() {}\n"
+	if got != want {
+		t.Fatalf("fileSearchPrompt() = %q, want %q", got, want)
+	}
+}
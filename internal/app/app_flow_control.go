@@ -0,0 +1,18 @@
+package app
+
+import (
+	"github.com/andyrewlee/amux/internal/config"
+	"github.com/andyrewlee/amux/internal/ui/ptyio"
+)
+
+// applyFlowControlFromConfig installs the configured PTY flow-control
+// watermarks. Unset values fall back to the ptyio defaults.
+func applyFlowControlFromConfig(cfg *config.Config) {
+	if cfg == nil {
+		return
+	}
+	ptyio.SetFlowThresholds(ptyio.FlowThresholds{
+		PauseBytes:  cfg.UI.OutputPauseBytes,
+		ResumeBytes: cfg.UI.OutputResumeBytes,
+	})
+}
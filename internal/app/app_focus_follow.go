@@ -0,0 +1,145 @@
+package app
+
+import (
+	"fmt"
+	"time"
+
+	tea "charm.land/bubbletea/v2"
+
+	"github.com/andyrewlee/amux/internal/messages"
+	"github.com/andyrewlee/amux/internal/ui/common"
+	"github.com/andyrewlee/amux/internal/ui/dashboard"
+)
+
+// focusFollowIdle is how long the dashboard must go without input before
+// focus follows an agent that needs attention.
+const focusFollowIdle = 10 * time.Second
+
+// focusFollowState tracks which workspaces needed attention last time, and
+// since when, and when the user last gave input.
+type focusFollowState struct {
+	attention   map[string]time.Time
+	lastInputAt time.Time
+	// queue is the attention queue last told to listener
+	// (app_attention.go).
+	queue     []Attention
+	published bool
+	listener  func([]Attention)
+}
+
+// focusFollowRequest asks Update to move focus to a workspace that started
+// needing attention.
+type focusFollowRequest struct {
+	workspaceID string
+}
+
+// noteUserInput records input for the focus-follows idle check.
+func (a *App) noteUserInput(msg tea.Msg) {
+	switch msg.(type) {
+	case tea.KeyPressMsg, tea.MouseClickMsg, tea.MouseWheelMsg, tea.PasteMsg:
+		a.focusFollow.lastInputAt = time.Now()
+	}
+}
+
+// followAttention requests a jump when exactly one workspace needs attention
+// and it only just started to. The jump itself is checked and made in
+// Update, since this runs from state-sync helpers.
+func (a *App) followAttention() {
+	if a.dashboard == nil {
+		return
+	}
+	prev := a.focusFollow.attention
+	current := make(map[string]time.Time)
+	fresh := ""
+	now := time.Now()
+	for _, entry := range a.dashboard.Workspaces() {
+		wsID := string(entry.Workspace.ID())
+		if !a.dashboard.NeedsAttention(wsID) {
+			continue
+		}
+		since, ok := prev[wsID]
+		if !ok {
+			since = now
+			fresh = wsID
+		}
+		current[wsID] = since
+	}
+	a.focusFollow.attention = current
+	a.publishAttention()
+	if fresh == "" || len(current) != 1 || !a.focusFollowsEnabled() {
+		return
+	}
+	a.enqueueExternalMsg(focusFollowRequest{workspaceID: fresh})
+}
+
+func (a *App) focusFollowsEnabled() bool {
+	return a.config != nil && a.config.UI.FocusFollowsAttention
+}
+
+// dashboardIdle reports whether the user is sitting on the dashboard: it has
+// focus, nothing is open over it, and no input arrived for focusFollowIdle.
+func (a *App) dashboardIdle(now time.Time) bool {
+	return a.focusedPane == messages.PaneDashboard &&
+		!a.overlayVisible() &&
+		now.Sub(a.focusFollow.lastInputAt) >= focusFollowIdle
+}
+
+// handleFocusFollow shows the tab of the workspace that needs attention,
+// leaving an undo entry that returns to the dashboard.
+func (a *App) handleFocusFollow(msg focusFollowRequest) tea.Cmd {
+	if !a.focusFollowsEnabled() || !a.dashboardIdle(time.Now()) {
+		return nil
+	}
+	if !a.dashboard.NeedsAttention(msg.workspaceID) {
+		return nil
+	}
+	ref, ok := a.workspaceTab(msg.workspaceID)
+	if !ok {
+		return nil
+	}
+	entry := undoEntry{kind: undoFocusJump}
+	if a.activeWorkspace != nil {
+		entry.workspaceID = string(a.activeWorkspace.ID())
+	}
+	a.undo.push(entry)
+	return common.SafeBatch(
+		a.showAgentTab(ref),
+		a.toast.ShowInfo(fmt.Sprintf("%s needs attention: jumped to it (%s t u to go back)",
+			ref.entry.Workspace.Name, a.prefixLabel())),
+	)
+}
+
+// workspaceTab finds the tab the workspace shows when it is active.
+func (a *App) workspaceTab(wsID string) (agentTabRef, bool) {
+	refs, _ := a.agentTabOrder()
+	for _, ref := range refs {
+		if ref.workspaceID() != wsID {
+			continue
+		}
+		if _, active := a.center.WorkspaceTabCount(wsID); active == ref.index {
+			return ref, true
+		}
+	}
+	return agentTabRef{}, false
+}
+
+// undoFocusJump returns to the dashboard, and to the workspace that was
+// active before focus followed an agent.
+func (a *App) undoFocusJump(workspaceID string) tea.Cmd {
+	focusDashboard := func() tea.Msg { return messages.FocusPane{Pane: messages.PaneDashboard} }
+	if workspaceID == "" || (a.activeWorkspace != nil && string(a.activeWorkspace.ID()) == workspaceID) {
+		return focusDashboard
+	}
+	var entry *dashboard.WorkspaceEntry
+	for _, e := range a.dashboard.Workspaces() {
+		if string(e.Workspace.ID()) == workspaceID {
+			entry = &e
+			break
+		}
+	}
+	if entry == nil {
+		return focusDashboard
+	}
+	activated := messages.WorkspaceActivated{Project: entry.Project, Workspace: entry.Workspace}
+	return tea.Sequence(func() tea.Msg { return activated }, focusDashboard)
+}
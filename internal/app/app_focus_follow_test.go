@@ -0,0 +1,67 @@
+package app
+
+import (
+	"testing"
+	"time"
+
+	tea "charm.land/bubbletea/v2"
+
+	"github.com/andyrewlee/amux/internal/app/activity"
+	"github.com/andyrewlee/amux/internal/config"
+	"github.com/andyrewlee/amux/internal/messages"
+	"github.com/andyrewlee/amux/internal/ui/common"
+)
+
+func TestFocusFollowsSingleAttention(t *testing.T) {
+	app, mainWS, featWS := newAgentCycleTestApp(t)
+	app.config = &config.Config{UI: config.UISettings{FocusFollowsAttention: true}}
+	app.toast = common.NewToastModel()
+	app.externalMsgs = make(chan tea.Msg, 4)
+	app.focusedPane = messages.PaneDashboard
+	featID, mainID := string(featWS.ID()), string(mainWS.ID())
+
+	app.dashboard.SetAgentStates(map[string]activity.AgentState{featID: activity.StateWorking})
+	app.followAttention()
+	app.dashboard.SetAgentStates(map[string]activity.AgentState{featID: activity.StateDone})
+	app.followAttention()
+	if len(app.externalMsgs) != 1 {
+		t.Fatalf("queued %d requests, want 1 when one workspace starts needing attention", len(app.externalMsgs))
+	}
+	req := (<-app.externalMsgs).(focusFollowRequest)
+
+	// Recent input means the user is not idle.
+	app.focusFollow.lastInputAt = time.Now()
+	if cmd := app.handleFocusFollow(req); cmd != nil {
+		t.Fatal("focus should not move while the user is active")
+	}
+
+	app.focusFollow.lastInputAt = time.Now().Add(-focusFollowIdle)
+	if cmd := app.handleFocusFollow(req); cmd == nil {
+		t.Fatal("expected focus to follow the workspace needing attention")
+	}
+	if len(app.undo.entries) != 1 || app.undo.entries[0].kind != undoFocusJump || app.undo.entries[0].workspaceID != mainID {
+		t.Fatalf("undo entries = %+v, want a focus jump back to main", app.undo.entries)
+	}
+
+	// A second workspace needing attention at once is ambiguous.
+	app.dashboard.SetAgentStates(map[string]activity.AgentState{featID: activity.StateWorking, mainID: activity.StateWorking})
+	app.followAttention()
+	app.dashboard.SetAgentStates(map[string]activity.AgentState{featID: activity.StateDone, mainID: activity.StateDone})
+	app.followAttention()
+	if len(app.externalMsgs) != 0 {
+		t.Fatal("two workspaces needing attention should not move focus")
+	}
+}
+
+func TestUndoFocusJumpReturnsToDashboard(t *testing.T) {
+	app, mainWS, featWS := newAgentCycleTestApp(t)
+	app.activeWorkspace = featWS
+
+	cmd := app.undoFocusJump(string(mainWS.ID()))
+	if cmd == nil {
+		t.Fatal("expected a command returning to main")
+	}
+	if got := app.undoFocusJump(string(featWS.ID()))(); got != (messages.FocusPane{Pane: messages.PaneDashboard}) {
+		t.Fatalf("undo within the active workspace = %#v, want a dashboard focus", got)
+	}
+}
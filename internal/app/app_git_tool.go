@@ -0,0 +1,45 @@
+package app
+
+import (
+	"errors"
+
+	tea "charm.land/bubbletea/v2"
+
+	"github.com/andyrewlee/amux/internal/messages"
+	"github.com/andyrewlee/amux/internal/ui/common"
+	"github.com/andyrewlee/amux/internal/ui/sidebar"
+)
+
+// toggleGitTool swaps the sidebar's changes list for the git tool embedded
+// in the sidebar terminal, starting it in the active workspace, and back.
+func (a *App) toggleGitTool() tea.Cmd {
+	if a.focusedPane == messages.PaneSidebarTerminal && a.sidebarTerminal.GitToolActive() {
+		return a.showChangesList()
+	}
+	if a.layout == nil || !a.layout.ShowSidebar() {
+		return a.toast.ShowWarning("Widen the window to show the sidebar for " + sidebar.GitTool)
+	}
+	if !a.tmuxAvailable {
+		return common.ReportError("opening "+sidebar.GitTool, errors.New("tmux not available"), "tmux required to create tabs. "+a.tmuxInstallHint)
+	}
+	cmd := a.sidebarTerminal.LaunchGitTool(a.activeWorkspace)
+	// Focus without focusPane's lazy shell tab, which would race the git
+	// tool's tab to become active.
+	a.setFocusedPane(messages.PaneSidebarTerminal)
+	return cmd
+}
+
+// showChangesList focuses the sidebar's changes list.
+func (a *App) showChangesList() tea.Cmd {
+	a.sidebar.SetActiveTab(sidebar.TabChanges)
+	return a.focusPane(messages.PaneSidebar)
+}
+
+// handleGitToolExited goes back to the changes list when the git tool the
+// user was in is quit.
+func (a *App) handleGitToolExited(msg sidebar.GitToolExited) tea.Cmd {
+	if a.activeWorkspace == nil || string(a.activeWorkspace.ID()) != msg.WorkspaceID || a.focusedPane != messages.PaneSidebarTerminal {
+		return nil
+	}
+	return a.showChangesList()
+}
@@ -0,0 +1,48 @@
+package app
+
+import (
+	"testing"
+
+	"github.com/andyrewlee/amux/internal/data"
+	"github.com/andyrewlee/amux/internal/messages"
+	"github.com/andyrewlee/amux/internal/ui/sidebar"
+)
+
+func TestToggleGitTool(t *testing.T) {
+	h, err := NewHarness(HarnessOptions{Mode: HarnessCenter, Width: 240, Height: 50, Tabs: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ws := harnessWorkspace()
+	h.app.activeWorkspace = ws
+	h.app.activeProject = &data.Project{Name: "primary", Path: ws.Repo}
+	h.app.tmuxAvailable = true
+	if !h.app.layout.ShowSidebar() {
+		t.Fatal("expected the layout to show the sidebar")
+	}
+
+	if cmd := h.app.toggleGitTool(); cmd == nil || h.app.focusedPane != messages.PaneSidebarTerminal {
+		t.Fatalf("focused pane = %v, want the sidebar terminal starting the git tool", h.app.focusedPane)
+	}
+	h.app.sidebarTerminal.Update(sidebar.SidebarTerminalCreated{WorkspaceID: string(ws.ID()), TabID: "git", GitTool: true})
+	if !h.app.sidebarTerminal.GitToolActive() {
+		t.Fatal("expected the git tool's tab to be active")
+	}
+
+	// Toggling again goes back to the changes list, leaving the tool running.
+	h.app.toggleGitTool()
+	if h.app.focusedPane != messages.PaneSidebar || !h.app.sidebarTerminal.GitToolActive() {
+		t.Fatalf("focused pane = %v, want the changes list", h.app.focusedPane)
+	}
+
+	// Quitting the tool while in it goes back to the changes list too.
+	h.app.setFocusedPane(messages.PaneSidebarTerminal)
+	h.app.handleGitToolExited(sidebar.GitToolExited{WorkspaceID: "other"})
+	if h.app.focusedPane != messages.PaneSidebarTerminal {
+		t.Fatal("another workspace's git tool exiting should not move focus")
+	}
+	h.app.handleGitToolExited(sidebar.GitToolExited{WorkspaceID: string(ws.ID())})
+	if h.app.focusedPane != messages.PaneSidebar {
+		t.Fatalf("focused pane = %v, want the changes list", h.app.focusedPane)
+	}
+}
@@ -0,0 +1,172 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	tea "charm.land/bubbletea/v2"
+
+	"github.com/andyrewlee/amux/internal/data"
+	"github.com/andyrewlee/amux/internal/gpu"
+	"github.com/andyrewlee/amux/internal/logging"
+	"github.com/andyrewlee/amux/internal/ui/common"
+)
+
+// gpuSampleTimeout bounds one nvidia-smi and ps sample.
+const gpuSampleTimeout = 15 * time.Second
+
+// Seams for tests, which have no GPUs.
+var (
+	gpuAvailable = gpu.Available
+	sampleGPUs   = gpu.Sample
+	gpuParents   = gpu.Parents
+)
+
+// gpuAgent is the agent session a GPU process belongs to.
+type gpuAgent struct {
+	workspaceID string
+	assistant   string
+}
+
+// gpuSampled carries a GPU sample, with the agent behind each compute
+// process that amux started.
+type gpuSampled struct {
+	workspace *data.Workspace
+	devices   []gpu.Device
+	agents    map[int]gpuAgent
+	err       error
+}
+
+// showGPUs samples the GPUs off the UI goroutine for the GPU panel.
+func (a *App) showGPUs(ws *data.Workspace) tea.Cmd {
+	if !gpuAvailable() {
+		return a.toast.ShowWarning(gpu.ErrUnavailable.Error())
+	}
+	svc, opts, tmuxOK := a.tmuxService, a.tmuxOptions, a.tmuxAvailable
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), gpuSampleTimeout)
+		defer cancel()
+		devices, err := sampleGPUs(ctx)
+		if err != nil {
+			return gpuSampled{workspace: ws, err: err}
+		}
+		msg := gpuSampled{workspace: ws, devices: devices, agents: map[int]gpuAgent{}}
+		if svc == nil || !tmuxOK {
+			return msg
+		}
+		parents, err := gpuParents(ctx)
+		if err != nil {
+			logging.Debug("gpu: %v", err)
+			return msg
+		}
+		rows, err := svc.SessionsWithTags(map[string]string{"@amux_type": "agent"}, []string{"@amux_workspace", "@amux_assistant"}, opts)
+		if err != nil {
+			logging.Debug("gpu: list agent sessions: %v", err)
+		}
+		roots := map[int]bool{}
+		rootAgents := map[int]gpuAgent{}
+		for _, row := range rows {
+			pids, err := svc.SessionPanePIDs(row.Name, opts)
+			if err != nil {
+				continue
+			}
+			for _, pid := range pids {
+				roots[pid] = true
+				rootAgents[pid] = gpuAgent{workspaceID: row.Tags["@amux_workspace"], assistant: row.Tags["@amux_assistant"]}
+			}
+		}
+		for _, d := range devices {
+			for _, p := range d.Processes {
+				if root := gpu.Owner(p.PID, parents, roots); root != 0 {
+					msg.agents[p.PID] = rootAgents[root]
+				}
+			}
+		}
+		return msg
+	}
+}
+
+// handleGPUSampled opens the GPU panel: one line per device with its
+// utilization, memory, and the agents on it. Choosing a device pins the
+// workspace's new agent tabs and scripts to it.
+func (a *App) handleGPUSampled(msg gpuSampled) tea.Cmd {
+	if msg.err != nil {
+		return common.ReportError(errorContext(errorServiceWorkspace, "sampling GPUs"), msg.err, "")
+	}
+	ws := msg.workspace
+	if len(msg.devices) == 0 {
+		return a.toast.ShowInfo("nvidia-smi found no GPUs")
+	}
+	options := make([]string, 0, len(msg.devices)+1)
+	a.gpuPins = a.gpuPins[:0]
+	current := len(msg.devices)
+	for i, d := range msg.devices {
+		options = append(options, a.gpuLine(d, msg.agents))
+		a.gpuPins = append(a.gpuPins, strconv.Itoa(d.Index))
+		if ws.GPUs == strconv.Itoa(d.Index) {
+			current = i
+		}
+	}
+	options = append(options, "All GPUs")
+	a.gpuPins = append(a.gpuPins, "")
+	pin := "every GPU"
+	if ws.GPUs != "" {
+		pin = "GPU " + ws.GPUs
+	}
+	message := fmt.Sprintf("New agent tabs and scripts in %s use %s. Choose the GPUs they should see; tabs already open keep theirs.", ws.Name, pin)
+	a.dialog = common.NewListDialog(DialogGPU, "GPUs", message, options)
+	a.dialog.SetDefaultOption(current)
+	a.dialogWorkspace = ws
+	a.presentDialog(a.dialog)
+	return nil
+}
+
+// gpuLine describes a device and the processes on it, agents by name.
+func (a *App) gpuLine(d gpu.Device, agents map[int]gpuAgent) string {
+	line := fmt.Sprintf("GPU %d · %s · %d%% · %.1f/%.1f GiB", d.Index, d.Name, d.Utilization,
+		float64(d.MemUsedMiB)/1024, float64(d.MemTotalMiB)/1024)
+	var users []string
+	for _, p := range d.Processes {
+		if agent, ok := agents[p.PID]; ok {
+			users = append(users, agent.assistant+" in "+a.workspaceLabel(agent.workspaceID))
+		} else {
+			users = append(users, "pid "+strconv.Itoa(p.PID))
+		}
+	}
+	sort.Strings(users)
+	if len(users) > 0 {
+		line += " · " + strings.Join(users, ", ")
+	}
+	return line
+}
+
+// handleGPUDialog pins the workspace to the chosen GPU, reporting whether
+// result was from the GPU panel.
+func (a *App) handleGPUDialog(result common.DialogResult, ws *data.Workspace) (tea.Cmd, bool) {
+	if result.ID != DialogGPU {
+		return nil, false
+	}
+	pins := a.gpuPins
+	a.gpuPins = nil
+	if !result.Confirmed || ws == nil || result.Index < 0 || result.Index >= len(pins) {
+		return nil, true
+	}
+	devices := pins[result.Index]
+	if devices == ws.GPUs {
+		return nil, true
+	}
+	if a.workspaceService != nil && a.workspaceService.store != nil {
+		if err := a.workspaceService.store.SetGPUs(ws.ID(), devices); err != nil {
+			return common.ReportError(errorContext(errorServiceWorkspace, "saving GPU pin"), err, ""), true
+		}
+	}
+	ws.GPUs = devices
+	if devices == "" {
+		return a.toast.ShowInfo("New agent tabs and scripts in " + ws.Name + " see every GPU"), true
+	}
+	return a.toast.ShowInfo("New agent tabs and scripts in " + ws.Name + " use GPU " + devices), true
+}
@@ -0,0 +1,44 @@
+package app
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/andyrewlee/amux/internal/config"
+	"github.com/andyrewlee/amux/internal/gpu"
+	"github.com/andyrewlee/amux/internal/ui/common"
+)
+
+func TestGPUPanelPinsWorkspace(t *testing.T) {
+	app, ws := newNotifyTestApp(config.UISettings{})
+	app.toast = common.NewToastModel()
+	oldAvailable, oldSample := gpuAvailable, sampleGPUs
+	t.Cleanup(func() { gpuAvailable, sampleGPUs = oldAvailable, oldSample })
+	gpuAvailable = func() bool { return true }
+	sampleGPUs = func(context.Context) ([]gpu.Device, error) {
+		return []gpu.Device{
+			{Index: 0, Name: "A100", Utilization: 87, MemUsedMiB: 30720, MemTotalMiB: 81920, Processes: []gpu.Process{{PID: 42}}},
+			{Index: 1, Name: "A100", MemTotalMiB: 81920},
+		}, nil
+	}
+
+	msg := app.showGPUs(ws)().(gpuSampled)
+	msg.agents = map[int]gpuAgent{42: {workspaceID: string(ws.ID()), assistant: "claude"}}
+	app.handleGPUSampled(msg)
+	app.dialog.SetSize(160, 40)
+	view := dialogView(t, app.dialog)
+	if !strings.Contains(view, "GPU 0 · A100 · 87% · 30.0/80.0 GiB · claude in feature") || !strings.Contains(view, "All GPUs") {
+		t.Fatalf("the panel should list each GPU with its agents:\n%s", view)
+	}
+
+	app.handleDialogResult(common.DialogResult{ID: DialogGPU, Confirmed: true, Index: 1})
+	if ws.GPUs != "1" {
+		t.Fatalf("GPUs = %q, want the workspace pinned to GPU 1", ws.GPUs)
+	}
+	app.handleGPUSampled(app.showGPUs(ws)().(gpuSampled))
+	app.handleDialogResult(common.DialogResult{ID: DialogGPU, Confirmed: true, Index: 2})
+	if ws.GPUs != "" {
+		t.Fatalf("GPUs = %q, want the pin cleared", ws.GPUs)
+	}
+}
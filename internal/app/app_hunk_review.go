@@ -0,0 +1,268 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	tea "charm.land/bubbletea/v2"
+	"charm.land/lipgloss/v2"
+	"github.com/charmbracelet/x/ansi"
+
+	"github.com/andyrewlee/amux/internal/codeowners"
+	"github.com/andyrewlee/amux/internal/data"
+	"github.com/andyrewlee/amux/internal/git"
+	"github.com/andyrewlee/amux/internal/ui/common"
+)
+
+// hunkReviewLines bounds how many lines of a hunk the review dialog shows.
+const hunkReviewLines = 18
+
+// hunkReviewWidth is the widest a hunk line is shown in the review dialog.
+const hunkReviewWidth = 72
+
+// Options of the hunk review dialog, in order.
+const (
+	hunkOptionAccept = "Accept"
+	hunkOptionReject = "Reject"
+	hunkOptionRedo   = "Redo"
+	hunkOptionSkip   = "Skip"
+	hunkOptionStop   = "Stop"
+)
+
+var hunkReviewOptions = []string{hunkOptionAccept, hunkOptionReject, hunkOptionRedo, hunkOptionSkip, hunkOptionStop}
+
+// hunkReviewState walks one workspace's unstaged hunks. Accepted hunks are
+// staged, rejected ones reverted, and redo requests are sent to the agent
+// together when the review ends.
+type hunkReviewState struct {
+	workspace *data.Workspace
+	hunks     []git.FileHunk
+	owners    *codeowners.Ruleset
+	index     int
+	accepted  int
+	rejected  int
+	redo      []hunkRedo
+}
+
+// hunkRedo is a hunk sent back to the agent, with what should change.
+type hunkRedo struct {
+	hunk git.FileHunk
+	note string
+}
+
+// hunksLoaded carries a workspace's unstaged hunks for review, and the
+// repository's code owners when it has a CODEOWNERS file.
+type hunksLoaded struct {
+	workspace *data.Workspace
+	hunks     []git.FileHunk
+	owners    *codeowners.Ruleset
+	err       error
+}
+
+// hunkApplied reports staging or reverting the reviewed hunk.
+type hunkApplied struct {
+	workspace *data.Workspace
+	err       error
+}
+
+// reviewHunks starts reviewing ws's unstaged changes hunk by hunk.
+func (a *App) reviewHunks(ws *data.Workspace) tea.Cmd {
+	if ws == nil {
+		return nil
+	}
+	return func() tea.Msg {
+		hunks, err := git.WorktreeHunks(context.Background(), ws.Root)
+		// A CODEOWNERS file that can't be read just leaves owners out.
+		owners, _ := codeowners.Load(ws.Root)
+		return hunksLoaded{workspace: ws, hunks: hunks, owners: owners, err: err}
+	}
+}
+
+// handleHunksLoaded opens the review at the first hunk, warning first when
+// the changes span more ownership areas than one review should.
+func (a *App) handleHunksLoaded(msg hunksLoaded) tea.Cmd {
+	switch {
+	case msg.workspace == nil:
+		return nil
+	case msg.err != nil:
+		return common.ReportError(errorContext(errorServiceWorkspace, "loading the diff for review"), msg.err, "")
+	case len(msg.hunks) == 0:
+		return a.toast.ShowInfo("No unstaged changes to review")
+	}
+	a.hunkReview = hunkReviewState{workspace: msg.workspace, hunks: msg.hunks, owners: msg.owners}
+	if warning := ownershipWarning(msg.owners, msg.hunks); warning != "" {
+		return common.SafeBatch(a.toast.ShowWarning(warning), a.showHunk())
+	}
+	return a.showHunk()
+}
+
+// ownershipWarning describes changes to hunks whose files are owned by more
+// than codeowners.ManyAreas teams, or returns "" when they aren't.
+func ownershipWarning(owners *codeowners.Ruleset, hunks []git.FileHunk) string {
+	var paths []string
+	for _, h := range hunks {
+		if !slices.Contains(paths, h.Path) {
+			paths = append(paths, h.Path)
+		}
+	}
+	areas := owners.Areas(paths)
+	if codeowners.Owned(areas) <= codeowners.ManyAreas {
+		return ""
+	}
+	labels := make([]string, 0, len(areas))
+	for _, area := range areas {
+		if len(area.Owners) > 0 {
+			labels = append(labels, area.Label())
+		}
+	}
+	return fmt.Sprintf("These changes span %d ownership areas (%s); consider splitting them", len(labels), strings.Join(labels, ", "))
+}
+
+// showHunk presents the current hunk, or ends the review after the last.
+func (a *App) showHunk() tea.Cmd {
+	r := &a.hunkReview
+	if r.workspace == nil {
+		return nil
+	}
+	if r.index >= len(r.hunks) {
+		return a.finishHunkReview()
+	}
+	title := fmt.Sprintf("Review %d/%d", r.index+1, len(r.hunks))
+	a.dialog = common.NewSelectDialog(DialogHunkReview, title, renderHunk(r.hunks[r.index], r.owners.Owners(r.hunks[r.index].Path)), hunkReviewOptions)
+	a.dialogWorkspace = r.workspace
+	a.presentDialog(a.dialog)
+	return nil
+}
+
+// handleHunkReviewChoice acts on the current hunk.
+func (a *App) handleHunkReviewChoice(ws *data.Workspace, index int) tea.Cmd {
+	r := &a.hunkReview
+	if ws == nil || r.workspace != ws || r.index >= len(r.hunks) || index < 0 || index >= len(hunkReviewOptions) {
+		return nil
+	}
+	hunk := r.hunks[r.index]
+	switch hunkReviewOptions[index] {
+	case hunkOptionAccept:
+		r.accepted++
+		return applyReviewedHunk(ws, hunk, git.StageHunk)
+	case hunkOptionReject:
+		r.rejected++
+		return applyReviewedHunk(ws, hunk, git.RevertHunk)
+	case hunkOptionRedo:
+		a.dialog = common.NewInputDialog(DialogHunkRedo, "Ask Agent to Redo", "What should change? (optional)")
+		a.dialogWorkspace = ws
+		a.presentDialog(a.dialog)
+		return nil
+	case hunkOptionSkip:
+		r.index++
+		return a.showHunk()
+	default:
+		return a.finishHunkReview()
+	}
+}
+
+// applyReviewedHunk stages or reverts hunk off the UI goroutine.
+func applyReviewedHunk(ws *data.Workspace, hunk git.FileHunk, apply func(context.Context, string, git.FileHunk) error) tea.Cmd {
+	return func() tea.Msg {
+		return hunkApplied{workspace: ws, err: apply(context.Background(), ws.Root, hunk)}
+	}
+}
+
+// handleHunkApplied moves on to the next hunk. A hunk git could not apply is
+// reported and left as it is.
+func (a *App) handleHunkApplied(msg hunkApplied) tea.Cmd {
+	r := &a.hunkReview
+	if msg.workspace == nil || r.workspace != msg.workspace {
+		return nil
+	}
+	r.index++
+	if msg.err != nil {
+		return common.SafeBatch(
+			common.ReportError(errorContext(errorServiceWorkspace, "applying a reviewed hunk"), msg.err, ""),
+			a.showHunk(),
+		)
+	}
+	return a.showHunk()
+}
+
+// handleHunkRedo records the current hunk for the agent to redo.
+func (a *App) handleHunkRedo(ws *data.Workspace, note string) tea.Cmd {
+	r := &a.hunkReview
+	if ws == nil || r.workspace != ws || r.index >= len(r.hunks) {
+		return nil
+	}
+	r.redo = append(r.redo, hunkRedo{hunk: r.hunks[r.index], note: strings.TrimSpace(note)})
+	r.index++
+	return a.showHunk()
+}
+
+// handleHunkReviewCancel stops the review when its dialog is dismissed, and
+// returns to the hunk when the redo note is.
+func (a *App) handleHunkReviewCancel(id string) tea.Cmd {
+	if id == DialogHunkRedo {
+		return a.showHunk()
+	}
+	return a.finishHunkReview()
+}
+
+// finishHunkReview summarizes the review and sends any redo requests to the
+// workspace's agent.
+func (a *App) finishHunkReview() tea.Cmd {
+	r := a.hunkReview
+	a.hunkReview = hunkReviewState{}
+	if r.workspace == nil {
+		return nil
+	}
+	summary := fmt.Sprintf("Review: %d accepted, %d rejected, %d sent back", r.accepted, r.rejected, len(r.redo))
+	if len(r.redo) == 0 {
+		return a.toast.ShowInfo(summary)
+	}
+	return common.SafeBatch(a.toast.ShowInfo(summary), a.sendToWorkspaceAgent(r.workspace, hunkRedoPrompt(r.redo)))
+}
+
+// hunkRedoPrompt asks an agent to redo the changes it was sent back.
+func hunkRedoPrompt(redo []hunkRedo) string {
+	var b strings.Builder
+	b.WriteString("I reviewed your changes. Please redo these hunks.\n")
+	for _, r := range redo {
+		fmt.Fprintf(&b, "\nIn %s:\n\n```diff\n%s```\n", r.hunk.Path, r.hunk.Body)
+		if r.note != "" {
+			fmt.Fprintf(&b, "%s\n", r.note)
+		}
+	}
+	return b.String()
+}
+
+// renderHunk shows a hunk's file, who owns it, and its lines, colored and
+// clipped to fit the dialog.
+func renderHunk(h git.FileHunk, owners []string) string {
+	added := lipgloss.NewStyle().Foreground(common.ColorSuccess())
+	deleted := lipgloss.NewStyle().Foreground(common.ColorError())
+	muted := lipgloss.NewStyle().Foreground(common.ColorMuted())
+
+	lines := strings.Split(strings.TrimRight(h.Body, "\n"), "\n")
+	var b strings.Builder
+	b.WriteString(h.Path)
+	if len(owners) > 0 {
+		b.WriteString(muted.Render("  owned by " + strings.Join(owners, " ")))
+	}
+	for i, line := range lines {
+		if i == hunkReviewLines {
+			b.WriteString("\n" + muted.Render(fmt.Sprintf("… %d more lines", len(lines)-i)))
+			break
+		}
+		line = ansi.Truncate(strings.ReplaceAll(line, "\t", "    "), hunkReviewWidth, "…")
+		switch {
+		case i == 0:
+			line = muted.Render(line)
+		case strings.HasPrefix(line, "+"):
+			line = added.Render(line)
+		case strings.HasPrefix(line, "-"):
+			line = deleted.Render(line)
+		}
+		b.WriteString("\n" + line)
+	}
+	return b.String()
+}
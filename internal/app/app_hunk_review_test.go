@@ -0,0 +1,101 @@
+package app
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/andyrewlee/amux/internal/codeowners"
+	"github.com/andyrewlee/amux/internal/git"
+)
+
+func TestHunkReviewWalksHunks(t *testing.T) {
+	h := newDialogHarness(t)
+	ws := harnessWorkspace()
+	hunks := []git.FileHunk{
+		{Path: "a.go", Body: "@@ -1 +1 @@\n-old\n+new\n"},
+		{Path: "b.go", Body: "@@ -3,0 +4 @@\n+added\n"},
+		{Path: "c.go", Body: "@@ -9 +9 @@\n-x\n+y\n"},
+	}
+
+	h.app.handleHunksLoaded(hunksLoaded{workspace: ws, hunks: hunks})
+	view := dialogView(t, h.app.dialog)
+	for _, want := range []string{"Review 1/3", "a.go", "-old", "+new", hunkOptionAccept, hunkOptionRedo} {
+		if !strings.Contains(view, want) {
+			t.Fatalf("review dialog missing %q, got %q", want, view)
+		}
+	}
+
+	// Accepting stages the hunk off the UI goroutine; a failed apply is
+	// reported and the review moves on.
+	if cmd := h.app.handleHunkReviewChoice(ws, 0); cmd == nil {
+		t.Fatal("expected the hunk to be staged")
+	}
+	h.app.handleHunkApplied(hunkApplied{workspace: ws, err: errors.New("patch does not apply")})
+	if view := dialogView(t, h.app.dialog); !strings.Contains(view, "Review 2/3") {
+		t.Fatalf("dialog = %q, want the second hunk", view)
+	}
+
+	// Redo asks what should change; dismissing that returns to the hunk.
+	h.app.handleHunkReviewChoice(ws, 2)
+	if view := dialogView(t, h.app.dialog); !strings.Contains(view, "Ask Agent to Redo") {
+		t.Fatalf("dialog = %q, want the redo note", view)
+	}
+	h.app.handleHunkReviewCancel(DialogHunkRedo)
+	if view := dialogView(t, h.app.dialog); !strings.Contains(view, "Review 2/3") {
+		t.Fatalf("dialog = %q, want the second hunk again", view)
+	}
+	h.app.handleHunkRedo(ws, "keep the old name")
+	if got := h.app.hunkReview.redo; len(got) != 1 || got[0].hunk.Path != "b.go" || got[0].note != "keep the old name" {
+		t.Fatalf("redo = %+v, want b.go with its note", got)
+	}
+
+	// Skipping the last hunk ends the review.
+	h.app.handleHunkReviewChoice(ws, 3)
+	if h.app.hunkReview.workspace != nil {
+		t.Fatal("the review should end after the last hunk")
+	}
+}
+
+func TestHunkReviewShowsOwners(t *testing.T) {
+	h := newDialogHarness(t)
+	ws := harnessWorkspace()
+	owners, err := codeowners.Parse(strings.NewReader("/web/ @acme/web\n/api/ @acme/api\n/db/ @acme/data\n/docs/ @acme/docs\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	hunks := []git.FileHunk{
+		{Path: "web/app.ts", Body: "@@ -1 +1 @@\n-a\n+b\n"},
+		{Path: "web/app.ts", Body: "@@ -9 +9 @@\n-c\n+d\n"},
+		{Path: "api/main.go", Body: "@@ -1 +1 @@\n-a\n+b\n"},
+		{Path: "db/schema.sql", Body: "@@ -1 +1 @@\n-a\n+b\n"},
+	}
+
+	if warning := ownershipWarning(owners, hunks); warning != "" {
+		t.Fatalf("warning = %q, want none for three areas", warning)
+	}
+	hunks = append(hunks, git.FileHunk{Path: "docs/guide.md", Body: "@@ -1 +1 @@\n-a\n+b\n"})
+	if warning := ownershipWarning(owners, hunks); !strings.Contains(warning, "4 ownership areas (@acme/web, ") {
+		t.Fatalf("warning = %q, want the four areas, largest first", warning)
+	}
+	if warning := ownershipWarning(nil, hunks); warning != "" {
+		t.Fatalf("warning = %q, want none without a CODEOWNERS file", warning)
+	}
+
+	h.app.handleHunksLoaded(hunksLoaded{workspace: ws, hunks: hunks, owners: owners})
+	if view := dialogView(t, h.app.dialog); !strings.Contains(view, "owned by @acme/web") {
+		t.Fatalf("review dialog = %q, want the hunk's owners", view)
+	}
+}
+
+func TestHunkRedoPrompt(t *testing.T) {
+	prompt := hunkRedoPrompt([]hunkRedo{
+		{hunk: git.FileHunk{Path: "b.go", Body: "@@ -3,0 +4 @@\n+added\n"}, note: "keep the old name"},
+		{hunk: git.FileHunk{Path: "c.go", Body: "@@ -9 +9 @@\n-x\n+y\n"}},
+	})
+	for _, want := range []string{"In b.go:", "```diff\n@@ -3,0 +4 @@\n+added\n```", "keep the old name", "In c.go:"} {
+		if !strings.Contains(prompt, want) {
+			t.Fatalf("prompt missing %q, got %q", want, prompt)
+		}
+	}
+}
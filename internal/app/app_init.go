@@ -3,18 +3,24 @@ package app
 import (
 	"context"
 	"os"
+	"path/filepath"
 	"strings"
 	"time"
 
 	tea "charm.land/bubbletea/v2"
 
+	"github.com/andyrewlee/amux/internal/audit"
 	"github.com/andyrewlee/amux/internal/config"
 	"github.com/andyrewlee/amux/internal/data"
 	"github.com/andyrewlee/amux/internal/git"
+	"github.com/andyrewlee/amux/internal/llm"
 	"github.com/andyrewlee/amux/internal/logging"
 	"github.com/andyrewlee/amux/internal/messages"
+	"github.com/andyrewlee/amux/internal/notify"
 	"github.com/andyrewlee/amux/internal/perf"
 	"github.com/andyrewlee/amux/internal/process"
+	"github.com/andyrewlee/amux/internal/safego"
+	"github.com/andyrewlee/amux/internal/secrets"
 	"github.com/andyrewlee/amux/internal/supervisor"
 	"github.com/andyrewlee/amux/internal/tmux"
 	"github.com/andyrewlee/amux/internal/ui/center"
@@ -68,7 +74,16 @@ func newAppShell(cfg *config.Config) *App {
 	app.propagateStyles()
 	if cfg != nil {
 		app.setKeymapHintsEnabled(cfg.UI.ShowKeymapHints)
-		app.dashboard.SetNotifyOnDone(cfg.UI.NotifyOnDone)
+		// The dashboard rings the bell for finished agents; other backends
+		// are notified from notifyDoneEdges.
+		app.dashboard.SetNotifyOnDone(cfg.UI.NotifyOnDone && notify.ParseBackend(cfg.UI.Notifications) == notify.Bell)
+		if cfg.Paths != nil {
+			app.dashboard.SetProfile(cfg.Paths.Profile)
+		}
+		// The status bar takes the bottom row only when it has widgets.
+		if len(cfg.StatusWidgets) > 0 {
+			app.layout.SetBottomGutter(1)
+		}
 	}
 	return app
 }
@@ -107,6 +122,11 @@ func New(version, commit, date string) (*App, error) {
 		return nil, err
 	}
 	applyTmuxEnvFromConfig(cfg)
+	applyLatencyProfileFromConfig(cfg)
+	applyReducedMotionFromConfig(cfg)
+	lowBandwidth := lowBandwidthEnabled(cfg, os.Getenv)
+	applyLowBandwidth(lowBandwidth)
+	applyFlowControlFromConfig(cfg)
 	tmuxOpts := tmux.DefaultOptions()
 
 	// Ensure directories exist
@@ -118,6 +138,21 @@ func New(version, commit, date string) (*App, error) {
 	workspaces := data.NewWorkspaceStore(cfg.Paths.MetadataRoot)
 	scripts := process.NewScriptRunner(cfg.PortStart, cfg.PortRangeSize)
 	workspaceService := newWorkspaceService(registry, workspaces, scripts, cfg.Paths.WorkspacesRoot)
+	workspaceService.trash = data.NewWorkspaceTrash(cfg.Paths.TrashRoot)
+	workspaceService.trashRetention = trashRetentionFromEnv()
+
+	// Keep credential-like workspace env values in the OS keychain (or the
+	// encrypted fallback file), moving any still stored in plaintext.
+	secretStore := secrets.Open(cfg.Paths.Home)
+	workspaces.SetSecretStore(secretStore)
+	workspaceService.trash.SetSecretStore(secretStore)
+	safego.Go("secrets.migrate", func() {
+		if n, err := workspaces.MigrateSecrets(); err != nil {
+			logging.Warn("Migrating workspace secrets: %v", err)
+		} else if n > 0 {
+			logging.Info("Moved secrets of %d workspace(s) out of plaintext metadata", n)
+		}
+	})
 
 	// Create status manager (used for synchronous status caching only).
 	statusManager := git.NewStatusManager()
@@ -164,6 +199,10 @@ func New(version, commit, date string) (*App, error) {
 	ctx := context.Background()
 	app := newAppShell(cfg)
 	app.workspaceService = workspaceService
+	app.lowBandwidth = lowBandwidth
+	app.llm = llm.New(cfg.LLM, llm.OpenLedger(filepath.Join(cfg.Paths.Home, llm.LedgerFile)))
+	app.applyNesting(DetectNesting(os.Getenv, OuterTmuxPrefixes))
+	app.sidebar.SetScratchpadStore(workspaces)
 	app.gitStatus = gitStatus
 	app.tmuxService = tmuxSvc
 	app.updateService = updateSvc
@@ -182,12 +221,15 @@ func New(version, commit, date string) (*App, error) {
 	app.ctx = ctx
 	app.tmuxOptions = tmuxOpts
 	app.instanceID = newInstanceID(cfg.Paths.Home)
+	app.activateHandledAt = time.Now()
 	app.supervisor = supervisor.New(ctx)
 	app.installSupervisorErrorHandler()
 	// Route PTY messages through the app-level pump.
 	app.center.SetMsgSinkTry(app.tryEnqueueExternalMsg)
 	app.sidebarTerminal.SetMsgSink(app.enqueueExternalMsg)
 	app.center.SetInstanceID(app.instanceID)
+	app.auditLog = audit.Open(audit.Path(filepath.Join(cfg.Paths.Home, "logs")))
+	app.center.SetAuditLog(app.auditLog)
 	app.sidebarTerminal.SetInstanceID(app.instanceID)
 	// Propagate tmux config to components
 	app.center.SetTmuxOptions(tmuxOpts)
@@ -225,10 +267,19 @@ func (a *App) Init() tea.Cmd {
 		a.startTmuxActivityTicker(),
 		a.triggerTmuxActivityScan(),
 		a.startTmuxSyncTicker(),
+		a.startEgressTicker(),
+		a.startLimitsTicker(),
+		a.refreshStatusWidgets(),
+		a.startColumnsTicker(),
+		a.startErrorScanTicker(),
+		a.startParkTicker(),
+		a.startActivateRequestTicker(),
 		a.checkTmuxAvailable(),
 		a.startFileWatcher(),
 		a.startStateWatcher(),
 		a.checkForUpdates(),
+		a.nestingNoticeCmd(),
+		a.readOnlyNoticeCmd(),
 	}
 	cmds = append(cmds, a.watcherWarningCmds()...)
 	return common.SafeBatch(cmds...)
@@ -81,6 +81,9 @@ func (f *fakeTmuxAvailability) CapturePaneTail(string, int, tmux.Options) (strin
 	return "", false
 }
 func (f *fakeTmuxAvailability) ContentHash(string) [16]byte { return [16]byte{} }
+func (f *fakeTmuxAvailability) SessionPanePIDs(string, tmux.Options) ([]int, error) {
+	return nil, nil
+}
 
 // ---------------------------------------------------------------------------
 // checkForUpdates
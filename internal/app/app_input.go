@@ -38,6 +38,9 @@ func (a *App) update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	if res, consumed := a.handlePreSwitchInput(msg, &cmds); consumed {
 		return a, res
 	}
+	if res, refused := a.refuseReadOnly(msg); refused {
+		return a, res
+	}
 
 	if a.updateTabMsg(msg, &cmds) {
 		return a, common.SafeBatch(cmds...)
@@ -51,6 +54,9 @@ func (a *App) update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	if a.updateDialogShowMsg(msg, &cmds) {
 		return a, common.SafeBatch(cmds...)
 	}
+	if a.updatePanelMsg(msg, &cmds) {
+		return a, common.SafeBatch(cmds...)
+	}
 	if a.updateUpgradeMsg(msg, &cmds) {
 		return a, common.SafeBatch(cmds...)
 	}
@@ -72,6 +78,9 @@ func (a *App) update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			cmds = append(cmds, cmd)
 		}
 
+	case takeoverQuitMsg:
+		return a, a.handleTakeoverQuit()
+
 	case prefixTimeoutMsg:
 		a.handlePrefixTimeout(msg)
 
@@ -93,7 +102,10 @@ func (a *App) update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case messages.Toast:
 		cmds = append(cmds, a.showToast(msg))
 
-	case messages.SidebarPTYOutput, messages.SidebarPTYFlush, messages.SidebarPTYStopped, messages.SidebarPTYRestart, sidebar.SidebarTerminalCreated, sidebar.SidebarTerminalCreateFailed, sidebar.SidebarTerminalReattachResult, sidebar.SidebarTerminalReattachFailed, sidebar.SidebarSelectionScrollTick:
+	case messages.FocusPane:
+		cmds = append(cmds, a.focusPane(msg.Pane))
+
+	case messages.SidebarPTYOutput, messages.SidebarPTYFlush, messages.SidebarPTYStopped, messages.SidebarPTYRestart, sidebar.SidebarTerminalCreated, sidebar.SidebarTerminalCreateFailed, sidebar.SidebarTerminalReattachResult, sidebar.SidebarTerminalReattachFailed, sidebar.SidebarSelectionScrollTick, sidebar.SidebarTerminalRedrawRestore:
 		if cmd := a.handleSidebarPTYMessages(msg); cmd != nil {
 			cmds = append(cmds, cmd)
 		}
@@ -103,10 +115,11 @@ func (a *App) update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			cmds = append(cmds, cmd)
 		}
 
-	case sidebar.BranchChangesLoaded, sidebar.AheadBehindLoaded:
-		// Branch-vs-base list / ahead-behind badge fetch results: route back
-		// into the sidebar regardless of which of its tabs is active (see
-		// TabbedSidebar.Update's special-case for these two types).
+	case sidebar.BranchChangesLoaded, sidebar.AheadBehindLoaded,
+		sidebar.ScratchpadLoaded, sidebar.ScratchpadSaveDue, sidebar.ScratchpadSaved:
+		// Branch-vs-base list / ahead-behind badge fetch results and Notes
+		// loads/saves: route back into the sidebar regardless of which of its
+		// tabs is active (see TabbedSidebar.Update's special-cases).
 		if a.sidebar != nil {
 			newSidebar, cmd := a.sidebar.Update(msg)
 			a.sidebar = newSidebar
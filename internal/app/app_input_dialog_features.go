@@ -0,0 +1,40 @@
+package app
+
+import (
+	tea "charm.land/bubbletea/v2"
+
+	"github.com/andyrewlee/amux/internal/data"
+	"github.com/andyrewlee/amux/internal/ui/common"
+)
+
+// handleFeatureDialog offers a dialog result to the features that handle
+// their own dialogs, canceled or confirmed, before handleDialogResult's shared
+// switch. ws is the dialog's workspace, already cleared from the App. It
+// reports whether one of them took the result.
+func (a *App) handleFeatureDialog(result common.DialogResult, ws *data.Workspace) (tea.Cmd, bool) {
+	if cmd, ok := a.handleMonorepoDialog(result); ok {
+		return cmd, true
+	}
+	if cmd, ok := a.handleChangelogDialog(result, ws); ok {
+		return cmd, true
+	}
+	if cmd, ok := a.handleGPUDialog(result, ws); ok {
+		return cmd, true
+	}
+	if cmd, ok := a.handleContainerDialog(result, ws); ok {
+		return cmd, true
+	}
+	if cmd, ok := a.handleBenchmarkDialog(result); ok {
+		return cmd, true
+	}
+	if cmd, ok := a.handleBisectDialog(result, ws); ok {
+		return cmd, true
+	}
+	if cmd, ok := a.handleStackDialog(result); ok {
+		return cmd, true
+	}
+	if cmd, ok := a.handleAskDialog(result); ok {
+		return cmd, true
+	}
+	return nil, false
+}
@@ -100,15 +100,25 @@ func (a *App) handleEnvDialogInput(msg tea.Msg, cmds *[]tea.Cmd) bool {
 	return consumed
 }
 
+func (a *App) handleFileSearchInput(msg tea.Msg, cmds *[]tea.Cmd) bool {
+	var consumed bool
+	a.searchPanel, consumed = handleOverlayInput(a.searchPanel, msg, cmds, true)
+	return consumed
+}
+
 // handleDialogResult handles dialog completion
 func (a *App) handleDialogResult(result common.DialogResult) tea.Cmd {
 	project := a.dialogProject
 	workspace := a.dialogWorkspace
 	trustScriptsHash := a.dialogTrustScriptsHash
+	trash := a.dialogTrash
+	trustThen := a.dialogTrustThen
 	a.dialog = nil
 	a.dialogProject = nil
 	a.dialogWorkspace = nil
 	a.dialogTrustScriptsHash = ""
+	a.dialogTrash = trashView{}
+	a.dialogTrustThen = nil
 	logging.Debug("Dialog result: id=%s confirmed=%v value_len=%d", result.ID, result.Confirmed, len(result.Value))
 
 	// Defensive: handleDialogResult only knows how to act on IDs in the shared
@@ -118,13 +128,57 @@ func (a *App) handleDialogResult(result common.DialogResult) tea.Cmd {
 		logging.Warn("handleDialogResult called with non-App dialog ID: %s", result.ID)
 		return nil
 	}
+	if cmd, ok := a.handleFeatureDialog(result, workspace); ok {
+		return cmd
+	}
 
 	if !result.Confirmed {
 		if result.ID == DialogSelectAssistant || result.ID == common.AgentPickerDialogID {
+			a.monorepo.pending = nil
 			a.pendingWorkspaceProject = nil
 			a.pendingWorkspaceName = ""
 			a.pendingWorkspaceBase = ""
 		}
+		if result.ID == DialogLargePaste {
+			a.largePaste.pending = ""
+		}
+		if result.ID == DialogPastePreview {
+			a.largePaste.preview = ""
+		}
+		if result.ID == DialogStartupLayout {
+			a.pendingLayout = nil
+		}
+		if result.ID == DialogCodeNav {
+			a.codeNav.locations = nil
+		}
+		if result.ID == DialogDiffComment {
+			a.reviewComments.pending = nil
+		}
+		if result.ID == DialogCompareWith || result.ID == DialogComparison {
+			a.compare = compareState{}
+		}
+		if result.ID == DialogMergeQueue {
+			a.mergeAssist = mergeAssistState{}
+		}
+		if result.ID == DialogFanOutTask || result.ID == DialogFanOutVariations || result.ID == DialogFanOutAgent {
+			a.fanOut.task, a.fanOut.variations = "", nil
+		}
+		if result.ID == DialogCodeBlocks || result.ID == DialogCodeBlockAction ||
+			result.ID == DialogCodeBlockSave || result.ID == DialogCodeBlockReplace {
+			a.codeBlocks = codeBlockState{}
+		}
+		if result.ID == DialogInputHistory {
+			a.inputHistory = nil
+		}
+		if result.ID == DialogLeftoverSessions {
+			a.leftover.found, a.leftover.choices = leftoverSessionsFound{}, nil
+		}
+		if result.ID == DialogMergeConflict || result.ID == DialogMergeChecks {
+			return a.handleMergePauseCancel()
+		}
+		if result.ID == DialogHunkReview || result.ID == DialogHunkRedo {
+			return a.handleHunkReviewCancel(result.ID)
+		}
 		logging.Debug("Dialog canceled")
 		return nil
 	}
@@ -158,6 +212,9 @@ func (a *App) handleDialogResult(result common.DialogResult) tea.Cmd {
 			a.pendingWorkspaceProject = project
 			a.pendingWorkspaceName = name
 			a.pendingWorkspaceBase = ""
+			if a.askMonorepoTask(project, name) {
+				return nil
+			}
 			return func() tea.Msg {
 				return messages.ShowSelectAssistantDialog{}
 			}
@@ -234,6 +291,7 @@ func (a *App) handleDialogResult(result common.DialogResult) tea.Cmd {
 			a.pendingWorkspaceProject = nil
 			a.pendingWorkspaceName = ""
 			a.pendingWorkspaceBase = ""
+			a.queueMonorepoRun(pendingProject, pendingName, assistant)
 			return func() tea.Msg {
 				return messages.CreateWorkspace{
 					Project:   pendingProject,
@@ -245,6 +303,9 @@ func (a *App) handleDialogResult(result common.DialogResult) tea.Cmd {
 		}
 		if a.activeWorkspace != nil {
 			ws := a.activeWorkspace
+			if cmd := a.applySandboxChoice(ws, result.Toggle); cmd != nil {
+				return cmd
+			}
 			return func() tea.Msg {
 				return messages.LaunchAgent{
 					Assistant: assistant,
@@ -264,23 +325,84 @@ func (a *App) handleDialogResult(result common.DialogResult) tea.Cmd {
 
 	case DialogCleanupTmux:
 		return func() tea.Msg { return messages.CleanupTmuxSessions{} }
+
+	case DialogTrash:
+		return a.restoreFromTrashDialog(trash, result.Index)
+	case DialogOpenIn:
+		if workspace != nil {
+			return a.openWorkspaceIn(workspace, result.Index)
+		}
+	case DialogLargePaste:
+		return a.handleLargePasteChoice(result.Index)
+	case DialogPastePreview:
+		return a.handlePastePreviewChoice(result.Index)
+	case DialogTerminalSearch:
+		return a.applyTerminalSearch(result.Value)
+	case DialogEgress:
+		return a.applyNoNetworkChoice(workspace, result.Toggle)
+	case DialogRunCommand:
+		return a.runCommandTab(workspace, result.Value)
+	case DialogRelaunch:
+		return a.handleRelaunchChoice(workspace, result.Index)
+	case DialogStartupLayout:
+		return a.applyStartupLayout(workspace)
+	case DialogTests:
+		return a.handleTestPanelChoice(workspace, result.Index)
+	case DialogChecks:
+		return a.handleChecksPanelChoice(workspace, result.Index)
+	case DialogSymbolSearch:
+		return a.searchSymbols(workspace, result.Value)
+	case DialogDefinition:
+		return a.findDefinition(workspace, result.Value)
+	case DialogCodeNav:
+		return a.handleCodeNavChoice(workspace, result.Index)
+	case DialogHunkReview:
+		return a.handleHunkReviewChoice(workspace, result.Index)
+	case DialogHunkRedo:
+		return a.handleHunkRedo(workspace, result.Value)
+	case DialogDiffComment:
+		return a.handleDiffComment(workspace, result.Value)
+	case DialogCompareWith:
+		return a.handleCompareWithChoice(workspace, result.Index)
+	case DialogComparison:
+		return a.handleComparisonChoice(workspace, result.Index)
+	case DialogMergeQueue:
+		return a.handleMergeQueueChoice(workspace, result.Index)
+	case DialogMergeConflict:
+		return a.handleMergeConflictChoice(workspace, result.Index)
+	case DialogMergeChecks:
+		return a.handleMergeChecksChoice(workspace, result.Index)
+	case DialogFanOutTask:
+		return a.handleFanOutTask(result.Value)
+	case DialogFanOutVariations:
+		return a.handleFanOutVariations(result.Value)
+	case DialogFanOutAgent:
+		return a.handleFanOutAgent(result.Value)
+	case DialogFanOutQueue:
+		return a.handleFanOutQueueChoice(result.Index)
+	case DialogCodeBlocks:
+		return a.handleCodeBlockChoice(result.Index)
+	case DialogCodeBlockAction:
+		return a.handleCodeBlockAction(result.Value)
+	case DialogCodeBlockSave:
+		return a.saveCodeBlock(result.Value, false)
+	case DialogCodeBlockReplace:
+		return a.saveCodeBlock(a.codeBlocks.path, true)
+	case DialogAttachImage:
+		return a.attachImage(workspace, result.Value)
+	case DialogInputHistory:
+		return a.handleInputHistoryChoice(result.Index)
+	case DialogLeftoverSessions:
+		return a.handleLeftoverSessionsChoice(result.Index)
+	case DialogTrustAndRun:
+		if trustThen != nil && workspace != nil {
+			return trustThen(workspace, trustScriptsHash)
+		}
 	}
 
 	return nil
 }
 
-func (a *App) showQuitDialog() {
-	if a.dialog != nil && a.dialog.Visible() {
-		return
-	}
-	a.dialog = common.NewConfirmDialog(
-		DialogQuit,
-		"Quit AMUX",
-		"Are you sure you want to quit?",
-	)
-	a.presentDialog(a.dialog)
-}
-
 // handleUpdateCheckComplete handles the UpdateCheckComplete message.
 func (a *App) handleUpdateCheckComplete(msg messages.UpdateCheckComplete) tea.Cmd {
 	if msg.Err != nil {
@@ -19,12 +19,13 @@ import (
 // Routing map — which dispatcher owns a message type, and where its handlers
 // live (see MESSAGE_FLOW.md for the create/activate and delete sequences):
 //
-//	handlePreSwitchInput   dialog/file-picker/settings/toast/overlay input
+//	handlePreSwitchInput   dialog/file-picker/settings/search-panel/toast/overlay input
 //	                       → app_input_dialogs.go
 //	updateUpgradeMsg       UpdateCheckComplete, TriggerUpgrade, UpgradeComplete
 //	                       → service_update.go
 //	updateTabMsg           OpenDiff, CloseTab, LaunchAgent, TabCreated/Closed/
 //	                       Detached/Reattached/StateChanged/SelectionChanged,
+//	                       TabSessionStatus,
 //	                       persistDebounceMsg, persistSaveFailedMsg,
 //	                       center.TabInputFailed
 //	                       → app_input_messages_center.go, app_persistence.go
@@ -32,23 +33,43 @@ import (
 //	                       OrphanGCTick, PTYWatchdogTick, tmuxActivityTick/
 //	                       Result, tmuxAvailableResult, TmuxSyncTick,
 //	                       tmuxTabsSyncResult, tmuxTabs/SidebarDiscoverResult,
-//	                       orphanGCResult, staleDetachedAgentGCResult
-//	                       → app_tmux*.go
+//	                       orphanGCResult, staleDetachedAgentGCResult,
+//	                       egressTick, egressSampleResult, limitsTick,
+//	                       limitsSampleResult, parkTick, sessionCountResult,
+//	                       notifyRequest, notifyDigestTick, focusFollowRequest,
+//	                       leftoverSessionsFound, activateRequestTick,
+//	                       statusWidgetTick/Result, columnsTick, columnsRan,
+//	                       errorScanTick, errorsScanned
+//	                       → app_tmux*.go, app_egress.go, app_limits.go,
+//	                         app_park.go, app_notify*.go, app_focus_follow.go,
+//	                         app_activate_request.go, app_status_bar.go,
+//	                         app_columns.go, app_recent_errors.go
 //	updateWorkspaceLifecycleMsg  ProjectsLoaded, WorkspaceActivated/Created/
 //	                       CreatedWithWarning/CreateFailed/SetupComplete,
 //	                       CreateWorkspace, DeleteWorkspace, WorkspaceDeleted/
 //	                       DeleteFailed, AddProject/RemoveProject/ProjectRemoved,
+//	                       Undo, trashRestoreLoaded, devEnvChecked,
+//	                       ToggleFavorite,
 //	                       RefreshDashboard, RescanWorkspaces, GitStatusResult,
 //	                       FileWatcherEvent, StateWatcherEvent
-//	                       → app_input_messages_workspace.go, app_input_workspace.go
-//	updateDialogShowMsg    Show* dialog requests, ThemePreview, SettingsResult,
-//	                       EnvDialogResult
-//	                       → app_input_dialogs.go
+//	                       → app_input_messages_workspace.go, app_input_workspace.go,
+//	                         app_undo.go, app_devenv.go, app_favorites.go
+//	updateDialogShowMsg    Show* dialog requests, ThemePreview, LatencyPreview,
+//	                       SettingsResult, EnvDialogResult, openInResult,
+//	                       trashLoaded, largePasteProgress, largePasteFileReady,
+//	                       layoutLoaded, runbookLoaded
+//	                       → app_input_dialogs.go, app_open_in.go, app_trash.go,
+//	                         app_latency_profile.go, app_large_paste.go,
+//	                         app_layout.go, app_runbook.go
+//	updatePanelMsg         results of the panels and assistants opened from the
+//	                       prefix palette → app_input_dispatch_panels.go, which
+//	                       keeps its own map
 
 // handlePreSwitchInput runs the overlay/dialog guards that may consume a message
 // before the main routing switch. It returns the resulting command and true when
 // the message was consumed (the caller returns immediately).
 func (a *App) handlePreSwitchInput(msg tea.Msg, cmds *[]tea.Cmd) (tea.Cmd, bool) {
+	a.noteUserInput(msg)
 	if perf.Enabled() {
 		switch msg.(type) {
 		case tea.KeyPressMsg, tea.KeyReleaseMsg, tea.MouseClickMsg, tea.MouseWheelMsg, tea.MouseMotionMsg, tea.MouseReleaseMsg, tea.PasteMsg:
@@ -82,6 +103,9 @@ func (a *App) handlePreSwitchInput(msg tea.Msg, cmds *[]tea.Cmd) (tea.Cmd, bool)
 	if a.handleEnvDialogInput(msg, cmds) {
 		return common.SafeBatch(*cmds...), true
 	}
+	if a.handleFileSearchInput(msg, cmds) {
+		return common.SafeBatch(*cmds...), true
+	}
 	return nil, false
 }
 
@@ -134,6 +158,7 @@ func (a *App) updateTabMsg(msg tea.Msg, cmds *[]tea.Cmd) bool {
 		}
 	case messages.TabClosed:
 		logging.Info("Tab closed: %d", msg.Index)
+		a.recordTabClosedUndo(msg)
 		if cmd := a.persistActiveWorkspaceTabs(); cmd != nil {
 			*cmds = append(*cmds, cmd)
 		}
@@ -152,6 +177,8 @@ func (a *App) updateTabMsg(msg tea.Msg, cmds *[]tea.Cmd) bool {
 		}
 	case messages.TabStateChanged:
 		*cmds = append(*cmds, a.persistWorkspaceTabs(msg.WorkspaceID))
+	case messages.TabSessionStatus:
+		*cmds = append(*cmds, a.notifyAgentExit(msg), a.recordAgentExit(msg), a.handlePTYMessages(msg))
 	case messages.TabSelectionChanged:
 		*cmds = append(*cmds, a.persistWorkspaceTabs(msg.WorkspaceID))
 	case persistDebounceMsg:
@@ -196,12 +223,44 @@ func (a *App) updateTmuxMsg(msg tea.Msg, cmds *[]tea.Cmd) bool {
 		*cmds = append(*cmds, a.handleTmuxTabsDiscoverResult(msg)...)
 	case tmuxSidebarDiscoverResult:
 		*cmds = append(*cmds, a.handleTmuxSidebarDiscoverResult(msg)...)
+	case egressTick:
+		*cmds = append(*cmds, a.handleEgressTick())
+	case egressSampleResult:
+		*cmds = append(*cmds, a.handleEgressSampleResult(msg)...)
+	case limitsTick:
+		*cmds = append(*cmds, a.handleLimitsTick())
+	case limitsSampleResult:
+		*cmds = append(*cmds, a.handleLimitsSampleResult(msg)...)
+	case parkTick:
+		*cmds = append(*cmds, a.handleParkTick())
+	case columnsTick:
+		*cmds = append(*cmds, a.handleColumnsTick())
+	case columnsRan:
+		a.handleColumnsRan(msg)
+	case errorScanTick:
+		*cmds = append(*cmds, a.handleErrorScanTick())
+	case errorsScanned:
+		a.handleErrorsScanned(msg)
+	case statusWidgetTick:
+		*cmds = append(*cmds, a.handleStatusWidgetTick(msg))
+	case statusWidgetResult:
+		*cmds = append(*cmds, a.handleStatusWidgetResult(msg))
+	case activateRequestTick:
+		*cmds = append(*cmds, a.handleActivateRequestTick(msg))
 	case orphanGCResult:
 		a.handleOrphanGCResult(msg)
 	case staleDetachedAgentGCResult:
 		a.handleStaleDetachedAgentGCResult(msg)
 	case sessionCountResult:
 		a.handleSessionCountResult(msg)
+	case notifyRequest:
+		*cmds = append(*cmds, a.notify(msg.n))
+	case notifyDigestTick:
+		*cmds = append(*cmds, a.handleNotifyDigestTick())
+	case focusFollowRequest:
+		*cmds = append(*cmds, a.handleFocusFollow(msg))
+	case leftoverSessionsFound:
+		*cmds = append(*cmds, a.handleLeftoverSessionsFound(msg))
 	default:
 		return false
 	}
@@ -216,6 +275,10 @@ func (a *App) updateWorkspaceLifecycleMsg(msg tea.Msg, cmds *[]tea.Cmd) bool {
 		*cmds = append(*cmds, a.handleProjectsLoaded(msg)...)
 	case messages.WorkspaceActivated:
 		*cmds = append(*cmds, a.handleWorkspaceActivated(msg)...)
+		*cmds = append(*cmds, a.checkDevEnv(msg.Workspace))
+		*cmds = append(*cmds, a.refreshStatusWidgets())
+	case devEnvChecked:
+		*cmds = append(*cmds, a.handleDevEnvChecked(msg))
 	case messages.RefreshDashboard:
 		*cmds = append(*cmds, a.loadProjects())
 	case messages.RescanWorkspaces:
@@ -224,14 +287,22 @@ func (a *App) updateWorkspaceLifecycleMsg(msg tea.Msg, cmds *[]tea.Cmd) bool {
 		*cmds = append(*cmds, a.handleWorkspaceCreatedWithWarning(msg)...)
 	case messages.WorkspaceCreated:
 		*cmds = append(*cmds, a.handleWorkspaceCreated(msg)...)
+		if cmd := a.finishTrashRestore(msg.Workspace); cmd != nil {
+			*cmds = append(*cmds, cmd)
+		}
 	case messages.WorkspaceSetupComplete:
 		if cmd := a.handleWorkspaceSetupComplete(msg); cmd != nil {
 			*cmds = append(*cmds, cmd)
 		}
+		*cmds = append(*cmds, a.handleFanOutSetupComplete(msg), a.handleMonorepoSetupComplete(msg),
+			a.handleBenchmarkSetupComplete(msg))
 	case messages.WorkspaceCreateFailed:
 		if cmd := a.handleWorkspaceCreateFailed(msg); cmd != nil {
 			*cmds = append(*cmds, cmd)
 		}
+		a.handleFanOutCreateFailed(msg)
+		a.handleMonorepoCreateFailed(msg)
+		a.handleBenchmarkCreateFailed(msg)
 	case messages.GitStatusResult:
 		if cmd := a.handleGitStatusResult(msg); cmd != nil {
 			*cmds = append(*cmds, cmd)
@@ -242,14 +313,24 @@ func (a *App) updateWorkspaceLifecycleMsg(msg tea.Msg, cmds *[]tea.Cmd) bool {
 		*cmds = append(*cmds, a.handleDeleteWorkspace(msg)...)
 	case messages.RenameWorkspace:
 		*cmds = append(*cmds, a.handleRenameWorkspace(msg)...)
+	case messages.Undo:
+		*cmds = append(*cmds, a.handleUndo())
+	case messages.ToggleFavorite:
+		*cmds = append(*cmds, a.toggleFavorite(msg))
+	case favoriteSaved:
+		*cmds = append(*cmds, a.handleFavoriteSaved(msg))
+	case trashRestoreLoaded:
+		*cmds = append(*cmds, a.restoreTrashedWorkspace(msg.Project, msg.Entry)...)
 	case messages.AddProject:
 		*cmds = append(*cmds, a.addProject(msg.Path))
 	case messages.RemoveProject:
 		*cmds = append(*cmds, a.removeProject(msg.Project))
 	case messages.WorkspaceDeleted:
+		a.recordWorkspaceDeletedUndo(msg)
 		*cmds = append(*cmds, a.handleWorkspaceDeleted(msg)...)
 	case messages.ProjectRemoved:
-		*cmds = append(*cmds, a.toast.ShowSuccess("Project removed"))
+		a.recordProjectRemovedUndo(msg.Path)
+		*cmds = append(*cmds, a.toast.ShowSuccess("Project removed (u to undo)"))
 		*cmds = append(*cmds, a.loadProjects())
 	case messages.WorkspaceDeleteFailed:
 		if cmd := a.handleWorkspaceDeleteFailed(msg); cmd != nil {
@@ -273,12 +354,16 @@ func (a *App) updateWorkspaceLifecycleMsg(msg tea.Msg, cmds *[]tea.Cmd) bool {
 func (a *App) updateDialogShowMsg(msg tea.Msg, cmds *[]tea.Cmd) bool {
 	switch msg := msg.(type) {
 	case messages.ShowWelcome:
-		a.goHome()
+		*cmds = append(*cmds, a.goHome())
 	case messages.ShowCommandsPalette:
 		if cmd := a.openCommandsPalette(); cmd != nil {
 			*cmds = append(*cmds, cmd)
 		}
 	case messages.ToggleKeymapHints:
+		if a.readOnly {
+			*cmds = append(*cmds, a.readOnlyRefusal("change settings"))
+			break
+		}
 		a.setKeymapHintsEnabled(!a.config.UI.ShowKeymapHints)
 		if err := a.config.SaveUISettings(); err != nil {
 			*cmds = append(*cmds, common.ReportError("saving keymap setting", err, "Failed to save keymap setting"))
@@ -295,8 +380,32 @@ func (a *App) updateDialogShowMsg(msg tea.Msg, cmds *[]tea.Cmd) bool {
 		a.handleShowRenameWorkspaceDialog(msg)
 	case messages.ShowWorkspaceEnvDialog:
 		a.handleShowWorkspaceEnvDialog(msg)
+	case messages.ShowOpenInDialog:
+		a.handleShowOpenInDialog(msg)
+	case openInResult:
+		if cmd := a.handleOpenInResult(msg); cmd != nil {
+			*cmds = append(*cmds, cmd)
+		}
+	case largePasteProgress:
+		if cmd := a.handleLargePasteProgress(msg); cmd != nil {
+			*cmds = append(*cmds, cmd)
+		}
+	case largePasteFileReady:
+		if cmd := a.handleLargePasteFileReady(msg); cmd != nil {
+			*cmds = append(*cmds, cmd)
+		}
+	case messages.ShowTrashDialog:
+		*cmds = append(*cmds, a.handleShowTrashDialog())
+	case trashLoaded:
+		*cmds = append(*cmds, a.handleTrashLoaded(msg))
+	case layoutLoaded:
+		*cmds = append(*cmds, a.handleLayoutLoaded(msg))
+	case runbookLoaded:
+		*cmds = append(*cmds, a.handleRunbookLoaded(msg))
+	case messages.ShowDiffCommentDialog:
+		a.handleShowDiffCommentDialog(msg)
 	case messages.ShowCommitWorkspaceDialog:
-		a.handleShowCommitWorkspaceDialog(msg)
+		*cmds = append(*cmds, a.commitAfterChecks(msg))
 	case messages.ShowTrustScriptsDialog:
 		a.handleShowTrustScriptsDialog(msg)
 	case messages.ShowRemoveProjectDialog:
@@ -311,6 +420,8 @@ func (a *App) updateDialogShowMsg(msg tea.Msg, cmds *[]tea.Cmd) bool {
 		if cmd := a.handleThemePreview(msg); cmd != nil {
 			*cmds = append(*cmds, cmd)
 		}
+	case common.LatencyPreview:
+		a.handleLatencyPreview(msg)
 	case common.SettingsResult:
 		if cmd := a.handleSettingsResult(msg); cmd != nil {
 			*cmds = append(*cmds, cmd)
@@ -0,0 +1,151 @@
+package app
+
+import (
+	tea "charm.land/bubbletea/v2"
+
+	"github.com/andyrewlee/amux/internal/messages"
+	"github.com/andyrewlee/amux/internal/ui/common"
+	"github.com/andyrewlee/amux/internal/ui/sidebar"
+)
+
+// updatePanelMsg handles the results of the panels and assistants opened from
+// the prefix palette, which all come back as commands run off the UI
+// goroutine. It follows the updateXMsg contract in app_input_dispatch.go.
+//
+// Routing map:
+//
+//	testsFinished, checksPlanned, checksFinished
+//	                       → app_test_panel.go, app_checks.go
+//	codeNavLoaded          → app_code_nav.go
+//	hunksLoaded, hunkApplied, SendReviewComments, SendToAgent
+//	                       → app_hunk_review.go, app_review_comments.go,
+//	                         app_agent_send.go
+//	comparisonLoaded, TakeFromComparison, comparisonTaken
+//	                       → app_compare.go
+//	mergePlanned, branchMerged, mergeContinued, mergeChecked, mergeRolledBack
+//	                       → app_merge_assistant.go, app_merge_assistant_pause.go
+//	fanOutTargets, fanOutLaunched, monorepoSuggested, monorepoNarrowed,
+//	monorepoLaunched       → app_fanout.go, app_monorepo.go
+//	codeBlockSaved, codeBlockApplied, imageAttached, dictationEnded
+//	                       → app_code_blocks.go, app_attach_image.go,
+//	                         app_dictation.go
+//	worktreeHistoryLoaded  → app_worktree_history.go
+//	commitDrafted, agentDraftRequested, changelogDrafted, changelogWritten
+//	                       → app_commit_message.go, app_changelog.go
+//	containerSpecLoaded, containerStarted, containerStopped, gpuSampled
+//	                       → app_container.go, app_gpu.go
+//	benchmarkLaunched, benchmarkChecked
+//	                       → app_benchmark.go
+//	bisectStarted, bisectTested, bisectVerdictPolled, bisectMarked
+//	                       → app_bisect.go, app_bisect_steps.go
+//	stackLoaded, stackRebased, stackPushed
+//	                       → app_stack.go, app_stack_ops.go
+//	askAnswered            → app_ask.go
+//	sidebar.GitToolExited  → app_git_tool.go
+//	SearchQueryChanged, fileSearchDue, fileSearchLoaded, SearchPanelResult
+//	                       → app_file_search.go, app_recent_errors.go
+func (a *App) updatePanelMsg(msg tea.Msg, cmds *[]tea.Cmd) bool {
+	switch msg := msg.(type) {
+	case testsFinished:
+		*cmds = append(*cmds, a.handleTestsFinished(msg))
+	case checksPlanned:
+		*cmds = append(*cmds, a.handleChecksPlanned(msg))
+	case checksFinished:
+		*cmds = append(*cmds, a.handleChecksFinished(msg))
+	case codeNavLoaded:
+		*cmds = append(*cmds, a.handleCodeNavLoaded(msg))
+	case hunksLoaded:
+		*cmds = append(*cmds, a.handleHunksLoaded(msg))
+	case hunkApplied:
+		*cmds = append(*cmds, a.handleHunkApplied(msg))
+	case messages.SendReviewComments:
+		*cmds = append(*cmds, a.handleSendReviewComments(msg))
+	case messages.SendToAgent:
+		*cmds = append(*cmds, a.handleSendToAgent(msg))
+	case comparisonLoaded:
+		*cmds = append(*cmds, a.handleComparisonLoaded(msg))
+	case messages.TakeFromComparison:
+		*cmds = append(*cmds, a.handleTakeFromComparison(msg))
+	case comparisonTaken:
+		*cmds = append(*cmds, a.handleComparisonTaken(msg))
+	case mergePlanned:
+		*cmds = append(*cmds, a.handleMergePlanned(msg))
+	case branchMerged:
+		*cmds = append(*cmds, a.handleBranchMerged(msg))
+	case mergeContinued:
+		*cmds = append(*cmds, a.handleMergeContinued(msg))
+	case mergeChecked:
+		*cmds = append(*cmds, a.handleMergeChecked(msg))
+	case mergeRolledBack:
+		*cmds = append(*cmds, a.handleMergeRolledBack(msg))
+	case fanOutTargets:
+		*cmds = append(*cmds, a.handleFanOutTargets(msg))
+	case fanOutLaunched:
+		*cmds = append(*cmds, a.handleFanOutLaunched(msg))
+	case monorepoSuggested:
+		*cmds = append(*cmds, a.handleMonorepoSuggested(msg))
+	case monorepoNarrowed:
+		*cmds = append(*cmds, a.handleMonorepoNarrowed(msg))
+	case monorepoLaunched:
+		*cmds = append(*cmds, a.handleMonorepoLaunched(msg))
+	case codeBlockSaved:
+		*cmds = append(*cmds, a.handleCodeBlockSaved(msg))
+	case codeBlockApplied:
+		*cmds = append(*cmds, a.handleCodeBlockApplied(msg))
+	case imageAttached:
+		*cmds = append(*cmds, a.handleImageAttached(msg))
+	case dictationEnded:
+		*cmds = append(*cmds, a.handleDictationEnded(msg))
+	case worktreeHistoryLoaded:
+		*cmds = append(*cmds, a.handleWorktreeHistoryLoaded(msg))
+	case commitDrafted:
+		*cmds = append(*cmds, a.handleCommitDrafted(msg))
+	case agentDraftRequested:
+		*cmds = append(*cmds, a.handleAgentDraftRequested(msg))
+	case changelogDrafted:
+		*cmds = append(*cmds, a.handleChangelogDrafted(msg))
+	case changelogWritten:
+		*cmds = append(*cmds, a.handleChangelogWritten(msg))
+	case containerSpecLoaded:
+		*cmds = append(*cmds, a.handleContainerSpecLoaded(msg))
+	case containerStarted:
+		*cmds = append(*cmds, a.handleContainerStarted(msg))
+	case containerStopped:
+		*cmds = append(*cmds, a.handleContainerStopped(msg))
+	case gpuSampled:
+		*cmds = append(*cmds, a.handleGPUSampled(msg))
+	case benchmarkLaunched:
+		*cmds = append(*cmds, a.handleBenchmarkLaunched(msg))
+	case benchmarkChecked:
+		*cmds = append(*cmds, a.handleBenchmarkChecked(msg))
+	case bisectStarted:
+		*cmds = append(*cmds, a.handleBisectStarted(msg))
+	case bisectTested:
+		*cmds = append(*cmds, a.handleBisectTested(msg))
+	case bisectVerdictPolled:
+		*cmds = append(*cmds, a.handleBisectVerdictPolled(msg))
+	case bisectMarked:
+		*cmds = append(*cmds, a.handleBisectMarked(msg))
+	case stackLoaded:
+		*cmds = append(*cmds, a.handleStackLoaded(msg))
+	case stackRebased:
+		*cmds = append(*cmds, a.handleStackRebased(msg))
+	case stackPushed:
+		*cmds = append(*cmds, a.handleStackPushed(msg))
+	case sidebar.GitToolExited:
+		*cmds = append(*cmds, a.handleGitToolExited(msg))
+	case askAnswered:
+		*cmds = append(*cmds, a.handleAskAnswered(msg))
+	case common.SearchQueryChanged:
+		*cmds = append(*cmds, a.handleSearchQueryChanged(msg))
+	case fileSearchDue:
+		*cmds = append(*cmds, a.handleFileSearchDue(msg))
+	case fileSearchLoaded:
+		*cmds = append(*cmds, a.handleFileSearchLoaded(msg))
+	case common.SearchPanelResult:
+		*cmds = append(*cmds, a.handleSearchPanelResult(msg))
+	default:
+		return false
+	}
+	return true
+}
@@ -0,0 +1,59 @@
+package app
+
+import (
+	"fmt"
+	"strings"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/charmbracelet/x/ansi"
+
+	"github.com/andyrewlee/amux/internal/audit"
+	"github.com/andyrewlee/amux/internal/inputhistory"
+	"github.com/andyrewlee/amux/internal/messages"
+	"github.com/andyrewlee/amux/internal/ui/common"
+)
+
+// inputHistoryLabelWidth bounds a prompt's first line in the history list.
+const inputHistoryLabelWidth = 70
+
+// showInputHistory lists the prompts typed into agent tabs, newest first,
+// for recalling one into the active tab. The filter matches workspace and
+// tab names as well as the prompt, so it searches across tabs.
+func (a *App) showInputHistory() tea.Cmd {
+	entries := a.center.InputHistory()
+	if len(entries) == 0 {
+		return a.toast.ShowInfo("No prompts typed into agent tabs yet")
+	}
+	options := make([]string, len(entries))
+	for i, e := range entries {
+		options[i] = inputHistoryLabel(e)
+	}
+	a.inputHistory = entries
+	a.dialog = common.NewListDialog(DialogInputHistory, "Input History",
+		"Prompts typed into agent tabs, newest first. The chosen one is pasted into the active tab without sending it.", options)
+	a.presentDialog(a.dialog)
+	return nil
+}
+
+func inputHistoryLabel(e inputhistory.Entry) string {
+	text, _, multiline := strings.Cut(e.Text, "\n")
+	text = ansi.Truncate(text, inputHistoryLabelWidth, "…")
+	if multiline {
+		text += fmt.Sprintf(" (+%d lines)", strings.Count(e.Text, "\n"))
+	}
+	return fmt.Sprintf("%s/%s  %s  %s", e.Workspace, e.Tab, e.At.Format("15:04"), text)
+}
+
+// handleInputHistoryChoice pastes the chosen prompt into the active center
+// tab, leaving it to be edited or sent.
+func (a *App) handleInputHistoryChoice(index int) tea.Cmd {
+	entries := a.inputHistory
+	a.inputHistory = nil
+	if index < 0 || index >= len(entries) {
+		return nil
+	}
+	return common.SafeBatch(
+		a.focusPane(messages.PaneCenter),
+		a.startAuditedPaste(audit.SourceHistory, messages.PaneCenter, entries[index].Text),
+	)
+}
@@ -0,0 +1,31 @@
+package app
+
+import (
+	"testing"
+
+	"github.com/andyrewlee/amux/internal/audit"
+	"github.com/andyrewlee/amux/internal/inputhistory"
+)
+
+func TestInputHistoryChoiceRecordsAudit(t *testing.T) {
+	app, _, auditPath := newAuditedPasteTestApp(t)
+	app.inputHistory = []inputhistory.Entry{{Text: "run the tests"}, {Text: "fix the lint"}}
+
+	if cmd := app.handleInputHistoryChoice(1); cmd == nil {
+		t.Fatal("expected a paste command")
+	}
+	if app.inputHistory != nil {
+		t.Fatal("choosing an entry should clear the history list")
+	}
+
+	entries, err := audit.Read(auditPath)
+	if err != nil {
+		t.Fatalf("Read() error = %v", err)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("entries = %d, want 1", len(entries))
+	}
+	if e := entries[0]; e.Source != audit.SourceHistory || e.Target != "amux-feat-0" || e.Bytes != len("fix the lint") {
+		t.Fatalf("entry = %+v", e)
+	}
+}
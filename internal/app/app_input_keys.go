@@ -30,6 +30,10 @@ func (a *App) syncActiveWorkspacesToDashboard() {
 	}
 	a.dashboard.SetActiveWorkspaces(activeWorkspaces)
 	a.emitDashboardStateCmd(a.dashboard.SetAgentStates(a.tmuxActivity.agentStates))
+	a.notifyDoneEdges(a.tmuxActivity.agentStates)
+	a.feedAgentEdges(a.tmuxActivity.agentStates)
+	a.followAttention()
+	a.emitDashboardStateCmd(a.syncTerminalBadge())
 }
 
 // emitDashboardStateCmd delivers a fire-and-forget command produced by a
@@ -58,8 +62,8 @@ func (a *App) handleKeyPress(msg tea.KeyPressMsg) tea.Cmd {
 	if a.isPrefixKey(msg) {
 		if a.prefixActive {
 			if len(a.prefixSequence) == 0 {
-				// Prefix + Prefix = send literal Ctrl+Space to terminal.
-				a.sendPrefixToTerminal()
+				// Prefix + Prefix = send the literal leader to the terminal.
+				a.sendPrefixToTerminal(a.leaderLiteral())
 				a.exitPrefix()
 				return nil
 			}
@@ -168,11 +172,21 @@ func (a *App) handleWindowSize(msg tea.WindowSizeMsg) {
 }
 
 func (a *App) handlePaste(msg tea.PasteMsg) tea.Cmd {
+	if cmd, held := a.interceptLargePaste(msg); held {
+		return cmd
+	}
+	if cmd, held := a.interceptMultilinePaste(msg); held {
+		return cmd
+	}
 	switch a.focusedPane {
 	case messages.PaneCenter:
 		newCenter, cmd := a.center.Update(msg)
 		a.center = newCenter
 		return cmd
+	case messages.PaneSidebar:
+		newSidebar, cmd := a.sidebar.Update(msg)
+		a.sidebar = newSidebar
+		return cmd
 	case messages.PaneSidebarTerminal:
 		newTerm, cmd := a.sidebarTerminal.Update(msg)
 		a.sidebarTerminal = newTerm
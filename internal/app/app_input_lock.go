@@ -0,0 +1,122 @@
+package app
+
+import (
+	tea "charm.land/bubbletea/v2"
+	"charm.land/lipgloss/v2"
+
+	"github.com/andyrewlee/amux/internal/messages"
+	"github.com/andyrewlee/amux/internal/ui/common"
+)
+
+// Input mode line and input lock. The mode line sits on the bottom border at
+// the right edge of the screen and names the focused pane and whether keys go
+// to a terminal or to amux itself. The input lock pins focus to the focused
+// terminal: clicks and focus commands aimed at other panes are refused until
+// the lock is released with prefix L.
+
+// isTerminalPane reports whether pane hosts terminals.
+func isTerminalPane(pane messages.PaneType) bool {
+	return pane == messages.PaneCenter || pane == messages.PaneSidebarTerminal
+}
+
+// keysGoToTerminal reports whether typed keys currently reach a terminal.
+func (a *App) keysGoToTerminal() bool {
+	if a.prefixActive || a.overlayVisible() {
+		return false
+	}
+	switch a.focusedPane {
+	case messages.PaneCenter:
+		return a.center != nil && a.center.HasActiveTerminal()
+	case messages.PaneSidebarTerminal:
+		return true
+	default:
+		return false
+	}
+}
+
+func paneDisplayName(pane messages.PaneType) string {
+	switch pane {
+	case messages.PaneDashboard:
+		return "dashboard"
+	case messages.PaneCenter:
+		return "center"
+	case messages.PaneSidebar:
+		return "sidebar"
+	case messages.PaneSidebarTerminal:
+		return "sidebar terminal"
+	default:
+		return "unknown"
+	}
+}
+
+// inputModeLine renders the mode line text, e.g. " center · keys → terminal ".
+func (a *App) inputModeLine() string {
+	target := "amux"
+	targetColor := common.ColorMuted()
+	if a.keysGoToTerminal() {
+		target = "terminal"
+		targetColor = common.ColorPrimary()
+	}
+	muted := lipgloss.NewStyle().Foreground(common.ColorMuted())
+	line := muted.Render(" "+paneDisplayName(a.focusedPane)+" · keys → ") +
+		lipgloss.NewStyle().Bold(true).Foreground(targetColor).Render(target)
+	if a.inputLockActive() {
+		line += muted.Render(" · ") + lipgloss.NewStyle().Bold(true).Foreground(common.ColorWarning()).Render("locked")
+	}
+	return line + " "
+}
+
+// composeInputModeLine draws the mode line over the bottom border, right
+// aligned, so it stays in the same place whichever pane has focus.
+func (a *App) composeInputModeLine(canvas *lipgloss.Canvas) {
+	if a.layout == nil || a.prefixActive {
+		a.renderCache.inputMode.get("", 0, 0)
+		return
+	}
+	line := a.inputModeLine()
+	width := lipgloss.Width(line)
+	x := a.width - a.layout.RightGutter() - width - 2
+	y := a.layout.TopGutter() + a.layout.Height() - 1
+	if x < a.layout.LeftGutter()+2 || y < 0 {
+		a.renderCache.inputMode.get("", 0, 0)
+		return
+	}
+	if drawable := a.renderCache.inputMode.get(line, x, y); drawable != nil {
+		canvas.Compose(drawable)
+	}
+}
+
+// inputLockActive reports whether focus is pinned to the focused terminal.
+func (a *App) inputLockActive() bool {
+	return a.inputLocked && isTerminalPane(a.focusedPane)
+}
+
+// inputLockRefuses reports whether the lock refuses moving focus to target.
+func (a *App) inputLockRefuses(target messages.PaneType) bool {
+	return a.inputLockActive() && target != a.focusedPane
+}
+
+// toggleInputLock locks input to the focused terminal, or releases the lock.
+func (a *App) toggleInputLock() tea.Cmd {
+	if a.inputLockActive() {
+		a.inputLocked = false
+		return a.showInputLockToast("Input unlocked")
+	}
+	if !isTerminalPane(a.focusedPane) {
+		return a.showInputLockToast("Focus a terminal to lock input")
+	}
+	a.inputLocked = true
+	return a.showInputLockToast("Input locked to " + paneDisplayName(a.focusedPane) + " (prefix L to unlock)")
+}
+
+// refuseFocusChange explains why focus did not move while locked.
+func (a *App) refuseFocusChange() tea.Cmd {
+	return a.showInputLockToast("Input locked to " + paneDisplayName(a.focusedPane) + " (prefix L to unlock)")
+}
+
+func (a *App) showInputLockToast(message string) tea.Cmd {
+	if a.toast == nil {
+		return nil
+	}
+	return a.toast.ShowInfo(message)
+}
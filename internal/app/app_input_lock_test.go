@@ -0,0 +1,62 @@
+package app
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/x/ansi"
+
+	"github.com/andyrewlee/amux/internal/messages"
+)
+
+func TestInputLockPinsFocusToTerminal(t *testing.T) {
+	app := &App{focusedPane: messages.PaneDashboard}
+
+	app.toggleInputLock()
+	if app.inputLockActive() {
+		t.Fatal("lock should require a focused terminal")
+	}
+
+	app.focusedPane = messages.PaneSidebarTerminal
+	app.toggleInputLock()
+	if !app.inputLockActive() {
+		t.Fatal("expected lock on the focused terminal")
+	}
+	app.runPrefixAction("focus_left")
+	if app.focusedPane != messages.PaneSidebarTerminal {
+		t.Fatalf("focus moved to %v while locked", app.focusedPane)
+	}
+	if !app.inputLockRefuses(messages.PaneDashboard) || app.inputLockRefuses(messages.PaneSidebarTerminal) {
+		t.Fatal("lock should refuse other panes only")
+	}
+
+	app.runPrefixAction("toggle_input_lock")
+	if app.inputLockActive() {
+		t.Fatal("prefix L should release the lock")
+	}
+}
+
+func TestInputLockReleasedWhenFocusLeavesTerminals(t *testing.T) {
+	app := &App{focusedPane: messages.PaneCenter, inputLocked: true}
+	app.setFocusedPane(messages.PaneDashboard)
+	if app.inputLocked {
+		t.Fatal("lock should not survive focus moving off terminals")
+	}
+}
+
+func TestInputModeLineNamesPaneAndTarget(t *testing.T) {
+	app := &App{focusedPane: messages.PaneSidebarTerminal}
+	if got := ansi.Strip(app.inputModeLine()); got != " sidebar terminal · keys → terminal " {
+		t.Fatalf("mode line = %q", got)
+	}
+
+	app.inputLocked = true
+	if got := ansi.Strip(app.inputModeLine()); !strings.HasSuffix(got, "· locked ") {
+		t.Fatalf("locked mode line = %q", got)
+	}
+
+	app.setFocusedPane(messages.PaneDashboard)
+	if got := ansi.Strip(app.inputModeLine()); got != " dashboard · keys → amux " {
+		t.Fatalf("dashboard mode line = %q", got)
+	}
+}
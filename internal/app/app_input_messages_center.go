@@ -3,6 +3,7 @@ package app
 import (
 	tea "charm.land/bubbletea/v2"
 
+	"github.com/andyrewlee/amux/internal/data"
 	"github.com/andyrewlee/amux/internal/logging"
 	"github.com/andyrewlee/amux/internal/messages"
 )
@@ -20,7 +21,12 @@ func (a *App) handleLaunchAgent(msg messages.LaunchAgent) tea.Cmd {
 	logging.Info("Launching agent: %s", msg.Assistant)
 	newCenter, cmd := a.center.Update(msg)
 	a.center = newCenter
-	return cmd
+	if msg.Workspace == nil {
+		return cmd
+	}
+	return tea.Batch(cmd,
+		a.recordLaunch(msg.Workspace, data.Launch{Kind: data.LaunchAgent, Name: msg.Assistant}),
+		a.recordHistory(msg.Workspace, data.HistoryAgentLaunched, msg.Assistant))
 }
 
 // handleTabCreated handles the TabCreated message.
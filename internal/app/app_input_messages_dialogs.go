@@ -12,6 +12,7 @@ import (
 	"github.com/andyrewlee/amux/internal/messages"
 	"github.com/andyrewlee/amux/internal/process"
 	"github.com/andyrewlee/amux/internal/ui/common"
+	"github.com/andyrewlee/amux/internal/ui/ptyio"
 	"github.com/andyrewlee/amux/internal/validation"
 )
 
@@ -113,30 +114,6 @@ func (a *App) handleShowWorkspaceEnvDialog(msg messages.ShowWorkspaceEnvDialog)
 	a.envDialog.Show()
 }
 
-// handleShowCommitWorkspaceDialog shows the commit-message input dialog for a
-// workspace's changes. The message the user types is the confirmation gesture;
-// on confirm handleDialogResult stages and commits via git.CommitAll. Esc
-// cancels. Live validation mirrors the create-workspace dialog (sanitize, then
-// only flag a non-empty value); an empty message is refused by CommitAll.
-func (a *App) handleShowCommitWorkspaceDialog(msg messages.ShowCommitWorkspaceDialog) {
-	a.dialogWorkspace = msg.Workspace
-	a.dialog = common.NewInputDialog(DialogCommitWorkspace, "Commit changes", "Commit message...")
-	a.dialog.SetInputValidate(func(s string) string {
-		s = validation.SanitizeInput(s)
-		if s == "" {
-			return "" // Don't show an error for empty input; block on confirm.
-		}
-		// Defense-in-depth: the message is the argv value of -m so a leading '-'
-		// is never parsed as a flag, but keep the value shape consistent with
-		// ValidateBaseRef and warn the user before they commit.
-		if strings.HasPrefix(s, "-") {
-			return "commit message cannot start with '-'"
-		}
-		return ""
-	})
-	a.presentDialog(a.dialog)
-}
-
 // handleShowTrustScriptsDialog shows the repo script trust confirmation dialog.
 func (a *App) handleShowTrustScriptsDialog(msg messages.ShowTrustScriptsDialog) {
 	a.dialogWorkspace = msg.Workspace
@@ -184,6 +161,15 @@ func (a *App) repoScriptCommandsForTrust(repo string) []string {
 	if config.ArchiveScript != "" {
 		commands = append(commands, config.ArchiveScript)
 	}
+	if config.Test != "" {
+		commands = append(commands, config.Test)
+	}
+	for _, step := range config.Runbook {
+		commands = append(commands, step.Command)
+	}
+	for _, check := range config.Checks {
+		commands = append(commands, check.Command)
+	}
 	return commands
 }
 
@@ -243,6 +229,9 @@ func (a *App) handleShowSelectAssistantDialog() {
 		return
 	}
 	a.dialog = common.NewAgentPicker(a.assistantNames())
+	if label := sandboxToggleLabel(); label != "" && a.pendingWorkspaceProject == nil {
+		a.dialog.SetToggle(label, a.activeWorkspace.Sandbox)
+	}
 	a.presentDialog(a.dialog)
 }
 
@@ -273,6 +262,8 @@ func (a *App) handleShowSettingsDialog() {
 		a.config.UI.TmuxSyncInterval,
 	)
 	a.settingsDialog.SetAssistants(a.config.AssistantNames(), assistantCommandMap(a.config.Assistants))
+	a.settingsLatencyOriginal = ptyio.CurrentLatencyProfile()
+	a.settingsDialog.SetLatencyProfiles(latencyProfileNames(), string(a.settingsLatencyOriginal))
 	a.settingsDialog.SetSession(a.settingsDialogSession)
 	a.settingsDialog.SetSize(a.width, a.height)
 
@@ -318,6 +309,10 @@ func (a *App) persistSettingsThemeIfDirty() tea.Cmd {
 	if !a.settingsThemeDirty {
 		return nil
 	}
+	if a.readOnly {
+		a.settingsThemeDirty = false
+		return a.readOnlyRefusal("save settings")
+	}
 	if err := a.config.SaveUISettings(); err != nil {
 		return common.ReportError("saving theme setting", err, "Failed to save theme setting")
 	}
@@ -389,31 +384,39 @@ func (a *App) handleSettingsResult(res common.SettingsResult) tea.Cmd {
 		// with it (the in-memory config is only mutated below, on confirm).
 		a.applyTheme(a.settingsThemeOriginal)
 		a.settingsThemeDirty = false
+		ptyio.SetLatencyProfile(a.settingsLatencyOriginal)
 		a.settingsDialog = nil
 		a.settingsDialogSession++
 		return nil
 	}
 	tmuxChanged := false
+	latencyChanged := false
 	assistantsChanged := false
 	if a.settingsDialog != nil {
 		a.applyTheme(a.settingsDialog.SelectedTheme())
 		tmuxChanged = a.applySettingsTmux(a.settingsDialog)
+		latencyChanged = a.applySettingsLatency(a.settingsDialog)
 		assistantsChanged = a.applySettingsAssistants(a.settingsDialog)
 	}
 	a.settingsDialog = nil
 	a.settingsDialogSession++
+	if a.readOnly && (a.settingsThemeDirty || tmuxChanged || latencyChanged || assistantsChanged) {
+		a.settingsThemeDirty = false
+		return a.readOnlyRefusal("save settings")
+	}
 
-	// A dirty theme save already persists the whole UI struct (tmux fields
-	// included, since applySettingsTmux wrote them). Only persist separately
-	// when tmux changed but the theme did not. Assistants live in a different
+	// A dirty theme save already persists the whole UI struct (tmux and
+	// latency fields included, since applySettingsTmux/applySettingsLatency
+	// wrote them). Only persist separately when those changed but the theme
+	// did not. Assistants live in a different
 	// config-file section (SaveAssistants, not SaveUISettings), so it is
 	// always persisted independently of the theme/tmux save above.
 	var saveCmd tea.Cmd
 	if a.settingsThemeDirty {
 		saveCmd = a.persistSettingsThemeIfDirty()
-	} else if tmuxChanged {
+	} else if tmuxChanged || latencyChanged {
 		if err := a.config.SaveUISettings(); err != nil {
-			saveCmd = common.ReportError("saving tmux settings", err, "Failed to save tmux settings")
+			saveCmd = common.ReportError("saving settings", err, "Failed to save settings")
 		}
 	}
 	var assistantsSaveCmd tea.Cmd
@@ -41,6 +41,9 @@ func (a *App) handleProjectsLoaded(msg messages.ProjectsLoaded) []tea.Cmd {
 	a.lifecycle.clearCreatedProjectLoadBarriersThrough(loadToken, loadedIdentities)
 	// Request git status for all workspaces
 	cmds = append(cmds, a.scanTmuxActivityNow())
+	if scanCmd := a.scanLeftoverSessions(); scanCmd != nil {
+		cmds = append(cmds, scanCmd)
+	}
 	if gcCmd := a.gcOrphanedTmuxSessions(); gcCmd != nil {
 		cmds = append(cmds, gcCmd)
 	}
@@ -105,7 +108,7 @@ func (a *App) rebindActiveSelectionForLoad(loadToken projectsLoadToken) []tea.Cm
 			if a.lifecycle.shouldRetainCreatedWorkspace(wsID, previous.Root, loadToken) {
 				return cmds
 			}
-			a.goHome()
+			cmds = append(cmds, a.goHome())
 			a.activeProject = nil
 			return cmds
 		}
@@ -327,6 +330,9 @@ func (a *App) handleWorkspaceActivated(msg messages.WorkspaceActivated) []tea.Cm
 	if restoreCmd := a.center.RestoreTabsFromWorkspace(msg.Workspace); restoreCmd != nil {
 		cmds = append(cmds, restoreCmd)
 	}
+	if layoutCmd := a.offerStartupLayout(msg.Workspace); layoutCmd != nil {
+		cmds = append(cmds, layoutCmd)
+	}
 	// Mouse-first behavior: if this workspace already has center chat tabs,
 	// route keyboard input to the active chat tab immediately.
 	if msg.Workspace != nil {
@@ -457,6 +463,7 @@ func (a *App) handleCreateWorkspace(msg messages.CreateWorkspace) []tea.Cmd {
 func (a *App) handleGitStatusResult(msg messages.GitStatusResult) tea.Cmd {
 	newDashboard, cmd := a.dashboard.Update(msg)
 	a.dashboard = newDashboard
+	a.feedGitStatus(msg)
 	if a.activeWorkspace != nil && rootsReferToSameWorkspace(msg.Root, a.activeWorkspace.Root) {
 		a.sidebar.SetGitStatus(msg.Status)
 	}
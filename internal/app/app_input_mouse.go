@@ -71,6 +71,9 @@ func (a *App) routeMouseClick(msg tea.MouseClickMsg) tea.Cmd {
 	}
 
 	targetPane, hasTarget := a.paneForPoint(msg.X, msg.Y)
+	if hasTarget && a.inputLockRefuses(targetPane) {
+		return a.refuseFocusChange()
+	}
 
 	// Left-click updates keyboard focus; other buttons preserve keyboard focus.
 	var focusCmd tea.Cmd
@@ -146,6 +149,7 @@ func (a *App) routeMouseWheel(msg tea.MouseWheelMsg) tea.Cmd {
 		(a.filePicker != nil && a.filePicker.Visible()) ||
 		(a.settingsDialog != nil && a.settingsDialog.Visible()) ||
 		(a.envDialog != nil && a.envDialog.Visible()) ||
+		(a.searchPanel != nil && a.searchPanel.Visible()) ||
 		a.err != nil ||
 		a.toastCoversPoint(msg.X, msg.Y) {
 		// Modal, error, and toast overlays should block background scrolling.
@@ -164,7 +168,7 @@ func (a *App) routeMouseWheel(msg tea.MouseWheelMsg) tea.Cmd {
 		if hoverPane == messages.PaneDashboard {
 			return nil
 		}
-		if !a.canRetargetWheelToPane(hoverPane) {
+		if !a.canRetargetWheelToPane(hoverPane) || a.inputLockRefuses(hoverPane) {
 			return nil
 		}
 		targetPane = hoverPane
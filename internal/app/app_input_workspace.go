@@ -102,7 +102,12 @@ func (a *App) handleWorkspaceCreated(msg messages.WorkspaceCreated) []tea.Cmd {
 		if cmd := a.dashboard.SetWorkspaceCreating(msg.Workspace, false); cmd != nil {
 			cmds = append(cmds, cmd)
 		}
-		cmds = append(cmds, a.runSetupAsync(msg.Workspace))
+		// A monorepo workspace is narrowed to its packages before setup.
+		if cmd := a.narrowMonorepoWorkspace(msg.Workspace); cmd != nil {
+			cmds = append(cmds, cmd)
+		} else {
+			cmds = append(cmds, a.runSetupAsync(msg.Workspace))
+		}
 	}
 	cmds = append(cmds, a.loadProjectsAfterCreate(msg.Workspace))
 	return cmds
@@ -167,9 +172,16 @@ func (a *App) handleWorkspaceDeleted(msg messages.WorkspaceDeleted) []tea.Cmd {
 		// Navigate home only now that the delete is confirmed (moved off the
 		// up-front deleteWorkspace path so a failed delete leaves the user put).
 		if a.activeWorkspace != nil && a.activeWorkspace.Root == msg.Workspace.Root {
-			a.goHome()
+			cmds = append(cmds, a.goHome())
 		}
 		delete(a.lifecycle.dirty, string(msg.Workspace.ID()))
+		if a.lsp != nil {
+			go a.lsp.Stop(msg.Workspace.Root)
+		}
+		delete(a.reviewComments.byWorkspace, string(msg.Workspace.ID()))
+		if a.mergeAssist.target != nil && a.mergeAssist.target.Root == msg.Workspace.Root {
+			a.mergeAssist = mergeAssistState{}
+		}
 		// No trailing tmux cleanup here: the validated delete path already tore
 		// down this workspace's sessions before removing the worktree. Re-running
 		// it after the delete-in-flight flag is cleared would, on a delete-then-
@@ -181,6 +193,9 @@ func (a *App) handleWorkspaceDeleted(msg messages.WorkspaceDeleted) []tea.Cmd {
 		if a.gitStatus != nil {
 			a.gitStatus.Invalidate(msg.Workspace.Root)
 		}
+		if cmd := discardContainer(msg.Workspace); cmd != nil {
+			cmds = append(cmds, cmd)
+		}
 		// Release the deleted workspace's file watch: the worktree is gone, so
 		// keeping the OS watch descriptor only leaks it. Unwatch is idempotent.
 		if a.fileWatcher != nil {
@@ -0,0 +1,259 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+
+	tea "charm.land/bubbletea/v2"
+
+	"github.com/andyrewlee/amux/internal/logging"
+	"github.com/andyrewlee/amux/internal/messages"
+	"github.com/andyrewlee/amux/internal/ui/common"
+)
+
+const (
+	// defaultPasteConfirmBytes is the paste size that asks for confirmation
+	// when ui.paste_confirm_bytes is unset.
+	defaultPasteConfirmBytes = 256 * 1024
+	// largePasteChunkSize bounds each PTY write of a confirmed large paste, so
+	// progress advances steadily and other input can interleave between writes.
+	largePasteChunkSize = 32 * 1024
+)
+
+// Options of the large-paste dialog, in display order.
+const (
+	largePasteOptionPaste = iota
+	largePasteOptionFile
+	largePasteOptionCancel
+)
+
+// largePasteState holds a paste waiting on the confirmation dialog and the
+// chunked write in progress, if any.
+type largePasteState struct {
+	pending     string
+	pendingPane messages.PaneType
+	// preview is a multi-line paste waiting on the preview dialog.
+	preview string
+	job     *largePasteJob
+	nextID  int
+	// files are the temp files pastes were written to, removed at shutdown
+	// if the tab they went to has not removed them already.
+	files []string
+}
+
+// largePasteJob writes a bracketed paste to a PTY in chunks. The sink is bound
+// to the tab focused when the paste was confirmed.
+type largePasteJob struct {
+	id    int
+	sink  func(string) bool
+	data  string
+	sent  int
+	total int
+}
+
+// largePasteProgress reports one chunk written by a largePasteJob.
+type largePasteProgress struct {
+	id int
+	n  int
+	ok bool
+}
+
+// largePasteFileReady reports the temp file a paste was written to.
+type largePasteFileReady struct {
+	pane messages.PaneType
+	path string
+	err  error
+}
+
+// pasteConfirmThreshold returns the paste size that needs confirmation, or 0
+// when confirmation is disabled.
+func (a *App) pasteConfirmThreshold() int {
+	n := defaultPasteConfirmBytes
+	if a.config != nil && a.config.UI.PasteConfirmBytes != 0 {
+		n = a.config.UI.PasteConfirmBytes
+	}
+	if n < 0 {
+		return 0
+	}
+	return n
+}
+
+// interceptLargePaste holds a paste at or over the confirmation threshold and
+// asks what to do with it. It reports whether msg was held.
+func (a *App) interceptLargePaste(msg tea.PasteMsg) (tea.Cmd, bool) {
+	threshold := a.pasteConfirmThreshold()
+	if threshold == 0 || len(msg.Content) < threshold {
+		return nil, false
+	}
+	if a.focusedPane != messages.PaneCenter && a.focusedPane != messages.PaneSidebarTerminal {
+		return nil, false
+	}
+	if a.largePaste.job != nil {
+		return a.toast.ShowWarning("A paste is still being written"), true
+	}
+	a.largePaste.pending = msg.Content
+	a.largePaste.pendingPane = a.focusedPane
+	a.dialog = common.NewSelectDialog(
+		DialogLargePaste,
+		"Large Paste",
+		fmt.Sprintf("Paste %s into the terminal?", formatPasteSize(len(msg.Content))),
+		[]string{"Paste", "Paste as file path", "Cancel"},
+	)
+	a.dialog.SetDefaultOption(largePasteOptionCancel)
+	a.presentDialog(a.dialog)
+	return nil, true
+}
+
+// handleLargePasteChoice acts on the large-paste dialog selection.
+func (a *App) handleLargePasteChoice(index int) tea.Cmd {
+	content, pane := a.largePaste.pending, a.largePaste.pendingPane
+	a.largePaste.pending = ""
+	if content == "" {
+		return nil
+	}
+	switch index {
+	case largePasteOptionPaste:
+		return a.startLargePaste(pane, content)
+	case largePasteOptionFile:
+		return func() tea.Msg {
+			path, err := writePasteFile(content)
+			return largePasteFileReady{pane: pane, path: path, err: err}
+		}
+	}
+	return nil
+}
+
+// startLargePaste begins writing content to the focused terminal of pane in
+// chunks, off the UI goroutine so a slow reader cannot freeze amux.
+func (a *App) startLargePaste(pane messages.PaneType, content string) tea.Cmd {
+	sink := a.pasteSink(pane)
+	if sink == nil {
+		return a.toast.ShowWarning("No terminal to paste into")
+	}
+	data := "\x1b[200~" + content + "\x1b[201~"
+	a.largePaste.nextID++
+	a.largePaste.job = &largePasteJob{id: a.largePaste.nextID, sink: sink, data: data, total: len(content)}
+	logging.Info("Starting chunked paste of %d bytes", len(content))
+	return a.largePasteWriteCmd(a.largePaste.job)
+}
+
+// startAuditedPaste is startLargePaste for text amux sends on its own behalf
+// rather than text the user pasted; it records the paste under source in the
+// audit log.
+func (a *App) startAuditedPaste(source string, pane messages.PaneType, content string) tea.Cmd {
+	if a.pasteSink(pane) != nil {
+		if err := a.auditLog.Record(source, "", a.pasteTarget(pane), content); err != nil {
+			logging.Warn("paste: audit failed: %v", err)
+		}
+	}
+	return a.startLargePaste(pane, content)
+}
+
+// pasteSink returns the paste writer for pane's active terminal, or nil.
+func (a *App) pasteSink(pane messages.PaneType) func(string) bool {
+	switch pane {
+	case messages.PaneCenter:
+		return a.center.ActivePasteSink()
+	case messages.PaneSidebarTerminal:
+		return a.sidebarTerminal.ActivePasteSink()
+	}
+	return nil
+}
+
+// pasteTarget names the tmux session a paste into pane goes to.
+func (a *App) pasteTarget(pane messages.PaneType) string {
+	switch pane {
+	case messages.PaneCenter:
+		return a.center.ActiveSessionName()
+	case messages.PaneSidebarTerminal:
+		return a.sidebarTerminal.ActiveSessionName()
+	}
+	return ""
+}
+
+func (a *App) largePasteWriteCmd(job *largePasteJob) tea.Cmd {
+	chunk := job.data[job.sent:min(job.sent+largePasteChunkSize, len(job.data))]
+	id, sink := job.id, job.sink
+	return func() tea.Msg {
+		return largePasteProgress{id: id, n: len(chunk), ok: sink(chunk)}
+	}
+}
+
+// handleLargePasteProgress advances the chunked paste and updates its
+// progress toast.
+func (a *App) handleLargePasteProgress(msg largePasteProgress) tea.Cmd {
+	job := a.largePaste.job
+	if job == nil || job.id != msg.id {
+		return nil
+	}
+	if !msg.ok {
+		a.largePaste.job = nil
+		return a.toast.ShowError("Paste interrupted: terminal is not accepting input")
+	}
+	job.sent += msg.n
+	if job.sent >= len(job.data) {
+		a.largePaste.job = nil
+		return a.toast.ShowSuccess("Pasted " + formatPasteSize(job.total))
+	}
+	done := min(job.sent, job.total)
+	progress := a.toast.ShowInfo(fmt.Sprintf("Pasting %s / %s (%d%%)",
+		formatPasteSize(done), formatPasteSize(job.total), done*100/job.total))
+	return common.SafeBatch(a.largePasteWriteCmd(job), progress)
+}
+
+// handleLargePasteFileReady pastes the temp file's path in place of the
+// original content. A path pasted into an agent tab is removed when the tab
+// closes; every paste file is removed when amux exits.
+func (a *App) handleLargePasteFileReady(msg largePasteFileReady) tea.Cmd {
+	if msg.err != nil {
+		return common.ReportError("writing paste file", msg.err, "Failed to write paste to a file")
+	}
+	a.largePaste.files = append(a.largePaste.files, msg.path)
+	if a.focusedPane != msg.pane {
+		return a.toast.ShowInfo("Paste saved to " + msg.path)
+	}
+	if msg.pane == messages.PaneCenter {
+		a.center.AttachPasteFile(msg.path)
+	}
+	return a.handlePaste(tea.PasteMsg{Content: msg.path})
+}
+
+// removePasteFiles deletes the paste files still on disk.
+func (a *App) removePasteFiles() {
+	for _, path := range a.largePaste.files {
+		if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			logging.Warn("paste file cleanup failed path=%s error=%v", path, err)
+		}
+	}
+	a.largePaste.files = nil
+}
+
+// writePasteFile saves content to a private temp file and returns its path.
+func writePasteFile(content string) (string, error) {
+	f, err := os.CreateTemp("", "amux-paste-*.txt")
+	if err != nil {
+		return "", err
+	}
+	if _, err := f.WriteString(content); err != nil {
+		_ = f.Close()
+		_ = os.Remove(f.Name())
+		return "", err
+	}
+	if err := f.Close(); err != nil {
+		_ = os.Remove(f.Name())
+		return "", err
+	}
+	return f.Name(), nil
+}
+
+func formatPasteSize(n int) string {
+	switch {
+	case n >= 1024*1024:
+		return fmt.Sprintf("%.1f MB", float64(n)/(1024*1024))
+	case n >= 1024:
+		return fmt.Sprintf("%.1f KB", float64(n)/1024)
+	}
+	return fmt.Sprintf("%d B", n)
+}
@@ -0,0 +1,121 @@
+package app
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	tea "charm.land/bubbletea/v2"
+
+	"github.com/andyrewlee/amux/internal/config"
+	"github.com/andyrewlee/amux/internal/messages"
+	"github.com/andyrewlee/amux/internal/ui/common"
+)
+
+func TestInterceptLargePasteHoldsOnlyOversizedPastes(t *testing.T) {
+	app := &App{
+		toast:       common.NewToastModel(),
+		focusedPane: messages.PaneCenter,
+		config:      &config.Config{UI: config.UISettings{PasteConfirmBytes: 8}},
+	}
+
+	if _, held := app.interceptLargePaste(tea.PasteMsg{Content: "short"}); held {
+		t.Fatal("paste under the threshold should not be held")
+	}
+	if _, held := app.interceptLargePaste(tea.PasteMsg{Content: "longer than eight"}); !held {
+		t.Fatal("paste over the threshold should be held")
+	}
+	if app.dialog == nil || !app.dialog.Visible() {
+		t.Fatal("expected the large-paste dialog to be visible")
+	}
+	if app.largePaste.pending != "longer than eight" || app.largePaste.pendingPane != messages.PaneCenter {
+		t.Fatalf("pending paste = %q for pane %v", app.largePaste.pending, app.largePaste.pendingPane)
+	}
+
+	app.handleDialogResult(common.DialogResult{ID: DialogLargePaste, Confirmed: false})
+	if app.largePaste.pending != "" {
+		t.Fatal("canceling the dialog should drop the pending paste")
+	}
+
+	app.config.UI.PasteConfirmBytes = -1
+	if _, held := app.interceptLargePaste(tea.PasteMsg{Content: "longer than eight"}); held {
+		t.Fatal("a negative threshold should disable confirmation")
+	}
+}
+
+func TestLargePasteWritesBracketedContentInChunks(t *testing.T) {
+	app := &App{toast: common.NewToastModel()}
+	content := strings.Repeat("x", 2*largePasteChunkSize+10)
+	var writes []string
+	app.largePaste.job = &largePasteJob{
+		id:    1,
+		sink:  func(s string) bool { writes = append(writes, s); return true },
+		data:  "\x1b[200~" + content + "\x1b[201~",
+		total: len(content),
+	}
+
+	for i := 0; app.largePaste.job != nil; i++ {
+		if i > 10 {
+			t.Fatal("paste did not finish")
+		}
+		msg := app.largePasteWriteCmd(app.largePaste.job)().(largePasteProgress)
+		app.handleLargePasteProgress(msg)
+	}
+
+	if len(writes) != 3 {
+		t.Fatalf("writes = %d, want 3 chunks", len(writes))
+	}
+	if got := strings.Join(writes, ""); got != "\x1b[200~"+content+"\x1b[201~" {
+		t.Fatalf("written paste length %d, want the bracketed content", len(got))
+	}
+}
+
+func TestLargePasteStopsOnFailedWrite(t *testing.T) {
+	app := &App{toast: common.NewToastModel()}
+	app.largePaste.job = &largePasteJob{id: 3, data: strings.Repeat("x", 3*largePasteChunkSize), total: 3 * largePasteChunkSize}
+
+	app.handleLargePasteProgress(largePasteProgress{id: 2, n: largePasteChunkSize, ok: true})
+	if app.largePaste.job.sent != 0 {
+		t.Fatal("progress for a stale job should be ignored")
+	}
+	app.handleLargePasteProgress(largePasteProgress{id: 3, ok: false})
+	if app.largePaste.job != nil {
+		t.Fatal("a failed write should end the paste")
+	}
+}
+
+func TestWritePasteFileIsPrivate(t *testing.T) {
+	path, err := writePasteFile("secret clipboard")
+	if err != nil {
+		t.Fatalf("writePasteFile() error = %v", err)
+	}
+	t.Cleanup(func() { _ = os.Remove(path) })
+
+	got, err := os.ReadFile(path)
+	if err != nil || string(got) != "secret clipboard" {
+		t.Fatalf("paste file = %q, %v", got, err)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if perm := info.Mode().Perm(); perm != 0o600 {
+		t.Fatalf("paste file mode = %o, want 600", perm)
+	}
+}
+
+func TestPasteFilesRemovedOnShutdown(t *testing.T) {
+	app := &App{toast: common.NewToastModel(), focusedPane: messages.PaneSidebarTerminal}
+	path, err := writePasteFile("clipboard")
+	if err != nil {
+		t.Fatalf("writePasteFile() error = %v", err)
+	}
+	t.Cleanup(func() { _ = os.Remove(path) })
+
+	// The pane lost focus, so the path is only shown; the file still goes.
+	app.handleLargePasteFileReady(largePasteFileReady{pane: messages.PaneCenter, path: path})
+	app.removePasteFiles()
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Fatalf("paste file still on disk after shutdown: %v", err)
+	}
+}
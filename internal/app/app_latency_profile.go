@@ -0,0 +1,73 @@
+package app
+
+import (
+	"github.com/andyrewlee/amux/internal/config"
+	"github.com/andyrewlee/amux/internal/logging"
+	"github.com/andyrewlee/amux/internal/ui/common"
+	"github.com/andyrewlee/amux/internal/ui/ptyio"
+)
+
+// applyLatencyProfileFromConfig installs the configured PTY latency profile.
+// An unknown name is logged and falls back to balanced.
+func applyLatencyProfileFromConfig(cfg *config.Config) {
+	if cfg == nil {
+		return
+	}
+	profile, ok := ptyio.ParseLatencyProfile(cfg.UI.LatencyProfile)
+	if !ok {
+		logging.Warn("Invalid latency_profile %q; using %s", cfg.UI.LatencyProfile, profile)
+	}
+	ptyio.SetLatencyProfile(profile)
+}
+
+// applyReducedMotionFromConfig turns animation off and lowers the render
+// rate when the config asks for reduced motion.
+func applyReducedMotionFromConfig(cfg *config.Config) {
+	if cfg == nil {
+		return
+	}
+	common.SetReducedMotion(cfg.UI.ReducedMotion)
+	ptyio.SetMinimalRedraw(cfg.UI.ReducedMotion)
+}
+
+// latencyProfileNames lists the profiles for the settings dialog.
+func latencyProfileNames() []string {
+	profiles := ptyio.LatencyProfiles()
+	names := make([]string, 0, len(profiles))
+	for _, profile := range profiles {
+		names = append(names, string(profile))
+	}
+	return names
+}
+
+// handleLatencyPreview switches the latency profile live while the settings
+// dialog is open; Esc restores the profile the dialog opened with.
+func (a *App) handleLatencyPreview(msg common.LatencyPreview) {
+	if msg.Session != a.settingsDialogSession {
+		return
+	}
+	if profile, ok := ptyio.ParseLatencyProfile(msg.Profile); ok {
+		ptyio.SetLatencyProfile(profile)
+	}
+}
+
+// applySettingsLatency copies the dialog's latency profile into the in-memory
+// config and reports whether it changed. The profile is already live from the
+// preview; this only decides whether it needs persisting.
+func (a *App) applySettingsLatency(d *common.SettingsDialog) bool {
+	selected := d.SelectedLatencyProfile()
+	if selected == "" {
+		return false
+	}
+	profile, ok := ptyio.ParseLatencyProfile(selected)
+	if !ok {
+		return false
+	}
+	ptyio.SetLatencyProfile(profile)
+	current, _ := ptyio.ParseLatencyProfile(a.config.UI.LatencyProfile)
+	if current == profile {
+		return false
+	}
+	a.config.UI.LatencyProfile = string(profile)
+	return true
+}
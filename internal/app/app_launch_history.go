@@ -0,0 +1,118 @@
+package app
+
+import (
+	"strings"
+
+	tea "charm.land/bubbletea/v2"
+
+	"github.com/andyrewlee/amux/internal/data"
+	"github.com/andyrewlee/amux/internal/messages"
+	"github.com/andyrewlee/amux/internal/ui/common"
+)
+
+// recordLaunch adds l to ws's launch history and schedules a save.
+func (a *App) recordLaunch(ws *data.Workspace, l data.Launch) tea.Cmd {
+	if ws == nil {
+		return nil
+	}
+	ws.RecordLaunch(l)
+	return a.persistWorkspaceTabs(string(ws.ID()))
+}
+
+// showRunCommandDialog asks for a command to run in a new center tab.
+func (a *App) showRunCommandDialog() tea.Cmd {
+	if a.activeWorkspace == nil {
+		return nil
+	}
+	a.dialog = common.NewInputDialog(DialogRunCommand, "Run Command", "Command to run in a new tab...")
+	a.dialogWorkspace = a.activeWorkspace
+	a.presentDialog(a.dialog)
+	return nil
+}
+
+// runCommandTab starts command in a new tab of ws and remembers it.
+func (a *App) runCommandTab(ws *data.Workspace, command string) tea.Cmd {
+	command = strings.TrimSpace(command)
+	if ws == nil || command == "" {
+		return nil
+	}
+	msg := messages.RunCommand{Command: command, Workspace: ws}
+	return common.SafeBatch(
+		func() tea.Msg { return msg },
+		a.recordLaunch(ws, data.Launch{Kind: data.LaunchCommand, Command: command}),
+	)
+}
+
+// launchLabel describes a recorded launch in the relaunch picker.
+func launchLabel(l data.Launch) string {
+	switch l.Kind {
+	case data.LaunchAgent:
+		return "agent: " + l.Name
+	case data.LaunchCommand:
+		return "command: " + l.Command
+	default:
+		return "terminal"
+	}
+}
+
+// relaunchAllLabel is the picker's first option when the history holds more
+// than one launch.
+const relaunchAllLabel = "all of these"
+
+// showRelaunchDialog lists the active workspace's launch history so a
+// previous tab, or the whole set, can be started again.
+func (a *App) showRelaunchDialog() tea.Cmd {
+	ws := a.activeWorkspace
+	if ws == nil || len(ws.Launches) == 0 {
+		return nil
+	}
+	options := make([]string, 0, len(ws.Launches)+1)
+	if len(ws.Launches) > 1 {
+		options = append(options, relaunchAllLabel)
+	}
+	for _, l := range ws.Launches {
+		options = append(options, launchLabel(l))
+	}
+	a.dialog = common.NewListDialog(DialogRelaunch, "Relaunch", "Start a tab this workspace has run before:", options)
+	a.dialogWorkspace = ws
+	a.presentDialog(a.dialog)
+	return nil
+}
+
+// handleRelaunchChoice starts the chosen launch, or every launch oldest
+// first so the tabs come back in the order they were opened.
+func (a *App) handleRelaunchChoice(ws *data.Workspace, index int) tea.Cmd {
+	if ws == nil || index < 0 {
+		return nil
+	}
+	launches := append([]data.Launch(nil), ws.Launches...)
+	if len(launches) > 1 {
+		if index == 0 {
+			var cmds []tea.Cmd
+			for i := len(launches) - 1; i >= 0; i-- {
+				cmds = append(cmds, a.relaunch(ws, launches[i]))
+			}
+			return tea.Sequence(cmds...)
+		}
+		index--
+	}
+	if index >= len(launches) {
+		return nil
+	}
+	return a.relaunch(ws, launches[index])
+}
+
+func (a *App) relaunch(ws *data.Workspace, l data.Launch) tea.Cmd {
+	switch l.Kind {
+	case data.LaunchAgent:
+		return func() tea.Msg { return messages.LaunchAgent{Assistant: l.Name, Workspace: ws} }
+	case data.LaunchCommand:
+		return a.runCommandTab(ws, l.Command)
+	case data.LaunchTerminal:
+		if a.sidebarTerminal == nil {
+			return nil
+		}
+		return common.SafeBatch(a.sidebarTerminal.CreateNewTab(), a.recordLaunch(ws, l))
+	}
+	return nil
+}
@@ -0,0 +1,53 @@
+package app
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/andyrewlee/amux/internal/messages"
+	"github.com/andyrewlee/amux/internal/ui/common"
+)
+
+func TestRelaunchPickerListsHistory(t *testing.T) {
+	h := newDialogHarness(t)
+	ws := harnessWorkspace()
+	h.app.activeWorkspace = ws
+
+	if h.app.showRelaunchDialog(); h.app.dialog != nil && h.app.dialog.Visible() {
+		t.Fatal("relaunch picker should not open without history")
+	}
+
+	h.app.handleLaunchAgent(messages.LaunchAgent{Assistant: "claude", Workspace: ws})
+	h.app.handleDialogResult(common.DialogResult{ID: DialogRunCommand, Confirmed: true, Value: "  npm test -- --watch "})
+	if len(ws.Launches) != 1 {
+		t.Fatalf("run command without a dialog workspace should be ignored, got %+v", ws.Launches)
+	}
+	h.app.showRunCommandDialog()
+	h.app.handleDialogResult(common.DialogResult{ID: DialogRunCommand, Confirmed: true, Value: "  npm test -- --watch "})
+	if len(ws.Launches) != 2 || ws.Launches[0].Command != "npm test -- --watch" {
+		t.Fatalf("launches = %+v, want the trimmed command first", ws.Launches)
+	}
+
+	h.app.showRelaunchDialog()
+	view := dialogView(t, h.app.dialog)
+	for _, want := range []string{relaunchAllLabel, "command: npm test -- --watch", "agent: claude"} {
+		if !strings.Contains(view, want) {
+			t.Fatalf("relaunch picker missing %q, got %q", want, view)
+		}
+	}
+
+	cmd := h.app.handleRelaunchChoice(ws, 2)
+	if cmd == nil {
+		t.Fatal("expected a relaunch command")
+	}
+	msg, ok := cmd().(messages.LaunchAgent)
+	if !ok || msg.Assistant != "claude" || msg.Workspace != ws {
+		t.Fatalf("relaunch of the agent = %#v", msg)
+	}
+	if cmd := h.app.handleRelaunchChoice(ws, 3); cmd != nil {
+		t.Fatal("out-of-range choice should do nothing")
+	}
+	if cmd := h.app.handleRelaunchChoice(ws, 0); cmd == nil {
+		t.Fatal("expected the relaunch-all sequence")
+	}
+}
@@ -0,0 +1,104 @@
+package app
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	tea "charm.land/bubbletea/v2"
+
+	"github.com/andyrewlee/amux/internal/data"
+	"github.com/andyrewlee/amux/internal/process"
+	"github.com/andyrewlee/amux/internal/ui/common"
+)
+
+// layoutLoaded carries a workspace's startup layout read off the UI
+// goroutine. force is set when the user asked for the layout, rather than it
+// being offered on first activation.
+type layoutLoaded struct {
+	workspace *data.Workspace
+	layout    *process.Layout
+	err       error
+	force     bool
+}
+
+// offerStartupLayout reads ws's startup layout the first time ws is
+// activated. A workspace that already has tabs predates its layout, so it is
+// marked as offered without reading.
+func (a *App) offerStartupLayout(ws *data.Workspace) tea.Cmd {
+	if ws == nil || ws.LayoutApplied || !a.tmuxAvailable {
+		return nil
+	}
+	if len(ws.OpenTabs) > 0 || len(ws.Launches) > 0 {
+		ws.LayoutApplied = true
+		return a.persistWorkspaceTabs(string(ws.ID()))
+	}
+	return loadStartupLayout(ws, false)
+}
+
+func loadStartupLayout(ws *data.Workspace, force bool) tea.Cmd {
+	return func() tea.Msg {
+		layout, err := process.LoadLayout(ws)
+		return layoutLoaded{workspace: ws, layout: layout, err: err, force: force}
+	}
+}
+
+// handleLayoutLoaded asks before opening the layout's tabs, since they run
+// commands from the repository.
+func (a *App) handleLayoutLoaded(msg layoutLoaded) tea.Cmd {
+	ws := msg.workspace
+	if ws == nil || a.activeWorkspace != ws {
+		return nil
+	}
+	if msg.err != nil {
+		return common.ReportError(errorContext(errorServiceWorkspace, "loading startup layout"), msg.err, "")
+	}
+	if msg.layout == nil || len(msg.layout.Tabs) == 0 {
+		if msg.force {
+			return a.toast.ShowInfo("No startup layout in .amux/layout.json")
+		}
+		return nil
+	}
+	launches, err := msg.layout.Launches()
+	if err != nil {
+		return common.ReportError(errorContext(errorServiceWorkspace, "loading startup layout"), err, "")
+	}
+	if !msg.force && a.dialog != nil && a.dialog.Visible() {
+		// Leave it for the next activation rather than replace another dialog.
+		return nil
+	}
+
+	labels := make([]string, len(launches))
+	for i, l := range launches {
+		labels[i] = "  " + launchLabel(l)
+	}
+	source := msg.layout.Path
+	if rel, err := filepath.Rel(ws.Root, source); err == nil && !strings.HasPrefix(rel, "..") {
+		source = rel
+	}
+	message := fmt.Sprintf("Open these tabs from %s?\n\n%s", source, strings.Join(labels, "\n"))
+	a.pendingLayout = launches
+	a.dialog = common.NewConfirmDialog(DialogStartupLayout, "Startup Layout", message)
+	a.dialogWorkspace = ws
+	a.presentDialog(a.dialog)
+
+	if ws.LayoutApplied {
+		return nil
+	}
+	ws.LayoutApplied = true
+	return a.persistWorkspaceTabs(string(ws.ID()))
+}
+
+// applyStartupLayout opens the confirmed layout's tabs in order.
+func (a *App) applyStartupLayout(ws *data.Workspace) tea.Cmd {
+	launches := a.pendingLayout
+	a.pendingLayout = nil
+	if ws == nil || a.activeWorkspace != ws {
+		return nil
+	}
+	cmds := make([]tea.Cmd, 0, len(launches))
+	for _, l := range launches {
+		cmds = append(cmds, a.relaunch(ws, l))
+	}
+	return tea.Sequence(cmds...)
+}
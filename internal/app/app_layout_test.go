@@ -0,0 +1,72 @@
+package app
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/andyrewlee/amux/internal/data"
+	"github.com/andyrewlee/amux/internal/messages"
+	"github.com/andyrewlee/amux/internal/ui/common"
+)
+
+func TestStartupLayoutOfferedOnFirstActivation(t *testing.T) {
+	h := newDialogHarness(t)
+	ws := &data.Workspace{Name: "feature", Repo: t.TempDir(), Root: t.TempDir()}
+	if err := os.MkdirAll(filepath.Join(ws.Root, ".amux"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	layout := `{"tabs":[{"agent":"claude"},{"command":"npm run dev"}]}`
+	if err := os.WriteFile(filepath.Join(ws.Root, ".amux", "layout.json"), []byte(layout), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	h.app.activeWorkspace = ws
+	h.app.tmuxAvailable = true
+
+	cmd := h.app.offerStartupLayout(ws)
+	if cmd == nil {
+		t.Fatal("expected the layout to be read on first activation")
+	}
+	msg, ok := cmd().(layoutLoaded)
+	if !ok || msg.err != nil || msg.layout == nil {
+		t.Fatalf("load = %#v", msg)
+	}
+	h.app.handleLayoutLoaded(msg)
+	if !ws.LayoutApplied {
+		t.Fatal("offering the layout should mark it applied")
+	}
+	view := dialogView(t, h.app.dialog)
+	for _, want := range []string{"agent: claude", "command: npm run dev", "layout.json"} {
+		if !strings.Contains(view, want) {
+			t.Fatalf("layout dialog missing %q, got %q", want, view)
+		}
+	}
+	if cmd := h.app.offerStartupLayout(ws); cmd != nil {
+		t.Fatal("the layout should only be offered once")
+	}
+
+	if cmd := h.app.handleDialogResult(common.DialogResult{ID: DialogStartupLayout, Confirmed: true}); cmd == nil {
+		t.Fatal("confirming should open the layout's tabs")
+	}
+	if h.app.pendingLayout != nil {
+		t.Fatal("pending layout should be cleared once applied")
+	}
+	if len(ws.Launches) != 1 || ws.Launches[0].Command != "npm run dev" {
+		t.Fatalf("launches = %+v, want the command recorded", ws.Launches)
+	}
+	if msg, ok := h.app.relaunch(ws, data.Launch{Kind: data.LaunchAgent, Name: "claude"})().(messages.LaunchAgent); !ok || msg.Assistant != "claude" {
+		t.Fatalf("agent tab = %#v", msg)
+	}
+}
+
+func TestStartupLayoutSkipsWorkspacesWithTabs(t *testing.T) {
+	h := newDialogHarness(t)
+	h.app.tmuxAvailable = true
+	ws := harnessWorkspace()
+	ws.OpenTabs = []data.TabInfo{{Assistant: "claude"}}
+	h.app.offerStartupLayout(ws)
+	if !ws.LayoutApplied {
+		t.Fatal("a workspace already in use should not be offered its layout")
+	}
+}
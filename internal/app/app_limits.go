@@ -0,0 +1,128 @@
+package app
+
+import (
+	"fmt"
+	"time"
+
+	tea "charm.land/bubbletea/v2"
+
+	"github.com/andyrewlee/amux/internal/feed"
+	"github.com/andyrewlee/amux/internal/limits"
+	"github.com/andyrewlee/amux/internal/logging"
+	"github.com/andyrewlee/amux/internal/notify"
+	"github.com/andyrewlee/amux/internal/ui/common"
+)
+
+// limitsSampleInterval is how often limited agents' usage is sampled.
+const limitsSampleInterval = 10 * time.Second
+
+type limitsTick struct{}
+
+// limitedSession is one sampled agent session whose assistant has limits.
+type limitedSession struct {
+	name        string
+	workspaceID string
+	assistant   string
+	usage       limits.Usage
+}
+
+type limitsSampleResult struct {
+	sessions []limitedSession
+	at       time.Time
+}
+
+func (a *App) startLimitsTicker() tea.Cmd {
+	return common.SafeTick(limitsSampleInterval, func(time.Time) tea.Msg {
+		return limitsTick{}
+	})
+}
+
+// limitedAssistants returns the assistants that have limits configured.
+func (a *App) limitedAssistants() map[string]limits.Limits {
+	if a.config == nil {
+		return nil
+	}
+	out := make(map[string]limits.Limits)
+	for name, cfg := range a.config.Assistants {
+		if !cfg.Limits.IsZero() {
+			out[name] = cfg.Limits
+		}
+	}
+	return out
+}
+
+// handleLimitsTick samples the usage of agents with limits off the UI
+// goroutine. Like the egress sampler, the next tick is armed when the sample
+// lands.
+func (a *App) handleLimitsTick() tea.Cmd {
+	limited := a.limitedAssistants()
+	if len(limited) == 0 || a.tmuxService == nil || !a.tmuxAvailable {
+		return a.startLimitsTicker()
+	}
+	svc := a.tmuxService
+	opts := a.tmuxOptions
+	return func() tea.Msg {
+		result := limitsSampleResult{at: time.Now()}
+		rows, err := svc.SessionsWithTags(map[string]string{"@amux_type": "agent"}, []string{"@amux_workspace", "@amux_assistant"}, opts)
+		if err != nil {
+			logging.Debug("limits: list agent sessions: %v", err)
+			return result
+		}
+		table, err := limits.Snapshot()
+		if err != nil {
+			logging.Debug("limits: %v", err)
+			return result
+		}
+		for _, row := range rows {
+			assistant := row.Tags["@amux_assistant"]
+			if _, ok := limited[assistant]; !ok {
+				continue
+			}
+			pids, err := svc.SessionPanePIDs(row.Name, opts)
+			if err != nil || len(pids) == 0 {
+				continue
+			}
+			result.sessions = append(result.sessions, limitedSession{
+				name:        row.Name,
+				workspaceID: row.Tags["@amux_workspace"],
+				assistant:   assistant,
+				usage:       table.Tree(pids),
+			})
+		}
+		return result
+	}
+}
+
+// handleLimitsSampleResult raises an attention alert on the workspace of any
+// agent that reached one of its limits.
+func (a *App) handleLimitsSampleResult(msg limitsSampleResult) []tea.Cmd {
+	if a.limitsMonitor == nil {
+		a.limitsMonitor = limits.NewMonitor()
+	}
+	limited := a.limitedAssistants()
+	live := make(map[string]bool, len(msg.sessions))
+	var cmds []tea.Cmd
+	for _, s := range msg.sessions {
+		live[s.name] = true
+		for _, breach := range a.limitsMonitor.Check(s.name, limited[s.assistant], s.usage, msg.at) {
+			logging.Warn("limits: %s (%s) reached its %s", s.assistant, s.name, breach)
+			if a.dashboard != nil {
+				a.dashboard.SetAlert(s.workspaceID, string(breach.Resource)+" limit")
+			}
+			a.feedAdd(feed.KindAttention, s.workspaceID, fmt.Sprintf("%s reached its %s", s.assistant, breach))
+			if a.toast != nil {
+				cmds = append(cmds, a.toast.ShowWarning(fmt.Sprintf("%s reached its limit: %s", s.assistant, breach)))
+			}
+			if cmd := a.notify(notify.Notification{
+				Event: notify.EventAttention,
+				Title: "amux: agent needs attention",
+				Body:  fmt.Sprintf("%s in %s reached its %s", s.assistant, a.workspaceLabel(s.workspaceID), breach),
+			}); cmd != nil {
+				cmds = append(cmds, cmd)
+			}
+		}
+	}
+	a.limitsMonitor.Forget(live)
+	a.followAttention()
+	return append(cmds, a.startLimitsTicker())
+}
@@ -0,0 +1,47 @@
+package app
+
+import (
+	"testing"
+	"time"
+
+	"github.com/andyrewlee/amux/internal/limits"
+)
+
+func TestLimitsBreachRaisesAttention(t *testing.T) {
+	h := newDialogHarness(t)
+	ws := harnessWorkspace()
+	wsID := string(ws.ID())
+	assistant := h.app.assistantNames()[0]
+	cfg := h.app.config.Assistants[assistant]
+	cfg.Limits = limits.Limits{MemoryMB: 1000}
+	h.app.config.Assistants[assistant] = cfg
+
+	sample := func(rssMB int) limitsSampleResult {
+		return limitsSampleResult{
+			sessions: []limitedSession{{name: "amux-s1", workspaceID: wsID, assistant: assistant, usage: limits.Usage{RSSMB: rssMB}}},
+			at:       time.Now(),
+		}
+	}
+	if cmds := h.app.handleLimitsSampleResult(sample(100)); len(cmds) != 1 || h.app.dashboard.NeedsAttention(wsID) {
+		t.Fatalf("under the limit: %d cmds, attention %v; want only the re-armed ticker", len(cmds), h.app.dashboard.NeedsAttention(wsID))
+	}
+	if cmds := h.app.handleLimitsSampleResult(sample(990)); len(cmds) != 2 {
+		t.Fatalf("at the limit: %d cmds, want a warning toast and the ticker", len(cmds))
+	}
+	if !h.app.dashboard.NeedsAttention(wsID) {
+		t.Fatal("a breach should flag the workspace for attention")
+	}
+	if cmds := h.app.handleLimitsSampleResult(sample(995)); len(cmds) != 1 {
+		t.Fatalf("still at the limit: %d cmds, want no repeat warning", len(cmds))
+	}
+}
+
+func TestLimitsTickSkipsWhenNothingIsLimited(t *testing.T) {
+	h := newDialogHarness(t)
+	if len(h.app.limitedAssistants()) != 0 {
+		t.Fatal("default assistants should have no limits")
+	}
+	if cmd := h.app.handleLimitsTick(); cmd == nil {
+		t.Fatal("expected the ticker to be re-armed")
+	}
+}
@@ -0,0 +1,68 @@
+package app
+
+import (
+	"strings"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/charmbracelet/colorprofile"
+
+	"github.com/andyrewlee/amux/internal/config"
+	"github.com/andyrewlee/amux/internal/logging"
+	"github.com/andyrewlee/amux/internal/ui/compositor"
+	"github.com/andyrewlee/amux/internal/ui/ptyio"
+)
+
+// lowBandwidthFPS caps the frame rate in low-bandwidth mode.
+const lowBandwidthFPS = 15
+
+// lowBandwidthEnabled resolves the low-bandwidth setting. AMUX_LOW_BANDWIDTH
+// overrides the config for one run; "auto" turns the mode on inside an SSH
+// session.
+func lowBandwidthEnabled(cfg *config.Config, getenv func(string) string) bool {
+	setting := strings.TrimSpace(getenv("AMUX_LOW_BANDWIDTH"))
+	if setting == "" && cfg != nil {
+		setting = cfg.UI.LowBandwidth
+	}
+	switch strings.ToLower(strings.TrimSpace(setting)) {
+	case "", "off", "0", "false":
+		return false
+	case "on", "1", "true":
+		return true
+	case "auto":
+		return getenv("SSH_CONNECTION") != "" || getenv("SSH_TTY") != ""
+	default:
+		logging.Warn("Invalid low_bandwidth %q; using off", setting)
+		return false
+	}
+}
+
+// applyLowBandwidth draws terminals with fewer style changes and redraws
+// them less often. Run it after applyReducedMotionFromConfig, which it
+// only adds to.
+func applyLowBandwidth(on bool) {
+	compositor.SetLowBandwidth(on)
+	if on {
+		ptyio.SetMinimalRedraw(true)
+	}
+}
+
+// ProgramOptions returns the Bubble Tea options the app's settings call for:
+// in low-bandwidth mode, 256 colors and a lower frame rate.
+func (a *App) ProgramOptions() []tea.ProgramOption {
+	if !a.lowBandwidth {
+		return nil
+	}
+	return []tea.ProgramOption{
+		tea.WithColorProfile(colorprofile.ANSI256),
+		tea.WithFPS(lowBandwidthFPS),
+	}
+}
+
+// mouseMode turns mouse tracking off in low-bandwidth mode, where every
+// motion event is a round trip.
+func (a *App) mouseMode() tea.MouseMode {
+	if a.lowBandwidth {
+		return tea.MouseModeNone
+	}
+	return tea.MouseModeCellMotion
+}
@@ -0,0 +1,45 @@
+package app
+
+import (
+	"testing"
+
+	tea "charm.land/bubbletea/v2"
+
+	"github.com/andyrewlee/amux/internal/config"
+)
+
+func TestLowBandwidthEnabled(t *testing.T) {
+	env := func(vars map[string]string) func(string) string {
+		return func(k string) string { return vars[k] }
+	}
+	cfg := &config.Config{UI: config.UISettings{LowBandwidth: "auto"}}
+	ssh := map[string]string{"SSH_CONNECTION": "10.0.0.2 50000 10.0.0.1 22"}
+
+	if !lowBandwidthEnabled(cfg, env(ssh)) {
+		t.Fatal("auto should turn on over SSH")
+	}
+	if lowBandwidthEnabled(cfg, env(nil)) {
+		t.Fatal("auto should stay off locally")
+	}
+	if !lowBandwidthEnabled(&config.Config{}, env(map[string]string{"AMUX_LOW_BANDWIDTH": "1"})) {
+		t.Fatal("AMUX_LOW_BANDWIDTH=1 should force it on")
+	}
+	ssh["AMUX_LOW_BANDWIDTH"] = "off"
+	if lowBandwidthEnabled(cfg, env(ssh)) {
+		t.Fatal("AMUX_LOW_BANDWIDTH=off should override the config")
+	}
+	if lowBandwidthEnabled(&config.Config{UI: config.UISettings{LowBandwidth: "sometimes"}}, env(nil)) {
+		t.Fatal("an unknown setting should be off")
+	}
+}
+
+func TestLowBandwidthDisablesMouse(t *testing.T) {
+	a := &App{}
+	if a.mouseMode() != tea.MouseModeCellMotion || a.ProgramOptions() != nil {
+		t.Fatal("normal mode should track the mouse with default options")
+	}
+	a.lowBandwidth = true
+	if a.mouseMode() != tea.MouseModeNone || len(a.ProgramOptions()) != 2 {
+		t.Fatal("low-bandwidth mode should drop mouse tracking and cap colors and fps")
+	}
+}
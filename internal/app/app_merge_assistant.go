@@ -0,0 +1,377 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	tea "charm.land/bubbletea/v2"
+
+	"github.com/andyrewlee/amux/internal/data"
+	"github.com/andyrewlee/amux/internal/git"
+	"github.com/andyrewlee/amux/internal/process"
+	"github.com/andyrewlee/amux/internal/ui/common"
+)
+
+// Options of the merge assistant's dialogs.
+const (
+	mergeOptionStart    = "Start merging"
+	mergeOptionContinue = "Continue"
+	mergeOptionAskAgent = "Ask agent to resolve"
+	mergeOptionSkip     = "Skip branch"
+	mergeOptionKeep     = "Keep merge"
+	mergeOptionUndo     = "Undo merge"
+	mergeOptionStop     = "Stop"
+)
+
+var (
+	mergeConflictOptions = []string{mergeOptionContinue, mergeOptionAskAgent, mergeOptionSkip, mergeOptionStop}
+	mergeChecksOptions   = []string{mergeOptionKeep, mergeOptionUndo, mergeOptionStop}
+)
+
+// mergeAssistState lands a queue of workspace branches, one at a time, on
+// the branch of an integration workspace. paused names the dialog the run
+// is waiting on, so it can be shown again after being dismissed.
+type mergeAssistState struct {
+	target  *data.Workspace
+	offered []*data.Workspace
+	queue   []*data.Workspace
+	steps   []mergeStep
+	index   int
+	checks  *process.CheckSet
+	running bool
+	paused  string
+	// head is the target's commit before the current merge.
+	head      string
+	conflicts []string
+	failed    []string
+}
+
+// mergeOutcome is what became of one queued branch.
+type mergeOutcome int
+
+const (
+	mergeNotRun mergeOutcome = iota
+	mergeMerged
+	mergeUpToDate
+	mergeSkipped
+	mergeUndone
+	mergeFailed
+)
+
+// mergeStep is a queued branch's outcome, with a note such as which checks
+// failed.
+type mergeStep struct {
+	outcome mergeOutcome
+	note    string
+}
+
+// mergePlanned carries the target's checks, loaded off the UI goroutine.
+// dirty is set when the target has uncommitted changes.
+type mergePlanned struct {
+	target *data.Workspace
+	checks *process.CheckSet
+	dirty  bool
+	err    error
+}
+
+// branchMerged reports merging the current branch of the queue.
+type branchMerged struct {
+	target *data.Workspace
+	result git.MergeResult
+	err    error
+}
+
+// mergeContinued reports committing a merge whose conflicts were resolved,
+// with the conflicts that remain when it could not be.
+type mergeContinued struct {
+	target    *data.Workspace
+	conflicts []string
+	err       error
+}
+
+// mergeChecked carries the checks run after a merge.
+type mergeChecked struct {
+	target  *data.Workspace
+	results []process.CheckResult
+}
+
+// mergeRolledBack reports aborting or undoing the current merge.
+type mergeRolledBack struct {
+	target *data.Workspace
+	step   mergeStep
+	stop   bool
+	err    error
+}
+
+// showMergeAssistant starts picking branches to merge into ws, or shows the
+// paused run's dialog again.
+func (a *App) showMergeAssistant(ws *data.Workspace, project *data.Project) tea.Cmd {
+	if ws == nil || project == nil {
+		return nil
+	}
+	m := &a.mergeAssist
+	switch {
+	case m.running:
+		return a.toast.ShowInfo("Merging into " + m.target.Name + " is in progress")
+	case m.paused != "":
+		return a.showMergePause()
+	}
+	a.mergeAssist = mergeAssistState{target: ws}
+	for i := range project.Workspaces {
+		other := &project.Workspaces[i]
+		if other.Root != ws.Root && other.Branch != "" && other.Branch != ws.Branch {
+			a.mergeAssist.offered = append(a.mergeAssist.offered, other)
+		}
+	}
+	if len(a.mergeAssist.offered) == 0 {
+		a.mergeAssist = mergeAssistState{}
+		return a.toast.ShowInfo("No other workspace branch in " + project.Name + " to merge")
+	}
+	return a.showMergeQueue()
+}
+
+// showMergeQueue lists the branches queued so far and those left to add.
+// Once one is queued, the first option starts merging.
+func (a *App) showMergeQueue() tea.Cmd {
+	m := &a.mergeAssist
+	var b strings.Builder
+	fmt.Fprintf(&b, "Pick branches to merge into %s, in order. Only committed work is merged.", m.target.Branch)
+	var options []string
+	if len(m.queue) > 0 {
+		b.WriteString("\n")
+		for i, ws := range m.queue {
+			fmt.Fprintf(&b, "\n%d. %s  (%s)", i+1, ws.Name, ws.Branch)
+		}
+		options = append(options, mergeOptionStart)
+	}
+	for _, ws := range m.offered {
+		options = append(options, fmt.Sprintf("%s  (%s)", ws.Name, ws.Branch))
+	}
+	a.dialog = common.NewListDialog(DialogMergeQueue, "Merge Branches into "+m.target.Name, b.String(), options)
+	a.dialogWorkspace = m.target
+	a.presentDialog(a.dialog)
+	return nil
+}
+
+// handleMergeQueueChoice queues the chosen branch, or starts merging.
+func (a *App) handleMergeQueueChoice(ws *data.Workspace, index int) tea.Cmd {
+	m := &a.mergeAssist
+	if ws == nil || m.target != ws || index < 0 {
+		return nil
+	}
+	if len(m.queue) > 0 {
+		if index == 0 {
+			return a.planMerges("")
+		}
+		index--
+	}
+	if index >= len(m.offered) {
+		return nil
+	}
+	m.queue = append(m.queue, m.offered[index])
+	m.offered = append(m.offered[:index:index], m.offered[index+1:]...)
+	return a.showMergeQueue()
+}
+
+// planMerges checks the target is clean and loads its checks off the UI
+// goroutine. With trustHash set, the repo's scripts are trusted first.
+func (a *App) planMerges(trustHash string) tea.Cmd {
+	m := &a.mergeAssist
+	if m.target == nil || len(m.queue) == 0 {
+		return nil
+	}
+	m.running = true
+	target := m.target
+	var scripts *process.ScriptRunner
+	if a.workspaceService != nil {
+		scripts = a.workspaceService.scripts
+	}
+	return func() tea.Msg {
+		planned := mergePlanned{target: target}
+		status, err := git.GetStatusFast(target.Root)
+		if err != nil {
+			planned.err = err
+			return planned
+		}
+		if planned.dirty = !status.Clean; planned.dirty || scripts == nil {
+			return planned
+		}
+		if trustHash != "" {
+			if planned.err = scripts.TrustRepoScriptsIfHash(target.Repo, trustHash); planned.err != nil {
+				return planned
+			}
+		}
+		planned.checks, planned.err = scripts.Checks(target)
+		if errors.Is(planned.err, process.ErrNoChecks) {
+			planned.checks, planned.err = nil, nil
+		}
+		return planned
+	}
+}
+
+// handleMergePlanned starts merging the queue once the target is clean and
+// its scripts are trusted.
+func (a *App) handleMergePlanned(msg mergePlanned) tea.Cmd {
+	m := &a.mergeAssist
+	if msg.target == nil || m.target != msg.target {
+		return nil
+	}
+	m.running = false
+	var trustErr *process.ScriptsNotTrustedError
+	switch {
+	case msg.dirty:
+		a.mergeAssist = mergeAssistState{}
+		return a.toast.ShowWarning("Commit or stash the changes in " + msg.target.Name + " before merging into it")
+	case errors.As(msg.err, &trustErr):
+		a.showTrustAndRunDialog(msg.target, trustErr.ConfigHash, "run its checks between merges", func(_ *data.Workspace, trustHash string) tea.Cmd {
+			return a.planMerges(trustHash)
+		})
+		return nil
+	case msg.err != nil:
+		a.mergeAssist = mergeAssistState{}
+		return common.ReportError(errorContext(errorServiceWorkspace, "preparing to merge"), msg.err, "")
+	}
+	m.checks = msg.checks
+	m.steps = make([]mergeStep, len(m.queue))
+	return a.mergeNext()
+}
+
+// mergeNext merges the next queued branch, or reports the run after the
+// last.
+func (a *App) mergeNext() tea.Cmd {
+	m := &a.mergeAssist
+	if m.index >= len(m.queue) {
+		return a.finishMerges()
+	}
+	m.running = true
+	target, branch := m.target, m.queue[m.index]
+	merge := func() tea.Msg {
+		result, err := git.MergeBranch(context.Background(), target.Root, branch.Branch)
+		return branchMerged{target: target, result: result, err: err}
+	}
+	label := fmt.Sprintf("Merging %s (%d/%d)...", branch.Name, m.index+1, len(m.queue))
+	return common.SafeBatch(a.toast.ShowInfo(label), merge)
+}
+
+// handleBranchMerged checks a clean merge, and pauses on conflicts.
+func (a *App) handleBranchMerged(msg branchMerged) tea.Cmd {
+	m := &a.mergeAssist
+	if msg.target == nil || m.target != msg.target || m.index >= len(m.queue) {
+		return nil
+	}
+	m.running = false
+	m.head = msg.result.Head
+	switch {
+	case errors.Is(msg.err, git.ErrMergeConflict):
+		m.conflicts = msg.result.Conflicts
+		m.paused = DialogMergeConflict
+		return a.showMergePause()
+	case msg.err != nil:
+		m.steps[m.index] = mergeStep{outcome: mergeFailed, note: msg.err.Error()}
+		return a.finishMerges()
+	case msg.result.UpToDate:
+		m.steps[m.index] = mergeStep{outcome: mergeUpToDate}
+		m.index++
+		return a.mergeNext()
+	}
+	return a.checkMerge()
+}
+
+// checkMerge runs the target's checks against the merge just made, or moves
+// on when the project has none.
+func (a *App) checkMerge() tea.Cmd {
+	m := &a.mergeAssist
+	if m.checks == nil || a.workspaceService == nil || a.workspaceService.scripts == nil {
+		m.steps[m.index] = mergeStep{outcome: mergeMerged}
+		m.index++
+		return a.mergeNext()
+	}
+	m.running = true
+	target, checks, scripts := m.target, m.checks, a.workspaceService.scripts
+	run := func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), checksRunTimeout)
+		defer cancel()
+		return mergeChecked{target: target, results: scripts.RunChecks(ctx, target.Root, checks)}
+	}
+	return common.SafeBatch(a.toast.ShowInfo("Running checks after merging "+m.queue[m.index].Name+"..."), run)
+}
+
+// handleMergeChecked moves on when every check passed, and pauses when one
+// did not.
+func (a *App) handleMergeChecked(msg mergeChecked) tea.Cmd {
+	m := &a.mergeAssist
+	if msg.target == nil || m.target != msg.target || m.index >= len(m.queue) {
+		return nil
+	}
+	m.running = false
+	failed := checksRun{results: msg.results}.failed()
+	if len(failed) == 0 {
+		m.steps[m.index] = mergeStep{outcome: mergeMerged, note: "checks passed"}
+		m.index++
+		return a.mergeNext()
+	}
+	m.failed = failed
+	m.paused = DialogMergeChecks
+	return a.showMergePause()
+}
+
+// finishMerges reports what became of each queued branch.
+func (a *App) finishMerges() tea.Cmd {
+	m := a.mergeAssist
+	a.mergeAssist = mergeAssistState{}
+	if m.target == nil {
+		return nil
+	}
+	a.dialog = common.NewSelectDialog(DialogMergeSummary, "Merge Summary", mergeSummary(m), []string{"Done"})
+	a.dialogWorkspace = m.target
+	a.presentDialog(a.dialog)
+	return nil
+}
+
+// mergeSummary lists each queued branch's outcome under a count of those
+// merged.
+func mergeSummary(m mergeAssistState) string {
+	merged := 0
+	var lines []string
+	for i, ws := range m.queue {
+		var step mergeStep
+		if i < len(m.steps) {
+			step = m.steps[i]
+		}
+		var mark, text string
+		switch step.outcome {
+		case mergeNotRun:
+			mark, text = "·", "not merged"
+		case mergeMerged:
+			merged++
+			mark, text = "✓", "merged"
+		case mergeUpToDate:
+			mark, text = "✓", "already merged"
+		case mergeSkipped:
+			mark, text = "✗", "skipped"
+		case mergeUndone:
+			mark, text = "✗", "undone"
+		case mergeFailed:
+			mark, text = "✗", "failed"
+		}
+		if step.note != "" {
+			text += ", " + step.note
+		}
+		lines = append(lines, fmt.Sprintf("%s %s  %s", mark, ws.Name, text))
+	}
+	return fmt.Sprintf("Merged %d of %d branches into %s.\n\n%s", merged, len(m.queue), m.target.Branch, strings.Join(lines, "\n"))
+}
+
+// mergeConflictPrompt asks the integration workspace's agent to resolve a
+// merge's conflicts without committing, so the assistant can continue it.
+func mergeConflictPrompt(branch *data.Workspace, conflicts []string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Merging branch %s into this worktree stopped on conflicts in:\n\n", branch.Branch)
+	for _, path := range conflicts {
+		fmt.Fprintf(&b, "- %s\n", path)
+	}
+	b.WriteString("\nResolve each conflict, keeping the intent of both sides, and stage the files with git add. Do not commit or abort the merge.\n")
+	return b.String()
+}
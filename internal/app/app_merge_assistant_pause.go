@@ -0,0 +1,153 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	tea "charm.land/bubbletea/v2"
+
+	"github.com/andyrewlee/amux/internal/data"
+	"github.com/andyrewlee/amux/internal/git"
+	"github.com/andyrewlee/amux/internal/ui/common"
+)
+
+// showMergePause asks what to do about the current merge's conflicts or
+// failed checks.
+func (a *App) showMergePause() tea.Cmd {
+	m := &a.mergeAssist
+	if m.target == nil || m.index >= len(m.queue) {
+		return nil
+	}
+	branch := m.queue[m.index]
+	switch m.paused {
+	case DialogMergeConflict:
+		message := fmt.Sprintf("Merging %s into %s stopped on conflicts in:\n\n%s\n\nResolve and stage them in %s, then continue.",
+			branch.Name, m.target.Branch, strings.Join(m.conflicts, "\n"), m.target.Name)
+		a.dialog = common.NewSelectDialog(DialogMergeConflict, "Merge Conflict", message, mergeConflictOptions)
+	case DialogMergeChecks:
+		message := fmt.Sprintf("After merging %s, these checks failed: %s.", branch.Name, strings.Join(m.failed, ", "))
+		a.dialog = common.NewSelectDialog(DialogMergeChecks, "Checks Failed", message, mergeChecksOptions)
+	default:
+		return nil
+	}
+	a.dialogWorkspace = m.target
+	a.presentDialog(a.dialog)
+	return nil
+}
+
+// handleMergeConflictChoice continues, hands off, skips, or stops at a
+// conflicted merge.
+func (a *App) handleMergeConflictChoice(ws *data.Workspace, index int) tea.Cmd {
+	m := &a.mergeAssist
+	if ws == nil || m.target != ws || m.paused != DialogMergeConflict || index < 0 || index >= len(mergeConflictOptions) {
+		return nil
+	}
+	branch := m.queue[m.index]
+	switch mergeConflictOptions[index] {
+	case mergeOptionContinue:
+		m.paused, m.running = "", true
+		return func() tea.Msg {
+			continued := mergeContinued{target: ws, err: git.ContinueMerge(context.Background(), ws.Root)}
+			if continued.err != nil {
+				continued.conflicts, _ = git.UnmergedFiles(context.Background(), ws.Root)
+			}
+			return continued
+		}
+	case mergeOptionAskAgent:
+		return common.SafeBatch(
+			a.sendToWorkspaceAgent(ws, mergeConflictPrompt(branch, m.conflicts)),
+			a.toast.ShowInfo("Merge paused; prefix M continues once the conflicts are resolved"),
+		)
+	case mergeOptionSkip:
+		return a.rollBackMerge(mergeStep{outcome: mergeSkipped, note: "conflicts"}, false)
+	default:
+		return a.rollBackMerge(mergeStep{outcome: mergeSkipped, note: "conflicts"}, true)
+	}
+}
+
+// handleMergeContinued checks the merge once committed. When conflicts
+// remain, the run stays paused on them.
+func (a *App) handleMergeContinued(msg mergeContinued) tea.Cmd {
+	m := &a.mergeAssist
+	if msg.target == nil || m.target != msg.target || m.index >= len(m.queue) {
+		return nil
+	}
+	m.running = false
+	if msg.err != nil {
+		m.paused = DialogMergeConflict
+		if len(msg.conflicts) > 0 {
+			m.conflicts = msg.conflicts
+		}
+		warn := a.toast.ShowWarning("Could not continue the merge: " + msg.err.Error())
+		return common.SafeBatch(warn, a.showMergePause())
+	}
+	return a.checkMerge()
+}
+
+// handleMergeChecksChoice keeps, undoes, or stops at a merge whose checks
+// failed.
+func (a *App) handleMergeChecksChoice(ws *data.Workspace, index int) tea.Cmd {
+	m := &a.mergeAssist
+	if ws == nil || m.target != ws || m.paused != DialogMergeChecks || index < 0 || index >= len(mergeChecksOptions) {
+		return nil
+	}
+	note := "checks failed: " + strings.Join(m.failed, ", ")
+	m.paused = ""
+	switch mergeChecksOptions[index] {
+	case mergeOptionKeep:
+		m.steps[m.index] = mergeStep{outcome: mergeMerged, note: note}
+		m.index++
+		return a.mergeNext()
+	case mergeOptionUndo:
+		return a.rollBackMerge(mergeStep{outcome: mergeUndone, note: note}, false)
+	default:
+		m.steps[m.index] = mergeStep{outcome: mergeMerged, note: note}
+		return a.finishMerges()
+	}
+}
+
+// rollBackMerge aborts the current merge when it is in progress, or undoes
+// it when committed, recording step for its branch.
+func (a *App) rollBackMerge(step mergeStep, stop bool) tea.Cmd {
+	m := &a.mergeAssist
+	m.paused, m.running = "", true
+	target, head := m.target, m.head
+	return func() tea.Msg {
+		var err error
+		if step.outcome == mergeUndone {
+			err = git.UndoMerge(context.Background(), target.Root, head)
+		} else {
+			err = git.AbortMerge(context.Background(), target.Root)
+		}
+		return mergeRolledBack{target: target, step: step, stop: stop, err: err}
+	}
+}
+
+// handleMergeRolledBack moves on from a branch that was not kept. If the
+// rollback failed, the target's state is unknown and the run stops.
+func (a *App) handleMergeRolledBack(msg mergeRolledBack) tea.Cmd {
+	m := &a.mergeAssist
+	if msg.target == nil || m.target != msg.target || m.index >= len(m.queue) {
+		return nil
+	}
+	m.running = false
+	if msg.err != nil {
+		m.steps[m.index] = mergeStep{outcome: mergeFailed, note: msg.err.Error()}
+		return a.finishMerges()
+	}
+	m.steps[m.index] = msg.step
+	if msg.stop {
+		return a.finishMerges()
+	}
+	m.index++
+	return a.mergeNext()
+}
+
+// handleMergePauseCancel leaves the run paused when its dialog is dismissed.
+func (a *App) handleMergePauseCancel() tea.Cmd {
+	if a.mergeAssist.paused == "" {
+		return nil
+	}
+	return a.toast.ShowInfo("Merge paused; prefix M picks it up again")
+}
@@ -0,0 +1,111 @@
+package app
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/andyrewlee/amux/internal/data"
+	"github.com/andyrewlee/amux/internal/git"
+	"github.com/andyrewlee/amux/internal/process"
+)
+
+func mergeAssistProject(ws *data.Workspace) *data.Project {
+	return &data.Project{Name: "primary", Path: ws.Repo, Workspaces: []data.Workspace{
+		*ws,
+		{Name: "auth", Branch: "auth", Repo: ws.Repo, Root: "/repo/primary/auth"},
+		{Name: "billing", Branch: "billing", Repo: ws.Repo, Root: "/repo/primary/billing"},
+	}}
+}
+
+func TestMergeAssistantFlow(t *testing.T) {
+	h := newDialogHarness(t)
+	ws := harnessWorkspace()
+	ws.Branch = "integration"
+	h.app.showMergeAssistant(ws, mergeAssistProject(ws))
+	view := dialogView(t, h.app.dialog)
+	if !strings.Contains(view, "auth  (auth)") || strings.Contains(view, mergeOptionStart) {
+		t.Fatalf("queue dialog = %q, want the branches and no start option", view)
+	}
+
+	// Queue billing, then auth: the start option now comes first.
+	h.app.handleMergeQueueChoice(ws, 1)
+	h.app.handleMergeQueueChoice(ws, 1)
+	view = dialogView(t, h.app.dialog)
+	if !strings.Contains(view, "1. billing") || !strings.Contains(view, "2. auth") || !strings.Contains(view, mergeOptionStart) {
+		t.Fatalf("queue dialog = %q, want billing then auth queued", view)
+	}
+
+	if cmd := h.app.handleMergePlanned(mergePlanned{target: ws}); cmd == nil || !h.app.mergeAssist.running {
+		t.Fatal("expected the first merge to start")
+	}
+	conflict := fmt.Errorf("merging billing: %w", git.ErrMergeConflict)
+	h.app.handleBranchMerged(branchMerged{target: ws, result: git.MergeResult{Conflicts: []string{"api.go"}}, err: conflict})
+	view = dialogView(t, h.app.dialog)
+	if !strings.Contains(view, "Merging billing into integration") || !strings.Contains(view, "api.go") {
+		t.Fatalf("conflict dialog = %q", view)
+	}
+
+	// Dismissing the dialog pauses the run; the prefix command shows it again.
+	if cmd := h.app.handleMergePauseCancel(); cmd == nil {
+		t.Fatal("expected a paused toast")
+	}
+	h.app.dialog = nil
+	h.app.showMergeAssistant(ws, mergeAssistProject(ws))
+	if view = dialogView(t, h.app.dialog); !strings.Contains(view, "api.go") {
+		t.Fatalf("resumed dialog = %q, want the conflict again", view)
+	}
+
+	skip := slices.Index(mergeConflictOptions, mergeOptionSkip)
+	if cmd := h.app.handleMergeConflictChoice(ws, skip); cmd == nil {
+		t.Fatal("expected the merge to be aborted")
+	}
+	h.app.handleMergeRolledBack(mergeRolledBack{target: ws, step: mergeStep{outcome: mergeSkipped, note: "conflicts"}})
+	h.app.handleBranchMerged(branchMerged{target: ws, result: git.MergeResult{Head: "abc"}})
+
+	view = dialogView(t, h.app.dialog)
+	for _, want := range []string{"Merged 1 of 2 branches into integration", "✗ billing  skipped, conflicts", "✓ auth  merged"} {
+		if !strings.Contains(view, want) {
+			t.Fatalf("summary missing %q, got %q", want, view)
+		}
+	}
+	if h.app.mergeAssist.target != nil {
+		t.Fatal("the run should end with the summary")
+	}
+}
+
+func TestMergeAssistantChecksFailed(t *testing.T) {
+	h := newDialogHarness(t)
+	ws := harnessWorkspace()
+	ws.Branch = "integration"
+	h.app.showMergeAssistant(ws, mergeAssistProject(ws))
+	h.app.handleMergeQueueChoice(ws, 0)
+	h.app.mergeAssist.steps = make([]mergeStep, 1)
+	h.app.mergeAssist.running = true
+
+	failed := []process.CheckResult{{Check: process.Check{Name: "test"}, ExitCode: 1}, {Check: process.Check{Name: "lint"}}}
+	h.app.handleMergeChecked(mergeChecked{target: ws, results: failed})
+	if view := dialogView(t, h.app.dialog); !strings.Contains(view, "these checks failed: test") {
+		t.Fatalf("checks dialog = %q", view)
+	}
+	if cmd := h.app.handleMergeChecksChoice(ws, slices.Index(mergeChecksOptions, mergeOptionUndo)); cmd == nil {
+		t.Fatal("expected the merge to be undone")
+	}
+	h.app.handleMergeRolledBack(mergeRolledBack{target: ws, step: mergeStep{outcome: mergeUndone, note: "checks failed: test"}})
+	if view := dialogView(t, h.app.dialog); !strings.Contains(view, "✗ auth  undone, checks failed: test") {
+		t.Fatalf("summary = %q", view)
+	}
+}
+
+func TestMergeAssistantRefusesDirtyTarget(t *testing.T) {
+	h := newDialogHarness(t)
+	ws := harnessWorkspace()
+	h.app.mergeAssist = mergeAssistState{target: ws, running: true}
+	if cmd := h.app.handleMergePlanned(mergePlanned{target: ws, dirty: true}); cmd == nil {
+		t.Fatal("expected a warning")
+	}
+	if h.app.mergeAssist.target != nil {
+		t.Fatal("a dirty target should end the run")
+	}
+}
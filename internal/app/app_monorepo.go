@@ -0,0 +1,290 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	tea "charm.land/bubbletea/v2"
+
+	"github.com/andyrewlee/amux/internal/data"
+	"github.com/andyrewlee/amux/internal/messages"
+	"github.com/andyrewlee/amux/internal/monorepo"
+	"github.com/andyrewlee/amux/internal/process"
+	"github.com/andyrewlee/amux/internal/ui/common"
+)
+
+// monorepoTimeout bounds reading a monorepo's packages, or scoping and
+// narrowing a worktree to some of them; build tools can be slow to start.
+const monorepoTimeout = 2 * time.Minute
+
+// monorepoState holds the task given for a workspace being created in a
+// monorepo, and the workspaces created for one until their agent starts.
+type monorepoState struct {
+	pending *monorepoRun
+	runs    []*monorepoRun
+}
+
+// monorepoRun is a workspace created for a task in a monorepo: the packages
+// it was scoped to and the agent started with the task.
+type monorepoRun struct {
+	project   *data.Project
+	name      string
+	tool      monorepo.Tool
+	task      string
+	graph     monorepo.Graph
+	targets   []monorepo.Package
+	assistant string
+	sparse    bool
+}
+
+// monorepoSuggested carries a monorepo's packages and those suggested for
+// the task.
+type monorepoSuggested struct {
+	run       *monorepoRun
+	graph     monorepo.Graph
+	suggested []monorepo.Package
+	err       error
+}
+
+// monorepoNarrowed reports the sparse checkout of a new workspace.
+type monorepoNarrowed struct {
+	run  *monorepoRun
+	ws   *data.Workspace
+	dirs []string
+	err  error
+}
+
+// monorepoLaunched reports starting a run's agent.
+type monorepoLaunched struct {
+	run     *monorepoRun
+	ws      *data.Workspace
+	session string
+	err     error
+}
+
+// askMonorepoTask asks for the task of the workspace being created when its
+// project is a monorepo, reporting whether it did.
+func (a *App) askMonorepoTask(project *data.Project, name string) bool {
+	tool := monorepo.Detect(project.Path)
+	if tool == "" {
+		return false
+	}
+	a.monorepo.pending = &monorepoRun{project: project, name: name, tool: tool}
+	a.dialog = common.NewInputDialog(DialogMonorepoTask, "New Workspace: Task",
+		fmt.Sprintf("The agent's task, to check out only the %s packages it touches; empty to skip", tool))
+	a.dialog.SetInputCharLimit(fanOutTaskLimit)
+	a.presentDialog(a.dialog)
+	return true
+}
+
+// handleMonorepoDialog handles the monorepo task and package dialogs,
+// reporting whether result was one of them. Canceling either cancels the
+// workspace.
+func (a *App) handleMonorepoDialog(result common.DialogResult) (tea.Cmd, bool) {
+	if result.ID != DialogMonorepoTask && result.ID != DialogMonorepoTargets {
+		return nil, false
+	}
+	if !result.Confirmed {
+		a.monorepo.pending = nil
+		a.pendingWorkspaceProject, a.pendingWorkspaceName, a.pendingWorkspaceBase = nil, "", ""
+		return nil, true
+	}
+	if result.ID == DialogMonorepoTask {
+		return a.handleMonorepoTask(result.Value), true
+	}
+	return a.handleMonorepoTargets(result.Value), true
+}
+
+// handleMonorepoTask reads the monorepo's packages to suggest those the task
+// touches. Without a task the workspace is a plain one.
+func (a *App) handleMonorepoTask(task string) tea.Cmd {
+	run := a.monorepo.pending
+	if run == nil {
+		return nil
+	}
+	run.task = strings.TrimSpace(task)
+	if run.task == "" {
+		a.monorepo.pending = nil
+		return func() tea.Msg { return messages.ShowSelectAssistantDialog{} }
+	}
+	root := run.project.Path
+	return common.SafeBatch(
+		a.toast.ShowInfo(fmt.Sprintf("Reading the %s packages...", run.tool)),
+		func() tea.Msg {
+			ctx, cancel := context.WithTimeout(context.Background(), monorepoTimeout)
+			defer cancel()
+			graph, err := monorepo.Load(ctx, root)
+			return monorepoSuggested{run: run, graph: graph, suggested: graph.Suggest(run.task), err: err}
+		},
+	)
+}
+
+// handleMonorepoSuggested offers the suggested packages for editing. When
+// the packages can't be read the workspace is a full checkout, and its agent
+// still gets the task.
+func (a *App) handleMonorepoSuggested(msg monorepoSuggested) tea.Cmd {
+	if msg.run == nil || msg.run != a.monorepo.pending {
+		return nil
+	}
+	if msg.err != nil {
+		return common.SafeBatch(
+			a.toast.ShowWarning(fmt.Sprintf("Could not list the %s packages (%v); the workspace will be a full checkout", msg.run.tool, msg.err)),
+			func() tea.Msg { return messages.ShowSelectAssistantDialog{} },
+		)
+	}
+	msg.run.graph = msg.graph
+	names := make([]string, 0, len(msg.suggested))
+	for _, p := range msg.suggested {
+		names = append(names, p.Name)
+	}
+	a.showMonorepoTargets(msg.graph, strings.Join(names, ", "))
+	return nil
+}
+
+// showMonorepoTargets asks for the packages to check out, prefilled with
+// value, flagging names the graph lacks as they are typed.
+func (a *App) showMonorepoTargets(graph monorepo.Graph, value string) {
+	a.dialog = common.NewInputDialog(DialogMonorepoTargets, "New Workspace: Packages",
+		"Comma-separated packages to check out; empty for a full checkout")
+	a.dialog.SetInputCharLimit(fanOutTaskLimit)
+	a.dialog.SetInputValidate(func(s string) string {
+		if _, unknown := graph.Find(splitVariations(s)); len(unknown) > 0 {
+			return "No package named " + strings.Join(unknown, ", ")
+		}
+		return ""
+	})
+	a.presentDialog(a.dialog)
+	a.dialog.SetInputValue(value)
+}
+
+// handleMonorepoTargets picks the agent next, once every package named is
+// one the monorepo has.
+func (a *App) handleMonorepoTargets(value string) tea.Cmd {
+	run := a.monorepo.pending
+	if run == nil {
+		return nil
+	}
+	targets, unknown := run.graph.Find(splitVariations(value))
+	if len(unknown) > 0 {
+		a.showMonorepoTargets(run.graph, value)
+		return a.toast.ShowWarning("No package named " + strings.Join(unknown, ", "))
+	}
+	run.targets = targets
+	return func() tea.Msg { return messages.ShowSelectAssistantDialog{} }
+}
+
+// queueMonorepoRun records the agent chosen for the workspace being created,
+// so its task is sent once the workspace is set up.
+func (a *App) queueMonorepoRun(project *data.Project, name, assistant string) {
+	run := a.monorepo.pending
+	a.monorepo.pending = nil
+	if run == nil || run.project != project || run.name != name {
+		return
+	}
+	run.assistant = assistant
+	a.monorepo.runs = append(a.monorepo.runs, run)
+}
+
+// monorepoRunFor returns the run creating ws.
+func (a *App) monorepoRunFor(ws *data.Workspace) *monorepoRun {
+	if ws == nil {
+		return nil
+	}
+	for _, run := range a.monorepo.runs {
+		if run.name == ws.Name && data.NormalizePath(run.project.Path) == data.NormalizePath(ws.Repo) {
+			return run
+		}
+	}
+	return nil
+}
+
+func (a *App) dropMonorepoRun(run *monorepoRun) {
+	for i, r := range a.monorepo.runs {
+		if r == run {
+			a.monorepo.runs = append(a.monorepo.runs[:i], a.monorepo.runs[i+1:]...)
+			return
+		}
+	}
+}
+
+// narrowMonorepoWorkspace narrows a new workspace scoped to some packages to
+// them and their dependencies, before its setup runs. It returns nil for
+// any other workspace.
+func (a *App) narrowMonorepoWorkspace(ws *data.Workspace) tea.Cmd {
+	run := a.monorepoRunFor(ws)
+	if run == nil || len(run.targets) == 0 {
+		return nil
+	}
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), monorepoTimeout)
+		defer cancel()
+		dirs, err := run.graph.Scope(ctx, run.targets)
+		if err == nil {
+			err = monorepo.SparseCheckout(ctx, ws.Root, dirs)
+		}
+		return monorepoNarrowed{run: run, ws: ws, dirs: dirs, err: err}
+	}
+}
+
+// handleMonorepoNarrowed runs the workspace's setup, narrowed or not.
+func (a *App) handleMonorepoNarrowed(msg monorepoNarrowed) tea.Cmd {
+	var toast tea.Cmd
+	if msg.err != nil {
+		toast = a.toast.ShowWarning(fmt.Sprintf("Kept a full checkout of %s: %v", msg.ws.Name, msg.err))
+	} else {
+		msg.run.sparse = true
+		toast = a.toast.ShowInfo(fmt.Sprintf("Checked out %d directories in %s", len(msg.dirs), msg.ws.Name))
+	}
+	return common.SafeBatch(toast, a.runSetupAsync(msg.ws))
+}
+
+// handleMonorepoSetupComplete starts the agent of a monorepo run whose
+// workspace finished its setup, with the task and its targets as the first
+// prompt. Setup skipped for untrusted scripts runs again once they are
+// trusted, so the run waits for that.
+func (a *App) handleMonorepoSetupComplete(msg messages.WorkspaceSetupComplete) tea.Cmd {
+	run := a.monorepoRunFor(msg.Workspace)
+	if run == nil {
+		return nil
+	}
+	if msg.Err != nil {
+		if !errors.Is(msg.Err, process.ErrScriptsNotTrusted) {
+			a.dropMonorepoRun(run)
+		}
+		return nil
+	}
+	a.dropMonorepoRun(run)
+	center, ws, assistant := a.center, msg.Workspace, run.assistant
+	prompt := monorepo.Prompt(run.task, run.tool, run.targets, run.sparse)
+	return func() tea.Msg {
+		session, err := center.StartDetachedAgent(ws, assistant, prompt)
+		return monorepoLaunched{run: run, ws: ws, session: session, err: err}
+	}
+}
+
+// handleMonorepoCreateFailed forgets the run of a workspace that could not
+// be created.
+func (a *App) handleMonorepoCreateFailed(msg messages.WorkspaceCreateFailed) {
+	if run := a.monorepoRunFor(msg.Workspace); run != nil {
+		a.dropMonorepoRun(run)
+	}
+}
+
+// handleMonorepoLaunched opens the workspace whose agent started, so its
+// tab shows.
+func (a *App) handleMonorepoLaunched(msg monorepoLaunched) tea.Cmd {
+	if msg.err != nil {
+		return common.ReportError(errorContext(errorServiceWorkspace, "starting monorepo agent"), msg.err, "")
+	}
+	ws, project := a.findWorkspaceAndProjectByID(string(msg.ws.ID()))
+	if ws == nil {
+		return nil
+	}
+	return common.SafeBatch(
+		a.toast.ShowSuccess(fmt.Sprintf("Started %s in %s with the task", msg.run.assistant, ws.Name)),
+		func() tea.Msg { return messages.WorkspaceActivated{Project: project, Workspace: ws} },
+	)
+}
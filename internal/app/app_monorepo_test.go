@@ -0,0 +1,73 @@
+package app
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/andyrewlee/amux/internal/data"
+	"github.com/andyrewlee/amux/internal/messages"
+	"github.com/andyrewlee/amux/internal/monorepo"
+	"github.com/andyrewlee/amux/internal/ui/common"
+)
+
+func TestMonorepoCreateFlow(t *testing.T) {
+	h := newDialogHarness(t)
+	root := t.TempDir()
+	if err := os.WriteFile(filepath.Join(root, "nx.json"), []byte("{}"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	project := &data.Project{Name: "shop", Path: root}
+
+	h.app.dialogProject = project
+	h.app.handleDialogResult(common.DialogResult{ID: DialogCreateWorkspace, Confirmed: true, Value: "dark-mode"})
+	if view := dialogView(t, h.app.dialog); !strings.Contains(view, "New Workspace: Task") {
+		t.Fatalf("dialog = %q, want the task prompt for an Nx project", view)
+	}
+	if cmd := h.app.handleMonorepoTask("Add dark mode to the web app"); cmd == nil {
+		t.Fatal("expected the packages to be read")
+	}
+	run := h.app.monorepo.pending
+	graph := monorepo.Graph{Tool: monorepo.Nx, Root: root, Packages: []monorepo.Package{
+		{Name: "theme", Dir: "libs/theme"},
+		{Name: "web", Dir: "apps/web", Deps: []string{"theme"}},
+	}}
+	h.app.handleMonorepoSuggested(monorepoSuggested{run: run, graph: graph, suggested: graph.Suggest(run.task)})
+	if view := dialogView(t, h.app.dialog); !strings.Contains(view, "New Workspace: Packages") || !strings.Contains(view, "web") {
+		t.Fatalf("packages dialog = %q, want web suggested", view)
+	}
+
+	h.app.handleMonorepoTargets("web, nope")
+	if view := dialogView(t, h.app.dialog); !strings.Contains(view, "New Workspace: Packages") || run.targets != nil {
+		t.Fatalf("dialog = %q, want the packages asked for again", view)
+	}
+	if _, ok := h.app.handleMonorepoTargets("web")().(messages.ShowSelectAssistantDialog); !ok {
+		t.Fatal("expected the agent to be picked next")
+	}
+	create, ok := h.app.handleDialogResult(common.DialogResult{ID: DialogSelectAssistant, Confirmed: true, Value: "claude"})().(messages.CreateWorkspace)
+	if !ok || create.Name != "dark-mode" || len(h.app.monorepo.runs) != 1 || h.app.monorepo.pending != nil {
+		t.Fatalf("create = %+v, runs = %d, want the run queued", create, len(h.app.monorepo.runs))
+	}
+
+	ws := &data.Workspace{Name: "dark-mode", Repo: root, Root: filepath.Join(root, "ws")}
+	if h.app.narrowMonorepoWorkspace(ws) == nil {
+		t.Fatal("expected the workspace to be narrowed before setup")
+	}
+	h.app.handleMonorepoNarrowed(monorepoNarrowed{run: run, ws: ws, dirs: []string{"apps/web", "libs/theme"}})
+	if cmd := h.app.handleMonorepoSetupComplete(messages.WorkspaceSetupComplete{Workspace: ws}); cmd == nil || len(h.app.monorepo.runs) != 0 {
+		t.Fatal("expected the agent to start once setup finished")
+	}
+	if prompt := monorepo.Prompt(run.task, run.tool, run.targets, run.sparse); !strings.Contains(prompt, "- web (apps/web)") || !strings.Contains(prompt, "sparse-checkout") {
+		t.Fatalf("prompt = %q", prompt)
+	}
+}
+
+func TestMonorepoSkippedOutsideMonorepos(t *testing.T) {
+	h := newDialogHarness(t)
+	h.app.dialogProject = &data.Project{Name: "plain", Path: t.TempDir()}
+	cmd := h.app.handleDialogResult(common.DialogResult{ID: DialogCreateWorkspace, Confirmed: true, Value: "feature"})
+	if _, ok := cmd().(messages.ShowSelectAssistantDialog); !ok || h.app.monorepo.pending != nil {
+		t.Fatal("expected the agent dialog straight away outside a monorepo")
+	}
+}
@@ -0,0 +1,159 @@
+package app
+
+import (
+	"context"
+	"os/exec"
+	"strings"
+	"time"
+
+	"charm.land/bubbles/v2/key"
+	tea "charm.land/bubbletea/v2"
+)
+
+const (
+	// defaultLeaderLiteral is the byte a terminal sends for Ctrl-Space.
+	defaultLeaderLiteral = "\x00"
+	// alternateLeaderLiteral is the byte a terminal sends for Ctrl-\.
+	alternateLeaderLiteral = "\x1c"
+)
+
+// Nesting describes the multiplexers amux is running inside.
+type Nesting struct {
+	// Amux is set inside a pane of another amux, which exports AMUX=1.
+	Amux bool
+	// Tmux is set inside a tmux session other than amux's own.
+	Tmux bool
+	// TmuxPrefixes are the outer tmux's prefix keys in tmux notation
+	// (e.g. "C-b"), when they could be read.
+	TmuxPrefixes []string
+}
+
+// DetectNesting reads the environment for an enclosing amux or tmux.
+// tmuxPrefixes is only called inside tmux.
+func DetectNesting(getenv func(string) string, tmuxPrefixes func() []string) Nesting {
+	n := Nesting{
+		Amux: getenv("AMUX") != "",
+		Tmux: getenv("TMUX") != "",
+	}
+	if n.Tmux && tmuxPrefixes != nil {
+		n.TmuxPrefixes = tmuxPrefixes()
+	}
+	return n
+}
+
+// OuterTmuxPrefixes asks the enclosing tmux server for its prefix keys.
+// Unset or unreadable options are left out.
+func OuterTmuxPrefixes() []string {
+	var prefixes []string
+	for _, option := range []string{"prefix", "prefix2"} {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		out, err := exec.CommandContext(ctx, "tmux", "show-options", "-gv", option).Output()
+		cancel()
+		if err != nil {
+			continue
+		}
+		if value := strings.TrimSpace(string(out)); value != "" && value != "None" {
+			prefixes = append(prefixes, value)
+		}
+	}
+	return prefixes
+}
+
+// tmuxPrefixTakesLeader reports whether the outer tmux binds Ctrl-Space,
+// which tmux spells C-Space or C-@.
+func (n Nesting) tmuxPrefixTakesLeader() bool {
+	for _, prefix := range n.TmuxPrefixes {
+		switch prefix {
+		case "C-Space", "C-@":
+			return true
+		}
+	}
+	return false
+}
+
+// LeaderMoved reports whether Ctrl-Space belongs to an enclosing
+// multiplexer, so amux uses Ctrl-\ as its leader instead.
+func (n Nesting) LeaderMoved() bool {
+	return n.Amux || n.tmuxPrefixTakesLeader()
+}
+
+// Warnings describes each way the nesting affects amux's keys.
+func (n Nesting) Warnings() []string {
+	var warnings []string
+	if n.Amux {
+		warnings = append(warnings,
+			"running inside another amux: C-Space goes to the outer amux, so this one's leader is C-\\")
+	}
+	if n.Tmux {
+		switch {
+		case n.tmuxPrefixTakesLeader():
+			warnings = append(warnings,
+				"running inside tmux with prefix C-Space: amux's leader is C-\\ instead")
+		case len(n.TmuxPrefixes) > 0:
+			warnings = append(warnings,
+				"running inside tmux: its prefix "+strings.Join(n.TmuxPrefixes, ", ")+" never reaches amux or its agents")
+		default:
+			warnings = append(warnings,
+				"running inside tmux: its prefix key never reaches amux or its agents")
+		}
+	}
+	return warnings
+}
+
+// alternatePrefixBinding is the leader used when Ctrl-Space is taken.
+// Ctrl-\ is rarely bound by agents or shells in raw mode.
+func alternatePrefixBinding() key.Binding {
+	return key.NewBinding(
+		key.WithKeys("ctrl+\\"),
+		key.WithHelp("C-\\", "Commands"),
+	)
+}
+
+// applyNesting moves the leader off Ctrl-Space when an enclosing
+// multiplexer owns it.
+func (a *App) applyNesting(n Nesting) {
+	a.nesting = n
+	if !n.LeaderMoved() {
+		return
+	}
+	a.keymap.Prefix = alternatePrefixBinding()
+	if a.dashboard != nil {
+		a.dashboard.SetPrefixLabel(a.prefixLabel())
+	}
+}
+
+// prefixLabel is the leader as shown in hints, e.g. "C-Space".
+func (a *App) prefixLabel() string {
+	if label := a.keymap.Prefix.Help().Key; label != "" {
+		return label
+	}
+	return "C-Space"
+}
+
+// leaderLiteral is the byte the leader key sends to a terminal.
+func (a *App) leaderLiteral() string {
+	if a.nesting.LeaderMoved() {
+		return alternateLeaderLiteral
+	}
+	return defaultLeaderLiteral
+}
+
+// sendOuterPrefix passes a Ctrl-Space through to the focused terminal while
+// amux's own leader is Ctrl-\, e.g. for the prefix of an amux running in it.
+func (a *App) sendOuterPrefix() {
+	a.sendPrefixToTerminal(defaultLeaderLiteral)
+}
+
+// nestingNoticeCmd tells the user at startup that the leader moved.
+func (a *App) nestingNoticeCmd() tea.Cmd {
+	if !a.nesting.LeaderMoved() || a.toast == nil {
+		return nil
+	}
+	return a.toast.ShowInfo("Nested in tmux/amux: the leader is " + a.prefixLabel() + "; C-Space goes to the outer one")
+}
+
+// sendOuterPrefixVisible reports whether passing Ctrl-Space through does
+// anything the leader itself doesn't.
+func (a *App) sendOuterPrefixVisible() bool {
+	return a.nesting.LeaderMoved() && isTerminalPane(a.focusedPane)
+}
@@ -0,0 +1,67 @@
+package app
+
+import (
+	"strings"
+	"testing"
+
+	tea "charm.land/bubbletea/v2"
+
+	"github.com/andyrewlee/amux/internal/ui/common"
+)
+
+func envOf(vars map[string]string) func(string) string {
+	return func(name string) string { return vars[name] }
+}
+
+func TestDetectNesting(t *testing.T) {
+	queried := false
+	prefixes := func() []string {
+		queried = true
+		return []string{"C-Space"}
+	}
+
+	if n := DetectNesting(envOf(nil), prefixes); n.Amux || n.Tmux || n.LeaderMoved() || queried {
+		t.Fatalf("no env: got %+v (queried tmux: %v), want no nesting", n, queried)
+	}
+	if n := DetectNesting(envOf(map[string]string{"AMUX": "1"}), prefixes); !n.Amux || !n.LeaderMoved() {
+		t.Fatalf("AMUX=1: got %+v, want nested amux with the leader moved", n)
+	}
+
+	tmuxEnv := envOf(map[string]string{"TMUX": "/tmp/tmux-1000/default,1,0"})
+	if n := DetectNesting(tmuxEnv, prefixes); !n.Tmux || !n.LeaderMoved() {
+		t.Fatalf("tmux with C-Space prefix: got %+v, want the leader moved", n)
+	}
+	n := DetectNesting(tmuxEnv, func() []string { return []string{"C-b"} })
+	if n.LeaderMoved() {
+		t.Fatalf("tmux with C-b prefix: got %+v, want the leader kept", n)
+	}
+	if warnings := n.Warnings(); len(warnings) != 1 || !strings.Contains(warnings[0], "C-b") {
+		t.Fatalf("warnings = %q, want one naming the C-b prefix", warnings)
+	}
+}
+
+func TestApplyNestingMovesLeader(t *testing.T) {
+	app, _, _ := newPrefixTestApp(t)
+	app.toast = common.NewToastModel()
+	ctrlSpace := tea.KeyPressMsg{Code: tea.KeySpace, Mod: tea.ModCtrl}
+	ctrlBackslash := tea.KeyPressMsg{Code: '\\', Mod: tea.ModCtrl}
+
+	app.applyNesting(Nesting{Tmux: true, TmuxPrefixes: []string{"C-b"}})
+	if !app.isPrefixKey(ctrlSpace) || app.leaderLiteral() != "\x00" {
+		t.Fatal("a non-colliding tmux prefix should keep C-Space as the leader")
+	}
+	if app.nestingNoticeCmd() != nil {
+		t.Fatal("no notice expected while the leader is unchanged")
+	}
+
+	app.applyNesting(Nesting{Amux: true})
+	if app.isPrefixKey(ctrlSpace) || !app.isPrefixKey(ctrlBackslash) {
+		t.Fatal("inside amux the leader should be C-\\")
+	}
+	if app.prefixLabel() != "C-\\" || app.leaderLiteral() != "\x1c" {
+		t.Fatalf("label %q literal %q, want C-\\ and 0x1c", app.prefixLabel(), app.leaderLiteral())
+	}
+	if app.nestingNoticeCmd() == nil {
+		t.Fatal("expected a startup notice about the moved leader")
+	}
+}
@@ -0,0 +1,178 @@
+package app
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	tea "charm.land/bubbletea/v2"
+
+	"github.com/andyrewlee/amux/internal/app/activity"
+	"github.com/andyrewlee/amux/internal/data"
+	"github.com/andyrewlee/amux/internal/logging"
+	"github.com/andyrewlee/amux/internal/messages"
+	"github.com/andyrewlee/amux/internal/notify"
+	"github.com/andyrewlee/amux/internal/ui/common"
+)
+
+// exitNotifyDedupWindow drops a second exit notification for a session: both
+// the tmux tab sync and the activity scan report a session that stopped.
+const exitNotifyDedupWindow = time.Minute
+
+// notifyState tracks what the notifications fired for so far.
+type notifyState struct {
+	// agentStates is the last agent state per workspace, to find the
+	// Working→Done edge.
+	agentStates map[string]activity.AgentState
+	// exits records when each session's exit was notified.
+	exits map[string]time.Time
+	// digest batches and paces deliveries; flushAt is when the tick
+	// delivering what it holds is due, zero when none is armed.
+	digest  notify.Digester
+	flushAt time.Time
+}
+
+// notifyRequest carries a notification raised outside Update, so desktop
+// delivery is started from Update rather than run inline.
+type notifyRequest struct {
+	n notify.Notification
+}
+
+// notifyBackend returns the configured delivery backend.
+func (a *App) notifyBackend() notify.Backend {
+	if a.config == nil {
+		return notify.Bell
+	}
+	return notify.ParseBackend(a.config.UI.Notifications)
+}
+
+// notifyEnabled reports whether notifications for e are turned on.
+func (a *App) notifyEnabled(e notify.Event) bool {
+	if a.config == nil {
+		return false
+	}
+	switch e {
+	case notify.EventAttention:
+		return a.config.UI.NotifyOnAttention
+	case notify.EventExit:
+		return a.config.UI.NotifyOnExit
+	case notify.EventDone:
+		return a.config.UI.NotifyOnDone
+	}
+	return false
+}
+
+// notify delivers n if its event is turned on, unless the digest policy holds
+// it for a later digest.
+func (a *App) notify(n notify.Notification) tea.Cmd {
+	if !a.notifyEnabled(n.Event) {
+		return nil
+	}
+	out, ok, next := a.notifications.digest.Offer(n, a.notifyPolicy(), time.Now())
+	var deliver tea.Cmd
+	if ok {
+		deliver = a.deliverNotification(out)
+	}
+	return common.SafeBatch(deliver, a.armDigestFlush(next))
+}
+
+// deliverNotification sends n through the configured backend. Desktop
+// notifications run off the UI goroutine and fall back to a terminal
+// notification when no notifier is installed.
+func (a *App) deliverNotification(n notify.Notification) tea.Cmd {
+	backend := a.notifyBackend()
+	if backend != notify.Auto {
+		return tea.Raw(notify.Sequence(backend, n, os.Getenv))
+	}
+	return func() tea.Msg {
+		ok, err := notify.Desktop(n.Title, n.Body)
+		if err != nil {
+			logging.Warn("notify: desktop notification failed: %v", err)
+		}
+		if ok {
+			return nil
+		}
+		return tea.Raw(notify.Sequence(notify.Terminal, n, os.Getenv))()
+	}
+}
+
+// notifyDoneEdges notifies for each workspace whose agent went from Working
+// to Done since the last call. With the bell backend the dashboard rings the
+// bell for this edge itself, so nothing is sent here.
+func (a *App) notifyDoneEdges(states map[string]activity.AgentState) {
+	prev := a.notifications.agentStates
+	a.notifications.agentStates = states
+	if a.notifyBackend() == notify.Bell || !a.notifyEnabled(notify.EventDone) {
+		return
+	}
+	for wsID, st := range states {
+		if st != activity.StateDone || prev[wsID] != activity.StateWorking {
+			continue
+		}
+		a.enqueueExternalMsg(notifyRequest{n: notify.Notification{
+			Event: notify.EventDone,
+			Title: "amux: agent finished",
+			Body:  a.workspaceLabel(wsID) + " is done",
+		}})
+	}
+}
+
+// notifyAgentExit notifies when an agent tab's tmux session stops.
+func (a *App) notifyAgentExit(msg messages.TabSessionStatus) tea.Cmd {
+	if !a.notifyEnabled(notify.EventExit) {
+		return nil
+	}
+	ws, assistant := a.stoppedAgentTab(msg)
+	if ws == nil || !firstExitReport(&a.notifications.exits, msg.SessionName, time.Now()) {
+		return nil
+	}
+	return a.notify(notify.Notification{
+		Event: notify.EventExit,
+		Title: "amux: agent exited",
+		Body:  fmt.Sprintf("%s in %s", assistant, ws.Name),
+	})
+}
+
+// stoppedAgentTab returns the workspace and assistant of the agent tab whose
+// session msg reports stopped, or nil when msg is about anything else.
+func (a *App) stoppedAgentTab(msg messages.TabSessionStatus) (*data.Workspace, string) {
+	if msg.Status != "stopped" {
+		return nil, ""
+	}
+	ws := a.findWorkspaceByID(msg.WorkspaceID)
+	if ws == nil {
+		return nil, ""
+	}
+	for _, tab := range ws.OpenTabs {
+		if tab.SessionName == msg.SessionName && a.isAgentTab(tab.Assistant) {
+			return ws, tab.Assistant
+		}
+	}
+	return nil, ""
+}
+
+// firstExitReport records session's exit in seen and reports whether it is
+// the first report of it within exitNotifyDedupWindow.
+func firstExitReport(seen *map[string]time.Time, session string, now time.Time) bool {
+	if at, ok := (*seen)[session]; ok && now.Sub(at) < exitNotifyDedupWindow {
+		return false
+	}
+	if *seen == nil {
+		*seen = make(map[string]time.Time)
+	}
+	for name, at := range *seen {
+		if now.Sub(at) >= exitNotifyDedupWindow {
+			delete(*seen, name)
+		}
+	}
+	(*seen)[session] = now
+	return true
+}
+
+// workspaceLabel names a workspace in notifications, falling back to its ID.
+func (a *App) workspaceLabel(wsID string) string {
+	if ws := a.findWorkspaceByID(wsID); ws != nil {
+		return ws.Name
+	}
+	return wsID
+}
@@ -0,0 +1,55 @@
+package app
+
+import (
+	"time"
+
+	tea "charm.land/bubbletea/v2"
+
+	"github.com/andyrewlee/amux/internal/notify"
+	"github.com/andyrewlee/amux/internal/ui/common"
+)
+
+// notifyDigestTick delivers the notifications the digest held back.
+type notifyDigestTick struct{}
+
+// notifyPolicy reads the digest window, rate limit, and quiet hours from the
+// config. Values that don't parse are ignored.
+func (a *App) notifyPolicy() notify.Policy {
+	p := notify.Policy{Channel: a.notifyBackend()}
+	if a.config == nil {
+		return p
+	}
+	ui := a.config.UI
+	if d, err := time.ParseDuration(ui.NotifyDigestWindow); err == nil && d > 0 {
+		p.Window = d
+	}
+	p.PerMinute = ui.NotifyRateLimits.For(string(p.Channel))
+	if q, err := notify.ParseQuietHours(ui.NotifyQuietHours); err == nil {
+		p.Quiet = q
+	}
+	return p
+}
+
+// armDigestFlush schedules a digest tick at next unless one is already due
+// by then. A zero next means nothing is held.
+func (a *App) armDigestFlush(next time.Time) tea.Cmd {
+	if next.IsZero() || (!a.notifications.flushAt.IsZero() && !next.Before(a.notifications.flushAt)) {
+		return nil
+	}
+	a.notifications.flushAt = next
+	return common.SafeTick(time.Until(next), func(time.Time) tea.Msg {
+		return notifyDigestTick{}
+	})
+}
+
+// handleNotifyDigestTick delivers what the digest held, if the policy now
+// allows it, and re-arms the tick for anything still held.
+func (a *App) handleNotifyDigestTick() tea.Cmd {
+	a.notifications.flushAt = time.Time{}
+	out, ok, next := a.notifications.digest.Flush(a.notifyPolicy(), time.Now())
+	var deliver tea.Cmd
+	if ok {
+		deliver = a.deliverNotification(out)
+	}
+	return common.SafeBatch(deliver, a.armDigestFlush(next))
+}
@@ -0,0 +1,130 @@
+package app
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	tea "charm.land/bubbletea/v2"
+
+	"github.com/andyrewlee/amux/internal/app/activity"
+	"github.com/andyrewlee/amux/internal/config"
+	"github.com/andyrewlee/amux/internal/data"
+	"github.com/andyrewlee/amux/internal/messages"
+	"github.com/andyrewlee/amux/internal/notify"
+)
+
+func newNotifyTestApp(ui config.UISettings) (*App, *data.Workspace) {
+	ws := &data.Workspace{
+		Name: "feature",
+		Repo: "/repo",
+		Root: "/repo/feature",
+		OpenTabs: []data.TabInfo{
+			{Assistant: "claude", SessionName: "amux-agent"},
+			{Assistant: "bash", SessionName: "amux-shell"},
+		},
+	}
+	app := &App{
+		config: &config.Config{
+			Assistants: map[string]config.AssistantConfig{"claude": {}},
+			UI:         ui,
+		},
+		projects:     []data.Project{{Path: "/repo", Workspaces: []data.Workspace{*ws}}},
+		externalMsgs: make(chan tea.Msg, 4),
+	}
+	return app, &app.projects[0].Workspaces[0]
+}
+
+func rawNotification(t *testing.T, cmd tea.Cmd) string {
+	t.Helper()
+	if cmd == nil {
+		t.Fatal("expected a notification command")
+	}
+	raw, ok := cmd().(tea.RawMsg)
+	if !ok {
+		t.Fatalf("notification produced %T, want tea.RawMsg", cmd())
+	}
+	return raw.Msg.(string)
+}
+
+func TestNotifyAgentExit(t *testing.T) {
+	app, ws := newNotifyTestApp(config.UISettings{NotifyOnExit: true, Notifications: "terminal"})
+	stopped := func(session string) messages.TabSessionStatus {
+		return messages.TabSessionStatus{WorkspaceID: string(ws.ID()), SessionName: session, Status: "stopped"}
+	}
+
+	got := rawNotification(t, app.notifyAgentExit(stopped("amux-agent")))
+	if !strings.Contains(got, "claude in feature") {
+		t.Fatalf("exit notification = %q, want the agent and workspace", got)
+	}
+	if cmd := app.notifyAgentExit(stopped("amux-agent")); cmd != nil {
+		t.Fatal("a second report of the same exit should not notify again")
+	}
+	if cmd := app.notifyAgentExit(stopped("amux-shell")); cmd != nil {
+		t.Fatal("a shell tab exiting should not notify")
+	}
+
+	app.config.UI.NotifyOnExit = false
+	app.notifications.exits = nil
+	if cmd := app.notifyAgentExit(stopped("amux-agent")); cmd != nil {
+		t.Fatal("exit notifications should follow notify_on_exit")
+	}
+}
+
+func TestNotifyDoneEdges(t *testing.T) {
+	app, ws := newNotifyTestApp(config.UISettings{NotifyOnDone: true, Notifications: "terminal"})
+	wsID := string(ws.ID())
+
+	app.notifyDoneEdges(map[string]activity.AgentState{wsID: activity.StateWorking})
+	app.notifyDoneEdges(map[string]activity.AgentState{wsID: activity.StateDone})
+	app.notifyDoneEdges(map[string]activity.AgentState{wsID: activity.StateDone})
+	if len(app.externalMsgs) != 1 {
+		t.Fatalf("queued %d notifications, want 1 for the Working→Done edge", len(app.externalMsgs))
+	}
+	req := (<-app.externalMsgs).(notifyRequest)
+	if req.n.Event != notify.EventDone || req.n.Body != "feature is done" {
+		t.Fatalf("notification = %+v", req.n)
+	}
+
+	// The bell backend leaves the edge to the dashboard.
+	app.config.UI.Notifications = ""
+	app.notifyDoneEdges(map[string]activity.AgentState{wsID: activity.StateWorking})
+	app.notifyDoneEdges(map[string]activity.AgentState{wsID: activity.StateDone})
+	if len(app.externalMsgs) != 0 {
+		t.Fatal("the bell backend should not queue done notifications")
+	}
+}
+
+func TestNotifyDigestsWithinWindow(t *testing.T) {
+	app, _ := newNotifyTestApp(config.UISettings{
+		NotifyOnDone:       true,
+		Notifications:      "terminal",
+		NotifyDigestWindow: "1m",
+	})
+	done := func(ws string) notify.Notification {
+		return notify.Notification{Event: notify.EventDone, Title: "amux: agent finished", Body: ws + " is done"}
+	}
+
+	if got := rawNotification(t, app.notify(done("feature"))); !strings.Contains(got, "feature is done") {
+		t.Fatalf("first notification = %q, want it delivered at once", got)
+	}
+	if cmd := app.notify(done("fix")); cmd == nil || app.notifications.flushAt.IsZero() {
+		t.Fatal("a notification inside the window should be held and a flush armed")
+	}
+	app.notify(done("docs"))
+	if app.notifications.digest.Pending() != 2 {
+		t.Fatalf("held %d notifications, want 2", app.notifications.digest.Pending())
+	}
+	if cmd := app.handleNotifyDigestTick(); app.notifications.digest.Pending() != 2 || cmd == nil {
+		t.Fatal("a tick inside the window should deliver nothing and re-arm")
+	}
+
+	app.config.UI.NotifyDigestWindow = ""
+	app.notifications = notifyState{}
+	app.notify(done("a"))
+	now := time.Now()
+	app.config.UI.NotifyQuietHours = now.Add(-time.Hour).Format("15:04") + "-" + now.Add(time.Hour).Format("15:04")
+	if cmd := app.notify(done("b")); app.notifications.digest.Pending() != 1 || cmd == nil {
+		t.Fatal("quiet hours should hold the notification")
+	}
+}
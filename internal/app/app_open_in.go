@@ -0,0 +1,72 @@
+package app
+
+import (
+	"fmt"
+
+	tea "charm.land/bubbletea/v2"
+
+	"github.com/andyrewlee/amux/internal/config"
+	"github.com/andyrewlee/amux/internal/data"
+	"github.com/andyrewlee/amux/internal/logging"
+	"github.com/andyrewlee/amux/internal/messages"
+	"github.com/andyrewlee/amux/internal/process"
+	"github.com/andyrewlee/amux/internal/ui/common"
+)
+
+// openInResult reports the outcome of launching an "open in…" target.
+type openInResult struct {
+	Target string
+	Err    error
+}
+
+// handleShowOpenInDialog shows the "open worktree in…" picker listing the
+// configured targets (config "open_in", or the per-OS defaults).
+func (a *App) handleShowOpenInDialog(msg messages.ShowOpenInDialog) {
+	if msg.Workspace == nil {
+		return
+	}
+	names := a.config.OpenInTargetNames()
+	if len(names) == 0 {
+		return
+	}
+	a.dialogWorkspace = msg.Workspace
+	a.dialog = common.NewSelectDialog(
+		DialogOpenIn,
+		"Open In",
+		fmt.Sprintf("Open '%s' in:", msg.Workspace.Name),
+		names,
+	)
+	a.presentDialog(a.dialog)
+}
+
+// openWorkspaceIn launches the index-th configured target for ws. The launch
+// runs in a Cmd goroutine; only process start is awaited, never the tool itself.
+func (a *App) openWorkspaceIn(ws *data.Workspace, index int) tea.Cmd {
+	if ws == nil || a.config == nil || index < 0 || index >= len(a.config.OpenIn) {
+		return nil
+	}
+	target := a.config.OpenIn[index]
+	launch := a.openInFn
+	if launch == nil {
+		launch = process.StartDetached
+	}
+	root := ws.Root
+	return func() tea.Msg {
+		return openInResult{Target: target.Name, Err: launchOpenInTarget(launch, target, root)}
+	}
+}
+
+func launchOpenInTarget(launch func(dir, command string) error, target config.OpenInTarget, root string) error {
+	command := target.ExpandCommand(root)
+	logging.Info("Opening %s in %s: %s", root, target.Name, command)
+	return launch(root, command)
+}
+
+// handleOpenInResult surfaces a failed launch; a successful one needs no
+// feedback beyond the external tool appearing.
+func (a *App) handleOpenInResult(msg openInResult) tea.Cmd {
+	if msg.Err != nil {
+		return common.ReportError("opening workspace in "+msg.Target, msg.Err, "Failed to open in "+msg.Target+": "+msg.Err.Error())
+	}
+	return nil
+}
@@ -0,0 +1,88 @@
+package app
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/andyrewlee/amux/internal/config"
+	"github.com/andyrewlee/amux/internal/data"
+	"github.com/andyrewlee/amux/internal/messages"
+	"github.com/andyrewlee/amux/internal/ui/common"
+)
+
+func TestHandleShowOpenInDialogListsConfiguredTargets(t *testing.T) {
+	ws := &data.Workspace{Name: "feature", Root: "/tmp/ws"}
+	app := &App{config: &config.Config{OpenIn: []config.OpenInTarget{
+		{Name: "VS Code", Command: "code {path}"},
+		{Name: "Terminal", Command: "x-terminal-emulator"},
+	}}}
+
+	app.handleShowOpenInDialog(messages.ShowOpenInDialog{Workspace: ws})
+
+	if app.dialog == nil || !app.dialog.Visible() {
+		t.Fatal("expected the open-in dialog to be visible")
+	}
+	if app.dialogWorkspace != ws {
+		t.Fatal("expected the dialog to remember the target workspace")
+	}
+}
+
+func TestHandleDialogResult_OpenInLaunchesSelectedTarget(t *testing.T) {
+	var gotDir, gotCommand string
+	ws := &data.Workspace{Name: "feature", Root: "/tmp/my ws"}
+	app := &App{
+		toast:           common.NewToastModel(),
+		dialogWorkspace: ws,
+		config: &config.Config{OpenIn: []config.OpenInTarget{
+			{Name: "VS Code", Command: "code {path}"},
+			{Name: "JetBrains", Command: "idea {path}"},
+		}},
+		openInFn: func(dir, command string) error {
+			gotDir = dir
+			gotCommand = command
+			return nil
+		},
+	}
+
+	cmd := app.handleDialogResult(common.DialogResult{ID: DialogOpenIn, Confirmed: true, Index: 1})
+	if cmd == nil {
+		t.Fatal("expected a launch command from a confirmed open-in dialog")
+	}
+	result, ok := cmd().(openInResult)
+	if !ok {
+		t.Fatal("expected an openInResult message")
+	}
+	if result.Err != nil || result.Target != "JetBrains" {
+		t.Fatalf("unexpected result: %+v", result)
+	}
+	if gotDir != "/tmp/my ws" {
+		t.Fatalf("launch dir = %q, want the workspace root", gotDir)
+	}
+	if gotCommand != "idea '/tmp/my ws'" {
+		t.Fatalf("launch command = %q, want the quoted root substituted", gotCommand)
+	}
+}
+
+func TestHandleDialogResult_OpenInOutOfRangeIsNoop(t *testing.T) {
+	app := &App{
+		dialogWorkspace: &data.Workspace{Root: "/tmp/ws"},
+		config:          &config.Config{OpenIn: []config.OpenInTarget{{Name: "VS Code", Command: "code"}}},
+		openInFn: func(string, string) error {
+			t.Fatal("launch must not run for an out-of-range index")
+			return nil
+		},
+	}
+	if cmd := app.handleDialogResult(common.DialogResult{ID: DialogOpenIn, Confirmed: true, Index: 3}); cmd != nil {
+		t.Fatal("expected no command for an out-of-range selection")
+	}
+}
+
+func TestHandleOpenInResultReportsFailure(t *testing.T) {
+	app := &App{toast: common.NewToastModel()}
+	if cmd := app.handleOpenInResult(openInResult{Target: "VS Code"}); cmd != nil {
+		t.Fatal("a successful launch should not produce feedback")
+	}
+	if cmd := app.handleOpenInResult(openInResult{Target: "VS Code", Err: errors.New("code: not found")}); cmd == nil {
+		t.Fatal("expected a failed launch to be reported")
+	}
+}
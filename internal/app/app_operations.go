@@ -5,6 +5,7 @@ import (
 
 	tea "charm.land/bubbletea/v2"
 
+	"github.com/andyrewlee/amux/internal/commitmsg"
 	"github.com/andyrewlee/amux/internal/data"
 	"github.com/andyrewlee/amux/internal/git"
 	"github.com/andyrewlee/amux/internal/messages"
@@ -44,7 +45,12 @@ func (a *App) commitWorkspaceAsync(ws *data.Workspace, message string) tea.Cmd {
 	ctx := a.ctx
 	root := ws.Root
 	return func() tea.Msg {
-		return messages.WorkspaceCommitted{Workspace: ws, Err: commit(ctx, root, message)}
+		err := commit(ctx, root, message)
+		if err == nil {
+			// An agent's draft was for the changes just committed.
+			_ = commitmsg.RemoveAgentDraft(root)
+		}
+		return messages.WorkspaceCommitted{Workspace: ws, Err: err}
 	}
 }
 
@@ -163,20 +169,23 @@ func (a *App) removeProject(project *data.Project) tea.Cmd {
 			return messages.Error{Err: errors.New("missing project"), Context: errorContext(errorServiceWorkspace, "removing project")}
 		}
 	}
+	var homeCmd tea.Cmd
 	if a.activeWorkspace != nil && a.activeWorkspace.Repo == project.Path {
-		a.goHome()
+		homeCmd = a.goHome()
 	}
 	if a.workspaceService == nil {
-		return nil
+		return homeCmd
 	}
-	return a.workspaceService.RemoveProject(project)
+	return common.SafeBatch(homeCmd, a.workspaceService.RemoveProject(project))
 }
 
 // goHome is the explicit "no active workspace" state transition: it clears the
 // active workspace and resets every pane that renders workspace-scoped state.
 // It runs only from message handlers (workspace deleted, project removed,
-// selection rebind), never from view code.
-func (a *App) goHome() {
+// selection rebind), never from view code. The returned command saves the
+// departed workspace's unsaved notes.
+func (a *App) goHome() tea.Cmd {
+	var cmd tea.Cmd
 	if a.fileWatcher != nil && a.activeWorkspace != nil {
 		a.fileWatcher.Unwatch(a.activeWorkspace.Root)
 	}
@@ -186,7 +195,7 @@ func (a *App) goHome() {
 		a.center.SetWorkspace(nil)
 	}
 	if a.sidebar != nil {
-		a.sidebar.SetWorkspace(nil)
+		cmd = a.sidebar.SetWorkspace(nil)
 		a.sidebar.SetGitStatus(nil)
 	}
 	if a.sidebarTerminal != nil {
@@ -197,4 +206,5 @@ func (a *App) goHome() {
 	}
 	a.centerBtnFocused = false
 	a.centerBtnIndex = 0
+	return cmd
 }
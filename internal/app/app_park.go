@@ -0,0 +1,30 @@
+package app
+
+import (
+	"time"
+
+	tea "charm.land/bubbletea/v2"
+
+	"github.com/andyrewlee/amux/internal/ui/common"
+)
+
+// parkCheckInterval is how often agent tabs are checked against their
+// assistant's park timeout.
+const parkCheckInterval = 30 * time.Second
+
+type parkTick struct{}
+
+func (a *App) startParkTicker() tea.Cmd {
+	return common.SafeTick(parkCheckInterval, func(time.Time) tea.Msg {
+		return parkTick{}
+	})
+}
+
+// handleParkTick parks the agent tabs left idle past their timeout and arms
+// the next check.
+func (a *App) handleParkTick() tea.Cmd {
+	if a.center == nil {
+		return a.startParkTicker()
+	}
+	return common.SafeBatch(a.center.ParkIdleTabs(time.Now()), a.startParkTicker())
+}
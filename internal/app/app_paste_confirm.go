@@ -0,0 +1,80 @@
+package app
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/charmbracelet/x/ansi"
+
+	"github.com/andyrewlee/amux/internal/messages"
+	"github.com/andyrewlee/amux/internal/ui/common"
+)
+
+const (
+	// pastePreviewLines bounds the lines shown in the multi-line paste preview.
+	pastePreviewLines = 6
+	// pastePreviewWidth bounds each previewed line.
+	pastePreviewWidth = 60
+)
+
+// interceptMultilinePaste holds a multi-line paste into an agent tab and
+// previews it, so a stray newline can't submit a half-composed prompt. Enter
+// sends it. It reports whether msg was held.
+func (a *App) interceptMultilinePaste(msg tea.PasteMsg) (tea.Cmd, bool) {
+	if a.config == nil || !a.config.UI.ConfirmMultilinePaste {
+		return nil, false
+	}
+	if a.focusedPane != messages.PaneCenter || !strings.ContainsAny(msg.Content, "\r\n") {
+		return nil, false
+	}
+	if a.center == nil {
+		return nil, false
+	}
+	tabs, active := a.center.GetTabsInfo()
+	if active < 0 || active >= len(tabs) || !a.isAgentTab(tabs[active].Assistant) {
+		return nil, false
+	}
+	a.largePaste.preview = msg.Content
+	a.dialog = common.NewSelectDialog(
+		DialogPastePreview,
+		"Send Multi-line Paste",
+		pastePreview(msg.Content),
+		[]string{"Send", "Cancel"},
+	)
+	a.dialog.SetDefaultOption(0)
+	a.presentDialog(a.dialog)
+	return nil, true
+}
+
+// handlePastePreviewChoice sends the previewed paste to the active center tab.
+func (a *App) handlePastePreviewChoice(index int) tea.Cmd {
+	content := a.largePaste.preview
+	a.largePaste.preview = ""
+	if content == "" || index != 0 {
+		return nil
+	}
+	newCenter, cmd := a.center.Update(tea.PasteMsg{Content: content})
+	a.center = newCenter
+	return common.SafeBatch(a.focusPane(messages.PaneCenter), cmd)
+}
+
+// pastePreview renders the first lines of content followed by its size.
+func pastePreview(content string) string {
+	normalized := strings.ReplaceAll(content, "\r\n", "\n")
+	normalized = strings.ReplaceAll(normalized, "\r", "\n")
+	lines := strings.Split(strings.TrimRight(normalized, "\n"), "\n")
+	var b strings.Builder
+	for i, line := range lines {
+		if i == pastePreviewLines {
+			fmt.Fprintf(&b, "  … %d more lines\n", len(lines)-i)
+			break
+		}
+		line = strings.ReplaceAll(ansi.Strip(line), "\t", "    ")
+		b.WriteString("  " + ansi.Truncate(line, pastePreviewWidth, "…") + "\n")
+	}
+	fmt.Fprintf(&b, "\n%d lines, %d characters. Enter sends, Esc cancels.",
+		strings.Count(normalized, "\n")+1, utf8.RuneCountInString(content))
+	return b.String()
+}
@@ -0,0 +1,72 @@
+package app
+
+import (
+	"strings"
+	"testing"
+
+	tea "charm.land/bubbletea/v2"
+
+	"github.com/andyrewlee/amux/internal/config"
+	"github.com/andyrewlee/amux/internal/ui/center"
+	"github.com/andyrewlee/amux/internal/ui/common"
+)
+
+func TestInterceptMultilinePasteHoldsAgentPastes(t *testing.T) {
+	app, ws, centerModel := newPrefixTestApp(t)
+	app.toast = common.NewToastModel()
+	app.config = &config.Config{
+		Assistants: map[string]config.AssistantConfig{"claude": {}},
+		UI:         config.UISettings{ConfirmMultilinePaste: true},
+	}
+	centerModel.AddTab(&center.Tab{ID: "agent-0", Assistant: "claude", Workspace: ws})
+
+	if _, held := app.interceptMultilinePaste(tea.PasteMsg{Content: "one line"}); held {
+		t.Fatal("a single-line paste should not be held")
+	}
+	if _, held := app.interceptMultilinePaste(tea.PasteMsg{Content: "fix this\nand that\n"}); !held {
+		t.Fatal("a multi-line paste into an agent tab should be held")
+	}
+	if app.dialog == nil || !app.dialog.Visible() {
+		t.Fatal("expected the paste preview dialog to be visible")
+	}
+	if app.largePaste.preview != "fix this\nand that\n" {
+		t.Fatalf("preview = %q", app.largePaste.preview)
+	}
+
+	app.handleDialogResult(common.DialogResult{ID: DialogPastePreview, Confirmed: false})
+	if app.largePaste.preview != "" {
+		t.Fatal("canceling the preview should drop the paste")
+	}
+
+	app.config.UI.ConfirmMultilinePaste = false
+	if _, held := app.interceptMultilinePaste(tea.PasteMsg{Content: "a\nb"}); held {
+		t.Fatal("disabling the setting should skip the preview")
+	}
+}
+
+func TestInterceptMultilinePasteSkipsNonAgentTabs(t *testing.T) {
+	app, ws, centerModel := newPrefixTestApp(t)
+	app.config = &config.Config{
+		Assistants: map[string]config.AssistantConfig{"claude": {}},
+		UI:         config.UISettings{ConfirmMultilinePaste: true},
+	}
+	centerModel.AddTab(&center.Tab{ID: "shell-0", Assistant: "bash", Workspace: ws})
+
+	if _, held := app.interceptMultilinePaste(tea.PasteMsg{Content: "a\nb"}); held {
+		t.Fatal("a paste into a non-agent tab should not be held")
+	}
+}
+
+func TestPastePreviewShowsHeadAndSize(t *testing.T) {
+	content := strings.Repeat("line\n", 9) + "last"
+	got := pastePreview(content)
+	if strings.Count(got, "  line\n") != pastePreviewLines {
+		t.Fatalf("preview should show the first %d lines:\n%s", pastePreviewLines, got)
+	}
+	if !strings.Contains(got, "… 4 more lines") {
+		t.Fatalf("preview should count the hidden lines:\n%s", got)
+	}
+	if !strings.Contains(got, "10 lines, 49 characters") {
+		t.Fatalf("preview should report the size:\n%s", got)
+	}
+}
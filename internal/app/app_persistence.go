@@ -15,7 +15,7 @@ import (
 // This intentionally skips delete-in-flight workspaces. Saving during a
 // destructive delete can recreate metadata after the delete removes it.
 func (a *App) persistAllWorkspacesNow() {
-	if a.workspaceService == nil || a.center == nil {
+	if a.workspaceService == nil || a.center == nil || a.readOnly {
 		return
 	}
 	for _, project := range a.projects {
@@ -63,7 +63,7 @@ type persistSaveFailedMsg struct {
 
 // persistWorkspaceTabs marks a workspace dirty and schedules a debounced save.
 func (a *App) persistWorkspaceTabs(wsID string) tea.Cmd {
-	if wsID == "" {
+	if wsID == "" || a.readOnly {
 		return nil
 	}
 	if a.isWorkspaceDeleteInFlight(wsID) {
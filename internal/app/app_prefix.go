@@ -7,6 +7,7 @@ import (
 	"charm.land/bubbles/v2/key"
 	tea "charm.land/bubbletea/v2"
 
+	"github.com/andyrewlee/amux/internal/data"
 	"github.com/andyrewlee/amux/internal/messages"
 	"github.com/andyrewlee/amux/internal/ui/common"
 )
@@ -28,19 +29,63 @@ type prefixCommand struct {
 var prefixCommandTable = []prefixCommand{
 	{Sequence: []string{"a"}, Desc: "add project", Action: "add_project"},
 	{Sequence: []string{"d"}, Desc: "delete workspace", Action: "delete_workspace"},
+	{Sequence: []string{"o"}, Desc: "open in…", Action: "open_in"},
 	{Sequence: []string{"S"}, Desc: "Settings", Action: "open_settings"},
+	{Sequence: []string{"/"}, Desc: "search terminal", Action: "search_terminal"},
 	{Sequence: []string{"q"}, Desc: "quit", Action: "quit"},
+	{Sequence: []string{"?"}, Desc: "ask amux", Action: "ask_amux"},
 	{Sequence: []string{"K"}, Desc: "cleanup tmux", Action: "cleanup_tmux"},
+	{Sequence: []string{"L"}, Desc: "lock input to terminal", Action: "toggle_input_lock"},
+	{Sequence: []string{"E"}, Desc: "agent network", Action: "show_egress"},
+	{Sequence: []string{"T"}, Desc: "tests", Action: "show_tests"},
+	{Sequence: []string{"C"}, Desc: "checks", Action: "show_checks"},
+	{Sequence: []string{"R"}, Desc: "review hunks", Action: "review_hunks"},
+	{Sequence: []string{"D"}, Desc: "compare worktrees", Action: "compare_worktrees"},
+	{Sequence: []string{"M"}, Desc: "merge branches", Action: "merge_branches"},
+	{Sequence: []string{"P"}, Desc: "stack", Action: "stack"},
+	{Sequence: []string{"F"}, Desc: "fan out task", Action: "fan_out"},
+	{Sequence: []string{"B"}, Desc: "benchmark agents", Action: "benchmark"},
+	{Sequence: []string{"H"}, Desc: "worktree history", Action: "worktree_history"},
+	{Sequence: []string{"A"}, Desc: "activity feed", Action: "activity_feed"},
+	{Sequence: []string{"W"}, Desc: "copy status report", Action: "copy_status_report"},
+	{Sequence: []string{"c"}, Desc: "agent drafts commit message", Action: "draft_commit_message"},
+	{Sequence: []string{"N"}, Desc: "changelog", Action: "changelog"},
+	{Sequence: []string{"X"}, Desc: "start/stop dev container", Action: "container"},
+	{Sequence: []string{"G"}, Desc: "GPUs", Action: "gpus"},
 	{Sequence: []string{"h"}, Desc: "focus left", Action: "focus_left"},
 	{Sequence: []string{"l"}, Desc: "focus right", Action: "focus_right"},
+	{Sequence: []string{"]"}, Desc: "next agent (all workspaces)", Action: "next_agent"},
+	{Sequence: []string{"["}, Desc: "prev agent (all workspaces)", Action: "prev_agent"},
+	{Sequence: []string{"!"}, Desc: "next agent needing attention", Action: "attention_agent"},
+	{Sequence: []string{"Y"}, Desc: "copy last output", Action: "copy_last_output"},
 	{Sequence: []string{"t", "a"}, Desc: "new agent tab", Action: "new_agent_tab"},
 	{Sequence: []string{"t", "t"}, Desc: "new terminal tab", Action: "new_terminal_tab"},
+	{Sequence: []string{"t", "e"}, Desc: "run command in new tab", Action: "run_command_tab"},
+	{Sequence: []string{"t", "l"}, Desc: "relaunch previous tab", Action: "relaunch_tab"},
+	{Sequence: []string{"t", "o"}, Desc: "open startup layout", Action: "open_layout"},
+	{Sequence: []string{"t", "b"}, Desc: "run project runbook", Action: "run_runbook"},
 	{Sequence: []string{"t", "n"}, Desc: "next tab", Action: "next_tab"},
 	{Sequence: []string{"t", "p"}, Desc: "prev tab", Action: "prev_tab"},
 	{Sequence: []string{"t", "x"}, Desc: "close tab", Action: "close_tab"},
 	{Sequence: []string{"t", "d"}, Desc: "detach tab", Action: "detach_tab"},
 	{Sequence: []string{"t", "r"}, Desc: "reattach tab", Action: "reattach_tab"},
 	{Sequence: []string{"t", "s"}, Desc: "restart tab", Action: "restart_tab"},
+	{Sequence: []string{"t", "c"}, Desc: "clear scrollback", Action: "clear_scrollback"},
+	{Sequence: []string{"t", "R"}, Desc: "reset terminal", Action: "reset_terminal"},
+	{Sequence: []string{"t", "w"}, Desc: "redraw tab", Action: "redraw_tab"},
+	{Sequence: []string{"t", "v"}, Desc: "peek primary screen", Action: "peek_primary"},
+	{Sequence: []string{"t", "y"}, Desc: "code blocks", Action: "code_blocks"},
+	{Sequence: []string{"t", "i"}, Desc: "attach image", Action: "attach_image"},
+	{Sequence: []string{"t", "m"}, Desc: "dictate (again to stop)", Action: "dictate"},
+	{Sequence: []string{"t", "k"}, Desc: "send C-Space through", Action: "send_outer_prefix"},
+	{Sequence: []string{"t", "h"}, Desc: "input history", Action: "input_history"},
+	{Sequence: []string{"t", "u"}, Desc: "undo", Action: "undo"},
+	{Sequence: []string{"g", "s"}, Desc: "find symbol", Action: "find_symbol"},
+	{Sequence: []string{"g", "d"}, Desc: "go to definition", Action: "go_to_definition"},
+	{Sequence: []string{"g", "f"}, Desc: "search files", Action: "search_files"},
+	{Sequence: []string{"g", "e"}, Desc: "recent errors", Action: "recent_errors"},
+	{Sequence: []string{"g", "b"}, Desc: "bisect (find first bad commit)", Action: "bisect"},
+	{Sequence: []string{"g", "l"}, Desc: "lazygit (toggle)", Action: "git_tool"},
 }
 
 // Prefix mode helpers (leader key)
@@ -157,6 +202,21 @@ func (a *App) prefixCommands() []prefixCommand {
 			}
 		}
 	}
+	if a.inputLockActive() {
+		for i := range commands {
+			if commands[i].Action == "toggle_input_lock" {
+				commands[i].Desc = "unlock input"
+				break
+			}
+		}
+	}
+	commands = append(commands, a.workspaceJumpCommands()...)
+	if a.terminalSearchActive() {
+		commands = append(commands,
+			prefixCommand{Sequence: []string{"n"}, Desc: "older match", Action: "search_older"},
+			prefixCommand{Sequence: []string{"N"}, Desc: "newer match", Action: "search_newer"},
+		)
+	}
 	return commands
 }
 
@@ -190,10 +250,16 @@ func (a *App) matchingPrefixCommands(sequence []string) []prefixCommand {
 
 func (a *App) runPrefixAction(action string) tea.Cmd {
 	switch action {
-	case "focus_left":
-		return a.focusPaneLeft()
-	case "focus_right":
+	case "focus_left", "focus_right":
+		if a.inputLockActive() {
+			return a.refuseFocusChange()
+		}
+		if action == "focus_left" {
+			return a.focusPaneLeft()
+		}
 		return a.focusPaneRight()
+	case "toggle_input_lock":
+		return a.toggleInputLock()
 	case "scroll_up":
 		if a.centerScrollPrefixActive() {
 			a.center.ScrollActiveTerminalPage(1)
@@ -208,6 +274,111 @@ func (a *App) runPrefixAction(action string) tea.Cmd {
 		return func() tea.Msg { return messages.ShowAddProjectDialog{} }
 	case "delete_workspace":
 		return a.deleteWorkspaceCommand()
+	case "open_in":
+		if a.activeWorkspace == nil || a.activeProject == nil {
+			return a.requireWorkspaceSelection("opening it elsewhere")
+		}
+		ws := a.activeWorkspace
+		return func() tea.Msg { return messages.ShowOpenInDialog{Workspace: ws} }
+	case "show_egress":
+		if a.activeWorkspace == nil || a.activeProject == nil {
+			return a.requireWorkspaceSelection("reviewing agent network")
+		}
+		return a.showEgressDialog()
+	case "show_tests":
+		if a.activeWorkspace == nil || a.activeProject == nil {
+			return a.requireWorkspaceSelection("running tests")
+		}
+		return a.showTests(a.activeWorkspace)
+	case "find_symbol", "go_to_definition", "search_files":
+		if a.activeWorkspace == nil || a.activeProject == nil {
+			return a.requireWorkspaceSelection("navigating code")
+		}
+		switch action {
+		case "find_symbol":
+			return a.showSymbolSearchDialog()
+		case "search_files":
+			return a.showFileSearch()
+		}
+		return a.showDefinitionDialog()
+	case "recent_errors":
+		return a.showRecentErrors()
+	case "compare_worktrees":
+		if a.activeWorkspace == nil || a.activeProject == nil {
+			return a.requireWorkspaceSelection("comparing worktrees")
+		}
+		return a.showCompareDialog(a.activeWorkspace, a.activeProject)
+	case "merge_branches":
+		if a.activeWorkspace == nil || a.activeProject == nil {
+			return a.requireWorkspaceSelection("merging branches")
+		}
+		return a.showMergeAssistant(a.activeWorkspace, a.activeProject)
+	case "git_tool":
+		if a.activeWorkspace == nil || a.activeProject == nil {
+			return a.requireWorkspaceSelection("opening lazygit")
+		}
+		return a.toggleGitTool()
+	case "stack":
+		if a.activeWorkspace == nil || a.activeProject == nil {
+			return a.requireWorkspaceSelection("stacking branches")
+		}
+		return a.showStack(a.activeWorkspace, a.activeProject)
+	case "bisect":
+		if a.activeWorkspace == nil || a.activeProject == nil {
+			return a.requireWorkspaceSelection("bisecting")
+		}
+		return a.showBisect(a.activeWorkspace)
+	case "fan_out":
+		if a.activeProject == nil {
+			return a.requireWorkspaceSelection("fanning out a task")
+		}
+		return a.showFanOut(a.activeProject)
+	case "benchmark":
+		if a.activeProject == nil {
+			return a.requireWorkspaceSelection("benchmarking agents")
+		}
+		return a.showBenchmark(a.activeProject)
+	case "draft_commit_message":
+		if a.activeWorkspace == nil || a.activeProject == nil {
+			return a.requireWorkspaceSelection("drafting a commit message")
+		}
+		return a.requestAgentCommitDraft(a.activeWorkspace)
+	case "container":
+		if a.activeWorkspace == nil || a.activeProject == nil {
+			return a.requireWorkspaceSelection("running a dev container")
+		}
+		return a.toggleContainer(a.activeWorkspace)
+	case "gpus":
+		if a.activeWorkspace == nil || a.activeProject == nil {
+			return a.requireWorkspaceSelection("pinning GPUs")
+		}
+		return a.showGPUs(a.activeWorkspace)
+	case "changelog":
+		if a.activeWorkspace == nil || a.activeProject == nil {
+			return a.requireWorkspaceSelection("drafting a changelog")
+		}
+		return a.showChangelog(a.activeWorkspace)
+	case "review_hunks":
+		if a.activeWorkspace == nil || a.activeProject == nil {
+			return a.requireWorkspaceSelection("reviewing changes")
+		}
+		return a.reviewHunks(a.activeWorkspace)
+	case "show_checks":
+		if a.activeWorkspace == nil || a.activeProject == nil {
+			return a.requireWorkspaceSelection("running checks")
+		}
+		return a.showChecks(a.activeWorkspace)
+	case "activity_feed":
+		return a.showActivityFeed()
+	case "ask_amux":
+		return a.showAsk()
+	case "copy_status_report":
+		return a.copyStatusReport()
+	case "worktree_history":
+		if a.activeWorkspace == nil || a.activeProject == nil {
+			return a.requireWorkspaceSelection("showing worktree history")
+		}
+		return a.showWorktreeHistory(a.activeWorkspace)
 	case "open_settings":
 		return func() tea.Msg { return messages.ShowSettingsDialog{} }
 	case "quit":
@@ -231,7 +402,32 @@ func (a *App) runPrefixAction(action string) tea.Cmd {
 			return common.ReportError("creating terminal tab", errors.New("tmux not available"), "tmux required to create tabs. "+a.tmuxInstallHint)
 		}
 		// Intentionally global to the workspace (no sidebar focus required).
-		return a.sidebarTerminal.CreateNewTab()
+		return common.SafeBatch(
+			a.sidebarTerminal.CreateNewTab(),
+			a.recordLaunch(a.activeWorkspace, data.Launch{Kind: data.LaunchTerminal}),
+		)
+	case "run_command_tab", "relaunch_tab", "open_layout", "run_runbook":
+		if a.activeWorkspace == nil || a.activeProject == nil {
+			return a.requireWorkspaceSelection("create tab")
+		}
+		if !a.tmuxAvailable {
+			return common.ReportError("creating tab", errors.New("tmux not available"), "tmux required to create tabs. "+a.tmuxInstallHint)
+		}
+		switch action {
+		case "relaunch_tab":
+			return a.showRelaunchDialog()
+		case "open_layout":
+			return loadStartupLayout(a.activeWorkspace, true)
+		case "run_runbook":
+			return a.loadRunbook(a.activeWorkspace, "")
+		}
+		return a.showRunCommandDialog()
+	case "next_agent":
+		return a.cycleAgentTab(1)
+	case "prev_agent":
+		return a.cycleAgentTab(-1)
+	case "attention_agent":
+		return a.jumpToAttentionTab()
 	case "next_tab":
 		return a.cycleTab(a.sidebar.NextTab, a.sidebarTerminal.NextTab, a.center.NextTab)
 	case "prev_tab":
@@ -250,18 +446,43 @@ func (a *App) runPrefixAction(action string) tea.Cmd {
 		return a.dispatchTabAction(a.center.ReattachActiveTab, a.sidebarTerminal.ReattachActiveTab)
 	case "restart_tab":
 		return a.dispatchTabAction(a.center.RestartActiveTab, a.sidebarTerminal.RestartActiveTab)
+	case "clear_scrollback":
+		return a.dispatchTabAction(a.center.ClearActiveScrollback, a.sidebarTerminal.ClearActiveScrollback)
+	case "reset_terminal":
+		return a.dispatchTabAction(a.center.ResetActiveTerminal, a.sidebarTerminal.ResetActiveTerminal)
+	case "redraw_tab":
+		return a.dispatchTabAction(a.center.RedrawActiveTab, a.sidebarTerminal.RedrawActiveTab)
+	case "peek_primary":
+		return a.togglePrimaryPeek()
+	case "search_terminal":
+		return a.openTerminalSearch()
+	case "code_blocks":
+		return a.showCodeBlocks()
+	case "copy_last_output":
+		return a.copyLastOutput()
+	case "attach_image":
+		return a.showAttachImage()
+	case "dictate":
+		return a.toggleDictation()
+	case "input_history":
+		return a.showInputHistory()
+	case "undo":
+		return a.handleUndo()
+	case "send_outer_prefix":
+		a.sendOuterPrefix()
+		return nil
+	case "search_older":
+		return a.stepTerminalSearch(-1)
+	case "search_newer":
+		return a.stepTerminalSearch(1)
 	default:
+		if cmd, ok := a.runWorkspaceJumpAction(action); ok {
+			return cmd
+		}
 		return nil
 	}
 }
 
-func (a *App) centerScrollPrefixActive() bool {
-	return a != nil &&
-		a.focusedPane == messages.PaneCenter &&
-		a.center != nil &&
-		a.center.HasActiveTerminal()
-}
-
 func (a *App) deleteWorkspaceCommand() tea.Cmd {
 	if a.activeWorkspace == nil || a.activeProject == nil {
 		return a.requireWorkspaceSelection("delete workspace")
@@ -273,61 +494,3 @@ func (a *App) deleteWorkspaceCommand() tea.Cmd {
 		}
 	}
 }
-
-// cycleTab handles next/prev tab for the focused pane, persisting center tab changes.
-func (a *App) cycleTab(sidebarFn, sidebarTermFn func(), centerFn func() tea.Cmd) tea.Cmd {
-	switch a.focusedPane {
-	case messages.PaneSidebarTerminal:
-		sidebarTermFn()
-	case messages.PaneSidebar:
-		sidebarFn()
-	default:
-		_, before := a.center.GetTabsInfo()
-		cmd := centerFn()
-		_, after := a.center.GetTabsInfo()
-		if after == before {
-			return nil
-		}
-		return common.SafeBatch(cmd, a.persistActiveWorkspaceTabs())
-	}
-	return nil
-}
-
-// dispatchTabAction dispatches a tab action to center or sidebar terminal.
-func (a *App) dispatchTabAction(centerFn, sidebarTermFn func() tea.Cmd) tea.Cmd {
-	switch a.focusedPane {
-	case messages.PaneCenter:
-		return centerFn()
-	case messages.PaneSidebarTerminal:
-		return sidebarTermFn()
-	}
-	return nil
-}
-
-func (a *App) requireWorkspaceSelection(action string) tea.Cmd {
-	if a.activeWorkspace != nil && a.activeProject != nil {
-		return nil
-	}
-	if a.toast != nil {
-		return a.toast.ShowWarning("Select a workspace before " + action)
-	}
-	return nil
-}
-
-func (a *App) prefixSelectTab(index int) tea.Cmd {
-	tabs, activeIdx := a.center.GetTabsInfo()
-	if index < 0 || index >= len(tabs) || index == activeIdx {
-		return nil
-	}
-	cmd := a.center.SelectTab(index)
-	return common.SafeBatch(cmd, a.persistActiveWorkspaceTabs())
-}
-
-// sendPrefixToTerminal sends a literal Ctrl-Space (NUL) to the focused terminal
-func (a *App) sendPrefixToTerminal() {
-	if a.focusedPane == messages.PaneCenter {
-		a.center.SendToTerminal("\x00")
-	} else if a.focusedPane == messages.PaneSidebarTerminal {
-		a.sidebarTerminal.SendToTerminal("\x00")
-	}
-}
@@ -21,7 +21,7 @@ func (a *App) renderPrefixPalette() string {
 		contentWidth = 1
 	}
 
-	sequence := "C-Space"
+	sequence := a.prefixLabel()
 	if len(a.prefixSequence) > 0 {
 		sequence += " " + strings.Join(a.prefixSequence, " ")
 	}
@@ -61,7 +61,7 @@ func (a *App) renderPrefixPalette() string {
 
 	footer := lipgloss.NewStyle().
 		Foreground(common.ColorMuted()).
-		Render("Esc cancel | Backspace undo | C-Space reset | C-Space C-Space sends literal")
+		Render(fmt.Sprintf("Esc cancel | Backspace undo | %[1]s reset | %[1]s %[1]s sends literal", a.prefixLabel()))
 
 	maxLines := a.height - 3
 	if maxLines < 2 {
@@ -234,6 +234,10 @@ func prefixPaletteGroupTitle(token string) string {
 	switch token {
 	case "t":
 		return "Tabs"
+	case "w":
+		return "Workspaces"
+	case "g":
+		return "Code"
 	default:
 		return "General"
 	}
@@ -243,6 +247,10 @@ func prefixPaletteGroupDesc(token string) string {
 	switch token {
 	case "t":
 		return "tab actions"
+	case "w":
+		return "jump to workspace"
+	case "g":
+		return "code navigation"
 	default:
 		return "commands"
 	}
@@ -1,6 +1,10 @@
 package app
 
-import "github.com/andyrewlee/amux/internal/messages"
+import (
+	tea "charm.land/bubbletea/v2"
+
+	"github.com/andyrewlee/amux/internal/messages"
+)
 
 func (a *App) prefixActionVisible(action string) bool {
 	// Keep behavior permissive in lightweight tests that don't fully initialize App state.
@@ -9,9 +13,17 @@ func (a *App) prefixActionVisible(action string) bool {
 	}
 
 	switch action {
+	case "toggle_input_lock":
+		return isTerminalPane(a.focusedPane)
 	case "focus_left":
+		if a.inputLockActive() {
+			return false
+		}
 		return a.focusedPane != messages.PaneDashboard
 	case "focus_right":
+		if a.inputLockActive() {
+			return false
+		}
 		switch a.focusedPane {
 		case messages.PaneSidebar, messages.PaneSidebarTerminal:
 			return false
@@ -20,15 +32,49 @@ func (a *App) prefixActionVisible(action string) bool {
 		default:
 			return (a.layout != nil && a.layout.ShowCenter()) || (a.layout != nil && a.layout.ShowSidebar())
 		}
-	case "new_agent_tab", "new_terminal_tab":
+	case "new_agent_tab", "new_terminal_tab", "run_command_tab", "open_layout", "run_runbook":
 		if a.activeWorkspace == nil || a.activeProject == nil {
 			return false
 		}
 		return !a.tmuxCheckDone || a.tmuxAvailable
+	case "git_tool":
+		if a.activeWorkspace == nil || a.activeProject == nil || a.layout == nil || !a.layout.ShowSidebar() {
+			return false
+		}
+		return !a.tmuxCheckDone || a.tmuxAvailable
+	case "relaunch_tab":
+		if a.activeWorkspace == nil || a.activeProject == nil || len(a.activeWorkspace.Launches) == 0 {
+			return false
+		}
+		return !a.tmuxCheckDone || a.tmuxAvailable
 	case "scroll_up", "scroll_down":
 		return a.centerScrollPrefixActive()
-	case "delete_workspace":
+	case "delete_workspace", "open_in", "show_egress", "show_tests", "show_checks", "review_hunks",
+		"compare_worktrees", "merge_branches", "stack", "attach_image", "worktree_history",
+		"find_symbol", "go_to_definition", "search_files", "recent_errors", "bisect", "changelog", "container", "gpus":
 		return a.activeWorkspace != nil && a.activeProject != nil
+	case "dictate":
+		if a.dictation.session != nil {
+			return true
+		}
+		return a.activeWorkspace != nil && a.config != nil && a.config.Dictation.Command != ""
+	case "input_history":
+		return a.center.HasTabs()
+	case "undo":
+		return len(a.undo.entries) > 0
+	case "send_outer_prefix":
+		return a.sendOuterPrefixVisible()
+	case "fan_out", "benchmark":
+		return a.activeProject != nil
+	case "copy_status_report":
+		return len(a.projects) > 0
+	case "draft_commit_message":
+		return a.activeWorkspace != nil && a.activeProject != nil && a.workspaceAgentTab(a.activeWorkspace) >= 0
+	case "next_agent", "prev_agent":
+		refs, current := a.agentTabOrder()
+		return len(refs) > 1 || (len(refs) == 1 && current < 0)
+	case "attention_agent":
+		return a.attentionPending()
 	case "next_tab", "prev_tab":
 		switch a.focusedPane {
 		case messages.PaneSidebarTerminal:
@@ -38,7 +84,9 @@ func (a *App) prefixActionVisible(action string) bool {
 		default:
 			return a.center.HasTabs()
 		}
-	case "close_tab", "detach_tab", "reattach_tab", "restart_tab":
+	case "close_tab", "detach_tab", "reattach_tab", "restart_tab",
+		"clear_scrollback", "reset_terminal", "redraw_tab", "peek_primary", "search_terminal",
+		"code_blocks", "copy_last_output":
 		if a.focusedPane == messages.PaneSidebarTerminal {
 			return true
 		}
@@ -55,3 +103,20 @@ func (a *App) showNumericTabJump() bool {
 	tabs, _ := a.center.GetTabsInfo()
 	return len(tabs) > 1
 }
+
+func (a *App) centerScrollPrefixActive() bool {
+	return a != nil &&
+		a.focusedPane == messages.PaneCenter &&
+		a.center != nil &&
+		a.center.HasActiveTerminal()
+}
+
+func (a *App) requireWorkspaceSelection(action string) tea.Cmd {
+	if a.activeWorkspace != nil && a.activeProject != nil {
+		return nil
+	}
+	if a.toast != nil {
+		return a.toast.ShowWarning("Select a workspace before " + action)
+	}
+	return nil
+}
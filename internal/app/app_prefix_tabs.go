@@ -0,0 +1,56 @@
+package app
+
+import (
+	tea "charm.land/bubbletea/v2"
+
+	"github.com/andyrewlee/amux/internal/messages"
+	"github.com/andyrewlee/amux/internal/ui/common"
+)
+
+// cycleTab handles next/prev tab for the focused pane, persisting center tab changes.
+func (a *App) cycleTab(sidebarFn, sidebarTermFn func(), centerFn func() tea.Cmd) tea.Cmd {
+	switch a.focusedPane {
+	case messages.PaneSidebarTerminal:
+		sidebarTermFn()
+	case messages.PaneSidebar:
+		sidebarFn()
+	default:
+		_, before := a.center.GetTabsInfo()
+		cmd := centerFn()
+		_, after := a.center.GetTabsInfo()
+		if after == before {
+			return nil
+		}
+		return common.SafeBatch(cmd, a.persistActiveWorkspaceTabs())
+	}
+	return nil
+}
+
+// dispatchTabAction dispatches a tab action to center or sidebar terminal.
+func (a *App) dispatchTabAction(centerFn, sidebarTermFn func() tea.Cmd) tea.Cmd {
+	switch a.focusedPane {
+	case messages.PaneCenter:
+		return centerFn()
+	case messages.PaneSidebarTerminal:
+		return sidebarTermFn()
+	}
+	return nil
+}
+
+func (a *App) prefixSelectTab(index int) tea.Cmd {
+	tabs, activeIdx := a.center.GetTabsInfo()
+	if index < 0 || index >= len(tabs) || index == activeIdx {
+		return nil
+	}
+	cmd := a.center.SelectTab(index)
+	return common.SafeBatch(cmd, a.persistActiveWorkspaceTabs())
+}
+
+// sendPrefixToTerminal sends a literal leader key to the focused terminal
+func (a *App) sendPrefixToTerminal(literal string) {
+	if a.focusedPane == messages.PaneCenter {
+		a.center.SendToTerminal(literal)
+	} else if a.focusedPane == messages.PaneSidebarTerminal {
+		a.sidebarTerminal.SendToTerminal(literal)
+	}
+}
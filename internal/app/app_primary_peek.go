@@ -0,0 +1,32 @@
+package app
+
+import (
+	tea "charm.land/bubbletea/v2"
+
+	"github.com/andyrewlee/amux/internal/messages"
+)
+
+// togglePrimaryPeek flips the focused terminal between a full-screen
+// program's alternate screen and the shell screen it covers.
+func (a *App) togglePrimaryPeek() tea.Cmd {
+	var peeking, ok bool
+	switch a.focusedPane {
+	case messages.PaneCenter:
+		peeking, ok = a.center.TogglePrimaryPeek()
+	case messages.PaneSidebarTerminal:
+		peeking, ok = a.sidebarTerminal.TogglePrimaryPeek()
+	default:
+		return nil
+	}
+	if a.toast == nil {
+		return nil
+	}
+	switch {
+	case !ok:
+		return a.toast.ShowInfo("No full-screen program to peek behind")
+	case peeking:
+		return a.toast.ShowInfo("Showing the primary screen; prefix t v returns")
+	default:
+		return a.toast.ShowInfo("Back to the full-screen program")
+	}
+}
@@ -0,0 +1,101 @@
+package app
+
+import (
+	tea "charm.land/bubbletea/v2"
+
+	"github.com/andyrewlee/amux/internal/data"
+	"github.com/andyrewlee/amux/internal/logging"
+	"github.com/andyrewlee/amux/internal/messages"
+)
+
+// errReadOnly is returned by writes refused in a read-only view.
+var errReadOnly = data.ErrReadOnly
+
+// readOnlySetter is implemented by the stores that can refuse writes.
+type readOnlySetter interface {
+	SetReadOnly()
+}
+
+// takeoverQuitMsg asks the App to save its tabs and quit because another amux
+// is taking over.
+type takeoverQuitMsg struct{}
+
+// SetReadOnly makes the App a second view of state another running amux owns.
+// It shows the same projects and attaches to the same tmux sessions, but
+// saves nothing, does not create, delete, or kill anything, and leaves session
+// cleanup to the owner. owner describes that amux for the notice shown on
+// start. Call it before the program runs.
+//
+// The registry, workspace store, and trash are set read-only too, so a write
+// no handler here thought to refuse still fails rather than racing the owner.
+func (a *App) SetReadOnly(owner string) {
+	a.readOnly = true
+	a.readOnlyOwner = owner
+	if s := a.workspaceService; s != nil {
+		s.readOnly = true
+		stores := []any{s.registry, s.store}
+		if s.trash != nil {
+			stores = append(stores, s.trash)
+		}
+		for _, store := range stores {
+			if store, ok := store.(readOnlySetter); ok {
+				store.SetReadOnly()
+			}
+		}
+	}
+}
+
+// QuitForTakeover saves the tabs and quits; another amux calls for it when it
+// takes over. Safe to call from any goroutine.
+func (a *App) QuitForTakeover() {
+	a.enqueueExternalMsg(takeoverQuitMsg{})
+}
+
+func (a *App) handleTakeoverQuit() tea.Cmd {
+	logging.Info("Quitting: another amux is taking over")
+	a.persistAllWorkspacesNow()
+	a.Shutdown()
+	a.quitting = true
+	return tea.Quit
+}
+
+func (a *App) readOnlyNoticeCmd() tea.Cmd {
+	if !a.readOnly || a.toast == nil {
+		return nil
+	}
+	return a.toast.ShowWarning("Read-only view: " + a.readOnlyOwner + " owns the state; nothing here is saved")
+}
+
+// refuseReadOnly turns away, with a toast, the requests a read-only view must
+// not act on.
+func (a *App) refuseReadOnly(msg tea.Msg) (tea.Cmd, bool) {
+	if !a.readOnly {
+		return nil, false
+	}
+	var action string
+	switch msg.(type) {
+	case messages.AddProject, messages.RemoveProject:
+		action = "change projects"
+	case messages.CreateWorkspace, messages.DeleteWorkspace, messages.RescanWorkspaces:
+		action = "change workspaces"
+	case messages.LaunchAgent:
+		action = "launch agents"
+	case messages.CloseTab:
+		action = "close tabs"
+	case messages.CleanupTmuxSessions:
+		action = "clean up sessions"
+	case messages.ToggleFavorite:
+		action = "change favorites"
+	default:
+		return nil, false
+	}
+	return a.readOnlyRefusal(action), true
+}
+
+// readOnlyRefusal warns that the read-only view cannot do action.
+func (a *App) readOnlyRefusal(action string) tea.Cmd {
+	if a.toast == nil {
+		return nil
+	}
+	return a.toast.ShowWarning("Read-only view: cannot " + action + " while " + a.readOnlyOwner + " owns the state")
+}
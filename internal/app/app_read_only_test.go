@@ -0,0 +1,85 @@
+package app
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	tea "charm.land/bubbletea/v2"
+
+	"github.com/andyrewlee/amux/internal/config"
+	"github.com/andyrewlee/amux/internal/data"
+	"github.com/andyrewlee/amux/internal/messages"
+	"github.com/andyrewlee/amux/internal/ui/common"
+)
+
+func TestReadOnlyRefusesWrites(t *testing.T) {
+	app, ws := newNotifyTestApp(config.UISettings{})
+	app.toast = common.NewToastModel()
+	store := data.NewWorkspaceStore(t.TempDir())
+	app.workspaceService = &workspaceService{store: store, trash: data.NewWorkspaceTrash(t.TempDir())}
+	if err := store.Save(ws); err != nil {
+		t.Fatal(err)
+	}
+	app.tmuxAvailable, app.projectsLoaded = true, true
+	app.tmuxService = tickGCOps{}
+
+	for _, msg := range []tea.Msg{messages.CreateWorkspace{}, messages.AddProject{}, messages.CloseTab{}} {
+		if _, refused := app.refuseReadOnly(msg); refused {
+			t.Fatalf("%T refused before SetReadOnly", msg)
+		}
+	}
+
+	app.SetReadOnly("amux (pid 1)")
+	if !app.workspaceService.readOnly {
+		t.Fatal("the workspace service should be read-only too")
+	}
+	for _, msg := range []tea.Msg{
+		messages.CreateWorkspace{}, messages.DeleteWorkspace{}, messages.AddProject{},
+		messages.RemoveProject{}, messages.LaunchAgent{}, messages.CloseTab{}, messages.CleanupTmuxSessions{},
+	} {
+		if cmd, refused := app.refuseReadOnly(msg); !refused || cmd == nil {
+			t.Fatalf("%T not refused with a toast", msg)
+		}
+	}
+	if _, refused := app.refuseReadOnly(messages.RefreshDashboard{}); refused {
+		t.Fatal("a read-only view should still refresh")
+	}
+	if app.persistWorkspaceTabs(string(ws.ID())) != nil || app.gcOrphanedTmuxSessions() != nil ||
+		app.gcStaleDetachedAgentSessions() != nil || app.scanLeftoverSessions() != nil {
+		t.Fatal("a read-only view should neither save tabs nor clean up sessions")
+	}
+	configPath := filepath.Join(t.TempDir(), "config.json")
+	app.config.Paths = &config.Paths{ConfigPath: configPath}
+	var cmds []tea.Cmd
+	app.updateDialogShowMsg(messages.ToggleKeymapHints{}, &cmds)
+	if len(cmds) != 1 || app.config.UI.ShowKeymapHints {
+		t.Fatal("the keymap hints toggle should be refused with a toast")
+	}
+	app.settingsThemeDirty = true
+	if app.persistSettingsThemeIfDirty() == nil {
+		t.Fatal("the theme save should be refused with a toast")
+	}
+	app.settingsThemeDirty = true
+	if app.handleSettingsResult(common.SettingsResult{}) == nil {
+		t.Fatal("the settings save should be refused with a toast")
+	}
+	if _, err := os.Stat(configPath); !os.IsNotExist(err) {
+		t.Fatalf("config.json written in a read-only view (stat err = %v)", err)
+	}
+	if err := app.workspaceService.Save(ws); !errors.Is(err, errReadOnly) {
+		t.Fatalf("Save = %v, want errReadOnly", err)
+	}
+
+	// Writers no handler refuses are turned away by the store itself.
+	if app.applySandboxChoice(ws, true) == nil || ws.Sandbox {
+		t.Fatal("the sandbox setting should not be saved")
+	}
+	if err := store.SaveScratchpad(ws.ID(), "notes"); !errors.Is(err, errReadOnly) {
+		t.Fatalf("SaveScratchpad = %v, want errReadOnly", err)
+	}
+	if err := app.workspaceService.trash.Put(ws, ""); !errors.Is(err, errReadOnly) {
+		t.Fatalf("trash Put = %v, want errReadOnly", err)
+	}
+}
@@ -0,0 +1,300 @@
+package app
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "charm.land/bubbletea/v2"
+
+	"github.com/andyrewlee/amux/internal/data"
+	"github.com/andyrewlee/amux/internal/errscan"
+	"github.com/andyrewlee/amux/internal/messages"
+	"github.com/andyrewlee/amux/internal/ui/center"
+	"github.com/andyrewlee/amux/internal/ui/common"
+)
+
+// errorScanInterval is how often changed terminals are scanned for errors.
+const errorScanInterval = 2 * time.Second
+
+// errorScanScrollback is how many rows above the screen each scan reads.
+// Output that scrolls further than this between scans is not scanned.
+const errorScanScrollback = 500
+
+// maxRecentErrors bounds the errors kept per worktree; the oldest are
+// dropped first.
+const maxRecentErrors = 50
+
+// errorSearchRunes bounds the text searched for when jumping to an error,
+// so the query fits on one terminal row.
+const errorSearchRunes = 40
+
+type errorScanTick struct{}
+
+// errorsScanned carries the errors found in each scanned tab.
+type errorsScanned struct {
+	tabs []tabErrors
+}
+
+// tabErrors is what one scan of a tab's output found.
+type tabErrors struct {
+	workspaceID string
+	tabID       center.TabID
+	tabName     string
+	errors      []errscan.Error
+}
+
+// recentError is an error a tab printed. count is the most times it was
+// printed in one scan of the tab.
+type recentError struct {
+	errscan.Error
+	key     string
+	tabID   center.TabID
+	tabName string
+	count   int
+	found   time.Time
+}
+
+// recentErrorsState holds the errors found per worktree, newest first, and
+// the open errors panel's workspace and listed errors.
+type recentErrorsState struct {
+	byWorkspace map[string][]*recentError
+	// versions is each tab's terminal version when it was last scanned.
+	versions  map[center.TabID]uint64
+	scanning  bool
+	workspace *data.Workspace
+	shown     []*recentError
+}
+
+func (a *App) startErrorScanTicker() tea.Cmd {
+	return common.SafeTick(errorScanInterval, func(time.Time) tea.Msg {
+		return errorScanTick{}
+	})
+}
+
+func (a *App) handleErrorScanTick() tea.Cmd {
+	return common.SafeBatch(a.startErrorScanTicker(), a.scanTerminalErrors())
+}
+
+// scanTerminalErrors scans the terminals whose output changed since the last
+// scan, off the UI goroutine once their text is read.
+func (a *App) scanTerminalErrors() tea.Cmd {
+	if a.recentErrors.scanning || a.center == nil {
+		return nil
+	}
+	a.pruneRecentErrors()
+	outputs := a.center.RecentTerminalOutput(errorScanScrollback, a.recentErrors.versions)
+	if len(outputs) == 0 {
+		return nil
+	}
+	if a.recentErrors.versions == nil {
+		a.recentErrors.versions = make(map[center.TabID]uint64)
+	}
+	for _, out := range outputs {
+		a.recentErrors.versions[out.TabID] = out.Version
+	}
+	a.recentErrors.scanning = true
+	return func() tea.Msg {
+		var msg errorsScanned
+		for _, out := range outputs {
+			if found := errscan.Scan(out.Text); len(found) > 0 {
+				msg.tabs = append(msg.tabs, tabErrors{workspaceID: out.WorkspaceID, tabID: out.TabID, tabName: out.TabName, errors: found})
+			}
+		}
+		return msg
+	}
+}
+
+// handleErrorsScanned adds newly found errors to their worktrees' lists and
+// counts those seen again.
+func (a *App) handleErrorsScanned(msg errorsScanned) {
+	a.recentErrors.scanning = false
+	if len(msg.tabs) == 0 {
+		return
+	}
+	if a.recentErrors.byWorkspace == nil {
+		a.recentErrors.byWorkspace = make(map[string][]*recentError)
+	}
+	now := time.Now()
+	for _, tab := range msg.tabs {
+		list := a.recentErrors.byWorkspace[tab.workspaceID]
+		counts := make(map[string]int)
+		for _, e := range tab.errors {
+			counts[e.Key()]++
+		}
+		for _, e := range tab.errors {
+			key := e.Key()
+			if existing := findRecentError(list, key); existing != nil {
+				existing.count = max(existing.count, counts[key])
+				continue
+			}
+			list = append([]*recentError{{Error: e, key: key, tabID: tab.tabID, tabName: tab.tabName, count: counts[key], found: now}}, list...)
+		}
+		if len(list) > maxRecentErrors {
+			list = list[:maxRecentErrors]
+		}
+		a.recentErrors.byWorkspace[tab.workspaceID] = list
+	}
+	if a.searchPanelOpen(SearchPanelErrors) {
+		a.filterRecentErrors(a.searchPanel.Query())
+	}
+}
+
+// pruneRecentErrors forgets the errors of deleted workspaces.
+func (a *App) pruneRecentErrors() {
+	if len(a.recentErrors.byWorkspace) == 0 {
+		return
+	}
+	seen := make(map[string]bool)
+	a.eachWorkspace(func(ws *data.Workspace, _ *data.Project) {
+		seen[string(ws.ID())] = true
+	})
+	for wsID := range a.recentErrors.byWorkspace {
+		if !seen[wsID] {
+			delete(a.recentErrors.byWorkspace, wsID)
+		}
+	}
+}
+
+func findRecentError(list []*recentError, key string) *recentError {
+	for _, e := range list {
+		if e.key == key {
+			return e
+		}
+	}
+	return nil
+}
+
+// showRecentErrors opens the errors panel for the active workspace.
+func (a *App) showRecentErrors() tea.Cmd {
+	ws := a.activeWorkspace
+	if ws == nil || a.activeProject == nil {
+		return a.requireWorkspaceSelection("viewing errors")
+	}
+	a.recentErrors.workspace = ws
+	a.searchPanel = common.NewSearchPanel(SearchPanelErrors, "Recent Errors", "Filter errors...")
+	a.searchPanel.SetHint("type to filter  up/down select  enter jump to output  ctrl+s send to agent  esc close")
+	a.searchPanel.SetSize(a.width, a.height)
+	a.filterRecentErrors("")
+	a.searchPanel.Show()
+	return nil
+}
+
+// filterRecentErrors lists the errors panel's workspace's errors that
+// contain query, ignoring case.
+func (a *App) filterRecentErrors(query string) {
+	ws := a.recentErrors.workspace
+	if !a.searchPanelOpen(SearchPanelErrors) || ws == nil {
+		return
+	}
+	query = strings.ToLower(strings.TrimSpace(query))
+	all := a.recentErrors.byWorkspace[string(ws.ID())]
+	var shown []*recentError
+	var rows []common.SearchPanelMatch
+	now := time.Now()
+	for _, e := range all {
+		if query != "" && !strings.Contains(strings.ToLower(strings.Join(e.Lines, "\n")+"\n"+e.tabName), query) {
+			continue
+		}
+		shown = append(shown, e)
+		rows = append(rows, recentErrorRow(e, now))
+	}
+	a.recentErrors.shown = shown
+	status := fmt.Sprintf("%d errors", len(shown))
+	switch {
+	case len(all) == 0:
+		status = "No errors seen in this worktree's terminals"
+	case len(shown) == 0:
+		status = "No errors match"
+	case len(shown) == 1:
+		status = "1 error"
+	}
+	a.searchPanel.ReplaceResults(rows, status)
+}
+
+// recentErrorRow is an error's row in the errors panel: the tab that
+// printed it, its summary, and when it was found, with the error as printed
+// as the preview.
+func recentErrorRow(e *recentError, now time.Time) common.SearchPanelMatch {
+	label := fmt.Sprintf("[%s] %s", e.tabName, strings.TrimSpace(e.Summary))
+	if e.count > 1 {
+		label += fmt.Sprintf(" ×%d", e.count)
+	}
+	label += fmt.Sprintf(" · %s ago", now.Sub(e.found).Round(time.Second))
+	row := common.SearchPanelMatch{Label: label}
+	for _, line := range e.Lines {
+		row.Preview = append(row.Preview, common.SearchPreviewLine{Text: line, Match: line == e.Summary})
+	}
+	return row
+}
+
+// handleRecentErrorsResult jumps to the selected error in the output of the
+// tab that printed it, or sends every listed error to the workspace's agent.
+func (a *App) handleRecentErrorsResult(res common.SearchPanelResult) tea.Cmd {
+	ws := a.recentErrors.workspace
+	shown := a.recentErrors.shown
+	a.recentErrors.workspace = nil
+	a.recentErrors.shown = nil
+	if ws == nil {
+		return nil
+	}
+	switch res.Action {
+	case common.SearchPanelOpen:
+		if res.Index < 0 || res.Index >= len(shown) {
+			return nil
+		}
+		return a.jumpToRecentError(ws, shown[res.Index])
+	case common.SearchPanelSend:
+		if len(shown) == 0 {
+			return nil
+		}
+		return a.sendToWorkspaceAgent(ws, recentErrorsPrompt(shown))
+	}
+	return nil
+}
+
+// jumpToRecentError selects the tab that printed e and searches its
+// scrollback for e's summary, scrolling to the newest occurrence.
+func (a *App) jumpToRecentError(ws *data.Workspace, e *recentError) tea.Cmd {
+	if a.activeWorkspace != ws {
+		return a.toast.ShowWarning("Open the workspace to jump to its errors")
+	}
+	selectCmd, ok := a.center.SelectTabByID(e.tabID)
+	if !ok {
+		return a.toast.ShowWarning("The tab that printed this error is closed")
+	}
+	total, _ := a.center.SetActiveSearch(errorSearchQuery(e.Summary))
+	notice := a.toast.ShowInfo(fmt.Sprintf("Match %d of %d (prefix n/N to step)", total, total))
+	if total == 0 {
+		notice = a.toast.ShowInfo("The error has scrolled out of the terminal's history")
+	}
+	return common.SafeBatch(selectCmd, a.persistActiveWorkspaceTabs(), a.focusPane(messages.PaneCenter), notice)
+}
+
+// errorSearchQuery is the start of an error's summary, short enough that a
+// terminal does not wrap it.
+func errorSearchQuery(summary string) string {
+	summary = strings.TrimSpace(summary)
+	if runes := []rune(summary); len(runes) > errorSearchRunes {
+		summary = string(runes[:errorSearchRunes])
+	}
+	return summary
+}
+
+// recentErrorsPrompt lists errors for an agent, each as printed.
+func recentErrorsPrompt(errors []*recentError) string {
+	var b strings.Builder
+	b.WriteString("These errors were printed in this worktree's terminals:\n")
+	for _, e := range errors {
+		b.WriteString("\n")
+		fmt.Fprintf(&b, "From %s", e.tabName)
+		if e.Location != "" {
+			fmt.Fprintf(&b, ", at %s", e.Location)
+		}
+		b.WriteString(":\n")
+		for _, line := range e.Lines {
+			b.WriteString(line + "\n")
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,74 @@
+package app
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/andyrewlee/amux/internal/data"
+	"github.com/andyrewlee/amux/internal/errscan"
+	"github.com/andyrewlee/amux/internal/ui/common"
+)
+
+const panicOutput = "$ go run .\r\npanic: boom\r\n\r\ngoroutine 1 [running]:\r\nmain.main()\r\n\t/work/main.go:5 +0x1d\r\nexit status 2\r\n$ "
+
+func TestRecentErrorsScanAndJump(t *testing.T) {
+	h, err := NewHarness(HarnessOptions{Mode: HarnessCenter, Width: 120, Height: 40, Tabs: 2})
+	if err != nil {
+		t.Fatalf("NewHarness returned error: %v", err)
+	}
+	ws := harnessWorkspace()
+	h.app.projects = []data.Project{{Name: "primary", Path: ws.Repo, Workspaces: []data.Workspace{*ws}}}
+	h.app.activeProject = &h.app.projects[0]
+	h.app.activeWorkspace = &h.app.projects[0].Workspaces[0]
+
+	scan := func() {
+		t.Helper()
+		cmd := h.app.scanTerminalErrors()
+		if cmd == nil {
+			t.Fatal("expected changed terminals to be scanned")
+		}
+		h.app.handleErrorsScanned(cmd().(errorsScanned))
+	}
+	h.tabs[1].Terminal.Write([]byte(panicOutput + "go run .\r\n" + panicOutput))
+	scan()
+	if cmd := h.app.scanTerminalErrors(); cmd != nil {
+		t.Fatal("unchanged terminals should not be scanned again")
+	}
+	h.tabs[0].Terminal.Write([]byte("./main.go:9:2: undefined: total\r\n"))
+	scan()
+
+	h.app.showRecentErrors()
+	view := h.app.searchPanel.View()
+	for _, want := range []string{"[amp-1] panic: boom ×2", "[amp-0] ./main.go:9:2: undefined: total", "2 errors"} {
+		if !strings.Contains(view, want) {
+			t.Fatalf("errors panel missing %q, got %q", want, view)
+		}
+	}
+	h.app.handleSearchQueryChanged(common.SearchQueryChanged{ID: SearchPanelErrors, Query: "PANIC"})
+	if len(h.app.recentErrors.shown) != 1 || h.app.recentErrors.shown[0].Summary != "panic: boom" {
+		t.Fatalf("filtered errors = %+v, want only the panic", h.app.recentErrors.shown)
+	}
+
+	h.app.handleSearchPanelResult(common.SearchPanelResult{ID: SearchPanelErrors, Action: common.SearchPanelOpen, Index: 0})
+	if h.app.searchPanel != nil {
+		t.Fatal("jumping to an error should close the panel")
+	}
+	if !h.app.center.ActiveSearchActive() {
+		t.Fatal("jumping should search the tab's scrollback for the error")
+	}
+	if total, _ := h.app.center.SetActiveSearch("panic: boom"); total != 2 {
+		t.Fatalf("active tab has %d matches, want the tab that printed the panic", total)
+	}
+}
+
+func TestRecentErrorsPrompt(t *testing.T) {
+	compile := errscan.Error{Kind: errscan.KindCompile, Summary: "main.go:9:2: undefined: total", Location: "main.go:9", Lines: []string{"main.go:9:2: undefined: total"}}
+	got := recentErrorsPrompt([]*recentError{{Error: compile, tabName: "claude"}})
+	want := "These errors were printed in this worktree's terminals:\n\nFrom claude, at main.go:9:\nmain.go:9:2: undefined: total\n"
+	if got != want {
+		t.Fatalf("recentErrorsPrompt() = %q, want %q", got, want)
+	}
+	if q := errorSearchQuery("  " + strings.Repeat("x", 60)); len(q) != errorSearchRunes {
+		t.Fatalf("errorSearchQuery() = %q, want %d runes", q, errorSearchRunes)
+	}
+}
@@ -0,0 +1,126 @@
+package app
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	tea "charm.land/bubbletea/v2"
+
+	"github.com/andyrewlee/amux/internal/data"
+	"github.com/andyrewlee/amux/internal/messages"
+	"github.com/andyrewlee/amux/internal/ui/common"
+)
+
+// reviewCommentLimit bounds the length of one review comment.
+const reviewCommentLimit = 500
+
+// reviewComment is a comment left on a diff line, waiting to be sent to the
+// workspace's agent.
+type reviewComment struct {
+	path    string
+	line    int
+	removed bool
+	code    string
+	text    string
+}
+
+// reviewCommentsState holds the comments left in diff viewers, by
+// workspace ID, and the line the open comment dialog is for.
+type reviewCommentsState struct {
+	byWorkspace map[string][]reviewComment
+	pending     *messages.ShowDiffCommentDialog
+}
+
+// handleShowDiffCommentDialog asks for a comment on a diff line.
+func (a *App) handleShowDiffCommentDialog(msg messages.ShowDiffCommentDialog) {
+	if msg.Workspace == nil {
+		return
+	}
+	a.reviewComments.pending = &msg
+	title := "Comment on " + commentLocation(msg.Path, msg.Line, msg.Removed)
+	a.dialog = common.NewInputDialog(DialogDiffComment, title, "What should change?").SetInputCharLimit(reviewCommentLimit)
+	a.dialogWorkspace = msg.Workspace
+	a.presentDialog(a.dialog)
+}
+
+// handleDiffComment records the comment for the pending line.
+func (a *App) handleDiffComment(ws *data.Workspace, text string) tea.Cmd {
+	pending := a.reviewComments.pending
+	a.reviewComments.pending = nil
+	text = strings.TrimSpace(text)
+	if ws == nil || pending == nil || text == "" {
+		return nil
+	}
+	if a.reviewComments.byWorkspace == nil {
+		a.reviewComments.byWorkspace = make(map[string][]reviewComment)
+	}
+	id := string(ws.ID())
+	a.reviewComments.byWorkspace[id] = append(a.reviewComments.byWorkspace[id], reviewComment{
+		path:    pending.Path,
+		line:    pending.Line,
+		removed: pending.Removed,
+		code:    pending.Code,
+		text:    text,
+	})
+	n := len(a.reviewComments.byWorkspace[id])
+	return a.toast.ShowInfo(fmt.Sprintf("%d review comment(s) to send; press S in a diff to send them", n))
+}
+
+// handleSendReviewComments pastes ws's pending comments into its agent tab
+// as one review prompt.
+func (a *App) handleSendReviewComments(msg messages.SendReviewComments) tea.Cmd {
+	ws := msg.Workspace
+	if ws == nil {
+		return nil
+	}
+	id := string(ws.ID())
+	// The diff tab may hold a workspace loaded before the last rescan.
+	if a.activeWorkspace != nil && string(a.activeWorkspace.ID()) == id {
+		ws = a.activeWorkspace
+	}
+	comments := a.reviewComments.byWorkspace[id]
+	if len(comments) == 0 {
+		return a.toast.ShowInfo("No review comments to send; press c in a diff to add one")
+	}
+	// Comments are dropped only once they can be sent; otherwise
+	// sendToWorkspaceAgent explains why not and they stay pending.
+	if a.activeWorkspace == ws && a.workspaceAgentTab(ws) >= 0 {
+		delete(a.reviewComments.byWorkspace, id)
+	}
+	return a.sendToWorkspaceAgent(ws, reviewCommentsPrompt(comments))
+}
+
+// reviewCommentsPrompt formats comments as a review for an agent, by file
+// and line.
+func reviewCommentsPrompt(comments []reviewComment) string {
+	sorted := append([]reviewComment(nil), comments...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].path != sorted[j].path {
+			return sorted[i].path < sorted[j].path
+		}
+		return sorted[i].line < sorted[j].line
+	})
+	var b strings.Builder
+	b.WriteString("Please address these review comments on your changes.\n")
+	for i, c := range sorted {
+		fmt.Fprintf(&b, "\n%d. %s\n", i+1, commentLocation(c.path, c.line, c.removed))
+		if c.code != "" {
+			fmt.Fprintf(&b, "   > %s\n", c.code)
+		}
+		fmt.Fprintf(&b, "   %s\n", c.text)
+	}
+	return b.String()
+}
+
+// commentLocation names the commented line, or the file for line zero.
+func commentLocation(path string, line int, removed bool) string {
+	switch {
+	case line <= 0:
+		return path
+	case removed:
+		return fmt.Sprintf("%s:%d (removed line)", path, line)
+	default:
+		return fmt.Sprintf("%s:%d", path, line)
+	}
+}
@@ -0,0 +1,54 @@
+package app
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/andyrewlee/amux/internal/messages"
+)
+
+func TestReviewCommentsCollectAndFormat(t *testing.T) {
+	h := newDialogHarness(t)
+	ws := harnessWorkspace()
+
+	h.app.handleShowDiffCommentDialog(messages.ShowDiffCommentDialog{Workspace: ws, Path: "b.go", Line: 7, Code: "+x := 2"})
+	if view := dialogView(t, h.app.dialog); !strings.Contains(view, "Comment on b.go:7") {
+		t.Fatalf("dialog = %q, want the commented line in the title", view)
+	}
+	h.app.handleDiffComment(ws, "  name this better  ")
+	h.app.handleShowDiffCommentDialog(messages.ShowDiffCommentDialog{Workspace: ws, Path: "a.go", Line: 3, Removed: true, Code: "-check()"})
+	h.app.handleDiffComment(ws, "keep this check")
+	// An empty comment is not recorded.
+	h.app.handleShowDiffCommentDialog(messages.ShowDiffCommentDialog{Workspace: ws, Path: "a.go"})
+	h.app.handleDiffComment(ws, " ")
+
+	comments := h.app.reviewComments.byWorkspace[string(ws.ID())]
+	if len(comments) != 2 || h.app.reviewComments.pending != nil {
+		t.Fatalf("comments = %+v, pending = %+v; want two recorded", comments, h.app.reviewComments.pending)
+	}
+	prompt := reviewCommentsPrompt(comments)
+	want := "Please address these review comments on your changes.\n" +
+		"\n1. a.go:3 (removed line)\n   > -check()\n   keep this check\n" +
+		"\n2. b.go:7\n   > +x := 2\n   name this better\n"
+	if prompt != want {
+		t.Fatalf("prompt = %q, want %q", prompt, want)
+	}
+
+	// Outside the workspace the comments cannot be sent and stay pending.
+	h.app.activeWorkspace = nil
+	if cmd := h.app.handleSendReviewComments(messages.SendReviewComments{Workspace: ws}); cmd == nil {
+		t.Fatal("expected a warning toast")
+	}
+	if got := len(h.app.reviewComments.byWorkspace[string(ws.ID())]); got != 2 {
+		t.Fatalf("pending comments = %d, want 2 kept after a failed send", got)
+	}
+
+	// The harness workspace has an agent tab, so sending clears them.
+	h.app.activeWorkspace = ws
+	if cmd := h.app.handleSendReviewComments(messages.SendReviewComments{Workspace: ws}); cmd == nil {
+		t.Fatal("expected the prompt to be pasted")
+	}
+	if got := len(h.app.reviewComments.byWorkspace[string(ws.ID())]); got != 0 {
+		t.Fatalf("pending comments = %d, want none after sending", got)
+	}
+}
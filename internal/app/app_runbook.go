@@ -0,0 +1,56 @@
+package app
+
+import (
+	"errors"
+
+	tea "charm.land/bubbletea/v2"
+
+	"github.com/andyrewlee/amux/internal/data"
+	"github.com/andyrewlee/amux/internal/messages"
+	"github.com/andyrewlee/amux/internal/process"
+	"github.com/andyrewlee/amux/internal/ui/common"
+)
+
+// runbookLoaded carries the shell program for a workspace's project runbook,
+// built off the UI goroutine.
+type runbookLoaded struct {
+	workspace *data.Workspace
+	command   string
+	err       error
+}
+
+// loadRunbook builds ws's runbook program. With trustHash set, the repo's
+// scripts are trusted first, provided they still match the reviewed content.
+func (a *App) loadRunbook(ws *data.Workspace, trustHash string) tea.Cmd {
+	if ws == nil || a.workspaceService == nil || a.workspaceService.scripts == nil {
+		return nil
+	}
+	scripts := a.workspaceService.scripts
+	return func() tea.Msg {
+		if trustHash != "" {
+			if err := scripts.TrustRepoScriptsIfHash(ws.Repo, trustHash); err != nil {
+				return runbookLoaded{workspace: ws, err: err}
+			}
+		}
+		command, err := scripts.RunbookCommand(ws)
+		return runbookLoaded{workspace: ws, command: command, err: err}
+	}
+}
+
+// handleRunbookLoaded opens the runbook in a new tab, or asks to trust the
+// repo's scripts when they have not been approved yet.
+func (a *App) handleRunbookLoaded(msg runbookLoaded) tea.Cmd {
+	ws := msg.workspace
+	var trustErr *process.ScriptsNotTrustedError
+	switch {
+	case errors.Is(msg.err, process.ErrNoRunbook):
+		return a.toast.ShowInfo("No runbook in .amux/workspaces.json")
+	case errors.As(msg.err, &trustErr):
+		a.showTrustAndRunDialog(ws, trustErr.ConfigHash, "start its runbook", a.loadRunbook)
+		return nil
+	case msg.err != nil:
+		return common.ReportError(errorContext(errorServiceWorkspace, "starting runbook"), msg.err, "")
+	}
+	run := messages.RunCommand{Command: msg.command, Workspace: ws, Name: "runbook"}
+	return func() tea.Msg { return run }
+}
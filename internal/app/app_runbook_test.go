@@ -0,0 +1,36 @@
+package app
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/andyrewlee/amux/internal/messages"
+	"github.com/andyrewlee/amux/internal/process"
+)
+
+func TestRunbookLoadedOpensTabOrAsksForTrust(t *testing.T) {
+	h := newDialogHarness(t)
+	ws := harnessWorkspace()
+
+	untrusted := &process.ScriptsNotTrustedError{Repo: ws.Repo, Command: "npm ci", ConfigHash: "abc"}
+	h.app.handleRunbookLoaded(runbookLoaded{workspace: ws, err: untrusted})
+	if view := dialogView(t, h.app.dialog); !strings.Contains(view, "start its runbook") {
+		t.Fatalf("dialog = %q, want the runbook trust prompt", view)
+	}
+	if h.app.dialogWorkspace != ws || h.app.dialogTrustScriptsHash != "abc" {
+		t.Fatal("trust prompt should remember the workspace and the reviewed hash")
+	}
+
+	cmd := h.app.handleRunbookLoaded(runbookLoaded{workspace: ws, command: "amux_step 'a' 'true'"})
+	if cmd == nil {
+		t.Fatal("expected the runbook tab to open")
+	}
+	msg, ok := cmd().(messages.RunCommand)
+	if !ok || msg.Name != "runbook" || msg.Workspace != ws || msg.Command == "" {
+		t.Fatalf("runbook tab = %#v", msg)
+	}
+
+	if cmd := h.app.handleRunbookLoaded(runbookLoaded{workspace: ws, err: process.ErrNoRunbook}); cmd == nil {
+		t.Fatal("a missing runbook should be reported")
+	}
+}
@@ -0,0 +1,37 @@
+package app
+
+import (
+	tea "charm.land/bubbletea/v2"
+
+	"github.com/andyrewlee/amux/internal/data"
+	"github.com/andyrewlee/amux/internal/sandbox"
+	"github.com/andyrewlee/amux/internal/ui/common"
+)
+
+// sandboxToggleLabel describes the agent picker's sandbox option, or returns
+// "" when no sandbox tool is installed so the option is not offered.
+func sandboxToggleLabel() string {
+	if sandboxDetect() == sandbox.None {
+		return ""
+	}
+	return "Sandbox: no network, writes only in this worktree"
+}
+
+// sandboxDetect is a seam for tests.
+var sandboxDetect = sandbox.Detect
+
+// applySandboxChoice persists the picker's sandbox option for ws before an
+// agent is launched there. The choice is remembered per worktree, so later
+// launches and restarts of its agents use it too.
+func (a *App) applySandboxChoice(ws *data.Workspace, enabled bool) tea.Cmd {
+	if ws == nil || ws.Sandbox == enabled {
+		return nil
+	}
+	if a.workspaceService != nil && a.workspaceService.store != nil {
+		if err := a.workspaceService.store.SetSandbox(ws.ID(), enabled); err != nil {
+			return common.ReportError(errorContext(errorServiceWorkspace, "saving sandbox setting"), err, "")
+		}
+	}
+	ws.Sandbox = enabled
+	return nil
+}
@@ -0,0 +1,62 @@
+package app
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/andyrewlee/amux/internal/messages"
+	"github.com/andyrewlee/amux/internal/sandbox"
+	"github.com/andyrewlee/amux/internal/ui/common"
+)
+
+func stubSandboxDetect(t *testing.T, kind sandbox.Kind) {
+	t.Helper()
+	old := sandboxDetect
+	sandboxDetect = func() sandbox.Kind { return kind }
+	t.Cleanup(func() { sandboxDetect = old })
+}
+
+func TestAgentPickerOffersSandboxOnlyWhenAvailable(t *testing.T) {
+	stubSandboxDetect(t, sandbox.None)
+	h := newDialogHarness(t)
+	h.app.activeWorkspace = harnessWorkspace()
+	h.app.handleShowSelectAssistantDialog()
+	if strings.Contains(dialogView(t, h.app.dialog), "Sandbox") {
+		t.Fatal("picker should not offer sandboxing without a sandbox tool")
+	}
+
+	stubSandboxDetect(t, sandbox.Bubblewrap)
+	h = newDialogHarness(t)
+	ws := harnessWorkspace()
+	ws.Sandbox = true
+	h.app.activeWorkspace = ws
+	h.app.handleShowSelectAssistantDialog()
+	if view := dialogView(t, h.app.dialog); !strings.Contains(view, "[x] Sandbox") {
+		t.Fatalf("picker should start from the worktree's setting, got %q", view)
+	}
+}
+
+func TestAgentPickerSandboxChoiceAppliesToWorkspace(t *testing.T) {
+	h := newDialogHarness(t)
+	ws := harnessWorkspace()
+	h.app.activeWorkspace = ws
+	h.app.workspaceService = nil
+	assistant := h.app.assistantNames()[0]
+
+	cmd := h.app.handleDialogResult(common.DialogResult{
+		ID:        common.AgentPickerDialogID,
+		Confirmed: true,
+		Value:     assistant,
+		Toggle:    true,
+	})
+	if cmd == nil {
+		t.Fatal("expected a launch command")
+	}
+	launch, ok := cmd().(messages.LaunchAgent)
+	if !ok || launch.Workspace != ws || launch.Assistant != assistant {
+		t.Fatalf("cmd() = %#v, want LaunchAgent for the active workspace", launch)
+	}
+	if !ws.Sandbox {
+		t.Fatal("the sandbox choice should be recorded on the workspace before launch")
+	}
+}
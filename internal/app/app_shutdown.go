@@ -1,6 +1,21 @@
 package app
 
-import "github.com/andyrewlee/amux/internal/perf"
+import (
+	"github.com/andyrewlee/amux/internal/perf"
+	"github.com/andyrewlee/amux/internal/ui/common"
+)
+
+func (a *App) showQuitDialog() {
+	if a.dialog != nil && a.dialog.Visible() {
+		return
+	}
+	a.dialog = common.NewConfirmDialog(
+		DialogQuit,
+		"Quit AMUX",
+		"Are you sure you want to quit?",
+	)
+	a.presentDialog(a.dialog)
+}
 
 // Shutdown releases resources that may outlive the Bubble Tea program.
 func (a *App) Shutdown() {
@@ -8,6 +23,8 @@ func (a *App) Shutdown() {
 		if a.supervisor != nil {
 			a.supervisor.Stop()
 		}
+		a.stopDictation()
+		a.stopBisect()
 		if a.fileWatcher != nil {
 			_ = a.fileWatcher.Close()
 		}
@@ -20,9 +37,17 @@ func (a *App) Shutdown() {
 		if a.sidebarTerminal != nil {
 			a.sidebarTerminal.CloseAll()
 		}
+		if a.sidebar != nil {
+			a.sidebar.Scratchpad().Flush()
+		}
+		a.removePasteFiles()
 		if a.workspaceService != nil {
 			a.workspaceService.StopAll()
 		}
+		if a.lsp != nil {
+			a.lsp.StopAll()
+		}
+		a.clearTerminalBadge()
 		perf.Flush("shutdown")
 	})
 }
@@ -0,0 +1,247 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	tea "charm.land/bubbletea/v2"
+
+	"github.com/andyrewlee/amux/internal/data"
+	"github.com/andyrewlee/amux/internal/forge"
+	"github.com/andyrewlee/amux/internal/git"
+	"github.com/andyrewlee/amux/internal/messages"
+	"github.com/andyrewlee/amux/internal/stack"
+	"github.com/andyrewlee/amux/internal/timeline"
+	"github.com/andyrewlee/amux/internal/ui/common"
+	"github.com/andyrewlee/amux/internal/validation"
+)
+
+const (
+	stackOptionNew     = "Stack a new workspace on top"
+	stackOptionRestack = "Restack"
+	stackOptionUp      = "Move this one up"
+	stackOptionDown    = "Move this one down"
+	stackOptionPush    = "Push and open pull requests"
+)
+
+// stackState is the stack view of the workspace's stack: the workspaces
+// whose branches are based on one another, each in its own worktree.
+type stackState struct {
+	workspace *data.Workspace
+	project   *data.Project
+	stack     stack.Stack
+	// entries is where each entry of stack stands, in the same order; nil
+	// until loaded.
+	entries []stackEntry
+	// options are the options of the view last shown.
+	options []string
+	// running is set while the stack is being rebased or pushed.
+	running bool
+}
+
+// stackEntry is where one branch of a stack stands.
+type stackEntry struct {
+	status   git.StackedBranch
+	err      error
+	rebasing bool
+	pr       forge.PR
+	hasPR    bool
+}
+
+// stackLoaded carries where each branch of a stack stands.
+type stackLoaded struct {
+	run     *stackState
+	entries []stackEntry
+}
+
+// showStack shows the stack ws is in, loading where its branches stand.
+func (a *App) showStack(ws *data.Workspace, project *data.Project) tea.Cmd {
+	if ws == nil || project == nil {
+		return nil
+	}
+	if ws.IsPrimaryCheckout() {
+		return a.toast.ShowInfo("The primary checkout is the trunk, not part of a stack")
+	}
+	if s := a.stack; s != nil && s.running {
+		return a.toast.ShowInfo("The stack of " + s.workspace.Name + " is being updated")
+	}
+	run := &stackState{workspace: ws, project: project, stack: stack.Of(ws, project.Workspaces)}
+	a.stack = run
+	return loadStack(run)
+}
+
+// loadStack reads where each branch of run's stack stands against the one
+// below it and finds its pull request.
+func loadStack(run *stackState) tea.Cmd {
+	s := run.stack
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), stackLoadTimeout)
+		defer cancel()
+		entries := make([]stackEntry, len(s.Entries))
+		for i, ws := range s.Entries {
+			e := &entries[i]
+			e.status, e.err = git.StackedBranchStatus(ctx, ws.Root, s.Parent(i))
+			e.rebasing = git.RebaseInProgress(ctx, ws.Root)
+			e.pr, e.hasPR = timeline.FindPR(ws)
+		}
+		return stackLoaded{run: run, entries: entries}
+	}
+}
+
+// handleStackLoaded shows the stack once its branches are read.
+func (a *App) handleStackLoaded(msg stackLoaded) tea.Cmd {
+	if msg.run != a.stack {
+		return nil
+	}
+	msg.run.entries = msg.entries
+	return a.showStackView()
+}
+
+// showStackView lists the stack top first, with what can be done to it.
+func (a *App) showStackView() tea.Cmd {
+	run := a.stack
+	if run == nil || run.entries == nil {
+		return nil
+	}
+	s := run.stack
+	here := s.Index(run.workspace)
+	message := fmt.Sprintf("%d branches onto %s, top first. Pick one to open its workspace.", len(s.Entries), s.Trunk)
+	options := []string{stackOptionNew}
+	if len(s.Entries) > 1 || stackNeedsRestack(run) {
+		options = append(options, stackOptionRestack)
+	}
+	if here >= 0 && here < len(s.Entries)-1 {
+		options = append(options, stackOptionUp)
+	}
+	if here > 0 {
+		options = append(options, stackOptionDown)
+	}
+	options = append(options, stackOptionPush)
+	for i := len(s.Entries) - 1; i >= 0; i-- {
+		options = append(options, stackRow(run, i, i == here))
+	}
+	run.options = options
+	a.dialog = common.NewListDialog(DialogStack, "Stack: "+run.workspace.Name, message, options)
+	a.dialogWorkspace = run.workspace
+	a.presentDialog(a.dialog)
+	return nil
+}
+
+// stackNeedsRestack reports whether any branch of run's stack is behind the
+// one below it.
+func stackNeedsRestack(run *stackState) bool {
+	for _, e := range run.entries {
+		if e.status.Behind {
+			return true
+		}
+	}
+	return false
+}
+
+// stackRow describes entry i of run's stack for the stack view.
+func stackRow(run *stackState, i int, here bool) string {
+	ws, e := run.stack.Entries[i], run.entries[i]
+	var notes []string
+	switch {
+	case e.err != nil:
+		notes = append(notes, "unreadable")
+	case len(e.status.Subjects) == 1:
+		notes = append(notes, "1 commit")
+	default:
+		notes = append(notes, fmt.Sprintf("%d commits", len(e.status.Subjects)))
+	}
+	if e.rebasing {
+		notes = append(notes, "rebase in progress")
+	} else if e.status.Behind {
+		notes = append(notes, "needs restack")
+	}
+	if e.hasPR {
+		notes = append(notes, fmt.Sprintf("#%d %s", e.pr.Number, strings.ToLower(e.pr.State)))
+	}
+	row := fmt.Sprintf("%d. %s  (%s)", i+1, ws.Branch, strings.Join(notes, ", "))
+	if here {
+		row += "  ← this one"
+	}
+	return row
+}
+
+// handleStackDialog acts on the stack view and the new entry's name,
+// reporting whether result was from one of them.
+func (a *App) handleStackDialog(result common.DialogResult) (tea.Cmd, bool) {
+	switch result.ID {
+	case DialogStack, DialogStackNew:
+	default:
+		return nil, false
+	}
+	run := a.stack
+	if run == nil || run.running {
+		return nil, true
+	}
+	if !result.Confirmed {
+		a.stack = nil
+		return nil, true
+	}
+	if result.ID == DialogStackNew {
+		return a.stackNewWorkspace(result.Value), true
+	}
+	if result.Index < 0 || result.Index >= len(run.options) || run.options[result.Index] != result.Value {
+		return nil, true
+	}
+	s := run.stack
+	here := s.Index(run.workspace)
+	switch result.Value {
+	case stackOptionNew:
+		a.dialog = common.NewInputDialog(DialogStackNew, "Stack: New Workspace",
+			"Name of the workspace based on "+s.Entries[len(s.Entries)-1].Branch)
+		a.dialogWorkspace = run.workspace
+		a.presentDialog(a.dialog)
+		return nil, true
+	case stackOptionRestack:
+		return a.runStackSteps(stack.Restack(s), "Restacked the stack"), true
+	case stackOptionUp, stackOptionDown:
+		delta := 1
+		if result.Value == stackOptionDown {
+			delta = -1
+		}
+		_, steps, err := stack.Move(s, here, delta)
+		if err != nil {
+			return a.toast.ShowWarning(err.Error()), true
+		}
+		return a.runStackSteps(steps, "Moved "+run.workspace.Branch), true
+	case stackOptionPush:
+		return a.pushStack(), true
+	}
+	// The entries are listed top first after the options above them.
+	i := len(s.Entries) - 1 - (result.Index - (len(run.options) - len(s.Entries)))
+	if i < 0 || i >= len(s.Entries) {
+		return nil, true
+	}
+	a.stack = nil
+	ws, project := a.findWorkspaceAndProjectByID(string(s.Entries[i].ID()))
+	if ws == nil {
+		return a.toast.ShowWarning(s.Entries[i].Name + " is no longer in amux"), true
+	}
+	return func() tea.Msg { return messages.WorkspaceActivated{Project: project, Workspace: ws} }, true
+}
+
+// stackNewWorkspace creates a workspace named value based on the top of the
+// stack, going on to pick its agent as a new workspace does.
+func (a *App) stackNewWorkspace(value string) tea.Cmd {
+	run := a.stack
+	a.stack = nil
+	name := validation.SanitizeInput(value)
+	if err := validation.ValidateWorkspaceName(name); err != nil {
+		return a.toast.ShowWarning(err.Error())
+	}
+	project := run.project
+	a.pendingWorkspaceProject = project
+	a.pendingWorkspaceName = name
+	a.pendingWorkspaceBase = run.stack.Entries[len(run.stack.Entries)-1].Branch
+	if a.askMonorepoTask(project, name) {
+		return nil
+	}
+	return func() tea.Msg {
+		return messages.ShowSelectAssistantDialog{}
+	}
+}
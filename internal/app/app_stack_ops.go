@@ -0,0 +1,207 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	tea "charm.land/bubbletea/v2"
+
+	"github.com/andyrewlee/amux/internal/data"
+	"github.com/andyrewlee/amux/internal/forge"
+	"github.com/andyrewlee/amux/internal/git"
+	"github.com/andyrewlee/amux/internal/stack"
+	"github.com/andyrewlee/amux/internal/ui/common"
+)
+
+const (
+	// stackLoadTimeout bounds reading where a stack's branches stand.
+	stackLoadTimeout = time.Minute
+	// stackPushTimeout bounds pushing a stack and opening its pull requests.
+	stackPushTimeout = 10 * time.Minute
+)
+
+// openStackForge opens the hosting service pull requests are opened on; a
+// test seam.
+var openStackForge = forge.Open
+
+// stackRebased reports rebasing a stack's entries. rebased lists the steps
+// done, in order; failed is the workspace of the step it stopped at, with
+// conflicts when that rebase was left in progress.
+type stackRebased struct {
+	run       *stackState
+	done      string
+	rebased   []stack.Step
+	failed    *data.Workspace
+	conflicts []string
+	err       error
+}
+
+// stackPushed reports pushing a stack and opening its pull requests.
+type stackPushed struct {
+	run    *stackState
+	pushed int
+	opened int
+	err    error
+}
+
+// runStackSteps rebases the entries of run's stack as steps plan, bottom
+// first, stopping at the first that fails. done describes the result.
+func (a *App) runStackSteps(steps []stack.Step, done string) tea.Cmd {
+	if a.readOnly {
+		return a.readOnlyRefusal("rebase the stack")
+	}
+	run := a.stack
+	for i, e := range run.entries {
+		if e.rebasing {
+			return a.toast.ShowWarning(run.stack.Entries[i].Name + " is in the middle of a rebase; finish it first")
+		}
+	}
+	run.running = true
+	return func() tea.Msg {
+		msg := stackRebased{run: run, done: done}
+		ctx := context.Background()
+		// Every branch's commits are found before any is rebased: rebasing
+		// one rewrites the upstream of the one above it.
+		upstreams := make([]string, len(steps))
+		for i, step := range steps {
+			ws := step.Workspace
+			status, err := git.GetStatusFast(ws.Root)
+			if err == nil && !status.Clean {
+				err = errors.New(ws.Name + " has uncommitted changes; commit or stash them first")
+			}
+			if err == nil {
+				upstreams[i], err = git.ForkPoint(ctx, ws.Root, step.Upstream, ws.Branch)
+			}
+			if err != nil {
+				msg.failed, msg.err = ws, err
+				return msg
+			}
+		}
+		for i, step := range steps {
+			conflicts, err := git.RebaseOnto(ctx, step.Workspace.Root, step.Onto, upstreams[i])
+			if err != nil {
+				msg.failed, msg.conflicts, msg.err = step.Workspace, conflicts, err
+				return msg
+			}
+			msg.rebased = append(msg.rebased, step)
+		}
+		return msg
+	}
+}
+
+// handleStackRebased records the new base of each rebased entry and shows
+// the stack again, or where it stopped.
+func (a *App) handleStackRebased(msg stackRebased) tea.Cmd {
+	var cmds []tea.Cmd
+	for _, step := range msg.rebased {
+		ws, _ := a.findWorkspaceAndProjectByID(string(step.Workspace.ID()))
+		if ws == nil || ws.Base == step.Onto || a.readOnly {
+			continue
+		}
+		if a.workspaceService != nil && a.workspaceService.store != nil {
+			if err := a.workspaceService.store.SetBase(ws.ID(), step.Onto); err != nil {
+				cmds = append(cmds, common.ReportError(errorContext(errorServiceWorkspace, "saving stack base"), err, ""))
+				continue
+			}
+		}
+		ws.Base = step.Onto
+	}
+	switch {
+	case len(msg.conflicts) > 0:
+		cmds = append(cmds, a.toast.ShowWarning(fmt.Sprintf("Conflicts in %s rebasing %s; resolve them in its workspace, run git rebase --continue, then restack",
+			strings.Join(msg.conflicts, ", "), msg.failed.Branch)))
+	case msg.err != nil:
+		cmds = append(cmds, a.toast.ShowWarning(fmt.Sprintf("Stopped at %s: %v", msg.failed.Branch, msg.err)))
+	default:
+		cmds = append(cmds, a.toast.ShowInfo(msg.done))
+	}
+	if msg.run == a.stack {
+		a.stack = nil
+		if ws, project := a.findWorkspaceAndProjectByID(string(msg.run.workspace.ID())); ws != nil {
+			cmds = append(cmds, a.showStack(ws, project))
+		}
+	}
+	return common.SafeBatch(cmds...)
+}
+
+// pushStack pushes every branch of the stack, bottom first, and opens a
+// pull request for each with commits that has none open, into the branch
+// below it.
+func (a *App) pushStack() tea.Cmd {
+	run := a.stack
+	for i, e := range run.entries {
+		switch {
+		case e.err != nil:
+			return a.toast.ShowWarning(fmt.Sprintf("Could not read %s: %v", run.stack.Entries[i].Branch, e.err))
+		case e.rebasing || e.status.Behind:
+			return a.toast.ShowWarning(run.stack.Entries[i].Branch + " is behind the branch below it; restack first")
+		}
+	}
+	run.running = true
+	s, entries := run.stack, run.entries
+	return func() tea.Msg {
+		msg := stackPushed{run: run}
+		ctx, cancel := context.WithTimeout(context.Background(), stackPushTimeout)
+		defer cancel()
+		for _, ws := range s.Entries {
+			if err := git.PushBranch(ctx, ws.Root, ws.Branch); err != nil {
+				msg.err = fmt.Errorf("pushing %s: %w", ws.Branch, err)
+				return msg
+			}
+			msg.pushed++
+		}
+		provider, err := openStackForge(ctx, run.workspace.Repo)
+		if err != nil {
+			msg.err = fmt.Errorf("opening pull requests: %w", err)
+			return msg
+		}
+		prs := make(map[string]forge.PR)
+		for i, ws := range s.Entries {
+			pr, ok, err := provider.FindPR(ctx, ws.Branch)
+			if err != nil {
+				msg.err = fmt.Errorf("finding the pull request for %s: %w", ws.Branch, err)
+				return msg
+			}
+			if ok && pr.State == "OPEN" {
+				prs[ws.Branch] = pr
+				continue
+			}
+			subjects := entries[i].status.Subjects
+			if len(subjects) == 0 {
+				continue
+			}
+			pr, err = provider.CreatePR(ctx, forge.CreateOptions{
+				Head:  ws.Branch,
+				Base:  stack.PRBase(s.Parent(i)),
+				Title: subjects[0],
+				Body:  stack.Description(s, i, prs),
+			})
+			if err != nil {
+				msg.err = fmt.Errorf("opening a pull request for %s: %w", ws.Branch, err)
+				return msg
+			}
+			prs[ws.Branch] = pr
+			msg.opened++
+		}
+		return msg
+	}
+}
+
+// handleStackPushed reports the push and shows the stack with its pull
+// requests.
+func (a *App) handleStackPushed(msg stackPushed) tea.Cmd {
+	var toast tea.Cmd
+	if msg.err != nil {
+		toast = a.toast.ShowWarning(fmt.Sprintf("Pushed %d branches, then: %v", msg.pushed, msg.err))
+	} else {
+		toast = a.toast.ShowInfo(fmt.Sprintf("Pushed %d branches and opened %d pull requests", msg.pushed, msg.opened))
+	}
+	if msg.run != a.stack {
+		return toast
+	}
+	msg.run.running = false
+	return common.SafeBatch(toast, loadStack(msg.run))
+}
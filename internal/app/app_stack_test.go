@@ -0,0 +1,165 @@
+package app
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/andyrewlee/amux/internal/data"
+	"github.com/andyrewlee/amux/internal/messages"
+	"github.com/andyrewlee/amux/internal/stack"
+	"github.com/andyrewlee/amux/internal/testutil"
+	"github.com/andyrewlee/amux/internal/ui/common"
+)
+
+// chooseStackOption picks option in the stack view.
+func chooseStackOption(t *testing.T, h *Harness, option string) common.DialogResult {
+	t.Helper()
+	for i, o := range h.app.stack.options {
+		if o == option || strings.Contains(o, option) {
+			return common.DialogResult{ID: DialogStack, Confirmed: true, Value: o, Index: i}
+		}
+	}
+	t.Fatalf("stack view options = %q, want %q", h.app.stack.options, option)
+	return common.DialogResult{}
+}
+
+func TestStackMoveAndNew(t *testing.T) {
+	skipIfNoGit(t)
+	h := newDialogHarness(t)
+	repo := testutil.InitRepo(t)
+	trunk := testutil.RunGit(t, repo, "branch", "--show-current")
+	worktree := func(branch, base, file string) data.Workspace {
+		root := filepath.Join(t.TempDir(), branch)
+		testutil.RunGit(t, repo, "worktree", "add", "-q", "-b", branch, root, base)
+		if err := os.WriteFile(filepath.Join(root, file), []byte(branch+"\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		testutil.RunGit(t, root, "add", file)
+		testutil.RunGit(t, root, "commit", "-q", "-m", branch+" change")
+		return data.Workspace{Name: branch, Branch: branch, Base: base, Repo: repo, Root: root}
+	}
+	api := worktree("api", trunk, "api.txt")
+	ui := worktree("ui", "api", "ui.txt")
+	h.app.projects = []data.Project{{Name: "shop", Path: repo, Workspaces: []data.Workspace{
+		{Name: "shop", Branch: trunk, Repo: repo, Root: repo}, api, ui,
+	}}}
+	project := &h.app.projects[0]
+	load := func(ws *data.Workspace) {
+		t.Helper()
+		cmd := h.app.showStack(ws, project)
+		if cmd == nil {
+			t.Fatal("expected the stack to load")
+		}
+		h.app.handleStackLoaded(cmd().(stackLoaded))
+	}
+
+	load(&project.Workspaces[2])
+	view := dialogView(t, h.app.dialog)
+	for _, want := range []string{"2 branches onto " + trunk, "2. ui  (1 commit)  ← this one", "1. api  (1 commit)", stackOptionDown} {
+		if !strings.Contains(view, want) {
+			t.Fatalf("stack view = %q, want %q", view, want)
+		}
+	}
+	if strings.Contains(view, stackOptionUp) {
+		t.Fatalf("stack view = %q, the top entry cannot move up", view)
+	}
+
+	// Moving ui below api rebases both.
+	run, _ := h.app.handleStackDialog(chooseStackOption(t, h, stackOptionDown))
+	if run == nil {
+		t.Fatal("expected the move to run")
+	}
+	rebased := run().(stackRebased)
+	if rebased.err != nil {
+		t.Fatalf("move error = %v", rebased.err)
+	}
+	h.app.handleStackRebased(rebased)
+	if ws := project.Workspaces; ws[2].Base != trunk || ws[1].Base != "ui" {
+		t.Fatalf("bases = ui on %q, api on %q", ws[2].Base, ws[1].Base)
+	}
+	if got := testutil.RunGit(t, ui.Root, "log", "--format=%s", trunk+"..HEAD"); got != "ui change" {
+		t.Fatalf("ui's commits = %q", got)
+	}
+	if got := testutil.RunGit(t, api.Root, "log", "--format=%s", "ui..HEAD"); got != "api change" {
+		t.Fatalf("api's commits above ui = %q", got)
+	}
+	if h.app.stack == nil || branchesOf(h.app.stack.stack.Entries) != "ui api" {
+		t.Fatalf("expected the moved stack to be shown again, got %+v", h.app.stack)
+	}
+
+	// A new entry is based on the top of the stack.
+	load(&project.Workspaces[2])
+	h.app.handleStackDialog(chooseStackOption(t, h, stackOptionNew))
+	if view := dialogView(t, h.app.dialog); !strings.Contains(view, "Stack: New Workspace") {
+		t.Fatalf("dialog = %q, want the new workspace's name asked for", view)
+	}
+	cmd, _ := h.app.handleStackDialog(common.DialogResult{ID: DialogStackNew, Confirmed: true, Value: "docs"})
+	if cmd == nil || h.app.pendingWorkspaceName != "docs" || h.app.pendingWorkspaceBase != "api" {
+		t.Fatalf("pending workspace = %q on %q", h.app.pendingWorkspaceName, h.app.pendingWorkspaceBase)
+	}
+	if _, ok := cmd().(messages.ShowSelectAssistantDialog); !ok {
+		t.Fatal("expected the agent to be picked next")
+	}
+}
+
+func TestStackRefusesUncommittedChanges(t *testing.T) {
+	skipIfNoGit(t)
+	h := newDialogHarness(t)
+	repo := testutil.InitRepo(t)
+	trunk := testutil.RunGit(t, repo, "branch", "--show-current")
+	root := filepath.Join(t.TempDir(), "api")
+	testutil.RunGit(t, repo, "worktree", "add", "-q", "-b", "api", root, trunk)
+	testutil.RunGit(t, repo, "commit", "-q", "--allow-empty", "-m", "trunk moves on")
+	if err := os.WriteFile(filepath.Join(root, "wip.txt"), []byte("wip\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	h.app.projects = []data.Project{{Name: "shop", Path: repo, Workspaces: []data.Workspace{
+		{Name: "api", Branch: "api", Base: trunk, Repo: repo, Root: root},
+	}}}
+	project := &h.app.projects[0]
+	h.app.handleStackLoaded(h.app.showStack(&project.Workspaces[0], project)().(stackLoaded))
+
+	run, _ := h.app.handleStackDialog(chooseStackOption(t, h, stackOptionRestack))
+	if run == nil {
+		t.Fatal("expected the restack to run")
+	}
+	rebased := run().(stackRebased)
+	if rebased.err == nil || !strings.Contains(rebased.err.Error(), "uncommitted changes") || len(rebased.rebased) != 0 {
+		t.Fatalf("restack = %+v, want it refused", rebased)
+	}
+}
+
+func TestStackRefusedReadOnly(t *testing.T) {
+	skipIfNoGit(t)
+	h := newDialogHarness(t)
+	repo := testutil.InitRepo(t)
+	trunk := testutil.RunGit(t, repo, "branch", "--show-current")
+	root := filepath.Join(t.TempDir(), "api")
+	testutil.RunGit(t, repo, "worktree", "add", "-q", "-b", "api", root, trunk)
+	testutil.RunGit(t, repo, "commit", "-q", "--allow-empty", "-m", "trunk moves on")
+	h.app.projects = []data.Project{{Name: "shop", Path: repo, Workspaces: []data.Workspace{
+		{Name: "api", Branch: "api", Base: trunk, Repo: repo, Root: root},
+	}}}
+	project := &h.app.projects[0]
+	h.app.handleStackLoaded(h.app.showStack(&project.Workspaces[0], project)().(stackLoaded))
+	h.app.SetReadOnly("amux (pid 1)")
+
+	h.app.handleStackDialog(chooseStackOption(t, h, stackOptionRestack))
+	if h.app.stack.running {
+		t.Fatal("a read-only view should not rebase the stack")
+	}
+	h.app.handleStackRebased(stackRebased{rebased: []stack.Step{{Workspace: &project.Workspaces[0], Onto: "main2"}}})
+	if project.Workspaces[0].Base != trunk {
+		t.Fatalf("base = %q, a read-only view should not record a new one", project.Workspaces[0].Base)
+	}
+}
+
+func branchesOf(entries []*data.Workspace) string {
+	var names []string
+	for _, ws := range entries {
+		names = append(names, ws.Branch)
+	}
+	return strings.Join(names, " ")
+}
@@ -0,0 +1,129 @@
+package app
+
+import (
+	"context"
+	"os"
+	"time"
+
+	tea "charm.land/bubbletea/v2"
+	"charm.land/lipgloss/v2"
+
+	"github.com/andyrewlee/amux/internal/config"
+	"github.com/andyrewlee/amux/internal/logging"
+	"github.com/andyrewlee/amux/internal/statusbar"
+	"github.com/andyrewlee/amux/internal/ui/common"
+)
+
+// statusBarState holds the latest segment of each configured widget. gen
+// changes when the active workspace does, so runs and ticks started for the
+// previous one are dropped.
+type statusBarState struct {
+	segments []string
+	gen      int
+}
+
+type statusWidgetTick struct {
+	index int
+	gen   int
+}
+
+type statusWidgetResult struct {
+	index int
+	gen   int
+	text  string
+	err   error
+}
+
+func (a *App) statusWidgets() []config.StatusWidget {
+	if a.config == nil {
+		return nil
+	}
+	return a.config.StatusWidgets
+}
+
+// refreshStatusWidgets runs every widget now, for the active workspace. It is
+// called at startup and whenever another workspace is activated.
+func (a *App) refreshStatusWidgets() tea.Cmd {
+	widgets := a.statusWidgets()
+	if len(widgets) == 0 {
+		return nil
+	}
+	a.statusBar.gen++
+	if len(a.statusBar.segments) != len(widgets) {
+		a.statusBar.segments = make([]string, len(widgets))
+	}
+	cmds := make([]tea.Cmd, 0, len(widgets))
+	for i := range widgets {
+		cmds = append(cmds, a.runStatusWidget(i))
+	}
+	return common.SafeBatch(cmds...)
+}
+
+// runStatusWidget runs widget index off the UI goroutine: in the active
+// worktree's environment when there is one, else in the home directory.
+func (a *App) runStatusWidget(index int) tea.Cmd {
+	w := a.statusWidgets()[index]
+	gen := a.statusBar.gen
+	dir, _ := os.UserHomeDir()
+	var env []string
+	if ws := a.activeWorkspace; ws != nil {
+		if scripts := a.scriptRunner(); scripts != nil {
+			command, wsEnv, err := scripts.WorkspaceCommand(ws, w.Command)
+			if err == nil {
+				w.Command, dir, env = command, ws.Root, wsEnv
+			}
+		}
+	}
+	return func() tea.Msg {
+		text, err := statusbar.Run(context.Background(), w, dir, env)
+		return statusWidgetResult{index: index, gen: gen, text: text, err: err}
+	}
+}
+
+// handleStatusWidgetResult stores a widget's segment and arms its next run.
+// A failing widget shows that it failed rather than a stale value.
+func (a *App) handleStatusWidgetResult(msg statusWidgetResult) tea.Cmd {
+	widgets := a.statusWidgets()
+	if msg.gen != a.statusBar.gen || msg.index >= len(widgets) {
+		return nil
+	}
+	w := widgets[msg.index]
+	text := msg.text
+	if msg.err != nil {
+		logging.Debug("status bar: widget %q: %v", w.Command, msg.err)
+		name := w.Name
+		if name == "" {
+			name = "widget"
+		}
+		text = statusbar.Sanitize(name+": error", w.MaxWidth)
+	}
+	a.statusBar.segments[msg.index] = text
+	tick := statusWidgetTick{index: msg.index, gen: msg.gen}
+	return common.SafeTick(w.Interval, func(time.Time) tea.Msg {
+		return tick
+	})
+}
+
+func (a *App) handleStatusWidgetTick(msg statusWidgetTick) tea.Cmd {
+	if msg.gen != a.statusBar.gen || msg.index >= len(a.statusWidgets()) {
+		return nil
+	}
+	return a.runStatusWidget(msg.index)
+}
+
+// composeStatusBar draws the widgets' segments on the row reserved below the
+// panes, right aligned.
+func (a *App) composeStatusBar(canvas *lipgloss.Canvas) {
+	if a.layout == nil || len(a.statusWidgets()) == 0 {
+		a.renderCache.statusBar.get("", 0, 0)
+		return
+	}
+	x := a.layout.LeftGutter()
+	bar := statusbar.Render(a.statusBar.segments, a.width-x-a.layout.RightGutter())
+	if bar != "" {
+		bar = lipgloss.NewStyle().Foreground(common.ColorMuted()).Render(bar)
+	}
+	if drawable := a.renderCache.statusBar.get(bar, x, a.height-1); drawable != nil {
+		canvas.Compose(drawable)
+	}
+}
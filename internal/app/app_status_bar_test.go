@@ -0,0 +1,49 @@
+package app
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/charmbracelet/x/ansi"
+
+	"github.com/andyrewlee/amux/internal/config"
+)
+
+func TestStatusBarWidgets(t *testing.T) {
+	h := newDialogHarness(t)
+	height := h.app.layout.Height()
+	h.app.config.StatusWidgets = []config.StatusWidget{
+		{Name: "greeting", Command: "echo hello", Interval: time.Minute, MaxWidth: 20},
+		{Name: "broken", Command: "exit 1", Interval: time.Minute, MaxWidth: 20},
+	}
+	h.app.layout.SetBottomGutter(1)
+	h.app.handleWindowSize(tea.WindowSizeMsg{Width: h.app.width, Height: h.app.height})
+	if h.app.layout.Height() != height-1 {
+		t.Fatalf("layout height = %d, want %d with a row for the bar", h.app.layout.Height(), height-1)
+	}
+
+	if h.app.refreshStatusWidgets() == nil {
+		t.Fatal("expected the widgets to run")
+	}
+	for i := range h.app.config.StatusWidgets {
+		result, ok := h.app.runStatusWidget(i)().(statusWidgetResult)
+		if !ok {
+			t.Fatal("expected a widget result")
+		}
+		if h.app.handleStatusWidgetResult(result) == nil {
+			t.Fatal("expected the widget's next run to be armed")
+		}
+	}
+	lines := strings.Split(ansi.Strip(h.Render().Content), "\n")
+	if bar := lines[len(lines)-1]; !strings.HasSuffix(strings.TrimRight(bar, " "), "hello │ broken: error") {
+		t.Fatalf("status bar = %q", bar)
+	}
+
+	stale := statusWidgetResult{index: 0, gen: h.app.statusBar.gen, text: "stale"}
+	h.app.refreshStatusWidgets()
+	if h.app.handleStatusWidgetResult(stale) != nil || h.app.statusBar.segments[0] != "hello" {
+		t.Fatal("a result from before the workspace changed should be dropped")
+	}
+}
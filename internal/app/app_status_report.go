@@ -0,0 +1,64 @@
+package app
+
+import (
+	"time"
+
+	tea "charm.land/bubbletea/v2"
+
+	"github.com/andyrewlee/amux/internal/app/activity"
+	"github.com/andyrewlee/amux/internal/data"
+	"github.com/andyrewlee/amux/internal/messages"
+	"github.com/andyrewlee/amux/internal/report"
+	"github.com/andyrewlee/amux/internal/ui/common"
+)
+
+// Seams for tests, which shouldn't run git and gh or touch the clipboard.
+var (
+	collectReport   = report.Collect
+	copyToClipboard = common.CopyToClipboard
+)
+
+// copyStatusReport copies a markdown report of every project's worktrees to
+// the clipboard, the same report `amux status --report md` prints. It reads
+// git and gh for each worktree, so it is built off the UI goroutine from a
+// snapshot of the projects.
+func (a *App) copyStatusReport() tea.Cmd {
+	if len(a.projects) == 0 {
+		return a.toast.ShowInfo("No projects to report on")
+	}
+	projects := make([]data.Project, len(a.projects))
+	states := make(map[string]activity.AgentState, len(a.tmuxActivity.agentStates))
+	for id, st := range a.tmuxActivity.agentStates {
+		states[id] = st
+	}
+	agents := make(map[string]bool)
+	for i := range a.projects {
+		p := a.projects[i]
+		p.Workspaces = make([]data.Workspace, len(a.projects[i].Workspaces))
+		for j := range a.projects[i].Workspaces {
+			ws := snapshotWorkspaceForSave(&a.projects[i].Workspaces[j])
+			p.Workspaces[j] = *ws
+			for _, tab := range ws.OpenTabs {
+				agents[tab.Assistant] = a.isAgentTab(tab.Assistant)
+			}
+		}
+		projects[i] = p
+	}
+	opts := report.Options{
+		IsAgent: func(assistant string) bool { return agents[assistant] },
+		Activity: func(ws *data.Workspace) string {
+			st, ok := states[string(ws.ID())]
+			if !ok || st == activity.StateIdle {
+				return ""
+			}
+			return st.String()
+		},
+	}
+	return func() tea.Msg {
+		md := report.Markdown(collectReport(projects, opts), time.Now())
+		if err := copyToClipboard(md); err != nil {
+			return messages.Toast{Message: "Copy failed: " + err.Error(), Level: messages.ToastWarning}
+		}
+		return messages.Toast{Message: "Copied status report", Level: messages.ToastSuccess}
+	}
+}
@@ -0,0 +1,81 @@
+package app
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/andyrewlee/amux/internal/app/activity"
+	"github.com/andyrewlee/amux/internal/config"
+	"github.com/andyrewlee/amux/internal/data"
+	"github.com/andyrewlee/amux/internal/messages"
+	"github.com/andyrewlee/amux/internal/report"
+	"github.com/andyrewlee/amux/internal/ui/common"
+)
+
+func stubStatusReport(t *testing.T, copyErr error) *string {
+	t.Helper()
+	oldCollect, oldCopy := collectReport, copyToClipboard
+	t.Cleanup(func() { collectReport, copyToClipboard = oldCollect, oldCopy })
+	collectReport = func(projects []data.Project, opts report.Options) []report.Project {
+		out := make([]report.Project, len(projects))
+		for i, p := range projects {
+			out[i] = report.Project{Name: p.Name, Path: p.Path}
+			for j := range p.Workspaces {
+				ws := &p.Workspaces[j]
+				wt := report.Worktree{Name: ws.Name, Activity: opts.Activity(ws)}
+				for _, tab := range ws.OpenTabs {
+					if opts.IsAgent(tab.Assistant) {
+						wt.Agents = append(wt.Agents, report.Agent{Assistant: tab.Assistant, Status: "running"})
+					}
+				}
+				out[i].Worktrees = append(out[i].Worktrees, wt)
+			}
+		}
+		return out
+	}
+	copied := new(string)
+	copyToClipboard = func(text string) error {
+		*copied = text
+		return copyErr
+	}
+	return copied
+}
+
+func TestCopyStatusReport(t *testing.T) {
+	copied := stubStatusReport(t, nil)
+	app, ws := newNotifyTestApp(config.UISettings{})
+	app.projects[0].Name = "repo"
+	app.tmuxActivity.agentStates = map[string]activity.AgentState{string(ws.ID()): activity.StateWorking}
+
+	cmd := app.copyStatusReport()
+	if cmd == nil {
+		t.Fatal("expected a command")
+	}
+	toast, ok := cmd().(messages.Toast)
+	if !ok || toast.Level != messages.ToastSuccess {
+		t.Fatalf("result = %+v, want a success toast", toast)
+	}
+	if !strings.Contains(*copied, "## repo") || !strings.Contains(*copied, "| feature |") {
+		t.Fatalf("copied report = %q", *copied)
+	}
+	if !strings.Contains(*copied, "claude (running) — working") || strings.Contains(*copied, "bash") {
+		t.Fatalf("copied report = %q, want only the agent tab with its activity", *copied)
+	}
+}
+
+func TestCopyStatusReportFailure(t *testing.T) {
+	stubStatusReport(t, errors.New("no clipboard"))
+	app, _ := newNotifyTestApp(config.UISettings{})
+	toast, _ := app.copyStatusReport()().(messages.Toast)
+	if toast.Level != messages.ToastWarning || !strings.Contains(toast.Message, "no clipboard") {
+		t.Fatalf("result = %+v, want a warning toast", toast)
+	}
+}
+
+func TestCopyStatusReportWithoutProjects(t *testing.T) {
+	app := &App{toast: common.NewToastModel()}
+	if cmd := app.copyStatusReport(); cmd == nil {
+		t.Fatal("expected a toast command")
+	}
+}
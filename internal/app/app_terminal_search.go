@@ -0,0 +1,83 @@
+package app
+
+import (
+	"fmt"
+
+	tea "charm.land/bubbletea/v2"
+
+	"github.com/andyrewlee/amux/internal/messages"
+	"github.com/andyrewlee/amux/internal/ui/common"
+)
+
+// openTerminalSearch prompts for a query to search the focused terminal's
+// scrollback. Matches are highlighted in place; prefix n/N step through them
+// and an empty query clears the search.
+func (a *App) openTerminalSearch() tea.Cmd {
+	switch a.focusedPane {
+	case messages.PaneCenter, messages.PaneSidebarTerminal:
+	default:
+		return nil
+	}
+	if a.dialog != nil && a.dialog.Visible() {
+		return nil
+	}
+	a.terminalSearchPane = a.focusedPane
+	a.dialog = common.NewInputDialog(DialogTerminalSearch, "Search Terminal", "Text to find (empty clears)...")
+	a.presentDialog(a.dialog)
+	return nil
+}
+
+// applyTerminalSearch runs the confirmed query against the terminal the
+// dialog was opened for.
+func (a *App) applyTerminalSearch(query string) tea.Cmd {
+	var total int
+	var ok bool
+	switch a.terminalSearchPane {
+	case messages.PaneCenter:
+		total, ok = a.center.SetActiveSearch(query)
+	case messages.PaneSidebarTerminal:
+		total, ok = a.sidebarTerminal.SetActiveSearch(query)
+	}
+	if !ok || query == "" || a.toast == nil {
+		return nil
+	}
+	if total == 0 {
+		return a.toast.ShowInfo(fmt.Sprintf("No matches for %q", query))
+	}
+	return a.toast.ShowInfo(fmt.Sprintf("Match %d of %d (prefix n/N to step)", total, total))
+}
+
+// stepTerminalSearch moves the focused terminal's search to an older (dir < 0)
+// or newer match.
+func (a *App) stepTerminalSearch(dir int) tea.Cmd {
+	var index, total int
+	var ok bool
+	switch a.focusedPane {
+	case messages.PaneCenter:
+		index, total, ok = a.center.StepActiveSearch(dir)
+	case messages.PaneSidebarTerminal:
+		index, total, ok = a.sidebarTerminal.StepActiveSearch(dir)
+	}
+	if !ok || a.toast == nil {
+		return nil
+	}
+	if total == 0 {
+		return a.toast.ShowInfo("No matches")
+	}
+	return a.toast.ShowInfo(fmt.Sprintf("Match %d of %d", index, total))
+}
+
+// terminalSearchActive reports whether the focused terminal has a search to
+// step through.
+func (a *App) terminalSearchActive() bool {
+	if a == nil {
+		return false
+	}
+	switch a.focusedPane {
+	case messages.PaneCenter:
+		return a.center != nil && a.center.ActiveSearchActive()
+	case messages.PaneSidebarTerminal:
+		return a.sidebarTerminal != nil && a.sidebarTerminal.ActiveSearchActive()
+	}
+	return false
+}
@@ -0,0 +1,85 @@
+package app
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	tea "charm.land/bubbletea/v2"
+
+	"github.com/andyrewlee/amux/internal/notify"
+)
+
+// Terminal title modes (ui.terminal_title).
+const (
+	terminalTitleAgent  = "agent"
+	terminalTitleStatus = "status"
+	terminalTitleOff    = "off"
+)
+
+// terminalOutput is where the badge is cleared on shutdown, after the
+// program has stopped writing. A test seam.
+var terminalOutput io.Writer = os.Stdout
+
+func (a *App) terminalTitleMode() string {
+	if a.config == nil || a.config.UI.TerminalTitle == "" {
+		return terminalTitleAgent
+	}
+	return a.config.UI.TerminalTitle
+}
+
+// windowTitle is the host terminal's title for the current state. Bubble Tea
+// clears it on exit.
+func (a *App) windowTitle() string {
+	switch a.terminalTitleMode() {
+	case terminalTitleOff:
+		return ""
+	case terminalTitleStatus:
+		if a.dashboard == nil {
+			return fallbackWindowTitle
+		}
+		return statusWindowTitle(a.dashboard.AgentCounts())
+	}
+	if a.center == nil {
+		return fallbackWindowTitle
+	}
+	return focusedWindowTitle(a.center.FocusedAgentTitle())
+}
+
+// statusWindowTitle summarizes the agents, attention first.
+func statusWindowTitle(attention, working int) string {
+	switch {
+	case attention == 1:
+		return fallbackWindowTitle + ": 1 agent needs attention"
+	case attention > 1:
+		return fmt.Sprintf("%s: %d agents need attention", fallbackWindowTitle, attention)
+	case working == 1:
+		return fallbackWindowTitle + ": 1 agent working"
+	case working > 1:
+		return fmt.Sprintf("%s: %d agents working", fallbackWindowTitle, working)
+	}
+	return fallbackWindowTitle
+}
+
+// syncTerminalBadge shows or clears the tab badge when whether any agent
+// needs attention changed.
+func (a *App) syncTerminalBadge() tea.Cmd {
+	if a.config == nil || !a.config.UI.TerminalBadge || a.dashboard == nil {
+		return nil
+	}
+	attention, _ := a.dashboard.AgentCounts()
+	if show := attention > 0; show != a.terminalBadge {
+		a.terminalBadge = show
+		return tea.Raw(notify.BadgeSequence(show, os.Getenv))
+	}
+	return nil
+}
+
+// clearTerminalBadge clears a badge left shown when amux exits.
+func (a *App) clearTerminalBadge() {
+	if !a.terminalBadge {
+		return
+	}
+	a.terminalBadge = false
+	_, _ = io.WriteString(terminalOutput, notify.BadgeSequence(false, os.Getenv))
+}
@@ -0,0 +1,68 @@
+package app
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/andyrewlee/amux/internal/app/activity"
+	"github.com/andyrewlee/amux/internal/config"
+	"github.com/andyrewlee/amux/internal/notify"
+)
+
+func TestStatusWindowTitle(t *testing.T) {
+	tests := []struct {
+		attention, working int
+		want               string
+	}{
+		{0, 0, "amux"},
+		{1, 2, "amux: 1 agent needs attention"},
+		{2, 0, "amux: 2 agents need attention"},
+		{0, 1, "amux: 1 agent working"},
+		{0, 3, "amux: 3 agents working"},
+	}
+	for _, tt := range tests {
+		if got := statusWindowTitle(tt.attention, tt.working); got != tt.want {
+			t.Errorf("statusWindowTitle(%d, %d) = %q, want %q", tt.attention, tt.working, got, tt.want)
+		}
+	}
+}
+
+func TestTerminalTitleAndBadge(t *testing.T) {
+	t.Setenv("TMUX", "")
+	app, mainWS, featWS := newAgentCycleTestApp(t)
+	app.config = &config.Config{UI: config.UISettings{TerminalTitle: terminalTitleStatus, TerminalBadge: true}}
+	featID, mainID := string(featWS.ID()), string(mainWS.ID())
+
+	app.dashboard.SetAgentStates(map[string]activity.AgentState{featID: activity.StateWorking, mainID: activity.StateWorking})
+	if got := app.windowTitle(); got != "amux: 2 agents working" {
+		t.Fatalf("windowTitle() = %q", got)
+	}
+	if cmd := app.syncTerminalBadge(); cmd != nil {
+		t.Fatal("no badge expected while agents are only working")
+	}
+
+	app.dashboard.SetAgentStates(map[string]activity.AgentState{featID: activity.StateDone, mainID: activity.StateWorking})
+	if got := app.windowTitle(); got != "amux: 1 agent needs attention" {
+		t.Fatalf("windowTitle() = %q", got)
+	}
+	if got := rawNotification(t, app.syncTerminalBadge()); got != notify.BadgeSequence(true, func(string) string { return "" }) {
+		t.Fatalf("badge sequence = %q", got)
+	}
+	if cmd := app.syncTerminalBadge(); cmd != nil {
+		t.Fatal("the badge should only be written when it changes")
+	}
+
+	var out bytes.Buffer
+	orig := terminalOutput
+	t.Cleanup(func() { terminalOutput = orig })
+	terminalOutput = &out
+	app.clearTerminalBadge()
+	if out.String() != notify.BadgeSequence(false, func(string) string { return "" }) {
+		t.Fatalf("shutdown wrote %q, want the badge cleared", out.String())
+	}
+
+	app.config.UI.TerminalTitle = terminalTitleOff
+	if got := app.windowTitle(); got != "" {
+		t.Fatalf("windowTitle() with the title off = %q", got)
+	}
+}
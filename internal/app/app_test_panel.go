@@ -0,0 +1,257 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	tea "charm.land/bubbletea/v2"
+
+	"github.com/andyrewlee/amux/internal/data"
+	"github.com/andyrewlee/amux/internal/feed"
+	"github.com/andyrewlee/amux/internal/git"
+	"github.com/andyrewlee/amux/internal/logging"
+	"github.com/andyrewlee/amux/internal/messages"
+	"github.com/andyrewlee/amux/internal/process"
+	"github.com/andyrewlee/amux/internal/testrun"
+	"github.com/andyrewlee/amux/internal/ui/common"
+)
+
+// testRunTimeout bounds one run of a worktree's test command.
+const testRunTimeout = 30 * time.Minute
+
+// testOutputPromptLines is how much output is sent to an agent when a run
+// failed without any failures amux could parse.
+const testOutputPromptLines = 80
+
+// testPanelState is the last test run of each workspace.
+type testPanelState struct {
+	running map[string]bool
+	results map[string]testPanelResult
+}
+
+type testPanelResult struct {
+	testrun.Result
+	coverage *testrun.CoverageReport
+	at       time.Time
+}
+
+type testsFinished struct {
+	workspace *data.Workspace
+	result    testrun.Result
+	// coverage is measured from a report the run wrote, or nil.
+	coverage *testrun.CoverageReport
+	err      error
+	at       time.Time
+}
+
+// Test panel options ahead of the failures.
+const (
+	testOptionRerun = "Run tests again"
+	testOptionSend  = "Send failures to agent"
+)
+
+// showTests opens ws's test panel, or runs its tests when there is no result
+// to show yet.
+func (a *App) showTests(ws *data.Workspace) tea.Cmd {
+	if ws == nil {
+		return nil
+	}
+	wsID := string(ws.ID())
+	if a.tests.running[wsID] {
+		return a.toast.ShowInfo("Tests are already running")
+	}
+	if _, ok := a.tests.results[wsID]; !ok {
+		return a.runTests(ws, "")
+	}
+	a.showTestPanel(ws)
+	return nil
+}
+
+// runTests runs ws's test command off the UI goroutine. With trustHash set,
+// the repo's scripts are trusted first, provided they still match the
+// reviewed content.
+func (a *App) runTests(ws *data.Workspace, trustHash string) tea.Cmd {
+	if ws == nil || a.workspaceService == nil || a.workspaceService.scripts == nil {
+		return nil
+	}
+	wsID := string(ws.ID())
+	if a.tests.running == nil {
+		a.tests.running = make(map[string]bool)
+	}
+	a.tests.running[wsID] = true
+	scripts := a.workspaceService.scripts
+	run := func() tea.Msg {
+		finished := testsFinished{workspace: ws}
+		if trustHash != "" {
+			if err := scripts.TrustRepoScriptsIfHash(ws.Repo, trustHash); err != nil {
+				finished.err = err
+				return finished
+			}
+		}
+		command, env, err := scripts.TestCommand(ws)
+		if err != nil {
+			finished.err = err
+			return finished
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), testRunTimeout)
+		defer cancel()
+		started := time.Now()
+		finished.result, finished.err = testrun.Run(ctx, ws.Root, command, env)
+		finished.at = time.Now()
+		if finished.err == nil {
+			finished.coverage = measureCoverage(ws.Root, started)
+		}
+		return finished
+	}
+	return common.SafeBatch(a.toast.ShowInfo("Running tests..."), run)
+}
+
+// handleTestsFinished records a run and reports it, opening the panel when
+// tests failed in the workspace on screen.
+func (a *App) handleTestsFinished(msg testsFinished) tea.Cmd {
+	ws := msg.workspace
+	if ws == nil {
+		return nil
+	}
+	wsID := string(ws.ID())
+	delete(a.tests.running, wsID)
+	var trustErr *process.ScriptsNotTrustedError
+	switch {
+	case errors.Is(msg.err, process.ErrNoTestCommand):
+		return a.toast.ShowWarning(`No test command: set "test" in .amux/workspaces.json`)
+	case errors.As(msg.err, &trustErr):
+		a.showTrustAndRunDialog(ws, trustErr.ConfigHash, "run its test command", a.runTests)
+		return nil
+	case msg.err != nil:
+		return common.ReportError(errorContext(errorServiceWorkspace, "running tests"), msg.err, "")
+	}
+	if a.tests.results == nil {
+		a.tests.results = make(map[string]testPanelResult)
+	}
+	a.tests.results[wsID] = testPanelResult{Result: msg.result, coverage: msg.coverage, at: msg.at}
+	if a.sidebar != nil {
+		a.sidebar.SetCoverage(ws.Root, msg.coverage)
+	}
+	if msg.result.Passed() {
+		a.feedAdd(feed.KindTask, wsID, "tests passed")
+		return a.toast.ShowSuccess(fmt.Sprintf("Tests passed in %s", ws.Name))
+	}
+	a.feedAdd(feed.KindTask, wsID, "tests failed: "+failureCount(msg.result))
+	toast := a.toast.ShowWarning(fmt.Sprintf("Tests failed in %s: %s", ws.Name, failureCount(msg.result)))
+	if a.activeWorkspace == ws && (a.dialog == nil || !a.dialog.Visible()) {
+		a.showTestPanel(ws)
+	}
+	return toast
+}
+
+func failureCount(r testrun.Result) string {
+	switch n := len(r.Failures); n {
+	case 0:
+		return fmt.Sprintf("exit status %d", r.ExitCode)
+	case 1:
+		return "1 failing test"
+	default:
+		return fmt.Sprintf("%d failing tests", n)
+	}
+}
+
+// showTestPanel lists ws's last run: rerun and send-to-agent first, then one
+// entry per failure that opens its file.
+func (a *App) showTestPanel(ws *data.Workspace) {
+	res, ok := a.tests.results[string(ws.ID())]
+	if !ok {
+		return
+	}
+	message := fmt.Sprintf("%s passed at %s.", res.Command, res.at.Format("15:04"))
+	options := []string{testOptionRerun}
+	if !res.Passed() {
+		message = fmt.Sprintf("%s failed at %s: %s.", res.Command, res.at.Format("15:04"), failureCount(res.Result))
+		options = append(options, testOptionSend)
+		for _, f := range res.Failures {
+			label := "✗ " + f.Name
+			if loc := f.Location(); loc != "" {
+				label += "  " + loc
+			}
+			options = append(options, label)
+		}
+	}
+	if cov := res.coverage; cov != nil && cov.Total.Total > 0 {
+		message += fmt.Sprintf(" Coverage %d%%", cov.Total.Percent())
+		if cov.Diff.Total > 0 {
+			message += fmt.Sprintf(", changed lines %d%%", cov.Diff.Percent())
+		}
+		message += "."
+	}
+	a.dialog = common.NewListDialog(DialogTests, "Tests", message, options)
+	a.dialogWorkspace = ws
+	a.presentDialog(a.dialog)
+}
+
+// handleTestPanelChoice acts on a test panel selection.
+func (a *App) handleTestPanelChoice(ws *data.Workspace, index int) tea.Cmd {
+	if ws == nil {
+		return nil
+	}
+	res, ok := a.tests.results[string(ws.ID())]
+	if !ok || index < 0 {
+		return nil
+	}
+	switch {
+	case index == 0:
+		return a.runTests(ws, "")
+	case index == 1 && !res.Passed():
+		return a.sendToWorkspaceAgent(ws, testFailurePrompt(res.Result))
+	}
+	index -= 2
+	if index >= len(res.Failures) {
+		return nil
+	}
+	f := res.Failures[index]
+	if f.File == "" {
+		return a.toast.ShowInfo("No file reported for " + f.Name)
+	}
+	path := f.File
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(ws.Root, path)
+	}
+	open := messages.OpenFileInVim{Path: path, Workspace: ws, Line: f.Line}
+	return common.SafeBatch(func() tea.Msg { return open }, a.focusPane(messages.PaneCenter))
+}
+
+// testFailurePrompt formats a failed run for an agent: the parsed failures,
+// or the tail of the output when none could be parsed.
+func testFailurePrompt(r testrun.Result) string {
+	if len(r.Failures) > 0 {
+		return testrun.Prompt(r.Command, r.Failures)
+	}
+	lines := strings.Split(strings.TrimRight(r.Output, "\n"), "\n")
+	if len(lines) > testOutputPromptLines {
+		lines = lines[len(lines)-testOutputPromptLines:]
+	}
+	return fmt.Sprintf("`%s` fails in this worktree with exit status %d. Please fix it. The end of its output:\n\n%s\n",
+		r.Command, r.ExitCode, strings.Join(lines, "\n"))
+}
+
+// measureCoverage reads the coverage report a run started at since wrote in
+// root, and measures it against the worktree's changes. It returns nil when
+// the run wrote no report.
+func measureCoverage(root string, since time.Time) *testrun.CoverageReport {
+	cov, err := testrun.FindCoverage(root, since)
+	if err != nil {
+		logging.Warn("Reading coverage in %s: %v", root, err)
+		return nil
+	}
+	if cov == nil {
+		return nil
+	}
+	changed, err := git.ChangedLines(root)
+	if err != nil {
+		logging.Warn("Listing changed lines in %s: %v", root, err)
+	}
+	report := cov.Report(changed)
+	return &report
+}
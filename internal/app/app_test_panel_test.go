@@ -0,0 +1,93 @@
+package app
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	tea "charm.land/bubbletea/v2"
+
+	"github.com/andyrewlee/amux/internal/messages"
+	"github.com/andyrewlee/amux/internal/testrun"
+)
+
+func TestTestPanelListsFailuresAndOpensFiles(t *testing.T) {
+	h := newDialogHarness(t)
+	ws := harnessWorkspace()
+	h.app.activeWorkspace = ws
+
+	result := testrun.Result{
+		Command:  "go test ./...",
+		ExitCode: 1,
+		Failures: []testrun.Failure{
+			{Name: "TestAdd", File: "calc/add_test.go", Line: 12, Output: []string{"add_test.go:12: got 2, want 3"}},
+			{Name: "TestNoFile"},
+		},
+	}
+	h.app.tests.running = map[string]bool{string(ws.ID()): true}
+	if cmd := h.app.handleTestsFinished(testsFinished{workspace: ws, result: result, at: time.Now()}); cmd == nil {
+		t.Fatal("expected a failure toast")
+	}
+	if h.app.tests.running[string(ws.ID())] {
+		t.Fatal("finished run should no longer be marked running")
+	}
+	view := dialogView(t, h.app.dialog)
+	for _, want := range []string{"2 failing tests", testOptionRerun, testOptionSend, "TestAdd  calc/add_test.go:12", "TestNoFile"} {
+		if !strings.Contains(view, want) {
+			t.Fatalf("test panel missing %q, got %q", want, view)
+		}
+	}
+
+	cmd := h.app.handleTestPanelChoice(ws, 2)
+	if cmd == nil {
+		t.Fatal("expected the failing test's file to open")
+	}
+	msgs := []tea.Msg{cmd()}
+	if batch, ok := msgs[0].(tea.BatchMsg); ok {
+		msgs = msgs[:0]
+		for _, sub := range batch {
+			msgs = append(msgs, sub())
+		}
+	}
+	var open messages.OpenFileInVim
+	for _, msg := range msgs {
+		if m, ok := msg.(messages.OpenFileInVim); ok {
+			open = m
+		}
+	}
+	if open.Path != filepath.Join(ws.Root, "calc/add_test.go") || open.Line != 12 {
+		t.Fatalf("open = %+v, want the file at line 12", open)
+	}
+}
+
+func TestTestFailurePromptFallsBackToOutput(t *testing.T) {
+	var out strings.Builder
+	for i := range 100 {
+		out.WriteString("line " + string(rune('a'+i%26)) + "\n")
+	}
+	prompt := testFailurePrompt(testrun.Result{Command: "make test", ExitCode: 2, Output: out.String()})
+	if !strings.Contains(prompt, "`make test` fails in this worktree with exit status 2") {
+		t.Fatalf("prompt = %q", prompt)
+	}
+	if got := strings.Count(prompt, "line "); got != testOutputPromptLines {
+		t.Fatalf("prompt kept %d output lines, want %d", got, testOutputPromptLines)
+	}
+}
+
+func TestTestPanelShowsCoverage(t *testing.T) {
+	h := newDialogHarness(t)
+	ws := harnessWorkspace()
+	h.app.activeWorkspace = ws
+
+	coverage := &testrun.CoverageReport{
+		Total: testrun.Ratio{Covered: 78, Total: 100},
+		Diff:  testrun.Ratio{Covered: 1, Total: 2},
+	}
+	result := testrun.Result{Command: "make", ExitCode: 1}
+	h.app.handleTestsFinished(testsFinished{workspace: ws, result: result, coverage: coverage, at: time.Now()})
+	view := dialogView(t, h.app.dialog)
+	if !strings.Contains(view, "Coverage 78%, changed lines 50%") {
+		t.Fatalf("test panel should show coverage, got %q", view)
+	}
+}
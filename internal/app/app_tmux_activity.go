@@ -11,6 +11,7 @@ import (
 	"github.com/andyrewlee/amux/internal/messages"
 	"github.com/andyrewlee/amux/internal/tmux"
 	"github.com/andyrewlee/amux/internal/ui/common"
+	"github.com/andyrewlee/amux/internal/ui/ptyio"
 )
 
 type tmuxActivityTick struct {
@@ -50,7 +51,7 @@ func (a *App) startTmuxActivityTicker() tea.Cmd {
 
 func (a *App) scheduleTmuxActivityTick() tea.Cmd {
 	token := a.tmuxActivity.token
-	return common.SafeTick(tmuxActivityInterval, func(time.Time) tea.Msg {
+	return common.SafeTick(ptyio.ScaleSnapshotInterval(tmuxActivityInterval), func(time.Time) tea.Msg {
 		return tmuxActivityTick{Token: token}
 	})
 }
@@ -341,6 +342,9 @@ func (a *App) handleTmuxAvailableResult(msg tmuxAvailableResult) []tea.Cmd {
 			return nil
 		})
 	}
+	if scanCmd := a.scanLeftoverSessions(); scanCmd != nil {
+		cmds = append(cmds, scanCmd)
+	}
 	// Availability and project loading race during Init. Scheduling both cleanup
 	// passes here complements handleProjectsLoaded, so whichever result arrives
 	// second performs reconciliation immediately instead of waiting for a tick.
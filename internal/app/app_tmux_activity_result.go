@@ -143,6 +143,7 @@ func (a *App) applyTmuxActivityPayload(msg tmuxActivityResult) tea.Cmd {
 	}
 	prevStates := a.tmuxActivity.agentStates
 	doneCount := countWorkingToDone(prevStates, msg.AgentStates)
+	benchCmd := a.benchmarkDoneEdges(prevStates, msg.AgentStates)
 	a.tmuxActivity.activeWorkspaceIDs = msg.ActiveWorkspaceIDs
 	a.tmuxActivity.agentStates = msg.AgentStates
 	a.tmuxActivity.settledScans++
@@ -157,9 +158,9 @@ func (a *App) applyTmuxActivityPayload(msg tmuxActivityResult) tea.Cmd {
 		if doneCount > 1 {
 			msgText = fmt.Sprintf("%d agents finished", doneCount)
 		}
-		return common.SafeBatch(a.toast.ShowInfo(msgText), spinner, tagCmd)
+		return common.SafeBatch(a.toast.ShowInfo(msgText), spinner, tagCmd, benchCmd)
 	}
-	return common.SafeBatch(spinner, tagCmd)
+	return common.SafeBatch(spinner, tagCmd, benchCmd)
 }
 
 // agentStateTagChange pairs a tmux session name with its newly classified
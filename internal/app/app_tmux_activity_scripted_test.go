@@ -0,0 +1,106 @@
+package app
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/andyrewlee/amux/internal/tmux"
+)
+
+// scriptedActivityTmuxOps replays scripted pane content, one entry per
+// activity scan, for a single agent session.
+type scriptedActivityTmuxOps struct {
+	sessionName   string
+	workspaceID   string
+	contentByScan []string
+	scanIndex     int
+	prefilterErr  error
+	lastOutputAge time.Duration
+}
+
+func (s *scriptedActivityTmuxOps) EnsureAvailable() error { return nil }
+func (s *scriptedActivityTmuxOps) InstallHint() string    { return "" }
+
+func (s *scriptedActivityTmuxOps) ActiveAgentSessionsByActivity(time.Duration, tmux.Options) ([]tmux.SessionActivity, error) {
+	if s.prefilterErr != nil {
+		return nil, s.prefilterErr
+	}
+	return []tmux.SessionActivity{{
+		Name:        s.sessionName,
+		WorkspaceID: s.workspaceID,
+		Type:        "agent",
+		Tagged:      true,
+	}}, nil
+}
+
+// SessionsWithTags increments scanIndex so CapturePaneTail can serve the
+// matching content. This mirrors the real call order: sessions are fetched
+// before pane content is captured.
+func (s *scriptedActivityTmuxOps) SessionsWithTags(map[string]string, []string, tmux.Options) ([]tmux.SessionTagValues, error) {
+	s.scanIndex++
+	tagTime := time.Now()
+	if s.lastOutputAge > 0 {
+		tagTime = tagTime.Add(-s.lastOutputAge)
+	}
+	nowMillis := strconv.FormatInt(tagTime.UnixMilli(), 10)
+	return []tmux.SessionTagValues{{
+		Name: s.sessionName,
+		Tags: map[string]string{
+			"@amux":              "1",
+			"@amux_workspace":    s.workspaceID,
+			"@amux_tab":          "tab-1",
+			"@amux_type":         "agent",
+			tmux.TagLastOutputAt: nowMillis,
+		},
+	}}, nil
+}
+
+func (s *scriptedActivityTmuxOps) AllSessionStates(tmux.Options) (map[string]tmux.SessionState, error) {
+	return map[string]tmux.SessionState{
+		s.sessionName: {Exists: true, HasLivePane: true},
+	}, nil
+}
+
+func (s *scriptedActivityTmuxOps) SessionStateFor(string, tmux.Options) (tmux.SessionState, error) {
+	return tmux.SessionState{Exists: true, HasLivePane: true}, nil
+}
+
+func (s *scriptedActivityTmuxOps) SessionHasClients(string, tmux.Options) (bool, error) {
+	return true, nil
+}
+
+func (s *scriptedActivityTmuxOps) SessionCreatedAt(string, tmux.Options) (int64, error) {
+	return 0, nil
+}
+func (s *scriptedActivityTmuxOps) SessionPanePIDs(string, tmux.Options) ([]int, error) {
+	return nil, nil
+}
+func (s *scriptedActivityTmuxOps) KillSession(string, tmux.Options) error { return nil }
+func (s *scriptedActivityTmuxOps) KillSessionsMatchingTags(map[string]string, tmux.Options) (bool, error) {
+	return false, nil
+}
+func (s *scriptedActivityTmuxOps) KillSessionsWithPrefix(string, tmux.Options) error { return nil }
+func (s *scriptedActivityTmuxOps) KillSessionsWithPrefixMissingTag(string, string, tmux.Options) error {
+	return nil
+}
+func (s *scriptedActivityTmuxOps) KillWorkspaceSessions(string, tmux.Options) error { return nil }
+func (s *scriptedActivityTmuxOps) SetMonitorActivityOn(tmux.Options) error          { return nil }
+func (s *scriptedActivityTmuxOps) SetStatusOff(tmux.Options) error                  { return nil }
+
+func (s *scriptedActivityTmuxOps) CapturePaneTail(string, int, tmux.Options) (string, bool) {
+	if len(s.contentByScan) == 0 {
+		return "", true
+	}
+	idx := s.scanIndex - 1
+	if idx < 0 {
+		idx = 0
+	}
+	if idx >= len(s.contentByScan) {
+		idx = len(s.contentByScan) - 1
+	}
+	return s.contentByScan[idx], true
+}
+
+func (s *scriptedActivityTmuxOps) ContentHash(content string) [16]byte {
+	return tmux.ContentHash(content)
+}
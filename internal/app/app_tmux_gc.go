@@ -56,7 +56,7 @@ func (a *App) collectKnownWorkspaceIDs() map[string]bool {
 // gcOrphanedTmuxSessions returns a Cmd that finds and kills tmux sessions
 // belonging to workspaces that no longer exist.
 func (a *App) gcOrphanedTmuxSessions() tea.Cmd {
-	if !a.tmuxAvailable || !a.projectsLoaded {
+	if !a.tmuxAvailable || !a.projectsLoaded || a.readOnly {
 		return nil
 	}
 	knownIDs := a.collectKnownWorkspaceIDs()
@@ -78,7 +78,7 @@ func (a *App) gcOrphanedTmuxSessions() tea.Cmd {
 }
 
 func (a *App) gcStaleDetachedAgentSessions() tea.Cmd {
-	if !a.tmuxAvailable {
+	if !a.tmuxAvailable || a.readOnly {
 		return nil
 	}
 	opts := a.tmuxOptions
@@ -0,0 +1,248 @@
+package app
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	tea "charm.land/bubbletea/v2"
+
+	"github.com/andyrewlee/amux/internal/data"
+	"github.com/andyrewlee/amux/internal/logging"
+	"github.com/andyrewlee/amux/internal/messages"
+	"github.com/andyrewlee/amux/internal/tmux"
+	"github.com/andyrewlee/amux/internal/ui/common"
+)
+
+// Choices offered by the leftover sessions dialog.
+const (
+	leftoverAdopt   = "Adopt into tabs"
+	leftoverCleanUp = "Clean up"
+	leftoverLeave   = "Leave running"
+)
+
+// leftoverState holds the sessions a previous amux left running, found once
+// per run when both tmux and the projects are ready.
+type leftoverState struct {
+	scanned bool
+	found   leftoverSessionsFound
+	choices []string
+}
+
+// leftoverSessionsFound lists live amux sessions that no running amux owns
+// and no tab shows, typically because amux crashed before saving its tabs.
+type leftoverSessionsFound struct {
+	// Adoptable holds, per known workspace, agent sessions that can be
+	// reopened as tabs.
+	Adoptable map[string][]data.TabInfo
+	// Stray names sessions whose workspace is no longer known.
+	Stray []string
+}
+
+func (f leftoverSessionsFound) count() (adoptable, stray int) {
+	for _, tabs := range f.Adoptable {
+		adoptable += len(tabs)
+	}
+	return adoptable, len(f.Stray)
+}
+
+func (f leftoverSessionsFound) sessionNames() []string {
+	names := append([]string(nil), f.Stray...)
+	for _, tabs := range f.Adoptable {
+		for _, tab := range tabs {
+			names = append(names, tab.SessionName)
+		}
+	}
+	return names
+}
+
+// scanLeftoverSessions returns a Cmd that looks for sessions left by a
+// previous amux. It runs once, when the second of tmux availability and the
+// project load arrives, so the tabs every workspace restores are known.
+func (a *App) scanLeftoverSessions() tea.Cmd {
+	if a.leftover.scanned || !a.tmuxAvailable || !a.projectsLoaded || a.tmuxService == nil || a.readOnly {
+		return nil
+	}
+	a.leftover.scanned = true
+	known := a.collectKnownWorkspaceIDs()
+	tracked := make(map[string]bool)
+	assistants := make(map[string]string)
+	a.eachWorkspace(func(ws *data.Workspace, _ *data.Project) {
+		for _, tab := range ws.OpenTabs {
+			if tab.SessionName != "" {
+				tracked[tab.SessionName] = true
+			}
+		}
+		assistants[string(ws.ID())] = strings.TrimSpace(ws.Assistant)
+	})
+	defaultAssistant := a.defaultAssistantName()
+	instanceID := a.instanceID
+	opts := a.tmuxOptions
+	svc := a.tmuxService
+	return func() tea.Msg {
+		rows, err := svc.SessionsWithTags(map[string]string{"@amux": "1"}, []string{
+			"@amux_workspace",
+			"@amux_type",
+			"@amux_assistant",
+			"@amux_instance",
+			"@amux_created_at",
+			tmux.TagSessionOwnerHeartbeatAt,
+		}, opts)
+		if err != nil {
+			logging.Warn("leftover session scan failed: %v", err)
+			return nil
+		}
+		states, err := svc.AllSessionStates(opts)
+		if err != nil {
+			logging.Warn("leftover session scan failed: %v", err)
+			return nil
+		}
+		// Attached sessions are in use from another terminal. Without the
+		// bulk listing each session is checked on its own, and one that cannot
+		// be checked is skipped so nothing in use is offered for cleanup.
+		type sessionClientsLister interface {
+			SessionNamesWithClients(opts tmux.Options) (map[string]bool, error)
+		}
+		var attached map[string]bool
+		if lister, ok := svc.(sessionClientsLister); ok {
+			if attached, err = lister.SessionNamesWithClients(opts); err != nil {
+				logging.Warn("leftover session scan: failed to list attached clients: %v", err)
+				return nil
+			}
+		}
+		hasClients := func(name string) bool {
+			if attached != nil {
+				return attached[name]
+			}
+			clients, err := svc.SessionHasClients(name, opts)
+			return err != nil || clients
+		}
+
+		now := time.Now()
+		found := leftoverSessionsFound{Adoptable: make(map[string][]data.TabInfo)}
+		for _, row := range rows {
+			name := strings.TrimSpace(row.Name)
+			if name == "" || tracked[name] {
+				continue
+			}
+			if !instancesShareState(row.Tags["@amux_instance"], instanceID) ||
+				foreignSessionOwnerAlive(row.Tags, instanceID, now) {
+				continue
+			}
+			if state := states[name]; !state.Exists || !state.HasLivePane {
+				continue // dead panes are the orphan GC's to collect
+			}
+			if hasClients(name) {
+				continue
+			}
+			createdAt, _ := strconv.ParseInt(strings.TrimSpace(row.Tags["@amux_created_at"]), 10, 64)
+			if isRecentOrphanSession(createdAt, now) {
+				continue
+			}
+			wsID := strings.TrimSpace(row.Tags["@amux_workspace"])
+			if !known[wsID] {
+				found.Stray = append(found.Stray, name)
+				continue
+			}
+			// Terminal sessions of known workspaces are reattached by the
+			// sidebar when the workspace is opened.
+			if strings.TrimSpace(row.Tags["@amux_type"]) != "agent" {
+				continue
+			}
+			assistant := strings.TrimSpace(row.Tags["@amux_assistant"])
+			if assistant == "" {
+				assistant = assistants[wsID]
+			}
+			if assistant == "" {
+				assistant = defaultAssistant
+			}
+			found.Adoptable[wsID] = append(found.Adoptable[wsID], data.TabInfo{
+				Assistant:   assistant,
+				Name:        assistant,
+				SessionName: name,
+				Status:      "running",
+				CreatedAt:   createdAt,
+			})
+		}
+		if len(found.Stray) == 0 && len(found.Adoptable) == 0 {
+			return nil
+		}
+		for _, tabs := range found.Adoptable {
+			sort.SliceStable(tabs, func(i, j int) bool { return tabs[i].CreatedAt < tabs[j].CreatedAt })
+		}
+		sort.Strings(found.Stray)
+		return found
+	}
+}
+
+// handleLeftoverSessionsFound asks what to do with the sessions found.
+func (a *App) handleLeftoverSessionsFound(msg leftoverSessionsFound) tea.Cmd {
+	adoptable, stray := msg.count()
+	if adoptable+stray == 0 {
+		return nil
+	}
+	if a.dialog != nil && a.dialog.Visible() {
+		return a.toast.ShowInfo(fmt.Sprintf("%d sessions left running by a previous amux; `amux session ls` lists them", adoptable+stray))
+	}
+	var lines []string
+	if adoptable > 0 {
+		lines = append(lines, fmt.Sprintf("%d agent sessions can be reopened as tabs, scrollback intact.", adoptable))
+	}
+	if stray > 0 {
+		lines = append(lines, fmt.Sprintf("%d sessions belong to no known worktree.", stray))
+	}
+	choices := []string{leftoverCleanUp, leftoverLeave}
+	if adoptable > 0 {
+		choices = append([]string{leftoverAdopt}, choices...)
+	}
+	a.leftover.found = msg
+	a.leftover.choices = choices
+	message := fmt.Sprintf("A previous amux left %d sessions running.\n%s", adoptable+stray, strings.Join(lines, "\n"))
+	a.dialog = common.NewSelectDialog(DialogLeftoverSessions, "Sessions Left Running", message, choices)
+	if adoptable == 0 {
+		a.dialog.SetDefaultOption(1) // never default to killing
+	}
+	a.presentDialog(a.dialog)
+	return nil
+}
+
+// handleLeftoverSessionsChoice adopts, kills, or leaves the sessions found.
+func (a *App) handleLeftoverSessionsChoice(index int) tea.Cmd {
+	found, choices := a.leftover.found, a.leftover.choices
+	a.leftover.found, a.leftover.choices = leftoverSessionsFound{}, nil
+	if index < 0 || index >= len(choices) {
+		return nil
+	}
+	switch choices[index] {
+	case leftoverAdopt:
+		var cmds []tea.Cmd
+		for wsID, tabs := range found.Adoptable {
+			cmds = append(cmds, a.handleTmuxTabsDiscoverResult(tmuxTabsDiscoverResult{WorkspaceID: wsID, Tabs: tabs})...)
+		}
+		if _, stray := found.count(); stray > 0 {
+			cmds = append(cmds, a.toast.ShowInfo(fmt.Sprintf("%d sessions with no known worktree left running", stray)))
+		}
+		return common.SafeBatch(cmds...)
+	case leftoverCleanUp:
+		names := found.sessionNames()
+		opts := a.tmuxOptions
+		svc := a.tmuxService
+		return func() tea.Msg {
+			killed := 0
+			for _, name := range names {
+				if err := svc.KillSession(name, opts); err != nil {
+					logging.Warn("leftover cleanup: failed to kill session %s: %v", name, err)
+					continue
+				}
+				killed++
+			}
+			if killed < len(names) {
+				return messages.Toast{Message: fmt.Sprintf("Killed %d of %d leftover sessions", killed, len(names)), Level: messages.ToastWarning}
+			}
+			return messages.Toast{Message: fmt.Sprintf("Killed %d leftover sessions", killed), Level: messages.ToastSuccess}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,111 @@
+package app
+
+import (
+	"slices"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/andyrewlee/amux/internal/config"
+	"github.com/andyrewlee/amux/internal/data"
+	"github.com/andyrewlee/amux/internal/tmux"
+	"github.com/andyrewlee/amux/internal/ui/common"
+)
+
+func TestScanLeftoverSessions(t *testing.T) {
+	app, ws := newNotifyTestApp(config.UISettings{})
+	app.instanceID = "aaaaaaaaaaaaaaaa.1111111111111111"
+	app.tmuxAvailable = true
+	app.projectsLoaded = true
+	wsID := string(ws.ID())
+	old := strconv.FormatInt(time.Now().Add(-time.Hour).Unix(), 10)
+	row := func(name, wsID, kind string) tmux.SessionTagValues {
+		return tmux.SessionTagValues{Name: name, Tags: map[string]string{
+			"@amux_workspace":  wsID,
+			"@amux_type":       kind,
+			"@amux_assistant":  "claude",
+			"@amux_instance":   "aaaaaaaaaaaaaaaa.2222222222222222",
+			"@amux_created_at": old,
+		}}
+	}
+	live := tmux.SessionState{Exists: true, HasLivePane: true}
+	ops := &detachedGCOps{
+		rows: []tmux.SessionTagValues{
+			row("amux-agent", wsID, "agent"),     // already a tab
+			row("amux-lost", wsID, "agent"),      // adoptable
+			row("amux-term", wsID, "terminal"),   // the sidebar reattaches it
+			row("amux-gone", "deleted", "agent"), // stray
+			row("amux-dead", "deleted", "agent"), // dead pane
+			row("amux-used", "deleted", "agent"), // attached elsewhere
+		},
+		allStates: map[string]tmux.SessionState{
+			"amux-agent": live, "amux-lost": live, "amux-term": live,
+			"amux-gone": live, "amux-used": live,
+			"amux-dead": {Exists: true},
+		},
+		bulkClientNames: map[string]bool{"amux-used": true},
+	}
+	app.tmuxService = ops
+
+	cmd := app.scanLeftoverSessions()
+	if cmd == nil {
+		t.Fatal("expected a scan once tmux and the projects are ready")
+	}
+	if app.scanLeftoverSessions() != nil {
+		t.Fatal("the scan should run once per run")
+	}
+	found, ok := cmd().(leftoverSessionsFound)
+	if !ok {
+		t.Fatalf("scan returned %T, want leftoverSessionsFound", cmd())
+	}
+	if tabs := found.Adoptable[wsID]; len(tabs) != 1 || tabs[0].SessionName != "amux-lost" || tabs[0].Assistant != "claude" {
+		t.Fatalf("adoptable = %+v", found.Adoptable)
+	}
+	if !slices.Equal(found.Stray, []string{"amux-gone"}) {
+		t.Fatalf("stray = %v", found.Stray)
+	}
+}
+
+func TestLeftoverSessionsAdopt(t *testing.T) {
+	app, ws := newNotifyTestApp(config.UISettings{})
+	app.toast = common.NewToastModel()
+	wsID := string(ws.ID())
+	app.handleLeftoverSessionsFound(leftoverSessionsFound{
+		Adoptable: map[string][]data.TabInfo{wsID: {{Assistant: "claude", SessionName: "amux-lost"}}},
+		Stray:     []string{"amux-gone"},
+	})
+	if app.dialog == nil || !app.dialog.Visible() {
+		t.Fatal("expected the leftover sessions dialog")
+	}
+	if !slices.Equal(app.leftover.choices, []string{leftoverAdopt, leftoverCleanUp, leftoverLeave}) {
+		t.Fatalf("choices = %v", app.leftover.choices)
+	}
+
+	app.handleLeftoverSessionsChoice(0)
+	if n := len(ws.OpenTabs); n != 3 || ws.OpenTabs[2].SessionName != "amux-lost" {
+		t.Fatalf("tabs = %+v, want the session adopted", ws.OpenTabs)
+	}
+	if app.leftover.choices != nil {
+		t.Fatal("the choice should clear the pending sessions")
+	}
+}
+
+func TestLeftoverSessionsCleanUp(t *testing.T) {
+	app, _ := newNotifyTestApp(config.UISettings{})
+	app.toast = common.NewToastModel()
+	ops := &detachedGCOps{}
+	app.tmuxService = ops
+	app.handleLeftoverSessionsFound(leftoverSessionsFound{Stray: []string{"amux-gone", "amux-other"}})
+	if !slices.Equal(app.leftover.choices, []string{leftoverCleanUp, leftoverLeave}) {
+		t.Fatalf("choices = %v, want no adopt without adoptable sessions", app.leftover.choices)
+	}
+
+	cmd := app.handleLeftoverSessionsChoice(0)
+	if cmd == nil {
+		t.Fatal("expected a cleanup command")
+	}
+	cmd()
+	if !slices.Equal(ops.killed, []string{"amux-gone", "amux-other"}) {
+		t.Fatalf("killed = %v", ops.killed)
+	}
+}
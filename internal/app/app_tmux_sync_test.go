@@ -59,6 +59,25 @@ func (s *blockingWorkspaceStore) SetEnv(id data.WorkspaceID, env map[string]stri
 	return nil
 }
 
+func (s *blockingWorkspaceStore) SetSandbox(data.WorkspaceID, bool) error {
+	return nil
+}
+
+func (s *blockingWorkspaceStore) SetNoNetwork(data.WorkspaceID, bool) error {
+	return nil
+}
+func (s *blockingWorkspaceStore) SetGPUs(data.WorkspaceID, string) error {
+	return nil
+}
+
+func (s *blockingWorkspaceStore) SetBase(data.WorkspaceID, string) error {
+	return nil
+}
+
+func (s *blockingWorkspaceStore) SetFavorite(data.WorkspaceID, bool) error {
+	return nil
+}
+
 func (s *blockingWorkspaceStore) ResolvedDefaultAssistant() string {
 	return data.DefaultAssistant
 }
@@ -0,0 +1,100 @@
+package app
+
+import (
+	"fmt"
+	"path/filepath"
+
+	tea "charm.land/bubbletea/v2"
+
+	"github.com/andyrewlee/amux/internal/data"
+	"github.com/andyrewlee/amux/internal/ui/common"
+)
+
+// trashView is the trash listing: deleted workspaces, then removed projects.
+// The trash dialog's options index into it in that order.
+type trashView struct {
+	workspaces []data.TrashedWorkspace
+	projects   []data.TrashedProject
+}
+
+// trashLoaded carries the trash listing for the trash dialog.
+type trashLoaded struct {
+	Entries  []data.TrashedWorkspace
+	Projects []data.TrashedProject
+	Err      error
+}
+
+// handleShowTrashDialog loads the trash off the UI goroutine.
+func (a *App) handleShowTrashDialog() tea.Cmd {
+	svc := a.workspaceService
+	if svc == nil {
+		return nil
+	}
+	return func() tea.Msg {
+		entries, err := svc.TrashedWorkspaces()
+		if err != nil {
+			return trashLoaded{Err: err}
+		}
+		projects, err := svc.TrashedProjects()
+		return trashLoaded{Entries: entries, Projects: projects, Err: err}
+	}
+}
+
+// handleTrashLoaded shows the trash view; choosing an entry restores it.
+func (a *App) handleTrashLoaded(msg trashLoaded) tea.Cmd {
+	if msg.Err != nil {
+		return common.ReportError("loading trash", msg.Err, "Failed to load trash: "+msg.Err.Error())
+	}
+	if len(msg.Entries) == 0 && len(msg.Projects) == 0 {
+		return a.toast.ShowInfo("Trash is empty")
+	}
+	options := make([]string, 0, len(msg.Entries)+len(msg.Projects))
+	for _, entry := range msg.Entries {
+		option := fmt.Sprintf("%s (%s) · deleted %s",
+			entry.Workspace.Name, filepath.Base(entry.Workspace.Repo), entry.DeletedAt.Format("Jan 2 15:04"))
+		if entry.HasChanges {
+			option += " · uncommitted changes"
+		}
+		options = append(options, option)
+	}
+	for _, project := range msg.Projects {
+		options = append(options, fmt.Sprintf("project %s (%d workspaces) · removed %s",
+			project.Name(), len(project.Workspaces), project.DeletedAt.Format("Jan 2 15:04")))
+	}
+	a.dialogTrash = trashView{workspaces: msg.Entries, projects: msg.Projects}
+	a.dialog = common.NewListDialog(DialogTrash, "Trash", "Restore a deleted workspace or project:", options)
+	a.presentDialog(a.dialog)
+	return nil
+}
+
+// restoreFromTrashDialog restores the trash entry chosen in the trash view.
+func (a *App) restoreFromTrashDialog(trash trashView, index int) tea.Cmd {
+	if index >= len(trash.workspaces) {
+		index -= len(trash.workspaces)
+		if index >= len(trash.projects) {
+			return nil
+		}
+		return a.restoreProject(trash.projects[index].Path)
+	}
+	if index < 0 {
+		return nil
+	}
+	entry := trash.workspaces[index]
+	project := a.findProjectByPath(entry.Workspace.Repo)
+	if project == nil {
+		return a.toast.ShowWarning("Cannot restore workspace: its project is no longer open")
+	}
+	return common.SafeBatch(a.restoreTrashedWorkspace(project, &entry)...)
+}
+
+// restoreProject re-adds a removed project, with its workspaces when it is
+// still in the trash.
+func (a *App) restoreProject(path string) tea.Cmd {
+	if a.readOnly {
+		return a.readOnlyRefusal("restore projects")
+	}
+	if a.workspaceService == nil {
+		return nil
+	}
+	return a.workspaceService.RestoreProject(path)
+}
@@ -0,0 +1,163 @@
+package app
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/andyrewlee/amux/internal/data"
+	"github.com/andyrewlee/amux/internal/messages"
+	"github.com/andyrewlee/amux/internal/testutil"
+)
+
+func TestDeleteWorkspaceKeepsUncommittedChanges(t *testing.T) {
+	trash := data.NewWorkspaceTrash(t.TempDir())
+	project := &data.Project{Name: "repo", Path: "/tmp/repo"}
+	ws := data.NewWorkspace("feature", "feature", "main", "/tmp/repo", "/tmp/workspaces/repo/feature")
+
+	svc := newWorkspaceService(nil, nil, nil, "/tmp/workspaces")
+	svc.trash = trash
+	svc.gitOps = &mockGitOps{uncommitted: func(string) ([]byte, error) { return []byte("diff --git a/x b/x\n"), nil }}
+	if deleted, ok := svc.DeleteWorkspace(project, ws)().(messages.WorkspaceDeleted); !ok || !deleted.Trashed {
+		t.Fatal("expected a trashed WorkspaceDeleted")
+	}
+	if entry, err := trash.Get(ws.ID()); err != nil || !entry.HasChanges {
+		t.Fatalf("trash entry = %+v, %v; want the changes kept", entry, err)
+	}
+}
+
+func TestFinishTrashedWorkspaceReappliesChanges(t *testing.T) {
+	repo := testutil.InitRepo(t)
+	root := filepath.Join(t.TempDir(), "feature")
+	testutil.RunGit(t, repo, "worktree", "add", "-b", "feature", root)
+	ws := data.NewWorkspace("feature", "feature", "main", repo, root)
+
+	trash := data.NewWorkspaceTrash(t.TempDir())
+	if err := trash.Put(ws, ""); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+	patch := "diff --git a/README.md b/README.md\n--- a/README.md\n+++ b/README.md\n@@ -1 +1 @@\n-init\n+edited\n"
+	if err := trash.PutChanges(ws.ID(), []byte("not a patch\n")); err != nil {
+		t.Fatalf("PutChanges: %v", err)
+	}
+	svc := newWorkspaceService(nil, nil, nil, "")
+	svc.trash = trash
+
+	if _, err := svc.finishTrashedWorkspace(ws, ws.Name); err == nil {
+		t.Fatal("expected an error when the changes do not apply")
+	}
+	if _, err := trash.Get(ws.ID()); err != nil {
+		t.Fatalf("the entry should stay in the trash when its changes fail: %v", err)
+	}
+
+	if err := trash.PutChanges(ws.ID(), []byte(patch)); err != nil {
+		t.Fatalf("PutChanges: %v", err)
+	}
+	if _, err := svc.finishTrashedWorkspace(ws, ws.Name); err != nil {
+		t.Fatalf("finishTrashedWorkspace() error = %v", err)
+	}
+	if got, _ := os.ReadFile(filepath.Join(root, "README.md")); string(got) != "edited\n" {
+		t.Fatalf("README.md = %q, want the kept change reapplied", got)
+	}
+	if _, err := trash.Get(ws.ID()); !os.IsNotExist(err) {
+		t.Fatalf("expected the entry removed after restore, got %v", err)
+	}
+}
+
+func TestRemovedProjectRestoresFromTrash(t *testing.T) {
+	repo := testutil.InitRepo(t)
+	registry := data.NewRegistry(filepath.Join(t.TempDir(), "projects.json"))
+	if err := registry.AddProject(repo); err != nil {
+		t.Fatalf("AddProject() error = %v", err)
+	}
+	store := data.NewWorkspaceStore(t.TempDir())
+	root := filepath.Join(t.TempDir(), "feature")
+	if err := os.MkdirAll(root, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	ws := data.NewWorkspace("Feature", "feature", "main", repo, root)
+	ws.Assistant = "codex"
+	if err := store.Save(ws); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	trash := data.NewWorkspaceTrash(t.TempDir())
+	svc := newWorkspaceService(registry, store, nil, "")
+	svc.trash = trash
+	svc.gitOps = &mockGitOps{}
+
+	project := data.NewProject(repo)
+	project.Favorite = true
+	if _, ok := svc.RemoveProject(project)().(messages.ProjectRemoved); !ok {
+		t.Fatal("expected ProjectRemoved")
+	}
+	if _, err := store.Load(ws.ID()); err == nil {
+		t.Fatal("removing the project should discard its workspace metadata")
+	}
+
+	h := newDialogHarness(t)
+	h.app.workspaceService = svc
+	h.app.handleTrashLoaded(h.app.handleShowTrashDialog()().(trashLoaded))
+	if view := h.Render().Content; !strings.Contains(view, "project "+filepath.Base(repo)+" (1 workspaces)") {
+		t.Fatalf("trash view = %q, want the removed project", view)
+	}
+
+	if _, ok := h.app.restoreFromTrashDialog(h.app.dialogTrash, 0)().(messages.RefreshDashboard); !ok {
+		t.Fatal("expected the restore to refresh the dashboard")
+	}
+	if paths, _ := registry.Projects(); len(paths) != 1 {
+		t.Fatalf("registry = %v, want the project back", paths)
+	}
+	if favorites, _ := registry.Favorites(); !favorites[repo] {
+		t.Fatal("the project should still be a favorite")
+	}
+	if stored, err := store.Load(ws.ID()); err != nil || stored.Name != "Feature" || stored.Assistant != "codex" {
+		t.Fatalf("restored workspace = %+v, %v; want its metadata back", stored, err)
+	}
+	if projects, _ := trash.Projects(); len(projects) != 0 {
+		t.Fatalf("trash = %+v, want the project entry dropped", projects)
+	}
+}
+
+func TestTrashViewListsWorkspacesThenProjects(t *testing.T) {
+	h := newDialogHarness(t)
+	deleted := time.Date(2026, 1, 2, 3, 4, 0, 0, time.UTC)
+	h.app.handleTrashLoaded(trashLoaded{
+		Entries: []data.TrashedWorkspace{{
+			Workspace:  data.Workspace{Name: "feature", Repo: "/repo/api"},
+			DeletedAt:  deleted,
+			HasChanges: true,
+		}},
+		Projects: []data.TrashedProject{{Path: "/repo/web", DeletedAt: deleted}},
+	})
+	view := h.Render().Content
+	for _, want := range []string{"feature (api) · deleted Jan 2 03:04 · uncommitted changes", "project web (0 workspaces)"} {
+		if !strings.Contains(view, want) {
+			t.Fatalf("trash view = %q, want %q", view, want)
+		}
+	}
+	if h.app.restoreFromTrashDialog(h.app.dialogTrash, 2) != nil {
+		t.Fatal("an index past the listing should do nothing")
+	}
+}
+
+func TestTrashRestoreRefusedReadOnly(t *testing.T) {
+	h := newDialogHarness(t)
+	h.app.projects = []data.Project{{Name: "api", Path: "/repo/api"}}
+	h.app.handleTrashLoaded(trashLoaded{
+		Entries:  []data.TrashedWorkspace{{Workspace: data.Workspace{Name: "feature", Branch: "feature", Repo: "/repo/api"}}},
+		Projects: []data.TrashedProject{{Path: "/repo/web"}},
+	})
+	h.app.SetReadOnly("amux (pid 1)")
+
+	for index, what := range []string{"workspace", "project"} {
+		h.app.toast.Dismiss()
+		if h.app.restoreFromTrashDialog(h.app.dialogTrash, index) == nil || !h.app.toast.Visible() {
+			t.Fatalf("restoring a %s should be refused with a toast", what)
+		}
+	}
+	if len(h.app.undo.restoring) != 0 {
+		t.Fatalf("restoring = %v, want no workspace recreated", h.app.undo.restoring)
+	}
+}
@@ -0,0 +1,33 @@
+package app
+
+import (
+	"fmt"
+
+	tea "charm.land/bubbletea/v2"
+
+	"github.com/andyrewlee/amux/internal/data"
+	"github.com/andyrewlee/amux/internal/ui/common"
+)
+
+// showTrustAndRunDialog asks to trust ws's .amux/workspaces.json before an
+// action that runs a command from it. On confirm, then is called with the
+// reviewed content hash, which it passes on so trust is only recorded if the
+// file has not changed since.
+func (a *App) showTrustAndRunDialog(ws *data.Workspace, hash, action string, then func(ws *data.Workspace, trustHash string) tea.Cmd) {
+	if ws == nil {
+		return
+	}
+	a.dialog = common.NewConfirmDialog(
+		DialogTrustAndRun,
+		"Trust Project Scripts",
+		fmt.Sprintf("Trust .amux/workspaces.json scripts for '%s' and %s?", ws.Name, action),
+	)
+	a.dialog.SetDefaultOption(1)
+	if warning := scriptIndirectionWarning(a.repoScriptCommandsForTrust(ws.Repo), ws.Repo); warning != "" {
+		a.dialog.SetWarning(warning)
+	}
+	a.dialogWorkspace = ws
+	a.dialogTrustScriptsHash = hash
+	a.dialogTrustThen = then
+	a.presentDialog(a.dialog)
+}
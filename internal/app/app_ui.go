@@ -9,6 +9,9 @@ import (
 // setFocusedPane updates pane focus state without triggering pane-specific side effects.
 func (a *App) setFocusedPane(pane messages.PaneType) {
 	a.focusedPane = pane
+	if !isTerminalPane(pane) {
+		a.inputLocked = false
+	}
 	// Keep focus transitions fail-safe for partially initialized App instances
 	// used in lightweight tests.
 	a.syncPaneFocusFlags()
@@ -143,6 +146,9 @@ func (a *App) updateLayout() {
 	if a.envDialog != nil {
 		a.envDialog.SetSize(a.width, a.height)
 	}
+	if a.searchPanel != nil {
+		a.searchPanel.SetSize(a.width, a.height)
+	}
 }
 
 func (a *App) setKeymapHintsEnabled(enabled bool) {
@@ -0,0 +1,210 @@
+package app
+
+import (
+	"strings"
+
+	tea "charm.land/bubbletea/v2"
+
+	"github.com/andyrewlee/amux/internal/data"
+	"github.com/andyrewlee/amux/internal/logging"
+	"github.com/andyrewlee/amux/internal/messages"
+)
+
+// maxUndoEntries bounds the undo stack; older entries are dropped first.
+const maxUndoEntries = 20
+
+type undoKind int
+
+const (
+	// undoCloseTab relaunches a closed agent tab's assistant in its workspace.
+	// The closed tab's tmux session is gone, so this is a fresh agent.
+	undoCloseTab undoKind = iota
+	// undoRemoveProject re-adds a removed project to the registry.
+	undoRemoveProject
+	// undoDeleteWorkspace recreates a deleted workspace from its trash entry.
+	undoDeleteWorkspace
+	// undoFocusJump returns to the dashboard after focus followed an agent
+	// that needed attention.
+	undoFocusJump
+)
+
+// undoEntry records enough about one destructive action to reverse it.
+type undoEntry struct {
+	kind        undoKind
+	projectPath string
+	workspaceID string
+	assistant   string
+}
+
+// undoState is the App's undo stack (newest last) plus the trashed workspaces
+// currently being recreated, by ID with the name each had, whose trash
+// entries are dropped once the recreated workspace exists.
+type undoState struct {
+	entries   []undoEntry
+	restoring map[string]string
+}
+
+func (s *undoState) push(entry undoEntry) {
+	s.entries = append(s.entries, entry)
+	if over := len(s.entries) - maxUndoEntries; over > 0 {
+		s.entries = append([]undoEntry(nil), s.entries[over:]...)
+	}
+}
+
+func (s *undoState) pop() (undoEntry, bool) {
+	if len(s.entries) == 0 {
+		return undoEntry{}, false
+	}
+	entry := s.entries[len(s.entries)-1]
+	s.entries = s.entries[:len(s.entries)-1]
+	return entry, true
+}
+
+// recordTabClosedUndo remembers a closed agent tab so it can be relaunched.
+// Diff viewers and unknown assistants are not recorded.
+func (a *App) recordTabClosedUndo(msg messages.TabClosed) {
+	if strings.TrimSpace(msg.WorkspaceID) == "" || msg.Assistant == "diff" || !a.isKnownAssistant(msg.Assistant) {
+		return
+	}
+	a.undo.push(undoEntry{kind: undoCloseTab, workspaceID: msg.WorkspaceID, assistant: msg.Assistant})
+}
+
+// recordProjectRemovedUndo remembers a removed project so it can be re-added.
+func (a *App) recordProjectRemovedUndo(path string) {
+	if strings.TrimSpace(path) == "" {
+		return
+	}
+	a.undo.push(undoEntry{kind: undoRemoveProject, projectPath: path})
+}
+
+// recordWorkspaceDeletedUndo remembers a trashed workspace delete so it can be
+// restored from the trash.
+func (a *App) recordWorkspaceDeletedUndo(msg messages.WorkspaceDeleted) {
+	if !msg.Trashed || msg.Project == nil || msg.Workspace == nil {
+		return
+	}
+	a.undo.push(undoEntry{
+		kind:        undoDeleteWorkspace,
+		projectPath: msg.Project.Path,
+		workspaceID: string(msg.Workspace.ID()),
+	})
+}
+
+// handleUndo reverses the most recent undoable action.
+func (a *App) handleUndo() tea.Cmd {
+	entry, ok := a.undo.pop()
+	if !ok {
+		return a.toast.ShowInfo("Nothing to undo")
+	}
+	switch entry.kind {
+	case undoCloseTab:
+		ws := a.findWorkspaceByID(entry.workspaceID)
+		if ws == nil {
+			return a.toast.ShowWarning("Cannot reopen tab: its workspace no longer exists")
+		}
+		logging.Info("Undo: reopening %s tab in workspace %s", entry.assistant, ws.Name)
+		return func() tea.Msg { return messages.LaunchAgent{Assistant: entry.assistant, Workspace: ws} }
+	case undoRemoveProject:
+		logging.Info("Undo: re-adding project %s", entry.projectPath)
+		return a.restoreProject(entry.projectPath)
+	case undoDeleteWorkspace:
+		return a.restoreTrashedWorkspaceByID(entry.projectPath, data.WorkspaceID(entry.workspaceID))
+	case undoFocusJump:
+		return a.undoFocusJump(entry.workspaceID)
+	}
+	return nil
+}
+
+// trashRestoreLoaded carries a trash entry loaded for an undo-restore.
+type trashRestoreLoaded struct {
+	Project *data.Project
+	Entry   *data.TrashedWorkspace
+}
+
+// restoreTrashedWorkspaceByID loads a trash entry off the UI goroutine; the
+// workspace is recreated when trashRestoreLoaded arrives.
+func (a *App) restoreTrashedWorkspaceByID(projectPath string, id data.WorkspaceID) tea.Cmd {
+	project := a.findProjectByPath(projectPath)
+	if project == nil {
+		return a.toast.ShowWarning("Cannot restore workspace: its project is no longer open")
+	}
+	svc := a.workspaceService
+	if svc == nil {
+		return nil
+	}
+	return func() tea.Msg {
+		entry, err := svc.TrashedWorkspace(id)
+		if err != nil {
+			return messages.Error{Err: err, Context: errorContext(errorServiceWorkspace, "restoring workspace from trash")}
+		}
+		return trashRestoreLoaded{Project: project, Entry: entry}
+	}
+}
+
+// restoreTrashedWorkspace recreates a trashed workspace through the normal
+// create flow: its branch recreated at the commit it was deleted at (the flow
+// names the worktree after it, so the path and ID match), and its assistant.
+// Its name, when it differs from the branch, and uncommitted changes kept
+// with the entry are restored by finishTrashRestore.
+func (a *App) restoreTrashedWorkspace(project *data.Project, entry *data.TrashedWorkspace) []tea.Cmd {
+	if project == nil || entry == nil {
+		return nil
+	}
+	if a.readOnly {
+		return []tea.Cmd{a.readOnlyRefusal("restore workspaces")}
+	}
+	ws := entry.Workspace
+	name := strings.TrimSpace(ws.Name)
+	branch := strings.TrimSpace(ws.Branch)
+	if branch == "" {
+		branch = name
+	}
+	base := entry.HeadCommit
+	if base == "" {
+		base = ws.Base
+	}
+	assistant := strings.TrimSpace(ws.Assistant)
+	if assistant == "" {
+		assistant = data.DefaultAssistant
+	}
+	logging.Info("Restoring workspace %s from trash at %s", name, base)
+	if a.undo.restoring == nil {
+		a.undo.restoring = make(map[string]string)
+	}
+	a.undo.restoring[string(entry.ID())] = name
+	return a.handleCreateWorkspace(messages.CreateWorkspace{
+		Project:   project,
+		Name:      branch,
+		Base:      base,
+		Assistant: assistant,
+	})
+}
+
+// finishTrashRestore gives a recreated workspace back its name, reapplies its
+// kept uncommitted changes and drops its trash entry. Workspaces that were not
+// restored from the trash are left alone.
+func (a *App) finishTrashRestore(ws *data.Workspace) tea.Cmd {
+	if ws == nil {
+		return nil
+	}
+	id := ws.ID()
+	name, restoring := a.undo.restoring[string(id)]
+	if !restoring {
+		return nil
+	}
+	delete(a.undo.restoring, string(id))
+	svc := a.workspaceService
+	if svc == nil {
+		return nil
+	}
+	return func() tea.Msg {
+		renamed, err := svc.finishTrashedWorkspace(ws, name)
+		if err != nil {
+			return messages.Error{Err: err, Context: errorContext(errorServiceWorkspace, "restoring workspace from trash")}
+		}
+		if renamed {
+			return messages.RefreshDashboard{}
+		}
+		return nil
+	}
+}
@@ -0,0 +1,171 @@
+package app
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/andyrewlee/amux/internal/data"
+	"github.com/andyrewlee/amux/internal/messages"
+	"github.com/andyrewlee/amux/internal/ui/common"
+	"github.com/andyrewlee/amux/internal/ui/dashboard"
+)
+
+func TestDeleteWorkspaceTrashesMetadataWithHead(t *testing.T) {
+	trash := data.NewWorkspaceTrash(t.TempDir())
+	project := &data.Project{Name: "repo", Path: "/tmp/repo"}
+	ws := data.NewWorkspace("feature", "feature", "main", "/tmp/repo", "/tmp/workspaces/repo/feature")
+
+	svc := newWorkspaceService(nil, nil, nil, "/tmp/workspaces")
+	svc.trash = trash
+	svc.gitOps = &mockGitOps{headCommit: func(string) (string, error) { return "deadbeef\n", nil }}
+	msg := svc.DeleteWorkspace(project, ws)()
+
+	deleted, ok := msg.(messages.WorkspaceDeleted)
+	if !ok {
+		t.Fatalf("expected WorkspaceDeleted, got %T", msg)
+	}
+	if !deleted.Trashed {
+		t.Fatal("expected the delete to report the workspace as trashed")
+	}
+	entry, err := trash.Get(ws.ID())
+	if err != nil {
+		t.Fatalf("trash.Get: %v", err)
+	}
+	if entry.HeadCommit != "deadbeef" || entry.Workspace.Branch != "feature" {
+		t.Fatalf("unexpected trash entry: %+v", entry)
+	}
+}
+
+func TestDeleteWorkspaceWithoutTrashIsNotUndoable(t *testing.T) {
+	project := &data.Project{Name: "repo", Path: "/tmp/repo"}
+	ws := data.NewWorkspace("feature", "feature", "main", "/tmp/repo", "/tmp/workspaces/repo/feature")
+
+	svc := newWorkspaceService(nil, nil, nil, "/tmp/workspaces")
+	svc.gitOps = &mockGitOps{}
+	msg := svc.DeleteWorkspace(project, ws)()
+
+	if deleted, ok := msg.(messages.WorkspaceDeleted); !ok || deleted.Trashed {
+		t.Fatalf("expected an untrashed WorkspaceDeleted, got %#v", msg)
+	}
+}
+
+func TestUndoReopensClosedAgentTab(t *testing.T) {
+	ws := data.NewWorkspace("feature", "feature", "main", "/tmp/repo", "/tmp/workspaces/repo/feature")
+	app := &App{toast: common.NewToastModel(), activeWorkspace: ws}
+
+	app.recordTabClosedUndo(messages.TabClosed{WorkspaceID: string(ws.ID()), Assistant: "diff"})
+	app.recordTabClosedUndo(messages.TabClosed{WorkspaceID: string(ws.ID()), Assistant: "codex"})
+	if len(app.undo.entries) != 1 {
+		t.Fatalf("undo entries = %d, want only the agent tab recorded", len(app.undo.entries))
+	}
+
+	cmd := app.handleUndo()
+	if cmd == nil {
+		t.Fatal("expected an undo command")
+	}
+	launched, ok := cmd().(messages.LaunchAgent)
+	if !ok || launched.Assistant != "codex" || launched.Workspace != ws {
+		t.Fatalf("expected LaunchAgent for codex in the workspace, got %#v", launched)
+	}
+	if len(app.undo.entries) != 0 {
+		t.Fatal("expected the undo entry to be consumed")
+	}
+}
+
+func TestUndoWithEmptyStackIsANoOpToast(t *testing.T) {
+	app := &App{toast: common.NewToastModel()}
+	_ = app.handleUndo()
+	if !app.toast.Visible() {
+		t.Fatal("expected a 'nothing to undo' toast")
+	}
+}
+
+func TestUndoStackIsBounded(t *testing.T) {
+	var state undoState
+	for i := 0; i < maxUndoEntries+5; i++ {
+		state.push(undoEntry{kind: undoRemoveProject, projectPath: string(rune('a' + i))})
+	}
+	if len(state.entries) != maxUndoEntries {
+		t.Fatalf("entries = %d, want %d", len(state.entries), maxUndoEntries)
+	}
+	last, _ := state.pop()
+	if last.projectPath != string(rune('a'+maxUndoEntries+4)) {
+		t.Fatalf("pop returned %q, want the newest entry", last.projectPath)
+	}
+}
+
+func TestRestoreTrashedWorkspaceRecreatesAtHeadAndForgetsEntry(t *testing.T) {
+	tmp := t.TempDir()
+	workspacesRoot := filepath.Join(tmp, "workspaces")
+	project := data.NewProject(filepath.Join(tmp, "repo"))
+	root := filepath.Join(workspacesRoot, project.Name, "feature")
+	// Renamed after it was created, so its name is no longer its branch.
+	ws := data.NewWorkspace("checkout-redesign", "feature", "main", project.Path, root)
+	ws.Assistant = "codex"
+
+	trash := data.NewWorkspaceTrash(filepath.Join(tmp, "trash"))
+	if err := trash.Put(ws, "deadbeef"); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+	entry, err := trash.Get(ws.ID())
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+
+	var gotBranch, gotBase string
+	store := data.NewWorkspaceStore(filepath.Join(tmp, "metadata"))
+	svc := newWorkspaceService(nil, store, nil, workspacesRoot)
+	svc.trash = trash
+	svc.gitOps = &mockGitOps{
+		createWorkspace: func(repoPath, workspacePath, branch, base string) error {
+			gotBranch, gotBase = branch, base
+			if err := os.MkdirAll(workspacePath, 0o755); err != nil {
+				return err
+			}
+			return os.WriteFile(filepath.Join(workspacePath, ".git"), []byte("gitdir: x"), 0o644)
+		},
+	}
+	app := &App{
+		toast:            common.NewToastModel(),
+		dashboard:        dashboard.New(),
+		lifecycle:        workspaceLifecycleState{phases: make(map[string]lifecyclePhase)},
+		workspaceService: svc,
+	}
+
+	var created *data.Workspace
+	for _, cmd := range app.restoreTrashedWorkspace(project, entry) {
+		if cmd == nil {
+			continue
+		}
+		if msg, ok := cmd().(messages.WorkspaceCreated); ok {
+			created = msg.Workspace
+		}
+	}
+	if created == nil {
+		t.Fatal("expected the restore to recreate the workspace")
+	}
+	if gotBranch != "feature" || gotBase != "deadbeef" {
+		t.Fatalf("recreated branch %q at %q, want feature at deadbeef", gotBranch, gotBase)
+	}
+	if created.ID() != ws.ID() || created.Assistant != "codex" {
+		t.Fatalf("recreated workspace %+v does not match the trashed one", created)
+	}
+
+	cmd := app.finishTrashRestore(created)
+	if cmd == nil {
+		t.Fatal("expected a trash cleanup command once the workspace is recreated")
+	}
+	if _, ok := cmd().(messages.RefreshDashboard); !ok {
+		t.Fatal("expected the dashboard to reload the restored name")
+	}
+	if stored, err := store.Load(ws.ID()); err != nil || stored.Name != "checkout-redesign" || stored.Branch != "feature" {
+		t.Fatalf("restored workspace = %+v, %v; want its name back on its branch", stored, err)
+	}
+	if _, err := trash.Get(ws.ID()); !os.IsNotExist(err) {
+		t.Fatalf("expected the trash entry to be removed after restore, got %v", err)
+	}
+	if app.finishTrashRestore(created) != nil {
+		t.Fatal("a workspace that is not being restored must not touch the trash")
+	}
+}
@@ -99,14 +99,11 @@ func (a *App) fallbackView() tea.View {
 func (a *App) viewLayerBased() tea.View {
 	view := tea.View{
 		AltScreen:            true,
-		MouseMode:            tea.MouseModeCellMotion,
+		MouseMode:            a.mouseMode(),
 		BackgroundColor:      common.ColorBackground(),
 		ForegroundColor:      common.ColorForeground(),
 		KeyboardEnhancements: tea.KeyboardEnhancements{ReportEventTypes: true},
-		WindowTitle:          fallbackWindowTitle,
-	}
-	if a.center != nil {
-		view.WindowTitle = focusedWindowTitle(a.center.FocusedAgentTitle())
+		WindowTitle:          a.windowTitle(),
 	}
 	var terminalCursor *tea.Cursor
 	setTerminalCursor := func(x, y int) {
@@ -134,6 +131,8 @@ func (a *App) viewLayerBased() tea.View {
 		a.composeSidebarPane(canvas, leftGutter, topGutter, blockingOverlayVisible, setTerminalCursor)
 	}
 
+	a.composeStatusBar(canvas)
+
 	// Overlay layers (dialogs, toasts, etc.)
 	a.composeOverlays(canvas)
 
@@ -148,6 +147,9 @@ func (a *App) viewLayerBased() tea.View {
 		!a.toastCoversPoint(terminalCursor.X, terminalCursor.Y) {
 		cursor = terminalCursor
 	}
+	if cursor != nil && common.ReducedMotion() {
+		cursor.Blink = false
+	}
 	view.SetContent(syncBegin + canvas.Render() + syncEnd)
 	view.Cursor = cursor
 	return view
@@ -16,6 +16,9 @@ import (
 func (a *App) composeOverlays(canvas *lipgloss.Canvas) {
 	prefixOverlayHeight := 0
 
+	// Input mode line (under dialogs so they stay on top)
+	a.composeInputModeLine(canvas)
+
 	// Dialog overlay
 	if a.dialog != nil && a.dialog.Visible() {
 		dialogView := a.dialog.View()
@@ -52,6 +55,15 @@ func (a *App) composeOverlays(canvas *lipgloss.Canvas) {
 		canvas.Compose(envDrawable)
 	}
 
+	// File search panel overlay
+	if a.searchPanel != nil && a.searchPanel.Visible() {
+		searchView := a.searchPanel.View()
+		searchWidth, searchHeight := viewDimensions(searchView)
+		x, y := a.centeredPosition(searchWidth, searchHeight)
+		searchDrawable := compositor.NewStringDrawable(searchView, x, y)
+		canvas.Compose(searchDrawable)
+	}
+
 	// Prefix command palette
 	if a.prefixActive {
 		palette := a.renderPrefixPalette()
@@ -211,6 +223,18 @@ func (a *App) overlayCursor() *tea.Cursor {
 		}
 	}
 
+	if a.searchPanel != nil && a.searchPanel.Visible() {
+		if c := a.searchPanel.Cursor(); c != nil {
+			searchView := a.searchPanel.View()
+			searchWidth, searchHeight := viewDimensions(searchView)
+			x, y := a.centeredPosition(searchWidth, searchHeight)
+			cursor := *c
+			cursor.X += x
+			cursor.Y += y
+			return &cursor
+		}
+	}
+
 	return nil
 }
 
@@ -219,6 +243,7 @@ func (a *App) overlayVisible() bool {
 		(a.filePicker != nil && a.filePicker.Visible()) ||
 		(a.settingsDialog != nil && a.settingsDialog.Visible()) ||
 		(a.envDialog != nil && a.envDialog.Visible()) ||
+		(a.searchPanel != nil && a.searchPanel.Visible()) ||
 		a.prefixActive ||
 		a.err != nil
 }
@@ -0,0 +1,72 @@
+package app
+
+import (
+	"strconv"
+	"strings"
+
+	tea "charm.land/bubbletea/v2"
+
+	"github.com/andyrewlee/amux/internal/messages"
+	"github.com/andyrewlee/amux/internal/ui/dashboard"
+)
+
+// jumpWorkspaceActionPrefix prefixes the actions of the numbered workspace
+// jump commands (prefix w 1..9); the suffix is the 1-based dashboard position.
+const jumpWorkspaceActionPrefix = "jump_workspace_"
+
+// workspaceJumpCommands returns a w N command for each of the first nine
+// workspaces listed on the dashboard, described by workspace name.
+func (a *App) workspaceJumpCommands() []prefixCommand {
+	if a == nil || a.dashboard == nil {
+		return nil
+	}
+	entries := a.dashboard.Workspaces()
+	commands := make([]prefixCommand, 0, min(len(entries), 9))
+	for i, entry := range entries {
+		if i == 9 {
+			break
+		}
+		n := strconv.Itoa(i + 1)
+		commands = append(commands, prefixCommand{
+			Sequence: []string{"w", n},
+			Desc:     entry.Workspace.Name,
+			Action:   jumpWorkspaceActionPrefix + n,
+		})
+	}
+	return commands
+}
+
+// runWorkspaceJumpAction handles a numbered workspace jump action, reporting
+// whether action was one.
+func (a *App) runWorkspaceJumpAction(action string) (tea.Cmd, bool) {
+	suffix, ok := strings.CutPrefix(action, jumpWorkspaceActionPrefix)
+	if !ok {
+		return nil, false
+	}
+	n, err := strconv.Atoi(suffix)
+	if err != nil {
+		return nil, true
+	}
+	return a.jumpToWorkspace(n - 1), true
+}
+
+// jumpToWorkspace activates the workspace at index in dashboard order.
+func (a *App) jumpToWorkspace(index int) tea.Cmd {
+	entries := a.dashboard.Workspaces()
+	if index < 0 || index >= len(entries) {
+		return nil
+	}
+	entry := entries[index]
+	if a.activeWorkspace != nil && a.activeWorkspace.ID() == entry.Workspace.ID() {
+		return nil
+	}
+	return a.activateWorkspaceEntry(entry)
+}
+
+// activateWorkspaceEntry acknowledges the workspace's finished indicator and
+// activates it through the same message a dashboard row selection sends.
+func (a *App) activateWorkspaceEntry(entry dashboard.WorkspaceEntry) tea.Cmd {
+	a.dashboard.AckDone(string(entry.Workspace.ID()))
+	activated := messages.WorkspaceActivated{Project: entry.Project, Workspace: entry.Workspace}
+	return func() tea.Msg { return activated }
+}
@@ -0,0 +1,33 @@
+package app
+
+import (
+	"testing"
+
+	"github.com/andyrewlee/amux/internal/messages"
+)
+
+func TestWorkspaceJumpCommandsFollowDashboardOrder(t *testing.T) {
+	app, _, featWS := newAgentCycleTestApp(t)
+
+	commands := app.workspaceJumpCommands()
+	if len(commands) != 2 {
+		t.Fatalf("expected 2 workspace jump commands, got %d", len(commands))
+	}
+	if got := commands[1]; got.Sequence[0] != "w" || got.Sequence[1] != "2" || got.Desc != featWS.Name {
+		t.Fatalf("second jump command = %+v, want w 2 for %q", got, featWS.Name)
+	}
+
+	cmd := app.runPrefixAction(commands[1].Action)
+	if cmd == nil {
+		t.Fatal("expected w 2 to activate the feat workspace")
+	}
+	if msg, ok := cmd().(messages.WorkspaceActivated); !ok || msg.Workspace.Root != featWS.Root {
+		t.Fatalf("w 2 activated %+v, want feat workspace", msg)
+	}
+	if cmd := app.runPrefixAction(commands[0].Action); cmd != nil {
+		t.Fatal("jumping to the active workspace should be a no-op")
+	}
+	if cmd := app.runPrefixAction(jumpWorkspaceActionPrefix + "9"); cmd != nil {
+		t.Fatal("jumping past the last workspace should be a no-op")
+	}
+}
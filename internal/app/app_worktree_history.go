@@ -0,0 +1,73 @@
+package app
+
+import (
+	"time"
+
+	tea "charm.land/bubbletea/v2"
+
+	"github.com/andyrewlee/amux/internal/data"
+	"github.com/andyrewlee/amux/internal/feed"
+	"github.com/andyrewlee/amux/internal/messages"
+	"github.com/andyrewlee/amux/internal/timeline"
+	"github.com/andyrewlee/amux/internal/ui/common"
+)
+
+// buildTimeline assembles a worktree's timeline; tests replace it to avoid
+// running git and gh.
+var buildTimeline = timeline.Build
+
+// worktreeHistoryLoaded carries a worktree's timeline, assembled off the UI
+// goroutine since it reads git and gh.
+type worktreeHistoryLoaded struct {
+	workspace *data.Workspace
+	events    []data.HistoryEvent
+}
+
+// showWorktreeHistory loads the active worktree's timeline for the history
+// dialog.
+func (a *App) showWorktreeHistory(ws *data.Workspace) tea.Cmd {
+	if ws == nil {
+		return nil
+	}
+	snapshot := snapshotWorkspaceForSave(ws)
+	return func() tea.Msg {
+		return worktreeHistoryLoaded{workspace: ws, events: buildTimeline(snapshot)}
+	}
+}
+
+// handleWorktreeHistoryLoaded shows the timeline, newest first.
+func (a *App) handleWorktreeHistoryLoaded(msg worktreeHistoryLoaded) tea.Cmd {
+	if len(msg.events) == 0 {
+		return a.toast.ShowInfo("No history recorded for " + msg.workspace.Name + " yet")
+	}
+	lines := make([]string, len(msg.events))
+	for i, e := range msg.events {
+		lines[len(msg.events)-1-i] = timeline.Line(e)
+	}
+	a.dialog = common.NewListDialog(DialogWorktreeHistory, "History: "+msg.workspace.Name,
+		"Lifecycle of this worktree, newest first. `amux workspace history "+msg.workspace.Name+" --json` prints it for scripts.", lines)
+	a.dialogWorkspace = msg.workspace
+	a.presentDialog(a.dialog)
+	return nil
+}
+
+// recordHistory adds an event to ws's timeline and schedules a save.
+func (a *App) recordHistory(ws *data.Workspace, kind, detail string) tea.Cmd {
+	if ws == nil {
+		return nil
+	}
+	ws.RecordHistory(kind, detail, time.Now())
+	return a.persistWorkspaceTabs(string(ws.ID()))
+}
+
+// recordAgentExit adds an agent tab's exit to its worktree's timeline and the
+// activity feed. Like the exit notification, a second report of the same exit
+// is dropped.
+func (a *App) recordAgentExit(msg messages.TabSessionStatus) tea.Cmd {
+	ws, assistant := a.stoppedAgentTab(msg)
+	if ws == nil || !firstExitReport(&a.historyExits, msg.SessionName, time.Now()) {
+		return nil
+	}
+	a.feedAdd(feed.KindExit, string(ws.ID()), assistant+" exited")
+	return a.recordHistory(ws, data.HistoryAgentExited, assistant)
+}
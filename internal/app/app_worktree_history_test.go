@@ -0,0 +1,82 @@
+package app
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/andyrewlee/amux/internal/config"
+	"github.com/andyrewlee/amux/internal/data"
+	"github.com/andyrewlee/amux/internal/messages"
+	"github.com/andyrewlee/amux/internal/ui/common"
+)
+
+func TestRecordAgentExit(t *testing.T) {
+	app, ws := newNotifyTestApp(config.UISettings{})
+	stopped := func(session string) messages.TabSessionStatus {
+		return messages.TabSessionStatus{WorkspaceID: string(ws.ID()), SessionName: session, Status: "stopped"}
+	}
+
+	if cmd := app.recordAgentExit(stopped("amux-agent")); cmd == nil {
+		t.Fatal("expected the exit to be saved")
+	}
+	app.recordAgentExit(stopped("amux-agent"))
+	app.recordAgentExit(stopped("amux-shell"))
+	if len(ws.History) != 1 || ws.History[0].Kind != data.HistoryAgentExited || ws.History[0].Detail != "claude" {
+		t.Fatalf("History = %+v, want one exit for the agent tab", ws.History)
+	}
+	if !app.lifecycle.dirty[string(ws.ID())] {
+		t.Fatal("recording history should mark the workspace for saving")
+	}
+}
+
+func TestWorktreeHistoryDialogNewestFirst(t *testing.T) {
+	app, ws := newNotifyTestApp(config.UISettings{})
+	app.toast = common.NewToastModel()
+	at := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	events := []data.HistoryEvent{
+		{Kind: data.HistoryCreated, At: at},
+		{Kind: data.HistoryAgentLaunched, At: at.Add(time.Minute), Detail: "claude"},
+	}
+	old := buildTimeline
+	t.Cleanup(func() { buildTimeline = old })
+	buildTimeline = func(*data.Workspace) []data.HistoryEvent { return events }
+
+	msg := app.showWorktreeHistory(ws)()
+	app.handleWorktreeHistoryLoaded(msg.(worktreeHistoryLoaded))
+	if app.dialog == nil || !app.dialog.Visible() {
+		t.Fatal("expected the history dialog")
+	}
+	app.dialog.SetSize(160, 40)
+	view := app.dialog.View()
+	if !strings.Contains(view, "History: feature") {
+		t.Fatalf("dialog should name the worktree:\n%s", view)
+	}
+	if launched, created := strings.Index(view, "agent launched"), strings.Index(view, "created"); launched < 0 || created < 0 || launched > created {
+		t.Fatalf("dialog should list the newest event first:\n%s", view)
+	}
+}
+
+func TestHistoryForTrashEndsWithDeletion(t *testing.T) {
+	old := deriveTimeline
+	t.Cleanup(func() { deriveTimeline = old })
+	at := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	deriveTimeline = func(*data.Workspace) []data.HistoryEvent {
+		return []data.HistoryEvent{{Kind: data.HistoryCommit, At: at.Add(time.Hour), Detail: "abc1234 Fix"}}
+	}
+	svc := &workspaceService{trash: data.NewWorkspaceTrash(t.TempDir())}
+	ws := &data.Workspace{Name: "feature"}
+	ws.RecordHistory(data.HistoryCreated, "", at)
+
+	history := svc.historyForTrash(ws)
+	var kinds []string
+	for _, e := range history {
+		kinds = append(kinds, e.Kind)
+	}
+	if got := strings.Join(kinds, ","); got != "created,commit,deleted" {
+		t.Fatalf("history kinds = %s, want the commit kept and the deletion last", got)
+	}
+	if len(ws.History) != 1 {
+		t.Fatal("the live workspace's history should be left alone")
+	}
+}
@@ -0,0 +1,55 @@
+package app
+
+import "github.com/andyrewlee/amux/internal/data"
+
+// mockGitOps implements GitOperations for tests.
+type mockGitOps struct {
+	createWorkspace    func(repoPath, workspacePath, branch, base string) error
+	removeWorkspace    func(repoPath, workspacePath string) error
+	deleteBranch       func(repoPath, branch string) error
+	headCommit         func(workspacePath string) (string, error)
+	uncommitted        func(workspacePath string) ([]byte, error)
+	discoverWorkspaces func(project *data.Project) ([]data.Workspace, error)
+}
+
+func (m *mockGitOps) CreateWorkspace(repoPath, workspacePath, branch, base string) error {
+	if m.createWorkspace != nil {
+		return m.createWorkspace(repoPath, workspacePath, branch, base)
+	}
+	return nil
+}
+
+func (m *mockGitOps) RemoveWorkspace(repoPath, workspacePath string) error {
+	if m.removeWorkspace != nil {
+		return m.removeWorkspace(repoPath, workspacePath)
+	}
+	return nil
+}
+
+func (m *mockGitOps) DeleteBranch(repoPath, branch string) error {
+	if m.deleteBranch != nil {
+		return m.deleteBranch(repoPath, branch)
+	}
+	return nil
+}
+
+func (m *mockGitOps) HeadCommit(workspacePath string) (string, error) {
+	if m.headCommit != nil {
+		return m.headCommit(workspacePath)
+	}
+	return "", nil
+}
+
+func (m *mockGitOps) UncommittedChanges(workspacePath string) ([]byte, error) {
+	if m.uncommitted != nil {
+		return m.uncommitted(workspacePath)
+	}
+	return nil, nil
+}
+
+func (m *mockGitOps) DiscoverWorkspaces(project *data.Project) ([]data.Workspace, error) {
+	if m.discoverWorkspaces != nil {
+		return m.discoverWorkspaces(project)
+	}
+	return nil, nil
+}
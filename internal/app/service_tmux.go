@@ -84,3 +84,7 @@ func (tmuxOps) CapturePaneTail(sessionName string, lines int, opts tmux.Options)
 func (tmuxOps) ContentHash(content string) [16]byte {
 	return tmux.ContentHash(content)
 }
+
+func (tmuxOps) SessionPanePIDs(sessionName string, opts tmux.Options) ([]int, error) {
+	return tmux.SessionPanePIDs(sessionName, opts)
+}
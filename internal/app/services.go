@@ -14,6 +14,8 @@ type ProjectRegistry interface {
 	Projects() ([]string, error)
 	AddProject(path string) error
 	RemoveProject(path string) error
+	Favorites() (map[string]bool, error)
+	SetFavorite(path string, favorite bool) error
 }
 
 // WorkspaceStore is the minimal interface used by the app for workspace metadata.
@@ -26,6 +28,11 @@ type WorkspaceStore interface {
 	Delete(id data.WorkspaceID) error
 	Rename(id data.WorkspaceID, newName string) error
 	SetEnv(id data.WorkspaceID, env map[string]string) error
+	SetSandbox(id data.WorkspaceID, enabled bool) error
+	SetNoNetwork(id data.WorkspaceID, enabled bool) error
+	SetGPUs(id data.WorkspaceID, devices string) error
+	SetBase(id data.WorkspaceID, base string) error
+	SetFavorite(id data.WorkspaceID, favorite bool) error
 	ResolvedDefaultAssistant() string
 }
 
@@ -57,6 +64,7 @@ type TmuxOps interface {
 	SetStatusOff(opts tmux.Options) error
 	CapturePaneTail(sessionName string, lines int, opts tmux.Options) (string, bool)
 	ContentHash(content string) [16]byte
+	SessionPanePIDs(sessionName string, opts tmux.Options) ([]int, error)
 }
 
 // UpdateService wraps release checks and upgrades.
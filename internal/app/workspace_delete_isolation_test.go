@@ -35,6 +35,23 @@ func (s *recordingWorkspaceStore) Rename(data.WorkspaceID, string) error { retur
 func (s *recordingWorkspaceStore) SetEnv(data.WorkspaceID, map[string]string) error {
 	return nil
 }
+
+func (s *recordingWorkspaceStore) SetSandbox(data.WorkspaceID, bool) error {
+	return nil
+}
+
+func (s *recordingWorkspaceStore) SetNoNetwork(data.WorkspaceID, bool) error {
+	return nil
+}
+func (s *recordingWorkspaceStore) SetGPUs(data.WorkspaceID, string) error {
+	return nil
+}
+func (s *recordingWorkspaceStore) SetBase(data.WorkspaceID, string) error {
+	return nil
+}
+func (s *recordingWorkspaceStore) SetFavorite(data.WorkspaceID, bool) error {
+	return nil
+}
 func (s *recordingWorkspaceStore) ResolvedDefaultAssistant() string { return data.DefaultAssistant }
 
 func (s *recordingWorkspaceStore) saved() []string {
@@ -72,6 +72,9 @@ func (s *workspaceService) finishInterruptedDelete(ws *data.Workspace) bool {
 		// do not finish the delete — the workspace must stay usable.
 		return false
 	}
+	if s.readOnly {
+		return true // the amux that owns the state finishes it
+	}
 	if err := s.store.Delete(ws.ID()); err != nil {
 		logging.Warn("startup recovery: failed to finish interrupted delete workspace_id=%s error=%v", ws.ID(), err)
 		if markErr := td.MarkDeleting(ws.ID()); markErr != nil {
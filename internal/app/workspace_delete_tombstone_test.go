@@ -33,6 +33,25 @@ func (s *failingTombstoneWorkspaceStore) SetEnv(data.WorkspaceID, map[string]str
 	return nil
 }
 
+func (s *failingTombstoneWorkspaceStore) SetSandbox(data.WorkspaceID, bool) error {
+	return nil
+}
+
+func (s *failingTombstoneWorkspaceStore) SetNoNetwork(data.WorkspaceID, bool) error {
+	return nil
+}
+func (s *failingTombstoneWorkspaceStore) SetGPUs(data.WorkspaceID, string) error {
+	return nil
+}
+
+func (s *failingTombstoneWorkspaceStore) SetBase(data.WorkspaceID, string) error {
+	return nil
+}
+
+func (s *failingTombstoneWorkspaceStore) SetFavorite(data.WorkspaceID, bool) error {
+	return nil
+}
+
 func (s *failingTombstoneWorkspaceStore) ResolvedDefaultAssistant() string {
 	return data.DefaultAssistant
 }
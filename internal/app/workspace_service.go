@@ -168,6 +168,8 @@ func (s *workspaceService) CreateWorkspace(project *data.Project, name, base str
 			}
 		}
 
+		ws.RecordHistory(data.HistoryCreated, "branch "+branch+" from "+base, time.Now())
+
 		// Save unified workspace
 		if s.store != nil {
 			if err := s.store.Save(ws); err != nil {
@@ -321,6 +323,9 @@ func (s *workspaceService) DeleteWorkspace(project *data.Project, ws *data.Works
 		// dir-less ghost workspace. store.Delete removes the whole metadata dir,
 		// clearing the tombstone on success.
 		s.markDeleteTombstone(ws.ID())
+		head := s.workspaceHeadForTrash(ws)
+		changes := s.workspaceChangesForTrash(ws)
+		history := s.historyForTrash(ws)
 
 		warning, failMsg := s.removeWorktreeAndBranchLocked(project, ws, projectPath, wsID, fail)
 		if failMsg != nil {
@@ -353,11 +358,13 @@ func (s *workspaceService) DeleteWorkspace(project *data.Project, ws *data.Works
 			ws.Root,
 			project.Path,
 		)
+		trashed := s.trashDeletedWorkspace(ws, head, changes, history)
 
 		return messages.WorkspaceDeleted{
 			Project:   project,
 			Workspace: ws,
 			Warning:   warning,
+			Trashed:   trashed,
 		}
 	}
 }
@@ -457,6 +464,9 @@ func (s *workspaceService) Save(workspace *data.Workspace) error {
 	if s == nil || s.store == nil {
 		return nil
 	}
+	if s.readOnly {
+		return errReadOnly
+	}
 	return s.store.Save(workspace)
 }
 
@@ -34,6 +34,23 @@ func (s *failingDeleteStore) Rename(data.WorkspaceID, string) error { return nil
 func (s *failingDeleteStore) SetEnv(data.WorkspaceID, map[string]string) error {
 	return nil
 }
+
+func (s *failingDeleteStore) SetSandbox(data.WorkspaceID, bool) error {
+	return nil
+}
+
+func (s *failingDeleteStore) SetNoNetwork(data.WorkspaceID, bool) error {
+	return nil
+}
+func (s *failingDeleteStore) SetGPUs(data.WorkspaceID, string) error {
+	return nil
+}
+func (s *failingDeleteStore) SetBase(data.WorkspaceID, string) error {
+	return nil
+}
+func (s *failingDeleteStore) SetFavorite(data.WorkspaceID, bool) error {
+	return nil
+}
 func (s *failingDeleteStore) ResolvedDefaultAssistant() string { return data.DefaultAssistant }
 
 // TestDeleteWorkspace_StoreDeleteFailureReportsPartialSuccess proves a
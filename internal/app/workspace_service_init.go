@@ -1,6 +1,7 @@
 package app
 
 import (
+	"context"
 	"sync"
 	"time"
 
@@ -14,6 +15,8 @@ type GitOperations interface {
 	CreateWorkspace(repoPath, workspacePath, branch, base string) error
 	RemoveWorkspace(repoPath, workspacePath string) error
 	DeleteBranch(repoPath, branch string) error
+	HeadCommit(workspacePath string) (string, error)
+	UncommittedChanges(workspacePath string) ([]byte, error)
 	DiscoverWorkspaces(project *data.Project) ([]data.Workspace, error)
 }
 
@@ -31,6 +34,14 @@ func (defaultGitOps) DeleteBranch(repoPath, branch string) error {
 	return git.DeleteBranch(repoPath, branch)
 }
 
+func (defaultGitOps) HeadCommit(workspacePath string) (string, error) {
+	return git.GetHeadCommit(workspacePath)
+}
+
+func (defaultGitOps) UncommittedChanges(workspacePath string) ([]byte, error) {
+	return git.UncommittedChanges(context.Background(), workspacePath)
+}
+
 func (defaultGitOps) DiscoverWorkspaces(project *data.Project) ([]data.Workspace, error) {
 	return git.DiscoverWorkspaces(project)
 }
@@ -42,6 +53,11 @@ type workspaceService struct {
 	workspacesRoot     string
 	gitOps             GitOperations
 	gitPathWaitTimeout time.Duration
+	// trash keeps deleted workspaces' metadata for trashRetention so a delete
+	// can be undone. Wired in app_init; nil in directly-constructed services,
+	// in which case deletes skip the trash.
+	trash          *data.WorkspaceTrash
+	trashRetention time.Duration
 	// deleteInFlight reports whether a workspace is currently mid-delete. It is
 	// wired to the App's guard in app_init; nil when the service is constructed
 	// directly (e.g. in tests) and then treated as "never in flight".
@@ -58,6 +74,10 @@ type workspaceService struct {
 	// by normalized project path) so concurrent create/delete of workspaces in the
 	// same repo do not contend on .git locks (index.lock / packed-refs).
 	repoGitLocks sync.Map
+	// readOnly is set in a second view of state another amux owns
+	// (app_read_only.go); the service then leaves the store and registry
+	// alone.
+	readOnly bool
 }
 
 // lockRepoGit acquires the per-repo git mutation lock and returns the unlock
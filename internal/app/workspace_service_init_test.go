@@ -195,6 +195,25 @@ func (f *fakeAssistantStore) SetEnv(data.WorkspaceID, map[string]string) error {
 	panic("unexpected SetEnv")
 }
 
+func (f *fakeAssistantStore) SetSandbox(data.WorkspaceID, bool) error {
+	panic("unexpected SetSandbox")
+}
+
+func (f *fakeAssistantStore) SetNoNetwork(data.WorkspaceID, bool) error {
+	panic("unexpected SetNoNetwork")
+}
+func (f *fakeAssistantStore) SetGPUs(data.WorkspaceID, string) error {
+	panic("unexpected SetGPUs")
+}
+
+func (f *fakeAssistantStore) SetBase(data.WorkspaceID, string) error {
+	panic("unexpected SetBase")
+}
+
+func (f *fakeAssistantStore) SetFavorite(data.WorkspaceID, bool) error {
+	panic("unexpected SetFavorite")
+}
+
 // TestWorkspaceServiceResolvedDefaultAssistant covers every branch of the
 // nil-safe resolver: a nil receiver and a nil store both fall back to the package
 // default, while a wired store is consulted verbatim.
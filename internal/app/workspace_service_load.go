@@ -107,6 +107,11 @@ func (s *workspaceService) LoadProjects(loadToken projectsLoadToken) tea.Cmd {
 			project.Workspaces = workspaces
 			projects = append(projects, *project)
 		}
+		favorites, err := s.registry.Favorites()
+		if err != nil {
+			logging.Warn("Failed to load favorite projects: %v", err)
+		}
+		orderFavorites(projects, favorites)
 
 		return messages.ProjectsLoaded{Projects: projects, LoadToken: int(loadToken)}
 	}
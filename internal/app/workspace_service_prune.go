@@ -20,7 +20,7 @@ type workspaceRepoMetadataDeleter interface {
 }
 
 func (s *workspaceService) reconcileWorkspaceMetadata(registeredRepos []string) {
-	if s == nil || s.store == nil {
+	if s == nil || s.store == nil || s.readOnly {
 		return
 	}
 	pruner, ok := s.store.(workspaceMetadataPruner)
@@ -53,7 +53,7 @@ func (s *workspaceService) reconcileWorkspaceMetadata(registeredRepos []string)
 // temp directory. A missing arbitrary path may be an offline volume and is
 // retained; a vanished temp directory cannot come back with the same contents.
 func (s *workspaceService) pruneMissingTemporaryProjects(paths []string) []string {
-	if s == nil || s.registry == nil {
+	if s == nil || s.registry == nil || s.readOnly {
 		return paths
 	}
 	kept := make([]string, 0, len(paths))
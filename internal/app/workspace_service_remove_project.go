@@ -29,6 +29,7 @@ func (s *workspaceService) RemoveProject(project *data.Project) tea.Cmd {
 			return messages.Error{Err: err, Context: errorContext(errorServiceWorkspace, "removing project")}
 		}
 		s.releaseProjectPorts(project.Workspaces)
+		s.trashRemovedProject(project)
 		// Discard amux's metadata and sessions while deliberately leaving the
 		// repository and worktrees untouched, as promised by the dialog.
 		s.removeProjectMetadata(project.Path, project.Workspaces...)
@@ -37,6 +37,14 @@ func (f *fakeProjectRegistry) RemoveProject(path string) error {
 	return f.removeErr
 }
 
+func (f *fakeProjectRegistry) Favorites() (map[string]bool, error) {
+	return nil, nil
+}
+
+func (f *fakeProjectRegistry) SetFavorite(path string, favorite bool) error {
+	return nil
+}
+
 // TestRemoveProjectNilProject covers the early-return guard: a nil project must
 // surface messages.Error without ever touching the registry.
 func TestRemoveProjectNilProject(t *testing.T) {
@@ -0,0 +1,243 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	tea "charm.land/bubbletea/v2"
+
+	"github.com/andyrewlee/amux/internal/data"
+	"github.com/andyrewlee/amux/internal/git"
+	"github.com/andyrewlee/amux/internal/logging"
+	"github.com/andyrewlee/amux/internal/messages"
+	"github.com/andyrewlee/amux/internal/timeline"
+)
+
+// deriveTimeline reads a worktree's commits and pull request before it is
+// deleted; tests replace it to avoid running git and gh.
+var deriveTimeline = timeline.Derived
+
+// trashRetentionFromEnv returns how long deleted workspaces stay in the trash,
+// read from AMUX_TRASH_RETENTION_DAYS. Empty or invalid values fall back to
+// data.DefaultTrashRetention; 0 keeps entries until they are restored.
+func trashRetentionFromEnv() time.Duration {
+	const envName = "AMUX_TRASH_RETENTION_DAYS"
+	raw := strings.TrimSpace(os.Getenv(envName))
+	if raw == "" {
+		return data.DefaultTrashRetention
+	}
+	days, err := strconv.Atoi(raw)
+	if err != nil || days < 0 {
+		logging.Warn("Invalid %s=%q; using default %s", envName, raw, data.DefaultTrashRetention)
+		return data.DefaultTrashRetention
+	}
+	return time.Duration(days) * 24 * time.Hour
+}
+
+// workspaceHeadForTrash resolves the worktree's HEAD before it is removed, so
+// the trashed workspace can later be restored at the commit it was deleted at.
+func (s *workspaceService) workspaceHeadForTrash(ws *data.Workspace) string {
+	if s == nil || s.trash == nil || s.gitOps == nil || ws == nil {
+		return ""
+	}
+	head, err := s.gitOps.HeadCommit(ws.Root)
+	if err != nil {
+		logging.Warn("workspace delete could not resolve HEAD for trash workspace_id=%s error=%v", ws.ID(), err)
+		return ""
+	}
+	return strings.TrimSpace(head)
+}
+
+// maxTrashChangesBytes bounds the patch of uncommitted changes kept with a
+// trashed workspace; larger changes, such as stray build output, are not kept.
+const maxTrashChangesBytes = 16 << 20
+
+// workspaceChangesForTrash captures the worktree's uncommitted changes,
+// including untracked files, before it is removed so a restore can bring
+// them back.
+func (s *workspaceService) workspaceChangesForTrash(ws *data.Workspace) []byte {
+	if s == nil || s.trash == nil || s.gitOps == nil || ws == nil {
+		return nil
+	}
+	patch, err := s.gitOps.UncommittedChanges(ws.Root)
+	if err != nil {
+		logging.Warn("workspace delete could not capture changes for trash workspace_id=%s error=%v", ws.ID(), err)
+		return nil
+	}
+	if len(patch) > maxTrashChangesBytes {
+		logging.Warn("workspace delete skipped %d bytes of changes for trash workspace_id=%s", len(patch), ws.ID())
+		return nil
+	}
+	return patch
+}
+
+// historyForTrash returns ws's timeline as it is deleted: the recorded events,
+// the commits and pull request read while its branch still exists, and the
+// deletion itself.
+func (s *workspaceService) historyForTrash(ws *data.Workspace) []data.HistoryEvent {
+	if s == nil || s.trash == nil || ws == nil {
+		return nil
+	}
+	final := data.Workspace{History: timeline.Merge(ws.History, deriveTimeline(ws))}
+	final.RecordHistory(data.HistoryDeleted, "", time.Now())
+	return final.History
+}
+
+// trashDeletedWorkspace records a successfully deleted workspace in the trash,
+// with its final timeline and uncommitted changes, and prunes expired entries. It reports whether the
+// workspace was trashed; failures are only logged because the delete itself
+// already succeeded.
+func (s *workspaceService) trashDeletedWorkspace(ws *data.Workspace, head string, changes []byte, history []data.HistoryEvent) bool {
+	if s == nil || s.trash == nil || ws == nil {
+		return false
+	}
+	trashed := *ws
+	trashed.History = history
+	if err := s.trash.Put(&trashed, head); err != nil {
+		logging.Warn("workspace delete trash failed workspace_id=%s error=%v", ws.ID(), err)
+		return false
+	}
+	if err := s.trash.PutChanges(ws.ID(), changes); err != nil {
+		logging.Warn("workspace delete could not keep changes in trash workspace_id=%s error=%v", ws.ID(), err)
+	}
+	if _, err := s.trash.Prune(s.trashRetention); err != nil {
+		logging.Warn("trash prune failed: %v", err)
+	}
+	return true
+}
+
+// TrashedWorkspaces lists the trash, most recently deleted first, after
+// pruning entries older than the retention window.
+func (s *workspaceService) TrashedWorkspaces() ([]data.TrashedWorkspace, error) {
+	if s == nil || s.trash == nil {
+		return nil, errors.New("trash unavailable")
+	}
+	if _, err := s.trash.Prune(s.trashRetention); err != nil {
+		logging.Warn("trash prune failed: %v", err)
+	}
+	return s.trash.List()
+}
+
+// TrashedWorkspace returns the trash entry for a deleted workspace ID.
+func (s *workspaceService) TrashedWorkspace(id data.WorkspaceID) (*data.TrashedWorkspace, error) {
+	if s == nil || s.trash == nil {
+		return nil, errors.New("trash unavailable")
+	}
+	return s.trash.Get(id)
+}
+
+// finishTrashedWorkspace gives a recreated workspace back name, the name it
+// was trashed with, and reapplies the uncommitted changes kept with its trash
+// entry, then drops the entry. When the changes do not apply the entry is kept, so
+// they stay recoverable until it expires. A workspace that was never trashed
+// is a no-op. renamed reports whether the stored name changed.
+func (s *workspaceService) finishTrashedWorkspace(ws *data.Workspace, name string) (renamed bool, err error) {
+	if s == nil || s.trash == nil || ws == nil {
+		return false, nil
+	}
+	id := ws.ID()
+	if name != "" && name != ws.Name && s.store != nil {
+		if err := s.store.Rename(id, name); err != nil {
+			logging.Warn("trash restore could not rename workspace_id=%s error=%v", id, err)
+		} else {
+			renamed = true
+		}
+	}
+	patch, err := s.trash.Changes(id)
+	if err != nil {
+		return renamed, err
+	}
+	if len(patch) > 0 {
+		if err := git.ApplyUncommittedChanges(context.Background(), ws.Root, patch); err != nil {
+			return renamed, fmt.Errorf("reapplying uncommitted changes, which stay in the trash: %w", err)
+		}
+	}
+	if err := s.trash.Remove(id); err != nil {
+		logging.Warn("trash cleanup failed workspace_id=%s error=%v", id, err)
+	}
+	return renamed, nil
+}
+
+// trashRemovedProject records a removed project in the trash with its
+// workspaces' metadata, which RemoveProject is about to discard. Failures are
+// only logged; the project can still be re-added by path.
+func (s *workspaceService) trashRemovedProject(project *data.Project) {
+	if s == nil || s.trash == nil || project == nil {
+		return
+	}
+	workspaces := project.Workspaces
+	if s.store != nil {
+		stored, err := s.store.ListByRepoIncludingArchived(project.Path)
+		if err != nil {
+			logging.Warn("project remove could not list workspaces for trash path=%s error=%v", project.Path, err)
+		}
+		if len(stored) > 0 {
+			workspaces = make([]data.Workspace, 0, len(stored))
+			for _, ws := range stored {
+				if ws != nil {
+					workspaces = append(workspaces, *ws)
+				}
+			}
+		}
+	}
+	if err := s.trash.PutProject(project.Path, project.Favorite, workspaces); err != nil {
+		logging.Warn("project remove trash failed path=%s error=%v", project.Path, err)
+		return
+	}
+	if _, err := s.trash.Prune(s.trashRetention); err != nil {
+		logging.Warn("trash prune failed: %v", err)
+	}
+}
+
+// TrashedProjects lists the removed projects in the trash, most recently
+// removed first. Expired entries are pruned by TrashedWorkspaces.
+func (s *workspaceService) TrashedProjects() ([]data.TrashedProject, error) {
+	if s == nil || s.trash == nil {
+		return nil, errors.New("trash unavailable")
+	}
+	return s.trash.Projects()
+}
+
+// RestoreProject re-adds a removed project. When it is still in the trash its
+// workspaces' metadata and favorite mark come back too, for the worktrees
+// that are still on disk; otherwise it is added like a new project.
+func (s *workspaceService) RestoreProject(path string) tea.Cmd {
+	return func() tea.Msg {
+		var entry *data.TrashedProject
+		if s != nil && s.trash != nil {
+			var err error
+			if entry, err = s.trash.Project(path); err != nil && !os.IsNotExist(err) {
+				logging.Warn("project restore could not read trash path=%s error=%v", path, err)
+			}
+		}
+		if entry != nil && s.store != nil {
+			for i := range entry.Workspaces {
+				ws := &entry.Workspaces[i]
+				if _, err := os.Stat(ws.Root); err != nil {
+					continue
+				}
+				if err := s.store.Save(ws); err != nil {
+					logging.Warn("project restore could not save workspace %s: %v", ws.Name, err)
+				}
+			}
+		}
+		msg := s.AddProject(path)()
+		if _, failed := msg.(messages.Error); failed || entry == nil {
+			return msg
+		}
+		if entry.Favorite {
+			if err := s.registry.SetFavorite(entry.Path, true); err != nil {
+				logging.Warn("project restore could not restore favorite path=%s error=%v", path, err)
+			}
+		}
+		if err := s.trash.RemoveProject(path); err != nil {
+			logging.Warn("trash cleanup failed path=%s error=%v", path, err)
+		}
+		return msg
+	}
+}
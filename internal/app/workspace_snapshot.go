@@ -12,6 +12,18 @@ func snapshotWorkspaceForSave(ws *data.Workspace) *data.Workspace {
 		snapshot.OpenTabs = make([]data.TabInfo, len(ws.OpenTabs))
 		copy(snapshot.OpenTabs, ws.OpenTabs)
 	}
+	if ws.Launches != nil {
+		snapshot.Launches = make([]data.Launch, len(ws.Launches))
+		copy(snapshot.Launches, ws.Launches)
+	}
+	if ws.History != nil {
+		snapshot.History = make([]data.HistoryEvent, len(ws.History))
+		copy(snapshot.History, ws.History)
+	}
+	if ws.Container != nil {
+		container := *ws.Container
+		snapshot.Container = &container
+	}
 	if ws.Env != nil {
 		snapshot.Env = make(map[string]string, len(ws.Env))
 		for key, value := range ws.Env {
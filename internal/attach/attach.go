@@ -0,0 +1,173 @@
+// Package attach copies images into a worktree so agents can be pointed at
+// them: a screenshot taken on the spot, or an existing file.
+package attach
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+)
+
+// Dir is where attachments are kept, relative to the worktree root. It holds
+// its own .gitignore so attachments never show up as changes.
+const Dir = ".amux/attachments"
+
+var (
+	// ErrNoScreenshotTool is returned when no supported screenshot tool is
+	// installed.
+	ErrNoScreenshotTool = errors.New("no screenshot tool found")
+	// ErrCanceled is returned when the screenshot tool exits without
+	// saving an image, as when the selection is canceled.
+	ErrCanceled = errors.New("screenshot canceled")
+	// ErrNotImage is returned for a file that is not a supported image.
+	ErrNotImage = errors.New("not a png, jpeg, gif, or webp image")
+)
+
+var imageExts = map[string]bool{".png": true, ".jpg": true, ".jpeg": true, ".gif": true, ".webp": true}
+
+// screenshotTool is a command that saves an interactively selected region of
+// the screen to the path appended to args.
+type screenshotTool struct {
+	name string
+	args []string
+}
+
+// screenshotTools are the supported tools per platform, in order of
+// preference.
+var screenshotTools = map[string][]screenshotTool{
+	"darwin": {{name: "screencapture", args: []string{"-i"}}},
+	"linux": {
+		{name: "gnome-screenshot", args: []string{"-a", "-f"}},
+		{name: "spectacle", args: []string{"-b", "-n", "-r", "-o"}},
+		{name: "scrot", args: []string{"-s"}},
+		{name: "import"},
+	},
+}
+
+// lookPath finds a screenshot tool; tests replace it.
+var lookPath = exec.LookPath
+
+// now names attachments; tests replace it.
+var now = time.Now
+
+// Screenshot lets the user select a region of the screen and saves it as a
+// png attachment in root, returning its path relative to root.
+func Screenshot(ctx context.Context, root string) (string, error) {
+	var tool screenshotTool
+	for _, t := range screenshotTools[runtime.GOOS] {
+		if _, err := lookPath(t.name); err == nil {
+			tool = t
+			break
+		}
+	}
+	if tool.name == "" {
+		return "", ErrNoScreenshotTool
+	}
+	dir, err := ensureDir(root)
+	if err != nil {
+		return "", err
+	}
+	name := attachmentName("screenshot.png")
+	path := filepath.Join(dir, name)
+	args := append(append([]string(nil), tool.args...), path)
+	if out, err := exec.CommandContext(ctx, tool.name, args...).CombinedOutput(); err != nil {
+		_ = os.Remove(path)
+		if msg := strings.TrimSpace(string(out)); msg != "" {
+			return "", fmt.Errorf("%s: %w: %s", tool.name, err, msg)
+		}
+		return "", fmt.Errorf("%s: %w", tool.name, err)
+	}
+	if info, err := os.Stat(path); err != nil || info.Size() == 0 {
+		_ = os.Remove(path)
+		return "", ErrCanceled
+	}
+	return filepath.Join(Dir, name), nil
+}
+
+// Import copies the image at src into root's attachments, returning its path
+// relative to root. src may be quoted or shell-escaped, as terminals paste
+// dragged files, and may start with ~/.
+func Import(root, src string) (string, error) {
+	src = CleanPath(src)
+	if !imageExts[strings.ToLower(filepath.Ext(src))] {
+		return "", fmt.Errorf("%s: %w", filepath.Base(src), ErrNotImage)
+	}
+	in, err := os.Open(src)
+	if err != nil {
+		return "", err
+	}
+	defer in.Close()
+	dir, err := ensureDir(root)
+	if err != nil {
+		return "", err
+	}
+	name := attachmentName(filepath.Base(src))
+	out, err := os.OpenFile(filepath.Join(dir, name), os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+	if err != nil {
+		return "", err
+	}
+	_, err = io.Copy(out, in)
+	if closeErr := out.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		_ = os.Remove(filepath.Join(dir, name))
+		return "", err
+	}
+	return filepath.Join(Dir, name), nil
+}
+
+// CleanPath undoes the quoting terminals add to dragged-in file paths and
+// expands a leading ~/.
+func CleanPath(path string) string {
+	path = strings.TrimSpace(path)
+	if len(path) >= 2 && (path[0] == '\'' || path[0] == '"') && path[len(path)-1] == path[0] {
+		path = path[1 : len(path)-1]
+	} else {
+		path = strings.ReplaceAll(path, `\ `, " ")
+	}
+	if rest, ok := strings.CutPrefix(path, "~/"); ok {
+		if home, err := os.UserHomeDir(); err == nil {
+			path = filepath.Join(home, rest)
+		}
+	}
+	return path
+}
+
+// ensureDir creates root's attachments directory and the .gitignore that
+// keeps it out of git.
+func ensureDir(root string) (string, error) {
+	dir := filepath.Join(root, Dir)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", err
+	}
+	ignore := filepath.Join(dir, ".gitignore")
+	if _, err := os.Stat(ignore); errors.Is(err, os.ErrNotExist) {
+		if err := os.WriteFile(ignore, []byte("*\n"), 0o644); err != nil {
+			return "", err
+		}
+	}
+	return dir, nil
+}
+
+// attachmentName prefixes base with the time, so attachments sort in the
+// order they were taken, and replaces characters agents might misread in a
+// path.
+func attachmentName(base string) string {
+	clean := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '.', r == '-', r == '_':
+			return r
+		default:
+			return '-'
+		}
+	}, base)
+	return now().Format("20060102-150405.000") + "-" + clean
+}
@@ -0,0 +1,67 @@
+package attach
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func fixedNow(t *testing.T) {
+	t.Helper()
+	orig := now
+	now = func() time.Time { return time.Date(2026, 3, 4, 5, 6, 7, 0, time.UTC) }
+	t.Cleanup(func() { now = orig })
+}
+
+func TestImportCopiesIntoIgnoredDir(t *testing.T) {
+	fixedNow(t)
+	src := filepath.Join(t.TempDir(), "UI bug.PNG")
+	if err := os.WriteFile(src, []byte("png"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	root := t.TempDir()
+
+	// Terminals paste dragged files shell-escaped.
+	rel, err := Import(root, `  `+filepath.Dir(src)+`/UI\ bug.PNG `)
+	if err != nil {
+		t.Fatalf("Import() error = %v", err)
+	}
+	if want := filepath.Join(Dir, "20260304-050607.000-UI-bug.PNG"); rel != want {
+		t.Fatalf("Import() = %q, want %q", rel, want)
+	}
+	if got, err := os.ReadFile(filepath.Join(root, rel)); err != nil || string(got) != "png" {
+		t.Fatalf("attachment = %q, %v", got, err)
+	}
+	if got, _ := os.ReadFile(filepath.Join(root, Dir, ".gitignore")); string(got) != "*\n" {
+		t.Fatalf(".gitignore = %q", got)
+	}
+
+	// The same name a second time within the clock's resolution is refused
+	// rather than overwritten.
+	if _, err := Import(root, "'"+src+"'"); !errors.Is(err, os.ErrExist) {
+		t.Fatalf("second Import() error = %v, want ErrExist", err)
+	}
+}
+
+func TestImportRejectsNonImages(t *testing.T) {
+	src := filepath.Join(t.TempDir(), "notes.txt")
+	if err := os.WriteFile(src, []byte("x"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Import(t.TempDir(), src); !errors.Is(err, ErrNotImage) {
+		t.Fatalf("Import() error = %v, want ErrNotImage", err)
+	}
+}
+
+func TestScreenshotWithoutTool(t *testing.T) {
+	orig := lookPath
+	lookPath = func(string) (string, error) { return "", exec.ErrNotFound }
+	t.Cleanup(func() { lookPath = orig })
+	if _, err := Screenshot(context.Background(), t.TempDir()); !errors.Is(err, ErrNoScreenshotTool) {
+		t.Fatalf("Screenshot() error = %v, want ErrNoScreenshotTool", err)
+	}
+}
@@ -0,0 +1,143 @@
+// Package audit keeps an append-only record of input that amux types into
+// sessions on someone else's behalf (share viewers, automation), so there is
+// a trail to follow when an agent does something unexpected.
+//
+// Entries record who sent input where, when, and how much, with a digest of
+// the bytes. The input itself is never written: like pty.SendString's debug
+// logging, it can carry pasted secrets and prompt text.
+package audit
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// FileName is the audit log's name inside the amux log directory. It does
+// not match the daily log pattern, so log retention never prunes it.
+const FileName = "audit.jsonl"
+
+// Sources of injected input.
+const (
+	SourceShare  = "share"
+	SourceEditor = "editor"
+	SourcePark   = "park"
+	// SourceAmux is text amux composed itself, such as a workspace's
+	// scratchpad notes or a generated prompt, pasted into an agent.
+	SourceAmux = "amux"
+	// SourceHistory is a prompt recalled from input history and pasted
+	// back into an agent.
+	SourceHistory = "history"
+	// SourceAsk is a shell command from an ask-amux answer typed into a
+	// terminal.
+	SourceAsk = "ask"
+)
+
+// Entry is one injected input.
+type Entry struct {
+	Time   time.Time `json:"time"`
+	Source string    `json:"source"`
+	Actor  string    `json:"actor,omitempty"`
+	Target string    `json:"target"`
+	Bytes  int       `json:"bytes"`
+	Digest string    `json:"digest"`
+}
+
+// Log appends entries to an audit file. A nil *Log records nothing.
+type Log struct {
+	path string
+	mu   sync.Mutex
+}
+
+// Path returns the audit log path for an amux log directory.
+func Path(logDir string) string {
+	return filepath.Join(logDir, FileName)
+}
+
+// Open returns a log that appends to path, created on first write.
+func Open(path string) *Log {
+	return &Log{path: path}
+}
+
+// Record appends an entry for input sent to target. Time and Digest are
+// filled in here.
+func (l *Log) Record(source, actor, target, input string) error {
+	if l == nil {
+		return nil
+	}
+	sum := sha256.Sum256([]byte(input))
+	line, err := json.Marshal(Entry{
+		Time:   time.Now().UTC(),
+		Source: source,
+		Actor:  actor,
+		Target: target,
+		Bytes:  len(input),
+		Digest: "sha256:" + hex.EncodeToString(sum[:8]),
+	})
+	if err != nil {
+		return err
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if err := os.MkdirAll(filepath.Dir(l.path), 0o700); err != nil {
+		return fmt.Errorf("audit: %w", err)
+	}
+	file, err := os.OpenFile(l.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("audit: %w", err)
+	}
+	_, writeErr := file.Write(append(line, '\n'))
+	return errors.Join(writeErr, file.Close())
+}
+
+// Read returns the entries in the audit file at path, oldest first. A
+// missing file has no entries; malformed lines are skipped.
+func Read(path string) ([]Entry, error) {
+	file, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	var entries []Entry
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var entry Entry
+		if json.Unmarshal(scanner.Bytes(), &entry) == nil {
+			entries = append(entries, entry)
+		}
+	}
+	return entries, scanner.Err()
+}
+
+// Write prints entries one per line in local time.
+func Write(w io.Writer, entries []Entry) error {
+	for _, e := range entries {
+		from := e.Source
+		if e.Actor != "" {
+			from += " " + e.Actor
+		}
+		line := strings.Join([]string{
+			e.Time.Local().Format("2006-01-02 15:04:05"),
+			from,
+			"-> " + e.Target,
+			fmt.Sprintf("%d bytes", e.Bytes),
+			e.Digest,
+		}, "  ")
+		if _, err := fmt.Fprintln(w, line); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,88 @@
+package audit
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRecordAppendsWithoutInput(t *testing.T) {
+	path := Path(filepath.Join(t.TempDir(), "logs"))
+	log := Open(path)
+	if err := log.Record(SourceShare, "10.0.0.2:5000", "amux-ws-tab", "echo hunter2\r"); err != nil {
+		t.Fatalf("Record() error = %v", err)
+	}
+	if err := log.Record(SourceShare, "", "amux-ws-tab", "ls\r"); err != nil {
+		t.Fatalf("Record() error = %v", err)
+	}
+
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read audit log: %v", err)
+	}
+	if strings.Contains(string(raw), "hunter2") {
+		t.Fatal("audit log must not contain the input itself")
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("stat: %v", err)
+	}
+	if perm := info.Mode().Perm(); perm != 0o600 {
+		t.Fatalf("audit log mode = %o, want 600", perm)
+	}
+
+	entries, err := Read(path)
+	if err != nil {
+		t.Fatalf("Read() error = %v", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("Read() returned %d entries, want 2", len(entries))
+	}
+	first := entries[0]
+	if first.Source != SourceShare || first.Actor != "10.0.0.2:5000" || first.Target != "amux-ws-tab" || first.Bytes != 13 {
+		t.Fatalf("first entry = %#v", first)
+	}
+	if !strings.HasPrefix(first.Digest, "sha256:") || first.Digest == entries[1].Digest {
+		t.Fatalf("digests = %q, %q; want distinct sha256 digests", first.Digest, entries[1].Digest)
+	}
+}
+
+func TestReadMissingAndMalformed(t *testing.T) {
+	path := filepath.Join(t.TempDir(), FileName)
+	if entries, err := Read(path); err != nil || entries != nil {
+		t.Fatalf("Read(missing) = %v, %v; want no entries", entries, err)
+	}
+	if err := os.WriteFile(path, []byte("not json\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := Open(path).Record(SourceShare, "", "s", "x"); err != nil {
+		t.Fatal(err)
+	}
+	entries, err := Read(path)
+	if err != nil || len(entries) != 1 {
+		t.Fatalf("Read() = %v, %v; want the one valid entry", entries, err)
+	}
+}
+
+func TestNilLogRecordsNothing(t *testing.T) {
+	var log *Log
+	if err := log.Record(SourceShare, "", "s", "x"); err != nil {
+		t.Fatalf("nil Record() error = %v", err)
+	}
+}
+
+func TestWrite(t *testing.T) {
+	var buf bytes.Buffer
+	entries := []Entry{{Source: SourceShare, Actor: "1.2.3.4:9", Target: "sess", Bytes: 3, Digest: "sha256:ab"}}
+	if err := Write(&buf, entries); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+	for _, want := range []string{"share 1.2.3.4:9", "-> sess", "3 bytes", "sha256:ab"} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("Write() = %q, missing %q", out, want)
+		}
+	}
+}
@@ -0,0 +1,176 @@
+// Package benchmark records experiments that give several agents the same
+// task, each in a worktree of its own, and compares how they did: whether
+// the task's acceptance command passed in the agent's worktree, how long the
+// agent worked, and how large a change it made. A report renders as a table
+// and exports as JSON.
+package benchmark
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Task is what every agent in a benchmark is given.
+type Task struct {
+	Prompt string `json:"prompt"`
+	// Accept is the shell command that succeeds once the task is done.
+	Accept string `json:"accept"`
+}
+
+// Status is how far a run has got.
+type Status string
+
+const (
+	Setup    Status = "setup"
+	Running  Status = "running"
+	Checking Status = "checking"
+	Passed   Status = "passed"
+	Failed   Status = "failed"
+	// Errored means the run ended without a verdict: its workspace or agent
+	// did not start, or the acceptance command could not be run.
+	Errored Status = "error"
+)
+
+// Finished reports whether the run has a final result.
+func (s Status) Finished() bool {
+	return s == Passed || s == Failed || s == Errored
+}
+
+// Run is one agent's attempt at the task.
+type Run struct {
+	Agent     string `json:"agent"`
+	Workspace string `json:"workspace"`
+	Status    Status `json:"status"`
+	Error     string `json:"error,omitempty"`
+	// ExitCode is the acceptance command's exit status.
+	ExitCode int `json:"exit_code"`
+	// Started is when the agent was given the task and Finished when it
+	// stopped working on it.
+	Started  time.Time `json:"started,omitzero"`
+	Finished time.Time `json:"finished,omitzero"`
+	Files    int       `json:"files_changed"`
+	Added    int       `json:"lines_added"`
+	Deleted  int       `json:"lines_deleted"`
+}
+
+// Duration is how long the agent worked, or 0 before it finished.
+func (r Run) Duration() time.Duration {
+	if r.Started.IsZero() || r.Finished.IsZero() {
+		return 0
+	}
+	return r.Finished.Sub(r.Started)
+}
+
+// MarshalJSON adds the run's duration in seconds.
+func (r Run) MarshalJSON() ([]byte, error) {
+	type plain Run
+	return json.Marshal(struct {
+		plain
+		DurationSeconds float64 `json:"duration_seconds"`
+	}{plain(r), r.Duration().Round(time.Second).Seconds()})
+}
+
+// Report is a benchmark: the task and every agent's run at it.
+type Report struct {
+	Task
+	Project string    `json:"project"`
+	Created time.Time `json:"created"`
+	Runs    []Run     `json:"runs"`
+}
+
+// Done reports whether every run has a final result.
+func (r *Report) Done() bool {
+	for _, run := range r.Runs {
+		if !run.Status.Finished() {
+			return false
+		}
+	}
+	return true
+}
+
+// Table renders the report as aligned rows, the header first: agent,
+// result, duration, and diff size. An agent run more than once is numbered.
+func (r *Report) Table() []string {
+	count, seen := map[string]int{}, map[string]int{}
+	for _, run := range r.Runs {
+		count[run.Agent]++
+	}
+	rows := [][]string{{"AGENT", "RESULT", "TIME", "DIFF"}}
+	for _, run := range r.Runs {
+		agent := run.Agent
+		if seen[run.Agent]++; count[run.Agent] > 1 {
+			agent = fmt.Sprintf("%s #%d", run.Agent, seen[run.Agent])
+		}
+		rows = append(rows, []string{agent, run.result(), duration(run), diffSize(run)})
+	}
+	widths := make([]int, len(rows[0]))
+	for _, row := range rows {
+		for i, cell := range row {
+			widths[i] = max(widths[i], len([]rune(cell)))
+		}
+	}
+	lines := make([]string, 0, len(rows))
+	for _, row := range rows {
+		var b strings.Builder
+		for i, cell := range row {
+			b.WriteString(cell)
+			if i < len(row)-1 {
+				b.WriteString(strings.Repeat(" ", widths[i]-len([]rune(cell))+2))
+			}
+		}
+		lines = append(lines, strings.TrimRight(b.String(), " "))
+	}
+	return lines
+}
+
+func (r Run) result() string {
+	switch r.Status {
+	case Passed:
+		return "pass"
+	case Failed:
+		return fmt.Sprintf("fail (exit %d)", r.ExitCode)
+	case Errored:
+		return "error: " + r.Error
+	}
+	return string(r.Status)
+}
+
+func duration(r Run) string {
+	if d := r.Duration(); d > 0 {
+		return d.Round(time.Second).String()
+	}
+	return "-"
+}
+
+func diffSize(r Run) string {
+	if !r.Status.Finished() || r.Status == Errored {
+		return "-"
+	}
+	files := "files"
+	if r.Files == 1 {
+		files = "file"
+	}
+	return fmt.Sprintf("%d %s +%d -%d", r.Files, files, r.Added, r.Deleted)
+}
+
+// Save writes the report as JSON to dir, named after its project and when
+// it was created, and returns the file's path.
+func Save(dir string, r *Report) (string, error) {
+	raw, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", err
+	}
+	name := fmt.Sprintf("%s-%s.json", r.Project, r.Created.Format("20060102-150405"))
+	path := filepath.Join(dir, strings.ReplaceAll(name, string(filepath.Separator), "-"))
+	if err := os.WriteFile(path, append(raw, '\n'), 0o644); err != nil {
+		return "", err
+	}
+	return path, nil
+}
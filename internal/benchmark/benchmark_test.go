@@ -0,0 +1,85 @@
+package benchmark
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func testReport() *Report {
+	started := time.Date(2026, 3, 4, 10, 0, 0, 0, time.UTC)
+	return &Report{
+		Task:    Task{Prompt: "fix the flaky test", Accept: "go test ./..."},
+		Project: "api",
+		Created: started,
+		Runs: []Run{
+			{Agent: "claude", Workspace: "bench-fix-claude", Status: Passed, Started: started, Finished: started.Add(252 * time.Second), Files: 1, Added: 12, Deleted: 3},
+			{Agent: "codex", Workspace: "bench-fix-codex", Status: Failed, ExitCode: 1, Started: started, Finished: started.Add(90 * time.Second), Files: 4, Added: 40},
+			{Agent: "gemini", Workspace: "bench-fix-gemini", Status: Running, Started: started},
+		},
+	}
+}
+
+func TestTable(t *testing.T) {
+	r := testReport()
+	r.Runs = append(r.Runs, Run{Agent: "codex", Workspace: "bench-fix-codex-2", Status: Errored, Error: "setup failed"})
+	want := []string{
+		"AGENT     RESULT               TIME   DIFF",
+		"claude    pass                 4m12s  1 file +12 -3",
+		"codex #1  fail (exit 1)        1m30s  4 files +40 -0",
+		"gemini    running              -      -",
+		"codex #2  error: setup failed  -      -",
+	}
+	got := r.Table()
+	if len(got) != len(want) {
+		t.Fatalf("Table() = %q", got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("row %d = %q, want %q", i, got[i], want[i])
+		}
+	}
+}
+
+func TestDone(t *testing.T) {
+	r := testReport()
+	if r.Done() {
+		t.Fatal("a report with a running agent is not done")
+	}
+	r.Runs[2].Status, r.Runs[2].Error = Errored, "agent did not start"
+	if !r.Done() {
+		t.Fatal("a report whose runs all have results is done")
+	}
+}
+
+func TestSave(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "benchmarks")
+	path, err := Save(dir, testReport())
+	if err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	if filepath.Base(path) != "api-20260304-100000.json" {
+		t.Fatalf("Save() path = %q", path)
+	}
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got struct {
+		Prompt string `json:"prompt"`
+		Runs   []struct {
+			Agent           string  `json:"agent"`
+			Status          Status  `json:"status"`
+			DurationSeconds float64 `json:"duration_seconds"`
+			LinesAdded      int     `json:"lines_added"`
+		} `json:"runs"`
+	}
+	if err := json.Unmarshal(raw, &got); err != nil {
+		t.Fatalf("saved report is not JSON: %v\n%s", err, raw)
+	}
+	if got.Prompt != "fix the flaky test" || len(got.Runs) != 3 || got.Runs[0].DurationSeconds != 252 || got.Runs[0].LinesAdded != 12 || got.Runs[2].Status != Running {
+		t.Fatalf("saved report = %+v", got)
+	}
+}
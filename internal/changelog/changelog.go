@@ -0,0 +1,228 @@
+// Package changelog drafts a changelog entry for a repository's unreleased
+// work: the conventional commits since its last tag, grouped by type, and a
+// summary of each branch merged since, such as the branches of agents whose
+// work was landed together.
+//
+// Only the types readers of a changelog care about get a section; docs,
+// refactor, test, and other maintenance commits are left out. Commits that
+// don't follow the convention are listed as other changes, except those a
+// merged branch brought in, which its summary covers.
+package changelog
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/andyrewlee/amux/internal/commitmsg"
+	"github.com/andyrewlee/amux/internal/git"
+)
+
+// maxBranchSubjects bounds the commit subjects a merged branch is summarized
+// with when its merge commit says nothing about it.
+const maxBranchSubjects = 3
+
+// runGit runs git; tests replace it.
+var runGit = git.RunGitCtx
+
+// sections are the commit types given a section, in order.
+var sections = []struct {
+	typ   string
+	title string
+}{
+	{"feat", "Features"},
+	{"fix", "Fixes"},
+	{"perf", "Performance"},
+	{"revert", "Reverts"},
+}
+
+// Commit is a conventional commit included in an entry.
+type Commit struct {
+	Hash   string
+	Header commitmsg.Header
+}
+
+// Branch is a branch merged since the last tag.
+type Branch struct {
+	Name    string
+	Hash    string
+	Summary string
+}
+
+// Entry is the unreleased work of a repository.
+type Entry struct {
+	// Since is the tag the entry starts after, or "" for the whole history.
+	Since    string
+	Commits  []Commit
+	Branches []Branch
+	// Other are the subjects of direct commits that aren't conventional.
+	Other []string
+}
+
+// Empty reports whether the entry has nothing to list.
+func (e Entry) Empty() bool {
+	return len(e.Commits) == 0 && len(e.Branches) == 0 && len(e.Other) == 0
+}
+
+const (
+	fieldSep  = "\x1f"
+	recordSep = "\x1e"
+)
+
+// Collect reads the commits on HEAD since the last tag in the repository at
+// root.
+func Collect(ctx context.Context, root string) (Entry, error) {
+	var e Entry
+	rev := "HEAD"
+	// describe fails when there is no tag; the entry then covers everything.
+	if tag, err := runGit(ctx, root, "describe", "--tags", "--abbrev=0", "HEAD"); err == nil && tag != "" {
+		e.Since = tag
+		rev = tag + "..HEAD"
+	}
+	out, err := runGit(ctx, root, "log", "--first-parent", "--format=%h"+fieldSep+"%p"+fieldSep+"%s"+fieldSep+"%b"+recordSep, rev, "--")
+	if err != nil {
+		return Entry{}, err
+	}
+	for _, record := range strings.Split(out, recordSep) {
+		fields := strings.Split(strings.TrimLeft(record, "\n"), fieldSep)
+		if len(fields) != 4 {
+			continue
+		}
+		hash, parents, subject, body := fields[0], strings.Fields(fields[1]), fields[2], fields[3]
+		if len(parents) > 1 {
+			branch, commits, err := collectMerge(ctx, root, hash, parents, subject, body)
+			if err != nil {
+				return Entry{}, err
+			}
+			e.Branches = append(e.Branches, branch)
+			e.Commits = append(e.Commits, commits...)
+			continue
+		}
+		if h, err := commitmsg.ParseHeader(subject); err == nil {
+			e.Commits = append(e.Commits, Commit{Hash: hash, Header: h})
+		} else {
+			e.Other = append(e.Other, subject)
+		}
+	}
+	return e, nil
+}
+
+// collectMerge summarizes the branch a merge commit brought in and returns
+// its conventional commits.
+func collectMerge(ctx context.Context, root, hash string, parents []string, subject, body string) (Branch, []Commit, error) {
+	b := Branch{Name: mergedBranch(subject), Hash: hash}
+	out, err := runGit(ctx, root, "log", "--no-merges", "--format=%h"+fieldSep+"%s"+recordSep, parents[0]+".."+parents[1], "--")
+	if err != nil {
+		return Branch{}, nil, err
+	}
+	var commits []Commit
+	var subjects []string
+	for _, record := range strings.Split(out, recordSep) {
+		fields := strings.Split(strings.TrimLeft(record, "\n"), fieldSep)
+		if len(fields) != 2 {
+			continue
+		}
+		subjects = append(subjects, fields[1])
+		if h, err := commitmsg.ParseHeader(fields[1]); err == nil {
+			commits = append(commits, Commit{Hash: fields[0], Header: h})
+		}
+	}
+	// A pull request merge carries the request's title in its body.
+	if line, _, _ := strings.Cut(strings.TrimSpace(body), "\n"); line != "" {
+		b.Summary = line
+	} else {
+		b.Summary = summarize(subjects)
+	}
+	return b, commits, nil
+}
+
+var mergeSubjects = []*regexp.Regexp{
+	regexp.MustCompile(`^Merge branch '([^']+)'`),
+	regexp.MustCompile(`^Merge pull request #\d+ from (\S+)`),
+	regexp.MustCompile(`^Merge remote-tracking branch '([^']+)'`),
+}
+
+// mergedBranch returns the branch a merge commit's subject names, or the
+// subject itself when it names none.
+func mergedBranch(subject string) string {
+	for _, re := range mergeSubjects {
+		if m := re.FindStringSubmatch(subject); m != nil {
+			return m[1]
+		}
+	}
+	return subject
+}
+
+// summarize lists the first subjects of a branch's commits, newest first as
+// git log gives them, and how many more there are.
+func summarize(subjects []string) string {
+	if len(subjects) == 0 {
+		return "no commits"
+	}
+	shown := subjects
+	if len(shown) > maxBranchSubjects {
+		shown = shown[:maxBranchSubjects]
+	}
+	s := strings.Join(shown, "; ")
+	if more := len(subjects) - len(shown); more > 0 {
+		s += fmt.Sprintf("; and %d more", more)
+	}
+	return s
+}
+
+// Markdown renders e as an "Unreleased" changelog section.
+func (e Entry) Markdown() string {
+	var b strings.Builder
+	b.WriteString("## Unreleased\n")
+	if e.Since != "" {
+		fmt.Fprintf(&b, "\nChanges since %s.\n", e.Since)
+	}
+	if e.Empty() {
+		b.WriteString("\nNo changes.\n")
+		return b.String()
+	}
+	var breaking []Commit
+	for _, c := range e.Commits {
+		if c.Header.Breaking {
+			breaking = append(breaking, c)
+		}
+	}
+	writeCommits(&b, "Breaking changes", breaking)
+	for _, s := range sections {
+		var commits []Commit
+		for _, c := range e.Commits {
+			if c.Header.Type == s.typ && !c.Header.Breaking {
+				commits = append(commits, c)
+			}
+		}
+		writeCommits(&b, s.title, commits)
+	}
+	if len(e.Branches) > 0 {
+		b.WriteString("\n### Merged branches\n\n")
+		for _, br := range e.Branches {
+			fmt.Fprintf(&b, "- `%s`: %s (%s)\n", br.Name, br.Summary, br.Hash)
+		}
+	}
+	if len(e.Other) > 0 {
+		b.WriteString("\n### Other changes\n\n")
+		for _, s := range e.Other {
+			fmt.Fprintf(&b, "- %s\n", s)
+		}
+	}
+	return b.String()
+}
+
+func writeCommits(b *strings.Builder, title string, commits []Commit) {
+	if len(commits) == 0 {
+		return
+	}
+	fmt.Fprintf(b, "\n### %s\n\n", title)
+	for _, c := range commits {
+		b.WriteString("- ")
+		if c.Header.Scope != "" {
+			fmt.Fprintf(b, "**%s:** ", c.Header.Scope)
+		}
+		fmt.Fprintf(b, "%s (%s)\n", c.Header.Subject, c.Hash)
+	}
+}
@@ -0,0 +1,101 @@
+package changelog
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/andyrewlee/amux/internal/testutil"
+)
+
+func commit(t *testing.T, repo, file, subject string) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(repo, file), []byte(subject+"\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	testutil.RunGit(t, repo, "add", file)
+	testutil.RunGit(t, repo, "commit", "-m", subject)
+}
+
+func TestCollect(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	repo := testutil.InitRepo(t)
+	commit(t, repo, "old.txt", "feat: released already")
+	testutil.RunGit(t, repo, "tag", "v1.0.0")
+	commit(t, repo, "ui.txt", "feat(ui): add dark mode")
+	commit(t, repo, "tidy.txt", "tidy up")
+	testutil.RunGit(t, repo, "checkout", "-b", "agent-a")
+	commit(t, repo, "config.txt", "feat!: read the new config format")
+	commit(t, repo, "wip.txt", "wip")
+	testutil.RunGit(t, repo, "checkout", "main")
+	commit(t, repo, "crash.txt", "fix(api): stop the crash on empty input")
+	testutil.RunGit(t, repo, "merge", "--no-ff", "--no-edit", "agent-a")
+	commit(t, repo, "docs.txt", "docs: describe dark mode")
+
+	e, err := Collect(context.Background(), repo)
+	if err != nil {
+		t.Fatal(err)
+	}
+	md := e.Markdown()
+	for _, want := range []string{
+		"## Unreleased\n\nChanges since v1.0.0.\n",
+		"### Breaking changes\n\n- read the new config format (",
+		"### Features\n\n- **ui:** add dark mode (",
+		"### Fixes\n\n- **api:** stop the crash on empty input (",
+		"### Merged branches\n\n- `agent-a`: wip; feat!: read the new config format (",
+		"### Other changes\n\n- tidy up\n",
+	} {
+		if !strings.Contains(md, want) {
+			t.Errorf("entry lacks %q:\n%s", want, md)
+		}
+	}
+	for _, unwanted := range []string{"released already", "describe dark mode", "- wip"} {
+		if strings.Contains(md, unwanted) {
+			t.Errorf("entry has %q:\n%s", unwanted, md)
+		}
+	}
+}
+
+func TestInsert(t *testing.T) {
+	entry := "## Unreleased\n\n### Fixes\n\n- new (abc)\n"
+	for _, tc := range []struct{ name, changelog, want string }{
+		{"empty", "", "# Changelog\n\n" + entry},
+		{"before the first release", "# Changelog\n\nAll changes.\n\n## 1.0.0\n\n- first\n",
+			"# Changelog\n\nAll changes.\n\n" + entry + "\n## 1.0.0\n\n- first\n"},
+		{"replacing unreleased", "# Changelog\n\n## [Unreleased]\n\n- stale\n\n## 1.0.0\n\n- first\n",
+			"# Changelog\n\n" + entry + "\n## 1.0.0\n\n- first\n"},
+		{"no releases", "# Changelog\n", "# Changelog\n\n" + entry},
+	} {
+		if got := Insert(tc.changelog, entry); got != tc.want {
+			t.Errorf("%s: Insert =\n%q\nwant\n%q", tc.name, got, tc.want)
+		}
+	}
+
+	root := t.TempDir()
+	if err := Write(root, entry); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := os.ReadFile(filepath.Join(root, Filename)); string(got) != "# Changelog\n\n"+entry {
+		t.Fatalf("CHANGELOG.md = %q", got)
+	}
+}
+
+func TestNotes(t *testing.T) {
+	notes := ToNotes("My notes\n", "## Unreleased\n\n- one\n")
+	if !strings.HasPrefix(notes, "My notes\n\n"+NotesStart+"\n## Unreleased") {
+		t.Fatalf("notes = %q", notes)
+	}
+	notes = ToNotes(notes+"\nMore notes\n", "## Unreleased\n\n- two\n")
+	entry, ok := FromNotes(notes)
+	if !ok || entry != "## Unreleased\n\n- two\n" || !strings.HasSuffix(notes, "\nMore notes\n") || strings.Count(notes, NotesStart) != 1 {
+		t.Fatalf("entry = %q, notes = %q", entry, notes)
+	}
+	if _, ok := FromNotes("no entry here"); ok {
+		t.Fatal("expected no entry without the markers")
+	}
+}
@@ -0,0 +1,109 @@
+package changelog
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/andyrewlee/amux/internal/fsatomic"
+)
+
+// Filename is the changelog an entry is written to, at the repository root.
+const Filename = "CHANGELOG.md"
+
+// The markers around an entry drafted into a workspace's notes, so it can be
+// found again once edited.
+const (
+	NotesStart = "<!-- changelog -->"
+	NotesEnd   = "<!-- /changelog -->"
+)
+
+// ToNotes puts entry into notes between NotesStart and NotesEnd, replacing an
+// entry drafted there before, or after the notes when there is none.
+func ToNotes(notes, entry string) string {
+	block := NotesStart + "\n" + strings.TrimRight(entry, "\n") + "\n" + NotesEnd
+	start, end, ok := notesBlock(notes)
+	if ok {
+		return notes[:start] + block + notes[end:]
+	}
+	if notes = strings.TrimRight(notes, "\n"); notes == "" {
+		return block + "\n"
+	}
+	return notes + "\n\n" + block + "\n"
+}
+
+// FromNotes returns the entry drafted into notes.
+func FromNotes(notes string) (string, bool) {
+	start, end, ok := notesBlock(notes)
+	if !ok {
+		return "", false
+	}
+	entry := notes[start+len(NotesStart) : end-len(NotesEnd)]
+	return strings.TrimSpace(entry) + "\n", true
+}
+
+// notesBlock returns where the drafted entry, markers included, starts and
+// ends in notes.
+func notesBlock(notes string) (int, int, bool) {
+	start := strings.Index(notes, NotesStart)
+	if start < 0 {
+		return 0, 0, false
+	}
+	end := strings.Index(notes[start:], NotesEnd)
+	if end < 0 {
+		return 0, 0, false
+	}
+	return start, start + end + len(NotesEnd), true
+}
+
+// Insert adds entry to the changelog text: in place of its "Unreleased"
+// section when it has one, otherwise before its first release. An empty
+// changelog gets a "# Changelog" title.
+func Insert(changelog, entry string) string {
+	entry = strings.TrimRight(entry, "\n") + "\n"
+	if strings.TrimSpace(changelog) == "" {
+		return "# Changelog\n\n" + entry
+	}
+	lines := strings.SplitAfter(changelog, "\n")
+	at, end := -1, len(lines)
+	for i, line := range lines {
+		if !strings.HasPrefix(line, "## ") {
+			continue
+		}
+		if at >= 0 {
+			end = i
+			break
+		}
+		at = i
+		if !isUnreleased(line) {
+			// Not ours to replace: insert before it.
+			end = i
+			break
+		}
+	}
+	if at < 0 {
+		return strings.TrimRight(changelog, "\n") + "\n\n" + entry
+	}
+	head := strings.Join(lines[:at], "")
+	tail := strings.Join(lines[end:], "")
+	if tail != "" {
+		entry += "\n"
+	}
+	return head + entry + tail
+}
+
+func isUnreleased(heading string) bool {
+	h := strings.ToLower(strings.TrimSpace(strings.TrimPrefix(heading, "## ")))
+	return strings.HasPrefix(strings.Trim(h, "[]"), "unreleased")
+}
+
+// Write inserts entry into the changelog at root, creating it when missing.
+func Write(root, entry string) error {
+	path := filepath.Join(root, Filename)
+	existing, err := os.ReadFile(path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return fsatomic.WriteFile(path, []byte(Insert(string(existing), entry)), 0o644)
+}
@@ -0,0 +1,197 @@
+// Package codeblock finds code blocks in terminal output: markdown fenced
+// blocks, and unified diffs an agent printed without fences, so they can be
+// copied, saved, or applied to a worktree.
+package codeblock
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Block is one code block found in terminal output.
+type Block struct {
+	// Lang is the first word of a fenced block's info string, or "diff" for
+	// an unfenced diff.
+	Lang string
+	// Text is the block's contents without fences or the fence's
+	// indentation, ending in a newline.
+	Text string
+	// Line is the 1-based line of the output where the contents start.
+	Line int
+}
+
+// Lines returns the number of lines in the block.
+func (b Block) Lines() int {
+	return strings.Count(b.Text, "\n")
+}
+
+// Title returns the block's first non-blank line.
+func (b Block) Title() string {
+	for _, line := range strings.Split(b.Text, "\n") {
+		if strings.TrimSpace(line) != "" {
+			return strings.TrimSpace(line)
+		}
+	}
+	return ""
+}
+
+// IsPatch reports whether the block is a unified diff that git apply could
+// take: it has file headers and at least one hunk.
+func (b Block) IsPatch() bool {
+	var header, hunk bool
+	for _, line := range strings.Split(b.Text, "\n") {
+		switch {
+		case strings.HasPrefix(line, "+++ "):
+			header = true
+		case strings.HasPrefix(line, "@@ ") && header:
+			hunk = true
+		}
+	}
+	return hunk
+}
+
+// Extract returns the code blocks in text, oldest first. A fence left open
+// at the end of text is still being printed and is skipped, and a block
+// printed more than once is kept only where it last appears.
+func Extract(text string) []Block {
+	lines := strings.Split(text, "\n")
+	var blocks []Block
+	for i := 0; i < len(lines); i++ {
+		if open, ok := parseFence(lines[i]); ok {
+			end, block, ok := fencedBlock(lines, i, open)
+			if !ok {
+				continue
+			}
+			blocks = append(blocks, block)
+			i = end
+			continue
+		}
+		if end, block, ok := diffBlock(lines, i); ok {
+			blocks = append(blocks, block)
+			i = end
+		}
+	}
+	return dedupe(blocks)
+}
+
+// fence is an opening or closing code fence.
+type fence struct {
+	indent int
+	char   byte
+	length int
+	info   string
+}
+
+// parseFence parses line as a code fence: up to any indentation, three or
+// more backticks or tildes, then an info string.
+func parseFence(line string) (fence, bool) {
+	trimmed := strings.TrimLeftFunc(line, unicode.IsSpace)
+	if len(trimmed) < 3 || (trimmed[0] != '`' && trimmed[0] != '~') {
+		return fence{}, false
+	}
+	f := fence{indent: len(line) - len(trimmed), char: trimmed[0]}
+	for f.length < len(trimmed) && trimmed[f.length] == f.char {
+		f.length++
+	}
+	if f.length < 3 {
+		return fence{}, false
+	}
+	f.info = strings.TrimSpace(trimmed[f.length:])
+	if f.char == '`' && strings.Contains(f.info, "`") {
+		return fence{}, false
+	}
+	return f, true
+}
+
+// fencedBlock reads the block opened at lines[start], returning the index
+// of its closing fence.
+func fencedBlock(lines []string, start int, open fence) (int, Block, bool) {
+	var body strings.Builder
+	for i := start + 1; i < len(lines); i++ {
+		if f, ok := parseFence(lines[i]); ok && f.char == open.char && f.length >= open.length && f.info == "" {
+			lang, _, _ := strings.Cut(open.info, " ")
+			return i, Block{Lang: lang, Text: body.String(), Line: start + 2}, true
+		}
+		body.WriteString(dedent(lines[i], open.indent))
+		body.WriteByte('\n')
+	}
+	return 0, Block{}, false
+}
+
+// dedent removes up to n columns of leading whitespace from line.
+func dedent(line string, n int) string {
+	i := 0
+	for i < n && i < len(line) && (line[i] == ' ' || line[i] == '\t') {
+		i++
+	}
+	return line[i:]
+}
+
+// diffBlock reads an unfenced unified diff starting at lines[start], which
+// must begin its first file header. The diff runs while lines look like diff
+// lines; a blank line counts as blank context, since terminals drop
+// trailing spaces.
+func diffBlock(lines []string, start int) (int, Block, bool) {
+	first := lines[start]
+	indent := len(first) - len(strings.TrimLeft(first, " "))
+	first = first[indent:]
+	switch {
+	case strings.HasPrefix(first, "diff --git "):
+	case strings.HasPrefix(first, "--- ") && start+1 < len(lines) && strings.HasPrefix(dedent(lines[start+1], indent), "+++ "):
+	default:
+		return 0, Block{}, false
+	}
+	end := start
+	for i := start + 1; i < len(lines) && isDiffLine(dedent(lines[i], indent)); i++ {
+		end = i
+	}
+	// Trailing blank lines end the output, not the diff.
+	for end > start && strings.TrimSpace(lines[end]) == "" {
+		end--
+	}
+	var body strings.Builder
+	for _, line := range lines[start : end+1] {
+		body.WriteString(dedent(line, indent))
+		body.WriteByte('\n')
+	}
+	block := Block{Lang: "diff", Text: body.String(), Line: start + 1}
+	if !block.IsPatch() {
+		return 0, Block{}, false
+	}
+	return end, block, true
+}
+
+var diffLinePrefixes = []string{
+	" ", "+", "-", "@@ ", `\ No newline`,
+	"diff --git ", "index ", "new file mode ", "deleted file mode ",
+	"old mode ", "new mode ", "similarity index ", "rename from ", "rename to ",
+	"Binary files ",
+}
+
+func isDiffLine(line string) bool {
+	if line == "" {
+		return true
+	}
+	for _, prefix := range diffLinePrefixes {
+		if strings.HasPrefix(line, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// dedupe drops every block whose text appears again later, so agents that
+// redraw their output don't list a block twice.
+func dedupe(blocks []Block) []Block {
+	last := make(map[string]int, len(blocks))
+	for i, b := range blocks {
+		last[b.Text] = i
+	}
+	out := blocks[:0]
+	for i, b := range blocks {
+		if last[b.Text] == i && strings.TrimSpace(b.Text) != "" {
+			out = append(out, b)
+		}
+	}
+	return out
+}
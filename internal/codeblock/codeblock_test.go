@@ -0,0 +1,72 @@
+package codeblock
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestExtractFencedBlocks(t *testing.T) {
+	text := strings.Join([]string{
+		"Here is the fix:",
+		"",
+		"  ```go title",
+		"  func add(a, b int) int {",
+		"  \treturn a + b",
+		"  }",
+		"  ```",
+		"",
+		"~~~~",
+		"``` not a close",
+		"~~~~",
+		"```sh",
+		"still printing",
+	}, "\n")
+	blocks := Extract(text)
+	if len(blocks) != 2 {
+		t.Fatalf("Extract() = %+v, want 2 blocks", blocks)
+	}
+	if b := blocks[0]; b.Lang != "go" || b.Line != 4 || b.Text != "func add(a, b int) int {\n\treturn a + b\n}\n" {
+		t.Fatalf("first block = %+v", b)
+	}
+	if b := blocks[1]; b.Lang != "" || b.Text != "``` not a close\n" {
+		t.Fatalf("second block = %+v", b)
+	}
+	if blocks[0].Title() != "func add(a, b int) int {" || blocks[0].Lines() != 3 {
+		t.Fatalf("Title() = %q, Lines() = %d", blocks[0].Title(), blocks[0].Lines())
+	}
+}
+
+func TestExtractUnfencedDiff(t *testing.T) {
+	text := strings.Join([]string{
+		"I would change it like this:",
+		"  diff --git a/main.go b/main.go",
+		"  --- a/main.go",
+		"  +++ b/main.go",
+		"  @@ -1,3 +1,3 @@",
+		"   package main",
+		"",
+		"  -var x = 1",
+		"  +var x = 2",
+		"",
+		"Want me to apply it?",
+	}, "\n")
+	blocks := Extract(text)
+	if len(blocks) != 1 {
+		t.Fatalf("Extract() = %+v, want one diff", blocks)
+	}
+	want := "diff --git a/main.go b/main.go\n--- a/main.go\n+++ b/main.go\n@@ -1,3 +1,3 @@\n package main\n\n-var x = 1\n+var x = 2\n"
+	if b := blocks[0]; b.Lang != "diff" || b.Line != 2 || b.Text != want || !b.IsPatch() {
+		t.Fatalf("diff block = %+v", b)
+	}
+}
+
+func TestExtractDropsRepeatsAndNonPatches(t *testing.T) {
+	text := "```\nsame\n```\n--- a list item\nnot a diff\n```\nsame\n```\n"
+	blocks := Extract(text)
+	if len(blocks) != 1 || blocks[0].Line != 7 {
+		t.Fatalf("Extract() = %+v, want the last copy only", blocks)
+	}
+	if blocks[0].IsPatch() {
+		t.Fatal("plain block reported as a patch")
+	}
+}
@@ -0,0 +1,202 @@
+// Package codeowners reads a repository's CODEOWNERS file and answers who
+// owns a path, so review views can show the team behind each changed file
+// and flag changes that cut across many teams.
+//
+// Patterns follow GitHub's rules, which GitLab shares: gitignore-style
+// globs, where the last matching line wins and a line without owners leaves
+// its paths unowned. GitLab's [Section] headers are read too; a section's
+// default owners apply to its lines that name none.
+package codeowners
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// ManyAreas is the number of ownership areas past which a set of changes is
+// worth splitting up.
+const ManyAreas = 3
+
+// Locations are where a CODEOWNERS file is looked for, in order.
+var Locations = []string{
+	".github/CODEOWNERS",
+	"CODEOWNERS",
+	"docs/CODEOWNERS",
+	".gitlab/CODEOWNERS",
+}
+
+// Ruleset is a parsed CODEOWNERS file. A nil Ruleset owns nothing.
+type Ruleset struct {
+	rules []rule
+}
+
+type rule struct {
+	pattern string
+	re      *regexp.Regexp
+	owners  []string
+}
+
+// Load reads the CODEOWNERS file of the repository at root. It returns nil
+// and no error when the repository has none.
+func Load(root string) (*Ruleset, error) {
+	for _, loc := range Locations {
+		f, err := os.Open(filepath.Join(root, loc))
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		rs, err := Parse(f)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", loc, err)
+		}
+		return rs, nil
+	}
+	return nil, nil
+}
+
+// Parse reads CODEOWNERS lines from r.
+func Parse(r io.Reader) (*Ruleset, error) {
+	rs := &Ruleset{}
+	var sectionOwners []string
+	scanner := bufio.NewScanner(r)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if i := strings.Index(line, " #"); i >= 0 {
+			line = strings.TrimSpace(line[:i])
+		}
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if owners, ok := sectionHeader(line); ok {
+			sectionOwners = owners
+			continue
+		}
+		fields := strings.Fields(line)
+		re, err := compile(fields[0])
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", n, err)
+		}
+		owners := fields[1:]
+		if len(owners) == 0 {
+			owners = sectionOwners
+		}
+		rs.rules = append(rs.rules, rule{pattern: fields[0], re: re, owners: owners})
+	}
+	return rs, scanner.Err()
+}
+
+// sectionHeader parses a GitLab section header such as
+// "^[Docs][2] @docs-team", returning its default owners.
+func sectionHeader(line string) ([]string, bool) {
+	line = strings.TrimPrefix(line, "^")
+	if !strings.HasPrefix(line, "[") {
+		return nil, false
+	}
+	end := strings.Index(line, "]")
+	if end < 0 {
+		return nil, false
+	}
+	rest := line[end+1:]
+	// An optional [n] gives the approvals required.
+	if strings.HasPrefix(rest, "[") {
+		if i := strings.Index(rest, "]"); i >= 0 {
+			rest = rest[i+1:]
+		}
+	}
+	return strings.Fields(rest), true
+}
+
+// Owners returns the owners of path, a slash-separated path relative to the
+// repository root; none when no line matches or the last one to match names
+// no owners.
+func (rs *Ruleset) Owners(path string) []string {
+	if rs == nil {
+		return nil
+	}
+	path = strings.TrimPrefix(filepath.ToSlash(path), "/")
+	for i := len(rs.rules) - 1; i >= 0; i-- {
+		if rs.rules[i].re.MatchString(path) {
+			return rs.rules[i].owners
+		}
+	}
+	return nil
+}
+
+// Area is a set of owners and the paths they own.
+type Area struct {
+	// Owners is empty for the paths nobody owns.
+	Owners []string
+	Paths  []string
+}
+
+// Label names the area's owners, or "unowned".
+func (a Area) Label() string {
+	if len(a.Owners) == 0 {
+		return "unowned"
+	}
+	return strings.Join(a.Owners, " ")
+}
+
+// Areas groups paths by their owners, the largest area first. Unowned paths
+// are grouped last.
+func (rs *Ruleset) Areas(paths []string) []Area {
+	var areas []Area
+	for _, path := range paths {
+		owners := rs.Owners(path)
+		i := slices.IndexFunc(areas, func(a Area) bool { return slices.Equal(a.Owners, owners) })
+		if i < 0 {
+			areas = append(areas, Area{Owners: owners})
+			i = len(areas) - 1
+		}
+		areas[i].Paths = append(areas[i].Paths, path)
+	}
+	slices.SortStableFunc(areas, func(a, b Area) int {
+		if (len(a.Owners) == 0) != (len(b.Owners) == 0) {
+			if len(a.Owners) == 0 {
+				return 1
+			}
+			return -1
+		}
+		return len(b.Paths) - len(a.Paths)
+	})
+	return areas
+}
+
+// Owned counts the areas that have owners.
+func Owned(areas []Area) int {
+	n := 0
+	for _, a := range areas {
+		if len(a.Owners) > 0 {
+			n++
+		}
+	}
+	return n
+}
+
+// Markdown renders areas as a "Code owners" section for a pull request
+// description: each area's owners and the files of theirs that changed.
+func Markdown(areas []Area) string {
+	if Owned(areas) == 0 {
+		return ""
+	}
+	var b strings.Builder
+	b.WriteString("## Code owners\n\n")
+	for _, a := range areas {
+		files := make([]string, len(a.Paths))
+		for i, p := range a.Paths {
+			files[i] = "`" + p + "`"
+		}
+		fmt.Fprintf(&b, "- %s: %s\n", a.Label(), strings.Join(files, ", "))
+	}
+	return b.String()
+}
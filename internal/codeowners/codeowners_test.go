@@ -0,0 +1,88 @@
+package codeowners
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+const sample = `# Default owners
+*                 @acme/core
+*.md              @acme/docs
+/apps/web/        @acme/web
+apps/api/**/*.go  @acme/api
+docs/*            @acme/docs @writer
+/vendor/          # nobody reviews vendored code
+
+[Payments] @acme/payments
+/billing/
+/billing/tax/ @acme/tax
+`
+
+func TestOwners(t *testing.T) {
+	rs, err := Parse(strings.NewReader(sample))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for path, want := range map[string]string{
+		"main.go":                       "@acme/core",
+		"README.md":                     "@acme/docs",
+		"apps/web/src/App.tsx":          "@acme/web",
+		"apps/web/README.md":            "@acme/web",
+		"apps/api/handlers/users.go":    "@acme/api",
+		"apps/api/main.go":              "@acme/api",
+		"apps/api/schema.sql":           "@acme/core",
+		"docs/guide.txt":                "@acme/docs @writer",
+		"docs/deep/guide.txt":           "@acme/core",
+		"vendor/lib/x.go":               "",
+		"billing/invoice.go":            "@acme/payments",
+		"billing/tax/rates.go":          "@acme/tax",
+		"other/apps/web/not-anchored.c": "@acme/core",
+	} {
+		if got := strings.Join(rs.Owners(path), " "); got != want {
+			t.Errorf("Owners(%q) = %q, want %q", path, got, want)
+		}
+	}
+}
+
+func TestAreas(t *testing.T) {
+	rs, _ := Parse(strings.NewReader(sample))
+	areas := rs.Areas([]string{"apps/web/a.ts", "vendor/x.go", "apps/web/b.ts", "main.go"})
+	var labels []string
+	for _, a := range areas {
+		labels = append(labels, a.Label())
+	}
+	if !slices.Equal(labels, []string{"@acme/web", "@acme/core", "unowned"}) || Owned(areas) != 2 {
+		t.Fatalf("areas = %v", labels)
+	}
+	md := Markdown(areas)
+	for _, want := range []string{"## Code owners", "- @acme/web: `apps/web/a.ts`, `apps/web/b.ts`", "- unowned: `vendor/x.go`"} {
+		if !strings.Contains(md, want) {
+			t.Errorf("Markdown lacks %q:\n%s", want, md)
+		}
+	}
+
+	var none *Ruleset
+	if got := none.Owners("main.go"); got != nil || Markdown(none.Areas([]string{"main.go"})) != "" {
+		t.Fatalf("nil ruleset owns %v", got)
+	}
+}
+
+func TestLoad(t *testing.T) {
+	root := t.TempDir()
+	if rs, err := Load(root); rs != nil || err != nil {
+		t.Fatalf("Load without a file = %v, %v", rs, err)
+	}
+	if err := os.MkdirAll(filepath.Join(root, ".github"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(root, ".github", "CODEOWNERS"), []byte("* @octocat\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	rs, err := Load(root)
+	if err != nil || strings.Join(rs.Owners("x"), "") != "@octocat" {
+		t.Fatalf("Load = %v, %v", rs, err)
+	}
+}
@@ -0,0 +1,54 @@
+package codeowners
+
+import (
+	"regexp"
+	"strings"
+)
+
+// compile turns a CODEOWNERS pattern into a regexp matching the paths it
+// covers:
+//
+//   - a pattern with a slash before its end is anchored at the root, one
+//     without matches at any depth;
+//   - a trailing slash matches only a directory, and everything under it;
+//   - * and ? match within one path segment and ** across segments;
+//   - a pattern naming a directory also covers everything under it, but one
+//     whose last segment is a wildcard, like docs/*, covers only that level.
+func compile(pattern string) (*regexp.Regexp, error) {
+	p := strings.ReplaceAll(pattern, `\#`, "#")
+	dirOnly := strings.HasSuffix(p, "/")
+	p = strings.TrimSuffix(p, "/")
+	anchored := strings.Contains(p, "/")
+	p = strings.TrimPrefix(p, "/")
+
+	var b strings.Builder
+	b.WriteString("^")
+	if !anchored && !strings.HasPrefix(p, "**") {
+		b.WriteString("(?:.*/)?")
+	}
+	for i := 0; i < len(p); i++ {
+		switch c := p[i]; {
+		case strings.HasPrefix(p[i:], "**/"):
+			b.WriteString("(?:.*/)?")
+			i += 2
+		case strings.HasPrefix(p[i:], "**"):
+			b.WriteString(".*")
+			i++
+		case c == '*':
+			b.WriteString("[^/]*")
+		case c == '?':
+			b.WriteString("[^/]")
+		default:
+			b.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	last := p[strings.LastIndex(p, "/")+1:]
+	switch {
+	case dirOnly:
+		b.WriteString("/.*")
+	case !strings.ContainsAny(last, "*?"):
+		b.WriteString("(?:/.*)?")
+	}
+	b.WriteString("$")
+	return regexp.Compile(b.String())
+}
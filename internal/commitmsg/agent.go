@@ -0,0 +1,83 @@
+package commitmsg
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// DraftDir holds the message an agent drafts, relative to the worktree root.
+// It holds its own .gitignore so the draft is never committed.
+const DraftDir = ".amux/commit"
+
+// DraftPath is the file an agent is asked to write its draft to, relative to
+// the worktree root.
+const DraftPath = DraftDir + "/message"
+
+// PrepareAgentDraft readies root for an agent's draft: it creates DraftDir
+// and removes any earlier draft, so a stale one is never offered.
+func PrepareAgentDraft(root string) error {
+	dir := filepath.Join(root, DraftDir)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	ignore := filepath.Join(dir, ".gitignore")
+	if _, err := os.Stat(ignore); errors.Is(err, os.ErrNotExist) {
+		if err := os.WriteFile(ignore, []byte("*\n"), 0o644); err != nil {
+			return err
+		}
+	}
+	return RemoveAgentDraft(root)
+}
+
+// AgentDraft returns the message an agent wrote to DraftPath in root: its
+// first non-empty line, or "" when there is none.
+func AgentDraft(root string) (string, error) {
+	data, err := os.ReadFile(filepath.Join(root, DraftPath))
+	if errors.Is(err, os.ErrNotExist) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return firstLine(string(data)), nil
+}
+
+// firstLine returns the first non-empty line of a drafted message.
+func firstLine(text string) string {
+	for _, line := range strings.Split(text, "\n") {
+		// Agents like to quote the message they were asked for.
+		if line = strings.Trim(strings.TrimSpace(line), "`\""); line != "" {
+			return line
+		}
+	}
+	return ""
+}
+
+// RemoveAgentDraft removes the draft in root, once it was used.
+func RemoveAgentDraft(root string) error {
+	err := os.Remove(filepath.Join(root, DraftPath))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	return err
+}
+
+// AgentPrompt asks an agent to draft a one-line message for its uncommitted
+// changes into DraftPath, in the conventional format when conventional is
+// set. scope, when known, is the scope inferred from the changed paths.
+func AgentPrompt(conventional bool, scope string) string {
+	var b strings.Builder
+	b.WriteString("Please write a one-line commit message for the uncommitted changes in this worktree")
+	fmt.Fprintf(&b, " and save it to %s, replacing anything there. Don't commit, and don't change any other file.\n", DraftPath)
+	if conventional {
+		fmt.Fprintf(&b, "\nUse the Conventional Commits format, \"type(scope): subject\", with one of these types: %s.", strings.Join(Types, ", "))
+		if scope != "" {
+			fmt.Fprintf(&b, " The changes look scoped to %q.", scope)
+		}
+		fmt.Fprintf(&b, " Keep the line to %d characters at most.\n", MaxHeader)
+	}
+	return b.String()
+}
@@ -0,0 +1,102 @@
+// Package commitmsg drafts commit messages for a worktree's changes and checks
+// them against the Conventional Commits format: "type(scope)!: subject",
+// then an optional body after a blank line.
+//
+// A draft comes from a template filled in from the changed paths, from the
+// workspace's agent, which is asked to write one to DraftPath, or from the
+// language model configured for amux, which is given the diff.
+package commitmsg
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// MaxHeader is the longest header line accepted, in characters.
+const MaxHeader = 72
+
+// Types are the accepted commit types, from the Angular convention that
+// commitlint's default config also uses.
+var Types = []string{"feat", "fix", "docs", "style", "refactor", "perf", "test", "build", "ci", "chore", "revert"}
+
+// commitlintConfigs are the files a commitlint config is read from.
+var commitlintConfigs = []string{
+	"commitlint.config.js", "commitlint.config.cjs", "commitlint.config.mjs", "commitlint.config.ts",
+	".commitlintrc", ".commitlintrc.json", ".commitlintrc.yaml", ".commitlintrc.yml", ".commitlintrc.js",
+}
+
+// HasCommitlint reports whether the repository at root configures
+// commitlint, which means its commits are expected to be conventional.
+func HasCommitlint(root string) bool {
+	for _, name := range commitlintConfigs {
+		if _, err := os.Stat(filepath.Join(root, name)); err == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// Header is the first line of a conventional commit message.
+type Header struct {
+	Type     string
+	Scope    string
+	Breaking bool
+	Subject  string
+}
+
+// String formats h as a header line.
+func (h Header) String() string {
+	var b strings.Builder
+	b.WriteString(h.Type)
+	if h.Scope != "" {
+		b.WriteString("(" + h.Scope + ")")
+	}
+	if h.Breaking {
+		b.WriteString("!")
+	}
+	b.WriteString(": " + h.Subject)
+	return b.String()
+}
+
+var headerRE = regexp.MustCompile(`^([a-z]+)(?:\(([^()\s]+)\))?(!)?: (.*)$`)
+
+// ErrNotConventional is wrapped by the errors Validate returns.
+var ErrNotConventional = errors.New("not a conventional commit")
+
+// ParseHeader parses the first line of message.
+func ParseHeader(message string) (Header, error) {
+	line, _, _ := strings.Cut(message, "\n")
+	m := headerRE.FindStringSubmatch(strings.TrimRight(line, "\r"))
+	if m == nil {
+		return Header{}, fmt.Errorf(`%w: start with "type: " or "type(scope): "`, ErrNotConventional)
+	}
+	h := Header{Type: m[1], Scope: m[2], Breaking: m[3] != "", Subject: strings.TrimSpace(m[4])}
+	if !slices.Contains(Types, h.Type) {
+		return h, fmt.Errorf("%w: unknown type %q (use %s)", ErrNotConventional, h.Type, strings.Join(Types, ", "))
+	}
+	if h.Subject == "" {
+		return h, fmt.Errorf("%w: the subject after %q is empty", ErrNotConventional, h.Type+":")
+	}
+	return h, nil
+}
+
+// Validate checks that message is a conventional commit: a header in the
+// format, no longer than MaxHeader, and a blank line before any body.
+func Validate(message string) error {
+	if _, err := ParseHeader(message); err != nil {
+		return err
+	}
+	lines := strings.Split(message, "\n")
+	if n := len([]rune(strings.TrimRight(lines[0], "\r"))); n > MaxHeader {
+		return fmt.Errorf("%w: the header is %d characters, over %d", ErrNotConventional, n, MaxHeader)
+	}
+	if len(lines) > 1 && strings.TrimSpace(lines[1]) != "" {
+		return fmt.Errorf("%w: leave a blank line between the header and the body", ErrNotConventional)
+	}
+	return nil
+}
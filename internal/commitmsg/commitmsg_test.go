@@ -0,0 +1,108 @@
+package commitmsg
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/andyrewlee/amux/internal/git"
+)
+
+func TestValidate(t *testing.T) {
+	for msg, want := range map[string]string{
+		"feat(app): add commit drafts":             "",
+		"fix!: drop the old flag":                  "",
+		"docs: explain scopes\n\nWith an example.": "",
+		"add commit drafts":                        `start with "type: "`,
+		"feature: add commit drafts":               `unknown type "feature"`,
+		"fix(app):   ":                             "subject",
+		"fix: " + strings.Repeat("x", MaxHeader):   "over 72",
+		"fix: x\nbody right away":                  "blank line",
+	} {
+		err := Validate(msg)
+		switch {
+		case want == "" && err != nil:
+			t.Errorf("Validate(%q) = %v", msg, err)
+		case want != "" && (err == nil || !strings.Contains(err.Error(), want) || !errors.Is(err, ErrNotConventional)):
+			t.Errorf("Validate(%q) = %v, want %q", msg, err, want)
+		}
+	}
+	h, _ := ParseHeader("refactor(ui)!: split the model")
+	if h != (Header{Type: "refactor", Scope: "ui", Breaking: true, Subject: "split the model"}) || h.String() != "refactor(ui)!: split the model" {
+		t.Fatalf("header = %+v", h)
+	}
+}
+
+func TestDraft(t *testing.T) {
+	for _, tc := range []struct {
+		changes []git.Change
+		want    string
+	}{
+		{[]git.Change{{Path: "internal/app/app.go"}, {Path: "internal/app/app_test.go"}}, "fix(app): update 2 files"},
+		{[]git.Change{{Path: "internal/app/new.go", Kind: git.ChangeUntracked}}, "feat(app): add new.go"},
+		{[]git.Change{{Path: "README.md"}, {Path: "docs/CONFIG.md"}}, "docs: update 2 files"},
+		{[]git.Change{{Path: "internal/git/status_test.go"}, {Path: "internal/git/status_test.go", Staged: true}}, "test(git): update status_test.go"},
+		{[]git.Change{{Path: "go.mod"}, {Path: "go.sum"}}, "build: update 2 files"},
+		{[]git.Change{{Path: "cmd/amux/old.go", Kind: git.ChangeDeleted}, {Path: "internal/ui/x.go"}}, "fix: update 2 files"},
+		{nil, ""},
+	} {
+		if got := Draft(tc.changes); got != tc.want {
+			t.Errorf("Draft(%v) = %q, want %q", tc.changes, got, tc.want)
+		}
+	}
+	if got := Scope([]string{"packages/web/src/a.ts", "packages/web/b.ts"}); got != "web" {
+		t.Errorf("Scope = %q, want web", got)
+	}
+}
+
+func TestAgentDraft(t *testing.T) {
+	root := t.TempDir()
+	if err := PrepareAgentDraft(root); err != nil {
+		t.Fatal(err)
+	}
+	if msg, err := AgentDraft(root); msg != "" || err != nil {
+		t.Fatalf("AgentDraft before the agent wrote one = %q, %v", msg, err)
+	}
+	if err := os.WriteFile(filepath.Join(root, DraftPath), []byte("\n`feat(app): draft commit messages`\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if msg, err := AgentDraft(root); msg != "feat(app): draft commit messages" || err != nil {
+		t.Fatalf("AgentDraft = %q, %v", msg, err)
+	}
+	if err := PrepareAgentDraft(root); err != nil {
+		t.Fatal(err)
+	}
+	if msg, _ := AgentDraft(root); msg != "" {
+		t.Fatalf("a new request should drop the earlier draft, got %q", msg)
+	}
+	if ignore, err := os.ReadFile(filepath.Join(root, DraftDir, ".gitignore")); err != nil || string(ignore) != "*\n" {
+		t.Fatalf("gitignore = %q, %v", ignore, err)
+	}
+	if HasCommitlint(root) {
+		t.Fatal("no commitlint config was written")
+	}
+	if err := os.WriteFile(filepath.Join(root, ".commitlintrc.json"), []byte("{}"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if !HasCommitlint(root) {
+		t.Fatal("expected the commitlint config to be found")
+	}
+	if prompt := AgentPrompt(true, "app"); !strings.Contains(prompt, DraftPath) || !strings.Contains(prompt, `scoped to "app"`) {
+		t.Fatalf("prompt = %q", prompt)
+	}
+}
+
+func TestModelDraft(t *testing.T) {
+	if got := ModelInstructions(true, "app"); !strings.Contains(got, "Conventional Commits") || !strings.Contains(got, `scoped to "app"`) {
+		t.Fatalf("ModelInstructions() = %q", got)
+	}
+	prompt := ModelPrompt(strings.Repeat("x", maxModelDiff+10), []string{"new.go"})
+	if !strings.Contains(prompt, "[diff truncated]") || !strings.HasSuffix(prompt, "New files:\nnew.go") || len(prompt) > maxModelDiff+100 {
+		t.Fatalf("ModelPrompt() = %d bytes ending %q", len(prompt), prompt[len(prompt)-40:])
+	}
+	if got := ModelDraft("\n\"fix(app): handle nil workspace\"\n\nThe body."); got != "fix(app): handle nil workspace" {
+		t.Fatalf("ModelDraft() = %q", got)
+	}
+}
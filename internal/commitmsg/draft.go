@@ -0,0 +1,151 @@
+package commitmsg
+
+import (
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/andyrewlee/amux/internal/git"
+)
+
+// containerDirs hold a project's modules rather than naming one, so a scope
+// is taken from the directory below them.
+var containerDirs = []string{"internal", "src", "pkg", "lib", "libs", "cmd", "apps", "packages", "services", "modules"}
+
+// buildFiles are dependency manifests and build scripts.
+var buildFiles = []string{
+	"go.mod", "go.sum", "Makefile", "Dockerfile", "package.json", "package-lock.json",
+	"yarn.lock", "pnpm-lock.yaml", "Cargo.toml", "Cargo.lock", "pyproject.toml",
+	"requirements.txt", "build.gradle", "pom.xml",
+}
+
+// Scope infers a scope from paths: the module directory they all fall under,
+// looking past container directories such as internal/ and packages/. It
+// returns "" when they span modules or sit at the root.
+func Scope(paths []string) string {
+	scope := ""
+	for i, p := range paths {
+		s := moduleDir(p)
+		if s == "" || (i > 0 && s != scope) {
+			return ""
+		}
+		scope = s
+	}
+	return scope
+}
+
+func moduleDir(p string) string {
+	dirs := strings.Split(path.Dir(strings.TrimPrefix(p, "./")), "/")
+	for len(dirs) > 1 && slices.Contains(containerDirs, dirs[0]) {
+		dirs = dirs[1:]
+	}
+	if dirs[0] == "." || slices.Contains(containerDirs, dirs[0]) {
+		return ""
+	}
+	return strings.TrimPrefix(dirs[0], ".")
+}
+
+// Type infers a commit type from changes: docs, test, ci, or build when every
+// file is of that kind; otherwise feat when a file was added and fix when not.
+// The last two are guesses for the author to correct.
+func Type(changes []git.Change) string {
+	kinds := []struct {
+		typ   string
+		match func(string) bool
+	}{
+		{"docs", isDoc},
+		{"test", isTest},
+		{"ci", isCI},
+		{"build", isBuild},
+	}
+	for _, k := range kinds {
+		if len(changes) > 0 && !slices.ContainsFunc(changes, func(c git.Change) bool { return !k.match(c.Path) }) {
+			return k.typ
+		}
+	}
+	if slices.ContainsFunc(changes, func(c git.Change) bool { return isNew(c.Kind) }) {
+		return "feat"
+	}
+	return "fix"
+}
+
+func isNew(k git.ChangeKind) bool {
+	return k == git.ChangeAdded || k == git.ChangeUntracked
+}
+
+func isDoc(p string) bool {
+	switch strings.ToLower(path.Ext(p)) {
+	case ".md", ".mdx", ".rst", ".adoc":
+		return true
+	}
+	return strings.HasPrefix(p, "docs/")
+}
+
+func isTest(p string) bool {
+	base := path.Base(p)
+	if strings.HasSuffix(base, "_test.go") || strings.Contains(base, ".test.") || strings.Contains(base, ".spec.") ||
+		(strings.HasPrefix(base, "test_") && strings.HasSuffix(base, ".py")) {
+		return true
+	}
+	for _, dir := range strings.Split(path.Dir(p), "/") {
+		if dir == "test" || dir == "tests" || dir == "__tests__" || dir == "testdata" {
+			return true
+		}
+	}
+	return false
+}
+
+func isCI(p string) bool {
+	return strings.HasPrefix(p, ".github/workflows/") || strings.HasPrefix(p, ".circleci/") || p == ".gitlab-ci.yml"
+}
+
+func isBuild(p string) bool {
+	return slices.Contains(buildFiles, path.Base(p))
+}
+
+// Draft fills in a message template for changes: an inferred type and scope,
+// and a subject saying what happened to which files. Changes listed both
+// staged and unstaged count once.
+func Draft(changes []git.Change) string {
+	var files []git.Change
+	var paths []string
+	for _, c := range changes {
+		if !slices.Contains(paths, c.Path) {
+			files = append(files, c)
+			paths = append(paths, c.Path)
+		}
+	}
+	if len(files) == 0 {
+		return ""
+	}
+	h := Header{Type: Type(files), Scope: Scope(paths)}
+	if h.Scope == h.Type {
+		h.Scope = ""
+	}
+	verb := changeVerb(files[0].Kind)
+	for _, c := range files[1:] {
+		if changeVerb(c.Kind) != verb {
+			verb = "update"
+			break
+		}
+	}
+	if len(files) == 1 {
+		h.Subject = verb + " " + path.Base(files[0].Path)
+	} else {
+		h.Subject = fmt.Sprintf("%s %d files", verb, len(files))
+	}
+	return h.String()
+}
+
+func changeVerb(k git.ChangeKind) string {
+	switch k {
+	case git.ChangeAdded, git.ChangeUntracked, git.ChangeCopied:
+		return "add"
+	case git.ChangeDeleted:
+		return "remove"
+	case git.ChangeRenamed:
+		return "rename"
+	}
+	return "update"
+}
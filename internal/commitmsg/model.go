@@ -0,0 +1,49 @@
+package commitmsg
+
+import (
+	"fmt"
+	"strings"
+)
+
+// maxModelDiff bounds the diff sent to a language model, in bytes.
+const maxModelDiff = 32 << 10
+
+// ModelInstructions asks a language model for a one-line message, in the
+// conventional format when conventional is set. scope, when known, is the
+// scope inferred from the changed paths.
+func ModelInstructions(conventional bool, scope string) string {
+	var b strings.Builder
+	b.WriteString("You write git commit messages. Reply with a one-line commit message for the changes you are given, and nothing else.")
+	if conventional {
+		fmt.Fprintf(&b, " Use the Conventional Commits format, \"type(scope): subject\", with one of these types: %s.", strings.Join(Types, ", "))
+		if scope != "" {
+			fmt.Fprintf(&b, " The changes look scoped to %q.", scope)
+		}
+	}
+	fmt.Fprintf(&b, " Keep the line to %d characters at most.", MaxHeader)
+	return b.String()
+}
+
+// ModelPrompt is the input a language model drafts from: the diff of the
+// tracked files, cut off past maxModelDiff, and the names of new files.
+func ModelPrompt(diff string, untracked []string) string {
+	var b strings.Builder
+	if len(diff) > maxModelDiff {
+		diff = diff[:maxModelDiff] + "\n[diff truncated]"
+	}
+	if diff != "" {
+		b.WriteString(diff)
+	}
+	if len(untracked) > 0 {
+		if b.Len() > 0 {
+			b.WriteString("\n\n")
+		}
+		b.WriteString("New files:\n" + strings.Join(untracked, "\n"))
+	}
+	return b.String()
+}
+
+// ModelDraft returns the message in a model's reply.
+func ModelDraft(reply string) string {
+	return firstLine(reply)
+}
@@ -5,6 +5,8 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+
+	"github.com/andyrewlee/amux/internal/limits"
 )
 
 // readAssistantsSection decodes the "assistants" object of a config file on
@@ -243,3 +245,45 @@ func TestConfigSaveAssistants(t *testing.T) {
 		}
 	})
 }
+
+func TestSaveAssistantsRoundTripsLimitsAndRestart(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	want := limits.Limits{MemoryMB: 4096, CPUPercent: 200, Nice: 5}
+	restart := RestartPolicy{MaxRetries: 3, ResumeCommand: "claude --continue", ResumePrompt: "Resume the task."}
+	if err := saveAssistants(path, map[string]AssistantConfig{
+		"claude": {Command: "claude", Limits: want, Restart: restart},
+		"codex":  {Command: "codex"},
+	}); err != nil {
+		t.Fatalf("saveAssistants() error = %v", err)
+	}
+	codex := readAssistantsSection(t, path)["codex"].(map[string]any)
+	if _, ok := codex["limits"]; ok {
+		t.Error("an assistant without limits should not write a limits entry")
+	}
+	if _, ok := codex["restart"]; ok {
+		t.Error("an assistant without a restart policy should not write a restart entry")
+	}
+
+	file, err := readConfigFile(path)
+	if err != nil {
+		t.Fatalf("readConfigFile() error = %v", err)
+	}
+	got := defaultAssistants()
+	applyAssistantOverrides(got, file.Assistants)
+	if got["claude"].Limits != want {
+		t.Errorf("claude limits = %+v, want %+v", got["claude"].Limits, want)
+	}
+	if got["claude"].Restart != restart {
+		t.Errorf("claude restart = %+v, want %+v", got["claude"].Restart, restart)
+	}
+}
+
+func TestAssistantLimitsAreNormalized(t *testing.T) {
+	got := defaultAssistants()
+	applyAssistantOverrides(got, map[string]assistantConfigRaw{
+		"claude": {Limits: &limits.Limits{MemoryMB: -1, Nice: -5}},
+	})
+	if got["claude"].Limits != (limits.Limits{}) {
+		t.Errorf("claude limits = %+v, want invalid values dropped", got["claude"].Limits)
+	}
+}
@@ -7,10 +7,13 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"runtime"
 	"sort"
 	"strings"
 
 	"github.com/andyrewlee/amux/internal/fsatomic"
+	"github.com/andyrewlee/amux/internal/limits"
+	"github.com/andyrewlee/amux/internal/llm"
 	"github.com/andyrewlee/amux/internal/logging"
 
 	"github.com/andyrewlee/amux/internal/validation"
@@ -23,19 +26,38 @@ type Config struct {
 	PortRangeSize int
 	Assistants    map[string]AssistantConfig
 	UI            UISettings
+	// OpenIn lists the external tools a worktree can be opened in from the
+	// dashboard ("open in…"), in display order.
+	OpenIn []OpenInTarget
+	// Schedules lists the agent tasks `amux schedule run` launches.
+	Schedules []ScheduledTask
+	// Dictation is the voice input command (prefix t m).
+	Dictation Dictation
+	// LLM is the model amux's assistive features use, such as drafting
+	// commit messages.
+	LLM llm.Config
+	// StatusWidgets are the status bar's segments, left to right. The bar is
+	// shown only when there are some.
+	StatusWidgets []StatusWidget
 }
 
 // AssistantConfig defines how to launch an AI assistant
 type AssistantConfig struct {
-	Command          string // Shell command to launch the assistant
-	InterruptCount   int    // Number of Ctrl-C signals to send (default 1, claude needs 2)
-	InterruptDelayMs int    // Delay between interrupts in milliseconds
+	Command          string        // Shell command to launch the assistant
+	InterruptCount   int           // Number of Ctrl-C signals to send (default 1, claude needs 2)
+	InterruptDelayMs int           // Delay between interrupts in milliseconds
+	Limits           limits.Limits // CPU/memory caps for each agent tab
+	Restart          RestartPolicy // Automatic restart after a crash
+	Park             ParkPolicy    // What to do with an agent left idle
 }
 
 type assistantConfigRaw struct {
-	Command          string `json:"command"`
-	InterruptCount   *int   `json:"interrupt_count"`
-	InterruptDelayMs *int   `json:"interrupt_delay_ms"`
+	Command          string         `json:"command"`
+	InterruptCount   *int           `json:"interrupt_count"`
+	InterruptDelayMs *int           `json:"interrupt_delay_ms"`
+	Limits           *limits.Limits `json:"limits"`
+	Restart          *RestartPolicy `json:"restart"`
+	Park             *ParkPolicy    `json:"park"`
 }
 
 const fallbackDefaultAssistant = "claude"
@@ -67,6 +89,11 @@ func DefaultConfig() (*Config, error) {
 		PortRangeSize: 10,
 		UI:            applyUISettings(defaultUISettings(), file.UI),
 		Assistants:    assistants,
+		OpenIn:        resolveOpenInTargets(runtime.GOOS, file.OpenIn),
+		Schedules:     resolveScheduledTasks(file.Schedules),
+		Dictation:     resolveDictation(file.Dictation),
+		LLM:           resolveLLM(file.LLM),
+		StatusWidgets: resolveStatusWidgets(file.StatusBar),
 	}
 	return cfg, nil
 }
@@ -75,11 +102,21 @@ func DefaultConfig() (*Config, error) {
 type configFile struct {
 	Assistants map[string]assistantConfigRaw `json:"assistants"`
 	UI         uiSettingsRaw                 `json:"ui"`
+	OpenIn     []openInTargetRaw             `json:"open_in"`
+	Schedules  []scheduledTaskRaw            `json:"schedules"`
+	Dictation  dictationRaw                  `json:"dictation"`
+	LLM        llm.Config                    `json:"llm"`
+	StatusBar  statusBarRaw                  `json:"status_bar"`
 }
 
 type configFileSections struct {
 	Assistants json.RawMessage `json:"assistants"`
 	UI         json.RawMessage `json:"ui"`
+	OpenIn     json.RawMessage `json:"open_in"`
+	Schedules  json.RawMessage `json:"schedules"`
+	Dictation  json.RawMessage `json:"dictation"`
+	LLM        json.RawMessage `json:"llm"`
+	StatusBar  json.RawMessage `json:"status_bar"`
 }
 
 // readConfigFile reads the config file once. A missing file is not an error;
@@ -100,23 +137,30 @@ func readConfigFile(path string) (configFile, error) {
 	}
 
 	var errs []error
-	if len(sections.Assistants) > 0 {
-		var assistants map[string]assistantConfigRaw
-		if err := json.Unmarshal(sections.Assistants, &assistants); err != nil {
-			errs = append(errs, fmt.Errorf("assistants: %w", err))
-		} else {
-			file.Assistants = assistants
-		}
+	decodeConfigSection(sections.Assistants, "assistants", &file.Assistants, &errs)
+	decodeConfigSection(sections.UI, "ui", &file.UI, &errs)
+	decodeConfigSection(sections.OpenIn, "open_in", &file.OpenIn, &errs)
+	decodeConfigSection(sections.Schedules, "schedules", &file.Schedules, &errs)
+	decodeConfigSection(sections.Dictation, "dictation", &file.Dictation, &errs)
+	decodeConfigSection(sections.LLM, "llm", &file.LLM, &errs)
+	decodeConfigSection(sections.StatusBar, "status_bar", &file.StatusBar, &errs)
+	return file, errors.Join(errs...)
+}
+
+// decodeConfigSection decodes one raw config section into dst. An absent
+// section leaves dst untouched; a malformed one is recorded in errs (prefixed
+// with the section name) and also leaves dst untouched, so one broken section
+// never takes its siblings down with it.
+func decodeConfigSection[T any](raw json.RawMessage, name string, dst *T, errs *[]error) {
+	if len(raw) == 0 {
+		return
 	}
-	if len(sections.UI) > 0 {
-		var ui uiSettingsRaw
-		if err := json.Unmarshal(sections.UI, &ui); err != nil {
-			errs = append(errs, fmt.Errorf("ui: %w", err))
-		} else {
-			file.UI = ui
-		}
+	var decoded T
+	if err := json.Unmarshal(raw, &decoded); err != nil {
+		*errs = append(*errs, fmt.Errorf("%s: %w", name, err))
+		return
 	}
-	return file, errors.Join(errs...)
+	*dst = decoded
 }
 
 func readConfigPath(path string) ([]byte, error) {
@@ -217,6 +261,15 @@ func applyAssistantOverrides(assistants map[string]AssistantConfig, overrides ma
 		if override.InterruptDelayMs != nil {
 			cfg.InterruptDelayMs = *override.InterruptDelayMs
 		}
+		if override.Limits != nil {
+			cfg.Limits = override.Limits.Normalize()
+		}
+		if override.Restart != nil {
+			cfg.Restart = override.Restart.normalize()
+		}
+		if override.Park != nil {
+			cfg.Park = override.Park.normalize()
+		}
 
 		if cfg.Command == "" {
 			continue
@@ -317,6 +370,15 @@ func saveAssistants(path string, assistants map[string]AssistantConfig) error {
 		if cfg.InterruptDelayMs > 0 {
 			entry["interrupt_delay_ms"] = cfg.InterruptDelayMs
 		}
+		if !cfg.Limits.IsZero() {
+			entry["limits"] = cfg.Limits
+		}
+		if cfg.Restart != (RestartPolicy{}) {
+			entry["restart"] = cfg.Restart
+		}
+		if cfg.Park.After != "" {
+			entry["park"] = cfg.Park
+		}
 		out[name] = entry
 	}
 	payload["assistants"] = out
@@ -0,0 +1,18 @@
+package config
+
+import "strings"
+
+// Dictation configures voice input: Command is run with `sh -c` in the
+// worktree and prints transcribed text to stdout, one utterance per line,
+// until it exits or is interrupted. Empty disables dictation.
+type Dictation struct {
+	Command string
+}
+
+type dictationRaw struct {
+	Command string `json:"command"`
+}
+
+func resolveDictation(raw dictationRaw) Dictation {
+	return Dictation{Command: strings.TrimSpace(raw.Command)}
+}
@@ -0,0 +1,17 @@
+package config
+
+import (
+	"github.com/andyrewlee/amux/internal/llm"
+	"github.com/andyrewlee/amux/internal/logging"
+)
+
+// resolveLLM fills in the "llm" section's defaults. A section naming an
+// unknown provider or no model is logged and leaves every feature off.
+func resolveLLM(raw llm.Config) llm.Config {
+	cfg := raw.WithDefaults()
+	if err := cfg.Validate(); err != nil {
+		logging.Warn("config: %v; assistive features are off", err)
+		return llm.Config{}
+	}
+	return cfg
+}
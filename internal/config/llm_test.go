@@ -0,0 +1,39 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/andyrewlee/amux/internal/llm"
+)
+
+func TestDefaultConfigLoadsLLMSection(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	configPath := filepath.Join(home, ".amux", "config.json")
+	if err := os.MkdirAll(filepath.Dir(configPath), 0o755); err != nil {
+		t.Fatalf("MkdirAll() error = %v", err)
+	}
+	content := `{"llm": {"provider": "anthropic", "model": "claude-haiku", "features": {"commit-message": true}}}`
+	if err := os.WriteFile(configPath, []byte(content), 0o644); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	cfg, err := DefaultConfig()
+	if err != nil {
+		t.Fatalf("DefaultConfig() error = %v", err)
+	}
+	if !cfg.LLM.Enabled(llm.FeatureCommitMessage) || cfg.LLM.Endpoint != "https://api.anthropic.com" || cfg.LLM.APIKeyEnv != "ANTHROPIC_API_KEY" {
+		t.Fatalf("LLM = %+v", cfg.LLM)
+	}
+
+	content = `{"llm": {"provider": "bard", "features": {"commit-message": true}}}`
+	if err := os.WriteFile(configPath, []byte(content), 0o644); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+	if cfg, err = DefaultConfig(); err != nil || cfg.LLM.Enabled(llm.FeatureCommitMessage) {
+		t.Fatalf("an unknown provider should leave features off: %+v, %v", cfg.LLM, err)
+	}
+}
@@ -0,0 +1,82 @@
+package config
+
+import (
+	"strings"
+
+	"github.com/andyrewlee/amux/internal/shellutil"
+)
+
+// OpenInPathPlaceholder is replaced with the shell-quoted worktree path when an
+// "open in…" command is expanded.
+const OpenInPathPlaceholder = "{path}"
+
+// OpenInTarget is one "open worktree in…" entry: the label shown in the picker
+// and the shell command that opens a directory in an external tool.
+type OpenInTarget struct {
+	Name    string
+	Command string
+}
+
+type openInTargetRaw struct {
+	Name    string `json:"name"`
+	Command string `json:"command"`
+}
+
+// defaultOpenInTargets returns the built-in "open in…" entries for goos. The
+// Linux terminal entry relies on the launcher's working directory rather than
+// on a per-emulator working-directory flag.
+func defaultOpenInTargets(goos string) []OpenInTarget {
+	targets := []OpenInTarget{
+		{Name: "VS Code", Command: "code {path}"},
+		{Name: "JetBrains", Command: "idea {path}"},
+	}
+	if goos == "darwin" {
+		return append(targets,
+			OpenInTarget{Name: "Finder", Command: "open {path}"},
+			OpenInTarget{Name: "Terminal", Command: "open -a Terminal {path}"},
+		)
+	}
+	return append(targets,
+		OpenInTarget{Name: "File manager", Command: "xdg-open {path}"},
+		OpenInTarget{Name: "Terminal", Command: "x-terminal-emulator"},
+	)
+}
+
+// resolveOpenInTargets returns the user's "open_in" entries when at least one
+// is usable, otherwise the built-in defaults. A configured list replaces the
+// defaults wholesale so users can drop tools they do not have installed.
+func resolveOpenInTargets(goos string, raw []openInTargetRaw) []OpenInTarget {
+	targets := make([]OpenInTarget, 0, len(raw))
+	for _, entry := range raw {
+		name := strings.TrimSpace(entry.Name)
+		command := strings.TrimSpace(entry.Command)
+		if name == "" || command == "" {
+			continue
+		}
+		targets = append(targets, OpenInTarget{Name: name, Command: command})
+	}
+	if len(targets) == 0 {
+		return defaultOpenInTargets(goos)
+	}
+	return targets
+}
+
+// OpenInTargetNames returns the configured "open in…" labels in display order.
+func (c *Config) OpenInTargetNames() []string {
+	if c == nil {
+		return nil
+	}
+	names := make([]string, 0, len(c.OpenIn))
+	for _, target := range c.OpenIn {
+		names = append(names, target.Name)
+	}
+	return names
+}
+
+// ExpandCommand returns the shell command that opens path, with every {path}
+// placeholder replaced by the single-quoted path. Commands always run with the
+// worktree as their working directory, so a command without a placeholder
+// (e.g. a terminal emulator) still opens in the right place.
+func (t OpenInTarget) ExpandCommand(path string) string {
+	return strings.ReplaceAll(t.Command, OpenInPathPlaceholder, shellutil.ShellQuote(path))
+}
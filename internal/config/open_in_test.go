@@ -0,0 +1,103 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestResolveOpenInTargetsDefaultsPerOS(t *testing.T) {
+	darwin := resolveOpenInTargets("darwin", nil)
+	linux := resolveOpenInTargets("linux", nil)
+	if len(darwin) != 4 || len(linux) != 4 {
+		t.Fatalf("expected 4 default targets, got darwin=%d linux=%d", len(darwin), len(linux))
+	}
+	if darwin[2].Name != "Finder" || darwin[2].Command != "open {path}" {
+		t.Fatalf("darwin file manager = %+v, want Finder via open", darwin[2])
+	}
+	if linux[2].Command != "xdg-open {path}" {
+		t.Fatalf("linux file manager = %+v, want xdg-open", linux[2])
+	}
+}
+
+func TestResolveOpenInTargetsConfiguredReplacesDefaults(t *testing.T) {
+	got := resolveOpenInTargets("linux", []openInTargetRaw{
+		{Name: " Zed ", Command: " zed {path} "},
+		{Name: "", Command: "nameless"},
+		{Name: "commandless", Command: "  "},
+	})
+	if len(got) != 1 {
+		t.Fatalf("expected only the valid entry to survive, got %+v", got)
+	}
+	if got[0].Name != "Zed" || got[0].Command != "zed {path}" {
+		t.Fatalf("expected trimmed Zed entry, got %+v", got[0])
+	}
+
+	if got := resolveOpenInTargets("linux", []openInTargetRaw{{Name: "", Command: ""}}); len(got) != 4 {
+		t.Fatalf("an all-invalid list should fall back to defaults, got %+v", got)
+	}
+}
+
+func TestOpenInTargetExpandCommandQuotesPath(t *testing.T) {
+	target := OpenInTarget{Name: "VS Code", Command: "code {path} --goto {path}"}
+	got := target.ExpandCommand("/tmp/it's here")
+	want := `code '/tmp/it'\''s here' --goto '/tmp/it'\''s here'`
+	if got != want {
+		t.Fatalf("ExpandCommand() = %q, want %q", got, want)
+	}
+
+	plain := OpenInTarget{Name: "Terminal", Command: "x-terminal-emulator"}
+	if got := plain.ExpandCommand("/tmp/ws"); got != "x-terminal-emulator" {
+		t.Fatalf("placeholder-free command should be unchanged, got %q", got)
+	}
+}
+
+func TestDefaultConfigLoadsOpenInSection(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	configPath := filepath.Join(home, ".amux", "config.json")
+	if err := os.MkdirAll(filepath.Dir(configPath), 0o755); err != nil {
+		t.Fatalf("MkdirAll() error = %v", err)
+	}
+	content := `{"open_in": [{"name": "Cursor", "command": "cursor {path}"}], "ui": {"theme": "dracula"}}`
+	if err := os.WriteFile(configPath, []byte(content), 0o644); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	cfg, err := DefaultConfig()
+	if err != nil {
+		t.Fatalf("DefaultConfig() error = %v", err)
+	}
+	if names := cfg.OpenInTargetNames(); len(names) != 1 || names[0] != "Cursor" {
+		t.Fatalf("OpenInTargetNames() = %v, want [Cursor]", names)
+	}
+	if cfg.UI.Theme != "dracula" {
+		t.Fatalf("sibling ui section should still load, got theme %q", cfg.UI.Theme)
+	}
+}
+
+func TestDefaultConfigMalformedOpenInKeepsDefaults(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	configPath := filepath.Join(home, ".amux", "config.json")
+	if err := os.MkdirAll(filepath.Dir(configPath), 0o755); err != nil {
+		t.Fatalf("MkdirAll() error = %v", err)
+	}
+	content := `{"open_in": {"not": "a list"}, "ui": {"theme": "nord"}}`
+	if err := os.WriteFile(configPath, []byte(content), 0o644); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	cfg, err := DefaultConfig()
+	if err != nil {
+		t.Fatalf("DefaultConfig() error = %v", err)
+	}
+	if len(cfg.OpenIn) == 0 {
+		t.Fatal("malformed open_in section should fall back to default targets")
+	}
+	if cfg.UI.Theme != "nord" {
+		t.Fatalf("malformed open_in must not drop the ui section, got theme %q", cfg.UI.Theme)
+	}
+}
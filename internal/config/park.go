@@ -0,0 +1,53 @@
+package config
+
+import (
+	"strings"
+	"time"
+)
+
+// Park actions, sent to an agent when it is parked.
+const (
+	// ParkActionNone only marks the tab parked.
+	ParkActionNone = "none"
+	// ParkActionInterrupt interrupts the agent as Ctrl-C in its tab would.
+	ParkActionInterrupt = "interrupt"
+	// ParkActionSend types ParkPolicy.Text into the agent and submits it,
+	// for example a command that saves the session.
+	ParkActionSend = "send"
+)
+
+// ParkPolicy parks an agent left idle: once neither it nor the user has
+// touched its tab for After, amux sends it Action and marks the tab parked.
+type ParkPolicy struct {
+	// After is the idle timeout as a Go duration ("2h", "45m"). Empty
+	// disables parking.
+	After string `json:"after,omitempty"`
+	// Action is ParkActionNone (the default), ParkActionInterrupt, or
+	// ParkActionSend.
+	Action string `json:"action,omitempty"`
+	// Text is what ParkActionSend types into the agent.
+	Text string `json:"text,omitempty"`
+}
+
+// Timeout returns the idle timeout, or 0 when parking is disabled or After
+// does not parse.
+func (p ParkPolicy) Timeout() time.Duration {
+	d, err := time.ParseDuration(p.After)
+	if err != nil || d <= 0 {
+		return 0
+	}
+	return d
+}
+
+func (p ParkPolicy) normalize() ParkPolicy {
+	p.After = strings.TrimSpace(p.After)
+	switch p.Action = strings.ToLower(strings.TrimSpace(p.Action)); p.Action {
+	case ParkActionInterrupt, ParkActionSend:
+	default:
+		p.Action = ParkActionNone
+	}
+	if p.Action == ParkActionSend && strings.TrimSpace(p.Text) == "" {
+		p.Action = ParkActionNone
+	}
+	return p
+}
@@ -0,0 +1,56 @@
+package config
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestParkPolicyNormalize(t *testing.T) {
+	tests := []struct {
+		in   ParkPolicy
+		want ParkPolicy
+	}{
+		{ParkPolicy{After: " 2h "}, ParkPolicy{After: "2h", Action: ParkActionNone}},
+		{ParkPolicy{After: "2h", Action: "Interrupt"}, ParkPolicy{After: "2h", Action: ParkActionInterrupt}},
+		{ParkPolicy{After: "2h", Action: "send", Text: "/save"}, ParkPolicy{After: "2h", Action: ParkActionSend, Text: "/save"}},
+		{ParkPolicy{After: "2h", Action: "send"}, ParkPolicy{After: "2h", Action: ParkActionNone}},
+		{ParkPolicy{After: "2h", Action: "reboot"}, ParkPolicy{After: "2h", Action: ParkActionNone}},
+	}
+	for _, tt := range tests {
+		if got := tt.in.normalize(); got != tt.want {
+			t.Errorf("normalize(%+v) = %+v, want %+v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestParkPolicyTimeout(t *testing.T) {
+	for after, want := range map[string]time.Duration{"": 0, "90m": 90 * time.Minute, "soon": 0, "-1h": 0} {
+		if got := (ParkPolicy{After: after}).Timeout(); got != want {
+			t.Errorf("Timeout(%q) = %v, want %v", after, got, want)
+		}
+	}
+}
+
+func TestSaveAssistantsRoundTripsPark(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	park := ParkPolicy{After: "2h", Action: ParkActionSend, Text: "/save"}
+	if err := saveAssistants(path, map[string]AssistantConfig{
+		"claude": {Command: "claude", Park: park},
+		"codex":  {Command: "codex"},
+	}); err != nil {
+		t.Fatalf("saveAssistants() error = %v", err)
+	}
+	if _, ok := readAssistantsSection(t, path)["codex"].(map[string]any)["park"]; ok {
+		t.Error("an assistant without a park policy should not write a park entry")
+	}
+	file, err := readConfigFile(path)
+	if err != nil {
+		t.Fatalf("readConfigFile() error = %v", err)
+	}
+	got := defaultAssistants()
+	applyAssistantOverrides(got, file.Assistants)
+	if got["claude"].Park != park {
+		t.Errorf("claude park = %+v, want %+v", got["claude"].Park, park)
+	}
+}
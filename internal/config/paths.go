@@ -1,12 +1,22 @@
 package config
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
+	"regexp"
+	"strings"
 )
 
 const WorkspacesRootEnvVar = "AMUX_WORKSPACES_ROOT"
 
+// ProfileEnvVar names the profile to use; `amux --profile <name>` sets it.
+// Each profile keeps its own projects, config, secrets, and worktrees under
+// ~/.amux/profiles/<name>, and runs on its own tmux server.
+const ProfileEnvVar = "AMUX_PROFILE_NAME"
+
+var profileNamePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_-]{0,63}$`)
+
 // Paths holds all the file system paths used by the application
 type Paths struct {
 	Home           string // ~/.amux
@@ -14,6 +24,8 @@ type Paths struct {
 	RegistryPath   string // ~/.amux/projects.json
 	MetadataRoot   string // ~/.amux/workspaces-metadata
 	ConfigPath     string // ~/.amux/config.json
+	TrashRoot      string // ~/.amux/trash
+	Profile        string // named profile, empty for the default
 }
 
 // DefaultPaths returns the default paths configuration
@@ -24,6 +36,13 @@ func DefaultPaths() (*Paths, error) {
 	}
 
 	amuxHome := filepath.Join(home, ".amux")
+	profile, err := Profile()
+	if err != nil {
+		return nil, err
+	}
+	if profile != "" {
+		amuxHome = filepath.Join(amuxHome, "profiles", profile)
+	}
 
 	return &Paths{
 		Home:           amuxHome,
@@ -31,9 +50,31 @@ func DefaultPaths() (*Paths, error) {
 		RegistryPath:   filepath.Join(amuxHome, "projects.json"),
 		MetadataRoot:   filepath.Join(amuxHome, "workspaces-metadata"),
 		ConfigPath:     filepath.Join(amuxHome, "config.json"),
+		TrashRoot:      filepath.Join(amuxHome, "trash"),
+		Profile:        profile,
 	}, nil
 }
 
+// Profile returns the profile ProfileEnvVar names, empty for the default.
+func Profile() (string, error) {
+	name := strings.TrimSpace(os.Getenv(ProfileEnvVar))
+	if name == "" || name == "default" {
+		return "", nil
+	}
+	if err := ValidateProfileName(name); err != nil {
+		return "", err
+	}
+	return name, nil
+}
+
+// ValidateProfileName checks that name can name a profile directory.
+func ValidateProfileName(name string) error {
+	if !profileNamePattern.MatchString(name) {
+		return fmt.Errorf("invalid profile name %q: use letters, digits, - and _", name)
+	}
+	return nil
+}
+
 // EnsureDirectories creates all required directories if they don't exist
 func (p *Paths) EnsureDirectories() error {
 	dirs := []string{
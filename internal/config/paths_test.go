@@ -33,3 +33,31 @@ func TestPathsEnsureDirectories(t *testing.T) {
 		}
 	}
 }
+
+func TestDefaultPathsProfile(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	t.Setenv(ProfileEnvVar, "")
+	paths, err := DefaultPaths()
+	if err != nil || paths.Home != filepath.Join(home, ".amux") || paths.Profile != "" {
+		t.Fatalf("default paths = %+v, %v", paths, err)
+	}
+
+	t.Setenv(ProfileEnvVar, "work")
+	paths, err = DefaultPaths()
+	if err != nil {
+		t.Fatalf("DefaultPaths() error = %v", err)
+	}
+	want := filepath.Join(home, ".amux", "profiles", "work")
+	if paths.Home != want || paths.RegistryPath != filepath.Join(want, "projects.json") || paths.Profile != "work" {
+		t.Fatalf("work paths = %+v", paths)
+	}
+
+	for _, bad := range []string{"../etc", "a/b", "-x", ".hidden"} {
+		t.Setenv(ProfileEnvVar, bad)
+		if _, err := DefaultPaths(); err == nil {
+			t.Fatalf("profile %q accepted", bad)
+		}
+	}
+}
@@ -0,0 +1,28 @@
+package config
+
+import "strings"
+
+// RestartPolicy restarts an assistant in its tab when it crashes.
+type RestartPolicy struct {
+	// MaxRetries is how many crashes in a row are restarted; 0 disables
+	// automatic restart.
+	MaxRetries int `json:"max_retries,omitempty"`
+	// ResumeCommand replaces the assistant's command on restart so it picks
+	// up where it left off (for example "claude --continue"). Empty reruns
+	// the original command.
+	ResumeCommand string `json:"resume_command,omitempty"`
+	// ResumePrompt is passed to the restarted assistant as its initial
+	// prompt, as the last argument of the resume command.
+	ResumePrompt string `json:"resume_prompt,omitempty"`
+}
+
+// Enabled reports whether the policy restarts crashed agents.
+func (p RestartPolicy) Enabled() bool {
+	return p.MaxRetries > 0
+}
+
+func (p RestartPolicy) normalize() RestartPolicy {
+	p.MaxRetries = max(p.MaxRetries, 0)
+	p.ResumeCommand = strings.TrimSpace(p.ResumeCommand)
+	return p
+}
@@ -0,0 +1,66 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ScheduledTask launches an agent with a prompt on a cron schedule while
+// `amux schedule run` is running (see internal/schedule).
+type ScheduledTask struct {
+	Name string
+	// Cron is a five-field cron expression or a macro such as @daily.
+	Cron string
+	// Project is the path of a project registered in amux.
+	Project string
+	// Workspace names an existing workspace of the project to run in. Empty
+	// runs each time in a fresh workspace branched from Base.
+	Workspace string
+	Base      string
+	// Assistant defaults to the workspace's, then the default assistant.
+	Assistant string
+	Prompt    string
+}
+
+type scheduledTaskRaw struct {
+	Name      string `json:"name"`
+	Cron      string `json:"cron"`
+	Project   string `json:"project"`
+	Workspace string `json:"workspace"`
+	Base      string `json:"base"`
+	Assistant string `json:"assistant"`
+	Prompt    string `json:"prompt"`
+}
+
+// resolveScheduledTasks trims the configured tasks and expands a leading ~
+// in their project paths. Tasks are validated when the scheduler starts, so
+// a broken entry is reported there rather than dropped here.
+func resolveScheduledTasks(raw []scheduledTaskRaw) []ScheduledTask {
+	tasks := make([]ScheduledTask, 0, len(raw))
+	for _, entry := range raw {
+		tasks = append(tasks, ScheduledTask{
+			Name:      strings.TrimSpace(entry.Name),
+			Cron:      strings.TrimSpace(entry.Cron),
+			Project:   expandHome(strings.TrimSpace(entry.Project)),
+			Workspace: strings.TrimSpace(entry.Workspace),
+			Base:      strings.TrimSpace(entry.Base),
+			Assistant: strings.TrimSpace(entry.Assistant),
+			Prompt:    strings.TrimSpace(entry.Prompt),
+		})
+	}
+	return tasks
+}
+
+// expandHome replaces a leading ~/ with the user's home directory.
+func expandHome(path string) string {
+	rest, ok := strings.CutPrefix(path, "~/")
+	if !ok {
+		return path
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return path
+	}
+	return filepath.Join(home, rest)
+}
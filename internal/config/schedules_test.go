@@ -0,0 +1,44 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestDefaultConfigLoadsSchedules(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	configPath := filepath.Join(home, ".amux", "config.json")
+	if err := os.MkdirAll(filepath.Dir(configPath), 0o755); err != nil {
+		t.Fatalf("MkdirAll() error = %v", err)
+	}
+	content := `{
+  "schedules": [
+    {
+      "name": " deps ",
+      "cron": "0 3 * * 1-5",
+      "project": "~/src/api",
+      "prompt": "Update dependencies and open a PR."
+    }
+  ]
+}`
+	if err := os.WriteFile(configPath, []byte(content), 0o644); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	cfg, err := DefaultConfig()
+	if err != nil {
+		t.Fatalf("DefaultConfig() error = %v", err)
+	}
+	want := ScheduledTask{
+		Name:    "deps",
+		Cron:    "0 3 * * 1-5",
+		Project: filepath.Join(home, "src", "api"),
+		Prompt:  "Update dependencies and open a PR.",
+	}
+	if len(cfg.Schedules) != 1 || cfg.Schedules[0] != want {
+		t.Fatalf("Schedules = %+v, want [%+v]", cfg.Schedules, want)
+	}
+}
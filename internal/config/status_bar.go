@@ -0,0 +1,73 @@
+package config
+
+import (
+	"strings"
+	"time"
+
+	"github.com/andyrewlee/amux/internal/logging"
+)
+
+const (
+	// defaultWidgetInterval is how often a widget runs when it doesn't say.
+	defaultWidgetInterval = 30 * time.Second
+	// minWidgetInterval keeps a widget from running flat out.
+	minWidgetInterval = time.Second
+	// defaultWidgetWidth and maxWidgetWidth bound a widget's segment, in cells.
+	defaultWidgetWidth = 30
+	maxWidgetWidth     = 80
+)
+
+// StatusWidget is a segment of the status bar at the bottom of the screen,
+// like a tmux status-right script: the first line Command prints, run with
+// `sh -c` in the active worktree every Interval, cut to MaxWidth cells.
+type StatusWidget struct {
+	// Name labels the widget in logs and when its command fails.
+	Name     string
+	Command  string
+	Interval time.Duration
+	MaxWidth int
+}
+
+type statusBarRaw struct {
+	Widgets []statusWidgetRaw `json:"widgets"`
+}
+
+type statusWidgetRaw struct {
+	Name    string `json:"name"`
+	Command string `json:"command"`
+	// Interval is a Go duration ("30s", "5m").
+	Interval string `json:"interval"`
+	MaxWidth int    `json:"max_width"`
+}
+
+// resolveStatusWidgets drops widgets without a command and bounds the
+// others' intervals and widths.
+func resolveStatusWidgets(raw statusBarRaw) []StatusWidget {
+	var widgets []StatusWidget
+	for i, entry := range raw.Widgets {
+		w := StatusWidget{
+			Name:     strings.TrimSpace(entry.Name),
+			Command:  strings.TrimSpace(entry.Command),
+			Interval: defaultWidgetInterval,
+			MaxWidth: entry.MaxWidth,
+		}
+		if w.Command == "" {
+			logging.Warn("config: status_bar widget %d has no command; skipping it", i+1)
+			continue
+		}
+		if s := strings.TrimSpace(entry.Interval); s != "" {
+			d, err := time.ParseDuration(s)
+			if err != nil {
+				logging.Warn("config: status_bar widget %d: interval %q: %v; using %s", i+1, s, err, defaultWidgetInterval)
+			} else {
+				w.Interval = max(d, minWidgetInterval)
+			}
+		}
+		if w.MaxWidth <= 0 {
+			w.MaxWidth = defaultWidgetWidth
+		}
+		w.MaxWidth = min(w.MaxWidth, maxWidgetWidth)
+		widgets = append(widgets, w)
+	}
+	return widgets
+}
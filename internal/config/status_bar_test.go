@@ -0,0 +1,45 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestDefaultConfigLoadsStatusWidgets(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	configPath := filepath.Join(home, ".amux", "config.json")
+	if err := os.MkdirAll(filepath.Dir(configPath), 0o755); err != nil {
+		t.Fatalf("MkdirAll() error = %v", err)
+	}
+	content := `{"status_bar": {"widgets": [
+		{"name": "k8s", "command": " kubectl config current-context ", "interval": "10s"},
+		{"name": "empty"},
+		{"command": "date", "interval": "10ms", "max_width": 500},
+		{"command": "uptime", "interval": "soon"}
+	]}}`
+	if err := os.WriteFile(configPath, []byte(content), 0o644); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	cfg, err := DefaultConfig()
+	if err != nil {
+		t.Fatalf("DefaultConfig() error = %v", err)
+	}
+	want := []StatusWidget{
+		{Name: "k8s", Command: "kubectl config current-context", Interval: 10 * time.Second, MaxWidth: defaultWidgetWidth},
+		{Command: "date", Interval: minWidgetInterval, MaxWidth: maxWidgetWidth},
+		{Command: "uptime", Interval: defaultWidgetInterval, MaxWidth: defaultWidgetWidth},
+	}
+	if len(cfg.StatusWidgets) != len(want) {
+		t.Fatalf("StatusWidgets = %+v, want %+v", cfg.StatusWidgets, want)
+	}
+	for i := range want {
+		if cfg.StatusWidgets[i] != want[i] {
+			t.Errorf("StatusWidgets[%d] = %+v, want %+v", i, cfg.StatusWidgets[i], want[i])
+		}
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/andyrewlee/amux/internal/fsatomic"
 )
@@ -20,28 +21,116 @@ type UISettings struct {
 	// NotifyOnDone rings a terminal bell when an agent finishes. Default off so
 	// existing users are not surprised by sound.
 	NotifyOnDone bool
+	// NotifyOnAttention notifies when an agent needs attention, such as
+	// reaching a resource limit. NotifyOnExit notifies when an agent's
+	// session ends. Both default off.
+	NotifyOnAttention bool
+	NotifyOnExit      bool
+	// Notifications picks how notifications are delivered: "bell" (the
+	// default), "terminal" (OSC 9/777), or "auto" (a desktop notification,
+	// falling back to the terminal).
+	Notifications string
+	// NotifyDigestWindow batches notifications: after one is delivered,
+	// those arriving within this duration (such as "30s") are delivered
+	// together as one digest. Empty delivers each as it comes.
+	NotifyDigestWindow string
+	// NotifyRateLimits caps the notifications delivered per minute on each
+	// backend; the excess joins the next digest.
+	NotifyRateLimits NotifyRateLimits
+	// NotifyQuietHours holds notifications during a daily "HH:MM-HH:MM"
+	// span in local time and delivers them as one digest when it ends.
+	NotifyQuietHours string
+	// FocusFollowsAttention moves focus from an idle dashboard to the one
+	// agent that starts needing attention. Default off.
+	FocusFollowsAttention bool
+	// LatencyProfile tunes PTY flush and render timing ("snappy", "balanced",
+	// "battery"). Empty means balanced.
+	LatencyProfile string
+	// OutputPauseBytes and OutputResumeBytes are the flow-control watermarks
+	// for agent tab output: past OutputPauseBytes of unrendered output amux
+	// stops reading the tab's PTY until the backlog drains to
+	// OutputResumeBytes. Zero uses the default; a negative pause disables it.
+	OutputPauseBytes  int
+	OutputResumeBytes int
+	// PasteConfirmBytes is the paste size that asks for confirmation before
+	// writing to a terminal. Zero uses the default; negative never asks.
+	PasteConfirmBytes int
+	// ConfirmMultilinePaste previews a multi-line paste into an agent tab
+	// and waits for Enter before sending it. Default on.
+	ConfirmMultilinePaste bool
+	// ReducedMotion turns off spinners and blinking and halves the render
+	// rate, for motion sensitivity and slow SSH links.
+	ReducedMotion bool
+	// LowBandwidth is "on", "off", or "auto" (on inside an SSH session).
+	// Empty means off.
+	LowBandwidth string
+	// TerminalTitle picks the host terminal's window title: "agent" (the
+	// default) shows the focused agent's own title, "status" summarizes
+	// the agents, such as "amux: 2 agents need attention", and "off"
+	// leaves the title alone.
+	TerminalTitle string
+	// TerminalBadge shows an attention indicator on amux's terminal tab
+	// (OSC 9;4) while agents need attention. Default off.
+	TerminalBadge bool
+}
+
+// NotifyRateLimits is the most notifications per minute for each delivery
+// backend. Zero is unlimited.
+type NotifyRateLimits struct {
+	Bell     int
+	Terminal int
+	Auto     int
+}
+
+// For returns the limit for the backend named as in UISettings.Notifications.
+func (l NotifyRateLimits) For(backend string) int {
+	switch strings.ToLower(strings.TrimSpace(backend)) {
+	case "terminal":
+		return l.Terminal
+	case "auto", "desktop":
+		return l.Auto
+	}
+	return l.Bell
 }
 
 func defaultUISettings() UISettings {
 	return UISettings{
-		ShowKeymapHints:  false,
-		Theme:            "gruvbox",
-		TmuxServer:       "",
-		TmuxConfigPath:   "",
-		TmuxSyncInterval: "",
-		NotifyOnDone:     false,
+		ShowKeymapHints:       false,
+		Theme:                 "gruvbox",
+		TmuxServer:            "",
+		TmuxConfigPath:        "",
+		TmuxSyncInterval:      "",
+		NotifyOnDone:          false,
+		LatencyProfile:        "",
+		ConfirmMultilinePaste: true,
 	}
 }
 
 // uiSettingsRaw is the on-disk shape of the "ui" config section. Pointer
 // fields distinguish "absent" from zero values.
 type uiSettingsRaw struct {
-	ShowKeymapHints  *bool   `json:"show_keymap_hints"`
-	Theme            *string `json:"theme"`
-	TmuxServer       *string `json:"tmux_server"`
-	TmuxConfigPath   *string `json:"tmux_config"`
-	TmuxSyncInterval *string `json:"tmux_sync_interval"`
-	NotifyOnDone     *bool   `json:"notify_on_done"`
+	ShowKeymapHints   *bool          `json:"show_keymap_hints"`
+	Theme             *string        `json:"theme"`
+	TmuxServer        *string        `json:"tmux_server"`
+	TmuxConfigPath    *string        `json:"tmux_config"`
+	TmuxSyncInterval  *string        `json:"tmux_sync_interval"`
+	NotifyOnDone      *bool          `json:"notify_on_done"`
+	NotifyOnAttention *bool          `json:"notify_on_attention"`
+	NotifyOnExit      *bool          `json:"notify_on_exit"`
+	Notifications     *string        `json:"notifications"`
+	NotifyDigest      *string        `json:"notify_digest_window"`
+	NotifyRateLimits  map[string]int `json:"notify_rate_limits"`
+	NotifyQuietHours  *string        `json:"notify_quiet_hours"`
+	FocusFollows      *bool          `json:"focus_follows_attention"`
+	LatencyProfile    *string        `json:"latency_profile"`
+	OutputPauseBytes  *int           `json:"output_pause_bytes"`
+	OutputResumeBytes *int           `json:"output_resume_bytes"`
+	PasteConfirmBytes *int           `json:"paste_confirm_bytes"`
+	ConfirmMultiline  *bool          `json:"confirm_multiline_paste"`
+	ReducedMotion     *bool          `json:"reduced_motion"`
+	LowBandwidth      *string        `json:"low_bandwidth"`
+	TerminalTitle     *string        `json:"terminal_title"`
+	TerminalBadge     *bool          `json:"terminal_badge"`
 }
 
 // applyUISettings overlays the parsed config-file section onto the defaults.
@@ -64,6 +153,61 @@ func applyUISettings(settings UISettings, raw uiSettingsRaw) UISettings {
 	if raw.NotifyOnDone != nil {
 		settings.NotifyOnDone = *raw.NotifyOnDone
 	}
+	if raw.NotifyOnAttention != nil {
+		settings.NotifyOnAttention = *raw.NotifyOnAttention
+	}
+	if raw.NotifyOnExit != nil {
+		settings.NotifyOnExit = *raw.NotifyOnExit
+	}
+	if raw.Notifications != nil {
+		settings.Notifications = *raw.Notifications
+	}
+	if raw.NotifyDigest != nil {
+		settings.NotifyDigestWindow = *raw.NotifyDigest
+	}
+	for backend, limit := range raw.NotifyRateLimits {
+		switch strings.ToLower(backend) {
+		case "bell":
+			settings.NotifyRateLimits.Bell = limit
+		case "terminal":
+			settings.NotifyRateLimits.Terminal = limit
+		case "auto", "desktop":
+			settings.NotifyRateLimits.Auto = limit
+		}
+	}
+	if raw.NotifyQuietHours != nil {
+		settings.NotifyQuietHours = *raw.NotifyQuietHours
+	}
+	if raw.FocusFollows != nil {
+		settings.FocusFollowsAttention = *raw.FocusFollows
+	}
+	if raw.LatencyProfile != nil {
+		settings.LatencyProfile = *raw.LatencyProfile
+	}
+	if raw.OutputPauseBytes != nil {
+		settings.OutputPauseBytes = *raw.OutputPauseBytes
+	}
+	if raw.OutputResumeBytes != nil {
+		settings.OutputResumeBytes = *raw.OutputResumeBytes
+	}
+	if raw.PasteConfirmBytes != nil {
+		settings.PasteConfirmBytes = *raw.PasteConfirmBytes
+	}
+	if raw.ConfirmMultiline != nil {
+		settings.ConfirmMultilinePaste = *raw.ConfirmMultiline
+	}
+	if raw.ReducedMotion != nil {
+		settings.ReducedMotion = *raw.ReducedMotion
+	}
+	if raw.LowBandwidth != nil {
+		settings.LowBandwidth = *raw.LowBandwidth
+	}
+	if raw.TerminalTitle != nil {
+		settings.TerminalTitle = *raw.TerminalTitle
+	}
+	if raw.TerminalBadge != nil {
+		settings.TerminalBadge = *raw.TerminalBadge
+	}
 	return settings
 }
 
@@ -93,6 +237,26 @@ func saveUISettings(path string, settings UISettings) error {
 	ui["tmux_config"] = settings.TmuxConfigPath
 	ui["tmux_sync_interval"] = settings.TmuxSyncInterval
 	ui["notify_on_done"] = settings.NotifyOnDone
+	ui["notify_on_attention"] = settings.NotifyOnAttention
+	ui["notify_on_exit"] = settings.NotifyOnExit
+	ui["notifications"] = settings.Notifications
+	ui["notify_digest_window"] = settings.NotifyDigestWindow
+	if limits := settings.NotifyRateLimits; limits != (NotifyRateLimits{}) {
+		ui["notify_rate_limits"] = map[string]int{"bell": limits.Bell, "terminal": limits.Terminal, "auto": limits.Auto}
+	} else {
+		delete(ui, "notify_rate_limits")
+	}
+	ui["notify_quiet_hours"] = settings.NotifyQuietHours
+	ui["focus_follows_attention"] = settings.FocusFollowsAttention
+	ui["latency_profile"] = settings.LatencyProfile
+	ui["output_pause_bytes"] = settings.OutputPauseBytes
+	ui["output_resume_bytes"] = settings.OutputResumeBytes
+	ui["paste_confirm_bytes"] = settings.PasteConfirmBytes
+	ui["confirm_multiline_paste"] = settings.ConfirmMultilinePaste
+	ui["reduced_motion"] = settings.ReducedMotion
+	ui["low_bandwidth"] = settings.LowBandwidth
+	ui["terminal_title"] = settings.TerminalTitle
+	ui["terminal_badge"] = settings.TerminalBadge
 	payload["ui"] = ui
 
 	// Crash-safe write (temp + fsync + atomic rename) so a crash mid-save can't
@@ -39,12 +39,28 @@ func TestSaveUISettingsWritesAllFields(t *testing.T) {
 		{
 			name: "fully populated",
 			settings: UISettings{
-				ShowKeymapHints:  true,
-				Theme:            "dracula",
-				TmuxServer:       "amux-test",
-				TmuxConfigPath:   "/tmp/tmux.conf",
-				TmuxSyncInterval: "5s",
-				NotifyOnDone:     true,
+				ShowKeymapHints:       true,
+				Theme:                 "dracula",
+				TmuxServer:            "amux-test",
+				TmuxConfigPath:        "/tmp/tmux.conf",
+				TmuxSyncInterval:      "5s",
+				NotifyOnDone:          true,
+				NotifyOnAttention:     true,
+				NotifyOnExit:          true,
+				Notifications:         "auto",
+				NotifyDigestWindow:    "30s",
+				NotifyRateLimits:      NotifyRateLimits{Terminal: 10, Auto: 4},
+				NotifyQuietHours:      "22:00-07:30",
+				FocusFollowsAttention: true,
+				LatencyProfile:        "battery",
+				OutputPauseBytes:      2 << 20,
+				OutputResumeBytes:     512 << 10,
+				PasteConfirmBytes:     -1,
+				ReducedMotion:         true,
+				LowBandwidth:          "auto",
+				ConfirmMultilinePaste: true,
+				TerminalTitle:         "status",
+				TerminalBadge:         true,
 			},
 		},
 		{
@@ -93,6 +109,33 @@ func TestSaveUISettingsWritesAllFields(t *testing.T) {
 			if got := ui["notify_on_done"]; got != tt.settings.NotifyOnDone {
 				t.Errorf("notify_on_done = %#v, want %#v", got, tt.settings.NotifyOnDone)
 			}
+			if got := ui["notifications"]; got != tt.settings.Notifications {
+				t.Errorf("notifications = %#v, want %#v", got, tt.settings.Notifications)
+			}
+			if got := ui["notify_quiet_hours"]; got != tt.settings.NotifyQuietHours {
+				t.Errorf("notify_quiet_hours = %#v, want %#v", got, tt.settings.NotifyQuietHours)
+			}
+			if got := ui["latency_profile"]; got != tt.settings.LatencyProfile {
+				t.Errorf("latency_profile = %#v, want %#v", got, tt.settings.LatencyProfile)
+			}
+			if got := ui["output_pause_bytes"]; got != float64(tt.settings.OutputPauseBytes) {
+				t.Errorf("output_pause_bytes = %#v, want %#v", got, tt.settings.OutputPauseBytes)
+			}
+			if got := ui["reduced_motion"]; got != tt.settings.ReducedMotion {
+				t.Errorf("reduced_motion = %#v, want %#v", got, tt.settings.ReducedMotion)
+			}
+			if got := ui["low_bandwidth"]; got != tt.settings.LowBandwidth {
+				t.Errorf("low_bandwidth = %#v, want %#v", got, tt.settings.LowBandwidth)
+			}
+			if got := ui["terminal_title"]; got != tt.settings.TerminalTitle {
+				t.Errorf("terminal_title = %#v, want %#v", got, tt.settings.TerminalTitle)
+			}
+			if got := ui["terminal_badge"]; got != tt.settings.TerminalBadge {
+				t.Errorf("terminal_badge = %#v, want %#v", got, tt.settings.TerminalBadge)
+			}
+			if got := ui["confirm_multiline_paste"]; got != tt.settings.ConfirmMultilinePaste {
+				t.Errorf("confirm_multiline_paste = %#v, want %#v", got, tt.settings.ConfirmMultilinePaste)
+			}
 
 			// What we wrote must round-trip back through the read path.
 			file, err := readConfigFile(path)
@@ -345,3 +388,14 @@ func TestConfigSaveUISettings(t *testing.T) {
 		}
 	})
 }
+
+func TestApplyUISettingsNotifyRateLimits(t *testing.T) {
+	raw := uiSettingsRaw{NotifyRateLimits: map[string]int{"desktop": 3, "Terminal": 8, "pager": 1}}
+	limits := applyUISettings(defaultUISettings(), raw).NotifyRateLimits
+	if limits != (NotifyRateLimits{Terminal: 8, Auto: 3}) {
+		t.Fatalf("NotifyRateLimits = %+v, want desktop read as auto and unknown keys ignored", limits)
+	}
+	if limits.For("auto") != 3 || limits.For("terminal") != 8 || limits.For("") != 0 {
+		t.Fatalf("For() = %d, %d, %d", limits.For("auto"), limits.For("terminal"), limits.For(""))
+	}
+}
@@ -0,0 +1,257 @@
+// Package container runs a worktree's dev environment in a container, defined
+// by its devcontainer.json or the "container" spec in .amux/workspaces.json.
+//
+// amux starts one long-lived container per worktree with docker or podman,
+// mounting the worktree (and the repository's .git directory, which a linked
+// worktree commits into) and mapping the workspace's port range. Tabs then
+// run inside it through "exec", so they come and go without restarting it.
+package container
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/andyrewlee/amux/internal/data"
+	"github.com/andyrewlee/amux/internal/shellutil"
+)
+
+// Kind names a container runtime CLI.
+type Kind string
+
+const (
+	// None means no supported runtime is installed.
+	None   Kind = ""
+	Docker Kind = "docker"
+	Podman Kind = "podman"
+)
+
+// RuntimeEnvVar picks the runtime when both are installed.
+const RuntimeEnvVar = "AMUX_CONTAINER_RUNTIME"
+
+// DefaultWorkdir is where the worktree is mounted when the spec doesn't say.
+const DefaultWorkdir = "/workspace"
+
+// LabelWorkspace labels each container with its workspace's ID.
+const LabelWorkspace = "amux.workspace"
+
+// ErrUnavailable is returned when neither docker nor podman is installed.
+var ErrUnavailable = errors.New("containers need docker or podman, and neither was found")
+
+var (
+	lookPath = exec.LookPath
+	// run runs a runtime command, with env added to amux's environment, and
+	// returns its combined output; tests replace it.
+	run = func(ctx context.Context, kind Kind, env []string, args ...string) (string, error) {
+		cmd := exec.CommandContext(ctx, string(kind), args...)
+		if len(env) > 0 {
+			cmd.Env = append(os.Environ(), env...)
+		}
+		out, err := cmd.CombinedOutput()
+		return strings.TrimSpace(string(out)), err
+	}
+)
+
+// Detect returns the runtime to use: the one RuntimeEnvVar names, else
+// docker, else podman.
+func Detect() Kind {
+	candidates := []Kind{Docker, Podman}
+	if want := Kind(os.Getenv(RuntimeEnvVar)); want == Docker || want == Podman {
+		candidates = []Kind{want}
+	}
+	for _, kind := range candidates {
+		if _, err := lookPath(string(kind)); err == nil {
+			return kind
+		}
+	}
+	return None
+}
+
+// Name returns the container name for a workspace.
+func Name(id data.WorkspaceID) string {
+	return "amux-" + string(id)
+}
+
+// Options are the host-side settings of a container.
+type Options struct {
+	Name string
+	// Workspace labels the container.
+	Workspace data.WorkspaceID
+	// Root is the worktree and GitDir the repository's .git directory,
+	// mounted at its own path so the worktree's .git file resolves.
+	Root   string
+	GitDir string
+	// PortStart and PortEnd are the workspace's host port range.
+	PortStart, PortEnd int
+	// NoNetwork runs the container without a network, and so without ports.
+	NoNetwork bool
+	// Env is set in the container under the spec's own.
+	Env map[string]string
+}
+
+// Start builds the spec's image when it has a Dockerfile, replaces any
+// container left under opts.Name, and starts a new one that idles until
+// Stop, then runs the spec's PostCreate in it.
+func Start(ctx context.Context, kind Kind, spec *Spec, opts Options) (*data.Container, error) {
+	if kind == None {
+		return nil, ErrUnavailable
+	}
+	if err := spec.Validate(); err != nil {
+		return nil, err
+	}
+	image := spec.Image
+	if spec.Dockerfile != "" {
+		image = opts.Name
+		context := spec.Context
+		if context == "" {
+			context = filepath.Dir(filepath.FromSlash(spec.Dockerfile))
+		}
+		if out, err := run(ctx, kind, nil, "build", "-t", image,
+			"-f", filepath.Join(opts.Root, filepath.FromSlash(spec.Dockerfile)),
+			filepath.Join(opts.Root, filepath.FromSlash(context))); err != nil {
+			return nil, commandError("building "+spec.Dockerfile, out, err)
+		}
+	}
+	ref := &data.Container{Runtime: string(kind), Name: opts.Name, Workdir: spec.Workdir, User: spec.User}
+	if ref.Workdir == "" {
+		ref.Workdir = DefaultWorkdir
+	}
+	ports, err := portMappings(spec.Ports, opts)
+	if err != nil {
+		return nil, err
+	}
+	ref.Ports = ports
+
+	_, _ = run(ctx, kind, nil, "rm", "-f", opts.Name)
+	args := []string{"run", "-d", "--name", opts.Name, "--init",
+		"--label", LabelWorkspace + "=" + string(opts.Workspace),
+		"-v", opts.Root + ":" + ref.Workdir, "-w", ref.Workdir}
+	if opts.GitDir != "" {
+		args = append(args, "-v", opts.GitDir+":"+opts.GitDir)
+	}
+	if opts.NoNetwork {
+		args = append(args, "--network", "none")
+	}
+	for _, p := range ports {
+		args = append(args, "-p", "127.0.0.1:"+p)
+	}
+	// Only the names go on the command line, where ps and the runtime's
+	// inspect would show them; the values, workspace secrets among them,
+	// are passed through the runtime's own environment.
+	env := envList(opts.Env, spec.Env)
+	for _, kv := range env {
+		key, _, _ := strings.Cut(kv, "=")
+		args = append(args, "-e", key)
+	}
+	// Idle until stopped, like the devcontainer CLI's own entrypoint.
+	args = append(args, "--entrypoint", "/bin/sh", image,
+		"-c", `trap "exit 0" TERM; while sleep 3600 & wait $!; do :; done`)
+	if out, err := run(ctx, kind, env, args...); err != nil {
+		return nil, commandError("starting "+image, out, err)
+	}
+	if spec.PostCreate != "" {
+		if out, err := run(ctx, kind, nil, append(execArgs(ref, false), "/bin/sh", "-c", spec.PostCreate)...); err != nil {
+			_, _ = run(ctx, kind, nil, "rm", "-f", opts.Name)
+			return nil, commandError("running the post-create command", out, err)
+		}
+	}
+	return ref, nil
+}
+
+// portMappings maps ports, in order, to the host range, or the whole range
+// to itself when there are none.
+func portMappings(ports []int, opts Options) ([]string, error) {
+	if opts.NoNetwork || opts.PortStart <= 0 {
+		return nil, nil
+	}
+	if len(ports) == 0 {
+		r := fmt.Sprintf("%d-%d", opts.PortStart, opts.PortEnd)
+		return []string{r + ":" + r}, nil
+	}
+	if n := opts.PortEnd - opts.PortStart + 1; len(ports) > n {
+		return nil, fmt.Errorf("the container forwards %d ports, more than the workspace's %d", len(ports), n)
+	}
+	mappings := make([]string, len(ports))
+	for i, p := range ports {
+		mappings[i] = strconv.Itoa(opts.PortStart+i) + ":" + strconv.Itoa(p)
+	}
+	return mappings, nil
+}
+
+// envList merges base and override into sorted KEY=value pairs.
+func envList(base, override map[string]string) []string {
+	merged := make(map[string]string, len(base)+len(override))
+	for k, v := range base {
+		merged[k] = v
+	}
+	for k, v := range override {
+		merged[k] = v
+	}
+	list := make([]string, 0, len(merged))
+	for k, v := range merged {
+		list = append(list, k+"="+v)
+	}
+	sort.Strings(list)
+	return list
+}
+
+func commandError(what, out string, err error) error {
+	if lines := strings.Split(out, "\n"); out != "" {
+		return fmt.Errorf("%s: %w: %s", what, err, lines[len(lines)-1])
+	}
+	return fmt.Errorf("%s: %w", what, err)
+}
+
+// Stop removes the container.
+func Stop(ctx context.Context, ref *data.Container) error {
+	if out, err := run(ctx, Kind(ref.Runtime), nil, "rm", "-f", ref.Name); err != nil {
+		return commandError("removing "+ref.Name, out, err)
+	}
+	return nil
+}
+
+// Running reports whether the container is up.
+func Running(ctx context.Context, ref *data.Container) bool {
+	out, err := run(ctx, Kind(ref.Runtime), nil, "inspect", "-f", "{{.State.Running}}", ref.Name)
+	return err == nil && out == "true"
+}
+
+// Exec returns a shell command line that runs command, itself a shell
+// command line, in the container with a terminal.
+func Exec(ref *data.Container, command string) string {
+	args := append([]string{ref.Runtime}, execArgs(ref, true)...)
+	return joinQuoted(append(args, "/bin/sh", "-lc", command))
+}
+
+// Shell returns a shell command line that opens a login shell in the
+// container: bash when the image has it, else sh.
+func Shell(ref *data.Container) string {
+	return Exec(ref, "if command -v bash >/dev/null 2>&1; then exec bash -l; else exec sh -l; fi")
+}
+
+// execArgs are the runtime arguments that run a command in the container,
+// with a terminal when tty is set.
+func execArgs(ref *data.Container, tty bool) []string {
+	args := []string{"exec"}
+	if tty {
+		args = append(args, "-it", "-e", "TERM", "-e", "COLORTERM")
+	}
+	if ref.User != "" {
+		args = append(args, "-u", ref.User)
+	}
+	return append(args, "-w", ref.Workdir, ref.Name)
+}
+
+func joinQuoted(args []string) string {
+	quoted := make([]string, len(args))
+	for i, arg := range args {
+		quoted[i] = shellutil.ShellQuote(arg)
+	}
+	return strings.Join(quoted, " ")
+}
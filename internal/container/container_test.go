@@ -0,0 +1,122 @@
+package container
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/andyrewlee/amux/internal/data"
+)
+
+func TestParseDevcontainer(t *testing.T) {
+	raw := []byte(`{
+		// Comments and trailing commas are allowed.
+		"name": "app /* not a comment */",
+		"build": {"dockerfile": "Dockerfile", "context": ".."},
+		"forwardPorts": [3000, "5432",],
+		"containerEnv": {"A": "1"},
+		"remoteEnv": {"A": "2", "B": "3"},
+		"remoteUser": "node",
+		/* The whole command line is quoted. */
+		"postCreateCommand": ["npm", "ci", "--no-audit"],
+	}`)
+	spec, err := ParseDevcontainer(raw, ".devcontainer")
+	if err != nil {
+		t.Fatalf("ParseDevcontainer: %v", err)
+	}
+	if spec.Dockerfile != ".devcontainer/Dockerfile" || spec.Context != "." {
+		t.Fatalf("build = %q in %q", spec.Dockerfile, spec.Context)
+	}
+	if len(spec.Ports) != 2 || spec.Ports[0] != 3000 || spec.Ports[1] != 5432 {
+		t.Fatalf("Ports = %v", spec.Ports)
+	}
+	if spec.Env["A"] != "2" || spec.Env["B"] != "3" || spec.User != "node" {
+		t.Fatalf("Env = %v, User = %q", spec.Env, spec.User)
+	}
+	if spec.PostCreate != "'npm' 'ci' '--no-audit'" {
+		t.Fatalf("PostCreate = %q", spec.PostCreate)
+	}
+
+	if _, err := ParseDevcontainer([]byte(`{"image": "x", "forwardPorts": ["db:5432"]}`), "."); err == nil {
+		t.Fatal("forwarding another container's port should be refused")
+	}
+	if _, err := ParseDevcontainer([]byte(`{"build": {"dockerfile": "../../Dockerfile"}}`), ".devcontainer"); err == nil {
+		t.Fatal("a Dockerfile outside the worktree should be refused")
+	}
+}
+
+func TestLoadDevcontainer(t *testing.T) {
+	root := t.TempDir()
+	if _, err := LoadDevcontainer(root); !errors.Is(err, ErrNoSpec) {
+		t.Fatalf("LoadDevcontainer(empty) = %v, want ErrNoSpec", err)
+	}
+	if err := os.WriteFile(filepath.Join(root, ".devcontainer.json"), []byte(`{"image": "golang:1.24"}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	spec, err := LoadDevcontainer(root)
+	if err != nil || spec.Image != "golang:1.24" || spec.Source != ".devcontainer.json" {
+		t.Fatalf("LoadDevcontainer = %+v, %v", spec, err)
+	}
+}
+
+func TestStart(t *testing.T) {
+	var calls []string
+	var runEnv []string
+	old := run
+	t.Cleanup(func() { run = old })
+	run = func(_ context.Context, kind Kind, env []string, args ...string) (string, error) {
+		calls = append(calls, string(kind)+" "+strings.Join(args, " "))
+		if len(env) > 0 {
+			runEnv = env
+		}
+		return "", nil
+	}
+	spec := &Spec{Dockerfile: "Dockerfile", Ports: []int{3000}, Env: map[string]string{"B": "spec"}, User: "dev", PostCreate: "make deps"}
+	opts := Options{Name: "amux-abc", Workspace: "abc", Root: "/wt", GitDir: "/repo/.git", PortStart: 6200, PortEnd: 6209, Env: map[string]string{"A": "1", "B": "amux", "API_TOKEN": "s3cret"}}
+
+	ref, err := Start(context.Background(), Docker, spec, opts)
+	if err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	if ref.Name != "amux-abc" || ref.Workdir != DefaultWorkdir || len(ref.Ports) != 1 || ref.Ports[0] != "6200:3000" {
+		t.Fatalf("ref = %+v", ref)
+	}
+	if len(calls) != 4 || !strings.HasPrefix(calls[0], "docker build -t amux-abc -f /wt/Dockerfile /wt") || calls[1] != "docker rm -f amux-abc" {
+		t.Fatalf("calls = %q", calls)
+	}
+	for _, want := range []string{"-v /wt:/workspace", "-v /repo/.git:/repo/.git", "-p 127.0.0.1:6200:3000", "-e A -e API_TOKEN -e B", "--entrypoint /bin/sh amux-abc"} {
+		if !strings.Contains(calls[2], want) {
+			t.Fatalf("run = %q, want %q", calls[2], want)
+		}
+	}
+	if strings.Contains(calls[2], "s3cret") || strings.Join(runEnv, " ") != "A=1 API_TOKEN=s3cret B=spec" {
+		t.Fatalf("run = %q with env %q, want the values kept off the command line", calls[2], runEnv)
+	}
+	if calls[3] != "docker exec -u dev -w /workspace amux-abc /bin/sh -c make deps" {
+		t.Fatalf("post-create = %q", calls[3])
+	}
+
+	calls = nil
+	opts.NoNetwork = true
+	if _, err := Start(context.Background(), Docker, &Spec{Image: "alpine"}, opts); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	if !strings.Contains(calls[1], "--network none") || strings.Contains(calls[1], " -p ") {
+		t.Fatalf("run without a network = %q", calls[1])
+	}
+
+	opts.NoNetwork, opts.PortEnd = false, 6200
+	if _, err := Start(context.Background(), Docker, &Spec{Image: "alpine", Ports: []int{1, 2}}, opts); err == nil {
+		t.Fatal("more ports than the range holds should be refused")
+	}
+}
+
+func TestExec(t *testing.T) {
+	ref := &data.Container{Runtime: "podman", Name: "amux-abc", Workdir: "/workspace"}
+	if got, want := Exec(ref, "claude"), "'podman' 'exec' '-it' '-e' 'TERM' '-e' 'COLORTERM' '-w' '/workspace' 'amux-abc' '/bin/sh' '-lc' 'claude'"; got != want {
+		t.Fatalf("Exec = %s, want %s", got, want)
+	}
+}
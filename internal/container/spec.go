@@ -0,0 +1,238 @@
+package container
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/andyrewlee/amux/internal/shellutil"
+)
+
+// ErrNoSpec is returned when a worktree has no devcontainer.json.
+var ErrNoSpec = errors.New("no devcontainer.json")
+
+// devcontainerFiles are where a devcontainer.json is looked for, relative to
+// the worktree root, in order.
+var devcontainerFiles = []string{".devcontainer/devcontainer.json", ".devcontainer.json"}
+
+// Spec describes a worktree's dev container. Its JSON form is the "container"
+// object of .amux/workspaces.json; LoadDevcontainer reads the same fields
+// from a devcontainer.json.
+type Spec struct {
+	// Image is the image to run, unless Dockerfile builds one.
+	Image string `json:"image,omitempty"`
+	// Dockerfile and Context build the image, relative to the worktree root.
+	// Context defaults to the Dockerfile's directory.
+	Dockerfile string `json:"dockerfile,omitempty"`
+	Context    string `json:"context,omitempty"`
+	// Workdir is where the worktree is mounted; DefaultWorkdir when empty.
+	Workdir string `json:"workdir,omitempty"`
+	// Ports are container ports mapped, in order, to the workspace's port
+	// range on the host. Without any the whole range is mapped as is.
+	Ports []int             `json:"ports,omitempty"`
+	Env   map[string]string `json:"env,omitempty"`
+	// User runs tabs and PostCreate in the container; the image's when empty.
+	User string `json:"user,omitempty"`
+	// PostCreate runs once in a new container, before any tab.
+	PostCreate string `json:"post-create,omitempty"`
+
+	// Source is the file the spec was read from, for display.
+	Source string `json:"-"`
+}
+
+// Validate checks that s names an image or a Dockerfile inside the worktree.
+func (s *Spec) Validate() error {
+	if s.Image == "" && s.Dockerfile == "" {
+		return errors.New("container spec needs an image or a dockerfile")
+	}
+	for _, p := range []string{s.Dockerfile, s.Context} {
+		if p != "" && !filepath.IsLocal(filepath.FromSlash(p)) {
+			return fmt.Errorf("container spec path %q must stay inside the worktree", p)
+		}
+	}
+	if s.Workdir != "" && !path.IsAbs(s.Workdir) {
+		return fmt.Errorf("container workdir %q must be absolute", s.Workdir)
+	}
+	for _, p := range s.Ports {
+		if p < 1 || p > 65535 {
+			return fmt.Errorf("container port %d is out of range", p)
+		}
+	}
+	return nil
+}
+
+// devcontainer is the part of the devcontainer.json format amux uses.
+// Features, mounts, and runArgs are not supported: amux mounts only the
+// worktree and never passes options to the runtime on a repository's say.
+type devcontainer struct {
+	Image string `json:"image"`
+	Build struct {
+		Dockerfile string `json:"dockerfile"`
+		Context    string `json:"context"`
+	} `json:"build"`
+	// DockerFile and Context are the older top-level spellings.
+	DockerFile        string            `json:"dockerFile"`
+	Context           string            `json:"context"`
+	WorkspaceFolder   string            `json:"workspaceFolder"`
+	ForwardPorts      []json.RawMessage `json:"forwardPorts"`
+	ContainerEnv      map[string]string `json:"containerEnv"`
+	RemoteEnv         map[string]string `json:"remoteEnv"`
+	RemoteUser        string            `json:"remoteUser"`
+	ContainerUser     string            `json:"containerUser"`
+	PostCreateCommand json.RawMessage   `json:"postCreateCommand"`
+}
+
+// LoadDevcontainer reads the devcontainer.json in the worktree at root.
+func LoadDevcontainer(root string) (*Spec, error) {
+	for _, name := range devcontainerFiles {
+		raw, err := os.ReadFile(filepath.Join(root, filepath.FromSlash(name)))
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		spec, err := ParseDevcontainer(raw, path.Dir(name))
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+		spec.Source = name
+		return spec, nil
+	}
+	return nil, ErrNoSpec
+}
+
+// ParseDevcontainer parses a devcontainer.json, whose build paths are
+// relative to dir, the file's directory relative to the worktree root.
+func ParseDevcontainer(raw []byte, dir string) (*Spec, error) {
+	var dc devcontainer
+	if err := json.Unmarshal(standardize(raw), &dc); err != nil {
+		return nil, err
+	}
+	spec := &Spec{Image: dc.Image, Workdir: dc.WorkspaceFolder}
+	dockerfile, context := dc.Build.Dockerfile, dc.Build.Context
+	if dockerfile == "" {
+		dockerfile, context = dc.DockerFile, dc.Context
+	}
+	if dockerfile != "" {
+		spec.Dockerfile = path.Join(dir, dockerfile)
+		if context == "" {
+			context = "."
+		}
+		spec.Context = path.Join(dir, context)
+		spec.Image = ""
+	}
+	for _, p := range dc.ForwardPorts {
+		port, err := forwardPort(p)
+		if err != nil {
+			return nil, err
+		}
+		spec.Ports = append(spec.Ports, port)
+	}
+	if len(dc.ContainerEnv)+len(dc.RemoteEnv) > 0 {
+		spec.Env = make(map[string]string, len(dc.ContainerEnv)+len(dc.RemoteEnv))
+		for k, v := range dc.ContainerEnv {
+			spec.Env[k] = v
+		}
+		for k, v := range dc.RemoteEnv {
+			spec.Env[k] = v
+		}
+	}
+	spec.User = dc.RemoteUser
+	if spec.User == "" {
+		spec.User = dc.ContainerUser
+	}
+	command, err := commandLine(dc.PostCreateCommand)
+	if err != nil {
+		return nil, fmt.Errorf("postCreateCommand: %w", err)
+	}
+	spec.PostCreate = command
+	return spec, spec.Validate()
+}
+
+// forwardPort reads a forwardPorts entry: a port number, or a string naming
+// one. "host:port" entries forward another container's port and aren't
+// supported.
+func forwardPort(raw json.RawMessage) (int, error) {
+	var port int
+	if err := json.Unmarshal(raw, &port); err == nil {
+		return port, nil
+	}
+	var s string
+	if err := json.Unmarshal(raw, &s); err == nil {
+		if port, err := strconv.Atoi(s); err == nil {
+			return port, nil
+		}
+	}
+	return 0, fmt.Errorf("unsupported forwardPorts entry %s", raw)
+}
+
+// commandLine reads a lifecycle command given as a string or an argument
+// list. The object form, running several commands in parallel, isn't
+// supported.
+func commandLine(raw json.RawMessage) (string, error) {
+	if len(raw) == 0 || string(raw) == "null" {
+		return "", nil
+	}
+	var s string
+	if err := json.Unmarshal(raw, &s); err == nil {
+		return s, nil
+	}
+	var args []string
+	if err := json.Unmarshal(raw, &args); err != nil {
+		return "", errors.New("only a string or an argument list is supported")
+	}
+	quoted := make([]string, len(args))
+	for i, arg := range args {
+		quoted[i] = shellutil.ShellQuote(arg)
+	}
+	return strings.Join(quoted, " "), nil
+}
+
+// standardize turns JSON with comments and trailing commas, as
+// devcontainer.json allows, into plain JSON.
+func standardize(src []byte) []byte {
+	out := make([]byte, 0, len(src))
+	for i := 0; i < len(src); i++ {
+		c := src[i]
+		switch {
+		case c == '"':
+			start := i
+			for i++; i < len(src) && src[i] != '"'; i++ {
+				if src[i] == '\\' {
+					i++
+				}
+			}
+			out = append(out, src[start:min(i+1, len(src))]...)
+		case c == '/' && i+1 < len(src) && src[i+1] == '/':
+			for i < len(src) && src[i] != '\n' {
+				i++
+			}
+			i--
+		case c == '/' && i+1 < len(src) && src[i+1] == '*':
+			end := strings.Index(string(src[i+2:]), "*/")
+			if end < 0 {
+				return out
+			}
+			i += end + 3
+		case c == ']' || c == '}':
+			// Drop a comma left before the closing bracket.
+			j := len(out) - 1
+			for j >= 0 && strings.ContainsRune(" \t\r\n", rune(out[j])) {
+				j--
+			}
+			if j >= 0 && out[j] == ',' {
+				out = append(out[:j], out[j+1:]...)
+			}
+			out = append(out, c)
+		default:
+			out = append(out, c)
+		}
+	}
+	return out
+}
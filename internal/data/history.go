@@ -0,0 +1,34 @@
+package data
+
+import "time"
+
+// History event kinds recorded in a workspace's timeline.
+const (
+	HistoryCreated       = "created"
+	HistoryAgentLaunched = "agent_launched"
+	HistoryAgentExited   = "agent_exited"
+	HistoryCommit        = "commit"
+	HistoryPROpened      = "pr_opened"
+	HistoryDeleted       = "deleted"
+)
+
+// maxHistory bounds a workspace's timeline; the oldest events go first.
+const maxHistory = 500
+
+// HistoryEvent is one entry in a workspace's lifecycle timeline.
+type HistoryEvent struct {
+	Kind string    `json:"kind"`
+	At   time.Time `json:"at"`
+	// Detail says what the event was about: the assistant for agent events,
+	// the commit or pull request for those.
+	Detail string `json:"detail,omitempty"`
+}
+
+// RecordHistory appends an event to the workspace's timeline, dropping the
+// oldest beyond the limit.
+func (w *Workspace) RecordHistory(kind, detail string, at time.Time) {
+	w.History = append(w.History, HistoryEvent{Kind: kind, At: at, Detail: detail})
+	if over := len(w.History) - maxHistory; over > 0 {
+		w.History = append(w.History[:0:0], w.History[over:]...)
+	}
+}
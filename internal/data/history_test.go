@@ -0,0 +1,42 @@
+package data
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRecordHistory(t *testing.T) {
+	ws := &Workspace{}
+	at := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	for i := range maxHistory + 5 {
+		ws.RecordHistory(HistoryAgentLaunched, "claude", at.Add(time.Duration(i)*time.Minute))
+	}
+	if len(ws.History) != maxHistory {
+		t.Fatalf("len(History) = %d, want %d", len(ws.History), maxHistory)
+	}
+	if first := ws.History[0].At; !first.Equal(at.Add(5 * time.Minute)) {
+		t.Fatalf("oldest event at %v, want the first %d dropped", first, 5)
+	}
+}
+
+func TestWorkspaceStoreKeepsHistory(t *testing.T) {
+	store, id := seedEnvWorkspace(t, nil)
+	ws, err := store.Load(id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	at := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	ws.RecordHistory(HistoryCreated, "", at)
+	ws.RecordHistory(HistoryAgentLaunched, "claude", at.Add(time.Minute))
+	if err := store.Save(ws); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	discovered := &Workspace{Repo: ws.Repo, Root: ws.Root, Branch: ws.Branch}
+	if found, err := store.LoadMetadataFor(discovered); err != nil || !found {
+		t.Fatalf("LoadMetadataFor() = %v, %v", found, err)
+	}
+	if len(discovered.History) != 2 || discovered.History[1].Detail != "claude" || !discovered.History[1].At.Equal(at.Add(time.Minute)) {
+		t.Fatalf("History = %+v, want the saved timeline", discovered.History)
+	}
+}
@@ -0,0 +1,34 @@
+package data
+
+// Launch kinds recorded in a workspace's launch history.
+const (
+	LaunchAgent    = "agent"
+	LaunchCommand  = "command"
+	LaunchTerminal = "terminal"
+)
+
+// maxLaunches bounds a workspace's launch history.
+const maxLaunches = 10
+
+// Launch is a tab the user started in a workspace, kept so it can be started
+// again from the relaunch picker.
+type Launch struct {
+	Kind string `json:"kind"`
+	// Name is the assistant, for agents.
+	Name string `json:"name,omitempty"`
+	// Command is the command line, for command tabs.
+	Command string `json:"command,omitempty"`
+}
+
+// RecordLaunch moves l to the front of the workspace's launch history,
+// dropping an identical earlier entry and the oldest beyond the limit.
+func (w *Workspace) RecordLaunch(l Launch) {
+	history := make([]Launch, 0, len(w.Launches)+1)
+	history = append(history, l)
+	for _, prev := range w.Launches {
+		if prev != l && len(history) < maxLaunches {
+			history = append(history, prev)
+		}
+	}
+	w.Launches = history
+}
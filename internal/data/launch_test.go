@@ -0,0 +1,47 @@
+package data
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestRecordLaunch(t *testing.T) {
+	ws := &Workspace{}
+	claude := Launch{Kind: LaunchAgent, Name: "claude"}
+	tests := Launch{Kind: LaunchCommand, Command: "npm test -- --watch"}
+	shell := Launch{Kind: LaunchTerminal}
+
+	ws.RecordLaunch(claude)
+	ws.RecordLaunch(tests)
+	ws.RecordLaunch(shell)
+	ws.RecordLaunch(claude)
+	want := []Launch{claude, shell, tests}
+	if fmt.Sprint(ws.Launches) != fmt.Sprint(want) {
+		t.Fatalf("Launches = %v, want %v (most recent first, no repeats)", ws.Launches, want)
+	}
+
+	for i := range 2 * maxLaunches {
+		ws.RecordLaunch(Launch{Kind: LaunchCommand, Command: fmt.Sprint("cmd", i)})
+	}
+	if len(ws.Launches) != maxLaunches || ws.Launches[0].Command != fmt.Sprint("cmd", 2*maxLaunches-1) {
+		t.Fatalf("Launches = %v, want the newest %d", ws.Launches, maxLaunches)
+	}
+}
+
+func TestWorkspaceStoreKeepsLaunches(t *testing.T) {
+	store, id := seedEnvWorkspace(t, nil)
+	ws, err := store.Load(id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ws.RecordLaunch(Launch{Kind: LaunchCommand, Command: "make watch"})
+	ws.LayoutApplied = true
+	if err := store.Save(ws); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	discovered := &Workspace{Repo: ws.Repo, Root: ws.Root, Branch: ws.Branch}
+	if found, err := store.LoadMetadataFor(discovered); err != nil || !found || len(discovered.Launches) != 1 || !discovered.LayoutApplied {
+		t.Fatalf("LoadMetadataFor() = %v, %v, Launches=%v, LayoutApplied=%v; want the saved history", found, err, discovered.Launches, discovered.LayoutApplied)
+	}
+}
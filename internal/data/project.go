@@ -8,7 +8,8 @@ import (
 type Project struct {
 	Name       string      `json:"name"`
 	Path       string      `json:"path"` // Absolute path to repository
-	Workspaces []Workspace `json:"-"`    // Discovered dynamically via git
+	Favorite   bool        `json:"favorite,omitempty"`
+	Workspaces []Workspace `json:"-"` // Discovered dynamically via git
 }
 
 // NewProject creates a new Project from a repository path
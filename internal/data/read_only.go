@@ -0,0 +1,29 @@
+package data
+
+import (
+	"errors"
+	"sync/atomic"
+)
+
+// ErrReadOnly is returned by every write to a store set read-only, as in a
+// second view of state another amux owns.
+var ErrReadOnly = errors.New("read-only: another amux owns the state")
+
+// readOnly refuses the writes of the store it is embedded in once set. The
+// stores check it where they write, so no caller can get around it.
+type readOnly struct {
+	set atomic.Bool
+}
+
+// SetReadOnly makes every later write return ErrReadOnly.
+func (r *readOnly) SetReadOnly() {
+	r.set.Store(true)
+}
+
+// writable returns ErrReadOnly once the store was set read-only.
+func (r *readOnly) writable() error {
+	if r.set.Load() {
+		return ErrReadOnly
+	}
+	return nil
+}
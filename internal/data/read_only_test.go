@@ -0,0 +1,33 @@
+package data
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+)
+
+func TestReadOnlyStoresRefuseWrites(t *testing.T) {
+	store, id := seedEnvWorkspace(t, nil)
+	registry := NewRegistry(filepath.Join(t.TempDir(), "projects.json"))
+	if err := registry.AddProject("/repo"); err != nil {
+		t.Fatalf("AddProject() error = %v", err)
+	}
+	store.SetReadOnly()
+	registry.SetReadOnly()
+
+	if err := store.SetSandbox(id, true); !errors.Is(err, ErrReadOnly) {
+		t.Fatalf("SetSandbox() = %v, want ErrReadOnly", err)
+	}
+	if err := store.Delete(id); !errors.Is(err, ErrReadOnly) {
+		t.Fatalf("Delete() = %v, want ErrReadOnly", err)
+	}
+	if err := registry.SetFavorite("/repo", true); !errors.Is(err, ErrReadOnly) {
+		t.Fatalf("SetFavorite() = %v, want ErrReadOnly", err)
+	}
+	if ws, err := store.Load(id); err != nil || ws.Sandbox {
+		t.Fatalf("Load() = %+v, %v; want the stored workspace unchanged", ws, err)
+	}
+	if paths, err := registry.Projects(); err != nil || len(paths) != 1 {
+		t.Fatalf("Projects() = %v, %v; want reads to work", paths, err)
+	}
+}
@@ -6,14 +6,17 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 	"sync"
 
 	"github.com/andyrewlee/amux/internal/fsatomic"
+	"github.com/andyrewlee/amux/internal/logging"
 )
 
 // Registry manages the projects.json file for persistent project tracking
 type Registry struct {
+	readOnly
 	path string
 	mu   sync.RWMutex
 }
@@ -26,6 +29,26 @@ type registryFile struct {
 type registryProject struct {
 	Name string `json:"name"`
 	Path string `json:"path"`
+	// Favorite pins the project to the top of the dashboard and pickers.
+	Favorite bool `json:"favorite,omitempty"`
+}
+
+func (f registryFile) paths() []string {
+	paths := make([]string, len(f.Projects))
+	for i, p := range f.Projects {
+		paths[i] = p.Path
+	}
+	return paths
+}
+
+func (f registryFile) favorites() map[string]bool {
+	favorites := make(map[string]bool)
+	for _, p := range f.Projects {
+		if p.Favorite {
+			favorites[canonicalProjectPath(p.Path)] = true
+		}
+	}
+	return favorites
 }
 
 // NewRegistry creates a new registry at the specified path
@@ -47,8 +70,8 @@ func (r *Registry) Load() ([]string, error) {
 	defer unlockRegistryFile(lockFile)
 
 	// Load is read-only and should not repair the primary file directly.
-	paths, _, err := r.loadUnlockedWithRecovery()
-	return paths, err
+	file, _, err := r.loadUnlockedWithRecovery()
+	return file.paths(), err
 }
 
 // Save writes the project paths to the registry file
@@ -62,45 +85,59 @@ func (r *Registry) Save(paths []string) error {
 	}
 	defer unlockRegistryFile(lockFile)
 
-	return r.saveUnlocked(paths)
+	// Keep the favorites of the projects that stay.
+	file, _, err := r.loadUnlockedWithRecovery()
+	if err != nil {
+		file = registryFile{}
+	}
+	return r.saveUnlocked(paths, file.favorites())
 }
 
-func (r *Registry) loadUnlockedWithRecovery() ([]string, bool, error) {
+func (r *Registry) loadUnlockedWithRecovery() (registryFile, bool, error) {
 	data, err := readRegistryFile(r.path)
 	if os.IsNotExist(err) {
 		backupPath := r.backupPath()
 		backupData, backupErr := readRegistryFile(backupPath)
 		if os.IsNotExist(backupErr) {
-			return []string{}, false, nil
+			return registryFile{}, false, nil
 		}
 		if backupErr != nil {
-			return nil, false, backupErr
+			return registryFile{}, false, backupErr
 		}
-		paths, parseErr := parseRegistryData(backupData, backupPath)
+		file, parseErr := parseRegistryFile(backupData, backupPath)
 		if parseErr != nil {
-			return nil, false, parseErr
+			return registryFile{}, false, parseErr
 		}
-		return paths, true, nil
+		return file, true, nil
 	}
 	if err != nil {
-		return nil, false, err
+		return registryFile{}, false, err
 	}
 
-	paths, parseErr := parseRegistryData(data, r.path)
+	file, parseErr := parseRegistryFile(data, r.path)
 	if parseErr == nil {
-		return paths, false, nil
+		return file, false, nil
 	}
 
 	backupPath := r.backupPath()
 	backupData, backupErr := readRegistryFile(backupPath)
-	if backupErr != nil {
-		return nil, false, errors.Join(parseErr, fmt.Errorf("read backup %s: %w", backupPath, backupErr))
+	if backupErr == nil {
+		backupFile, backupParseErr := parseRegistryFile(backupData, backupPath)
+		if backupParseErr == nil {
+			logging.Warn("Registry %s is damaged (%v); using its backup", r.path, parseErr)
+			return backupFile, true, nil
+		}
+		backupErr = fmt.Errorf("parse backup %s: %w", backupPath, backupParseErr)
+	} else {
+		backupErr = fmt.Errorf("read backup %s: %w", backupPath, backupErr)
 	}
-	backupPaths, backupParseErr := parseRegistryData(backupData, backupPath)
-	if backupParseErr != nil {
-		return nil, false, errors.Join(parseErr, fmt.Errorf("parse backup %s: %w", backupPath, backupParseErr))
+	// A file that parses but fails its checksum, say after a hand edit, is
+	// still better than refusing to load any projects.
+	if errors.Is(parseErr, fsatomic.ErrChecksum) {
+		logging.Warn("Registry %s failed its checksum and has no usable backup; loading it anyway", r.path)
+		return file, false, nil
 	}
-	return backupPaths, true, nil
+	return registryFile{}, false, errors.Join(parseErr, backupErr)
 }
 
 func readRegistryFile(path string) ([]byte, error) {
@@ -122,7 +159,10 @@ func readRegistryFile(path string) ([]byte, error) {
 	return data, nil
 }
 
-func (r *Registry) saveUnlocked(paths []string) error {
+func (r *Registry) saveUnlocked(paths []string, favorites map[string]bool) error {
+	if err := r.writable(); err != nil {
+		return err
+	}
 	paths = normalizeAndDedupeProjectPaths(paths)
 	dir := filepath.Dir(r.path)
 	if err := os.MkdirAll(dir, 0o700); err != nil {
@@ -136,12 +176,13 @@ func (r *Registry) saveUnlocked(paths []string) error {
 	for i, path := range paths {
 		name := filepath.Base(path)
 		registry.Projects[i] = registryProject{
-			Name: name,
-			Path: path,
+			Name:     name,
+			Path:     path,
+			Favorite: favorites[path],
 		}
 	}
 
-	return fsatomic.WriteJSON(r.path, registry)
+	return fsatomic.WriteCheckedJSON(r.path, registry)
 }
 
 // AddProject adds a project path to the registry
@@ -159,23 +200,24 @@ func (r *Registry) AddProject(path string) error {
 	}
 	defer unlockRegistryFile(lockFile)
 
-	paths, recoveredFromBackup, err := r.loadUnlockedWithRecovery()
+	file, recoveredFromBackup, err := r.loadUnlockedWithRecovery()
 	if err != nil {
 		return err
 	}
+	paths, favorites := file.paths(), file.favorites()
 
 	// Check if already exists
 	for _, p := range paths {
 		if canonicalProjectPath(p) == path {
 			if recoveredFromBackup {
-				return r.saveUnlocked(paths)
+				return r.saveUnlocked(paths, favorites)
 			}
 			return nil // Already registered
 		}
 	}
 
 	paths = append(paths, path)
-	return r.saveUnlocked(paths)
+	return r.saveUnlocked(paths, favorites)
 }
 
 // RemoveProject removes a project path from the registry
@@ -193,10 +235,11 @@ func (r *Registry) RemoveProject(path string) error {
 	}
 	defer unlockRegistryFile(lockFile)
 
-	paths, recoveredFromBackup, err := r.loadUnlockedWithRecovery()
+	file, recoveredFromBackup, err := r.loadUnlockedWithRecovery()
 	if err != nil {
 		return err
 	}
+	paths, favorites := file.paths(), file.favorites()
 
 	// Filter out the path
 	var newPaths []string
@@ -207,12 +250,12 @@ func (r *Registry) RemoveProject(path string) error {
 	}
 	if len(newPaths) == len(paths) {
 		if recoveredFromBackup {
-			return r.saveUnlocked(paths)
+			return r.saveUnlocked(paths, favorites)
 		}
 		return nil
 	}
 
-	return r.saveUnlocked(newPaths)
+	return r.saveUnlocked(newPaths, favorites)
 }
 
 // Projects returns a copy of all registered project paths
@@ -220,6 +263,51 @@ func (r *Registry) Projects() ([]string, error) {
 	return r.Load()
 }
 
+// Favorites returns the canonical paths of the projects marked as favorites.
+func (r *Registry) Favorites() (map[string]bool, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	lockFile, err := lockRegistryFile(r.lockPath(), true)
+	if err != nil {
+		return nil, err
+	}
+	defer unlockRegistryFile(lockFile)
+
+	file, _, err := r.loadUnlockedWithRecovery()
+	return file.favorites(), err
+}
+
+// SetFavorite marks or unmarks a registered project as a favorite.
+func (r *Registry) SetFavorite(path string, favorite bool) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	path = canonicalProjectPath(path)
+	if path == "" {
+		return errors.New("project path is required")
+	}
+
+	lockFile, err := lockRegistryFile(r.lockPath(), false)
+	if err != nil {
+		return err
+	}
+	defer unlockRegistryFile(lockFile)
+
+	file, _, err := r.loadUnlockedWithRecovery()
+	if err != nil {
+		return err
+	}
+	paths, favorites := file.paths(), file.favorites()
+	if !slices.ContainsFunc(paths, func(p string) bool { return canonicalProjectPath(p) == path }) {
+		return fmt.Errorf("project %s is not registered", path)
+	}
+	if favorites[path] == favorite {
+		return nil
+	}
+	favorites[path] = favorite
+	return r.saveUnlocked(paths, favorites)
+}
+
 func (r *Registry) lockPath() string {
 	return r.path + ".lock"
 }
@@ -228,16 +316,27 @@ func (r *Registry) backupPath() string {
 	return r.path + ".bak"
 }
 
+// parseRegistryData decodes a registry file. When the file decodes but fails
+// its checksum, the paths are returned along with an fsatomic.ErrChecksum error.
 func parseRegistryData(data []byte, path string) ([]string, error) {
+	registry, err := parseRegistryFile(data, path)
+	if err != nil && !errors.Is(err, fsatomic.ErrChecksum) {
+		return nil, err
+	}
+	return registry.paths(), err
+}
+
+// parseRegistryFile is parseRegistryData returning the whole file, favorites
+// included.
+func parseRegistryFile(data []byte, path string) (registryFile, error) {
 	var registry registryFile
 	if err := json.Unmarshal(data, &registry); err != nil {
-		return nil, fmt.Errorf("parse %s: %w", path, err)
+		return registryFile{}, fmt.Errorf("parse %s: %w", path, err)
 	}
-	paths := make([]string, len(registry.Projects))
-	for i, p := range registry.Projects {
-		paths[i] = p.Path
+	if err := fsatomic.VerifyJSON(data); err != nil {
+		return registry, fmt.Errorf("parse %s: %w", path, err)
 	}
-	return paths, nil
+	return registry, nil
 }
 
 func canonicalProjectPath(path string) string {
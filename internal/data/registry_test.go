@@ -323,3 +323,36 @@ func TestRegistry_RemoveProjectNoopRepairsPrimaryFromBackup(t *testing.T) {
 		t.Fatalf("unexpected repaired primary data: %v", paths)
 	}
 }
+
+func TestRegistryFavorites(t *testing.T) {
+	r := NewRegistry(filepath.Join(t.TempDir(), "projects.json"))
+	if err := r.Save([]string{"/path/to/project1", "/path/to/project2"}); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	if err := r.SetFavorite("/path/to/project2/", true); err != nil {
+		t.Fatalf("SetFavorite() error = %v", err)
+	}
+	if err := r.SetFavorite("/path/to/other", true); err == nil {
+		t.Fatal("SetFavorite() on an unregistered project should fail")
+	}
+	// Adding and removing other projects keeps the favorite.
+	if err := r.AddProject("/path/to/project3"); err != nil {
+		t.Fatalf("AddProject() error = %v", err)
+	}
+	if err := r.RemoveProject("/path/to/project1"); err != nil {
+		t.Fatalf("RemoveProject() error = %v", err)
+	}
+	if err := r.Save([]string{"/path/to/project2", "/path/to/project3"}); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	favorites, err := r.Favorites()
+	if err != nil || len(favorites) != 1 || !favorites["/path/to/project2"] {
+		t.Fatalf("Favorites() = %v, %v; want project2", favorites, err)
+	}
+	if err := r.SetFavorite("/path/to/project2", false); err != nil {
+		t.Fatalf("SetFavorite(false) error = %v", err)
+	}
+	if favorites, err := r.Favorites(); err != nil || len(favorites) != 0 {
+		t.Fatalf("Favorites() = %v, %v; want none", favorites, err)
+	}
+}
@@ -0,0 +1,199 @@
+package data
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/andyrewlee/amux/internal/fsatomic"
+)
+
+// staleTempAge is how old a temp file left by fsatomic must be before it is
+// taken as the leftover of an interrupted write rather than one in progress.
+const staleTempAge = time.Minute
+
+// StateIssue is a damaged or leftover state file found by CheckState.
+type StateIssue struct {
+	Path    string
+	Problem string
+	// Repair says what RepairState does about it; empty when it cannot.
+	Repair string
+	// RepairErr is set by RepairState when the repair failed.
+	RepairErr error
+	fix       func() error
+}
+
+// CheckState looks for a damaged project registry at registryPath, damaged
+// workspace metadata under metadataRoot, and temp files left by interrupted
+// writes in either.
+func CheckState(registryPath, metadataRoot string) ([]StateIssue, error) {
+	store := NewWorkspaceStore(metadataRoot)
+	issues := registryIssues(NewRegistry(registryPath), store)
+	issues = append(issues, staleTempIssues(filepath.Dir(registryPath))...)
+	ids, err := store.List()
+	if err != nil {
+		return issues, fmt.Errorf("list workspace metadata: %w", err)
+	}
+	for _, id := range ids {
+		if issue, ok := workspaceIssue(store, id); ok {
+			issues = append(issues, issue)
+		}
+		issues = append(issues, staleTempIssues(filepath.Join(metadataRoot, string(id)))...)
+	}
+	return issues, nil
+}
+
+// RepairState repairs what CheckState finds and returns the issues it found,
+// each with RepairErr set if its repair failed. Damaged files are restored
+// from their backups where one is good, and moved aside with a .damaged
+// suffix otherwise.
+func RepairState(registryPath, metadataRoot string) ([]StateIssue, error) {
+	issues, err := CheckState(registryPath, metadataRoot)
+	for i := range issues {
+		if issues[i].fix != nil {
+			issues[i].RepairErr = issues[i].fix()
+		}
+	}
+	return issues, err
+}
+
+func registryIssues(r *Registry, store *WorkspaceStore) []StateIssue {
+	data, err := os.ReadFile(r.path)
+	backupPaths, backupErr := readRegistryBackup(r)
+	var problem string
+	switch {
+	case os.IsNotExist(err):
+		if os.IsNotExist(backupErr) {
+			return nil
+		}
+		problem = "missing"
+	case err != nil:
+		return []StateIssue{{Path: r.path, Problem: "unreadable: " + err.Error()}}
+	default:
+		verifyErr := fsatomic.VerifyJSON(data)
+		if verifyErr == nil {
+			return nil
+		}
+		if backupErr != nil && errors.Is(verifyErr, fsatomic.ErrChecksum) {
+			return []StateIssue{{Path: r.path, Problem: "fails its checksum and has no good backup",
+				Repair: "keep its contents and rewrite the checksum", fix: func() error { return fsatomic.Reseal(r.path) }}}
+		}
+		problem = "damaged: " + verifyErr.Error()
+	}
+	if backupErr == nil {
+		return []StateIssue{{Path: r.path, Problem: problem + ", but its backup is good", Repair: "restore the backup",
+			fix: func() error { return r.Save(backupPaths) }}}
+	}
+	return []StateIssue{{
+		Path:    r.path,
+		Problem: problem + ", and has no good backup",
+		Repair:  "move it aside and rebuild it from the workspace metadata",
+		fix: func() error {
+			for _, p := range []string{r.path, r.backupPath()} {
+				if err := os.Rename(p, p+".damaged"); err != nil && !os.IsNotExist(err) {
+					return err
+				}
+			}
+			return r.Save(storedRepos(store))
+		},
+	}}
+}
+
+func readRegistryBackup(r *Registry) ([]string, error) {
+	data, err := os.ReadFile(r.backupPath())
+	if err != nil {
+		return nil, err
+	}
+	return parseRegistryData(data, r.backupPath())
+}
+
+// storedRepos lists the repositories of the stored workspaces.
+func storedRepos(store *WorkspaceStore) []string {
+	ids, _ := store.List()
+	var repos []string
+	for _, id := range ids {
+		if ws, err := store.load(id, false); err == nil && ws.Repo != "" {
+			repos = append(repos, ws.Repo)
+		}
+	}
+	return normalizeAndDedupeProjectPaths(repos)
+}
+
+func workspaceIssue(store *WorkspaceStore, id WorkspaceID) (StateIssue, bool) {
+	path := store.workspacePath(id)
+	data, err := os.ReadFile(path)
+	backup, backupErr := os.ReadFile(store.workspaceBackupPath(id))
+	if backupErr == nil {
+		backupErr = fsatomic.VerifyJSON(backup)
+	}
+	locked := func(fix func() error) func() error {
+		return func() error {
+			lockFiles, err := store.lockWorkspaceIDs(id)
+			if err != nil {
+				return err
+			}
+			defer unlockRegistryFiles(lockFiles)
+			return fix()
+		}
+	}
+	var problem string
+	switch {
+	case os.IsNotExist(err):
+		problem = "missing"
+	case err != nil:
+		return StateIssue{Path: path, Problem: "unreadable: " + err.Error()}, true
+	default:
+		verifyErr := fsatomic.VerifyJSON(data)
+		if verifyErr == nil {
+			return StateIssue{}, false
+		}
+		if backupErr != nil && errors.Is(verifyErr, fsatomic.ErrChecksum) {
+			return StateIssue{Path: path, Problem: "fails its checksum and has no good backup",
+				Repair: "keep its contents and rewrite the checksum", fix: locked(func() error { return fsatomic.Reseal(path) })}, true
+		}
+		problem = "damaged: " + verifyErr.Error()
+	}
+	if backupErr == nil {
+		return StateIssue{Path: path, Problem: problem + ", but its backup is good", Repair: "restore the backup",
+			fix: locked(func() error { return fsatomic.WriteFile(path, backup, 0o644) })}, true
+	}
+	return StateIssue{
+		Path:    path,
+		Problem: problem + ", and has no good backup",
+		Repair:  "move it aside; rescanning the project in amux re-imports its worktree",
+		fix: locked(func() error {
+			for _, p := range []string{path, store.workspaceBackupPath(id)} {
+				if err := os.Rename(p, p+".damaged"); err != nil && !os.IsNotExist(err) {
+					return err
+				}
+			}
+			return nil
+		}),
+	}, true
+}
+
+// staleTempIssues finds temp files fsatomic left in dir when a write was cut
+// short.
+func staleTempIssues(dir string) []StateIssue {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil
+	}
+	var issues []StateIssue
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.Contains(entry.Name(), ".tmp-") {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil || time.Since(info.ModTime()) < staleTempAge {
+			continue
+		}
+		path := filepath.Join(dir, entry.Name())
+		issues = append(issues, StateIssue{Path: path, Problem: "left by an interrupted write", Repair: "remove it",
+			fix: func() error { return os.Remove(path) }})
+	}
+	return issues
+}
@@ -0,0 +1,111 @@
+package data
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRepairStateRestoresRegistryBackup(t *testing.T) {
+	dir := t.TempDir()
+	registryPath := filepath.Join(dir, "projects.json")
+	r := NewRegistry(registryPath)
+	if err := r.Save([]string{"/src/a"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Save([]string{"/src/a", "/src/b"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(registryPath, []byte("{\"projects\": [{"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	// Loading already falls back to the backup.
+	if paths, err := r.Load(); err != nil || !slices.Equal(paths, []string{"/src/a"}) {
+		t.Fatalf("Load() = %v, %v; want the backup", paths, err)
+	}
+
+	metadataRoot := filepath.Join(dir, "metadata")
+	issues, err := CheckState(registryPath, metadataRoot)
+	if err != nil || len(issues) != 1 || issues[0].Repair != "restore the backup" {
+		t.Fatalf("CheckState() = %+v, %v", issues, err)
+	}
+	issues, err = RepairState(registryPath, metadataRoot)
+	if err != nil || issues[0].RepairErr != nil {
+		t.Fatalf("RepairState() = %+v, %v", issues, err)
+	}
+	if issues, _ := CheckState(registryPath, metadataRoot); len(issues) != 0 {
+		t.Fatalf("issues after repair = %+v", issues)
+	}
+}
+
+func TestRepairStateRebuildsRegistryFromMetadata(t *testing.T) {
+	dir := t.TempDir()
+	registryPath := filepath.Join(dir, "projects.json")
+	metadataRoot := filepath.Join(dir, "metadata")
+	store := NewWorkspaceStore(metadataRoot)
+	for _, ws := range []*Workspace{
+		{Name: "one", Repo: "/src/a", Root: "/work/a/one"},
+		{Name: "two", Repo: "/src/a", Root: "/work/a/two"},
+		{Name: "three", Repo: "/src/b", Root: "/work/b/three"},
+	} {
+		if err := store.Save(ws); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.WriteFile(registryPath, []byte{0, 0, 0}, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	issues, err := RepairState(registryPath, metadataRoot)
+	if err != nil || len(issues) != 1 || issues[0].RepairErr != nil {
+		t.Fatalf("RepairState() = %+v, %v", issues, err)
+	}
+	paths, err := NewRegistry(registryPath).Load()
+	slices.Sort(paths)
+	if err != nil || !slices.Equal(paths, []string{"/src/a", "/src/b"}) {
+		t.Fatalf("rebuilt registry = %v, %v", paths, err)
+	}
+	if _, err := os.Stat(registryPath + ".damaged"); err != nil {
+		t.Fatalf("the damaged registry should be kept aside: %v", err)
+	}
+}
+
+func TestRepairStateWorkspaceMetadata(t *testing.T) {
+	dir := t.TempDir()
+	registryPath := filepath.Join(dir, "projects.json")
+	metadataRoot := filepath.Join(dir, "metadata")
+	store := NewWorkspaceStore(metadataRoot)
+	ws := &Workspace{Name: "one", Repo: "/src/a", Root: "/work/a/one"}
+	if err := store.Save(ws); err != nil {
+		t.Fatal(err)
+	}
+	path := store.workspacePath(ws.ID())
+	if err := os.WriteFile(path, []byte("{\"name\": \"one\""), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	stale := filepath.Join(filepath.Dir(path), "workspace.json.tmp-123")
+	if err := os.WriteFile(stale, nil, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	old := time.Now().Add(-time.Hour)
+	if err := os.Chtimes(stale, old, old); err != nil {
+		t.Fatal(err)
+	}
+
+	issues, err := RepairState(registryPath, metadataRoot)
+	if err != nil || len(issues) != 2 {
+		t.Fatalf("RepairState() = %+v, %v", issues, err)
+	}
+	if !strings.HasPrefix(issues[0].Problem, "damaged") || !strings.HasPrefix(issues[0].Repair, "move it aside") {
+		t.Fatalf("workspace issue = %+v", issues[0])
+	}
+	if ids, _ := store.List(); len(ids) != 0 {
+		t.Fatalf("the damaged workspace is still listed: %v", ids)
+	}
+	if _, err := os.Stat(stale); !os.IsNotExist(err) {
+		t.Fatalf("stale temp file not removed: %v", err)
+	}
+}
@@ -70,14 +70,50 @@ type Workspace struct {
 
 	// Environment
 	Env map[string]string `json:"env"`
+	// Sandbox runs this workspace's agents without network access and with
+	// writes limited to the worktree (see internal/sandbox).
+	Sandbox bool `json:"sandbox,omitempty"`
+	// NoNetwork runs this workspace's agents without network access but
+	// leaves the filesystem writable. Sandbox implies it.
+	NoNetwork bool `json:"no_network,omitempty"`
+	// GPUs is the CUDA_VISIBLE_DEVICES value new agent tabs and scripts in
+	// this workspace run with, pinning them to those devices; empty leaves
+	// every GPU visible.
+	GPUs string `json:"gpus,omitempty"`
+	// Favorite pins the workspace to the top of its project in the
+	// dashboard and pickers.
+	Favorite bool `json:"favorite,omitempty"`
+	// Container is the dev container new agent and terminal tabs run in,
+	// while amux keeps one up for this workspace (see internal/container).
+	Container *Container `json:"container,omitempty"`
 
 	// UI state
 	OpenTabs       []TabInfo `json:"open_tabs,omitempty"`
 	ActiveTabIndex int       `json:"active_tab_index"`
+	// Launches lists the tabs started here, most recent first.
+	Launches []Launch `json:"launches,omitempty"`
+	// LayoutApplied records that the startup layout was offered, so it is
+	// only offered on first activation.
+	LayoutApplied bool `json:"layout_applied,omitempty"`
 
 	// Lifecycle
 	Archived   bool      `json:"archived"`
 	ArchivedAt time.Time `json:"archived_at,omitempty"`
+	// History is the workspace's lifecycle timeline, oldest first.
+	History []HistoryEvent `json:"history,omitempty"`
+}
+
+// Container is a workspace's running dev container.
+type Container struct {
+	// Runtime is the CLI managing it, docker or podman.
+	Runtime string `json:"runtime"`
+	Name    string `json:"name"`
+	// Workdir is where the worktree is mounted in the container.
+	Workdir string `json:"workdir"`
+	// User runs tabs in the container; the image's default when empty.
+	User string `json:"user,omitempty"`
+	// Ports are the runtime's "host:container" port mappings.
+	Ports []string `json:"ports,omitempty"`
 }
 
 // WorkspaceID is a unique identifier based on repo+root hash
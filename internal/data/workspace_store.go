@@ -20,12 +20,15 @@ const workspaceFilename = "workspace.json"
 
 // WorkspaceStore manages workspace persistence
 type WorkspaceStore struct {
+	readOnly
 	root             string // ~/.amux/workspaces-metadata
 	defaultAssistant string
 	// now supplies the current time when stamping Created on freshly discovered
 	// workspaces. It defaults to time.Now and is overridable in tests so
 	// discovery timestamps can be asserted deterministically.
 	now func() time.Time
+	// secrets holds secret env values when set (workspace_store_secrets.go).
+	secrets SecretStore
 }
 
 // NewWorkspaceStore creates a new workspace store
@@ -151,12 +154,21 @@ func (s *WorkspaceStore) load(id WorkspaceID, applyDefaults bool) (*Workspace, e
 		Scripts:        raw.Scripts,
 		ScriptMode:     raw.ScriptMode,
 		Env:            raw.Env,
+		Sandbox:        raw.Sandbox,
+		NoNetwork:      raw.NoNetwork,
+		GPUs:           raw.GPUs,
+		Favorite:       raw.Favorite,
+		Container:      raw.Container,
 		OpenTabs:       raw.OpenTabs,
 		ActiveTabIndex: raw.ActiveTabIndex,
+		Launches:       raw.Launches,
+		LayoutApplied:  raw.LayoutApplied,
 		Archived:       raw.Archived,
 		ArchivedAt:     parseCreated(raw.ArchivedAt),
+		History:        raw.History,
 	}
 	ws.storeID = id
+	openEnv(s.secrets, ws)
 
 	if applyDefaults {
 		// Apply defaults for missing fields.
@@ -166,22 +178,11 @@ func (s *WorkspaceStore) load(id WorkspaceID, applyDefaults bool) (*Workspace, e
 	return ws, nil
 }
 
-func (s *WorkspaceStore) readWorkspaceMetadata(id WorkspaceID) ([]byte, error) {
-	data, err := os.ReadFile(s.workspacePath(id))
-	if os.IsNotExist(err) {
-		backupData, backupErr := os.ReadFile(s.workspaceBackupPath(id))
-		if backupErr == nil {
-			return backupData, nil
-		}
-		if !os.IsNotExist(backupErr) {
-			return nil, backupErr
-		}
-	}
-	return data, err
-}
-
 // Save saves a workspace to the store using atomic write
 func (s *WorkspaceStore) Save(ws *Workspace) error {
+	if err := s.writable(); err != nil {
+		return err
+	}
 	if err := validateWorkspaceForSave(ws); err != nil {
 		return err
 	}
@@ -211,9 +212,10 @@ func (s *WorkspaceStore) Save(ws *Workspace) error {
 
 	// Atomic replace (temp + fsync + rename) so a crash mid-save can never
 	// leave a truncated workspace.json behind.
-	if err := fsatomic.WriteJSON(path, ws); err != nil {
+	if err := fsatomic.WriteCheckedJSON(path, sealEnv(s.secrets, workspaceSecrets(id), ws)); err != nil {
 		return fmt.Errorf("save workspace %s: %w", id, err)
 	}
+	s.scrubBackup(id)
 	if oldID != "" {
 		if err := s.deleteWorkspaceDir(oldID); err != nil {
 			logging.Warn("Failed to remove old workspace metadata %s: %v", oldID, err)
@@ -261,6 +263,9 @@ func (s *WorkspaceStore) saveWorkspaceLocked(id WorkspaceID, ws *Workspace) erro
 	if ws == nil {
 		return errors.New("workspace is required")
 	}
+	if err := s.writable(); err != nil {
+		return err
+	}
 	path := s.workspacePath(id)
 	dir := filepath.Dir(path)
 	if err := os.MkdirAll(dir, 0o700); err != nil {
@@ -269,9 +274,10 @@ func (s *WorkspaceStore) saveWorkspaceLocked(id WorkspaceID, ws *Workspace) erro
 	// Atomic replace (temp + fsync + rename, with backup recovery on platforms
 	// that need it), matching Save. The caller already holds the workspace lock.
 	// A crash mid-save can never leave a truncated workspace.json behind.
-	if err := fsatomic.WriteJSON(path, ws); err != nil {
+	if err := fsatomic.WriteCheckedJSON(path, sealEnv(s.secrets, workspaceSecrets(id), ws)); err != nil {
 		return err
 	}
+	s.scrubBackup(id)
 	return nil
 }
 
@@ -280,11 +286,15 @@ func (s *WorkspaceStore) Delete(id WorkspaceID) error {
 	if err := validateWorkspaceID(id); err != nil {
 		return err
 	}
+	if err := s.writable(); err != nil {
+		return err
+	}
 	lockFiles, err := s.lockWorkspaceIDs(id)
 	if err != nil {
 		return err
 	}
 	defer unlockRegistryFiles(lockFiles)
+	s.deleteWorkspaceSecrets(id)
 	if err := s.deleteWorkspaceDir(id); err != nil {
 		return err
 	}
@@ -336,10 +346,18 @@ func (s *WorkspaceStore) LoadMetadataFor(ws *Workspace) (bool, error) {
 	ws.Scripts = stored.Scripts
 	ws.ScriptMode = stored.ScriptMode
 	ws.Env = stored.Env
+	ws.Sandbox = stored.Sandbox
+	ws.NoNetwork = stored.NoNetwork
+	ws.GPUs = stored.GPUs
+	ws.Favorite = stored.Favorite
+	ws.Container = stored.Container
 	ws.OpenTabs = stored.OpenTabs
 	ws.ActiveTabIndex = stored.ActiveTabIndex
+	ws.Launches = stored.Launches
+	ws.LayoutApplied = stored.LayoutApplied
 	ws.Archived = stored.Archived
 	ws.ArchivedAt = stored.ArchivedAt
+	ws.History = stored.History
 	ws.storeID = stored.storeID
 
 	// Apply defaults if stored metadata had empty values
@@ -0,0 +1,20 @@
+package data
+
+import "fmt"
+
+// SetBase records base as the ref a workspace's branch is based on, as when
+// the branch is rebased onto another, and persists it.
+func (s *WorkspaceStore) SetBase(id WorkspaceID, base string) error {
+	ws, err := s.Load(id)
+	if err != nil {
+		return fmt.Errorf("set base for workspace %s: %w", id, err)
+	}
+	if ws.Base == base {
+		return nil
+	}
+	ws.Base = base
+	if err := s.Save(ws); err != nil {
+		return fmt.Errorf("set base for workspace %s: %w", id, err)
+	}
+	return nil
+}
@@ -0,0 +1,15 @@
+package data
+
+import "testing"
+
+func TestWorkspaceStoreSetBase(t *testing.T) {
+	store, id := seedEnvWorkspace(t, nil)
+
+	if err := store.SetBase(id, "api"); err != nil {
+		t.Fatalf("SetBase(api) error = %v", err)
+	}
+	reloaded, err := store.Load(id)
+	if err != nil || reloaded.Base != "api" {
+		t.Fatalf("Load() = %#v, %v; want base api", reloaded, err)
+	}
+}
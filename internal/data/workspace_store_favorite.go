@@ -0,0 +1,19 @@
+package data
+
+import "fmt"
+
+// SetFavorite marks or unmarks a workspace as a favorite and persists it.
+func (s *WorkspaceStore) SetFavorite(id WorkspaceID, favorite bool) error {
+	ws, err := s.Load(id)
+	if err != nil {
+		return fmt.Errorf("set favorite for workspace %s: %w", id, err)
+	}
+	if ws.Favorite == favorite {
+		return nil
+	}
+	ws.Favorite = favorite
+	if err := s.Save(ws); err != nil {
+		return fmt.Errorf("set favorite for workspace %s: %w", id, err)
+	}
+	return nil
+}
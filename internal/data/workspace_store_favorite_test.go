@@ -0,0 +1,25 @@
+package data
+
+import "testing"
+
+func TestWorkspaceStoreSetFavorite(t *testing.T) {
+	store, id := seedEnvWorkspace(t, nil)
+
+	if err := store.SetFavorite(id, true); err != nil {
+		t.Fatalf("SetFavorite(true) error = %v", err)
+	}
+	reloaded, err := store.Load(id)
+	if err != nil || !reloaded.Favorite {
+		t.Fatalf("Load() = %#v, %v; want a favorite", reloaded, err)
+	}
+	discovered := &Workspace{Repo: reloaded.Repo, Root: reloaded.Root, Branch: reloaded.Branch}
+	if found, err := store.LoadMetadataFor(discovered); err != nil || !found || !discovered.Favorite {
+		t.Fatalf("LoadMetadataFor() = %v, %v, Favorite=%v; want the stored favorite", found, err, discovered.Favorite)
+	}
+	if err := store.SetFavorite(id, false); err != nil {
+		t.Fatalf("SetFavorite(false) error = %v", err)
+	}
+	if reloaded, err := store.Load(id); err != nil || reloaded.Favorite {
+		t.Fatalf("Load() = %#v, %v; want no longer a favorite", reloaded, err)
+	}
+}
@@ -0,0 +1,20 @@
+package data
+
+import "fmt"
+
+// SetGPUs pins a workspace's new agent tabs and scripts to the GPUs in
+// devices (a CUDA_VISIBLE_DEVICES value; empty unpins) and persists it.
+func (s *WorkspaceStore) SetGPUs(id WorkspaceID, devices string) error {
+	ws, err := s.Load(id)
+	if err != nil {
+		return fmt.Errorf("set GPUs for workspace %s: %w", id, err)
+	}
+	if ws.GPUs == devices {
+		return nil
+	}
+	ws.GPUs = devices
+	if err := s.Save(ws); err != nil {
+		return fmt.Errorf("set GPUs for workspace %s: %w", id, err)
+	}
+	return nil
+}
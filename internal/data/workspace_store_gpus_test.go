@@ -0,0 +1,25 @@
+package data
+
+import "testing"
+
+func TestWorkspaceStoreSetGPUs(t *testing.T) {
+	store, id := seedEnvWorkspace(t, nil)
+
+	if err := store.SetGPUs(id, "1"); err != nil {
+		t.Fatalf("SetGPUs(1) error = %v", err)
+	}
+	reloaded, err := store.Load(id)
+	if err != nil || reloaded.GPUs != "1" {
+		t.Fatalf("Load() = %#v, %v; want GPUs 1", reloaded, err)
+	}
+	discovered := &Workspace{Repo: reloaded.Repo, Root: reloaded.Root, Branch: reloaded.Branch}
+	if found, err := store.LoadMetadataFor(discovered); err != nil || !found || discovered.GPUs != "1" {
+		t.Fatalf("LoadMetadataFor() = %v, %v, GPUs=%q; want the stored pin", found, err, discovered.GPUs)
+	}
+	if err := store.SetGPUs(id, ""); err != nil {
+		t.Fatalf("SetGPUs(\"\") error = %v", err)
+	}
+	if reloaded, err := store.Load(id); err != nil || reloaded.GPUs != "" {
+		t.Fatalf("Load() = %#v, %v; want GPUs unpinned", reloaded, err)
+	}
+}
@@ -46,6 +46,9 @@ func (s *WorkspaceStore) DeleteByRepo(repoPath string) ([]WorkspaceID, error) {
 	if targetRepo == "" {
 		return nil, errors.New("repo path is required")
 	}
+	if err := s.writable(); err != nil {
+		return nil, err
+	}
 	ids, err := s.List()
 	if err != nil {
 		return nil, err
@@ -86,6 +89,9 @@ func (s *WorkspaceStore) PruneStale(options WorkspacePruneOptions) (WorkspacePru
 	if s == nil {
 		return result, nil
 	}
+	if err := s.writable(); err != nil {
+		return result, err
+	}
 	now := options.Now
 	if now.IsZero() {
 		now = time.Now()
@@ -176,6 +182,7 @@ func (s *WorkspaceStore) pruneWorkspaceIfStale(
 	if reason == "" {
 		return "", nil
 	}
+	s.deleteWorkspaceSecrets(id)
 	if err := s.deleteWorkspaceDir(id); err != nil {
 		return "", fmt.Errorf("delete %s metadata: %w", reason, err)
 	}
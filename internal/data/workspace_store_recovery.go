@@ -0,0 +1,45 @@
+package data
+
+import (
+	"errors"
+	"os"
+
+	"github.com/andyrewlee/amux/internal/fsatomic"
+	"github.com/andyrewlee/amux/internal/logging"
+)
+
+// readWorkspaceMetadata reads a workspace's metadata, falling back to the
+// backup kept by the last save when the file is missing or damaged. A file
+// that only fails its checksum is still used when there is no good backup.
+func (s *WorkspaceStore) readWorkspaceMetadata(id WorkspaceID) ([]byte, error) {
+	data, err := os.ReadFile(s.workspacePath(id))
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	var verifyErr error
+	if err == nil {
+		if verifyErr = fsatomic.VerifyJSON(data); verifyErr == nil {
+			return data, nil
+		}
+	}
+	backupData, backupErr := os.ReadFile(s.workspaceBackupPath(id))
+	if backupErr == nil && fsatomic.VerifyJSON(backupData) == nil {
+		if verifyErr != nil {
+			logging.Warn("Workspace %s metadata is damaged (%v); using its backup", id, verifyErr)
+		}
+		return backupData, nil
+	}
+	if err != nil {
+		if backupErr != nil && !os.IsNotExist(backupErr) {
+			return nil, backupErr
+		}
+		if backupErr == nil {
+			return backupData, nil // decoding reports the damage
+		}
+		return nil, err
+	}
+	if errors.Is(verifyErr, fsatomic.ErrChecksum) {
+		logging.Warn("Workspace %s metadata failed its checksum and has no usable backup; loading it anyway", id)
+	}
+	return data, nil
+}
@@ -0,0 +1,37 @@
+package data
+
+import "fmt"
+
+// SetSandbox turns sandboxed agent launches on or off for a workspace and
+// persists the choice, loading fresh before saving like SetEnv.
+func (s *WorkspaceStore) SetSandbox(id WorkspaceID, enabled bool) error {
+	ws, err := s.Load(id)
+	if err != nil {
+		return fmt.Errorf("set sandbox for workspace %s: %w", id, err)
+	}
+	if ws.Sandbox == enabled {
+		return nil
+	}
+	ws.Sandbox = enabled
+	if err := s.Save(ws); err != nil {
+		return fmt.Errorf("set sandbox for workspace %s: %w", id, err)
+	}
+	return nil
+}
+
+// SetNoNetwork turns network blocking for a workspace's agents on or off
+// and persists the choice.
+func (s *WorkspaceStore) SetNoNetwork(id WorkspaceID, enabled bool) error {
+	ws, err := s.Load(id)
+	if err != nil {
+		return fmt.Errorf("set no-network for workspace %s: %w", id, err)
+	}
+	if ws.NoNetwork == enabled {
+		return nil
+	}
+	ws.NoNetwork = enabled
+	if err := s.Save(ws); err != nil {
+		return fmt.Errorf("set no-network for workspace %s: %w", id, err)
+	}
+	return nil
+}
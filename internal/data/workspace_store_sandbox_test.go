@@ -0,0 +1,46 @@
+package data
+
+import (
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestWorkspaceStoreSetSandbox(t *testing.T) {
+	store, id := seedEnvWorkspace(t, nil)
+
+	if err := store.SetSandbox(id, true); err != nil {
+		t.Fatalf("SetSandbox(true) error = %v", err)
+	}
+	reloaded, err := store.Load(id)
+	if err != nil || !reloaded.Sandbox {
+		t.Fatalf("Load() = %#v, %v; want Sandbox on", reloaded, err)
+	}
+
+	// LoadMetadataFor, the discovery merge path, carries the flag too.
+	discovered := &Workspace{Repo: reloaded.Repo, Root: reloaded.Root, Branch: reloaded.Branch}
+	if found, err := store.LoadMetadataFor(discovered); err != nil || !found || !discovered.Sandbox {
+		t.Fatalf("LoadMetadataFor() = %v, %v, Sandbox=%v; want the stored flag", found, err, discovered.Sandbox)
+	}
+
+	if err := store.SetNoNetwork(id, true); err != nil {
+		t.Fatalf("SetNoNetwork(true) error = %v", err)
+	}
+	if reloaded, err := store.Load(id); err != nil || !reloaded.NoNetwork || !reloaded.Sandbox {
+		t.Fatalf("Load() = %#v, %v; want both flags on", reloaded, err)
+	}
+	if err := store.SetNoNetwork(id, false); err != nil {
+		t.Fatalf("SetNoNetwork(false) error = %v", err)
+	}
+
+	if err := store.SetSandbox(id, false); err != nil {
+		t.Fatalf("SetSandbox(false) error = %v", err)
+	}
+	raw, err := os.ReadFile(store.workspacePath(id))
+	if err != nil {
+		t.Fatalf("read metadata: %v", err)
+	}
+	if strings.Contains(string(raw), `"sandbox"`) {
+		t.Fatalf("metadata = %s, want sandbox omitted when off", raw)
+	}
+}
@@ -0,0 +1,69 @@
+package data
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/andyrewlee/amux/internal/fsatomic"
+)
+
+const scratchpadFilename = "scratchpad.md"
+
+func (s *WorkspaceStore) scratchpadPath(id WorkspaceID) string {
+	return filepath.Join(s.root, string(id), scratchpadFilename)
+}
+
+// LoadScratchpad returns the notes saved for a workspace, or "" when it has
+// none. The scratchpad lives next to workspace.json, so deleting the
+// workspace's metadata deletes it too.
+func (s *WorkspaceStore) LoadScratchpad(id WorkspaceID) (string, error) {
+	if err := validateWorkspaceID(id); err != nil {
+		return "", err
+	}
+	text, err := os.ReadFile(s.scratchpadPath(id))
+	if errors.Is(err, fs.ErrNotExist) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("read scratchpad for workspace %s: %w", id, err)
+	}
+	return string(text), nil
+}
+
+// SaveScratchpad replaces a workspace's saved notes atomically. Saving ""
+// removes the file. A workspace with no stored metadata, such as one just
+// deleted, is left alone rather than having its directory recreated.
+func (s *WorkspaceStore) SaveScratchpad(id WorkspaceID, text string) error {
+	if err := validateWorkspaceID(id); err != nil {
+		return err
+	}
+	if err := s.writable(); err != nil {
+		return err
+	}
+	lockFiles, err := s.lockWorkspaceIDs(id)
+	if err != nil {
+		return err
+	}
+	defer unlockRegistryFiles(lockFiles)
+	if !s.workspaceMetadataExists(id) {
+		return nil
+	}
+
+	path := s.scratchpadPath(id)
+	if text == "" {
+		if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return fmt.Errorf("save scratchpad for workspace %s: %w", id, err)
+		}
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return fmt.Errorf("save scratchpad for workspace %s: %w", id, err)
+	}
+	if err := fsatomic.WriteFile(path, []byte(text), 0o600); err != nil {
+		return fmt.Errorf("save scratchpad for workspace %s: %w", id, err)
+	}
+	return nil
+}
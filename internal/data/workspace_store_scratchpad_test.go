@@ -0,0 +1,51 @@
+package data
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestWorkspaceStoreScratchpad_SaveLoadAndDelete(t *testing.T) {
+	store, id := seedEnvWorkspace(t, nil)
+
+	if text, err := store.LoadScratchpad(id); err != nil || text != "" {
+		t.Fatalf("LoadScratchpad() before save = %q, %v; want empty", text, err)
+	}
+	if err := store.SaveScratchpad(id, "# Notes\n- try the cache\n"); err != nil {
+		t.Fatalf("SaveScratchpad() error = %v", err)
+	}
+	if text, err := store.LoadScratchpad(id); err != nil || text != "# Notes\n- try the cache\n" {
+		t.Fatalf("LoadScratchpad() = %q, %v", text, err)
+	}
+
+	// Saving an empty scratchpad removes the file.
+	if err := store.SaveScratchpad(id, ""); err != nil {
+		t.Fatalf("SaveScratchpad(\"\") error = %v", err)
+	}
+	if _, err := os.Stat(store.scratchpadPath(id)); !os.IsNotExist(err) {
+		t.Fatalf("scratchpad file still exists: %v", err)
+	}
+
+	// Deleting the workspace deletes its notes with it, and a late save
+	// does not bring its directory back.
+	if err := store.SaveScratchpad(id, "keep"); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Delete(id); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.SaveScratchpad(id, "late"); err != nil {
+		t.Fatalf("SaveScratchpad() after Delete error = %v", err)
+	}
+	if _, err := os.Stat(filepath.Dir(store.scratchpadPath(id))); !os.IsNotExist(err) {
+		t.Fatalf("workspace directory recreated by a late save: %v", err)
+	}
+}
+
+func TestWorkspaceStoreScratchpad_RejectsInvalidID(t *testing.T) {
+	store := NewWorkspaceStore(t.TempDir())
+	if err := store.SaveScratchpad("../escape", "x"); err == nil {
+		t.Fatal("SaveScratchpad() with an invalid id succeeded")
+	}
+}
@@ -0,0 +1,216 @@
+package data
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/andyrewlee/amux/internal/fsatomic"
+	"github.com/andyrewlee/amux/internal/logging"
+)
+
+// secretRefPrefix marks an env value in workspace.json that lives in the
+// secret store instead; the rest of the value is the store key.
+const secretRefPrefix = "amux-secret:"
+
+// SecretStore keeps secret workspace env values out of workspace.json. It is
+// satisfied by secrets.Store; the interface lives here so this package does
+// not depend on the keychain plumbing.
+type SecretStore interface {
+	Get(key string) (string, error)
+	Set(key, value string) error
+	Delete(key string) error
+}
+
+// SetSecretStore routes secret-looking env values (see IsSecretEnvKey)
+// through store on save and resolves them on load. Without a store, env
+// values are written as given.
+func (s *WorkspaceStore) SetSecretStore(store SecretStore) {
+	s.secrets = store
+}
+
+// SetSecretStore keeps secret env values of trashed workspaces in store, as
+// WorkspaceStore.SetSecretStore does for live ones.
+func (t *WorkspaceTrash) SetSecretStore(store SecretStore) {
+	t.secrets = store
+}
+
+// IsSecretEnvKey reports whether an env var name looks like it holds a
+// credential: API keys, tokens, passwords, and the like.
+func IsSecretEnvKey(key string) bool {
+	upper := strings.ToUpper(key)
+	for _, marker := range []string{"TOKEN", "SECRET", "PASSWORD", "PASSWD", "CREDENTIAL", "APIKEY", "API_KEY", "PRIVATE_KEY", "ACCESS_KEY"} {
+		if strings.Contains(upper, marker) {
+			return true
+		}
+	}
+	return strings.HasSuffix(upper, "_KEY")
+}
+
+// workspaceSecrets is where a live workspace's secret env values are kept.
+// Trash entries keep copies of their own (see trashSecrets), so deleting a
+// workspace and later purging its trash entry each drop only their own.
+func workspaceSecrets(id WorkspaceID) string {
+	return "workspace/" + string(id) + "/"
+}
+
+// sealEnv returns ws as it should be written: secret env values are moved to
+// store under prefix and replaced by references. ws itself is not modified. A
+// value the store refuses stays in plaintext rather than being lost.
+func sealEnv(store SecretStore, prefix string, ws *Workspace) *Workspace {
+	if store == nil || len(ws.Env) == 0 {
+		return ws
+	}
+	sealed := *ws
+	sealed.Env = make(map[string]string, len(ws.Env))
+	for key, value := range ws.Env {
+		sealed.Env[key] = value
+		if !IsSecretEnvKey(key) || value == "" || strings.HasPrefix(value, secretRefPrefix) {
+			continue
+		}
+		ref := prefix + key
+		if err := store.Set(ref, value); err != nil {
+			logging.Warn("Keeping %s for workspace %s in plaintext: %v", key, ws.Name, err)
+			continue
+		}
+		sealed.Env[key] = secretRefPrefix + ref
+	}
+	return &sealed
+}
+
+// openEnv resolves secret references in a freshly loaded workspace. A
+// reference that cannot be resolved is left in place so saving the workspace
+// again does not drop it.
+func openEnv(store SecretStore, ws *Workspace) {
+	if store == nil {
+		return
+	}
+	for key, value := range ws.Env {
+		ref, ok := strings.CutPrefix(value, secretRefPrefix)
+		if !ok {
+			continue
+		}
+		secret, err := store.Get(ref)
+		if err != nil {
+			logging.Warn("Could not read secret %s for workspace %s: %v", key, ws.Name, err)
+			continue
+		}
+		ws.Env[key] = secret
+	}
+}
+
+// deleteSecrets removes the secrets under prefix that env refers to. Other
+// references, such as those a trash entry copied from a live workspace that
+// could not be resolved, are left alone.
+func deleteSecrets(store SecretStore, prefix string, env map[string]string) error {
+	if store == nil {
+		return nil
+	}
+	var errs []error
+	for _, value := range env {
+		ref, ok := strings.CutPrefix(value, secretRefPrefix)
+		if !ok || !strings.HasPrefix(ref, prefix) {
+			continue
+		}
+		if err := store.Delete(ref); err != nil {
+			errs = append(errs, fmt.Errorf("delete secret %s: %w", ref, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// deleteWorkspaceSecrets removes the secrets id's metadata refers to, before
+// the metadata itself is deleted. Failures are logged: the delete goes on,
+// and an orphaned secret is only reachable by its key.
+func (s *WorkspaceStore) deleteWorkspaceSecrets(id WorkspaceID) {
+	if s.secrets == nil {
+		return
+	}
+	data, err := s.readWorkspaceMetadata(id)
+	if err != nil {
+		return
+	}
+	var raw struct {
+		Env map[string]string `json:"env"`
+	}
+	if json.Unmarshal(data, &raw) != nil {
+		return
+	}
+	if err := deleteSecrets(s.secrets, workspaceSecrets(id), raw.Env); err != nil {
+		logging.Warn("Could not delete the secrets of workspace %s: %v", id, err)
+	}
+}
+
+// MigrateSecrets re-saves every workspace whose metadata still holds a
+// secret-looking env value in plaintext, moving it to the secret store. It
+// returns the number of workspaces migrated.
+func (s *WorkspaceStore) MigrateSecrets() (int, error) {
+	if s.secrets == nil {
+		return 0, nil
+	}
+	ids, err := s.List()
+	if err != nil {
+		return 0, err
+	}
+	migrated := 0
+	for _, id := range ids {
+		if !s.hasPlaintextSecrets(id) {
+			continue
+		}
+		ws, err := s.Load(id)
+		if err != nil {
+			return migrated, fmt.Errorf("migrate secrets: %w", err)
+		}
+		if err := s.Save(ws); err != nil {
+			return migrated, fmt.Errorf("migrate secrets: %w", err)
+		}
+		migrated++
+	}
+	return migrated, nil
+}
+
+func (s *WorkspaceStore) hasPlaintextSecrets(id WorkspaceID) bool {
+	data, err := s.readWorkspaceMetadata(id)
+	return err == nil && holdsPlaintextSecrets(data)
+}
+
+// holdsPlaintextSecrets reports whether workspace metadata holds a
+// secret-looking env value in plaintext.
+func holdsPlaintextSecrets(data []byte) bool {
+	var raw struct {
+		Env map[string]string `json:"env"`
+	}
+	if json.Unmarshal(data, &raw) != nil {
+		return false
+	}
+	for key, value := range raw.Env {
+		if IsSecretEnvKey(key) && value != "" && !strings.HasPrefix(value, secretRefPrefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// scrubBackup replaces the backup the last save kept of id's metadata with
+// the metadata just written when the backup still holds plaintext secrets,
+// so sealing them does not leave a copy behind. The caller holds the
+// workspace lock.
+func (s *WorkspaceStore) scrubBackup(id WorkspaceID) {
+	if s.secrets == nil {
+		return
+	}
+	backupPath := s.workspaceBackupPath(id)
+	backup, err := os.ReadFile(backupPath)
+	if err != nil || !holdsPlaintextSecrets(backup) {
+		return
+	}
+	current, err := os.ReadFile(s.workspacePath(id))
+	if err == nil && !holdsPlaintextSecrets(current) && fsatomic.WriteFile(backupPath, current, 0o644) == nil {
+		return
+	}
+	if err := os.Remove(backupPath); err != nil && !os.IsNotExist(err) {
+		logging.Warn("Could not remove the plaintext backup of workspace %s: %v", id, err)
+	}
+}
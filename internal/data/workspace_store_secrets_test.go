@@ -0,0 +1,220 @@
+package data
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+type memorySecrets struct {
+	values map[string]string
+	setErr error
+}
+
+func newMemorySecrets() *memorySecrets { return &memorySecrets{values: make(map[string]string)} }
+
+func (m *memorySecrets) Get(key string) (string, error) {
+	value, ok := m.values[key]
+	if !ok {
+		return "", errors.New("not found")
+	}
+	return value, nil
+}
+
+func (m *memorySecrets) Set(key, value string) error {
+	if m.setErr != nil {
+		return m.setErr
+	}
+	m.values[key] = value
+	return nil
+}
+
+func (m *memorySecrets) Delete(key string) error {
+	delete(m.values, key)
+	return nil
+}
+
+func TestIsSecretEnvKey(t *testing.T) {
+	for key, want := range map[string]bool{
+		"OPENAI_API_KEY": true,
+		"GITHUB_TOKEN":   true,
+		"db_password":    true,
+		"AWS_SECRET":     true,
+		"NODE_ENV":       false,
+		"PORT":           false,
+		"KEYBOARD":       false,
+	} {
+		if got := IsSecretEnvKey(key); got != want {
+			t.Errorf("IsSecretEnvKey(%q) = %v, want %v", key, got, want)
+		}
+	}
+}
+
+func TestWorkspaceStoreKeepsSecretEnvOutOfMetadata(t *testing.T) {
+	store, id := seedEnvWorkspace(t, nil)
+	secrets := newMemorySecrets()
+	store.SetSecretStore(secrets)
+
+	env := map[string]string{"GITHUB_TOKEN": "ghp_plain", "NODE_ENV": "production"}
+	if err := store.SetEnv(id, env); err != nil {
+		t.Fatalf("SetEnv() error = %v", err)
+	}
+	raw, err := os.ReadFile(store.workspacePath(id))
+	if err != nil {
+		t.Fatalf("read metadata: %v", err)
+	}
+	if strings.Contains(string(raw), "ghp_plain") {
+		t.Fatal("secret env value written to workspace.json in plaintext")
+	}
+	if !strings.Contains(string(raw), "production") {
+		t.Fatal("non-secret env values should stay in workspace.json")
+	}
+
+	reloaded, err := store.Load(id)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if reloaded.Env["GITHUB_TOKEN"] != "ghp_plain" || reloaded.Env["NODE_ENV"] != "production" {
+		t.Fatalf("reloaded Env = %#v, want resolved values", reloaded.Env)
+	}
+	if env["GITHUB_TOKEN"] != "ghp_plain" {
+		t.Fatal("Save must not rewrite the caller's Env map")
+	}
+}
+
+func TestWorkspaceStoreKeepsPlaintextWhenSecretStoreFails(t *testing.T) {
+	store, id := seedEnvWorkspace(t, nil)
+	secrets := newMemorySecrets()
+	secrets.setErr = errors.New("keyring locked")
+	store.SetSecretStore(secrets)
+
+	if err := store.SetEnv(id, map[string]string{"API_KEY": "k"}); err != nil {
+		t.Fatalf("SetEnv() error = %v", err)
+	}
+	reloaded, err := store.Load(id)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if reloaded.Env["API_KEY"] != "k" {
+		t.Fatalf("Env = %#v, want the value kept rather than lost", reloaded.Env)
+	}
+}
+
+func TestWorkspaceStoreMigrateSecrets(t *testing.T) {
+	store, id := seedEnvWorkspace(t, map[string]string{"API_KEY": "legacy", "PORT": "3000"})
+	secrets := newMemorySecrets()
+	store.SetSecretStore(secrets)
+
+	n, err := store.MigrateSecrets()
+	if err != nil || n != 1 {
+		t.Fatalf("MigrateSecrets() = %d, %v; want 1 workspace migrated", n, err)
+	}
+	// Neither the metadata nor the backup kept by the save may hold it.
+	err = filepath.WalkDir(store.root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		raw, err := os.ReadFile(path)
+		if err == nil && strings.Contains(string(raw), "legacy") {
+			t.Errorf("migration left the secret in plaintext in %s", filepath.Base(path))
+		}
+		return err
+	})
+	if err != nil {
+		t.Fatalf("walk metadata: %v", err)
+	}
+	if _, err := os.Stat(store.workspaceBackupPath(id)); err != nil {
+		t.Fatalf("expected the sealed metadata kept as the backup: %v", err)
+	}
+	if n, err := store.MigrateSecrets(); err != nil || n != 0 {
+		t.Fatalf("second MigrateSecrets() = %d, %v; want nothing left to migrate", n, err)
+	}
+	reloaded, err := store.Load(id)
+	if err != nil || reloaded.Env["API_KEY"] != "legacy" {
+		t.Fatalf("Load() = %#v, %v; want the migrated value", reloaded, err)
+	}
+}
+
+func TestWorkspaceTrashSealsSecretEnv(t *testing.T) {
+	trash := NewWorkspaceTrash(t.TempDir())
+	trash.SetSecretStore(newMemorySecrets())
+	ws := NewWorkspace("feature", "feature", "main", "/repo", "/repo/feature")
+	ws.Env["GITHUB_TOKEN"] = "ghp_plain"
+
+	if err := trash.Put(ws, ""); err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+	raw, err := os.ReadFile(trash.entryPath(ws.ID()))
+	if err != nil {
+		t.Fatalf("read trash entry: %v", err)
+	}
+	if strings.Contains(string(raw), "ghp_plain") {
+		t.Fatal("trash entry holds the secret in plaintext")
+	}
+	entry, err := trash.Get(ws.ID())
+	if err != nil || entry.Workspace.Env["GITHUB_TOKEN"] != "ghp_plain" {
+		t.Fatalf("Get() = %#v, %v; want the resolved secret", entry, err)
+	}
+}
+
+func TestDeletingWorkspaceAndPurgingTrashDeleteSecrets(t *testing.T) {
+	store, id := seedEnvWorkspace(t, nil)
+	trash := NewWorkspaceTrash(t.TempDir())
+	secrets := newMemorySecrets()
+	store.SetSecretStore(secrets)
+	trash.SetSecretStore(secrets)
+	if err := store.SetEnv(id, map[string]string{"API_KEY": "k"}); err != nil {
+		t.Fatalf("SetEnv() error = %v", err)
+	}
+	ws, err := store.Load(id)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	// Deleting keeps the trash entry's copy, so the delete can be undone.
+	if err := trash.Put(ws, ""); err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+	if err := store.Delete(id); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if len(secrets.values) != 1 {
+		t.Fatalf("secrets = %v, want only the trash entry's", secrets.values)
+	}
+	entry, err := trash.Get(id)
+	if err != nil || entry.Workspace.Env["API_KEY"] != "k" {
+		t.Fatalf("Get() = %#v, %v; want the secret still resolvable", entry, err)
+	}
+
+	// Purging the entry drops its copy too.
+	if err := trash.Remove(id); err != nil {
+		t.Fatalf("Remove() error = %v", err)
+	}
+	if len(secrets.values) != 0 {
+		t.Fatalf("secrets = %v, want none left", secrets.values)
+	}
+}
+
+func TestPurgingTrashedProjectDeletesSecrets(t *testing.T) {
+	trash := NewWorkspaceTrash(t.TempDir())
+	secrets := newMemorySecrets()
+	trash.SetSecretStore(secrets)
+	ws := NewWorkspace("feature", "feature", "main", "/repo", "/repo/feature")
+	ws.Env["GITHUB_TOKEN"] = "ghp_plain"
+
+	if err := trash.PutProject("/repo", false, []Workspace{*ws}); err != nil {
+		t.Fatalf("PutProject() error = %v", err)
+	}
+	if len(secrets.values) != 1 {
+		t.Fatalf("secrets = %v, want the project entry's", secrets.values)
+	}
+	if err := trash.RemoveProject("/repo"); err != nil {
+		t.Fatalf("RemoveProject() error = %v", err)
+	}
+	if len(secrets.values) != 0 {
+		t.Fatalf("secrets = %v, want none left", secrets.values)
+	}
+}
@@ -23,8 +23,16 @@ type workspaceJSON struct {
 	Scripts        ScriptsConfig     `json:"scripts"`
 	ScriptMode     string            `json:"script_mode"`
 	Env            map[string]string `json:"env"`
+	Sandbox        bool              `json:"sandbox,omitempty"`
+	NoNetwork      bool              `json:"no_network,omitempty"`
+	GPUs           string            `json:"gpus,omitempty"`
+	Favorite       bool              `json:"favorite,omitempty"`
+	Container      *Container        `json:"container,omitempty"`
 	OpenTabs       []TabInfo         `json:"open_tabs,omitempty"`
 	ActiveTabIndex int               `json:"active_tab_index"`
+	Launches       []Launch          `json:"launches,omitempty"`
+	LayoutApplied  bool              `json:"layout_applied,omitempty"`
+	History        []HistoryEvent    `json:"history,omitempty"`
 }
 
 // parseCreated parses a created timestamp from either time.Time format or string format
@@ -27,6 +27,9 @@ func (s *WorkspaceStore) MarkDeleting(id WorkspaceID) error {
 	if err := validateWorkspaceID(id); err != nil {
 		return err
 	}
+	if err := s.writable(); err != nil {
+		return err
+	}
 	dir := filepath.Join(s.root, string(id))
 	if err := os.MkdirAll(dir, 0o700); err != nil {
 		return err
@@ -49,6 +52,9 @@ func (s *WorkspaceStore) ClearDeleting(id WorkspaceID) error {
 	if err := validateWorkspaceID(id); err != nil {
 		return err
 	}
+	if err := s.writable(); err != nil {
+		return err
+	}
 	if err := os.Remove(s.deletingMarkerPath(id)); err != nil && !errors.Is(err, fs.ErrNotExist) {
 		return err
 	}
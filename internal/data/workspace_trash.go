@@ -0,0 +1,235 @@
+package data
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/andyrewlee/amux/internal/fsatomic"
+	"github.com/andyrewlee/amux/internal/logging"
+)
+
+// DefaultTrashRetention is how long a deleted workspace's metadata stays in the
+// trash before it is pruned.
+const DefaultTrashRetention = 7 * 24 * time.Hour
+
+const (
+	trashEntrySuffix   = ".json"
+	trashChangesSuffix = ".patch"
+)
+
+// TrashedWorkspace is a deleted workspace's metadata kept in the trash so the
+// deletion can be undone while the entry is retained.
+type TrashedWorkspace struct {
+	Workspace Workspace `json:"workspace"`
+	DeletedAt time.Time `json:"deleted_at"`
+	// HeadCommit is the worktree's HEAD when it was deleted; restoring
+	// recreates the branch from it. Empty when it could not be resolved.
+	HeadCommit string `json:"head_commit,omitempty"`
+	// HasChanges reports whether the worktree's uncommitted changes were
+	// kept alongside the entry; see WorkspaceTrash.Changes.
+	HasChanges bool `json:"-"`
+}
+
+// ID returns the trashed workspace's ID (the ID it had before deletion).
+func (t TrashedWorkspace) ID() WorkspaceID {
+	return t.Workspace.ID()
+}
+
+// WorkspaceTrash stores the metadata of deleted workspaces, one JSON file per
+// workspace ID. Re-deleting a workspace with the same ID replaces its entry.
+type WorkspaceTrash struct {
+	readOnly
+	root    string // ~/.amux/trash
+	now     func() time.Time
+	secrets SecretStore
+}
+
+// NewWorkspaceTrash creates a trash rooted at root.
+func NewWorkspaceTrash(root string) *WorkspaceTrash {
+	return &WorkspaceTrash{root: root, now: time.Now}
+}
+
+func (t *WorkspaceTrash) clock() time.Time {
+	if t.now != nil {
+		return t.now()
+	}
+	return time.Now()
+}
+
+func (t *WorkspaceTrash) entryPath(id WorkspaceID) string {
+	return filepath.Join(t.root, string(id)+trashEntrySuffix)
+}
+
+func (t *WorkspaceTrash) changesPath(id WorkspaceID) string {
+	return filepath.Join(t.root, string(id)+trashChangesSuffix)
+}
+
+// Put records ws as deleted now, with the worktree HEAD it was deleted at.
+func (t *WorkspaceTrash) Put(ws *Workspace, headCommit string) error {
+	if ws == nil {
+		return errors.New("workspace is required")
+	}
+	if err := t.writable(); err != nil {
+		return err
+	}
+	id := ws.ID()
+	if err := validateWorkspaceID(id); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(t.root, 0o700); err != nil {
+		return err
+	}
+	entry := TrashedWorkspace{
+		Workspace:  *sealEnv(t.secrets, trashSecrets(id), ws),
+		DeletedAt:  t.clock(),
+		HeadCommit: strings.TrimSpace(headCommit),
+	}
+	entry.Workspace.OpenTabs = nil
+	return fsatomic.WriteJSON(t.entryPath(id), entry)
+}
+
+// Get returns the trashed entry for id.
+func (t *WorkspaceTrash) Get(id WorkspaceID) (*TrashedWorkspace, error) {
+	if err := validateWorkspaceID(id); err != nil {
+		return nil, err
+	}
+	raw, err := os.ReadFile(t.entryPath(id))
+	if err != nil {
+		return nil, err
+	}
+	var entry TrashedWorkspace
+	if err := json.Unmarshal(raw, &entry); err != nil {
+		return nil, err
+	}
+	openEnv(t.secrets, &entry.Workspace)
+	if _, err := os.Stat(t.changesPath(id)); err == nil {
+		entry.HasChanges = true
+	}
+	return &entry, nil
+}
+
+// PutChanges keeps a patch of the trashed workspace's uncommitted changes
+// next to its entry, replacing any earlier one. An empty patch removes it.
+func (t *WorkspaceTrash) PutChanges(id WorkspaceID, patch []byte) error {
+	if err := validateWorkspaceID(id); err != nil {
+		return err
+	}
+	if err := t.writable(); err != nil {
+		return err
+	}
+	if len(patch) == 0 {
+		if err := os.Remove(t.changesPath(id)); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		return nil
+	}
+	if err := os.MkdirAll(t.root, 0o700); err != nil {
+		return err
+	}
+	return fsatomic.WriteFile(t.changesPath(id), patch, 0o600)
+}
+
+// Changes returns the patch kept by PutChanges for id, or nil when the
+// workspace had no uncommitted changes.
+func (t *WorkspaceTrash) Changes(id WorkspaceID) ([]byte, error) {
+	if err := validateWorkspaceID(id); err != nil {
+		return nil, err
+	}
+	patch, err := os.ReadFile(t.changesPath(id))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	return patch, err
+}
+
+// List returns every trashed workspace, most recently deleted first. Entries
+// that cannot be read are logged and skipped.
+func (t *WorkspaceTrash) List() ([]TrashedWorkspace, error) {
+	dirEntries, err := os.ReadDir(t.root)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var entries []TrashedWorkspace
+	for _, dirEntry := range dirEntries {
+		name := dirEntry.Name()
+		if dirEntry.IsDir() || !strings.HasSuffix(name, trashEntrySuffix) {
+			continue
+		}
+		entry, err := t.Get(WorkspaceID(strings.TrimSuffix(name, trashEntrySuffix)))
+		if err != nil {
+			logging.Warn("trash: skipping unreadable entry %s: %v", name, err)
+			continue
+		}
+		entries = append(entries, *entry)
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].DeletedAt.After(entries[j].DeletedAt)
+	})
+	return entries, nil
+}
+
+// trashSecrets is where a trashed workspace's secret env values are kept.
+func trashSecrets(id WorkspaceID) string {
+	return "trash/workspace/" + string(id) + "/"
+}
+
+// Remove drops the trashed entry for id, the secrets it keeps, and its kept
+// changes. A missing entry is not an error.
+func (t *WorkspaceTrash) Remove(id WorkspaceID) error {
+	if err := validateWorkspaceID(id); err != nil {
+		return err
+	}
+	if err := t.writable(); err != nil {
+		return err
+	}
+	var errs []error
+	if raw, err := os.ReadFile(t.entryPath(id)); err == nil {
+		var entry TrashedWorkspace
+		if json.Unmarshal(raw, &entry) == nil {
+			errs = append(errs, deleteSecrets(t.secrets, trashSecrets(id), entry.Workspace.Env))
+		}
+	}
+	for _, path := range []string{t.entryPath(id), t.changesPath(id)} {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Prune removes workspace and project entries deleted more than retention
+// ago and returns how many were removed. A non-positive retention, or a
+// read-only trash, keeps everything.
+func (t *WorkspaceTrash) Prune(retention time.Duration) (int, error) {
+	if retention <= 0 || t.writable() != nil {
+		return 0, nil
+	}
+	entries, err := t.List()
+	if err != nil {
+		return 0, err
+	}
+	cutoff := t.clock().Add(-retention)
+	removed := 0
+	var errs []error
+	for _, entry := range entries {
+		if entry.DeletedAt.After(cutoff) {
+			continue
+		}
+		if err := t.Remove(entry.ID()); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		removed++
+	}
+	projects, err := t.pruneProjects(cutoff)
+	errs = append(errs, err)
+	return removed + projects, errors.Join(errs...)
+}
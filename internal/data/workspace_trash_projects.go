@@ -0,0 +1,163 @@
+package data
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/andyrewlee/amux/internal/fsatomic"
+	"github.com/andyrewlee/amux/internal/logging"
+)
+
+// trashProjectsDir holds removed projects, apart from the workspace entries.
+const trashProjectsDir = "projects"
+
+// TrashedProject is a removed project kept in the trash with its workspaces'
+// metadata. Removing a project leaves the repository and worktrees on disk,
+// so restoring it only needs the registry entry and the metadata back.
+type TrashedProject struct {
+	Path       string      `json:"path"`
+	Favorite   bool        `json:"favorite,omitempty"`
+	Workspaces []Workspace `json:"workspaces,omitempty"`
+	DeletedAt  time.Time   `json:"deleted_at"`
+}
+
+// Name returns the project's display name.
+func (p TrashedProject) Name() string {
+	return filepath.Base(p.Path)
+}
+
+func (t *WorkspaceTrash) projectEntryPath(path string) string {
+	return filepath.Join(t.root, trashProjectsDir, projectKey(path)+trashEntrySuffix)
+}
+
+func projectKey(path string) string {
+	sum := sha256.Sum256([]byte(NormalizePath(path)))
+	return hex.EncodeToString(sum[:8])
+}
+
+// trashProjectSecrets is where the secret env values of a workspace of a
+// trashed project are kept, apart from its own trash entry's.
+func trashProjectSecrets(path string, id WorkspaceID) string {
+	return "trash/project/" + projectKey(path) + "/" + string(id) + "/"
+}
+
+// PutProject records the project at path as removed now, with its
+// workspaces. Removing the same path again replaces its entry.
+func (t *WorkspaceTrash) PutProject(path string, favorite bool, workspaces []Workspace) error {
+	path = NormalizePath(path)
+	if path == "" {
+		return errors.New("project path is required")
+	}
+	if err := t.writable(); err != nil {
+		return err
+	}
+	entry := TrashedProject{Path: path, Favorite: favorite, DeletedAt: t.clock()}
+	for i := range workspaces {
+		ws := *sealEnv(t.secrets, trashProjectSecrets(path, workspaces[i].ID()), &workspaces[i])
+		ws.OpenTabs = nil
+		entry.Workspaces = append(entry.Workspaces, ws)
+	}
+	if err := os.MkdirAll(filepath.Join(t.root, trashProjectsDir), 0o700); err != nil {
+		return err
+	}
+	return fsatomic.WriteJSON(t.projectEntryPath(path), entry)
+}
+
+// Project returns the trashed entry for the project at path.
+func (t *WorkspaceTrash) Project(path string) (*TrashedProject, error) {
+	return t.readProject(t.projectEntryPath(path))
+}
+
+func (t *WorkspaceTrash) readProject(entryPath string) (*TrashedProject, error) {
+	raw, err := os.ReadFile(entryPath)
+	if err != nil {
+		return nil, err
+	}
+	var entry TrashedProject
+	if err := json.Unmarshal(raw, &entry); err != nil {
+		return nil, err
+	}
+	for i := range entry.Workspaces {
+		openEnv(t.secrets, &entry.Workspaces[i])
+	}
+	return &entry, nil
+}
+
+// Projects returns every trashed project, most recently removed first.
+// Entries that cannot be read are logged and skipped.
+func (t *WorkspaceTrash) Projects() ([]TrashedProject, error) {
+	dir := filepath.Join(t.root, trashProjectsDir)
+	dirEntries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var entries []TrashedProject
+	for _, dirEntry := range dirEntries {
+		name := dirEntry.Name()
+		if dirEntry.IsDir() || !strings.HasSuffix(name, trashEntrySuffix) {
+			continue
+		}
+		entry, err := t.readProject(filepath.Join(dir, name))
+		if err != nil {
+			logging.Warn("trash: skipping unreadable project entry %s: %v", name, err)
+			continue
+		}
+		entries = append(entries, *entry)
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].DeletedAt.After(entries[j].DeletedAt)
+	})
+	return entries, nil
+}
+
+// RemoveProject drops the trashed entry for the project at path and the
+// secrets it keeps. A missing entry is not an error.
+func (t *WorkspaceTrash) RemoveProject(path string) error {
+	if err := t.writable(); err != nil {
+		return err
+	}
+	var errs []error
+	if raw, err := os.ReadFile(t.projectEntryPath(path)); err == nil {
+		var entry TrashedProject
+		if json.Unmarshal(raw, &entry) == nil {
+			for _, ws := range entry.Workspaces {
+				errs = append(errs, deleteSecrets(t.secrets, trashProjectSecrets(path, ws.ID()), ws.Env))
+			}
+		}
+	}
+	if err := os.Remove(t.projectEntryPath(path)); err != nil && !os.IsNotExist(err) {
+		errs = append(errs, err)
+	}
+	return errors.Join(errs...)
+}
+
+// pruneProjects removes project entries removed before cutoff.
+func (t *WorkspaceTrash) pruneProjects(cutoff time.Time) (int, error) {
+	entries, err := t.Projects()
+	if err != nil {
+		return 0, err
+	}
+	removed := 0
+	var errs []error
+	for _, entry := range entries {
+		if entry.DeletedAt.After(cutoff) {
+			continue
+		}
+		if err := t.RemoveProject(entry.Path); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		removed++
+	}
+	return removed, errors.Join(errs...)
+}
@@ -0,0 +1,166 @@
+package data
+
+import (
+	"os"
+	"testing"
+	"time"
+)
+
+func TestWorkspaceTrashPutListRemove(t *testing.T) {
+	trash := NewWorkspaceTrash(t.TempDir())
+	now := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	trash.now = func() time.Time { return now }
+
+	older := &Workspace{Name: "older", Repo: "/repo", Root: "/repo/older", Branch: "older"}
+	newer := &Workspace{
+		Name: "newer", Repo: "/repo", Root: "/repo/newer", Branch: "newer",
+		OpenTabs: []TabInfo{{Assistant: "claude", Name: "claude"}},
+	}
+	if err := trash.Put(older, "abc123\n"); err != nil {
+		t.Fatalf("Put(older): %v", err)
+	}
+	now = now.Add(time.Minute)
+	if err := trash.Put(newer, ""); err != nil {
+		t.Fatalf("Put(newer): %v", err)
+	}
+
+	entries, err := trash.List()
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("len(entries) = %d, want 2", len(entries))
+	}
+	if entries[0].Workspace.Name != "newer" || entries[1].Workspace.Name != "older" {
+		t.Fatalf("entries not newest-first: %q, %q", entries[0].Workspace.Name, entries[1].Workspace.Name)
+	}
+	if entries[1].HeadCommit != "abc123" {
+		t.Fatalf("HeadCommit = %q, want trimmed abc123", entries[1].HeadCommit)
+	}
+	if entries[0].ID() != newer.ID() {
+		t.Fatalf("ID() = %q, want %q", entries[0].ID(), newer.ID())
+	}
+	if len(entries[0].Workspace.OpenTabs) != 0 {
+		t.Fatal("trashed metadata must not keep open tabs of killed sessions")
+	}
+
+	if err := trash.Remove(older.ID()); err != nil {
+		t.Fatalf("Remove: %v", err)
+	}
+	if _, err := trash.Get(older.ID()); !os.IsNotExist(err) {
+		t.Fatalf("Get after Remove err = %v, want not-exist", err)
+	}
+	if err := trash.Remove(older.ID()); err != nil {
+		t.Fatalf("Remove (missing) must not fail: %v", err)
+	}
+}
+
+func TestWorkspaceTrashListMissingRoot(t *testing.T) {
+	trash := NewWorkspaceTrash(t.TempDir() + "/missing")
+	entries, err := trash.List()
+	if err != nil || len(entries) != 0 {
+		t.Fatalf("List on missing root = %v, %v; want empty, nil", entries, err)
+	}
+}
+
+func TestWorkspaceTrashPrune(t *testing.T) {
+	trash := NewWorkspaceTrash(t.TempDir())
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	trash.now = func() time.Time { return now }
+
+	stale := &Workspace{Name: "stale", Repo: "/repo", Root: "/repo/stale"}
+	fresh := &Workspace{Name: "fresh", Repo: "/repo", Root: "/repo/fresh"}
+	if err := trash.Put(stale, ""); err != nil {
+		t.Fatalf("Put(stale): %v", err)
+	}
+	now = now.Add(6 * 24 * time.Hour)
+	if err := trash.Put(fresh, ""); err != nil {
+		t.Fatalf("Put(fresh): %v", err)
+	}
+	now = now.Add(2 * 24 * time.Hour)
+
+	if removed, err := trash.Prune(0); err != nil || removed != 0 {
+		t.Fatalf("Prune(0) = %d, %v; want 0, nil", removed, err)
+	}
+	removed, err := trash.Prune(DefaultTrashRetention)
+	if err != nil {
+		t.Fatalf("Prune: %v", err)
+	}
+	if removed != 1 {
+		t.Fatalf("removed = %d, want 1", removed)
+	}
+	entries, _ := trash.List()
+	if len(entries) != 1 || entries[0].Workspace.Name != "fresh" {
+		t.Fatalf("entries after prune = %+v, want only fresh", entries)
+	}
+}
+
+func TestWorkspaceTrashChanges(t *testing.T) {
+	trash := NewWorkspaceTrash(t.TempDir())
+	ws := &Workspace{Name: "feature", Repo: "/repo", Root: "/repo/feature"}
+	if err := trash.Put(ws, "abc123"); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+	if patch, err := trash.Changes(ws.ID()); err != nil || patch != nil {
+		t.Fatalf("Changes before PutChanges = %q, %v; want nil", patch, err)
+	}
+	if err := trash.PutChanges(ws.ID(), []byte("diff --git a/x b/x\n")); err != nil {
+		t.Fatalf("PutChanges: %v", err)
+	}
+	entry, err := trash.Get(ws.ID())
+	if err != nil || !entry.HasChanges {
+		t.Fatalf("Get = %+v, %v; want HasChanges", entry, err)
+	}
+	if patch, _ := trash.Changes(ws.ID()); string(patch) != "diff --git a/x b/x\n" {
+		t.Fatalf("Changes = %q", patch)
+	}
+	if err := trash.Remove(ws.ID()); err != nil {
+		t.Fatalf("Remove: %v", err)
+	}
+	if patch, err := trash.Changes(ws.ID()); err != nil || patch != nil {
+		t.Fatalf("Changes after Remove = %q, %v; want the patch removed too", patch, err)
+	}
+}
+
+func TestWorkspaceTrashProjects(t *testing.T) {
+	trash := NewWorkspaceTrash(t.TempDir())
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	trash.now = func() time.Time { return now }
+
+	workspaces := []Workspace{{Name: "feature", Repo: "/repo/api", Root: "/wt/api/feature", OpenTabs: []TabInfo{{Name: "claude"}}}}
+	if err := trash.PutProject("/repo/api", true, workspaces); err != nil {
+		t.Fatalf("PutProject: %v", err)
+	}
+	now = now.Add(6 * 24 * time.Hour)
+	if err := trash.PutProject("/repo/web", false, nil); err != nil {
+		t.Fatalf("PutProject: %v", err)
+	}
+	if err := trash.Put(&Workspace{Name: "ws", Repo: "/repo/web", Root: "/wt/web/ws"}, ""); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+
+	projects, err := trash.Projects()
+	if err != nil || len(projects) != 2 || projects[0].Name() != "web" || projects[1].Name() != "api" {
+		t.Fatalf("Projects = %+v, %v; want web then api", projects, err)
+	}
+	if api := projects[1]; !api.Favorite || len(api.Workspaces) != 1 || len(api.Workspaces[0].OpenTabs) != 0 {
+		t.Fatalf("api entry = %+v, want the favorite with its workspace and no tabs", api)
+	}
+	if entries, _ := trash.List(); len(entries) != 1 {
+		t.Fatalf("List = %+v, want only the workspace entry", entries)
+	}
+
+	now = now.Add(2 * 24 * time.Hour)
+	if removed, err := trash.Prune(DefaultTrashRetention); err != nil || removed != 1 {
+		t.Fatalf("Prune = %d, %v; want the api project pruned", removed, err)
+	}
+	if _, err := trash.Project("/repo/api"); !os.IsNotExist(err) {
+		t.Fatalf("Project(api) err = %v, want not-exist", err)
+	}
+	if err := trash.RemoveProject("/repo/web"); err != nil {
+		t.Fatalf("RemoveProject: %v", err)
+	}
+	if projects, _ := trash.Projects(); len(projects) != 0 {
+		t.Fatalf("Projects after RemoveProject = %+v", projects)
+	}
+}
@@ -0,0 +1,133 @@
+// Package devenv loads a worktree's declared development environment, from
+// its .envrc (direnv) or flake.nix (nix develop), around the shells and
+// agents amux launches in it.
+//
+// Commands are wrapped rather than given a precomputed environment, so each
+// tab sees the environment as it is when the tab starts. If the environment
+// fails to load (an .envrc not yet allowed, a flake that doesn't evaluate),
+// the wrapped command still runs, without it, after a warning.
+package devenv
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/andyrewlee/amux/internal/shellutil"
+)
+
+// Kind names an environment loader.
+type Kind string
+
+const (
+	// None means the worktree declares no environment amux can load.
+	None   Kind = ""
+	Direnv Kind = "direnv"
+	Nix    Kind = "nix"
+)
+
+// EnvVar turns environment loading off when set to "off".
+const EnvVar = "AMUX_DEVENV"
+
+var (
+	lookPath = exec.LookPath
+	// run runs a loader command in dir and returns its combined output;
+	// tests replace it.
+	run = func(ctx context.Context, dir, name string, args ...string) (string, error) {
+		cmd := exec.CommandContext(ctx, name, args...)
+		cmd.Dir = dir
+		out, err := cmd.CombinedOutput()
+		return strings.TrimSpace(string(out)), err
+	}
+)
+
+// Detect returns the loader for the worktree at root: direnv when it has an
+// .envrc, else nix when it has a flake.nix, provided the tool is installed.
+// An .envrc wins because it commonly loads the flake itself ("use flake").
+func Detect(root string) Kind {
+	if root == "" || os.Getenv(EnvVar) == "off" {
+		return None
+	}
+	for _, c := range []struct {
+		file string
+		kind Kind
+	}{{".envrc", Direnv}, {"flake.nix", Nix}} {
+		if _, err := os.Stat(filepath.Join(root, c.file)); err != nil {
+			continue
+		}
+		if _, err := lookPath(string(c.kind)); err == nil {
+			return c.kind
+		}
+	}
+	return None
+}
+
+// loader returns the command that runs the rest of its arguments in root's
+// environment.
+func loader(kind Kind, root string) []string {
+	switch kind {
+	case Direnv:
+		return []string{"direnv", "exec", root}
+	case Nix:
+		return []string{"nix", "develop", root, "--command"}
+	}
+	return nil
+}
+
+// Wrap returns a shell command line that runs command in root's environment.
+// A marker file, removed once the environment has loaded, tells a loader
+// failure apart from command's own exit status; on a loader failure command
+// runs without the environment. With kind None command is returned as is.
+func Wrap(kind Kind, root, command string) string {
+	prefix := loader(kind, root)
+	if prefix == nil {
+		return command
+	}
+	quoted := shellutil.ShellQuote(command)
+	warning := shellutil.ShellQuote(fmt.Sprintf("[amux] %s could not load the environment; running without it", kind))
+	return "if amux_env=$(mktemp); then " +
+		joinQuoted(prefix) + ` /bin/sh -c 'rm -f "$0"; eval "$1"' "$amux_env" ` + quoted + "; amux_env_code=$?; " +
+		"else amux_env_code=; fi; " +
+		`if [ -z "$amux_env_code" ] || [ -e "$amux_env" ]; then rm -f "$amux_env"; printf '\r\n%s\r\n' ` + warning + " >&2; " +
+		"/bin/sh -c " + quoted + "; " +
+		`else (exit "$amux_env_code"); fi`
+}
+
+// Check loads root's environment once, without running anything in it, and
+// returns why it failed to load. For nix this also builds the environment,
+// so later tabs start without waiting for it.
+func Check(ctx context.Context, kind Kind, root string) error {
+	prefix := loader(kind, root)
+	if prefix == nil {
+		return nil
+	}
+	out, err := run(ctx, root, prefix[0], append(prefix[1:], "true")...)
+	if err == nil {
+		return nil
+	}
+	if ctx.Err() != nil {
+		return fmt.Errorf("%s: %w", kind, ctx.Err())
+	}
+	if line := lastLine(out); line != "" {
+		return fmt.Errorf("%s: %s", kind, line)
+	}
+	return fmt.Errorf("%s: %w", kind, err)
+}
+
+// lastLine returns the last non-empty line of out, where loaders put the
+// error that stopped them.
+func lastLine(out string) string {
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	return strings.TrimSpace(lines[len(lines)-1])
+}
+
+func joinQuoted(args []string) string {
+	quoted := make([]string, len(args))
+	for i, arg := range args {
+		quoted[i] = shellutil.ShellQuote(arg)
+	}
+	return strings.Join(quoted, " ")
+}
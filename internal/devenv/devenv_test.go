@@ -0,0 +1,106 @@
+package devenv
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// fakeDirenv puts a direnv on PATH that sets LOADED for the command it runs,
+// or fails to load when fail is set.
+func fakeDirenv(t *testing.T, fail bool) {
+	t.Helper()
+	bin := t.TempDir()
+	script := "#!/bin/sh\nshift 2\nLOADED=1 exec \"$@\"\n"
+	if fail {
+		script = "#!/bin/sh\necho 'direnv: error .envrc is blocked' >&2\nexit 1\n"
+	}
+	if err := os.WriteFile(filepath.Join(bin, "direnv"), []byte(script), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", bin+string(os.PathListSeparator)+os.Getenv("PATH"))
+}
+
+func TestDetect(t *testing.T) {
+	lookPath = func(string) (string, error) { return "/bin/true", nil }
+	t.Cleanup(func() { lookPath = exec.LookPath })
+
+	root := t.TempDir()
+	if got := Detect(root); got != None {
+		t.Fatalf("Detect(empty) = %q", got)
+	}
+	if err := os.WriteFile(filepath.Join(root, "flake.nix"), nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if got := Detect(root); got != Nix {
+		t.Fatalf("Detect(flake) = %q", got)
+	}
+	if err := os.WriteFile(filepath.Join(root, ".envrc"), nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if got := Detect(root); got != Direnv {
+		t.Fatalf("Detect(envrc and flake) = %q", got)
+	}
+	t.Setenv(EnvVar, "off")
+	if got := Detect(root); got != None {
+		t.Fatalf("Detect with %s=off = %q", EnvVar, got)
+	}
+}
+
+func TestWrap(t *testing.T) {
+	root := t.TempDir()
+	cases := []struct {
+		name     string
+		fail     bool
+		command  string
+		wantOut  string
+		wantCode int
+	}{
+		{"runs in the environment", false, `echo "loaded=$LOADED"`, "loaded=1", 0},
+		{"keeps the command's status", false, "exit 3", "", 3},
+		{"falls back without the environment", true, `echo "loaded=$LOADED"; exit 4`, "loaded=", 4},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			fakeDirenv(t, tc.fail)
+			cmd := exec.Command("/bin/sh", "-c", Wrap(Direnv, root, tc.command)+`; echo "code=$?"`)
+			out, _ := cmd.Output()
+			lines := strings.Fields(string(out))
+			if len(lines) == 0 || lines[len(lines)-1] != fmt.Sprintf("code=%d", tc.wantCode) {
+				t.Fatalf("output = %q, want code %d", out, tc.wantCode)
+			}
+			if tc.wantOut != "" && lines[0] != tc.wantOut {
+				t.Fatalf("output = %q, want %q", out, tc.wantOut)
+			}
+		})
+	}
+	if got := Wrap(None, root, "true"); got != "true" {
+		t.Fatalf("Wrap(None) = %q", got)
+	}
+}
+
+func TestCheck(t *testing.T) {
+	var got []string
+	old := run
+	run = func(_ context.Context, dir, name string, args ...string) (string, error) {
+		got = append([]string{dir, name}, args...)
+		return "building...\nerror: flake.nix:3: syntax error\n", errors.New("exit status 1")
+	}
+	t.Cleanup(func() { run = old })
+
+	err := Check(context.Background(), Nix, "/repo")
+	if err == nil || err.Error() != "nix: error: flake.nix:3: syntax error" {
+		t.Fatalf("Check() = %v", err)
+	}
+	if strings.Join(got, " ") != "/repo nix develop /repo --command true" {
+		t.Fatalf("ran %q", got)
+	}
+	if err := Check(context.Background(), None, "/repo"); err != nil {
+		t.Fatalf("Check(None) = %v", err)
+	}
+}
@@ -0,0 +1,144 @@
+// Package dictation runs a speech-to-text command and streams the text it
+// transcribes, for typing prompts into an agent by voice.
+package dictation
+
+import (
+	"bufio"
+	"errors"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"github.com/andyrewlee/amux/internal/process"
+	"github.com/andyrewlee/amux/internal/safego"
+)
+
+// stopGrace is how long a stopped command has to transcribe what it heard
+// before it is killed.
+const stopGrace = 5 * time.Second
+
+// Session is one running dictation command.
+type Session struct {
+	cmd      *exec.Cmd
+	done     chan struct{}
+	err      error
+	stopOnce sync.Once
+	stopped  chan struct{}
+}
+
+// Start runs command with `sh -c` in dir and calls emit from another
+// goroutine with each line of text it prints, control characters removed. Lines
+// after the first are prefixed with a space so utterances join into one
+// prompt. Blank lines are skipped.
+func Start(dir, command string, emit func(string)) (*Session, error) {
+	if strings.TrimSpace(command) == "" {
+		return nil, errors.New("empty dictation command")
+	}
+	cmd := exec.Command("sh", "-c", command)
+	cmd.Dir = dir
+	process.SetProcessGroup(cmd)
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	var stderr strings.Builder
+	cmd.Stderr = &limitedWriter{w: &stderr, n: 4096}
+	if err := cmd.Start(); err != nil {
+		return nil, err
+	}
+	s := &Session{cmd: cmd, done: make(chan struct{}), stopped: make(chan struct{})}
+	safego.Go("dictation.read", func() {
+		defer close(s.done)
+		scanner := bufio.NewScanner(stdout)
+		first := true
+		for scanner.Scan() {
+			text := Clean(scanner.Text())
+			if text == "" {
+				continue
+			}
+			if !first {
+				text = " " + text
+			}
+			first = false
+			emit(text)
+		}
+		err := cmd.Wait()
+		select {
+		case <-s.stopped:
+			// Exiting on the interrupt is how a stopped command ends.
+		default:
+			if err != nil {
+				if msg := strings.TrimSpace(stderr.String()); msg != "" {
+					err = errors.New(err.Error() + ": " + msg)
+				}
+				s.err = err
+			}
+		}
+	})
+	return s, nil
+}
+
+// Stop interrupts the command so it can finish transcribing, then kills its
+// process group if it has not exited within a few seconds. Text printed
+// before it exits is still emitted.
+func (s *Session) Stop() {
+	s.stopOnce.Do(func() {
+		close(s.stopped)
+		pid := s.cmd.Process.Pid
+		if err := process.InterruptProcessGroup(pid); err != nil {
+			_ = process.ForceKillProcess(pid)
+			return
+		}
+		safego.Go("dictation.stop", func() {
+			select {
+			case <-s.done:
+			case <-time.After(stopGrace):
+				_ = process.ForceKillProcess(pid)
+			}
+		})
+	})
+}
+
+// Done is closed once the command has exited and all its text was emitted.
+func (s *Session) Done() <-chan struct{} {
+	return s.done
+}
+
+// Err reports why the command failed once Done is closed. A command that
+// exits after Stop is not an error.
+func (s *Session) Err() error {
+	<-s.done
+	return s.err
+}
+
+// Clean trims text and drops control characters, so a transcript can't end a
+// bracketed paste or send escape sequences to the agent.
+func Clean(text string) string {
+	return strings.TrimSpace(strings.Map(func(r rune) rune {
+		if r == '\t' {
+			return ' '
+		}
+		if unicode.IsControl(r) {
+			return -1
+		}
+		return r
+	}, text))
+}
+
+// limitedWriter keeps the first n bytes written to it.
+type limitedWriter struct {
+	mu sync.Mutex
+	w  *strings.Builder
+	n  int
+}
+
+func (l *limitedWriter) Write(p []byte) (int, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if room := l.n - l.w.Len(); room > 0 {
+		l.w.Write(p[:min(len(p), room)])
+	}
+	return len(p), nil
+}
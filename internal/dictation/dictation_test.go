@@ -0,0 +1,73 @@
+//go:build !windows
+
+package dictation
+
+import (
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+type collector struct {
+	mu   sync.Mutex
+	text strings.Builder
+}
+
+func (c *collector) emit(s string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.text.WriteString(s)
+}
+
+func (c *collector) String() string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.text.String()
+}
+
+func TestSessionStreamsTranscript(t *testing.T) {
+	var got collector
+	s, err := Start(t.TempDir(), `printf 'fix the\n\n\033[31mcache\tbug\n'`, got.emit)
+	if err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	if err := s.Err(); err != nil {
+		t.Fatalf("Err() = %v", err)
+	}
+	if got.String() != "fix the [31mcache bug" {
+		t.Fatalf("emitted %q", got.String())
+	}
+}
+
+func TestSessionStopFlushesBeforeExit(t *testing.T) {
+	var got collector
+	// Like a push-to-talk recorder: transcribe what was heard on Ctrl-C.
+	s, err := Start(t.TempDir(), `trap 'echo heard; exit 130' INT; while :; do sleep 0.05; done`, got.emit)
+	if err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	time.Sleep(100 * time.Millisecond)
+	s.Stop()
+	select {
+	case <-s.Done():
+	case <-time.After(5 * time.Second):
+		t.Fatal("dictation did not stop")
+	}
+	if err := s.Err(); err != nil {
+		t.Fatalf("Err() after Stop = %v, want nil", err)
+	}
+	if got.String() != "heard" {
+		t.Fatalf("emitted %q, want the text printed on stop", got.String())
+	}
+}
+
+func TestSessionReportsFailure(t *testing.T) {
+	s, err := Start(t.TempDir(), `echo no microphone >&2; exit 3`, func(string) {})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Err(); err == nil || !strings.Contains(err.Error(), "no microphone") {
+		t.Fatalf("Err() = %v, want the command's stderr", err)
+	}
+}
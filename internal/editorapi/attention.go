@@ -0,0 +1,40 @@
+package editorapi
+
+import (
+	"errors"
+	"time"
+)
+
+// Attention is a worktree whose agent needs attention, as agents.attention
+// reports it.
+type Attention struct {
+	WorkspaceID string `json:"workspace_id"`
+	Workspace   string `json:"workspace"`
+	Project     string `json:"project"`
+	Root        string `json:"root"`
+	// Reason is "done" for an agent that finished, or an alert such as
+	// "mem limit".
+	Reason string    `json:"reason"`
+	Since  time.Time `json:"since"`
+}
+
+// errNoAttention is returned by agents.attention when no TUI has reported
+// the queue, as under `amux editor serve`.
+var errNoAttention = errors.New("only a running amux knows which agents need attention")
+
+// SetAttention records the attention queue, most urgent first, for
+// agents.attention. The TUI calls it each time the queue changes.
+func (s *Server) SetAttention(queue []Attention) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.attention = append([]Attention{}, queue...)
+}
+
+func (s *Server) attentionQueue() ([]Attention, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.attention == nil {
+		return nil, errNoAttention
+	}
+	return append([]Attention{}, s.attention...), nil
+}
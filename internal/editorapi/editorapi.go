@@ -0,0 +1,151 @@
+// Package editorapi serves a local API for editor extensions: listing
+// worktrees, opening or activating one, showing agent states in a status bar,
+// sending selected code with instructions to an agent tab, listing the agents
+// needing attention, and pushing events such as an agent needing attention.
+//
+// The API is JSON-RPC 2.0 over a unix socket in the amux home, one JSON
+// object per line in each direction. The socket is only accessible to its
+// owner, since whoever can connect can type into agents. Methods and fields
+// are only ever added while APIVersion stays the same.
+package editorapi
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/andyrewlee/amux/internal/data"
+	"github.com/andyrewlee/amux/internal/logging"
+	"github.com/andyrewlee/amux/internal/sessions"
+)
+
+// APIVersion is bumped when a method or field is removed or changes meaning.
+const APIVersion = 1
+
+// SocketFile is the socket's name in the amux home.
+const SocketFile = "editor.sock"
+
+// maxMessageBytes bounds one request, which may carry a large selection.
+const maxMessageBytes = 4 << 20
+
+// ErrServing is returned by Listen when another amux serves the socket.
+var ErrServing = errors.New("editor API is already served by another amux")
+
+// SocketPath returns the socket path for the amux home.
+func SocketPath(home string) string {
+	return filepath.Join(home, SocketFile)
+}
+
+// Config supplies the state the API reads and the actions it takes.
+type Config struct {
+	// Version is the amux version reported by amux.version.
+	Version string
+	// Worktrees returns the worktrees amux knows.
+	Worktrees func() ([]*data.Workspace, error)
+	// Sessions returns the running amux sessions.
+	Sessions func() ([]sessions.Session, error)
+	// Open opens root in editor, a command such as code or cursor.
+	Open func(editor, root string) error
+	// Send types text into the agent's session, pressing Enter after it
+	// when submit is set.
+	Send func(agent sessions.Session, text string, submit bool) error
+	// Activate switches the running amux to the worktree, also focusing
+	// its tab when showTab is set.
+	Activate func(ws *data.Workspace, showTab bool) error
+}
+
+// Server answers API requests.
+type Server struct {
+	cfg Config
+
+	mu      sync.Mutex
+	clients map[*client]bool
+	// attention is nil until the TUI reports the queue.
+	attention []Attention
+}
+
+// New returns a server for cfg.
+func New(cfg Config) (*Server, error) {
+	if cfg.Worktrees == nil || cfg.Sessions == nil || cfg.Open == nil || cfg.Send == nil || cfg.Activate == nil {
+		return nil, errors.New("editorapi: Worktrees, Sessions, Open, Send, and Activate are required")
+	}
+	return &Server{cfg: cfg, clients: make(map[*client]bool)}, nil
+}
+
+// Listen listens on the socket at path, replacing a socket left by an amux
+// that exited without removing it.
+func Listen(path string) (net.Listener, error) {
+	if conn, err := net.Dial("unix", path); err == nil {
+		_ = conn.Close()
+		return nil, ErrServing
+	}
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("remove stale socket: %w", err)
+	}
+	ln, err := net.Listen("unix", path)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.Chmod(path, 0o600); err != nil {
+		_ = ln.Close()
+		return nil, err
+	}
+	return ln, nil
+}
+
+// Serve answers connections on ln until it is closed, then closes the
+// connections still open.
+func (s *Server) Serve(ln net.Listener) error {
+	defer s.closeConns()
+	for {
+		conn, err := ln.Accept()
+		if err != nil {
+			if errors.Is(err, net.ErrClosed) {
+				return nil
+			}
+			return err
+		}
+		c := &client{conn: conn, out: make(chan any, clientQueue)}
+		s.mu.Lock()
+		s.clients[c] = true
+		s.mu.Unlock()
+		go c.write()
+		go s.serveClient(c)
+	}
+}
+
+func (s *Server) closeConns() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for c := range s.clients {
+		_ = c.conn.Close()
+	}
+}
+
+func (s *Server) serveClient(c *client) {
+	defer func() {
+		s.mu.Lock()
+		delete(s.clients, c)
+		close(c.out)
+		s.mu.Unlock()
+		_ = c.conn.Close()
+	}()
+	scanner := bufio.NewScanner(c.conn)
+	scanner.Buffer(make([]byte, 0, 64<<10), maxMessageBytes)
+	for scanner.Scan() {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		if resp, ok := s.handle(c, scanner.Bytes()); ok {
+			c.out <- resp
+		}
+	}
+	if err := scanner.Err(); err != nil && !errors.Is(err, io.EOF) && !errors.Is(err, net.ErrClosed) {
+		logging.Debug("editor API connection: %v", err)
+	}
+}
@@ -0,0 +1,247 @@
+package editorapi
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/andyrewlee/amux/internal/data"
+	"github.com/andyrewlee/amux/internal/sessions"
+)
+
+type sent struct {
+	agent  string
+	text   string
+	submit bool
+}
+
+func startServer(t *testing.T, cfg Config) (*Server, *bufio.ReadWriter) {
+	t.Helper()
+	// Socket paths are limited to about 100 bytes, more than t.TempDir can
+	// promise on some systems.
+	dir, err := os.MkdirTemp("", "amux-api")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = os.RemoveAll(dir) })
+	srv, err := New(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ln, err := Listen(SocketPath(dir))
+	if err != nil {
+		t.Fatal(err)
+	}
+	done := make(chan error, 1)
+	go func() { done <- srv.Serve(ln) }()
+	t.Cleanup(func() {
+		_ = ln.Close()
+		if err := <-done; err != nil {
+			t.Errorf("Serve: %v", err)
+		}
+	})
+	conn, err := net.Dial("unix", SocketPath(dir))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+	return srv, bufio.NewReadWriter(bufio.NewReader(conn), bufio.NewWriter(conn))
+}
+
+func roundTrip(t *testing.T, rw *bufio.ReadWriter, msg string) map[string]any {
+	t.Helper()
+	if _, err := rw.WriteString(msg + "\n"); err != nil {
+		t.Fatal(err)
+	}
+	if err := rw.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	line, err := rw.ReadBytes('\n')
+	if err != nil {
+		t.Fatal(err)
+	}
+	var resp map[string]any
+	if err := json.Unmarshal(line, &resp); err != nil {
+		t.Fatalf("response %q: %v", line, err)
+	}
+	return resp
+}
+
+func errorCode(resp map[string]any) int {
+	e, _ := resp["error"].(map[string]any)
+	code, _ := e["code"].(float64)
+	return int(code)
+}
+
+func TestServer(t *testing.T) {
+	feature := data.NewWorkspace("feature", "feature", "main", "/src/api", "/work/api/feature")
+	other := data.NewWorkspace("other", "other", "main", "/src/web", "/work/web/other")
+	list := []sessions.Session{
+		{Name: "amux/api/feature/claude", TmuxSession: "amux-1", Type: "agent", WorkspaceID: string(feature.ID()), State: "working"},
+		{Name: "amux/api/feature/terminal", TmuxSession: "amux-2", Type: "terminal", WorkspaceID: string(feature.ID())},
+		{Name: "amux/web/other/codex", TmuxSession: "amux-3", Type: "agent", WorkspaceID: string(other.ID())},
+	}
+	var opened, activated []string
+	var sends []sent
+	srv, rw := startServer(t, Config{
+		Version:   "1.2.3",
+		Worktrees: func() ([]*data.Workspace, error) { return []*data.Workspace{feature, other}, nil },
+		Sessions:  func() ([]sessions.Session, error) { return list, nil },
+		Open: func(editor, root string) error {
+			opened = append(opened, editor+" "+root)
+			return nil
+		},
+		Send: func(agent sessions.Session, text string, submit bool) error {
+			sends = append(sends, sent{agent.TmuxSession, text, submit})
+			return nil
+		},
+		Activate: func(ws *data.Workspace, showTab bool) error {
+			activated = append(activated, fmt.Sprintf("%s %v", ws.Name, showTab))
+			return nil
+		},
+	})
+
+	resp := roundTrip(t, rw, `{"jsonrpc":"2.0","id":1,"method":"amux.version"}`)
+	if result, _ := resp["result"].(map[string]any); result["version"] != "1.2.3" || result["api_version"] != float64(APIVersion) || resp["id"] != float64(1) {
+		t.Fatalf("amux.version = %v", resp)
+	}
+
+	resp = roundTrip(t, rw, `{"jsonrpc":"2.0","id":2,"method":"worktrees.list"}`)
+	if worktrees, _ := resp["result"].([]any); len(worktrees) != 2 || worktrees[0].(map[string]any)["project"] != "api" {
+		t.Fatalf("worktrees.list = %v", resp)
+	}
+
+	// A path inside a worktree names it, as an editor's open folder would.
+	resp = roundTrip(t, rw, `{"jsonrpc":"2.0","id":3,"method":"worktrees.open","params":{"workspace":"/work/web/other/src"}}`)
+	if resp["error"] != nil || len(opened) != 1 || opened[0] != "code /work/web/other" {
+		t.Fatalf("worktrees.open = %v, opened %v", resp, opened)
+	}
+
+	resp = roundTrip(t, rw, `{"jsonrpc":"2.0","id":4,"method":"agents.status","params":{"workspace":"feature"}}`)
+	agents, _ := resp["result"].([]any)
+	if len(agents) != 1 || agents[0].(map[string]any)["state"] != "working" {
+		t.Fatalf("agents.status = %v", resp)
+	}
+
+	resp = roundTrip(t, rw, `{"jsonrpc":"2.0","id":5,"method":"agents.send","params":{"agent":"api/feature/claude","text":"Explain this","code":"x := 1\n","path":"main.go","language":"go"}}`)
+	if resp["error"] != nil || len(sends) != 1 || !sends[0].submit || sends[0].agent != "amux-1" {
+		t.Fatalf("agents.send = %v, sent %+v", resp, sends)
+	}
+	if want := "Explain this\n\nmain.go:\n```go\nx := 1\n```"; sends[0].text != want {
+		t.Fatalf("sent %q, want %q", sends[0].text, want)
+	}
+
+	resp = roundTrip(t, rw, `{"jsonrpc":"2.0","id":51,"method":"agents.send","params":{"workspace":"/work/api/feature/main.go","text":"Go on","submit":false}}`)
+	if resp["error"] != nil || len(sends) != 2 || sends[1].submit || sends[1].agent != "amux-1" {
+		t.Fatalf("agents.send to a worktree's agent = %v, sent %+v", resp, sends)
+	}
+	sends = sends[:1]
+
+	resp = roundTrip(t, rw, `{"jsonrpc":"2.0","id":52,"method":"worktrees.activate","params":{"workspace":"/work/api/feature/main.go"}}`)
+	if resp["error"] != nil || len(activated) != 1 || activated[0] != "feature false" {
+		t.Fatalf("worktrees.activate = %v, activated %v", resp, activated)
+	}
+	resp = roundTrip(t, rw, `{"jsonrpc":"2.0","id":53,"method":"worktrees.activate","params":{"workspace":"feature","show_tab":true}}`)
+	if resp["error"] != nil || len(activated) != 2 || activated[1] != "feature true" {
+		t.Fatalf("worktrees.activate with show_tab = %v, activated %v", resp, activated)
+	}
+
+	// The attention queue is only known once the TUI reports it.
+	resp = roundTrip(t, rw, `{"jsonrpc":"2.0","id":54,"method":"agents.attention"}`)
+	if errorCode(resp) != codeFailed {
+		t.Fatalf("agents.attention before the TUI reported = %v", resp)
+	}
+	srv.SetAttention(nil)
+	resp = roundTrip(t, rw, `{"jsonrpc":"2.0","id":55,"method":"agents.attention"}`)
+	if queue, ok := resp["result"].([]any); !ok || len(queue) != 0 {
+		t.Fatalf("agents.attention with nothing waiting = %v", resp)
+	}
+	srv.SetAttention([]Attention{{WorkspaceID: string(feature.ID()), Workspace: "feature", Reason: "mem limit"}})
+	resp = roundTrip(t, rw, `{"jsonrpc":"2.0","id":56,"method":"agents.attention"}`)
+	if queue, _ := resp["result"].([]any); len(queue) != 1 || queue[0].(map[string]any)["reason"] != "mem limit" {
+		t.Fatalf("agents.attention = %v", resp)
+	}
+
+	resp = roundTrip(t, rw, `{"jsonrpc":"2.0","id":6,"method":"agents.send","params":{"agent":"api/feature/terminal","text":"ls"}}`)
+	if errorCode(resp) != codeFailed || len(sends) != 1 {
+		t.Fatalf("sending to a terminal = %v", resp)
+	}
+
+	// Events reach a client once it subscribes, only of the kinds it asked
+	// for, as notifications ahead of the next response.
+	srv.Publish(Event{Kind: "attention", Text: "before subscribing"})
+	resp = roundTrip(t, rw, `{"jsonrpc":"2.0","id":60,"method":"events.subscribe","params":{"kinds":["attention"]}}`)
+	if resp["error"] != nil {
+		t.Fatalf("events.subscribe = %v", resp)
+	}
+	srv.Publish(Event{Kind: "git", Text: "not asked for"})
+	srv.Publish(Event{Kind: "attention", WorkspaceID: string(feature.ID()), Text: "claude reached its memory limit"})
+	resp = roundTrip(t, rw, `{"jsonrpc":"2.0","id":61,"method":"events.unsubscribe"}`)
+	if params, _ := resp["params"].(map[string]any); resp["method"] != "event" || params["text"] != "claude reached its memory limit" {
+		t.Fatalf("event = %v", resp)
+	}
+	if line, err := rw.ReadBytes('\n'); err != nil || !strings.Contains(string(line), `"id":61`) {
+		t.Fatalf("unsubscribe response = %s, %v", line, err)
+	}
+
+	// A notification gets no response; the next request's response is the
+	// next line.
+	if _, err := rw.WriteString(`{"jsonrpc":"2.0","method":"amux.version"}` + "\n"); err != nil {
+		t.Fatal(err)
+	}
+	for msg, code := range map[string]int{
+		`{"jsonrpc":"2.0","id":7,"method":"nope"}`:                                    codeMethodNotFound,
+		`{"jsonrpc":"2.0","id":8,"method":"agents.send","params":{"agent":"a"}}`:      codeInvalidParams,
+		`{"jsonrpc":"2.0","id":9,"method":"worktrees.open","params":{"workspace":7}}`: codeInvalidParams,
+		`{"id":10,"method":"amux.version"}`:                                           codeInvalidRequest,
+		`{not json`:                                                                   codeParseError,
+	} {
+		if resp := roundTrip(t, rw, msg); errorCode(resp) != code {
+			t.Errorf("%s = %v, want error %d", msg, resp, code)
+		}
+	}
+}
+
+func TestListen(t *testing.T) {
+	dir, err := os.MkdirTemp("", "amux-api")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = os.RemoveAll(dir) })
+	path := filepath.Join(dir, SocketFile)
+
+	// A socket nobody listens on is replaced.
+	stale, err := net.Listen("unix", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stale.(*net.UnixListener).SetUnlinkOnClose(false)
+	_ = stale.Close()
+	ln, err := Listen(path)
+	if err != nil {
+		t.Fatalf("Listen over a stale socket: %v", err)
+	}
+	defer ln.Close()
+	if info, err := os.Stat(path); err != nil || info.Mode().Perm() != 0o600 {
+		t.Fatalf("socket mode = %v, %v", info.Mode(), err)
+	}
+	if _, err := Listen(path); !errors.Is(err, ErrServing) {
+		t.Fatalf("second Listen error = %v, want ErrServing", err)
+	}
+}
+
+func TestCompose(t *testing.T) {
+	if got := Compose("  just text  ", "", "", ""); got != "just text" {
+		t.Fatalf("text only = %q", got)
+	}
+	got := Compose("", "a := \"```\"", "", "")
+	if !strings.HasPrefix(got, "````\n") || !strings.HasSuffix(got, "\n````") {
+		t.Fatalf("code with a fence inside = %q", got)
+	}
+}
@@ -0,0 +1,108 @@
+package editorapi
+
+import (
+	"encoding/json"
+	"net"
+	"slices"
+	"sync"
+	"time"
+)
+
+// clientQueue bounds the messages waiting to be written to one client.
+// Events for a client that falls this far behind are dropped.
+const clientQueue = 64
+
+// Event is something that happened in amux, such as an agent finishing or
+// needing attention. Subscribed clients receive it as an "event"
+// notification. Kinds are the activity feed's: output, done, attention,
+// exit, git, and task.
+type Event struct {
+	Kind        string    `json:"kind"`
+	WorkspaceID string    `json:"workspace_id,omitempty"`
+	Workspace   string    `json:"workspace,omitempty"`
+	Text        string    `json:"text"`
+	At          time.Time `json:"at"`
+}
+
+type notification struct {
+	JSONRPC string `json:"jsonrpc"`
+	Method  string `json:"method"`
+	Params  any    `json:"params"`
+}
+
+type subscribeParams struct {
+	Kinds []string `json:"kinds"`
+}
+
+type subscribeResult struct {
+	Kinds []string `json:"kinds,omitempty"`
+}
+
+// client is one connection. Responses and events go out through one queue,
+// written in order by write.
+type client struct {
+	conn net.Conn
+	out  chan any
+
+	mu         sync.Mutex
+	subscribed bool
+	kinds      []string // empty for every kind
+}
+
+func (c *client) write() {
+	enc := json.NewEncoder(c.conn)
+	var err error
+	for msg := range c.out {
+		if err != nil {
+			continue
+		}
+		if err = enc.Encode(msg); err != nil {
+			_ = c.conn.Close()
+		}
+	}
+}
+
+func (c *client) wants(kind string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.subscribed && (len(c.kinds) == 0 || slices.Contains(c.kinds, kind))
+}
+
+func (c *client) subscribe(raw json.RawMessage) (any, error) {
+	var p subscribeParams
+	if err := decodeParams(raw, &p); err != nil {
+		return nil, err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.subscribed = true
+	c.kinds = p.Kinds
+	return subscribeResult(p), nil
+}
+
+func (c *client) unsubscribe() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.subscribed = false
+	c.kinds = nil
+}
+
+// Publish sends e to the clients subscribed to its kind. It does not block:
+// a client too far behind misses the event.
+func (s *Server) Publish(e Event) {
+	if e.At.IsZero() {
+		e.At = time.Now()
+	}
+	msg := notification{JSONRPC: "2.0", Method: "event", Params: e}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for c := range s.clients {
+		if !c.wants(e.Kind) {
+			continue
+		}
+		select {
+		case c.out <- msg:
+		default:
+		}
+	}
+}
@@ -0,0 +1,282 @@
+package editorapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/andyrewlee/amux/internal/data"
+	"github.com/andyrewlee/amux/internal/sessions"
+)
+
+// defaultEditor is the command worktrees.open runs without an editor param.
+const defaultEditor = "code"
+
+// Worktree is a worktree as the API reports it.
+type Worktree struct {
+	ID      string `json:"id"`
+	Name    string `json:"name"`
+	Project string `json:"project"`
+	Branch  string `json:"branch"`
+	Root    string `json:"root"`
+}
+
+type versionResult struct {
+	Version    string `json:"version"`
+	APIVersion int    `json:"api_version"`
+}
+
+type openParams struct {
+	Workspace string `json:"workspace"`
+	Editor    string `json:"editor"`
+}
+
+type statusParams struct {
+	Workspace string `json:"workspace"`
+}
+
+type activateParams struct {
+	Workspace string `json:"workspace"`
+	ShowTab   bool   `json:"show_tab"`
+}
+
+type sendParams struct {
+	Agent     string `json:"agent"`
+	Workspace string `json:"workspace"`
+	Text      string `json:"text"`
+	Code      string `json:"code"`
+	Path      string `json:"path"`
+	Language  string `json:"language"`
+	Submit    *bool  `json:"submit"`
+}
+
+func (s *Server) call(c *client, method string, params json.RawMessage) (any, error) {
+	switch method {
+	case "amux.version":
+		return versionResult{Version: s.cfg.Version, APIVersion: APIVersion}, nil
+	case "worktrees.list":
+		workspaces, err := s.cfg.Worktrees()
+		if err != nil {
+			return nil, err
+		}
+		out := make([]Worktree, 0, len(workspaces))
+		for _, ws := range workspaces {
+			out = append(out, worktree(ws))
+		}
+		return out, nil
+	case "worktrees.open":
+		return s.open(params)
+	case "worktrees.activate":
+		return s.activate(params)
+	case "agents.status":
+		return s.status(params)
+	case "agents.send":
+		return s.send(params)
+	case "agents.attention":
+		return s.attentionQueue()
+	case "events.subscribe":
+		return c.subscribe(params)
+	case "events.unsubscribe":
+		c.unsubscribe()
+		return struct{}{}, nil
+	}
+	return nil, &rpcError{Code: codeMethodNotFound, Message: "unknown method " + method}
+}
+
+func worktree(ws *data.Workspace) Worktree {
+	return Worktree{
+		ID:      string(ws.ID()),
+		Name:    ws.Name,
+		Project: filepath.Base(ws.Repo),
+		Branch:  ws.Branch,
+		Root:    ws.Root,
+	}
+}
+
+func (s *Server) open(raw json.RawMessage) (any, error) {
+	var p openParams
+	if err := decodeParams(raw, &p); err != nil {
+		return nil, err
+	}
+	ws, err := s.resolve(p.Workspace)
+	if err != nil {
+		return nil, err
+	}
+	editor := strings.TrimSpace(p.Editor)
+	if editor == "" {
+		editor = defaultEditor
+	}
+	if err := s.cfg.Open(editor, ws.Root); err != nil {
+		return nil, err
+	}
+	return worktree(ws), nil
+}
+
+func (s *Server) activate(raw json.RawMessage) (any, error) {
+	var p activateParams
+	if err := decodeParams(raw, &p); err != nil {
+		return nil, err
+	}
+	ws, err := s.resolve(p.Workspace)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.cfg.Activate(ws, p.ShowTab); err != nil {
+		return nil, err
+	}
+	return worktree(ws), nil
+}
+
+func (s *Server) status(raw json.RawMessage) (any, error) {
+	var p statusParams
+	if err := decodeParams(raw, &p); err != nil {
+		return nil, err
+	}
+	return s.agents(p.Workspace)
+}
+
+// agents returns the running agents, only those of the worktree ref names
+// when it is set.
+func (s *Server) agents(ref string) ([]sessions.Session, error) {
+	var wsID string
+	if ref != "" {
+		ws, err := s.resolve(ref)
+		if err != nil {
+			return nil, err
+		}
+		wsID = string(ws.ID())
+	}
+	list, err := s.cfg.Sessions()
+	if err != nil {
+		return nil, err
+	}
+	agents := make([]sessions.Session, 0, len(list))
+	for _, sess := range list {
+		if sess.Type == "agent" && (wsID == "" || sess.WorkspaceID == wsID) {
+			agents = append(agents, sess)
+		}
+	}
+	return agents, nil
+}
+
+func (s *Server) send(raw json.RawMessage) (any, error) {
+	var p sendParams
+	if err := decodeParams(raw, &p); err != nil {
+		return nil, err
+	}
+	text := Compose(p.Text, p.Code, p.Path, p.Language)
+	if text == "" {
+		return nil, invalidParams("text or code is required")
+	}
+	agent, err := s.sendTarget(p.Agent, p.Workspace)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.cfg.Send(agent, text, p.Submit == nil || *p.Submit); err != nil {
+		return nil, err
+	}
+	return agent, nil
+}
+
+// sendTarget finds the agent named name, or else the one agent running in the
+// worktree ref names.
+func (s *Server) sendTarget(name, ref string) (sessions.Session, error) {
+	if name == "" {
+		if ref == "" {
+			return sessions.Session{}, invalidParams("agent or workspace is required")
+		}
+		agents, err := s.agents(ref)
+		if err != nil {
+			return sessions.Session{}, err
+		}
+		if len(agents) != 1 {
+			return sessions.Session{}, fmt.Errorf("%d agents are running in %s; name one with agent", len(agents), ref)
+		}
+		return agents[0], nil
+	}
+	list, err := s.cfg.Sessions()
+	if err != nil {
+		return sessions.Session{}, err
+	}
+	agent, err := sessions.Find(list, name)
+	if err != nil {
+		return sessions.Session{}, err
+	}
+	if agent.Type != "agent" {
+		return sessions.Session{}, fmt.Errorf("%s is a %s session, not an agent", agent.Name, agent.Type)
+	}
+	return agent, nil
+}
+
+// resolve finds the worktree ref names: its ID, its root or a path inside
+// it, or its name when only one worktree has that name.
+func (s *Server) resolve(ref string) (*data.Workspace, error) {
+	ref = strings.TrimSpace(ref)
+	if ref == "" {
+		return nil, invalidParams("workspace is required")
+	}
+	workspaces, err := s.cfg.Worktrees()
+	if err != nil {
+		return nil, err
+	}
+	var byPath, byName *data.Workspace
+	names := 0
+	for _, ws := range workspaces {
+		if string(ws.ID()) == ref {
+			return ws, nil
+		}
+		if filepath.IsAbs(ref) && within(ref, ws.Root) && (byPath == nil || len(ws.Root) > len(byPath.Root)) {
+			byPath = ws
+		}
+		if ws.Name == ref {
+			byName = ws
+			names++
+		}
+	}
+	switch {
+	case byPath != nil:
+		return byPath, nil
+	case names == 1:
+		return byName, nil
+	case names > 1:
+		return nil, fmt.Errorf("%d worktrees are named %s; use its path or ID", names, ref)
+	}
+	return nil, fmt.Errorf("no worktree %s", ref)
+}
+
+// within reports whether path is root or inside it.
+func within(path, root string) bool {
+	if root == "" {
+		return false
+	}
+	rel, err := filepath.Rel(filepath.Clean(root), filepath.Clean(path))
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// Compose joins instructions and a code selection into one message for an
+// agent. The code is fenced, with the file it came from above it.
+func Compose(text, code, path, language string) string {
+	text = strings.TrimSpace(text)
+	if strings.TrimSpace(code) == "" {
+		return text
+	}
+	var b strings.Builder
+	if text != "" {
+		b.WriteString(text)
+		b.WriteString("\n\n")
+	}
+	if path != "" {
+		b.WriteString(path)
+		b.WriteString(":\n")
+	}
+	// The fence is longer than any run of backticks in the code.
+	fence := "```"
+	for strings.Contains(code, fence) {
+		fence += "`"
+	}
+	b.WriteString(fence + strings.TrimSpace(language) + "\n")
+	b.WriteString(strings.TrimRight(code, "\n"))
+	b.WriteString("\n" + fence)
+	return b.String()
+}
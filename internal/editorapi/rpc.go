@@ -0,0 +1,80 @@
+package editorapi
+
+import (
+	"encoding/json"
+	"errors"
+)
+
+// JSON-RPC 2.0 error codes.
+const (
+	codeParseError     = -32700
+	codeInvalidRequest = -32600
+	codeMethodNotFound = -32601
+	codeInvalidParams  = -32602
+	// codeFailed is a method that could not do what was asked, such as
+	// sending to an agent that is not running.
+	codeFailed = -32000
+)
+
+type request struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+}
+
+type response struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  any             `json:"result,omitempty"`
+	Error   *rpcError       `json:"error,omitempty"`
+}
+
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *rpcError) Error() string { return e.Message }
+
+func invalidParams(msg string) *rpcError { return &rpcError{Code: codeInvalidParams, Message: msg} }
+
+// handle answers one message. Notifications, requests without an id, get no
+// response.
+func (s *Server) handle(c *client, msg []byte) (response, bool) {
+	var req request
+	if err := json.Unmarshal(msg, &req); err != nil {
+		return response{JSONRPC: "2.0", ID: json.RawMessage("null"), Error: &rpcError{Code: codeParseError, Message: err.Error()}}, true
+	}
+	id := req.ID
+	isNotification := len(id) == 0
+	if isNotification {
+		id = json.RawMessage("null")
+	}
+	if req.JSONRPC != "2.0" || req.Method == "" {
+		return response{JSONRPC: "2.0", ID: id, Error: &rpcError{Code: codeInvalidRequest, Message: `want a "jsonrpc": "2.0" request with a method`}}, !isNotification
+	}
+	result, err := s.call(c, req.Method, req.Params)
+	if isNotification {
+		return response{}, false
+	}
+	if err != nil {
+		var rerr *rpcError
+		if !errors.As(err, &rerr) {
+			rerr = &rpcError{Code: codeFailed, Message: err.Error()}
+		}
+		return response{JSONRPC: "2.0", ID: id, Error: rerr}, true
+	}
+	return response{JSONRPC: "2.0", ID: id, Result: result}, true
+}
+
+// decodeParams decodes params into v; absent params leave v as it is.
+func decodeParams(params json.RawMessage, v any) error {
+	if len(params) == 0 || string(params) == "null" {
+		return nil
+	}
+	if err := json.Unmarshal(params, v); err != nil {
+		return invalidParams(err.Error())
+	}
+	return nil
+}
@@ -0,0 +1,135 @@
+package egress
+
+import (
+	"context"
+	"net"
+	"net/netip"
+	"strings"
+	"sync"
+	"time"
+)
+
+// AllowEnvVar lists the destinations agents are expected to reach, separated
+// by commas: IP addresses, CIDR prefixes, or host names (which also match
+// their subdomains).
+const AllowEnvVar = "AMUX_EGRESS_ALLOW"
+
+const (
+	resolveTTL     = 5 * time.Minute
+	resolveTimeout = time.Second
+)
+
+// Allowlist decides which destinations are expected. An empty list expects
+// everything: there is nothing to compare against, so nothing is flagged.
+type Allowlist struct {
+	prefixes []netip.Prefix
+	hosts    []string
+
+	mu       sync.Mutex
+	resolved map[netip.Addr]bool
+	expires  time.Time
+	lookup   func(ctx context.Context, host string) ([]string, error)
+}
+
+// ParseAllowlist parses an AMUX_EGRESS_ALLOW value.
+func ParseAllowlist(spec string) *Allowlist {
+	a := &Allowlist{lookup: net.DefaultResolver.LookupHost}
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.ToLower(strings.TrimSpace(entry))
+		if entry == "" {
+			continue
+		}
+		if prefix, err := netip.ParsePrefix(entry); err == nil {
+			a.prefixes = append(a.prefixes, prefix.Masked())
+		} else if addr, err := netip.ParseAddr(entry); err == nil {
+			a.prefixes = append(a.prefixes, netip.PrefixFrom(addr.Unmap(), addr.Unmap().BitLen()))
+		} else {
+			a.hosts = append(a.hosts, strings.TrimSuffix(entry, "."))
+		}
+	}
+	return a
+}
+
+// Empty reports whether no destinations are listed.
+func (a *Allowlist) Empty() bool {
+	return a == nil || (len(a.prefixes) == 0 && len(a.hosts) == 0)
+}
+
+// Allows reports whether a connection to addr, whose reverse DNS name is
+// name ("" when unknown), is expected. Loopback is always expected. It may
+// resolve the listed host names, so call it off the UI goroutine.
+func (a *Allowlist) Allows(addr netip.Addr, name string) bool {
+	if a.Empty() || addr.IsLoopback() {
+		return true
+	}
+	for _, prefix := range a.prefixes {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	name = strings.TrimSuffix(strings.ToLower(name), ".")
+	for _, host := range a.hosts {
+		if name == host || strings.HasSuffix(name, "."+host) {
+			return true
+		}
+	}
+	return a.resolvedAddrs()[addr]
+}
+
+// resolvedAddrs returns the addresses the listed host names resolve to,
+// refreshed every few minutes since service IPs rotate.
+func (a *Allowlist) resolvedAddrs() map[netip.Addr]bool {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.resolved != nil && time.Now().Before(a.expires) {
+		return a.resolved
+	}
+	resolved := make(map[netip.Addr]bool)
+	for _, host := range a.hosts {
+		ctx, cancel := context.WithTimeout(context.Background(), resolveTimeout)
+		addrs, _ := a.lookup(ctx, host)
+		cancel()
+		for _, raw := range addrs {
+			if addr, err := netip.ParseAddr(raw); err == nil {
+				resolved[addr.Unmap()] = true
+			}
+		}
+	}
+	a.resolved = resolved
+	a.expires = time.Now().Add(resolveTTL)
+	return resolved
+}
+
+// Names caches reverse DNS lookups.
+type Names struct {
+	mu     sync.Mutex
+	names  map[netip.Addr]string
+	lookup func(ctx context.Context, addr string) ([]string, error)
+}
+
+// NewNames returns an empty reverse DNS cache.
+func NewNames() *Names {
+	return &Names{names: make(map[netip.Addr]string), lookup: net.DefaultResolver.LookupAddr}
+}
+
+// Name returns addr's reverse DNS name, or "" when it has none. Failed
+// lookups are cached too, so each address is looked up once.
+func (n *Names) Name(addr netip.Addr) string {
+	n.mu.Lock()
+	name, ok := n.names[addr]
+	n.mu.Unlock()
+	if ok {
+		return name
+	}
+	if !addr.IsLoopback() {
+		ctx, cancel := context.WithTimeout(context.Background(), resolveTimeout)
+		if names, err := n.lookup(ctx, addr.String()); err == nil && len(names) > 0 {
+			name = strings.TrimSuffix(names[0], ".")
+		}
+		cancel()
+	}
+	n.mu.Lock()
+	n.names[addr] = name
+	n.mu.Unlock()
+	return name
+}
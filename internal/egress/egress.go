@@ -0,0 +1,43 @@
+// Package egress watches the network connections agent processes open, so
+// users can see what agent CLIs talk to and spot destinations they did not
+// expect.
+//
+// Connections are sampled, not captured: short-lived connections that open
+// and close between samples are missed. Linux reads /proc; other systems use
+// ps and lsof.
+package egress
+
+import (
+	"net/netip"
+	"os"
+	"runtime"
+)
+
+// Conn is one open TCP connection of a process.
+type Conn struct {
+	PID     int
+	Command string
+	Remote  netip.AddrPort
+}
+
+type sampler interface {
+	sample(roots []int) ([]Conn, error)
+}
+
+func platformSampler() sampler {
+	if runtime.GOOS == "linux" {
+		if _, err := os.Stat("/proc/self/fd"); err == nil {
+			return procfs{root: "/proc"}
+		}
+	}
+	return lsofSampler{}
+}
+
+// Sample returns the TCP connections held by the processes rooted at roots
+// (typically an agent's tmux pane processes) and their descendants.
+func Sample(roots []int) ([]Conn, error) {
+	if len(roots) == 0 {
+		return nil, nil
+	}
+	return platformSampler().sample(roots)
+}
@@ -0,0 +1,207 @@
+package egress
+
+import (
+	"context"
+	"net"
+	"net/netip"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestParseProcAddr(t *testing.T) {
+	cases := map[string]string{
+		"0100007F:1F90":                         "127.0.0.1:8080",
+		"0502000A:01BB":                         "10.0.2.5:443",
+		"0000000000000000FFFF00000100007F:0050": "127.0.0.1:80",
+		"B80D0120000000000000000001000000:01BB": "[2001:db8::1]:443",
+	}
+	for raw, want := range cases {
+		got, ok := parseProcAddr(raw)
+		if !ok || got.String() != want {
+			t.Errorf("parseProcAddr(%q) = %v, %v; want %s", raw, got, ok, want)
+		}
+	}
+	if _, ok := parseProcAddr("zz:1"); ok {
+		t.Error("parseProcAddr should reject bad hex")
+	}
+}
+
+// writeProc builds a fake /proc with an agent (pid 10) whose child (pid 11)
+// holds an established connection; pid 20 is unrelated.
+func writeProc(t *testing.T) string {
+	t.Helper()
+	root := t.TempDir()
+	write := func(rel, body string) {
+		path := filepath.Join(root, rel)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(body), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	link := func(pid, fd int, target string) {
+		dir := filepath.Join(root, strconv.Itoa(pid), "fd")
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Symlink(target, filepath.Join(dir, strconv.Itoa(fd))); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("10/stat", "10 (zsh) S 1 10 10")
+	write("10/comm", "zsh\n")
+	write("11/stat", "11 (node (agent)) S 10 10 10")
+	write("11/comm", "node\n")
+	write("20/stat", "20 (curl) S 1 20 20")
+	write("20/comm", "curl\n")
+	write("10/net/tcp", "  sl  local_address rem_address   st tx_queue rx_queue tr tm->when retrnsmt   uid  timeout inode\n"+
+		"   0: 0100007F:A000 0502000A:01BB 01 00000000:00000000 00:00000000 00000000  1000        0 555 1\n"+
+		"   1: 00000000:1F90 00000000:0000 0A 00000000:00000000 00:00000000 00000000  1000        0 556 1\n"+
+		"   2: 0100007F:A001 0602000A:01BB 01 00000000:00000000 00:00000000 00000000  1000        0 557 1\n")
+	link(10, 0, "/dev/pts/1")
+	link(11, 3, "socket:[555]")
+	link(11, 4, "socket:[556]")
+	link(20, 3, "socket:[557]")
+	return root
+}
+
+func TestProcfsSample(t *testing.T) {
+	p := procfs{root: writeProc(t)}
+	conns, err := p.sample([]int{10})
+	if err != nil {
+		t.Fatalf("sample() error = %v", err)
+	}
+	if len(conns) != 1 {
+		t.Fatalf("sample() = %#v, want only the child's established connection", conns)
+	}
+	if c := conns[0]; c.PID != 11 || c.Command != "node" || c.Remote.String() != "10.0.2.5:443" {
+		t.Fatalf("conn = %#v", c)
+	}
+}
+
+func TestSampleFindsRealConnection(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("procfs sampling is Linux-only")
+	}
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+	conn, err := net.Dial("tcp", ln.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	conns, err := Sample([]int{os.Getpid()})
+	if err != nil {
+		t.Fatalf("Sample() error = %v", err)
+	}
+	want := netip.MustParseAddrPort(ln.Addr().String())
+	for _, c := range conns {
+		if c.Remote == want && c.PID == os.Getpid() {
+			return
+		}
+	}
+	t.Fatalf("Sample() = %v, want a connection to %s", conns, want)
+}
+
+func TestParseLsofAndPS(t *testing.T) {
+	children := parsePS("  1     0\n 10     1\n 11    10\n 12    11\n 20     1\n")
+	if got := walkTree([]int{10}, children); len(got) != 3 {
+		t.Fatalf("walkTree() = %v, want 10, 11, 12", got)
+	}
+
+	conns := parseLsof("p11\ncnode\nf20\nn10.0.0.2:50000->104.18.1.1:443\nf21\nn*:3000\np12\nccurl\nf3\nn[::1]:5000->[2001:db8::1]:443\n")
+	if len(conns) != 2 {
+		t.Fatalf("parseLsof() = %#v, want two connections", conns)
+	}
+	if conns[0].PID != 11 || conns[0].Command != "node" || conns[0].Remote.String() != "104.18.1.1:443" {
+		t.Fatalf("first conn = %#v", conns[0])
+	}
+	if conns[1].PID != 12 || conns[1].Remote.String() != "[2001:db8::1]:443" {
+		t.Fatalf("second conn = %#v", conns[1])
+	}
+}
+
+func TestAllowlist(t *testing.T) {
+	if !ParseAllowlist("").Allows(netip.MustParseAddr("203.0.113.9"), "") {
+		t.Fatal("an empty allowlist flags nothing")
+	}
+	a := ParseAllowlist("10.0.0.0/8, 192.0.2.7, anthropic.com")
+	a.lookup = func(_ context.Context, host string) ([]string, error) {
+		return []string{"198.51.100.20"}, nil
+	}
+	for addr, name := range map[string]string{
+		"127.0.0.1":     "",
+		"10.1.2.3":      "",
+		"192.0.2.7":     "",
+		"203.0.113.9":   "api.anthropic.com.",
+		"198.51.100.20": "",
+	} {
+		if !a.Allows(netip.MustParseAddr(addr), name) {
+			t.Errorf("Allows(%s, %q) = false, want true", addr, name)
+		}
+	}
+	if a.Allows(netip.MustParseAddr("203.0.113.9"), "evilanthropic.com") {
+		t.Error("host matching must respect label boundaries")
+	}
+	if a.Allows(netip.MustParseAddr("192.0.2.8"), "") {
+		t.Error("an unlisted address should be flagged")
+	}
+}
+
+func TestNamesCachesLookups(t *testing.T) {
+	n := NewNames()
+	calls := 0
+	n.lookup = func(context.Context, string) ([]string, error) {
+		calls++
+		return []string{"host.example."}, nil
+	}
+	addr := netip.MustParseAddr("192.0.2.1")
+	if got := n.Name(addr); got != "host.example" {
+		t.Fatalf("Name() = %q", got)
+	}
+	n.Name(addr)
+	if calls != 1 {
+		t.Fatalf("lookups = %d, want 1", calls)
+	}
+	if got := n.Name(netip.MustParseAddr("127.0.0.1")); got != "" || calls != 1 {
+		t.Fatalf("loopback Name() = %q after %d lookups, want no lookup", got, calls)
+	}
+}
+
+func TestTracker(t *testing.T) {
+	tr := NewTracker()
+	t0 := time.Unix(1000, 0)
+	api := netip.MustParseAddrPort("198.51.100.20:443")
+	odd := netip.MustParseAddrPort("203.0.113.9:8443")
+
+	fresh := tr.Observe("s", []Observation{
+		{Conn: Conn{Remote: api, Command: "node"}},
+		{Conn: Conn{Remote: api, Command: "node"}},
+		{Conn: Conn{Remote: odd, Command: "node"}, Flagged: true},
+	}, t0)
+	if len(fresh) != 1 || fresh[0].Remote != odd {
+		t.Fatalf("first Observe() = %v, want the flagged destination", fresh)
+	}
+	if fresh := tr.Observe("s", []Observation{{Conn: Conn{Remote: odd}, Flagged: true}}, t0.Add(time.Minute)); len(fresh) != 0 {
+		t.Fatalf("second Observe() = %v, want nothing new", fresh)
+	}
+
+	dests := tr.Destinations("s")
+	if len(dests) != 2 || dests[0].Remote != odd || dests[0].Samples != 2 || dests[1].Samples != 1 {
+		t.Fatalf("Destinations() = %#v, want flagged first with sample counts", dests)
+	}
+
+	tr.Forget(map[string]bool{"other": true})
+	if len(tr.Destinations("s")) != 0 {
+		t.Fatal("Forget should drop sessions that are gone")
+	}
+}
@@ -0,0 +1,95 @@
+package egress
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"net/netip"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const commandTimeout = 5 * time.Second
+
+// runCommand runs a tool and returns its stdout. Tests replace it.
+var runCommand = func(name string, args ...string) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), commandTimeout)
+	defer cancel()
+	out, err := exec.CommandContext(ctx, name, args...).Output()
+	return string(out), err
+}
+
+// lsofSampler reads connections with ps and lsof, for systems without procfs.
+type lsofSampler struct{}
+
+func (lsofSampler) sample(roots []int) ([]Conn, error) {
+	psOut, err := runCommand("ps", "-A", "-o", "pid=,ppid=")
+	if err != nil {
+		return nil, err
+	}
+	pids := walkTree(roots, parsePS(psOut))
+	list := make([]string, len(pids))
+	for i, pid := range pids {
+		list[i] = strconv.Itoa(pid)
+	}
+	out, err := runCommand("lsof", "-nP", "-a", "-i", "TCP", "-p", strings.Join(list, ","), "-F", "pcn")
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && exitErr.ExitCode() == 1 {
+		return nil, nil // no matching files
+	}
+	if err != nil {
+		return nil, err
+	}
+	return parseLsof(out), nil
+}
+
+func parsePS(out string) map[int][]int {
+	children := make(map[int][]int)
+	for _, line := range strings.Split(out, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			continue
+		}
+		pid, err1 := strconv.Atoi(fields[0])
+		ppid, err2 := strconv.Atoi(fields[1])
+		if err1 == nil && err2 == nil {
+			children[ppid] = append(children[ppid], pid)
+		}
+	}
+	return children
+}
+
+// parseLsof reads lsof -F pcn output: p<pid> and c<command> start a process,
+// n<local>-><remote> names each connection.
+func parseLsof(out string) []Conn {
+	var conns []Conn
+	pid, command := 0, ""
+	scanner := bufio.NewScanner(strings.NewReader(out))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" {
+			continue
+		}
+		value := line[1:]
+		switch line[0] {
+		case 'p':
+			pid, _ = strconv.Atoi(value)
+			command = ""
+		case 'c':
+			command = value
+		case 'n':
+			_, remote, ok := strings.Cut(value, "->")
+			if !ok {
+				continue
+			}
+			addr, err := netip.ParseAddrPort(remote)
+			if err != nil {
+				continue
+			}
+			conns = append(conns, Conn{PID: pid, Command: command, Remote: netip.AddrPortFrom(addr.Addr().Unmap(), addr.Port())})
+		}
+	}
+	return conns
+}
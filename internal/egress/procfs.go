@@ -0,0 +1,162 @@
+package egress
+
+import (
+	"bufio"
+	"encoding/hex"
+	"net/netip"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// TCP states worth reporting from /proc/net/tcp: established connections and
+// ones still being opened.
+const (
+	tcpEstablished = "01"
+	tcpSynSent     = "02"
+)
+
+// procfs reads connections from a Linux /proc tree.
+type procfs struct {
+	root string
+}
+
+// descendants returns roots and every process below them.
+func (p procfs) descendants(roots []int) []int {
+	children := make(map[int][]int)
+	entries, _ := os.ReadDir(p.root)
+	for _, entry := range entries {
+		pid, err := strconv.Atoi(entry.Name())
+		if err != nil {
+			continue
+		}
+		if ppid, ok := p.parent(pid); ok {
+			children[ppid] = append(children[ppid], pid)
+		}
+	}
+	return walkTree(roots, children)
+}
+
+func (p procfs) parent(pid int) (int, bool) {
+	raw, err := os.ReadFile(filepath.Join(p.root, strconv.Itoa(pid), "stat"))
+	if err != nil {
+		return 0, false
+	}
+	// "pid (comm) state ppid ...": comm may hold spaces and parens, so
+	// parse from the last ')'.
+	s := string(raw)
+	end := strings.LastIndexByte(s, ')')
+	if end < 0 {
+		return 0, false
+	}
+	fields := strings.Fields(s[end+1:])
+	if len(fields) < 2 {
+		return 0, false
+	}
+	ppid, err := strconv.Atoi(fields[1])
+	return ppid, err == nil
+}
+
+func (p procfs) sample(roots []int) ([]Conn, error) {
+	pids := p.descendants(roots)
+	// Socket tables are per network namespace; a sandboxed agent has its
+	// own, so read them through each root process.
+	remotes := make(map[string]netip.AddrPort)
+	for _, root := range roots {
+		for _, table := range []string{"tcp", "tcp6"} {
+			p.readSockets(filepath.Join(p.root, strconv.Itoa(root), "net", table), remotes)
+		}
+	}
+	var conns []Conn
+	for _, pid := range pids {
+		dir := filepath.Join(p.root, strconv.Itoa(pid))
+		fds, err := os.ReadDir(filepath.Join(dir, "fd"))
+		if err != nil {
+			continue // exited, or not ours to inspect
+		}
+		command := ""
+		for _, fd := range fds {
+			link, err := os.Readlink(filepath.Join(dir, "fd", fd.Name()))
+			if err != nil {
+				continue
+			}
+			inode, ok := strings.CutPrefix(link, "socket:[")
+			if !ok {
+				continue
+			}
+			remote, ok := remotes[strings.TrimSuffix(inode, "]")]
+			if !ok {
+				continue
+			}
+			if command == "" {
+				comm, _ := os.ReadFile(filepath.Join(dir, "comm"))
+				command = strings.TrimSpace(string(comm))
+			}
+			conns = append(conns, Conn{PID: pid, Command: command, Remote: remote})
+		}
+	}
+	return conns, nil
+}
+
+// readSockets adds inode -> remote address for connected sockets in a
+// /proc/net/tcp{,6} table.
+func (p procfs) readSockets(path string, into map[string]netip.AddrPort) {
+	file, err := os.Open(path)
+	if err != nil {
+		return
+	}
+	defer file.Close()
+	scanner := bufio.NewScanner(file)
+	scanner.Scan() // header
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 10 || (fields[3] != tcpEstablished && fields[3] != tcpSynSent) {
+			continue
+		}
+		remote, ok := parseProcAddr(fields[2])
+		if !ok || remote.Addr().IsUnspecified() {
+			continue
+		}
+		into[fields[9]] = remote
+	}
+}
+
+// parseProcAddr decodes "0100007F:1F90": the address is hex in host (little
+// endian) order, 32 bits at a time; the port is big-endian hex.
+func parseProcAddr(s string) (netip.AddrPort, bool) {
+	addrHex, portHex, ok := strings.Cut(s, ":")
+	if !ok {
+		return netip.AddrPort{}, false
+	}
+	raw, err := hex.DecodeString(addrHex)
+	if err != nil || (len(raw) != 4 && len(raw) != 16) {
+		return netip.AddrPort{}, false
+	}
+	for i := 0; i < len(raw); i += 4 {
+		raw[i], raw[i+1], raw[i+2], raw[i+3] = raw[i+3], raw[i+2], raw[i+1], raw[i]
+	}
+	port, err := strconv.ParseUint(portHex, 16, 16)
+	if err != nil {
+		return netip.AddrPort{}, false
+	}
+	addr, _ := netip.AddrFromSlice(raw)
+	return netip.AddrPortFrom(addr.Unmap(), uint16(port)), true
+}
+
+func walkTree(roots []int, children map[int][]int) []int {
+	seen := make(map[int]bool)
+	var out []int
+	queue := append([]int(nil), roots...)
+	for len(queue) > 0 {
+		pid := queue[0]
+		queue = queue[1:]
+		if seen[pid] {
+			continue
+		}
+		seen[pid] = true
+		out = append(out, pid)
+		queue = append(queue, children[pid]...)
+	}
+	return out
+}
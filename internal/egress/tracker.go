@@ -0,0 +1,109 @@
+package egress
+
+import (
+	"net/netip"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Destination is a remote endpoint an agent session has been seen talking to.
+type Destination struct {
+	Remote    netip.AddrPort
+	Name      string // reverse DNS name, "" when unknown
+	Command   string // process that held the connection
+	FirstSeen time.Time
+	LastSeen  time.Time
+	Samples   int  // how many samples saw it open
+	Flagged   bool // not in the allowlist
+}
+
+// Label is the destination as shown to users: its name when known.
+func (d Destination) Label() string {
+	if d.Name != "" {
+		return d.Name + " (" + d.Remote.String() + ")"
+	}
+	return d.Remote.String()
+}
+
+// Observation is one sampled connection with its classification.
+type Observation struct {
+	Conn
+	Name    string
+	Flagged bool
+}
+
+// Tracker accumulates destinations per agent session across samples. It is
+// safe for concurrent use.
+type Tracker struct {
+	mu       sync.Mutex
+	sessions map[string]map[netip.AddrPort]*Destination
+}
+
+// NewTracker returns an empty tracker.
+func NewTracker() *Tracker {
+	return &Tracker{sessions: make(map[string]map[netip.AddrPort]*Destination)}
+}
+
+// Observe records one sample of a session's connections and returns the
+// flagged destinations seen for the first time.
+func (t *Tracker) Observe(session string, obs []Observation, now time.Time) []Destination {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	dests := t.sessions[session]
+	if dests == nil {
+		dests = make(map[netip.AddrPort]*Destination)
+		t.sessions[session] = dests
+	}
+	var fresh []Destination
+	seen := make(map[netip.AddrPort]bool, len(obs))
+	for _, o := range obs {
+		if seen[o.Remote] {
+			continue
+		}
+		seen[o.Remote] = true
+		d, ok := dests[o.Remote]
+		if !ok {
+			d = &Destination{Remote: o.Remote, Name: o.Name, Command: o.Command, FirstSeen: now, Flagged: o.Flagged}
+			dests[o.Remote] = d
+			if d.Flagged {
+				fresh = append(fresh, *d)
+			}
+		}
+		d.LastSeen = now
+		d.Samples++
+	}
+	return fresh
+}
+
+// Destinations returns what session has talked to, flagged first, then most
+// recently seen.
+func (t *Tracker) Destinations(session string) []Destination {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	out := make([]Destination, 0, len(t.sessions[session]))
+	for _, d := range t.sessions[session] {
+		out = append(out, *d)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Flagged != out[j].Flagged {
+			return out[i].Flagged
+		}
+		if !out[i].LastSeen.Equal(out[j].LastSeen) {
+			return out[i].LastSeen.After(out[j].LastSeen)
+		}
+		return out[i].Remote.String() < out[j].Remote.String()
+	})
+	return out
+}
+
+// Forget drops sessions not in live, so closed agents do not accumulate.
+func (t *Tracker) Forget(live map[string]bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for session := range t.sessions {
+		if !live[session] {
+			delete(t.sessions, session)
+		}
+	}
+}
@@ -0,0 +1,204 @@
+// Package errscan finds errors in terminal output, for amux's errors panel:
+// panics and stack traces, compiler errors, and uncaught exceptions.
+//
+// Output is matched line by line against the formats of common toolchains
+// (Go, Rust, TypeScript, C and C++ compilers, Python, Node, and the JVM).
+// Each error found keeps the lines it was printed as, bounded by maxLines.
+package errscan
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Kind is what sort of error was found.
+type Kind string
+
+const (
+	KindPanic     Kind = "panic"
+	KindCompile   Kind = "compile"
+	KindException Kind = "exception"
+)
+
+// maxLines bounds the lines kept for one error, such as a deep stack trace.
+const maxLines = 20
+
+// Error is one error found in output.
+type Error struct {
+	Kind Kind
+	// Summary is the line naming the error, such as "panic: boom".
+	Summary string
+	// Location is the "path:line" the error points at, or "".
+	Location string
+	// Lines is the error as printed, Summary included.
+	Lines []string
+}
+
+var (
+	goPanic       = regexp.MustCompile(`^(panic|fatal error): `)
+	goFrame       = regexp.MustCompile(`^\s+(\S+\.go):(\d+)`)
+	fileLineCol   = regexp.MustCompile(`^(\S+?\.\w+):(\d+):(\d+): (.+)$`)
+	tscParen      = regexp.MustCompile(`^(\S+?\.[cm]?[jt]sx?)\((\d+),\d+\): error TS\d+: `)
+	tscDash       = regexp.MustCompile(`^(\S+?\.[cm]?[jt]sx?):(\d+):\d+ - error TS\d+: `)
+	rustError     = regexp.MustCompile(`^error(\[E\d+\])?: `)
+	rustArrow     = regexp.MustCompile(`^\s*--> (\S+?):(\d+):\d+`)
+	pyTraceback   = regexp.MustCompile(`^Traceback \(most recent call last\):`)
+	pyFrame       = regexp.MustCompile(`^\s+File "([^"]+)", line (\d+)`)
+	exception     = regexp.MustCompile(`^(Uncaught |Exception in thread "[^"]*" )?[\w$.]*(Error|Exception)\b(: .*)?$`)
+	stackAt       = regexp.MustCompile(`^\s+at `)
+	stackLocation = regexp.MustCompile(`\(?([^\s()]+?):(\d+)(:\d+)?\)?$`)
+	numbers       = regexp.MustCompile(`0x[0-9a-fA-F]+|\d+`)
+)
+
+// compiledSources are the extensions whose "path:line:col: message" lines
+// are always errors; other languages' compilers say "error:" in the message.
+var compiledSources = map[string]bool{".go": true, ".rs": true, ".swift": true, ".zig": true}
+
+// Scan returns the errors in text, in the order printed.
+func Scan(text string) []Error {
+	lines := strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRight(line, " \t")
+	}
+	var found []Error
+	for i := 0; i < len(lines); {
+		e, n := match(lines, i)
+		if n == 0 {
+			i++
+			continue
+		}
+		if len(e.Lines) > maxLines {
+			e.Lines = e.Lines[:maxLines]
+		}
+		found = append(found, e)
+		i += n
+	}
+	return found
+}
+
+// Key identifies an error for deduplication: its kind, location, and
+// summary with numbers such as addresses and indexes masked.
+func (e Error) Key() string {
+	return string(e.Kind) + "|" + e.Location + "|" + numbers.ReplaceAllString(e.Summary, "#")
+}
+
+// match reports the error starting at lines[i] and how many lines it spans,
+// or 0 lines when none starts there.
+func match(lines []string, i int) (Error, int) {
+	line := lines[i]
+	switch {
+	case goPanic.MatchString(line):
+		return goPanicAt(lines, i)
+	case pyTraceback.MatchString(line):
+		return pythonTracebackAt(lines, i)
+	case rustError.MatchString(line) && !rustSummaryLine(line):
+		return rustErrorAt(lines, i)
+	}
+	if m := tscParen.FindStringSubmatch(line); m != nil {
+		return compileError(line, m[1], m[2]), 1
+	}
+	if m := tscDash.FindStringSubmatch(line); m != nil {
+		return compileError(line, m[1], m[2]), 1
+	}
+	if m := fileLineCol.FindStringSubmatch(line); m != nil {
+		ext := m[1][strings.LastIndex(m[1], "."):]
+		if compiledSources[ext] || strings.HasPrefix(m[4], "error:") || strings.HasPrefix(m[4], "fatal error:") {
+			return compileError(line, m[1], m[2]), 1
+		}
+	}
+	if exception.MatchString(line) && i+1 < len(lines) && stackAt.MatchString(lines[i+1]) {
+		return exceptionAt(lines, i)
+	}
+	return Error{}, 0
+}
+
+func compileError(line, path, lineNo string) Error {
+	return Error{Kind: KindCompile, Summary: line, Location: path + ":" + lineNo, Lines: []string{line}}
+}
+
+// goPanicAt reads a Go panic and its goroutine traces, up to the first line
+// after them that is not part of a trace. The location is the first frame
+// outside the runtime.
+func goPanicAt(lines []string, i int) (Error, int) {
+	e := Error{Kind: KindPanic, Summary: lines[i], Lines: []string{lines[i]}}
+	j := i + 1
+	for ; j < len(lines); j++ {
+		line := lines[j]
+		// Terminals render the tab before each frame's file as spaces.
+		if line != "" && !strings.HasPrefix(line, "goroutine ") && !strings.HasPrefix(line, "[") &&
+			!strings.HasPrefix(line, " ") && !strings.HasPrefix(line, "\t") &&
+			!strings.HasSuffix(line, ")") && !strings.HasPrefix(line, "created by ") {
+			break
+		}
+		if line == "" && j+1 < len(lines) && !strings.HasPrefix(lines[j+1], "goroutine ") {
+			break
+		}
+		e.Lines = append(e.Lines, line)
+		if m := goFrame.FindStringSubmatch(line); m != nil && e.Location == "" && !strings.Contains(m[1], "/runtime/") {
+			e.Location = m[1] + ":" + m[2]
+		}
+	}
+	return e, j - i
+}
+
+// pythonTracebackAt reads a Python traceback: its indented frames and the
+// exception line that ends it, which is the summary. The location is the
+// innermost frame.
+func pythonTracebackAt(lines []string, i int) (Error, int) {
+	e := Error{Kind: KindException, Lines: []string{lines[i]}}
+	j := i + 1
+	for ; j < len(lines); j++ {
+		line := lines[j]
+		e.Lines = append(e.Lines, line)
+		if m := pyFrame.FindStringSubmatch(line); m != nil {
+			e.Location = m[1] + ":" + m[2]
+		}
+		if line != "" && !strings.HasPrefix(line, " ") {
+			e.Summary = line
+			j++
+			break
+		}
+	}
+	if e.Summary == "" {
+		e.Summary = lines[i]
+	}
+	return e, j - i
+}
+
+// rustErrorAt reads a rustc error up to the blank line that ends it; its
+// location follows the "-->" arrow.
+func rustErrorAt(lines []string, i int) (Error, int) {
+	e := Error{Kind: KindCompile, Summary: lines[i], Lines: []string{lines[i]}}
+	j := i + 1
+	for ; j < len(lines) && lines[j] != ""; j++ {
+		e.Lines = append(e.Lines, lines[j])
+		if m := rustArrow.FindStringSubmatch(lines[j]); m != nil && e.Location == "" {
+			e.Location = m[1] + ":" + m[2]
+		}
+	}
+	return e, j - i
+}
+
+// rustSummaryLine reports whether line is cargo's closing note that errors
+// were printed above, rather than an error itself.
+func rustSummaryLine(line string) bool {
+	return strings.HasPrefix(line, "error: aborting due to") || strings.HasPrefix(line, "error: could not compile")
+}
+
+// exceptionAt reads an exception line and the "at ..." stack frames under
+// it, as Node and the JVM print them. The location is the first frame that
+// names a file, skipping Node's internals.
+func exceptionAt(lines []string, i int) (Error, int) {
+	e := Error{Kind: KindException, Summary: lines[i], Lines: []string{lines[i]}}
+	j := i + 1
+	for ; j < len(lines) && stackAt.MatchString(lines[j]); j++ {
+		e.Lines = append(e.Lines, lines[j])
+		if e.Location != "" || strings.Contains(lines[j], "node:") {
+			continue
+		}
+		if m := stackLocation.FindStringSubmatch(lines[j]); m != nil {
+			e.Location = m[1] + ":" + m[2]
+		}
+	}
+	return e, j - i
+}
@@ -0,0 +1,95 @@
+package errscan
+
+import (
+	"strings"
+	"testing"
+)
+
+const output = `$ go run .
+panic: runtime error: index out of range [5] with length 3
+
+goroutine 1 [running]:
+main.pick(...)
+        /work/app/main.go:12
+main.main()
+        /work/app/main.go:7 +0x1d
+exit status 2
+$ go build ./...
+# example.com/app
+./main.go:9:2: undefined: total
+main.c:3:10: warning: unused variable 'x'
+main.c:4:1: error: expected ';' after expression
+src/index.ts(4,7): error TS2322: Type 'string' is not assignable to type 'number'.
+error[E0425]: cannot find value ` + "`y`" + ` in this scope
+ --> src/main.rs:3:13
+  |
+3 |     let x = y;
+  |             ^ not found in this scope
+
+error: aborting due to 1 previous error
+Traceback (most recent call last):
+  File "/work/app/run.py", line 8, in <module>
+    main()
+  File "/work/app/run.py", line 5, in main
+    raise ValueError("bad input")
+ValueError: bad input
+TypeError: Cannot read properties of undefined (reading 'id')
+    at handler (/work/app/server.js:21:17)
+    at node:internal/process/task_queues:95:5
+Exception in thread "main" java.lang.IllegalStateException: boom
+	at com.example.App.run(App.java:14)
+	at com.example.App.main(App.java:5)
+all done
+`
+
+func TestScan(t *testing.T) {
+	found := Scan(output)
+	want := []struct {
+		kind     Kind
+		summary  string
+		location string
+		lines    int
+	}{
+		{KindPanic, "panic: runtime error: index out of range [5] with length 3", "/work/app/main.go:12", 7},
+		{KindCompile, "./main.go:9:2: undefined: total", "./main.go:9", 1},
+		{KindCompile, "main.c:4:1: error: expected ';' after expression", "main.c:4", 1},
+		{KindCompile, "src/index.ts(4,7): error TS2322: Type 'string' is not assignable to type 'number'.", "src/index.ts:4", 1},
+		{KindCompile, "error[E0425]: cannot find value `y` in this scope", "src/main.rs:3", 5},
+		{KindException, "ValueError: bad input", "/work/app/run.py:5", 6},
+		{KindException, "TypeError: Cannot read properties of undefined (reading 'id')", "/work/app/server.js:21", 3},
+		{KindException, `Exception in thread "main" java.lang.IllegalStateException: boom`, "App.java:14", 3},
+	}
+	if len(found) != len(want) {
+		for _, e := range found {
+			t.Logf("found %s %q at %q", e.Kind, e.Summary, e.Location)
+		}
+		t.Fatalf("Scan() found %d errors, want %d", len(found), len(want))
+	}
+	for i, w := range want {
+		e := found[i]
+		if e.Kind != w.kind || e.Summary != w.summary || e.Location != w.location || len(e.Lines) != w.lines {
+			t.Errorf("error %d = %s %q at %q (%d lines), want %s %q at %q (%d lines)",
+				i, e.Kind, e.Summary, e.Location, len(e.Lines), w.kind, w.summary, w.location, w.lines)
+		}
+	}
+}
+
+func TestScanBoundsLines(t *testing.T) {
+	trace := "Error: deep\n" + strings.Repeat("    at f (/app/a.js:1:1)\n", 50)
+	found := Scan(trace)
+	if len(found) != 1 || len(found[0].Lines) != maxLines {
+		t.Fatalf("Scan() = %+v, want one error of %d lines", found, maxLines)
+	}
+}
+
+func TestKey(t *testing.T) {
+	a := Error{Kind: KindPanic, Summary: "panic: index out of range [5] with length 3", Location: "main.go:12"}
+	b := Error{Kind: KindPanic, Summary: "panic: index out of range [7] with length 4", Location: "main.go:12"}
+	if a.Key() != b.Key() {
+		t.Fatalf("Key() differs for the same panic: %q, %q", a.Key(), b.Key())
+	}
+	b.Location = "main.go:20"
+	if a.Key() == b.Key() {
+		t.Fatal("Key() should differ by location")
+	}
+}
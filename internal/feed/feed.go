@@ -0,0 +1,97 @@
+// Package feed keeps a chronological feed of what happened across all
+// worktrees: agents starting and finishing work, needing attention, or
+// exiting, git changes, and finished tasks. It is meant for catching up after
+// stepping away, without visiting each tab.
+package feed
+
+import "time"
+
+// Kind says what an entry is about.
+type Kind string
+
+const (
+	// KindOutput: an agent started producing output.
+	KindOutput Kind = "output"
+	// KindDone: an agent finished working.
+	KindDone Kind = "done"
+	// KindAttention: an agent needs the user.
+	KindAttention Kind = "attention"
+	// KindExit: an agent's session ended.
+	KindExit Kind = "exit"
+	// KindGit: a worktree's uncommitted changes changed.
+	KindGit Kind = "git"
+	// KindTask: a test or check run finished.
+	KindTask Kind = "task"
+)
+
+// MaxEntries bounds the feed; the oldest entries go first.
+const MaxEntries = 300
+
+// CoalesceWindow is how long a workspace's git entry is updated in place
+// rather than followed by a new one, so an agent editing file after file
+// doesn't flood the feed.
+const CoalesceWindow = 2 * time.Minute
+
+// Entry is one thing that happened.
+type Entry struct {
+	At        time.Time
+	Kind      Kind
+	Workspace string
+	Text      string
+}
+
+// Feed is the list of entries, oldest first. The zero value is ready to use.
+// It is not safe for concurrent use.
+type Feed struct {
+	entries []Entry
+	seenAt  time.Time
+}
+
+// Add appends e. A git entry for the workspace of the newest git entry
+// within CoalesceWindow replaces it instead.
+func (f *Feed) Add(e Entry) {
+	if e.Kind == KindGit {
+		for i := len(f.entries) - 1; i >= 0; i-- {
+			prev := f.entries[i]
+			if e.At.Sub(prev.At) >= CoalesceWindow {
+				break
+			}
+			if prev.Kind == KindGit && prev.Workspace == e.Workspace {
+				f.entries = append(f.entries[:i], f.entries[i+1:]...)
+				break
+			}
+		}
+	}
+	f.entries = append(f.entries, e)
+	if over := len(f.entries) - MaxEntries; over > 0 {
+		f.entries = append(f.entries[:0], f.entries[over:]...)
+	}
+}
+
+// Entries returns the entries, newest first.
+func (f *Feed) Entries() []Entry {
+	out := make([]Entry, len(f.entries))
+	for i, e := range f.entries {
+		out[len(f.entries)-1-i] = e
+	}
+	return out
+}
+
+// Unseen counts the entries added after the last MarkSeen.
+func (f *Feed) Unseen() int {
+	n := 0
+	for i := len(f.entries) - 1; i >= 0 && f.IsUnseen(f.entries[i]); i-- {
+		n++
+	}
+	return n
+}
+
+// IsUnseen reports whether e was added after the last MarkSeen.
+func (f *Feed) IsUnseen(e Entry) bool {
+	return e.At.After(f.seenAt)
+}
+
+// MarkSeen marks every entry up to now as seen.
+func (f *Feed) MarkSeen(now time.Time) {
+	f.seenAt = now
+}
@@ -0,0 +1,55 @@
+package feed
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAddCoalescesGitEntries(t *testing.T) {
+	var f Feed
+	at := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	f.Add(Entry{At: at, Kind: KindGit, Workspace: "api", Text: "1 file changed"})
+	f.Add(Entry{At: at.Add(10 * time.Second), Kind: KindOutput, Workspace: "web", Text: "claude is working"})
+	f.Add(Entry{At: at.Add(30 * time.Second), Kind: KindGit, Workspace: "api", Text: "3 files changed"})
+	f.Add(Entry{At: at.Add(40 * time.Second), Kind: KindGit, Workspace: "web", Text: "1 file changed"})
+
+	got := f.Entries()
+	if len(got) != 3 {
+		t.Fatalf("Entries() = %+v, want api's git entries merged", got)
+	}
+	if got[0].Workspace != "web" || got[1].Text != "3 files changed" || got[2].Kind != KindOutput {
+		t.Fatalf("Entries() = %+v, want newest first with the latest git text", got)
+	}
+
+	f.Add(Entry{At: at.Add(30*time.Second + CoalesceWindow), Kind: KindGit, Workspace: "api", Text: "clean"})
+	if n := len(f.Entries()); n != 4 {
+		t.Fatalf("len(Entries()) = %d, want a new git entry after the window", n)
+	}
+}
+
+func TestAddBoundsFeed(t *testing.T) {
+	var f Feed
+	at := time.Now()
+	for i := range MaxEntries + 10 {
+		f.Add(Entry{At: at.Add(time.Duration(i) * time.Second), Kind: KindDone, Workspace: "api"})
+	}
+	got := f.Entries()
+	if len(got) != MaxEntries || !got[len(got)-1].At.Equal(at.Add(10*time.Second)) {
+		t.Fatalf("kept %d entries from %v, want the newest %d", len(got), got[len(got)-1].At, MaxEntries)
+	}
+}
+
+func TestUnseen(t *testing.T) {
+	var f Feed
+	at := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	f.Add(Entry{At: at, Kind: KindDone, Workspace: "api"})
+	f.MarkSeen(at.Add(time.Second))
+	f.Add(Entry{At: at.Add(2 * time.Second), Kind: KindExit, Workspace: "api"})
+	f.Add(Entry{At: at.Add(3 * time.Second), Kind: KindAttention, Workspace: "web"})
+	if n := f.Unseen(); n != 2 {
+		t.Fatalf("Unseen() = %d, want 2", n)
+	}
+	if f.IsUnseen(f.Entries()[2]) {
+		t.Fatal("the entry before MarkSeen should be seen")
+	}
+}
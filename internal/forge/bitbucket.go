@@ -0,0 +1,184 @@
+package forge
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+// bitbucketAPI is Bitbucket Cloud's REST API root; a var for tests.
+var bitbucketAPI = "https://api.bitbucket.org/2.0"
+
+// bitbucket calls Bitbucket Cloud's REST API.
+type bitbucket struct {
+	remote Remote
+}
+
+func (b *bitbucket) Kind() Kind     { return Bitbucket }
+func (b *bitbucket) Remote() Remote { return b.remote }
+
+// call sends a request to the repository's endpoint and decodes the JSON
+// response into out.
+func (b *bitbucket) call(ctx context.Context, method, endpoint string, body, out any) error {
+	var reader io.Reader
+	if body != nil {
+		payload, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(payload)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, bitbucketAPI+"/repositories/"+b.remote.Path+"/"+endpoint, reader)
+	if err != nil {
+		return err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	switch {
+	case os.Getenv("BITBUCKET_TOKEN") != "":
+		req.Header.Set("Authorization", "Bearer "+os.Getenv("BITBUCKET_TOKEN"))
+	case os.Getenv("BITBUCKET_USERNAME") != "" && os.Getenv("BITBUCKET_APP_PASSWORD") != "":
+		req.SetBasicAuth(os.Getenv("BITBUCKET_USERNAME"), os.Getenv("BITBUCKET_APP_PASSWORD"))
+	default:
+		return errors.New("set BITBUCKET_TOKEN, or BITBUCKET_USERNAME and BITBUCKET_APP_PASSWORD")
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 4<<20))
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode/100 != 2 {
+		var apiErr struct {
+			Error struct {
+				Message string `json:"message"`
+			} `json:"error"`
+		}
+		if json.Unmarshal(data, &apiErr) == nil && apiErr.Error.Message != "" {
+			return fmt.Errorf("bitbucket: %s", apiErr.Error.Message)
+		}
+		return fmt.Errorf("bitbucket: %s", resp.Status)
+	}
+	if err := json.Unmarshal(data, out); err != nil {
+		return fmt.Errorf("parse bitbucket response: %w", err)
+	}
+	return nil
+}
+
+type bitbucketPR struct {
+	ID        int       `json:"id"`
+	Title     string    `json:"title"`
+	State     string    `json:"state"`
+	Draft     bool      `json:"draft"`
+	CreatedOn time.Time `json:"created_on"`
+	Links     struct {
+		HTML struct {
+			Href string `json:"href"`
+		} `json:"html"`
+	} `json:"links"`
+}
+
+func (p bitbucketPR) pr() PR {
+	state := "CLOSED"
+	switch p.State {
+	case "OPEN":
+		state = "OPEN"
+	case "MERGED":
+		state = "MERGED"
+	}
+	return PR{Number: p.ID, Title: p.Title, URL: p.Links.HTML.Href, State: state, Draft: p.Draft, CreatedAt: p.CreatedOn}
+}
+
+func (b *bitbucket) FindPR(ctx context.Context, branch string) (PR, bool, error) {
+	q := url.Values{
+		"q":       {fmt.Sprintf("source.branch.name=%q", branch)},
+		"state":   {"OPEN", "MERGED", "DECLINED", "SUPERSEDED"},
+		"sort":    {"-created_on"},
+		"pagelen": {"1"},
+	}
+	var resp struct {
+		Values []bitbucketPR `json:"values"`
+	}
+	if err := b.call(ctx, http.MethodGet, "pullrequests?"+q.Encode(), nil, &resp); err != nil {
+		return PR{}, false, err
+	}
+	if len(resp.Values) == 0 {
+		return PR{}, false, nil
+	}
+	return resp.Values[0].pr(), true, nil
+}
+
+func (b *bitbucket) CreatePR(ctx context.Context, opts CreateOptions) (PR, error) {
+	branch := func(name string) map[string]any {
+		return map[string]any{"branch": map[string]string{"name": name}}
+	}
+	body := map[string]any{
+		"title":       opts.Title,
+		"description": opts.Body,
+		"source":      branch(opts.Head),
+		"destination": branch(opts.Base),
+		"draft":       opts.Draft,
+	}
+	var created bitbucketPR
+	if err := b.call(ctx, http.MethodPost, "pullrequests", body, &created); err != nil {
+		return PR{}, err
+	}
+	return created.pr(), nil
+}
+
+// Checks reports the build statuses on the branch's head commit.
+func (b *bitbucket) Checks(ctx context.Context, branch string) (Checks, error) {
+	var ref struct {
+		Target struct {
+			Hash string `json:"hash"`
+		} `json:"target"`
+	}
+	if err := b.call(ctx, http.MethodGet, "refs/branches/"+url.PathEscape(branch), nil, &ref); err != nil {
+		return Checks{}, err
+	}
+	var statuses struct {
+		Values []struct {
+			State string `json:"state"`
+		} `json:"values"`
+	}
+	if err := b.call(ctx, http.MethodGet, "commit/"+ref.Target.Hash+"/statuses?pagelen=100", nil, &statuses); err != nil {
+		return Checks{}, err
+	}
+	var passed, failed, pending int
+	for _, s := range statuses.Values {
+		switch strings.ToUpper(s.State) {
+		case "SUCCESSFUL":
+			passed++
+		case "FAILED", "STOPPED":
+			failed++
+		default:
+			pending++
+		}
+	}
+	return summarize(passed, failed, pending), nil
+}
+
+func (b *bitbucket) NewPRURL(head, base string) string {
+	q := url.Values{"source": {head}, "dest": {base}}
+	return b.remote.WebURL() + "/pull-requests/new?" + q.Encode()
+}
+
+func (b *bitbucket) BranchURL(branch string) string {
+	return b.remote.WebURL() + "/branch/" + branch
+}
+
+func (b *bitbucket) CommitURL(sha string) string {
+	return b.remote.WebURL() + "/commits/" + sha
+}
@@ -0,0 +1,191 @@
+// Package forge talks to the service hosting a repository's remote — GitHub,
+// GitLab, or Bitbucket — to find and open pull requests (merge requests on
+// GitLab), read the checks run on a branch, and derive web URLs from the
+// remote.
+//
+// GitHub is reached through the gh CLI and GitLab through glab, so their
+// logins are reused. Bitbucket has no standard CLI and is called over its
+// REST API with the credentials in BITBUCKET_TOKEN, or BITBUCKET_USERNAME and
+// BITBUCKET_APP_PASSWORD.
+package forge
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/andyrewlee/amux/internal/git"
+)
+
+// Kind names a hosting service.
+type Kind string
+
+const (
+	GitHub    Kind = "github"
+	GitLab    Kind = "gitlab"
+	Bitbucket Kind = "bitbucket"
+)
+
+// ConfigKey is the git config key that names the service for a remote on a
+// host amux does not recognize, such as a self-hosted GitLab:
+// `git config amux.forge gitlab`.
+const ConfigKey = "amux.forge"
+
+var (
+	// ErrNoRemote is returned by Open for a repository without a remote.
+	ErrNoRemote = errors.New("the repository has no remote")
+	// ErrUnknownHost is returned by Open when the remote's host is not a
+	// service amux knows and ConfigKey does not name one.
+	ErrUnknownHost = errors.New("unknown git hosting service")
+)
+
+// PR is a pull request, or a GitLab merge request.
+type PR struct {
+	Number    int       `json:"number"`
+	Title     string    `json:"title"`
+	URL       string    `json:"url"`
+	State     string    `json:"state"` // OPEN, CLOSED, or MERGED
+	Draft     bool      `json:"draft,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// CreateOptions describes a pull request to open.
+type CreateOptions struct {
+	Head  string // branch with the changes, already pushed
+	Base  string // branch to merge into
+	Title string
+	Body  string
+	Draft bool
+}
+
+// CheckState summarizes the checks run on a branch.
+type CheckState string
+
+const (
+	ChecksNone    CheckState = "none"
+	ChecksPending CheckState = "pending"
+	ChecksPassing CheckState = "passing"
+	ChecksFailing CheckState = "failing"
+)
+
+// Checks counts the checks (CI jobs, status checks, pipelines) on a branch's
+// latest commit.
+type Checks struct {
+	State   CheckState `json:"state"`
+	Passed  int        `json:"passed"`
+	Failed  int        `json:"failed"`
+	Pending int        `json:"pending"`
+}
+
+func summarize(passed, failed, pending int) Checks {
+	c := Checks{State: ChecksNone, Passed: passed, Failed: failed, Pending: pending}
+	switch {
+	case failed > 0:
+		c.State = ChecksFailing
+	case pending > 0:
+		c.State = ChecksPending
+	case passed > 0:
+		c.State = ChecksPassing
+	}
+	return c
+}
+
+// Provider is a repository on a hosting service.
+type Provider interface {
+	Kind() Kind
+	Remote() Remote
+	// FindPR returns the newest pull request opened from branch, in any
+	// state; ok is false when there is none.
+	FindPR(ctx context.Context, branch string) (pr PR, ok bool, err error)
+	CreatePR(ctx context.Context, opts CreateOptions) (PR, error)
+	Checks(ctx context.Context, branch string) (Checks, error)
+	// NewPRURL is the web page for opening a pull request from head into
+	// base, for when the service cannot be called.
+	NewPRURL(head, base string) string
+	BranchURL(branch string) string
+	CommitURL(sha string) string
+}
+
+// Test seams for git and the service CLIs.
+var (
+	runGit   = git.RunGitCtx
+	lookPath = exec.LookPath
+	runCLI   = func(ctx context.Context, dir, name string, args ...string) ([]byte, error) {
+		cmd := exec.CommandContext(ctx, name, args...)
+		cmd.Dir = dir
+		out, err := cmd.Output()
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && len(exitErr.Stderr) > 0 {
+			return out, fmt.Errorf("%s: %s", name, strings.TrimSpace(string(exitErr.Stderr)))
+		}
+		return out, err
+	}
+)
+
+// Open returns the provider for the repository at dir, from its origin
+// remote, or its first remote when it has no origin.
+func Open(ctx context.Context, dir string) (Provider, error) {
+	name := "origin"
+	url, err := runGit(ctx, dir, "remote", "get-url", name)
+	if err != nil {
+		remotes, _ := runGit(ctx, dir, "remote")
+		first, _, _ := strings.Cut(strings.TrimSpace(remotes), "\n")
+		if first == "" {
+			return nil, ErrNoRemote
+		}
+		if url, err = runGit(ctx, dir, "remote", "get-url", first); err != nil {
+			return nil, err
+		}
+	}
+	remote, ok := ParseRemote(strings.TrimSpace(url))
+	if !ok {
+		return nil, fmt.Errorf("cannot parse remote URL %q", strings.TrimSpace(url))
+	}
+	configured, _ := runGit(ctx, dir, "config", "--get", ConfigKey)
+	kind, ok := detectKind(remote.Host, strings.TrimSpace(configured))
+	if !ok {
+		return nil, fmt.Errorf("%w: %s (set `git config %s` to github, gitlab, or bitbucket)", ErrUnknownHost, remote.Host, ConfigKey)
+	}
+	return New(kind, remote, dir)
+}
+
+// New returns the provider of kind for remote, run from the repository at
+// dir.
+func New(kind Kind, remote Remote, dir string) (Provider, error) {
+	switch kind {
+	case GitHub:
+		return &github{remote: remote, dir: dir}, nil
+	case GitLab:
+		return &gitlab{remote: remote, dir: dir}, nil
+	case Bitbucket:
+		return &bitbucket{remote: remote}, nil
+	}
+	return nil, fmt.Errorf("%w: %q", ErrUnknownHost, kind)
+}
+
+// detectKind picks the service for host; configured, the ConfigKey value,
+// takes precedence.
+func detectKind(host, configured string) (Kind, bool) {
+	switch k := Kind(strings.ToLower(configured)); k {
+	case GitHub, GitLab, Bitbucket:
+		return k, true
+	}
+	host = strings.ToLower(host)
+	for _, k := range []Kind{GitHub, GitLab, Bitbucket} {
+		if strings.Contains(host, string(k)) {
+			return k, true
+		}
+	}
+	return "", false
+}
+
+// requireCLI reports a missing service CLI as an error naming it.
+func requireCLI(name string) error {
+	if _, err := lookPath(name); err != nil {
+		return fmt.Errorf("%s is not installed", name)
+	}
+	return nil
+}
@@ -0,0 +1,139 @@
+package forge
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestParseRemote(t *testing.T) {
+	for raw, want := range map[string]Remote{
+		"git@github.com:andyrewlee/amux.git":            {Host: "github.com", Path: "andyrewlee/amux"},
+		"https://github.com/andyrewlee/amux":            {Host: "github.com", Path: "andyrewlee/amux"},
+		"ssh://git@gitlab.com:2222/group/sub/proj.git":  {Host: "gitlab.com", Path: "group/sub/proj"},
+		"https://git.example.com:8443/team/app.git/":    {Host: "git.example.com:8443", Path: "team/app"},
+		"https://user@Bitbucket.org/workspace/repo.git": {Host: "bitbucket.org", Path: "workspace/repo"},
+	} {
+		got, ok := ParseRemote(raw)
+		if !ok || got != want {
+			t.Errorf("ParseRemote(%q) = %+v, %v, want %+v", raw, got, ok, want)
+		}
+	}
+	for _, raw := range []string{"", "/local/path/repo", "https://github.com/", "git@github.com:noslash"} {
+		if got, ok := ParseRemote(raw); ok {
+			t.Errorf("ParseRemote(%q) = %+v, want no match", raw, got)
+		}
+	}
+
+	r := Remote{Host: "gitlab.com", Path: "group/sub/proj"}
+	if r.Owner() != "group/sub" || r.Name() != "proj" {
+		t.Fatalf("Owner, Name = %q, %q", r.Owner(), r.Name())
+	}
+}
+
+func TestDetectKind(t *testing.T) {
+	for _, tc := range []struct {
+		host, configured string
+		want             Kind
+		ok               bool
+	}{
+		{"github.com", "", GitHub, true},
+		{"gitlab.example.com", "", GitLab, true},
+		{"bitbucket.org", "", Bitbucket, true},
+		{"git.example.com", "", "", false},
+		{"git.example.com", "GitLab", GitLab, true},
+		{"github.com", "gitlab", GitLab, true},
+	} {
+		if got, ok := detectKind(tc.host, tc.configured); got != tc.want || ok != tc.ok {
+			t.Errorf("detectKind(%q, %q) = %q, %v", tc.host, tc.configured, got, ok)
+		}
+	}
+}
+
+func TestURLs(t *testing.T) {
+	for _, tc := range []struct {
+		kind               Kind
+		remote             Remote
+		newPR, branch, sha string
+	}{
+		{GitHub, Remote{"github.com", "o/r"},
+			"https://github.com/o/r/compare/main...feat?expand=1",
+			"https://github.com/o/r/tree/feat",
+			"https://github.com/o/r/commit/abc"},
+		{GitLab, Remote{"gitlab.com", "g/s/r"},
+			"https://gitlab.com/g/s/r/-/merge_requests/new?merge_request%5Bsource_branch%5D=feat&merge_request%5Btarget_branch%5D=main",
+			"https://gitlab.com/g/s/r/-/tree/feat",
+			"https://gitlab.com/g/s/r/-/commit/abc"},
+		{Bitbucket, Remote{"bitbucket.org", "w/r"},
+			"https://bitbucket.org/w/r/pull-requests/new?dest=main&source=feat",
+			"https://bitbucket.org/w/r/branch/feat",
+			"https://bitbucket.org/w/r/commits/abc"},
+	} {
+		p, err := New(tc.kind, tc.remote, "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := p.NewPRURL("feat", "main"); got != tc.newPR {
+			t.Errorf("%s NewPRURL = %q", tc.kind, got)
+		}
+		if got := p.BranchURL("feat"); got != tc.branch {
+			t.Errorf("%s BranchURL = %q", tc.kind, got)
+		}
+		if got := p.CommitURL("abc"); got != tc.sha {
+			t.Errorf("%s CommitURL = %q", tc.kind, got)
+		}
+	}
+}
+
+func stubGit(t *testing.T, remotes map[string]string, configured string) {
+	t.Helper()
+	old := runGit
+	t.Cleanup(func() { runGit = old })
+	runGit = func(_ context.Context, _ string, args ...string) (string, error) {
+		switch strings.Join(args[:min(2, len(args))], " ") {
+		case "remote get-url":
+			if url, ok := remotes[args[2]]; ok {
+				return url + "\n", nil
+			}
+			return "", errors.New("no such remote")
+		case "remote":
+			var names []string
+			for name := range remotes {
+				names = append(names, name)
+			}
+			return strings.Join(names, "\n"), nil
+		case "config --get":
+			if configured == "" {
+				return "", errors.New("unset")
+			}
+			return configured + "\n", nil
+		}
+		return "", errors.New("unexpected git " + strings.Join(args, " "))
+	}
+}
+
+func TestOpen(t *testing.T) {
+	stubGit(t, map[string]string{"origin": "git@github.com:o/r.git"}, "")
+	p, err := Open(context.Background(), "/repo")
+	if err != nil || p.Kind() != GitHub || p.Remote().Path != "o/r" {
+		t.Fatalf("Open = %v, %v", p, err)
+	}
+
+	// Without an origin the first remote is used, and the config names a
+	// self-hosted service.
+	stubGit(t, map[string]string{"upstream": "https://code.example.com/team/app.git"}, "gitlab")
+	if p, err = Open(context.Background(), "/repo"); err != nil || p.Kind() != GitLab {
+		t.Fatalf("Open with a configured kind = %v, %v", p, err)
+	}
+
+	stubGit(t, map[string]string{"origin": "https://code.example.com/team/app.git"}, "")
+	if _, err = Open(context.Background(), "/repo"); !errors.Is(err, ErrUnknownHost) {
+		t.Fatalf("Open on an unknown host error = %v", err)
+	}
+
+	stubGit(t, nil, "")
+	if _, err = Open(context.Background(), "/repo"); !errors.Is(err, ErrNoRemote) {
+		t.Fatalf("Open without remotes error = %v", err)
+	}
+}
@@ -0,0 +1,116 @@
+package forge
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// github calls GitHub through the gh CLI.
+type github struct {
+	remote Remote
+	dir    string
+}
+
+func (g *github) Kind() Kind     { return GitHub }
+func (g *github) Remote() Remote { return g.remote }
+
+// repo is the --repo value, with the host so GitHub Enterprise works too.
+func (g *github) repo() string { return g.remote.Host + "/" + g.remote.Path }
+
+func (g *github) gh(ctx context.Context, args ...string) ([]byte, error) {
+	if err := requireCLI("gh"); err != nil {
+		return nil, err
+	}
+	return runCLI(ctx, g.dir, "gh", args...)
+}
+
+type githubPR struct {
+	Number    int       `json:"number"`
+	Title     string    `json:"title"`
+	URL       string    `json:"url"`
+	State     string    `json:"state"`
+	IsDraft   bool      `json:"isDraft"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+func (g *github) FindPR(ctx context.Context, branch string) (PR, bool, error) {
+	out, err := g.gh(ctx, "pr", "list", "--repo", g.repo(), "--head", branch, "--state", "all",
+		"--limit", "1", "--json", "number,title,url,state,isDraft,createdAt")
+	if err != nil {
+		return PR{}, false, err
+	}
+	var prs []githubPR
+	if err := json.Unmarshal(out, &prs); err != nil {
+		return PR{}, false, fmt.Errorf("parse gh output: %w", err)
+	}
+	if len(prs) == 0 {
+		return PR{}, false, nil
+	}
+	p := prs[0]
+	return PR{Number: p.Number, Title: p.Title, URL: p.URL, State: strings.ToUpper(p.State), Draft: p.IsDraft, CreatedAt: p.CreatedAt}, true, nil
+}
+
+func (g *github) CreatePR(ctx context.Context, opts CreateOptions) (PR, error) {
+	args := []string{"pr", "create", "--repo", g.repo(), "--head", opts.Head, "--base", opts.Base,
+		"--title", opts.Title, "--body", opts.Body}
+	if opts.Draft {
+		args = append(args, "--draft")
+	}
+	out, err := g.gh(ctx, args...)
+	if err != nil {
+		return PR{}, err
+	}
+	if pr, ok, err := g.FindPR(ctx, opts.Head); err == nil && ok {
+		return pr, nil
+	}
+	// gh prints the new pull request's URL.
+	return PR{Title: opts.Title, URL: strings.TrimSpace(string(out)), State: "OPEN", Draft: opts.Draft, CreatedAt: time.Now()}, nil
+}
+
+func (g *github) Checks(ctx context.Context, branch string) (Checks, error) {
+	args := []string{"api", "repos/" + g.remote.Path + "/commits/" + url.PathEscape(branch) + "/check-runs?per_page=100"}
+	if g.remote.Host != "github.com" {
+		args = append(args, "--hostname", g.remote.Host)
+	}
+	out, err := g.gh(ctx, args...)
+	if err != nil {
+		return Checks{}, err
+	}
+	var resp struct {
+		CheckRuns []struct {
+			Status     string `json:"status"`
+			Conclusion string `json:"conclusion"`
+		} `json:"check_runs"`
+	}
+	if err := json.Unmarshal(out, &resp); err != nil {
+		return Checks{}, fmt.Errorf("parse gh output: %w", err)
+	}
+	var passed, failed, pending int
+	for _, run := range resp.CheckRuns {
+		switch {
+		case run.Status != "completed":
+			pending++
+		case run.Conclusion == "success" || run.Conclusion == "neutral" || run.Conclusion == "skipped":
+			passed++
+		default:
+			failed++
+		}
+	}
+	return summarize(passed, failed, pending), nil
+}
+
+func (g *github) NewPRURL(head, base string) string {
+	return g.remote.WebURL() + "/compare/" + base + "..." + head + "?expand=1"
+}
+
+func (g *github) BranchURL(branch string) string {
+	return g.remote.WebURL() + "/tree/" + branch
+}
+
+func (g *github) CommitURL(sha string) string {
+	return g.remote.WebURL() + "/commit/" + sha
+}
@@ -0,0 +1,159 @@
+package forge
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// gitlab calls GitLab's REST API through the glab CLI.
+type gitlab struct {
+	remote Remote
+	dir    string
+}
+
+func (g *gitlab) Kind() Kind     { return GitLab }
+func (g *gitlab) Remote() Remote { return g.remote }
+
+// api calls the project's endpoint below projects/<id>.
+func (g *gitlab) api(ctx context.Context, endpoint string, extra ...string) ([]byte, error) {
+	if err := requireCLI("glab"); err != nil {
+		return nil, err
+	}
+	args := []string{"api", "projects/" + url.PathEscape(g.remote.Path) + "/" + endpoint}
+	if g.remote.Host != "gitlab.com" {
+		args = append(args, "--hostname", g.remote.Host)
+	}
+	return runCLI(ctx, g.dir, "glab", append(args, extra...)...)
+}
+
+type gitlabMR struct {
+	IID       int       `json:"iid"`
+	Title     string    `json:"title"`
+	WebURL    string    `json:"web_url"`
+	State     string    `json:"state"`
+	Draft     bool      `json:"draft"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func (m gitlabMR) pr() PR {
+	state := "OPEN"
+	switch m.State {
+	case "merged":
+		state = "MERGED"
+	case "closed", "locked":
+		state = "CLOSED"
+	}
+	title := m.Title
+	if m.Draft {
+		title = gitlabTitle(title)
+	}
+	return PR{Number: m.IID, Title: title, URL: m.WebURL, State: state, Draft: m.Draft, CreatedAt: m.CreatedAt}
+}
+
+func (g *gitlab) FindPR(ctx context.Context, branch string) (PR, bool, error) {
+	q := url.Values{"source_branch": {branch}, "state": {"all"}, "order_by": {"created_at"}, "per_page": {"1"}}
+	out, err := g.api(ctx, "merge_requests?"+q.Encode())
+	if err != nil {
+		return PR{}, false, err
+	}
+	var mrs []gitlabMR
+	if err := json.Unmarshal(out, &mrs); err != nil {
+		return PR{}, false, fmt.Errorf("parse glab output: %w", err)
+	}
+	if len(mrs) == 0 {
+		return PR{}, false, nil
+	}
+	return mrs[0].pr(), true, nil
+}
+
+func (g *gitlab) CreatePR(ctx context.Context, opts CreateOptions) (PR, error) {
+	title := opts.Title
+	if opts.Draft {
+		title = "Draft: " + title
+	}
+	out, err := g.api(ctx, "merge_requests", "--method", "POST",
+		"-f", "source_branch="+opts.Head,
+		"-f", "target_branch="+opts.Base,
+		"-f", "title="+title,
+		"-f", "description="+opts.Body)
+	if err != nil {
+		return PR{}, err
+	}
+	var mr gitlabMR
+	if err := json.Unmarshal(out, &mr); err != nil {
+		return PR{}, fmt.Errorf("parse glab output: %w", err)
+	}
+	return mr.pr(), nil
+}
+
+// Checks reports the jobs of the branch's latest pipeline.
+func (g *gitlab) Checks(ctx context.Context, branch string) (Checks, error) {
+	q := url.Values{"ref": {branch}, "per_page": {"1"}}
+	out, err := g.api(ctx, "pipelines?"+q.Encode())
+	if err != nil {
+		return Checks{}, err
+	}
+	var pipelines []struct {
+		ID int `json:"id"`
+	}
+	if err := json.Unmarshal(out, &pipelines); err != nil {
+		return Checks{}, fmt.Errorf("parse glab output: %w", err)
+	}
+	if len(pipelines) == 0 {
+		return summarize(0, 0, 0), nil
+	}
+	out, err = g.api(ctx, fmt.Sprintf("pipelines/%d/jobs?per_page=100", pipelines[0].ID))
+	if err != nil {
+		return Checks{}, err
+	}
+	var jobs []struct {
+		Status       string `json:"status"`
+		AllowFailure bool   `json:"allow_failure"`
+	}
+	if err := json.Unmarshal(out, &jobs); err != nil {
+		return Checks{}, fmt.Errorf("parse glab output: %w", err)
+	}
+	var passed, failed, pending int
+	for _, job := range jobs {
+		switch job.Status {
+		case "success", "skipped", "manual":
+			passed++
+		case "failed", "canceled":
+			if job.AllowFailure {
+				passed++
+			} else {
+				failed++
+			}
+		default:
+			pending++
+		}
+	}
+	return summarize(passed, failed, pending), nil
+}
+
+func (g *gitlab) NewPRURL(head, base string) string {
+	q := url.Values{"merge_request[source_branch]": {head}, "merge_request[target_branch]": {base}}
+	return g.remote.WebURL() + "/-/merge_requests/new?" + q.Encode()
+}
+
+func (g *gitlab) BranchURL(branch string) string {
+	return g.remote.WebURL() + "/-/tree/" + branch
+}
+
+func (g *gitlab) CommitURL(sha string) string {
+	return g.remote.WebURL() + "/-/commit/" + sha
+}
+
+// gitlabTitle strips the prefix that marks a GitLab draft from its title.
+func gitlabTitle(title string) string {
+	for _, prefix := range []string{"Draft: ", "Draft:", "[Draft] ", "WIP: "} {
+		if rest, ok := strings.CutPrefix(title, prefix); ok {
+			return rest
+		}
+	}
+	return title
+}
@@ -0,0 +1,138 @@
+package forge
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// stubCLI answers service CLI calls from replies, keyed by the first
+// argument that names the endpoint or subcommand, and records each call.
+func stubCLI(t *testing.T, replies map[string]string) *[]string {
+	t.Helper()
+	oldLook, oldRun := lookPath, runCLI
+	t.Cleanup(func() { lookPath, runCLI = oldLook, oldRun })
+	lookPath = func(name string) (string, error) { return "/usr/bin/" + name, nil }
+	var calls []string
+	runCLI = func(_ context.Context, _, name string, args ...string) ([]byte, error) {
+		call := name + " " + strings.Join(args, " ")
+		calls = append(calls, call)
+		for key, reply := range replies {
+			if strings.Contains(call, key) {
+				return []byte(reply), nil
+			}
+		}
+		return nil, errors.New("unexpected " + call)
+	}
+	return &calls
+}
+
+func TestGitHub(t *testing.T) {
+	calls := stubCLI(t, map[string]string{
+		"pr list":     `[{"number":4,"title":"Fix","url":"https://github.com/o/r/pull/4","state":"MERGED","createdAt":"2026-03-01T11:00:00Z"}]`,
+		"/check-runs": `{"check_runs":[{"status":"completed","conclusion":"success"},{"status":"in_progress"},{"status":"completed","conclusion":"skipped"}]}`,
+	})
+	p, _ := New(GitHub, Remote{"github.com", "o/r"}, "/repo")
+	pr, ok, err := p.FindPR(context.Background(), "fix")
+	if err != nil || !ok || pr.Number != 4 || pr.State != "MERGED" || pr.CreatedAt.IsZero() {
+		t.Fatalf("FindPR = %+v, %v, %v", pr, ok, err)
+	}
+	if !strings.Contains((*calls)[0], "--repo github.com/o/r --head fix") {
+		t.Fatalf("gh call = %q", (*calls)[0])
+	}
+	checks, err := p.Checks(context.Background(), "feat/x")
+	if err != nil || checks != (Checks{State: ChecksPending, Passed: 2, Pending: 1}) {
+		t.Fatalf("Checks = %+v, %v", checks, err)
+	}
+	if !strings.Contains((*calls)[1], "repos/o/r/commits/feat%2Fx/check-runs") {
+		t.Fatalf("gh call = %q", (*calls)[1])
+	}
+
+	lookPath = func(string) (string, error) { return "", errors.New("not found") }
+	if _, _, err := p.FindPR(context.Background(), "fix"); err == nil || !strings.Contains(err.Error(), "gh is not installed") {
+		t.Fatalf("FindPR without gh error = %v", err)
+	}
+}
+
+func TestGitLab(t *testing.T) {
+	calls := stubCLI(t, map[string]string{
+		"merge_requests?": `[{"iid":9,"title":"Draft: Parser","web_url":"https://gitlab.example.com/g/r/-/merge_requests/9","state":"opened","draft":true}]`,
+		"--method POST":   `{"iid":10,"title":"New","web_url":"https://gitlab.example.com/g/r/-/merge_requests/10","state":"opened"}`,
+		"pipelines?":      `[{"id":77}]`,
+		"pipelines/77":    `[{"status":"success"},{"status":"failed","allow_failure":true},{"status":"failed"}]`,
+	})
+	p, _ := New(GitLab, Remote{"gitlab.example.com", "g/r"}, "/repo")
+	pr, ok, err := p.FindPR(context.Background(), "parser")
+	if err != nil || !ok || pr.Number != 9 || pr.Title != "Parser" || pr.State != "OPEN" || !pr.Draft {
+		t.Fatalf("FindPR = %+v, %v, %v", pr, ok, err)
+	}
+	if !strings.Contains((*calls)[0], "projects/g%2Fr/merge_requests?") || !strings.Contains((*calls)[0], "--hostname gitlab.example.com") {
+		t.Fatalf("glab call = %q", (*calls)[0])
+	}
+	pr, err = p.CreatePR(context.Background(), CreateOptions{Head: "new", Base: "main", Title: "New", Draft: true})
+	if err != nil || pr.Number != 10 || !strings.Contains((*calls)[1], "title=Draft: New") {
+		t.Fatalf("CreatePR = %+v, %v (call %q)", pr, err, (*calls)[1])
+	}
+	checks, err := p.Checks(context.Background(), "parser")
+	if err != nil || checks != (Checks{State: ChecksFailing, Passed: 2, Failed: 1}) {
+		t.Fatalf("Checks = %+v, %v", checks, err)
+	}
+}
+
+func TestBitbucket(t *testing.T) {
+	var created map[string]any
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = w.Write([]byte(`{"error":{"message":"Unauthorized"}}`))
+			return
+		}
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/repositories/w/r/pullrequests":
+			if r.URL.Query().Get("q") != `source.branch.name="feat"` {
+				t.Errorf("query = %q", r.URL.RawQuery)
+			}
+			_, _ = w.Write([]byte(`{"values":[{"id":3,"title":"Feat","state":"DECLINED","links":{"html":{"href":"https://bitbucket.org/w/r/pull-requests/3"}}}]}`))
+		case r.Method == http.MethodPost && r.URL.Path == "/repositories/w/r/pullrequests":
+			_ = json.NewDecoder(r.Body).Decode(&created)
+			_, _ = w.Write([]byte(`{"id":4,"title":"Feat","state":"OPEN"}`))
+		case r.URL.Path == "/repositories/w/r/refs/branches/feat":
+			_, _ = w.Write([]byte(`{"target":{"hash":"abc"}}`))
+		case r.URL.Path == "/repositories/w/r/commit/abc/statuses":
+			_, _ = w.Write([]byte(`{"values":[{"state":"SUCCESSFUL"},{"state":"SUCCESSFUL"}]}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+	old := bitbucketAPI
+	bitbucketAPI = srv.URL
+	t.Cleanup(func() { bitbucketAPI = old })
+
+	p, _ := New(Bitbucket, Remote{"bitbucket.org", "w/r"}, "")
+	t.Setenv("BITBUCKET_TOKEN", "")
+	t.Setenv("BITBUCKET_USERNAME", "")
+	if _, _, err := p.FindPR(context.Background(), "feat"); err == nil || !strings.Contains(err.Error(), "BITBUCKET_TOKEN") {
+		t.Fatalf("FindPR without credentials error = %v", err)
+	}
+
+	t.Setenv("BITBUCKET_TOKEN", "secret")
+	pr, ok, err := p.FindPR(context.Background(), "feat")
+	if err != nil || !ok || pr.Number != 3 || pr.State != "CLOSED" || pr.URL != "https://bitbucket.org/w/r/pull-requests/3" {
+		t.Fatalf("FindPR = %+v, %v, %v", pr, ok, err)
+	}
+	if pr, err = p.CreatePR(context.Background(), CreateOptions{Head: "feat", Base: "main", Title: "Feat"}); err != nil || pr.Number != 4 {
+		t.Fatalf("CreatePR = %+v, %v", pr, err)
+	}
+	if dest, _ := created["destination"].(map[string]any); dest["branch"].(map[string]any)["name"] != "main" {
+		t.Fatalf("created = %v", created)
+	}
+	checks, err := p.Checks(context.Background(), "feat")
+	if err != nil || checks != (Checks{State: ChecksPassing, Passed: 2}) {
+		t.Fatalf("Checks = %+v, %v", checks, err)
+	}
+}
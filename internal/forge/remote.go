@@ -0,0 +1,73 @@
+package forge
+
+import (
+	"net/url"
+	"strings"
+)
+
+// Remote is where a git remote points: a host and the repository's path on
+// it, such as github.com and owner/repo. GitLab paths may have more than two
+// parts, for subgroups.
+type Remote struct {
+	// Host is the web host, with the port only for an HTTP remote that
+	// names one.
+	Host string
+	Path string
+}
+
+// Owner is the path without the repository name: the user, organization,
+// group, or Bitbucket workspace.
+func (r Remote) Owner() string {
+	owner, _, _ := cutLast(r.Path, "/")
+	return owner
+}
+
+// Name is the repository name.
+func (r Remote) Name() string {
+	_, name, _ := cutLast(r.Path, "/")
+	return name
+}
+
+// WebURL is the repository's web page.
+func (r Remote) WebURL() string {
+	return "https://" + r.Host + "/" + r.Path
+}
+
+// ParseRemote parses a git remote URL: scp-like (git@host:owner/repo.git),
+// ssh://, or http(s)://.
+func ParseRemote(raw string) (Remote, bool) {
+	raw = strings.TrimSpace(raw)
+	var host, path string
+	if strings.Contains(raw, "://") {
+		u, err := url.Parse(raw)
+		if err != nil || u.Host == "" {
+			return Remote{}, false
+		}
+		host, path = u.Host, u.Path
+		if u.Scheme != "http" && u.Scheme != "https" {
+			// An SSH port is not the web port.
+			host = u.Hostname()
+		}
+	} else {
+		// scp-like: [user@]host:path
+		at := strings.Index(raw, "@")
+		colon := strings.Index(raw, ":")
+		if colon < 0 || colon < at {
+			return Remote{}, false
+		}
+		host, path = raw[at+1:colon], raw[colon+1:]
+	}
+	path = strings.TrimSuffix(strings.Trim(path, "/"), ".git")
+	path = strings.Trim(path, "/")
+	if host == "" || !strings.Contains(path, "/") {
+		return Remote{}, false
+	}
+	return Remote{Host: strings.ToLower(host), Path: path}, true
+}
+
+func cutLast(s, sep string) (before, after string, found bool) {
+	if i := strings.LastIndex(s, sep); i >= 0 {
+		return s[:i], s[i+len(sep):], true
+	}
+	return "", s, false
+}
@@ -0,0 +1,94 @@
+package fsatomic
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"runtime"
+)
+
+// ErrChecksum reports a file whose contents do not match its checksum.
+var ErrChecksum = errors.New("checksum mismatch")
+
+// checksumMember matches the "checksum" member WriteCheckedJSON appends as the
+// last line of the top-level object.
+var checksumMember = regexp.MustCompile(`,\n  "checksum": "sha256:([0-9a-f]{64})"\n}\s*$`)
+
+// WriteCheckedJSON is WriteJSON with a trailing "checksum" member holding the
+// SHA-256 of the document without it, so damage that still parses as JSON is
+// caught by VerifyJSON. Readers that do not know the member ignore it. Before
+// replacing path, the current file is kept as path+".bak" when it verifies,
+// so a reader always has a last good copy to fall back to.
+func WriteCheckedJSON(path string, v any) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return fmt.Errorf("fsatomic: marshal %s: %w", path, err)
+	}
+	keepBackup(path)
+	return WriteFile(path, addChecksum(data), 0o644)
+}
+
+// VerifyJSON checks data against the checksum WriteCheckedJSON appended. A
+// document without one, such as a file written by hand or by an older amux,
+// verifies as long as it is valid JSON.
+func VerifyJSON(data []byte) error {
+	if !json.Valid(data) {
+		return errors.New("not valid JSON")
+	}
+	m := checksumMember.FindSubmatchIndex(data)
+	if m == nil {
+		return nil
+	}
+	payload := append(bytes.Clone(data[:m[0]]), "\n}"...)
+	sum := sha256.Sum256(payload)
+	if hex.EncodeToString(sum[:]) != string(data[m[2]:m[3]]) {
+		return ErrChecksum
+	}
+	return nil
+}
+
+// Reseal rewrites path's checksum to match its current contents, accepting a
+// document that fails VerifyJSON only because of its checksum.
+func Reseal(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	if !json.Valid(data) {
+		return fmt.Errorf("fsatomic: %s is not valid JSON", path)
+	}
+	if m := checksumMember.FindSubmatchIndex(data); m != nil {
+		data = append(bytes.Clone(data[:m[0]]), "\n}"...)
+	}
+	return WriteFile(path, addChecksum(bytes.TrimRight(data, " \t\r\n")), 0o644)
+}
+
+// addChecksum appends the checksum member to an indented JSON object. Other
+// documents, including an empty object, are returned as they are.
+func addChecksum(data []byte) []byte {
+	if !bytes.HasSuffix(data, []byte("\n}")) {
+		return data
+	}
+	sum := sha256.Sum256(data)
+	out := bytes.Clone(data[:len(data)-2])
+	return fmt.Appendf(out, ",\n  \"checksum\": \"sha256:%x\"\n}", sum)
+}
+
+// keepBackup copies path to path+".bak" if it verifies. It is best effort: a
+// failed copy leaves the previous backup, which is still a good one. Windows
+// writes go through a .bak shuffle of their own, so no copy is kept there.
+func keepBackup(path string) {
+	if runtime.GOOS == "windows" {
+		return
+	}
+	data, err := os.ReadFile(path)
+	if err != nil || VerifyJSON(data) != nil {
+		return
+	}
+	_ = WriteFile(path+".bak", data, 0o644)
+}
@@ -0,0 +1,77 @@
+package fsatomic
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestWriteCheckedJSONVerifies(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.json")
+	if err := WriteCheckedJSON(path, map[string]string{"name": "amux"}); err != nil {
+		t.Fatal(err)
+	}
+	data, _ := os.ReadFile(path)
+	if !strings.HasPrefix(string(data), "{\n  \"name\": \"amux\",\n  \"checksum\": \"sha256:") {
+		t.Fatalf("content = %q", data)
+	}
+	if err := VerifyJSON(data); err != nil {
+		t.Fatalf("VerifyJSON = %v", err)
+	}
+
+	tampered := []byte(strings.Replace(string(data), "amux", "xmux", 1))
+	if err := VerifyJSON(tampered); !errors.Is(err, ErrChecksum) {
+		t.Fatalf("VerifyJSON(tampered) = %v, want ErrChecksum", err)
+	}
+	if err := VerifyJSON([]byte(`{"name": "by hand"}`)); err != nil {
+		t.Fatalf("a document without a checksum should verify, got %v", err)
+	}
+	if err := VerifyJSON(data[:len(data)/2]); err == nil {
+		t.Fatal("a truncated document verified")
+	}
+}
+
+func TestWriteCheckedJSONKeepsLastGoodBackup(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.json")
+	for _, name := range []string{"one", "two"} {
+		if err := WriteCheckedJSON(path, map[string]string{"name": name}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	backup, err := os.ReadFile(path + ".bak")
+	if err != nil || !strings.Contains(string(backup), `"one"`) {
+		t.Fatalf("backup = %q, %v; want the previous version", backup, err)
+	}
+
+	// A damaged file never replaces the good backup.
+	if err := os.WriteFile(path, []byte("{\"na"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := WriteCheckedJSON(path, map[string]string{"name": "three"}); err != nil {
+		t.Fatal(err)
+	}
+	if backup, _ := os.ReadFile(path + ".bak"); !strings.Contains(string(backup), `"one"`) {
+		t.Fatalf("backup = %q, want the last good version kept", backup)
+	}
+}
+
+func TestReseal(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.json")
+	if err := WriteCheckedJSON(path, map[string]string{"name": "amux"}); err != nil {
+		t.Fatal(err)
+	}
+	data, _ := os.ReadFile(path)
+	edited := strings.Replace(string(data), "amux", "edited", 1)
+	if err := os.WriteFile(path, []byte(edited), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := Reseal(path); err != nil {
+		t.Fatal(err)
+	}
+	data, _ = os.ReadFile(path)
+	if err := VerifyJSON(data); err != nil || !strings.Contains(string(data), "edited") || strings.Count(string(data), "checksum") != 1 {
+		t.Fatalf("resealed = %q, %v", data, err)
+	}
+}
@@ -0,0 +1,129 @@
+package git
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// bisectTimeout bounds one bisect command, which checks out a commit.
+const bisectTimeout = time.Minute
+
+// Bisect verdicts, as git bisect names them.
+const (
+	BisectGood = "good"
+	BisectBad  = "bad"
+	BisectSkip = "skip"
+)
+
+// ErrBisectInconclusive is returned when only skipped commits are left to
+// test, so the first bad commit is one of them but git cannot tell which.
+var ErrBisectInconclusive = errors.New("only skipped commits are left to test")
+
+var (
+	bisectStepsLeft = regexp.MustCompile(`roughly (\d+) step`)
+	bisectFirstBad  = regexp.MustCompile(`^# first bad commit: \[([0-9a-f]+)\] ?(.*)$`)
+	bisectPossible  = regexp.MustCompile(`^# possible first bad commit: \[([0-9a-f]+)\]`)
+)
+
+// BisectStep is where a bisect stands after starting it or marking a
+// commit.
+type BisectStep struct {
+	// Commit is the commit checked out to test next or, once Done, the
+	// first bad commit.
+	Commit  string
+	Subject string
+	// Steps is git's estimate of the steps left after testing Commit.
+	Steps int
+	Done  bool
+	// Candidates are the commits the first bad commit may be, when the
+	// bisect is inconclusive.
+	Candidates []string
+}
+
+// ResolveCommit resolves rev, such as HEAD or a tag, to a commit hash in
+// root.
+func ResolveCommit(ctx context.Context, root, rev string) (string, error) {
+	if strings.HasPrefix(rev, "-") {
+		return "", fmt.Errorf("%s is not a commit", rev)
+	}
+	out, err := RunGitCtx(ctx, root, "rev-parse", "-q", "--verify", rev+"^{commit}")
+	if err != nil {
+		return "", fmt.Errorf("%s is not a commit", rev)
+	}
+	return out, nil
+}
+
+// AddBisectWorktree checks commit out in a new worktree of repo at dir,
+// detached so that no branch is checked out or moved by bisecting in it.
+func AddBisectWorktree(ctx context.Context, repo, dir, commit string) error {
+	ctx, cancel := context.WithTimeout(ctx, worktreeTimeout)
+	defer cancel()
+	_, err := RunGitCtx(ctx, repo, "worktree", "add", "--detach", dir, commit)
+	return err
+}
+
+// RemoveBisectWorktree removes a worktree AddBisectWorktree made, with the
+// bisect state git keeps for it.
+func RemoveBisectWorktree(ctx context.Context, repo, dir string) error {
+	ctx, cancel := context.WithTimeout(ctx, worktreeTimeout)
+	defer cancel()
+	_, err := RunGitCtx(ctx, repo, "worktree", "remove", "--force", dir)
+	return err
+}
+
+// StartBisect starts bisecting in root between a bad and a good commit,
+// checking out the first commit to test. Bisect state is kept per worktree,
+// so other worktrees of the repository are not affected.
+func StartBisect(ctx context.Context, root, bad, good string) (BisectStep, error) {
+	return bisectCommand(ctx, root, "bisect", "start", bad, good, "--")
+}
+
+// MarkBisect marks the commit checked out in root with verdict, one of
+// BisectGood, BisectBad, or BisectSkip, and checks out the next one.
+func MarkBisect(ctx context.Context, root, verdict string) (BisectStep, error) {
+	return bisectCommand(ctx, root, "bisect", verdict)
+}
+
+// bisectCommand runs a bisect command and reads where the bisect stands
+// from its log, which records the first bad commit once found.
+func bisectCommand(ctx context.Context, root string, args ...string) (BisectStep, error) {
+	ctx, cancel := context.WithTimeout(ctx, bisectTimeout)
+	defer cancel()
+	out, runErr := RunGitCtx(ctx, root, args...)
+	log, err := RunGitCtx(ctx, root, "bisect", "log")
+	if err != nil {
+		if runErr != nil {
+			return BisectStep{}, runErr
+		}
+		return BisectStep{}, err
+	}
+	var step BisectStep
+	for _, line := range strings.Split(log, "\n") {
+		if m := bisectFirstBad.FindStringSubmatch(line); m != nil {
+			return BisectStep{Commit: m[1], Subject: m[2], Done: true}, nil
+		}
+		if m := bisectPossible.FindStringSubmatch(line); m != nil {
+			step.Candidates = append(step.Candidates, m[1])
+		}
+	}
+	if len(step.Candidates) > 0 {
+		return step, ErrBisectInconclusive
+	}
+	if runErr != nil {
+		return BisectStep{}, runErr
+	}
+	head, err := RunGitCtx(ctx, root, "log", "-1", "--format=%H%x00%s")
+	if err != nil {
+		return BisectStep{}, err
+	}
+	step.Commit, step.Subject, _ = strings.Cut(head, "\x00")
+	if m := bisectStepsLeft.FindStringSubmatch(out); m != nil {
+		step.Steps, _ = strconv.Atoi(m[1])
+	}
+	return step, nil
+}
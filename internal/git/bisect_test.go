@@ -0,0 +1,94 @@
+package git
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestBisect(t *testing.T) {
+	skipIfNoGit(t)
+	repo := initRepo(t)
+	good := runGit(t, repo, "rev-parse", "HEAD")
+	commits := map[string]int{}
+	for i := 1; i <= 8; i++ {
+		content := "ok "
+		if i >= 5 {
+			content = "broken "
+		}
+		if err := os.WriteFile(filepath.Join(repo, "state"), []byte(content+strconv.Itoa(i)), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		runGit(t, repo, "add", "state")
+		runGit(t, repo, "commit", "-q", "-m", "change "+strconv.Itoa(i))
+		commits[runGit(t, repo, "rev-parse", "HEAD")] = i
+	}
+	branch := runGit(t, repo, "branch", "--show-current")
+
+	ctx := context.Background()
+	if got, err := ResolveCommit(ctx, repo, "HEAD~8"); err != nil || got != good {
+		t.Fatalf("ResolveCommit(HEAD~8) = %q, %v; want %q", got, err, good)
+	}
+	if _, err := ResolveCommit(ctx, repo, "no-such-branch"); err == nil || !strings.Contains(err.Error(), "no-such-branch is not a commit") {
+		t.Fatalf("ResolveCommit(no-such-branch) error = %v", err)
+	}
+	dir := filepath.Join(t.TempDir(), "bisect")
+	if err := AddBisectWorktree(ctx, repo, dir, "HEAD"); err != nil {
+		t.Fatalf("AddBisectWorktree() error = %v", err)
+	}
+	step, err := StartBisect(ctx, dir, "HEAD", good)
+	for tested := 0; err == nil && !step.Done; tested++ {
+		if tested > 4 || commits[step.Commit] == 0 || step.Subject == "" {
+			t.Fatalf("step %d = %+v", tested, step)
+		}
+		verdict := BisectGood
+		if raw, _ := os.ReadFile(filepath.Join(dir, "state")); strings.HasPrefix(string(raw), "broken") {
+			verdict = BisectBad
+		}
+		step, err = MarkBisect(ctx, dir, verdict)
+	}
+	if err != nil {
+		t.Fatalf("bisect error = %v", err)
+	}
+	if commits[step.Commit] != 5 || step.Subject != "change 5" {
+		t.Fatalf("first bad commit = %+v, want change 5", step)
+	}
+	if got := runGit(t, repo, "branch", "--show-current"); got != branch {
+		t.Fatalf("the repository's checkout moved to %q", got)
+	}
+	if err := RemoveBisectWorktree(ctx, repo, dir); err != nil {
+		t.Fatalf("RemoveBisectWorktree() error = %v", err)
+	}
+	if _, err := os.Stat(dir); !os.IsNotExist(err) {
+		t.Fatalf("worktree still exists: %v", err)
+	}
+}
+
+func TestBisectInconclusive(t *testing.T) {
+	skipIfNoGit(t)
+	repo := initRepo(t)
+	good := runGit(t, repo, "rev-parse", "HEAD")
+	for i := 1; i <= 3; i++ {
+		runGit(t, repo, "commit", "-q", "--allow-empty", "-m", "change "+strconv.Itoa(i))
+	}
+	ctx := context.Background()
+	dir := filepath.Join(t.TempDir(), "bisect")
+	if err := AddBisectWorktree(ctx, repo, dir, "HEAD"); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = RemoveBisectWorktree(ctx, repo, dir) })
+	step, err := StartBisect(ctx, dir, "HEAD", good)
+	for i := 0; err == nil; i++ {
+		if i > 3 {
+			t.Fatalf("skipping every commit should end the bisect, at %+v", step)
+		}
+		step, err = MarkBisect(ctx, dir, BisectSkip)
+	}
+	if !errors.Is(err, ErrBisectInconclusive) || len(step.Candidates) < 2 {
+		t.Fatalf("skipping every commit = %+v, %v; want inconclusive", step, err)
+	}
+}
@@ -0,0 +1,103 @@
+package git
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// ChangedLines returns the line numbers, in the working tree, of every line
+// the worktree at repoPath adds or changes relative to merge-base(base, HEAD):
+// both committed and uncommitted edits, plus every line of untracked files.
+// Paths are relative to the worktree root. Without a base branch, lines are
+// compared against HEAD.
+func ChangedLines(repoPath string) (map[string][]int, error) {
+	from := "HEAD"
+	if base, err := GetBaseBranch(repoPath); err == nil {
+		from = resolveMergeBase(repoPath, base)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), branchDiffTimeout)
+	defer cancel()
+	diff, err := RunGitCtx(ctx, repoPath, "diff", "--no-color", "--no-ext-diff", "--no-textconv", "--no-renames", "-U0", from)
+	if err != nil {
+		return nil, err
+	}
+	changed := parseChangedLines(diff)
+	untracked, err := RunGitRawCtx(ctx, repoPath, "ls-files", "--others", "--exclude-standard", "-z")
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range bytes.Split(untracked, []byte{0}) {
+		if len(name) == 0 {
+			continue
+		}
+		content, err := os.ReadFile(filepath.Join(repoPath, string(name)))
+		if err != nil || bytes.IndexByte(content, 0) >= 0 {
+			continue
+		}
+		n := bytes.Count(content, []byte{'\n'})
+		if len(content) > 0 && content[len(content)-1] != '\n' {
+			n++
+		}
+		lines := make([]int, n)
+		for i := range lines {
+			lines[i] = i + 1
+		}
+		changed[string(name)] = lines
+	}
+	return changed, nil
+}
+
+// parseChangedLines reads the added line numbers of each file from a -U0
+// unified diff.
+func parseChangedLines(diff string) map[string][]int {
+	changed := make(map[string][]int)
+	var path string
+	for _, line := range strings.Split(diff, "\n") {
+		switch {
+		case strings.HasPrefix(line, "+++ "):
+			path = strings.TrimPrefix(line, "+++ ")
+			if path == "/dev/null" {
+				path = ""
+			} else {
+				path = strings.TrimPrefix(path, "b/")
+			}
+		case strings.HasPrefix(line, "@@ ") && path != "":
+			start, count, ok := parseHunkNewRange(line)
+			if !ok {
+				continue
+			}
+			for i := range count {
+				changed[path] = append(changed[path], start+i)
+			}
+		}
+	}
+	return changed
+}
+
+// parseHunkNewRange returns the new-file range of a hunk header such as
+// "@@ -10,2 +12,3 @@".
+func parseHunkNewRange(header string) (start, count int, ok bool) {
+	fields := strings.Fields(header)
+	if len(fields) < 3 || !strings.HasPrefix(fields[2], "+") {
+		return 0, 0, false
+	}
+	rng := strings.TrimPrefix(fields[2], "+")
+	count = 1
+	if s, c, found := strings.Cut(rng, ","); found {
+		rng = s
+		n, err := strconv.Atoi(c)
+		if err != nil {
+			return 0, 0, false
+		}
+		count = n
+	}
+	start, err := strconv.Atoi(rng)
+	if err != nil {
+		return 0, 0, false
+	}
+	return start, count, true
+}
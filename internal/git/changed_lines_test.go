@@ -0,0 +1,60 @@
+package git
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestChangedLines(t *testing.T) {
+	skipIfNoGit(t)
+	repo := initRepo(t)
+	write := func(name, content string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(repo, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("a.go", "one\ntwo\nthree\n")
+	runGit(t, repo, "add", "a.go")
+	runGit(t, repo, "commit", "-m", "add a")
+	runGit(t, repo, "checkout", "-b", "feature")
+
+	// A committed edit on the branch and an uncommitted one both count.
+	write("a.go", "one\nTWO\nthree\nfour\n")
+	runGit(t, repo, "commit", "-am", "edit a")
+	write("a.go", "ONE\nTWO\nthree\nfour\n")
+	write("new.go", "x\ny")
+
+	changed, err := ChangedLines(repo)
+	if err != nil {
+		t.Fatalf("ChangedLines() error = %v", err)
+	}
+	if got, want := changed["a.go"], []int{1, 2, 4}; !slices.Equal(got, want) {
+		t.Fatalf("a.go lines = %v, want %v", got, want)
+	}
+	if got, want := changed["new.go"], []int{1, 2}; !slices.Equal(got, want) {
+		t.Fatalf("new.go lines = %v, want %v", got, want)
+	}
+	if _, ok := changed["README.md"]; ok {
+		t.Fatal("unchanged README.md should not be listed")
+	}
+}
+
+func TestParseHunkNewRange(t *testing.T) {
+	tests := []struct {
+		header       string
+		start, count int
+	}{
+		{"@@ -1 +1 @@", 1, 1},
+		{"@@ -10,2 +12,3 @@ func x()", 12, 3},
+		{"@@ -5,1 +4,0 @@", 4, 0},
+	}
+	for _, tc := range tests {
+		start, count, ok := parseHunkNewRange(tc.header)
+		if !ok || start != tc.start || count != tc.count {
+			t.Errorf("parseHunkNewRange(%q) = %d, %d, %v; want %d, %d", tc.header, start, count, ok, tc.start, tc.count)
+		}
+	}
+}
@@ -0,0 +1,240 @@
+package git
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// compareTimeout bounds snapshotting and diffing two worktrees.
+const compareTimeout = time.Minute
+
+// Snapshot is a worktree's content as a tree object: HEAD plus uncommitted
+// changes and untracked, non-ignored files.
+type Snapshot struct {
+	Root string
+	Tree string
+}
+
+// ComparedFile is a file whose content differs between two worktrees.
+type ComparedFile struct {
+	Path    string
+	Added   int
+	Deleted int
+	Binary  bool
+}
+
+// Comparison is the difference between two worktrees of one repository,
+// from From to To.
+type Comparison struct {
+	From  Snapshot
+	To    Snapshot
+	Files []ComparedFile
+}
+
+// SnapshotWorktree writes root's current content to the object store as a
+// tree, through a scratch index so the worktree's own index is untouched.
+// The objects written are unreferenced and left for git gc.
+func SnapshotWorktree(ctx context.Context, root string) (Snapshot, error) {
+	ctx, cancel := context.WithTimeout(ctx, compareTimeout)
+	defer cancel()
+	indexPath, err := RunGitCtx(ctx, root, "rev-parse", "--path-format=absolute", "--git-path", "index")
+	if err != nil {
+		return Snapshot{}, err
+	}
+	scratch, err := os.CreateTemp("", "amux-snapshot-*.index")
+	if err != nil {
+		return Snapshot{}, err
+	}
+	defer os.Remove(scratch.Name())
+	// Starting from a copy of the index lets git add reuse its stat data
+	// instead of hashing every file.
+	if index, err := os.Open(indexPath); err == nil {
+		_, err = io.Copy(scratch, index)
+		_ = index.Close()
+		if err != nil {
+			_ = scratch.Close()
+			return Snapshot{}, err
+		}
+	}
+	if err := scratch.Close(); err != nil {
+		return Snapshot{}, err
+	}
+	if _, err := runGitWithIndex(ctx, root, scratch.Name(), "add", "--all"); err != nil {
+		return Snapshot{}, err
+	}
+	tree, err := runGitWithIndex(ctx, root, scratch.Name(), "write-tree")
+	if err != nil {
+		return Snapshot{}, err
+	}
+	return Snapshot{Root: root, Tree: tree}, nil
+}
+
+// runGitWithIndex runs git against indexFile instead of the worktree's index.
+func runGitWithIndex(ctx context.Context, dir, indexFile string, args ...string) (string, error) {
+	cmd := exec.Command("git", hardenedGitArgs(args)...)
+	cmd.Dir = dir
+	cmd.Env = append(filteredGitEnv(), "GIT_INDEX_FILE="+indexFile)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	killedByContext, err := runGitCommand(ctx, cmd)
+	if err != nil {
+		if ctxErr := gitCommandContextErrorWithKill(ctx, err, args, killedByContext); ctxErr != nil {
+			return "", ctxErr
+		}
+		return "", newGitError(args, stderr.String(), err)
+	}
+	return strings.TrimSpace(stdout.String()), nil
+}
+
+// CompareWorktrees snapshots two worktrees of the same repository and lists
+// the files that differ between them.
+func CompareWorktrees(ctx context.Context, from, to string) (*Comparison, error) {
+	fromSnap, err := SnapshotWorktree(ctx, from)
+	if err != nil {
+		return nil, err
+	}
+	toSnap, err := SnapshotWorktree(ctx, to)
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithTimeout(ctx, compareTimeout)
+	defer cancel()
+	out, err := RunGitRawCtx(ctx, from, "diff", "--numstat", "-z", "--no-renames", fromSnap.Tree, toSnap.Tree)
+	if err != nil {
+		return nil, err
+	}
+	return &Comparison{From: fromSnap, To: toSnap, Files: parseNumstatZ(out)}, nil
+}
+
+// DiffFromBase lists the files root's current content, committed or not,
+// changes since its branch forked from base. With no base it is compared
+// with HEAD.
+func DiffFromBase(ctx context.Context, root, base string) ([]ComparedFile, error) {
+	snap, err := SnapshotWorktree(ctx, root)
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithTimeout(ctx, compareTimeout)
+	defer cancel()
+	from := "HEAD"
+	if base != "" {
+		if from, err = RunGitCtx(ctx, root, "merge-base", base, "HEAD"); err != nil {
+			return nil, err
+		}
+	}
+	out, err := RunGitRawCtx(ctx, root, "diff", "--numstat", "-z", "--no-renames", from, snap.Tree)
+	if err != nil {
+		return nil, err
+	}
+	return parseNumstatZ(out), nil
+}
+
+// UncommittedChanges returns a binary patch of root's changes since HEAD,
+// staged or not and including untracked, non-ignored files. It is empty
+// when the worktree is clean.
+func UncommittedChanges(ctx context.Context, root string) ([]byte, error) {
+	snap, err := SnapshotWorktree(ctx, root)
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithTimeout(ctx, compareTimeout)
+	defer cancel()
+	return RunGitRawCtx(ctx, root, "diff", "--binary", "--no-renames", "HEAD", snap.Tree)
+}
+
+// ApplyUncommittedChanges applies a patch from UncommittedChanges to root's
+// worktree, leaving the index alone. Nothing is changed unless the whole
+// patch applies.
+func ApplyUncommittedChanges(ctx context.Context, root string, patch []byte) error {
+	if len(patch) == 0 {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(ctx, compareTimeout)
+	defer cancel()
+	return applyPatch(ctx, root, string(patch))
+}
+
+// parseNumstatZ reads `git diff --numstat -z --no-renames` output.
+func parseNumstatZ(out []byte) []ComparedFile {
+	var files []ComparedFile
+	for _, entry := range strings.Split(string(out), "\x00") {
+		fields := strings.SplitN(entry, "\t", 3)
+		if len(fields) != 3 {
+			continue
+		}
+		f := ComparedFile{Path: fields[2]}
+		if fields[0] == "-" && fields[1] == "-" {
+			f.Binary = true
+		} else {
+			f.Added, _ = strconv.Atoi(fields[0])
+			f.Deleted, _ = strconv.Atoi(fields[1])
+		}
+		files = append(files, f)
+	}
+	return files
+}
+
+// GetComparisonFileDiff returns how path differs from c.From to c.To.
+func GetComparisonFileDiff(c *Comparison, path string) (*DiffResult, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), diffTimeout)
+	defer cancel()
+	output, err := RunGitCtx(ctx, c.From.Root, "diff", "--no-color", "--no-ext-diff", "--no-textconv", "-U3",
+		c.From.Tree, c.To.Tree, "--", path)
+	if err != nil {
+		return &DiffResult{Path: path, Error: err.Error()}, nil
+	}
+	return parseDiff(path, output), nil
+}
+
+// ErrTakeConflict is returned when changes taken from a comparison do not
+// apply because the target worktree has changed since.
+var ErrTakeConflict = errors.New("does not apply to the worktree as compared")
+
+// TakeFile makes path in c.From's worktree match c.To's version of it.
+func TakeFile(ctx context.Context, c *Comparison, path string) error {
+	ctx, cancel := context.WithTimeout(ctx, compareTimeout)
+	defer cancel()
+	patch, err := RunGitRawCtx(ctx, c.From.Root, "diff", "--binary", "--no-color", "--no-ext-diff", "--no-textconv",
+		c.From.Tree, c.To.Tree, "--", path)
+	if err != nil {
+		return err
+	}
+	if len(patch) == 0 {
+		return nil
+	}
+	return takePatch(ctx, c, path, string(patch))
+}
+
+// TakeHunk applies one hunk of the comparison, as a patch from
+// DiffResult.HunkPatch, to c.From's worktree.
+func TakeHunk(ctx context.Context, c *Comparison, path, patch string) error {
+	ctx, cancel := context.WithTimeout(ctx, compareTimeout)
+	defer cancel()
+	return takePatch(ctx, c, path, patch)
+}
+
+// takePatch applies patch in c.From's worktree. git apply checks every hunk
+// before writing anything, so a conflict leaves the worktree as it was.
+func takePatch(ctx context.Context, c *Comparison, path, patch string) error {
+	err := applyPatch(ctx, c.From.Root, patch)
+	var gitErr *Error
+	if errors.As(err, &gitErr) && gitErr.ExitCode > 0 {
+		return fmt.Errorf("%s %w: %s", path, ErrTakeConflict, strings.TrimSpace(gitErr.Stderr))
+	}
+	return err
+}
+
+// Name describes the comparison by its worktree directories.
+func (c *Comparison) Name() string {
+	return filepath.Base(c.From.Root) + " → " + filepath.Base(c.To.Root)
+}
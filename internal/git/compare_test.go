@@ -0,0 +1,194 @@
+package git
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCompareWorktrees(t *testing.T) {
+	skipIfNoGit(t)
+	repo := initRepo(t)
+	write := func(dir, name, content string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write(repo, "shared.txt", "one\ntwo\n")
+	write(repo, ".gitignore", "build/\n")
+	runGit(t, repo, "add", ".")
+	runGit(t, repo, "commit", "-m", "base")
+	other := filepath.Join(t.TempDir(), "attempt-b")
+	runGit(t, repo, "worktree", "add", "-b", "attempt-b", other)
+
+	// Attempt A edits a file without committing; attempt B commits a
+	// different edit and adds an untracked file and an ignored one.
+	write(repo, "shared.txt", "one\nTWO\n")
+	write(other, "shared.txt", "one\n2\n")
+	runGit(t, other, "commit", "-am", "attempt b")
+	write(other, "new.txt", "fresh\n")
+	if err := os.MkdirAll(filepath.Join(other, "build"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	write(other, "build/out.bin", "ignored\n")
+	statusBefore := runGit(t, repo, "status", "--porcelain")
+
+	cmp, err := CompareWorktrees(context.Background(), repo, other)
+	if err != nil {
+		t.Fatalf("CompareWorktrees() error = %v", err)
+	}
+	got := map[string]ComparedFile{}
+	for _, f := range cmp.Files {
+		got[f.Path] = f
+	}
+	if len(got) != 2 || got["shared.txt"].Added != 1 || got["shared.txt"].Deleted != 1 || got["new.txt"].Added != 1 {
+		t.Fatalf("files = %+v, want shared.txt and new.txt", cmp.Files)
+	}
+	if statusAfter := runGit(t, repo, "status", "--porcelain"); statusAfter != statusBefore {
+		t.Fatalf("comparing changed the worktree's status: %q, was %q", statusAfter, statusBefore)
+	}
+
+	diff, err := GetComparisonFileDiff(cmp, "shared.txt")
+	if err != nil || diff.Error != "" {
+		t.Fatalf("GetComparisonFileDiff() = %+v, %v", diff, err)
+	}
+	if !strings.Contains(diff.Content, "-TWO") || !strings.Contains(diff.Content, "+2") || len(diff.Hunks) != 1 {
+		t.Fatalf("diff = %q, want TWO replaced by 2", diff.Content)
+	}
+}
+
+func TestDiffFromBase(t *testing.T) {
+	skipIfNoGit(t)
+	repo := initRepo(t)
+	base := runGit(t, repo, "rev-parse", "HEAD")
+	if err := os.WriteFile(filepath.Join(repo, "committed.txt"), []byte("a\nb\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	runGit(t, repo, "add", ".")
+	runGit(t, repo, "commit", "-m", "work")
+	if err := os.WriteFile(filepath.Join(repo, "untracked.txt"), []byte("c\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	files, err := DiffFromBase(context.Background(), repo, base)
+	if err != nil {
+		t.Fatalf("DiffFromBase() error = %v", err)
+	}
+	if len(files) != 2 || files[0] != (ComparedFile{Path: "committed.txt", Added: 2}) || files[1] != (ComparedFile{Path: "untracked.txt", Added: 1}) {
+		t.Fatalf("DiffFromBase() = %+v, want the commit and the untracked file", files)
+	}
+	if files, err = DiffFromBase(context.Background(), repo, ""); err != nil || len(files) != 1 {
+		t.Fatalf("DiffFromBase() against HEAD = %+v, %v", files, err)
+	}
+}
+
+func TestUncommittedChanges(t *testing.T) {
+	skipIfNoGit(t)
+	repo := initRepo(t)
+	if patch, err := UncommittedChanges(context.Background(), repo); err != nil || len(patch) != 0 {
+		t.Fatalf("UncommittedChanges() on a clean worktree = %q, %v", patch, err)
+	}
+	if err := os.WriteFile(filepath.Join(repo, "README.md"), []byte("edited\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(repo, "notes.bin"), []byte{0, 1, 2, 0}, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	patch, err := UncommittedChanges(context.Background(), repo)
+	if err != nil || len(patch) == 0 {
+		t.Fatalf("UncommittedChanges() = %q, %v", patch, err)
+	}
+
+	clone := filepath.Join(t.TempDir(), "restored")
+	runGit(t, repo, "worktree", "add", "--detach", clone, "HEAD")
+	if err := ApplyUncommittedChanges(context.Background(), clone, patch); err != nil {
+		t.Fatalf("ApplyUncommittedChanges() error = %v", err)
+	}
+	if got, _ := os.ReadFile(filepath.Join(clone, "README.md")); string(got) != "edited\n" {
+		t.Fatalf("README.md = %q, want the edit", got)
+	}
+	if got, _ := os.ReadFile(filepath.Join(clone, "notes.bin")); string(got) != "\x00\x01\x02\x00" {
+		t.Fatalf("notes.bin = %q, want the untracked binary file", got)
+	}
+	if staged := runGit(t, clone, "diff", "--cached", "--name-only"); staged != "" {
+		t.Fatalf("staged = %q, want the index untouched", staged)
+	}
+}
+
+func TestParseNumstatZ(t *testing.T) {
+	files := parseNumstatZ([]byte("3\t1\ta b.go\x00-\t-\timg.png\x00"))
+	if len(files) != 2 || files[0] != (ComparedFile{Path: "a b.go", Added: 3, Deleted: 1}) || !files[1].Binary {
+		t.Fatalf("parseNumstatZ() = %+v", files)
+	}
+}
+
+func TestTakeFromComparison(t *testing.T) {
+	skipIfNoGit(t)
+	repo := initRepo(t)
+	write := func(dir, name, content string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	var lines []string
+	for i := 1; i <= 20; i++ {
+		lines = append(lines, fmt.Sprintf("line %d", i))
+	}
+	write(repo, "calc.txt", strings.Join(lines, "\n")+"\n")
+	runGit(t, repo, "add", ".")
+	runGit(t, repo, "commit", "-m", "base")
+	other := filepath.Join(t.TempDir(), "attempt-b")
+	runGit(t, repo, "worktree", "add", "-b", "attempt-b", other)
+
+	theirs := append([]string(nil), lines...)
+	theirs[1], theirs[17] = "top from b", "bottom from b"
+	write(other, "calc.txt", strings.Join(theirs, "\n")+"\n")
+	write(other, "extra.txt", "only in b\n")
+
+	ctx := context.Background()
+	cmp, err := CompareWorktrees(ctx, repo, other)
+	if err != nil {
+		t.Fatal(err)
+	}
+	diff, err := GetComparisonFileDiff(cmp, "calc.txt")
+	if err != nil || len(diff.Hunks) != 2 {
+		t.Fatalf("diff = %+v, %v; want two hunks", diff, err)
+	}
+	patch, ok := diff.HunkPatch(1)
+	if !ok || !strings.HasPrefix(patch, "diff --git") || strings.Contains(patch, "top from b") {
+		t.Fatalf("HunkPatch(1) = %q, want the header and the second hunk only", patch)
+	}
+
+	if err := TakeHunk(ctx, cmp, "calc.txt", patch); err != nil {
+		t.Fatalf("TakeHunk() error = %v", err)
+	}
+	content, _ := os.ReadFile(filepath.Join(repo, "calc.txt"))
+	if !strings.Contains(string(content), "bottom from b") || strings.Contains(string(content), "top from b") {
+		t.Fatalf("calc.txt = %q, want only the bottom hunk taken", content)
+	}
+	// The same hunk no longer applies.
+	if err := TakeHunk(ctx, cmp, "calc.txt", patch); !errors.Is(err, ErrTakeConflict) {
+		t.Fatalf("TakeHunk() again = %v, want ErrTakeConflict", err)
+	}
+
+	if err := TakeFile(ctx, cmp, "extra.txt"); err != nil {
+		t.Fatalf("TakeFile() error = %v", err)
+	}
+	if content, err := os.ReadFile(filepath.Join(repo, "extra.txt")); err != nil || string(content) != "only in b\n" {
+		t.Fatalf("extra.txt = %q, %v; want b's file", content, err)
+	}
+	// calc.txt has changed since the comparison, so taking all of it
+	// conflicts and leaves it alone.
+	if err := TakeFile(ctx, cmp, "calc.txt"); !errors.Is(err, ErrTakeConflict) {
+		t.Fatalf("TakeFile() on a changed file = %v, want ErrTakeConflict", err)
+	}
+	if after, _ := os.ReadFile(filepath.Join(repo, "calc.txt")); string(after) != string(content) {
+		t.Fatal("a conflicting take should not write the file")
+	}
+}
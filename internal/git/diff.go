@@ -194,6 +194,26 @@ func parseDiff(path, content string) *DiffResult {
 	return result
 }
 
+// HunkPatch returns the i-th hunk with the file header before it, as a patch
+// git apply accepts.
+func (d *DiffResult) HunkPatch(i int) (string, bool) {
+	if i < 0 || i >= len(d.Hunks) {
+		return "", false
+	}
+	end := len(d.Lines)
+	if i+1 < len(d.Hunks) {
+		end = d.Hunks[i+1].StartLine
+	}
+	var b strings.Builder
+	for _, line := range d.Lines[:d.Hunks[0].StartLine] {
+		b.WriteString(line.Content + "\n")
+	}
+	for _, line := range d.Lines[d.Hunks[i].StartLine:end] {
+		b.WriteString(line.Content + "\n")
+	}
+	return b.String(), true
+}
+
 // HunkCount returns the number of hunks in the diff
 func (d *DiffResult) HunkCount() int {
 	return len(d.Hunks)
@@ -0,0 +1,64 @@
+package git
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// maxFingerprintFile bounds how much of an untracked file is hashed; larger
+// files contribute their size and modification time instead.
+const maxFingerprintFile = 1 << 20
+
+// TreeFingerprint returns a hash of the working tree's contents at dir: the
+// commit HEAD points at, the diff of tracked files against it, and the
+// untracked files that are not ignored. It changes whenever a file in the
+// tree does, so results computed for one tree can be reused until then.
+func TreeFingerprint(ctx context.Context, dir string) (string, error) {
+	head, err := RunGitCtx(ctx, dir, "rev-parse", "HEAD")
+	if err != nil {
+		return "", err
+	}
+	diff, err := RunGitRawCtx(ctx, dir, "diff", "HEAD", "--binary", "--no-ext-diff", "--no-textconv")
+	if err != nil {
+		return "", err
+	}
+	untracked, err := RunGitRawCtx(ctx, dir, "ls-files", "--others", "--exclude-standard", "-z")
+	if err != nil {
+		return "", err
+	}
+	h := sha256.New()
+	fmt.Fprintf(h, "%s\x00%d\x00", head, len(diff))
+	h.Write(diff)
+	for _, name := range bytes.Split(untracked, []byte{0}) {
+		if len(name) == 0 {
+			continue
+		}
+		h.Write(name)
+		h.Write([]byte{0})
+		hashUntracked(h, filepath.Join(dir, string(name)))
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+func hashUntracked(w io.Writer, path string) {
+	info, err := os.Lstat(path)
+	if err != nil || !info.Mode().IsRegular() {
+		return
+	}
+	if info.Size() > maxFingerprintFile {
+		fmt.Fprintf(w, "%d %d\x00", info.Size(), info.ModTime().UnixNano())
+		return
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return
+	}
+	defer f.Close()
+	_, _ = io.Copy(w, f)
+}
@@ -0,0 +1,58 @@
+package git
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestTreeFingerprint(t *testing.T) {
+	skipIfNoGit(t)
+	repo := initRepo(t)
+	ctx := context.Background()
+	fingerprint := func() string {
+		t.Helper()
+		fp, err := TreeFingerprint(ctx, repo)
+		if err != nil {
+			t.Fatalf("TreeFingerprint() error = %v", err)
+		}
+		return fp
+	}
+
+	clean := fingerprint()
+	if again := fingerprint(); again != clean {
+		t.Fatalf("fingerprint of an unchanged tree moved: %s != %s", again, clean)
+	}
+	if err := os.WriteFile(filepath.Join(repo, "new.txt"), []byte("one"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	untracked := fingerprint()
+	if untracked == clean {
+		t.Fatal("adding an untracked file did not change the fingerprint")
+	}
+	if err := os.WriteFile(filepath.Join(repo, "new.txt"), []byte("two"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if edited := fingerprint(); edited == untracked {
+		t.Fatal("editing an untracked file did not change the fingerprint")
+	}
+	if err := os.Remove(filepath.Join(repo, "new.txt")); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(repo, ".gitignore"), []byte("*.log\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	runGit(t, repo, "add", ".gitignore")
+	runGit(t, repo, "commit", "-m", "ignore logs")
+	committed := fingerprint()
+	if committed == clean {
+		t.Fatal("a new commit did not change the fingerprint")
+	}
+	if err := os.WriteFile(filepath.Join(repo, "debug.log"), []byte("noise"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if ignored := fingerprint(); ignored != committed {
+		t.Fatal("an ignored file changed the fingerprint")
+	}
+}
@@ -0,0 +1,162 @@
+package git
+
+import (
+	"context"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// FileHunk is one hunk of a worktree's unstaged diff, with the file header
+// needed to apply it on its own.
+type FileHunk struct {
+	Path string
+	Hunk Hunk
+	// header is the file's diff header, from "diff --git" through "+++".
+	header string
+	// Body is the hunk: its @@ line and the lines after it.
+	Body string
+}
+
+// Patch returns the hunk as a patch git apply accepts.
+func (h FileHunk) Patch() string {
+	return h.header + h.Body
+}
+
+// WorktreeHunks returns the hunks of repoPath's unstaged changes to tracked
+// files, in diff order. Binary and mode-only changes have no hunks and are
+// left out.
+func WorktreeHunks(ctx context.Context, repoPath string) ([]FileHunk, error) {
+	out, err := RunGitRawCtx(ctx, repoPath, "diff", "--no-color", "--no-ext-diff", "--no-textconv", "-U3")
+	if err != nil {
+		return nil, err
+	}
+	return parseHunks(string(out)), nil
+}
+
+// parseHunks splits unified diff output into its hunks.
+func parseHunks(diff string) []FileHunk {
+	var (
+		hunks    []FileHunk
+		header   strings.Builder
+		path     string
+		inHeader bool
+	)
+	lines := strings.SplitAfter(diff, "\n")
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+		switch {
+		case strings.HasPrefix(line, "diff --git "):
+			header.Reset()
+			header.WriteString(line)
+			path = ""
+			inHeader = true
+		case inHeader && !strings.HasPrefix(line, "@@"):
+			header.WriteString(line)
+			if name, ok := strings.CutPrefix(line, "+++ "); ok && !strings.HasPrefix(name, "/dev/null") {
+				path = diffPath(name)
+			} else if name, ok := strings.CutPrefix(line, "--- "); ok && path == "" {
+				path = diffPath(name)
+			}
+		case strings.HasPrefix(line, "@@"):
+			inHeader = false
+			matches := hunkPattern.FindStringSubmatch(strings.TrimRight(line, "\n"))
+			if matches == nil {
+				continue
+			}
+			var body strings.Builder
+			body.WriteString(line)
+			for i+1 < len(lines) && !strings.HasPrefix(lines[i+1], "@@") && !strings.HasPrefix(lines[i+1], "diff --git ") {
+				i++
+				body.WriteString(lines[i])
+			}
+			hunks = append(hunks, FileHunk{
+				Path:   path,
+				Hunk:   hunkFromHeader(matches),
+				header: header.String(),
+				Body:   body.String(),
+			})
+		}
+	}
+	return hunks
+}
+
+// hunkFromHeader reads the ranges of a matched @@ line.
+func hunkFromHeader(matches []string) Hunk {
+	hunk := Hunk{Header: matches[0], OldCount: 1, NewCount: 1}
+	hunk.OldStart, _ = strconv.Atoi(matches[1])
+	if matches[2] != "" {
+		hunk.OldCount, _ = strconv.Atoi(matches[2])
+	}
+	hunk.NewStart, _ = strconv.Atoi(matches[3])
+	if matches[4] != "" {
+		hunk.NewCount, _ = strconv.Atoi(matches[4])
+	}
+	return hunk
+}
+
+// diffPath strips the a/ or b/ prefix from a ---/+++ name, unquoting it
+// first when git quoted it.
+func diffPath(name string) string {
+	name = strings.TrimRight(name, "\n")
+	if unquoted, err := strconv.Unquote(name); err == nil {
+		name = unquoted
+	}
+	if len(name) > 2 && (name[:2] == "a/" || name[:2] == "b/") {
+		name = name[2:]
+	}
+	return name
+}
+
+// StageHunk adds the hunk to the index, leaving the worktree as it is.
+func StageHunk(ctx context.Context, repoPath string, h FileHunk) error {
+	return applyHunk(ctx, repoPath, h, "--cached")
+}
+
+// RevertHunk undoes the hunk in the worktree, like answering yes to it in
+// git checkout -p.
+func RevertHunk(ctx context.Context, repoPath string, h FileHunk) error {
+	return applyHunk(ctx, repoPath, h, "--reverse")
+}
+
+// applyHunk runs git apply on the hunk's patch. Earlier hunks of the same
+// file may have moved it; git apply finds it by its context.
+func applyHunk(ctx context.Context, repoPath string, h FileHunk, mode string) error {
+	return applyPatch(ctx, repoPath, h.Patch(), mode)
+}
+
+// applyPatch runs git apply with args on patch, which is passed through a
+// temporary file.
+func applyPatch(ctx context.Context, repoPath, patch string, args ...string) error {
+	f, err := os.CreateTemp("", "amux-*.patch")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+	if _, err := f.WriteString(patch); err != nil {
+		_ = f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	args = append(append([]string{"apply"}, args...), "--whitespace=nowarn", f.Name())
+	_, err = RunGitCtx(ctx, repoPath, args...)
+	return err
+}
+
+// ApplyPatch applies a patch copied from elsewhere, such as an agent's
+// output, to repoPath's worktree. Hunk line counts are recounted and
+// whitespace differences in context are ignored, since copied patches often
+// get both wrong; paths without git's a/ and b/ prefixes are taken as they
+// are. Nothing is changed unless the whole patch applies.
+func ApplyPatch(ctx context.Context, repoPath, patch string) error {
+	if !strings.HasSuffix(patch, "\n") {
+		patch += "\n"
+	}
+	strip := "-p1"
+	if !strings.Contains(patch, "\n+++ b/") && !strings.HasPrefix(patch, "+++ b/") {
+		strip = "-p0"
+	}
+	return applyPatch(ctx, repoPath, patch, strip, "--recount", "--ignore-whitespace")
+}
@@ -0,0 +1,120 @@
+package git
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestWorktreeHunksStageAndRevert(t *testing.T) {
+	skipIfNoGit(t)
+	repo := initRepo(t)
+	file := filepath.Join(repo, "a.txt")
+	var lines []string
+	for i := 1; i <= 20; i++ {
+		lines = append(lines, "line")
+	}
+	original := strings.Join(lines, "\n") + "\n"
+	if err := os.WriteFile(file, []byte(original), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	runGit(t, repo, "add", "a.txt")
+	runGit(t, repo, "commit", "-m", "add a")
+
+	// Two edits far enough apart to be separate hunks.
+	edited := append([]string(nil), lines...)
+	edited[1] = "first edit"
+	edited[17] = "second edit"
+	if err := os.WriteFile(file, []byte(strings.Join(edited, "\n")+"\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+	hunks, err := WorktreeHunks(ctx, repo)
+	if err != nil {
+		t.Fatalf("WorktreeHunks() error = %v", err)
+	}
+	if len(hunks) != 2 || hunks[0].Path != "a.txt" || !strings.Contains(hunks[1].Body, "+second edit") {
+		t.Fatalf("hunks = %+v, want two hunks of a.txt", hunks)
+	}
+
+	if err := StageHunk(ctx, repo, hunks[0]); err != nil {
+		t.Fatalf("StageHunk() error = %v", err)
+	}
+	if err := RevertHunk(ctx, repo, hunks[1]); err != nil {
+		t.Fatalf("RevertHunk() error = %v", err)
+	}
+	if staged := runGit(t, repo, "diff", "--cached"); !strings.Contains(staged, "+first edit") || strings.Contains(staged, "second edit") {
+		t.Fatalf("staged diff = %q, want only the first edit", staged)
+	}
+	content, err := os.ReadFile(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(content), "second edit") || !strings.Contains(string(content), "first edit") {
+		t.Fatalf("worktree = %q, want the second edit reverted", content)
+	}
+	if hunks, err := WorktreeHunks(ctx, repo); err != nil || len(hunks) != 0 {
+		t.Fatalf("WorktreeHunks() after review = %+v, %v; want none", hunks, err)
+	}
+}
+
+func TestParseHunksPaths(t *testing.T) {
+	diff := "diff --git a/old.txt b/old.txt\n" +
+		"deleted file mode 100644\n" +
+		"--- a/old.txt\n" +
+		"+++ /dev/null\n" +
+		"@@ -1 +0,0 @@\n" +
+		"-gone\n" +
+		"diff --git \"a/sp ace.txt\" \"b/sp ace.txt\"\n" +
+		"--- \"a/sp ace.txt\"\n" +
+		"+++ \"b/sp ace.txt\"\n" +
+		"@@ -2,0 +3,2 @@ func\n" +
+		"+x\n" +
+		"+y\n"
+	hunks := parseHunks(diff)
+	if len(hunks) != 2 {
+		t.Fatalf("parseHunks() = %+v, want 2 hunks", hunks)
+	}
+	if hunks[0].Path != "old.txt" || hunks[0].Hunk.OldCount != 1 || hunks[0].Hunk.NewCount != 0 {
+		t.Fatalf("deleted file hunk = %+v", hunks[0])
+	}
+	if hunks[1].Path != "sp ace.txt" || hunks[1].Hunk.NewStart != 3 || hunks[1].Hunk.NewCount != 2 {
+		t.Fatalf("quoted path hunk = %+v", hunks[1])
+	}
+	if !strings.HasPrefix(hunks[1].Patch(), "diff --git \"a/sp ace.txt\"") {
+		t.Fatalf("Patch() = %q, want the file header first", hunks[1].Patch())
+	}
+}
+
+func TestApplyPatchFromTerminalOutput(t *testing.T) {
+	skipIfNoGit(t)
+	repo := initRepo(t)
+	file := filepath.Join(repo, "main.go")
+	if err := os.WriteFile(file, []byte("package main\n\nvar x = 1\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	runGit(t, repo, "add", "main.go")
+	runGit(t, repo, "commit", "-m", "add main")
+
+	// As copied from a terminal: no a/ b/ prefixes, a wrong line count, the
+	// blank context line's space trimmed, and no final newline.
+	patch := "--- main.go\n+++ main.go\n@@ -1,9 +1,9 @@\n package main\n\n-var x = 1\n+var x = 2"
+	ctx := context.Background()
+	if err := ApplyPatch(ctx, repo, patch); err != nil {
+		t.Fatalf("ApplyPatch() error = %v", err)
+	}
+	if content, _ := os.ReadFile(file); string(content) != "package main\n\nvar x = 2\n" {
+		t.Fatalf("main.go = %q", content)
+	}
+
+	// A patch that no longer applies leaves the worktree alone.
+	if err := ApplyPatch(ctx, repo, "--- a/main.go\n+++ b/main.go\n@@ -3 +3 @@\n-var y = 1\n+var y = 2\n"); err == nil {
+		t.Fatal("ApplyPatch() of a stale patch succeeded")
+	}
+	if content, _ := os.ReadFile(file); string(content) != "package main\n\nvar x = 2\n" {
+		t.Fatalf("main.go changed by a failed apply: %q", content)
+	}
+}
@@ -0,0 +1,116 @@
+package git
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// mergeTimeout bounds one merge, continue, or undo in an integration
+// worktree.
+const mergeTimeout = time.Minute
+
+// ErrMergeConflict is returned when a merge stops on conflicting files, or
+// is continued before they are all resolved.
+var ErrMergeConflict = errors.New("merge has conflicts")
+
+// MergeResult is the outcome of merging a branch into a worktree.
+type MergeResult struct {
+	// Head is the worktree's commit before the merge; UndoMerge returns to it.
+	Head string
+	// UpToDate is set when the branch had nothing to merge.
+	UpToDate bool
+	// Conflicts lists the unmerged files when the merge stopped on them.
+	Conflicts []string
+}
+
+// MergeBranch merges branch into the branch checked out in root, always
+// with a merge commit. On conflicts the merge is left in progress for the
+// user to resolve, and the error wraps ErrMergeConflict.
+func MergeBranch(ctx context.Context, root, branch string) (MergeResult, error) {
+	ctx, cancel := context.WithTimeout(ctx, mergeTimeout)
+	defer cancel()
+	head, err := RunGitCtx(ctx, root, "rev-parse", "HEAD")
+	if err != nil {
+		return MergeResult{}, err
+	}
+	result := MergeResult{Head: head}
+	if _, err := RunGitCtx(ctx, root, "merge", "--no-ff", "--no-edit", branch); err != nil {
+		conflicts, listErr := UnmergedFiles(ctx, root)
+		if listErr != nil || len(conflicts) == 0 {
+			return result, err
+		}
+		result.Conflicts = conflicts
+		return result, fmt.Errorf("merging %s: %w", branch, ErrMergeConflict)
+	}
+	after, err := RunGitCtx(ctx, root, "rev-parse", "HEAD")
+	if err != nil {
+		return result, err
+	}
+	result.UpToDate = after == head
+	return result, nil
+}
+
+// UnmergedFiles lists the files of root's in-progress merge that still have
+// conflicts.
+func UnmergedFiles(ctx context.Context, root string) ([]string, error) {
+	out, err := RunGitRawCtx(ctx, root, "diff", "--name-only", "-z", "--diff-filter=U")
+	if err != nil {
+		return nil, err
+	}
+	var files []string
+	for _, name := range strings.Split(string(out), "\x00") {
+		if name != "" {
+			files = append(files, name)
+		}
+	}
+	return files, nil
+}
+
+// ContinueMerge commits root's in-progress merge once its conflicts are
+// resolved and staged. A merge the user already committed or aborted is
+// left as it is.
+func ContinueMerge(ctx context.Context, root string) error {
+	ctx, cancel := context.WithTimeout(ctx, mergeTimeout)
+	defer cancel()
+	if !mergeInProgress(ctx, root) {
+		return nil
+	}
+	conflicts, err := UnmergedFiles(ctx, root)
+	if err != nil {
+		return err
+	}
+	if len(conflicts) > 0 {
+		return fmt.Errorf("%s: %w", strings.Join(conflicts, ", "), ErrMergeConflict)
+	}
+	_, err = RunGitCtx(ctx, root, "commit", "--no-edit")
+	return err
+}
+
+// AbortMerge abandons root's in-progress merge, if there is one.
+func AbortMerge(ctx context.Context, root string) error {
+	ctx, cancel := context.WithTimeout(ctx, mergeTimeout)
+	defer cancel()
+	if !mergeInProgress(ctx, root) {
+		return nil
+	}
+	_, err := RunGitCtx(ctx, root, "merge", "--abort")
+	return err
+}
+
+// UndoMerge moves root's branch back to head, the commit before a merge.
+// --keep refuses, rather than discards, local changes the reset would touch.
+func UndoMerge(ctx context.Context, root, head string) error {
+	ctx, cancel := context.WithTimeout(ctx, mergeTimeout)
+	defer cancel()
+	_, err := RunGitCtx(ctx, root, "reset", "--keep", head)
+	return err
+}
+
+// mergeInProgress reports whether root has a merge waiting to be committed.
+func mergeInProgress(ctx context.Context, root string) bool {
+	_, err := RunGitCtx(ctx, root, "rev-parse", "-q", "--verify", "MERGE_HEAD")
+	return err == nil
+}
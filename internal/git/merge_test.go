@@ -0,0 +1,79 @@
+package git
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestMergeBranch(t *testing.T) {
+	skipIfNoGit(t)
+	repo := initRepo(t)
+	write := func(name, content string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(repo, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	base := runGit(t, repo, "branch", "--show-current")
+	branch := func(name, file, content string) {
+		t.Helper()
+		runGit(t, repo, "checkout", "-q", "-b", name, base)
+		write(file, content)
+		runGit(t, repo, "add", ".")
+		runGit(t, repo, "commit", "-q", "-m", name)
+	}
+	branch("feature-a", "a.txt", "a\n")
+	branch("feature-b", "README.md", "from b\n")
+	branch("feature-c", "README.md", "from c\n")
+	runGit(t, repo, "checkout", "-q", "-b", "integration", base)
+
+	ctx := context.Background()
+	result, err := MergeBranch(ctx, repo, "feature-a")
+	if err != nil || result.UpToDate || result.Head == "" {
+		t.Fatalf("MergeBranch(feature-a) = %+v, %v", result, err)
+	}
+	if again, err := MergeBranch(ctx, repo, "feature-a"); err != nil || !again.UpToDate {
+		t.Fatalf("MergeBranch(feature-a) again = %+v, %v; want up to date", again, err)
+	}
+	if _, err := MergeBranch(ctx, repo, "feature-b"); err != nil {
+		t.Fatal(err)
+	}
+
+	// c edits the line b did.
+	beforeC := runGit(t, repo, "rev-parse", "HEAD")
+	result, err = MergeBranch(ctx, repo, "feature-c")
+	if !errors.Is(err, ErrMergeConflict) || len(result.Conflicts) != 1 || result.Conflicts[0] != "README.md" {
+		t.Fatalf("MergeBranch(feature-c) = %+v, %v; want a README.md conflict", result, err)
+	}
+	if err := ContinueMerge(ctx, repo); !errors.Is(err, ErrMergeConflict) {
+		t.Fatalf("ContinueMerge() before resolving = %v, want ErrMergeConflict", err)
+	}
+	write("README.md", "from b and c\n")
+	runGit(t, repo, "add", "README.md")
+	if err := ContinueMerge(ctx, repo); err != nil {
+		t.Fatalf("ContinueMerge() error = %v", err)
+	}
+	if parents := runGit(t, repo, "log", "-1", "--pretty=%P"); len(parents) < 80 {
+		t.Fatalf("HEAD parents = %q, want a merge commit", parents)
+	}
+
+	if err := UndoMerge(ctx, repo, beforeC); err != nil {
+		t.Fatalf("UndoMerge() error = %v", err)
+	}
+	if head := runGit(t, repo, "rev-parse", "HEAD"); head != beforeC {
+		t.Fatalf("HEAD = %s after undo, want %s", head, beforeC)
+	}
+
+	if _, err := MergeBranch(ctx, repo, "feature-c"); !errors.Is(err, ErrMergeConflict) {
+		t.Fatal(err)
+	}
+	if err := AbortMerge(ctx, repo); err != nil {
+		t.Fatalf("AbortMerge() error = %v", err)
+	}
+	if status := runGit(t, repo, "status", "--porcelain"); status != "" || runGit(t, repo, "rev-parse", "HEAD") != beforeC {
+		t.Fatalf("abort left status %q, want the tree as before the merge", status)
+	}
+}
@@ -136,6 +136,11 @@ func GetCurrentBranch(path string) (string, error) {
 	return RunGitCtx(context.Background(), path, "rev-parse", "--abbrev-ref", "HEAD")
 }
 
+// GetHeadCommit returns the full hash of the commit HEAD points at.
+func GetHeadCommit(path string) (string, error) {
+	return RunGitCtx(context.Background(), path, "rev-parse", "HEAD")
+}
+
 // RunGitAllowFailureCtx executes git and returns stdout even if exit code is non-zero.
 // Use for commands like `git diff --no-index` which return 1 when differences exist.
 func RunGitAllowFailureCtx(ctx context.Context, dir string, args ...string) (string, error) {
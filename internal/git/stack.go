@@ -0,0 +1,131 @@
+package git
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// stackTimeout bounds one rebase or push of a stacked branch.
+const stackTimeout = 2 * time.Minute
+
+var (
+	// ErrRebaseConflict is returned when a rebase stops on conflicting files.
+	ErrRebaseConflict = errors.New("rebase has conflicts")
+	// ErrRebaseInProgress is returned when a worktree is already in the
+	// middle of a rebase.
+	ErrRebaseInProgress = errors.New("a rebase is in progress")
+)
+
+// StackedBranch is where the branch checked out in a worktree stands against
+// the branch it is stacked on.
+type StackedBranch struct {
+	// Subjects are the subjects of the branch's commits above its parent,
+	// oldest first.
+	Subjects []string
+	// Behind is set when the parent has moved on from where the branch is
+	// based, so the branch needs rebasing onto it.
+	Behind bool
+}
+
+// StackedBranchStatus reports how the branch checked out in root stands
+// against parent.
+func StackedBranchStatus(ctx context.Context, root, parent string) (StackedBranch, error) {
+	var status StackedBranch
+	out, err := RunGitCtx(ctx, root, "log", "--reverse", "--format=%s", parent+"..HEAD")
+	if err != nil {
+		return status, err
+	}
+	if out != "" {
+		status.Subjects = strings.Split(out, "\n")
+	}
+	base, err := RunGitCtx(ctx, root, "merge-base", parent, "HEAD")
+	if err != nil {
+		return status, err
+	}
+	tip, err := RunGitCtx(ctx, root, "rev-parse", parent)
+	if err != nil {
+		return status, err
+	}
+	status.Behind = base != tip
+	return status, nil
+}
+
+// ForkPoint returns the commit branch was based on in upstream. It comes
+// from upstream's reflog when that has it, so the commits of an upstream
+// since rewritten, such as by a rebase, are not taken to be branch's own;
+// otherwise it is their merge base.
+func ForkPoint(ctx context.Context, root, upstream, branch string) (string, error) {
+	if out, err := RunGitCtx(ctx, root, "merge-base", "--fork-point", upstream, branch); err == nil && out != "" {
+		return out, nil
+	}
+	return RunGitCtx(ctx, root, "merge-base", upstream, branch)
+}
+
+// RebaseOnto moves the commits of the branch checked out in root that are
+// above upstream onto onto. On conflicts the rebase is left in progress for
+// the user to finish, and the error wraps ErrRebaseConflict, with the
+// conflicting files listed.
+func RebaseOnto(ctx context.Context, root, onto, upstream string) ([]string, error) {
+	ctx, cancel := context.WithTimeout(ctx, stackTimeout)
+	defer cancel()
+	if RebaseInProgress(ctx, root) {
+		return nil, ErrRebaseInProgress
+	}
+	if _, err := RunGitCtx(ctx, root, "rebase", "--onto", onto, upstream); err != nil {
+		conflicts, listErr := UnmergedFiles(ctx, root)
+		if listErr != nil || len(conflicts) == 0 {
+			return nil, err
+		}
+		return conflicts, fmt.Errorf("rebasing onto %s: %w", onto, ErrRebaseConflict)
+	}
+	return nil, nil
+}
+
+// RebaseInProgress reports whether root is in the middle of a rebase.
+func RebaseInProgress(ctx context.Context, root string) bool {
+	for _, name := range []string{"rebase-merge", "rebase-apply"} {
+		path, err := RunGitCtx(ctx, root, "rev-parse", "--git-path", name)
+		if err != nil {
+			continue
+		}
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(root, path)
+		}
+		if _, err := os.Stat(path); err == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// PushBranch pushes branch from root to its remote: the one it tracks,
+// else origin, else the first remote. A rebased branch replaces the remote
+// one only if that is still where root last fetched it, and the remote
+// branch becomes branch's upstream.
+func PushBranch(ctx context.Context, root, branch string) error {
+	ctx, cancel := context.WithTimeout(ctx, stackTimeout)
+	defer cancel()
+	remote, err := RunGitCtx(ctx, root, "config", "--get", "branch."+branch+".remote")
+	if err != nil || remote == "" {
+		remotes, err := RunGitCtx(ctx, root, "remote")
+		if err != nil {
+			return err
+		}
+		names := strings.Fields(remotes)
+		switch {
+		case len(names) == 0:
+			return errors.New("the repository has no remote to push to")
+		case strings.Contains("\n"+remotes+"\n", "\norigin\n"):
+			remote = "origin"
+		default:
+			remote = names[0]
+		}
+	}
+	_, err = RunGitCtx(ctx, root, "push", "--force-with-lease", "--set-upstream", remote, branch)
+	return err
+}
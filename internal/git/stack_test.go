@@ -0,0 +1,96 @@
+package git
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestRestackBranch(t *testing.T) {
+	skipIfNoGit(t)
+	repo := initRepo(t)
+	commit := func(dir, file, content, subject string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(dir, file), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		runGit(t, dir, "add", ".")
+		runGit(t, dir, "commit", "-q", "-m", subject)
+	}
+	trunk := runGit(t, repo, "branch", "--show-current")
+	runGit(t, repo, "checkout", "-q", "-b", "parent")
+	commit(repo, "a.txt", "a\n", "add a")
+	child := filepath.Join(t.TempDir(), "child")
+	runGit(t, repo, "worktree", "add", "-q", "-b", "child", child, "parent")
+	commit(child, "b.txt", "b\n", "add b")
+
+	ctx := context.Background()
+	status, err := StackedBranchStatus(ctx, child, "parent")
+	if err != nil || status.Behind || !reflect.DeepEqual(status.Subjects, []string{"add b"}) {
+		t.Fatalf("StackedBranchStatus() = %+v, %v", status, err)
+	}
+
+	// Rewriting the parent leaves the child on its old commit.
+	commit(repo, "a.txt", "a, amended\n", "add a")
+	runGit(t, repo, "reset", "-q", "--soft", "HEAD~2")
+	runGit(t, repo, "commit", "-q", "-m", "add a")
+	if status, _ = StackedBranchStatus(ctx, child, "parent"); !status.Behind || len(status.Subjects) != 2 {
+		t.Fatalf("after rewriting the parent = %+v, want behind with its old commit", status)
+	}
+	upstream, err := ForkPoint(ctx, child, "parent", "child")
+	if err != nil {
+		t.Fatalf("ForkPoint() error = %v", err)
+	}
+	if _, err := RebaseOnto(ctx, child, "parent", upstream); err != nil {
+		t.Fatalf("RebaseOnto() error = %v", err)
+	}
+	status, err = StackedBranchStatus(ctx, child, "parent")
+	if err != nil || status.Behind || !reflect.DeepEqual(status.Subjects, []string{"add b"}) {
+		t.Fatalf("after restacking = %+v, %v; want just the child's commit", status, err)
+	}
+
+	// The trunk changing a file the child also changed conflicts.
+	runGit(t, repo, "checkout", "-q", trunk)
+	commit(repo, "b.txt", "trunk\n", "trunk b")
+	conflicts, err := RebaseOnto(ctx, child, trunk, "parent")
+	if !errors.Is(err, ErrRebaseConflict) || !reflect.DeepEqual(conflicts, []string{"b.txt"}) {
+		t.Fatalf("RebaseOnto(trunk) = %v, %v; want a b.txt conflict", conflicts, err)
+	}
+	if !RebaseInProgress(ctx, child) {
+		t.Fatal("the conflicted rebase should be left in progress")
+	}
+	if _, err := RebaseOnto(ctx, child, trunk, "parent"); !errors.Is(err, ErrRebaseInProgress) {
+		t.Fatalf("RebaseOnto() during a rebase = %v, want ErrRebaseInProgress", err)
+	}
+}
+
+func TestPushBranch(t *testing.T) {
+	skipIfNoGit(t)
+	repo := initRepo(t)
+	ctx := context.Background()
+	if err := PushBranch(ctx, repo, "main"); err == nil {
+		t.Fatal("PushBranch() without a remote should fail")
+	}
+	remote := filepath.Join(t.TempDir(), "remote.git")
+	runGit(t, repo, "init", "-q", "--bare", remote)
+	runGit(t, repo, "remote", "add", "upstream", remote)
+	branch := runGit(t, repo, "branch", "--show-current")
+	if err := PushBranch(ctx, repo, branch); err != nil {
+		t.Fatalf("PushBranch() error = %v", err)
+	}
+	if got := runGit(t, repo, "rev-parse", "--abbrev-ref", branch+"@{upstream}"); got != "upstream/"+branch {
+		t.Fatalf("upstream = %q", got)
+	}
+
+	// A rewritten branch replaces the one pushed.
+	runGit(t, repo, "commit", "-q", "--amend", "-m", "rewritten")
+	if err := PushBranch(ctx, repo, branch); err != nil {
+		t.Fatalf("PushBranch() after amending error = %v", err)
+	}
+	if got, want := runGit(t, remote, "rev-parse", branch), runGit(t, repo, "rev-parse", "HEAD"); got != want {
+		t.Fatalf("remote branch = %s, want %s", got, want)
+	}
+}
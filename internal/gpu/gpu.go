@@ -0,0 +1,160 @@
+// Package gpu samples NVIDIA GPUs with nvidia-smi: each device's utilization
+// and memory, and the compute processes running on it, so amux can show
+// which agents share a device and pin workspaces to separate ones.
+package gpu
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// VisibleDevicesEnvVar limits the devices CUDA programs see.
+const VisibleDevicesEnvVar = "CUDA_VISIBLE_DEVICES"
+
+// ErrUnavailable is returned when nvidia-smi is not installed.
+var ErrUnavailable = errors.New("GPU sampling needs nvidia-smi, which was not found")
+
+var (
+	lookPath = exec.LookPath
+	// run runs a command and returns its output; tests replace it.
+	run = func(ctx context.Context, name string, args ...string) (string, error) {
+		out, err := exec.CommandContext(ctx, name, args...).Output()
+		return string(out), err
+	}
+)
+
+// Device is one GPU as of a sample.
+type Device struct {
+	Index int
+	UUID  string
+	Name  string
+	// Utilization is the percentage of time a kernel was running.
+	Utilization int
+	MemUsedMiB  int
+	MemTotalMiB int
+	Processes   []Process
+}
+
+// Process is a compute process on a device.
+type Process struct {
+	PID        int
+	MemUsedMiB int
+}
+
+// Available reports whether nvidia-smi is installed.
+func Available() bool {
+	_, err := lookPath("nvidia-smi")
+	return err == nil
+}
+
+// Sample returns every device with the compute processes running on it.
+func Sample(ctx context.Context) ([]Device, error) {
+	if !Available() {
+		return nil, ErrUnavailable
+	}
+	out, err := run(ctx, "nvidia-smi", "--query-gpu=index,uuid,name,utilization.gpu,memory.used,memory.total", "--format=csv,noheader,nounits")
+	if err != nil {
+		return nil, fmt.Errorf("nvidia-smi: %w", err)
+	}
+	devices, err := parseDevices(out)
+	if err != nil {
+		return nil, err
+	}
+	out, err = run(ctx, "nvidia-smi", "--query-compute-apps=gpu_uuid,pid,used_memory", "--format=csv,noheader,nounits")
+	if err != nil {
+		return nil, fmt.Errorf("nvidia-smi: %w", err)
+	}
+	byUUID := make(map[string]*Device, len(devices))
+	for i := range devices {
+		byUUID[devices[i].UUID] = &devices[i]
+	}
+	for _, fields := range csvRows(out, 3) {
+		d := byUUID[fields[0]]
+		pid, err := strconv.Atoi(fields[1])
+		if d == nil || err != nil {
+			continue
+		}
+		d.Processes = append(d.Processes, Process{PID: pid, MemUsedMiB: number(fields[2])})
+	}
+	return devices, nil
+}
+
+// parseDevices parses nvidia-smi's --query-gpu CSV.
+func parseDevices(out string) ([]Device, error) {
+	var devices []Device
+	for _, fields := range csvRows(out, 6) {
+		index, err := strconv.Atoi(fields[0])
+		if err != nil {
+			return nil, fmt.Errorf("nvidia-smi: unexpected device index %q", fields[0])
+		}
+		devices = append(devices, Device{
+			Index:       index,
+			UUID:        fields[1],
+			Name:        fields[2],
+			Utilization: number(fields[3]),
+			MemUsedMiB:  number(fields[4]),
+			MemTotalMiB: number(fields[5]),
+		})
+	}
+	return devices, nil
+}
+
+// csvRows splits nvidia-smi's CSV output into rows of n trimmed fields,
+// skipping rows of another width.
+func csvRows(out string, n int) [][]string {
+	var rows [][]string
+	for _, line := range strings.Split(out, "\n") {
+		fields := strings.Split(line, ",")
+		if len(fields) != n {
+			continue
+		}
+		for i := range fields {
+			fields[i] = strings.TrimSpace(fields[i])
+		}
+		rows = append(rows, fields)
+	}
+	return rows
+}
+
+// number parses a numeric field, reading "[N/A]" and the like as 0.
+func number(field string) int {
+	n, _ := strconv.Atoi(field)
+	return n
+}
+
+// Owner returns the root in roots that pid descends from, following parents,
+// or 0 when it descends from none of them.
+func Owner(pid int, parents map[int]int, roots map[int]bool) int {
+	for seen := 0; pid > 1 && seen < 64; seen++ {
+		if roots[pid] {
+			return pid
+		}
+		pid = parents[pid]
+	}
+	return 0
+}
+
+// Parents returns each process's parent, from ps.
+func Parents(ctx context.Context) (map[int]int, error) {
+	out, err := run(ctx, "ps", "-A", "-o", "pid=,ppid=")
+	if err != nil {
+		return nil, fmt.Errorf("ps: %w", err)
+	}
+	parents := make(map[int]int)
+	for _, line := range strings.Split(out, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			continue
+		}
+		pid, err1 := strconv.Atoi(fields[0])
+		ppid, err2 := strconv.Atoi(fields[1])
+		if err1 == nil && err2 == nil {
+			parents[pid] = ppid
+		}
+	}
+	return parents, nil
+}
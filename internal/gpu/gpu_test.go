@@ -0,0 +1,54 @@
+package gpu
+
+import (
+	"context"
+	"os/exec"
+	"strings"
+	"testing"
+)
+
+func TestSample(t *testing.T) {
+	oldLookPath, oldRun := lookPath, run
+	t.Cleanup(func() { lookPath, run = oldLookPath, oldRun })
+	lookPath = func(string) (string, error) { return "/usr/bin/nvidia-smi", nil }
+	run = func(_ context.Context, _ string, args ...string) (string, error) {
+		if strings.HasPrefix(args[0], "--query-gpu") {
+			return "0, GPU-aaa, NVIDIA A100-SXM4-80GB, 87, 30720, 81920\n1, GPU-bbb, NVIDIA A100-SXM4-80GB, [N/A], 0, 81920\n", nil
+		}
+		return "GPU-aaa, 4242, 30000\nGPU-zzz, 1, 5\n", nil
+	}
+
+	devices, err := Sample(context.Background())
+	if err != nil {
+		t.Fatalf("Sample() error = %v", err)
+	}
+	if len(devices) != 2 {
+		t.Fatalf("Sample() = %+v, want 2 devices", devices)
+	}
+	d := devices[0]
+	if d.Index != 0 || d.Name != "NVIDIA A100-SXM4-80GB" || d.Utilization != 87 || d.MemUsedMiB != 30720 || d.MemTotalMiB != 81920 {
+		t.Fatalf("device 0 = %+v", d)
+	}
+	if len(d.Processes) != 1 || d.Processes[0] != (Process{PID: 4242, MemUsedMiB: 30000}) {
+		t.Fatalf("device 0 processes = %+v", d.Processes)
+	}
+	if devices[1].Utilization != 0 || len(devices[1].Processes) != 0 {
+		t.Fatalf("device 1 = %+v", devices[1])
+	}
+
+	lookPath = func(string) (string, error) { return "", exec.ErrNotFound }
+	if _, err := Sample(context.Background()); err != ErrUnavailable {
+		t.Fatalf("Sample() without nvidia-smi = %v", err)
+	}
+}
+
+func TestOwner(t *testing.T) {
+	parents := map[int]int{400: 300, 300: 200, 200: 1, 500: 1}
+	roots := map[int]bool{200: true}
+	if got := Owner(400, parents, roots); got != 200 {
+		t.Fatalf("Owner(400) = %d, want 200", got)
+	}
+	if got := Owner(500, parents, roots); got != 0 {
+		t.Fatalf("Owner(500) = %d, want 0", got)
+	}
+}
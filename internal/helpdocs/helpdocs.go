@@ -0,0 +1,151 @@
+// Package helpdocs answers questions about using amux from its built-in
+// docs: a set of topics written for the questions people ask, such as why a
+// tab froze, plus whatever the caller adds, such as the prefix key table.
+// Search ranks them by the words a question shares with each.
+package helpdocs
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// Topic is one entry of the docs. Action and Command are what the answer
+// points to, so it can be carried out from where it was found.
+type Topic struct {
+	Title string
+	// Keywords are words a question about the topic is likely to use, such
+	// as "frozen" for a tab that stopped updating.
+	Keywords []string
+	Answer   string
+	// Keys is the key sequence after the leader that runs Action.
+	Keys   string
+	Action string
+	// Command is a shell command to run, e.g. "amux doctor".
+	Command string
+}
+
+// Label is the topic's title with its keys or command, as one line.
+func (t Topic) Label() string {
+	switch {
+	case t.Keys != "":
+		return fmt.Sprintf("%s  (prefix %s)", t.Title, t.Keys)
+	case t.Command != "":
+		return fmt.Sprintf("%s  ($ %s)", t.Title, t.Command)
+	}
+	return t.Title
+}
+
+// Weights of a question word found in a topic's keywords, title, and answer;
+// a word found in several adds up.
+const (
+	keywordWeight = 3
+	titleWeight   = 2
+	answerWeight  = 1
+)
+
+// stopWords are left out of questions since every topic could match them.
+var stopWords = map[string]bool{
+	"a": true, "an": true, "and": true, "are": true, "can": true, "do": true, "does": true,
+	"for": true, "how": true, "i": true, "in": true, "is": true, "it": true, "me": true,
+	"my": true, "of": true, "on": true, "or": true, "the": true, "to": true, "what": true,
+	"when": true, "where": true, "which": true, "why": true, "with": true, "amux": true,
+}
+
+// Search returns up to limit topics sharing words with question, best match
+// first. Ties keep the order topics were given in.
+func Search(question string, topics []Topic, limit int) []Topic {
+	terms := words(question)
+	type scored struct {
+		topic Topic
+		score int
+	}
+	var found []scored
+	for _, t := range topics {
+		if score := score(terms, t); score > 0 {
+			found = append(found, scored{t, score})
+		}
+	}
+	sort.SliceStable(found, func(i, j int) bool { return found[i].score > found[j].score })
+	out := make([]Topic, 0, min(limit, len(found)))
+	for _, s := range found[:min(limit, len(found))] {
+		out = append(out, s.topic)
+	}
+	return out
+}
+
+func score(terms []string, t Topic) int {
+	keywords := set(words(strings.Join(t.Keywords, " ")))
+	title := set(words(t.Title))
+	answer := set(words(t.Answer))
+	total := 0
+	for _, term := range terms {
+		if keywords[term] {
+			total += keywordWeight
+		}
+		if title[term] {
+			total += titleWeight
+		}
+		if answer[term] {
+			total += answerWeight
+		}
+	}
+	return total
+}
+
+// words splits text into lowercase stems, without stop words.
+func words(text string) []string {
+	fields := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	out := fields[:0]
+	for _, f := range fields {
+		if !stopWords[f] {
+			out = append(out, stem(f))
+		}
+	}
+	return out
+}
+
+// stem drops common English endings, so "tabs" matches "tab" and "closed"
+// matches "close".
+func stem(w string) string {
+	for _, suffix := range []string{"ing", "en", "ed", "es", "e", "s"} {
+		if len(w) > len(suffix)+2 && strings.HasSuffix(w, suffix) {
+			return strings.TrimSuffix(w, suffix)
+		}
+	}
+	return w
+}
+
+func set(ws []string) map[string]bool {
+	m := make(map[string]bool, len(ws))
+	for _, w := range ws {
+		m[w] = true
+	}
+	return m
+}
+
+// Reference writes topics out as the context a language model answers from.
+func Reference(topics []Topic) string {
+	var b strings.Builder
+	for _, t := range topics {
+		fmt.Fprintf(&b, "- %s", t.Label())
+		if t.Answer != "" {
+			b.WriteString(": " + t.Answer)
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// ModelInstructions asks a language model to answer from the reference
+// only, naming the keys or command to use. leader is the leader key's label,
+// e.g. "C-Space".
+func ModelInstructions(leader string) string {
+	return "You answer questions about amux, a terminal UI for running coding agents in git worktrees. " +
+		"Its commands are reached by pressing the leader key, " + leader + ", and then the keys listed as \"prefix\". " +
+		"Answer from the reference you are given only, in at most three sentences, naming the keys or command to use. " +
+		"If the reference doesn't cover the question, say so."
+}
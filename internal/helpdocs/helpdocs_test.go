@@ -0,0 +1,41 @@
+package helpdocs
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSearch(t *testing.T) {
+	for question, want := range map[string]string{
+		"how do I rebind the leader":  "Leader key",
+		"why is my tab frozen?":       "A tab looks frozen or garbled",
+		"Where are the logs":          "Read amux's logs",
+		"run several agents at once?": "Give several agents the same task",
+	} {
+		got := Search(question, Topics, 3)
+		if len(got) == 0 || got[0].Title != want {
+			t.Errorf("Search(%q) = %v, want %q first", question, got, want)
+		}
+	}
+	if got := Search("xyzzy plugh", Topics, 3); len(got) != 0 {
+		t.Errorf("Search() for nonsense = %v, want nothing", got)
+	}
+	keys := []Topic{{Title: "redraw tab", Keys: "t w"}, {Title: "next tab", Keys: "t n"}}
+	if got := Search("tabs", keys, 1); len(got) != 1 || got[0].Keys != "t w" {
+		t.Errorf("Search() = %v, want the first of equal matches, limited to one", got)
+	}
+}
+
+func TestReference(t *testing.T) {
+	got := Reference([]Topic{
+		{Title: "redraw tab", Keys: "t w"},
+		{Title: "Check the environment", Command: "amux doctor", Answer: "Checks tmux."},
+	})
+	want := "- redraw tab  (prefix t w)\n- Check the environment  ($ amux doctor): Checks tmux.\n"
+	if got != want {
+		t.Fatalf("Reference() = %q, want %q", got, want)
+	}
+	if !strings.Contains(ModelInstructions(`C-\`), `C-\`) {
+		t.Fatal("the instructions should name the leader in use")
+	}
+}
@@ -0,0 +1,165 @@
+package helpdocs
+
+// Topics are the built-in docs, written for the questions people ask rather
+// than as a list of keys; the prefix key table covers those.
+var Topics = []Topic{
+	{
+		Title:    "Leader key",
+		Keywords: []string{"leader", "prefix", "rebind", "remap", "change", "ctrl", "space", "conflict", "nested", "tmux"},
+		Answer: "The leader is C-Space; it isn't configurable. When amux runs inside another amux, or inside tmux with C-Space as its " +
+			"prefix, it moves the leader to C-\\ by itself and says so when it opens. Press the leader twice to send it to the " +
+			"focused terminal, or prefix t k to send a C-Space through.",
+		Keys:   "t k",
+		Action: "send_outer_prefix",
+	},
+	{
+		Title:    "A tab looks frozen or garbled",
+		Keywords: []string{"frozen", "freeze", "stuck", "hung", "hang", "garbled", "broken", "blank", "unresponsive", "redraw"},
+		Answer: "prefix t w redraws the tab, which fixes most garbled screens; prefix t R resets the terminal's modes. If keys do " +
+			"nothing, check that input isn't locked to another pane (prefix L). prefix t s restarts the tab's process.",
+		Keys:   "t w",
+		Action: "redraw_tab",
+	},
+	{
+		Title:    "Restart a tab's process",
+		Keywords: []string{"restart", "crashed", "dead", "exited", "relaunch", "rerun"},
+		Answer:   "prefix t s restarts the tab's process. An agent that exited keeps its final output; press r in its tab to relaunch it.",
+		Keys:     "t s",
+		Action:   "restart_tab",
+	},
+	{
+		Title:    "Keys go to the wrong pane",
+		Keywords: []string{"lock", "locked", "unlock", "keys", "typing", "focus", "input", "wrong"},
+		Answer:   "prefix L locks input to the focused terminal so stray keys can't move focus; press it again to unlock. prefix h and prefix l move focus left and right.",
+		Keys:     "L",
+		Action:   "toggle_input_lock",
+	},
+	{
+		Title:    "Scroll back through output",
+		Keywords: []string{"scroll", "scrollback", "history", "output", "up", "page", "copy"},
+		Answer:   "With a center terminal focused, prefix u and prefix d page up and down its scrollback. prefix / searches it, and prefix Y copies what it printed since Enter was last pressed.",
+		Keys:     "/",
+		Action:   "search_terminal",
+	},
+	{
+		Title:    "Start an agent",
+		Keywords: []string{"agent", "start", "launch", "new", "claude", "codex", "gemini", "assistant"},
+		Answer:   "prefix t a opens a new agent tab in the workspace; agents are configured in ~/.amux/config.json. prefix t t opens a terminal tab instead.",
+		Keys:     "t a",
+		Action:   "new_agent_tab",
+	},
+	{
+		Title:    "Create a workspace",
+		Keywords: []string{"workspace", "worktree", "branch", "create", "new", "project", "add"},
+		Answer:   "Each workspace is a git worktree on its own branch. prefix a adds a project; then press enter on the project's \"New\" row in the dashboard to create one.",
+		Keys:     "a",
+		Action:   "add_project",
+	},
+	{
+		Title:    "Commit an agent's changes",
+		Keywords: []string{"commit", "message", "changes", "save", "stage", "conventional"},
+		Answer:   "Press c in the sidebar to commit everything in the workspace, with a drafted message to edit. prefix c asks the agent to draft a better one first.",
+		Keys:     "c",
+		Action:   "draft_commit_message",
+	},
+	{
+		Title:    "Review an agent's changes",
+		Keywords: []string{"review", "diff", "hunk", "changes", "accept", "reject"},
+		Answer:   "prefix R steps through the worktree's unstaged changes hunk by hunk to accept, reject, or send back to the agent; prefix D compares two worktrees.",
+		Keys:     "R",
+		Action:   "review_hunks",
+	},
+	{
+		Title:    "Run the tests",
+		Keywords: []string{"test", "tests", "failing", "coverage", "run"},
+		Answer:   "prefix T runs the project's test command in the worktree and lists the failures; pick one to open it, or send them all to the agent. Set \"test\" in .amux/workspaces.json to choose the command.",
+		Keys:     "T",
+		Action:   "show_tests",
+	},
+	{
+		Title:    "Run build and lint checks",
+		Keywords: []string{"check", "checks", "lint", "build", "typecheck"},
+		Answer:   "prefix C runs the \"checks\" listed in .amux/workspaces.json in parallel and shows which passed.",
+		Keys:     "C",
+		Action:   "show_checks",
+	},
+	{
+		Title:    "Give several agents the same task",
+		Keywords: []string{"parallel", "several", "multiple", "many", "fan", "compare", "benchmark", "same"},
+		Answer:   "prefix F fans a task out to several agents, each in its own workspace; prefix B benchmarks agents on a task with an acceptance command.",
+		Keys:     "F",
+		Action:   "fan_out",
+	},
+	{
+		Title:    "Find an agent that needs attention",
+		Keywords: []string{"attention", "waiting", "idle", "done", "finished", "notify", "notification"},
+		Answer:   "prefix ! jumps to the next agent needing attention, and prefix ] and prefix [ cycle through agents in every workspace. Notifications are set up in ~/.amux/config.json.",
+		Keys:     "!",
+		Action:   "attention_agent",
+	},
+	{
+		Title:    "See what happened while away",
+		Keywords: []string{"activity", "feed", "away", "happened", "log", "events"},
+		Answer:   "prefix A lists what happened across all worktrees, newest first.",
+		Keys:     "A",
+		Action:   "activity_feed",
+	},
+	{
+		Title:    "Get a tab back after detaching it",
+		Keywords: []string{"detach", "detached", "reattach", "lost", "gone", "missing", "closed", "undo"},
+		Answer:   "prefix t r reattaches a detached tab; its tmux session keeps running meanwhile. prefix t u undoes closing a tab.",
+		Keys:     "t r",
+		Action:   "reattach_tab",
+	},
+	{
+		Title:    "Change the theme and settings",
+		Keywords: []string{"theme", "color", "colors", "settings", "preferences", "config", "configure"},
+		Answer:   "prefix S opens Settings. Everything else is in ~/.amux/config.json; see docs/CONFIG.md.",
+		Keys:     "S",
+		Action:   "open_settings",
+	},
+	{
+		Title:    "Clean up tmux sessions",
+		Keywords: []string{"tmux", "sessions", "cleanup", "orphan", "leftover", "kill"},
+		Answer:   "prefix K cleans up tmux sessions amux no longer uses. amux session ls lists them from a shell.",
+		Keys:     "K",
+		Action:   "cleanup_tmux",
+	},
+	{
+		Title:    "Check the environment",
+		Keywords: []string{"doctor", "install", "installed", "missing", "tmux", "git", "broken", "setup", "diagnose"},
+		Answer:   "amux doctor checks that tmux and git are installed, warns about nesting, and reports pinned runtime versions that aren't installed.",
+		Command:  "amux doctor",
+	},
+	{
+		Title:    "Read amux's logs",
+		Keywords: []string{"logs", "log", "error", "errors", "debug", "bug", "crash"},
+		Answer:   "amux logs prints the latest log; amux logs --audit shows the audit log. Set AMUX_LOG_LEVEL=debug for more detail.",
+		Command:  "amux logs",
+	},
+	{
+		Title:    "Sync settings across machines",
+		Keywords: []string{"sync", "machines", "laptop", "dropbox", "share", "settings"},
+		Answer:   "Make ~/.amux/sync a clone of a private git repository or a link to a synced folder; amux syncs when it starts and amux sync syncs on demand.",
+		Command:  "amux sync",
+	},
+	{
+		Title:    "Share a session",
+		Keywords: []string{"share", "pair", "viewer", "watch", "remote", "browser"},
+		Answer:   "amux share <session> serves a read-only view of a tmux session over HTTP, or HTTPS with --cert and --key; --write lets approved viewers type.",
+		Command:  "amux share",
+	},
+	{
+		Title:    "Language model usage",
+		Keywords: []string{"llm", "model", "tokens", "usage", "openai", "anthropic", "ollama", "cost"},
+		Answer:   "Configure the \"llm\" section of ~/.amux/config.json and enable the features that may use it. amux llm prints the model and the tokens each feature used.",
+		Command:  "amux llm",
+	},
+	{
+		Title:    "Quit amux",
+		Keywords: []string{"quit", "exit", "close", "leave", "stop"},
+		Answer:   "prefix q quits. Agents keep running in tmux and are there when amux starts again.",
+		Keys:     "q",
+		Action:   "quit",
+	},
+}
@@ -0,0 +1,191 @@
+// Package inputhistory records the prompts typed into agent tabs so they can
+// be recalled later, in the same tab or another one. It rebuilds each line
+// from the bytes sent to the terminal, since agents keep their own input
+// state.
+package inputhistory
+
+import (
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	// MaxEntries bounds the history across all tabs; the oldest entries go
+	// first.
+	MaxEntries = 500
+	// maxLineBytes bounds one line, so a runaway paste doesn't grow it
+	// without limit.
+	maxLineBytes = 64 * 1024
+)
+
+const (
+	pasteStart = "\x1b[200~"
+	pasteEnd   = "\x1b[201~"
+)
+
+// Line rebuilds the line being typed from the input sent to a terminal.
+// Cursor movement and history keys make the agent's line differ from what
+// was typed, so a line edited that way is dropped rather than recorded
+// wrong. The zero value is ready to use.
+type Line struct {
+	buf    strings.Builder
+	edited bool
+}
+
+// Feed applies input sent to the terminal and returns each line it
+// submitted.
+func (l *Line) Feed(data string) []string {
+	var submitted []string
+	for len(data) > 0 {
+		switch {
+		case strings.HasPrefix(data, pasteStart):
+			content, rest, ok := strings.Cut(data[len(pasteStart):], pasteEnd)
+			if !ok {
+				rest = ""
+			}
+			content = strings.ReplaceAll(content, "\r\n", "\n")
+			content = strings.ReplaceAll(content, "\r", "\n")
+			if trimmed := strings.TrimRight(content, "\n"); trimmed != content {
+				// A paste ending in a newline submits, as typed Enter would.
+				l.write(trimmed)
+				submitted = l.submit(submitted)
+			} else {
+				l.write(content)
+			}
+			data = rest
+		case strings.HasPrefix(data, "\x1b\r"), strings.HasPrefix(data, "\x1b\n"):
+			// Alt/Shift-Enter inserts a newline in most agents.
+			l.write("\n")
+			data = data[2:]
+		case data == "\x1b":
+			// A lone Escape interrupts the agent; the line is kept.
+			data = ""
+		case data[0] == '\x1b':
+			// Arrow keys, history, and other escape sequences.
+			l.edited = true
+			data = data[escapeLen(data):]
+		case data[0] == '\r' || data[0] == '\n':
+			submitted = l.submit(submitted)
+			data = data[1:]
+		case data[0] == '\x7f' || data[0] == '\b':
+			l.backspace()
+			data = data[1:]
+		case data[0] == '\x03' || data[0] == '\x15':
+			// Ctrl-C and Ctrl-U discard the line.
+			l.reset()
+			data = data[1:]
+		case data[0] < 0x20:
+			// Other control keys (completion, kill, transpose) edit the line
+			// in ways it can't follow.
+			if data[0] != '\t' {
+				l.edited = true
+			}
+			data = data[1:]
+		default:
+			_, size := utf8.DecodeRuneInString(data)
+			l.write(data[:size])
+			data = data[size:]
+		}
+	}
+	return submitted
+}
+
+func (l *Line) write(s string) {
+	if l.buf.Len()+len(s) > maxLineBytes {
+		l.edited = true
+		return
+	}
+	l.buf.WriteString(s)
+}
+
+func (l *Line) backspace() {
+	s := l.buf.String()
+	if s == "" {
+		return
+	}
+	_, size := utf8.DecodeLastRuneInString(s)
+	l.buf.Reset()
+	l.buf.WriteString(s[:len(s)-size])
+}
+
+func (l *Line) submit(submitted []string) []string {
+	text := strings.TrimSpace(l.buf.String())
+	if text != "" && !l.edited {
+		submitted = append(submitted, text)
+	}
+	l.reset()
+	return submitted
+}
+
+func (l *Line) reset() {
+	l.buf.Reset()
+	l.edited = false
+}
+
+// escapeLen returns the length of the escape sequence data starts with:
+// CSI and SS3 sequences through their final byte, otherwise ESC and the
+// byte after it.
+func escapeLen(data string) int {
+	if len(data) < 2 {
+		return len(data)
+	}
+	switch data[1] {
+	case '[':
+		for i := 2; i < len(data); i++ {
+			if data[i] >= 0x40 && data[i] <= 0x7e {
+				return i + 1
+			}
+		}
+		return len(data)
+	case 'O':
+		return min(3, len(data))
+	}
+	return 2
+}
+
+// Entry is one recorded prompt.
+type Entry struct {
+	Text      string
+	Workspace string
+	Tab       string
+	At        time.Time
+}
+
+// Store keeps recorded prompts across tabs, oldest first. It is safe for
+// concurrent use.
+type Store struct {
+	mu      sync.Mutex
+	entries []Entry
+}
+
+// Add records e, unless it repeats the newest entry from the same tab.
+func (s *Store) Add(e Entry) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i := len(s.entries) - 1; i >= 0; i-- {
+		prev := s.entries[i]
+		if prev.Workspace == e.Workspace && prev.Tab == e.Tab {
+			if prev.Text == e.Text {
+				return
+			}
+			break
+		}
+	}
+	s.entries = append(s.entries, e)
+	if over := len(s.entries) - MaxEntries; over > 0 {
+		s.entries = append(s.entries[:0], s.entries[over:]...)
+	}
+}
+
+// Entries returns the recorded prompts, newest first.
+func (s *Store) Entries() []Entry {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := make([]Entry, len(s.entries))
+	for i, e := range s.entries {
+		out[len(s.entries)-1-i] = e
+	}
+	return out
+}
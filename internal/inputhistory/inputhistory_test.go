@@ -0,0 +1,63 @@
+package inputhistory
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestLineFeed(t *testing.T) {
+	tests := []struct {
+		name   string
+		inputs []string
+		want   []string
+	}{
+		{name: "typed keys", inputs: []string{"f", "i", "x", "\r"}, want: []string{"fix"}},
+		{name: "backspace", inputs: []string{"fox", "\x7f", "\x7f", "ix", "\r"}, want: []string{"fix"}},
+		{name: "ctrl-u discards", inputs: []string{"oops", "\x15", "go", "\r"}, want: []string{"go"}},
+		{name: "blank line", inputs: []string{"  ", "\r"}, want: nil},
+		{name: "paste then enter", inputs: []string{"\x1b[200~a\r\nb\x1b[201~", "\r"}, want: []string{"a\nb"}},
+		{name: "paste ending in newline submits", inputs: []string{"\x1b[200~run it\n\x1b[201~"}, want: []string{"run it"}},
+		{name: "shift-enter newline", inputs: []string{"one", "\x1b\r", "two", "\r"}, want: []string{"one\ntwo"}},
+		{name: "cursor movement drops the line", inputs: []string{"ab", "\x1b[D", "c", "\r", "next", "\r"}, want: []string{"next"}},
+		{name: "history key drops the line", inputs: []string{"\x1b[A", "\r"}, want: nil},
+		{name: "lone escape keeps the line", inputs: []string{"keep", "\x1b", "\r"}, want: []string{"keep"}},
+		{name: "multibyte backspace", inputs: []string{"héé", "\x7f", "\r"}, want: []string{"hé"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var l Line
+			var got []string
+			for _, in := range tt.inputs {
+				got = append(got, l.Feed(in)...)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("submitted %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStore(t *testing.T) {
+	var s Store
+	s.Add(Entry{Text: "a", Workspace: "w", Tab: "claude"})
+	s.Add(Entry{Text: "a", Workspace: "w", Tab: "claude"})
+	s.Add(Entry{Text: "a", Workspace: "w", Tab: "codex"})
+	s.Add(Entry{Text: "b", Workspace: "w", Tab: "claude"})
+
+	var texts []string
+	for _, e := range s.Entries() {
+		texts = append(texts, e.Tab+":"+e.Text)
+	}
+	if want := []string{"claude:b", "codex:a", "claude:a"}; !reflect.DeepEqual(texts, want) {
+		t.Fatalf("entries = %q, want %q (newest first, repeats in one tab dropped)", texts, want)
+	}
+
+	for i := range MaxEntries + 10 {
+		s.Add(Entry{Text: fmt.Sprint(i), Tab: "t"})
+	}
+	entries := s.Entries()
+	if len(entries) != MaxEntries || entries[0].Text != fmt.Sprint(MaxEntries+9) {
+		t.Fatalf("got %d entries, newest %q; want %d, newest the last added", len(entries), entries[0].Text, MaxEntries)
+	}
+}
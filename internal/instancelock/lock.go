@@ -0,0 +1,87 @@
+// Package instancelock keeps one amux TUI in charge of a state directory. The
+// holder writes its PID into the lock file so another TUI can say who has it
+// and ask it to quit.
+package instancelock
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"time"
+)
+
+// ErrHeld is returned by Acquire when another process holds the lock.
+var ErrHeld = errors.New("another amux holds the instance lock")
+
+// Holder describes the process holding the lock.
+type Holder struct {
+	PID       int       `json:"pid"`
+	StartedAt time.Time `json:"started_at"`
+}
+
+// Lock is a held instance lock.
+type Lock struct {
+	file *os.File
+}
+
+// Acquire takes the lock at path without waiting. When another process holds
+// it, Acquire returns ErrHeld along with what that process recorded.
+func Acquire(path string) (*Lock, Holder, error) {
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0o600)
+	if err != nil {
+		return nil, Holder{}, err
+	}
+	held, err := tryLock(file)
+	if err != nil {
+		_ = file.Close()
+		return nil, Holder{}, err
+	}
+	if held {
+		holder := readHolder(file)
+		_ = file.Close()
+		return nil, holder, ErrHeld
+	}
+	self := Holder{PID: os.Getpid(), StartedAt: time.Now().UTC()}
+	data, _ := json.Marshal(self)
+	if err := file.Truncate(0); err == nil {
+		_, _ = file.WriteAt(data, 0)
+	}
+	return &Lock{file: file}, self, nil
+}
+
+// TakeOver asks the holder to quit and waits up to timeout for the lock.
+func TakeOver(path string, holder Holder, timeout time.Duration) (*Lock, error) {
+	if holder.PID <= 0 || holder.PID == os.Getpid() {
+		return nil, errors.New("the instance lock holder is unknown")
+	}
+	if err := signalQuit(holder.PID); err != nil {
+		return nil, err
+	}
+	deadline := time.Now().Add(timeout)
+	for {
+		lock, _, err := Acquire(path)
+		if !errors.Is(err, ErrHeld) || time.Now().After(deadline) {
+			return lock, err
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+}
+
+// Release drops the lock.
+func (l *Lock) Release() {
+	if l == nil || l.file == nil {
+		return
+	}
+	_ = l.file.Truncate(0)
+	unlock(l.file)
+	_ = l.file.Close()
+	l.file = nil
+}
+
+func readHolder(file *os.File) Holder {
+	var holder Holder
+	buf := make([]byte, 512)
+	n, _ := file.ReadAt(buf, 0)
+	_ = json.Unmarshal(buf[:n], &holder)
+	return holder
+}
@@ -0,0 +1,41 @@
+//go:build !windows
+
+package instancelock
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestAcquire(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "instance.lock")
+	lock, self, err := Acquire(path)
+	if err != nil || self.PID != os.Getpid() {
+		t.Fatalf("Acquire() = %+v, %v", self, err)
+	}
+
+	// flock conflicts across open files, even within one process.
+	_, holder, err := Acquire(path)
+	if !errors.Is(err, ErrHeld) || holder.PID != os.Getpid() || !holder.StartedAt.Equal(self.StartedAt) {
+		t.Fatalf("second Acquire() = %+v, %v; want ErrHeld naming the holder", holder, err)
+	}
+
+	lock.Release()
+	again, _, err := Acquire(path)
+	if err != nil {
+		t.Fatalf("Acquire() after Release = %v", err)
+	}
+	again.Release()
+}
+
+func TestTakeOverRefusesUnknownHolder(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "instance.lock")
+	if _, err := TakeOver(path, Holder{}, 0); err == nil {
+		t.Fatal("expected an error without a holder PID")
+	}
+	if _, err := TakeOver(path, Holder{PID: os.Getpid()}, 0); err == nil {
+		t.Fatal("expected an error taking over from this process")
+	}
+}
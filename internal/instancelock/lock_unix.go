@@ -0,0 +1,28 @@
+//go:build !windows
+
+package instancelock
+
+import (
+	"errors"
+	"os"
+	"syscall"
+)
+
+// tryLock takes an exclusive flock on file, reporting held when another
+// process has it.
+func tryLock(file *os.File) (held bool, err error) {
+	err = syscall.Flock(int(file.Fd()), syscall.LOCK_EX|syscall.LOCK_NB)
+	if errors.Is(err, syscall.EWOULDBLOCK) {
+		return true, nil
+	}
+	return false, err
+}
+
+func unlock(file *os.File) {
+	_ = syscall.Flock(int(file.Fd()), syscall.LOCK_UN)
+}
+
+// signalQuit asks the amux with pid to save its tabs and quit.
+func signalQuit(pid int) error {
+	return syscall.Kill(pid, syscall.SIGTERM)
+}
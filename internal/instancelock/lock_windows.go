@@ -0,0 +1,17 @@
+//go:build windows
+
+package instancelock
+
+import (
+	"errors"
+	"os"
+)
+
+// Windows builds do not run the TUI, so the lock is never contended.
+func tryLock(*os.File) (bool, error) { return false, nil }
+
+func unlock(*os.File) {}
+
+func signalQuit(int) error {
+	return errors.New("taking over another amux is not supported on Windows")
+}
@@ -0,0 +1,121 @@
+// Package limits caps the CPU and memory an agent's process tree may use and
+// watches how close it gets.
+//
+// Caps are applied by a wrapper run in front of the agent: a transient
+// systemd scope (cgroup v2 MemoryMax and CPUQuota) on Linux when a user
+// systemd instance is running, and otherwise an address-space rlimit set with
+// ulimit. Niceness is applied with nice(1) either way. Usage is sampled with
+// ps(1) so breaches can be reported even where a limit cannot be enforced.
+package limits
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+
+	"github.com/andyrewlee/amux/internal/shellutil"
+)
+
+// Limits caps one agent's process tree. Zero fields are unlimited.
+type Limits struct {
+	// MemoryMB is the most memory the tree may use, in MiB.
+	MemoryMB int `json:"memory_mb,omitempty"`
+	// CPUPercent is the CPU time the tree may use, as a percentage of one
+	// core (200 is two full cores).
+	CPUPercent int `json:"cpu_percent,omitempty"`
+	// Nice is added to the agent's scheduling niceness (0-19).
+	Nice int `json:"nice,omitempty"`
+}
+
+// Normalize drops values that cannot be applied: negative caps, and
+// niceness outside 0-19 (raising priority needs privileges).
+func (l Limits) Normalize() Limits {
+	l.MemoryMB = max(l.MemoryMB, 0)
+	l.CPUPercent = max(l.CPUPercent, 0)
+	l.Nice = min(max(l.Nice, 0), 19)
+	return l
+}
+
+// IsZero reports whether l sets no limit.
+func (l Limits) IsZero() bool {
+	return l == Limits{}
+}
+
+// Kind names how caps are enforced.
+type Kind string
+
+const (
+	// Rlimit sets an address-space limit with ulimit; CPU caps are only
+	// monitored.
+	Rlimit Kind = "rlimit"
+	// Systemd runs the agent in a transient user scope with cgroup limits.
+	Systemd Kind = "systemd"
+)
+
+var (
+	goos       = runtime.GOOS
+	lookPath   = exec.LookPath
+	runtimeDir = func() string { return os.Getenv("XDG_RUNTIME_DIR") }
+)
+
+// Detect returns the strongest enforcement available on this machine.
+func Detect() Kind {
+	if goos != "linux" {
+		return Rlimit
+	}
+	if _, err := lookPath("systemd-run"); err != nil {
+		return Rlimit
+	}
+	// systemd-run --user needs the user manager's bus; without it every
+	// launch would fail.
+	dir := runtimeDir()
+	if dir == "" {
+		return Rlimit
+	}
+	if _, err := os.Stat(filepath.Join(dir, "systemd", "private")); err != nil {
+		return Rlimit
+	}
+	return Systemd
+}
+
+// Wrap returns a shell command line that runs command (itself a shell command
+// line) under l, enforced with kind. A zero l returns command unchanged.
+func Wrap(kind Kind, command string, l Limits) string {
+	l = l.Normalize()
+	if l.IsZero() {
+		return command
+	}
+	inner := []string{"/bin/sh", "-c", command}
+	if l.Nice > 0 {
+		inner = append([]string{"nice", "-n", strconv.Itoa(l.Nice)}, inner...)
+	}
+	if kind == Systemd && (l.MemoryMB > 0 || l.CPUPercent > 0) {
+		args := []string{"systemd-run", "--user", "--scope", "--quiet", "--collect"}
+		if l.MemoryMB > 0 {
+			args = append(args, "-p", "MemoryMax="+strconv.Itoa(l.MemoryMB)+"M", "-p", "MemorySwapMax=0")
+		}
+		if l.CPUPercent > 0 {
+			args = append(args, "-p", "CPUQuota="+strconv.Itoa(l.CPUPercent)+"%")
+		}
+		return joinQuoted(append(append(args, "--"), inner...))
+	}
+	if l.MemoryMB > 0 {
+		// The rlimit applies per process, not to the tree; it still stops a
+		// single runaway allocation. Some systems (macOS) reject -v, so a
+		// failure to set it is not fatal.
+		kb := strconv.Itoa(l.MemoryMB * 1024)
+		return joinQuoted([]string{"/bin/sh", "-c", "ulimit -v " + kb + " 2>/dev/null; exec \"$@\"", "sh"}) + " " + joinQuoted(inner)
+	}
+	return joinQuoted(inner)
+}
+
+func joinQuoted(args []string) string {
+	quoted := make([]string, len(args))
+	for i, arg := range args {
+		quoted[i] = shellutil.ShellQuote(arg)
+	}
+	return strings.Join(quoted, " ")
+}
@@ -0,0 +1,169 @@
+package limits
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func stubPlatform(t *testing.T, os, runtime string, tools ...string) {
+	t.Helper()
+	oldGOOS, oldLookPath, oldRuntimeDir := goos, lookPath, runtimeDir
+	t.Cleanup(func() { goos, lookPath, runtimeDir = oldGOOS, oldLookPath, oldRuntimeDir })
+	goos = os
+	runtimeDir = func() string { return runtime }
+	lookPath = func(name string) (string, error) {
+		for _, tool := range tools {
+			if tool == name {
+				return "/usr/bin/" + name, nil
+			}
+		}
+		return "", exec.ErrNotFound
+	}
+}
+
+func TestDetect(t *testing.T) {
+	withManager := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(withManager, "systemd", "private"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	cases := []struct {
+		goos    string
+		runtime string
+		tools   []string
+		want    Kind
+	}{
+		{"linux", withManager, []string{"systemd-run"}, Systemd},
+		{"linux", t.TempDir(), []string{"systemd-run"}, Rlimit},
+		{"linux", "", []string{"systemd-run"}, Rlimit},
+		{"linux", withManager, nil, Rlimit},
+		{"darwin", withManager, []string{"systemd-run"}, Rlimit},
+	}
+	for _, tc := range cases {
+		stubPlatform(t, tc.goos, tc.runtime, tc.tools...)
+		if got := Detect(); got != tc.want {
+			t.Errorf("Detect() on %s with %v = %q, want %q", tc.goos, tc.tools, got, tc.want)
+		}
+	}
+}
+
+func TestWrap(t *testing.T) {
+	if got := Wrap(Systemd, "claude", Limits{}); got != "claude" {
+		t.Fatalf("Wrap() with no limits = %q, want the command unchanged", got)
+	}
+
+	got := Wrap(Systemd, "claude", Limits{MemoryMB: 2048, CPUPercent: 150, Nice: 10})
+	for _, want := range []string{"'systemd-run' '--user' '--scope'", "'MemoryMax=2048M'", "'CPUQuota=150%'", "'nice' '-n' '10' '/bin/sh' '-c' 'claude'"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("systemd wrap = %q, missing %q", got, want)
+		}
+	}
+
+	got = Wrap(Rlimit, "claude", Limits{MemoryMB: 1, CPUPercent: 150})
+	if strings.Contains(got, "systemd-run") || !strings.Contains(got, "ulimit -v 1024") || !strings.HasSuffix(got, "'/bin/sh' '-c' 'claude'") {
+		t.Fatalf("rlimit wrap = %q", got)
+	}
+
+	if got := Wrap(Systemd, "claude", Limits{Nice: 40}); got != "'nice' '-n' '19' '/bin/sh' '-c' 'claude'" {
+		t.Fatalf("nice-only wrap = %q, want niceness clamped and no scope", got)
+	}
+}
+
+func TestWrapRunsCommand(t *testing.T) {
+	if _, err := exec.LookPath("nice"); err != nil {
+		t.Skip("nice not installed")
+	}
+	out, err := exec.Command("/bin/sh", "-c", Wrap(Rlimit, "echo ok", Limits{MemoryMB: 4096, Nice: 1})).CombinedOutput()
+	if err != nil || strings.TrimSpace(string(out)) != "ok" {
+		t.Fatalf("wrapped command = %q, %v", out, err)
+	}
+}
+
+func TestParseCPUTime(t *testing.T) {
+	cases := map[string]float64{
+		"00:00:05":    5,
+		"01:02:03":    3723,
+		"2-00:00:01":  172801,
+		"0:01.50":     1.5,
+		"1:02:03.25":  3723.25,
+		"garbage":     -1,
+		"1:2:3:4":     -1,
+		"-1:00:00:00": -1,
+	}
+	for in, want := range cases {
+		got, ok := parseCPUTime(in)
+		if want < 0 {
+			if ok {
+				t.Errorf("parseCPUTime(%q) = %v, want failure", in, got)
+			}
+			continue
+		}
+		if !ok || got != want {
+			t.Errorf("parseCPUTime(%q) = %v, %v; want %v", in, got, ok, want)
+		}
+	}
+}
+
+func TestTableTree(t *testing.T) {
+	table := parsePS(`    1     0  1000 00:10:00
+  100     1  2048 00:00:10
+  101   100  4096 00:00:05
+  102   101  1024 00:00:01
+  200     1  9999 00:00:01
+bogus line
+`)
+	u := table.Tree([]int{100})
+	if u.RSSMB != 7 || u.CPUSeconds != 16 {
+		t.Fatalf("Tree(100) = %+v, want 7 MiB and 16s across the subtree", u)
+	}
+	if u := table.Tree([]int{404}); u != (Usage{}) {
+		t.Fatalf("Tree(missing) = %+v, want zero", u)
+	}
+}
+
+func TestSnapshotListsThisProcess(t *testing.T) {
+	if _, err := exec.LookPath("ps"); err != nil {
+		t.Skip("ps not installed")
+	}
+	table, err := Snapshot()
+	if err != nil {
+		t.Fatalf("Snapshot() error = %v", err)
+	}
+	if u := table.Tree([]int{os.Getpid()}); u.RSSMB == 0 {
+		t.Fatalf("Tree(self) = %+v, want nonzero memory", u)
+	}
+}
+
+func TestMonitorReportsEachCrossingOnce(t *testing.T) {
+	m := NewMonitor()
+	l := Limits{MemoryMB: 1000, CPUPercent: 100}
+	start := time.Unix(1000, 0)
+
+	if got := m.Check("s", l, Usage{RSSMB: 500, CPUSeconds: 100}, start); len(got) != 0 {
+		t.Fatalf("first sample = %v, want no breach", got)
+	}
+	got := m.Check("s", l, Usage{RSSMB: 950, CPUSeconds: 110}, start.Add(10*time.Second))
+	if len(got) != 2 || got[0].Resource != Memory || got[1].Resource != CPU || got[1].Used != 100 {
+		t.Fatalf("over both limits = %v, want memory and CPU breaches", got)
+	}
+	if got := m.Check("s", l, Usage{RSSMB: 980, CPUSeconds: 120}, start.Add(20*time.Second)); len(got) != 0 {
+		t.Fatalf("still over = %v, want no repeat", got)
+	}
+	// Dropping just below the breach line is not enough to re-arm.
+	m.Check("s", l, Usage{RSSMB: 800, CPUSeconds: 120}, start.Add(30*time.Second))
+	if got := m.Check("s", l, Usage{RSSMB: 950, CPUSeconds: 120}, start.Add(40*time.Second)); len(got) != 0 {
+		t.Fatalf("hovering = %v, want no repeat", got)
+	}
+	m.Check("s", l, Usage{RSSMB: 100, CPUSeconds: 120}, start.Add(50*time.Second))
+	if got := m.Check("s", l, Usage{RSSMB: 950, CPUSeconds: 120}, start.Add(60*time.Second)); len(got) != 1 || got[0].String() != "memory 950 of 1000 MiB" {
+		t.Fatalf("after recovery = %v, want a fresh memory breach", got)
+	}
+
+	m.Forget(map[string]bool{})
+	if len(m.trees) != 0 {
+		t.Fatal("Forget should drop trees that are gone")
+	}
+}
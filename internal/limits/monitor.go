@@ -0,0 +1,101 @@
+package limits
+
+import (
+	"fmt"
+	"time"
+)
+
+// Resource names what a breach is about.
+type Resource string
+
+const (
+	Memory Resource = "memory"
+	CPU    Resource = "cpu"
+)
+
+// A tree breaches a limit when it reaches breachRatio of it, and is reported
+// again only after dropping below clearRatio, so usage hovering at the cap
+// does not flap.
+const (
+	breachRatio = 0.9
+	clearRatio  = 0.75
+)
+
+// Breach is a limit a process tree reached.
+type Breach struct {
+	Resource Resource
+	Used     int // MiB or percent of a core
+	Limit    int
+}
+
+func (b Breach) String() string {
+	if b.Resource == Memory {
+		return fmt.Sprintf("memory %d of %d MiB", b.Used, b.Limit)
+	}
+	return fmt.Sprintf("CPU %d%% of %d%%", b.Used, b.Limit)
+}
+
+type treeState struct {
+	lastCPU  float64
+	lastAt   time.Time
+	breached map[Resource]bool
+}
+
+// Monitor turns usage samples into breaches, one per crossing. It is not
+// safe for concurrent use.
+type Monitor struct {
+	trees map[string]*treeState
+}
+
+// NewMonitor returns an empty monitor.
+func NewMonitor() *Monitor {
+	return &Monitor{trees: make(map[string]*treeState)}
+}
+
+// Check records usage u of the tree named key at now and returns the limits
+// it newly reached. CPU use is averaged since the previous sample, so the
+// first sample of a tree never reports a CPU breach.
+func (m *Monitor) Check(key string, l Limits, u Usage, now time.Time) []Breach {
+	st := m.trees[key]
+	if st == nil {
+		st = &treeState{breached: make(map[Resource]bool)}
+		m.trees[key] = st
+	}
+	var out []Breach
+	if l.MemoryMB > 0 {
+		if b, ok := st.cross(Memory, u.RSSMB, l.MemoryMB); ok {
+			out = append(out, b)
+		}
+	}
+	if l.CPUPercent > 0 && !st.lastAt.IsZero() && now.After(st.lastAt) && u.CPUSeconds >= st.lastCPU {
+		percent := int((u.CPUSeconds - st.lastCPU) / now.Sub(st.lastAt).Seconds() * 100)
+		if b, ok := st.cross(CPU, percent, l.CPUPercent); ok {
+			out = append(out, b)
+		}
+	}
+	st.lastCPU, st.lastAt = u.CPUSeconds, now
+	return out
+}
+
+func (st *treeState) cross(r Resource, used, limit int) (Breach, bool) {
+	switch {
+	case float64(used) >= float64(limit)*breachRatio:
+		if st.breached[r] {
+			return Breach{}, false
+		}
+		st.breached[r] = true
+		return Breach{Resource: r, Used: used, Limit: limit}, true
+	case float64(used) < float64(limit)*clearRatio:
+		st.breached[r] = false
+	}
+	return Breach{}, false
+}
+
+// Forget drops state for trees not in live.
+func (m *Monitor) Forget(live map[string]bool) {
+	for key := range m.trees {
+		if !live[key] {
+			delete(m.trees, key)
+		}
+	}
+}
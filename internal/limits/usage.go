@@ -0,0 +1,130 @@
+package limits
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const psTimeout = 5 * time.Second
+
+// runPS runs ps and returns its output. Tests replace it.
+var runPS = func() (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), psTimeout)
+	defer cancel()
+	out, err := exec.CommandContext(ctx, "ps", "-A", "-o", "pid=,ppid=,rss=,time=").Output()
+	return string(out), err
+}
+
+// Usage is what a process tree is using at one moment.
+type Usage struct {
+	RSSMB int
+	// CPUSeconds is the CPU time the live processes have used so far.
+	CPUSeconds float64
+}
+
+type procInfo struct {
+	ppid       int
+	rssKB      int
+	cpuSeconds float64
+}
+
+// Table is one ps snapshot of every process on the machine.
+type Table struct {
+	procs    map[int]procInfo
+	children map[int][]int
+}
+
+// Snapshot lists every process once, so several trees can be measured from a
+// single ps run.
+func Snapshot() (Table, error) {
+	out, err := runPS()
+	if err != nil {
+		return Table{}, fmt.Errorf("limits: ps: %w", err)
+	}
+	return parsePS(out), nil
+}
+
+func parsePS(out string) Table {
+	t := Table{procs: make(map[int]procInfo), children: make(map[int][]int)}
+	scanner := bufio.NewScanner(strings.NewReader(out))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 4 {
+			continue
+		}
+		pid, err1 := strconv.Atoi(fields[0])
+		ppid, err2 := strconv.Atoi(fields[1])
+		rss, err3 := strconv.Atoi(fields[2])
+		cpu, ok := parseCPUTime(fields[3])
+		if err1 != nil || err2 != nil || err3 != nil || !ok {
+			continue
+		}
+		t.procs[pid] = procInfo{ppid: ppid, rssKB: rss, cpuSeconds: cpu}
+		t.children[ppid] = append(t.children[ppid], pid)
+	}
+	return t
+}
+
+// parseCPUTime parses ps's cumulative CPU time: [DD-]HH:MM:SS on Linux,
+// [HH:]MM:SS.ss on macOS and the BSDs.
+func parseCPUTime(s string) (float64, bool) {
+	var days float64
+	if d, rest, ok := strings.Cut(s, "-"); ok {
+		n, err := strconv.Atoi(d)
+		if err != nil {
+			return 0, false
+		}
+		days, s = float64(n), rest
+	}
+	parts := strings.Split(s, ":")
+	if len(parts) < 2 || len(parts) > 3 {
+		return 0, false
+	}
+	total := days * 86400
+	for i, part := range parts {
+		v, err := strconv.ParseFloat(part, 64)
+		if err != nil || v < 0 {
+			return 0, false
+		}
+		total += v * float64(pow60(len(parts)-1-i))
+	}
+	return total, true
+}
+
+func pow60(n int) int {
+	p := 1
+	for range n {
+		p *= 60
+	}
+	return p
+}
+
+// Tree sums the usage of roots and all their descendants.
+func (t Table) Tree(roots []int) Usage {
+	var rssKB int
+	var u Usage
+	seen := make(map[int]bool)
+	stack := append([]int(nil), roots...)
+	for len(stack) > 0 {
+		pid := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if seen[pid] {
+			continue
+		}
+		seen[pid] = true
+		info, ok := t.procs[pid]
+		if !ok {
+			continue
+		}
+		rssKB += info.rssKB
+		u.CPUSeconds += info.cpuSeconds
+		stack = append(stack, t.children[pid]...)
+	}
+	u.RSSMB = rssKB / 1024
+	return u
+}
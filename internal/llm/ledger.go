@@ -0,0 +1,59 @@
+package llm
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"sync"
+
+	"github.com/andyrewlee/amux/internal/fsatomic"
+)
+
+// LedgerFile is the ledger's name in amux's home directory.
+const LedgerFile = "llm-usage.json"
+
+// Ledger keeps the usage of each feature in a JSON file, so it adds up
+// across runs of amux.
+type Ledger struct {
+	path string
+	mu   sync.Mutex
+}
+
+// OpenLedger returns the ledger kept at path.
+func OpenLedger(path string) *Ledger {
+	return &Ledger{path: path}
+}
+
+// Record adds u to feature's usage.
+func (l *Ledger) Record(feature Feature, u Usage) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	totals, err := l.read()
+	if err != nil {
+		return err
+	}
+	totals[feature] = totals[feature].add(u)
+	return fsatomic.WriteJSON(l.path, totals)
+}
+
+// Totals returns each feature's usage so far.
+func (l *Ledger) Totals() (map[Feature]Usage, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.read()
+}
+
+func (l *Ledger) read() (map[Feature]Usage, error) {
+	totals := map[Feature]Usage{}
+	raw, err := os.ReadFile(l.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return totals, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(raw, &totals); err != nil {
+		return nil, err
+	}
+	return totals, nil
+}
@@ -0,0 +1,231 @@
+// Package llm is the language model client amux's own assistive features
+// share, such as drafting commit messages. It talks to an OpenAI-compatible
+// endpoint, which covers a local Ollama, or to Anthropic's Messages API, as
+// configured once in config.json. Each feature stays off until it is enabled
+// there, since using it sends the worktree's changes to the endpoint.
+//
+// The tokens every completion uses are added up per feature in a ledger.
+package llm
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+// Provider names the API an endpoint speaks.
+type Provider string
+
+const (
+	OpenAI    Provider = "openai"
+	Anthropic Provider = "anthropic"
+	// Ollama is a local Ollama server, through its OpenAI-compatible API.
+	Ollama Provider = "ollama"
+)
+
+// Feature names an assistive feature in Config.Features.
+type Feature string
+
+const (
+	// FeatureCommitMessage drafts the commit dialog's message from the diff.
+	FeatureCommitMessage Feature = "commit-message"
+	// FeatureAsk answers questions about using amux from its built-in docs.
+	FeatureAsk Feature = "ask"
+)
+
+// defaultMaxTokens bounds a completion when the config doesn't.
+const defaultMaxTokens = 1024
+
+// requestTimeout bounds one completion.
+const requestTimeout = 2 * time.Minute
+
+// maxErrorBody bounds how much of an error response is read.
+const maxErrorBody = 4096
+
+// ErrDisabled is returned for a feature that is not enabled.
+var ErrDisabled = errors.New("llm: feature not enabled")
+
+// Config is the "llm" section of config.json.
+type Config struct {
+	Provider Provider `json:"provider"`
+	// Endpoint is the API's base URL; each provider has a default.
+	Endpoint string `json:"endpoint"`
+	Model    string `json:"model"`
+	// APIKeyEnv names the environment variable holding the API key:
+	// ANTHROPIC_API_KEY or OPENAI_API_KEY unless set. Ollama needs none.
+	APIKeyEnv string           `json:"api_key_env"`
+	MaxTokens int              `json:"max_tokens"`
+	Features  map[Feature]bool `json:"features"`
+}
+
+// WithDefaults fills in the provider's endpoint and key variable and the
+// token bound where the config leaves them out.
+func (c Config) WithDefaults() Config {
+	c.Provider = Provider(strings.ToLower(strings.TrimSpace(string(c.Provider))))
+	c.Model = strings.TrimSpace(c.Model)
+	endpoint, keyEnv := "", ""
+	switch c.Provider {
+	case OpenAI:
+		endpoint, keyEnv = "https://api.openai.com/v1", "OPENAI_API_KEY"
+	case Anthropic:
+		endpoint, keyEnv = "https://api.anthropic.com", "ANTHROPIC_API_KEY"
+	case Ollama:
+		endpoint = "http://localhost:11434/v1"
+	}
+	if c.Endpoint == "" {
+		c.Endpoint = endpoint
+	}
+	c.Endpoint = strings.TrimRight(c.Endpoint, "/")
+	if c.APIKeyEnv == "" {
+		c.APIKeyEnv = keyEnv
+	}
+	if c.MaxTokens <= 0 {
+		c.MaxTokens = defaultMaxTokens
+	}
+	return c
+}
+
+// Validate reports a config that names no model or an unknown provider. An
+// empty provider is valid: the features are simply off.
+func (c Config) Validate() error {
+	switch c.Provider {
+	case "":
+		return nil
+	case OpenAI, Anthropic, Ollama:
+	default:
+		return fmt.Errorf("llm: unknown provider %q (use openai, anthropic, or ollama)", c.Provider)
+	}
+	if c.Model == "" {
+		return errors.New("llm: no model configured")
+	}
+	return nil
+}
+
+// Enabled reports whether feature is turned on for a valid provider.
+func (c Config) Enabled(feature Feature) bool {
+	return c.Provider != "" && c.Validate() == nil && c.Features[feature]
+}
+
+// Request is one completion: instructions and the input they apply to.
+type Request struct {
+	Feature Feature
+	System  string
+	Prompt  string
+}
+
+// Usage counts requests and the tokens they used.
+type Usage struct {
+	Requests     int `json:"requests"`
+	InputTokens  int `json:"input_tokens"`
+	OutputTokens int `json:"output_tokens"`
+}
+
+func (u Usage) add(o Usage) Usage {
+	return Usage{u.Requests + o.Requests, u.InputTokens + o.InputTokens, u.OutputTokens + o.OutputTokens}
+}
+
+// Client completes requests for the enabled features.
+type Client struct {
+	cfg    Config
+	ledger *Ledger
+	http   *http.Client
+	getenv func(string) string
+}
+
+// New returns a client for cfg that records usage in ledger, which may be
+// nil.
+func New(cfg Config, ledger *Ledger) *Client {
+	return &Client{
+		cfg:    cfg.WithDefaults(),
+		ledger: ledger,
+		http:   &http.Client{Timeout: requestTimeout},
+		getenv: os.Getenv,
+	}
+}
+
+// Enabled reports whether feature may be used.
+func (c *Client) Enabled(feature Feature) bool {
+	return c != nil && c.cfg.Enabled(feature)
+}
+
+// Complete returns the model's reply to req, recording the tokens it used.
+func (c *Client) Complete(ctx context.Context, req Request) (string, error) {
+	if !c.Enabled(req.Feature) {
+		return "", ErrDisabled
+	}
+	key := ""
+	if c.cfg.APIKeyEnv != "" {
+		if key = c.getenv(c.cfg.APIKeyEnv); key == "" {
+			return "", fmt.Errorf("llm: %s is not set", c.cfg.APIKeyEnv)
+		}
+	}
+	var text string
+	var usage Usage
+	var err error
+	if c.cfg.Provider == Anthropic {
+		text, usage, err = c.anthropic(ctx, key, req)
+	} else {
+		text, usage, err = c.openAI(ctx, key, req)
+	}
+	if err != nil {
+		return "", err
+	}
+	usage.Requests = 1
+	if c.ledger != nil {
+		if err := c.ledger.Record(req.Feature, usage); err != nil {
+			return "", err
+		}
+	}
+	return strings.TrimSpace(text), nil
+}
+
+// post sends body as JSON to the endpoint's path and decodes the reply into
+// out.
+func (c *Client) post(ctx context.Context, path string, headers map[string]string, body, out any) error {
+	raw, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, c.cfg.Endpoint+path, bytes.NewReader(raw))
+	if err != nil {
+		return err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	for k, v := range headers {
+		httpReq.Header.Set(k, v)
+	}
+	resp, err := c.http.Do(httpReq)
+	if err != nil {
+		return fmt.Errorf("llm: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBody))
+		return fmt.Errorf("llm: %s: %s", resp.Status, errorMessage(msg))
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("llm: reading response: %w", err)
+	}
+	return nil
+}
+
+// errorMessage extracts the message both APIs put in error.message, or
+// returns the body as is.
+func errorMessage(body []byte) string {
+	var parsed struct {
+		Error struct {
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	if json.Unmarshal(body, &parsed) == nil && parsed.Error.Message != "" {
+		return parsed.Error.Message
+	}
+	return strings.TrimSpace(string(body))
+}
@@ -0,0 +1,112 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// fakeAPI serves one canned reply and records the request it got.
+func fakeAPI(t *testing.T, status int, reply string) (*httptest.Server, *http.Request, map[string]any) {
+	t.Helper()
+	got := &http.Request{}
+	body := map[string]any{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*got = *r.Clone(context.Background())
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Errorf("request body: %v", err)
+		}
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(reply))
+	}))
+	t.Cleanup(srv.Close)
+	return srv, got, body
+}
+
+func testClient(cfg Config, ledger *Ledger, env map[string]string) *Client {
+	c := New(cfg, ledger)
+	c.getenv = func(key string) string { return env[key] }
+	return c
+}
+
+func TestCompleteOpenAI(t *testing.T) {
+	srv, got, body := fakeAPI(t, http.StatusOK, `{"choices":[{"message":{"role":"assistant","content":" fix(app): handle nil workspace\n"}}],"usage":{"prompt_tokens":120,"completion_tokens":9}}`)
+	ledger := OpenLedger(filepath.Join(t.TempDir(), "llm-usage.json"))
+	c := testClient(Config{Provider: OpenAI, Endpoint: srv.URL + "/", Model: "gpt-test", Features: map[Feature]bool{FeatureCommitMessage: true}},
+		ledger, map[string]string{"OPENAI_API_KEY": "sk-test"})
+
+	text, err := c.Complete(context.Background(), Request{Feature: FeatureCommitMessage, System: "Write a commit message.", Prompt: "diff"})
+	if err != nil || text != "fix(app): handle nil workspace" {
+		t.Fatalf("Complete() = %q, %v", text, err)
+	}
+	if got.URL.Path != "/chat/completions" || got.Header.Get("Authorization") != "Bearer sk-test" {
+		t.Fatalf("request = %s %v", got.URL.Path, got.Header)
+	}
+	if body["model"] != "gpt-test" || len(body["messages"].([]any)) != 2 {
+		t.Fatalf("request body = %v", body)
+	}
+	if _, err := c.Complete(context.Background(), Request{Feature: FeatureCommitMessage, Prompt: "diff"}); err != nil {
+		t.Fatal(err)
+	}
+	totals, err := ledger.Totals()
+	if err != nil || totals[FeatureCommitMessage] != (Usage{Requests: 2, InputTokens: 240, OutputTokens: 18}) {
+		t.Fatalf("Totals() = %+v, %v", totals, err)
+	}
+}
+
+func TestCompleteAnthropic(t *testing.T) {
+	srv, got, body := fakeAPI(t, http.StatusOK, `{"content":[{"type":"text","text":"docs: update README.md"}],"usage":{"input_tokens":50,"output_tokens":6}}`)
+	c := testClient(Config{Provider: Anthropic, Endpoint: srv.URL, Model: "claude-test", Features: map[Feature]bool{FeatureCommitMessage: true}},
+		nil, map[string]string{"ANTHROPIC_API_KEY": "key"})
+
+	text, err := c.Complete(context.Background(), Request{Feature: FeatureCommitMessage, System: "Write a commit message.", Prompt: "diff"})
+	if err != nil || text != "docs: update README.md" {
+		t.Fatalf("Complete() = %q, %v", text, err)
+	}
+	if got.URL.Path != "/v1/messages" || got.Header.Get("x-api-key") != "key" || got.Header.Get("anthropic-version") == "" {
+		t.Fatalf("request = %s %v", got.URL.Path, got.Header)
+	}
+	if body["system"] != "Write a commit message." || body["max_tokens"] != float64(defaultMaxTokens) {
+		t.Fatalf("request body = %v", body)
+	}
+}
+
+func TestCompleteErrors(t *testing.T) {
+	srv, _, _ := fakeAPI(t, http.StatusUnauthorized, `{"error":{"message":"invalid api key"}}`)
+	cfg := Config{Provider: OpenAI, Endpoint: srv.URL, Model: "gpt-test", Features: map[Feature]bool{FeatureCommitMessage: true}}
+
+	if _, err := testClient(cfg, nil, nil).Complete(context.Background(), Request{Feature: "summary"}); !errors.Is(err, ErrDisabled) {
+		t.Fatalf("Complete() for a feature not enabled = %v, want ErrDisabled", err)
+	}
+	if _, err := testClient(cfg, nil, nil).Complete(context.Background(), Request{Feature: FeatureCommitMessage}); err == nil || !strings.Contains(err.Error(), "OPENAI_API_KEY") {
+		t.Fatalf("Complete() without a key = %v", err)
+	}
+	_, err := testClient(cfg, nil, map[string]string{"OPENAI_API_KEY": "bad"}).Complete(context.Background(), Request{Feature: FeatureCommitMessage})
+	if err == nil || !strings.Contains(err.Error(), "invalid api key") {
+		t.Fatalf("Complete() with a rejected key = %v", err)
+	}
+}
+
+func TestConfig(t *testing.T) {
+	cfg := Config{Provider: " Ollama ", Model: "llama3"}.WithDefaults()
+	if cfg.Provider != Ollama || cfg.Endpoint != "http://localhost:11434/v1" || cfg.APIKeyEnv != "" || cfg.MaxTokens != defaultMaxTokens {
+		t.Fatalf("WithDefaults() = %+v", cfg)
+	}
+	if cfg.Enabled(FeatureCommitMessage) {
+		t.Fatal("features should be off until enabled")
+	}
+	if err := (Config{Provider: "bard", Model: "x"}).Validate(); err == nil {
+		t.Fatal("an unknown provider should not validate")
+	}
+	if err := (Config{Provider: OpenAI}).Validate(); err == nil {
+		t.Fatal("a provider without a model should not validate")
+	}
+	if err := (Config{}).Validate(); err != nil {
+		t.Fatalf("no provider at all is valid: %v", err)
+	}
+}
@@ -0,0 +1,78 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"strings"
+)
+
+// anthropicVersion is the Messages API version requests are written for.
+const anthropicVersion = "2023-06-01"
+
+type message struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+// openAI completes req through the chat completions API.
+func (c *Client) openAI(ctx context.Context, key string, req Request) (string, Usage, error) {
+	var messages []message
+	if req.System != "" {
+		messages = append(messages, message{Role: "system", Content: req.System})
+	}
+	messages = append(messages, message{Role: "user", Content: req.Prompt})
+	body := map[string]any{"model": c.cfg.Model, "max_tokens": c.cfg.MaxTokens, "messages": messages}
+	headers := map[string]string{}
+	if key != "" {
+		headers["Authorization"] = "Bearer " + key
+	}
+	var resp struct {
+		Choices []struct {
+			Message message `json:"message"`
+		} `json:"choices"`
+		Usage struct {
+			PromptTokens     int `json:"prompt_tokens"`
+			CompletionTokens int `json:"completion_tokens"`
+		} `json:"usage"`
+	}
+	if err := c.post(ctx, "/chat/completions", headers, body, &resp); err != nil {
+		return "", Usage{}, err
+	}
+	if len(resp.Choices) == 0 {
+		return "", Usage{}, errors.New("llm: the response has no choices")
+	}
+	return resp.Choices[0].Message.Content, Usage{InputTokens: resp.Usage.PromptTokens, OutputTokens: resp.Usage.CompletionTokens}, nil
+}
+
+// anthropic completes req through the Messages API.
+func (c *Client) anthropic(ctx context.Context, key string, req Request) (string, Usage, error) {
+	body := map[string]any{
+		"model":      c.cfg.Model,
+		"max_tokens": c.cfg.MaxTokens,
+		"messages":   []message{{Role: "user", Content: req.Prompt}},
+	}
+	if req.System != "" {
+		body["system"] = req.System
+	}
+	headers := map[string]string{"x-api-key": key, "anthropic-version": anthropicVersion}
+	var resp struct {
+		Content []struct {
+			Type string `json:"type"`
+			Text string `json:"text"`
+		} `json:"content"`
+		Usage struct {
+			InputTokens  int `json:"input_tokens"`
+			OutputTokens int `json:"output_tokens"`
+		} `json:"usage"`
+	}
+	if err := c.post(ctx, "/v1/messages", headers, body, &resp); err != nil {
+		return "", Usage{}, err
+	}
+	var text strings.Builder
+	for _, block := range resp.Content {
+		if block.Type == "text" {
+			text.WriteString(block.Text)
+		}
+	}
+	return text.String(), Usage{InputTokens: resp.Usage.InputTokens, OutputTokens: resp.Usage.OutputTokens}, nil
+}
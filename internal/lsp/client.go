@@ -0,0 +1,415 @@
+// Package lsp is a minimal Language Server Protocol client. It starts a
+// language server (gopls, typescript-language-server) for a worktree and asks
+// it for workspace symbols and definitions, so agent changes can be navigated
+// without a full editor.
+//
+// Only the requests amux makes are implemented. Requests the server sends to
+// the client are answered with empty results, and notifications from the
+// server are dropped.
+package lsp
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// ErrClosed is returned for requests on a client whose server has exited.
+var ErrClosed = errors.New("language server closed")
+
+// Position is a zero-based line and UTF-16 column, as in the protocol.
+type Position struct {
+	Line      int `json:"line"`
+	Character int `json:"character"`
+}
+
+// Range is a span between two positions.
+type Range struct {
+	Start Position `json:"start"`
+	End   Position `json:"end"`
+}
+
+// Location is a range in a file.
+type Location struct {
+	URI   string `json:"uri"`
+	Range Range  `json:"range"`
+}
+
+// Path returns the location's file path, or "" for a non-file URI.
+func (l Location) Path() string {
+	return uriToPath(l.URI)
+}
+
+// Symbol is a named declaration found by a workspace symbol search.
+type Symbol struct {
+	Name          string   `json:"name"`
+	Kind          int      `json:"kind"`
+	ContainerName string   `json:"containerName,omitempty"`
+	Location      Location `json:"location"`
+}
+
+// Client talks to one language server over a stream.
+type Client struct {
+	root string
+	w    io.Writer
+	// closeFn stops the server; it is called once.
+	closeFn func() error
+
+	writeMu sync.Mutex
+	mu      sync.Mutex
+	nextID  int
+	pending map[int]chan response
+	err     error
+	done    chan struct{}
+	closed  sync.Once
+}
+
+type message struct {
+	JSONRPC string           `json:"jsonrpc"`
+	ID      *json.RawMessage `json:"id,omitempty"`
+	Method  string           `json:"method,omitempty"`
+	Params  any              `json:"params,omitempty"`
+	Result  json.RawMessage  `json:"result,omitempty"`
+	Error   *responseError   `json:"error,omitempty"`
+}
+
+type incoming struct {
+	ID     *json.RawMessage `json:"id"`
+	Method string           `json:"method"`
+	Params json.RawMessage  `json:"params"`
+	Result json.RawMessage  `json:"result"`
+	Error  *responseError   `json:"error"`
+}
+
+type responseError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *responseError) Error() string {
+	return fmt.Sprintf("language server error %d: %s", e.Code, e.Message)
+}
+
+type response struct {
+	result json.RawMessage
+	err    error
+}
+
+// newClient starts reading server messages from r. Requests are written to
+// w; closeFn is called by Close after the shutdown handshake.
+func newClient(root string, r io.Reader, w io.Writer, closeFn func() error) *Client {
+	c := &Client{
+		root:    root,
+		w:       w,
+		closeFn: closeFn,
+		pending: make(map[int]chan response),
+		done:    make(chan struct{}),
+	}
+	go c.readLoop(bufio.NewReader(r))
+	return c
+}
+
+// Initialize performs the initialize handshake for the client's root.
+func (c *Client) Initialize(ctx context.Context) error {
+	uri := pathToURI(c.root)
+	params := map[string]any{
+		"processId": os.Getpid(),
+		"rootUri":   uri,
+		"workspaceFolders": []map[string]string{
+			{"uri": uri, "name": filepath.Base(c.root)},
+		},
+		"capabilities": map[string]any{
+			"workspace":    map[string]any{"symbol": map[string]any{}, "workspaceFolders": true},
+			"textDocument": map[string]any{"definition": map[string]any{"linkSupport": true}},
+		},
+	}
+	if _, err := c.call(ctx, "initialize", params); err != nil {
+		return err
+	}
+	return c.notify("initialized", map[string]any{})
+}
+
+// WorkspaceSymbols returns the symbols in the workspace matching query.
+func (c *Client) WorkspaceSymbols(ctx context.Context, query string) ([]Symbol, error) {
+	raw, err := c.call(ctx, "workspace/symbol", map[string]string{"query": query})
+	if err != nil {
+		return nil, err
+	}
+	var symbols []Symbol
+	if len(raw) == 0 || string(raw) == "null" {
+		return nil, nil
+	}
+	if err := json.Unmarshal(raw, &symbols); err != nil {
+		return nil, fmt.Errorf("decoding workspace symbols: %w", err)
+	}
+	return symbols, nil
+}
+
+// Definition returns where the identifier at pos in path is defined. The file
+// is opened on the server for the request, since some servers only answer
+// for open documents.
+func (c *Client) Definition(ctx context.Context, path string, pos Position) ([]Location, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	uri := pathToURI(path)
+	doc := map[string]any{"uri": uri}
+	if err := c.notify("textDocument/didOpen", map[string]any{
+		"textDocument": map[string]any{"uri": uri, "languageId": languageID(path), "version": 1, "text": string(content)},
+	}); err != nil {
+		return nil, err
+	}
+	defer func() { _ = c.notify("textDocument/didClose", map[string]any{"textDocument": doc}) }()
+	raw, err := c.call(ctx, "textDocument/definition", map[string]any{"textDocument": doc, "position": pos})
+	if err != nil {
+		return nil, err
+	}
+	return decodeLocations(raw)
+}
+
+// decodeLocations reads a definition result: null, a Location, or a list of
+// Locations or LocationLinks.
+func decodeLocations(raw json.RawMessage) ([]Location, error) {
+	if len(raw) == 0 || string(raw) == "null" {
+		return nil, nil
+	}
+	type link struct {
+		URI                  string `json:"uri"`
+		Range                *Range `json:"range"`
+		TargetURI            string `json:"targetUri"`
+		TargetSelectionRange *Range `json:"targetSelectionRange"`
+	}
+	var items []link
+	if raw[0] == '[' {
+		if err := json.Unmarshal(raw, &items); err != nil {
+			return nil, fmt.Errorf("decoding definition: %w", err)
+		}
+	} else {
+		var one link
+		if err := json.Unmarshal(raw, &one); err != nil {
+			return nil, fmt.Errorf("decoding definition: %w", err)
+		}
+		items = []link{one}
+	}
+	locations := make([]Location, 0, len(items))
+	for _, item := range items {
+		switch {
+		case item.TargetURI != "" && item.TargetSelectionRange != nil:
+			locations = append(locations, Location{URI: item.TargetURI, Range: *item.TargetSelectionRange})
+		case item.URI != "" && item.Range != nil:
+			locations = append(locations, Location{URI: item.URI, Range: *item.Range})
+		}
+	}
+	return locations, nil
+}
+
+// Close asks the server to shut down and stops it.
+func (c *Client) Close(ctx context.Context) error {
+	var err error
+	c.closed.Do(func() {
+		if _, callErr := c.call(ctx, "shutdown", nil); callErr == nil {
+			_ = c.notify("exit", nil)
+		}
+		if c.closeFn != nil {
+			err = c.closeFn()
+		}
+	})
+	return err
+}
+
+// Done is closed when the server's output ends.
+func (c *Client) Done() <-chan struct{} {
+	return c.done
+}
+
+func (c *Client) call(ctx context.Context, method string, params any) (json.RawMessage, error) {
+	c.mu.Lock()
+	if c.err != nil {
+		c.mu.Unlock()
+		return nil, c.err
+	}
+	c.nextID++
+	id := c.nextID
+	ch := make(chan response, 1)
+	c.pending[id] = ch
+	c.mu.Unlock()
+
+	rawID := json.RawMessage(strconv.Itoa(id))
+	if err := c.write(message{JSONRPC: "2.0", ID: &rawID, Method: method, Params: params}); err != nil {
+		c.forget(id)
+		return nil, err
+	}
+	select {
+	case resp := <-ch:
+		return resp.result, resp.err
+	case <-ctx.Done():
+		c.forget(id)
+		return nil, ctx.Err()
+	}
+}
+
+func (c *Client) forget(id int) {
+	c.mu.Lock()
+	delete(c.pending, id)
+	c.mu.Unlock()
+}
+
+func (c *Client) notify(method string, params any) error {
+	return c.write(message{JSONRPC: "2.0", Method: method, Params: params})
+}
+
+func (c *Client) write(msg message) error {
+	body, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+	if _, err := fmt.Fprintf(c.w, "Content-Length: %d\r\n\r\n", len(body)); err != nil {
+		return err
+	}
+	_, err = c.w.Write(body)
+	return err
+}
+
+func (c *Client) readLoop(r *bufio.Reader) {
+	defer close(c.done)
+	for {
+		body, err := readFrame(r)
+		if err != nil {
+			c.fail(err)
+			return
+		}
+		var msg incoming
+		if err := json.Unmarshal(body, &msg); err != nil {
+			continue
+		}
+		switch {
+		case msg.ID != nil && msg.Method != "":
+			// Replying must not hold up reading, or a server blocked on
+			// writing to us would never read the reply.
+			go c.replyToServer(msg)
+		case msg.ID != nil:
+			c.deliver(msg)
+		}
+	}
+}
+
+// replyToServer answers a server-to-client request with an empty result:
+// a null per item for workspace/configuration, null otherwise.
+func (c *Client) replyToServer(msg incoming) {
+	result := json.RawMessage("null")
+	if msg.Method == "workspace/configuration" {
+		var params struct {
+			Items []json.RawMessage `json:"items"`
+		}
+		_ = json.Unmarshal(msg.Params, &params)
+		nulls := make([]any, len(params.Items))
+		result, _ = json.Marshal(nulls)
+	}
+	_ = c.write(message{JSONRPC: "2.0", ID: msg.ID, Result: result})
+}
+
+func (c *Client) deliver(msg incoming) {
+	id, err := strconv.Atoi(string(*msg.ID))
+	if err != nil {
+		return
+	}
+	c.mu.Lock()
+	ch, ok := c.pending[id]
+	delete(c.pending, id)
+	c.mu.Unlock()
+	if !ok {
+		return
+	}
+	resp := response{result: msg.Result}
+	if msg.Error != nil {
+		resp.err = msg.Error
+	}
+	ch <- resp
+}
+
+// fail ends every pending request once the server's output ends.
+func (c *Client) fail(err error) {
+	if errors.Is(err, io.EOF) || errors.Is(err, io.ErrClosedPipe) {
+		err = ErrClosed
+	} else {
+		err = fmt.Errorf("%w: %v", ErrClosed, err)
+	}
+	c.mu.Lock()
+	c.err = err
+	pending := c.pending
+	c.pending = make(map[int]chan response)
+	c.mu.Unlock()
+	for _, ch := range pending {
+		ch <- response{err: err}
+	}
+}
+
+// readFrame reads one Content-Length framed message body.
+func readFrame(r *bufio.Reader) ([]byte, error) {
+	length := -1
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return nil, err
+		}
+		line = strings.TrimRight(line, "\r\n")
+		if line == "" {
+			break
+		}
+		name, value, ok := strings.Cut(line, ":")
+		if ok && strings.EqualFold(strings.TrimSpace(name), "Content-Length") {
+			length, err = strconv.Atoi(strings.TrimSpace(value))
+			if err != nil {
+				return nil, fmt.Errorf("bad Content-Length %q", value)
+			}
+		}
+	}
+	if length < 0 {
+		return nil, errors.New("message without Content-Length")
+	}
+	body := make([]byte, length)
+	_, err := io.ReadFull(r, body)
+	return body, err
+}
+
+func pathToURI(path string) string {
+	return (&url.URL{Scheme: "file", Path: filepath.ToSlash(path)}).String()
+}
+
+func uriToPath(uri string) string {
+	u, err := url.Parse(uri)
+	if err != nil || u.Scheme != "file" {
+		return ""
+	}
+	return filepath.FromSlash(u.Path)
+}
+
+// languageID returns the protocol's language identifier for path.
+func languageID(path string) string {
+	switch filepath.Ext(path) {
+	case ".go":
+		return "go"
+	case ".ts":
+		return "typescript"
+	case ".tsx":
+		return "typescriptreact"
+	case ".js", ".mjs", ".cjs":
+		return "javascript"
+	case ".jsx":
+		return "javascriptreact"
+	default:
+		return strings.TrimPrefix(filepath.Ext(path), ".")
+	}
+}
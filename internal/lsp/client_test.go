@@ -0,0 +1,201 @@
+package lsp
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeServer answers a client's requests with canned results, and first
+// sends a workspace/configuration request of its own, like gopls does.
+type fakeServer struct {
+	t       *testing.T
+	r       *bufio.Reader
+	w       io.Writer
+	results map[string]string
+	methods chan string
+	// replies are the client's answers to the server's own requests.
+	replies []string
+}
+
+func startFake(t *testing.T, results map[string]string) (*Client, *fakeServer) {
+	t.Helper()
+	clientR, serverW := io.Pipe()
+	serverR, clientW := io.Pipe()
+	s := &fakeServer{t: t, r: bufio.NewReader(serverR), w: serverW, results: results, methods: make(chan string, 32)}
+	client := newClient(t.TempDir(), clientR, clientW, func() error {
+		_ = clientW.Close()
+		return serverW.Close()
+	})
+	go s.serve()
+	t.Cleanup(func() { _ = client.Close(context.Background()) })
+	return client, s
+}
+
+func (s *fakeServer) send(v any) {
+	body, _ := json.Marshal(v)
+	fmt.Fprintf(s.w, "Content-Length: %d\r\n\r\n%s", len(body), body)
+}
+
+func (s *fakeServer) serve() {
+	s.send(map[string]any{"jsonrpc": "2.0", "id": "cfg", "method": "workspace/configuration",
+		"params": map[string]any{"items": []any{map[string]string{"section": "gopls"}}}})
+	for {
+		body, err := readFrame(s.r)
+		if err != nil {
+			return
+		}
+		var msg incoming
+		if err := json.Unmarshal(body, &msg); err != nil {
+			continue
+		}
+		if msg.Method == "" {
+			// The client's reply to our configuration request.
+			s.methods <- "reply:" + string(msg.Result)
+			continue
+		}
+		s.methods <- msg.Method
+		if msg.ID == nil {
+			continue
+		}
+		result, ok := s.results[msg.Method]
+		if !ok {
+			result = "null"
+		}
+		if result == "error" {
+			s.send(map[string]any{"jsonrpc": "2.0", "id": msg.ID, "error": map[string]any{"code": -32601, "message": "nope"}})
+			continue
+		}
+		s.send(map[string]any{"jsonrpc": "2.0", "id": msg.ID, "result": json.RawMessage(result)})
+	}
+}
+
+// expect waits for the next client request or notification. Replies to the
+// server's requests arrive concurrently and are set aside.
+func (s *fakeServer) expect(want string) {
+	s.t.Helper()
+	for {
+		select {
+		case got := <-s.methods:
+			if strings.HasPrefix(got, "reply:") {
+				s.replies = append(s.replies, strings.TrimPrefix(got, "reply:"))
+				continue
+			}
+			if got != want {
+				s.t.Fatalf("server saw %q, want %q", got, want)
+			}
+			return
+		case <-time.After(5 * time.Second):
+			s.t.Fatalf("server never saw %q", want)
+		}
+	}
+}
+
+func TestClientSymbolsAndDefinition(t *testing.T) {
+	client, server := startFake(t, map[string]string{
+		"initialize": `{"capabilities":{}}`,
+		"workspace/symbol": `[{"name":"Add","kind":12,"containerName":"calc",
+			"location":{"uri":"file:///src/calc/add.go","range":{"start":{"line":4,"character":5},"end":{"line":4,"character":8}}}}]`,
+		"textDocument/definition": `[{"targetUri":"file:///src/calc/add.go",
+			"targetRange":{"start":{"line":4,"character":0},"end":{"line":9,"character":1}},
+			"targetSelectionRange":{"start":{"line":4,"character":5},"end":{"line":4,"character":8}}}]`,
+	})
+	ctx := context.Background()
+	if err := client.Initialize(ctx); err != nil {
+		t.Fatalf("Initialize() error = %v", err)
+	}
+	server.expect("initialize")
+	server.expect("initialized")
+
+	symbols, err := client.WorkspaceSymbols(ctx, "Add")
+	if err != nil || len(symbols) != 1 {
+		t.Fatalf("WorkspaceSymbols() = %+v, %v", symbols, err)
+	}
+	if s := symbols[0]; s.Name != "Add" || s.Location.Path() != filepath.FromSlash("/src/calc/add.go") || s.Location.Range.Start.Line != 4 {
+		t.Fatalf("symbol = %+v", s)
+	}
+
+	file := filepath.Join(t.TempDir(), "main.go")
+	if err := os.WriteFile(file, []byte("package main\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	locations, err := client.Definition(ctx, file, Position{Line: 0, Character: 9})
+	if err != nil || len(locations) != 1 || locations[0].Range.Start != (Position{Line: 4, Character: 5}) {
+		t.Fatalf("Definition() = %+v, %v; want the link's selection range", locations, err)
+	}
+	server.expect("workspace/symbol")
+	server.expect("textDocument/didOpen")
+	server.expect("textDocument/definition")
+	server.expect("textDocument/didClose")
+	if len(server.replies) == 0 {
+		select {
+		case got := <-server.methods:
+			server.replies = append(server.replies, strings.TrimPrefix(got, "reply:"))
+		case <-time.After(5 * time.Second):
+		}
+	}
+	if len(server.replies) != 1 || server.replies[0] != "[null]" {
+		t.Fatalf("configuration replies = %q, want one [null]", server.replies)
+	}
+}
+
+func TestClientErrors(t *testing.T) {
+	client, _ := startFake(t, map[string]string{"workspace/symbol": "error"})
+	var respErr *responseError
+	if _, err := client.WorkspaceSymbols(context.Background(), "x"); !errors.As(err, &respErr) {
+		t.Fatalf("WorkspaceSymbols() error = %v, want the server's error", err)
+	}
+	if err := client.closeFn(); err != nil {
+		t.Fatal(err)
+	}
+	<-client.Done()
+	if _, err := client.WorkspaceSymbols(context.Background(), "x"); !errors.Is(err, ErrClosed) {
+		t.Fatalf("WorkspaceSymbols() after exit = %v, want ErrClosed", err)
+	}
+}
+
+func TestDecodeLocations(t *testing.T) {
+	one, err := decodeLocations(json.RawMessage(`{"uri":"file:///a.go","range":{"start":{"line":1,"character":2},"end":{"line":1,"character":3}}}`))
+	if err != nil || len(one) != 1 || one[0].Path() != filepath.FromSlash("/a.go") {
+		t.Fatalf("single location = %+v, %v", one, err)
+	}
+	none, err := decodeLocations(json.RawMessage(`null`))
+	if err != nil || len(none) != 0 {
+		t.Fatalf("null = %+v, %v", none, err)
+	}
+}
+
+func TestServerFor(t *testing.T) {
+	orig := lookPath
+	t.Cleanup(func() { lookPath = orig })
+	lookPath = func(name string) (string, error) { return "/usr/bin/" + name, nil }
+
+	root := t.TempDir()
+	if _, err := ServerFor(root); !errors.Is(err, ErrNoServer) {
+		t.Fatalf("ServerFor() on an empty dir = %v, want ErrNoServer", err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "package.json"), []byte("{}"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if s, err := ServerFor(root); err != nil || s.Name != "typescript-language-server" {
+		t.Fatalf("ServerFor() = %+v, %v; want typescript-language-server", s, err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "go.mod"), []byte("module x\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if s, err := ServerFor(root); err != nil || s.Name != "gopls" {
+		t.Fatalf("ServerFor() = %+v, %v; want gopls first", s, err)
+	}
+	lookPath = func(string) (string, error) { return "", errors.New("not found") }
+	if _, err := ServerFor(root); !errors.Is(err, ErrNoServer) {
+		t.Fatalf("ServerFor() without gopls installed = %v, want ErrNoServer", err)
+	}
+}
@@ -0,0 +1,168 @@
+package lsp
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/andyrewlee/amux/internal/logging"
+	"github.com/andyrewlee/amux/internal/process"
+)
+
+// ErrNoServer is returned when no supported language server applies to a
+// worktree or is installed.
+var ErrNoServer = errors.New("no language server available")
+
+// initializeTimeout bounds the initialize handshake; servers index the
+// workspace lazily, so it is quick.
+const initializeTimeout = 30 * time.Second
+
+// shutdownTimeout bounds the shutdown handshake of each server.
+const shutdownTimeout = 2 * time.Second
+
+// Server is a language server command for a kind of project.
+type Server struct {
+	Name string
+	Args []string
+	// Markers are files whose presence at the worktree root selects this
+	// server.
+	Markers []string
+}
+
+// servers are the supported language servers, in order of preference.
+var servers = []Server{
+	{Name: "gopls", Markers: []string{"go.mod", "go.work"}},
+	{Name: "typescript-language-server", Args: []string{"--stdio"}, Markers: []string{"tsconfig.json", "jsconfig.json", "package.json"}},
+}
+
+// lookPath finds a server binary; tests replace it.
+var lookPath = exec.LookPath
+
+// ServerFor returns the installed language server for the project at root.
+func ServerFor(root string) (Server, error) {
+	for _, s := range servers {
+		for _, marker := range s.Markers {
+			if _, err := os.Stat(filepath.Join(root, marker)); err != nil {
+				continue
+			}
+			if _, err := lookPath(s.Name); err != nil {
+				return Server{}, errors.Join(ErrNoServer, err)
+			}
+			return s, nil
+		}
+	}
+	return Server{}, ErrNoServer
+}
+
+// Start runs server for root and initializes it.
+func Start(ctx context.Context, root string, server Server) (*Client, error) {
+	cmd := exec.Command(server.Name, server.Args...)
+	cmd.Dir = root
+	// Its own process group, so a server's helpers (tsserver) are killed
+	// with it.
+	process.SetProcessGroup(cmd)
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, err
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, err
+	}
+	client := newClient(root, stdout, stdin, func() error {
+		_ = stdin.Close()
+		done := make(chan error, 1)
+		go func() { done <- cmd.Wait() }()
+		select {
+		case err := <-done:
+			return err
+		case <-time.After(shutdownTimeout):
+			_ = process.ForceKillProcess(cmd.Process.Pid)
+			return <-done
+		}
+	})
+	ctx, cancel := context.WithTimeout(ctx, initializeTimeout)
+	defer cancel()
+	if err := client.Initialize(ctx); err != nil {
+		_ = process.ForceKillProcess(cmd.Process.Pid)
+		_ = client.closeFn()
+		return nil, err
+	}
+	return client, nil
+}
+
+// Manager keeps one language server per worktree, started on first use.
+type Manager struct {
+	mu      sync.Mutex
+	clients map[string]*Client
+}
+
+// NewManager returns an empty Manager.
+func NewManager() *Manager {
+	return &Manager{clients: make(map[string]*Client)}
+}
+
+// Client returns root's language server client, starting one if none is
+// running. A server that has exited is replaced.
+func (m *Manager) Client(ctx context.Context, root string) (*Client, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if c, ok := m.clients[root]; ok {
+		select {
+		case <-c.Done():
+			delete(m.clients, root)
+		default:
+			return c, nil
+		}
+	}
+	server, err := ServerFor(root)
+	if err != nil {
+		return nil, err
+	}
+	c, err := Start(ctx, root, server)
+	if err != nil {
+		return nil, err
+	}
+	logging.Info("Started %s for %s", server.Name, root)
+	m.clients[root] = c
+	return c, nil
+}
+
+// Stop shuts down root's language server, if one is running.
+func (m *Manager) Stop(root string) {
+	m.mu.Lock()
+	c, ok := m.clients[root]
+	delete(m.clients, root)
+	m.mu.Unlock()
+	if ok {
+		ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+		_ = c.Close(ctx)
+	}
+}
+
+// StopAll shuts down every language server.
+func (m *Manager) StopAll() {
+	m.mu.Lock()
+	roots := make([]string, 0, len(m.clients))
+	for root := range m.clients {
+		roots = append(roots, root)
+	}
+	m.mu.Unlock()
+	var wg sync.WaitGroup
+	for _, root := range roots {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			m.Stop(root)
+		}()
+	}
+	wg.Wait()
+}
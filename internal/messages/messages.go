@@ -56,6 +56,9 @@ type WorkspaceDeleted struct {
 	// Warning is a non-fatal note (e.g. the branch could not be deleted). The
 	// workspace delete still succeeded; this is surfaced to the user as a toast.
 	Warning string
+	// Trashed reports that the workspace's metadata was kept in the trash, so
+	// the delete can be undone.
+	Trashed bool
 }
 
 // WorkspaceDeleteFailed is sent when a workspace deletion fails
@@ -104,9 +107,12 @@ type TabCreated struct {
 	Name  string
 }
 
-// TabClosed is sent when a tab is closed
+// TabClosed is sent when a tab is closed. WorkspaceID and Assistant identify
+// what the tab ran so the close can be undone by relaunching it.
 type TabClosed struct {
-	Index int
+	Index       int
+	WorkspaceID string
+	Assistant   string
 }
 
 // TabDetached is sent when a tab is detached (tmux session remains).
@@ -230,6 +236,25 @@ type ShowRenameWorkspaceDialog struct {
 	Workspace *data.Workspace
 }
 
+// ShowOpenInDialog requests the "open worktree in…" picker for a workspace.
+type ShowOpenInDialog struct {
+	Workspace *data.Workspace
+}
+
+// ToggleFavorite requests marking or unmarking a project (when Workspace is
+// nil) or a workspace as a favorite.
+type ToggleFavorite struct {
+	Project   *data.Project
+	Workspace *data.Workspace
+}
+
+// ShowTrashDialog requests the trash view listing recently deleted workspaces.
+type ShowTrashDialog struct{}
+
+// Undo requests reversing the most recent undoable destructive action (tab
+// close, project removal, workspace delete).
+type Undo struct{}
+
 // ShowWorkspaceEnvDialog requests showing the workspace environment-variable
 // editor for the given workspace.
 type ShowWorkspaceEnvDialog struct {
@@ -308,6 +333,9 @@ type OpenDiff struct {
 	Change    *git.Change
 	Mode      git.DiffMode
 	Workspace *data.Workspace
+	// Compare, when set, shows how Change.Path differs between the two
+	// worktrees compared instead of the workspace's own changes.
+	Compare *git.Comparison
 }
 
 // CloseTab requests closing the current tab
@@ -396,4 +424,49 @@ type UpgradeComplete struct {
 type OpenFileInVim struct {
 	Path      string
 	Workspace *data.Workspace
+	// Line, when positive, is the line to open the file at.
+	Line int
+}
+
+// ShowDiffCommentDialog requests the input dialog for a review comment on a
+// line of a diff.
+type ShowDiffCommentDialog struct {
+	Workspace *data.Workspace
+	Path      string
+	// Line is the commented line in the new file, or in the old file when
+	// Removed is set. Zero comments on the whole file.
+	Line    int
+	Removed bool
+	// Code is the commented diff line, or the @@ header for a whole hunk.
+	Code string
+}
+
+// SendReviewComments requests sending a workspace's pending review comments
+// to its agent.
+type SendReviewComments struct {
+	Workspace *data.Workspace
+}
+
+// SendToAgent requests pasting Text into the agent tab of Workspace.
+type SendToAgent struct {
+	Workspace *data.Workspace
+	Text      string
+}
+
+// TakeFromComparison requests copying changes from the other worktree of a
+// comparison into Workspace: the hunk in Patch, or all of Path when Patch is
+// empty.
+type TakeFromComparison struct {
+	Workspace *data.Workspace
+	Compare   *git.Comparison
+	Path      string
+	Patch     string
+}
+
+// RunCommand requests running a shell command in a new center tab
+type RunCommand struct {
+	Command   string
+	Workspace *data.Workspace
+	// Name labels the tab; empty names it after the command's first word.
+	Name string
 }
@@ -0,0 +1,233 @@
+// Package monorepo reads a monorepo's packages from its build tool — Nx,
+// Turborepo, or Bazel — and suggests the ones a task touches, so a worktree
+// for the task can check out only those packages and their dependencies (git
+// sparse-checkout) and its agent can be told which targets are affected.
+package monorepo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/andyrewlee/amux/internal/git"
+)
+
+// Tool names a monorepo build tool.
+type Tool string
+
+const (
+	Nx    Tool = "nx"
+	Turbo Tool = "turbo"
+	Bazel Tool = "bazel"
+)
+
+// markers are the files at a repository's root that identify its tool, in
+// the order Detect checks them.
+var markers = []struct {
+	file string
+	tool Tool
+}{
+	{"nx.json", Nx},
+	{"turbo.json", Turbo},
+	{"MODULE.bazel", Bazel},
+	{"WORKSPACE.bazel", Bazel},
+	{"WORKSPACE", Bazel},
+}
+
+// Package is one project, workspace package, or Bazel package.
+type Package struct {
+	Name string
+	// Dir is the package's directory relative to the repository root,
+	// slash-separated.
+	Dir string
+	// Deps names the packages this one depends on, when the tool's listing
+	// reports them.
+	Deps []string
+}
+
+// Graph is a monorepo's packages.
+type Graph struct {
+	Tool     Tool
+	Root     string
+	Packages []Package
+}
+
+// Test seams for git and the build tools.
+var (
+	runGit  = git.RunGitCtx
+	runTool = func(ctx context.Context, dir, name string, args ...string) ([]byte, error) {
+		cmd := exec.CommandContext(ctx, name, args...)
+		cmd.Dir = dir
+		out, err := cmd.Output()
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && len(exitErr.Stderr) > 0 {
+			return out, fmt.Errorf("%s: %s", filepath.Base(name), lastLine(exitErr.Stderr))
+		}
+		return out, err
+	}
+	lookPath = exec.LookPath
+)
+
+// Detect returns the build tool of the repository at root, or "" when it is
+// not a monorepo amux knows.
+func Detect(root string) Tool {
+	for _, m := range markers {
+		if _, err := os.Stat(filepath.Join(root, m.file)); err == nil {
+			return m.tool
+		}
+	}
+	return ""
+}
+
+// Load lists the packages of the monorepo at root by asking its build tool.
+func Load(ctx context.Context, root string) (Graph, error) {
+	tool := Detect(root)
+	var pkgs []Package
+	var err error
+	switch tool {
+	case Nx:
+		pkgs, err = loadNx(ctx, root)
+	case Turbo:
+		pkgs, err = loadTurbo(ctx, root)
+	case Bazel:
+		pkgs, err = loadBazel(ctx, root)
+	default:
+		return Graph{}, fmt.Errorf("%s is not an Nx, Turborepo, or Bazel monorepo", root)
+	}
+	if err != nil {
+		return Graph{}, err
+	}
+	slices.SortFunc(pkgs, func(a, b Package) int { return strings.Compare(a.Name, b.Name) })
+	return Graph{Tool: tool, Root: root, Packages: pkgs}, nil
+}
+
+// Find returns the packages named in names, and the names that match none.
+func (g Graph) Find(names []string) (found []Package, unknown []string) {
+	for _, name := range names {
+		i := slices.IndexFunc(g.Packages, func(p Package) bool { return p.Name == name })
+		if i < 0 {
+			unknown = append(unknown, name)
+			continue
+		}
+		if !slices.ContainsFunc(found, func(p Package) bool { return p.Name == name }) {
+			found = append(found, g.Packages[i])
+		}
+	}
+	return found, unknown
+}
+
+// Scope returns the directories a worktree needs to build targets: theirs
+// and those of the packages they depend on, directly or not.
+func (g Graph) Scope(ctx context.Context, targets []Package) ([]string, error) {
+	if g.Tool == Bazel {
+		return bazelScope(ctx, g.Root, targets)
+	}
+	byName := make(map[string]Package, len(g.Packages))
+	for _, p := range g.Packages {
+		byName[p.Name] = p
+	}
+	seen := make(map[string]bool)
+	var dirs []string
+	var visit func(p Package)
+	visit = func(p Package) {
+		if seen[p.Name] {
+			return
+		}
+		seen[p.Name] = true
+		dirs = append(dirs, p.Dir)
+		for _, dep := range p.Deps {
+			if d, ok := byName[dep]; ok {
+				visit(d)
+			}
+		}
+	}
+	for _, p := range targets {
+		visit(p)
+	}
+	return compactDirs(dirs), nil
+}
+
+// SparseCheckout narrows the worktree at dir to dirs, plus the files at the
+// repository's root, with a cone-mode sparse checkout. More directories can
+// be added later with `git sparse-checkout add`.
+func SparseCheckout(ctx context.Context, dir string, dirs []string) error {
+	if len(dirs) == 0 {
+		return nil
+	}
+	args := append([]string{"sparse-checkout", "set", "--cone"}, dirs...)
+	_, err := runGit(ctx, dir, args...)
+	return err
+}
+
+// Prompt is the agent's first message for task: the task, then the targets
+// it was scoped to.
+func Prompt(task string, tool Tool, targets []Package, sparse bool) string {
+	task = strings.TrimSpace(task)
+	if len(targets) == 0 {
+		return task
+	}
+	var b strings.Builder
+	b.WriteString(task)
+	fmt.Fprintf(&b, "\n\nThis is %s monorepo. The targets this task likely touches:\n", toolArticle(tool))
+	for _, p := range targets {
+		fmt.Fprintf(&b, "- %s (%s)\n", p.Name, p.Dir)
+	}
+	if sparse {
+		b.WriteString("\nThis worktree checks out only these packages and their dependencies. " +
+			"Run `git sparse-checkout add <dir>` if you need another one.")
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
+
+func toolArticle(tool Tool) string {
+	switch tool {
+	case Nx:
+		return "an Nx"
+	case Turbo:
+		return "a Turborepo"
+	case Bazel:
+		return "a Bazel"
+	}
+	return "a"
+}
+
+// compactDirs sorts dirs and drops duplicates, the root, and directories
+// inside another one listed.
+func compactDirs(dirs []string) []string {
+	slices.Sort(dirs)
+	var out []string
+	for _, d := range dirs {
+		d = strings.Trim(filepath.ToSlash(d), "/")
+		if d == "" || d == "." {
+			continue
+		}
+		if n := len(out); n > 0 && (out[n-1] == d || strings.HasPrefix(d, out[n-1]+"/")) {
+			continue
+		}
+		out = append(out, d)
+	}
+	return out
+}
+
+// command returns how to run a JavaScript build tool: the repository's own
+// copy in node_modules/.bin, then one on PATH, then through npx.
+func command(root, name string) (string, []string) {
+	local := filepath.Join(root, "node_modules", ".bin", name)
+	if _, err := os.Stat(local); err == nil {
+		return local, nil
+	}
+	if path, err := lookPath(name); err == nil {
+		return path, nil
+	}
+	return "npx", []string{"--no-install", name}
+}
+
+func lastLine(b []byte) string {
+	lines := strings.Split(strings.TrimSpace(string(b)), "\n")
+	return strings.TrimSpace(lines[len(lines)-1])
+}
@@ -0,0 +1,177 @@
+package monorepo
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// stubTool answers build tool calls with out, and records their arguments.
+func stubTool(t *testing.T, out map[string]string) *[]string {
+	t.Helper()
+	oldRun, oldLook := runTool, lookPath
+	t.Cleanup(func() { runTool, lookPath = oldRun, oldLook })
+	lookPath = func(name string) (string, error) { return "", errors.New("not found") }
+	var calls []string
+	runTool = func(_ context.Context, _, name string, args ...string) ([]byte, error) {
+		call := filepath.Base(name) + " " + strings.Join(args, " ")
+		calls = append(calls, call)
+		for key, reply := range out {
+			if strings.Contains(call, key) {
+				return []byte(reply), nil
+			}
+		}
+		return nil, errors.New("unexpected " + call)
+	}
+	return &calls
+}
+
+func names(pkgs []Package) []string {
+	var out []string
+	for _, p := range pkgs {
+		out = append(out, p.Name)
+	}
+	return out
+}
+
+func TestDetect(t *testing.T) {
+	root := t.TempDir()
+	if tool := Detect(root); tool != "" {
+		t.Fatalf("Detect(empty) = %q", tool)
+	}
+	writeFile(t, filepath.Join(root, "WORKSPACE.bazel"), "")
+	if tool := Detect(root); tool != Bazel {
+		t.Fatalf("Detect(bazel) = %q", tool)
+	}
+	writeFile(t, filepath.Join(root, "nx.json"), "{}")
+	if tool := Detect(root); tool != Nx {
+		t.Fatalf("Detect(nx) = %q", tool)
+	}
+}
+
+func TestLoadNx(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, filepath.Join(root, "nx.json"), "{}")
+	calls := stubTool(t, map[string]string{"graph": `{"graph":{
+		"nodes":{"web":{"data":{"root":"apps/web"}},"ui":{"data":{"root":"libs/ui"}},"auth":{"data":{"root":"libs/auth"}}},
+		"dependencies":{"web":[{"target":"ui"},{"target":"npm:react"}],"ui":[{"target":"auth"}],"auth":[]}}}`})
+	g, err := Load(context.Background(), root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if (*calls)[0] != "npx --no-install nx graph --file=stdout" {
+		t.Fatalf("nx call = %q", (*calls)[0])
+	}
+	if got := names(g.Packages); !slices.Equal(got, []string{"auth", "ui", "web"}) {
+		t.Fatalf("packages = %v", got)
+	}
+	web, _ := g.Find([]string{"web"})
+	dirs, err := g.Scope(context.Background(), web)
+	if err != nil || !slices.Equal(dirs, []string{"apps/web", "libs/auth", "libs/ui"}) {
+		t.Fatalf("Scope(web) = %v, %v", dirs, err)
+	}
+}
+
+func TestLoadTurbo(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, filepath.Join(root, "turbo.json"), "{}")
+	writeFile(t, filepath.Join(root, "node_modules", ".bin", "turbo"), "")
+	writeFile(t, filepath.Join(root, "apps", "docs", "package.json"), `{"dependencies":{"@acme/ui":"*","react":"^19"}}`)
+	writeFile(t, filepath.Join(root, "packages", "ui", "package.json"), `{"devDependencies":{"typescript":"^5"}}`)
+	calls := stubTool(t, map[string]string{"ls": `{"packages":{"count":2,"items":[
+		{"name":"docs","path":"apps/docs"},{"name":"@acme/ui","path":"packages/ui"}]}}`})
+	g, err := Load(context.Background(), root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if (*calls)[0] != "turbo ls --output=json" {
+		t.Fatalf("turbo call = %q", (*calls)[0])
+	}
+	docs, unknown := g.Find([]string{"docs", "nope"})
+	if len(docs) != 1 || !slices.Equal(docs[0].Deps, []string{"@acme/ui"}) || !slices.Equal(unknown, []string{"nope"}) {
+		t.Fatalf("Find = %+v, %v", docs, unknown)
+	}
+	if dirs, _ := g.Scope(context.Background(), docs); !slices.Equal(dirs, []string{"apps/docs", "packages/ui"}) {
+		t.Fatalf("Scope(docs) = %v", dirs)
+	}
+}
+
+func TestLoadBazel(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, filepath.Join(root, "MODULE.bazel"), "")
+	calls := stubTool(t, map[string]string{
+		"query //...":         "services/api\nservices/api/handlers\nlib/log\n",
+		"query buildfiles(de": "services/api\nservices/api/handlers\nlib/log\n@rules_go//go\n",
+	})
+	g, err := Load(context.Background(), root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	api, _ := g.Find([]string{"//services/api"})
+	dirs, err := g.Scope(context.Background(), api)
+	if err != nil || !slices.Equal(dirs, []string{"lib/log", "services/api"}) {
+		t.Fatalf("Scope = %v, %v", dirs, err)
+	}
+	if !strings.Contains((*calls)[1], "buildfiles(deps(set(//services/api/...)))") {
+		t.Fatalf("bazel call = %q", (*calls)[1])
+	}
+}
+
+func TestSuggest(t *testing.T) {
+	g := Graph{Packages: []Package{
+		{Name: "web", Dir: "apps/web"},
+		{Name: "admin", Dir: "apps/admin"},
+		{Name: "auth", Dir: "libs/auth"},
+		{Name: "autocomplete", Dir: "libs/autocomplete"},
+		{Name: "parser", Dir: "libs/parser"},
+	}}
+	if got := names(g.Suggest("Fix the authentication redirect in the web app")); !slices.Equal(got, []string{"auth", "web"}) {
+		t.Fatalf("Suggest = %v", got)
+	}
+	if got := names(g.Suggest("Speed up parsing")); !slices.Equal(got, []string{"parser"}) {
+		t.Fatalf("Suggest(parsing) = %v", got)
+	}
+	if got := g.Suggest("fix it"); len(got) != 0 {
+		t.Fatalf("Suggest(stop words) = %v", got)
+	}
+}
+
+func TestSparseCheckoutAndPrompt(t *testing.T) {
+	old := runGit
+	t.Cleanup(func() { runGit = old })
+	var got []string
+	runGit = func(_ context.Context, dir string, args ...string) (string, error) {
+		got = append([]string{dir}, args...)
+		return "", nil
+	}
+	if err := SparseCheckout(context.Background(), "/wt", []string{"apps/web", "libs/ui"}); err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"/wt", "sparse-checkout", "set", "--cone", "apps/web", "libs/ui"}; !slices.Equal(got, want) {
+		t.Fatalf("git %v, want %v", got, want)
+	}
+
+	prompt := Prompt("Add dark mode", Nx, []Package{{Name: "web", Dir: "apps/web"}}, true)
+	for _, want := range []string{"Add dark mode\n\nThis is an Nx monorepo.", "- web (apps/web)", "git sparse-checkout add"} {
+		if !strings.Contains(prompt, want) {
+			t.Errorf("prompt %q lacks %q", prompt, want)
+		}
+	}
+	if got := Prompt(" Add dark mode ", Nx, nil, false); got != "Add dark mode" {
+		t.Fatalf("prompt without targets = %q", got)
+	}
+}
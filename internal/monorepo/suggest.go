@@ -0,0 +1,96 @@
+package monorepo
+
+import (
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// MaxSuggestions bounds the packages Suggest returns.
+const MaxSuggestions = 8
+
+// stopWords are task words too common to name a package.
+var stopWords = map[string]bool{
+	"add": true, "and": true, "app": true, "for": true, "fix": true, "from": true,
+	"into": true, "make": true, "new": true, "not": true, "the": true, "this": true,
+	"that": true, "update": true, "use": true, "when": true, "with": true,
+}
+
+// Suggest returns the packages task likely touches, best match first: those
+// whose name or directory shares words with the task.
+func (g Graph) Suggest(task string) []Package {
+	words := taskWords(task)
+	if len(words) == 0 {
+		return nil
+	}
+	lower := strings.ToLower(task)
+	type scored struct {
+		pkg   Package
+		score int
+	}
+	var matches []scored
+	for _, p := range g.Packages {
+		score := 0
+		pkgTokens := tokens(p.Name + " " + p.Dir)
+		slices.Sort(pkgTokens)
+		for _, token := range slices.Compact(pkgTokens) {
+			for _, w := range words {
+				if matchWord(w, token) {
+					score++
+				}
+			}
+		}
+		// The whole name in the task, as in "the web-admin app", counts most.
+		if base := strings.ToLower(strings.TrimPrefix(p.Name, "//")); len(base) > 2 && strings.Contains(lower, base) {
+			score += 2
+		}
+		if score > 0 {
+			matches = append(matches, scored{p, score})
+		}
+	}
+	slices.SortStableFunc(matches, func(a, b scored) int {
+		if a.score != b.score {
+			return b.score - a.score
+		}
+		return strings.Compare(a.pkg.Name, b.pkg.Name)
+	})
+	out := make([]Package, 0, min(len(matches), MaxSuggestions))
+	for _, m := range matches[:min(len(matches), MaxSuggestions)] {
+		out = append(out, m.pkg)
+	}
+	return out
+}
+
+// matchWord reports whether a task word names a package token: the same
+// word, or two that share all but the last two letters of the shorter one
+// and at least four letters (parser and parsing, auth and authentication,
+// but not auth and autocomplete).
+func matchWord(word, token string) bool {
+	if word == token {
+		return true
+	}
+	n := 0
+	for n < len(word) && n < len(token) && word[n] == token[n] {
+		n++
+	}
+	return n >= 4 && n >= min(len(word), len(token))-2
+}
+
+// taskWords splits a task into its lowercase words, without stop words and
+// words under three letters.
+func taskWords(task string) []string {
+	var words []string
+	for _, w := range tokens(task) {
+		if len(w) >= 3 && !stopWords[w] && !slices.Contains(words, w) {
+			words = append(words, w)
+		}
+	}
+	return words
+}
+
+// tokens splits s into lowercase runs of letters and digits.
+func tokens(s string) []string {
+	return strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
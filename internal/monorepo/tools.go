@@ -0,0 +1,149 @@
+package monorepo
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// loadNx reads the project graph Nx prints with `nx graph --file=stdout`.
+func loadNx(ctx context.Context, root string) ([]Package, error) {
+	name, args := command(root, "nx")
+	out, err := runTool(ctx, root, name, append(args, "graph", "--file=stdout")...)
+	if err != nil {
+		return nil, err
+	}
+	var resp struct {
+		Graph struct {
+			Nodes map[string]struct {
+				Data struct {
+					Root string `json:"root"`
+				} `json:"data"`
+			} `json:"nodes"`
+			Dependencies map[string][]struct {
+				Target string `json:"target"`
+			} `json:"dependencies"`
+		} `json:"graph"`
+	}
+	if err := json.Unmarshal(out, &resp); err != nil {
+		return nil, fmt.Errorf("parse nx graph: %w", err)
+	}
+	pkgs := make([]Package, 0, len(resp.Graph.Nodes))
+	for name, node := range resp.Graph.Nodes {
+		p := Package{Name: name, Dir: node.Data.Root}
+		for _, dep := range resp.Graph.Dependencies[name] {
+			// Targets outside the graph's nodes are npm packages.
+			if _, ok := resp.Graph.Nodes[dep.Target]; ok && dep.Target != name {
+				p.Deps = append(p.Deps, dep.Target)
+			}
+		}
+		pkgs = append(pkgs, p)
+	}
+	return pkgs, nil
+}
+
+// loadTurbo lists the workspace packages with `turbo ls` and reads each
+// one's package.json for its dependencies on the others.
+func loadTurbo(ctx context.Context, root string) ([]Package, error) {
+	name, args := command(root, "turbo")
+	out, err := runTool(ctx, root, name, append(args, "ls", "--output=json")...)
+	if err != nil {
+		return nil, err
+	}
+	var resp struct {
+		Packages struct {
+			Items []struct {
+				Name string `json:"name"`
+				Path string `json:"path"`
+			} `json:"items"`
+		} `json:"packages"`
+	}
+	if err := json.Unmarshal(out, &resp); err != nil {
+		return nil, fmt.Errorf("parse turbo ls: %w", err)
+	}
+	names := make(map[string]bool, len(resp.Packages.Items))
+	for _, item := range resp.Packages.Items {
+		names[item.Name] = true
+	}
+	pkgs := make([]Package, 0, len(resp.Packages.Items))
+	for _, item := range resp.Packages.Items {
+		p := Package{Name: item.Name, Dir: filepath.ToSlash(item.Path)}
+		for _, dep := range packageJSONDeps(filepath.Join(root, item.Path)) {
+			if names[dep] && dep != item.Name {
+				p.Deps = append(p.Deps, dep)
+			}
+		}
+		pkgs = append(pkgs, p)
+	}
+	return pkgs, nil
+}
+
+// packageJSONDeps lists every dependency a package.json declares, sorted.
+func packageJSONDeps(dir string) []string {
+	data, err := os.ReadFile(filepath.Join(dir, "package.json"))
+	if err != nil {
+		return nil
+	}
+	var manifest map[string]json.RawMessage
+	if json.Unmarshal(data, &manifest) != nil {
+		return nil
+	}
+	var deps []string
+	for _, field := range []string{"dependencies", "devDependencies", "peerDependencies"} {
+		var m map[string]string
+		if json.Unmarshal(manifest[field], &m) != nil {
+			continue
+		}
+		for dep := range m {
+			deps = append(deps, dep)
+		}
+	}
+	slices.Sort(deps)
+	return slices.Compact(deps)
+}
+
+// loadBazel lists the workspace's packages with `bazel query`. Their
+// dependencies are left to bazelScope, which asks Bazel for only the
+// targets chosen.
+func loadBazel(ctx context.Context, root string) ([]Package, error) {
+	out, err := runTool(ctx, root, "bazel", "query", "//...", "--output=package")
+	if err != nil {
+		return nil, err
+	}
+	var pkgs []Package
+	for dir := range strings.SplitSeq(strings.TrimSpace(string(out)), "\n") {
+		if dir = strings.TrimSpace(dir); dir != "" {
+			pkgs = append(pkgs, Package{Name: "//" + dir, Dir: dir})
+		}
+	}
+	return pkgs, nil
+}
+
+// bazelScope returns the packages whose BUILD files building targets reads.
+func bazelScope(ctx context.Context, root string, targets []Package) ([]string, error) {
+	if len(targets) == 0 {
+		return nil, nil
+	}
+	labels := make([]string, 0, len(targets))
+	dirs := make([]string, 0, len(targets))
+	for _, p := range targets {
+		labels = append(labels, "//"+p.Dir+"/...")
+		dirs = append(dirs, p.Dir)
+	}
+	query := "buildfiles(deps(set(" + strings.Join(labels, " ") + ")))"
+	out, err := runTool(ctx, root, "bazel", "query", query, "--output=package")
+	if err != nil {
+		return nil, err
+	}
+	for dir := range strings.SplitSeq(strings.TrimSpace(string(out)), "\n") {
+		// Packages in external repositories print as @repo//dir.
+		if dir = strings.TrimSpace(dir); dir != "" && !strings.HasPrefix(dir, "@") {
+			dirs = append(dirs, dir)
+		}
+	}
+	return compactDirs(dirs), nil
+}
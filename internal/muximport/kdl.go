@@ -0,0 +1,288 @@
+package muximport
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// kdlNode is a node of a KDL document, as zellij layouts are written.
+// Values are kept as text: strings unquoted, numbers and keywords as
+// written.
+type kdlNode struct {
+	name     string
+	args     []string
+	props    map[string]string
+	children []*kdlNode
+}
+
+// child returns n's first child named name, or nil.
+func (n *kdlNode) child(name string) *kdlNode {
+	for _, c := range n.children {
+		if c.name == name {
+			return c
+		}
+	}
+	return nil
+}
+
+type kdlParser struct {
+	src  []rune
+	pos  int
+	line int
+}
+
+// parseKDL parses src's top-level nodes. Type annotations are skipped and
+// slashdash-commented nodes, arguments, and blocks are dropped.
+func parseKDL(src string) ([]*kdlNode, error) {
+	p := &kdlParser{src: []rune(src), line: 1}
+	return p.nodes(false)
+}
+
+func (p *kdlParser) errorf(format string, args ...any) error {
+	return fmt.Errorf("line %d: %s", p.line, fmt.Sprintf(format, args...))
+}
+
+func (p *kdlParser) peek(offset int) rune {
+	if p.pos+offset >= len(p.src) {
+		return 0
+	}
+	return p.src[p.pos+offset]
+}
+
+func (p *kdlParser) next() rune {
+	r := p.src[p.pos]
+	p.pos++
+	if r == '\n' {
+		p.line++
+	}
+	return r
+}
+
+// nodes parses nodes up to the end of input or, in a block, its closing
+// brace.
+func (p *kdlParser) nodes(inBlock bool) ([]*kdlNode, error) {
+	var nodes []*kdlNode
+	for {
+		if err := p.skipSpace(true); err != nil {
+			return nil, err
+		}
+		switch {
+		case p.pos == len(p.src):
+			if inBlock {
+				return nil, p.errorf("missing }")
+			}
+			return nodes, nil
+		case p.peek(0) == '}':
+			if !inBlock {
+				return nil, p.errorf("unexpected }")
+			}
+			p.next()
+			return nodes, nil
+		}
+		dropped := p.slashdash()
+		n, err := p.node()
+		if err != nil {
+			return nil, err
+		}
+		if !dropped {
+			nodes = append(nodes, n)
+		}
+	}
+}
+
+// node parses a node's name, entries, and children.
+func (p *kdlParser) node() (*kdlNode, error) {
+	name, err := p.value()
+	if err != nil {
+		return nil, err
+	}
+	n := &kdlNode{name: name, props: make(map[string]string)}
+	for {
+		if err := p.skipSpace(false); err != nil {
+			return nil, err
+		}
+		if p.pos == len(p.src) {
+			return n, nil
+		}
+		switch p.peek(0) {
+		case '\n', ';':
+			p.next()
+			return n, nil
+		case '}':
+			return n, nil
+		}
+		dropped := p.slashdash()
+		if p.peek(0) == '{' {
+			p.next()
+			children, err := p.nodes(true)
+			if err != nil {
+				return nil, err
+			}
+			if !dropped {
+				n.children = children
+			}
+			continue
+		}
+		v, err := p.value()
+		if err != nil {
+			return nil, err
+		}
+		if p.peek(0) == '=' {
+			p.next()
+			pv, err := p.value()
+			if err != nil {
+				return nil, err
+			}
+			if !dropped {
+				n.props[v] = pv
+			}
+			continue
+		}
+		if !dropped {
+			n.args = append(n.args, v)
+		}
+	}
+}
+
+// slashdash consumes a "/-" comment marker, reporting whether there was one.
+func (p *kdlParser) slashdash() bool {
+	if p.peek(0) == '/' && p.peek(1) == '-' {
+		p.pos += 2
+		_ = p.skipSpace(false)
+		return true
+	}
+	return false
+}
+
+// skipSpace skips whitespace, comments, and line continuations, and
+// newlines and semicolons too when between nodes.
+func (p *kdlParser) skipSpace(newlines bool) error {
+	for p.pos < len(p.src) {
+		r := p.peek(0)
+		switch {
+		case r == '\n' || r == ';':
+			if !newlines {
+				return nil
+			}
+			p.next()
+		case unicode.IsSpace(r) || r == '\uFEFF':
+			p.next()
+		case r == '\\':
+			// A line continuation: skip to the next line.
+			for p.pos < len(p.src) && p.next() != '\n' {
+			}
+		case r == '/' && p.peek(1) == '/':
+			for p.pos < len(p.src) && p.peek(0) != '\n' {
+				p.next()
+			}
+		case r == '/' && p.peek(1) == '*':
+			if err := p.blockComment(); err != nil {
+				return err
+			}
+		default:
+			return nil
+		}
+	}
+	return nil
+}
+
+// blockComment skips a /* */ comment, which may nest.
+func (p *kdlParser) blockComment() error {
+	depth := 0
+	for p.pos < len(p.src) {
+		switch {
+		case p.peek(0) == '/' && p.peek(1) == '*':
+			depth++
+			p.pos += 2
+		case p.peek(0) == '*' && p.peek(1) == '/':
+			depth--
+			p.pos += 2
+			if depth == 0 {
+				return nil
+			}
+		default:
+			p.next()
+		}
+	}
+	return p.errorf("unterminated comment")
+}
+
+// value parses a string, raw string, or bare identifier, number, or
+// keyword, after any type annotation.
+func (p *kdlParser) value() (string, error) {
+	if p.peek(0) == '(' {
+		for p.pos < len(p.src) && p.next() != ')' {
+		}
+	}
+	switch r := p.peek(0); {
+	case r == '"':
+		return p.quoted()
+	case r == 'r' && (p.peek(1) == '"' || p.peek(1) == '#'):
+		p.next()
+		return p.raw()
+	case r == '#' && p.peek(1) == '"':
+		return p.raw()
+	}
+	start := p.pos
+	for p.pos < len(p.src) && !unicode.IsSpace(p.peek(0)) && !strings.ContainsRune(`(){}[]/\"=;`, p.peek(0)) {
+		p.pos++
+	}
+	if p.pos == start {
+		if p.pos == len(p.src) {
+			return "", p.errorf("unexpected end of layout")
+		}
+		return "", p.errorf("unexpected %q", p.peek(0))
+	}
+	// KDL 2 writes keywords such as true and null with a leading #.
+	return strings.TrimPrefix(string(p.src[start:p.pos]), "#"), nil
+}
+
+// quoted parses a "..." string with escapes.
+func (p *kdlParser) quoted() (string, error) {
+	start := p.pos
+	p.next()
+	for p.pos < len(p.src) {
+		switch p.next() {
+		case '\\':
+			if p.pos < len(p.src) {
+				p.next()
+			}
+		case '"':
+			text := string(p.src[start:p.pos])
+			// KDL's escapes are Go's, plus \/ for a slash.
+			text = strings.ReplaceAll(text, `\/`, "/")
+			s, err := strconv.Unquote(text)
+			if err != nil {
+				return "", p.errorf("bad string %s", text)
+			}
+			return s, nil
+		}
+	}
+	return "", p.errorf("unterminated string")
+}
+
+// raw parses a raw string, r"..." or r#"..."#, from its hashes.
+func (p *kdlParser) raw() (string, error) {
+	hashes := 0
+	for p.peek(0) == '#' {
+		hashes++
+		p.next()
+	}
+	if p.peek(0) != '"' {
+		return "", p.errorf("bad raw string")
+	}
+	p.next()
+	closing := "\"" + strings.Repeat("#", hashes)
+	start := p.pos
+	for p.pos < len(p.src) {
+		if strings.HasPrefix(string(p.src[p.pos:min(p.pos+len(closing), len(p.src))]), closing) {
+			s := string(p.src[start:p.pos])
+			p.pos += len(closing)
+			return s, nil
+		}
+		p.next()
+	}
+	return "", p.errorf("unterminated string")
+}
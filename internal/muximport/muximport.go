@@ -0,0 +1,91 @@
+// Package muximport converts other terminal multiplexers' session
+// definitions, tmuxinator projects and zellij layouts, into amux projects
+// with a startup layout, for `amux import`.
+//
+// amux tabs are not split, so every pane becomes a tab of its own, in the
+// order the windows and panes are defined. Panes running a command become
+// command tabs, or agent tabs when the command is just an assistant's name,
+// and empty panes become terminals. What cannot be carried over is listed
+// in the project's notes rather than failing the import.
+package muximport
+
+import (
+	"strings"
+
+	"github.com/andyrewlee/amux/internal/process"
+)
+
+// Project is an imported session definition.
+type Project struct {
+	// Name is the session's name, or "" when the format has none.
+	Name string
+	// Root is the project directory as written, which may start with "~"
+	// or be relative to where the multiplexer was started; "" when unset.
+	Root  string
+	Tabs  []process.LayoutTab
+	Notes []string
+}
+
+// builder collects a project's tabs, turning commands that name one of
+// agents into agent tabs.
+type builder struct {
+	project *Project
+	agents  map[string]bool
+	noted   map[string]bool
+}
+
+func newBuilder(agents []string) *builder {
+	b := &builder{project: &Project{}, agents: make(map[string]bool), noted: make(map[string]bool)}
+	for _, name := range agents {
+		b.agents[name] = true
+	}
+	return b
+}
+
+// pane adds a tab running the commands in order, or a terminal when there
+// are none. prefix holds commands run before them, such as changing
+// directory; a terminal cannot run them, so they are dropped and noted.
+func (b *builder) pane(prefix, commands []string) {
+	var cmds []string
+	for _, c := range commands {
+		if c = strings.TrimSpace(c); c != "" {
+			cmds = append(cmds, c)
+		}
+	}
+	switch {
+	case len(cmds) == 0:
+		b.project.Tabs = append(b.project.Tabs, process.LayoutTab{Terminal: true})
+		if len(prefix) > 0 {
+			b.note("terminal tabs open in the project root without running the commands set to run first")
+		}
+	case len(prefix) == 0 && len(cmds) == 1 && b.agents[cmds[0]]:
+		b.project.Tabs = append(b.project.Tabs, process.LayoutTab{Agent: cmds[0]})
+	default:
+		cmd := strings.Join(append(append([]string{}, prefix...), cmds...), "; ")
+		b.project.Tabs = append(b.project.Tabs, process.LayoutTab{Command: cmd})
+	}
+}
+
+// note records something the import could not carry over, once.
+func (b *builder) note(msg string) {
+	if !b.noted[msg] {
+		b.noted[msg] = true
+		b.project.Notes = append(b.project.Notes, msg)
+	}
+}
+
+// cdCommand changes to dir, leaving a leading "~/" for the shell to expand.
+func cdCommand(dir string) string {
+	if rest, ok := strings.CutPrefix(dir, "~/"); ok {
+		return "cd ~/" + shellQuote(rest)
+	}
+	return "cd " + shellQuote(dir)
+}
+
+// shellQuote quotes s for a POSIX shell when it is not a plain word.
+func shellQuote(s string) string {
+	if s != "" && strings.Trim(s, "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789-_./=:,+@%") == "" {
+		return s
+	}
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
@@ -0,0 +1,189 @@
+package muximport
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/andyrewlee/amux/internal/process"
+)
+
+var agents = []string{"claude", "codex"}
+
+const tmuxinatorProject = `# ~/.config/tmuxinator/api.yml
+name: api
+root: ~/src/api
+pre_window: nvm use
+on_project_start: docker compose up -d
+
+windows:
+  - agent: claude
+  - editor:
+      layout: main-vertical
+      panes:
+        - vim
+        - # an empty pane
+        - logs:
+          - cd log
+          - tail -f "development.log"   # follow
+  - server: bundle exec rails s
+  - shell:
+  - docs:
+      root: ~/src/api docs
+      pre: ['source .env', "echo ready"]
+      panes:
+        - make serve
+`
+
+func TestTmuxinator(t *testing.T) {
+	p, err := Tmuxinator([]byte(tmuxinatorProject), agents)
+	if err != nil {
+		t.Fatalf("Tmuxinator() error = %v", err)
+	}
+	if p.Name != "api" || p.Root != "~/src/api" {
+		t.Fatalf("name, root = %q, %q", p.Name, p.Root)
+	}
+	want := []process.LayoutTab{
+		{Command: "nvm use; claude"},
+		{Command: "nvm use; vim"},
+		{Terminal: true},
+		{Command: "nvm use; cd log; tail -f \"development.log\""},
+		{Command: "nvm use; bundle exec rails s"},
+		{Terminal: true},
+		{Command: "cd ~/'src/api docs'; nvm use; source .env; echo ready; make serve"},
+	}
+	if !reflect.DeepEqual(p.Tabs, want) {
+		t.Fatalf("tabs = %+v\nwant %+v", p.Tabs, want)
+	}
+	wantNotes := []string{
+		"on_project_start is not imported",
+		"terminal tabs open in the project root without running the commands set to run first",
+	}
+	if !reflect.DeepEqual(p.Notes, wantNotes) {
+		t.Fatalf("notes = %q, want %q", p.Notes, wantNotes)
+	}
+}
+
+func TestTmuxinatorAgents(t *testing.T) {
+	src := "project_name: web\nwindows:\n- claude: claude\n- review: codex --full-auto\n- 'quoted: name': \"claude\"\n"
+	p, err := Tmuxinator([]byte(src), agents)
+	if err != nil {
+		t.Fatalf("Tmuxinator() error = %v", err)
+	}
+	want := []process.LayoutTab{{Agent: "claude"}, {Command: "codex --full-auto"}, {Agent: "claude"}}
+	if p.Name != "web" || !reflect.DeepEqual(p.Tabs, want) {
+		t.Fatalf("project = %+v, want tabs %+v", p, want)
+	}
+}
+
+func TestTmuxinatorErrors(t *testing.T) {
+	for _, tc := range []struct{ src, want string }{
+		{"name: api\n", "no windows"},
+		{"- a\n- b\n", "expected a mapping"},
+		{"windows:\n  - a: b\n     c: d\n", "line 3: unexpected indent"},
+		{"windows:\n\t- a\n", "line 2: indent with spaces"},
+		{"windows:\n  - a: {layout: tiled}\n", "flow mappings are not supported"},
+		{"windows:\n  - plain\n", "window 1: expected"},
+	} {
+		if _, err := Tmuxinator([]byte(tc.src), nil); err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Errorf("Tmuxinator(%q) error = %v, want %q", tc.src, err, tc.want)
+		}
+	}
+}
+
+func TestParseYAMLBlockScalars(t *testing.T) {
+	doc, err := parseYAML("literal: |\n  one\n  two\nfolded: >\n  a\n  b\nafter: x # note\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := scalar(doc.get("literal")); got != "one\ntwo" {
+		t.Fatalf("literal = %q", got)
+	}
+	if got := scalar(doc.get("folded")); got != "a b" {
+		t.Fatalf("folded = %q", got)
+	}
+	if got := scalar(doc.get("after")); got != "x" {
+		t.Fatalf("after = %q", got)
+	}
+}
+
+const zellijLayout = `
+layout {
+    cwd "/src/api"
+    default_tab_template {
+        pane size=1 borderless=true { plugin location="zellij:tab-bar"; }
+        children
+    }
+    tab name="agents" focus=true {
+        pane split_direction="vertical" {
+            pane command="claude"
+            pane command="codex" {
+                args "--model" "o3 mini"
+            }
+        }
+    }
+    tab name="dev" cwd="web" {
+        pane command="npm" { args "run" "dev"; }
+        pane
+        /- pane command="ignored"
+        pane edit="src/index.ts" cwd="/src/api"
+    }
+    tab name="empty"
+    floating_panes {
+        pane command=r#"htop"#
+    }
+}
+`
+
+func TestZellij(t *testing.T) {
+	p, err := Zellij([]byte(zellijLayout), agents)
+	if err != nil {
+		t.Fatalf("Zellij() error = %v", err)
+	}
+	if p.Root != "/src/api" {
+		t.Fatalf("root = %q", p.Root)
+	}
+	want := []process.LayoutTab{
+		{Agent: "claude"},
+		{Command: "codex --model 'o3 mini'"},
+		{Command: "cd /src/api/web; npm run dev"},
+		{Terminal: true},
+		{Command: "${EDITOR:-vi} src/index.ts"},
+		{Terminal: true},
+		{Command: "htop"},
+	}
+	if !reflect.DeepEqual(p.Tabs, want) {
+		t.Fatalf("tabs = %+v\nwant %+v", p.Tabs, want)
+	}
+	wantNotes := []string{"terminal tabs open in the project root without running the commands set to run first"}
+	if !reflect.DeepEqual(p.Notes, wantNotes) {
+		t.Fatalf("notes = %q, want %q", p.Notes, wantNotes)
+	}
+}
+
+func TestZellijErrors(t *testing.T) {
+	for _, tc := range []struct{ src, want string }{
+		{"pane\n", "no layout node"},
+		{"layout {\n  pane size=1 { plugin location=\"zellij:status-bar\" }\n}\n", "no panes"},
+		{"layout {\n  pane command=\"x\n}\n", "unterminated string"},
+		{"layout {\n  pane\n", "line 3: missing }"},
+	} {
+		if _, err := Zellij([]byte(tc.src), nil); err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Errorf("Zellij(%q) error = %v, want %q", tc.src, err, tc.want)
+		}
+	}
+}
+
+func TestParseKDL(t *testing.T) {
+	nodes, err := parseKDL("/* a /* nested */ comment */ node 1 \"two\\/2\" (u8)3 key=#true \\\n  more // trailing\nnext; last")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(nodes) != 3 || nodes[1].name != "next" || nodes[2].name != "last" {
+		t.Fatalf("nodes = %+v", nodes)
+	}
+	n := nodes[0]
+	if !reflect.DeepEqual(n.args, []string{"1", "two/2", "3", "more"}) || n.props["key"] != "true" {
+		t.Fatalf("node = %+v", n)
+	}
+}
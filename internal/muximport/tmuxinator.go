@@ -0,0 +1,129 @@
+package muximport
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// tmuxinatorKeys are the project keys the import understands; any other
+// key is noted as not imported.
+var tmuxinatorKeys = map[string]bool{
+	"name": true, "project_name": true, "root": true, "project_root": true,
+	"windows": true, "tabs": true, "pre_window": true, "pre_tab": true,
+	// Only change how tmux itself is set up.
+	"tmux_options": true, "tmux_command": true, "socket_name": true, "attach": true,
+	"startup_window": true, "startup_pane": true, "enable_pane_titles": true,
+}
+
+// Tmuxinator converts a tmuxinator project file. Each window's panes
+// become tabs; a window without panes is one tab running the window's
+// commands. pre_window commands and a window's pre commands run first in
+// each command tab, and a window root that differs from the project's is
+// changed to first. Commands that name one of agents become agent tabs.
+func Tmuxinator(src []byte, agents []string) (*Project, error) {
+	doc, err := parseYAML(string(src))
+	if err != nil {
+		return nil, fmt.Errorf("parse tmuxinator project: %w", err)
+	}
+	if doc.kind != yamlMap {
+		return nil, errors.New("parse tmuxinator project: expected a mapping of project settings")
+	}
+	b := newBuilder(agents)
+	p := b.project
+	p.Name = firstScalar(doc, "name", "project_name")
+	p.Root = firstScalar(doc, "root", "project_root")
+	if strings.Contains(string(src), "<%") {
+		b.note("ERB tags (<% %>) are not evaluated; check values that used them")
+	}
+	for _, key := range doc.keys {
+		if !tmuxinatorKeys[key] {
+			b.note(fmt.Sprintf("%s is not imported", key))
+		}
+	}
+	windows := doc.get("windows")
+	if windows == nil {
+		windows = doc.get("tabs")
+	}
+	if windows == nil || windows.kind != yamlList || len(windows.items) == 0 {
+		return nil, errors.New("tmuxinator project has no windows")
+	}
+	preWindow := scalars(doc.get("pre_window"))
+	if preWindow == nil {
+		preWindow = scalars(doc.get("pre_tab"))
+	}
+	for i, w := range windows.items {
+		if w.kind != yamlMap || len(w.keys) != 1 {
+			return nil, fmt.Errorf("tmuxinator window %d: expected \"name: commands\"", i+1)
+		}
+		if err := tmuxinatorWindow(b, w.keys[0], w.values[0], p.Root, preWindow); err != nil {
+			return nil, err
+		}
+	}
+	return p, nil
+}
+
+// tmuxinatorWindow adds a window's tabs. Its value is its commands, or a
+// mapping with its panes.
+func tmuxinatorWindow(b *builder, name string, w *yamlNode, root string, preWindow []string) error {
+	prefix := append([]string{}, preWindow...)
+	if w.kind != yamlMap {
+		b.pane(prefix, scalars(w))
+		return nil
+	}
+	if r := scalar(w.get("root")); r != "" && r != root {
+		prefix = append([]string{cdCommand(r)}, prefix...)
+	}
+	prefix = append(prefix, scalars(w.get("pre"))...)
+	panes := w.get("panes")
+	if panes == nil || panes.kind == yamlNull {
+		b.pane(prefix, nil)
+		return nil
+	}
+	if panes.kind != yamlList {
+		return fmt.Errorf("tmuxinator window %q: panes must be a list", name)
+	}
+	for _, pane := range panes.items {
+		cmds := scalars(pane)
+		// A pane may be named: "- title: commands".
+		if pane.kind == yamlMap && len(pane.keys) == 1 {
+			cmds = scalars(pane.values[0])
+		}
+		b.pane(prefix, cmds)
+	}
+	return nil
+}
+
+// scalar returns a scalar node's text, or "".
+func scalar(n *yamlNode) string {
+	if n == nil || n.kind != yamlScalar {
+		return ""
+	}
+	return n.value
+}
+
+// scalars returns a scalar as one command, or a list's scalars in order.
+func scalars(n *yamlNode) []string {
+	if n == nil {
+		return nil
+	}
+	if n.kind == yamlScalar {
+		return []string{n.value}
+	}
+	var out []string
+	if n.kind == yamlList {
+		for _, item := range n.items {
+			out = append(out, scalars(item)...)
+		}
+	}
+	return out
+}
+
+func firstScalar(n *yamlNode, keys ...string) string {
+	for _, key := range keys {
+		if v := scalar(n.get(key)); v != "" {
+			return v
+		}
+	}
+	return ""
+}
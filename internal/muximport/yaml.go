@@ -0,0 +1,320 @@
+package muximport
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// yamlKind is the kind of a yamlNode.
+type yamlKind int
+
+const (
+	yamlNull yamlKind = iota
+	yamlScalar
+	yamlList
+	yamlMap
+)
+
+// yamlNode is a value in the subset of YAML tmuxinator projects are
+// written in: block mappings and lists, plain and quoted scalars, block
+// scalars, and flow lists of scalars. Anchors, tags, and flow mappings are
+// not supported.
+type yamlNode struct {
+	kind   yamlKind
+	value  string
+	items  []*yamlNode
+	keys   []string
+	values []*yamlNode
+}
+
+// get returns the value of key in a mapping, or nil.
+func (n *yamlNode) get(key string) *yamlNode {
+	if n == nil {
+		return nil
+	}
+	for i, k := range n.keys {
+		if k == key {
+			return n.values[i]
+		}
+	}
+	return nil
+}
+
+// yamlLine is a line of the document: its indent, its text after the
+// indent, and its 1-based number.
+type yamlLine struct {
+	indent int
+	text   string
+	num    int
+}
+
+type yamlParser struct {
+	lines []yamlLine
+	pos   int
+}
+
+// parseYAML parses src, which holds one document.
+func parseYAML(src string) (*yamlNode, error) {
+	p := &yamlParser{}
+	for i, raw := range strings.Split(strings.ReplaceAll(src, "\r\n", "\n"), "\n") {
+		text := strings.TrimRight(raw, " \t")
+		trimmed := strings.TrimLeft(text, " ")
+		if strings.HasPrefix(trimmed, "\t") {
+			return nil, fmt.Errorf("line %d: indent with spaces, not tabs", i+1)
+		}
+		if text == "---" || text == "..." || strings.HasPrefix(text, "%") {
+			continue
+		}
+		p.lines = append(p.lines, yamlLine{indent: len(text) - len(trimmed), text: trimmed, num: i + 1})
+	}
+	p.skipBlank()
+	if p.pos == len(p.lines) {
+		return &yamlNode{}, nil
+	}
+	node, err := p.node(p.lines[p.pos].indent)
+	if err != nil {
+		return nil, err
+	}
+	if p.skipBlank(); p.pos < len(p.lines) {
+		return nil, fmt.Errorf("line %d: unexpected indent", p.lines[p.pos].num)
+	}
+	return node, nil
+}
+
+// skipBlank moves past empty and comment-only lines.
+func (p *yamlParser) skipBlank() {
+	for p.pos < len(p.lines) && (p.lines[p.pos].text == "" || strings.HasPrefix(p.lines[p.pos].text, "#")) {
+		p.pos++
+	}
+}
+
+// node parses the block value whose first line, the current one, is at
+// indent.
+func (p *yamlParser) node(indent int) (*yamlNode, error) {
+	line := p.lines[p.pos]
+	if isListItem(line.text) {
+		return p.list(indent)
+	}
+	if _, _, ok := splitKey(line.text); ok {
+		return p.mapping(indent)
+	}
+	p.pos++
+	return yamlValue(line.text, line.num)
+}
+
+func isListItem(text string) bool {
+	return text == "-" || strings.HasPrefix(text, "- ")
+}
+
+func (p *yamlParser) list(indent int) (*yamlNode, error) {
+	n := &yamlNode{kind: yamlList}
+	for p.skipBlank(); p.pos < len(p.lines); p.skipBlank() {
+		line := p.lines[p.pos]
+		if line.indent != indent || !isListItem(line.text) {
+			break
+		}
+		rest := strings.TrimLeft(strings.TrimPrefix(line.text, "-"), " ")
+		var item *yamlNode
+		var err error
+		if rest == "" || strings.HasPrefix(rest, "#") {
+			p.pos++
+			item, err = p.child(indent, false)
+		} else {
+			// The item's value starts on this line, at the column after
+			// the dash; lines under it at that column continue it.
+			p.lines[p.pos] = yamlLine{indent: indent + len(line.text) - len(rest), text: rest, num: line.num}
+			item, err = p.node(p.lines[p.pos].indent)
+		}
+		if err != nil {
+			return nil, err
+		}
+		n.items = append(n.items, item)
+	}
+	return n, nil
+}
+
+func (p *yamlParser) mapping(indent int) (*yamlNode, error) {
+	n := &yamlNode{kind: yamlMap}
+	for p.skipBlank(); p.pos < len(p.lines); p.skipBlank() {
+		line := p.lines[p.pos]
+		if line.indent != indent {
+			if line.indent > indent {
+				return nil, fmt.Errorf("line %d: unexpected indent", line.num)
+			}
+			break
+		}
+		key, rest, ok := splitKey(line.text)
+		if !ok {
+			return nil, fmt.Errorf("line %d: expected \"key: value\"", line.num)
+		}
+		p.pos++
+		var value *yamlNode
+		var err error
+		switch {
+		case rest == "" || strings.HasPrefix(rest, "#"):
+			value, err = p.child(indent, true)
+		case rest[0] == '|' || rest[0] == '>':
+			value = p.blockScalar(indent, rest[0] == '|')
+		default:
+			value, err = yamlValue(rest, line.num)
+		}
+		if err != nil {
+			return nil, err
+		}
+		n.keys = append(n.keys, key)
+		n.values = append(n.values, value)
+	}
+	return n, nil
+}
+
+// child parses the block value under a key or dash at indent, or returns
+// null when there is none. A mapping's list value may sit at the key's own
+// indent.
+func (p *yamlParser) child(indent int, inMapping bool) (*yamlNode, error) {
+	if p.skipBlank(); p.pos == len(p.lines) {
+		return &yamlNode{}, nil
+	}
+	line := p.lines[p.pos]
+	if line.indent > indent || (inMapping && line.indent == indent && isListItem(line.text)) {
+		return p.node(line.indent)
+	}
+	return &yamlNode{}, nil
+}
+
+// blockScalar reads the lines indented under a key as text, kept as lines
+// when literal and folded into one line otherwise.
+func (p *yamlParser) blockScalar(indent int, literal bool) *yamlNode {
+	var lines []string
+	for ; p.pos < len(p.lines); p.pos++ {
+		line := p.lines[p.pos]
+		if line.text != "" && line.indent <= indent {
+			break
+		}
+		lines = append(lines, line.text)
+	}
+	sep := " "
+	if literal {
+		sep = "\n"
+	}
+	return &yamlNode{kind: yamlScalar, value: strings.TrimSpace(strings.Join(lines, sep))}
+}
+
+// splitKey splits a "key: value" line. The key may be quoted.
+func splitKey(text string) (key, rest string, ok bool) {
+	if text != "" && (text[0] == '"' || text[0] == '\'') {
+		end := strings.IndexByte(text[1:], text[0])
+		if end < 0 {
+			return "", "", false
+		}
+		key, text = text[1:end+1], text[end+2:]
+		if !strings.HasPrefix(text, ":") || (len(text) > 1 && text[1] != ' ') {
+			return "", "", false
+		}
+		return key, strings.TrimSpace(text[1:]), true
+	}
+	i := strings.Index(text, ": ")
+	if j := strings.Index(text, " #"); j >= 0 && (i < 0 || j < i) {
+		text = strings.TrimRight(text[:j], " ")
+		i = strings.Index(text, ": ")
+	}
+	switch {
+	case i > 0:
+		return text[:i], strings.TrimSpace(text[i+2:]), true
+	case strings.HasSuffix(text, ":") && len(text) > 1:
+		return text[:len(text)-1], "", true
+	}
+	return "", "", false
+}
+
+// yamlValue parses a value written on one line: a scalar or a flow list.
+func yamlValue(text string, num int) (*yamlNode, error) {
+	text = stripComment(text)
+	switch {
+	case text == "" || text == "~" || text == "null" || text == "{}":
+		return &yamlNode{}, nil
+	case text[0] == '{':
+		return nil, fmt.Errorf("line %d: flow mappings are not supported", num)
+	case text[0] == '[':
+		if !strings.HasSuffix(text, "]") {
+			return nil, fmt.Errorf("line %d: unterminated list", num)
+		}
+		n := &yamlNode{kind: yamlList}
+		for _, item := range splitFlow(text[1 : len(text)-1]) {
+			v, err := yamlValue(item, num)
+			if err != nil {
+				return nil, err
+			}
+			n.items = append(n.items, v)
+		}
+		return n, nil
+	}
+	s, err := unquote(text)
+	if err != nil {
+		return nil, fmt.Errorf("line %d: %w", num, err)
+	}
+	return &yamlNode{kind: yamlScalar, value: s}, nil
+}
+
+// stripComment drops a trailing " # comment" outside quotes.
+func stripComment(text string) string {
+	var quote byte
+	for i := 0; i < len(text); i++ {
+		c := text[i]
+		switch {
+		case quote != 0:
+			if c == '\\' && quote == '"' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			if i == 0 || text[i-1] == ' ' || text[i-1] == '[' || text[i-1] == ',' {
+				quote = c
+			}
+		case c == '#' && (i == 0 || text[i-1] == ' '):
+			return strings.TrimSpace(text[:i])
+		}
+	}
+	return strings.TrimSpace(text)
+}
+
+// splitFlow splits a flow list's contents at commas outside quotes.
+func splitFlow(text string) []string {
+	var items []string
+	var quote byte
+	start := 0
+	for i := 0; i < len(text); i++ {
+		c := text[i]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == ',':
+			items = append(items, strings.TrimSpace(text[start:i]))
+			start = i + 1
+		}
+	}
+	if last := strings.TrimSpace(text[start:]); last != "" || len(items) > 0 {
+		items = append(items, last)
+	}
+	return items
+}
+
+// unquote returns a scalar's text.
+func unquote(text string) (string, error) {
+	switch {
+	case len(text) >= 2 && text[0] == '\'' && text[len(text)-1] == '\'':
+		return strings.ReplaceAll(text[1:len(text)-1], "''", "'"), nil
+	case text[0] == '"':
+		s, err := strconv.Unquote(text)
+		if err != nil {
+			return "", fmt.Errorf("bad quoted string %s", text)
+		}
+		return s, nil
+	}
+	return text, nil
+}
@@ -0,0 +1,113 @@
+package muximport
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+)
+
+// zellijSkipped are layout nodes whose panes are not imported: templates
+// and swap layouts.
+var zellijSkipped = map[string]bool{
+	"default_tab_template": true, "new_tab_template": true, "tab_template": true,
+	"pane_template": true, "swap_tiled_layout": true, "swap_floating_layout": true,
+}
+
+// Zellij converts a zellij KDL layout. Every pane that is not a plugin,
+// in tabs and floating panes alike, becomes a tab: a command pane a tab
+// running the command and its args, an edit pane a tab opening the file in
+// $EDITOR, and any other pane a terminal. A pane whose cwd differs from
+// the layout's changes to it first. Commands that name one of agents
+// become agent tabs.
+func Zellij(src []byte, agents []string) (*Project, error) {
+	nodes, err := parseKDL(string(src))
+	if err != nil {
+		return nil, fmt.Errorf("parse zellij layout: %w", err)
+	}
+	var layout *kdlNode
+	for _, n := range nodes {
+		if n.name == "layout" {
+			layout = n
+		}
+	}
+	if layout == nil {
+		return nil, errors.New("zellij layout has no layout node")
+	}
+	b := newBuilder(agents)
+	if cwd := layout.child("cwd"); cwd != nil && len(cwd.args) > 0 {
+		b.project.Root = cwd.args[0]
+	}
+	for _, n := range layout.children {
+		switch {
+		case zellijSkipped[n.name]:
+			if n.name == "pane_template" || n.name == "tab_template" {
+				b.note("pane and tab templates are not expanded; their panes are not imported")
+			}
+		case n.name == "tab":
+			before := len(b.project.Tabs)
+			zellijPanes(b, n.children, joinCwd(b.project.Root, n.props["cwd"]), b.project.Root)
+			// zellij opens a shell in a tab that lists no panes of its own.
+			if len(b.project.Tabs) == before {
+				b.pane(nil, nil)
+			}
+		default:
+			zellijPanes(b, []*kdlNode{n}, b.project.Root, b.project.Root)
+		}
+	}
+	if len(b.project.Tabs) == 0 {
+		return nil, errors.New("zellij layout has no panes")
+	}
+	return b.project, nil
+}
+
+// zellijPanes adds the panes among nodes, and the panes nested in them,
+// in order. cwd is the directory their parent opens in and root the
+// project's.
+func zellijPanes(b *builder, nodes []*kdlNode, cwd, root string) {
+	for _, n := range nodes {
+		if n.name != "pane" && n.name != "floating_panes" {
+			continue
+		}
+		dir := joinCwd(cwd, n.props["cwd"])
+		if n.child("pane") != nil || n.name == "floating_panes" {
+			zellijPanes(b, n.children, dir, root)
+			continue
+		}
+		if n.props["plugin"] != "" || n.child("plugin") != nil {
+			continue
+		}
+		var prefix []string
+		if dir != root && dir != "" {
+			prefix = []string{cdCommand(dir)}
+		}
+		var cmd string
+		switch {
+		case n.props["command"] != "":
+			words := []string{shellQuote(n.props["command"])}
+			if args := n.child("args"); args != nil {
+				for _, a := range args.args {
+					words = append(words, shellQuote(a))
+				}
+			}
+			cmd = strings.Join(words, " ")
+		case n.props["edit"] != "":
+			cmd = "${EDITOR:-vi} " + shellQuote(n.props["edit"])
+		}
+		if cmd != "" && n.props["start_suspended"] == "true" {
+			b.note("start_suspended is not imported; command tabs start their commands at once")
+		}
+		b.pane(prefix, []string{cmd})
+	}
+}
+
+// joinCwd resolves a pane's cwd against its parent's, as zellij does.
+func joinCwd(parent, cwd string) string {
+	switch {
+	case cwd == "":
+		return parent
+	case parent == "" || path.IsAbs(cwd) || strings.HasPrefix(cwd, "~"):
+		return cwd
+	}
+	return path.Join(parent, cwd)
+}
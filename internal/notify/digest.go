@@ -0,0 +1,173 @@
+package notify
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// maxDigestLines bounds the events listed in a digest's body.
+const maxDigestLines = 5
+
+// Policy says how a Digester batches and paces notifications.
+type Policy struct {
+	// Window is how long after a delivery further notifications are held
+	// and then delivered together as one digest. Zero delivers each one as
+	// it comes.
+	Window time.Duration
+	// Channel is the backend notifications are delivered through; rate
+	// limits are counted per channel.
+	Channel Backend
+	// PerMinute caps deliveries on Channel in any minute. Zero is
+	// unlimited.
+	PerMinute int
+	// Quiet holds every notification during do-not-disturb hours.
+	Quiet QuietHours
+}
+
+// Digester batches notifications so that many agents finishing together
+// raise one notification rather than a flood. The first notification is
+// delivered at once and opens a window; what arrives inside it, or while rate
+// limited or in quiet hours, is held and delivered as one digest when that
+// ends. The zero value is ready to use. It is not safe for concurrent use.
+type Digester struct {
+	pending   []Notification
+	windowEnd time.Time
+	sent      map[Backend][]time.Time
+}
+
+// Offer adds n and returns what to deliver now, if anything, and when Flush
+// should next be called for the notifications still held (zero when none
+// are).
+func (d *Digester) Offer(n Notification, p Policy, now time.Time) (Notification, bool, time.Time) {
+	d.pending = append(d.pending, n)
+	return d.Flush(p, now)
+}
+
+// Flush delivers the held notifications as one digest if p allows it at now,
+// otherwise it returns when to try again.
+func (d *Digester) Flush(p Policy, now time.Time) (Notification, bool, time.Time) {
+	if len(d.pending) == 0 {
+		return Notification{}, false, time.Time{}
+	}
+	if end, quiet := p.Quiet.End(now); quiet {
+		return Notification{}, false, end
+	}
+	if now.Before(d.windowEnd) {
+		return Notification{}, false, d.windowEnd
+	}
+	if next := d.nextSlot(p, now); next.After(now) {
+		return Notification{}, false, next
+	}
+	out := Summarize(d.pending)
+	d.pending = nil
+	d.windowEnd = now.Add(p.Window)
+	if d.sent == nil {
+		d.sent = make(map[Backend][]time.Time)
+	}
+	d.sent[p.Channel] = append(d.sent[p.Channel], now)
+	return out, true, time.Time{}
+}
+
+// Pending counts the notifications held.
+func (d *Digester) Pending() int {
+	return len(d.pending)
+}
+
+// nextSlot returns when p.Channel may deliver again under its rate limit,
+// forgetting deliveries more than a minute old.
+func (d *Digester) nextSlot(p Policy, now time.Time) time.Time {
+	sent := d.sent[p.Channel]
+	for len(sent) > 0 && now.Sub(sent[0]) >= time.Minute {
+		sent = sent[1:]
+	}
+	if d.sent != nil {
+		d.sent[p.Channel] = sent
+	}
+	if p.PerMinute <= 0 || len(sent) < p.PerMinute {
+		return now
+	}
+	return sent[len(sent)-p.PerMinute].Add(time.Minute)
+}
+
+// Summarize folds ns into one notification: ns[0] itself when it is alone,
+// otherwise a count with the first few bodies.
+func Summarize(ns []Notification) Notification {
+	if len(ns) == 1 {
+		return ns[0]
+	}
+	out := Notification{Event: ns[0].Event, Title: fmt.Sprintf("amux: %d agent events", len(ns))}
+	for _, n := range ns {
+		if n.Event != out.Event {
+			out.Event = ""
+			break
+		}
+	}
+	var lines []string
+	for _, n := range ns[:min(len(ns), maxDigestLines)] {
+		lines = append(lines, n.Body)
+	}
+	if extra := len(ns) - maxDigestLines; extra > 0 {
+		lines = append(lines, fmt.Sprintf("and %d more", extra))
+	}
+	out.Body = strings.Join(lines, "; ")
+	return out
+}
+
+// QuietHours is a daily do-not-disturb span in local time. The zero value is
+// never quiet.
+type QuietHours struct {
+	start, end time.Duration // since midnight
+	set        bool
+}
+
+// ParseQuietHours parses "HH:MM-HH:MM", such as "22:00-07:30"; the span may
+// cross midnight. Empty is never quiet.
+func ParseQuietHours(s string) (QuietHours, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return QuietHours{}, nil
+	}
+	from, to, ok := strings.Cut(s, "-")
+	if !ok {
+		return QuietHours{}, fmt.Errorf("quiet hours %q: want HH:MM-HH:MM", s)
+	}
+	start, err := parseClock(from)
+	if err != nil {
+		return QuietHours{}, fmt.Errorf("quiet hours %q: %w", s, err)
+	}
+	end, err := parseClock(to)
+	if err != nil {
+		return QuietHours{}, fmt.Errorf("quiet hours %q: %w", s, err)
+	}
+	if start == end {
+		return QuietHours{}, fmt.Errorf("quiet hours %q: start and end are the same", s)
+	}
+	return QuietHours{start: start, end: end, set: true}, nil
+}
+
+func parseClock(s string) (time.Duration, error) {
+	t, err := time.Parse("15:04", strings.TrimSpace(s))
+	if err != nil {
+		return 0, fmt.Errorf("%q is not a HH:MM time", strings.TrimSpace(s))
+	}
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
+
+// End reports whether now falls in the quiet hours and, if so, when they end.
+func (q QuietHours) End(now time.Time) (time.Time, bool) {
+	if !q.set {
+		return time.Time{}, false
+	}
+	midnight := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	at := now.Sub(midnight)
+	switch {
+	case q.start < q.end && at >= q.start && at < q.end:
+		return midnight.Add(q.end), true
+	case q.start > q.end && at >= q.start:
+		return midnight.AddDate(0, 0, 1).Add(q.end), true
+	case q.start > q.end && at < q.end:
+		return midnight.Add(q.end), true
+	}
+	return time.Time{}, false
+}
@@ -0,0 +1,131 @@
+package notify
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func done(ws string) Notification {
+	return Notification{Event: EventDone, Title: "amux: agent finished", Body: ws + " is done"}
+}
+
+func TestDigesterWithoutPolicyDeliversEach(t *testing.T) {
+	var d Digester
+	now := time.Date(2026, 3, 2, 12, 0, 0, 0, time.UTC)
+	for _, ws := range []string{"a", "b", "c"} {
+		out, ok, next := d.Offer(done(ws), Policy{}, now)
+		if !ok || out.Body != ws+" is done" || !next.IsZero() {
+			t.Fatalf("Offer(%s) = %+v, %v, %v; want it delivered as is", ws, out, ok, next)
+		}
+	}
+}
+
+func TestDigesterBatchesWithinWindow(t *testing.T) {
+	var d Digester
+	p := Policy{Window: 30 * time.Second}
+	now := time.Date(2026, 3, 2, 12, 0, 0, 0, time.UTC)
+
+	if _, ok, _ := d.Offer(done("a"), p, now); !ok {
+		t.Fatal("the first notification should be delivered at once")
+	}
+	if _, ok, next := d.Offer(done("b"), p, now.Add(5*time.Second)); ok || !next.Equal(now.Add(30*time.Second)) {
+		t.Fatalf("second notification: ok=%v next=%v, want it held until the window ends", ok, next)
+	}
+	d.Offer(Notification{Event: EventExit, Body: "claude in c"}, p, now.Add(10*time.Second))
+	if d.Pending() != 2 {
+		t.Fatalf("Pending() = %d, want 2", d.Pending())
+	}
+	if _, ok, _ := d.Flush(p, now.Add(20*time.Second)); ok {
+		t.Fatal("Flush inside the window delivered")
+	}
+	out, ok, next := d.Flush(p, now.Add(30*time.Second))
+	if !ok || !next.IsZero() || d.Pending() != 0 {
+		t.Fatalf("Flush after the window = %+v, %v, %v", out, ok, next)
+	}
+	if out.Title != "amux: 2 agent events" || out.Body != "b is done; claude in c" || out.Event != "" {
+		t.Fatalf("digest = %+v", out)
+	}
+}
+
+func TestDigesterRateLimitsPerChannel(t *testing.T) {
+	var d Digester
+	p := Policy{Channel: Auto, PerMinute: 2}
+	now := time.Date(2026, 3, 2, 12, 0, 0, 0, time.UTC)
+
+	d.Offer(done("a"), p, now)
+	d.Offer(done("b"), p, now.Add(10*time.Second))
+	if _, ok, next := d.Offer(done("c"), p, now.Add(20*time.Second)); ok || !next.Equal(now.Add(time.Minute)) {
+		t.Fatalf("third notification: ok=%v next=%v, want it held a minute after the first", ok, next)
+	}
+	// Another channel has its own budget.
+	if out, ok, _ := d.Flush(Policy{Channel: Terminal, PerMinute: 2}, now.Add(20*time.Second)); !ok || out.Body != "c is done" {
+		t.Fatalf("terminal flush = %+v, %v", out, ok)
+	}
+	d.Offer(done("d"), p, now.Add(30*time.Second))
+	if out, ok, _ := d.Flush(p, now.Add(time.Minute)); !ok || out.Body != "d is done" {
+		t.Fatalf("flush once the rate allows = %+v, %v", out, ok)
+	}
+}
+
+func TestDigesterHoldsDuringQuietHours(t *testing.T) {
+	quiet, err := ParseQuietHours("22:00-07:30")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var d Digester
+	p := Policy{Quiet: quiet}
+	night := time.Date(2026, 3, 2, 23, 15, 0, 0, time.UTC)
+	morning := time.Date(2026, 3, 3, 7, 30, 0, 0, time.UTC)
+
+	if _, ok, next := d.Offer(done("a"), p, night); ok || !next.Equal(morning) {
+		t.Fatalf("night offer: ok=%v next=%v, want held until %v", ok, next, morning)
+	}
+	d.Offer(done("b"), p, night.Add(time.Hour))
+	if _, ok, _ := d.Flush(p, morning.Add(-time.Minute)); ok {
+		t.Fatal("delivered before the quiet hours ended")
+	}
+	if out, ok, _ := d.Flush(p, morning); !ok || out.Event != EventDone || !strings.Contains(out.Body, "a is done; b is done") {
+		t.Fatalf("morning digest = %+v, %v", out, ok)
+	}
+}
+
+func TestSummarizeTruncates(t *testing.T) {
+	var ns []Notification
+	for _, ws := range []string{"a", "b", "c", "d", "e", "f", "g"} {
+		ns = append(ns, done(ws))
+	}
+	out := Summarize(ns)
+	if out.Title != "amux: 7 agent events" || out.Event != EventDone || !strings.HasSuffix(out.Body, "e is done; and 2 more") {
+		t.Fatalf("Summarize = %+v", out)
+	}
+}
+
+func TestQuietHours(t *testing.T) {
+	day := func(h, m int) time.Time { return time.Date(2026, 3, 2, h, m, 0, 0, time.UTC) }
+	lunch, err := ParseQuietHours("12:00-13:00")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if end, ok := lunch.End(day(12, 30)); !ok || !end.Equal(day(13, 0)) {
+		t.Fatalf("lunch.End(12:30) = %v, %v", end, ok)
+	}
+	if _, ok := lunch.End(day(13, 0)); ok {
+		t.Fatal("the end time should not be quiet")
+	}
+	overnight, _ := ParseQuietHours("22:00-07:00")
+	if end, ok := overnight.End(day(6, 0)); !ok || !end.Equal(day(7, 0)) {
+		t.Fatalf("overnight.End(06:00) = %v, %v", end, ok)
+	}
+	if _, ok := overnight.End(day(12, 0)); ok {
+		t.Fatal("midday should not be quiet overnight")
+	}
+	if _, ok := (QuietHours{}).End(day(3, 0)); ok {
+		t.Fatal("the zero value should never be quiet")
+	}
+	for _, bad := range []string{"22:00", "25:00-07:00", "09:00-09:00", "late-early"} {
+		if _, err := ParseQuietHours(bad); err == nil {
+			t.Errorf("ParseQuietHours(%q) succeeded", bad)
+		}
+	}
+}
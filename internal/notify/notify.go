@@ -0,0 +1,177 @@
+// Package notify delivers notifications about agents to the user: as a
+// desktop notification (osascript on macOS, notify-send or D-Bus on Linux),
+// as an OSC 9/777 terminal notification, or as a plain terminal bell. It also
+// draws the attention indicator on amux's terminal tab.
+//
+// Desktop delivery runs an external command and so must happen off the UI
+// goroutine; terminal delivery returns an escape sequence for the caller to
+// write to the outer terminal. A Digester batches and paces notifications
+// before delivery.
+package notify
+
+import (
+	"context"
+	"os/exec"
+	"runtime"
+	"strings"
+	"time"
+)
+
+// Backend names a way of delivering notifications.
+type Backend string
+
+const (
+	// Bell rings the terminal bell, with no text.
+	Bell Backend = "bell"
+	// Terminal sends an OSC 9 or OSC 777 notification through the terminal.
+	Terminal Backend = "terminal"
+	// Auto raises a desktop notification, falling back to Terminal when no
+	// notifier is installed.
+	Auto Backend = "auto"
+)
+
+// ParseBackend returns the backend named by s; "desktop" is Auto, and empty
+// and unknown names are Bell.
+func ParseBackend(s string) Backend {
+	switch b := Backend(strings.ToLower(strings.TrimSpace(s))); b {
+	case Terminal, Auto:
+		return b
+	case "desktop":
+		return Auto
+	}
+	return Bell
+}
+
+// Event identifies what a notification is about, for per-event toggles.
+type Event string
+
+const (
+	// EventAttention: an agent needs the user, e.g. it reached a limit.
+	EventAttention Event = "attention"
+	// EventExit: an agent's session ended.
+	EventExit Event = "exit"
+	// EventDone: an agent finished working.
+	EventDone Event = "done"
+)
+
+// Notification is one message to deliver.
+type Notification struct {
+	Event Event
+	Title string
+	Body  string
+}
+
+const desktopTimeout = 5 * time.Second
+
+var (
+	goos     = runtime.GOOS
+	lookPath = exec.LookPath
+	runCmd   = func(ctx context.Context, name string, args ...string) error {
+		return exec.CommandContext(ctx, name, args...).Run()
+	}
+)
+
+// Desktop raises a desktop notification and reports whether a notifier was
+// found to do it. It blocks for up to a few seconds.
+func Desktop(title, body string) (bool, error) {
+	for _, c := range desktopCommands(goos, title, body) {
+		if _, err := lookPath(c[0]); err != nil {
+			continue
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), desktopTimeout)
+		err := runCmd(ctx, c[0], c[1:]...)
+		cancel()
+		return true, err
+	}
+	return false, nil
+}
+
+// desktopCommands lists the commands that can raise a notification on goos,
+// in order of preference.
+func desktopCommands(goos, title, body string) [][]string {
+	switch goos {
+	case "darwin":
+		script := "display notification " + appleScriptString(body) + " with title " + appleScriptString(title)
+		return [][]string{{"osascript", "-e", script}}
+	case "linux", "freebsd", "openbsd", "netbsd":
+		return [][]string{
+			{"notify-send", "--app-name=amux", title, body},
+			{"gdbus", "call", "--session",
+				"--dest", "org.freedesktop.Notifications",
+				"--object-path", "/org/freedesktop/Notifications",
+				"--method", "org.freedesktop.Notifications.Notify",
+				"amux", "0", "", title, body, "[]", "{}", "-1"},
+		}
+	}
+	return nil
+}
+
+// appleScriptString quotes s as an AppleScript string literal.
+func appleScriptString(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+}
+
+// Sequence returns the escape sequence that delivers n through the terminal
+// for backend b: OSC 777 on terminals known to want it (foot, rxvt, VTE),
+// OSC 9 elsewhere, or BEL for Bell. Inside tmux the sequence is wrapped for
+// passthrough, which needs tmux's allow-passthrough option. getenv is
+// typically os.Getenv.
+func Sequence(b Backend, n Notification, getenv func(string) string) string {
+	if b == Bell {
+		return "\a"
+	}
+	title, body := sanitize(n.Title), sanitize(n.Body)
+	var seq string
+	if wantsOSC777(getenv) {
+		seq = "\x1b]777;notify;" + title + ";" + body + "\x1b\\"
+	} else {
+		text := title
+		if body != "" {
+			text += ": " + body
+		}
+		seq = "\x1b]9;" + text + "\x1b\\"
+	}
+	return passthrough(seq, getenv)
+}
+
+// BadgeSequence returns the escape sequence that shows (on) or clears the
+// attention indicator on the terminal's tab: an OSC 9;4 progress state,
+// which Windows Terminal, Ghostty, and ConEmu draw on the tab. Inside tmux
+// it is wrapped for passthrough like Sequence.
+func BadgeSequence(on bool, getenv func(string) string) string {
+	seq := "\x1b]9;4;0;0\x1b\\"
+	if on {
+		seq = "\x1b]9;4;4;100\x1b\\"
+	}
+	return passthrough(seq, getenv)
+}
+
+// passthrough wraps seq for tmux to hand to the outer terminal.
+func passthrough(seq string, getenv func(string) string) string {
+	if getenv("TMUX") != "" {
+		seq = "\x1bPtmux;" + strings.ReplaceAll(seq, "\x1b", "\x1b\x1b") + "\x1b\\"
+	}
+	return seq
+}
+
+func wantsOSC777(getenv func(string) string) bool {
+	if getenv("VTE_VERSION") != "" {
+		return true
+	}
+	term := getenv("TERM")
+	return strings.HasPrefix(term, "foot") || strings.HasPrefix(term, "rxvt")
+}
+
+// sanitize drops control characters and the ';' OSC 777 uses as a field
+// separator, so text can't end the sequence early.
+func sanitize(s string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r == ';':
+			return ','
+		case r < 0x20, r == 0x7f, r >= 0x80 && r < 0xa0:
+			return ' '
+		}
+		return r
+	}, s)
+}
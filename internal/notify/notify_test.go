@@ -0,0 +1,90 @@
+package notify
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestDesktopCommands(t *testing.T) {
+	cmds := desktopCommands("darwin", "amux: deps failed", `say "hi"`)
+	if len(cmds) != 1 || cmds[0][0] != "osascript" || cmds[0][2] != `display notification "say \"hi\"" with title "amux: deps failed"` {
+		t.Fatalf("desktopCommands(darwin) = %q", cmds)
+	}
+	cmds = desktopCommands("linux", "t", "b")
+	if len(cmds) != 2 || cmds[0][0] != "notify-send" || cmds[1][0] != "gdbus" {
+		t.Fatalf("desktopCommands(linux) = %q, want notify-send then gdbus", cmds)
+	}
+	if cmds := desktopCommands("plan9", "t", "b"); cmds != nil {
+		t.Fatalf("desktopCommands(plan9) = %q, want none", cmds)
+	}
+}
+
+func TestDesktopFallsBackToGdbus(t *testing.T) {
+	origGOOS, origLookPath, origRun := goos, lookPath, runCmd
+	t.Cleanup(func() { goos, lookPath, runCmd = origGOOS, origLookPath, origRun })
+
+	goos = "linux"
+	lookPath = func(name string) (string, error) {
+		if name == "gdbus" {
+			return "/usr/bin/gdbus", nil
+		}
+		return "", errors.New("not found")
+	}
+	var ran string
+	runCmd = func(_ context.Context, name string, _ ...string) error {
+		ran = name
+		return nil
+	}
+	if ok, err := Desktop("t", "b"); !ok || err != nil || ran != "gdbus" {
+		t.Fatalf("Desktop() = %v, %v after running %q; want gdbus", ok, err, ran)
+	}
+
+	lookPath = func(string) (string, error) { return "", errors.New("not found") }
+	if ok, _ := Desktop("t", "b"); ok {
+		t.Fatal("Desktop() should report no notifier when none is installed")
+	}
+}
+
+func TestSequence(t *testing.T) {
+	env := func(vars map[string]string) func(string) string {
+		return func(k string) string { return vars[k] }
+	}
+	n := Notification{Title: "amux", Body: "claude; done\a"}
+
+	if got := Sequence(Bell, n, env(nil)); got != "\a" {
+		t.Fatalf("Sequence(Bell) = %q", got)
+	}
+	if got := Sequence(Terminal, n, env(nil)); got != "\x1b]9;amux: claude, done \x1b\\" {
+		t.Fatalf("Sequence(Terminal) = %q, want a sanitized OSC 9", got)
+	}
+	if got := Sequence(Terminal, n, env(map[string]string{"TERM": "foot"})); got != "\x1b]777;notify;amux;claude, done \x1b\\" {
+		t.Fatalf("Sequence(Terminal) on foot = %q, want OSC 777", got)
+	}
+	got := Sequence(Auto, Notification{Title: "t"}, env(map[string]string{"TMUX": "/tmp/tmux-0/default,1,0"}))
+	if got != "\x1bPtmux;\x1b\x1b]9;t\x1b\x1b\\\x1b\\" {
+		t.Fatalf("Sequence() inside tmux = %q, want passthrough", got)
+	}
+}
+
+func TestBadgeSequence(t *testing.T) {
+	none := func(string) string { return "" }
+	if got := BadgeSequence(true, none); got != "\x1b]9;4;4;100\x1b\\" {
+		t.Fatalf("BadgeSequence(true) = %q", got)
+	}
+	if got := BadgeSequence(false, none); got != "\x1b]9;4;0;0\x1b\\" {
+		t.Fatalf("BadgeSequence(false) = %q", got)
+	}
+	tmux := func(k string) string { return map[string]string{"TMUX": "/tmp/tmux-0/default,1,0"}[k] }
+	if got := BadgeSequence(false, tmux); got != "\x1bPtmux;\x1b\x1b]9;4;0;0\x1b\x1b\\\x1b\\" {
+		t.Fatalf("BadgeSequence() inside tmux = %q, want passthrough", got)
+	}
+}
+
+func TestParseBackend(t *testing.T) {
+	for in, want := range map[string]Backend{"": Bell, "bell": Bell, "Auto": Auto, "terminal": Terminal, "desktop": Auto, "loud": Bell} {
+		if got := ParseBackend(in); got != want {
+			t.Errorf("ParseBackend(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
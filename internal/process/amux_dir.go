@@ -0,0 +1,29 @@
+package process
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// readAmuxFile reads name from dir's .amux directory without following links
+// out of it.
+func readAmuxFile(dir, name string) ([]byte, error) {
+	root, err := os.OpenRoot(filepath.Join(dir, ".amux"))
+	if err != nil {
+		return nil, err
+	}
+	data, readErr := root.ReadFile(name)
+	closeErr := root.Close()
+	if readErr != nil {
+		if closeErr != nil {
+			return nil, errors.Join(readErr, fmt.Errorf("close workspace config directory: %w", closeErr))
+		}
+		return nil, readErr
+	}
+	if closeErr != nil {
+		return nil, fmt.Errorf("close workspace config directory: %w", closeErr)
+	}
+	return data, nil
+}
@@ -0,0 +1,141 @@
+package process
+
+import (
+	"context"
+	"errors"
+	"os"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/andyrewlee/amux/internal/data"
+	"github.com/andyrewlee/amux/internal/logging"
+	"github.com/andyrewlee/amux/internal/testrun"
+)
+
+// ErrNoChecks is returned when a project's .amux/workspaces.json defines no
+// checks.
+var ErrNoChecks = errors.New("no checks configured")
+
+// ChecksParallelEnvVar overrides how many checks run at once.
+const ChecksParallelEnvVar = "AMUX_CHECKS_PARALLEL"
+
+// Check is one named build, lint, or typecheck command.
+type Check struct {
+	Name    string `json:"name"`
+	Command string `json:"command"`
+}
+
+// CheckSet is a project's checks and the environment to run them in.
+type CheckSet struct {
+	Checks []Check
+	// Required reports whether the project blocks committing until every
+	// check passes.
+	Required bool
+	Env      []string
+}
+
+// CheckResult is the outcome of running one check.
+type CheckResult struct {
+	Check
+	ExitCode int
+	// Output is the tail of the check's combined output.
+	Output   string
+	Duration time.Duration
+	// Err is set when the check could not be started.
+	Err error
+}
+
+// Passed reports whether the check ran and succeeded.
+func (c CheckResult) Passed() bool {
+	return c.Err == nil && c.ExitCode == 0
+}
+
+// Checks returns ws's configured checks. Like the setup scripts they come from
+// the repository and are gated behind the repo's script trust; on a trust
+// error the returned set still reports Required, since honoring it runs
+// nothing.
+func (r *ScriptRunner) Checks(ws *data.Workspace) (*CheckSet, error) {
+	if err := validateScriptWorkspace(ws); err != nil {
+		return nil, err
+	}
+	config, raw, err := r.loadConfigRaw(ws.Repo)
+	if err != nil {
+		return nil, err
+	}
+	set := &CheckSet{Required: config.RequireChecks}
+	for _, check := range config.Checks {
+		check.Command = strings.TrimSpace(check.Command)
+		if check.Command == "" {
+			continue
+		}
+		if check.Name = strings.TrimSpace(check.Name); check.Name == "" {
+			check.Name = strings.Fields(check.Command)[0]
+		}
+		set.Checks = append(set.Checks, check)
+	}
+	if len(set.Checks) == 0 {
+		return set, ErrNoChecks
+	}
+	if !r.trust.IsTrusted(ws.Repo, raw) {
+		return set, &ScriptsNotTrustedError{
+			Repo:       ws.Repo,
+			Command:    set.Checks[0].Command,
+			ConfigHash: hashConfig(raw),
+		}
+	}
+	set.Env = r.envBuilder.BuildEnv(ws)
+	return set, nil
+}
+
+// RunChecks runs set's checks in dir and returns their results in the order
+// they were configured. Checks run concurrently, but never more at once, across
+// all workspaces, than the runner's parallelism limit.
+func (r *ScriptRunner) RunChecks(ctx context.Context, dir string, set *CheckSet) []CheckResult {
+	if set == nil {
+		return nil
+	}
+	slots := r.checkSlots
+	if slots == nil {
+		slots = make(chan struct{}, checksParallelism())
+	}
+	results := make([]CheckResult, len(set.Checks))
+	var wg sync.WaitGroup
+	for i, check := range set.Checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i] = CheckResult{Check: check}
+			select {
+			case slots <- struct{}{}:
+				defer func() { <-slots }()
+			case <-ctx.Done():
+				results[i].Err = ctx.Err()
+				return
+			}
+			start := time.Now()
+			res, err := testrun.Run(ctx, dir, check.Command, set.Env)
+			results[i].ExitCode = res.ExitCode
+			results[i].Output = res.Output
+			results[i].Duration = time.Since(start)
+			results[i].Err = err
+		}()
+	}
+	wg.Wait()
+	return results
+}
+
+// checksParallelism is how many checks may run at once: half the CPUs by
+// default, since build and lint tools are themselves parallel.
+func checksParallelism() int {
+	if value := strings.TrimSpace(os.Getenv(ChecksParallelEnvVar)); value != "" {
+		n, err := strconv.Atoi(value)
+		if err == nil && n > 0 {
+			return n
+		}
+		logging.Warn("Invalid %s=%q; using the default", ChecksParallelEnvVar, value)
+	}
+	return max(1, runtime.NumCPU()/2)
+}
@@ -0,0 +1,72 @@
+package process
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/andyrewlee/amux/internal/data"
+)
+
+func TestChecks(t *testing.T) {
+	repo := t.TempDir()
+	runner := NewScriptRunner(6200, 10)
+	useTempTrust(t, runner)
+	ws := &data.Workspace{Name: "ws", Repo: repo, Root: t.TempDir()}
+
+	if _, err := runner.Checks(ws); !errors.Is(err, ErrNoChecks) {
+		t.Fatalf("Checks() with no config = %v, want ErrNoChecks", err)
+	}
+	writeWorkspaceConfig(t, repo, `{"require-checks": true, "checks": [
+		{"name": "build", "command": "make build"},
+		{"name": "empty", "command": "  "},
+		{"command": "golangci-lint run"}
+	]}`)
+	set, err := runner.Checks(ws)
+	if !errors.Is(err, ErrScriptsNotTrusted) {
+		t.Fatalf("Checks() on an untrusted repo = %v, want ErrScriptsNotTrusted", err)
+	}
+	if set == nil || !set.Required {
+		t.Fatal("Checks() on an untrusted repo should still report Required")
+	}
+	trustRepo(t, runner, repo)
+	set, err = runner.Checks(ws)
+	if err != nil {
+		t.Fatalf("Checks() error = %v", err)
+	}
+	want := []Check{{Name: "build", Command: "make build"}, {Name: "golangci-lint", Command: "golangci-lint run"}}
+	if len(set.Checks) != len(want) || set.Checks[0] != want[0] || set.Checks[1] != want[1] {
+		t.Fatalf("Checks() = %+v, want %+v", set.Checks, want)
+	}
+}
+
+func TestRunChecks(t *testing.T) {
+	runner := NewScriptRunner(6200, 10)
+	runner.checkSlots = make(chan struct{}, 1)
+	set := &CheckSet{Checks: []Check{
+		{Name: "ok", Command: "echo fine"},
+		{Name: "bad", Command: "echo broken >&2; exit 3"},
+	}}
+	results := runner.RunChecks(context.Background(), t.TempDir(), set)
+	if len(results) != 2 {
+		t.Fatalf("RunChecks() returned %d results, want 2", len(results))
+	}
+	if !results[0].Passed() || results[0].Name != "ok" {
+		t.Fatalf("first result = %+v, want ok to pass", results[0])
+	}
+	if results[1].Passed() || results[1].ExitCode != 3 || !strings.Contains(results[1].Output, "broken") {
+		t.Fatalf("second result = %+v, want bad to fail with its output", results[1])
+	}
+}
+
+func TestChecksParallelism(t *testing.T) {
+	t.Setenv(ChecksParallelEnvVar, "3")
+	if got := checksParallelism(); got != 3 {
+		t.Fatalf("checksParallelism() = %d, want 3", got)
+	}
+	t.Setenv(ChecksParallelEnvVar, "zero")
+	if got := checksParallelism(); got < 1 {
+		t.Fatalf("checksParallelism() with a bad value = %d, want the default", got)
+	}
+}
@@ -0,0 +1,104 @@
+package process
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/andyrewlee/amux/internal/data"
+	"github.com/andyrewlee/amux/internal/logging"
+)
+
+// ErrNoColumns is returned when a project's .amux/workspaces.json defines no
+// dashboard columns.
+var ErrNoColumns = errors.New("no dashboard columns configured")
+
+const (
+	defaultColumnInterval = time.Minute
+	minColumnInterval     = 10 * time.Second
+	defaultColumnTimeout  = 10 * time.Second
+	maxColumnTimeout      = time.Minute
+)
+
+// Column is an extra dashboard column: the first line a command prints in
+// each worktree, such as its version or TODO count, shown next to it.
+type Column struct {
+	Name    string `json:"name"`
+	Command string `json:"command"`
+	// Interval is how often the command reruns, as a Go duration ("5m").
+	Interval string `json:"interval,omitempty"`
+	// Timeout bounds one run, as a Go duration ("10s").
+	Timeout string `json:"timeout,omitempty"`
+}
+
+// RefreshInterval returns how often the column reruns: its interval, a
+// minute when it has none, and never more often than every 10 seconds.
+func (c Column) RefreshInterval() time.Duration {
+	return parseColumnDuration(c, "interval", c.Interval, defaultColumnInterval, minColumnInterval, 0)
+}
+
+// RunTimeout returns how long one run may take: its timeout, 10 seconds
+// when it has none, and at most a minute.
+func (c Column) RunTimeout() time.Duration {
+	return parseColumnDuration(c, "timeout", c.Timeout, defaultColumnTimeout, 0, maxColumnTimeout)
+}
+
+func parseColumnDuration(c Column, field, value string, def, lo, hi time.Duration) time.Duration {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return def
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil || d <= 0 {
+		logging.Debug("Dashboard column %q: invalid %s %q; using %s", c.Name, field, value, def)
+		return def
+	}
+	d = max(d, lo)
+	if hi > 0 {
+		d = min(d, hi)
+	}
+	return d
+}
+
+// ColumnSet is a project's dashboard columns and the environment to run
+// them in for one worktree.
+type ColumnSet struct {
+	Columns []Column
+	Env     []string
+}
+
+// Columns returns the dashboard columns configured for ws's project. Like
+// the checks they come from the repository, so they are gated behind the
+// repo's script trust.
+func (r *ScriptRunner) Columns(ws *data.Workspace) (*ColumnSet, error) {
+	if err := validateScriptWorkspace(ws); err != nil {
+		return nil, err
+	}
+	config, raw, err := r.loadConfigRaw(ws.Repo)
+	if err != nil {
+		return nil, err
+	}
+	set := &ColumnSet{}
+	for _, column := range config.Columns {
+		column.Command = strings.TrimSpace(column.Command)
+		if column.Command == "" {
+			continue
+		}
+		if column.Name = strings.TrimSpace(column.Name); column.Name == "" {
+			column.Name = strings.Fields(column.Command)[0]
+		}
+		set.Columns = append(set.Columns, column)
+	}
+	if len(set.Columns) == 0 {
+		return set, ErrNoColumns
+	}
+	if !r.trust.IsTrusted(ws.Repo, raw) {
+		return set, &ScriptsNotTrustedError{
+			Repo:       ws.Repo,
+			Command:    set.Columns[0].Command,
+			ConfigHash: hashConfig(raw),
+		}
+	}
+	set.Env = r.envBuilder.BuildEnv(ws)
+	return set, nil
+}
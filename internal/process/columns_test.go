@@ -0,0 +1,45 @@
+package process
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/andyrewlee/amux/internal/data"
+)
+
+func TestColumns(t *testing.T) {
+	repo := t.TempDir()
+	runner := NewScriptRunner(6200, 10)
+	useTempTrust(t, runner)
+	ws := &data.Workspace{Name: "ws", Repo: repo, Root: t.TempDir()}
+
+	if _, err := runner.Columns(ws); !errors.Is(err, ErrNoColumns) {
+		t.Fatalf("Columns() with no config = %v, want ErrNoColumns", err)
+	}
+	writeWorkspaceConfig(t, repo, `{"columns": [
+		{"name": "version", "command": "make version", "interval": "5m", "timeout": "2s"},
+		{"name": "empty", "command": " "},
+		{"command": "grep -rc TODO .", "interval": "1s", "timeout": "1h"}
+	]}`)
+	if _, err := runner.Columns(ws); !errors.Is(err, ErrScriptsNotTrusted) {
+		t.Fatalf("Columns() on an untrusted repo = %v, want ErrScriptsNotTrusted", err)
+	}
+	trustRepo(t, runner, repo)
+	set, err := runner.Columns(ws)
+	if err != nil {
+		t.Fatalf("Columns() error = %v", err)
+	}
+	if len(set.Columns) != 2 || set.Columns[0].Name != "version" || set.Columns[1].Name != "grep" || len(set.Env) == 0 {
+		t.Fatalf("Columns() = %+v", set)
+	}
+	if got := set.Columns[0]; got.RefreshInterval() != 5*time.Minute || got.RunTimeout() != 2*time.Second {
+		t.Fatalf("version column runs every %s for %s", got.RefreshInterval(), got.RunTimeout())
+	}
+	if got := set.Columns[1]; got.RefreshInterval() != minColumnInterval || got.RunTimeout() != maxColumnTimeout {
+		t.Fatalf("grep column runs every %s for %s, want both bounded", got.RefreshInterval(), got.RunTimeout())
+	}
+	if got := (Column{Interval: "soon"}); got.RefreshInterval() != defaultColumnInterval || got.RunTimeout() != defaultColumnTimeout {
+		t.Fatal("a column without valid durations should use the defaults")
+	}
+}
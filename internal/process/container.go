@@ -0,0 +1,71 @@
+package process
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/andyrewlee/amux/internal/container"
+	"github.com/andyrewlee/amux/internal/data"
+	"github.com/andyrewlee/amux/internal/gpu"
+)
+
+// ContainerSpec returns ws's dev container spec: the "container" object of
+// .amux/workspaces.json, gated behind the repo's script trust like the setup
+// scripts since its build and post-create command run repository code, or
+// else the worktree's devcontainer.json, which amux asks about before
+// starting. It returns container.ErrNoSpec when there is neither.
+func (r *ScriptRunner) ContainerSpec(ws *data.Workspace) (*container.Spec, error) {
+	if err := validateScriptWorkspace(ws); err != nil {
+		return nil, err
+	}
+	config, raw, err := r.loadConfigRaw(ws.Repo)
+	if err != nil {
+		return nil, err
+	}
+	if config.Container == nil {
+		return container.LoadDevcontainer(ws.Root)
+	}
+	spec := *config.Container
+	spec.Source = ".amux/" + configFilename
+	if err := spec.Validate(); err != nil {
+		return nil, err
+	}
+	if !r.trust.IsTrusted(ws.Repo, raw) {
+		command := spec.PostCreate
+		if command == "" {
+			command = "container " + spec.Image + spec.Dockerfile
+		}
+		return nil, &ScriptsNotTrustedError{Repo: ws.Repo, Command: command, ConfigHash: hashConfig(raw)}
+	}
+	return &spec, nil
+}
+
+// ContainerOptions returns the host side of ws's container: its port range,
+// the repository's .git directory for a linked worktree, and the script
+// environment with the worktree root as the container sees it.
+func (r *ScriptRunner) ContainerOptions(ws *data.Workspace, spec *container.Spec) container.Options {
+	opts := container.Options{
+		Name:      container.Name(ws.ID()),
+		Workspace: ws.ID(),
+		Root:      ws.Root,
+		NoNetwork: ws.Sandbox || ws.NoNetwork,
+		Env:       r.envBuilder.BuildEnvMap(ws),
+	}
+	if r.portAllocator != nil {
+		opts.PortStart, opts.PortEnd = r.portAllocator.PortRange(ws.Root)
+	}
+	workdir := spec.Workdir
+	if workdir == "" {
+		workdir = container.DefaultWorkdir
+	}
+	opts.Env["AMUX_WORKSPACE_ROOT"] = workdir
+	delete(opts.Env, "ROOT_WORKSPACE_PATH")
+	// The pin names host devices, which the container doesn't get.
+	delete(opts.Env, gpu.VisibleDevicesEnvVar)
+	gitDir := filepath.Join(ws.Repo, ".git")
+	if info, err := os.Stat(gitDir); err == nil && info.IsDir() && !strings.HasPrefix(gitDir, ws.Root+string(filepath.Separator)) {
+		opts.GitDir = gitDir
+	}
+	return opts
+}
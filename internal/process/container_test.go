@@ -0,0 +1,47 @@
+package process
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/andyrewlee/amux/internal/container"
+	"github.com/andyrewlee/amux/internal/data"
+)
+
+func TestContainerSpec(t *testing.T) {
+	repo := t.TempDir()
+	runner := NewScriptRunner(6200, 10)
+	useTempTrust(t, runner)
+	ws := &data.Workspace{Name: "ws", Repo: repo, Root: t.TempDir()}
+
+	if _, err := runner.ContainerSpec(ws); !errors.Is(err, container.ErrNoSpec) {
+		t.Fatalf("ContainerSpec() without a spec = %v, want ErrNoSpec", err)
+	}
+	if err := os.WriteFile(filepath.Join(ws.Root, ".devcontainer.json"), []byte(`{"image": "node:22"}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if spec, err := runner.ContainerSpec(ws); err != nil || spec.Image != "node:22" {
+		t.Fatalf("ContainerSpec() from devcontainer.json = %+v, %v", spec, err)
+	}
+
+	// The amux spec takes precedence, behind the repo's script trust.
+	writeWorkspaceConfig(t, repo, `{"container": {"image": "golang:1.24", "post-create": "go mod download"}}`)
+	if _, err := runner.ContainerSpec(ws); !errors.Is(err, ErrScriptsNotTrusted) {
+		t.Fatalf("ContainerSpec() on an untrusted repo = %v, want ErrScriptsNotTrusted", err)
+	}
+	trustRepo(t, runner, repo)
+	spec, err := runner.ContainerSpec(ws)
+	if err != nil || spec.Image != "golang:1.24" || spec.Source != ".amux/workspaces.json" {
+		t.Fatalf("ContainerSpec() = %+v, %v", spec, err)
+	}
+
+	opts := runner.ContainerOptions(ws, spec)
+	if opts.PortStart != 6200 || opts.PortEnd != 6209 || opts.Name != container.Name(ws.ID()) {
+		t.Fatalf("ContainerOptions() = %+v", opts)
+	}
+	if opts.Env["AMUX_WORKSPACE_ROOT"] != container.DefaultWorkdir || opts.Env["AMUX_PORT"] != "6200" {
+		t.Fatalf("ContainerOptions().Env = %v", opts.Env)
+	}
+}
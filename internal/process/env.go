@@ -7,6 +7,7 @@ import (
 	"strconv"
 
 	"github.com/andyrewlee/amux/internal/data"
+	"github.com/andyrewlee/amux/internal/gpu"
 )
 
 // EnvBuilder builds environment variables for script execution
@@ -57,6 +58,10 @@ func (b *EnvBuilder) BuildEnv(ws *data.Workspace) []string {
 		v := ws.Env[k]
 		env = append(env, fmt.Sprintf("%s=%s", k, v))
 	}
+	// The workspace's GPU pin comes last so it wins over a custom value.
+	if ws.GPUs != "" {
+		env = append(env, gpu.VisibleDevicesEnvVar+"="+ws.GPUs)
+	}
 
 	return env
 }
@@ -85,6 +90,9 @@ func (b *EnvBuilder) BuildEnvMap(ws *data.Workspace) map[string]string {
 		}
 		envMap[k] = v
 	}
+	if ws.GPUs != "" {
+		envMap[gpu.VisibleDevicesEnvVar] = ws.GPUs
+	}
 
 	return envMap
 }
@@ -252,3 +252,26 @@ func envSliceMap(env []string) map[string]string {
 	}
 	return out
 }
+
+func TestEnvBuilder_GPUPinOverridesCustomEnv(t *testing.T) {
+	builder := NewEnvBuilder(nil)
+	wt := &data.Workspace{
+		Name: "train",
+		Root: "/tmp/train",
+		Env:  map[string]string{"CUDA_VISIBLE_DEVICES": "0,1"},
+		GPUs: "1",
+	}
+
+	var last string
+	for _, e := range builder.BuildEnv(wt) {
+		if strings.HasPrefix(e, "CUDA_VISIBLE_DEVICES=") {
+			last = e
+		}
+	}
+	if last != "CUDA_VISIBLE_DEVICES=1" {
+		t.Fatalf("last CUDA_VISIBLE_DEVICES entry = %q, want the pin", last)
+	}
+	if got := builder.BuildEnvMap(wt)["CUDA_VISIBLE_DEVICES"]; got != "1" {
+		t.Fatalf("BuildEnvMap()[CUDA_VISIBLE_DEVICES] = %q, want the pin", got)
+	}
+}
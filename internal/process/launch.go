@@ -0,0 +1,33 @@
+package process
+
+import (
+	"errors"
+	"log/slog"
+	"os/exec"
+	"strings"
+
+	"github.com/andyrewlee/amux/internal/safego"
+)
+
+// StartDetached launches command via `sh -c` in dir and returns once it has
+// started, without waiting for it to finish. The child gets its own process
+// group and no stdio, so an editor or file manager it opens neither receives
+// the TUI's signals nor writes over the alternate screen. The process is
+// reaped in the background.
+func StartDetached(dir, command string) error {
+	if strings.TrimSpace(command) == "" {
+		return errors.New("empty command")
+	}
+	cmd := exec.Command("sh", "-c", command)
+	cmd.Dir = dir
+	SetProcessGroup(cmd)
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	safego.Go("process.detached_wait", func() {
+		if err := cmd.Wait(); err != nil {
+			slog.Debug("detached process exited with error", "command", command, "error", err)
+		}
+	})
+	return nil
+}
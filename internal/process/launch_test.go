@@ -0,0 +1,40 @@
+package process
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/andyrewlee/amux/internal/testutil"
+)
+
+func TestStartDetachedRunsInDir(t *testing.T) {
+	dir := t.TempDir()
+	if err := StartDetached(dir, "pwd > out.txt.tmp && mv out.txt.tmp out.txt"); err != nil {
+		t.Fatalf("StartDetached() error = %v", err)
+	}
+
+	out := filepath.Join(dir, "out.txt")
+	testutil.Eventually(t, 5*time.Second, 10*time.Millisecond, func() bool {
+		_, err := os.Stat(out)
+		return err == nil
+	}, "detached command never wrote %s", out)
+
+	data, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatalf("ReadFile() error = %v", err)
+	}
+	got, _ := filepath.EvalSymlinks(strings.TrimSpace(string(data)))
+	want, _ := filepath.EvalSymlinks(dir)
+	if got != want {
+		t.Fatalf("command ran in %q, want %q", got, want)
+	}
+}
+
+func TestStartDetachedRejectsEmptyCommand(t *testing.T) {
+	if err := StartDetached(t.TempDir(), "  "); err == nil {
+		t.Fatal("expected an error for an empty command")
+	}
+}
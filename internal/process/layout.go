@@ -0,0 +1,103 @@
+package process
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/andyrewlee/amux/internal/data"
+	"github.com/andyrewlee/amux/internal/fsatomic"
+)
+
+// layoutFilename is the startup layout's basename inside .amux.
+const layoutFilename = "layout.json"
+
+// Layout is a startup layout: the tabs to open when a workspace is first
+// activated.
+type Layout struct {
+	// Path is the file the layout was read from.
+	Path string      `json:"-"`
+	Tabs []LayoutTab `json:"tabs"`
+}
+
+// LayoutTab is one tab in a startup layout. Exactly one field is set: Agent
+// opens an assistant and Command a command tab, both in the center pane, and
+// Terminal a shell in the sidebar.
+type LayoutTab struct {
+	Agent    string `json:"agent,omitempty"`
+	Command  string `json:"command,omitempty"`
+	Terminal bool   `json:"terminal,omitempty"`
+}
+
+// LoadLayout reads the startup layout for ws from the worktree's
+// .amux/layout.json, falling back to the project's. It returns nil when
+// neither exists.
+func LoadLayout(ws *data.Workspace) (*Layout, error) {
+	if ws == nil {
+		return nil, nil
+	}
+	dirs := []string{ws.Root}
+	if ws.Repo != "" && ws.Repo != ws.Root {
+		dirs = append(dirs, ws.Repo)
+	}
+	for _, dir := range dirs {
+		if dir == "" {
+			continue
+		}
+		raw, err := readAmuxFile(dir, layoutFilename)
+		if os.IsNotExist(err) {
+			continue
+		}
+		path := LayoutPath(dir)
+		if err != nil {
+			return nil, err
+		}
+		var layout Layout
+		if err := json.Unmarshal(raw, &layout); err != nil {
+			return nil, fmt.Errorf("parse %s: %w", path, err)
+		}
+		layout.Path = path
+		return &layout, nil
+	}
+	return nil, nil
+}
+
+// LayoutPath returns where dir's startup layout is kept.
+func LayoutPath(dir string) string {
+	return filepath.Join(dir, ".amux", layoutFilename)
+}
+
+// SaveLayout writes layout to dir's .amux/layout.json, replacing any there.
+func SaveLayout(dir string, layout *Layout) error {
+	if err := os.MkdirAll(filepath.Join(dir, ".amux"), 0o755); err != nil {
+		return err
+	}
+	return fsatomic.WriteJSON(LayoutPath(dir), layout)
+}
+
+// Launches returns the layout's tabs in order as launches.
+func (l *Layout) Launches() ([]data.Launch, error) {
+	launches := make([]data.Launch, 0, len(l.Tabs))
+	for i, tab := range l.Tabs {
+		var set int
+		var launch data.Launch
+		if tab.Agent != "" {
+			set++
+			launch = data.Launch{Kind: data.LaunchAgent, Name: tab.Agent}
+		}
+		if tab.Command != "" {
+			set++
+			launch = data.Launch{Kind: data.LaunchCommand, Command: tab.Command}
+		}
+		if tab.Terminal {
+			set++
+			launch = data.Launch{Kind: data.LaunchTerminal}
+		}
+		if set != 1 {
+			return nil, fmt.Errorf("%s: tab %d must set exactly one of agent, command, or terminal", l.Path, i+1)
+		}
+		launches = append(launches, launch)
+	}
+	return launches, nil
+}
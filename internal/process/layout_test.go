@@ -0,0 +1,84 @@
+package process
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/andyrewlee/amux/internal/data"
+)
+
+func writeLayout(t *testing.T, dir, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Join(dir, ".amux"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, ".amux", layoutFilename), []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLoadLayoutPrefersWorktree(t *testing.T) {
+	ws := &data.Workspace{Repo: t.TempDir(), Root: t.TempDir()}
+	if layout, err := LoadLayout(ws); layout != nil || err != nil {
+		t.Fatalf("LoadLayout() without a file = %v, %v; want nil, nil", layout, err)
+	}
+
+	writeLayout(t, ws.Repo, `{"tabs":[{"agent":"claude"}]}`)
+	layout, err := LoadLayout(ws)
+	if err != nil || layout == nil || layout.Path != filepath.Join(ws.Repo, ".amux", layoutFilename) {
+		t.Fatalf("LoadLayout() = %+v, %v; want the project layout", layout, err)
+	}
+
+	writeLayout(t, ws.Root, `{"tabs":[{"command":"npm run dev"},{"terminal":true},{"agent":"codex"}]}`)
+	layout, err = LoadLayout(ws)
+	if err != nil {
+		t.Fatalf("LoadLayout() error = %v", err)
+	}
+	launches, err := layout.Launches()
+	want := []data.Launch{
+		{Kind: data.LaunchCommand, Command: "npm run dev"},
+		{Kind: data.LaunchTerminal},
+		{Kind: data.LaunchAgent, Name: "codex"},
+	}
+	if err != nil || len(launches) != len(want) {
+		t.Fatalf("Launches() = %v, %v; want %v", launches, err, want)
+	}
+	for i := range want {
+		if launches[i] != want[i] {
+			t.Fatalf("Launches()[%d] = %v, want %v", i, launches[i], want[i])
+		}
+	}
+}
+
+func TestLayoutRejectsAmbiguousTabs(t *testing.T) {
+	ws := &data.Workspace{Root: t.TempDir()}
+	writeLayout(t, ws.Root, `{"tabs":[{"agent":"claude"},{"agent":"claude","command":"ls"}]}`)
+	layout, err := LoadLayout(ws)
+	if err != nil {
+		t.Fatalf("LoadLayout() error = %v", err)
+	}
+	if _, err := layout.Launches(); err == nil || !strings.Contains(err.Error(), "tab 2") {
+		t.Fatalf("Launches() error = %v, want one naming tab 2", err)
+	}
+
+	writeLayout(t, ws.Root, `{"tabs":`)
+	if _, err := LoadLayout(ws); err == nil {
+		t.Fatal("LoadLayout() with malformed JSON should fail")
+	}
+}
+
+func TestSaveLayoutRoundTrips(t *testing.T) {
+	dir := t.TempDir()
+	if err := SaveLayout(dir, &Layout{Tabs: []LayoutTab{{Agent: "claude"}, {Terminal: true}}}); err != nil {
+		t.Fatalf("SaveLayout() error = %v", err)
+	}
+	layout, err := LoadLayout(&data.Workspace{Root: dir})
+	if err != nil || layout == nil || layout.Path != LayoutPath(dir) {
+		t.Fatalf("LoadLayout() = %+v, %v; want the saved layout", layout, err)
+	}
+	if len(layout.Tabs) != 2 || layout.Tabs[0].Agent != "claude" || !layout.Tabs[1].Terminal {
+		t.Fatalf("saved tabs = %+v", layout.Tabs)
+	}
+}
@@ -0,0 +1,101 @@
+package process
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/andyrewlee/amux/internal/data"
+	"github.com/andyrewlee/amux/internal/shellutil"
+)
+
+// ErrNoRunbook is returned when a project's .amux/workspaces.json defines no
+// runbook.
+var ErrNoRunbook = errors.New("no runbook configured")
+
+// RunbookStep is one named command in a project's onboarding runbook.
+type RunbookStep struct {
+	Name    string `json:"name"`
+	Command string `json:"command"`
+}
+
+// RunbookCommand returns a shell program that walks ws's project runbook one
+// step at a time in a terminal: each step is confirmed before it runs, a
+// failed step can be retried, and a pass/fail summary is printed at the end.
+// Like the setup scripts, the runbook comes from the repository and is gated
+// behind the repo's script trust.
+func (r *ScriptRunner) RunbookCommand(ws *data.Workspace) (string, error) {
+	if err := validateScriptWorkspace(ws); err != nil {
+		return "", err
+	}
+	config, raw, err := r.loadConfigRaw(ws.Repo)
+	if err != nil {
+		return "", err
+	}
+	var steps []RunbookStep
+	for _, step := range config.Runbook {
+		if strings.TrimSpace(step.Command) != "" {
+			steps = append(steps, step)
+		}
+	}
+	if len(steps) == 0 {
+		return "", ErrNoRunbook
+	}
+	if !r.trust.IsTrusted(ws.Repo, raw) {
+		return "", &ScriptsNotTrustedError{
+			Repo:       ws.Repo,
+			Command:    steps[0].Command,
+			ConfigHash: hashConfig(raw),
+		}
+	}
+	return runbookScript(steps, r.envBuilder.BuildEnvMap(ws)), nil
+}
+
+// runbookStepFunc runs one step: $1 is its name and $2 its command. A retry
+// runs the step again without asking first.
+const runbookStepFunc = `amux_step() { amux_i=$((amux_i + 1)); ` +
+	`if [ "$amux_stop" = 1 ]; then amux_summary="$amux_summary$amux_nl  -     $1"; return; fi; ` +
+	`while :; do ` +
+	`printf '\r\n\033[1m[%s/%s] %s\033[0m\r\n  $ %s\r\n' "$amux_i" "$amux_total" "$1" "$2"; ` +
+	`if [ "$amux_retry" != 1 ]; then printf 'Enter to run, s to skip, q to stop: '; read -r amux_key || amux_key=q; ` +
+	`case "$amux_key" in q) amux_stop=1; amux_mark='-   '; break ;; s) amux_mark=skip; amux_skipped=$((amux_skipped + 1)); break ;; esac; fi; amux_retry=0; ` +
+	`amux_t0=$(date +%s); sh -c "$2"; amux_status=$?; amux_secs=$(($(date +%s) - amux_t0)); ` +
+	`if [ "$amux_status" -eq 0 ]; then printf '\033[32mPASS\033[0m %s (%ss)\r\n' "$1" "$amux_secs"; amux_mark=pass; amux_passed=$((amux_passed + 1)); break; fi; ` +
+	`printf '\033[31mFAIL\033[0m %s: exit status %s (%ss)\r\n' "$1" "$amux_status" "$amux_secs"; ` +
+	`printf 'r to retry, Enter to continue, q to stop: '; read -r amux_key || amux_key=q; ` +
+	`[ "$amux_key" = r ] && { amux_retry=1; continue; }; [ "$amux_key" = q ] && amux_stop=1; ` +
+	`amux_mark=FAIL; amux_failed=$((amux_failed + 1)); break; ` +
+	`done; amux_summary="$amux_summary$amux_nl  $amux_mark  $1"; }`
+
+// runbookScript builds the program RunbookCommand returns, exporting env so
+// steps see the same workspace variables as the setup scripts.
+func runbookScript(steps []RunbookStep, env map[string]string) string {
+	keys := make([]string, 0, len(env))
+	for k := range env {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	parts := make([]string, 0, len(keys)+len(steps)+6)
+	for _, k := range keys {
+		parts = append(parts, fmt.Sprintf("export %s=%s", k, shellutil.ShellQuote(env[k])))
+	}
+	parts = append(parts,
+		`amux_nl=$(printf '\n_'); amux_nl=${amux_nl%_}`,
+		fmt.Sprintf("amux_i=0; amux_total=%d; amux_passed=0; amux_failed=0; amux_skipped=0; amux_stop=0; amux_retry=0; amux_summary=''", len(steps)),
+		runbookStepFunc,
+	)
+	for _, step := range steps {
+		name := strings.TrimSpace(step.Name)
+		if name == "" {
+			name = step.Command
+		}
+		parts = append(parts, fmt.Sprintf("amux_step %s %s", shellutil.ShellQuote(name), shellutil.ShellQuote(step.Command)))
+	}
+	parts = append(parts,
+		`printf '\r\n\033[1mRunbook: %s passed, %s failed, %s skipped\033[0m\r\n' "$amux_passed" "$amux_failed" "$amux_skipped"`,
+		`printf '%s\n' "$amux_summary" | sed '1d'`,
+		`printf '\r\nPress Enter to close. '; read -r amux_key`,
+	)
+	return strings.Join(parts, "; ")
+}
@@ -0,0 +1,78 @@
+package process
+
+import (
+	"errors"
+	"os/exec"
+	"strings"
+	"testing"
+
+	"github.com/andyrewlee/amux/internal/data"
+)
+
+func TestRunbookCommandIsTrustGated(t *testing.T) {
+	repo := t.TempDir()
+	writeWorkspaceConfig(t, repo, `{"runbook": [{"name": "install deps", "command": "npm ci"}, {"name": "blank", "command": " "}]}`)
+	runner := NewScriptRunner(6200, 10)
+	useTempTrust(t, runner)
+	ws := &data.Workspace{Name: "ws", Repo: repo, Root: t.TempDir()}
+
+	if _, err := runner.RunbookCommand(ws); !errors.Is(err, ErrScriptsNotTrusted) {
+		t.Fatalf("RunbookCommand() on an untrusted repo = %v, want ErrScriptsNotTrusted", err)
+	}
+	trustRepo(t, runner, repo)
+	command, err := runner.RunbookCommand(ws)
+	if err != nil {
+		t.Fatalf("RunbookCommand() error = %v", err)
+	}
+	if !strings.Contains(command, "amux_step 'install deps' 'npm ci'") || strings.Contains(command, "'blank'") {
+		t.Fatalf("RunbookCommand() = %q, want the install step and no blank step", command)
+	}
+	if !strings.Contains(command, "export AMUX_WORKSPACE_NAME='ws'") {
+		t.Fatalf("RunbookCommand() = %q, want the workspace env exported", command)
+	}
+
+	writeWorkspaceConfig(t, repo, `{"setup-workspace": ["true"]}`)
+	trustRepo(t, runner, repo)
+	if _, err := runner.RunbookCommand(ws); !errors.Is(err, ErrNoRunbook) {
+		t.Fatalf("RunbookCommand() without a runbook = %v, want ErrNoRunbook", err)
+	}
+}
+
+func TestRunbookScriptTracksSteps(t *testing.T) {
+	script := runbookScript([]RunbookStep{
+		{Name: "greet", Command: `echo "hello $AMUX_PORT"`},
+		{Name: "broken", Command: "exit 3"},
+		{Name: "optional", Command: "echo never"},
+		{Command: "echo unnamed"},
+		{Name: "after stop", Command: "echo never"},
+	}, map[string]string{"AMUX_PORT": "6200"})
+
+	// Run greet, fail broken then retry and continue, skip optional, stop.
+	cmd := exec.Command("/bin/sh", "-c", script)
+	cmd.Stdin = strings.NewReader("\n\nr\n\ns\nq\n\n")
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("runbook script failed: %v\n%s", err, out)
+	}
+	got := string(out)
+	for _, want := range []string{
+		"hello 6200",
+		"[2/5] broken",
+		"Runbook: 1 passed, 1 failed, 1 skipped",
+		"  pass  greet",
+		"  FAIL  broken",
+		"  skip  optional",
+		"  -     echo unnamed",
+		"  -     after stop",
+	} {
+		if !strings.Contains(got, want) {
+			t.Fatalf("runbook output missing %q:\n%s", want, got)
+		}
+	}
+	if strings.Count(got, "FAIL\033[0m broken") != 2 {
+		t.Fatalf("expected broken to run twice after a retry:\n%s", got)
+	}
+	if strings.Contains(got, "never") && !strings.Contains(got, "$ echo never") {
+		t.Fatalf("skipped steps should not run:\n%s", got)
+	}
+}
@@ -0,0 +1,27 @@
+package process
+
+import (
+	"github.com/andyrewlee/amux/internal/data"
+	"github.com/andyrewlee/amux/internal/devenv"
+	"github.com/andyrewlee/amux/internal/runtimes"
+)
+
+// scriptCommand returns command run in the same environment agents in the
+// worktree at root get: its declared one (.envrc or flake.nix) when it has
+// one, else its pinned runtime versions.
+func scriptCommand(root, command string) string {
+	if kind := devenv.Detect(root); kind != devenv.None {
+		return devenv.Wrap(kind, root, command)
+	}
+	return runtimes.Wrap(runtimes.Resolve(root), command)
+}
+
+// WorkspaceCommand returns a command the user typed set up to run in ws the
+// way its scripts do: in the worktree's environment, with the workspace's
+// variables. It is the user's own command, so repo trust does not apply.
+func (r *ScriptRunner) WorkspaceCommand(ws *data.Workspace, command string) (string, []string, error) {
+	if err := validateScriptWorkspace(ws); err != nil {
+		return "", nil, err
+	}
+	return scriptCommand(ws.Root, command), r.envBuilder.BuildEnv(ws), nil
+}
@@ -0,0 +1,55 @@
+package process
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/andyrewlee/amux/internal/data"
+	"github.com/andyrewlee/amux/internal/runtimes"
+)
+
+func TestScriptRunnerRunSetupActivatesPinnedRuntimes(t *testing.T) {
+	repo, wsRoot, home := t.TempDir(), t.TempDir(), t.TempDir()
+	bin, shims := filepath.Join(home, "bin"), filepath.Join(home, ".asdf", "shims")
+	for dir, script := range map[string]string{bin: "asdf", shims: "node"} {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, script), []byte("#!/bin/sh\necho node 20 from "+script+"\n"), 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	t.Setenv("HOME", home)
+	t.Setenv("ASDF_DATA_DIR", "")
+	t.Setenv("PATH", bin+string(os.PathListSeparator)+os.Getenv("PATH"))
+	if err := os.WriteFile(filepath.Join(wsRoot, ".tool-versions"), []byte("nodejs 20.11.0\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	writeWorkspaceConfig(t, repo, `{"setup-workspace": ["node > setup.txt"]}`)
+
+	runner := NewScriptRunner(6200, 10)
+	trustRepo(t, runner, repo)
+	if err := runner.RunSetup(&data.Workspace{Repo: repo, Root: wsRoot}); err != nil {
+		t.Fatalf("RunSetup() error = %v", err)
+	}
+	contents, err := os.ReadFile(filepath.Join(wsRoot, "setup.txt"))
+	if err != nil || strings.TrimSpace(string(contents)) != "node 20 from node" {
+		t.Fatalf("setup.txt = %q, %v; want the asdf shim run", contents, err)
+	}
+}
+
+func TestWorkspaceCommand(t *testing.T) {
+	root := t.TempDir()
+	t.Setenv(runtimes.EnvVar, "off")
+	runner := NewScriptRunner(6200, 10)
+	command, env, err := runner.WorkspaceCommand(&data.Workspace{Name: "ws", Repo: t.TempDir(), Root: root}, "make check")
+	if err != nil || command != "make check" || !slices.Contains(env, "AMUX_WORKSPACE_NAME=ws") {
+		t.Fatalf("WorkspaceCommand() = %q, %v; want the command and workspace env", command, err)
+	}
+	if _, _, err := runner.WorkspaceCommand(&data.Workspace{}, "make check"); err == nil {
+		t.Fatal("WorkspaceCommand() without a worktree should fail")
+	}
+}
@@ -8,11 +8,11 @@ import (
 	"log/slog"
 	"os"
 	"os/exec"
-	"path/filepath"
 	"strings"
 	"sync"
 	"time"
 
+	"github.com/andyrewlee/amux/internal/container"
 	"github.com/andyrewlee/amux/internal/data"
 	"github.com/andyrewlee/amux/internal/safego"
 )
@@ -127,6 +127,22 @@ type WorkspaceConfig struct {
 	SetupWorkspace []string `json:"setup-workspace"`
 	RunScript      string   `json:"run"`
 	ArchiveScript  string   `json:"archive"`
+	// Test is the command the test panel runs; detected when empty.
+	Test string `json:"test,omitempty"`
+	// Runbook lists named onboarding steps run one at a time in a tab.
+	Runbook []RunbookStep `json:"runbook,omitempty"`
+	// Checks are the build, lint, and typecheck commands run before review.
+	Checks []Check `json:"checks,omitempty"`
+	// Columns are extra dashboard columns filled per worktree.
+	Columns []Column `json:"columns,omitempty"`
+	// RequireChecks blocks committing until every check passes.
+	RequireChecks bool `json:"require-checks,omitempty"`
+	// ConventionalCommits requires commit messages written in amux to
+	// follow the Conventional Commits format.
+	ConventionalCommits bool `json:"conventional-commits,omitempty"`
+	// Container defines the dev container tabs can run in; see
+	// ScriptRunner.ContainerSpec.
+	Container *container.Spec `json:"container,omitempty"`
 }
 
 // ScriptRunner manages script execution for workspaces
@@ -137,7 +153,8 @@ type ScriptRunner struct {
 	running          map[string]*runningScript // workspace root -> running process
 	pendingRelease   map[string]pendingPortRelease
 	killProcessGroup func(pid int, opts KillOptions) error
-	trust            *ScriptTrust // per-user approval registry for repo-supplied scripts
+	trust            *ScriptTrust  // per-user approval registry for repo-supplied scripts
+	checkSlots       chan struct{} // bounds checks running at once across workspaces
 }
 
 type runningScript struct {
@@ -169,6 +186,7 @@ func NewScriptRunner(portStart, portRange int) *ScriptRunner {
 		pendingRelease:   make(map[string]pendingPortRelease),
 		killProcessGroup: KillProcessGroup,
 		trust:            defaultScriptTrust(),
+		checkSlots:       make(chan struct{}, checksParallelism()),
 	}
 }
 
@@ -183,7 +201,7 @@ func (r *ScriptRunner) LoadConfig(repoPath string) (*WorkspaceConfig, error) {
 // disk read. A missing file yields an empty config and nil bytes (nothing to
 // trust or run).
 func (r *ScriptRunner) loadConfigRaw(repoPath string) (*WorkspaceConfig, []byte, error) {
-	fileData, err := readWorkspaceConfigFile(repoPath)
+	fileData, err := readAmuxFile(repoPath, configFilename)
 	if os.IsNotExist(err) {
 		return &WorkspaceConfig{}, nil, nil
 	}
@@ -198,25 +216,6 @@ func (r *ScriptRunner) loadConfigRaw(repoPath string) (*WorkspaceConfig, []byte,
 	return &config, fileData, nil
 }
 
-func readWorkspaceConfigFile(repoPath string) ([]byte, error) {
-	root, err := os.OpenRoot(filepath.Join(repoPath, ".amux"))
-	if err != nil {
-		return nil, err
-	}
-	data, readErr := root.ReadFile(configFilename)
-	closeErr := root.Close()
-	if readErr != nil {
-		if closeErr != nil {
-			return nil, errors.Join(readErr, fmt.Errorf("close workspace config directory: %w", closeErr))
-		}
-		return nil, readErr
-	}
-	if closeErr != nil {
-		return nil, fmt.Errorf("close workspace config directory: %w", closeErr)
-	}
-	return data, nil
-}
-
 // RunSetup runs the setup scripts for a workspace
 func (r *ScriptRunner) RunSetup(ws *data.Workspace) error {
 	if err := validateScriptWorkspace(ws); err != nil {
@@ -242,7 +241,7 @@ func (r *ScriptRunner) RunSetup(ws *data.Workspace) error {
 
 	// Run each setup command sequentially
 	for _, cmdStr := range config.SetupWorkspace {
-		cmd := exec.Command("sh", "-c", cmdStr)
+		cmd := exec.Command("sh", "-c", scriptCommand(ws.Root, cmdStr))
 		cmd.Dir = ws.Root
 		cmd.Env = env
 		SetProcessGroup(cmd)
@@ -325,7 +324,7 @@ func (r *ScriptRunner) RunScript(ws *data.Workspace, scriptType ScriptType) (*ex
 
 	env := r.envBuilder.BuildEnv(ws)
 
-	cmd := exec.Command("sh", "-c", cmdStr)
+	cmd := exec.Command("sh", "-c", scriptCommand(ws.Root, cmdStr))
 	cmd.Dir = ws.Root
 	cmd.Env = env
 	SetProcessGroup(cmd)
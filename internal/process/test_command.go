@@ -0,0 +1,41 @@
+package process
+
+import (
+	"errors"
+
+	"github.com/andyrewlee/amux/internal/data"
+	"github.com/andyrewlee/amux/internal/testrun"
+)
+
+// ErrNoTestCommand is returned when a project neither configures a test
+// command nor has one amux recognizes.
+var ErrNoTestCommand = errors.New("no test command configured")
+
+// TestCommand returns the command that runs ws's tests and the environment to
+// run it in. The command is the project's "test" entry in
+// .amux/workspaces.json, gated behind repo trust like the other scripts, or
+// else the conventional command for the project's language.
+func (r *ScriptRunner) TestCommand(ws *data.Workspace) (string, []string, error) {
+	if err := validateScriptWorkspace(ws); err != nil {
+		return "", nil, err
+	}
+	config, raw, err := r.loadConfigRaw(ws.Repo)
+	if err != nil {
+		return "", nil, err
+	}
+	command := config.Test
+	if command != "" && !r.trust.IsTrusted(ws.Repo, raw) {
+		return "", nil, &ScriptsNotTrustedError{
+			Repo:       ws.Repo,
+			Command:    command,
+			ConfigHash: hashConfig(raw),
+		}
+	}
+	if command == "" {
+		command = testrun.Detect(ws.Root)
+	}
+	if command == "" {
+		return "", nil, ErrNoTestCommand
+	}
+	return command, r.envBuilder.BuildEnv(ws), nil
+}
@@ -0,0 +1,30 @@
+package process
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/andyrewlee/amux/internal/data"
+)
+
+func TestTestCommand(t *testing.T) {
+	repo := t.TempDir()
+	root := t.TempDir()
+	runner := NewScriptRunner(6200, 10)
+	useTempTrust(t, runner)
+	ws := &data.Workspace{Name: "ws", Repo: repo, Root: root}
+
+	if _, _, err := runner.TestCommand(ws); !errors.Is(err, ErrNoTestCommand) {
+		t.Fatalf("TestCommand() with nothing to run = %v, want ErrNoTestCommand", err)
+	}
+	writeWorkspaceConfig(t, repo, `{"test": "make check"}`)
+	if _, _, err := runner.TestCommand(ws); !errors.Is(err, ErrScriptsNotTrusted) {
+		t.Fatalf("TestCommand() on an untrusted repo = %v, want ErrScriptsNotTrusted", err)
+	}
+	trustRepo(t, runner, repo)
+	command, env, err := runner.TestCommand(ws)
+	if err != nil || command != "make check" || !slices.Contains(env, "AMUX_WORKSPACE_NAME=ws") {
+		t.Fatalf("TestCommand() = %q, %v; want the configured command and workspace env", command, err)
+	}
+}
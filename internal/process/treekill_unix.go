@@ -65,6 +65,16 @@ func KillProcessGroup(leaderPID int, opts KillOptions) error {
 	return nil
 }
 
+// InterruptProcessGroup sends SIGINT to the process group led by pid, as
+// pressing Ctrl-C in a terminal would.
+func InterruptProcessGroup(pid int) error {
+	err := syscall.Kill(-pid, syscall.SIGINT)
+	if err == syscall.ESRCH {
+		return nil
+	}
+	return err
+}
+
 // ForceKillProcess sends SIGKILL to the process group led by pid.
 func ForceKillProcess(pid int) error {
 	return syscall.Kill(-pid, syscall.SIGKILL)
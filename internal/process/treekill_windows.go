@@ -41,6 +41,19 @@ func KillProcessGroup(leaderPID int, opts KillOptions) error {
 	return proc.Kill()
 }
 
+// InterruptProcessGroup sends an interrupt to the process on Windows, which
+// has no process groups; where that is unsupported the process is killed.
+func InterruptProcessGroup(pid int) error {
+	proc, err := os.FindProcess(pid)
+	if err != nil {
+		return err
+	}
+	if err := proc.Signal(os.Interrupt); err != nil {
+		return proc.Kill()
+	}
+	return nil
+}
+
 // ForceKillProcess terminates the process by PID on Windows.
 func ForceKillProcess(pid int) error {
 	proc, err := os.FindProcess(pid)
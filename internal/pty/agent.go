@@ -11,7 +11,10 @@ import (
 
 	"github.com/andyrewlee/amux/internal/config"
 	"github.com/andyrewlee/amux/internal/data"
+	"github.com/andyrewlee/amux/internal/devenv"
+	"github.com/andyrewlee/amux/internal/limits"
 	"github.com/andyrewlee/amux/internal/logging"
+	"github.com/andyrewlee/amux/internal/sandbox"
 	"github.com/andyrewlee/amux/internal/tmux"
 )
 
@@ -87,25 +90,11 @@ func (m *AgentManager) CreateAgentWithTags(ws *data.Workspace, agentType AgentTy
 		return nil, err
 	}
 
-	// Build environment
-	env := []string{
-		"WORKSPACE_ROOT=" + ws.Root,
-		"WORKSPACE_NAME=" + ws.Name,
-		"LINES=",   // Unset to force ioctl usage
-		"COLUMNS=", // Unset to force ioctl usage
-		"COLORTERM=truecolor",
-	}
-
-	// Create terminal with agent command, falling back to shell on exit
-	loginShellCommand, err := LoginShellCommandFromEnv()
+	fullCommand, err := agentCommandLine(ws, agentType, assistantCfg, sessionName)
 	if err != nil {
 		return nil, err
 	}
-
-	// Execute agent, then reset terminal state and drop to shell
-	// Reset sequence: stty sane (terminal modes), exit alt screen, show cursor, reset attrs, RIS
-	// Use -l flag to start login shell so .zshrc/.bashrc are loaded
-	fullCommand := fmt.Sprintf("%s; stty sane; printf '\\033[?1049l\\033[?25h\\033[0m\\033c'; echo 'Agent exited. Dropping to shell...'; export TERM=xterm-256color; %s", assistantCfg.Command, loginShellCommand)
+	env := agentEnv(ws)
 
 	termCommand := tmux.NewClientCommand(sessionName, tmux.ClientCommandParams{
 		WorkDir:        ws.Root,
@@ -134,6 +123,62 @@ func (m *AgentManager) CreateAgentWithTags(ws *data.Workspace, agentType AgentTy
 	return agent, nil
 }
 
+// agentEnv is the environment an agent's terminal runs with.
+func agentEnv(ws *data.Workspace) []string {
+	return []string{
+		"WORKSPACE_ROOT=" + ws.Root,
+		"WORKSPACE_NAME=" + ws.Name,
+		"LINES=",   // Unset to force ioctl usage
+		"COLUMNS=", // Unset to force ioctl usage
+		"COLORTERM=truecolor",
+	}
+}
+
+// agentCommandLine returns the command an agent's tmux session runs: the
+// assistant, confined as configured, then its exit banner and a login shell.
+func agentCommandLine(ws *data.Workspace, agentType AgentType, assistantCfg config.AssistantConfig, sessionName string) (string, error) {
+	loginShellCommand, err := WorkspaceShellCommand(ws)
+	if err != nil {
+		return "", err
+	}
+
+	sandboxKind, limitsKind, env := sandbox.Detect(), limits.Detect(), detectLaunchEnv(ws.Root)
+	agentCommand, err := launchCommand(ws, assistantCfg, sandboxKind, limitsKind, env)
+	if err != nil {
+		return "", err
+	}
+	if ws.Container != nil {
+		logging.Info("Running agent %s in container %s", sessionName, ws.Container.Name)
+	} else if ws.Sandbox || ws.NoNetwork {
+		logging.Info("Sandboxing agent in %s with %s", ws.Root, sandboxKind)
+	}
+	if env.devenv != devenv.None && ws.Container == nil {
+		logging.Info("Loading agent %s environment with %s", sessionName, env.devenv)
+	} else if len(env.runtimes) > 0 && ws.Container == nil {
+		logging.Info("Activating agent %s runtimes: %+v", sessionName, env.runtimes)
+	}
+	if !assistantCfg.Limits.IsZero() && ws.Container == nil {
+		logging.Info("Limiting agent %s with %s: %+v", sessionName, limitsKind, assistantCfg.Limits)
+	}
+
+	// Execute agent, then show how it exited and offer a relaunch before
+	// dropping to a login shell (so .zshrc/.bashrc are loaded).
+	return pinGPUs(ws, exitBannerCommand(agentCommand, string(agentType), loginShellCommand)), nil
+}
+
+// sandboxedCommand wraps command in kind's sandbox when ws asks for one
+// (Sandbox, or just NoNetwork). The shell the tab drops to after the agent
+// exits is not sandboxed. Without a sandbox tool the launch fails instead of
+// quietly running unconfined.
+func sandboxedCommand(ws *data.Workspace, command string, kind sandbox.Kind) (string, error) {
+	if !ws.Sandbox && !ws.NoNetwork {
+		return command, nil
+	}
+	policy := sandbox.ForWorktree(ws.Root, ws.Repo)
+	policy.NetworkOnly = !ws.Sandbox
+	return sandbox.Wrap(kind, command, policy)
+}
+
 // CreateViewer creates a new agent (viewer) for the given workspace and command.
 func (m *AgentManager) CreateViewer(ws *data.Workspace, command, sessionName string, rows, cols uint16) (*Agent, error) {
 	return m.CreateViewerWithTags(ws, command, sessionName, rows, cols, tmux.SessionTags{})
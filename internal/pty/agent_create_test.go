@@ -77,13 +77,14 @@ func TestAgentManager_CreateAgentWithTags_RegistersAgent(t *testing.T) {
 	}
 
 	// Reset sequence: the post-exit escape sequence that exits alt-screen,
-	// shows the cursor, resets attrs, and issues RIS (stty sane; printf
-	// '\033[?1049l\033[?25h\033[0m\033c') must be embedded in the spawned
+	// shows the cursor, resets attrs, and soft-resets (stty sane; printf
+	// '\033[?1049l\033[?25h\033[0m\033[!p') must be embedded in the spawned
 	// command. This is the regression guard: if a future change drops the
 	// `?1049l` or otherwise mangles this sequence, the pane is left stuck in
-	// alt-screen / hidden-cursor after every agent session.
+	// alt-screen / hidden-cursor after every agent session. It is a soft
+	// reset rather than RIS so the agent's last output stays on screen.
 	cmdStr := strings.Join(agent.Terminal.cmd.Args, " ")
-	const resetSeq = "\\033[?1049l\\033[?25h\\033[0m\\033c"
+	const resetSeq = "\\033[?1049l\\033[?25h\\033[0m\\033[!p"
 	if !strings.Contains(cmdStr, resetSeq) {
 		t.Errorf("spawned command missing terminal-reset sequence %q", resetSeq)
 	}
@@ -108,3 +109,38 @@ func TestAgentManager_CreateAgentWithTags_RegistersAgent(t *testing.T) {
 		t.Errorf("expected 0 agents after CloseAgent, got %d", remaining)
 	}
 }
+
+// TestAgentManager_StartDetachedAgent starts an agent with a prompt and no
+// terminal, and checks the tagged session is left running on the server.
+func TestAgentManager_StartDetachedAgent(t *testing.T) {
+	if err := tmux.EnsureAvailable(); err != nil {
+		t.Skipf("tmux unavailable: %v", err)
+	}
+	serverName := fmt.Sprintf("amux-ptytest-%d", time.Now().UnixNano())
+	t.Cleanup(func() {
+		_ = exec.Command("tmux", "-L", serverName, "kill-server").Run()
+	})
+
+	m := NewAgentManager(testConfig())
+	m.SetTmuxOptions(tmux.Options{ServerName: serverName, ConfigPath: "/dev/null", CommandTimeout: 5 * time.Second})
+	ws := &data.Workspace{Name: "detached-ws", Root: t.TempDir(), Repo: "/tmp/test-repo"}
+	tags := tmux.SessionTags{WorkspaceID: string(ws.ID()), TabID: "schedule-deps", Type: "agent", Assistant: "claude"}
+
+	if err := m.StartDetachedAgent(ws, AgentType("claude"), "amux-test-detached", "update deps", tags); err != nil {
+		t.Fatalf("StartDetachedAgent failed: %v", err)
+	}
+	out, err := exec.Command("tmux", "-L", serverName, "-f", "/dev/null", "show-options", "-t", "amux-test-detached", "-v", "@amux_tab").CombinedOutput()
+	if err != nil || strings.TrimSpace(string(out)) != "schedule-deps" {
+		t.Fatalf("@amux_tab = %q (%v), want schedule-deps", out, err)
+	}
+	m.mu.Lock()
+	registered := len(m.agents[ws.ID()])
+	m.mu.Unlock()
+	if registered != 0 {
+		t.Fatalf("detached agents should not be registered, got %d", registered)
+	}
+
+	if err := m.StartDetachedAgent(ws, AgentType("unknown"), "amux-test-detached-2", "", tmux.SessionTags{}); err == nil {
+		t.Fatal("expected an error for an unknown agent type")
+	}
+}
@@ -0,0 +1,60 @@
+package pty
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/andyrewlee/amux/internal/data"
+	"github.com/andyrewlee/amux/internal/shellutil"
+	"github.com/andyrewlee/amux/internal/tmux"
+)
+
+// StartDetachedAgent starts an agent in its own tmux session without a
+// terminal attached, giving it prompt as its first message. It is for
+// agents launched with no UI running (see internal/schedule); amux adopts
+// the session as a tab the next time it starts, from its tags. The agent is
+// not registered with the manager.
+func (m *AgentManager) StartDetachedAgent(ws *data.Workspace, agentType AgentType, sessionName, prompt string, tags tmux.SessionTags) error {
+	if ws == nil {
+		return errors.New("workspace is required")
+	}
+	if sessionName == "" {
+		return errors.New("session name is required")
+	}
+	assistantCfg, ok := m.config.Assistants[string(agentType)]
+	if !ok {
+		return fmt.Errorf("unknown agent type: %s", agentType)
+	}
+	if err := tmux.EnsureAvailable(); err != nil {
+		return err
+	}
+
+	// The prompt is only for the first run: a crashed agent restarts with
+	// its resume command as usual.
+	if assistantCfg.Restart.ResumeCommand == "" {
+		assistantCfg.Restart.ResumeCommand = assistantCfg.Command
+	}
+	if prompt != "" {
+		assistantCfg.Command += " " + shellutil.ShellQuote(prompt)
+	}
+	fullCommand, err := agentCommandLine(ws, agentType, assistantCfg, sessionName)
+	if err != nil {
+		return err
+	}
+
+	cmd := exec.Command("sh", "-c", tmux.NewDetachedCommand(sessionName, tmux.ClientCommandParams{
+		WorkDir: ws.Root,
+		Command: fullCommand,
+		Options: m.getTmuxOptions(),
+		Tags:    tags,
+	}))
+	cmd.Dir = ws.Root
+	cmd.Env = append(os.Environ(), agentEnv(ws)...)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("starting session %s: %w: %s", sessionName, err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
@@ -0,0 +1,38 @@
+package pty
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/andyrewlee/amux/internal/shellutil"
+)
+
+// exitBannerCommand runs agentCommand and, when it exits, leaves an exit
+// banner (status, runtime, time) under its output and offers to relaunch it
+// with r; any other key drops to shell.
+//
+// The terminal is restored with a soft reset (leave the alternate screen,
+// show the cursor, reset attributes) rather than RIS, which would also wipe
+// the screen and the agent's last output with it.
+func exitBannerCommand(agentCommand, name, shell string) string {
+	label := shellutil.ShellQuote(name)
+	return strings.Join([]string{
+		"while :; do amux_t0=$(date +%s)",
+		agentCommand,
+		"amux_status=$?",
+		"stty sane 2>/dev/null",
+		`printf '\033[?1049l\033[?25h\033[0m\033[!p'`,
+		"amux_secs=$(($(date +%s) - amux_t0))",
+		`if [ "$amux_secs" -ge 3600 ]; then amux_ran=$(printf '%dh%02dm' $((amux_secs / 3600)) $((amux_secs % 3600 / 60))); ` +
+			`elif [ "$amux_secs" -ge 60 ]; then amux_ran=$(printf '%dm%02ds' $((amux_secs / 60)) $((amux_secs % 60))); ` +
+			`else amux_ran="${amux_secs}s"; fi`,
+		fmt.Sprintf(`printf '\r\n\033[7m %%s exited with status %%s after %%s at %%s \033[0m\r\n' %s "$amux_status" "$amux_ran" "$(date '+%%Y-%%m-%%d %%H:%%M:%%S')"`, label),
+		`printf 'Press r to relaunch, or any other key for a shell. '`,
+		"amux_key=$(stty -icanon -echo min 1 time 0 2>/dev/null; dd bs=1 count=1 2>/dev/null; stty sane 2>/dev/null)",
+		`printf '\r\n'`,
+		`[ "$amux_key" = r ] || break`,
+		"done",
+		"export TERM=xterm-256color",
+		shell,
+	}, "; ")
+}
@@ -0,0 +1,31 @@
+package pty
+
+import (
+	"os/exec"
+	"strings"
+	"testing"
+)
+
+func TestExitBannerCommand(t *testing.T) {
+	script := exitBannerCommand("echo run; (exit 3)", "claude", "echo shell")
+
+	cmd := exec.Command("/bin/sh", "-c", script)
+	cmd.Stdin = strings.NewReader("r")
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("script failed: %v\n%s", err, out)
+	}
+	got := string(out)
+	if n := strings.Count(got, "run\n"); n != 2 {
+		t.Fatalf("agent ran %d times, want a relaunch after r:\n%s", n, got)
+	}
+	if !strings.Contains(got, "claude exited with status 3 after 0s at ") {
+		t.Fatalf("output lacks the exit banner:\n%s", got)
+	}
+	if !strings.HasSuffix(strings.TrimSpace(got), "shell") {
+		t.Fatalf("output should end in the shell once relaunching stops:\n%s", got)
+	}
+	if strings.Contains(got, "\033c") {
+		t.Fatal("the banner must not reset the terminal with RIS, which clears the agent's output")
+	}
+}
@@ -0,0 +1,109 @@
+package pty
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/andyrewlee/amux/internal/config"
+	"github.com/andyrewlee/amux/internal/container"
+	"github.com/andyrewlee/amux/internal/data"
+	"github.com/andyrewlee/amux/internal/devenv"
+	"github.com/andyrewlee/amux/internal/limits"
+	"github.com/andyrewlee/amux/internal/runtimes"
+	"github.com/andyrewlee/amux/internal/sandbox"
+	"github.com/andyrewlee/amux/internal/shellutil"
+)
+
+// Restart backoff, in seconds. A run that lasts restartResetAfter counts as
+// healthy, so the retry budget and the delay start over at its next crash.
+// Variables so tests can run without sleeping.
+var (
+	restartInitialDelay = 2
+	restartMaxDelay     = 60
+	restartResetAfter   = 300
+)
+
+// launchEnv is the environment an agent is launched into: the worktree's
+// declared one when it has one, else its pinned runtime versions.
+type launchEnv struct {
+	devenv   devenv.Kind
+	runtimes []runtimes.Pin
+}
+
+// detectLaunchEnv returns the launch environment of the worktree at root.
+func detectLaunchEnv(root string) launchEnv {
+	if kind := devenv.Detect(root); kind != devenv.None {
+		return launchEnv{devenv: kind}
+	}
+	return launchEnv{runtimes: runtimes.Resolve(root)}
+}
+
+// wrap returns command run in e for the worktree at root.
+func (e launchEnv) wrap(root, command string) string {
+	if e.devenv != devenv.None {
+		return devenv.Wrap(e.devenv, root, command)
+	}
+	return runtimes.Wrap(e.runtimes, command)
+}
+
+// launchCommand returns the command line that runs cfg's assistant in ws:
+// in its launch environment, sandboxed and limited as configured, or inside
+// ws's container when it has one up, and restarted after a crash when
+// cfg.Restart asks for it.
+func launchCommand(ws *data.Workspace, cfg config.AssistantConfig, sandboxKind sandbox.Kind, limitsKind limits.Kind, env launchEnv) (string, error) {
+	confine := func(command string) (string, error) {
+		if ws.Container != nil {
+			// The container is the confinement; it was started without a
+			// network when ws asks for a sandbox.
+			return container.Exec(ws.Container, command), nil
+		}
+		// The environment loads inside the sandbox, so a flake or .envrc
+		// the agent edited can't run code outside it.
+		command, err := sandboxedCommand(ws, env.wrap(ws.Root, command), sandboxKind)
+		if err != nil {
+			return "", err
+		}
+		return limits.Wrap(limitsKind, command, cfg.Limits), nil
+	}
+	command, err := confine(cfg.Command)
+	if err != nil || !cfg.Restart.Enabled() {
+		return command, err
+	}
+	resume, err := confine(resumeCommand(cfg))
+	if err != nil {
+		return "", err
+	}
+	return restartingCommand(command, resume, cfg.Restart.MaxRetries), nil
+}
+
+// resumeCommand is the command a crashed assistant restarts with.
+func resumeCommand(cfg config.AssistantConfig) string {
+	command := cfg.Command
+	if cfg.Restart.ResumeCommand != "" {
+		command = cfg.Restart.ResumeCommand
+	}
+	if cfg.Restart.ResumePrompt != "" {
+		command += " " + shellutil.ShellQuote(cfg.Restart.ResumePrompt)
+	}
+	return command
+}
+
+// restartingCommand runs command and, each time it exits nonzero, resume,
+// up to maxRetries times with exponential backoff. Exits from SIGHUP, SIGINT,
+// and SIGTERM (129, 130, 143) are taken as deliberate and not restarted.
+func restartingCommand(command, resume string, maxRetries int) string {
+	return strings.Join([]string{
+		fmt.Sprintf("amux_n=0; amux_delay=%d; amux_start=$(date +%%s)", restartInitialDelay),
+		command,
+		"amux_code=$?",
+		`while [ "$amux_code" -ne 0 ] && [ "$amux_code" -ne 129 ] && [ "$amux_code" -ne 130 ] && [ "$amux_code" -ne 143 ]; do ` +
+			fmt.Sprintf(`if [ $(($(date +%%s) - amux_start)) -ge %d ]; then amux_n=0; amux_delay=%d; fi; `, restartResetAfter, restartInitialDelay) +
+			fmt.Sprintf(`[ "$amux_n" -ge %d ] && break; `, maxRetries) +
+			"amux_n=$((amux_n + 1)); " +
+			fmt.Sprintf(`printf '\r\n[amux] Agent exited with status %%s; restarting in %%ss (%%s/%d)...\r\n' "$amux_code" "$amux_delay" "$amux_n"; `, maxRetries) +
+			`sleep "$amux_delay"; ` +
+			fmt.Sprintf(`amux_delay=$((amux_delay * 2)); [ "$amux_delay" -gt %d ] && amux_delay=%d; `, restartMaxDelay, restartMaxDelay) +
+			"amux_start=$(date +%s); " + resume + "; amux_code=$?; " +
+			"done",
+	}, "; ")
+}
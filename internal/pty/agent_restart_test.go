@@ -0,0 +1,113 @@
+package pty
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/andyrewlee/amux/internal/config"
+	"github.com/andyrewlee/amux/internal/data"
+	"github.com/andyrewlee/amux/internal/devenv"
+	"github.com/andyrewlee/amux/internal/limits"
+	"github.com/andyrewlee/amux/internal/runtimes"
+	"github.com/andyrewlee/amux/internal/sandbox"
+)
+
+func noRestartDelay(t *testing.T) {
+	t.Helper()
+	old := restartInitialDelay
+	restartInitialDelay = 0
+	t.Cleanup(func() { restartInitialDelay = old })
+}
+
+// runRestarting runs the restart loop around a command that records each run
+// in a file and exits with the next code from codes.
+func runRestarting(t *testing.T, maxRetries int, codes ...string) []string {
+	t.Helper()
+	dir := t.TempDir()
+	log := filepath.Join(dir, "runs")
+	codeFile := filepath.Join(dir, "codes")
+	if err := os.WriteFile(codeFile, []byte(strings.Join(codes, "\n")+"\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	step := func(name string) string {
+		return "echo " + name + " >> " + log + "; c=$(head -n 1 " + codeFile + "); sed -i.bak 1d " + codeFile + "; (exit $c)"
+	}
+	script := restartingCommand(step("start"), step("resume"), maxRetries)
+	if out, err := exec.Command("/bin/sh", "-c", script).CombinedOutput(); err != nil {
+		t.Fatalf("restart loop failed: %v\n%s", err, out)
+	}
+	data, err := os.ReadFile(log)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return strings.Fields(string(data))
+}
+
+func TestRestartingCommand(t *testing.T) {
+	noRestartDelay(t)
+	cases := []struct {
+		name       string
+		maxRetries int
+		codes      []string
+		want       string
+	}{
+		{"clean exit is not restarted", 3, []string{"0"}, "start"},
+		{"crash resumes until clean", 3, []string{"1", "2", "0"}, "start resume resume"},
+		{"retries are capped", 2, []string{"1", "1", "1", "1"}, "start resume resume"},
+		{"interrupt is not restarted", 3, []string{"130"}, "start"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := strings.Join(runRestarting(t, tc.maxRetries, tc.codes...), " "); got != tc.want {
+				t.Fatalf("runs = %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestLaunchCommand(t *testing.T) {
+	ws := data.NewWorkspace("feature", "feature", "main", "/repo", "/repo/.amux/feature")
+	cfg := config.AssistantConfig{Command: "claude"}
+
+	got, err := launchCommand(ws, cfg, sandbox.None, limits.Rlimit, launchEnv{})
+	if err != nil || got != "claude" {
+		t.Fatalf("launchCommand() = %q, %v; want the bare command", got, err)
+	}
+
+	cfg.Restart = config.RestartPolicy{MaxRetries: 3, ResumeCommand: "claude --continue", ResumePrompt: "You crashed; keep going."}
+	cfg.Limits = limits.Limits{Nice: 5}
+	got, err = launchCommand(ws, cfg, sandbox.None, limits.Rlimit, launchEnv{})
+	if err != nil {
+		t.Fatalf("launchCommand() error = %v", err)
+	}
+	if !strings.Contains(got, `'claude --continue '\''You crashed; keep going.'\'''`) {
+		t.Fatalf("launchCommand() = %q, want the resume command with its prompt, under nice", got)
+	}
+	if !strings.HasPrefix(got, "amux_n=0") || !strings.Contains(got, "'nice' '-n' '5' '/bin/sh' '-c' 'claude';") {
+		t.Fatalf("launchCommand() = %q, want both runs limited inside the restart loop", got)
+	}
+
+	// A declared environment loads inside the limits.
+	got, err = launchCommand(ws, config.AssistantConfig{Command: "claude", Limits: limits.Limits{Nice: 5}}, sandbox.None, limits.Rlimit, launchEnv{devenv: devenv.Direnv})
+	if err != nil || !strings.HasPrefix(got, "'nice' '-n' '5' '/bin/sh' '-c' 'if amux_env=$(mktemp); then '\\''direnv'\\'' '\\''exec'\\'' '\\''/repo/.amux/feature'\\''") {
+		t.Fatalf("launchCommand() with direnv = %q, %v", got, err)
+	}
+
+	// Pinned runtimes are activated the same way.
+	env := launchEnv{runtimes: []runtimes.Pin{{Tool: "python", Version: "3.12", Manager: runtimes.Pyenv}}}
+	got, err = launchCommand(ws, config.AssistantConfig{Command: "claude"}, sandbox.None, limits.Rlimit, env)
+	if err != nil || got != `'/bin/sh' '-c' 'PATH="$(pyenv root)/shims:$PATH"; export PATH; claude'` {
+		t.Fatalf("launchCommand() with pyenv = %q, %v", got, err)
+	}
+
+	// In a container, the container confines the agent instead.
+	ws.Container = &data.Container{Runtime: "docker", Name: "amux-x", Workdir: "/workspace"}
+	ws.Sandbox = true
+	got, err = launchCommand(ws, config.AssistantConfig{Command: "claude", Limits: limits.Limits{Nice: 5}}, sandbox.None, limits.Rlimit, launchEnv{devenv: devenv.Direnv})
+	if err != nil || !strings.HasPrefix(got, "'docker' 'exec' '-it'") || !strings.HasSuffix(got, "'amux-x' '/bin/sh' '-lc' 'claude'") {
+		t.Fatalf("launchCommand() in a container = %q, %v", got, err)
+	}
+}
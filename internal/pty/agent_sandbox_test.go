@@ -0,0 +1,38 @@
+package pty
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/andyrewlee/amux/internal/data"
+	"github.com/andyrewlee/amux/internal/sandbox"
+)
+
+func TestSandboxedCommand(t *testing.T) {
+	ws := data.NewWorkspace("feature", "feature", "main", "/repo", "/repo/.amux/feature")
+
+	got, err := sandboxedCommand(ws, "claude", sandbox.None)
+	if err != nil || got != "claude" {
+		t.Fatalf("unsandboxed = %q, %v; want the command unchanged", got, err)
+	}
+
+	ws.Sandbox = true
+	got, err = sandboxedCommand(ws, "claude", sandbox.Bubblewrap)
+	if err != nil {
+		t.Fatalf("sandboxedCommand() error = %v", err)
+	}
+	if !strings.HasPrefix(got, "'bwrap' ") || !strings.Contains(got, "'--unshare-net'") || !strings.HasSuffix(got, "'claude'") {
+		t.Fatalf("sandboxedCommand() = %q, want claude wrapped in bwrap", got)
+	}
+
+	ws.Sandbox, ws.NoNetwork = false, true
+	got, err = sandboxedCommand(ws, "claude", sandbox.Bubblewrap)
+	if err != nil || !strings.Contains(got, "'--unshare-net'") || strings.Contains(got, "--ro-bind") {
+		t.Fatalf("no-network = %q, %v; want the network blocked with a writable filesystem", got, err)
+	}
+
+	if _, err := sandboxedCommand(ws, "claude", sandbox.None); !errors.Is(err, sandbox.ErrUnavailable) {
+		t.Fatalf("missing tool error = %v, want ErrUnavailable so the launch fails closed", err)
+	}
+}
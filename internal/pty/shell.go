@@ -7,6 +7,10 @@ import (
 	"path/filepath"
 	"strings"
 
+	"github.com/andyrewlee/amux/internal/container"
+	"github.com/andyrewlee/amux/internal/data"
+	"github.com/andyrewlee/amux/internal/devenv"
+	"github.com/andyrewlee/amux/internal/gpu"
 	"github.com/andyrewlee/amux/internal/shellutil"
 )
 
@@ -21,6 +25,30 @@ func LoginShellCommandFromEnv() (string, error) {
 	return LoginShellCommand(shell)
 }
 
+// WorkspaceShellCommand is the login shell a tab in ws drops to: one in ws's
+// container when it has one up, else the user's from SHELL, in ws's declared
+// environment when it has one.
+func WorkspaceShellCommand(ws *data.Workspace) (string, error) {
+	if ws != nil && ws.Container != nil {
+		return container.Shell(ws.Container), nil
+	}
+	shell, err := LoginShellCommandFromEnv()
+	if err != nil || ws == nil {
+		return shell, err
+	}
+	return pinGPUs(ws, devenv.Wrap(devenv.Detect(ws.Root), ws.Root, shell)), nil
+}
+
+// pinGPUs prefixes command with an export of the GPUs ws is pinned to, if
+// any. The pin is set in the command rather than the terminal's environment
+// because a running tmux server gives new sessions its own environment.
+func pinGPUs(ws *data.Workspace, command string) string {
+	if ws == nil || ws.GPUs == "" || ws.Container != nil {
+		return command
+	}
+	return gpu.VisibleDevicesEnvVar + "=" + shellutil.ShellQuote(ws.GPUs) + "; export " + gpu.VisibleDevicesEnvVar + "; " + command
+}
+
 // LoginShellCommand validates and quotes shell for use in a sh -c command.
 func LoginShellCommand(shell string) (string, error) {
 	if strings.ContainsRune(shell, 0) {
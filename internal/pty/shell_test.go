@@ -1,6 +1,11 @@
 package pty
 
-import "testing"
+import (
+	"strings"
+	"testing"
+
+	"github.com/andyrewlee/amux/internal/data"
+)
 
 func TestLoginShellCommand(t *testing.T) {
 	tests := []struct {
@@ -67,3 +72,16 @@ func TestLoginShellCommandFromEnvDefault(t *testing.T) {
 		t.Fatalf("LoginShellCommandFromEnv() = %q, want %q", got, want)
 	}
 }
+
+func TestWorkspaceShellCommandPinsGPUs(t *testing.T) {
+	t.Setenv("SHELL", "/bin/zsh")
+	ws := &data.Workspace{Root: t.TempDir(), GPUs: "1"}
+	got, err := WorkspaceShellCommand(ws)
+	if err != nil || got != "CUDA_VISIBLE_DEVICES='1'; export CUDA_VISIBLE_DEVICES; exec '/bin/zsh' -l" {
+		t.Fatalf("WorkspaceShellCommand() = %q, %v", got, err)
+	}
+	ws.Container = &data.Container{Runtime: "docker", Name: "amux-x", Workdir: "/workspace"}
+	if got, _ := WorkspaceShellCommand(ws); strings.Contains(got, "CUDA_VISIBLE_DEVICES") {
+		t.Fatalf("WorkspaceShellCommand() in a container = %q; the host's devices don't apply", got)
+	}
+}
@@ -0,0 +1,261 @@
+// Package report builds a markdown status report of every project's
+// worktrees — branch state against base, uncommitted changes and who owns
+// them, agent tabs, and open pull requests — meant for pasting into a standup note or an issue.
+package report
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/andyrewlee/amux/internal/codeowners"
+	"github.com/andyrewlee/amux/internal/data"
+	"github.com/andyrewlee/amux/internal/forge"
+	"github.com/andyrewlee/amux/internal/git"
+	"github.com/andyrewlee/amux/internal/timeline"
+)
+
+// Test seams for the git, code owner, and pull request lookups.
+var (
+	getStatus   = git.GetStatus
+	aheadBehind = git.AheadBehind
+	loadOwners  = codeowners.Load
+	findPR      = timeline.FindPR
+)
+
+// Agent is an agent tab open in a worktree.
+type Agent struct {
+	Assistant string
+	Status    string // running or stopped
+}
+
+// Worktree is one worktree's state.
+type Worktree struct {
+	Name    string
+	Branch  string
+	Base    string
+	Primary bool
+
+	// Ahead and Behind count commits against the base branch; they are
+	// only meaningful when HasAheadBehind is set.
+	Ahead, Behind  int
+	HasAheadBehind bool
+
+	// Changed counts uncommitted files; Added and Deleted count their
+	// lines. StatusErr is set when git status failed.
+	Changed        int
+	Added, Deleted int
+	StatusErr      bool
+	// Owners are the code owners of the changed files, the owners of the
+	// most files first.
+	Owners []string
+
+	Agents []Agent
+	// Activity is what the worktree's agents are doing, when known (the
+	// TUI knows, the CLI doesn't).
+	Activity string
+	PR       *forge.PR
+}
+
+// Project is a registered repository and its worktrees.
+type Project struct {
+	Name      string
+	Path      string
+	Worktrees []Worktree
+}
+
+// Options tunes Collect.
+type Options struct {
+	// IsAgent reports whether a tab's assistant is an agent rather than,
+	// say, a shell. Nil counts every tab.
+	IsAgent func(assistant string) bool
+	// Activity returns what ws's agents are doing, or "" when unknown.
+	Activity func(ws *data.Workspace) string
+}
+
+// Collect reads the state of every worktree in projects. It runs git and gh
+// for each one, so call it off the UI goroutine.
+func Collect(projects []data.Project, opts Options) []Project {
+	out := make([]Project, 0, len(projects))
+	for i := range projects {
+		p := &projects[i]
+		rp := Project{Name: p.Name, Path: p.Path}
+		for j := range p.Workspaces {
+			rp.Worktrees = append(rp.Worktrees, collectWorktree(&p.Workspaces[j], opts))
+		}
+		out = append(out, rp)
+	}
+	return out
+}
+
+func collectWorktree(ws *data.Workspace, opts Options) Worktree {
+	wt := Worktree{Name: ws.Name, Branch: ws.Branch, Base: ws.Base, Primary: ws.IsPrimaryCheckout()}
+	if st, err := getStatus(ws.Root); err != nil {
+		wt.StatusErr = true
+	} else {
+		wt.Changed = len(st.Staged) + len(st.Unstaged) + len(st.Untracked)
+		wt.Added, wt.Deleted = st.TotalAdded, st.TotalDeleted
+		wt.Owners = changeOwners(ws.Root, st)
+	}
+	if ahead, behind, err := aheadBehind(ws.Root); err == nil {
+		wt.Ahead, wt.Behind, wt.HasAheadBehind = ahead, behind, true
+	}
+	for _, tab := range ws.OpenTabs {
+		if opts.IsAgent != nil && !opts.IsAgent(tab.Assistant) {
+			continue
+		}
+		status := tab.Status
+		if status == "" {
+			status = "running"
+		}
+		wt.Agents = append(wt.Agents, Agent{Assistant: tab.Assistant, Status: status})
+	}
+	if opts.Activity != nil {
+		wt.Activity = opts.Activity(ws)
+	}
+	if !wt.Primary {
+		if pr, ok := findPR(ws); ok {
+			wt.PR = &pr
+		}
+	}
+	return wt
+}
+
+// changeOwners returns the owners of st's changed files, by ownership area.
+func changeOwners(root string, st *git.StatusResult) []string {
+	if st.Clean {
+		return nil
+	}
+	rules, err := loadOwners(root)
+	if err != nil || rules == nil {
+		return nil
+	}
+	var paths []string
+	for _, c := range st.AllChanges() {
+		// A file can be both staged and modified again.
+		if !slices.Contains(paths, c.Path) {
+			paths = append(paths, c.Path)
+		}
+	}
+	var owners []string
+	for _, area := range rules.Areas(paths) {
+		if len(area.Owners) > 0 {
+			owners = append(owners, area.Label())
+		}
+	}
+	return owners
+}
+
+// Markdown renders projects as a markdown report generated at at.
+func Markdown(projects []Project, at time.Time) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# amux status — %s\n", at.Format("2006-01-02 15:04"))
+	if len(projects) == 0 {
+		b.WriteString("\nNo projects registered.\n")
+		return b.String()
+	}
+	for _, p := range projects {
+		fmt.Fprintf(&b, "\n## %s\n\n", p.Name)
+		if len(p.Worktrees) == 0 {
+			b.WriteString("No worktrees.\n")
+			continue
+		}
+		b.WriteString("| Worktree | Branch | vs base | Changes | Owners | Agents | PR |\n")
+		b.WriteString("| --- | --- | --- | --- | --- | --- | --- |\n")
+		for _, wt := range p.Worktrees {
+			fmt.Fprintf(&b, "| %s | %s | %s | %s | %s | %s | %s |\n",
+				cell(worktreeName(wt)), cell(code(wt.Branch)), cell(branchState(wt)),
+				cell(changes(wt)), cell(owners(wt)), cell(agents(wt)), cell(pullRequest(wt)))
+		}
+	}
+	return b.String()
+}
+
+func worktreeName(wt Worktree) string {
+	if wt.Primary {
+		return wt.Name + " (primary)"
+	}
+	return wt.Name
+}
+
+func code(s string) string {
+	if s == "" {
+		return "—"
+	}
+	return "`" + s + "`"
+}
+
+func branchState(wt Worktree) string {
+	if !wt.HasAheadBehind {
+		return "—"
+	}
+	if wt.Ahead == 0 && wt.Behind == 0 {
+		return "up to date"
+	}
+	var parts []string
+	if wt.Ahead > 0 {
+		parts = append(parts, fmt.Sprintf("%d ahead", wt.Ahead))
+	}
+	if wt.Behind > 0 {
+		parts = append(parts, fmt.Sprintf("%d behind", wt.Behind))
+	}
+	return strings.Join(parts, ", ")
+}
+
+func changes(wt Worktree) string {
+	switch {
+	case wt.StatusErr:
+		return "unknown"
+	case wt.Changed == 0:
+		return "clean"
+	}
+	s := fmt.Sprintf("%d files", wt.Changed)
+	if wt.Changed == 1 {
+		s = "1 file"
+	}
+	if wt.Added > 0 || wt.Deleted > 0 {
+		s += fmt.Sprintf(" (+%d/-%d)", wt.Added, wt.Deleted)
+	}
+	return s
+}
+
+func owners(wt Worktree) string {
+	if len(wt.Owners) == 0 {
+		return "—"
+	}
+	return strings.Join(wt.Owners, ", ")
+}
+
+func agents(wt Worktree) string {
+	if len(wt.Agents) == 0 {
+		return "—"
+	}
+	parts := make([]string, len(wt.Agents))
+	for i, ag := range wt.Agents {
+		parts[i] = ag.Assistant + " (" + ag.Status + ")"
+	}
+	s := strings.Join(parts, ", ")
+	if wt.Activity != "" {
+		s += " — " + wt.Activity
+	}
+	return s
+}
+
+func pullRequest(wt Worktree) string {
+	if wt.PR == nil {
+		return "—"
+	}
+	s := fmt.Sprintf("[#%d](%s) %s", wt.PR.Number, wt.PR.URL, wt.PR.Title)
+	if wt.PR.State != "" {
+		s += " (" + strings.ToLower(wt.PR.State) + ")"
+	}
+	return s
+}
+
+// cell escapes a table cell so a pipe or newline in a name or title can't
+// break the row.
+func cell(s string) string {
+	s = strings.ReplaceAll(s, "|", `\|`)
+	return strings.ReplaceAll(s, "\n", " ")
+}
@@ -0,0 +1,126 @@
+package report
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/andyrewlee/amux/internal/codeowners"
+	"github.com/andyrewlee/amux/internal/data"
+	"github.com/andyrewlee/amux/internal/forge"
+	"github.com/andyrewlee/amux/internal/git"
+)
+
+func stubLookups(t *testing.T) {
+	t.Helper()
+	oldStatus, oldAB, oldOwners, oldPR := getStatus, aheadBehind, loadOwners, findPR
+	t.Cleanup(func() { getStatus, aheadBehind, loadOwners, findPR = oldStatus, oldAB, oldOwners, oldPR })
+	getStatus = func(root string) (*git.StatusResult, error) {
+		switch root {
+		case "/repo/feature":
+			return &git.StatusResult{
+				Unstaged:     []git.Change{{Path: "a.go"}},
+				Untracked:    []git.Change{{Path: "b.go"}},
+				TotalAdded:   12,
+				TotalDeleted: 3,
+			}, nil
+		case "/repo/broken":
+			return nil, errors.New("not a git repository")
+		}
+		return &git.StatusResult{Clean: true}, nil
+	}
+	aheadBehind = func(root string) (int, int, error) {
+		if root == "/repo/feature" {
+			return 3, 1, nil
+		}
+		if root == "/repo" {
+			return 0, 0, nil
+		}
+		return 0, 0, errors.New("no base")
+	}
+	loadOwners = func(root string) (*codeowners.Ruleset, error) {
+		return codeowners.Parse(strings.NewReader("*.go @acme/core\nb.go @acme/api\n"))
+	}
+	findPR = func(ws *data.Workspace) (forge.PR, bool) {
+		if ws.Branch != "feature" {
+			return forge.PR{}, false
+		}
+		return forge.PR{Number: 7, Title: "Add a | pipe", URL: "https://example.com/pr/7", State: "OPEN"}, true
+	}
+}
+
+func testProjects() []data.Project {
+	primary := data.Workspace{Name: "repo", Branch: "main", Repo: "/repo", Root: "/repo"}
+	feature := data.Workspace{Name: "feature", Branch: "feature", Base: "origin/main", Repo: "/repo", Root: "/repo/feature",
+		OpenTabs: []data.TabInfo{
+			{Assistant: "claude", Status: "running"},
+			{Assistant: "shell"},
+			{Assistant: "codex", Status: "stopped"},
+		}}
+	broken := data.Workspace{Name: "broken", Branch: "broken", Repo: "/repo", Root: "/repo/broken"}
+	return []data.Project{
+		{Name: "repo", Path: "/repo", Workspaces: []data.Workspace{primary, feature, broken}},
+		{Name: "empty", Path: "/empty"},
+	}
+}
+
+func TestCollect(t *testing.T) {
+	stubLookups(t)
+	projects := Collect(testProjects(), Options{
+		IsAgent: func(assistant string) bool { return assistant != "shell" },
+		Activity: func(ws *data.Workspace) string {
+			if ws.Name == "feature" {
+				return "working"
+			}
+			return ""
+		},
+	})
+	if len(projects) != 2 || len(projects[0].Worktrees) != 3 {
+		t.Fatalf("projects = %+v", projects)
+	}
+	primary, feature, broken := projects[0].Worktrees[0], projects[0].Worktrees[1], projects[0].Worktrees[2]
+	if !primary.Primary || !primary.HasAheadBehind || primary.PR != nil {
+		t.Fatalf("primary = %+v", primary)
+	}
+	if feature.Ahead != 3 || feature.Behind != 1 || feature.Changed != 2 || feature.Added != 12 || feature.Deleted != 3 {
+		t.Fatalf("feature = %+v", feature)
+	}
+	if len(feature.Agents) != 2 || feature.Agents[1] != (Agent{Assistant: "codex", Status: "stopped"}) {
+		t.Fatalf("agents = %+v", feature.Agents)
+	}
+	if feature.Activity != "working" || feature.PR == nil || feature.PR.Number != 7 {
+		t.Fatalf("feature = %+v", feature)
+	}
+	if strings.Join(feature.Owners, ", ") != "@acme/core, @acme/api" || primary.Owners != nil {
+		t.Fatalf("owners = %v, %v", feature.Owners, primary.Owners)
+	}
+	if !broken.StatusErr || broken.HasAheadBehind {
+		t.Fatalf("broken = %+v", broken)
+	}
+}
+
+func TestMarkdown(t *testing.T) {
+	stubLookups(t)
+	at := time.Date(2026, 3, 2, 9, 30, 0, 0, time.UTC)
+	md := Markdown(Collect(testProjects(), Options{}), at)
+	for _, want := range []string{
+		"# amux status — 2026-03-02 09:30",
+		"## repo",
+		"| repo (primary) | `main` | up to date | clean | — | — | — |",
+		"| feature | `feature` | 3 ahead, 1 behind | 2 files (+12/-3) | @acme/core, @acme/api | claude (running), shell (running), codex (stopped) | [#7](https://example.com/pr/7) Add a \\| pipe (open) |",
+		"| broken | `broken` | — | unknown | — | — | — |",
+		"## empty\n\nNo worktrees.",
+	} {
+		if !strings.Contains(md, want) {
+			t.Errorf("report missing %q:\n%s", want, md)
+		}
+	}
+}
+
+func TestMarkdownNoProjects(t *testing.T) {
+	md := Markdown(nil, time.Now())
+	if !strings.Contains(md, "No projects registered.") {
+		t.Fatalf("report = %q", md)
+	}
+}
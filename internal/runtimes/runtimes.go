@@ -0,0 +1,249 @@
+// Package runtimes activates the language runtime versions a worktree pins,
+// in .tool-versions, .nvmrc, .node-version, or .python-version, through the
+// version manager installed to provide them: mise, asdf, nvm, or pyenv.
+//
+// amux launches agents and scripts from a non-interactive shell, which never
+// runs the shell hooks that usually switch versions on cd, so without this an
+// agent's "npm test" runs whatever node happens to be first on PATH.
+package runtimes
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/andyrewlee/amux/internal/shellutil"
+)
+
+// Manager names a version manager.
+type Manager string
+
+const (
+	// None means no installed manager provides a pin.
+	None  Manager = ""
+	Mise  Manager = "mise"
+	Asdf  Manager = "asdf"
+	Nvm   Manager = "nvm"
+	Pyenv Manager = "pyenv"
+)
+
+// EnvVar turns version activation off when set to "off".
+const EnvVar = "AMUX_RUNTIMES"
+
+// Pin is one runtime version a worktree asks for.
+type Pin struct {
+	Tool    string
+	Version string
+	// File is the file that pins it, relative to the worktree.
+	File string
+	// Manager provides the version, or None when none is installed.
+	Manager Manager
+}
+
+var (
+	lookPath = exec.LookPath
+	getenv   = os.Getenv
+	// run runs a command in dir and returns its trimmed output; tests
+	// replace it.
+	run = func(ctx context.Context, dir, name string, args ...string) (string, error) {
+		cmd := exec.CommandContext(ctx, name, args...)
+		cmd.Dir = dir
+		out, err := cmd.Output()
+		return strings.TrimSpace(string(out)), err
+	}
+)
+
+// Resolve returns the versions the worktree at root pins, each with the
+// manager that will provide it. A tool pinned in .tool-versions is not
+// pinned again by a legacy file.
+func Resolve(root string) []Pin {
+	if root == "" || getenv(EnvVar) == "off" {
+		return nil
+	}
+	var pins []Pin
+	pinned := map[string]bool{}
+	add := func(pin Pin, managers ...Manager) {
+		if pinned[canonical(pin.Tool)] {
+			return
+		}
+		pinned[canonical(pin.Tool)] = true
+		pin.Manager = firstInstalled(managers...)
+		pins = append(pins, pin)
+	}
+	for _, pin := range toolVersions(root) {
+		add(pin, Mise, Asdf)
+	}
+	for _, file := range []string{".nvmrc", ".node-version"} {
+		if version := firstLine(filepath.Join(root, file)); version != "" {
+			add(Pin{Tool: "node", Version: version, File: file}, Nvm, Mise)
+		}
+	}
+	if version := firstLine(filepath.Join(root, ".python-version")); version != "" {
+		add(Pin{Tool: "python", Version: version, File: ".python-version"}, Pyenv, Mise)
+	}
+	return pins
+}
+
+// toolVersions parses root's .tool-versions: one "tool version [fallback...]"
+// per line, with # comments.
+func toolVersions(root string) []Pin {
+	raw, err := os.ReadFile(filepath.Join(root, ".tool-versions"))
+	if err != nil {
+		return nil
+	}
+	var pins []Pin
+	scanner := bufio.NewScanner(bytes.NewReader(raw))
+	for scanner.Scan() {
+		line, _, _ := strings.Cut(scanner.Text(), "#")
+		if fields := strings.Fields(line); len(fields) >= 2 {
+			pins = append(pins, Pin{Tool: fields[0], Version: fields[1], File: ".tool-versions"})
+		}
+	}
+	return pins
+}
+
+// firstLine returns the first line of path that isn't blank or a comment.
+func firstLine(path string) string {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+	for _, line := range strings.Split(string(raw), "\n") {
+		if line = strings.TrimSpace(line); line != "" && !strings.HasPrefix(line, "#") {
+			return line
+		}
+	}
+	return ""
+}
+
+// canonical folds asdf's plugin names onto the tools they install.
+func canonical(tool string) string {
+	if tool == "nodejs" {
+		return "node"
+	}
+	return tool
+}
+
+func firstInstalled(managers ...Manager) Manager {
+	for _, m := range managers {
+		if m == Nvm {
+			if _, err := os.Stat(nvmScript()); err == nil {
+				return Nvm
+			}
+			continue
+		}
+		if _, err := lookPath(string(m)); err == nil {
+			return m
+		}
+	}
+	return None
+}
+
+// nvmScript is nvm's entry point; nvm is a shell function, not a binary.
+func nvmScript() string {
+	dir := getenv("NVM_DIR")
+	if dir == "" {
+		dir = filepath.Join(getenv("HOME"), ".nvm")
+	}
+	return filepath.Join(dir, "nvm.sh")
+}
+
+// Wrap returns a shell command line that runs command with pins' versions
+// active: under "mise exec" for the versions mise provides, after putting
+// asdf's and pyenv's shims first on PATH (they read the pin files
+// themselves), and after "nvm use". A version that isn't installed is
+// reported on stderr by its manager, and command runs anyway. With nothing
+// to activate command is returned as is.
+func Wrap(pins []Pin, command string) string {
+	var prelude, miseSpecs []string
+	seen := map[Manager]bool{}
+	for _, pin := range pins {
+		switch pin.Manager {
+		case Mise:
+			miseSpecs = append(miseSpecs, canonical(pin.Tool)+"@"+miseVersion(pin.Version))
+		case Asdf:
+			if !seen[Asdf] {
+				prelude = append(prelude, `PATH="${ASDF_DATA_DIR:-$HOME/.asdf}/shims:$PATH"; export PATH`)
+			}
+		case Pyenv:
+			if !seen[Pyenv] {
+				prelude = append(prelude, `PATH="$(pyenv root)/shims:$PATH"; export PATH`)
+			}
+		case Nvm:
+			prelude = append(prelude, ". "+shellutil.ShellQuote(nvmScript())+" --no-use && nvm use --silent "+shellutil.ShellQuote(pin.Version))
+		}
+		seen[pin.Manager] = true
+	}
+	if len(prelude) == 0 && len(miseSpecs) == 0 {
+		return command
+	}
+	inner := command
+	if len(prelude) > 0 {
+		inner = strings.Join(append(prelude, command), "; ")
+	}
+	args := []string{"/bin/sh", "-c", inner}
+	if len(miseSpecs) > 0 {
+		args = append(append(append([]string{"mise", "exec"}, miseSpecs...), "--"), args...)
+	}
+	return joinQuoted(args)
+}
+
+// miseVersion translates nvm's version syntax ("v20", "lts/*") to mise's.
+func miseVersion(version string) string {
+	if strings.HasPrefix(version, "lts/") {
+		return "lts"
+	}
+	return strings.TrimPrefix(version, "v")
+}
+
+// Installed reports whether pin's version is installed by its manager.
+func Installed(ctx context.Context, root string, pin Pin) (bool, error) {
+	var err error
+	switch pin.Manager {
+	case Mise:
+		_, err = run(ctx, root, "mise", "where", canonical(pin.Tool)+"@"+miseVersion(pin.Version))
+	case Asdf:
+		_, err = run(ctx, root, "asdf", "where", pin.Tool, pin.Version)
+	case Pyenv:
+		_, err = run(ctx, root, "pyenv", "prefix", pin.Version)
+	case Nvm:
+		var out string
+		out, err = run(ctx, root, "/bin/sh", "-c", `. "$0" --no-use && nvm version "$1"`, nvmScript(), pin.Version)
+		if err == nil && (out == "" || out == "N/A") {
+			return false, nil
+		}
+	default:
+		return false, nil
+	}
+	if _, ok := err.(*exec.ExitError); ok {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// InstallHint is the command that installs pin's version.
+func InstallHint(pin Pin) string {
+	switch pin.Manager {
+	case Mise:
+		return "mise install " + canonical(pin.Tool) + "@" + miseVersion(pin.Version)
+	case Asdf:
+		return "asdf install " + pin.Tool + " " + pin.Version
+	case Nvm:
+		return "nvm install " + pin.Version
+	case Pyenv:
+		return "pyenv install " + pin.Version
+	}
+	return ""
+}
+
+func joinQuoted(args []string) string {
+	quoted := make([]string, len(args))
+	for i, arg := range args {
+		quoted[i] = shellutil.ShellQuote(arg)
+	}
+	return strings.Join(quoted, " ")
+}
@@ -0,0 +1,147 @@
+package runtimes
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeFiles(t *testing.T, root string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(root, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// fakeManagers makes the named managers look installed.
+func fakeManagers(t *testing.T, installed ...Manager) {
+	t.Helper()
+	home := t.TempDir()
+	oldLookPath, oldGetenv := lookPath, getenv
+	t.Cleanup(func() { lookPath, getenv = oldLookPath, oldGetenv })
+	env := map[string]string{"HOME": home}
+	getenv = func(key string) string { return env[key] }
+	lookPath = func(name string) (string, error) {
+		for _, m := range installed {
+			if string(m) == name {
+				return "/usr/bin/" + name, nil
+			}
+		}
+		return "", exec.ErrNotFound
+	}
+	for _, m := range installed {
+		if m == Nvm {
+			if err := os.MkdirAll(filepath.Join(home, ".nvm"), 0o755); err != nil {
+				t.Fatal(err)
+			}
+			writeFiles(t, filepath.Join(home, ".nvm"), map[string]string{"nvm.sh": ""})
+		}
+	}
+}
+
+func TestResolve(t *testing.T) {
+	root := t.TempDir()
+	writeFiles(t, root, map[string]string{
+		".tool-versions":  "# pinned\nnodejs 20.11.0 system\nterraform 1.7.0 # infra\n",
+		".nvmrc":          "v18\n",
+		".python-version": "\n3.12.1\n3.11\n",
+	})
+
+	fakeManagers(t, Asdf, Pyenv)
+	got := Resolve(root)
+	want := []Pin{
+		{Tool: "nodejs", Version: "20.11.0", File: ".tool-versions", Manager: Asdf},
+		{Tool: "terraform", Version: "1.7.0", File: ".tool-versions", Manager: Asdf},
+		{Tool: "python", Version: "3.12.1", File: ".python-version", Manager: Pyenv},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("Resolve() = %+v, want %+v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("pin %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+
+	os.Remove(filepath.Join(root, ".tool-versions"))
+	fakeManagers(t, Nvm)
+	got = Resolve(root)
+	if len(got) != 2 || got[0].Manager != Nvm || got[0].Version != "v18" || got[1].Manager != None {
+		t.Fatalf("Resolve() with only nvm = %+v", got)
+	}
+	env := map[string]string{EnvVar: "off"}
+	getenv = func(key string) string { return env[key] }
+	if got := Resolve(root); got != nil {
+		t.Fatalf("Resolve() with %s=off = %+v", EnvVar, got)
+	}
+}
+
+func TestWrap(t *testing.T) {
+	fakeManagers(t)
+	if got := Wrap([]Pin{{Tool: "node", Version: "20"}}, "npm test"); got != "npm test" {
+		t.Fatalf("Wrap() without a manager = %q", got)
+	}
+	got := Wrap([]Pin{
+		{Tool: "nodejs", Version: "lts/*", Manager: Mise},
+		{Tool: "ruby", Version: "3.3.0", Manager: Asdf},
+		{Tool: "terraform", Version: "1.7.0", Manager: Asdf},
+	}, "npm test")
+	want := `'mise' 'exec' 'node@lts' '--' '/bin/sh' '-c' 'PATH="${ASDF_DATA_DIR:-$HOME/.asdf}/shims:$PATH"; export PATH; npm test'`
+	if got != want {
+		t.Fatalf("Wrap() = %q, want %q", got, want)
+	}
+	got = Wrap([]Pin{{Tool: "node", Version: "18", Manager: Nvm}}, "npm test")
+	if !strings.HasPrefix(got, "'/bin/sh' '-c' '. '\\''") || !strings.HasSuffix(got, "nvm use --silent '\\''18'\\''; npm test'") {
+		t.Fatalf("Wrap() with nvm = %q", got)
+	}
+}
+
+func TestWrapRunsCommand(t *testing.T) {
+	home := t.TempDir()
+	shims := filepath.Join(home, ".asdf", "shims")
+	if err := os.MkdirAll(shims, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	writeFiles(t, shims, map[string]string{"node": "#!/bin/sh\necho shimmed node\n"})
+	if err := os.Chmod(filepath.Join(shims, "node"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	cmd := exec.Command("/bin/sh", "-c", Wrap([]Pin{{Tool: "nodejs", Version: "20", Manager: Asdf}}, "node; exit 3"))
+	cmd.Env = append(os.Environ(), "HOME="+home, "ASDF_DATA_DIR=")
+	out, err := cmd.Output()
+	if exitErr, ok := err.(*exec.ExitError); !ok || exitErr.ExitCode() != 3 || strings.TrimSpace(string(out)) != "shimmed node" {
+		t.Fatalf("output = %q, %v; want the shim run and the command's status", out, err)
+	}
+}
+
+func TestInstalled(t *testing.T) {
+	fakeManagers(t)
+	var ran []string
+	oldRun := run
+	t.Cleanup(func() { run = oldRun })
+	run = func(_ context.Context, _, name string, args ...string) (string, error) {
+		ran = append(ran, name+" "+strings.Join(args, " "))
+		if name == "pyenv" {
+			return "", exec.Command("/bin/sh", "-c", "exit 1").Run()
+		}
+		return "/home/me/.local/share/mise/installs/node/20", nil
+	}
+
+	if ok, err := Installed(context.Background(), "/repo", Pin{Tool: "nodejs", Version: "v20", Manager: Mise}); !ok || err != nil {
+		t.Fatalf("Installed(mise) = %v, %v", ok, err)
+	}
+	if ok, err := Installed(context.Background(), "/repo", Pin{Tool: "python", Version: "3.12", Manager: Pyenv}); ok || err != nil {
+		t.Fatalf("Installed(pyenv, missing) = %v, %v", ok, err)
+	}
+	if want := "mise where node@20|pyenv prefix 3.12"; strings.Join(ran, "|") != want {
+		t.Fatalf("ran %q, want %q", strings.Join(ran, "|"), want)
+	}
+	if hint := InstallHint(Pin{Tool: "python", Version: "3.12", Manager: Pyenv}); hint != "pyenv install 3.12" {
+		t.Fatalf("InstallHint() = %q", hint)
+	}
+}
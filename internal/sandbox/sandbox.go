@@ -0,0 +1,170 @@
+// Package sandbox wraps agent commands so they run without network access and
+// can write only inside their worktree.
+//
+// The wrapper is an OS tool run in front of the agent: sandbox-exec(1) with a
+// generated profile on macOS, bubblewrap (bwrap) on Linux. Everything outside
+// the policy's writable paths stays readable, so agents still find their
+// binaries, toolchains, and configuration.
+package sandbox
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/andyrewlee/amux/internal/shellutil"
+)
+
+// Kind names a sandbox tool.
+type Kind string
+
+const (
+	// None means no supported sandbox tool is installed.
+	None Kind = ""
+	// SandboxExec is macOS's sandbox-exec.
+	SandboxExec Kind = "sandbox-exec"
+	// Bubblewrap is bwrap, available on most Linux distributions.
+	Bubblewrap Kind = "bwrap"
+)
+
+// WritableEnvVar lists extra writable paths, separated by the OS path list
+// separator, for agents that must write state outside the worktree (for
+// example ~/.claude).
+const WritableEnvVar = "AMUX_SANDBOX_WRITABLE"
+
+// ErrUnavailable is returned when sandboxing is requested but no tool is
+// installed. Callers should refuse to launch rather than run unsandboxed.
+var ErrUnavailable = errors.New("sandboxing needs sandbox-exec (macOS) or bwrap (Linux), and neither was found")
+
+var (
+	goos     = runtime.GOOS
+	lookPath = exec.LookPath
+)
+
+// Detect returns the sandbox tool usable on this machine, or None.
+func Detect() Kind {
+	switch goos {
+	case "darwin":
+		if _, err := lookPath("sandbox-exec"); err == nil {
+			return SandboxExec
+		}
+	case "linux":
+		if _, err := lookPath("bwrap"); err == nil {
+			return Bubblewrap
+		}
+	}
+	return None
+}
+
+// Policy describes what a sandboxed agent may write. Network access is always
+// denied.
+type Policy struct {
+	Worktree string
+	Writable []string
+	// NetworkOnly blocks the network but leaves the filesystem writable.
+	NetworkOnly bool
+}
+
+// ForWorktree returns the policy for an agent in worktree: the worktree, the
+// repository's .git directory (a linked worktree commits into it), and any
+// paths listed in AMUX_SANDBOX_WRITABLE are writable.
+func ForWorktree(worktree, repo string) Policy {
+	p := Policy{Worktree: worktree}
+	if repo != "" {
+		gitDir := filepath.Join(repo, ".git")
+		if info, err := os.Stat(gitDir); err == nil && info.IsDir() && !strings.HasPrefix(gitDir, worktree+string(filepath.Separator)) {
+			p.Writable = append(p.Writable, gitDir)
+		}
+	}
+	for _, path := range filepath.SplitList(os.Getenv(WritableEnvVar)) {
+		if path = strings.TrimSpace(path); filepath.IsAbs(path) {
+			p.Writable = append(p.Writable, path)
+		}
+	}
+	return p
+}
+
+// Wrap returns a shell command line that runs command (itself a shell command
+// line) under kind with policy p.
+func Wrap(kind Kind, command string, p Policy) (string, error) {
+	if !filepath.IsAbs(p.Worktree) {
+		return "", errors.New("sandbox: worktree must be an absolute path")
+	}
+	switch kind {
+	case Bubblewrap:
+		return joinQuoted(bwrapArgs(command, p)), nil
+	case SandboxExec:
+		return joinQuoted([]string{"sandbox-exec", "-p", macProfile(p), "/bin/sh", "-c", command}), nil
+	default:
+		return "", ErrUnavailable
+	}
+}
+
+func bwrapArgs(command string, p Policy) []string {
+	var args []string
+	if p.NetworkOnly {
+		args = []string{"bwrap", "--bind", "/", "/", "--dev", "/dev", "--proc", "/proc"}
+	} else {
+		args = []string{
+			"bwrap",
+			"--ro-bind", "/", "/",
+			"--dev", "/dev",
+			"--proc", "/proc",
+			"--tmpfs", "/tmp",
+		}
+		for _, path := range append([]string{p.Worktree}, p.Writable...) {
+			args = append(args, "--bind-try", path, path)
+		}
+	}
+	return append(args,
+		"--unshare-net",
+		"--die-with-parent",
+		"--chdir", p.Worktree,
+		"--", "/bin/sh", "-c", command,
+	)
+}
+
+// macProfile allows everything except network access and writes outside the
+// policy's paths, temp directories, and terminal devices.
+func macProfile(p Policy) string {
+	var b strings.Builder
+	b.WriteString("(version 1)\n(allow default)\n(deny network*)\n")
+	if p.NetworkOnly {
+		return b.String()
+	}
+	b.WriteString("(deny file-write*)\n(allow file-write*\n")
+	for _, path := range append([]string{p.Worktree}, p.Writable...) {
+		b.WriteString("  (subpath " + profileString(resolve(path)) + ")\n")
+	}
+	b.WriteString(`  (subpath "/private/tmp")
+  (subpath "/private/var/folders")
+  (literal "/dev/null")
+  (regex #"^/dev/tty")
+  (regex #"^/dev/fd/"))
+`)
+	return b.String()
+}
+
+// resolve follows symlinks, since the sandbox matches real paths (/var is a
+// link to /private/var on macOS).
+func resolve(path string) string {
+	if real, err := filepath.EvalSymlinks(path); err == nil {
+		return real
+	}
+	return path
+}
+
+func profileString(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+}
+
+func joinQuoted(args []string) string {
+	quoted := make([]string, len(args))
+	for i, arg := range args {
+		quoted[i] = shellutil.ShellQuote(arg)
+	}
+	return strings.Join(quoted, " ")
+}
@@ -0,0 +1,128 @@
+package sandbox
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func stubPlatform(t *testing.T, os string, tools ...string) {
+	t.Helper()
+	oldGOOS, oldLookPath := goos, lookPath
+	t.Cleanup(func() { goos, lookPath = oldGOOS, oldLookPath })
+	goos = os
+	lookPath = func(name string) (string, error) {
+		for _, tool := range tools {
+			if tool == name {
+				return "/usr/bin/" + name, nil
+			}
+		}
+		return "", exec.ErrNotFound
+	}
+}
+
+func TestDetect(t *testing.T) {
+	cases := []struct {
+		goos  string
+		tools []string
+		want  Kind
+	}{
+		{"darwin", []string{"sandbox-exec"}, SandboxExec},
+		{"linux", []string{"bwrap"}, Bubblewrap},
+		{"linux", nil, None},
+		{"darwin", []string{"bwrap"}, None},
+		{"freebsd", []string{"bwrap"}, None},
+	}
+	for _, tc := range cases {
+		stubPlatform(t, tc.goos, tc.tools...)
+		if got := Detect(); got != tc.want {
+			t.Errorf("Detect() on %s with %v = %q, want %q", tc.goos, tc.tools, got, tc.want)
+		}
+	}
+}
+
+func TestWrapBubblewrap(t *testing.T) {
+	cmd, err := Wrap(Bubblewrap, "claude --resume 'x'", Policy{Worktree: "/w/feature", Writable: []string{"/repo/.git"}})
+	if err != nil {
+		t.Fatalf("Wrap() error = %v", err)
+	}
+	for _, want := range []string{
+		"'bwrap' '--ro-bind' '/' '/'",
+		"'--bind-try' '/w/feature' '/w/feature'",
+		"'--bind-try' '/repo/.git' '/repo/.git'",
+		"'--unshare-net'",
+		"'--chdir' '/w/feature'",
+		`'/bin/sh' '-c' 'claude --resume '\''x'\'''`,
+	} {
+		if !strings.Contains(cmd, want) {
+			t.Errorf("Wrap() = %s\nmissing %s", cmd, want)
+		}
+	}
+}
+
+func TestWrapSandboxExec(t *testing.T) {
+	cmd, err := Wrap(SandboxExec, "codex", Policy{Worktree: "/nonexistent/w\"t"})
+	if err != nil {
+		t.Fatalf("Wrap() error = %v", err)
+	}
+	for _, want := range []string{
+		"'sandbox-exec' '-p' '(version 1)",
+		"(deny network*)",
+		"(deny file-write*)",
+		`(subpath "/nonexistent/w\"t")`,
+		"'/bin/sh' '-c' 'codex'",
+	} {
+		if !strings.Contains(cmd, want) {
+			t.Errorf("Wrap() = %s\nmissing %s", cmd, want)
+		}
+	}
+}
+
+func TestWrapRejectsMissingToolAndRelativeWorktree(t *testing.T) {
+	if _, err := Wrap(None, "claude", Policy{Worktree: "/w"}); !errors.Is(err, ErrUnavailable) {
+		t.Fatalf("Wrap(None) error = %v, want ErrUnavailable", err)
+	}
+	if _, err := Wrap(Bubblewrap, "claude", Policy{Worktree: "w"}); err == nil {
+		t.Fatal("Wrap() should reject a relative worktree")
+	}
+}
+
+func TestForWorktree(t *testing.T) {
+	repo := t.TempDir()
+	if err := os.Mkdir(filepath.Join(repo, ".git"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv(WritableEnvVar, "/home/u/.claude"+string(os.PathListSeparator)+"relative")
+
+	p := ForWorktree("/w/feature", repo)
+	want := []string{filepath.Join(repo, ".git"), "/home/u/.claude"}
+	if p.Worktree != "/w/feature" || strings.Join(p.Writable, ",") != strings.Join(want, ",") {
+		t.Fatalf("ForWorktree() = %#v, want writable %v", p, want)
+	}
+
+	// The primary checkout's .git is already inside the worktree.
+	if p := ForWorktree(repo, repo); len(p.Writable) != 1 {
+		t.Fatalf("ForWorktree(primary) writable = %v, want only the env path", p.Writable)
+	}
+}
+
+func TestWrapNetworkOnly(t *testing.T) {
+	p := Policy{Worktree: "/w/feature", NetworkOnly: true}
+	cmd, err := Wrap(Bubblewrap, "claude", p)
+	if err != nil {
+		t.Fatalf("Wrap() error = %v", err)
+	}
+	if !strings.Contains(cmd, "'--bind' '/' '/'") || strings.Contains(cmd, "--ro-bind") || !strings.Contains(cmd, "'--unshare-net'") {
+		t.Fatalf("bwrap network-only = %s, want a writable root without network", cmd)
+	}
+	cmd, err = Wrap(SandboxExec, "claude", p)
+	if err != nil {
+		t.Fatalf("Wrap() error = %v", err)
+	}
+	if !strings.Contains(cmd, "(deny network*)") || strings.Contains(cmd, "file-write") {
+		t.Fatalf("sandbox-exec network-only = %s, want only the network denied", cmd)
+	}
+}
@@ -0,0 +1,67 @@
+package schedule
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSpecNext(t *testing.T) {
+	// Wednesday 2026-10-14 10:17.
+	from := time.Date(2026, 10, 14, 10, 17, 30, 0, time.UTC)
+	tests := []struct {
+		expr string
+		want time.Time
+	}{
+		{"* * * * *", time.Date(2026, 10, 14, 10, 18, 0, 0, time.UTC)},
+		{"*/15 * * * *", time.Date(2026, 10, 14, 10, 30, 0, 0, time.UTC)},
+		{"0 3 * * *", time.Date(2026, 10, 15, 3, 0, 0, 0, time.UTC)},
+		{"@hourly", time.Date(2026, 10, 14, 11, 0, 0, 0, time.UTC)},
+		{"@weekly", time.Date(2026, 10, 18, 0, 0, 0, 0, time.UTC)},
+		{"30 2 * * sat,sun", time.Date(2026, 10, 17, 2, 30, 0, 0, time.UTC)},
+		{"0 9 * * 7", time.Date(2026, 10, 18, 9, 0, 0, 0, time.UTC)},
+		{"0 0 1 jan,jul *", time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{"0 12 10-20/5 * *", time.Date(2026, 10, 15, 12, 0, 0, 0, time.UTC)},
+		// Both day fields restricted: the 1st of the month or any Friday.
+		{"0 8 1 * fri", time.Date(2026, 10, 16, 8, 0, 0, 0, time.UTC)},
+		{"0 0 29 2 *", time.Date(2028, 2, 29, 0, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		spec, err := ParseSpec(tt.expr)
+		if err != nil {
+			t.Fatalf("ParseSpec(%q) error = %v", tt.expr, err)
+		}
+		if got := spec.Next(from); !got.Equal(tt.want) {
+			t.Errorf("ParseSpec(%q).Next() = %v, want %v", tt.expr, got, tt.want)
+		}
+	}
+}
+
+func TestSpecNextNeverMatches(t *testing.T) {
+	spec, err := ParseSpec("0 0 30 2 *")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := spec.Next(time.Now()); !got.IsZero() {
+		t.Fatalf("Next() = %v, want the zero time", got)
+	}
+}
+
+func TestParseSpecErrors(t *testing.T) {
+	for _, expr := range []string{
+		"",
+		"* * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"*/0 * * * *",
+		"10-5 * * * *",
+		"* * * foo *",
+		"@reboot",
+	} {
+		if _, err := ParseSpec(expr); err == nil {
+			t.Errorf("ParseSpec(%q) succeeded, want an error", expr)
+		}
+	}
+}
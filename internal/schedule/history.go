@@ -0,0 +1,96 @@
+package schedule
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// HistoryFileName is the run history's name inside the amux home directory.
+const HistoryFileName = "schedule-history.jsonl"
+
+// Run is one scheduled launch, successful or not.
+type Run struct {
+	Task      string    `json:"task"`
+	Started   time.Time `json:"started"`
+	Workspace string    `json:"workspace,omitempty"`
+	Session   string    `json:"session,omitempty"`
+	Error     string    `json:"error,omitempty"`
+}
+
+// Failed reports whether the launch failed.
+func (r Run) Failed() bool {
+	return r.Error != ""
+}
+
+// HistoryPath returns the run history path for an amux home directory.
+func HistoryPath(home string) string {
+	return filepath.Join(home, HistoryFileName)
+}
+
+// AppendRun appends run to the history file at path, creating it if needed.
+func AppendRun(path string, run Run) error {
+	line, err := json.Marshal(run)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return fmt.Errorf("schedule history: %w", err)
+	}
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("schedule history: %w", err)
+	}
+	_, writeErr := file.Write(append(line, '\n'))
+	return errors.Join(writeErr, file.Close())
+}
+
+// ReadRuns returns the runs in the history file at path, oldest first. A
+// missing file has no runs; malformed lines are skipped.
+func ReadRuns(path string) ([]Run, error) {
+	file, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	var runs []Run
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var run Run
+		if json.Unmarshal(scanner.Bytes(), &run) == nil {
+			runs = append(runs, run)
+		}
+	}
+	return runs, scanner.Err()
+}
+
+// WriteRuns prints runs one per line in local time.
+func WriteRuns(w io.Writer, runs []Run) error {
+	for _, r := range runs {
+		outcome := "ok  " + r.Workspace
+		if r.Session != "" {
+			outcome += " (" + r.Session + ")"
+		}
+		if r.Failed() {
+			outcome = "FAILED  " + r.Error
+		}
+		line := strings.Join([]string{
+			r.Started.Local().Format("2006-01-02 15:04:05"),
+			r.Task,
+			outcome,
+		}, "  ")
+		if _, err := fmt.Fprintln(w, line); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,155 @@
+package schedule
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/andyrewlee/amux/internal/config"
+	"github.com/andyrewlee/amux/internal/data"
+	"github.com/andyrewlee/amux/internal/git"
+	"github.com/andyrewlee/amux/internal/process"
+	"github.com/andyrewlee/amux/internal/pty"
+	"github.com/andyrewlee/amux/internal/tmux"
+	"github.com/andyrewlee/amux/internal/validation"
+)
+
+// AgentLauncher starts scheduled agents the way the amux UI starts agent
+// tabs, creating a fresh workspace first when the task names none.
+type AgentLauncher struct {
+	Config  *config.Config
+	Store   *data.WorkspaceStore
+	Agents  *pty.AgentManager
+	Scripts *process.ScriptRunner
+}
+
+// NewAgentLauncher returns a launcher using cfg's paths and defaults.
+func NewAgentLauncher(cfg *config.Config) *AgentLauncher {
+	store := data.NewWorkspaceStore(cfg.Paths.MetadataRoot)
+	store.SetDefaultAssistant(cfg.ResolvedDefaultAssistant())
+	return &AgentLauncher{
+		Config:  cfg,
+		Store:   store,
+		Agents:  pty.NewAgentManager(cfg),
+		Scripts: process.NewScriptRunner(cfg.PortStart, cfg.PortRangeSize),
+	}
+}
+
+// Launch starts task's agent in its workspace. A fresh workspace whose setup
+// fails is kept, so the failure can be looked into from amux.
+func (l *AgentLauncher) Launch(ctx context.Context, task config.ScheduledTask) (Launch, error) {
+	if err := ctx.Err(); err != nil {
+		return Launch{}, err
+	}
+	project, err := l.project(task.Project)
+	if err != nil {
+		return Launch{}, err
+	}
+	now := time.Now()
+	var ws *data.Workspace
+	if task.Workspace != "" {
+		ws, err = l.existingWorkspace(project, task.Workspace)
+	} else {
+		ws, err = l.freshWorkspace(project, task, now)
+	}
+	if err != nil {
+		return Launch{}, err
+	}
+	launch := Launch{Workspace: ws.Name}
+
+	assistant := task.Assistant
+	if assistant == "" {
+		assistant = strings.TrimSpace(ws.Assistant)
+	}
+	if assistant == "" {
+		assistant = l.Config.ResolvedDefaultAssistant()
+	}
+	if err := validation.ValidateAssistant(assistant); err != nil {
+		return launch, err
+	}
+
+	tabID := "schedule-" + task.Name + "-" + strconv.FormatInt(now.Unix(), 10)
+	launch.Session = tmux.SessionName("amux", string(ws.ID()), tabID)
+	tags := tmux.SessionTags{
+		WorkspaceID: string(ws.ID()),
+		TabID:       tabID,
+		Type:        "agent",
+		Assistant:   assistant,
+		CreatedAt:   now.Unix(),
+	}
+	if err := l.Agents.StartDetachedAgent(ws, pty.AgentType(assistant), launch.Session, task.Prompt, tags); err != nil {
+		return launch, err
+	}
+	return launch, nil
+}
+
+// project returns the registered project at path.
+func (l *AgentLauncher) project(path string) (*data.Project, error) {
+	paths, err := data.NewRegistry(l.Config.Paths.RegistryPath).Projects()
+	if err != nil {
+		return nil, fmt.Errorf("loading projects: %w", err)
+	}
+	path = filepath.Clean(path)
+	if !slices.ContainsFunc(paths, func(p string) bool { return filepath.Clean(p) == path }) {
+		return nil, fmt.Errorf("project %s is not added to amux", path)
+	}
+	return data.NewProject(path), nil
+}
+
+func (l *AgentLauncher) existingWorkspace(project *data.Project, name string) (*data.Workspace, error) {
+	workspaces, err := l.Store.ListByRepo(project.Path)
+	if err != nil {
+		return nil, fmt.Errorf("loading workspaces: %w", err)
+	}
+	for _, ws := range workspaces {
+		if ws.Name == name {
+			return ws, nil
+		}
+	}
+	return nil, fmt.Errorf("project %s has no workspace %q", project.Name, name)
+}
+
+// freshWorkspace creates a workspace named for the task and the time, runs
+// its setup scripts, and saves it so amux lists it.
+func (l *AgentLauncher) freshWorkspace(project *data.Project, task config.ScheduledTask, now time.Time) (*data.Workspace, error) {
+	name := task.Name + "-" + now.Format("0102-1504")
+	if err := validation.ValidateWorkspaceName(name); err != nil {
+		return nil, err
+	}
+	base := task.Base
+	if base == "" {
+		if base, _ = git.GetBaseBranch(project.Path); base == "" {
+			base = "HEAD"
+		}
+	}
+	if err := validation.ValidateBaseRef(base); err != nil {
+		return nil, err
+	}
+	root := filepath.Join(l.Config.Paths.WorkspacesRoot, project.Name, name)
+	ws := data.NewWorkspace(name, name, base, project.Path, root)
+	if task.Assistant != "" {
+		ws.Assistant = task.Assistant
+	}
+
+	if err := git.CreateWorkspace(project.Path, root, name, base); err != nil {
+		return nil, fmt.Errorf("creating workspace %s: %w", name, err)
+	}
+	if err := l.Store.Save(ws); err != nil {
+		_ = git.RemoveWorkspace(project.Path, root)
+		_ = git.DeleteBranch(project.Path, name)
+		return nil, fmt.Errorf("saving workspace %s: %w", name, err)
+	}
+	if err := l.Scripts.RunSetup(ws); err != nil {
+		var untrusted *process.ScriptsNotTrustedError
+		if errors.As(err, &untrusted) {
+			return nil, fmt.Errorf("setup scripts in %s are not trusted yet; open the project in amux to review them", project.Name)
+		}
+		return nil, fmt.Errorf("setting up workspace %s: %w", name, err)
+	}
+	return ws, nil
+}
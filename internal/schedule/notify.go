@@ -0,0 +1,14 @@
+package schedule
+
+import "github.com/andyrewlee/amux/internal/notify"
+
+// NotifyFailure raises a desktop notification for a failed run. It is best
+// effort: without a notifier installed the failure is only in the history and
+// the scheduler output.
+func NotifyFailure(run Run) error {
+	if !run.Failed() {
+		return nil
+	}
+	_, err := notify.Desktop("amux: "+run.Task+" failed", run.Error)
+	return err
+}
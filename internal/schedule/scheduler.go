@@ -0,0 +1,177 @@
+// Package schedule launches agents on cron schedules defined in the
+// "schedules" section of config.json. It runs only inside `amux schedule
+// run`, a long-lived process that stands in for a daemon: amux itself
+// starts nothing while the UI is closed.
+//
+// Each launch starts the agent in a detached tmux session tagged for its
+// workspace, so the next amux UI to open that workspace adopts it as a tab.
+// Every launch is appended to a run history, and failures are passed to
+// the caller to report.
+package schedule
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/andyrewlee/amux/internal/config"
+	"github.com/andyrewlee/amux/internal/logging"
+)
+
+// Launch is where a task's agent was started.
+type Launch struct {
+	Workspace string
+	Session   string
+}
+
+// Launcher starts the agent for a task.
+type Launcher interface {
+	Launch(ctx context.Context, task config.ScheduledTask) (Launch, error)
+}
+
+// Config configures a Scheduler.
+type Config struct {
+	Tasks    []config.ScheduledTask
+	Launcher Launcher
+	// HistoryPath is the run history file; empty keeps no history.
+	HistoryPath string
+	// OnRun, if set, is called after every launch.
+	OnRun func(Run)
+}
+
+// Entry is a task and the next time it runs.
+type Entry struct {
+	Task config.ScheduledTask
+	Next time.Time
+}
+
+type scheduledTask struct {
+	task config.ScheduledTask
+	spec Spec
+	next time.Time
+}
+
+// Scheduler launches tasks when their schedules come due.
+type Scheduler struct {
+	cfg   Config
+	tasks []*scheduledTask
+	now   func() time.Time
+}
+
+// New validates the tasks and returns a scheduler for them. Every invalid
+// task is reported, so one broken entry does not hide the next.
+func New(cfg Config) (*Scheduler, error) {
+	if cfg.Launcher == nil {
+		return nil, errors.New("schedule: launcher is required")
+	}
+	s := &Scheduler{cfg: cfg, now: time.Now}
+	var errs []error
+	seen := make(map[string]bool)
+	for i, task := range cfg.Tasks {
+		label := task.Name
+		if label == "" {
+			label = fmt.Sprintf("#%d", i+1)
+		}
+		spec, err := validateTask(task, seen)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("schedule %s: %w", label, err))
+			continue
+		}
+		seen[task.Name] = true
+		s.tasks = append(s.tasks, &scheduledTask{task: task, spec: spec})
+	}
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func validateTask(task config.ScheduledTask, seen map[string]bool) (Spec, error) {
+	switch {
+	case task.Name == "":
+		return Spec{}, errors.New("name is required")
+	case seen[task.Name]:
+		return Spec{}, errors.New("name is used by another schedule")
+	case task.Project == "":
+		return Spec{}, errors.New("project is required")
+	case task.Prompt == "":
+		return Spec{}, errors.New("prompt is required")
+	}
+	spec, err := ParseSpec(task.Cron)
+	if err != nil {
+		return Spec{}, err
+	}
+	if spec.Next(time.Now()).IsZero() {
+		return Spec{}, fmt.Errorf("cron %q never matches", task.Cron)
+	}
+	return spec, nil
+}
+
+// Entries returns the tasks ordered by their next run after now.
+func (s *Scheduler) Entries(now time.Time) []Entry {
+	entries := make([]Entry, 0, len(s.tasks))
+	for _, t := range s.tasks {
+		entries = append(entries, Entry{Task: t.task, Next: t.spec.Next(now)})
+	}
+	sort.SliceStable(entries, func(i, j int) bool { return entries[i].Next.Before(entries[j].Next) })
+	return entries
+}
+
+// Run launches tasks as they come due until ctx is done. A run missed while
+// the machine slept happens once on waking rather than once per missed time.
+func (s *Scheduler) Run(ctx context.Context) error {
+	now := s.now()
+	for _, t := range s.tasks {
+		t.next = t.spec.Next(now)
+	}
+	if len(s.tasks) == 0 {
+		<-ctx.Done()
+		return nil
+	}
+	for {
+		wake := s.tasks[0].next
+		for _, t := range s.tasks[1:] {
+			if t.next.Before(wake) {
+				wake = t.next
+			}
+		}
+		timer := time.NewTimer(time.Until(wake))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil
+		case <-timer.C:
+		}
+		s.runDue(ctx, s.now())
+	}
+}
+
+// runDue launches each task due at now and schedules its next run.
+func (s *Scheduler) runDue(ctx context.Context, now time.Time) {
+	for _, t := range s.tasks {
+		if t.next.After(now) {
+			continue
+		}
+		s.launch(ctx, t.task, now)
+		t.next = t.spec.Next(s.now())
+	}
+}
+
+func (s *Scheduler) launch(ctx context.Context, task config.ScheduledTask, started time.Time) {
+	run := Run{Task: task.Name, Started: started.UTC()}
+	launch, err := s.cfg.Launcher.Launch(ctx, task)
+	run.Workspace, run.Session = launch.Workspace, launch.Session
+	if err != nil {
+		run.Error = err.Error()
+	}
+	if s.cfg.HistoryPath != "" {
+		if err := AppendRun(s.cfg.HistoryPath, run); err != nil {
+			logging.Warn("schedule: recording run of %s: %v", task.Name, err)
+		}
+	}
+	if s.cfg.OnRun != nil {
+		s.cfg.OnRun(run)
+	}
+}
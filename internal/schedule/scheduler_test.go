@@ -0,0 +1,120 @@
+package schedule
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/andyrewlee/amux/internal/config"
+)
+
+type fakeLauncher struct {
+	launched []string
+	fail     map[string]error
+}
+
+func (f *fakeLauncher) Launch(_ context.Context, task config.ScheduledTask) (Launch, error) {
+	f.launched = append(f.launched, task.Name)
+	if err := f.fail[task.Name]; err != nil {
+		return Launch{}, err
+	}
+	return Launch{Workspace: task.Name + "-ws", Session: "amux-" + task.Name}, nil
+}
+
+func task(name, cron string) config.ScheduledTask {
+	return config.ScheduledTask{Name: name, Cron: cron, Project: "/repo", Prompt: "do " + name}
+}
+
+func TestNewValidatesTasks(t *testing.T) {
+	_, err := New(Config{Launcher: &fakeLauncher{}, Tasks: []config.ScheduledTask{
+		task("deps", "@daily"),
+		task("deps", "@hourly"),
+		{Name: "noprompt", Cron: "@daily", Project: "/repo"},
+		task("badcron", "61 * * * *"),
+		{Cron: "@daily", Project: "/repo", Prompt: "x"},
+	}})
+	if err == nil {
+		t.Fatal("New() succeeded, want errors")
+	}
+	for _, want := range []string{"deps: name is used", "noprompt: prompt is required", "badcron: cron", "#5: name is required"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("New() error = %q, want it to mention %q", err, want)
+		}
+	}
+}
+
+func TestSchedulerRunsDueTasksAndRecordsHistory(t *testing.T) {
+	launcher := &fakeLauncher{fail: map[string]error{"lint": errors.New("project /repo is not added to amux")}}
+	historyPath := HistoryPath(t.TempDir())
+	var reported []Run
+	s, err := New(Config{
+		Tasks:       []config.ScheduledTask{task("deps", "0 3 * * *"), task("lint", "*/30 * * * *"), task("weekly", "@weekly")},
+		Launcher:    launcher,
+		HistoryPath: historyPath,
+		OnRun:       func(r Run) { reported = append(reported, r) },
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Wednesday 02:45: deps is next at 03:00, lint at 03:00 too.
+	start := time.Date(2026, 10, 14, 2, 45, 0, 0, time.UTC)
+	entries := s.Entries(start)
+	if entries[2].Task.Name != "weekly" {
+		t.Fatalf("Entries() = %+v, want weekly last", entries)
+	}
+	for _, st := range s.tasks {
+		st.next = st.spec.Next(start)
+	}
+
+	due := start.Add(15 * time.Minute)
+	s.now = func() time.Time { return due }
+	s.runDue(context.Background(), due)
+	if strings.Join(launcher.launched, ",") != "deps,lint" {
+		t.Fatalf("launched %v, want deps and lint", launcher.launched)
+	}
+	if next := s.tasks[1].next; !next.Equal(due.Add(30 * time.Minute)) {
+		t.Fatalf("lint next = %v, want 03:30", next)
+	}
+	if len(reported) != 2 || reported[0].Failed() || !reported[1].Failed() {
+		t.Fatalf("reported = %+v, want deps ok and lint failed", reported)
+	}
+
+	runs, err := ReadRuns(historyPath)
+	if err != nil || len(runs) != 2 {
+		t.Fatalf("ReadRuns() = %+v, %v", runs, err)
+	}
+	if runs[0].Workspace != "deps-ws" || runs[0].Session != "amux-deps" || runs[1].Error == "" {
+		t.Fatalf("runs = %+v", runs)
+	}
+	var out bytes.Buffer
+	if err := WriteRuns(&out, runs); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out.String(), "deps  ok  deps-ws (amux-deps)") || !strings.Contains(out.String(), "lint  FAILED  project /repo") {
+		t.Fatalf("WriteRuns() = %q", out.String())
+	}
+}
+
+func TestSchedulerRunStopsWithContext(t *testing.T) {
+	s, err := New(Config{Tasks: []config.ScheduledTask{task("deps", "@yearly")}, Launcher: &fakeLauncher{}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := s.Run(ctx); err != nil {
+		t.Fatalf("Run() = %v", err)
+	}
+}
+
+func TestReadRunsMissingFile(t *testing.T) {
+	runs, err := ReadRuns(filepath.Join(t.TempDir(), "missing.jsonl"))
+	if err != nil || runs != nil {
+		t.Fatalf("ReadRuns() = %v, %v; want nothing", runs, err)
+	}
+}
@@ -0,0 +1,190 @@
+// Package search runs ripgrep over a worktree and collects its matches with
+// the lines around each, for amux's search panel.
+//
+// The query is matched literally, case-insensitively unless it has an upper
+// case letter, and ripgrep's usual filters apply: ignored, hidden, and binary
+// files are skipped.
+package search
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"strings"
+)
+
+// ErrNoRipgrep is returned when rg is not on PATH.
+var ErrNoRipgrep = errors.New("ripgrep (rg) is not installed")
+
+// ContextLines is how many lines are kept on each side of a match.
+const ContextLines = 3
+
+// maxLineBytes bounds the text kept for one line, such as a line of minified
+// code.
+const maxLineBytes = 400
+
+// maxEventBytes bounds one line of ripgrep's JSON output; --max-filesize
+// keeps lines well under it.
+const maxEventBytes = 8 << 20
+
+// Line is one line of a file.
+type Line struct {
+	// Number is one-based.
+	Number int
+	Text   string
+}
+
+// Match is one matching line.
+type Match struct {
+	// Path is relative to the searched root.
+	Path string
+	// Line is one-based; Column is the one-based byte offset of the first
+	// match in the line.
+	Line   int
+	Column int
+	Text   string
+	// Context is the lines around the match, the match included, in order.
+	Context []Line
+}
+
+// Result is what one search found.
+type Result struct {
+	Matches []Match
+	// Truncated is set when more than the limit matched.
+	Truncated bool
+}
+
+// Run searches the files under root for query and returns at most limit
+// matches.
+func Run(ctx context.Context, root, query string, limit int) (Result, error) {
+	if query == "" {
+		return Result{}, nil
+	}
+	rg, err := exec.LookPath("rg")
+	if err != nil {
+		return Result{}, ErrNoRipgrep
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	// #nosec G204 -- rg runs as argv, without a shell; the query is a literal.
+	cmd := exec.CommandContext(ctx, rg, "--json", "--fixed-strings", "--smart-case",
+		"--context", fmt.Sprint(ContextLines), "--max-filesize", "1M", "--", query, ".")
+	cmd.Dir = root
+	var stderr strings.Builder
+	cmd.Stderr = &stderr
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return Result{}, err
+	}
+	if err := cmd.Start(); err != nil {
+		return Result{}, err
+	}
+	result, parseErr := parse(stdout, limit)
+	if result.Truncated || parseErr != nil {
+		// Stop rg rather than wait for matches that will not be shown.
+		cancel()
+	}
+	waitErr := cmd.Wait()
+	if parseErr != nil {
+		return Result{}, parseErr
+	}
+	var exitErr *exec.ExitError
+	switch {
+	case result.Truncated, waitErr == nil:
+	case errors.As(waitErr, &exitErr) && exitErr.ExitCode() == 1:
+		// No matches.
+	default:
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return Result{}, fmt.Errorf("rg: %s", msg)
+		}
+		return Result{}, fmt.Errorf("rg: %w", waitErr)
+	}
+	return result, nil
+}
+
+// event is one line of `rg --json` output. Paths and lines that are not
+// valid UTF-8 come as base64 "bytes" instead of "text" and are skipped.
+type event struct {
+	Type string `json:"type"`
+	Data struct {
+		Path struct {
+			Text string `json:"text"`
+		} `json:"path"`
+		Lines struct {
+			Text string `json:"text"`
+		} `json:"lines"`
+		LineNumber int `json:"line_number"`
+		Submatches []struct {
+			Start int `json:"start"`
+		} `json:"submatches"`
+	} `json:"data"`
+}
+
+// parse reads `rg --json --context` output, stopping after limit matches.
+func parse(r io.Reader, limit int) (Result, error) {
+	var result Result
+	// lines holds the current file's lines seen so far, to give each of
+	// its matches its context once the file ends.
+	lines := make(map[int]string)
+	fileStart := 0
+	endFile := func() {
+		for i := fileStart; i < len(result.Matches); i++ {
+			m := &result.Matches[i]
+			for n := m.Line - ContextLines; n <= m.Line+ContextLines; n++ {
+				if text, ok := lines[n]; ok {
+					m.Context = append(m.Context, Line{Number: n, Text: text})
+				}
+			}
+		}
+		clear(lines)
+		fileStart = len(result.Matches)
+	}
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64<<10), maxEventBytes)
+	for scanner.Scan() {
+		var e event
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			return Result{}, fmt.Errorf("rg output: %w", err)
+		}
+		path := strings.TrimPrefix(e.Data.Path.Text, "./")
+		switch e.Type {
+		case "context", "match":
+			if path == "" || e.Data.LineNumber <= 0 {
+				continue
+			}
+			text := trimLine(e.Data.Lines.Text)
+			lines[e.Data.LineNumber] = text
+			if e.Type == "context" {
+				continue
+			}
+			if limit > 0 && len(result.Matches) == limit {
+				result.Truncated = true
+				endFile()
+				return result, nil
+			}
+			m := Match{Path: path, Line: e.Data.LineNumber, Column: 1, Text: text}
+			if len(e.Data.Submatches) > 0 {
+				m.Column = e.Data.Submatches[0].Start + 1
+			}
+			result.Matches = append(result.Matches, m)
+		case "end":
+			endFile()
+		}
+	}
+	endFile()
+	return result, scanner.Err()
+}
+
+// trimLine drops the line ending and bounds the line's length.
+func trimLine(s string) string {
+	s = strings.TrimRight(s, "\r\n")
+	if len(s) <= maxLineBytes {
+		return s
+	}
+	// Cutting may split a rune; drop what is left of it.
+	return strings.ToValidUTF8(s[:maxLineBytes], "") + "…"
+}
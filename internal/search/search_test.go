@@ -0,0 +1,81 @@
+package search
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// rgOutput is `rg --json --context 3` output for two files, trimmed to the
+// fields parse reads.
+const rgOutput = `{"type":"begin","data":{"path":{"text":"./a.go"}}}
+{"type":"context","data":{"path":{"text":"./a.go"},"lines":{"text":"package a\n"},"line_number":1,"submatches":[]}}
+{"type":"match","data":{"path":{"text":"./a.go"},"lines":{"text":"func Foo() {}\n"},"line_number":2,"submatches":[{"start":5,"end":8}]}}
+{"type":"context","data":{"path":{"text":"./a.go"},"lines":{"text":"\n"},"line_number":3,"submatches":[]}}
+{"type":"match","data":{"path":{"text":"./a.go"},"lines":{"text":"var foo = Foo\n"},"line_number":4,"submatches":[{"start":4,"end":7},{"start":10,"end":13}]}}
+{"type":"end","data":{"path":{"text":"./a.go"}}}
+{"type":"begin","data":{"path":{"text":"./b/b.go"}}}
+{"type":"match","data":{"path":{"text":"./b/b.go"},"lines":{"text":"// foo\r\n"},"line_number":9,"submatches":[{"start":3,"end":6}]}}
+{"type":"end","data":{"path":{"text":"./b/b.go"}}}
+{"type":"summary","data":{}}
+`
+
+func TestParse(t *testing.T) {
+	result, err := parse(strings.NewReader(rgOutput), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Matches) != 3 || result.Truncated {
+		t.Fatalf("matches = %+v, truncated %v", result.Matches, result.Truncated)
+	}
+	first := result.Matches[0]
+	if first.Path != "a.go" || first.Line != 2 || first.Column != 6 || first.Text != "func Foo() {}" {
+		t.Fatalf("first match = %+v", first)
+	}
+	// A match's context includes the other matches around it.
+	if len(first.Context) != 4 || first.Context[0].Number != 1 || first.Context[3].Text != "var foo = Foo" {
+		t.Fatalf("first context = %+v", first.Context)
+	}
+	if last := result.Matches[2]; last.Path != "b/b.go" || last.Text != "// foo" || len(last.Context) != 1 {
+		t.Fatalf("last match = %+v", last)
+	}
+
+	result, err = parse(strings.NewReader(rgOutput), 2)
+	if err != nil || len(result.Matches) != 2 || !result.Truncated {
+		t.Fatalf("limited to 2 = %+v, %v", result, err)
+	}
+	if len(result.Matches[1].Context) != 4 {
+		t.Fatalf("a match before the limit keeps its context: %+v", result.Matches[1].Context)
+	}
+}
+
+func TestTrimLine(t *testing.T) {
+	long := strings.Repeat("é", maxLineBytes)
+	got := trimLine(long + "\n")
+	if len(got) > maxLineBytes+len("…") || !strings.HasSuffix(got, "…") || !strings.HasPrefix(got, "éé") {
+		t.Fatalf("trimLine() = %q", got)
+	}
+}
+
+func TestRun(t *testing.T) {
+	if _, err := exec.LookPath("rg"); err != nil {
+		t.Skip("rg not installed")
+	}
+	root := t.TempDir()
+	if err := os.WriteFile(filepath.Join(root, "main.go"), []byte("package main\n\nfunc main() {\n\tprintln(\"Hello\")\n}\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	result, err := Run(context.Background(), root, "hello", 10)
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if len(result.Matches) != 1 || result.Matches[0].Path != "main.go" || result.Matches[0].Line != 4 {
+		t.Fatalf("Run() = %+v", result)
+	}
+	if result, err := Run(context.Background(), root, "absent", 10); err != nil || len(result.Matches) != 0 {
+		t.Fatalf("Run() without matches = %+v, %v", result, err)
+	}
+}
@@ -0,0 +1,162 @@
+package secrets
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/andyrewlee/amux/internal/fsatomic"
+)
+
+const (
+	fileKeyName  = "key"
+	fileDataName = "secrets.json"
+	fileKeySize  = 32
+)
+
+// FileStore keeps secrets AES-256-GCM encrypted in dir/secrets.json under a
+// random key in dir/key, both readable only by the user. It keeps secrets out
+// of metadata that gets copied, shared, or backed up; it does not protect
+// against someone who can read the user's files.
+type FileStore struct {
+	dir string
+	mu  sync.Mutex
+}
+
+type fileData struct {
+	Version int               `json:"version"`
+	Entries map[string]string `json:"entries"` // key -> base64(nonce || ciphertext)
+}
+
+// NewFileStore returns a store rooted at dir, created on first write.
+func NewFileStore(dir string) *FileStore {
+	return &FileStore{dir: dir}
+}
+
+func (s *FileStore) Get(key string) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	data, err := s.read()
+	if err != nil {
+		return "", err
+	}
+	sealed, ok := data.Entries[key]
+	if !ok {
+		return "", ErrNotFound
+	}
+	aead, err := s.cipher(false)
+	if err != nil {
+		return "", err
+	}
+	raw, err := base64.StdEncoding.DecodeString(sealed)
+	if err != nil || len(raw) < aead.NonceSize() {
+		return "", fmt.Errorf("secrets: corrupt entry %q", key)
+	}
+	nonce, ciphertext := raw[:aead.NonceSize()], raw[aead.NonceSize():]
+	plain, err := aead.Open(nil, nonce, ciphertext, []byte(key))
+	if err != nil {
+		return "", fmt.Errorf("secrets: decrypt %q: %w", key, err)
+	}
+	return string(plain), nil
+}
+
+func (s *FileStore) Set(key, value string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	data, err := s.read()
+	if err != nil {
+		return err
+	}
+	aead, err := s.cipher(true)
+	if err != nil {
+		return err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return fmt.Errorf("secrets: nonce: %w", err)
+	}
+	sealed := aead.Seal(nonce, nonce, []byte(value), []byte(key))
+	data.Entries[key] = base64.StdEncoding.EncodeToString(sealed)
+	return s.write(data)
+}
+
+func (s *FileStore) Delete(key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	data, err := s.read()
+	if err != nil {
+		return err
+	}
+	if _, ok := data.Entries[key]; !ok {
+		return nil
+	}
+	delete(data.Entries, key)
+	return s.write(data)
+}
+
+func (s *FileStore) read() (fileData, error) {
+	data := fileData{Version: 1, Entries: make(map[string]string)}
+	raw, err := os.ReadFile(filepath.Join(s.dir, fileDataName))
+	if errors.Is(err, os.ErrNotExist) {
+		return data, nil
+	}
+	if err != nil {
+		return data, fmt.Errorf("secrets: read: %w", err)
+	}
+	if err := json.Unmarshal(raw, &data); err != nil {
+		return data, fmt.Errorf("secrets: decode: %w", err)
+	}
+	if data.Entries == nil {
+		data.Entries = make(map[string]string)
+	}
+	return data, nil
+}
+
+func (s *FileStore) write(data fileData) error {
+	if err := os.MkdirAll(s.dir, 0o700); err != nil {
+		return fmt.Errorf("secrets: %w", err)
+	}
+	raw, err := json.MarshalIndent(data, "", "  ")
+	if err != nil {
+		return fmt.Errorf("secrets: encode: %w", err)
+	}
+	if err := fsatomic.WriteFile(filepath.Join(s.dir, fileDataName), raw, 0o600); err != nil {
+		return fmt.Errorf("secrets: write: %w", err)
+	}
+	return nil
+}
+
+// cipher loads the file key, generating it first when create is set.
+func (s *FileStore) cipher(create bool) (cipher.AEAD, error) {
+	path := filepath.Join(s.dir, fileKeyName)
+	key, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) && create {
+		key = make([]byte, fileKeySize)
+		if _, err := rand.Read(key); err != nil {
+			return nil, fmt.Errorf("secrets: generate key: %w", err)
+		}
+		if err := os.MkdirAll(s.dir, 0o700); err != nil {
+			return nil, fmt.Errorf("secrets: %w", err)
+		}
+		if err := fsatomic.WriteFile(path, key, 0o600); err != nil {
+			return nil, fmt.Errorf("secrets: write key: %w", err)
+		}
+	} else if err != nil {
+		return nil, fmt.Errorf("secrets: read key: %w", err)
+	}
+	if len(key) != fileKeySize {
+		return nil, errors.New("secrets: key file has the wrong size")
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
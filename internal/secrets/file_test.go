@@ -0,0 +1,88 @@
+package secrets
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestFileStoreRoundTripsEncrypted(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "secrets")
+	store := NewFileStore(dir)
+
+	if _, err := store.Get("k"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("Get on empty store = %v, want ErrNotFound", err)
+	}
+	if err := store.Set("workspace/abc/OPENAI_API_KEY", "sk-plain-value"); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+	got, err := NewFileStore(dir).Get("workspace/abc/OPENAI_API_KEY")
+	if err != nil || got != "sk-plain-value" {
+		t.Fatalf("Get = %q, %v; want the stored value", got, err)
+	}
+
+	raw, err := os.ReadFile(filepath.Join(dir, fileDataName))
+	if err != nil {
+		t.Fatalf("read data file: %v", err)
+	}
+	if strings.Contains(string(raw), "sk-plain-value") {
+		t.Fatal("secret value is stored in plaintext")
+	}
+	for _, name := range []string{fileKeyName, fileDataName} {
+		info, err := os.Stat(filepath.Join(dir, name))
+		if err != nil {
+			t.Fatalf("stat %s: %v", name, err)
+		}
+		if perm := info.Mode().Perm(); perm&0o077 != 0 {
+			t.Fatalf("%s permissions = %o, want user-only", name, perm)
+		}
+	}
+
+	if err := store.Delete("workspace/abc/OPENAI_API_KEY"); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if _, err := store.Get("workspace/abc/OPENAI_API_KEY"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("Get after Delete = %v, want ErrNotFound", err)
+	}
+}
+
+func TestFileStoreDataFileStaysPrivate(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "secrets")
+	store := NewFileStore(dir)
+	path := filepath.Join(dir, fileDataName)
+
+	for _, value := range []string{"first", "second"} {
+		if err := store.Set("workspace/abc/TOKEN", value); err != nil {
+			t.Fatalf("Set: %v", err)
+		}
+		info, err := os.Stat(path)
+		if err != nil {
+			t.Fatalf("stat %s: %v", fileDataName, err)
+		}
+		if perm := info.Mode().Perm(); perm != 0o600 {
+			t.Fatalf("%s permissions after writing %q = %o, want 600", fileDataName, value, perm)
+		}
+	}
+}
+
+func TestFileStoreBindsCiphertextToKey(t *testing.T) {
+	dir := t.TempDir()
+	store := NewFileStore(dir)
+	if err := store.Set("a", "value-a"); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+	data, err := store.read()
+	if err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	// Moving a sealed value under another name must not decrypt.
+	data.Entries["b"] = data.Entries["a"]
+	if err := store.write(data); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if _, err := store.Get("b"); err == nil {
+		t.Fatal("expected a value moved to another key to fail authentication")
+	}
+}
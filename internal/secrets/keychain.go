@@ -0,0 +1,127 @@
+package secrets
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"runtime"
+	"strings"
+	"time"
+)
+
+// keychainService names amux's items in the OS keychain.
+const keychainService = "amux"
+
+const keychainTimeout = 5 * time.Second
+
+// runFunc runs a keychain tool with stdin and returns its stdout and exit
+// code. Tests replace it to avoid touching the real keychain.
+type runFunc func(name string, args []string, stdin string) (stdout string, exitCode int, err error)
+
+func runCommand(name string, args []string, stdin string) (string, int, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), keychainTimeout)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.Stdin = strings.NewReader(stdin)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	err := cmd.Run()
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return stdout.String(), exitErr.ExitCode(), fmt.Errorf("%s: %w: %s", name, err, strings.TrimSpace(stderr.String()))
+	}
+	return stdout.String(), 0, err
+}
+
+// systemKeychain returns the keychain for this OS, or nil when its tool is
+// not installed.
+func systemKeychain() Store {
+	switch runtime.GOOS {
+	case "darwin":
+		if _, err := exec.LookPath("security"); err == nil {
+			return &macKeychain{run: runCommand}
+		}
+	case "linux", "freebsd", "openbsd", "netbsd":
+		if _, err := exec.LookPath("secret-tool"); err == nil {
+			return &secretService{run: runCommand}
+		}
+	}
+	return nil
+}
+
+// macKeychain stores generic passwords in the login keychain. Writes go
+// through security's interactive mode on stdin so values never appear in the
+// process list.
+type macKeychain struct {
+	run runFunc
+}
+
+// securityNotFound is security(1)'s exit code for a missing item.
+const securityNotFound = 44
+
+func (k *macKeychain) Get(key string) (string, error) {
+	out, code, err := k.run("security", []string{"find-generic-password", "-s", keychainService, "-a", key, "-w"}, "")
+	if code == securityNotFound {
+		return "", ErrNotFound
+	}
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSuffix(out, "\n"), nil
+}
+
+func (k *macKeychain) Set(key, value string) error {
+	if strings.ContainsAny(value, "\r\n") {
+		return errors.New("security: multi-line values are not supported")
+	}
+	line := "add-generic-password -U -s " + securityQuote(keychainService) +
+		" -a " + securityQuote(key) + " -w " + securityQuote(value) + "\n"
+	_, _, err := k.run("security", []string{"-i"}, line)
+	return err
+}
+
+func (k *macKeychain) Delete(key string) error {
+	_, code, err := k.run("security", []string{"delete-generic-password", "-s", keychainService, "-a", key}, "")
+	if code == securityNotFound {
+		return nil
+	}
+	return err
+}
+
+// securityQuote quotes an argument for security's interactive mode.
+func securityQuote(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+}
+
+// secretService stores items in the freedesktop Secret Service (GNOME
+// Keyring, KWallet) through secret-tool, which reads values from stdin.
+type secretService struct {
+	run runFunc
+}
+
+func (s *secretService) Get(key string) (string, error) {
+	out, code, err := s.run("secret-tool", []string{"lookup", "service", keychainService, "key", key}, "")
+	if code == 1 && out == "" {
+		return "", ErrNotFound
+	}
+	if err != nil {
+		return "", err
+	}
+	return out, nil
+}
+
+func (s *secretService) Set(key, value string) error {
+	_, _, err := s.run("secret-tool", []string{"store", "--label", "amux: " + key, "service", keychainService, "key", key}, value)
+	return err
+}
+
+func (s *secretService) Delete(key string) error {
+	_, code, err := s.run("secret-tool", []string{"clear", "service", keychainService, "key", key}, "")
+	if code == 1 {
+		return nil // nothing matched
+	}
+	return err
+}
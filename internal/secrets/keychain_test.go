@@ -0,0 +1,61 @@
+package secrets
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+type recordedRun struct {
+	name  string
+	args  []string
+	stdin string
+}
+
+func fakeRun(runs *[]recordedRun, stdout string, code int) runFunc {
+	return func(name string, args []string, stdin string) (string, int, error) {
+		*runs = append(*runs, recordedRun{name: name, args: args, stdin: stdin})
+		if code != 0 {
+			return stdout, code, errors.New("exit status")
+		}
+		return stdout, 0, nil
+	}
+}
+
+func TestMacKeychainKeepsValuesOffTheCommandLine(t *testing.T) {
+	var runs []recordedRun
+	k := &macKeychain{run: fakeRun(&runs, "", 0)}
+	if err := k.Set("workspace/x/TOKEN", `se"cr\et`); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+	if strings.Contains(strings.Join(runs[0].args, " "), "se") {
+		t.Fatalf("value leaked into argv: %v", runs[0].args)
+	}
+	if want := `-w "se\"cr\\et"`; !strings.Contains(runs[0].stdin, want) {
+		t.Fatalf("stdin = %q, want quoted value %s", runs[0].stdin, want)
+	}
+
+	k.run = fakeRun(&runs, "", securityNotFound)
+	if _, err := k.Get("missing"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("Get missing = %v, want ErrNotFound", err)
+	}
+	if err := k.Delete("missing"); err != nil {
+		t.Fatalf("Delete missing = %v, want nil", err)
+	}
+}
+
+func TestSecretServicePassesValueOnStdin(t *testing.T) {
+	var runs []recordedRun
+	s := &secretService{run: fakeRun(&runs, "", 0)}
+	if err := s.Set("workspace/x/TOKEN", "hunter2"); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+	if runs[0].stdin != "hunter2" || strings.Contains(strings.Join(runs[0].args, " "), "hunter2") {
+		t.Fatalf("run = %+v, want the value on stdin only", runs[0])
+	}
+
+	s.run = fakeRun(&runs, "", 1)
+	if _, err := s.Get("missing"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("Get missing = %v, want ErrNotFound", err)
+	}
+}
@@ -0,0 +1,120 @@
+// Package secrets keeps credentials out of amux's plaintext metadata.
+//
+// Values go to the OS keychain when one is usable (macOS Keychain through
+// security(1), or the freedesktop Secret Service through secret-tool(1)), and
+// otherwise to an AES-GCM encrypted file under ~/.amux. The keychain is tried
+// per operation, so a locked or missing keyring degrades to the file instead
+// of failing.
+package secrets
+
+import (
+	"errors"
+	"path/filepath"
+	"sync"
+
+	"github.com/andyrewlee/amux/internal/logging"
+)
+
+// ErrNotFound is returned by Get when no value is stored under the key.
+var ErrNotFound = errors.New("secret not found")
+
+// Store holds secret values by key.
+type Store interface {
+	Get(key string) (string, error)
+	Set(key, value string) error
+	Delete(key string) error
+}
+
+// Open returns the store for the amux home directory: the OS keychain backed
+// by the encrypted file, with lookups cached for the life of the process.
+func Open(home string) Store {
+	file := NewFileStore(filepath.Join(home, "secrets"))
+	var store Store = file
+	if keychain := systemKeychain(); keychain != nil {
+		store = &fallbackStore{primary: keychain, fallback: file}
+	}
+	return &cachedStore{store: store, values: make(map[string]string)}
+}
+
+// fallbackStore writes to primary and falls back to the file when primary
+// fails. Reads check both, so values written while the keychain was
+// unavailable stay readable after it comes back.
+type fallbackStore struct {
+	primary  Store
+	fallback Store
+}
+
+func (s *fallbackStore) Get(key string) (string, error) {
+	value, err := s.primary.Get(key)
+	if err == nil {
+		return value, nil
+	}
+	if !errors.Is(err, ErrNotFound) {
+		logging.Warn("secrets: keychain lookup failed, trying encrypted file: %v", err)
+	}
+	return s.fallback.Get(key)
+}
+
+func (s *fallbackStore) Set(key, value string) error {
+	if err := s.primary.Set(key, value); err != nil {
+		logging.Warn("secrets: keychain write failed, using encrypted file: %v", err)
+		return s.fallback.Set(key, value)
+	}
+	// Drop any copy written while the keychain was unavailable.
+	if err := s.fallback.Delete(key); err != nil {
+		logging.Warn("secrets: remove file copy of %q: %v", key, err)
+	}
+	return nil
+}
+
+func (s *fallbackStore) Delete(key string) error {
+	return errors.Join(s.primary.Delete(key), s.fallback.Delete(key))
+}
+
+// cachedStore remembers values it has read or written. Keychain lookups
+// spawn a process each, and workspace metadata is reloaded often.
+type cachedStore struct {
+	store  Store
+	mu     sync.Mutex
+	values map[string]string
+}
+
+func (s *cachedStore) Get(key string) (string, error) {
+	s.mu.Lock()
+	value, ok := s.values[key]
+	s.mu.Unlock()
+	if ok {
+		return value, nil
+	}
+	value, err := s.store.Get(key)
+	if err != nil {
+		return "", err
+	}
+	s.mu.Lock()
+	s.values[key] = value
+	s.mu.Unlock()
+	return value, nil
+}
+
+func (s *cachedStore) Set(key, value string) error {
+	s.mu.Lock()
+	cached, ok := s.values[key]
+	s.mu.Unlock()
+	if ok && cached == value {
+		return nil
+	}
+	if err := s.store.Set(key, value); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	s.values[key] = value
+	s.mu.Unlock()
+	return nil
+}
+
+func (s *cachedStore) Delete(key string) error {
+	s.mu.Lock()
+	delete(s.values, key)
+	s.mu.Unlock()
+	return s.store.Delete(key)
+}
@@ -0,0 +1,87 @@
+package secrets
+
+import (
+	"errors"
+	"testing"
+)
+
+type mapStore struct {
+	values map[string]string
+	err    error
+	gets   int
+}
+
+func newMapStore() *mapStore { return &mapStore{values: make(map[string]string)} }
+
+func (m *mapStore) Get(key string) (string, error) {
+	m.gets++
+	if m.err != nil {
+		return "", m.err
+	}
+	value, ok := m.values[key]
+	if !ok {
+		return "", ErrNotFound
+	}
+	return value, nil
+}
+
+func (m *mapStore) Set(key, value string) error {
+	if m.err != nil {
+		return m.err
+	}
+	m.values[key] = value
+	return nil
+}
+
+func (m *mapStore) Delete(key string) error {
+	delete(m.values, key)
+	return m.err
+}
+
+func TestFallbackStoreUsesFileWhenKeychainFails(t *testing.T) {
+	keychain, file := newMapStore(), newMapStore()
+	store := &fallbackStore{primary: keychain, fallback: file}
+
+	keychain.err = errors.New("keyring locked")
+	if err := store.Set("k", "v1"); err != nil {
+		t.Fatalf("Set with locked keychain: %v", err)
+	}
+	if file.values["k"] != "v1" {
+		t.Fatal("value should land in the file when the keychain fails")
+	}
+	if got, err := store.Get("k"); err != nil || got != "v1" {
+		t.Fatalf("Get = %q, %v; want the file copy", got, err)
+	}
+
+	keychain.err = nil
+	if err := store.Set("k", "v2"); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+	if keychain.values["k"] != "v2" {
+		t.Fatal("value should go to the keychain once it works")
+	}
+	if _, ok := file.values["k"]; ok {
+		t.Fatal("the stale file copy should be removed")
+	}
+}
+
+func TestCachedStoreAvoidsRepeatLookups(t *testing.T) {
+	backing := newMapStore()
+	backing.values["k"] = "v"
+	store := &cachedStore{store: backing, values: make(map[string]string)}
+
+	for range 3 {
+		if got, err := store.Get("k"); err != nil || got != "v" {
+			t.Fatalf("Get = %q, %v", got, err)
+		}
+	}
+	if backing.gets != 1 {
+		t.Fatalf("backing lookups = %d, want 1", backing.gets)
+	}
+	if err := store.Delete("k"); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if _, err := store.Get("k"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("Get after Delete = %v, want ErrNotFound", err)
+	}
+}
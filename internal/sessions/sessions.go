@@ -0,0 +1,187 @@
+// Package sessions names the tmux sessions amux hosts tabs in after the
+// project, worktree, and tab they belong to, as amux/<project>/<worktree>/<tab>,
+// so scripts and other terminals can find and attach to them. The tmux
+// session names themselves stay ID-based, since discovery and cleanup key on
+// them; these names are resolved to them.
+package sessions
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/andyrewlee/amux/internal/data"
+	"github.com/andyrewlee/amux/internal/tmux"
+)
+
+// Tag keys read from each session.
+const (
+	tagWorkspace = "@amux_workspace"
+	tagTab       = "@amux_tab"
+	tagType      = "@amux_type"
+	tagAssistant = "@amux_assistant"
+	tagCreatedAt = "@amux_created_at"
+)
+
+// Test seams for the tmux lookups.
+var (
+	sessionsWithTags = tmux.SessionsWithTags
+	attachedSessions = tmux.SessionNamesWithClients
+)
+
+// Session is an amux tmux session.
+type Session struct {
+	// Name is amux/<project>/<worktree>/<tab>, or the tmux session name
+	// when its worktree is unknown.
+	Name        string    `json:"name"`
+	TmuxSession string    `json:"tmux_session"`
+	Project     string    `json:"project,omitempty"`
+	Worktree    string    `json:"worktree,omitempty"`
+	Tab         string    `json:"tab,omitempty"`
+	Type        string    `json:"type,omitempty"` // agent or terminal
+	Assistant   string    `json:"assistant,omitempty"`
+	WorkspaceID string    `json:"workspace_id,omitempty"`
+	Attached    bool      `json:"attached"`
+	CreatedAt   time.Time `json:"created_at,omitzero"`
+	// State is the agent's idle, working, or done state as amux last
+	// published it; empty for terminals and before amux has classified it.
+	State string `json:"state,omitempty"`
+}
+
+// Name joins project, worktree, and tab into a session name, replacing
+// characters that are awkward in a shell or a tmux target.
+func Name(project, worktree, tab string) string {
+	return strings.Join([]string{"amux", part(project), part(worktree), part(tab)}, "/")
+}
+
+func part(s string) string {
+	s = strings.Map(func(r rune) rune {
+		switch {
+		case r == '/' || r == ':' || r == '.' || r == ' ' || r == '\t':
+			return '-'
+		case r < ' ':
+			return -1
+		}
+		return r
+	}, strings.TrimSpace(s))
+	if s == "" {
+		return "-"
+	}
+	return s
+}
+
+// List returns the amux sessions on the server opts points at, named from
+// the workspaces in store, ordered by name.
+func List(opts tmux.Options, store *data.WorkspaceStore) ([]Session, error) {
+	rows, err := sessionsWithTags(map[string]string{"@amux": "1"},
+		[]string{tagWorkspace, tagTab, tagType, tagAssistant, tagCreatedAt, tmux.TagAgentState}, opts)
+	if err != nil {
+		if tmux.IsNoServerError(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, nil
+	}
+	// Whether a session is attached is informational; a failed lookup
+	// reports them all detached rather than failing the list.
+	attached, _ := attachedSessions(opts)
+	workspaces := make(map[string]*data.Workspace)
+	if store != nil {
+		ids, err := store.List()
+		if err != nil {
+			return nil, fmt.Errorf("list workspaces: %w", err)
+		}
+		for _, id := range ids {
+			if ws, err := store.Load(id); err == nil {
+				workspaces[string(ws.ID())] = ws
+			}
+		}
+	}
+	return build(rows, workspaces, attached), nil
+}
+
+// build names each session after its workspace and tab. Sessions that would
+// share a name are told apart by a -2, -3, ... suffix, oldest first.
+func build(rows []tmux.SessionTagValues, workspaces map[string]*data.Workspace, attached map[string]bool) []Session {
+	out := make([]Session, 0, len(rows))
+	for _, row := range rows {
+		s := Session{
+			TmuxSession: row.Name,
+			Type:        strings.TrimSpace(row.Tags[tagType]),
+			Assistant:   strings.TrimSpace(row.Tags[tagAssistant]),
+			WorkspaceID: strings.TrimSpace(row.Tags[tagWorkspace]),
+			Attached:    attached[row.Name],
+			State:       strings.TrimSpace(row.Tags[tmux.TagAgentState]),
+		}
+		if secs, err := strconv.ParseInt(strings.TrimSpace(row.Tags[tagCreatedAt]), 10, 64); err == nil && secs > 0 {
+			s.CreatedAt = time.Unix(secs, 0)
+		}
+		s.Name = row.Name
+		if ws := workspaces[s.WorkspaceID]; ws != nil {
+			s.Project = filepath.Base(ws.Repo)
+			s.Worktree = ws.Name
+			s.Tab = tabLabel(ws, row)
+			s.Name = Name(s.Project, s.Worktree, s.Tab)
+		}
+		out = append(out, s)
+	}
+	slices.SortStableFunc(out, func(a, b Session) int {
+		return cmp.Or(strings.Compare(a.Name, b.Name), a.CreatedAt.Compare(b.CreatedAt),
+			strings.Compare(a.TmuxSession, b.TmuxSession))
+	})
+	seen := make(map[string]int, len(out))
+	for i := range out {
+		base := out[i].Name
+		seen[base]++
+		if n := seen[base]; n > 1 {
+			out[i].Name = base + "-" + strconv.Itoa(n)
+		}
+	}
+	return out
+}
+
+// tabLabel names the session's tab: the tab's own name, else its assistant
+// or type, else the tab ID.
+func tabLabel(ws *data.Workspace, row tmux.SessionTagValues) string {
+	for _, tab := range ws.OpenTabs {
+		if tab.SessionName != row.Name {
+			continue
+		}
+		if name := strings.TrimSpace(tab.Name); name != "" {
+			return name
+		}
+		if tab.Assistant != "" {
+			return tab.Assistant
+		}
+	}
+	for _, key := range []string{tagAssistant, tagType, tagTab} {
+		if v := strings.TrimSpace(row.Tags[key]); v != "" {
+			return v
+		}
+	}
+	return "tab"
+}
+
+// ErrNotFound is returned by Find when no session matches.
+var ErrNotFound = errors.New("no such session")
+
+// Find returns the session named name, accepting either its amux name or its
+// tmux session name. A name without the amux/ prefix is tried with it too.
+func Find(list []Session, name string) (Session, error) {
+	name = strings.TrimSpace(name)
+	for _, candidate := range []string{name, "amux/" + name} {
+		for _, s := range list {
+			if s.Name == candidate || s.TmuxSession == candidate {
+				return s, nil
+			}
+		}
+	}
+	return Session{}, fmt.Errorf("%w: %q", ErrNotFound, name)
+}
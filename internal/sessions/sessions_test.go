@@ -0,0 +1,104 @@
+package sessions
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/andyrewlee/amux/internal/data"
+	"github.com/andyrewlee/amux/internal/tmux"
+)
+
+func TestName(t *testing.T) {
+	if got := Name("my.repo", "fix: login", "claude"); got != "amux/my-repo/fix--login/claude" {
+		t.Fatalf("Name() = %q", got)
+	}
+	if got := Name("repo", "", "term"); got != "amux/repo/-/term" {
+		t.Fatalf("Name() with an empty part = %q", got)
+	}
+}
+
+func TestBuild(t *testing.T) {
+	ws := data.NewWorkspace("feature", "feature", "main", "/src/repo", "/work/repo/feature")
+	ws.OpenTabs = []data.TabInfo{
+		{Assistant: "claude", SessionName: "amux-w1-a"},
+		{Assistant: "claude", Name: "reviewer", SessionName: "amux-w1-c"},
+	}
+	wsID := string(ws.ID())
+	row := func(name, created string, tags map[string]string) tmux.SessionTagValues {
+		all := map[string]string{tagWorkspace: wsID, tagCreatedAt: created}
+		for k, v := range tags {
+			all[k] = v
+		}
+		return tmux.SessionTagValues{Name: name, Tags: all}
+	}
+	rows := []tmux.SessionTagValues{
+		row("amux-w1-b", "200", map[string]string{tagType: "agent", tagAssistant: "claude"}),
+		row("amux-w1-a", "100", map[string]string{tagType: "agent", tagAssistant: "claude", tmux.TagAgentState: "working"}),
+		row("amux-w1-c", "300", map[string]string{tagType: "agent", tagAssistant: "claude"}),
+		row("amux-w1-t", "400", map[string]string{tagType: "terminal", tagTab: "t1"}),
+		{Name: "amux-gone-x", Tags: map[string]string{tagWorkspace: "gone", tagType: "agent"}},
+	}
+
+	list := build(rows, map[string]*data.Workspace{wsID: ws}, map[string]bool{"amux-w1-c": true})
+	got := make(map[string]Session, len(list))
+	for _, s := range list {
+		got[s.TmuxSession] = s
+	}
+	want := map[string]string{
+		"amux-w1-a":   "amux/repo/feature/claude",
+		"amux-w1-b":   "amux/repo/feature/claude-2",
+		"amux-w1-c":   "amux/repo/feature/reviewer",
+		"amux-w1-t":   "amux/repo/feature/terminal",
+		"amux-gone-x": "amux-gone-x",
+	}
+	for session, name := range want {
+		if got[session].Name != name {
+			t.Errorf("%s named %q, want %q", session, got[session].Name, name)
+		}
+	}
+	if !got["amux-w1-c"].Attached || got["amux-w1-a"].Attached {
+		t.Error("attached flags not carried over")
+	}
+	if got["amux-w1-a"].Project != "repo" || got["amux-w1-a"].Worktree != "feature" || got["amux-w1-a"].CreatedAt.Unix() != 100 || got["amux-w1-a"].State != "working" {
+		t.Errorf("session = %+v", got["amux-w1-a"])
+	}
+}
+
+func TestList(t *testing.T) {
+	oldTags, oldAttached := sessionsWithTags, attachedSessions
+	t.Cleanup(func() { sessionsWithTags, attachedSessions = oldTags, oldAttached })
+	sessionsWithTags = func(match map[string]string, _ []string, _ tmux.Options) ([]tmux.SessionTagValues, error) {
+		if match["@amux"] != "1" {
+			t.Fatalf("match = %v, want only amux sessions", match)
+		}
+		return []tmux.SessionTagValues{{Name: "amux-x-y", Tags: map[string]string{}}}, nil
+	}
+	attachedSessions = func(tmux.Options) (map[string]bool, error) { return nil, nil }
+
+	list, err := List(tmux.Options{}, data.NewWorkspaceStore(t.TempDir()))
+	if err != nil || len(list) != 1 || list[0].Name != "amux-x-y" {
+		t.Fatalf("List() = %+v, %v", list, err)
+	}
+
+	sessionsWithTags = func(map[string]string, []string, tmux.Options) ([]tmux.SessionTagValues, error) {
+		return nil, errors.New("boom")
+	}
+	if _, err := List(tmux.Options{}, nil); err == nil {
+		t.Fatal("expected the tmux error")
+	}
+}
+
+func TestFind(t *testing.T) {
+	list := []Session{
+		{Name: "amux/repo/feature/claude", TmuxSession: "amux-w1-a"},
+		{Name: "amux/repo/main/claude", TmuxSession: "amux-w2-a"},
+	}
+	for _, name := range []string{"amux/repo/feature/claude", "repo/feature/claude", "amux-w1-a"} {
+		if s, err := Find(list, name); err != nil || s.TmuxSession != "amux-w1-a" {
+			t.Errorf("Find(%q) = %+v, %v", name, s, err)
+		}
+	}
+	if _, err := Find(list, "repo/other/claude"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("Find(unknown) error = %v, want ErrNotFound", err)
+	}
+}
@@ -0,0 +1,92 @@
+package share
+
+import "html/template"
+
+type pageData struct {
+	Session    string
+	Token      string
+	AllowInput bool
+}
+
+// pageTemplate is the viewer page. It renders the streamed screen in a <pre>
+// and, once write access is granted, forwards keystrokes and pastes as the
+// bytes a terminal would send for them.
+var pageTemplate = template.Must(template.New("share").Parse(`<!doctype html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>amux · {{.Session}}</title>
+<style>
+body { margin: 0; background: #1d2021; color: #ebdbb2; font: 13px/1.3 ui-monospace, Menlo, monospace; }
+header { padding: 6px 10px; background: #3c3836; color: #a89984; }
+#screen { margin: 0; padding: 8px 10px; white-space: pre; outline: none; }
+</style>
+</head>
+<body>
+<header>amux · <b>{{.Session}}</b> · <span id="status">waiting for the owner to approve…</span></header>
+<pre id="screen" tabindex="0"></pre>
+<script>
+(function () {
+  const token = {{.Token}};
+  const wantWrite = {{.AllowInput}};
+  const screen = document.getElementById("screen");
+  const status = document.getElementById("status");
+  const q = "token=" + encodeURIComponent(token);
+  const events = new EventSource("events?" + q + (wantWrite ? "&write=1" : ""));
+  let viewer = "", canWrite = false, ended = false;
+
+  events.addEventListener("hello", function (e) {
+    const hello = JSON.parse(e.data);
+    viewer = hello.viewer;
+    canWrite = hello.write;
+    status.textContent = canWrite ? "connected · typing goes to the session" : "connected · view only";
+    screen.focus();
+  });
+  events.addEventListener("screen", function (e) {
+    screen.textContent = JSON.parse(e.data);
+  });
+  events.addEventListener("ended", function (e) {
+    ended = true;
+    status.textContent = "session ended: " + JSON.parse(e.data);
+    events.close();
+  });
+  events.onerror = function () {
+    if (!ended && events.readyState === EventSource.CLOSED) {
+      status.textContent = viewer ? "disconnected" : "not approved";
+    }
+  };
+
+  function send(text) {
+    if (!canWrite || !text) return;
+    fetch("input?" + q + "&viewer=" + encodeURIComponent(viewer), { method: "POST", body: text });
+  }
+
+  const named = {
+    Enter: "\r", Backspace: "\x7f", Tab: "\t", Escape: "\x1b",
+    ArrowUp: "\x1b[A", ArrowDown: "\x1b[B", ArrowRight: "\x1b[C", ArrowLeft: "\x1b[D",
+    Home: "\x1b[H", End: "\x1b[F", Delete: "\x1b[3~", PageUp: "\x1b[5~", PageDown: "\x1b[6~"
+  };
+  screen.addEventListener("keydown", function (e) {
+    if (!canWrite || e.metaKey) return;
+    let text = "";
+    if (named[e.key]) {
+      text = named[e.key];
+    } else if (e.ctrlKey && e.key.length === 1 && /[a-z@\[\\\]^_]/i.test(e.key)) {
+      text = String.fromCharCode(e.key.toUpperCase().charCodeAt(0) & 0x1f);
+    } else if (e.key.length === 1 && !e.ctrlKey) {
+      text = (e.altKey ? "\x1b" : "") + e.key;
+    }
+    if (text) {
+      e.preventDefault();
+      send(text);
+    }
+  });
+  screen.addEventListener("paste", function (e) {
+    e.preventDefault();
+    send(e.clipboardData.getData("text"));
+  });
+})();
+</script>
+</body>
+</html>
+`))
@@ -0,0 +1,226 @@
+// Package share serves one tmux-backed amux session to browsers, so a
+// teammate can watch it and, when allowed, type into it.
+//
+// Every request must carry the share token, and each browser that connects is
+// put to the person sharing through Config.Approve before it sees anything.
+// The screen is streamed as server-sent events and keystrokes come back as
+// POSTs, which keeps the server on the standard library.
+package share
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+)
+
+const (
+	defaultPollInterval = 250 * time.Millisecond
+	maxInputBytes       = 4096
+	// ReadHeaderTimeout bounds slow clients; streams never set a write
+	// deadline, so callers must not set WriteTimeout on the http.Server.
+	ReadHeaderTimeout = 5 * time.Second
+)
+
+// Viewer is a browser asking to join the share.
+type Viewer struct {
+	Remote string // client address as seen by the server
+	Write  bool   // whether the viewer asked to type into the session
+}
+
+// Config describes the shared session and how to reach it.
+type Config struct {
+	// Session is the tmux session name shown to viewers.
+	Session string
+	// AllowInput lets approved viewers request write access.
+	AllowInput bool
+	// Capture returns the session's visible screen as plain text.
+	Capture func() (string, error)
+	// Send types text from viewer into the session.
+	Send func(viewer Viewer, text string) error
+	// Approve asks the person sharing whether a viewer may join, with write
+	// access when Viewer.Write is set. It may block while they decide.
+	Approve func(Viewer) bool
+	// PollInterval is how often the screen is captured; zero means 250ms.
+	PollInterval time.Duration
+}
+
+// Server is an http.Handler for one share.
+type Server struct {
+	cfg   Config
+	token string
+	mux   *http.ServeMux
+
+	mu      sync.Mutex
+	nextID  int
+	viewers map[string]bool // viewer ID -> may write
+}
+
+// New returns a server for cfg with a fresh random token.
+func New(cfg Config) (*Server, error) {
+	if cfg.Capture == nil || cfg.Approve == nil {
+		return nil, errors.New("share: Capture and Approve are required")
+	}
+	if cfg.AllowInput && cfg.Send == nil {
+		return nil, errors.New("share: Send is required when input is allowed")
+	}
+	if cfg.PollInterval <= 0 {
+		cfg.PollInterval = defaultPollInterval
+	}
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return nil, fmt.Errorf("share: generate token: %w", err)
+	}
+	s := &Server{cfg: cfg, token: hex.EncodeToString(buf), viewers: make(map[string]bool)}
+	s.mux = http.NewServeMux()
+	s.mux.HandleFunc("GET /{$}", s.handlePage)
+	s.mux.HandleFunc("GET /events", s.handleEvents)
+	s.mux.HandleFunc("POST /input", s.handleInput)
+	return s, nil
+}
+
+// Token returns the secret every request must present.
+func (s *Server) Token() string {
+	return s.token
+}
+
+// URL returns the link to hand to a viewer for a server listening on addr,
+// reached over scheme ("http" or "https").
+func (s *Server) URL(scheme, addr string) string {
+	return scheme + "://" + addr + "/?token=" + url.QueryEscape(s.token)
+}
+
+// ServeHTTP implements http.Handler.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !s.authorized(r) {
+		http.Error(w, "invalid or missing share token", http.StatusUnauthorized)
+		return
+	}
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("Referrer-Policy", "no-referrer")
+	s.mux.ServeHTTP(w, r)
+}
+
+func (s *Server) authorized(r *http.Request) bool {
+	got := r.URL.Query().Get("token")
+	return subtle.ConstantTimeCompare([]byte(got), []byte(s.token)) == 1
+}
+
+func (s *Server) handlePage(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Content-Security-Policy", "default-src 'none'; script-src 'unsafe-inline'; style-src 'unsafe-inline'; connect-src 'self'")
+	_ = pageTemplate.Execute(w, pageData{Session: s.cfg.Session, Token: s.token, AllowInput: s.cfg.AllowInput})
+}
+
+// handleEvents asks for approval, then streams the screen whenever it
+// changes until the viewer disconnects.
+func (s *Server) handleEvents(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+	viewer := Viewer{Remote: r.RemoteAddr, Write: s.cfg.AllowInput && r.URL.Query().Get("write") == "1"}
+	if !s.cfg.Approve(viewer) {
+		http.Error(w, "the session owner declined", http.StatusForbidden)
+		return
+	}
+	id := s.addViewer(viewer.Write)
+	defer s.removeViewer(id)
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	hello, _ := json.Marshal(struct {
+		Viewer string `json:"viewer"`
+		Write  bool   `json:"write"`
+	}{id, viewer.Write})
+	writeEvent(w, "hello", hello)
+	flusher.Flush()
+
+	ticker := time.NewTicker(s.cfg.PollInterval)
+	defer ticker.Stop()
+	last := ""
+	for {
+		screen, err := s.cfg.Capture()
+		if err != nil {
+			msg, _ := json.Marshal(err.Error())
+			writeEvent(w, "ended", msg)
+			flusher.Flush()
+			return
+		}
+		if screen != last {
+			last = screen
+			data, _ := json.Marshal(screen)
+			writeEvent(w, "screen", data)
+			flusher.Flush()
+		}
+		select {
+		case <-r.Context().Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func writeEvent(w io.Writer, event string, data []byte) {
+	fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event, data)
+}
+
+// handleInput types the request body into the session for a viewer that was
+// granted write access.
+func (s *Server) handleInput(w http.ResponseWriter, r *http.Request) {
+	if !s.viewerMayWrite(r.URL.Query().Get("viewer")) {
+		http.Error(w, "input not permitted", http.StatusForbidden)
+		return
+	}
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxInputBytes+1))
+	if err != nil {
+		http.Error(w, "read input", http.StatusBadRequest)
+		return
+	}
+	if len(body) > maxInputBytes {
+		http.Error(w, "input too large", http.StatusRequestEntityTooLarge)
+		return
+	}
+	if err := s.cfg.Send(Viewer{Remote: r.RemoteAddr, Write: true}, string(body)); err != nil {
+		http.Error(w, "send input: "+err.Error(), http.StatusBadGateway)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) addViewer(write bool) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.nextID++
+	buf := make([]byte, 8)
+	_, _ = rand.Read(buf)
+	id := fmt.Sprintf("%d-%s", s.nextID, hex.EncodeToString(buf))
+	s.viewers[id] = write
+	return id
+}
+
+func (s *Server) removeViewer(id string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.viewers, id)
+}
+
+func (s *Server) viewerMayWrite(id string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.viewers[id]
+}
+
+// Viewers returns the number of connected viewers.
+func (s *Server) Viewers() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.viewers)
+}
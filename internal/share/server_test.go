@@ -0,0 +1,194 @@
+package share
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+type fakeSession struct {
+	mu       sync.Mutex
+	screen   string
+	sent     []string
+	approve  bool
+	requests []Viewer
+}
+
+func (f *fakeSession) config(allowInput bool) Config {
+	return Config{
+		Session:    "amux-test",
+		AllowInput: allowInput,
+		Capture: func() (string, error) {
+			f.mu.Lock()
+			defer f.mu.Unlock()
+			return f.screen, nil
+		},
+		Send: func(_ Viewer, text string) error {
+			f.mu.Lock()
+			defer f.mu.Unlock()
+			f.sent = append(f.sent, text)
+			return nil
+		},
+		Approve: func(v Viewer) bool {
+			f.mu.Lock()
+			defer f.mu.Unlock()
+			f.requests = append(f.requests, v)
+			return f.approve
+		},
+		PollInterval: 10 * time.Millisecond,
+	}
+}
+
+func (f *fakeSession) approvals() []Viewer {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]Viewer(nil), f.requests...)
+}
+
+func newTestServer(t *testing.T, f *fakeSession, allowInput bool) (*Server, *httptest.Server) {
+	t.Helper()
+	s, err := New(f.config(allowInput))
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	ts := httptest.NewServer(s)
+	t.Cleanup(ts.Close)
+	return s, ts
+}
+
+func TestServerRejectsMissingOrWrongToken(t *testing.T) {
+	f := &fakeSession{approve: true}
+	s, ts := newTestServer(t, f, false)
+
+	for _, path := range []string{"/", "/?token=nope", "/events?token=nope"} {
+		resp, err := http.Get(ts.URL + path)
+		if err != nil {
+			t.Fatalf("GET %s: %v", path, err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusUnauthorized {
+			t.Fatalf("GET %s status = %d, want 401", path, resp.StatusCode)
+		}
+	}
+	resp, err := http.Get(ts.URL + "/?token=" + s.Token())
+	if err != nil {
+		t.Fatalf("GET page: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("page status = %d, want 200", resp.StatusCode)
+	}
+	if len(f.approvals()) != 0 {
+		t.Fatal("loading the page must not prompt; only the stream does")
+	}
+}
+
+func TestServerDeclinedViewerSeesNothing(t *testing.T) {
+	f := &fakeSession{screen: "secret"}
+	s, ts := newTestServer(t, f, false)
+
+	resp, err := http.Get(ts.URL + "/events?token=" + s.Token())
+	if err != nil {
+		t.Fatalf("GET events: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusForbidden {
+		t.Fatalf("declined stream status = %d, want 403", resp.StatusCode)
+	}
+}
+
+// readEvent returns the next event name and its JSON data.
+func readEvent(t *testing.T, r *bufio.Reader) (string, string) {
+	t.Helper()
+	var event, data string
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			t.Fatalf("read event: %v", err)
+		}
+		line = strings.TrimRight(line, "\n")
+		switch {
+		case line == "":
+			return event, data
+		case strings.HasPrefix(line, "event: "):
+			event = strings.TrimPrefix(line, "event: ")
+		case strings.HasPrefix(line, "data: "):
+			data = strings.TrimPrefix(line, "data: ")
+		}
+	}
+}
+
+func TestServerStreamsScreenAndAcceptsApprovedInput(t *testing.T) {
+	f := &fakeSession{screen: "$ ls", approve: true}
+	s, ts := newTestServer(t, f, true)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, ts.URL+"/events?write=1&token="+s.Token(), nil)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("GET events: %v", err)
+	}
+	defer resp.Body.Close()
+	r := bufio.NewReader(resp.Body)
+
+	event, data := readEvent(t, r)
+	var hello struct {
+		Viewer string `json:"viewer"`
+		Write  bool   `json:"write"`
+	}
+	if err := json.Unmarshal([]byte(data), &hello); event != "hello" || err != nil || !hello.Write {
+		t.Fatalf("first event = %s %s, want hello with write access", event, data)
+	}
+	if event, data := readEvent(t, r); event != "screen" || data != `"$ ls"` {
+		t.Fatalf("second event = %s %s, want the screen", event, data)
+	}
+	if got := f.approvals(); len(got) != 1 || !got[0].Write {
+		t.Fatalf("approval requests = %+v, want one write request", got)
+	}
+
+	post := func(viewer string) int {
+		resp, err := http.Post(ts.URL+"/input?token="+s.Token()+"&viewer="+viewer, "text/plain", strings.NewReader("pwd\r"))
+		if err != nil {
+			t.Fatalf("POST input: %v", err)
+		}
+		resp.Body.Close()
+		return resp.StatusCode
+	}
+	if code := post("bogus"); code != http.StatusForbidden {
+		t.Fatalf("unknown viewer input status = %d, want 403", code)
+	}
+	if code := post(hello.Viewer); code != http.StatusNoContent {
+		t.Fatalf("approved input status = %d, want 204", code)
+	}
+	f.mu.Lock()
+	sent := append([]string(nil), f.sent...)
+	f.mu.Unlock()
+	if len(sent) != 1 || sent[0] != "pwd\r" {
+		t.Fatalf("sent = %q, want the posted keys", sent)
+	}
+}
+
+func TestServerWithoutInputGrantsViewOnly(t *testing.T) {
+	f := &fakeSession{screen: "x", approve: true}
+	s, ts := newTestServer(t, f, false)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, ts.URL+"/events?write=1&token="+s.Token(), nil)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("GET events: %v", err)
+	}
+	defer resp.Body.Close()
+	_, data := readEvent(t, bufio.NewReader(resp.Body))
+	if !strings.Contains(data, `"write":false`) {
+		t.Fatalf("hello = %s, want view-only when input is not allowed", data)
+	}
+}
@@ -0,0 +1,147 @@
+// Package stack models stacked branches: workspaces whose branches are
+// based on one another, so that work too large for one pull request lands as
+// a chain of small ones, each reviewed against the one below it. It works out
+// a workspace's stack from the project's workspaces, plans the rebases that
+// restack or reorder it, and writes the stack's part of each pull request's
+// description.
+package stack
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/andyrewlee/amux/internal/data"
+	"github.com/andyrewlee/amux/internal/forge"
+)
+
+// Stack is a chain of workspaces above a trunk branch, bottom first: the
+// first is based on Trunk and each of the others on the one before it.
+type Stack struct {
+	Trunk   string
+	Entries []*data.Workspace
+}
+
+// Step rebases one entry of a stack onto Onto, the branch it is to be based
+// on, taking its commits above Upstream, the branch it was based on.
+type Step struct {
+	Workspace *data.Workspace
+	Onto      string
+	Upstream  string
+}
+
+// Of returns the stack ws is in among workspaces, its project's. Below ws,
+// the stack follows each workspace's base to the workspace with that branch,
+// down to a base that is no workspace's, the trunk. Above, it follows the
+// workspace based on each branch, stopping where several are. The primary
+// checkout and archived workspaces are never part of a stack.
+func Of(ws *data.Workspace, workspaces []data.Workspace) Stack {
+	byBranch := map[string]*data.Workspace{}
+	children := map[string][]*data.Workspace{}
+	var current *data.Workspace
+	for i := range workspaces {
+		w := &workspaces[i]
+		if w.Branch == "" || w.Archived || w.IsPrimaryCheckout() {
+			continue
+		}
+		byBranch[w.Branch] = w
+		children[w.Base] = append(children[w.Base], w)
+		if w.Root == ws.Root {
+			current = w
+		}
+	}
+	if current == nil {
+		return Stack{Trunk: ws.Base, Entries: []*data.Workspace{ws}}
+	}
+	seen := map[string]bool{}
+	var entries []*data.Workspace
+	for w := current; w != nil && !seen[w.Branch]; w = byBranch[w.Base] {
+		seen[w.Branch] = true
+		entries = append([]*data.Workspace{w}, entries...)
+	}
+	for top := current; len(children[top.Branch]) == 1 && !seen[children[top.Branch][0].Branch]; {
+		top = children[top.Branch][0]
+		seen[top.Branch] = true
+		entries = append(entries, top)
+	}
+	return Stack{Trunk: entries[0].Base, Entries: entries}
+}
+
+// Index returns the position of the entry checked out at ws's root, or -1.
+func (s Stack) Index(ws *data.Workspace) int {
+	for i, entry := range s.Entries {
+		if entry.Root == ws.Root {
+			return i
+		}
+	}
+	return -1
+}
+
+// Parent returns the branch entry i is based on: the entry below it, or the
+// trunk.
+func (s Stack) Parent(i int) string {
+	if i == 0 {
+		return s.Trunk
+	}
+	return s.Entries[i-1].Branch
+}
+
+// Restack plans rebasing every entry onto its parent, bottom first, so that
+// each has the latest commits of the ones below it.
+func Restack(s Stack) []Step {
+	steps := make([]Step, len(s.Entries))
+	for i, entry := range s.Entries {
+		steps[i] = Step{Workspace: entry, Onto: s.Parent(i), Upstream: s.Parent(i)}
+	}
+	return steps
+}
+
+// Move returns s with entry i swapped with its neighbor delta places away,
+// -1 toward the trunk or 1 away from it, and the steps that rebase the
+// entries from the lower of the two up to match.
+func Move(s Stack, i, delta int) (Stack, []Step, error) {
+	j := i + delta
+	if delta != -1 && delta != 1 || i < 0 || i >= len(s.Entries) || j < 0 || j >= len(s.Entries) {
+		return s, nil, errors.New("no entry to swap with")
+	}
+	moved := Stack{Trunk: s.Trunk, Entries: append([]*data.Workspace(nil), s.Entries...)}
+	moved.Entries[i], moved.Entries[j] = moved.Entries[j], moved.Entries[i]
+	var steps []Step
+	for k := min(i, j); k < len(moved.Entries); k++ {
+		entry := moved.Entries[k]
+		steps = append(steps, Step{Workspace: entry, Onto: moved.Parent(k), Upstream: s.Parent(s.Index(entry))})
+	}
+	return moved, steps, nil
+}
+
+// Description is the part of entry i's pull request description that
+// places it in the stack: what it is based on and every entry from the
+// bottom, linking those with a pull request in prs, keyed by branch.
+func Description(s Stack, i int, prs map[string]forge.PR) string {
+	var b strings.Builder
+	if i > 0 {
+		fmt.Fprintf(&b, "Stacked on %s; review and merge that first.\n\n", link(s.Entries[i-1].Branch, prs))
+	}
+	fmt.Fprintf(&b, "Part of a stack of %d onto %s, bottom first:\n\n", len(s.Entries), PRBase(s.Trunk))
+	for k, entry := range s.Entries {
+		line := link(entry.Branch, prs)
+		if k == i {
+			line = "**" + entry.Branch + "** (this one)"
+		}
+		fmt.Fprintf(&b, "%d. %s\n", k+1, line)
+	}
+	return b.String()
+}
+
+func link(branch string, prs map[string]forge.PR) string {
+	if pr, ok := prs[branch]; ok && pr.URL != "" {
+		return fmt.Sprintf("[%s](%s)", branch, pr.URL)
+	}
+	return branch
+}
+
+// PRBase is the branch a pull request into base targets: base without the
+// remote name of a remote-tracking branch such as origin/main.
+func PRBase(base string) string {
+	return strings.TrimPrefix(base, "origin/")
+}
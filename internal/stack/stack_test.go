@@ -0,0 +1,98 @@
+package stack
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/andyrewlee/amux/internal/data"
+	"github.com/andyrewlee/amux/internal/forge"
+)
+
+func workspaces() []data.Workspace {
+	ws := func(branch, base string) data.Workspace {
+		return data.Workspace{Name: branch, Branch: branch, Base: base, Repo: "/repo", Root: "/wt/" + branch}
+	}
+	return []data.Workspace{
+		{Name: "repo", Branch: "main", Repo: "/repo", Root: "/repo"},
+		ws("api", "main"),
+		ws("ui", "api"),
+		ws("docs", "ui"),
+		ws("other", "main"),
+		ws("fork-a", "docs"),
+		ws("fork-b", "docs"),
+	}
+}
+
+func branches(s Stack) []string {
+	var names []string
+	for _, entry := range s.Entries {
+		names = append(names, entry.Branch)
+	}
+	return names
+}
+
+func TestOf(t *testing.T) {
+	all := workspaces()
+	s := Of(&all[2], all)
+	if s.Trunk != "main" || !reflect.DeepEqual(branches(s), []string{"api", "ui", "docs"}) {
+		t.Fatalf("Of(ui) = %s on %q, want api, ui, docs on main", branches(s), s.Trunk)
+	}
+	if s.Index(&all[3]) != 2 || s.Index(&all[4]) != -1 || s.Parent(0) != "main" || s.Parent(2) != "ui" {
+		t.Fatalf("Index, Parent = %d, %d, %q, %q", s.Index(&all[3]), s.Index(&all[4]), s.Parent(0), s.Parent(2))
+	}
+	if s := Of(&all[4], all); !reflect.DeepEqual(branches(s), []string{"other"}) {
+		t.Fatalf("Of(other) = %s", branches(s))
+	}
+	if s := Of(&all[6], all); !reflect.DeepEqual(branches(s), []string{"api", "ui", "docs", "fork-b"}) {
+		t.Fatalf("Of(fork-b) = %s", branches(s))
+	}
+}
+
+func TestRestackAndMove(t *testing.T) {
+	all := workspaces()
+	s := Of(&all[1], all)
+	steps := Restack(s)
+	if len(steps) != 3 || steps[1].Onto != "api" || steps[1].Upstream != "api" || steps[0].Onto != "main" {
+		t.Fatalf("Restack() = %+v", steps)
+	}
+
+	moved, steps, err := Move(s, 1, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(branches(moved), []string{"api", "docs", "ui"}) || !reflect.DeepEqual(branches(s), []string{"api", "ui", "docs"}) {
+		t.Fatalf("Move(ui up) = %s, from %s", branches(moved), branches(s))
+	}
+	want := []struct{ branch, onto, upstream string }{{"docs", "api", "ui"}, {"ui", "docs", "api"}}
+	if len(steps) != len(want) {
+		t.Fatalf("Move() steps = %+v", steps)
+	}
+	for i, w := range want {
+		if steps[i].Workspace.Branch != w.branch || steps[i].Onto != w.onto || steps[i].Upstream != w.upstream {
+			t.Fatalf("step %d = %+v, want %+v", i, steps[i], w)
+		}
+	}
+	if _, _, err := Move(s, 0, -1); err == nil {
+		t.Fatal("moving the bottom entry down should fail")
+	}
+}
+
+func TestDescription(t *testing.T) {
+	all := workspaces()
+	s := Of(&all[1], all)
+	s.Trunk = "origin/main"
+	got := Description(s, 1, map[string]forge.PR{"api": {URL: "https://example.com/pr/1"}})
+	for _, want := range []string{
+		"Stacked on [api](https://example.com/pr/1)",
+		"stack of 3 onto main",
+		"1. [api](https://example.com/pr/1)\n2. **ui** (this one)\n3. docs\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Fatalf("Description() = %q, want %q", got, want)
+		}
+	}
+	if got := Description(s, 0, nil); strings.Contains(got, "Stacked on") {
+		t.Fatalf("bottom entry's description = %q", got)
+	}
+}
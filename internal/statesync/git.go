@@ -0,0 +1,61 @@
+package statesync
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/andyrewlee/amux/internal/git"
+)
+
+// pull brings the sync repository up to its upstream. amux owns the
+// repository and everything in it is rebuilt from the local state on each
+// sync, so it is reset to the upstream rather than merged: a commit that
+// never got pushed is made again from the same local state.
+func pull(ctx context.Context, dir string) error {
+	if !hasUpstream(ctx, dir) {
+		return nil
+	}
+	if _, err := git.RunGitCtx(ctx, dir, "fetch", "--quiet"); err != nil {
+		return fmt.Errorf("fetch: %w", err)
+	}
+	if _, err := git.RunGitCtx(ctx, dir, "reset", "--hard", "--quiet", "@{upstream}"); err != nil {
+		return fmt.Errorf("reset to upstream: %w", err)
+	}
+	return nil
+}
+
+// commitAndPush commits the synced files if they changed and pushes them
+// when the repository has an upstream.
+func commitAndPush(ctx context.Context, dir string) error {
+	status, err := git.RunGitCtx(ctx, dir, "status", "--porcelain", "--", configFile, projectsFile)
+	if err != nil {
+		return fmt.Errorf("status: %w", err)
+	}
+	if strings.TrimSpace(status) == "" {
+		return nil
+	}
+	if _, err := git.RunGitCtx(ctx, dir, "add", "--", configFile, projectsFile); err != nil {
+		return fmt.Errorf("add: %w", err)
+	}
+	host, _ := os.Hostname()
+	if host == "" {
+		host = "unknown host"
+	}
+	if _, err := git.RunGitCtx(ctx, dir, "commit", "--quiet", "-m", "amux sync from "+host); err != nil {
+		return fmt.Errorf("commit: %w", err)
+	}
+	if !hasUpstream(ctx, dir) {
+		return nil
+	}
+	if _, err := git.RunGitCtx(ctx, dir, "push", "--quiet"); err != nil {
+		return fmt.Errorf("push: %w", err)
+	}
+	return nil
+}
+
+func hasUpstream(ctx context.Context, dir string) bool {
+	_, err := git.RunGitCtx(ctx, dir, "rev-parse", "--abbrev-ref", "@{upstream}")
+	return err == nil
+}
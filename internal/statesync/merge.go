@@ -0,0 +1,89 @@
+package statesync
+
+import (
+	"reflect"
+	"slices"
+)
+
+// absent stands for a key missing from one side of a merge.
+type absent struct{}
+
+// merge collects what a three-way merge of JSON values took from the other
+// machine and where the two machines disagree.
+type merge struct {
+	changed   []string
+	conflicts []string
+}
+
+// mergeValue merges the decoded JSON values local and remote, which both
+// started from base. A side that left a value as it was in base takes the
+// other side's change; objects changed on both sides are merged member by
+// member; anything else changed on both sides differently is a conflict,
+// and the local value is kept. key names the value in the lists m collects.
+func (m *merge) mergeValue(base, local, remote any, key string) any {
+	switch {
+	case reflect.DeepEqual(local, remote), reflect.DeepEqual(base, remote):
+		return local
+	case reflect.DeepEqual(base, local):
+		m.changed = append(m.changed, key)
+		return remote
+	}
+	localObj, localOK := local.(map[string]any)
+	remoteObj, remoteOK := remote.(map[string]any)
+	if localOK && remoteOK {
+		baseObj, _ := base.(map[string]any)
+		return m.mergeObjects(baseObj, localObj, remoteObj, key)
+	}
+	m.conflicts = append(m.conflicts, key)
+	return local
+}
+
+func (m *merge) mergeObjects(base, local, remote map[string]any, key string) map[string]any {
+	keys := make([]string, 0, len(local)+len(remote))
+	for k := range local {
+		keys = append(keys, k)
+	}
+	for k := range remote {
+		if _, ok := local[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	slices.Sort(keys)
+	out := make(map[string]any, len(keys))
+	for _, k := range keys {
+		child := k
+		if key != "" {
+			child = key + "." + k
+		}
+		if v := m.mergeValue(member(base, k), member(local, k), member(remote, k), child); v != (absent{}) {
+			out[k] = v
+		}
+	}
+	return out
+}
+
+func member(obj map[string]any, key string) any {
+	if v, ok := obj[key]; ok {
+		return v
+	}
+	return absent{}
+}
+
+// mergeSets merges two sets that both started from base: an item is kept
+// when both sides have it or one side added it, and dropped when one side
+// removed it. The result is sorted.
+func mergeSets(base, local, remote []string) []string {
+	var out []string
+	for _, item := range local {
+		if slices.Contains(remote, item) || !slices.Contains(base, item) {
+			out = append(out, item)
+		}
+	}
+	for _, item := range remote {
+		if !slices.Contains(local, item) && !slices.Contains(base, item) {
+			out = append(out, item)
+		}
+	}
+	slices.Sort(out)
+	return slices.Compact(out)
+}
@@ -0,0 +1,48 @@
+package statesync
+
+import (
+	"reflect"
+	"slices"
+	"testing"
+)
+
+func TestMergeObjects(t *testing.T) {
+	base := map[string]any{
+		"ui":         map[string]any{"theme": "gruvbox", "notify_on_done": false, "reduced_motion": false},
+		"assistants": map[string]any{"claude": map[string]any{"command": "claude"}},
+	}
+	local := map[string]any{
+		"ui":         map[string]any{"theme": "nord", "notify_on_done": false, "reduced_motion": true},
+		"assistants": map[string]any{"claude": map[string]any{"command": "claude"}},
+	}
+	remote := map[string]any{
+		"ui":         map[string]any{"theme": "dracula", "notify_on_done": true, "reduced_motion": false},
+		"assistants": map[string]any{},
+		"schedules":  []any{"nightly"},
+	}
+	var m merge
+	got := m.mergeObjects(base, local, remote, "")
+	want := map[string]any{
+		"ui":         map[string]any{"theme": "nord", "notify_on_done": true, "reduced_motion": true},
+		"assistants": map[string]any{},
+		"schedules":  []any{"nightly"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("merged = %v, want %v", got, want)
+	}
+	if !slices.Equal(m.changed, []string{"assistants", "schedules", "ui.notify_on_done"}) {
+		t.Fatalf("changed = %v", m.changed)
+	}
+	if !slices.Equal(m.conflicts, []string{"ui.theme"}) {
+		t.Fatalf("conflicts = %v", m.conflicts)
+	}
+}
+
+func TestMergeSets(t *testing.T) {
+	base := []string{"a", "b", "c"}
+	local := []string{"a", "b", "d"}  // removed c, added d
+	remote := []string{"b", "c", "e"} // removed a, added e
+	if got := mergeSets(base, local, remote); !slices.Equal(got, []string{"b", "d", "e"}) {
+		t.Fatalf("merged = %v", got)
+	}
+}
@@ -0,0 +1,282 @@
+// Package statesync keeps amux's settings and project list consistent across
+// machines through a shared sync directory: a git repository or a folder a
+// file-syncing service such as Dropbox keeps in step. Each sync merges the
+// directory's copy with the local one three ways, against the copy this
+// machine last synced, so changes made on either machine are kept and only
+// a setting changed differently on both is a conflict.
+package statesync
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/andyrewlee/amux/internal/config"
+	"github.com/andyrewlee/amux/internal/data"
+	"github.com/andyrewlee/amux/internal/fsatomic"
+)
+
+// DirEnvVar names a sync directory other than ~/.amux/sync.
+const DirEnvVar = "AMUX_SYNC_DIR"
+
+const (
+	configFile   = "config.json"
+	projectsFile = "projects.json"
+	// baseDir, under the amux home, holds what this machine last synced.
+	baseDir = "sync-base"
+	// ConflictFile, under the amux home, keeps the other machine's config
+	// when a setting conflicts.
+	ConflictFile = "config.sync-conflict.json"
+)
+
+// Conflict is a setting changed differently on this machine and another.
+// The local value is kept.
+type Conflict struct {
+	Key string // dotted path in config.json, such as "ui.theme"
+}
+
+// Result is what a sync changed.
+type Result struct {
+	Dir string
+	Git bool
+	// ProjectsAdded and ProjectsRemoved are the projects the sync added to
+	// and removed from the local registry.
+	ProjectsAdded   []string
+	ProjectsRemoved []string
+	// ConfigChanged lists the config.json keys taken from the other machine.
+	ConfigChanged []string
+	Conflicts     []Conflict
+	// GitErr is set when pulling or pushing the repository failed; the
+	// files in it were still merged.
+	GitErr error
+}
+
+// Dir returns the sync directory for paths and whether it exists. Sync is
+// set up by creating it, cloning a repository to it, or linking it to a
+// synced folder.
+func Dir(paths *config.Paths) (string, bool) {
+	dir := strings.TrimSpace(os.Getenv(DirEnvVar))
+	if dir == "" {
+		dir = filepath.Join(paths.Home, "sync")
+	} else if rest, ok := strings.CutPrefix(dir, "~/"); ok {
+		if home, err := os.UserHomeDir(); err == nil {
+			dir = filepath.Join(home, rest)
+		}
+	}
+	info, err := os.Stat(dir)
+	return dir, err == nil && info.IsDir()
+}
+
+// Sync merges the project registry and config.json at paths with their
+// copies in dir, writes the merged state to both, and, when dir is a git
+// repository, pulls before and commits and pushes after.
+func Sync(ctx context.Context, paths *config.Paths, dir string) (Result, error) {
+	res := Result{Dir: dir}
+	if info, err := os.Stat(filepath.Join(dir, ".git")); err == nil && info.IsDir() {
+		res.Git = true
+		res.GitErr = pull(ctx, dir)
+	}
+	base := filepath.Join(paths.Home, baseDir)
+	if err := os.MkdirAll(base, 0o700); err != nil {
+		return res, err
+	}
+	if err := syncProjects(paths.RegistryPath, dir, base, &res); err != nil {
+		return res, fmt.Errorf("projects: %w", err)
+	}
+	if err := syncConfig(paths, dir, base, &res); err != nil {
+		return res, fmt.Errorf("config: %w", err)
+	}
+	if res.Git && res.GitErr == nil {
+		res.GitErr = commitAndPush(ctx, dir)
+	}
+	return res, nil
+}
+
+// syncedProjects is the shape of projects.json in the sync directory. Paths
+// under the home directory are written as ~/..., so machines with different
+// home directories agree on them.
+type syncedProjects struct {
+	Projects []string `json:"projects"`
+}
+
+func syncProjects(registryPath, dir, base string, res *Result) error {
+	registry := data.NewRegistry(registryPath)
+	local, err := registry.Projects()
+	if err != nil {
+		return err
+	}
+	basePaths, _, err := readProjects(filepath.Join(base, projectsFile))
+	if err != nil {
+		return err
+	}
+	remote, ok, err := readProjects(filepath.Join(dir, projectsFile))
+	if err != nil {
+		return err
+	}
+	if !ok {
+		remote = basePaths
+	}
+	localSet := basePaths
+	if _, err := os.Stat(registryPath); err == nil {
+		localSet = make([]string, 0, len(local))
+		for _, p := range local {
+			localSet = append(localSet, portable(p))
+		}
+		// Projects this machine does not have were never in its registry;
+		// they are not removals.
+		for _, p := range basePaths {
+			if !exists(expand(p)) {
+				localSet = append(localSet, p)
+			}
+		}
+	}
+	merged := mergeSets(basePaths, localSet, remote)
+
+	var want []string
+	for _, p := range merged {
+		if path := expand(p); exists(path) {
+			want = append(want, path)
+		}
+	}
+	for _, p := range want {
+		if !slices.Contains(local, p) {
+			res.ProjectsAdded = append(res.ProjectsAdded, p)
+		}
+	}
+	for _, p := range local {
+		if !slices.Contains(want, p) {
+			res.ProjectsRemoved = append(res.ProjectsRemoved, p)
+		}
+	}
+	if len(res.ProjectsAdded) > 0 || len(res.ProjectsRemoved) > 0 {
+		if err := registry.Save(want); err != nil {
+			return err
+		}
+	}
+	file := syncedProjects{Projects: merged}
+	if !ok || !slices.Equal(remote, merged) {
+		if err := fsatomic.WriteJSON(filepath.Join(dir, projectsFile), file); err != nil {
+			return err
+		}
+	}
+	return fsatomic.WriteJSON(filepath.Join(base, projectsFile), file)
+}
+
+func readProjects(path string) ([]string, bool, error) {
+	raw, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	var file syncedProjects
+	if err := json.Unmarshal(raw, &file); err != nil {
+		return nil, false, fmt.Errorf("%s: %w", path, err)
+	}
+	slices.Sort(file.Projects)
+	return slices.Compact(file.Projects), true, nil
+}
+
+func syncConfig(paths *config.Paths, dir, base string, res *Result) error {
+	basePath := filepath.Join(base, configFile)
+	baseObj, _, err := readObject(basePath)
+	if err != nil {
+		return err
+	}
+	local, localOK, err := readObject(paths.ConfigPath)
+	if err != nil {
+		return err
+	}
+	remote, remoteOK, err := readObject(filepath.Join(dir, configFile))
+	if err != nil {
+		return err
+	}
+	// A missing copy is taken as unchanged rather than emptied, so a new
+	// machine or a fresh sync directory never wipes the other side.
+	if !localOK {
+		local = baseObj
+	}
+	if !remoteOK {
+		remote = baseObj
+	}
+
+	var m merge
+	merged := m.mergeObjects(baseObj, local, remote, "")
+	res.ConfigChanged = m.changed
+	for _, key := range m.conflicts {
+		res.Conflicts = append(res.Conflicts, Conflict{Key: key})
+	}
+	if len(m.conflicts) > 0 {
+		if err := fsatomic.WriteJSON(filepath.Join(paths.Home, ConflictFile), remote); err != nil {
+			return err
+		}
+	}
+	if len(m.changed) > 0 {
+		if err := fsatomic.WriteJSON(paths.ConfigPath, merged); err != nil {
+			return err
+		}
+	}
+	if !remoteOK || !reflect.DeepEqual(remote, merged) {
+		if err := fsatomic.WriteJSON(filepath.Join(dir, configFile), merged); err != nil {
+			return err
+		}
+	}
+	return fsatomic.WriteJSON(basePath, merged)
+}
+
+// readObject reads a JSON object. A missing file reads as an empty object
+// and false.
+func readObject(path string) (map[string]any, bool, error) {
+	raw, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return map[string]any{}, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	var obj map[string]any
+	if err := json.Unmarshal(raw, &obj); err != nil {
+		return nil, false, fmt.Errorf("%s is not a JSON object: %w", path, err)
+	}
+	if obj == nil {
+		obj = map[string]any{}
+	}
+	return obj, true, nil
+}
+
+// portable writes a path under the home directory as ~/...
+func portable(path string) string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return path
+	}
+	if rel, err := filepath.Rel(home, path); err == nil && rel != "." && !strings.HasPrefix(rel, "..") {
+		return "~/" + filepath.ToSlash(rel)
+	}
+	return path
+}
+
+// expand is the inverse of portable.
+func expand(path string) string {
+	rest, ok := strings.CutPrefix(path, "~/")
+	if !ok {
+		return path
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return path
+	}
+	return filepath.Join(home, filepath.FromSlash(rest))
+}
+
+func exists(path string) bool {
+	_, err := os.Stat(path)
+	return !errors.Is(err, os.ErrNotExist)
+}
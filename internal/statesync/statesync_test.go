@@ -0,0 +1,195 @@
+package statesync
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/andyrewlee/amux/internal/config"
+	"github.com/andyrewlee/amux/internal/data"
+	"github.com/andyrewlee/amux/internal/testutil"
+)
+
+// machine is one computer's amux home, with its own home directory.
+type machine struct {
+	userHome string
+	paths    *config.Paths
+}
+
+func newMachine(t *testing.T, projects ...string) machine {
+	t.Helper()
+	userHome := t.TempDir()
+	amuxHome := filepath.Join(userHome, ".amux")
+	m := machine{userHome: userHome, paths: &config.Paths{
+		Home:         amuxHome,
+		RegistryPath: filepath.Join(amuxHome, "projects.json"),
+		ConfigPath:   filepath.Join(amuxHome, "config.json"),
+	}}
+	for _, p := range projects {
+		if err := os.MkdirAll(filepath.Join(userHome, p), 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return m
+}
+
+func (m machine) sync(t *testing.T, dir string) Result {
+	t.Helper()
+	t.Setenv("HOME", m.userHome)
+	res, err := Sync(context.Background(), m.paths, dir)
+	if err != nil {
+		t.Fatalf("Sync: %v", err)
+	}
+	return res
+}
+
+func (m machine) writeConfig(t *testing.T, v any) {
+	t.Helper()
+	raw, _ := json.Marshal(v)
+	if err := os.MkdirAll(m.paths.Home, 0o700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(m.paths.ConfigPath, raw, 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func (m machine) theme(t *testing.T) any {
+	t.Helper()
+	obj, _, err := readObject(m.paths.ConfigPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ui, _ := obj["ui"].(map[string]any)
+	return ui["theme"]
+}
+
+func (m machine) projects(t *testing.T) []string {
+	t.Helper()
+	paths, err := data.NewRegistry(m.paths.RegistryPath).Projects()
+	if err != nil {
+		t.Fatal(err)
+	}
+	slices.Sort(paths)
+	return paths
+}
+
+func (m machine) project(name string) string { return filepath.Join(m.userHome, name) }
+
+func TestSyncTwoMachines(t *testing.T) {
+	dir := t.TempDir()
+	work := newMachine(t, "src/api", "src/web")
+	laptop := newMachine(t, "src/api", "src/cli")
+
+	if err := data.NewRegistry(work.paths.RegistryPath).Save([]string{work.project("src/api"), work.project("src/web")}); err != nil {
+		t.Fatal(err)
+	}
+	work.writeConfig(t, map[string]any{"ui": map[string]any{"theme": "nord"}})
+	work.sync(t, dir)
+
+	res := laptop.sync(t, dir)
+	if !slices.Equal(laptop.projects(t), []string{laptop.project("src/api")}) {
+		t.Fatalf("laptop projects = %v; src/web does not exist there", laptop.projects(t))
+	}
+	if laptop.theme(t) != "nord" || !slices.Equal(res.ConfigChanged, []string{"ui"}) {
+		t.Fatalf("laptop theme = %v, changed = %v", laptop.theme(t), res.ConfigChanged)
+	}
+
+	// The laptop adds a project and drops one; the work machine keeps the
+	// project the laptop does not have.
+	if err := data.NewRegistry(laptop.paths.RegistryPath).Save([]string{laptop.project("src/cli")}); err != nil {
+		t.Fatal(err)
+	}
+	laptop.sync(t, dir)
+	res = work.sync(t, dir)
+	if !slices.Equal(work.projects(t), []string{work.project("src/web")}) {
+		t.Fatalf("work projects = %v", work.projects(t))
+	}
+	if !slices.Equal(res.ProjectsRemoved, []string{work.project("src/api")}) {
+		t.Fatalf("removed = %v", res.ProjectsRemoved)
+	}
+	synced, _, _ := readProjects(filepath.Join(dir, projectsFile))
+	if !slices.Equal(synced, []string{"~/src/cli", "~/src/web"}) {
+		t.Fatalf("synced projects = %v", synced)
+	}
+}
+
+func TestSyncConflictKeepsLocal(t *testing.T) {
+	dir := t.TempDir()
+	a := newMachine(t)
+	b := newMachine(t)
+	a.writeConfig(t, map[string]any{"ui": map[string]any{"theme": "nord"}})
+	a.sync(t, dir)
+	b.sync(t, dir)
+
+	a.writeConfig(t, map[string]any{"ui": map[string]any{"theme": "dracula"}})
+	b.writeConfig(t, map[string]any{"ui": map[string]any{"theme": "tokyonight"}})
+	a.sync(t, dir)
+	res := b.sync(t, dir)
+	if len(res.Conflicts) != 1 || res.Conflicts[0].Key != "ui.theme" {
+		t.Fatalf("conflicts = %+v", res.Conflicts)
+	}
+	if b.theme(t) != "tokyonight" {
+		t.Fatalf("theme = %v, want the local value kept", b.theme(t))
+	}
+	other, _, err := readObject(filepath.Join(b.paths.Home, ConflictFile))
+	if err != nil || other["ui"].(map[string]any)["theme"] != "dracula" {
+		t.Fatalf("conflict copy = %v, %v", other, err)
+	}
+}
+
+func TestSyncMissingFilesAreUnchanged(t *testing.T) {
+	dir := t.TempDir()
+	m := newMachine(t, "src/api")
+	if err := data.NewRegistry(m.paths.RegistryPath).Save([]string{m.project("src/api")}); err != nil {
+		t.Fatal(err)
+	}
+	m.writeConfig(t, map[string]any{"ui": map[string]any{"theme": "nord"}})
+	m.sync(t, dir)
+
+	// A fresh sync directory must not wipe this machine's state.
+	fresh := t.TempDir()
+	m.sync(t, fresh)
+	if m.theme(t) != "nord" || len(m.projects(t)) != 1 {
+		t.Fatalf("theme = %v, projects = %v", m.theme(t), m.projects(t))
+	}
+	if _, err := os.Stat(filepath.Join(fresh, configFile)); err != nil {
+		t.Fatalf("the fresh directory should get a copy: %v", err)
+	}
+}
+
+func TestSyncGit(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	remote := t.TempDir()
+	testutil.RunGit(t, remote, "init", "--bare", "--quiet", "--initial-branch=main")
+	seed := testutil.InitRepo(t)
+	testutil.RunGit(t, seed, "remote", "add", "origin", remote)
+	testutil.RunGit(t, seed, "push", "--quiet", "-u", "origin", "HEAD:main")
+
+	clone := func() string {
+		dir := filepath.Join(t.TempDir(), "sync")
+		testutil.RunGit(t, filepath.Dir(dir), "clone", "--quiet", remote, dir)
+		testutil.RunGit(t, dir, "config", "user.name", "Test")
+		testutil.RunGit(t, dir, "config", "user.email", "test@example.com")
+		return dir
+	}
+	a, b := newMachine(t), newMachine(t)
+	aDir, bDir := clone(), clone()
+
+	a.writeConfig(t, map[string]any{"ui": map[string]any{"theme": "nord"}})
+	if res := a.sync(t, aDir); !res.Git || res.GitErr != nil {
+		t.Fatalf("git = %v, err = %v", res.Git, res.GitErr)
+	}
+	if res := b.sync(t, bDir); res.GitErr != nil {
+		t.Fatalf("git err = %v", res.GitErr)
+	}
+	if b.theme(t) != "nord" {
+		t.Fatalf("theme = %v, want it pulled from the other clone", b.theme(t))
+	}
+}
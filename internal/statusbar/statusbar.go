@@ -0,0 +1,116 @@
+// Package statusbar runs the status bar's widgets: external commands whose
+// output, like a tmux status-right script's, becomes a segment of the bar at
+// the bottom of the screen. Output is reduced to one line of plain text
+// within the widget's width, so a widget can't break the layout or write
+// escape sequences to the terminal.
+package statusbar
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/charmbracelet/x/ansi"
+
+	"github.com/andyrewlee/amux/internal/config"
+	"github.com/andyrewlee/amux/internal/process"
+)
+
+// Separator goes between segments.
+const Separator = " │ "
+
+// maxRunTime bounds a widget's run, however long its interval.
+const maxRunTime = 10 * time.Second
+
+// maxOutput bounds how much of a widget's output is kept.
+const maxOutput = 4096
+
+// Run runs w's command in dir with env added to amux's environment and
+// returns its segment. A command that fails, or prints nothing, is an error.
+func Run(ctx context.Context, w config.StatusWidget, dir string, env []string) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, min(w.Interval, maxRunTime))
+	defer cancel()
+	return RunCommand(ctx, w.Command, dir, env, w.MaxWidth)
+}
+
+// RunCommand runs command with `sh -c` until ctx is done and returns its
+// output reduced by Sanitize to width cells. Run uses it for widgets; it
+// suits any command whose output is shown as a short label.
+func RunCommand(ctx context.Context, command, dir string, env []string, width int) (string, error) {
+	cmd := exec.CommandContext(ctx, "sh", "-c", command)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), env...)
+	process.SetProcessGroup(cmd)
+	// Kill what the command started too, such as a hung kubectl.
+	cmd.Cancel = func() error { return process.ForceKillProcess(cmd.Process.Pid) }
+	cmd.WaitDelay = time.Second
+	var out headBuffer
+	cmd.Stdout = &out
+	if err := cmd.Run(); err != nil {
+		return "", err
+	}
+	text := Sanitize(out.String(), width)
+	if text == "" {
+		return "", errors.New("no output")
+	}
+	return text, nil
+}
+
+// Sanitize returns the first non-blank line of output as plain text at most
+// width cells wide: escape sequences are removed and other control
+// characters become spaces.
+func Sanitize(output string, width int) string {
+	for _, line := range strings.Split(ansi.Strip(output), "\n") {
+		line = strings.TrimSpace(strings.Map(func(r rune) rune {
+			if unicode.IsControl(r) {
+				return ' '
+			}
+			return r
+		}, line))
+		if line != "" {
+			return ansi.Truncate(line, width, "…")
+		}
+	}
+	return ""
+}
+
+// Render joins segments into a bar width cells wide, aligned right like
+// tmux's status-right. Empty segments are left out, and a bar too wide
+// loses its leftmost cells.
+func Render(segments []string, width int) string {
+	var shown []string
+	for _, s := range segments {
+		if s != "" {
+			shown = append(shown, s)
+		}
+	}
+	if len(shown) == 0 || width <= 0 {
+		return ""
+	}
+	bar := strings.Join(shown, Separator) + " "
+	if w := ansi.StringWidth(bar); w > width {
+		bar = "…" + ansi.TruncateLeft(bar, w-width+1, "")
+	}
+	return strings.Repeat(" ", width-ansi.StringWidth(bar)) + bar
+}
+
+// headBuffer keeps the first maxOutput bytes written to it.
+type headBuffer struct {
+	buf bytes.Buffer
+}
+
+func (h *headBuffer) Write(p []byte) (int, error) {
+	if room := maxOutput - h.buf.Len(); room > 0 {
+		h.buf.Write(p[:min(room, len(p))])
+	}
+	return len(p), nil
+}
+
+func (h *headBuffer) String() string {
+	return h.buf.String()
+}
@@ -0,0 +1,61 @@
+package statusbar
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/x/ansi"
+
+	"github.com/andyrewlee/amux/internal/config"
+)
+
+func TestSanitize(t *testing.T) {
+	for _, tc := range []struct {
+		output string
+		width  int
+		want   string
+	}{
+		{"\n  prod-cluster\nsecond line\n", 30, "prod-cluster"},
+		{"\x1b[31mfailing\x1b[0m\tci\x07", 30, "failing ci"},
+		{"\x1b]0;title\x07ok", 30, "ok"},
+		{"a very long kubernetes context name", 10, "a very lo…"},
+		{"日本語のコンテキスト", 7, "日本語…"},
+		{"\n \n", 30, ""},
+	} {
+		got := Sanitize(tc.output, tc.width)
+		if got != tc.want || ansi.StringWidth(got) > tc.width {
+			t.Errorf("Sanitize(%q, %d) = %q, want %q", tc.output, tc.width, got, tc.want)
+		}
+	}
+}
+
+func TestRender(t *testing.T) {
+	if got := Render([]string{"k8s: prod", "", "ci: ok"}, 30); got != strings.Repeat(" ", 11)+"k8s: prod │ ci: ok " {
+		t.Fatalf("Render() = %q", got)
+	}
+	got := Render([]string{"k8s: prod", "ci: ok"}, 12)
+	if ansi.StringWidth(got) != 12 || got != "…d │ ci: ok " {
+		t.Fatalf("Render() too wide = %q, want the left cut off", got)
+	}
+	if Render([]string{"", ""}, 30) != "" {
+		t.Fatal("a bar without segments should render nothing")
+	}
+}
+
+func TestRun(t *testing.T) {
+	dir := t.TempDir()
+	w := config.StatusWidget{Command: `printf '%s\n' "$(basename "$PWD") $AMUX_TEST_VALUE"`, Interval: time.Second, MaxWidth: 60}
+	got, err := Run(context.Background(), w, dir, []string{"AMUX_TEST_VALUE=42"})
+	if err != nil || !strings.HasSuffix(got, " 42") || !strings.HasPrefix(got, dir[strings.LastIndex(dir, "/")+1:]) {
+		t.Fatalf("Run() = %q, %v", got, err)
+	}
+	if _, err := Run(context.Background(), config.StatusWidget{Command: "exit 3", Interval: time.Second, MaxWidth: 10}, dir, nil); err == nil {
+		t.Fatal("a failing command should be an error")
+	}
+	start := time.Now()
+	if _, err := Run(context.Background(), config.StatusWidget{Command: "sleep 30", Interval: time.Second, MaxWidth: 10}, dir, nil); err == nil || time.Since(start) > 5*time.Second {
+		t.Fatalf("a hung command should time out with its interval: %v after %s", err, time.Since(start))
+	}
+}
@@ -0,0 +1,286 @@
+package testrun
+
+import (
+	"bufio"
+	"encoding/xml"
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// coverageFiles are where test runners conventionally write coverage: Go
+// profiles, LCOV (Jest, c8, nyc), and Cobertura XML (pytest-cov, Jest).
+var coverageFiles = []string{
+	"coverage.out",
+	"cover.out",
+	"coverage.txt",
+	"coverage/lcov.info",
+	"lcov.info",
+	"coverage.xml",
+	"coverage/cobertura-coverage.xml",
+}
+
+// Ratio is a count of covered lines out of instrumented ones.
+type Ratio struct {
+	Covered int
+	Total   int
+}
+
+// Percent returns the covered share as a whole percentage, rounded down, or
+// -1 when no lines are instrumented.
+func (r Ratio) Percent() int {
+	if r.Total == 0 {
+		return -1
+	}
+	return r.Covered * 100 / r.Total
+}
+
+func (r *Ratio) add(covered bool) {
+	r.Total++
+	if covered {
+		r.Covered++
+	}
+}
+
+// Coverage is line coverage read from a coverage report.
+type Coverage struct {
+	// Source is the report's path relative to the worktree.
+	Source string
+	// lines maps a file, relative to the worktree, to whether each of its
+	// instrumented lines ran.
+	lines map[string]map[int]bool
+}
+
+// CoverageReport summarizes coverage for a worktree's changes.
+type CoverageReport struct {
+	Source string
+	// Total covers every instrumented line in the report.
+	Total Ratio
+	// Diff covers the instrumented lines the worktree changed.
+	Diff Ratio
+	// Files is the diff coverage of each changed file with instrumented
+	// lines, keyed by path relative to the worktree.
+	Files map[string]Ratio
+}
+
+// FindCoverage reads the newest coverage report in root written at or after
+// since, so a report left over from an earlier run is not mistaken for this
+// one. It returns nil when there is none.
+func FindCoverage(root string, since time.Time) (*Coverage, error) {
+	var newest string
+	var newestAt time.Time
+	for _, name := range coverageFiles {
+		info, err := os.Stat(filepath.Join(root, name))
+		if err != nil || !info.Mode().IsRegular() || info.ModTime().Before(since) {
+			continue
+		}
+		if newest == "" || info.ModTime().After(newestAt) {
+			newest, newestAt = name, info.ModTime()
+		}
+	}
+	if newest == "" {
+		return nil, nil
+	}
+	raw, err := os.ReadFile(filepath.Join(root, newest))
+	if err != nil {
+		return nil, err
+	}
+	var lines map[string]map[int]bool
+	switch {
+	case strings.HasSuffix(newest, ".xml"):
+		lines, err = parseCobertura(root, raw)
+	case strings.HasSuffix(newest, ".info"):
+		lines = parseLCOV(root, string(raw))
+	default:
+		lines, err = parseGoProfile(root, string(raw))
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &Coverage{Source: newest, lines: lines}, nil
+}
+
+// Report measures c against changed, the changed line numbers of each file
+// relative to the worktree.
+func (c *Coverage) Report(changed map[string][]int) CoverageReport {
+	report := CoverageReport{Source: c.Source, Files: make(map[string]Ratio)}
+	for _, lines := range c.lines {
+		for _, covered := range lines {
+			report.Total.add(covered)
+		}
+	}
+	for file, numbers := range changed {
+		lines, ok := c.lines[file]
+		if !ok {
+			continue
+		}
+		var r Ratio
+		for _, n := range numbers {
+			if covered, ok := lines[n]; ok {
+				r.add(covered)
+			}
+		}
+		if r.Total > 0 {
+			report.Files[file] = r
+			report.Diff.Covered += r.Covered
+			report.Diff.Total += r.Total
+		}
+	}
+	return report
+}
+
+// SortedFiles returns the report's files, least covered first.
+func (r CoverageReport) SortedFiles() []string {
+	files := make([]string, 0, len(r.Files))
+	for file := range r.Files {
+		files = append(files, file)
+	}
+	sort.Slice(files, func(i, j int) bool {
+		pi, pj := r.Files[files[i]].Percent(), r.Files[files[j]].Percent()
+		if pi != pj {
+			return pi < pj
+		}
+		return files[i] < files[j]
+	})
+	return files
+}
+
+// markLine records a line's coverage. A line with several blocks counts as
+// covered only when all of them ran.
+func markLine(lines map[string]map[int]bool, file string, line int, covered bool) {
+	if lines[file] == nil {
+		lines[file] = make(map[int]bool)
+	}
+	if prev, ok := lines[file][line]; ok {
+		covered = covered && prev
+	}
+	lines[file][line] = covered
+}
+
+// parseGoProfile reads a go test -coverprofile profile. Files are named by
+// import path and are mapped into root through its go.mod module path.
+func parseGoProfile(root, profile string) (map[string]map[int]bool, error) {
+	scanner := bufio.NewScanner(strings.NewReader(profile))
+	if !scanner.Scan() || !strings.HasPrefix(scanner.Text(), "mode:") {
+		return nil, errors.New("not a go coverage profile")
+	}
+	module := goModule(root)
+	// The same block appears once per test binary that covers it; it ran
+	// if any of them ran it.
+	blocks := make(map[string]bool)
+	for scanner.Scan() {
+		// pkg/file.go:startLine.startCol,endLine.endCol numStmts count
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 3 {
+			continue
+		}
+		count, err := strconv.Atoi(fields[2])
+		if err != nil {
+			continue
+		}
+		blocks[fields[0]] = blocks[fields[0]] || count > 0
+	}
+	lines := make(map[string]map[int]bool)
+	for block, covered := range blocks {
+		file, span, ok := strings.Cut(block, ":")
+		if !ok {
+			continue
+		}
+		start, end, ok := strings.Cut(span, ",")
+		if !ok {
+			continue
+		}
+		startLine, err1 := strconv.Atoi(strings.SplitN(start, ".", 2)[0])
+		endLine, err2 := strconv.Atoi(strings.SplitN(end, ".", 2)[0])
+		if err1 != nil || err2 != nil {
+			continue
+		}
+		if module != "" {
+			file = strings.TrimPrefix(file, module+"/")
+		}
+		for n := startLine; n <= endLine; n++ {
+			markLine(lines, file, n, covered)
+		}
+	}
+	return lines, scanner.Err()
+}
+
+// parseLCOV reads an LCOV tracefile's per-line hit counts.
+func parseLCOV(root, report string) map[string]map[int]bool {
+	lines := make(map[string]map[int]bool)
+	var file string
+	for _, line := range strings.Split(report, "\n") {
+		line = strings.TrimSpace(line)
+		switch {
+		case strings.HasPrefix(line, "SF:"):
+			file = relativeTo(root, strings.TrimPrefix(line, "SF:"))
+		case strings.HasPrefix(line, "DA:") && file != "":
+			n, hits, ok := strings.Cut(strings.TrimPrefix(line, "DA:"), ",")
+			if !ok {
+				continue
+			}
+			hits, _, _ = strings.Cut(hits, ",")
+			number, err1 := strconv.Atoi(n)
+			count, err2 := strconv.Atoi(hits)
+			if err1 == nil && err2 == nil {
+				markLine(lines, file, number, count > 0)
+			}
+		case line == "end_of_record":
+			file = ""
+		}
+	}
+	return lines
+}
+
+type coberturaReport struct {
+	Sources []string `xml:"sources>source"`
+	Classes []struct {
+		Filename string `xml:"filename,attr"`
+		Lines    []struct {
+			Number int `xml:"number,attr"`
+			Hits   int `xml:"hits,attr"`
+		} `xml:"lines>line"`
+	} `xml:"packages>package>classes>class"`
+}
+
+// parseCobertura reads a Cobertura XML report. Class files are relative to
+// the report's first source directory.
+func parseCobertura(root string, raw []byte) (map[string]map[int]bool, error) {
+	var report coberturaReport
+	if err := xml.Unmarshal(raw, &report); err != nil {
+		return nil, err
+	}
+	source := root
+	if len(report.Sources) > 0 && strings.TrimSpace(report.Sources[0]) != "" {
+		source = strings.TrimSpace(report.Sources[0])
+		if !filepath.IsAbs(source) {
+			source = filepath.Join(root, source)
+		}
+	}
+	lines := make(map[string]map[int]bool)
+	for _, class := range report.Classes {
+		file := class.Filename
+		if !filepath.IsAbs(file) {
+			file = filepath.Join(source, file)
+		}
+		file = relativeTo(root, file)
+		for _, line := range class.Lines {
+			markLine(lines, file, line.Number, line.Hits > 0)
+		}
+	}
+	return lines, nil
+}
+
+// relativeTo makes an absolute path inside root relative to it.
+func relativeTo(root, path string) string {
+	if filepath.IsAbs(path) {
+		if rel, err := filepath.Rel(root, path); err == nil && !strings.HasPrefix(rel, "..") {
+			return filepath.ToSlash(rel)
+		}
+	}
+	return filepath.ToSlash(path)
+}
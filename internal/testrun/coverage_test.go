@@ -0,0 +1,83 @@
+package testrun
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+)
+
+func writeFile(t *testing.T, root, name, content string) {
+	t.Helper()
+	path := filepath.Join(root, name)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCoverageFromGoProfile(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, root, "go.mod", "module example.com/calc\n")
+	writeFile(t, root, "coverage.out", `mode: set
+example.com/calc/add.go:3.20,5.2 1 1
+example.com/calc/add.go:7.20,9.2 1 0
+example.com/calc/add.go:7.20,9.2 1 1
+example.com/calc/sub.go:3.20,4.2 1 0
+`)
+	cov, err := FindCoverage(root, time.Now().Add(-time.Minute))
+	if err != nil || cov == nil {
+		t.Fatalf("FindCoverage() = %v, %v", cov, err)
+	}
+	report := cov.Report(map[string][]int{"add.go": {4, 8, 20}, "sub.go": {3}, "README.md": {1}})
+	// add.go 3-5 and 7-9 ran (the second block in one of two binaries);
+	// sub.go 3-4 did not.
+	if report.Total != (Ratio{Covered: 6, Total: 8}) {
+		t.Fatalf("Total = %+v, want 6/8", report.Total)
+	}
+	if report.Diff != (Ratio{Covered: 2, Total: 3}) {
+		t.Fatalf("Diff = %+v, want 2/3", report.Diff)
+	}
+	if got := report.SortedFiles(); !slices.Equal(got, []string{"sub.go", "add.go"}) {
+		t.Fatalf("SortedFiles() = %v, want least covered first", got)
+	}
+	if report.Diff.Percent() != 66 {
+		t.Fatalf("Diff.Percent() = %d, want 66", report.Diff.Percent())
+	}
+}
+
+func TestCoverageFromLCOVAndCobertura(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, root, "coverage/lcov.info", "TN:\nSF:"+filepath.Join(root, "src/add.js")+"\nDA:1,3\nDA:2,0\nend_of_record\n")
+	cov, err := FindCoverage(root, time.Time{})
+	if err != nil || cov == nil || cov.Source != "coverage/lcov.info" {
+		t.Fatalf("FindCoverage() = %+v, %v", cov, err)
+	}
+	if got := cov.Report(map[string][]int{"src/add.js": {1, 2}}).Files["src/add.js"]; got != (Ratio{Covered: 1, Total: 2}) {
+		t.Fatalf("src/add.js = %+v, want 1/2", got)
+	}
+
+	root = t.TempDir()
+	writeFile(t, root, "coverage.xml", `<?xml version="1.0" ?>
+<coverage><sources><source>`+root+`</source></sources><packages><package><classes>
+<class filename="pkg/mod.py"><lines><line number="1" hits="1"/><line number="2" hits="0"/></lines></class>
+</classes></package></packages></coverage>`)
+	cov, err = FindCoverage(root, time.Time{})
+	if err != nil || cov == nil {
+		t.Fatalf("FindCoverage() = %+v, %v", cov, err)
+	}
+	if got := cov.Report(map[string][]int{"pkg/mod.py": {2}}).Diff; got != (Ratio{Covered: 0, Total: 1}) {
+		t.Fatalf("Diff = %+v, want 0/1", got)
+	}
+}
+
+func TestFindCoverageIgnoresStaleReports(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, root, "coverage.out", "mode: set\n")
+	if cov, err := FindCoverage(root, time.Now().Add(time.Hour)); cov != nil || err != nil {
+		t.Fatalf("FindCoverage() = %+v, %v; want no report older than the run", cov, err)
+	}
+}
@@ -0,0 +1,218 @@
+package testrun
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var (
+	goFailHeader   = regexp.MustCompile(`^(\s*)--- FAIL: (\S+)`)
+	goPackageLine  = regexp.MustCompile(`^(?:FAIL|ok)\s+(\S+)`)
+	goLocation     = regexp.MustCompile(`^\s+([\w.\-/]+\.go):(\d+):`)
+	goBuildPackage = regexp.MustCompile(`^# (\S+)`)
+	goBuildError   = regexp.MustCompile(`^([\w.\-/]+\.go):(\d+):\d+: `)
+
+	jestHeader   = regexp.MustCompile(`^\s*● (.+)$`)
+	jestFileLine = regexp.MustCompile(`^\s*FAIL\s+(\S+)`)
+	jestEnd      = regexp.MustCompile(`^(Test Suites:|Tests:|Snapshots:|\s*(PASS|FAIL)\s)`)
+	jestLocation = regexp.MustCompile(`at .*?\(?([^\s()]+):(\d+):\d+\)?$`)
+
+	pytestHeader   = regexp.MustCompile(`^_{3,} (.+?) _{3,}$`)
+	pytestSection  = regexp.MustCompile(`^={3,} (.+?) ={3,}$`)
+	pytestLocation = regexp.MustCompile(`^([^\s:]+\.py):(\d+): `)
+	pytestSummary  = regexp.MustCompile(`^FAILED (\S+?\.py)::(\S+)`)
+)
+
+// Parse returns the failing tests reported in output, in the order reported.
+func Parse(output string) []Failure {
+	lines := strings.Split(strings.ReplaceAll(output, "\r\n", "\n"), "\n")
+	var failures []Failure
+	failures = append(failures, parseGo(lines)...)
+	failures = append(failures, parseJest(lines)...)
+	failures = append(failures, parsePytest(lines)...)
+	return failures
+}
+
+// parseGo reads go test output: "--- FAIL" headers with their indented
+// reports, the "FAIL <package>" line that follows them, and compile errors
+// under "# <package>".
+func parseGo(lines []string) []Failure {
+	var failures []Failure
+	current, indent, pending := -1, 0, 0
+	buildPkg := ""
+	for _, line := range lines {
+		if m := goFailHeader.FindStringSubmatch(line); m != nil {
+			failures = append(failures, Failure{Name: m[2]})
+			current, indent = len(failures)-1, len(m[1])
+			continue
+		}
+		if m := goBuildPackage.FindStringSubmatch(line); m != nil {
+			buildPkg, current = m[1], -1
+			continue
+		}
+		if buildPkg != "" {
+			if m := goBuildError.FindStringSubmatch(line); m != nil {
+				if current < 0 {
+					failures = append(failures, Failure{Name: "build " + buildPkg, File: m[1], Line: atoi(m[2])})
+					current = len(failures) - 1
+				}
+				addLine(&failures[current], line)
+				continue
+			}
+			buildPkg = ""
+		}
+		if m := goPackageLine.FindStringSubmatch(line); m != nil {
+			for i := pending; i < len(failures); i++ {
+				failures[i].pkg = m[1]
+			}
+			pending, current = len(failures), -1
+			continue
+		}
+		if current < 0 || strings.TrimSpace(line) == "" {
+			continue
+		}
+		if len(line)-len(strings.TrimLeft(line, " \t")) <= indent {
+			current = -1
+			continue
+		}
+		f := &failures[current]
+		if f.File == "" {
+			if m := goLocation.FindStringSubmatch(line); m != nil {
+				f.File, f.Line = m[1], atoi(m[2])
+			}
+		}
+		addLine(f, strings.TrimSpace(line))
+	}
+	return dropFailedParents(failures)
+}
+
+// dropFailedParents removes Go tests that failed only because one of their
+// subtests did.
+func dropFailedParents(failures []Failure) []Failure {
+	out := failures[:0]
+	for i, f := range failures {
+		parent := false
+		for j, g := range failures {
+			if i != j && strings.HasPrefix(g.Name, f.Name+"/") {
+				parent = true
+				break
+			}
+		}
+		if !parent || len(f.Output) > 0 {
+			out = append(out, f)
+		}
+	}
+	return out
+}
+
+// parseJest reads Jest's "●" failure blocks.
+func parseJest(lines []string) []Failure {
+	var failures []Failure
+	current := -1
+	file := ""
+	for _, line := range lines {
+		if m := jestFileLine.FindStringSubmatch(line); m != nil {
+			file, current = m[1], -1
+			continue
+		}
+		if m := jestHeader.FindStringSubmatch(line); m != nil {
+			failures = append(failures, Failure{Name: strings.TrimSpace(m[1]), File: file})
+			current = len(failures) - 1
+			continue
+		}
+		if current < 0 {
+			continue
+		}
+		if jestEnd.MatchString(line) {
+			current = -1
+			continue
+		}
+		f := &failures[current]
+		if m := jestLocation.FindStringSubmatch(line); m != nil {
+			if f.Line == 0 && !strings.Contains(m[1], "node_modules") && !strings.HasPrefix(m[1], "node:") {
+				f.File, f.Line = m[1], atoi(m[2])
+			}
+			continue
+		}
+		addLine(f, strings.TrimSpace(line))
+	}
+	for i := range failures {
+		failures[i].Output = trimBlank(failures[i].Output)
+	}
+	return failures
+}
+
+// parsePytest reads pytest's FAILURES section and its short summary.
+func parsePytest(lines []string) []Failure {
+	var blocks []Failure
+	var summary []Failure
+	inFailures := false
+	current := -1
+	for _, line := range lines {
+		if m := pytestSection.FindStringSubmatch(line); m != nil {
+			inFailures = m[1] == "FAILURES"
+			current = -1
+			continue
+		}
+		if m := pytestSummary.FindStringSubmatch(line); m != nil {
+			summary = append(summary, Failure{Name: m[1] + "::" + m[2], File: m[1]})
+			continue
+		}
+		if !inFailures {
+			continue
+		}
+		if m := pytestHeader.FindStringSubmatch(line); m != nil {
+			blocks = append(blocks, Failure{Name: m[1]})
+			current = len(blocks) - 1
+			continue
+		}
+		if current < 0 {
+			continue
+		}
+		f := &blocks[current]
+		if m := pytestLocation.FindStringSubmatch(line); m != nil {
+			f.File, f.Line = m[1], atoi(m[2])
+		}
+		addLine(f, line)
+	}
+	if len(summary) == 0 {
+		return blocks
+	}
+	for i := range summary {
+		s := &summary[i]
+		test := strings.ReplaceAll(s.Name[strings.Index(s.Name, "::")+2:], "::", ".")
+		for _, b := range blocks {
+			if b.Name == test {
+				s.Output = trimBlank(b.Output)
+				if b.File == s.File {
+					s.Line = b.Line
+				}
+				break
+			}
+		}
+	}
+	return summary
+}
+
+func addLine(f *Failure, line string) {
+	if len(f.Output) < maxFailureLines {
+		f.Output = append(f.Output, line)
+	}
+}
+
+// trimBlank drops leading and trailing blank lines.
+func trimBlank(lines []string) []string {
+	for len(lines) > 0 && strings.TrimSpace(lines[0]) == "" {
+		lines = lines[1:]
+	}
+	for len(lines) > 0 && strings.TrimSpace(lines[len(lines)-1]) == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+func atoi(s string) int {
+	n, _ := strconv.Atoi(s)
+	return n
+}
@@ -0,0 +1,180 @@
+// Package testrun runs a worktree's test command and extracts the failing
+// tests from its output, so they can be listed, opened, and handed to an
+// agent.
+//
+// Failures are recognized in the output formats of go test, Jest, and
+// pytest. Output in any other format still runs and reports its exit status,
+// but yields no failures. Coverage reports a run leaves behind (Go profiles,
+// LCOV, and Cobertura XML) are read to measure coverage of a worktree's
+// changed lines.
+package testrun
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// maxOutput bounds the output kept from a run; the tail is kept, since test
+// runners print their failure summaries last.
+const maxOutput = 1 << 20
+
+// maxFailureLines bounds the output kept for each failure.
+const maxFailureLines = 40
+
+// Failure is one failing test.
+type Failure struct {
+	Name string
+	// File is the failing test's file, relative to the worktree when known.
+	File string
+	Line int
+	// Output is the runner's report for this test.
+	Output []string
+	// pkg is the Go import path the test belongs to, used to resolve File.
+	pkg string
+}
+
+// Location formats the failure's file and line, or "" when unknown.
+func (f Failure) Location() string {
+	switch {
+	case f.File == "":
+		return ""
+	case f.Line > 0:
+		return fmt.Sprintf("%s:%d", f.File, f.Line)
+	default:
+		return f.File
+	}
+}
+
+// Result is the outcome of one test run.
+type Result struct {
+	Command  string
+	ExitCode int
+	Output   string
+	Failures []Failure
+}
+
+// Passed reports whether the run succeeded.
+func (r Result) Passed() bool {
+	return r.ExitCode == 0
+}
+
+// Detect returns the conventional test command for the project at root, or
+// "" when none is recognized.
+func Detect(root string) string {
+	exists := func(name string) bool {
+		_, err := os.Stat(filepath.Join(root, name))
+		return err == nil
+	}
+	switch {
+	case exists("go.mod"):
+		return "go test ./..."
+	case exists("package.json"):
+		return "npm test"
+	case exists("pytest.ini"), exists("conftest.py"), exists("pyproject.toml"), exists("setup.cfg"), exists("tox.ini"):
+		return "pytest"
+	}
+	return ""
+}
+
+// Run runs command with sh in dir and parses its failures. A nonzero exit is
+// reported in the result, not as an error; the error is for a command that
+// could not be started.
+func Run(ctx context.Context, dir, command string, env []string) (Result, error) {
+	cmd := exec.CommandContext(ctx, "sh", "-c", command)
+	cmd.Dir = dir
+	cmd.Env = env
+	var out tailBuffer
+	cmd.Stdout = &out
+	cmd.Stderr = &out
+	err := cmd.Run()
+	result := Result{Command: command, Output: out.String()}
+	if err != nil {
+		exitErr, ok := err.(*exec.ExitError)
+		if !ok {
+			return result, err
+		}
+		result.ExitCode = exitErr.ExitCode()
+	}
+	result.Failures = resolvePaths(dir, Parse(result.Output))
+	return result, nil
+}
+
+// tailBuffer keeps the last maxOutput bytes written to it.
+type tailBuffer struct {
+	buf bytes.Buffer
+}
+
+func (t *tailBuffer) Write(p []byte) (int, error) {
+	t.buf.Write(p)
+	if over := t.buf.Len() - maxOutput; over > 0 {
+		t.buf.Next(over)
+	}
+	return len(p), nil
+}
+
+func (t *tailBuffer) String() string {
+	return t.buf.String()
+}
+
+// Prompt formats failures as a request for an agent to fix them.
+func Prompt(command string, failures []Failure) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "`%s` fails in this worktree. Please fix these failing tests:\n", command)
+	for i, f := range failures {
+		fmt.Fprintf(&b, "\n%d. %s", i+1, f.Name)
+		if loc := f.Location(); loc != "" {
+			fmt.Fprintf(&b, " (%s)", loc)
+		}
+		b.WriteString("\n")
+		for _, line := range f.Output {
+			b.WriteString("    " + line + "\n")
+		}
+	}
+	return b.String()
+}
+
+// resolvePaths makes failure files relative to root. Go reports only a file's
+// base name, so it is joined to the package's directory under the module.
+func resolvePaths(root string, failures []Failure) []Failure {
+	module := goModule(root)
+	for i := range failures {
+		f := &failures[i]
+		if f.File == "" {
+			continue
+		}
+		if f.pkg != "" && module != "" && !strings.Contains(f.File, "/") {
+			if f.pkg == module {
+				continue
+			}
+			if rel, ok := strings.CutPrefix(f.pkg, module+"/"); ok {
+				f.File = filepath.ToSlash(filepath.Join(rel, f.File))
+			}
+			continue
+		}
+		if filepath.IsAbs(f.File) {
+			if rel, err := filepath.Rel(root, f.File); err == nil && !strings.HasPrefix(rel, "..") {
+				f.File = filepath.ToSlash(rel)
+			}
+		}
+	}
+	return failures
+}
+
+// goModule returns the module path declared in root's go.mod, or "".
+func goModule(root string) string {
+	raw, err := os.ReadFile(filepath.Join(root, "go.mod"))
+	if err != nil {
+		return ""
+	}
+	for _, line := range strings.Split(string(raw), "\n") {
+		if rest, ok := strings.CutPrefix(strings.TrimSpace(line), "module "); ok {
+			return strings.Trim(strings.TrimSpace(rest), `"`)
+		}
+	}
+	return ""
+}
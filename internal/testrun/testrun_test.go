@@ -0,0 +1,121 @@
+package testrun
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const goOutput = `--- FAIL: TestAdd (0.00s)
+    add_test.go:12: got 2, want 3
+--- FAIL: TestTable (0.00s)
+    --- FAIL: TestTable/negative (0.00s)
+        table_test.go:30: got -1
+FAIL
+FAIL	example.com/calc/internal/add	0.004s
+ok  	example.com/calc/internal/sub	0.002s
+# example.com/calc/internal/mul
+internal/mul/mul.go:7:2: undefined: product
+FAIL	example.com/calc/internal/mul [build failed]
+`
+
+const jestOutput = ` FAIL  src/add.test.js
+  ● math › adds numbers
+
+    expect(received).toBe(expected) // Object.is equality
+
+    Expected: 3
+    Received: 2
+
+      at Object.<anonymous> (src/add.test.js:11:23)
+      at Promise.then.completed (node_modules/jest-circus/build/utils.js:298:28)
+
+Test Suites: 1 failed, 1 total
+`
+
+const pytestOutput = `=================================== FAILURES ===================================
+_________________________________ TestMath.test_add _________________________________
+
+    def test_add(self):
+>       assert add(1, 1) == 3
+E       assert 2 == 3
+
+tests/test_math.py:5: AssertionError
+=========================== short test summary info ============================
+FAILED tests/test_math.py::TestMath::test_add - assert 2 == 3
+========================= 1 failed, 3 passed in 0.05s ==========================
+`
+
+func TestParseGo(t *testing.T) {
+	root := t.TempDir()
+	if err := os.WriteFile(filepath.Join(root, "go.mod"), []byte("module example.com/calc\n\ngo 1.22\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	got := resolvePaths(root, Parse(goOutput))
+	want := []string{
+		"TestAdd internal/add/add_test.go:12",
+		"TestTable/negative internal/add/table_test.go:30",
+		"build example.com/calc/internal/mul internal/mul/mul.go:7",
+	}
+	if len(got) != len(want) {
+		t.Fatalf("Parse() = %+v, want %d failures", got, len(want))
+	}
+	for i, w := range want {
+		if s := got[i].Name + " " + got[i].Location(); s != w {
+			t.Errorf("failure %d = %q, want %q", i, s, w)
+		}
+	}
+	if len(got[0].Output) != 1 || got[0].Output[0] != "add_test.go:12: got 2, want 3" {
+		t.Fatalf("TestAdd output = %q", got[0].Output)
+	}
+}
+
+func TestParseJest(t *testing.T) {
+	got := Parse(jestOutput)
+	if len(got) != 1 || got[0].Name != "math › adds numbers" || got[0].Location() != "src/add.test.js:11" {
+		t.Fatalf("Parse() = %+v", got)
+	}
+	if got[0].Output[0] != "expect(received).toBe(expected) // Object.is equality" {
+		t.Fatalf("output = %q", got[0].Output)
+	}
+}
+
+func TestParsePytest(t *testing.T) {
+	got := Parse(pytestOutput)
+	if len(got) != 1 || got[0].Name != "tests/test_math.py::TestMath::test_add" || got[0].Location() != "tests/test_math.py:5" {
+		t.Fatalf("Parse() = %+v", got)
+	}
+	if !strings.Contains(strings.Join(got[0].Output, "\n"), "E       assert 2 == 3") {
+		t.Fatalf("output = %q", got[0].Output)
+	}
+}
+
+func TestDetect(t *testing.T) {
+	root := t.TempDir()
+	if got := Detect(root); got != "" {
+		t.Fatalf("Detect(empty) = %q", got)
+	}
+	if err := os.WriteFile(filepath.Join(root, "package.json"), []byte("{}"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if got := Detect(root); got != "npm test" {
+		t.Fatalf("Detect(node) = %q", got)
+	}
+}
+
+func TestRunReportsExitAndFailures(t *testing.T) {
+	res, err := Run(context.Background(), t.TempDir(), "printf -- '--- FAIL: TestX (0.00s)\\n    x_test.go:3: nope\\n'; exit 1", nil)
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if res.Passed() || res.ExitCode != 1 || len(res.Failures) != 1 || res.Failures[0].Location() != "x_test.go:3" {
+		t.Fatalf("Run() = %+v", res)
+	}
+
+	prompt := Prompt(res.Command, res.Failures)
+	if !strings.Contains(prompt, "1. TestX (x_test.go:3)") || !strings.Contains(prompt, "    x_test.go:3: nope") {
+		t.Fatalf("Prompt() = %q", prompt)
+	}
+}
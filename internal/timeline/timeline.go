@@ -0,0 +1,182 @@
+// Package timeline assembles a worktree's lifecycle timeline: the events
+// amux recorded in its metadata (created, agents launched and exited,
+// deleted) merged with what git and the repository's hosting service know
+// about its branch (commits made, pull request opened).
+package timeline
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/andyrewlee/amux/internal/data"
+	"github.com/andyrewlee/amux/internal/forge"
+	"github.com/andyrewlee/amux/internal/git"
+)
+
+// maxCommits bounds the commits read from the branch.
+const maxCommits = 200
+
+// lookupTimeout bounds each git or hosting service call.
+const lookupTimeout = 10 * time.Second
+
+var (
+	runGit    = git.RunGitCtx
+	openForge = forge.Open
+)
+
+// Build returns ws's timeline, oldest first: its recorded history plus the
+// commits and pull request found for its branch that weren't recorded yet.
+func Build(ws *data.Workspace) []data.HistoryEvent {
+	if ws == nil {
+		return nil
+	}
+	return Merge(ws.History, Derived(ws))
+}
+
+// Derived returns the events read from git and the hosting service rather
+// than recorded: the
+// branch's commits since its base and the pull request opened from it. A
+// lookup that fails contributes nothing.
+func Derived(ws *data.Workspace) []data.HistoryEvent {
+	if ws == nil {
+		return nil
+	}
+	events := commits(ws)
+	if pr, ok := FindPR(ws); ok {
+		events = append(events, data.HistoryEvent{
+			Kind:   data.HistoryPROpened,
+			At:     pr.CreatedAt,
+			Detail: fmt.Sprintf("#%d %s %s", pr.Number, pr.Title, pr.URL),
+		})
+	}
+	return events
+}
+
+// Merge adds the derived events that recorded doesn't already hold and sorts
+// the result oldest first.
+func Merge(recorded, derived []data.HistoryEvent) []data.HistoryEvent {
+	out := slices.Clone(recorded)
+	for _, e := range derived {
+		if !slices.ContainsFunc(recorded, func(r data.HistoryEvent) bool {
+			return r.Kind == e.Kind && r.Detail == e.Detail
+		}) {
+			out = append(out, e)
+		}
+	}
+	slices.SortStableFunc(out, func(a, b data.HistoryEvent) int { return a.At.Compare(b.At) })
+	return out
+}
+
+// commits lists the branch's commits since its base, from the worktree or,
+// once the worktree is gone, from the primary checkout.
+func commits(ws *data.Workspace) []data.HistoryEvent {
+	if ws.Base == "" {
+		return nil
+	}
+	dir, tip := ws.Root, "HEAD"
+	if _, err := os.Stat(dir); err != nil {
+		dir, tip = ws.Repo, ws.Branch
+	}
+	if dir == "" || tip == "" {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), lookupTimeout)
+	defer cancel()
+	out, err := runGit(ctx, dir, "log", "--no-merges", fmt.Sprintf("--max-count=%d", maxCommits),
+		"--format=%h%x1f%cI%x1f%s", ws.Base+".."+tip, "--")
+	if err != nil {
+		return nil
+	}
+	return parseCommits(out)
+}
+
+func parseCommits(out string) []data.HistoryEvent {
+	var events []data.HistoryEvent
+	for line := range strings.SplitSeq(strings.TrimSpace(out), "\n") {
+		hash, rest, ok := strings.Cut(line, "\x1f")
+		if !ok {
+			continue
+		}
+		date, subject, _ := strings.Cut(rest, "\x1f")
+		at, err := time.Parse(time.RFC3339, date)
+		if err != nil {
+			continue
+		}
+		events = append(events, data.HistoryEvent{Kind: data.HistoryCommit, At: at, Detail: hash + " " + subject})
+	}
+	return events
+}
+
+// FindPR finds the newest pull request opened from ws's branch on the
+// service hosting its repository's remote, when that service's CLI or
+// credentials are available.
+func FindPR(ws *data.Workspace) (forge.PR, bool) {
+	if ws == nil || ws.Branch == "" || ws.Repo == "" {
+		return forge.PR{}, false
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), lookupTimeout)
+	defer cancel()
+	provider, err := openForge(ctx, ws.Repo)
+	if err != nil {
+		return forge.PR{}, false
+	}
+	pr, ok, err := provider.FindPR(ctx, ws.Branch)
+	if err != nil || !ok {
+		return forge.PR{}, false
+	}
+	return pr, true
+}
+
+// Label describes an event kind for people.
+func Label(kind string) string {
+	switch kind {
+	case data.HistoryCreated:
+		return "created"
+	case data.HistoryAgentLaunched:
+		return "agent launched"
+	case data.HistoryAgentExited:
+		return "agent exited"
+	case data.HistoryCommit:
+		return "commit"
+	case data.HistoryPROpened:
+		return "PR opened"
+	case data.HistoryDeleted:
+		return "deleted"
+	}
+	return strings.ReplaceAll(kind, "_", " ")
+}
+
+// Line formats one event for a list: its local time, label, and detail.
+func Line(e data.HistoryEvent) string {
+	line := fmt.Sprintf("%s  %-14s", e.At.Local().Format("2006-01-02 15:04"), Label(e.Kind))
+	if e.Detail != "" {
+		line += "  " + e.Detail
+	}
+	return strings.TrimRight(line, " ")
+}
+
+// Write prints events one per line.
+func Write(out io.Writer, events []data.HistoryEvent) error {
+	for _, e := range events {
+		if _, err := fmt.Fprintln(out, Line(e)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// WriteJSON prints events as a JSON array, oldest first.
+func WriteJSON(out io.Writer, events []data.HistoryEvent) error {
+	if events == nil {
+		events = []data.HistoryEvent{}
+	}
+	enc := json.NewEncoder(out)
+	enc.SetIndent("", "  ")
+	return enc.Encode(events)
+}
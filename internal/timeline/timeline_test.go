@@ -0,0 +1,106 @@
+package timeline
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/andyrewlee/amux/internal/data"
+	"github.com/andyrewlee/amux/internal/forge"
+)
+
+// fakeForge finds one pull request, or none when pr is nil.
+type fakeForge struct {
+	forge.Provider
+	pr *forge.PR
+}
+
+func (f fakeForge) FindPR(context.Context, string) (forge.PR, bool, error) {
+	if f.pr == nil {
+		return forge.PR{}, false, nil
+	}
+	return *f.pr, true, nil
+}
+
+// stubLookups stubs git to print gitOut and the hosting service to know pr;
+// a nil pr means the repository has no known service.
+func stubLookups(t *testing.T, gitOut string, pr *forge.PR) {
+	t.Helper()
+	oldGit, oldForge := runGit, openForge
+	t.Cleanup(func() { runGit, openForge = oldGit, oldForge })
+	runGit = func(_ context.Context, _ string, args ...string) (string, error) {
+		return gitOut, nil
+	}
+	openForge = func(context.Context, string) (forge.Provider, error) {
+		if pr == nil {
+			return nil, errors.New("no remote")
+		}
+		return fakeForge{pr: pr}, nil
+	}
+}
+
+func TestBuildMergesRecordedAndDerived(t *testing.T) {
+	at := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	stubLookups(t,
+		"abc1234\x1f2026-03-01T10:00:00Z\x1fAdd parser\n"+
+			"def5678\x1f2026-03-01T09:30:00Z\x1fRecorded already\n",
+		&forge.PR{Number: 12, Title: "Parser", URL: "https://example.com/pr/12", CreatedAt: at.Add(2 * time.Hour)})
+	ws := &data.Workspace{Repo: "/repo", Root: "/repo", Branch: "parser", Base: "origin/main"}
+	ws.RecordHistory(data.HistoryCreated, "", at)
+	ws.RecordHistory(data.HistoryCommit, "def5678 Recorded already", at.Add(30*time.Minute))
+	ws.RecordHistory(data.HistoryAgentExited, "claude", at.Add(3*time.Hour))
+
+	events := Build(ws)
+	var kinds []string
+	for _, e := range events {
+		kinds = append(kinds, e.Kind)
+	}
+	want := []string{data.HistoryCreated, data.HistoryCommit, data.HistoryCommit, data.HistoryPROpened, data.HistoryAgentExited}
+	if strings.Join(kinds, ",") != strings.Join(want, ",") {
+		t.Fatalf("kinds = %v, want %v (oldest first, recorded commit not repeated)", kinds, want)
+	}
+	if pr := events[3]; pr.Detail != "#12 Parser https://example.com/pr/12" {
+		t.Fatalf("PR detail = %q", pr.Detail)
+	}
+}
+
+func TestDerivedWithoutBaseOrForge(t *testing.T) {
+	stubLookups(t, "abc1234\x1f2026-03-01T10:00:00Z\x1fAdd parser\n", nil)
+	if events := Derived(&data.Workspace{Repo: "/repo", Root: "/repo", Branch: "parser"}); len(events) != 0 {
+		t.Fatalf("Derived() = %+v, want nothing without a base or a hosting service", events)
+	}
+}
+
+func TestWriteJSON(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteJSON(&buf, nil); err != nil {
+		t.Fatal(err)
+	}
+	if strings.TrimSpace(buf.String()) != "[]" {
+		t.Fatalf("empty timeline = %q, want []", buf.String())
+	}
+
+	buf.Reset()
+	at := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	if err := WriteJSON(&buf, []data.HistoryEvent{{Kind: data.HistoryAgentLaunched, At: at, Detail: "claude"}}); err != nil {
+		t.Fatal(err)
+	}
+	var got []map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 1 || got[0]["kind"] != "agent_launched" || got[0]["detail"] != "claude" || got[0]["at"] != "2026-03-01T09:00:00Z" {
+		t.Fatalf("JSON = %s", buf.String())
+	}
+}
+
+func TestLine(t *testing.T) {
+	e := data.HistoryEvent{Kind: data.HistoryPROpened, At: time.Now(), Detail: "#3 Fix"}
+	if got := Line(e); !strings.Contains(got, "PR opened") || !strings.HasSuffix(got, "#3 Fix") {
+		t.Fatalf("Line() = %q", got)
+	}
+}
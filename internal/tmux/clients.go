@@ -91,3 +91,10 @@ func SessionCreatedAt(sessionName string, opts Options) (int64, error) {
 	}
 	return 0, nil
 }
+
+// AttachArgs returns the tmux command line, starting with "tmux", that
+// attaches a terminal to sessionName on the amux server, for running tmux in
+// place of the current process.
+func AttachArgs(sessionName string, opts Options) []string {
+	return append([]string{"tmux"}, tmuxArgs(opts, "attach-session", "-t", sessionTarget(sessionName))...)
+}
@@ -2,6 +2,7 @@ package tmux
 
 import (
 	"os/exec"
+	"slices"
 	"testing"
 	"time"
 
@@ -228,3 +229,11 @@ func attachClient(t *testing.T, opts Options, session string) {
 	}
 	t.Skipf("client never attached to %q within deadline", session)
 }
+
+func TestAttachArgs(t *testing.T) {
+	got := AttachArgs("amux-ws-tab", Options{ServerName: "amux", ConfigPath: "/dev/null"})
+	want := []string{"tmux", "-L", "amux", "-f", "/dev/null", "attach-session", "-t", "=amux-ws-tab"}
+	if !slices.Equal(got, want) {
+		t.Fatalf("AttachArgs() = %q, want %q", got, want)
+	}
+}
@@ -26,15 +26,43 @@ func NewClientCommand(sessionName string, p ClientCommandParams) string {
 	return clientCommand(sessionName, p.WorkDir, p.Command, p.Options, p.Tags, p.DetachExisting)
 }
 
+// NewDetachedCommand builds the shell command string that creates a tmux
+// session like NewClientCommand, but leaves it running detached instead of
+// attaching to it. DetachExisting is ignored.
+func NewDetachedCommand(sessionName string, p ClientCommandParams) string {
+	if p.Options == (Options{}) {
+		p.Options = DefaultOptions()
+	}
+	return sessionSetupCommand(sessionName, p.WorkDir, p.Command, p.Options, p.Tags)
+}
+
 func clientCommand(sessionName, workDir, command string, opts Options, tags SessionTags, detachExisting bool) string {
+	base := tmuxBase(opts)
+	sessionTgt := shellutil.ShellQuote(sessionTarget(sessionName))
+
+	// Attach to the session, optionally detaching other clients.
+	attachFlag := "-t"
+	if detachExisting {
+		attachFlag = "-dt"
+	}
+	attach := fmt.Sprintf("%s attach %s %s", base, attachFlag, sessionTgt)
+
+	return sessionSetupCommand(sessionName, workDir, command, opts, tags) + attach
+}
+
+// sessionSetupCommand creates the session if it does not exist and applies
+// amux's per-session settings and tags. It ends with "; " so a command can
+// follow it.
+func sessionSetupCommand(sessionName, workDir, command string, opts Options, tags SessionTags) string {
 	base := tmuxBase(opts)
 	session := shellutil.ShellQuote(sessionName)
 	optionTgt := shellutil.ShellQuote(exactSessionOptionTarget(sessionName))
 	sessionTgt := shellutil.ShellQuote(sessionTarget(sessionName))
 	dir := shellutil.ShellQuote(workDir)
 	// Strip tmux-specific vars inside managed panes so `tmux` commands do not
-	// accidentally target the AMUX control server.
-	command = "unset TMUX TMUX_PANE; " + command
+	// accidentally target the AMUX control server. AMUX=1 lets an amux started
+	// in the pane detect that it is nested.
+	command = "unset TMUX TMUX_PANE; export AMUX=1; " + command
 	cmd := shellutil.ShellQuote(command)
 
 	// Ensure the session/server exists without attaching yet. tmux computes
@@ -66,14 +94,7 @@ func clientCommand(sessionName, workDir, command string, opts Options, tags Sess
 	settings.WriteString(fmt.Sprintf("%s set-option -t %s -w monitor-activity on 2>/dev/null; ", base, optionTgt))
 	appendSessionTags(&settings, base, optionTgt, tags)
 
-	// Attach to the session, optionally detaching other clients.
-	attachFlag := "-t"
-	if detachExisting {
-		attachFlag = "-dt"
-	}
-	attach := fmt.Sprintf("%s attach %s %s", base, attachFlag, sessionTgt)
-
-	return fmt.Sprintf("%s && %s && %s%s", ensureSession, syncFeatureSet, settings.String(), attach)
+	return fmt.Sprintf("%s && %s && %s", ensureSession, syncFeatureSet, settings.String())
 }
 
 func appendSessionTags(settings *strings.Builder, base, session string, tags SessionTags) {
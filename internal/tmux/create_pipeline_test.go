@@ -77,6 +77,31 @@ func TestClientCommandAppliesOptionsAndTagsOnRealTmux(t *testing.T) {
 	}
 }
 
+// TestDetachedCommandLeavesSessionRunning runs NewDetachedCommand, which
+// must succeed without a terminal and leave the tagged session detached.
+func TestDetachedCommandLeavesSessionRunning(t *testing.T) {
+	opts := realTmuxServerWithKeepalive(t)
+	const session = "create-detached"
+
+	cmdStr := NewDetachedCommand(session, ClientCommandParams{
+		WorkDir: t.TempDir(),
+		Command: "sleep 300",
+		Options: Options{ServerName: opts.ServerName, ConfigPath: opts.ConfigPath},
+		Tags:    SessionTags{WorkspaceID: "ws-detached", Type: "agent"},
+	})
+	if out, err := exec.Command("sh", "-c", cmdStr).CombinedOutput(); err != nil {
+		t.Fatalf("detached create failed: %v\n%s", err, out)
+	}
+	if strings.Contains(cmdStr, " attach ") {
+		t.Fatal("a detached create should not attach")
+	}
+
+	waitForSessionExists(t, opts, session)
+	if got := showSessionOption(t, opts, session, "@amux_workspace"); got != "ws-detached" {
+		t.Fatalf("@amux_workspace = %q, want ws-detached", got)
+	}
+}
+
 func waitForSessionExists(t *testing.T, opts Options, session string) {
 	t.Helper()
 	deadline := time.Now().Add(5 * time.Second)
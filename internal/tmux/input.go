@@ -0,0 +1,68 @@
+package tmux
+
+import "strings"
+
+// CapturePaneVisible captures the visible screen of a session's active pane
+// as plain text, one row per line.
+func CapturePaneVisible(sessionName string, opts Options) (string, error) {
+	paneID, err := sessionPaneID(sessionName, opts)
+	if err != nil {
+		return "", err
+	}
+	if paneID == "" {
+		return "", errPaneSnapshotUnavailable
+	}
+	cmd, cancel := tmuxCommand(opts, "capture-pane", "-p", "-t", paneID)
+	defer cancel()
+	output, err := runTmuxCmd(cmd)
+	if err != nil {
+		return "", err
+	}
+	return string(output), nil
+}
+
+// SendLiteral types text into a session's active pane as if entered on the
+// keyboard. Key names in text are not interpreted.
+func SendLiteral(sessionName, text string, opts Options) error {
+	if text == "" {
+		return nil
+	}
+	paneID, err := sessionPaneID(sessionName, opts)
+	if err != nil {
+		return err
+	}
+	if paneID == "" {
+		return errPaneSnapshotUnavailable
+	}
+	cmd, cancel := tmuxCommand(opts, "send-keys", "-l", "-t", paneID, "--", text)
+	defer cancel()
+	_, err = runTmuxCmd(cmd)
+	return err
+}
+
+// PasteText pastes text into a session's active pane through a tmux buffer,
+// as a bracketed paste when the program in the pane asked for one, so that
+// multi-line text reaches an agent as one input instead of line by line.
+func PasteText(sessionName, text string, opts Options) error {
+	if text == "" {
+		return nil
+	}
+	paneID, err := sessionPaneID(sessionName, opts)
+	if err != nil {
+		return err
+	}
+	if paneID == "" {
+		return errPaneSnapshotUnavailable
+	}
+	buffer := "amux-paste-" + strings.TrimPrefix(paneID, "%")
+	load, cancel := tmuxCommand(opts, "load-buffer", "-b", buffer, "-")
+	defer cancel()
+	load.Stdin = strings.NewReader(text)
+	if _, err := runTmuxCmd(load); err != nil {
+		return err
+	}
+	paste, cancelPaste := tmuxCommand(opts, "paste-buffer", "-p", "-d", "-b", buffer, "-t", paneID)
+	defer cancelPaste()
+	_, err = runTmuxCmd(paste)
+	return err
+}
@@ -0,0 +1,32 @@
+package tmux
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPasteText_PastesMultipleLines(t *testing.T) {
+	skipIfNoTmux(t)
+	opts := testServer(t)
+
+	createSession(t, opts, "paste-lines", "cat")
+
+	var err error
+	if !eventually(5*time.Second, func() bool {
+		err = PasteText("paste-lines", "first-line\nsecond-line\n", opts)
+		return err == nil
+	}) {
+		t.Fatalf("PasteText: %v", err)
+	}
+	var screen string
+	if !eventually(5*time.Second, func() bool {
+		screen, _ = CapturePaneVisible("paste-lines", opts)
+		return strings.Contains(screen, "first-line") && strings.Contains(screen, "second-line")
+	}) {
+		t.Fatalf("pasted lines not on screen: %q", screen)
+	}
+	if buffers, _ := listTmux(opts, "list-buffers", "-F", "#{buffer_name}"); len(buffers) != 0 {
+		t.Fatalf("paste left buffers behind: %v", buffers)
+	}
+}
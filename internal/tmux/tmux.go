@@ -51,6 +51,10 @@ func DefaultOptions() Options {
 	server := strings.TrimSpace(os.Getenv("AMUX_TMUX_SERVER"))
 	if server == "" {
 		server = "amux"
+		// Each profile gets its own server, so its sessions stay apart.
+		if profile := strings.TrimSpace(os.Getenv("AMUX_PROFILE_NAME")); profile != "" && profile != "default" {
+			server += "-" + profile
+		}
 	}
 	config := strings.TrimSpace(os.Getenv("AMUX_TMUX_CONFIG"))
 	if config == "" {
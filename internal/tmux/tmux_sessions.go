@@ -10,6 +10,12 @@ func ListSessions(opts Options) ([]string, error) {
 	return listTmux(opts, "list-sessions", "-F", "#{session_name}")
 }
 
+// SessionPanePIDs returns the PID of each pane's initial process in a
+// session, or nil when the session does not exist.
+func SessionPanePIDs(sessionName string, opts Options) ([]int, error) {
+	return panePIDs(sessionName, opts)
+}
+
 // KillSessionsWithPrefix kills all sessions with a matching name prefix.
 func KillSessionsWithPrefix(prefix string, opts Options) error {
 	if prefix == "" {
@@ -105,6 +105,18 @@ func TestDefaultOptions(t *testing.T) {
 	}
 }
 
+func TestDefaultOptionsProfileServer(t *testing.T) {
+	t.Setenv("AMUX_TMUX_SERVER", "")
+	t.Setenv("AMUX_PROFILE_NAME", "work")
+	if got := DefaultOptions().ServerName; got != "amux-work" {
+		t.Fatalf("ServerName = %q, want amux-work", got)
+	}
+	t.Setenv("AMUX_TMUX_SERVER", "custom")
+	if got := DefaultOptions().ServerName; got != "custom" {
+		t.Fatalf("ServerName = %q, want the configured server", got)
+	}
+}
+
 func TestNewClientCommand(t *testing.T) {
 	opts := Options{
 		ServerName:      "test-server",
@@ -155,7 +167,7 @@ func TestNewClientCommand(t *testing.T) {
 		t.Error("Command should include server name")
 	}
 	// Should run pane command via sh -lc
-	if !strings.Contains(cmd, "sh -lc 'unset TMUX TMUX_PANE; echo hello'") {
+	if !strings.Contains(cmd, "sh -lc 'unset TMUX TMUX_PANE; export AMUX=1; echo hello'") {
 		t.Error("Command should run pane command via sh -lc with tmux env sanitized")
 	}
 
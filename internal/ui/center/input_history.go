@@ -0,0 +1,38 @@
+package center
+
+import (
+	"time"
+
+	"github.com/andyrewlee/amux/internal/inputhistory"
+)
+
+// recordPromptInput adds the prompts that input submits in an agent tab to
+// the input history. It runs on the actor goroutine as well as in Update.
+func (m *Model) recordPromptInput(tab *Tab, data string, now time.Time) {
+	if m.inputHistory == nil || tab == nil {
+		return
+	}
+	tab.mu.Lock()
+	if !m.isChatTabLocked(tab) {
+		tab.mu.Unlock()
+		return
+	}
+	lines := tab.promptLine.Feed(data)
+	workspace := ""
+	if tab.Workspace != nil {
+		workspace = tab.Workspace.Name
+	}
+	name := tab.Name
+	tab.mu.Unlock()
+	for _, line := range lines {
+		m.inputHistory.Add(inputhistory.Entry{Text: line, Workspace: workspace, Tab: name, At: now})
+	}
+}
+
+// InputHistory returns the prompts typed into agent tabs, newest first.
+func (m *Model) InputHistory() []inputhistory.Entry {
+	if m.inputHistory == nil {
+		return nil
+	}
+	return m.inputHistory.Entries()
+}
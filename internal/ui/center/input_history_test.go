@@ -0,0 +1,30 @@
+package center
+
+import (
+	"testing"
+	"time"
+
+	"github.com/andyrewlee/amux/internal/config"
+	"github.com/andyrewlee/amux/internal/data"
+)
+
+func TestRecordPromptInputOnlyInAgentTabs(t *testing.T) {
+	m := New(&config.Config{Assistants: map[string]config.AssistantConfig{"claude": {}}})
+	ws := &data.Workspace{Name: "feature", Repo: "/repo", Root: "/repo"}
+	agent := &Tab{Name: "claude", Assistant: "claude", Workspace: ws}
+	shell := &Tab{Name: "Terminal", Workspace: ws}
+	now := time.Now()
+
+	for _, in := range []string{"add", " tests", "\r"} {
+		m.recordPromptInput(agent, in, now)
+		m.recordPromptInput(shell, in, now)
+	}
+
+	entries := m.InputHistory()
+	if len(entries) != 1 {
+		t.Fatalf("history = %+v, want only the agent tab's prompt", entries)
+	}
+	if e := entries[0]; e.Text != "add tests" || e.Workspace != "feature" || e.Tab != "claude" {
+		t.Fatalf("entry = %+v, want the prompt tagged with its workspace and tab", e)
+	}
+}
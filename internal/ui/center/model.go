@@ -8,13 +8,16 @@
 package center
 
 import (
+	"sync"
 	"sync/atomic"
 	"time"
 
 	tea "charm.land/bubbletea/v2"
 
+	"github.com/andyrewlee/amux/internal/audit"
 	"github.com/andyrewlee/amux/internal/config"
 	"github.com/andyrewlee/amux/internal/data"
+	"github.com/andyrewlee/amux/internal/inputhistory"
 	"github.com/andyrewlee/amux/internal/logging"
 	appPty "github.com/andyrewlee/amux/internal/pty"
 	"github.com/andyrewlee/amux/internal/tmux"
@@ -45,6 +48,8 @@ type Model struct {
 	msgSink               func(tea.Msg)
 	msgSinkTry            func(tea.Msg) bool
 	tabEvents             chan tabEvent
+	tabWorkersMu          sync.Mutex
+	tabWorkers            map[*Tab]*tabWorker
 	tabActorReady         uint32
 	tabActorHeartbeat     int64
 	tabActorRedrawPending uint32
@@ -69,6 +74,15 @@ type Model struct {
 	tabHits    []tabHit
 	tmuxOpts   tmux.Options
 	instanceID string
+
+	// inputHistory records prompts typed into agent tabs (input_history.go).
+	inputHistory *inputhistory.Store
+	// auditLog records the park actions sent to idle tabs (park.go).
+	auditLog *audit.Log
+
+	// Pre-rendered chrome, rebuilt only when the segment's inputs change.
+	tabBarCache common.SegmentCache[renderedTabBar]
+	helpCache   common.SegmentCache[[]string]
 }
 
 // SetInstanceID sets the tmux instance tag for sessions created by this model.
@@ -39,6 +39,8 @@ func (m *Model) noteLocalInput(tab *Tab, workspaceID, data string, now time.Time
 		return nil
 	}
 	recordLocalInputEchoWindow(tab, data, now)
+	m.recordPromptInput(tab, data, now)
+	tab.noteEngaged(now)
 	return m.scheduleChatCursorRefresh(tab, workspaceID, now)
 }
 
@@ -116,6 +118,9 @@ func (m *Model) Update(msg tea.Msg) (*Model, tea.Cmd) {
 	case messages.OpenFileInVim:
 		return m.updateOpenFileInVim(msg)
 
+	case messages.RunCommand:
+		return m, m.createCommandTab(msg.Command, msg.Name, msg.Workspace)
+
 	case ptyTabCreateResult:
 		return m.updatePtyTabCreateResult(msg)
 
@@ -147,6 +152,10 @@ func (m *Model) Update(msg tea.Msg) (*Model, tea.Cmd) {
 		m.clearTabActorRedrawPending()
 		return m, nil
 
+	case tabRedrawRestore:
+		m.updateTabRedrawRestore(msg)
+		return m, nil
+
 	case PTYOutput:
 		cmd := m.updatePTYOutput(msg)
 		cmds = append(cmds, cmd)
@@ -107,6 +107,14 @@ func (m *Model) forwardKeyToTerminal(msg tea.KeyPressMsg, tab *Tab) (*Model, tea
 	}
 	// Any typing returns a scrolled view to the live bottom before forwarding.
 	m.scrollToBottomOnType(tab)
+	if msg.Code == tea.KeyEnter && msg.Mod == 0 {
+		// Output printed after this line is what "copy last output" copies.
+		tab.mu.Lock()
+		if tab.Terminal != nil {
+			tab.Terminal.MarkInput()
+		}
+		tab.mu.Unlock()
+	}
 	return m.sendKeyToTerminal(msg, tab)
 }
 
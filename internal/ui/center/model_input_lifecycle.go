@@ -52,7 +52,7 @@ func (m *Model) updateLaunchAgent(msg messages.LaunchAgent) (*Model, tea.Cmd) {
 
 // updateOpenFileInVim handles messages.OpenFileInVim.
 func (m *Model) updateOpenFileInVim(msg messages.OpenFileInVim) (*Model, tea.Cmd) {
-	return m, m.createVimTab(msg.Path, msg.Workspace)
+	return m, m.createVimTab(msg.Path, msg.Line, msg.Workspace)
 }
 
 // updatePtyTabCreateResult handles ptyTabCreateResult.
@@ -226,6 +226,9 @@ func (m *Model) updateOpenDiff(msg messages.OpenDiff) (*Model, tea.Cmd) {
 	if msg.Change == nil {
 		return m, nil
 	}
+	if msg.Compare != nil {
+		return m, m.createCompareTab(msg.Workspace, msg.Compare, msg.Change.Path)
+	}
 	return m, m.createDiffTab(msg.Change, msg.Mode, msg.Workspace)
 }
 
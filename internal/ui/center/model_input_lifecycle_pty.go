@@ -32,6 +32,7 @@ func (m *Model) updatePTYOutput(msg PTYOutput) tea.Cmd {
 			AfterAppendLocked: func(appendedLen int) {
 				tab.pendingOutputBytes = len(tab.PendingOutput)
 				tab.ptyBytesReceived += uint64(appendedLen)
+				tab.observeFlowLocked()
 			},
 			SeedForTrim: func() vterm.ParserCarryState {
 				seed := vterm.ParserCarryState{}
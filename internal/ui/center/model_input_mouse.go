@@ -5,6 +5,7 @@ import (
 
 	tea "charm.land/bubbletea/v2"
 
+	"github.com/andyrewlee/amux/internal/perf"
 	"github.com/andyrewlee/amux/internal/ui/common"
 	"github.com/andyrewlee/amux/internal/ui/diff"
 	"github.com/andyrewlee/amux/internal/vterm"
@@ -233,6 +234,10 @@ func (m *Model) dispatchDiffInput(tab *Tab, msg tea.Msg) (bool, tea.Cmd) {
 	if m.isTabActorReady() && m.sendTabEvent(ev) {
 		return true, nil
 	}
+	if tab.workerBusy() {
+		perf.Count("tab_event_drop_worker_busy", 1)
+		return true, nil
+	}
 	return true, m.updateDiffViewer(tab, msg)
 }
 
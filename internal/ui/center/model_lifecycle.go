@@ -5,6 +5,7 @@ import (
 
 	"github.com/andyrewlee/amux/internal/config"
 	"github.com/andyrewlee/amux/internal/data"
+	"github.com/andyrewlee/amux/internal/inputhistory"
 	appPty "github.com/andyrewlee/amux/internal/pty"
 	"github.com/andyrewlee/amux/internal/tmux"
 	"github.com/andyrewlee/amux/internal/ui/common"
@@ -19,6 +20,7 @@ func New(cfg *config.Config) *Model {
 		styles:       common.DefaultStyles(),
 		tabEvents:    make(chan tabEvent, 4096),
 		tmuxOpts:     tmux.DefaultOptions(),
+		inputHistory: &inputhistory.Store{},
 	}
 }
 
@@ -82,6 +84,8 @@ func (m *Model) SetShowKeymapHints(show bool) {
 func (m *Model) SetStyles(styles common.Styles) {
 	m.styles = styles
 	m.markHelpDirty()
+	m.tabBarCache.Invalidate()
+	m.helpCache.Invalidate()
 	// Propagate to all viewers in tabs
 	for _, tabs := range m.tabs.ByWorkspace {
 		for _, tab := range tabs {
@@ -212,6 +216,8 @@ func (m *Model) Close() {
 			tab.Running = false
 			tab.mu.Unlock()
 			tab.markClosed()
+			m.stopTabWorker(tab)
+			removePasteFiles(tab)
 		}
 	}
 	if m.agentManager != nil {
@@ -0,0 +1,34 @@
+package center
+
+import (
+	"github.com/andyrewlee/amux/internal/logging"
+	"github.com/andyrewlee/amux/internal/perf"
+)
+
+// observeFlowLocked reports the tab's unapplied output (received but not yet
+// written to the terminal) to its flow gate, pausing or resuming the PTY
+// reader at the configured thresholds. Caller holds t.mu.
+func (t *Tab) observeFlowLocked() {
+	if t == nil {
+		return
+	}
+	backlog := 0
+	if t.ptyBytesReceived > t.ptyBytesSettled {
+		backlog = int(t.ptyBytesReceived - t.ptyBytesSettled)
+	}
+	if !t.Flow.Observe(backlog) {
+		return
+	}
+	if t.Flow.Paused() {
+		perf.Count("pty_flow_pause", 1)
+		logging.Info("PTY output throttled for tab %s: %d bytes buffered", t.ID, backlog)
+	} else {
+		perf.Count("pty_flow_resume", 1)
+		logging.Info("PTY output resumed for tab %s", t.ID)
+	}
+}
+
+// outputThrottled reports whether the tab's reader is paused by flow control.
+func (t *Tab) outputThrottled() bool {
+	return t != nil && t.Flow.Paused()
+}
@@ -0,0 +1,39 @@
+package center
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/andyrewlee/amux/internal/ui/ptyio"
+)
+
+func TestTabFlowPausesOnBacklogAndShowsBadge(t *testing.T) {
+	prev := ptyio.CurrentFlowThresholds()
+	ptyio.SetFlowThresholds(ptyio.FlowThresholds{PauseBytes: 1000, ResumeBytes: 200})
+	t.Cleanup(func() { ptyio.SetFlowThresholds(prev) })
+
+	ws := newTestWorkspace("ws", "/repo/ws")
+	tab := chatTab(ws, "tab-0")
+	m, _, _ := newActionsModel(t, tab)
+
+	tab.mu.Lock()
+	tab.ptyBytesReceived = 1500
+	tab.observeFlowLocked()
+	tab.mu.Unlock()
+	if !tab.outputThrottled() {
+		t.Fatal("expected backlog past the pause threshold to throttle the tab")
+	}
+	if bar := m.renderTabBar(); !strings.Contains(bar, throttledBadge) {
+		t.Fatalf("tab bar %q missing throttled badge", bar)
+	}
+
+	tab.mu.Lock()
+	tab.settlePTYBytesLocked(1300)
+	tab.mu.Unlock()
+	if tab.outputThrottled() {
+		t.Fatal("expected draining to the resume threshold to unthrottle the tab")
+	}
+	if bar := m.renderTabBar(); strings.Contains(bar, throttledBadge) {
+		t.Fatalf("tab bar %q still shows throttled badge", bar)
+	}
+}
@@ -20,7 +20,7 @@ func (m *Model) startPTYReader(wtID string, tab *Tab) tea.Cmd {
 		return nil
 	}
 	tabID := tab.ID
-	tab.State.StartReader(&tab.mu, ptyio.StartReaderOptionsFor(
+	opts := ptyio.StartReaderOptionsFor(
 		ptyio.ReaderNamespace{
 			LabelPrefix:     "center",
 			ReadQueueSize:   ptyReadQueueSize,
@@ -37,7 +37,9 @@ func (m *Model) startPTYReader(wtID string, tab *Tab) tea.Cmd {
 			Stopped: func(err error) tea.Msg { return PTYStopped{WorkspaceID: wtID, TabID: tabID, Err: err} },
 		},
 		m.forwardPTYMsgs,
-	))
+	)
+	opts.Config.Gate = &tab.Flow
+	tab.State.StartReader(&tab.mu, opts)
 	return nil
 }
 
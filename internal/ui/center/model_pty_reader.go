@@ -9,8 +9,8 @@ import (
 )
 
 func (m *Model) flushTiming(tab *Tab, active bool) (time.Duration, time.Duration) {
-	quiet := ptyFlushQuiet
-	maxInterval := ptyFlushMaxInterval
+	quiet := ptyio.ScaleFlushInterval(ptyFlushQuiet)
+	maxInterval := ptyio.ScaleFlushInterval(ptyFlushMaxInterval)
 
 	// Snapshot terminal state under lock, then release before the load-sampling call.
 	tab.mu.Lock()
@@ -28,8 +28,8 @@ func (m *Model) flushTiming(tab *Tab, active bool) (time.Duration, time.Duration
 	// SyncActive (DEC 2026) already handles partial updates via screen snapshots,
 	// so we don't need slower flush timing - it just makes streaming text feel laggy.
 	if altScreen {
-		quiet = ptyFlushQuietAlt
-		maxInterval = ptyFlushMaxAlt
+		quiet = ptyio.ScaleFlushInterval(ptyFlushQuietAlt)
+		maxInterval = ptyio.ScaleFlushInterval(ptyFlushMaxAlt)
 	}
 
 	// Apply backpressure when pending output exceeds threshold
@@ -9,6 +9,7 @@ import (
 	"github.com/andyrewlee/amux/internal/perf"
 	"github.com/andyrewlee/amux/internal/ui/common"
 	"github.com/andyrewlee/amux/internal/ui/compositor"
+	"github.com/andyrewlee/amux/internal/ui/theme"
 	"github.com/andyrewlee/amux/internal/vterm"
 )
 
@@ -60,7 +61,7 @@ func (m *Model) View() string {
 					vterm.Color{Type: vterm.ColorDefault},
 				))
 			} else {
-				b.WriteString(tab.Terminal.Render())
+				_, _ = tab.Terminal.RenderTo(&b)
 			}
 
 			if status := m.terminalStatusLineLocked(tab); status != "" {
@@ -160,11 +161,20 @@ func (m *Model) helpItem(key, desc string) string {
 	return common.RenderHelpItem(m.styles, key, desc)
 }
 
+// helpLines returns the wrapped help bar, memoized on the inputs that shape it.
 func (m *Model) helpLines(contentWidth int) []string {
+	hasWorkspace := m.workspace != nil
+	hasTabs := len(m.getTabs()) > 0
+	key := fmt.Sprintf("%s\x00%d\x00%t\x00%t", common.GetCurrentTheme().ID, contentWidth, hasWorkspace, hasTabs)
+	return m.helpCache.Get(key, func() []string {
+		return m.buildHelpLines(contentWidth, hasWorkspace, hasTabs)
+	})
+}
+
+func (m *Model) buildHelpLines(contentWidth int, hasWorkspace, hasTabs bool) []string {
 	items := []string{}
 
-	hasTabs := len(m.getTabs()) > 0
-	if m.workspace != nil {
+	if hasWorkspace {
 		items = append(
 			items,
 			m.helpItem("C-Spc t a", "new agent tab"),
@@ -297,10 +307,7 @@ func (m *Model) terminalStatusLineLocked(tab *Tab) string {
 	}
 	if tab.Terminal.IsScrolled() {
 		offset, total := m.displayedScrollInfoLocked(tab)
-		scrollStyle := lipgloss.NewStyle().
-			Bold(true).
-			Foreground(common.ColorBackground()).
-			Background(common.ColorInfo())
+		scrollStyle := theme.CachedStyle(common.ColorBackground(), common.ColorInfo(), true)
 		return scrollStyle.Render(" SCROLL: " + formatScrollPos(offset, total) + " ")
 	}
 	if tab.Running && !tab.Detached {
@@ -312,15 +319,13 @@ func (m *Model) terminalStatusLineLocked(tab *Tab) string {
 	} else if !tab.Running {
 		status = " STOPPED "
 	}
-	statusStyle := lipgloss.NewStyle().
-		Bold(true).
-		Foreground(common.ColorBackground()).
-		Background(common.ColorInfo())
+	statusBg := common.ColorInfo()
 	if tab.Detached {
-		statusStyle = statusStyle.Background(common.ColorWarning())
+		statusBg = common.ColorWarning()
 	} else if !tab.Running {
-		statusStyle = statusStyle.Background(common.ColorError())
+		statusBg = common.ColorError()
 	}
+	statusStyle := theme.CachedStyle(common.ColorBackground(), statusBg, true)
 	return statusStyle.Render(status)
 }
 
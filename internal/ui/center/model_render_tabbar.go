@@ -1,20 +1,106 @@
 package center
 
 import (
+	"strconv"
+	"strings"
+
 	tea "charm.land/bubbletea/v2"
 	"charm.land/lipgloss/v2"
 
 	"github.com/andyrewlee/amux/internal/messages"
 	"github.com/andyrewlee/amux/internal/ui/common"
+	"github.com/andyrewlee/amux/internal/ui/theme"
 )
 
-// renderTabBar renders the tab bar with activity indicators
+// throttledBadge marks a tab whose PTY reader is paused by flow control.
+const throttledBadge = "⏸ throttled"
+
+// tabBarEntry is the per-tab state the tab bar renders from. Gathering it
+// once up front lets renderTabBar key the cached bar without re-locking tabs.
+type tabBarEntry struct {
+	name         string
+	assistant    string
+	isChat       bool
+	disconnected bool
+	working      bool
+	throttled    bool
+	parked       bool
+}
+
+// renderedTabBar is a cached tab bar render plus the hit regions it produced.
+type renderedTabBar struct {
+	view string
+	hits []tabHit
+}
+
+// renderTabBar renders the tab bar with activity indicators. The render is
+// memoized on the tab bar's inputs, so frames where no tab changed name,
+// state, or activity reuse the previous string and hit regions.
 func (m *Model) renderTabBar() string {
-	m.tabHits = m.tabHits[:0]
 	currentTabs := m.getTabs()
 	activeIdx := m.getActiveTabIdx()
+	entries := make([]tabBarEntry, len(currentTabs))
+	for i, tab := range currentTabs {
+		name := tab.Name
+		if name == "" {
+			name = tab.Assistant
+		}
+		// Check if tab is disconnected (detached or stopped)
+		tab.mu.Lock()
+		tabDisconnected := tab.Detached || !tab.Running
+		tab.mu.Unlock()
+		entry := tabBarEntry{
+			name:         name,
+			assistant:    tab.Assistant,
+			isChat:       m.isChatTab(tab),
+			disconnected: tabDisconnected,
+			throttled:    tab.outputThrottled(),
+			parked:       tab.isParked(),
+		}
+		if entry.isChat {
+			entry.working = m.IsTabActive(tab)
+		}
+		entries[i] = entry
+	}
+
+	bar := m.tabBarCache.Get(tabBarKey(entries, activeIdx), func() renderedTabBar {
+		view := m.buildTabBar(entries, activeIdx)
+		return renderedTabBar{view: view, hits: append([]tabHit(nil), m.tabHits...)}
+	})
+	m.tabHits = append(m.tabHits[:0], bar.hits...)
+	return bar.view
+}
+
+// tabBarKey encodes every input buildTabBar reads, including the theme that
+// its colors and styles derive from.
+func tabBarKey(entries []tabBarEntry, activeIdx int) string {
+	var b strings.Builder
+	b.WriteString(string(common.GetCurrentTheme().ID))
+	b.WriteByte(0)
+	b.WriteString(strconv.Itoa(activeIdx))
+	for _, e := range entries {
+		b.WriteByte(0)
+		b.WriteString(e.name)
+		b.WriteByte(0)
+		b.WriteString(e.assistant)
+		b.WriteByte(0)
+		for _, flag := range [...]bool{e.isChat, e.disconnected, e.working, e.throttled, e.parked} {
+			if flag {
+				b.WriteByte('1')
+			} else {
+				b.WriteByte('0')
+			}
+		}
+	}
+	return b.String()
+}
 
-	if len(currentTabs) == 0 {
+// buildTabBar renders the tab bar from entries, recording hit regions into
+// m.tabHits.
+func (m *Model) buildTabBar(entries []tabBarEntry, activeIdx int) string {
+	m.tabHits = m.tabHits[:0]
+
+	if len(entries) == 0 {
 		empty := m.styles.TabPlus.Render("New agent")
 		emptyWidth := lipgloss.Width(empty)
 		if emptyWidth > 0 {
@@ -35,31 +121,31 @@ func (m *Model) renderTabBar() string {
 	var renderedTabs []string
 	x := 0
 
-	for i, tab := range currentTabs {
-		name := tab.Name
-		if name == "" {
-			name = tab.Assistant
-		}
-
-		// Check if tab is disconnected (detached or stopped)
-		tab.mu.Lock()
-		tabDisconnected := tab.Detached || !tab.Running
-		tab.mu.Unlock()
+	for i, entry := range entries {
+		name := entry.name
+		tabDisconnected := entry.disconnected
 
 		// Add brand color indicator for agent tabs (not file viewers)
 		var indicator string
-		var tabActive bool
-		isChat := m.isChatTab(tab)
-		if isChat {
+		tabActive := entry.working
+		if entry.isChat {
 			if tabDisconnected {
 				indicator = common.Icons.Idle + " " // Disconnected indicator
 			} else {
 				indicator = common.Icons.Running + " " // Brand color dot
 			}
-			tabActive = m.IsTabActive(tab)
 		}
 
-		agentStyle := lipgloss.NewStyle().Foreground(common.AgentColor(tab.Assistant))
+		agentStyle := theme.CachedStyle(common.AgentColor(entry.assistant), nil, false)
+
+		// Flow control paused this tab's reader; say so next to its name.
+		var badge string
+		if entry.throttled {
+			badge = " " + throttledBadge
+		}
+		if entry.parked {
+			badge += " " + parkedBadge
+		}
 
 		// Build tab content with close affordance
 		closeLabel := m.styles.Muted.Render("×")
@@ -68,23 +154,26 @@ func (m *Model) renderTabBar() string {
 		if i == activeIdx {
 			// Active tab - each part styled with same background
 			bg := common.ColorSurface2()
-			pad := lipgloss.NewStyle().Background(bg).Render(" ")
+			pad := theme.CachedStyle(nil, bg, false).Render(" ")
 			// Use muted color for disconnected tabs
 			indicatorFg := agentStyle.GetForeground()
 			if tabDisconnected {
 				indicatorFg = common.ColorMuted()
 			}
-			indicatorPart := lipgloss.NewStyle().Foreground(indicatorFg).Background(bg).Render(indicator)
+			indicatorPart := theme.CachedStyle(indicatorFg, bg, false).Render(indicator)
 			// Use primary color and bold when actively working, muted when disconnected
-			nameStyle := lipgloss.NewStyle().Foreground(common.ColorForeground()).Background(bg)
+			nameStyle := theme.CachedStyle(common.ColorForeground(), bg, false)
 			if tabDisconnected {
-				nameStyle = nameStyle.Foreground(common.ColorMuted())
+				nameStyle = theme.CachedStyle(common.ColorMuted(), bg, false)
 			} else if tabActive {
-				nameStyle = nameStyle.Foreground(common.ColorPrimary()).Bold(true)
+				nameStyle = theme.CachedStyle(common.ColorPrimary(), bg, true)
 			}
 			namePart := nameStyle.Render(name)
-			space := lipgloss.NewStyle().Background(bg).Render(" ")
-			closePart := lipgloss.NewStyle().Foreground(common.ColorMuted()).Background(bg).Render("×")
+			if badge != "" {
+				namePart += theme.CachedStyle(common.ColorWarning(), bg, false).Render(badge)
+			}
+			space := pad
+			closePart := theme.CachedStyle(common.ColorMuted(), bg, false).Render("×")
 			rendered = pad + indicatorPart + namePart + space + closePart + pad
 			style = m.styles.ActiveTab
 		} else {
@@ -93,7 +182,7 @@ func (m *Model) renderTabBar() string {
 			if tabDisconnected {
 				nameStyled = m.styles.Muted.Render(name)
 			} else if tabActive {
-				nameStyled = lipgloss.NewStyle().Foreground(common.ColorPrimary()).Bold(true).Render(name)
+				nameStyled = theme.CachedStyle(common.ColorPrimary(), nil, true).Render(name)
 			} else {
 				nameStyled = m.styles.Muted.Render(name)
 			}
@@ -104,6 +193,9 @@ func (m *Model) renderTabBar() string {
 			} else {
 				indicatorStyled = agentStyle.Render(indicator)
 			}
+			if badge != "" {
+				nameStyled += theme.CachedStyle(common.ColorWarning(), nil, false).Render(badge)
+			}
 			content := indicatorStyled + nameStyled + " " + closeLabel
 			rendered = m.styles.Tab.Render(content)
 			style = m.styles.Tab
@@ -123,7 +215,7 @@ func (m *Model) renderTabBar() string {
 
 			frameX, _ := style.GetFrameSize()
 			leftFrame := frameX / 2
-			prefixWidth := lipgloss.Width(agentStyle.Render(indicator) + name + " ")
+			prefixWidth := lipgloss.Width(agentStyle.Render(indicator) + name + badge + " ")
 			closeWidth := lipgloss.Width(closeLabel)
 			closeX := x + leftFrame + prefixWidth
 			if closeWidth > 0 {
@@ -0,0 +1,53 @@
+package center
+
+import (
+	"testing"
+
+	"github.com/andyrewlee/amux/internal/ui/common"
+)
+
+func TestRenderTabBarReusesCachedSegment(t *testing.T) {
+	ws := newTestWorkspace("ws", "/repo/ws")
+	tab := chatTab(ws, "tab-0")
+	m, _, _ := newActionsModel(t, tab, chatTab(ws, "tab-1"))
+
+	first := m.renderTabBar()
+	hits := len(m.tabHits)
+	if hits == 0 {
+		t.Fatal("expected tab hit regions")
+	}
+	if again := m.renderTabBar(); again != first || len(m.tabHits) != hits {
+		t.Fatalf("unchanged tab bar differed on reuse (hits %d, want %d)", len(m.tabHits), hits)
+	}
+	if builds := m.tabBarCache.Builds(); builds != 1 {
+		t.Fatalf("tab bar builds = %d after two unchanged renders, want 1", builds)
+	}
+
+	tab.mu.Lock()
+	tab.Running = false
+	tab.mu.Unlock()
+	if m.renderTabBar() == first {
+		t.Fatal("expected a stopped tab to change the tab bar")
+	}
+
+	m.SetStyles(common.DefaultStyles())
+	m.renderTabBar()
+	if builds := m.tabBarCache.Builds(); builds != 3 {
+		t.Fatalf("tab bar builds = %d after state and style changes, want 3", builds)
+	}
+}
+
+func TestHelpLinesRebuildOnlyOnInputChange(t *testing.T) {
+	ws := newTestWorkspace("ws", "/repo/ws")
+	m, _, _ := newActionsModel(t, chatTab(ws, "tab-0"))
+
+	m.helpLines(80)
+	m.helpLines(80)
+	if builds := m.helpCache.Builds(); builds != 1 {
+		t.Fatalf("help builds = %d for the same width, want 1", builds)
+	}
+	m.helpLines(40)
+	if builds := m.helpCache.Builds(); builds != 2 {
+		t.Fatalf("help builds = %d after a width change, want 2", builds)
+	}
+}
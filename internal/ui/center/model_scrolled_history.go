@@ -227,53 +227,9 @@ func rebaseScrolledChatSelection(term *vterm.VTerm, snap *compositor.VTermSnapsh
 		return
 	}
 
-	startAbs := term.SelStartLine()
-	endAbs := term.SelEndLine()
-	startX := term.SelStartX()
-	endX := term.SelEndX()
-
-	if startAbs > endAbs || (startAbs == endAbs && startX > endX) {
-		startAbs, endAbs = endAbs, startAbs
-		startX, endX = endX, startX
-	}
-
 	visibleEnd := startLine + height - 1
 	if _, endLine, ok := scrolledChatHistoryVisibleRange(term, height); ok {
 		visibleEnd = endLine
 	}
-
-	if endAbs < startLine || startAbs > visibleEnd {
-		snap.SelActive = false
-		return
-	}
-
-	if startAbs < startLine {
-		snap.SelStartY = 0
-		startX = 0
-	} else {
-		snap.SelStartY = startAbs - startLine
-	}
-
-	if endAbs > visibleEnd {
-		snap.SelEndY = visibleEnd - startLine
-		endX = width - 1
-	} else {
-		snap.SelEndY = endAbs - startLine
-	}
-
-	if startX < 0 {
-		startX = 0
-	}
-	if startX >= width {
-		startX = width - 1
-	}
-	if endX < 0 {
-		endX = 0
-	}
-	if endX >= width {
-		endX = width - 1
-	}
-
-	snap.SelStartX = startX
-	snap.SelEndX = endX
+	snap.SetSelection(term.Selection().Clip(startLine, visibleEnd-startLine+1, width))
 }
@@ -10,6 +10,7 @@ import (
 	"time"
 
 	"github.com/andyrewlee/amux/internal/data"
+	"github.com/andyrewlee/amux/internal/inputhistory"
 	appPty "github.com/andyrewlee/amux/internal/pty"
 	"github.com/andyrewlee/amux/internal/ui/common"
 	"github.com/andyrewlee/amux/internal/ui/diff"
@@ -60,7 +61,10 @@ type Tab struct {
 	mu               sync.Mutex   // Protects Terminal, Agent, Running, Detached, Workspace, DiffViewer and the embedded state groups
 	closed           uint32
 	closing          uint32
-	Running          bool // Whether the agent is actively running
+	// workerInFlight counts events accepted by sendTabEvent and not yet
+	// applied by the tab's worker (see tab_actor_workers.go).
+	workerInFlight int32
+	Running        bool // Whether the agent is actively running
 
 	// ptyio.State holds the shared PTY buffering/reader/restart/snapshot
 	// bookkeeping (locking owned by mu, as documented on the type).
@@ -92,6 +96,16 @@ type Tab struct {
 	lastFocusedAt  time.Time
 
 	createdAt int64 // Unix timestamp for ordering; persisted in workspace.json
+
+	// promptLine rebuilds the prompt being typed for the input history.
+	promptLine inputhistory.Line
+	// engagedAt is when the user last gave the tab input, and parked is set
+	// once the agent sat idle past its park timeout (park.go).
+	engagedAt time.Time
+	parked    bool
+	// pasteFiles are temp files whose paths were pasted into the tab; they
+	// are removed when it closes (paste_files.go).
+	pasteFiles []string
 }
 
 // tabActivityState groups chat-activity detection state: visible-output
@@ -235,6 +249,7 @@ func (t *Tab) resetPTYStateLocked() {
 	t.ptyBytesSettled = 0
 	t.NoiseTrailing = nil
 	t.actorQueuedBytes = 0
+	t.Flow.Release()
 }
 
 func (t *Tab) clearCatchUpLocked() {
@@ -311,6 +326,7 @@ func (t *Tab) settlePTYBytesLocked(n int) (before, after bool) {
 	}
 	t.expireCatchUpLocked()
 	after = t.catchUpActiveLocked()
+	t.observeFlowLocked()
 	return before, after
 }
 
@@ -450,6 +466,8 @@ func (m *Model) CleanupWorkspace(ws *data.Workspace) {
 		tab.Running = false
 		tab.mu.Unlock()
 		tab.markClosed()
+		m.stopTabWorker(tab)
+		removePasteFiles(tab)
 	}
 
 	m.tabs.DeleteWorkspace(wsID)
@@ -33,6 +33,13 @@ func (m *Model) closeTabAt(index int) tea.Cmd {
 	// Capture session info before cleanup for async kill
 	sessionName := tab.SessionName
 	tmuxOpts := m.tmuxOpts
+	assistant := tab.Assistant
+	wsID := ""
+	tab.mu.Lock()
+	if tab.Workspace != nil {
+		wsID = string(tab.Workspace.ID())
+	}
+	tab.mu.Unlock()
 
 	m.stopPTYReader(tab)
 
@@ -59,6 +66,8 @@ func (m *Model) closeTabAt(index int) tea.Cmd {
 	tab.resetPTYStateLocked()
 	tab.mu.Unlock()
 	tab.markClosed()
+	m.stopTabWorker(tab)
+	removePasteFiles(tab)
 
 	// Remove from tabs
 	m.removeTab(index)
@@ -75,7 +84,7 @@ func (m *Model) closeTabAt(index int) tea.Cmd {
 	}
 
 	closedCmd := func() tea.Msg {
-		return messages.TabClosed{Index: index}
+		return messages.TabClosed{Index: index, WorkspaceID: wsID, Assistant: assistant}
 	}
 
 	// Kill tmux session asynchronously to avoid blocking the UI
@@ -201,6 +210,59 @@ func (m *Model) SendToTerminal(s string) {
 	}
 }
 
+// ActivePasteSink returns a writer bound to the active tab's PTY, for pastes
+// written in chunks off the UI goroutine. It keeps targeting that tab if the
+// user switches tabs mid-paste, and reports false once the tab is closed or a
+// write fails. Nil when there is no active tab.
+func (m *Model) ActivePasteSink() func(string) bool {
+	tabs := m.getTabs()
+	activeIdx := m.getActiveTabIdx()
+	if len(tabs) == 0 || activeIdx >= len(tabs) {
+		return nil
+	}
+	tab := tabs[activeIdx]
+	if tab.isClosed() {
+		return nil
+	}
+	return func(s string) bool {
+		if tab.isClosed() {
+			return false
+		}
+		tab.mu.Lock()
+		agent := tab.Agent
+		tab.mu.Unlock()
+		if agent == nil || agent.Terminal == nil {
+			return false
+		}
+		m.tracePTYInput(tab, []byte(s))
+		if err := agent.Terminal.SendString(s); err != nil {
+			logging.Warn("Paste failed for tab %s: %v", tab.ID, err)
+			tab.mu.Lock()
+			tab.markDetachedLocked()
+			tab.mu.Unlock()
+			return false
+		}
+		return true
+	}
+}
+
+// ActiveSessionName returns the tmux session of the active tab, or "" when
+// there is no active tab.
+func (m *Model) ActiveSessionName() string {
+	tabs := m.getTabs()
+	activeIdx := m.getActiveTabIdx()
+	if len(tabs) == 0 || activeIdx >= len(tabs) {
+		return ""
+	}
+	tab := tabs[activeIdx]
+	tab.mu.Lock()
+	defer tab.mu.Unlock()
+	if tab.Agent != nil && tab.Agent.Session != "" {
+		return tab.Agent.Session
+	}
+	return tab.SessionName
+}
+
 // ScrollActiveTerminalPage scrolls the active terminal by one page-sized step.
 // A positive direction scrolls up into history; a negative direction scrolls
 // down toward live output.
@@ -0,0 +1,18 @@
+package center
+
+// WorkspaceTabCount returns how many tabs workspace wsID has and which one is
+// active, for cycling across workspaces without switching to them.
+func (m *Model) WorkspaceTabCount(wsID string) (count, active int) {
+	return len(m.tabs.Tabs(wsID)), m.tabs.ActiveIdx(wsID)
+}
+
+// SelectTabInWorkspace makes index the active tab of workspace wsID. For a
+// workspace other than the current one it takes effect when the workspace is
+// next shown (see SetWorkspace).
+func (m *Model) SelectTabInWorkspace(wsID string, index int) bool {
+	if index < 0 || index >= len(m.tabs.Tabs(wsID)) {
+		return false
+	}
+	m.setActiveTabIdxForWorkspace(wsID, index)
+	return true
+}
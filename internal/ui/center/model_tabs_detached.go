@@ -0,0 +1,31 @@
+package center
+
+import (
+	"time"
+
+	"github.com/andyrewlee/amux/internal/data"
+	appPty "github.com/andyrewlee/amux/internal/pty"
+	"github.com/andyrewlee/amux/internal/tmux"
+)
+
+// StartDetachedAgent starts an agent for ws in a session named and tagged
+// like an agent tab's, but with no tab or terminal: the agent is given
+// prompt as its first message and runs on its own until ws is opened, when
+// tmux discovery adopts the session as a tab. It blocks while tmux starts
+// the session, so call it off the UI goroutine. It returns the session name.
+func (m *Model) StartDetachedAgent(ws *data.Workspace, assistant, prompt string) (string, error) {
+	tabID := generateTabID()
+	sessionName := tmux.SessionName("amux", string(ws.ID()), string(tabID))
+	tags := tmux.SessionTags{
+		WorkspaceID: string(ws.ID()),
+		TabID:       string(tabID),
+		Type:        "agent",
+		Assistant:   assistant,
+		CreatedAt:   time.Now().Unix(),
+		InstanceID:  m.instanceID,
+	}
+	if err := m.agentManager.StartDetachedAgent(ws, appPty.AgentType(assistant), sessionName, prompt, tags); err != nil {
+		return "", err
+	}
+	return sessionName, nil
+}
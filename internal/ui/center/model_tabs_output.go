@@ -0,0 +1,60 @@
+package center
+
+import (
+	"math"
+
+	tea "charm.land/bubbletea/v2"
+)
+
+// TerminalOutput is the recent text of one terminal tab.
+type TerminalOutput struct {
+	WorkspaceID string
+	TabID       TabID
+	TabName     string
+	// Version is the terminal's version when read; pass it back through
+	// RecentTerminalOutput's seen to skip the tab until it changes.
+	Version uint64
+	Text    string
+}
+
+// RecentTerminalOutput returns the text on screen, and up to scrollback rows
+// above it, of every terminal tab in all workspaces whose terminal changed
+// since the version recorded for it in seen.
+func (m *Model) RecentTerminalOutput(scrollback int, seen map[TabID]uint64) []TerminalOutput {
+	var out []TerminalOutput
+	for wsID, tabs := range m.tabs.ByWorkspace {
+		for _, tab := range tabs {
+			if tab == nil || tab.isClosed() {
+				continue
+			}
+			tab.mu.Lock()
+			term := tab.Terminal
+			if term == nil {
+				tab.mu.Unlock()
+				continue
+			}
+			version := term.Version()
+			if v, ok := seen[tab.ID]; ok && v == version {
+				tab.mu.Unlock()
+				continue
+			}
+			_, scrollbackLen := term.RenderBuffers()
+			start := max(scrollbackLen-scrollback, 0)
+			text := term.GetTextRange(0, start, term.Width-1, math.MaxInt)
+			tab.mu.Unlock()
+			out = append(out, TerminalOutput{WorkspaceID: wsID, TabID: tab.ID, TabName: tab.Name, Version: version, Text: text})
+		}
+	}
+	return out
+}
+
+// SelectTabByID switches to the active workspace's tab with id. ok is false
+// when the workspace has no such tab open.
+func (m *Model) SelectTabByID(id TabID) (cmd tea.Cmd, ok bool) {
+	for i, tab := range m.getTabs() {
+		if tab != nil && tab.ID == id && !tab.isClosed() {
+			return m.SelectTab(i), true
+		}
+	}
+	return nil, false
+}
@@ -0,0 +1,45 @@
+package center
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/andyrewlee/amux/internal/vterm"
+)
+
+func TestRecentTerminalOutput(t *testing.T) {
+	ws := newTestWorkspace("ws", "/repo/ws")
+	first, second := chatTab(ws, "tab-0"), chatTab(ws, "tab-1")
+	first.Terminal = vterm.New(20, 3)
+	first.Terminal.Write([]byte("one\r\ntwo\r\nthree\r\nfour"))
+	second.Terminal = vterm.New(20, 3)
+	m, _, wsID := newActionsModel(t, first, second)
+
+	out := m.RecentTerminalOutput(0, nil)
+	if len(out) != 2 {
+		t.Fatalf("RecentTerminalOutput() = %+v, want both tabs", out)
+	}
+	seen := map[TabID]uint64{}
+	for _, o := range out {
+		if o.WorkspaceID != wsID {
+			t.Fatalf("output %+v has the wrong workspace", o)
+		}
+		if o.TabID == first.ID && strings.TrimSpace(o.Text) != "two\nthree\nfour" {
+			t.Fatalf("text = %q, want the screen", o.Text)
+		}
+		seen[o.TabID] = o.Version
+	}
+
+	second.Terminal.Write([]byte("panic: boom"))
+	out = m.RecentTerminalOutput(0, seen)
+	if len(out) != 1 || out[0].TabID != second.ID || !strings.Contains(out[0].Text, "panic: boom") {
+		t.Fatalf("RecentTerminalOutput(seen) = %+v, want only the changed tab", out)
+	}
+
+	if _, ok := m.SelectTabByID(second.ID); !ok || m.getActiveTabIdx() != 1 {
+		t.Fatal("SelectTabByID should switch to the tab")
+	}
+	if _, ok := m.SelectTabByID("missing"); ok {
+		t.Fatal("SelectTabByID should report a missing tab")
+	}
+}
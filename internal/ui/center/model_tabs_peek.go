@@ -0,0 +1,17 @@
+package center
+
+// TogglePrimaryPeek shows or hides the primary screen (and its scrollback)
+// behind the active tab's full-screen program. ok is false when the tab is
+// not on the alternate screen, so there is nothing to peek at.
+func (m *Model) TogglePrimaryPeek() (peeking, ok bool) {
+	tab := m.activeTerminalTab()
+	if tab == nil {
+		return false, false
+	}
+	tab.mu.Lock()
+	defer tab.mu.Unlock()
+	if tab.Terminal == nil || (!tab.Terminal.AltScreen && !tab.Terminal.PeekingPrimary()) {
+		return false, false
+	}
+	return tab.Terminal.TogglePrimaryPeek(), true
+}
@@ -0,0 +1,27 @@
+package center
+
+import (
+	"testing"
+
+	"github.com/andyrewlee/amux/internal/vterm"
+)
+
+func TestTogglePrimaryPeekOnlyBehindAltScreen(t *testing.T) {
+	ws := newTestWorkspace("ws", "/repo/ws")
+	tab := chatTab(ws, "tab-0")
+	tab.Terminal = vterm.New(20, 3)
+	tab.Terminal.Write([]byte("$ htop"))
+	m, _, _ := newActionsModel(t, tab)
+
+	if _, ok := m.TogglePrimaryPeek(); ok {
+		t.Fatal("peek should be unavailable on the primary screen")
+	}
+
+	tab.Terminal.Write([]byte("\x1b[?1049h"))
+	if peeking, ok := m.TogglePrimaryPeek(); !ok || !peeking {
+		t.Fatalf("toggle = (%v,%v), want (true,true)", peeking, ok)
+	}
+	if peeking, ok := m.TogglePrimaryPeek(); !ok || peeking {
+		t.Fatalf("second toggle = (%v,%v), want (false,true)", peeking, ok)
+	}
+}
@@ -0,0 +1,80 @@
+package center
+
+import "math"
+
+// SetActiveSearch searches the active tab's scrollback for query and jumps to
+// the newest match; an empty query clears the search. ok is false when there
+// is no active terminal.
+func (m *Model) SetActiveSearch(query string) (total int, ok bool) {
+	tab := m.activeTerminalTab()
+	if tab == nil {
+		return 0, false
+	}
+	tab.mu.Lock()
+	defer tab.mu.Unlock()
+	if tab.Terminal == nil {
+		return 0, false
+	}
+	return tab.Terminal.SetSearch(query), true
+}
+
+// StepActiveSearch moves the active tab's search to the next newer (dir > 0)
+// or older (dir < 0) match and reports the new position. ok is false when
+// there is no search to step.
+func (m *Model) StepActiveSearch(dir int) (index, total int, ok bool) {
+	tab := m.activeTerminalTab()
+	if tab == nil {
+		return 0, 0, false
+	}
+	tab.mu.Lock()
+	defer tab.mu.Unlock()
+	if tab.Terminal == nil || !tab.Terminal.SearchActive() {
+		return 0, 0, false
+	}
+	tab.Terminal.StepSearch(dir)
+	index, total = tab.Terminal.SearchStatus()
+	return index, total, true
+}
+
+// ActiveSearchActive reports whether the active tab has a search query set.
+func (m *Model) ActiveSearchActive() bool {
+	tab := m.activeTerminalTab()
+	if tab == nil {
+		return false
+	}
+	tab.mu.Lock()
+	defer tab.mu.Unlock()
+	return tab.Terminal != nil && tab.Terminal.SearchActive()
+}
+
+// ActiveTerminalText returns the active tab's scrollback and screen as text,
+// with soft-wrapped rows joined. ok is false when there is no active
+// terminal.
+func (m *Model) ActiveTerminalText() (text string, ok bool) {
+	tab := m.activeTerminalTab()
+	if tab == nil {
+		return "", false
+	}
+	tab.mu.Lock()
+	defer tab.mu.Unlock()
+	if tab.Terminal == nil {
+		return "", false
+	}
+	return tab.Terminal.GetTextRange(0, 0, tab.Terminal.Width-1, math.MaxInt), true
+}
+
+// ActiveLastOutput returns what the active tab printed since its last
+// command or submitted input. ok is false when there is no active terminal
+// or nothing has been marked.
+func (m *Model) ActiveLastOutput() (text string, ok bool) {
+	tab := m.activeTerminalTab()
+	if tab == nil {
+		return "", false
+	}
+	tab.mu.Lock()
+	defer tab.mu.Unlock()
+	if tab.Terminal == nil {
+		return "", false
+	}
+	return tab.Terminal.LastOutput()
+}
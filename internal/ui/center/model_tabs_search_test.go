@@ -0,0 +1,31 @@
+package center
+
+import (
+	"testing"
+
+	"github.com/andyrewlee/amux/internal/vterm"
+)
+
+func TestActiveSearchStepsThroughMatches(t *testing.T) {
+	ws := newTestWorkspace("ws", "/repo/ws")
+	tab := chatTab(ws, "tab-0")
+	tab.Terminal = vterm.New(20, 3)
+	tab.Terminal.Write([]byte("error one\r\nerror two"))
+	m, _, _ := newActionsModel(t, tab)
+
+	if _, _, ok := m.StepActiveSearch(1); ok {
+		t.Fatal("stepping without a search should report no search")
+	}
+	if total, ok := m.SetActiveSearch("error"); !ok || total != 2 {
+		t.Fatalf("SetActiveSearch = (%d,%v), want (2,true)", total, ok)
+	}
+	if !m.ActiveSearchActive() {
+		t.Fatal("expected an active search")
+	}
+	if index, total, ok := m.StepActiveSearch(-1); !ok || index != 1 || total != 2 {
+		t.Fatalf("StepActiveSearch(-1) = (%d,%d,%v), want (1,2,true)", index, total, ok)
+	}
+	if _, ok := m.SetActiveSearch(""); !ok || m.ActiveSearchActive() {
+		t.Fatal("empty query should clear the search")
+	}
+}
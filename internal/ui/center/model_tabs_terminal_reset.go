@@ -0,0 +1,139 @@
+package center
+
+import (
+	"time"
+
+	tea "charm.land/bubbletea/v2"
+
+	"github.com/andyrewlee/amux/internal/logging"
+	appPty "github.com/andyrewlee/amux/internal/pty"
+	"github.com/andyrewlee/amux/internal/ui/common"
+)
+
+// redrawRestoreDelay is how long a redraw keeps the PTY one column narrower
+// before restoring it. tmux only forwards a resize to the pane when the client
+// size actually changes, so the two SIGWINCHs must not coalesce.
+const redrawRestoreDelay = 150 * time.Millisecond
+
+// tabRedrawRestore restores a tab's PTY size after a redraw nudge.
+type tabRedrawRestore struct {
+	WorkspaceID string
+	TabID       TabID
+}
+
+func (m *Model) activeTerminalTab() *Tab {
+	tabs := m.getTabs()
+	activeIdx := m.getActiveTabIdx()
+	if len(tabs) == 0 || activeIdx >= len(tabs) {
+		return nil
+	}
+	tab := tabs[activeIdx]
+	if tab == nil || tab.isClosed() {
+		return nil
+	}
+	return tab
+}
+
+// ClearActiveScrollback drops the active tab's local scrollback history.
+// The tmux session's own history is untouched, so a later reattach may
+// restore it.
+func (m *Model) ClearActiveScrollback() tea.Cmd {
+	tab := m.activeTerminalTab()
+	if tab == nil {
+		return nil
+	}
+	m.dispatchOrHandleTabEvent(tabEvent{
+		tab:         tab,
+		workspaceID: m.workspaceID(),
+		tabID:       tab.ID,
+		kind:        tabEventClearScrollback,
+	})
+	return nil
+}
+
+// ResetActiveTerminal hard-resets the active tab's emulator (RIS) and asks
+// the hosted program to redraw onto the clean screen.
+func (m *Model) ResetActiveTerminal() tea.Cmd {
+	tab := m.activeTerminalTab()
+	if tab == nil {
+		return nil
+	}
+	m.dispatchOrHandleTabEvent(tabEvent{
+		tab:         tab,
+		workspaceID: m.workspaceID(),
+		tabID:       tab.ID,
+		kind:        tabEventResetTerminal,
+	})
+	return m.RedrawActiveTab()
+}
+
+// RedrawActiveTab makes the hosted program repaint by briefly narrowing the
+// PTY by one column, which delivers SIGWINCH through tmux, and restoring the
+// size after redrawRestoreDelay.
+func (m *Model) RedrawActiveTab() tea.Cmd {
+	tab := m.activeTerminalTab()
+	if tab == nil {
+		return nil
+	}
+	tab.mu.Lock()
+	agent := tab.Agent
+	tab.mu.Unlock()
+	if agent == nil || agent.Terminal == nil {
+		return nil
+	}
+	rows, cols := tab.ptyRows, tab.ptyCols
+	if rows < 1 || cols < 2 {
+		return nil
+	}
+	ptyRows, ptyCols, ok := appPty.WinsizeFromInts(rows, cols-1)
+	if !ok {
+		return nil
+	}
+	if err := agent.Terminal.SetSize(ptyRows, ptyCols); err != nil {
+		logging.Warn("Redraw resize failed for tab %s: %v", tab.ID, err)
+		return nil
+	}
+	wsID := m.workspaceID()
+	tabID := tab.ID
+	return common.SafeTick(redrawRestoreDelay, func(time.Time) tea.Msg {
+		return tabRedrawRestore{WorkspaceID: wsID, TabID: tabID}
+	})
+}
+
+// updateTabRedrawRestore puts the PTY back at the tab's current size, which
+// may have changed while the redraw nudge was in flight.
+func (m *Model) updateTabRedrawRestore(msg tabRedrawRestore) {
+	tab := m.getTabByID(msg.WorkspaceID, msg.TabID)
+	if tab == nil {
+		return
+	}
+	tab.mu.Lock()
+	agent := tab.Agent
+	tab.mu.Unlock()
+	if agent == nil || agent.Terminal == nil {
+		return
+	}
+	ptyRows, ptyCols, ok := appPty.WinsizeFromInts(tab.ptyRows, tab.ptyCols)
+	if !ok {
+		return
+	}
+	_ = agent.Terminal.SetSize(ptyRows, ptyCols)
+}
+
+func (m *Model) handleClearScrollback(ev tabEvent) {
+	tab := ev.tab
+	tab.mu.Lock()
+	if tab.Terminal != nil {
+		tab.Terminal.ClearScrollback()
+	}
+	tab.mu.Unlock()
+}
+
+func (m *Model) handleResetTerminal(ev tabEvent) {
+	tab := ev.tab
+	tab.mu.Lock()
+	if tab.Terminal != nil {
+		tab.Terminal.Reset()
+	}
+	tab.mu.Unlock()
+}
@@ -0,0 +1,64 @@
+package center
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/creack/pty"
+
+	appPty "github.com/andyrewlee/amux/internal/pty"
+	"github.com/andyrewlee/amux/internal/vterm"
+)
+
+func TestClearAndResetActiveTerminal(t *testing.T) {
+	ws := newTestWorkspace("ws", "/repo/ws")
+	tab := chatTab(ws, "tab-0")
+	tab.Terminal = vterm.New(20, 3)
+	for i := 0; i < 10; i++ {
+		tab.Terminal.Write([]byte(fmt.Sprintf("line-%d\r\n", i)))
+	}
+	tab.Terminal.Write([]byte("\x1b[?1049h"))
+	m, _, _ := newActionsModel(t, tab)
+
+	m.ClearActiveScrollback()
+	if n := len(tab.Terminal.Scrollback); n != 0 {
+		t.Fatalf("scrollback len = %d after clear, want 0", n)
+	}
+	if !tab.Terminal.AltScreen {
+		t.Fatal("clearing scrollback must not touch the screen state")
+	}
+
+	if cmd := m.ResetActiveTerminal(); cmd != nil {
+		t.Fatal("a tab without a live PTY has nothing to redraw")
+	}
+	if tab.Terminal.AltScreen {
+		t.Fatal("expected reset to leave the alt screen")
+	}
+}
+
+func TestRedrawActiveTabNudgesAndRestoresPTYSize(t *testing.T) {
+	term, err := appPty.NewWithSize("cat >/dev/null", t.TempDir(), nil, 24, 80)
+	if err != nil {
+		t.Fatalf("expected test PTY terminal: %v", err)
+	}
+	defer func() { _ = term.Close() }()
+
+	ws := newTestWorkspace("ws", "/repo/ws")
+	tab := chatTab(ws, "tab-0")
+	tab.Agent = &appPty.Agent{Terminal: term}
+	tab.ptyRows, tab.ptyCols = 24, 80
+	m, _, wsID := newActionsModel(t, tab)
+
+	if cmd := m.RedrawActiveTab(); cmd == nil {
+		t.Fatal("expected a restore tick after the redraw nudge")
+	}
+	if _, cols, err := pty.Getsize(term.File()); err != nil || cols != 79 {
+		t.Fatalf("cols during redraw = %d (%v), want 79", cols, err)
+	}
+
+	m.updateTabRedrawRestore(tabRedrawRestore{WorkspaceID: wsID, TabID: tab.ID})
+	rows, cols, err := pty.Getsize(term.File())
+	if err != nil || rows != 24 || cols != 80 {
+		t.Fatalf("size after restore = %dx%d (%v), want 24x80", rows, cols, err)
+	}
+}
@@ -2,6 +2,7 @@ package center
 
 import (
 	"errors"
+	"fmt"
 	"strings"
 	"time"
 
@@ -17,11 +18,39 @@ import (
 	"github.com/andyrewlee/amux/internal/ui/diff"
 )
 
-// createVimTab creates a new tab that opens a file in vim
-func (m *Model) createVimTab(filePath string, ws *data.Workspace) tea.Cmd {
+// createVimTab creates a new tab that opens a file in vim, at line when it is
+// positive.
+func (m *Model) createVimTab(filePath string, line int, ws *data.Workspace) tea.Cmd {
+	escapedFile := "'" + strings.ReplaceAll(filePath, "'", "'\\''") + "'"
+	fileName := filePath
+	if idx := strings.LastIndex(filePath, "/"); idx >= 0 {
+		fileName = fileName[idx+1:]
+	}
+	cmd := "vim -- " + escapedFile
+	if line > 0 {
+		cmd = fmt.Sprintf("vim +%d -- %s", line, escapedFile)
+	}
+	return m.createViewerTab(ws, cmd, "vim", fileName, "creating vim viewer")
+}
+
+// createCommandTab creates a new tab running a shell command, named name or
+// else after the command's first word.
+func (m *Model) createCommandTab(command, name string, ws *data.Workspace) tea.Cmd {
+	if name == "" {
+		name = command
+		if fields := strings.Fields(command); len(fields) > 0 {
+			name = fields[0]
+		}
+	}
+	return m.createViewerTab(ws, command, "command", name, "running command")
+}
+
+// createViewerTab creates a non-agent tab running cmd in ws. assistant labels
+// the tab kind and context prefixes errors.
+func (m *Model) createViewerTab(ws *data.Workspace, cmd, assistant, name, context string) tea.Cmd {
 	if ws == nil {
 		return func() tea.Msg {
-			return messages.Error{Err: errors.New("no workspace selected"), Context: "creating vim viewer"}
+			return messages.Error{Err: errors.New("no workspace selected"), Context: context}
 		}
 	}
 
@@ -32,10 +61,7 @@ func (m *Model) createVimTab(filePath string, ws *data.Workspace) tea.Cmd {
 	sessionName := tmux.SessionName("amux", string(ws.ID()), string(tabID))
 
 	return func() tea.Msg {
-		logging.Info("Creating vim tab: file=%s workspace=%s", filePath, ws.Name)
-
-		escapedFile := "'" + strings.ReplaceAll(filePath, "'", "'\\''") + "'"
-		cmd := "vim -- " + escapedFile
+		logging.Info("Creating %s tab: command=%s workspace=%s", assistant, cmd, ws.Name)
 
 		tags := tmux.SessionTags{
 			WorkspaceID:  string(ws.ID()),
@@ -50,22 +76,16 @@ func (m *Model) createVimTab(filePath string, ws *data.Workspace) tea.Cmd {
 		ptyRows, ptyCols, _ := appPty.WinsizeFromInts(termHeight, termWidth)
 		agent, err := m.agentManager.CreateViewerWithTags(ws, cmd, sessionName, ptyRows, ptyCols, tags)
 		if err != nil {
-			logging.Error("Failed to create vim viewer: %v", err)
-			return messages.Error{Err: err, Context: "creating vim viewer"}
+			logging.Error("Failed to create %s tab: %v", assistant, err)
+			return messages.Error{Err: err, Context: context}
 		}
 
-		logging.Info("Vim viewer created, Terminal=%v", agent.Terminal != nil)
-
-		fileName := filePath
-		if idx := strings.LastIndex(filePath, "/"); idx >= 0 {
-			fileName = fileName[idx+1:]
-		}
-		displayName := truncateDisplayName(fileName)
+		logging.Info("%s tab created, Terminal=%v", assistant, agent.Terminal != nil)
 
 		return ptyTabCreateResult{
 			Workspace:   ws,
-			Assistant:   "vim",
-			DisplayName: displayName,
+			Assistant:   assistant,
+			DisplayName: truncateDisplayName(name),
 			Agent:       agent,
 			TabID:       tabID,
 			Activate:    true,
@@ -136,10 +156,27 @@ func (m *Model) createDiffTab(change *git.Change, mode git.DiffMode, ws *data.Wo
 	viewerHeight := tm.Height
 
 	dv := diff.New(ws, change, mode, viewerWidth, viewerHeight)
+	return m.addDiffViewerTab(ws, dv, "Diff: "+change.Path)
+}
+
+// createCompareTab opens a diff viewer tab showing how path differs between
+// the two worktrees of cmp.
+func (m *Model) createCompareTab(ws *data.Workspace, cmp *git.Comparison, path string) tea.Cmd {
+	if ws == nil || cmp == nil {
+		return nil
+	}
+	logging.Info("Creating compare tab: path=%s comparison=%s", path, cmp.Name())
+	tm := m.terminalMetrics()
+	dv := diff.NewComparison(ws, cmp, path, tm.Width, tm.Height)
+	return m.addDiffViewerTab(ws, dv, "Compare: "+path)
+}
+
+// addDiffViewerTab adds dv to ws's tabs and selects it.
+func (m *Model) addDiffViewerTab(ws *data.Workspace, dv *diff.Model, name string) tea.Cmd {
 	dv.SetFocused(true)
 
 	wsID := string(ws.ID())
-	displayName := truncateDisplayName("Diff: " + change.Path)
+	displayName := truncateDisplayName(name)
 
 	tab := &Tab{
 		ID:            generateTabID(),
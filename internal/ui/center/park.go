@@ -0,0 +1,138 @@
+package center
+
+import (
+	"strings"
+	"time"
+
+	tea "charm.land/bubbletea/v2"
+
+	"github.com/andyrewlee/amux/internal/audit"
+	"github.com/andyrewlee/amux/internal/config"
+	"github.com/andyrewlee/amux/internal/logging"
+	"github.com/andyrewlee/amux/internal/messages"
+	appPty "github.com/andyrewlee/amux/internal/pty"
+	"github.com/andyrewlee/amux/internal/ui/common"
+)
+
+// parkedBadge marks an agent tab parked after sitting idle.
+const parkedBadge = "☾ parked"
+
+// noteEngaged restarts the tab's idle clock and unparks it.
+func (tab *Tab) noteEngaged(now time.Time) {
+	tab.mu.Lock()
+	tab.engagedAt = now
+	tab.parked = false
+	tab.mu.Unlock()
+}
+
+// isParked reports whether the tab was parked for sitting idle.
+func (tab *Tab) isParked() bool {
+	tab.mu.Lock()
+	defer tab.mu.Unlock()
+	return tab.parked
+}
+
+// ParkIdleTabs parks the running agent tabs, in any workspace, that had
+// neither input nor output for their assistant's park timeout: it sends the
+// configured park action and marks the tab parked until the user types in it
+// again.
+func (m *Model) ParkIdleTabs(now time.Time) tea.Cmd {
+	if m.config == nil {
+		return nil
+	}
+	var cmds []tea.Cmd
+	var names []string
+	for _, tabs := range m.tabs.ByWorkspace {
+		for _, tab := range tabs {
+			if tab.isClosed() {
+				continue
+			}
+			policy := m.config.Assistants[tab.Assistant].Park
+			timeout := policy.Timeout()
+			if timeout == 0 {
+				continue
+			}
+			tab.mu.Lock()
+			if tab.parked || !tab.Running || tab.Detached || !m.isChatTabLocked(tab) {
+				tab.mu.Unlock()
+				continue
+			}
+			since := tab.engagedAt
+			if tab.lastVisibleOutput.After(since) {
+				since = tab.lastVisibleOutput
+			}
+			if since.IsZero() {
+				// Nothing seen yet: start the clock now.
+				tab.engagedAt = now
+				tab.mu.Unlock()
+				continue
+			}
+			if now.Sub(since) < timeout {
+				tab.mu.Unlock()
+				continue
+			}
+			tab.parked = true
+			agent := tab.Agent
+			name := tab.Name
+			if tab.Workspace != nil {
+				name = tab.Workspace.Name + "/" + name
+			}
+			tab.mu.Unlock()
+			logging.Info("Parking %s after %s idle (action %s)", name, timeout, policy.Action)
+			names = append(names, name)
+			cmds = append(cmds, m.parkActionCmd(agent, policy))
+		}
+	}
+	if len(names) == 0 {
+		return nil
+	}
+	toast := messages.Toast{Message: "Parked idle agent: " + strings.Join(names, ", "), Level: messages.ToastInfo}
+	cmds = append(cmds, func() tea.Msg { return toast })
+	return common.SafeBatch(cmds...)
+}
+
+// SetAuditLog sets the log park actions are recorded in.
+func (m *Model) SetAuditLog(log *audit.Log) {
+	m.auditLog = log
+}
+
+// parkActionCmd sends policy's park action to agent and records it in the
+// audit log.
+func (m *Model) parkActionCmd(agent *appPty.Agent, policy config.ParkPolicy) tea.Cmd {
+	if agent == nil || agent.Terminal == nil {
+		return nil
+	}
+	auditLog := m.auditLog
+	switch policy.Action {
+	case config.ParkActionInterrupt:
+		if m.agentManager == nil {
+			return nil
+		}
+		return func() tea.Msg {
+			if err := m.agentManager.SendInterrupt(agent); err != nil {
+				logging.Warn("park: interrupt failed: %v", err)
+				return nil
+			}
+			recordParkAction(auditLog, agent, "\x03")
+			return nil
+		}
+	case config.ParkActionSend:
+		text := policy.Text + "\r"
+		return func() tea.Msg {
+			if err := agent.Terminal.SendString(text); err != nil {
+				logging.Warn("park: send failed: %v", err)
+				return nil
+			}
+			recordParkAction(auditLog, agent, text)
+			return nil
+		}
+	}
+	return nil
+}
+
+// recordParkAction records input sent to park agent's session.
+func recordParkAction(auditLog *audit.Log, agent *appPty.Agent, input string) {
+	if err := auditLog.Record(audit.SourcePark, "", agent.Session, input); err != nil {
+		logging.Warn("park: audit failed: %v", err)
+	}
+}
@@ -0,0 +1,83 @@
+package center
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/andyrewlee/amux/internal/audit"
+	"github.com/andyrewlee/amux/internal/config"
+	appPty "github.com/andyrewlee/amux/internal/pty"
+)
+
+func TestParkIdleTabsParksAfterTimeout(t *testing.T) {
+	m := New(&config.Config{Assistants: map[string]config.AssistantConfig{
+		"claude": {Park: config.ParkPolicy{After: "1h", Action: config.ParkActionNone}},
+		"codex":  {},
+	}})
+	ws := newTestWorkspace("ws", "/repo/ws")
+	parkable := &Tab{Name: "claude", Assistant: "claude", Workspace: ws, Running: true}
+	noPolicy := &Tab{Name: "codex", Assistant: "codex", Workspace: ws, Running: true}
+	m.AddTab(parkable)
+	m.AddTab(noPolicy)
+	now := time.Now()
+
+	if cmd := m.ParkIdleTabs(now); cmd != nil {
+		t.Fatal("first check should only start the idle clock")
+	}
+	if cmd := m.ParkIdleTabs(now.Add(59 * time.Minute)); cmd != nil || parkable.isParked() {
+		t.Fatal("tab parked before its timeout")
+	}
+	if cmd := m.ParkIdleTabs(now.Add(61 * time.Minute)); cmd == nil {
+		t.Fatal("expected a cmd announcing the parked tab")
+	}
+	if !parkable.isParked() {
+		t.Fatal("tab idle past its timeout should be parked")
+	}
+	if noPolicy.isParked() {
+		t.Fatal("tab without a park policy should never park")
+	}
+
+	parkable.noteEngaged(now.Add(62 * time.Minute))
+	if parkable.isParked() {
+		t.Fatal("input should unpark the tab")
+	}
+	if cmd := m.ParkIdleTabs(now.Add(90 * time.Minute)); cmd != nil || parkable.isParked() {
+		t.Fatal("input should restart the idle clock")
+	}
+}
+
+func TestParkIdleTabsSkipsStoppedTabs(t *testing.T) {
+	m := New(&config.Config{Assistants: map[string]config.AssistantConfig{
+		"claude": {Park: config.ParkPolicy{After: "1m"}},
+	}})
+	ws := newTestWorkspace("ws", "/repo/ws")
+	tab := &Tab{Name: "claude", Assistant: "claude", Workspace: ws}
+	m.AddTab(tab)
+	tab.engagedAt = time.Now().Add(-time.Hour)
+
+	if cmd := m.ParkIdleTabs(time.Now()); cmd != nil || tab.isParked() {
+		t.Fatal("a tab whose agent isn't running should not park")
+	}
+}
+
+func TestParkActionIsAudited(t *testing.T) {
+	term, err := appPty.New("cat", t.TempDir(), nil)
+	if err != nil {
+		t.Fatalf("new terminal: %v", err)
+	}
+	defer func() { _ = term.Close() }()
+	path := filepath.Join(t.TempDir(), audit.FileName)
+	m := New(&config.Config{})
+	m.SetAuditLog(audit.Open(path))
+	agent := &appPty.Agent{Terminal: term, Session: "amux-ws-claude"}
+
+	m.parkActionCmd(agent, config.ParkPolicy{Action: config.ParkActionSend, Text: "/compact"})()
+	entries, err := audit.Read(path)
+	if err != nil || len(entries) != 1 {
+		t.Fatalf("audit entries = %+v, %v; want the park action", entries, err)
+	}
+	if e := entries[0]; e.Source != audit.SourcePark || e.Target != "amux-ws-claude" || e.Bytes != len("/compact\r") {
+		t.Fatalf("audit entry = %+v", e)
+	}
+}
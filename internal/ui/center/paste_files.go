@@ -0,0 +1,44 @@
+package center
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+
+	"github.com/andyrewlee/amux/internal/logging"
+	"github.com/andyrewlee/amux/internal/safego"
+)
+
+// AttachPasteFile ties a temp file whose path is about to be pasted into the
+// active tab to that tab, so the file is removed once the tab closes and its
+// agent can no longer read it. It reports false when there is no open tab.
+func (m *Model) AttachPasteFile(path string) bool {
+	tabs := m.getTabs()
+	activeIdx := m.getActiveTabIdx()
+	if len(tabs) == 0 || activeIdx >= len(tabs) || tabs[activeIdx].isClosed() {
+		return false
+	}
+	tab := tabs[activeIdx]
+	tab.mu.Lock()
+	tab.pasteFiles = append(tab.pasteFiles, path)
+	tab.mu.Unlock()
+	return true
+}
+
+// removePasteFiles deletes the closed tab's paste files off the UI goroutine.
+func removePasteFiles(tab *Tab) {
+	tab.mu.Lock()
+	paths := tab.pasteFiles
+	tab.pasteFiles = nil
+	tab.mu.Unlock()
+	if len(paths) == 0 {
+		return
+	}
+	safego.Go("center.paste_file_cleanup", func() {
+		for _, path := range paths {
+			if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+				logging.Warn("paste file cleanup failed path=%s error=%v", path, err)
+			}
+		}
+	})
+}
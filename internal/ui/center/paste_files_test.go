@@ -0,0 +1,36 @@
+package center
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/andyrewlee/amux/internal/testutil"
+	"github.com/andyrewlee/amux/internal/vterm"
+)
+
+func TestCloseTabAt_RemovesPasteFiles(t *testing.T) {
+	ws := newTestWorkspace("ws", "/repo/ws")
+	tab := chatTab(ws, "tab-0")
+	tab.Terminal = vterm.New(80, 24)
+	m, _, _ := newActionsModel(t, tab)
+	path := filepath.Join(t.TempDir(), "amux-paste-1.txt")
+	if err := os.WriteFile(path, []byte("pasted"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	if !m.AttachPasteFile(path) {
+		t.Fatal("expected the file to attach to the active tab")
+	}
+	m.closeTabAt(0)
+	testutil.Eventually(t, time.Second, 10*time.Millisecond, func() bool {
+		_, err := os.Stat(path)
+		return errors.Is(err, fs.ErrNotExist)
+	}, "expected the closed tab's paste file to be removed")
+	if m.AttachPasteFile(path) {
+		t.Fatal("a file cannot attach with no tab open")
+	}
+}
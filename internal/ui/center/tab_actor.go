@@ -9,12 +9,22 @@
 //     recoverFailedActorSend. Never call tab.WriteToTerminal or other terminal
 //     mutators directly from Update; that bypasses the actor and races the reader.
 //
-//  2. Backpressure contract. shouldDropTabEvent sheds load only for the
+//  2. Per-tab ordering. RunTabActor is the single intake; it routes each event
+//     to the worker goroutine owned by the event's tab (tab_actor_workers.go),
+//     so events for one tab apply in send order while tabs run in parallel.
+//     A refused gesture is only handled synchronously when nothing for its
+//     tab is in flight (dispatchOrHandleTabEvent); otherwise it is dropped.
+//     Handlers must only touch their own tab (under tab.mu) and Model state
+//     that is safe across goroutines.
+//
+//  3. Backpressure contract. shouldDropTabEvent sheds load only for the
 //     selection/scroll class of events (selection-update, selection-scroll-tick,
 //     scroll-by, scroll-page) once tabEvents is >=75% full; those are coalescible
 //     UI gestures. tabEventWriteOutput is NEVER dropped — losing output corrupts
 //     the terminal — and the closed-tab guard in sendTabEvent returns true (treat
-//     as delivered) for write events so callers do not re-buffer them.
+//     as delivered) for write events so callers do not re-buffer them. A tab
+//     over its worker budget (overWorkerBudget) is refused, never blocked: its
+//     writes fall back to recoverFailedActorSend and stay in PendingOutput.
 //
 // See internal/app/MESSAGE_FLOW.md and internal/app/ARCHITECTURE.md (Invariants)
 // for the broader External-message rules these constraints fit inside.
@@ -22,6 +32,7 @@ package center
 
 import (
 	"context"
+	"sync/atomic"
 	"time"
 
 	tea "charm.land/bubbletea/v2"
@@ -50,6 +61,8 @@ const (
 	tabEventSendMouse
 	tabEventPaste
 	tabEventWriteOutput
+	tabEventClearScrollback
+	tabEventResetTerminal
 )
 
 type tabEvent struct {
@@ -120,10 +133,16 @@ func (m *Model) sendTabEvent(ev tabEvent) bool {
 		perf.Count("tab_event_drop_backpressure", 1)
 		return false
 	}
+	if ev.tab.overWorkerBudget(ev.kind) {
+		perf.Count("tab_event_drop_worker_budget", 1)
+		return false
+	}
+	atomic.AddInt32(&ev.tab.workerInFlight, 1)
 	select {
 	case m.tabEvents <- ev:
 		return true
 	default:
+		ev.tab.noteWorkerDone()
 		perf.Count("tab_event_drop", 1)
 	}
 	return false
@@ -133,12 +152,19 @@ func (m *Model) sendTabEvent(ev tabEvent) bool {
 // synchronously on the caller's goroutine when the actor is not ready or its
 // queue rejected the event. Both routes run the same per-kind handler under
 // tab.mu, so gesture behavior cannot diverge between the actor path and the
-// fallback path. Use this for coalescible UI gestures (selection, scroll);
+// fallback path. A gesture refused while the tab's worker still has events
+// queued is dropped instead, as handling it here would apply it ahead of
+// them. Use this for coalescible UI gestures (selection, scroll);
 // terminal writes have their own rollback-aware fallback (recoverFailedActorSend).
 func (m *Model) dispatchOrHandleTabEvent(ev tabEvent) {
 	if m.isTabActorReady() && m.sendTabEvent(ev) {
 		return
 	}
+	if ev.tab != nil && ev.tab.workerBusy() {
+		// Handling it here would overtake the tab's queued events.
+		perf.Count("tab_event_drop_worker_busy", 1)
+		return
+	}
 	m.handleTabEvent(ev)
 }
 
@@ -169,7 +195,9 @@ func shouldPostTabActorRedraw(kind tabEventKind) bool {
 		tabEventScrollToBottom,
 		tabEventScrollPage,
 		tabEventScrollToTop,
-		tabEventDiffInput:
+		tabEventDiffInput,
+		tabEventClearScrollback,
+		tabEventResetTerminal:
 		return true
 	default:
 		return false
@@ -189,10 +217,7 @@ func (m *Model) RunTabActor(ctx context.Context) error {
 			return nil
 		case ev := <-m.tabEvents:
 			m.noteTabActorHeartbeat()
-			m.handleTabEvent(ev)
-			if shouldPostTabActorRedraw(ev.kind) {
-				m.requestTabActorRedraw()
-			}
+			m.routeTabEvent(ctx, ev)
 		case <-ticker.C:
 			m.noteTabActorHeartbeat()
 		}
@@ -237,6 +262,10 @@ func (m *Model) handleTabEvent(ev tabEvent) {
 		m.handlePaste(ev)
 	case tabEventWriteOutput:
 		m.handleWriteOutput(ev)
+	case tabEventClearScrollback:
+		m.handleClearScrollback(ev)
+	case tabEventResetTerminal:
+		m.handleResetTerminal(ev)
 	default:
 		logging.Debug("unknown tab event: %v", ev.kind)
 	}
@@ -358,7 +387,9 @@ func (m *Model) sendToTerminal(tab *Tab, data string, tabID TabID, workspaceID,
 		}
 		return
 	}
-	recordLocalInputEchoWindow(tab, data, time.Now())
+	now := time.Now()
+	recordLocalInputEchoWindow(tab, data, now)
+	m.recordPromptInput(tab, data, now)
 	if m.msgSink != nil && m.isChatTab(tab) {
 		m.msgSink(PTYCursorRefresh{WorkspaceID: workspaceID, TabID: tabID})
 	}
@@ -0,0 +1,156 @@
+package center
+
+import (
+	"context"
+	"sync/atomic"
+
+	"github.com/andyrewlee/amux/internal/perf"
+	"github.com/andyrewlee/amux/internal/safego"
+)
+
+// Per-tab workers. RunTabActor stays the single intake for tabEvents (it owns
+// the heartbeat and preserves per-tab event order) but hands each event to a
+// worker goroutine owned by the event's tab. A tab whose agent floods output
+// therefore only delays its own VTerm writes; other tabs' output, input, and
+// selection events run on their own workers.
+const (
+	// tabWorkerQueueSize is each worker's queue capacity.
+	tabWorkerQueueSize = 256
+	// tabWorkerEventBudget caps events a tab may have in flight between
+	// sendTabEvent and its worker. Past it, sendTabEvent refuses the event so
+	// the caller's fallback applies: writes are rebuffered into PendingOutput
+	// (see recoverFailedActorSend) and gestures run synchronously. Keeping it
+	// below the queue size means the intake never blocks on a full worker.
+	tabWorkerEventBudget = tabWorkerQueueSize * 3 / 4
+	// tabWorkerWriteBudget caps output bytes a tab may have queued for its
+	// worker. A chatty tab past this keeps its backlog in PendingOutput, where
+	// overflow trimming applies, instead of piling chunks onto the worker.
+	tabWorkerWriteBudget = 4 * ptyFlushChunkSizeCatchUp
+)
+
+// tabWorker applies one tab's events in order on its own goroutine.
+type tabWorker struct {
+	events chan tabEvent
+	// done is closed by stopTabWorker when the tab closes. Events still
+	// queued are for a closed tab, which handleTabEvent would drop anyway.
+	done chan struct{}
+}
+
+// overWorkerBudget reports whether tab already has a full share of in-flight
+// work, so a new event of kind should be refused.
+func (t *Tab) overWorkerBudget(kind tabEventKind) bool {
+	if atomic.LoadInt32(&t.workerInFlight) >= tabWorkerEventBudget {
+		return true
+	}
+	if kind != tabEventWriteOutput {
+		return false
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	// The chunk being sent is already counted (enqueueActorWrite runs first),
+	// so a lone oversized chunk is still admitted.
+	return t.actorWritesPending > 1 && t.actorQueuedBytes > tabWorkerWriteBudget
+}
+
+// workerBusy reports whether events for t are still queued on or being
+// applied by its worker. Handling another event for t synchronously then
+// would apply it out of order.
+func (t *Tab) workerBusy() bool {
+	return atomic.LoadInt32(&t.workerInFlight) > 0
+}
+
+// noteWorkerDone releases one in-flight slot, tolerating events that reached
+// the intake without passing sendTabEvent's accounting.
+func (t *Tab) noteWorkerDone() {
+	for {
+		n := atomic.LoadInt32(&t.workerInFlight)
+		if n <= 0 || atomic.CompareAndSwapInt32(&t.workerInFlight, n, n-1) {
+			return
+		}
+	}
+}
+
+// routeTabEvent hands ev to its tab's worker, starting one on first use.
+// Only the RunTabActor goroutine calls it. The send blocks only if a worker
+// queue is full, which the per-tab event budget normally prevents.
+func (m *Model) routeTabEvent(ctx context.Context, ev tabEvent) {
+	if ev.tab == nil || ev.tab.isClosed() {
+		m.stopTabWorker(ev.tab)
+		m.handleTabEvent(ev)
+		return
+	}
+	w := m.tabWorkerFor(ctx, ev.tab)
+	select {
+	case w.events <- ev:
+	case <-w.done:
+	case <-ctx.Done():
+	}
+}
+
+func (m *Model) tabWorkerFor(ctx context.Context, tab *Tab) *tabWorker {
+	m.tabWorkersMu.Lock()
+	defer m.tabWorkersMu.Unlock()
+	if w := m.tabWorkers[tab]; w != nil {
+		return w
+	}
+	if m.tabWorkers == nil {
+		m.tabWorkers = make(map[*Tab]*tabWorker)
+	}
+	w := &tabWorker{events: make(chan tabEvent, tabWorkerQueueSize), done: make(chan struct{})}
+	m.tabWorkers[tab] = w
+	perf.Count("tab_worker_start", 1)
+	safego.Go("center.tab_worker", func() { m.runTabWorker(ctx, tab, w) })
+	return w
+}
+
+// runTabWorker drains w until the tab closes or the actor context ends. It
+// unregisters itself on exit (including after a recovered panic) so the next
+// event for a still-open tab starts a fresh worker.
+func (m *Model) runTabWorker(ctx context.Context, tab *Tab, w *tabWorker) {
+	defer func() {
+		m.tabWorkersMu.Lock()
+		if m.tabWorkers[tab] == w {
+			delete(m.tabWorkers, tab)
+		}
+		m.tabWorkersMu.Unlock()
+	}()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-w.done:
+			return
+		case ev := <-w.events:
+			m.handleTabEvent(ev)
+			tab.noteWorkerDone()
+			if shouldPostTabActorRedraw(ev.kind) {
+				m.requestTabActorRedraw()
+			}
+			if tab.isClosed() && len(w.events) == 0 {
+				return
+			}
+		}
+	}
+}
+
+// stopTabWorker ends the closed tab's worker and forgets it, so neither the
+// goroutine nor the tab it holds outlive the tab. Tab-close paths call it
+// right after markClosed; routeTabEvent calls it again for late events.
+func (m *Model) stopTabWorker(tab *Tab) {
+	if m == nil || tab == nil {
+		return
+	}
+	m.tabWorkersMu.Lock()
+	defer m.tabWorkersMu.Unlock()
+	if w := m.tabWorkers[tab]; w != nil {
+		delete(m.tabWorkers, tab)
+		close(w.done)
+	}
+}
+
+// tabWorkerCount reports the number of live per-tab workers.
+func (m *Model) tabWorkerCount() int {
+	m.tabWorkersMu.Lock()
+	defer m.tabWorkersMu.Unlock()
+	return len(m.tabWorkers)
+}
@@ -0,0 +1,145 @@
+package center
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	tea "charm.land/bubbletea/v2"
+
+	"github.com/andyrewlee/amux/internal/testutil"
+	"github.com/andyrewlee/amux/internal/vterm"
+)
+
+func TestRunTabActor_BlockedTabDoesNotStallOtherTabs(t *testing.T) {
+	m := newTestModel()
+	m.tabEvents = make(chan tabEvent, 8)
+	sinkMsgs := make(chan tea.Msg, 8)
+	m.msgSink = func(msg tea.Msg) { sinkMsgs <- msg }
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	done := make(chan error, 1)
+	go func() { done <- m.RunTabActor(ctx) }()
+	testutil.Eventually(t, time.Second, 10*time.Millisecond, m.isTabActorReady, "expected actor to become ready")
+
+	busy := &Tab{Terminal: vterm.New(80, 24)}
+	idle := &Tab{Terminal: vterm.New(80, 24)}
+
+	// Hold the busy tab's lock so its worker parks inside the handler, the way
+	// a long VTerm write would.
+	busy.mu.Lock()
+	m.tabEvents <- tabEvent{kind: tabEventSelectionStart, tab: busy}
+	m.tabEvents <- tabEvent{kind: tabEventSelectionStart, tab: idle}
+
+	select {
+	case msg := <-sinkMsgs:
+		if _, ok := msg.(tabActorRedraw); !ok {
+			t.Fatalf("expected redraw message, got %T", msg)
+		}
+	case <-time.After(time.Second):
+		busy.mu.Unlock()
+		t.Fatal("idle tab's event waited behind the busy tab")
+	}
+	busy.mu.Unlock()
+
+	if got := m.tabWorkerCount(); got != 2 {
+		t.Fatalf("tabWorkerCount() = %d, want 2", got)
+	}
+
+	cancel()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("RunTabActor() error = %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for actor to stop")
+	}
+	testutil.Eventually(t, time.Second, 10*time.Millisecond, func() bool {
+		return m.tabWorkerCount() == 0
+	}, "expected workers to exit with the actor context")
+}
+
+func TestSendTabEvent_RefusesTabOverEventBudget(t *testing.T) {
+	m := &Model{tabEvents: make(chan tabEvent, 64)}
+	tab := &Tab{}
+	atomic.StoreInt32(&tab.workerInFlight, tabWorkerEventBudget)
+
+	if m.sendTabEvent(tabEvent{tab: tab, kind: tabEventSelectionClear}) {
+		t.Fatal("expected event past the per-tab budget to be refused")
+	}
+	other := &Tab{}
+	if !m.sendTabEvent(tabEvent{tab: other, kind: tabEventSelectionClear}) {
+		t.Fatal("expected another tab's event to be accepted")
+	}
+	if got := atomic.LoadInt32(&other.workerInFlight); got != 1 {
+		t.Fatalf("workerInFlight = %d, want 1", got)
+	}
+}
+
+func TestSendTabEvent_RefusesWriteOverByteBudget(t *testing.T) {
+	m := &Model{tabEvents: make(chan tabEvent, 64)}
+	tab := &Tab{}
+
+	tab.actorWritesPending = 1
+	tab.actorQueuedBytes = tabWorkerWriteBudget + 1
+	if !m.sendTabEvent(tabEvent{tab: tab, kind: tabEventWriteOutput}) {
+		t.Fatal("expected a lone oversized write to be admitted")
+	}
+
+	tab.actorWritesPending = 2
+	if m.sendTabEvent(tabEvent{tab: tab, kind: tabEventWriteOutput}) {
+		t.Fatal("expected write past the byte budget to be refused")
+	}
+	if !m.sendTabEvent(tabEvent{tab: tab, kind: tabEventSelectionClear}) {
+		t.Fatal("expected non-write events to ignore the byte budget")
+	}
+}
+
+func TestCloseTabAt_StopsTabWorker(t *testing.T) {
+	ws := newTestWorkspace("ws", "/repo/ws")
+	tab := chatTab(ws, "tab-0")
+	tab.Terminal = vterm.New(80, 24)
+	m, _, _ := newActionsModel(t, tab)
+	m.tabEvents = make(chan tabEvent, 8)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() { _ = m.RunTabActor(ctx) }()
+	testutil.Eventually(t, time.Second, 10*time.Millisecond, m.isTabActorReady, "expected actor to become ready")
+
+	if !m.sendTabEvent(tabEvent{kind: tabEventSelectionClear, tab: tab}) {
+		t.Fatal("expected the event to be accepted")
+	}
+	testutil.Eventually(t, time.Second, 10*time.Millisecond, func() bool {
+		return m.tabWorkerCount() == 1 && !tab.workerBusy()
+	}, "expected the tab's worker to start and go idle")
+
+	// The worker's queue is empty, so only the close can stop it.
+	m.closeTabAt(0)
+	testutil.Eventually(t, time.Second, 10*time.Millisecond, func() bool {
+		return m.tabWorkerCount() == 0
+	}, "expected the closed tab's worker to exit")
+}
+
+func TestDispatchOrHandleTabEvent_DropsRefusedGestureBehindQueuedEvents(t *testing.T) {
+	m := &Model{tabEvents: make(chan tabEvent, 64)}
+	m.setTabActorReady()
+	tab := &Tab{Terminal: vterm.New(80, 24)}
+	tab.Selection.Active = true
+	atomic.StoreInt32(&tab.workerInFlight, tabWorkerEventBudget)
+
+	m.dispatchOrHandleTabEvent(tabEvent{tab: tab, kind: tabEventSelectionClear})
+	if !tab.Selection.Active {
+		t.Fatal("refused gesture was applied ahead of the tab's queued events")
+	}
+
+	atomic.StoreInt32(&tab.workerInFlight, 0)
+	m.tabEvents = nil
+	m.dispatchOrHandleTabEvent(tabEvent{tab: tab, kind: tabEventSelectionClear})
+	if tab.Selection.Active {
+		t.Fatal("expected the gesture to be handled in place with nothing queued")
+	}
+}
@@ -23,6 +23,8 @@ type DialogResult struct {
 	Confirmed bool
 	Value     string
 	Index     int
+	// Toggle is the state of the dialog's on/off option (see SetToggle).
+	Toggle bool
 }
 
 // InputTransformFunc transforms input text before it's added to the input field
@@ -61,6 +63,12 @@ type Dialog struct {
 	filterEnabled   bool
 	filterInput     textinput.Model
 	filteredIndices []int // indices into options
+	// listLayout renders select options one per line (NewListDialog) instead
+	// of as a row of buttons.
+	listLayout bool
+	// toggleLabel, when set, adds an on/off option flipped with ctrl+s.
+	toggleLabel string
+	toggleOn    bool
 
 	// Layout
 	width      int
@@ -106,6 +114,18 @@ func NewConfirmDialog(id, title, message string) *Dialog {
 	}
 }
 
+// NewSelectDialog creates a single-choice dialog that lists options as
+// buttons. The confirmed DialogResult carries the chosen Index and Value.
+func NewSelectDialog(id, title, message string, options []string) *Dialog {
+	return &Dialog{
+		id:      id,
+		dtype:   DialogSelect,
+		title:   title,
+		message: message,
+		options: append([]string(nil), options...),
+	}
+}
+
 // SetDefaultOption sets the option selected whenever the dialog is shown.
 func (d *Dialog) SetDefaultOption(index int) {
 	if d == nil || index < 0 || index >= len(d.options) {
@@ -155,14 +175,33 @@ func (d *Dialog) SetInputValidate(fn InputValidateFunc) *Dialog {
 	return d
 }
 
+// SetInputCharLimit sets how many characters the input accepts; input
+// dialogs default to 100.
+func (d *Dialog) SetInputCharLimit(n int) *Dialog {
+	d.input.CharLimit = n
+	return d
+}
+
 // SetInputValue prefills the input field's current value so a dialog opened for
 // editing (e.g. rename) renders the existing value ready to edit. It affects
-// input dialogs only. Call it after Show(), which resets the input to empty.
+// input dialogs only, and runs the validator on s as typing would. Call it
+// after Show(), which resets the input to empty.
 func (d *Dialog) SetInputValue(s string) {
 	if d == nil || d.dtype != DialogInput {
 		return
 	}
 	d.input.SetValue(s)
+	if d.inputValidate != nil {
+		d.validationErr = d.inputValidate(d.input.Value())
+	}
+}
+
+// InputValue returns what is typed in an input dialog.
+func (d *Dialog) InputValue() string {
+	if d == nil || d.dtype != DialogInput {
+		return ""
+	}
+	return d.input.Value()
 }
 
 // transformInputMsg applies the input transform to key press and paste messages
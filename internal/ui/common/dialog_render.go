@@ -158,6 +158,10 @@ func (d *Dialog) renderLines() []string {
 			appendLines(d.message)
 			appendBlank(1)
 		}
+		if d.toggleLabel != "" {
+			appendLines(d.renderToggle())
+			appendBlank(1)
+		}
 		baseLine := d.renderedLineCount(lines)
 		lines = append(lines, d.renderOptionsLines(baseLine)...)
 	}
@@ -177,6 +181,9 @@ func (d *Dialog) renderOptionsLines(baseLine int) []string {
 	if d.id == AgentPickerDialogID {
 		return d.renderAgentPickerOptions(baseLine)
 	}
+	if d.listLayout {
+		return d.renderListOptions(baseLine)
+	}
 	return []string{d.renderHorizontalOptionsLine(baseLine)}
 }
 
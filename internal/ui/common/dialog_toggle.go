@@ -0,0 +1,29 @@
+package common
+
+import "charm.land/lipgloss/v2"
+
+// toggleKey flips a dialog's on/off option. Tab and the arrows move the
+// cursor and printable keys feed the filter, so it needs a modifier.
+const toggleKey = "ctrl+s"
+
+// SetToggle adds an on/off option, shown under the message of a select dialog
+// and flipped with ctrl+s. Its state when the dialog is confirmed is reported
+// in DialogResult.Toggle.
+func (d *Dialog) SetToggle(label string, on bool) {
+	if d == nil {
+		return
+	}
+	d.toggleLabel = label
+	d.toggleOn = on
+}
+
+func (d *Dialog) renderToggle() string {
+	box := "[ ]"
+	style := lipgloss.NewStyle().Foreground(ColorMuted())
+	if d.toggleOn {
+		box = "[x]"
+		style = lipgloss.NewStyle().Foreground(ColorWarning())
+	}
+	hint := lipgloss.NewStyle().Foreground(ColorMuted()).Render("  " + toggleKey)
+	return style.Render(box+" "+d.toggleLabel) + hint
+}
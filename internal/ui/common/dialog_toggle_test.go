@@ -0,0 +1,47 @@
+package common
+
+import (
+	"strings"
+	"testing"
+
+	tea "charm.land/bubbletea/v2"
+)
+
+func TestDialogToggleFlipsAndIsReported(t *testing.T) {
+	d := NewAgentPicker([]string{"claude", "codex"})
+	d.SetToggle("Sandbox", false)
+	d.SetSize(80, 24)
+	d.Show()
+
+	if !strings.Contains(d.View(), "[ ] Sandbox") {
+		t.Fatalf("View() missing unchecked toggle:\n%s", d.View())
+	}
+	d.Update(tea.KeyPressMsg{Code: 's', Mod: tea.ModCtrl})
+	if !strings.Contains(d.View(), "[x] Sandbox") {
+		t.Fatalf("View() missing checked toggle after ctrl+s:\n%s", d.View())
+	}
+	if got := d.filterInput.Value(); got != "" {
+		t.Fatalf("ctrl+s leaked into the filter: %q", got)
+	}
+
+	_, cmd := d.Update(tea.KeyPressMsg{Code: tea.KeyEnter})
+	result, ok := cmd().(DialogResult)
+	if !ok || !result.Confirmed || result.Value != "claude" || !result.Toggle {
+		t.Fatalf("result = %#v, want claude confirmed with the toggle on", result)
+	}
+}
+
+func TestDialogWithoutToggleIgnoresCtrlS(t *testing.T) {
+	d := NewAgentPicker([]string{"claude"})
+	d.SetSize(80, 24)
+	d.Show()
+
+	d.Update(tea.KeyPressMsg{Code: 's', Mod: tea.ModCtrl})
+	if strings.Contains(d.View(), "[x]") {
+		t.Fatal("a dialog without a toggle should not render one")
+	}
+	_, cmd := d.Update(tea.KeyPressMsg{Code: tea.KeyEnter})
+	if result := cmd().(DialogResult); result.Toggle {
+		t.Fatalf("result = %#v, want Toggle off", result)
+	}
+}
@@ -76,16 +76,22 @@ func (d *Dialog) Update(msg tea.Msg) (*Dialog, tea.Cmd) {
 						return DialogResult{ID: d.id, Confirmed: false}
 					}
 				}
+				toggle := d.toggleOn
 				return d, func() tea.Msg {
 					return DialogResult{
 						ID:        d.id,
 						Confirmed: true,
 						Index:     originalIdx,
 						Value:     value,
+						Toggle:    toggle,
 					}
 				}
 			}
 
+		case d.toggleLabel != "" && key.Matches(msg, key.NewBinding(key.WithKeys(toggleKey))):
+			d.toggleOn = !d.toggleOn
+			return d, nil
+
 		case key.Matches(msg, key.NewBinding(key.WithKeys("tab", "down"))):
 			if d.dtype != DialogInput {
 				maxLen := len(d.options)
@@ -0,0 +1,63 @@
+package common
+
+import (
+	"strings"
+
+	"charm.land/bubbles/v2/textinput"
+	"charm.land/lipgloss/v2"
+)
+
+// NewListDialog creates a select dialog that lists options one per line with
+// fuzzy filtering, for choices too long or numerous for a row of buttons. The
+// confirmed DialogResult carries the original Index and Value of the choice.
+func NewListDialog(id, title, message string, options []string) *Dialog {
+	allIndices := make([]int, len(options))
+	for i := range options {
+		allIndices[i] = i
+	}
+
+	fi := textinput.New()
+	fi.Placeholder = "Type to filter..."
+	fi.Focus()
+	fi.CharLimit = 40
+	fi.SetWidth(30)
+	fi.SetVirtualCursor(false)
+
+	return &Dialog{
+		id:              id,
+		dtype:           DialogSelect,
+		title:           title,
+		message:         message,
+		options:         append([]string(nil), options...),
+		filterEnabled:   true,
+		filterInput:     fi,
+		filteredIndices: allIndices,
+		listLayout:      true,
+	}
+}
+
+func (d *Dialog) renderListOptions(baseLine int) []string {
+	inputLines := strings.Split(d.filterInput.View(), "\n")
+	lines := append([]string{}, inputLines...)
+	lines = append(lines, "", "")
+	lineIndex := baseLine + len(lines)
+
+	if len(d.filteredIndices) == 0 {
+		return append(lines, lipgloss.NewStyle().Foreground(ColorMuted()).Render("No matches"))
+	}
+
+	width := d.dialogContentWidth()
+	for cursorIdx, originalIdx := range d.filteredIndices {
+		cursor := Icons.CursorEmpty + " "
+		style := lipgloss.NewStyle().Foreground(ColorForeground())
+		if cursorIdx == d.cursor {
+			cursor = Icons.Cursor + " "
+			style = style.Bold(true)
+		}
+		line := cursor + style.Render(d.options[originalIdx])
+		d.addOptionHit(cursorIdx, originalIdx, lineIndex, 0, width)
+		lines = append(lines, line)
+		lineIndex++
+	}
+	return lines
+}
@@ -0,0 +1,46 @@
+package common
+
+import (
+	"strings"
+	"testing"
+
+	tea "charm.land/bubbletea/v2"
+)
+
+func TestListDialogRendersOptionsOnSeparateLines(t *testing.T) {
+	d := NewListDialog("trash", "Trash", "Restore a workspace:", []string{"alpha (repo)", "beta (repo)"})
+	d.SetSize(80, 24)
+	d.Show()
+
+	view := d.View()
+	for _, want := range []string{"Trash", "Restore a workspace:", "alpha (repo)", "beta (repo)"} {
+		if !strings.Contains(view, want) {
+			t.Fatalf("expected list dialog view to contain %q, got:\n%s", want, view)
+		}
+	}
+	for _, line := range strings.Split(view, "\n") {
+		if strings.Contains(line, "alpha") && strings.Contains(line, "beta") {
+			t.Fatalf("expected options on separate lines, got %q", line)
+		}
+	}
+}
+
+func TestListDialogConfirmReturnsOriginalIndexAfterFilter(t *testing.T) {
+	d := NewListDialog("trash", "Trash", "", []string{"alpha", "beta", "gamma"})
+	d.SetSize(80, 24)
+	d.Show()
+
+	d.filterInput.SetValue("gam")
+	d.applyFilter()
+	_, cmd := d.Update(tea.KeyPressMsg{Code: tea.KeyEnter})
+	if cmd == nil {
+		t.Fatal("expected a result command on enter")
+	}
+	result, ok := cmd().(DialogResult)
+	if !ok {
+		t.Fatalf("expected DialogResult, got %T", cmd())
+	}
+	if !result.Confirmed || result.Index != 2 || result.Value != "gamma" {
+		t.Fatalf("result = %+v, want confirmed index 2 value gamma", result)
+	}
+}
@@ -0,0 +1,226 @@
+package common
+
+import (
+	"charm.land/bubbles/v2/key"
+	"charm.land/bubbles/v2/textinput"
+	tea "charm.land/bubbletea/v2"
+)
+
+// SearchPanelAction is what the user asked of the search panel.
+type SearchPanelAction int
+
+const (
+	// SearchPanelClosed is Esc: the panel closed without acting.
+	SearchPanelClosed SearchPanelAction = iota
+	// SearchPanelOpen opens the selected match (Enter).
+	SearchPanelOpen
+	// SearchPanelSend sends every listed match to an agent (Ctrl+S).
+	SearchPanelSend
+)
+
+// SearchQueryChanged is sent each time the search panel's query is edited,
+// for the caller to run the search and hand the matches back through
+// SetResults. The panel does not search itself.
+type SearchQueryChanged struct {
+	ID    string
+	Query string
+}
+
+// SearchPanelResult is sent when the search panel closes, acting on its
+// matches or not.
+type SearchPanelResult struct {
+	ID     string
+	Action SearchPanelAction
+	// Index is the selected match, for SearchPanelOpen.
+	Index int
+}
+
+// SearchPanelMatch is one row of the search panel's results.
+type SearchPanelMatch struct {
+	Label string
+	// Preview is shown under the list while the match is selected.
+	Preview []SearchPreviewLine
+}
+
+// SearchPreviewLine is one line of a match's preview, numbered unless Number
+// is 0; Match marks the matching line.
+type SearchPreviewLine struct {
+	Number int
+	Text   string
+	Match  bool
+}
+
+// SearchPanel is a modal panel with a query input, the matches found for it
+// as it is typed, and a preview of the selected match. Like EnvDialog it is
+// domain-agnostic: the caller runs each search on SearchQueryChanged and acts
+// on SearchPanelResult, both of which carry the panel's ID, as DialogResult
+// carries a Dialog's.
+type SearchPanel struct {
+	id      string
+	visible bool
+	width   int
+	height  int
+
+	title   string
+	input   textinput.Model
+	matches []SearchPanelMatch
+	// status describes the results, such as "12 matches" or an error.
+	status string
+	// hint is the key help under the preview.
+	hint   string
+	cursor int
+	offset int
+}
+
+// NewSearchPanel returns a hidden search panel titled title.
+func NewSearchPanel(id, title, placeholder string) *SearchPanel {
+	input := textinput.New()
+	input.Placeholder = placeholder
+	input.CharLimit = 200
+	input.SetVirtualCursor(false)
+	input.Focus()
+	return &SearchPanel{
+		id:    id,
+		title: title,
+		input: input,
+		hint:  "type to search  up/down select  enter open  ctrl+s send to agent  esc close",
+	}
+}
+
+// ID returns the ID the panel was created with.
+func (p *SearchPanel) ID() string { return p.id }
+
+func (p *SearchPanel) Show()         { p.visible = true }
+func (p *SearchPanel) Hide()         { p.visible = false }
+func (p *SearchPanel) Visible() bool { return p.visible }
+
+func (p *SearchPanel) SetSize(w, h int) {
+	p.width, p.height = w, h
+	p.input.SetWidth(p.contentWidth() - 4)
+	p.clampOffset()
+}
+
+// Query returns the query as typed.
+func (p *SearchPanel) Query() string {
+	return p.input.Value()
+}
+
+// SetQuery replaces the query without sending SearchQueryChanged.
+func (p *SearchPanel) SetQuery(query string) {
+	p.input.SetValue(query)
+	p.input.CursorEnd()
+}
+
+// SetResults lists matches, with status describing them, and selects the
+// first.
+func (p *SearchPanel) SetResults(matches []SearchPanelMatch, status string) {
+	p.matches = matches
+	p.status = status
+	p.cursor = 0
+	p.offset = 0
+}
+
+// ReplaceResults lists matches like SetResults but keeps the selection,
+// for a list that updates while it is browsed.
+func (p *SearchPanel) ReplaceResults(matches []SearchPanelMatch, status string) {
+	p.matches = matches
+	p.status = status
+	p.cursor = max(0, min(p.cursor, len(matches)-1))
+	p.clampOffset()
+}
+
+// SetHint replaces the key help, for a caller whose Enter does something
+// other than open a file.
+func (p *SearchPanel) SetHint(hint string) {
+	p.hint = hint
+}
+
+// SetStatus replaces the status line, keeping the matches.
+func (p *SearchPanel) SetStatus(status string) {
+	p.status = status
+}
+
+// Selected returns the selected match's index, or -1 when none is listed.
+func (p *SearchPanel) Selected() int {
+	if len(p.matches) == 0 {
+		return -1
+	}
+	return p.cursor
+}
+
+// Update handles input. Esc closes; Enter opens the selected match and
+// Ctrl+S sends them all, both closing the panel. Every other key edits the
+// query.
+func (p *SearchPanel) Update(msg tea.Msg) (*SearchPanel, tea.Cmd) {
+	if !p.visible {
+		return p, nil
+	}
+	if keyMsg, ok := msg.(tea.KeyPressMsg); ok {
+		switch {
+		case key.Matches(keyMsg, key.NewBinding(key.WithKeys("esc"))):
+			return p, p.close(SearchPanelClosed)
+		case key.Matches(keyMsg, key.NewBinding(key.WithKeys("enter"))):
+			if len(p.matches) == 0 {
+				return p, nil
+			}
+			return p, p.close(SearchPanelOpen)
+		case key.Matches(keyMsg, key.NewBinding(key.WithKeys("ctrl+s"))):
+			if len(p.matches) == 0 {
+				return p, nil
+			}
+			return p, p.close(SearchPanelSend)
+		case key.Matches(keyMsg, key.NewBinding(key.WithKeys("up", "ctrl+p"))):
+			p.moveCursor(-1)
+			return p, nil
+		case key.Matches(keyMsg, key.NewBinding(key.WithKeys("down", "ctrl+n"))):
+			p.moveCursor(1)
+			return p, nil
+		case key.Matches(keyMsg, key.NewBinding(key.WithKeys("pgup"))):
+			p.moveCursor(-p.listHeight())
+			return p, nil
+		case key.Matches(keyMsg, key.NewBinding(key.WithKeys("pgdown"))):
+			p.moveCursor(p.listHeight())
+			return p, nil
+		}
+	}
+	switch msg.(type) {
+	case tea.KeyPressMsg, tea.PasteMsg:
+	default:
+		return p, nil
+	}
+	before := p.input.Value()
+	var cmd tea.Cmd
+	p.input, cmd = p.input.Update(msg)
+	if query := p.input.Value(); query != before {
+		changed := func() tea.Msg { return SearchQueryChanged{ID: p.id, Query: query} }
+		return p, SafeBatch(cmd, changed)
+	}
+	return p, cmd
+}
+
+func (p *SearchPanel) close(action SearchPanelAction) tea.Cmd {
+	p.visible = false
+	result := SearchPanelResult{ID: p.id, Action: action, Index: p.Selected()}
+	return func() tea.Msg { return result }
+}
+
+// moveCursor moves the selection by delta, stopping at either end, and
+// scrolls the list to keep it in view.
+func (p *SearchPanel) moveCursor(delta int) {
+	if len(p.matches) == 0 {
+		return
+	}
+	p.cursor = max(0, min(len(p.matches)-1, p.cursor+delta))
+	p.clampOffset()
+}
+
+func (p *SearchPanel) clampOffset() {
+	height := p.listHeight()
+	if p.cursor < p.offset {
+		p.offset = p.cursor
+	}
+	if p.cursor >= p.offset+height {
+		p.offset = p.cursor - height + 1
+	}
+	p.offset = max(0, min(p.offset, len(p.matches)-height))
+}
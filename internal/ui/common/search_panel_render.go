@@ -0,0 +1,119 @@
+package common
+
+import (
+	"fmt"
+	"strings"
+
+	tea "charm.land/bubbletea/v2"
+	"charm.land/lipgloss/v2"
+)
+
+// searchPreviewLines is the preview's height, enough for a match and three
+// lines on each side.
+const searchPreviewLines = 7
+
+func (p *SearchPanel) contentWidth() int {
+	if p.width > 0 {
+		return min(120, max(50, p.width-10))
+	}
+	return 80
+}
+
+// listHeight is how many matches fit above the preview.
+func (p *SearchPanel) listHeight() int {
+	if p.height <= 0 {
+		return 10
+	}
+	// Title, input, status, preview, help, the blank lines between them,
+	// and the frame.
+	return max(3, min(15, p.height-searchPreviewLines-14))
+}
+
+func (p *SearchPanel) View() string {
+	if !p.visible {
+		return ""
+	}
+	return dialogBorderStyle(p.contentWidth()).Render(strings.Join(p.renderLines(), "\n"))
+}
+
+func (p *SearchPanel) renderLines() []string {
+	width := p.contentWidth() - 4
+	title := lipgloss.NewStyle().Bold(true).Foreground(ColorPrimary())
+	muted := lipgloss.NewStyle().Foreground(ColorMuted())
+
+	lines := []string{title.Render(p.title), "", p.input.View(), ""}
+	lines = append(lines, muted.Render(truncateToWidth(p.status, width)))
+
+	height := p.listHeight()
+	for i := p.offset; i < p.offset+height; i++ {
+		if i >= len(p.matches) {
+			lines = append(lines, "")
+			continue
+		}
+		label := truncateToWidth(expandTabs(p.matches[i].Label), width-2)
+		if i == p.cursor {
+			lines = append(lines, Icons.Cursor+" "+lipgloss.NewStyle().Bold(true).Foreground(ColorForeground()).Render(label))
+		} else {
+			lines = append(lines, Icons.CursorEmpty+" "+lipgloss.NewStyle().Foreground(ColorForeground()).Render(label))
+		}
+	}
+
+	lines = append(lines, "")
+	lines = append(lines, p.renderPreview(width)...)
+	lines = append(lines, "", muted.Render(p.hint))
+	return lines
+}
+
+// renderPreview shows the selected match's preview, padded to a fixed
+// height so the panel does not jump as the selection moves.
+func (p *SearchPanel) renderPreview(width int) []string {
+	muted := lipgloss.NewStyle().Foreground(ColorMuted())
+	var preview []SearchPreviewLine
+	if i := p.Selected(); i >= 0 {
+		preview = p.matches[i].Preview
+	}
+	numberWidth := 1
+	for _, line := range preview {
+		numberWidth = max(numberWidth, len(fmt.Sprint(line.Number)))
+	}
+	lines := make([]string, 0, searchPreviewLines)
+	for _, line := range preview {
+		if len(lines) == searchPreviewLines {
+			break
+		}
+		gutter := fmt.Sprintf("%*d │ ", numberWidth, line.Number)
+		if line.Number == 0 {
+			gutter = strings.Repeat(" ", numberWidth) + " │ "
+		}
+		text := truncateToWidth(expandTabs(line.Text), width-lipgloss.Width(gutter))
+		if line.Match {
+			lines = append(lines, muted.Render(gutter)+lipgloss.NewStyle().Bold(true).Foreground(ColorPrimary()).Render(text))
+		} else {
+			lines = append(lines, muted.Render(gutter+text))
+		}
+	}
+	for len(lines) < searchPreviewLines {
+		lines = append(lines, "")
+	}
+	return lines
+}
+
+// Cursor places the terminal cursor in the query input.
+func (p *SearchPanel) Cursor() *tea.Cursor {
+	if !p.visible || p.input.VirtualCursor() || !p.input.Focused() {
+		return nil
+	}
+	c := p.input.Cursor()
+	if c == nil {
+		return nil
+	}
+	// The input follows the title and a blank line; account for the
+	// border and padding too (Border=1, Padding=(1,2)).
+	c.X += 3
+	c.Y += 2 + 2
+	return c
+}
+
+func expandTabs(s string) string {
+	return strings.ReplaceAll(s, "\t", "    ")
+}
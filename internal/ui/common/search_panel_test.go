@@ -0,0 +1,85 @@
+package common
+
+import (
+	"strings"
+	"testing"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/charmbracelet/x/ansi"
+)
+
+func TestSearchPanel(t *testing.T) {
+	p := NewSearchPanel("files", "Search Files", "text to find...")
+	p.SetSize(120, 40)
+	p.Show()
+
+	_, cmd := p.Update(tea.KeyPressMsg{Code: 'f', Text: "f"})
+	if msg := findSearchMsg[SearchQueryChanged](t, cmd); msg.ID != "files" || msg.Query != "f" {
+		t.Fatalf("query changed = %+v, want f from the files panel", msg)
+	}
+	if _, cmd := p.Update(tea.KeyPressMsg{Code: tea.KeyEnter}); cmd != nil {
+		t.Fatal("enter without matches should do nothing")
+	}
+
+	p.SetResults([]SearchPanelMatch{
+		{Label: "a.go:2  func Foo() {}", Preview: []SearchPreviewLine{{Number: 1, Text: "package a"}, {Number: 2, Text: "func Foo() {}", Match: true}}},
+		{Label: "b.go:9  // foo", Preview: []SearchPreviewLine{{Number: 9, Text: "// foo preview", Match: true}}},
+	}, "2 matches")
+	p.Update(tea.KeyPressMsg{Code: tea.KeyDown})
+	view := ansi.Strip(p.View())
+	if !strings.Contains(view, "2 matches") || !strings.Contains(view, "9 │ // foo preview") {
+		t.Fatalf("view does not show the status and the selected match's preview:\n%s", view)
+	}
+
+	_, cmd = p.Update(tea.KeyPressMsg{Code: tea.KeyEnter})
+	if result := findSearchMsg[SearchPanelResult](t, cmd); result.Action != SearchPanelOpen || result.Index != 1 || p.Visible() {
+		t.Fatalf("enter = %+v, visible %v; want the second match opened", result, p.Visible())
+	}
+
+	p.Show()
+	_, cmd = p.Update(tea.KeyPressMsg{Code: 's', Mod: tea.ModCtrl})
+	if result := findSearchMsg[SearchPanelResult](t, cmd); result.Action != SearchPanelSend {
+		t.Fatalf("ctrl+s = %+v", result)
+	}
+	p.Show()
+	_, cmd = p.Update(tea.KeyPressMsg{Code: tea.KeyEscape})
+	if result := findSearchMsg[SearchPanelResult](t, cmd); result.Action != SearchPanelClosed {
+		t.Fatalf("esc = %+v", result)
+	}
+
+	p.Show()
+	p.Update(tea.KeyPressMsg{Code: tea.KeyDown})
+	p.ReplaceResults([]SearchPanelMatch{{Label: "a"}, {Label: "b"}, {Label: "c"}}, "3 matches")
+	if p.Selected() != 1 {
+		t.Fatalf("selected %d after the list grew, want the selection kept", p.Selected())
+	}
+	p.ReplaceResults([]SearchPanelMatch{{Label: "a"}}, "1 match")
+	if p.Selected() != 0 {
+		t.Fatalf("selected %d after the list shrank, want 0", p.Selected())
+	}
+}
+
+// findSearchMsg runs cmd, a command or a batch of them, and returns the first
+// message of type T.
+func findSearchMsg[T any](t *testing.T, cmd tea.Cmd) T {
+	t.Helper()
+	var zero T
+	if cmd == nil {
+		t.Fatalf("expected a %T", zero)
+	}
+	switch msg := cmd().(type) {
+	case T:
+		return msg
+	case tea.BatchMsg:
+		for _, c := range msg {
+			if c == nil {
+				continue
+			}
+			if m, ok := c().(T); ok {
+				return m
+			}
+		}
+	}
+	t.Fatalf("no %T produced", zero)
+	return zero
+}
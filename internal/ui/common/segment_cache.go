@@ -0,0 +1,40 @@
+package common
+
+// SegmentCache memoizes one pre-rendered chrome segment (a tab bar or help
+// bar) keyed by a caller-built string of every input that shapes it. Building
+// the key is cheap; the lipgloss render it guards is not, and chrome is
+// re-requested every frame while it rarely changes. Unlike the version gates
+// in internal/app, the key carries its own inputs, so a missed invalidation
+// cannot serve a stale segment.
+type SegmentCache[T any] struct {
+	key    string
+	value  T
+	valid  bool
+	builds uint64
+}
+
+// Get returns the cached value when key matches the last build, otherwise
+// calls build and caches its result under key.
+func (c *SegmentCache[T]) Get(key string, build func() T) T {
+	if c.valid && c.key == key {
+		return c.value
+	}
+	c.builds++
+	c.key = key
+	c.value = build()
+	c.valid = true
+	return c.value
+}
+
+// Invalidate forces the next Get to rebuild.
+func (c *SegmentCache[T]) Invalidate() {
+	var zero T
+	c.value = zero
+	c.valid = false
+}
+
+// Builds reports how many times Get rebuilt the segment. Test instrumentation;
+// not for production use.
+func (c *SegmentCache[T]) Builds() uint64 {
+	return c.builds
+}
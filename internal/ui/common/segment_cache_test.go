@@ -0,0 +1,31 @@
+package common
+
+import "testing"
+
+func TestSegmentCacheRebuildsOnlyOnKeyChange(t *testing.T) {
+	var c SegmentCache[string]
+	calls := 0
+	build := func(v string) func() string {
+		return func() string {
+			calls++
+			return v
+		}
+	}
+
+	if got := c.Get("a", build("one")); got != "one" {
+		t.Fatalf("first get = %q, want one", got)
+	}
+	if got := c.Get("a", build("two")); got != "one" {
+		t.Fatalf("same key get = %q, want cached one", got)
+	}
+	if got := c.Get("b", build("three")); got != "three" {
+		t.Fatalf("new key get = %q, want three", got)
+	}
+	c.Invalidate()
+	if got := c.Get("b", build("four")); got != "four" {
+		t.Fatalf("get after invalidate = %q, want four", got)
+	}
+	if calls != 3 || c.Builds() != 3 {
+		t.Fatalf("builds = %d (calls %d), want 3", c.Builds(), calls)
+	}
+}
@@ -31,6 +31,7 @@ const (
 	settingsItemTmuxServer
 	settingsItemTmuxConfig
 	settingsItemTmuxSync
+	settingsItemLatency // only shown when latency profiles are set
 	settingsItemAssistants
 	settingsItemUpdate // only shown when update available
 	settingsItemClose
@@ -59,6 +60,11 @@ type SettingsDialog struct {
 	tmuxConfigPath   string
 	tmuxSyncInterval string
 
+	// Latency profile names in display order (set via SetLatencyProfiles);
+	// latencyCursor is the selected one. Switching previews live.
+	latencyProfiles []string
+	latencyCursor   int
+
 	// Assistant roster values. assistantNames is the fixed, ordered display
 	// list for the dialog's lifetime (set via SetAssistants); assistantCommands
 	// holds the (possibly edited) command string per assistant name, persisted
@@ -303,6 +309,9 @@ func (s *SettingsDialog) handleSelect() (*SettingsDialog, tea.Cmd) {
 		}
 		return s, func() tea.Msg { return ThemePreview{Theme: s.theme, Session: s.session} }
 
+	case settingsItemLatency:
+		return s, s.moveLatencyCursor(0)
+
 	case settingsItemUpdate:
 		if s.updateAvailable {
 			s.visible = false
@@ -319,6 +328,9 @@ func (s *SettingsDialog) handleSelect() (*SettingsDialog, tea.Cmd) {
 // handleNextSection moves focus to the next section (Tab key).
 func (s *SettingsDialog) handleNextSection() (*SettingsDialog, tea.Cmd) {
 	s.focusedItem++
+	if s.focusedItem == settingsItemLatency && len(s.latencyProfiles) == 0 {
+		s.focusedItem++
+	}
 	// Skip update item if no update available
 	if s.focusedItem == settingsItemUpdate && !s.updateAvailable {
 		s.focusedItem = settingsItemClose
@@ -336,6 +348,9 @@ func (s *SettingsDialog) handlePrevSection() (*SettingsDialog, tea.Cmd) {
 	if s.focusedItem == settingsItemUpdate && !s.updateAvailable {
 		s.focusedItem = settingsItemAssistants
 	}
+	if s.focusedItem == settingsItemLatency && len(s.latencyProfiles) == 0 {
+		s.focusedItem--
+	}
 	if s.focusedItem < 0 {
 		s.focusedItem = settingsItemClose
 	}
@@ -350,6 +365,9 @@ func (s *SettingsDialog) handleNext() (*SettingsDialog, tea.Cmd) {
 		s.theme = s.themes[s.themeCursor].ID
 		return s, func() tea.Msg { return ThemePreview{Theme: s.theme, Session: s.session} }
 	}
+	if s.focusedItem == settingsItemLatency {
+		return s, s.moveLatencyCursor(1)
+	}
 	return s.handleNextSection()
 }
 
@@ -364,6 +382,9 @@ func (s *SettingsDialog) handlePrev() (*SettingsDialog, tea.Cmd) {
 		s.theme = s.themes[s.themeCursor].ID
 		return s, func() tea.Msg { return ThemePreview{Theme: s.theme, Session: s.session} }
 	}
+	if s.focusedItem == settingsItemLatency {
+		return s, s.moveLatencyCursor(-1)
+	}
 	return s.handlePrevSection()
 }
 
@@ -397,6 +418,9 @@ func (s *SettingsDialog) handleClick(msg tea.MouseClickMsg) tea.Cmd {
 			if hit.item == settingsItemTheme && hit.index >= 0 {
 				s.themeCursor = hit.index
 			}
+			if hit.item == settingsItemLatency && hit.index >= 0 {
+				s.latencyCursor = hit.index
+			}
 			if hit.item == settingsItemAssistants && hit.index >= 0 {
 				s.assistantCursor = hit.index
 			}
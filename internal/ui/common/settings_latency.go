@@ -0,0 +1,46 @@
+package common
+
+import tea "charm.land/bubbletea/v2"
+
+// LatencyPreview is sent when the user moves through latency profiles so the
+// app can apply the selection live.
+type LatencyPreview struct {
+	Profile string
+	Session int
+}
+
+// SetLatencyProfiles sets the latency profile names the Latency section lists
+// and selects current. Like SetAssistants it is populated after construction;
+// without it the section is hidden and skipped by navigation.
+func (s *SettingsDialog) SetLatencyProfiles(names []string, current string) {
+	s.latencyProfiles = names
+	s.latencyCursor = 0
+	for i, name := range names {
+		if name == current {
+			s.latencyCursor = i
+			break
+		}
+	}
+}
+
+// SelectedLatencyProfile returns the highlighted latency profile, or "" when
+// the section is not shown.
+func (s *SettingsDialog) SelectedLatencyProfile() string {
+	if s.latencyCursor < 0 || s.latencyCursor >= len(s.latencyProfiles) {
+		return ""
+	}
+	return s.latencyProfiles[s.latencyCursor]
+}
+
+// moveLatencyCursor cycles the latency selection by delta (0 re-emits the
+// current selection) and returns its live preview.
+func (s *SettingsDialog) moveLatencyCursor(delta int) tea.Cmd {
+	n := len(s.latencyProfiles)
+	if n == 0 {
+		return nil
+	}
+	s.latencyCursor = ((s.latencyCursor+delta)%n + n) % n
+	profile := s.latencyProfiles[s.latencyCursor]
+	session := s.session
+	return func() tea.Msg { return LatencyPreview{Profile: profile, Session: session} }
+}
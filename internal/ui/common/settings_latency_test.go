@@ -0,0 +1,53 @@
+package common
+
+import (
+	"testing"
+
+	tea "charm.land/bubbletea/v2"
+)
+
+func TestSettingsDialogLatencyCyclesAndPreviews(t *testing.T) {
+	d := NewSettingsDialog(ThemeAyuDark, "", "", "")
+	d.SetLatencyProfiles([]string{"snappy", "balanced", "battery"}, "balanced")
+	d.Show()
+
+	// Tab from Theme through the three tmux fields lands on Latency.
+	for range 4 {
+		d.Update(tea.KeyPressMsg{Code: tea.KeyTab})
+	}
+	if d.focusedItem != settingsItemLatency {
+		t.Fatalf("focusedItem = %d, want settingsItemLatency", d.focusedItem)
+	}
+
+	_, cmd := d.handleNext()
+	if cmd == nil {
+		t.Fatal("expected a latency preview")
+	}
+	preview, ok := cmd().(LatencyPreview)
+	if !ok || preview.Profile != "battery" {
+		t.Fatalf("preview = %#v, want battery", cmd())
+	}
+
+	// Wraps from the last profile back to the first.
+	_, _ = d.handleNext()
+	if got := d.SelectedLatencyProfile(); got != "snappy" {
+		t.Fatalf("selected = %q after wrap, want snappy", got)
+	}
+}
+
+func TestSettingsDialogLatencySkippedWithoutProfiles(t *testing.T) {
+	d := NewSettingsDialog(ThemeAyuDark, "", "", "")
+	d.focusedItem = settingsItemTmuxSync
+
+	_, _ = d.handleNextSection()
+	if d.focusedItem != settingsItemAssistants {
+		t.Fatalf("focusedItem = %d, want settingsItemAssistants", d.focusedItem)
+	}
+	_, _ = d.handlePrevSection()
+	if d.focusedItem != settingsItemTmuxSync {
+		t.Fatalf("focusedItem = %d, want settingsItemTmuxSync", d.focusedItem)
+	}
+	if got := d.SelectedLatencyProfile(); got != "" {
+		t.Fatalf("selected = %q, want empty without profiles", got)
+	}
+}
@@ -67,6 +67,22 @@ func (s *SettingsDialog) renderLines() []string {
 	}
 	lines = append(lines, "")
 
+	// Latency section: applies live, so no restart hint.
+	if len(s.latencyProfiles) > 0 {
+		lines = append(lines, label.Render("Latency"))
+		for i, name := range s.latencyProfiles {
+			style, prefix := muted, "  "
+			if i == s.latencyCursor {
+				style = lipgloss.NewStyle().Foreground(ColorPrimary()).Bold(true)
+				prefix = Icons.Cursor + " "
+			}
+			y := len(lines)
+			lines = append(lines, prefix+style.Render(name))
+			s.addHit(settingsItemLatency, i, y)
+		}
+		lines = append(lines, "")
+	}
+
 	// Assistants section: one row per roster entry (name + editable command).
 	// Only rendered when a roster was set via SetAssistants (production always
 	// sets one; dialogs built directly in tests without it simply show no
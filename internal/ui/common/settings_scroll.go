@@ -85,7 +85,7 @@ func (s *SettingsDialog) clampScrollOffset(fullHits []settingsHitRegion, bodyLen
 		return 0
 	}
 
-	if idx := focusedBodyIndex(fullHits, s.focusedItem, s.themeCursor, s.assistantCursor); idx >= 0 {
+	if idx := focusedBodyIndex(fullHits, s.focusedItem, s.focusedRowCursor()); idx >= 0 {
 		switch {
 		case idx < s.scrollOffset:
 			s.scrollOffset = idx
@@ -108,20 +108,18 @@ func (s *SettingsDialog) clampScrollOffset(fullHits []settingsHitRegion, bodyLen
 // already records for every focusable row. It returns -1 when the focused
 // item has no body row (settingsItemClose, rendered in the fixed footer).
 //
-// settingsItemTheme and settingsItemAssistants each pack multiple rows under
-// one settingsItem (themeCursor / assistantCursor selects which), so a
-// matching item alone is not enough to identify the row -- both cursors must
-// also be checked, or focus would always resolve to the first hit recorded
-// for that item regardless of which row is actually selected.
-func focusedBodyIndex(hits []settingsHitRegion, focused settingsItem, themeCursor, assistantCursor int) int {
+// settingsItemTheme, settingsItemLatency and settingsItemAssistants each pack
+// multiple rows under one settingsItem (a per-section cursor selects which),
+// so a matching item alone is not enough to identify the row -- the cursor
+// must also be checked, or focus would always resolve to the first hit
+// recorded for that item regardless of which row is actually selected.
+// Single-row items pass a negative cursor.
+func focusedBodyIndex(hits []settingsHitRegion, focused settingsItem, cursor int) int {
 	for _, h := range hits {
 		if h.item != focused {
 			continue
 		}
-		if focused == settingsItemTheme && h.index != themeCursor {
-			continue
-		}
-		if focused == settingsItemAssistants && h.index != assistantCursor {
+		if cursor >= 0 && h.index != cursor {
 			continue
 		}
 		return h.region.Y - settingsHeaderLines
@@ -129,6 +127,20 @@ func focusedBodyIndex(hits []settingsHitRegion, focused settingsItem, themeCurso
 	return -1
 }
 
+// focusedRowCursor returns the row cursor of the focused multi-row section,
+// or -1 when the focused item is a single row.
+func (s *SettingsDialog) focusedRowCursor() int {
+	switch s.focusedItem {
+	case settingsItemTheme:
+		return s.themeCursor
+	case settingsItemLatency:
+		return s.latencyCursor
+	case settingsItemAssistants:
+		return s.assistantCursor
+	}
+	return -1
+}
+
 // remapHitRegions translates hit regions from renderLines' full, unclamped
 // coordinates into the coordinates of the composed/visible lines, dropping
 // any row currently scrolled out of the body window.
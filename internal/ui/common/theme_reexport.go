@@ -58,6 +58,8 @@ var (
 	HexColor           = theme.HexColor
 	SetCurrentTheme    = theme.SetCurrentTheme
 	SpinnerFrame       = theme.SpinnerFrame
+	ReducedMotion      = theme.ReducedMotion
+	SetReducedMotion   = theme.SetReducedMotion
 	Icons              = theme.Icons
 	ColorClaude        = theme.ColorClaude
 	ColorCodex         = theme.ColorCodex
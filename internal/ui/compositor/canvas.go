@@ -89,11 +89,14 @@ type CursorState struct {
 	Visible bool
 }
 
-// SelectionRegion holds selection bounds for DrawScreen.
+// SelectionRegion holds selection bounds for DrawScreen, plus the visible
+// search matches highlighted alongside them.
 type SelectionRegion struct {
 	Active         bool
+	Rect           bool
 	StartX, StartY int
 	EndX, EndY     int
+	Search         []vterm.SearchSpan
 }
 
 // DrawScreen draws a vterm screen into the canvas with clipping.
@@ -104,12 +107,12 @@ func (c *Canvas) DrawScreen(x, y, w, h int, screen [][]vterm.Cell, cursor Cursor
 	// Normalize the selection bounds once instead of per cell: the start/end
 	// ordering is identical for every cell, so recomputing it in the per-cell
 	// loop is pure redundancy.
-	selActive := selection.Active
-	var selStartX, selStartY, selEndX, selEndY int
-	if selActive {
-		selStartX, selStartY, selEndX, selEndY = vterm.NormalizeSelectionRange(
-			selection.StartX, selection.StartY, selection.EndX, selection.EndY)
+	var sel vterm.SelectionBounds
+	if selection.Active {
+		sel = vterm.NewSelectionBounds(selection.StartX, selection.StartY, selection.EndX, selection.EndY, selection.Rect)
 	}
+	search := vterm.NewSearchHighlighter(selection.Search)
+	cursorOn := cursor.Visible && viewOffset == 0
 	maxY := min(h, len(screen))
 	for row := 0; row < maxY; row++ {
 		line := screen[row]
@@ -119,9 +122,9 @@ func (c *Canvas) DrawScreen(x, y, w, h int, screen [][]vterm.Cell, cursor Cursor
 			if cell.Width == 2 && col+1 >= w {
 				cell = vterm.DefaultCell()
 			}
-			if selActive && vterm.SelectionContains(selStartX, selStartY, selEndX, selEndY, col, row) {
-				cell.Style.Reverse = !cell.Style.Reverse
-			}
+			cursorHere := cursorOn && col == cursor.X && row == cursor.Y
+			cell.Style = search.Style(cell.Style, col, row)
+			cell.Style = vterm.HighlightStyle(cell.Style, sel.ContainsCell(cell, col, row), cursorHere)
 			targetX := x + col
 			targetY := y + row
 			if targetX < 0 || targetY < 0 || targetX >= c.Width || targetY >= c.Height {
@@ -131,15 +134,16 @@ func (c *Canvas) DrawScreen(x, y, w, h int, screen [][]vterm.Cell, cursor Cursor
 		}
 	}
 
-	if cursor.Visible && viewOffset == 0 {
-		if cursor.X >= 0 && cursor.Y >= 0 && cursor.X < w && cursor.Y < h {
-			targetX := x + cursor.X
-			targetY := y + cursor.Y
-			if targetX >= 0 && targetX < c.Width && targetY >= 0 && targetY < c.Height {
-				cell := c.Cells[targetY][targetX]
-				cell.Style.Reverse = !cell.Style.Reverse
-				c.Cells[targetY][targetX] = cell
-			}
+	// A cursor past the end of its row's cells sits on the fill and was not
+	// drawn above.
+	if cursorOn && cursor.X >= 0 && cursor.Y >= 0 && cursor.X < w && cursor.Y < h &&
+		(cursor.Y >= maxY || cursor.X >= len(screen[cursor.Y])) {
+		targetX := x + cursor.X
+		targetY := y + cursor.Y
+		if targetX >= 0 && targetX < c.Width && targetY >= 0 && targetY < c.Height {
+			cell := c.Cells[targetY][targetX]
+			cell.Style.Reverse = !cell.Style.Reverse
+			c.Cells[targetY][targetX] = cell
 		}
 	}
 }
@@ -228,6 +232,8 @@ func RenderSnapshotWithCanvas(canvas *Canvas, snap *VTermSnapshot, width, height
 			StartY: snap.SelStartY,
 			EndX:   snap.SelEndX,
 			EndY:   snap.SelEndY,
+			Rect:   snap.SelRect,
+			Search: snap.SearchSpans,
 		},
 	)
 	return canvas.Render()
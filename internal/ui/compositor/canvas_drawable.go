@@ -5,6 +5,8 @@ import (
 
 	uv "github.com/charmbracelet/ultraviolet"
 	"github.com/charmbracelet/x/ansi"
+
+	"github.com/andyrewlee/amux/internal/ui/theme"
 )
 
 // parsedCell is a single decoded grapheme from a StringDrawable's content,
@@ -149,7 +151,9 @@ func applySGR(style uv.Style, params ansi.Params) uv.Style {
 		case p == 4:
 			style.Underline = uv.UnderlineSingle
 		case p == 5:
-			style.Attrs |= uv.AttrBlink
+			if !theme.ReducedMotion() {
+				style.Attrs |= uv.AttrBlink
+			}
 		case p == 7:
 			style.Attrs |= uv.AttrReverse
 		case p == 8:
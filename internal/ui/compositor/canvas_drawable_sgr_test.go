@@ -5,6 +5,8 @@ import (
 
 	uv "github.com/charmbracelet/ultraviolet"
 	"github.com/charmbracelet/x/ansi"
+
+	"github.com/andyrewlee/amux/internal/ui/theme"
 )
 
 // TestApplySGR exercises applySGR directly across every SGR family it handles:
@@ -362,3 +364,16 @@ func TestApplySGR(t *testing.T) {
 		})
 	}
 }
+
+func TestApplySGRDropsBlinkUnderReducedMotion(t *testing.T) {
+	theme.SetReducedMotion(true)
+	t.Cleanup(func() { theme.SetReducedMotion(false) })
+
+	got := applySGR(uv.Style{}, ansi.Params{ansi.Param(1), ansi.Param(5)})
+	if got.Attrs&uv.AttrBlink != 0 || got.Attrs&uv.AttrBold == 0 {
+		t.Fatalf("attrs = %d, want bold without blink", got.Attrs)
+	}
+	if frame := theme.SpinnerFrame(3); frame != theme.SpinnerFrame(4) {
+		t.Fatalf("spinner frames %q and %q differ under reduced motion", frame, theme.SpinnerFrame(4))
+	}
+}
@@ -0,0 +1,160 @@
+package compositor
+
+import (
+	"strings"
+	"testing"
+
+	uv "github.com/charmbracelet/ultraviolet"
+
+	"github.com/andyrewlee/amux/internal/vterm"
+)
+
+// Highlight classes compared across render paths.
+const (
+	hlNone = iota
+	hlSelected
+	hlMatch
+	hlCurrent
+)
+
+// layerHighlights draws the snapshot through VTermLayer and classifies each
+// visible glyph.
+func layerHighlights(t *testing.T, snap *VTermSnapshot, w, h int) [][]int {
+	t.Helper()
+	screen := &bufferScreen{Buffer: uv.NewBuffer(w, h)}
+	NewVTermLayer(snap).Draw(screen, screen.Bounds())
+	match := vtermColorToUV(vterm.SearchMatchBg)
+	current := vtermColorToUV(vterm.SearchCurrentBg)
+	out := make([][]int, h)
+	for y := range out {
+		out[y] = make([]int, w)
+		for x := 0; x < w; x++ {
+			cell := screen.CellAt(x, y)
+			switch {
+			case cell == nil || cell.Width == 0:
+			case cell.Style.Bg == current:
+				out[y][x] = hlCurrent
+			case cell.Style.Bg == match:
+				out[y][x] = hlMatch
+			case cell.Style.Attrs&uv.AttrReverse != 0:
+				out[y][x] = hlSelected
+			}
+		}
+	}
+	return out
+}
+
+// parsedHighlights replays an ANSI frame into a fresh terminal and
+// classifies each visible glyph, so string render paths can be compared with
+// the layer path cell for cell.
+func parsedHighlights(frame string, w, h int) [][]int {
+	vt := vterm.New(w, h)
+	vt.Write([]byte(strings.ReplaceAll(frame, "\n", "\r\n")))
+	out := make([][]int, h)
+	for y := range out {
+		out[y] = make([]int, w)
+		for x, cell := range vt.Screen[y] {
+			switch {
+			case cell.Width == 0:
+			case cell.Style.Bg == vterm.SearchCurrentBg:
+				out[y][x] = hlCurrent
+			case cell.Style.Bg == vterm.SearchMatchBg:
+				out[y][x] = hlMatch
+			case cell.Style.Reverse:
+				out[y][x] = hlSelected
+			}
+		}
+	}
+	return out
+}
+
+func diffHighlights(t *testing.T, path string, got, want [][]int) {
+	t.Helper()
+	for y := range want {
+		for x := range want[y] {
+			if got[y][x] != want[y][x] {
+				t.Errorf("%s at (%d,%d): class %d, want %d", path, x, y, got[y][x], want[y][x])
+			}
+		}
+	}
+}
+
+// TestHighlightParityAcrossRenderPaths renders the same terminal state
+// through the live string renderer, the VTermLayer snapshot path, and the
+// canvas fallback, and requires all three to highlight the same cells.
+func TestHighlightParityAcrossRenderPaths(t *testing.T) {
+	const w, h = 8, 3
+	tests := []struct {
+		name   string
+		output string
+		setup  func(vt *vterm.VTerm)
+		want   []string // per row: . none, s selected, m match, c current
+	}{
+		{
+			name:   "linear selection",
+			output: "abcdefgh\r\nijklmnop\r\nqrstuvwx",
+			setup: func(vt *vterm.VTerm) {
+				vt.SetSelection(5, 0, 2, 1, true, false)
+			},
+			want: []string{".....sss", "sss.....", "........"},
+		},
+		{
+			name:   "block selection",
+			output: "abcdefgh\r\nijklmnop\r\nqrstuvwx",
+			setup: func(vt *vterm.VTerm) {
+				vt.SetSelection(5, 0, 2, 1, true, true)
+			},
+			want: []string{"..ssss..", "..ssss..", "........"},
+		},
+		{
+			name:   "selection edge on second column of wide char",
+			output: "ab中文xy",
+			setup: func(vt *vterm.VTerm) {
+				vt.SetSelection(3, 0, 4, 0, true, false)
+			},
+			want: []string{"..s.s...", "........", "........"},
+		},
+		{
+			name:   "scrolled view with selection starting above it",
+			output: "00000000\r\n11111111\r\n22222222\r\n33333333\r\n44444444",
+			setup: func(vt *vterm.VTerm) {
+				vt.SetSelection(4, 0, 3, 2, true, false)
+				vt.ScrollViewTo(1)
+			},
+			want: []string{"ssssssss", "ssss....", "........"},
+		},
+		{
+			name:   "search matches and selection",
+			output: "ab ab ab",
+			setup: func(vt *vterm.VTerm) {
+				vt.SetSearch("ab")
+				vt.SetSelection(2, 0, 2, 0, true, false)
+			},
+			want: []string{"mmsmm.cc", "........", "........"},
+		},
+	}
+
+	classes := map[byte]int{'.': hlNone, 's': hlSelected, 'm': hlMatch, 'c': hlCurrent}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			vt := vterm.New(w, h)
+			vt.ShowCursor = false
+			vt.Write([]byte(tt.output))
+			tt.setup(vt)
+
+			want := make([][]int, h)
+			for y, row := range tt.want {
+				want[y] = make([]int, w)
+				for x := 0; x < w; x++ {
+					want[y][x] = classes[row[x]]
+				}
+			}
+
+			snap := NewVTermSnapshot(vt, false)
+			diffHighlights(t, "layer", layerHighlights(t, snap, w, h), want)
+			diffHighlights(t, "canvas", parsedHighlights(
+				RenderSnapshotWithCanvas(nil, snap, w, h, vterm.Color{}, vterm.Color{}), w, h), want)
+			diffHighlights(t, "render", parsedHighlights(vt.Render(), w, h), want)
+		})
+	}
+}
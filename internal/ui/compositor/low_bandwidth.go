@@ -0,0 +1,57 @@
+package compositor
+
+import (
+	"image/color"
+	"sync"
+	"sync/atomic"
+
+	"github.com/charmbracelet/x/ansi"
+
+	"github.com/andyrewlee/amux/internal/vterm"
+)
+
+var lowBandwidth atomic.Bool
+
+// SetLowBandwidth makes terminal layers draw with fewer style changes:
+// truecolor is reduced to the 256-color palette, and blank cells take the
+// foreground of the cell before them, since a foreground is invisible on a
+// space. Both cut the SGR sequences sent to the terminal on every frame.
+func SetLowBandwidth(on bool) {
+	lowBandwidth.Store(on)
+}
+
+// LowBandwidth reports whether terminal layers draw with fewer style changes.
+func LowBandwidth() bool {
+	return lowBandwidth.Load()
+}
+
+// collapseStyle returns cell's style for low-bandwidth drawing, given the
+// style drawn in the cell before it on the row.
+func collapseStyle(cell vterm.Cell, prev vterm.Style) vterm.Style {
+	style := cell.Style
+	style.Fg = quantizeColor(style.Fg)
+	style.Bg = quantizeColor(style.Bg)
+	blank := cell.Rune == 0 || cell.Rune == ' '
+	if blank && cell.GraphemeCluster == "" && !style.Underline && !style.Reverse && !style.Strike {
+		style.Fg = prev.Fg
+		style.Bold, style.Dim, style.Italic, style.Blink = prev.Bold, prev.Dim, prev.Italic, prev.Blink
+	}
+	return style
+}
+
+// quantizedColors memoizes the palette index for each 24-bit color.
+var quantizedColors sync.Map // uint32 -> uint32
+
+// quantizeColor maps an RGB color to the nearest 256-color palette entry.
+func quantizeColor(c vterm.Color) vterm.Color {
+	if c.Type != vterm.ColorRGB {
+		return c
+	}
+	if idx, ok := quantizedColors.Load(c.Value); ok {
+		return vterm.Color{Type: vterm.ColorIndexed, Value: idx.(uint32)}
+	}
+	rgb := color.RGBA{R: uint8(c.Value >> 16), G: uint8(c.Value >> 8), B: uint8(c.Value), A: 0xff}
+	idx := uint32(ansi.Convert256(rgb))
+	quantizedColors.Store(c.Value, idx)
+	return vterm.Color{Type: vterm.ColorIndexed, Value: idx}
+}
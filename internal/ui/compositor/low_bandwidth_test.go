@@ -0,0 +1,30 @@
+package compositor
+
+import (
+	"testing"
+
+	"github.com/andyrewlee/amux/internal/vterm"
+)
+
+func TestCollapseStyleQuantizesAndReusesBlankForeground(t *testing.T) {
+	red := vterm.Color{Type: vterm.ColorRGB, Value: 0xff0000}
+	prev := vterm.Style{Fg: vterm.Color{Type: vterm.ColorIndexed, Value: 196}, Bold: true}
+
+	got := collapseStyle(vterm.Cell{Rune: 'x', Style: vterm.Style{Fg: red}}, vterm.Style{})
+	if got.Fg != (vterm.Color{Type: vterm.ColorIndexed, Value: 196}) {
+		t.Fatalf("Fg = %+v, want palette red", got.Fg)
+	}
+
+	// A space keeps the previous cell's foreground so no SGR is needed.
+	blank := vterm.Cell{Rune: ' ', Style: vterm.Style{Bg: red}}
+	got = collapseStyle(blank, prev)
+	if got.Fg != prev.Fg || !got.Bold || got.Bg.Type != vterm.ColorIndexed {
+		t.Fatalf("blank style = %+v, want previous fg and bold with quantized bg", got)
+	}
+
+	// Underlined and reversed spaces show their foreground.
+	blank.Style.Underline = true
+	if got = collapseStyle(blank, prev); got.Fg == prev.Fg || got.Bold {
+		t.Fatalf("underlined blank took the previous style: %+v", got)
+	}
+}
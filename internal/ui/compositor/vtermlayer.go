@@ -6,6 +6,7 @@ import (
 
 	uv "github.com/charmbracelet/ultraviolet"
 
+	"github.com/andyrewlee/amux/internal/ui/theme"
 	"github.com/andyrewlee/amux/internal/vterm"
 )
 
@@ -84,12 +85,7 @@ func (l *VTermLayer) DrawAt(s uv.Screen, posX, posY, maxWidth, maxHeight int) {
 	// Normalize the selection bounds once per frame instead of per cell: the
 	// start/end ordering is identical for every cell, so recomputing it in the
 	// per-cell loop is pure redundancy.
-	selActive := snap.SelActive
-	var selStartX, selStartY, selEndX, selEndY int
-	if selActive {
-		selStartX, selStartY, selEndX, selEndY = vterm.NormalizeSelectionRange(
-			snap.SelStartX, snap.SelStartY, snap.SelEndX, snap.SelEndY)
-	}
+	sel := snap.Selection()
 
 	// When compositing layers, we must draw ALL cells every frame.
 	// The dirty line optimization only works for single-layer rendering.
@@ -99,11 +95,14 @@ func (l *VTermLayer) DrawAt(s uv.Screen, posX, posY, maxWidth, maxHeight int) {
 	// so a single local cell can be reused across every iteration instead of
 	// renting one from a sync.Pool per cell per frame.
 	var uvCell uv.Cell
+	search := vterm.NewSearchHighlighter(snap.SearchSpans)
+	collapse := LowBandwidth()
 	for y := 0; y < height && y < len(snap.Screen); y++ {
 		row := snap.Screen[y]
 		if row == nil {
 			continue
 		}
+		var prev vterm.Style
 
 		for x := 0; x < width && x < len(row); x++ {
 			cell := row[x]
@@ -124,8 +123,12 @@ func (l *VTermLayer) DrawAt(s uv.Screen, posX, posY, maxWidth, maxHeight int) {
 			}
 
 			// Build the ultraviolet cell into the reused local.
-			inSel := selActive && vterm.SelectionContains(
-				selStartX, selStartY, selEndX, selEndY, x, y)
+			inSel := sel.ContainsCell(cell, x, y)
+			if collapse {
+				cell.Style = collapseStyle(cell, prev)
+				prev = cell.Style
+			}
+			cell.Style = search.Style(cell.Style, x, y)
 			cellToUVSnapshot(&uvCell, cell, snap, x, y, inSel)
 
 			// Set cell at screen position (ultraviolet copies the value).
@@ -144,14 +147,12 @@ func cellToUVSnapshot(uvCell *uv.Cell, cell vterm.Cell, snap *VTermSnapshot, x,
 	// Apply selection and cursor reverse (selection has precedence over cursor)
 	cursorHere := snap.ShowCursor && !snap.CursorHidden &&
 		y == snap.CursorY && x == snap.CursorX && snap.ViewOffset == 0
-	if inSel || cursorHere {
-		style.Reverse = !style.Reverse
-	}
+	style = vterm.HighlightStyle(style, inSel, cursorHere)
 
 	// Suppress underline on blank cells (prevents visual scanlines)
 	style = vterm.SuppressBlankUnderline(cell.Rune, style)
 
-	if snap.SuppressBlink {
+	if snap.SuppressBlink || theme.ReducedMotion() {
 		style.Blink = false
 	}
 
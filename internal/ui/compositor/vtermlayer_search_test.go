@@ -0,0 +1,45 @@
+package compositor
+
+import (
+	"image/color"
+	"testing"
+
+	uv "github.com/charmbracelet/ultraviolet"
+
+	"github.com/andyrewlee/amux/internal/vterm"
+)
+
+func TestVTermLayerDrawsSearchHighlights(t *testing.T) {
+	term := vterm.New(12, 1)
+	term.Write([]byte("ab cd ab"))
+	term.SetSearch("ab")
+
+	snap := NewVTermSnapshot(term, false)
+	screen := &bufferScreen{Buffer: uv.NewBuffer(12, 1)}
+	NewVTermLayer(snap).Draw(screen, screen.Bounds())
+
+	match := vtermColorToUV(vterm.SearchMatchBg)
+	current := vtermColorToUV(vterm.SearchCurrentBg)
+	for x := 0; x < 12; x++ {
+		var want color.Color
+		switch {
+		case x <= 1:
+			want = match
+		case x >= 6 && x <= 7:
+			want = current
+		}
+		got := screen.CellAt(x, 0).Style.Bg
+		if want == nil {
+			if got != nil {
+				t.Errorf("cell %d: unexpected background %v", x, got)
+			}
+			continue
+		}
+		if got != want {
+			t.Errorf("cell %d: background %v, want %v", x, got, want)
+		}
+	}
+	if screen.CellAt(6, 0).Style.Attrs&uv.AttrBold == 0 {
+		t.Error("current match should be bold")
+	}
+}
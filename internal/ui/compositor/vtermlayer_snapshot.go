@@ -19,10 +19,31 @@ type VTermSnapshot struct {
 	SuppressBlink bool
 	Width         int
 	Height        int
-	// Selection state (used during rendering)
+	// Selection state (used during rendering), in viewport rows. SelRect
+	// marks a block selection.
 	SelActive            bool
+	SelRect              bool
 	SelStartX, SelStartY int
 	SelEndX, SelEndY     int
+	// SearchSpans are the visible search matches, in row then column order.
+	SearchSpans []vterm.SearchSpan
+}
+
+// Selection returns the snapshot's selection bounds.
+func (s *VTermSnapshot) Selection() vterm.SelectionBounds {
+	if !s.SelActive {
+		return vterm.SelectionBounds{}
+	}
+	return vterm.NewSelectionBounds(s.SelStartX, s.SelStartY, s.SelEndX, s.SelEndY, s.SelRect)
+}
+
+// SetSelection stores viewport-relative selection bounds (see
+// vterm.VTerm.ViewSelection) for rendering.
+func (s *VTermSnapshot) SetSelection(b vterm.SelectionBounds) {
+	s.SelActive = b.Active
+	s.SelRect = b.Rect
+	s.SelStartX, s.SelStartY = b.StartX, b.StartY
+	s.SelEndX, s.SelEndY = b.EndX, b.EndY
 }
 
 // NewVTermSnapshot creates a snapshot from a VTerm.
@@ -119,6 +140,7 @@ func newVTermSnapshot(term *vterm.VTerm, showCursor bool, prev *VTermSnapshot, e
 			}
 			if y < len(visible) {
 				copy(line, visible[y])
+				vterm.ReorderVisual(line)
 			} else {
 				for i := range line {
 					line[i] = vterm.DefaultCell()
@@ -137,6 +159,10 @@ func newVTermSnapshot(term *vterm.VTerm, showCursor bool, prev *VTermSnapshot, e
 		if len(screen) == 0 {
 			return nil
 		}
+		// Snapshot rows are display rows: right-to-left runs in visual order.
+		for _, line := range screen {
+			vterm.ReorderVisual(line)
+		}
 	}
 
 	snap := prev
@@ -147,7 +173,7 @@ func newVTermSnapshot(term *vterm.VTerm, showCursor bool, prev *VTermSnapshot, e
 	snap.Screen = screen
 	snap.DirtyLines = dirtyLinesCopy
 	snap.AllDirty = allDirty
-	snap.CursorX = term.CursorX
+	snap.CursorX = term.VisualCursorX()
 	snap.CursorY = term.CursorY
 	snap.ViewOffset = term.ViewOffset
 	snap.CursorHidden = term.CursorHiddenForRender()
@@ -155,59 +181,9 @@ func newVTermSnapshot(term *vterm.VTerm, showCursor bool, prev *VTermSnapshot, e
 	snap.SuppressBlink = false
 	snap.Width = width
 	snap.Height = height
-	snap.SelActive = term.SelActive()
-	snap.SelStartX = 0
-	snap.SelStartY = 0
-	snap.SelEndX = 0
-	snap.SelEndY = 0
-
-	if snap.SelActive {
-		startLine := term.SelStartLine()
-		endLine := term.SelEndLine()
-		startX := term.SelStartX()
-		endX := term.SelEndX()
-
-		// Normalize so start is before end.
-		startX, startLine, endX, endLine = vterm.NormalizeSelectionRange(startX, startLine, endX, endLine)
-
-		visibleStartLine := term.ScreenYToAbsoluteLine(0)
-		visibleEndLine := term.ScreenYToAbsoluteLine(height - 1)
-
-		// If selection is entirely outside viewport, disable selection rendering.
-		if endLine < visibleStartLine || startLine > visibleEndLine {
-			snap.SelActive = false
-		} else {
-			if startLine < visibleStartLine {
-				snap.SelStartY = 0
-				startX = 0
-			} else {
-				snap.SelStartY = startLine - visibleStartLine
-			}
+	snap.SetSelection(term.ViewSelection())
 
-			if endLine > visibleEndLine {
-				snap.SelEndY = height - 1
-				endX = width - 1
-			} else {
-				snap.SelEndY = endLine - visibleStartLine
-			}
-
-			if startX < 0 {
-				startX = 0
-			}
-			if startX >= width {
-				startX = width - 1
-			}
-			if endX < 0 {
-				endX = 0
-			}
-			if endX >= width {
-				endX = width - 1
-			}
-
-			snap.SelStartX = startX
-			snap.SelEndX = endX
-		}
-	}
+	snap.SearchSpans = term.AppendVisibleSearchMatches(snap.SearchSpans[:0])
 
 	// Clear dirty state after snapshotting (while still holding the lock)
 	// Also update cursor tracking for next frame
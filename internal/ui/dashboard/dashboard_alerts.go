@@ -0,0 +1,23 @@
+package dashboard
+
+// SetAlert flags a workspace for attention with a short status label (for
+// example "mem limit"), shown in place of its other status until the user
+// selects the workspace. An empty label clears the alert.
+func (m *Model) SetAlert(wsID, label string) {
+	if wsID == "" {
+		return
+	}
+	if label == "" {
+		delete(m.alerts, wsID)
+		return
+	}
+	if m.alerts == nil {
+		m.alerts = make(map[string]string)
+	}
+	m.alerts[wsID] = label
+}
+
+// Alert returns the workspace's alert label, empty when it has none.
+func (m *Model) Alert(wsID string) string {
+	return m.alerts[wsID]
+}
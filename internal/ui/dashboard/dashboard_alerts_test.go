@@ -0,0 +1,33 @@
+package dashboard
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/andyrewlee/amux/internal/data"
+)
+
+func TestAlertNeedsAttentionUntilSelected(t *testing.T) {
+	m := New()
+	m.SetProjects([]data.Project{makeProject()})
+	row := m.rows[3]
+	wsID := row.ActivityWorkspaceID
+	// An alert shows even while the agent is still working.
+	m.SetActiveWorkspaces(map[string]bool{wsID: true})
+
+	m.SetAlert(wsID, "mem limit")
+	if !m.NeedsAttention(wsID) {
+		t.Fatal("an alert should need attention")
+	}
+	if got := m.renderRow(row, false); !strings.Contains(got, "mem limit") {
+		t.Fatalf("row = %q, want the alert label", got)
+	}
+
+	m.AckDone(wsID)
+	if m.NeedsAttention(wsID) {
+		t.Fatal("selecting the workspace should clear the alert")
+	}
+	if got := m.renderRow(row, false); strings.Contains(got, "mem limit") {
+		t.Fatalf("row = %q, want the alert gone", got)
+	}
+}
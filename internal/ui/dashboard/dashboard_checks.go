@@ -0,0 +1,54 @@
+package dashboard
+
+import (
+	"strings"
+
+	"github.com/andyrewlee/amux/internal/ui/common"
+)
+
+// ChecksBadge is a workspace's build/lint check status, shown after its other
+// status.
+type ChecksBadge struct {
+	Running bool
+	// Failed names the checks that failed in the last run; empty when every
+	// check passed.
+	Failed []string
+}
+
+// SetChecks sets the check badge shown for a workspace. A nil badge clears it.
+func (m *Model) SetChecks(wsID string, badge *ChecksBadge) {
+	if wsID == "" {
+		return
+	}
+	if badge == nil {
+		delete(m.checks, wsID)
+		return
+	}
+	if m.checks == nil {
+		m.checks = make(map[string]ChecksBadge)
+	}
+	m.checks[wsID] = *badge
+}
+
+// checksStatus renders wsID's check badge, or "" when it has none.
+func (m *Model) checksStatus(wsID string) string {
+	badge, ok := m.checks[wsID]
+	switch {
+	case !ok:
+		return ""
+	case badge.Running:
+		return m.styles.StatusRunning.Render("checking")
+	case len(badge.Failed) > 0:
+		return m.styles.StatusDirty.Render("✗ " + strings.Join(badge.Failed, ","))
+	default:
+		return m.styles.StatusClean.Render(common.Icons.Clean)
+	}
+}
+
+// withChecksStatus appends wsID's check badge to a row's status.
+func (m *Model) withChecksStatus(status, wsID string) string {
+	if badge := m.checksStatus(wsID); badge != "" {
+		return status + " " + badge
+	}
+	return status
+}
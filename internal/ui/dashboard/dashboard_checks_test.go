@@ -0,0 +1,32 @@
+package dashboard
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/andyrewlee/amux/internal/data"
+)
+
+func TestChecksBadge(t *testing.T) {
+	m := New()
+	m.SetProjects([]data.Project{makeProject()})
+	row := m.rows[3]
+	wsID := row.ActivityWorkspaceID
+
+	m.SetChecks(wsID, &ChecksBadge{Running: true})
+	if got := m.renderRow(row, false); !strings.Contains(got, "checking") {
+		t.Fatalf("row = %q, want the running badge", got)
+	}
+	m.SetChecks(wsID, &ChecksBadge{Failed: []string{"build", "lint"}})
+	if got := m.renderRow(row, false); !strings.Contains(got, "✗ build,lint") {
+		t.Fatalf("row = %q, want the failed checks", got)
+	}
+	m.SetChecks(wsID, &ChecksBadge{})
+	if got := m.renderRow(row, false); !strings.Contains(got, "✓") {
+		t.Fatalf("row = %q, want the passed badge", got)
+	}
+	m.SetChecks(wsID, nil)
+	if got := m.renderRow(row, false); strings.Contains(got, "✓") {
+		t.Fatalf("row = %q, want the badge cleared", got)
+	}
+}
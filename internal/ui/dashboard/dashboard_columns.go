@@ -0,0 +1,46 @@
+package dashboard
+
+import "github.com/charmbracelet/x/ansi"
+
+// ColumnWidth is the most cells a custom column's value takes in a row.
+const ColumnWidth = 12
+
+// ColumnCell is a workspace's value in one of its project's custom columns.
+type ColumnCell struct {
+	Name string
+	// Value is the column command's output, one line of plain text.
+	Value string
+	// Failed is set when the command failed or timed out; the cell then
+	// shows an error badge instead of a stale value.
+	Failed bool
+}
+
+// SetColumns sets the custom column cells shown for a workspace, in column
+// order. Nil or empty cells clear them.
+func (m *Model) SetColumns(wsID string, cells []ColumnCell) {
+	if wsID == "" {
+		return
+	}
+	if len(cells) == 0 {
+		delete(m.columns, wsID)
+		return
+	}
+	if m.columns == nil {
+		m.columns = make(map[string][]ColumnCell)
+	}
+	m.columns[wsID] = cells
+}
+
+// withColumns appends wsID's custom column cells to a row's status, after
+// its check badge.
+func (m *Model) withColumns(status, wsID string) string {
+	for _, cell := range m.columns[wsID] {
+		switch {
+		case cell.Failed:
+			status += " " + m.styles.StatusDirty.Render(ansi.Truncate("✗ "+cell.Name, ColumnWidth, "…"))
+		case cell.Value != "":
+			status += " " + m.styles.Muted.Render(ansi.Truncate(cell.Value, ColumnWidth, "…"))
+		}
+	}
+	return status
+}
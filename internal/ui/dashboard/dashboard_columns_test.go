@@ -0,0 +1,33 @@
+package dashboard
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/x/ansi"
+
+	"github.com/andyrewlee/amux/internal/data"
+)
+
+func TestColumnCells(t *testing.T) {
+	m := New()
+	m.SetProjects([]data.Project{makeProject()})
+	row := m.rows[3]
+	wsID := row.ActivityWorkspaceID
+
+	m.SetChecks(wsID, &ChecksBadge{})
+	m.SetColumns(wsID, []ColumnCell{
+		{Name: "version", Value: "1.4.2"},
+		{Name: "todo", Value: ""},
+		{Name: "ci", Failed: true},
+		{Name: "branch", Value: "a-very-long-branch-name"},
+	})
+	got := ansi.Strip(m.renderRow(row, false))
+	if !strings.HasSuffix(got, "✓ 1.4.2 ✗ ci a-very-long…") {
+		t.Fatalf("row = %q, want the cells after the check badge", got)
+	}
+	m.SetColumns(wsID, nil)
+	if got := m.renderRow(row, false); strings.Contains(got, "1.4.2") {
+		t.Fatalf("row = %q, want the cells cleared", got)
+	}
+}
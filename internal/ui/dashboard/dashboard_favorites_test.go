@@ -0,0 +1,43 @@
+package dashboard
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/charmbracelet/x/ansi"
+
+	"github.com/andyrewlee/amux/internal/data"
+	"github.com/andyrewlee/amux/internal/messages"
+)
+
+func TestFavoriteWorkspacesPinnedFirst(t *testing.T) {
+	m := New()
+	project := makeProject()
+	newer := time.Now()
+	project.Workspaces = append(project.Workspaces,
+		data.Workspace{Name: "newest", Branch: "newest", Repo: "/repo", Root: "/repo/.amux/workspaces/newest", Created: newer},
+	)
+	project.Workspaces[1].Favorite = true
+	m.SetProjects([]data.Project{project})
+
+	var names []string
+	for _, row := range m.rows {
+		if row.Type == RowWorkspace {
+			names = append(names, row.Workspace.Name)
+		}
+	}
+	if len(names) != 2 || names[0] != "feature" {
+		t.Fatalf("workspace rows = %v, want the favorite first", names)
+	}
+	if got := ansi.Strip(m.renderRow(m.rows[3], false)); !strings.Contains(got, "★ feature") {
+		t.Fatalf("row = %q, want the favorite marked", got)
+	}
+
+	m.cursor = 3
+	_, cmd := m.Update(tea.KeyPressMsg{Code: 'f', Text: "f"})
+	if msg, ok := cmd().(messages.ToggleFavorite); !ok || msg.Workspace == nil || msg.Workspace.Name != "feature" {
+		t.Fatalf("f = %#v, want a favorite toggle for the selected workspace", msg)
+	}
+}
@@ -169,6 +169,7 @@ func (m *Model) ackDone(wsID string) {
 		m.doneAcked = make(map[string]bool)
 	}
 	m.doneAcked[wsID] = true
+	delete(m.alerts, wsID)
 }
 
 // activateCurrentRow returns a command to activate the currently selected row.
@@ -310,6 +311,45 @@ func (m *Model) handleRename() tea.Cmd {
 	return nil
 }
 
+// handleOpenIn handles the open-in key. Workspace rows open their own
+// worktree; project rows open the project's main checkout.
+func (m *Model) handleOpenIn() tea.Cmd {
+	if m.cursor >= len(m.rows) {
+		return nil
+	}
+
+	row := m.rows[m.cursor]
+	ws := row.Workspace
+	if row.Type == RowProject {
+		ws = row.MainWorkspace
+	}
+	if ws == nil || (row.Type != RowWorkspace && row.Type != RowProject) {
+		return nil
+	}
+	return func() tea.Msg {
+		return messages.ShowOpenInDialog{Workspace: ws}
+	}
+}
+
+// handleToggleFavorite requests toggling the selected project or workspace
+// as a favorite.
+func (m *Model) handleToggleFavorite() tea.Cmd {
+	if m.cursor >= len(m.rows) {
+		return nil
+	}
+	row := m.rows[m.cursor]
+	var msg messages.ToggleFavorite
+	switch row.Type {
+	case RowProject:
+		msg.Project = row.Project
+	case RowWorkspace:
+		msg.Project, msg.Workspace = row.Project, row.Workspace
+	default:
+		return nil
+	}
+	return func() tea.Msg { return msg }
+}
+
 // refresh requests a workspace rescan/import.
 func (m *Model) refresh() tea.Cmd {
 	return func() tea.Msg { return messages.RescanWorkspaces{} }
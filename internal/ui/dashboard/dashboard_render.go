@@ -3,7 +3,6 @@ package dashboard
 import (
 	"charm.land/lipgloss/v2"
 
-	"github.com/andyrewlee/amux/internal/app/activity"
 	"github.com/andyrewlee/amux/internal/ui/common"
 )
 
@@ -32,6 +31,9 @@ func (m *Model) renderRow(row Row, selected bool) string {
 		} else if m.activeRoot == "" {
 			style = style.Bold(true).Foreground(common.ColorPrimary())
 		}
+		if m.profile != "" {
+			return style.Render("[amux:" + m.profile + "]")
+		}
 		return style.Render("[amux]")
 
 	case RowProject:
@@ -46,10 +48,10 @@ func (m *Model) renderRow(row Row, selected bool) string {
 			if m.deletingWorkspaces[main.Root] {
 				frame := common.SpinnerFrame(m.spinnerFrame)
 				statusText = m.styles.StatusPending.Render(frame + " deleting")
+			} else if alert := m.alerts[row.ActivityWorkspaceID]; alert != "" {
+				statusText = m.styles.StatusPending.Render(alert)
 			} else if !active &&
-				row.ActivityWorkspaceID != "" &&
-				m.agentStates[row.ActivityWorkspaceID] == activity.StateDone &&
-				!m.doneAcked[row.ActivityWorkspaceID] {
+				m.NeedsAttention(row.ActivityWorkspaceID) {
 				done = true
 			} else if s, ok := m.statusCache[main.Root]; ok && !s.Clean {
 				dirty = true
@@ -60,6 +62,7 @@ func (m *Model) renderRow(row Row, selected bool) string {
 		} else if done {
 			status = " " + m.styles.StatusPending.Render("done")
 		}
+		status = m.withColumns(m.withChecksStatus(status, row.ActivityWorkspaceID), row.ActivityWorkspaceID)
 
 		// Project headers are selectable to access main branch
 		style := m.styles.ProjectHeader.MarginTop(0)
@@ -85,6 +88,9 @@ func (m *Model) renderRow(row Row, selected bool) string {
 
 		// Truncate project name to fit within pane (width - border - padding - status - deleteSlot)
 		name := row.Project.Name
+		if row.Project.Favorite {
+			name = common.Icons.Favorite + " " + name
+		}
 		maxNameWidth := m.width - 3 - lipgloss.Width(status) - deleteSlotWidth - lipgloss.Width(prefix) - 1
 		if maxNameWidth > 0 && lipgloss.Width(name) > maxNameWidth {
 			runes := []rune(name)
@@ -105,6 +111,9 @@ func (m *Model) renderRow(row Row, selected bool) string {
 		unstyledPrefix := " "
 		styledPrefix := " "
 		name := row.Workspace.Name
+		if row.Workspace.Favorite {
+			name = common.Icons.Favorite + " " + name
+		}
 		status := ""
 		statusText := ""
 		dirty := false
@@ -118,12 +127,12 @@ func (m *Model) renderRow(row Row, selected bool) string {
 		} else if _, ok := m.creatingWorkspaces[row.Workspace.Root]; ok {
 			frame := common.SpinnerFrame(m.spinnerFrame)
 			statusText = m.styles.StatusPending.Render(frame + " creating")
+		} else if alert := m.alerts[row.ActivityWorkspaceID]; alert != "" {
+			statusText = m.styles.StatusPending.Render(alert)
 		} else if row.ActivityWorkspaceID != "" && m.activeWorkspaceIDs[row.ActivityWorkspaceID] {
 			// Active agents - color change only, no spinner
 			working = true
-		} else if row.ActivityWorkspaceID != "" &&
-			m.agentStates[row.ActivityWorkspaceID] == activity.StateDone &&
-			!m.doneAcked[row.ActivityWorkspaceID] {
+		} else if m.NeedsAttention(row.ActivityWorkspaceID) {
 			done = true
 		} else if s, ok := m.statusCache[row.Workspace.Root]; ok && !s.Clean {
 			dirty = true
@@ -133,6 +142,7 @@ func (m *Model) renderRow(row Row, selected bool) string {
 		} else if done {
 			status = " " + m.styles.StatusPending.Render("done")
 		}
+		status = m.withColumns(m.withChecksStatus(status, row.ActivityWorkspaceID), row.ActivityWorkspaceID)
 
 		// Determine row style based on selection and active state
 		style := m.styles.WorkspaceRow
@@ -213,21 +223,27 @@ func (m *Model) helpLines(contentWidth int) []string {
 	if m.cursor >= 0 && m.cursor < len(m.rows) {
 		switch m.rows[m.cursor].Type {
 		case RowWorkspace:
+			items = append(items, m.helpItem("o", "open in"))
 			items = append(items, m.helpItem("R", "rename"))
 			items = append(items, m.helpItem("D", "delete"))
+			items = append(items, m.helpItem("f", "favorite"))
 		case RowProject:
+			items = append(items, m.helpItem("o", "open in"))
 			items = append(items, m.helpItem("D", "remove"))
+			items = append(items, m.helpItem("f", "favorite"))
 		}
 	}
 	items = append(items,
+		m.helpItem("u", "undo"),
+		m.helpItem("T", "trash"),
 		m.helpItem("r", "rescan"),
 		m.helpItem("g", "top"),
 		m.helpItem("G", "bottom"),
 	)
 	items = append(items,
-		m.helpItem("C-Space", "Commands"),
-		m.helpItem("C-Space S", "Settings"),
-		m.helpItem("C-Space q", "quit"),
+		m.helpItem(m.prefixLabel, "Commands"),
+		m.helpItem(m.prefixLabel+" S", "Settings"),
+		m.helpItem(m.prefixLabel+" q", "quit"),
 	)
 	return common.WrapHelpItems(items, contentWidth)
 }
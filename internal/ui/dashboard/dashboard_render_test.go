@@ -269,3 +269,14 @@ func TestDashboardHelpLineCountClampsContentWidth(t *testing.T) {
 		}
 	}
 }
+
+func TestHomeRowShowsProfile(t *testing.T) {
+	m := New()
+	if got := m.renderRow(Row{Type: RowHome}, false); !strings.Contains(got, "[amux]") {
+		t.Fatalf("home row = %q", got)
+	}
+	m.SetProfile("work")
+	if got := m.renderRow(Row{Type: RowHome}, false); !strings.Contains(got, "[amux:work]") {
+		t.Fatalf("home row = %q, want the profile", got)
+	}
+}
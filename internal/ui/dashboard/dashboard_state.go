@@ -6,13 +6,20 @@ import (
 
 	tea "charm.land/bubbletea/v2"
 
+	"github.com/andyrewlee/amux/internal/app/activity"
 	"github.com/andyrewlee/amux/internal/data"
 	"github.com/andyrewlee/amux/internal/ui/common"
 )
 
 // tickSpinner returns a command that ticks the spinner
 func (m *Model) tickSpinner() tea.Cmd {
-	return common.SafeTick(spinnerInterval, func(t time.Time) tea.Msg {
+	interval := spinnerInterval
+	if common.ReducedMotion() {
+		// The spinner is static; keep ticking slowly for the state the tick
+		// refreshes.
+		interval = reducedMotionSpinnerInterval
+	}
+	return common.SafeTick(interval, func(t time.Time) tea.Msg {
 		return SpinnerTickMsg{}
 	})
 }
@@ -157,6 +164,10 @@ func (m *Model) sortedWorkspaces(project *data.Project) []*data.Workspace {
 	}
 
 	sort.SliceStable(workspaces, func(i, j int) bool {
+		// Favorites are pinned above the rest.
+		if workspaces[i].Favorite != workspaces[j].Favorite {
+			return workspaces[i].Favorite
+		}
 		if workspaces[i].Created.Equal(workspaces[j].Created) {
 			if workspaces[i].Name == workspaces[j].Name {
 				return workspaces[i].Root < workspaces[j].Root
@@ -210,6 +221,58 @@ func (m *Model) Projects() []data.Project {
 	return m.projects
 }
 
+// WorkspaceEntry is a workspace listed on the dashboard, with its project.
+type WorkspaceEntry struct {
+	Project   *data.Project
+	Workspace *data.Workspace
+}
+
+// Workspaces returns the listed workspaces in row order: each project's main
+// workspace (shown as the project row) followed by its other worktrees.
+func (m *Model) Workspaces() []WorkspaceEntry {
+	var out []WorkspaceEntry
+	for _, row := range m.rows {
+		switch {
+		case row.Type == RowProject && row.MainWorkspace != nil:
+			out = append(out, WorkspaceEntry{Project: row.Project, Workspace: row.MainWorkspace})
+		case row.Type == RowWorkspace && row.Workspace != nil:
+			out = append(out, WorkspaceEntry{Project: row.Project, Workspace: row.Workspace})
+		}
+	}
+	return out
+}
+
+// NeedsAttention reports whether the workspace's agent finished, or raised an
+// alert, and the user has not looked at it since.
+func (m *Model) NeedsAttention(wsID string) bool {
+	if wsID == "" {
+		return false
+	}
+	return m.alerts[wsID] != "" || (m.agentStates[wsID] == activity.StateDone && !m.doneAcked[wsID])
+}
+
+// AgentCounts returns how many workspaces need attention and how many have
+// an agent working.
+func (m *Model) AgentCounts() (attention, working int) {
+	attention = len(m.alerts)
+	for wsID, state := range m.agentStates {
+		switch {
+		case m.alerts[wsID] != "":
+		case m.NeedsAttention(wsID):
+			attention++
+		case state == activity.StateWorking:
+			working++
+		}
+	}
+	return attention, working
+}
+
+// AckDone marks a workspace's "done" indicator as seen, as selecting its row
+// does.
+func (m *Model) AckDone(wsID string) {
+	m.ackDone(wsID)
+}
+
 // ClearActiveRoot resets the active workspace selection to "Home".
 func (m *Model) ClearActiveRoot() {
 	m.activeRoot = ""
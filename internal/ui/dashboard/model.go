@@ -17,6 +17,9 @@ type SpinnerTickMsg struct{}
 // spinnerInterval is how often the spinner updates
 const spinnerInterval = 80 * time.Millisecond
 
+// reducedMotionSpinnerInterval is the spinner tick while motion is reduced.
+const reducedMotionSpinnerInterval = time.Second
+
 // bellSequence is the ASCII BEL control byte (0x07). Written verbatim to the
 // program output it rings the user's terminal bell.
 const bellSequence = "\a"
@@ -81,6 +84,8 @@ type Model struct {
 	scrollOffset    int
 	canFocusRight   bool
 	showKeymapHints bool
+	prefixLabel     string          // Leader key as shown in help, e.g. "C-Space"
+	profile         string          // Named profile shown on the home row
 	toolbarHits     []toolbarButton // Clickable toolbar buttons
 	toolbarY        int             // Y position of toolbar in content coordinates
 	toolbarFocused  bool            // Whether toolbar actions are focused
@@ -97,6 +102,9 @@ type Model struct {
 	activeWorkspaceIDs map[string]bool                // Workspace IDs with active agents (synced from center)
 	agentStates        map[string]activity.AgentState // Per-workspace semantic agent states
 	doneAcked          map[string]bool                // Workspace IDs whose "done" indicator has been seen by the user
+	alerts             map[string]string              // Workspace IDs flagged for attention, with their status label
+	checks             map[string]ChecksBadge         // Workspace IDs with a check run to show
+	columns            map[string][]ColumnCell        // Workspace IDs' custom column values
 	notifyOnDone       bool                           // Ring a terminal bell on the unacked Working→Done edge
 
 	// Styles
@@ -115,6 +123,7 @@ func New() *Model {
 		doneAcked:          make(map[string]bool),
 		cursor:             0,
 		focused:            true,
+		prefixLabel:        "C-Space",
 		styles:             common.DefaultStyles(),
 	}
 }
@@ -181,6 +190,17 @@ func (m *Model) SetShowKeymapHints(show bool) {
 	m.showKeymapHints = show
 }
 
+// SetPrefixLabel sets how the leader key is shown in help text.
+func (m *Model) SetPrefixLabel(label string) {
+	m.prefixLabel = label
+}
+
+// SetProfile sets the named profile shown on the home row; empty for the
+// default profile.
+func (m *Model) SetProfile(profile string) {
+	m.profile = profile
+}
+
 // SetStyles updates the component's styles (for theme changes).
 func (m *Model) SetStyles(styles common.Styles) {
 	m.styles = styles
@@ -335,3 +335,57 @@ func TestDashboardNewKeyBinding(t *testing.T) {
 		}
 	})
 }
+
+func TestDashboardHandleOpenIn(t *testing.T) {
+	m := New()
+	m.SetProjects([]data.Project{makeProject()})
+
+	for i, row := range m.rows {
+		if row.Type != RowWorkspace && row.Type != RowProject {
+			continue
+		}
+		m.cursor = i
+		cmd := m.handleOpenIn()
+		if cmd == nil {
+			t.Fatalf("expected open-in command for row type %v", row.Type)
+		}
+		msg, ok := cmd().(messages.ShowOpenInDialog)
+		if !ok {
+			t.Fatalf("expected ShowOpenInDialog for row type %v", row.Type)
+		}
+		want := row.Workspace
+		if row.Type == RowProject {
+			want = row.MainWorkspace
+		}
+		if msg.Workspace != want {
+			t.Fatalf("row type %v opened %+v, want %+v", row.Type, msg.Workspace, want)
+		}
+	}
+
+	m.cursor = 0 // Home row
+	if cmd := m.handleOpenIn(); cmd != nil {
+		t.Fatal("expected no open-in command on the home row")
+	}
+}
+
+func TestDashboardUndoAndTrashKeys(t *testing.T) {
+	m := New()
+	m.Focus()
+	m.SetProjects([]data.Project{makeProject()})
+
+	_, cmd := m.Update(tea.KeyPressMsg{Code: 'u', Text: "u"})
+	if cmd == nil {
+		t.Fatal("expected a command for u")
+	}
+	if _, ok := cmd().(messages.Undo); !ok {
+		t.Fatal("expected u to request an undo")
+	}
+
+	_, cmd = m.Update(tea.KeyPressMsg{Code: 'T', Text: "T"})
+	if cmd == nil {
+		t.Fatal("expected a command for T")
+	}
+	if _, ok := cmd().(messages.ShowTrashDialog); !ok {
+		t.Fatal("expected T to open the trash view")
+	}
+}
@@ -173,6 +173,14 @@ func (m *Model) handleNavKey(msg tea.KeyPressMsg, toolbarItems []toolbarItem) (*
 		return m, m.handleDelete()
 	case key.Matches(msg, key.NewBinding(key.WithKeys("R"))):
 		return m, m.handleRename()
+	case key.Matches(msg, key.NewBinding(key.WithKeys("o"))):
+		return m, m.handleOpenIn()
+	case key.Matches(msg, key.NewBinding(key.WithKeys("f"))):
+		return m, m.handleToggleFavorite()
+	case key.Matches(msg, key.NewBinding(key.WithKeys("u"))):
+		return m, func() tea.Msg { return messages.Undo{} }
+	case key.Matches(msg, key.NewBinding(key.WithKeys("T"))):
+		return m, func() tea.Msg { return messages.ShowTrashDialog{} }
 	case key.Matches(msg, key.NewBinding(key.WithKeys("r"))):
 		return m, m.refresh()
 	case key.Matches(msg, key.NewBinding(key.WithKeys("G"))):
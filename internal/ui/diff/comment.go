@@ -0,0 +1,68 @@
+package diff
+
+import (
+	"strings"
+
+	tea "charm.land/bubbletea/v2"
+
+	"github.com/andyrewlee/amux/internal/git"
+	"github.com/andyrewlee/amux/internal/messages"
+)
+
+// commentOnTopLine requests a review comment on the line at the top of the
+// view: a changed or context line, or a whole hunk from its @@ header.
+// Comparisons of two worktrees take no comments.
+func (m *Model) commentOnTopLine() tea.Cmd {
+	if m.workspace == nil || m.change == nil || m.compare != nil || m.diff == nil || m.scroll >= len(m.diff.Lines) {
+		return nil
+	}
+	req := messages.ShowDiffCommentDialog{Workspace: m.workspace, Path: m.change.Path}
+	req.Line, req.Removed, req.Code = m.lineAt(m.scroll)
+	return func() tea.Msg { return req }
+}
+
+// hunkAt returns the index of the hunk containing diff line idx, or -1 for
+// the file header lines before the first hunk.
+func (m *Model) hunkAt(idx int) int {
+	at := -1
+	for i, hunk := range m.diff.Hunks {
+		if hunk.StartLine > idx {
+			break
+		}
+		at = i
+	}
+	return at
+}
+
+// lineAt maps a diff line index to its file line, counting from the hunk
+// header above it. Deleted lines are numbered in the old file. File header
+// lines map to line zero, a comment on the whole file.
+func (m *Model) lineAt(idx int) (line int, removed bool, code string) {
+	at := m.hunkAt(idx)
+	if at < 0 {
+		return 0, false, ""
+	}
+	hunk := &m.diff.Hunks[at]
+	if hunk.StartLine == idx {
+		return hunk.NewStart, false, hunk.Header
+	}
+	oldLine, newLine := hunk.OldStart, hunk.NewStart
+	for i := hunk.StartLine + 1; i < idx; i++ {
+		switch m.diff.Lines[i].Kind {
+		case git.DiffLineAdd:
+			newLine++
+		case git.DiffLineDelete:
+			oldLine++
+		case git.DiffLineContext:
+			if !strings.HasPrefix(m.diff.Lines[i].Content, `\`) {
+				oldLine++
+				newLine++
+			}
+		}
+	}
+	l := m.diff.Lines[idx]
+	if l.Kind == git.DiffLineDelete {
+		return oldLine, true, l.Content
+	}
+	return newLine, false, l.Content
+}
@@ -0,0 +1,65 @@
+package diff
+
+import (
+	"testing"
+
+	tea "charm.land/bubbletea/v2"
+
+	"github.com/andyrewlee/amux/internal/data"
+	"github.com/andyrewlee/amux/internal/git"
+	"github.com/andyrewlee/amux/internal/messages"
+)
+
+func TestCommentOnTopLine(t *testing.T) {
+	ws := &data.Workspace{Name: "ws", Root: "/repo/ws"}
+	m := New(ws, &git.Change{Path: "calc.go"}, git.DiffModeUnstaged, 80, 20)
+	m.loading = false
+	m.focused = true
+	m.diff = &git.DiffResult{
+		Lines: []git.DiffLine{
+			{Kind: git.DiffLineHeader, Content: "--- a/calc.go"},
+			{Kind: git.DiffLineHeader, Content: "@@ -10,4 +10,4 @@ func Add"},
+			{Kind: git.DiffLineContext, Content: " a := 1"},
+			{Kind: git.DiffLineDelete, Content: "-b := 2"},
+			{Kind: git.DiffLineAdd, Content: "+b := 3"},
+			{Kind: git.DiffLineContext, Content: " return a + b"},
+		},
+		Hunks: []git.Hunk{{OldStart: 10, NewStart: 10, StartLine: 1, Header: "@@ -10,4 +10,4 @@ func Add"}},
+	}
+
+	tests := []struct {
+		scroll  int
+		line    int
+		removed bool
+		code    string
+	}{
+		{scroll: 0, line: 0},
+		{scroll: 1, line: 10, code: "@@ -10,4 +10,4 @@ func Add"},
+		{scroll: 3, line: 11, removed: true, code: "-b := 2"},
+		{scroll: 4, line: 11, code: "+b := 3"},
+		{scroll: 5, line: 12, code: " return a + b"},
+	}
+	for _, tt := range tests {
+		m.scroll = tt.scroll
+		_, cmd := m.Update(tea.KeyPressMsg{Code: 'c', Text: "c"})
+		if cmd == nil {
+			t.Fatalf("scroll %d: expected a comment request", tt.scroll)
+		}
+		req, ok := cmd().(messages.ShowDiffCommentDialog)
+		if !ok || req.Workspace != ws || req.Path != "calc.go" {
+			t.Fatalf("scroll %d: request = %+v", tt.scroll, req)
+		}
+		if req.Line != tt.line || req.Removed != tt.removed || req.Code != tt.code {
+			t.Fatalf("scroll %d: got line %d removed %v code %q, want %d %v %q",
+				tt.scroll, req.Line, req.Removed, req.Code, tt.line, tt.removed, tt.code)
+		}
+	}
+
+	_, cmd := m.Update(tea.KeyPressMsg{Code: 'S', Text: "S"})
+	if cmd == nil {
+		t.Fatal("expected a send request")
+	}
+	if send, ok := cmd().(messages.SendReviewComments); !ok || send.Workspace != ws {
+		t.Fatalf("send = %+v, want the viewer's workspace", send)
+	}
+}
@@ -0,0 +1,36 @@
+package diff
+
+import (
+	tea "charm.land/bubbletea/v2"
+
+	"github.com/andyrewlee/amux/internal/messages"
+)
+
+// takeTopHunk requests copying the hunk at the top of a comparison's view
+// into the workspace.
+func (m *Model) takeTopHunk() tea.Cmd {
+	if m.compare == nil || m.diff == nil || m.change == nil {
+		return nil
+	}
+	at := m.hunkAt(m.scroll)
+	if at < 0 {
+		// From the file header, the first hunk is the one in view.
+		at = 0
+	}
+	patch, ok := m.diff.HunkPatch(at)
+	if !ok {
+		return nil
+	}
+	req := messages.TakeFromComparison{Workspace: m.workspace, Compare: m.compare, Path: m.change.Path, Patch: patch}
+	return func() tea.Msg { return req }
+}
+
+// takeFile requests copying the other worktree's version of a comparison's
+// file into the workspace.
+func (m *Model) takeFile() tea.Cmd {
+	if m.compare == nil || m.change == nil {
+		return nil
+	}
+	req := messages.TakeFromComparison{Workspace: m.workspace, Compare: m.compare, Path: m.change.Path}
+	return func() tea.Msg { return req }
+}
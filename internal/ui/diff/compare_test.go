@@ -0,0 +1,74 @@
+package diff
+
+import (
+	"strings"
+	"testing"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/charmbracelet/x/ansi"
+
+	"github.com/andyrewlee/amux/internal/data"
+	"github.com/andyrewlee/amux/internal/git"
+	"github.com/andyrewlee/amux/internal/messages"
+)
+
+func TestComparisonViewer(t *testing.T) {
+	ws := &data.Workspace{Name: "ws", Root: "/repo/attempt-a"}
+	cmp := &git.Comparison{
+		From: git.Snapshot{Root: "/repo/attempt-a", Tree: "aaa"},
+		To:   git.Snapshot{Root: "/repo/attempt-b", Tree: "bbb"},
+	}
+	m := NewComparison(ws, cmp, "calc.go", 80, 20)
+	if m.MatchesSource("calc.go", git.DiffModeBoth) {
+		t.Fatal("a comparison should not be reused for the workspace's own diff")
+	}
+	if header := ansi.Strip(m.renderHeader()); !strings.Contains(header, "calc.go (attempt-a → attempt-b)") {
+		t.Fatalf("header = %q, want the compared worktrees", header)
+	}
+
+	m.loading = false
+	m.focused = true
+	m.diff = &git.DiffResult{Lines: []git.DiffLine{{Kind: git.DiffLineAdd, Content: "+x"}}}
+	if _, cmd := m.Update(tea.KeyPressMsg{Code: 'c', Text: "c"}); cmd != nil {
+		t.Fatal("comparisons should not take review comments")
+	}
+}
+
+func TestComparisonTakeKeys(t *testing.T) {
+	ws := &data.Workspace{Name: "ws", Root: "/repo/attempt-a"}
+	cmp := &git.Comparison{}
+	m := NewComparison(ws, cmp, "calc.go", 80, 20)
+	m.loading = false
+	m.focused = true
+	m.diff = &git.DiffResult{
+		Lines: []git.DiffLine{
+			{Kind: git.DiffLineHeader, Content: "diff --git a/calc.go b/calc.go"},
+			{Kind: git.DiffLineHeader, Content: "@@ -1 +1 @@"},
+			{Kind: git.DiffLineAdd, Content: "+first"},
+			{Kind: git.DiffLineHeader, Content: "@@ -9 +9 @@"},
+			{Kind: git.DiffLineAdd, Content: "+second"},
+		},
+		Hunks: []git.Hunk{{StartLine: 1}, {StartLine: 3}},
+	}
+
+	take := func(key rune) messages.TakeFromComparison {
+		t.Helper()
+		_, cmd := m.Update(tea.KeyPressMsg{Code: key, Text: string(key)})
+		if cmd == nil {
+			t.Fatalf("%c: expected a take request", key)
+		}
+		req, ok := cmd().(messages.TakeFromComparison)
+		if !ok || req.Workspace != ws || req.Compare != cmp || req.Path != "calc.go" {
+			t.Fatalf("%c: request = %+v", key, req)
+		}
+		return req
+	}
+
+	m.scroll = 4
+	if req := take('a'); req.Patch != "diff --git a/calc.go b/calc.go\n@@ -9 +9 @@\n+second\n" {
+		t.Fatalf("patch = %q, want the second hunk", req.Patch)
+	}
+	if req := take('A'); req.Patch != "" {
+		t.Fatalf("patch = %q, want the whole file", req.Patch)
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"charm.land/bubbles/v2/key"
 	tea "charm.land/bubbletea/v2"
 
+	"github.com/andyrewlee/amux/internal/codeowners"
 	"github.com/andyrewlee/amux/internal/data"
 	"github.com/andyrewlee/amux/internal/git"
 	"github.com/andyrewlee/amux/internal/messages"
@@ -20,7 +21,12 @@ type Model struct {
 	change    *git.Change
 	diff      *git.DiffResult
 	mode      git.DiffMode
-	loadID    uint64
+	// compare, when set, makes the viewer show change's path across two
+	// worktrees instead of the workspace's own diff.
+	compare *git.Comparison
+	loadID  uint64
+	// owners are the CODEOWNERS owners of the file, shown in the header.
+	owners []string
 
 	// State
 	loading bool
@@ -41,6 +47,7 @@ type Model struct {
 // diffLoaded is sent when the diff has been loaded
 type diffLoaded struct {
 	diff   *git.DiffResult
+	owners []string
 	err    error
 	loadID uint64
 }
@@ -58,6 +65,14 @@ func New(ws *data.Workspace, change *git.Change, mode git.DiffMode, width, heigh
 	}
 }
 
+// NewComparison creates a diff viewer showing how path differs between the
+// two worktrees of cmp.
+func NewComparison(ws *data.Workspace, cmp *git.Comparison, path string, width, height int) *Model {
+	m := New(ws, &git.Change{Path: path, Kind: git.ChangeModified}, git.DiffModeBoth, width, height)
+	m.compare = cmp
+	return m
+}
+
 // Init initializes the diff viewer and starts loading the diff
 func (m *Model) Init() tea.Cmd {
 	return m.loadDiff()
@@ -72,7 +87,7 @@ func normalizeSourcePath(path string) string {
 
 // MatchesSource reports whether the viewer is already showing the same diff target.
 func (m *Model) MatchesSource(changePath string, mode git.DiffMode) bool {
-	if m == nil || m.change == nil {
+	if m == nil || m.change == nil || m.compare != nil {
 		return false
 	}
 	return normalizeSourcePath(m.change.Path) == normalizeSourcePath(changePath) && m.mode == mode
@@ -95,6 +110,7 @@ func (m *Model) loadDiff() tea.Cmd {
 	ws := m.workspace
 	change := m.change
 	mode := m.mode
+	cmp := m.compare
 	m.loadID++
 	loadID := m.loadID
 
@@ -107,6 +123,8 @@ func (m *Model) loadDiff() tea.Cmd {
 		var err error
 
 		switch {
+		case cmp != nil:
+			diff, err = git.GetComparisonFileDiff(cmp, change.Path)
 		case change.Kind == git.ChangeUntracked:
 			diff, err = git.GetUntrackedFileContent(ws.Root, change.Path)
 		case mode == git.DiffModeBranch:
@@ -115,7 +133,9 @@ func (m *Model) loadDiff() tea.Cmd {
 			diff, err = git.GetFileDiff(ws.Root, change.Path, mode)
 		}
 
-		return diffLoaded{diff: diff, err: err, loadID: loadID}
+		// A CODEOWNERS file that can't be read just leaves owners out.
+		rules, _ := codeowners.Load(ws.Root)
+		return diffLoaded{diff: diff, owners: rules.Owners(change.Path), err: err, loadID: loadID}
 	}
 }
 
@@ -127,6 +147,7 @@ func (m *Model) Update(msg tea.Msg) (*Model, tea.Cmd) {
 			return m, nil
 		}
 		m.loading = false
+		m.owners = msg.owners
 		if msg.err != nil {
 			m.err = msg.err
 			return m, nil
@@ -184,6 +205,17 @@ func (m *Model) Update(msg tea.Msg) (*Model, tea.Cmd) {
 		case key.Matches(msg, key.NewBinding(key.WithKeys("w"))):
 			m.wrap = !m.wrap
 
+		// Review comments
+		case key.Matches(msg, key.NewBinding(key.WithKeys("c"))):
+			return m, m.commentOnTopLine()
+		case key.Matches(msg, key.NewBinding(key.WithKeys("a"))):
+			return m, m.takeTopHunk()
+		case key.Matches(msg, key.NewBinding(key.WithKeys("A"))):
+			return m, m.takeFile()
+		case key.Matches(msg, key.NewBinding(key.WithKeys("S"))):
+			ws := m.workspace
+			return m, func() tea.Msg { return messages.SendReviewComments{Workspace: ws} }
+
 		// Close
 		case key.Matches(msg, key.NewBinding(key.WithKeys("q", "esc"))):
 			return m, func() tea.Msg { return messages.CloseTab{} }
@@ -1,9 +1,13 @@
 package diff
 
 import (
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	tea "charm.land/bubbletea/v2"
+	"github.com/charmbracelet/x/ansi"
 
 	"github.com/andyrewlee/amux/internal/data"
 	"github.com/andyrewlee/amux/internal/git"
@@ -166,6 +170,35 @@ func TestDiffLoaded_WithResultErrorShowsError(t *testing.T) {
 	}
 }
 
+func TestDiffLoaded_ShowsCodeOwners(t *testing.T) {
+	root := t.TempDir()
+	for name, content := range map[string]string{
+		"CODEOWNERS":   "/web/ @acme/web\n",
+		"web/app.ts":   "export {}\n",
+		"other/lib.go": "package lib\n",
+	} {
+		if err := os.MkdirAll(filepath.Dir(filepath.Join(root, name)), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(root, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	ws := &data.Workspace{Name: "ws", Root: root}
+
+	m := New(ws, &git.Change{Path: "web/app.ts", Kind: git.ChangeUntracked}, git.DiffModeUnstaged, 80, 20)
+	m, _ = m.Update(m.loadDiff()())
+	if header := ansi.Strip(m.renderHeader()); !strings.Contains(header, "owned by @acme/web") {
+		t.Fatalf("header = %q, want the file's owners", header)
+	}
+
+	m = New(ws, &git.Change{Path: "other/lib.go", Kind: git.ChangeUntracked}, git.DiffModeUnstaged, 80, 20)
+	m, _ = m.Update(m.loadDiff()())
+	if header := ansi.Strip(m.renderHeader()); strings.Contains(header, "owned by") {
+		t.Fatalf("header = %q, want no owners for an unowned file", header)
+	}
+}
+
 func TestPageScrollUsesMinimumOneLine(t *testing.T) {
 	m := newModelWithDiff(4, 10, nil)
 	m.focused = true
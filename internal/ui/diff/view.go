@@ -149,7 +149,15 @@ func (m *Model) renderHeader() string {
 		modeStr = " (branch)"
 	}
 
-	return headerStyle.Render(path + modeStr)
+	if m.compare != nil {
+		modeStr = " (" + m.compare.Name() + ")"
+	}
+
+	header := headerStyle.Render(path + modeStr)
+	if len(m.owners) > 0 {
+		header += lipgloss.NewStyle().Foreground(common.ColorMuted()).Render("  owned by " + strings.Join(m.owners, " "))
+	}
+	return header
 }
 
 // renderDiff renders the actual diff content
@@ -239,6 +247,10 @@ func (m *Model) renderLine(lineNum int, line git.DiffLine, numWidth, contentWidt
 		Width(numWidth).
 		Align(lipgloss.Right)
 
+	// The top line is the one a review comment attaches to.
+	if lineNum == m.scroll {
+		gutterStyle = gutterStyle.Foreground(common.ColorPrimary()).Bold(true)
+	}
 	lineNumStr := gutterStyle.Render(strconv.Itoa(lineNum + 1))
 
 	// Get line content and style based on type
@@ -319,8 +331,13 @@ func (m *Model) renderFooter() string {
 		keyStyle.Render("j/k") + ":scroll",
 		keyStyle.Render("n/p") + ":hunk",
 		keyStyle.Render("w") + ":wrap",
-		keyStyle.Render("q") + ":close",
 	}
+	if m.compare != nil {
+		helpItems = append(helpItems, keyStyle.Render("a")+":take hunk", keyStyle.Render("A")+":take file")
+	} else {
+		helpItems = append(helpItems, keyStyle.Render("c")+":comment", keyStyle.Render("S")+":send comments")
+	}
+	helpItems = append(helpItems, keyStyle.Render("q")+":close")
 
 	return footerStyle.Render(strings.Join(parts, " | ")) + "  " + footerStyle.Render(strings.Join(helpItems, " "))
 }
@@ -171,6 +171,12 @@ func (m *Manager) TopGutter() int {
 	return m.topGutter
 }
 
+// SetBottomGutter reserves rows below the panes, such as for the status bar.
+// It takes effect on the next Resize.
+func (m *Manager) SetBottomGutter(rows int) {
+	m.bottomGutter = max(rows, 0)
+}
+
 // GapX returns the horizontal gap between panes.
 func (m *Manager) GapX() int {
 	return m.gapX
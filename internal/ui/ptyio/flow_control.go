@@ -0,0 +1,135 @@
+package ptyio
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// FlowThresholds are the buffered-output watermarks for PTY flow control. When
+// a tab's unapplied output reaches PauseBytes its reader stops reading the PTY,
+// so the kernel buffer fills and the producer blocks instead of amux trimming
+// output; reading resumes once the backlog drains to ResumeBytes.
+type FlowThresholds struct {
+	PauseBytes  int
+	ResumeBytes int
+}
+
+const (
+	// DefaultFlowPauseBytes sits well below the center buffered ceiling (8M)
+	// so a flood pauses before overflow trimming starts dropping output.
+	DefaultFlowPauseBytes = 4 * 1024 * 1024
+	// DefaultFlowResumeBytes leaves about one catch-up chunk of backlog, so
+	// the reader is running again before the terminal drains dry.
+	DefaultFlowResumeBytes = 1024 * 1024
+)
+
+// DefaultFlowThresholds returns the watermarks used when none are configured.
+func DefaultFlowThresholds() FlowThresholds {
+	return FlowThresholds{PauseBytes: DefaultFlowPauseBytes, ResumeBytes: DefaultFlowResumeBytes}
+}
+
+// Enabled reports whether these thresholds ever pause a reader.
+func (t FlowThresholds) Enabled() bool {
+	return t.PauseBytes > 0
+}
+
+// NormalizeFlowThresholds fills unset (zero) fields from the defaults and keeps
+// ResumeBytes below PauseBytes. A negative PauseBytes disables flow control.
+func NormalizeFlowThresholds(t FlowThresholds) FlowThresholds {
+	if t.PauseBytes < 0 {
+		return FlowThresholds{}
+	}
+	if t.PauseBytes == 0 {
+		t.PauseBytes = DefaultFlowPauseBytes
+	}
+	if t.ResumeBytes <= 0 {
+		t.ResumeBytes = DefaultFlowResumeBytes
+	}
+	if t.ResumeBytes >= t.PauseBytes {
+		t.ResumeBytes = t.PauseBytes / 2
+	}
+	return t
+}
+
+var currentFlowThresholds atomic.Value // FlowThresholds
+
+// SetFlowThresholds installs the process-wide watermarks after normalizing
+// them. Gates pick the new values up on their next Observe.
+func SetFlowThresholds(t FlowThresholds) {
+	currentFlowThresholds.Store(NormalizeFlowThresholds(t))
+}
+
+// CurrentFlowThresholds returns the active watermarks.
+func CurrentFlowThresholds() FlowThresholds {
+	if t, ok := currentFlowThresholds.Load().(FlowThresholds); ok {
+		return t
+	}
+	return DefaultFlowThresholds()
+}
+
+// FlowGate pauses a PTY read loop while its consumer is behind. The consumer
+// reports its backlog with Observe; the read loop blocks in Wait before each
+// read while the gate is closed. The zero value is an open gate and all
+// methods are safe for concurrent use.
+type FlowGate struct {
+	mu     sync.Mutex
+	paused bool
+	resume chan struct{}
+}
+
+// Observe records the consumer's current backlog in bytes against the active
+// thresholds and reports whether the gate changed state.
+func (g *FlowGate) Observe(buffered int) bool {
+	t := CurrentFlowThresholds()
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	switch {
+	case !g.paused && t.Enabled() && buffered >= t.PauseBytes:
+		g.paused = true
+		g.resume = make(chan struct{})
+		return true
+	case g.paused && (!t.Enabled() || buffered <= t.ResumeBytes):
+		g.openLocked()
+		return true
+	}
+	return false
+}
+
+// Release opens the gate unconditionally, e.g. when the backlog is discarded.
+func (g *FlowGate) Release() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.paused {
+		g.openLocked()
+	}
+}
+
+func (g *FlowGate) openLocked() {
+	g.paused = false
+	close(g.resume)
+	g.resume = nil
+}
+
+// Paused reports whether the gate is currently holding its reader.
+func (g *FlowGate) Paused() bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.paused
+}
+
+// Wait blocks while the gate is paused. It returns false if cancel fires
+// first.
+func (g *FlowGate) Wait(cancel <-chan struct{}) bool {
+	g.mu.Lock()
+	resume := g.resume
+	g.mu.Unlock()
+	if resume == nil {
+		return true
+	}
+	select {
+	case <-resume:
+		return true
+	case <-cancel:
+		return false
+	}
+}
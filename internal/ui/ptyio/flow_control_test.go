@@ -0,0 +1,129 @@
+package ptyio
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func setFlowThresholdsForTest(t *testing.T, th FlowThresholds) {
+	t.Helper()
+	prev := CurrentFlowThresholds()
+	SetFlowThresholds(th)
+	t.Cleanup(func() { SetFlowThresholds(prev) })
+}
+
+func TestNormalizeFlowThresholds(t *testing.T) {
+	tests := []struct {
+		name string
+		in   FlowThresholds
+		want FlowThresholds
+	}{
+		{"zero uses defaults", FlowThresholds{}, DefaultFlowThresholds()},
+		{"negative disables", FlowThresholds{PauseBytes: -1, ResumeBytes: 10}, FlowThresholds{}},
+		{"resume kept below pause", FlowThresholds{PauseBytes: 100, ResumeBytes: 200}, FlowThresholds{PauseBytes: 100, ResumeBytes: 50}},
+		{"explicit values kept", FlowThresholds{PauseBytes: 100, ResumeBytes: 40}, FlowThresholds{PauseBytes: 100, ResumeBytes: 40}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NormalizeFlowThresholds(tt.in); got != tt.want {
+				t.Fatalf("NormalizeFlowThresholds(%+v) = %+v, want %+v", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFlowGateHysteresis(t *testing.T) {
+	setFlowThresholdsForTest(t, FlowThresholds{PauseBytes: 100, ResumeBytes: 40})
+	var g FlowGate
+
+	steps := []struct {
+		buffered    int
+		wantChanged bool
+		wantPaused  bool
+	}{
+		{50, false, false},
+		{100, true, true},
+		{60, false, true},
+		{40, true, false},
+		{99, false, false},
+	}
+	for _, s := range steps {
+		if changed := g.Observe(s.buffered); changed != s.wantChanged {
+			t.Fatalf("Observe(%d) changed = %v, want %v", s.buffered, changed, s.wantChanged)
+		}
+		if g.Paused() != s.wantPaused {
+			t.Fatalf("after Observe(%d) Paused() = %v, want %v", s.buffered, g.Paused(), s.wantPaused)
+		}
+	}
+}
+
+func TestFlowGateDisabledNeverPauses(t *testing.T) {
+	setFlowThresholdsForTest(t, FlowThresholds{PauseBytes: -1})
+	var g FlowGate
+	if g.Observe(1 << 30) {
+		t.Fatal("disabled flow control should never pause")
+	}
+}
+
+func TestFlowGateWaitReturnsOnReleaseOrCancel(t *testing.T) {
+	setFlowThresholdsForTest(t, FlowThresholds{PauseBytes: 10, ResumeBytes: 5})
+	var g FlowGate
+	if !g.Wait(nil) {
+		t.Fatal("open gate should not block")
+	}
+
+	g.Observe(10)
+	released := make(chan bool, 1)
+	go func() { released <- g.Wait(make(chan struct{})) }()
+	select {
+	case <-released:
+		t.Fatal("Wait returned while paused")
+	case <-time.After(20 * time.Millisecond):
+	}
+	g.Release()
+	if ok := <-released; !ok {
+		t.Fatal("Wait after Release should report true")
+	}
+
+	g.Observe(10)
+	cancel := make(chan struct{})
+	close(cancel)
+	if g.Wait(cancel) {
+		t.Fatal("Wait should report false on cancel")
+	}
+}
+
+// countingReader counts reads and returns one byte per read until EOF.
+type countingReader struct {
+	reads atomic.Int32
+	inner scriptedReader
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	c.reads.Add(1)
+	return c.inner.Read(p)
+}
+
+func TestRunPTYReaderHoldsReadsWhilePaused(t *testing.T) {
+	setFlowThresholdsForTest(t, FlowThresholds{PauseBytes: 10, ResumeBytes: 5})
+	gate := &FlowGate{}
+	gate.Observe(10)
+
+	r := &countingReader{inner: scriptedReader{chunks: [][]byte{[]byte("held")}}}
+	cfg := baseReaderCfg()
+	cfg.Gate = gate
+
+	go func() {
+		time.Sleep(30 * time.Millisecond)
+		if n := r.reads.Load(); n != 0 {
+			t.Errorf("reader read %d times while paused", n)
+		}
+		gate.Release()
+	}()
+	got := runReaderAndForward(t, r, make(chan struct{}), cfg)
+
+	if string(got.outputBytes()) != "held" {
+		t.Fatalf("output = %q, want %q after resume", got.outputBytes(), "held")
+	}
+}
@@ -0,0 +1,126 @@
+package ptyio
+
+import (
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// LatencyProfile selects how aggressively PTY output is flushed and rendered.
+// Profiles scale the baseline tuning constants rather than replacing them, so
+// the per-pane divergences documented in the center and sidebar config files
+// keep their relative shape under every profile.
+type LatencyProfile string
+
+const (
+	// LatencyProfileSnappy flushes and renders sooner at the cost of more
+	// frames and wakeups.
+	LatencyProfileSnappy LatencyProfile = "snappy"
+	// LatencyProfileBalanced is the default and uses the baseline constants
+	// unchanged.
+	LatencyProfileBalanced LatencyProfile = "balanced"
+	// LatencyProfileBattery coalesces more output per frame and polls less
+	// often to save power.
+	LatencyProfileBattery LatencyProfile = "battery"
+)
+
+// latencyScale holds a profile's multipliers for the three timing families.
+type latencyScale struct {
+	flush    float64 // flush quiet periods and ceilings
+	frame    float64 // reader frame interval
+	snapshot float64 // background snapshot/scan intervals
+}
+
+var latencyScales = map[LatencyProfile]latencyScale{
+	LatencyProfileSnappy:   {flush: 0.5, frame: 0.5, snapshot: 0.6},
+	LatencyProfileBalanced: {flush: 1, frame: 1, snapshot: 1},
+	LatencyProfileBattery:  {flush: 2, frame: 2, snapshot: 3},
+}
+
+// LatencyProfiles returns the profiles in display order.
+func LatencyProfiles() []LatencyProfile {
+	return []LatencyProfile{LatencyProfileSnappy, LatencyProfileBalanced, LatencyProfileBattery}
+}
+
+// ParseLatencyProfile resolves a configured profile name. Empty selects the
+// balanced default; unknown names report false.
+func ParseLatencyProfile(name string) (LatencyProfile, bool) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "" {
+		return LatencyProfileBalanced, true
+	}
+	profile := LatencyProfile(name)
+	if _, ok := latencyScales[profile]; !ok {
+		return LatencyProfileBalanced, false
+	}
+	return profile, true
+}
+
+var currentLatencyProfile atomic.Value // LatencyProfile
+
+// SetLatencyProfile switches the process-wide profile. Readers and flush
+// schedulers pick it up on their next timing decision, so switching is live.
+func SetLatencyProfile(profile LatencyProfile) {
+	if _, ok := latencyScales[profile]; !ok {
+		profile = LatencyProfileBalanced
+	}
+	currentLatencyProfile.Store(profile)
+}
+
+// CurrentLatencyProfile returns the active profile.
+func CurrentLatencyProfile() LatencyProfile {
+	if profile, ok := currentLatencyProfile.Load().(LatencyProfile); ok {
+		return profile
+	}
+	return LatencyProfileBalanced
+}
+
+// reducedMotionFrameScale slows reader frames and background snapshots on
+// top of the latency profile while minimal redraw is on.
+const reducedMotionFrameScale = 2
+
+var minimalRedraw atomic.Bool
+
+// SetMinimalRedraw lowers the render and snapshot rates process-wide, for
+// reduced motion and slow links where every redraw costs latency. Flush
+// timing is left to the latency profile so output still arrives promptly.
+func SetMinimalRedraw(on bool) {
+	minimalRedraw.Store(on)
+}
+
+func currentLatencyScale() latencyScale {
+	scale := latencyScales[CurrentLatencyProfile()]
+	if minimalRedraw.Load() {
+		scale.frame *= reducedMotionFrameScale
+		scale.snapshot *= reducedMotionFrameScale
+	}
+	return scale
+}
+
+func scaleDuration(d time.Duration, factor float64) time.Duration {
+	if factor == 1 || d <= 0 {
+		return d
+	}
+	scaled := time.Duration(float64(d) * factor)
+	if scaled < time.Millisecond {
+		scaled = time.Millisecond
+	}
+	return scaled
+}
+
+// ScaleFlushInterval applies the active profile to a flush quiet period or
+// ceiling.
+func ScaleFlushInterval(d time.Duration) time.Duration {
+	return scaleDuration(d, currentLatencyScale().flush)
+}
+
+// ScaleFrameInterval applies the active profile to a reader frame interval.
+func ScaleFrameInterval(d time.Duration) time.Duration {
+	return scaleDuration(d, currentLatencyScale().frame)
+}
+
+// ScaleSnapshotInterval applies the active profile to a background
+// snapshot or activity-scan interval.
+func ScaleSnapshotInterval(d time.Duration) time.Duration {
+	return scaleDuration(d, currentLatencyScale().snapshot)
+}
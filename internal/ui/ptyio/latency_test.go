@@ -0,0 +1,76 @@
+package ptyio
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseLatencyProfile(t *testing.T) {
+	tests := []struct {
+		name string
+		want LatencyProfile
+		ok   bool
+	}{
+		{"", LatencyProfileBalanced, true},
+		{"snappy", LatencyProfileSnappy, true},
+		{" Battery ", LatencyProfileBattery, true},
+		{"turbo", LatencyProfileBalanced, false},
+	}
+	for _, tt := range tests {
+		got, ok := ParseLatencyProfile(tt.name)
+		if got != tt.want || ok != tt.ok {
+			t.Fatalf("ParseLatencyProfile(%q) = %q, %v; want %q, %v", tt.name, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestLatencyProfileScalesIntervals(t *testing.T) {
+	t.Cleanup(func() { SetLatencyProfile(LatencyProfileBalanced) })
+
+	SetLatencyProfile(LatencyProfileBalanced)
+	if got := ScaleFlushInterval(8 * time.Millisecond); got != 8*time.Millisecond {
+		t.Fatalf("balanced flush = %v, want unchanged", got)
+	}
+
+	SetLatencyProfile(LatencyProfileSnappy)
+	if got := ScaleFrameInterval(16 * time.Millisecond); got != 8*time.Millisecond {
+		t.Fatalf("snappy frame = %v, want 8ms", got)
+	}
+	if got := ScaleFlushInterval(time.Millisecond); got != time.Millisecond {
+		t.Fatalf("snappy flush = %v, want 1ms floor", got)
+	}
+
+	SetLatencyProfile(LatencyProfileBattery)
+	if got := ScaleSnapshotInterval(time.Second); got != 3*time.Second {
+		t.Fatalf("battery snapshot = %v, want 3s", got)
+	}
+
+	SetLatencyProfile("bogus")
+	if got := CurrentLatencyProfile(); got != LatencyProfileBalanced {
+		t.Fatalf("unknown profile installed as %q, want balanced", got)
+	}
+}
+
+func TestMinimalRedrawSlowsFramesNotFlushes(t *testing.T) {
+	t.Cleanup(func() {
+		SetMinimalRedraw(false)
+		SetLatencyProfile(LatencyProfileBalanced)
+	})
+
+	SetLatencyProfile(LatencyProfileBattery)
+	SetMinimalRedraw(true)
+	if got := ScaleFrameInterval(16 * time.Millisecond); got != 64*time.Millisecond {
+		t.Fatalf("battery frame with minimal redraw = %v, want 64ms", got)
+	}
+	if got := ScaleSnapshotInterval(time.Second); got != 6*time.Second {
+		t.Fatalf("battery snapshot with minimal redraw = %v, want 6s", got)
+	}
+	if got := ScaleFlushInterval(8 * time.Millisecond); got != 16*time.Millisecond {
+		t.Fatalf("flush = %v, want the profile's 16ms only", got)
+	}
+
+	SetMinimalRedraw(false)
+	if got := ScaleFrameInterval(16 * time.Millisecond); got != 32*time.Millisecond {
+		t.Fatalf("battery frame = %v, want 32ms", got)
+	}
+}
@@ -28,6 +28,10 @@ type PTYReaderConfig struct {
 	ReadQueueSize   int
 	FrameInterval   time.Duration
 	MaxPendingBytes int
+	// Gate, when set, is waited on before every read so the consumer can
+	// stop reading (and let the kernel buffer push back on the producer)
+	// while it is behind. Nil never pauses.
+	Gate *FlowGate
 }
 
 // PTYMsgFactory creates tea.Msg values from PTY events.
@@ -79,6 +83,9 @@ func RunPTYReader(
 				return
 			default:
 			}
+			if cfg.Gate != nil && !cfg.Gate.Wait(cancel) {
+				return
+			}
 			if deadlineSupported {
 				if err := deadliner.SetReadDeadline(time.Now().Add(ptyReadDeadlineInterval)); err != nil {
 					deadlineSupported = false
@@ -121,7 +128,8 @@ func RunPTYReader(
 		if flushInterval <= 0 {
 			flushInterval = 40 * time.Millisecond
 		}
-		flushTicker = time.NewTicker(flushInterval)
+		// Resolved per burst so a latency profile switch applies live.
+		flushTicker = time.NewTicker(ScaleFrameInterval(flushInterval))
 		flushTick = flushTicker.C
 	}
 	stopFlushTicker := func() {
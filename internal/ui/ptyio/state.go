@@ -50,6 +50,9 @@ type State struct {
 	ReaderGen uint64
 	// Heartbeat is the last reader read time in nanoseconds. Atomic.
 	Heartbeat int64
+	// Flow pauses the reader while the consumer's backlog is over the flow
+	// control thresholds. Safe for concurrent use; see FlowGate.
+	Flow FlowGate
 
 	// RestartBackoff/RestartCount/RestartSince implement exponential backoff
 	// for reader restarts within a rolling window.
@@ -0,0 +1,79 @@
+package sidebar
+
+import (
+	"strconv"
+
+	"github.com/andyrewlee/amux/internal/testrun"
+)
+
+// SetCoverage records the coverage report from a test run in the worktree at
+// root. A nil report clears it.
+func (m *Model) SetCoverage(root string, report *testrun.CoverageReport) {
+	if root == "" {
+		return
+	}
+	if report == nil {
+		delete(m.coverage, root)
+		return
+	}
+	if m.coverage == nil {
+		m.coverage = make(map[string]*testrun.CoverageReport)
+	}
+	m.coverage[root] = report
+}
+
+// SetCoverage forwards a coverage report to the changes view.
+func (m *TabbedSidebar) SetCoverage(root string, report *testrun.CoverageReport) {
+	m.changes.SetCoverage(root, report)
+}
+
+// currentCoverage returns the report for the workspace on screen, or nil.
+func (m *Model) currentCoverage() *testrun.CoverageReport {
+	if m.workspace == nil {
+		return nil
+	}
+	return m.coverage[m.workspace.Root]
+}
+
+// renderCoverageBadge renders "cov N% · diff M%" for the last test run, or ""
+// when it produced no coverage report.
+func (m *Model) renderCoverageBadge() string {
+	report := m.currentCoverage()
+	if report == nil || report.Total.Total == 0 {
+		return ""
+	}
+	badge := m.styles.Muted.Render("cov ") + m.renderPercent(report.Total)
+	if report.Diff.Total > 0 {
+		badge += m.styles.Muted.Render(" · diff ") + m.renderPercent(report.Diff)
+	}
+	return badge
+}
+
+// renderFileCoverage renders a changed file's diff coverage, or "" when the
+// report has none for it.
+func (m *Model) renderFileCoverage(path string) string {
+	report := m.currentCoverage()
+	if report == nil {
+		return ""
+	}
+	ratio, ok := report.Files[path]
+	if !ok {
+		return ""
+	}
+	return m.renderPercent(ratio)
+}
+
+// renderPercent colors a coverage percentage: 80% and up is good, under 50%
+// is poor.
+func (m *Model) renderPercent(r testrun.Ratio) string {
+	pct := r.Percent()
+	text := strconv.Itoa(pct) + "%"
+	switch {
+	case pct >= 80:
+		return m.styles.StatusAdded.Render(text)
+	case pct >= 50:
+		return m.styles.StatusModified.Render(text)
+	default:
+		return m.styles.StatusDeleted.Render(text)
+	}
+}
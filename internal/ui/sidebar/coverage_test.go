@@ -0,0 +1,49 @@
+package sidebar
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/x/ansi"
+
+	"github.com/andyrewlee/amux/internal/data"
+	"github.com/andyrewlee/amux/internal/git"
+	"github.com/andyrewlee/amux/internal/testrun"
+)
+
+func TestViewRendersCoverage(t *testing.T) {
+	m := New()
+	m.SetSize(60, 12)
+	m.SetWorkspace(&data.Workspace{Branch: "feature/widget", Root: "/repo/ws"})
+	m.SetGitStatus(&git.StatusResult{
+		Unstaged: []git.Change{
+			{Path: "alpha.go", Kind: git.ChangeModified},
+			{Path: "beta.go", Kind: git.ChangeModified},
+		},
+	})
+	header := m.listHeaderLines()
+
+	m.SetCoverage("/repo/ws", &testrun.CoverageReport{
+		Total: testrun.Ratio{Covered: 78, Total: 100},
+		Diff:  testrun.Ratio{Covered: 2, Total: 3},
+		Files: map[string]testrun.Ratio{"alpha.go": {Covered: 1, Total: 4}},
+	})
+	out := ansi.Strip(m.View())
+	if !strings.Contains(out, "cov 78% · diff 66%") {
+		t.Fatalf("View() should render the coverage badge, got %q", out)
+	}
+	if !strings.Contains(out, "alpha.go 25%") {
+		t.Fatalf("View() should render the file's diff coverage, got %q", out)
+	}
+	if strings.Contains(out, "beta.go ") {
+		t.Fatalf("beta.go has no coverage and should render bare, got %q", out)
+	}
+	if got := m.listHeaderLines(); got != header+1 {
+		t.Fatalf("listHeaderLines() = %d, want %d with the coverage line", got, header+1)
+	}
+
+	m.SetCoverage("/repo/ws", nil)
+	if out := ansi.Strip(m.View()); strings.Contains(out, "cov ") {
+		t.Fatalf("View() should drop cleared coverage, got %q", out)
+	}
+}
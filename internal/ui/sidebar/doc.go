@@ -1,3 +1,4 @@
-// Package sidebar implements the sidebar pane: the workspace file tree plus an
-// embedded tmux-backed terminal with its own PTY I/O and text selection.
+// Package sidebar implements the sidebar pane: the workspace file tree and
+// notes scratchpad plus an embedded tmux-backed terminal with its own PTY I/O
+// and text selection.
 package sidebar
@@ -8,6 +8,7 @@ import (
 
 	"github.com/andyrewlee/amux/internal/data"
 	"github.com/andyrewlee/amux/internal/git"
+	"github.com/andyrewlee/amux/internal/testrun"
 	"github.com/andyrewlee/amux/internal/ui/common"
 )
 
@@ -53,6 +54,10 @@ type Model struct {
 	aheadBehindErr    error
 	aheadBehindLoadID int // guards a stale refresh from clobbering a newer one
 
+	// coverage is the last test run's coverage report per worktree root
+	// (coverage.go), shown as a badge and per changed file.
+	coverage map[string]*testrun.CoverageReport
+
 	// Display list (flattened from grouped status, or from branchChanges when
 	// branchMode is active)
 	displayItems []displayItem
@@ -200,6 +205,9 @@ func (m *Model) listHeaderLines() int {
 	if m.workspace != nil && m.workspace.Branch != "" {
 		header++
 	}
+	if m.renderCoverageBadge() != "" {
+		header++
+	}
 	if m.filterMode || m.filterQuery != "" {
 		header++
 	}
@@ -69,6 +69,10 @@ func (m *Model) renderChanges() string {
 		}
 		b.WriteString("\n")
 	}
+	if badge := m.renderCoverageBadge(); badge != "" {
+		b.WriteString(badge)
+		b.WriteString("\n")
+	}
 
 	// Filter input when in filter mode
 	if m.filterMode {
@@ -213,8 +217,14 @@ func (m *Model) renderDisplayItemRows() string {
 		prefix := cursor + statusStyle.Render(statusCode) + " "
 		prefixWidth := lipgloss.Width(prefix)
 
-		// Calculate max path width, leaving room for prefix
-		maxPathWidth := m.width - prefixWidth
+		// Diff coverage from the last test run, right after the path
+		coverage := m.renderFileCoverage(item.change.Path)
+		if coverage != "" {
+			coverage = " " + coverage
+		}
+
+		// Calculate max path width, leaving room for prefix and coverage
+		maxPathWidth := m.width - prefixWidth - lipgloss.Width(coverage)
 		if maxPathWidth < 5 {
 			maxPathWidth = 5
 		}
@@ -231,7 +241,7 @@ func (m *Model) renderDisplayItemRows() string {
 			displayPath = "..." + string(runes)
 		}
 
-		line := prefix + m.styles.FilePath.Render(displayPath)
+		line := prefix + m.styles.FilePath.Render(displayPath) + coverage
 		b.WriteString(line + "\n")
 	}
 
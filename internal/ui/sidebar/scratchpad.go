@@ -0,0 +1,397 @@
+package sidebar
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"charm.land/bubbles/v2/key"
+	"charm.land/bubbles/v2/textarea"
+	tea "charm.land/bubbletea/v2"
+	"charm.land/lipgloss/v2"
+
+	"github.com/andyrewlee/amux/internal/data"
+	"github.com/andyrewlee/amux/internal/logging"
+	"github.com/andyrewlee/amux/internal/messages"
+	"github.com/andyrewlee/amux/internal/ui/common"
+)
+
+// scratchpadSaveDelay is how long the scratchpad waits after the last edit
+// before saving.
+const scratchpadSaveDelay = time.Second
+
+var (
+	scratchpadMarkKey    = key.NewBinding(key.WithKeys("ctrl+x"))
+	scratchpadSendSelKey = key.NewBinding(key.WithKeys("ctrl+r"))
+	scratchpadSendAllKey = key.NewBinding(key.WithKeys("ctrl+s"))
+)
+
+// ScratchpadStore loads and saves each workspace's scratchpad;
+// *data.WorkspaceStore implements it.
+type ScratchpadStore interface {
+	LoadScratchpad(id data.WorkspaceID) (string, error)
+	SaveScratchpad(id data.WorkspaceID, text string) error
+}
+
+// ScratchpadLoaded carries a workspace's saved scratchpad.
+type ScratchpadLoaded struct {
+	WorkspaceID data.WorkspaceID
+	Text        string
+	Err         error
+}
+
+// ScratchpadSaveDue fires scratchpadSaveDelay after an edit; the save goes
+// ahead only if no later edit has come in.
+type ScratchpadSaveDue struct {
+	WorkspaceID data.WorkspaceID
+	Edit        int
+}
+
+// ScratchpadSaved reports the result of saving a scratchpad up to Edit.
+type ScratchpadSaved struct {
+	WorkspaceID data.WorkspaceID
+	Edit        int
+	Err         error
+}
+
+// Scratchpad is a markdown notes buffer kept per workspace, for drafting
+// prompts and sending them to the workspace's agent. It saves itself a
+// moment after each edit and when the workspace changes.
+type Scratchpad struct {
+	store     ScratchpadStore
+	workspace *data.Workspace
+	editor    textarea.Model
+	loaded    bool
+	// edit counts changes to the buffer; saved is the edit last written.
+	// Both keep counting across workspaces so a stale save never matches.
+	edit  int
+	saved int
+	err   error
+	// mark is the line where the selection starts, or -1 for none. The
+	// selection runs from it to the cursor's line.
+	mark int
+
+	focused         bool
+	width           int
+	height          int
+	showKeymapHints bool
+
+	styles common.Styles
+}
+
+// NewScratchpad creates an empty scratchpad with no workspace.
+func NewScratchpad() *Scratchpad {
+	editor := textarea.New()
+	editor.Prompt = ""
+	editor.ShowLineNumbers = false
+	editor.CharLimit = 0
+	editor.MaxHeight = 0
+	editor.Placeholder = "Notes and prompt drafts for this workspace"
+	m := &Scratchpad{
+		editor: editor,
+		loaded: true,
+		mark:   -1,
+		styles: common.DefaultStyles(),
+	}
+	m.applyEditorStyles()
+	return m
+}
+
+// SetStore sets where scratchpads are loaded from and saved to. Without a
+// store the scratchpad keeps its notes in memory only.
+func (m *Scratchpad) SetStore(store ScratchpadStore) {
+	m.store = store
+}
+
+// SetShowKeymapHints controls whether helper text is rendered.
+func (m *Scratchpad) SetShowKeymapHints(show bool) {
+	m.showKeymapHints = show
+}
+
+// SetStyles updates the component's styles (for theme changes).
+func (m *Scratchpad) SetStyles(styles common.Styles) {
+	m.styles = styles
+	m.applyEditorStyles()
+}
+
+func (m *Scratchpad) applyEditorStyles() {
+	styles := textarea.DefaultDarkStyles()
+	for _, state := range []*textarea.StyleState{&styles.Focused, &styles.Blurred} {
+		state.Base = lipgloss.NewStyle()
+		state.CursorLine = lipgloss.NewStyle()
+		state.Text = lipgloss.NewStyle().Foreground(common.ColorForeground())
+		state.Placeholder = m.styles.Muted
+		state.EndOfBuffer = m.styles.Muted
+	}
+	styles.Cursor.Color = common.ColorPrimary()
+	styles.Cursor.Blink = false
+	m.editor.SetStyles(styles)
+}
+
+// SetSize sets the scratchpad size, including the status line and any help.
+func (m *Scratchpad) SetSize(width, height int) {
+	m.width = width
+	m.height = height
+	m.editor.SetWidth(max(width, 1))
+	m.editor.SetHeight(max(height-1-m.helpLineCount(), 1))
+}
+
+// Focus sets the focus state.
+func (m *Scratchpad) Focus() {
+	m.focused = true
+	m.editor.Focus()
+}
+
+// Blur removes focus.
+func (m *Scratchpad) Blur() {
+	m.focused = false
+	m.editor.Blur()
+}
+
+// SetWorkspace switches to ws's notes. It returns commands that save the
+// previous workspace's unsaved edits and load ws's notes; a rebind of the
+// same workspace keeps the buffer as it is.
+func (m *Scratchpad) SetWorkspace(ws *data.Workspace) tea.Cmd {
+	if m.workspace != nil && ws != nil && m.workspace.ID() == ws.ID() {
+		m.workspace = ws
+		return nil
+	}
+	saveCmd := m.saveCmd()
+	m.workspace = ws
+	m.editor.Reset()
+	m.mark = -1
+	m.err = nil
+	m.edit++
+	m.saved = m.edit
+	if ws == nil || m.store == nil {
+		m.loaded = true
+		return saveCmd
+	}
+	m.loaded = false
+	store, id := m.store, ws.ID()
+	return common.SafeBatch(saveCmd, func() tea.Msg {
+		text, err := store.LoadScratchpad(id)
+		return ScratchpadLoaded{WorkspaceID: id, Text: text, Err: err}
+	})
+}
+
+// saveCmd returns a command saving the current buffer, or nil when it has
+// no unsaved edits.
+func (m *Scratchpad) saveCmd() tea.Cmd {
+	if m.store == nil || m.workspace == nil || !m.loaded || m.edit == m.saved {
+		return nil
+	}
+	store, id, text, edit := m.store, m.workspace.ID(), m.editor.Value(), m.edit
+	return func() tea.Msg {
+		err := store.SaveScratchpad(id, text)
+		if err != nil {
+			logging.Warn("scratchpad: %v", err)
+		}
+		return ScratchpadSaved{WorkspaceID: id, Edit: edit, Err: err}
+	}
+}
+
+// Flush saves any unsaved edits before returning.
+func (m *Scratchpad) Flush() {
+	if cmd := m.saveCmd(); cmd != nil {
+		cmd()
+	}
+}
+
+func (m *Scratchpad) isCurrent(id data.WorkspaceID) bool {
+	return m.workspace != nil && m.workspace.ID() == id
+}
+
+// Update handles messages.
+func (m *Scratchpad) Update(msg tea.Msg) (*Scratchpad, tea.Cmd) {
+	switch msg := msg.(type) {
+	case ScratchpadLoaded:
+		if !m.isCurrent(msg.WorkspaceID) || m.loaded {
+			return m, nil
+		}
+		// An unreadable file stays unloaded so edits can't overwrite it.
+		m.err = msg.Err
+		if msg.Err != nil {
+			return m, nil
+		}
+		m.loaded = true
+		m.editor.SetValue(msg.Text)
+		m.editor.MoveToBegin()
+		return m, nil
+	case ScratchpadSaveDue:
+		if !m.isCurrent(msg.WorkspaceID) || msg.Edit != m.edit {
+			return m, nil
+		}
+		return m, m.saveCmd()
+	case ScratchpadSaved:
+		if !m.isCurrent(msg.WorkspaceID) {
+			return m, nil
+		}
+		m.err = msg.Err
+		if msg.Err == nil && msg.Edit > m.saved {
+			m.saved = msg.Edit
+		}
+		return m, nil
+	}
+
+	if !m.focused || m.workspace == nil || !m.loaded {
+		return m, nil
+	}
+	if msg, ok := msg.(tea.KeyPressMsg); ok {
+		switch {
+		case key.Matches(msg, scratchpadMarkKey):
+			if m.mark >= 0 {
+				m.mark = -1
+			} else {
+				m.mark = m.editor.Line()
+			}
+			return m, nil
+		case key.Matches(msg, scratchpadSendSelKey):
+			if m.mark < 0 {
+				return m, toastCmd(messages.ToastInfo, "Press ctrl+x to start a selection, then move to its last line")
+			}
+			text := m.selection()
+			m.mark = -1
+			return m, m.sendCmd(text)
+		case key.Matches(msg, scratchpadSendAllKey):
+			return m, m.sendCmd(m.editor.Value())
+		case msg.Key().Code == tea.KeyEsc && m.mark >= 0:
+			m.mark = -1
+			return m, nil
+		}
+	}
+	switch msg.(type) {
+	case tea.KeyPressMsg, tea.PasteMsg:
+	default:
+		return m, nil
+	}
+
+	before := m.editor.Value()
+	var cmd tea.Cmd
+	m.editor, cmd = m.editor.Update(msg)
+	if m.editor.Value() == before {
+		return m, cmd
+	}
+	m.edit++
+	if m.mark >= m.editor.LineCount() {
+		m.mark = m.editor.LineCount() - 1
+	}
+	id, edit := m.workspace.ID(), m.edit
+	return m, common.SafeBatch(cmd, common.SafeTick(scratchpadSaveDelay, func(time.Time) tea.Msg {
+		return ScratchpadSaveDue{WorkspaceID: id, Edit: edit}
+	}))
+}
+
+// selection returns the lines from the mark to the cursor's line.
+func (m *Scratchpad) selection() string {
+	first, last := m.selectedLines()
+	lines := strings.Split(m.editor.Value(), "\n")
+	if last >= len(lines) {
+		last = len(lines) - 1
+	}
+	if first > last {
+		return ""
+	}
+	return strings.Join(lines[first:last+1], "\n")
+}
+
+// selectedLines returns the first and last selected line, zero-based.
+func (m *Scratchpad) selectedLines() (int, int) {
+	first, last := m.mark, m.editor.Line()
+	if first > last {
+		first, last = last, first
+	}
+	return first, last
+}
+
+func (m *Scratchpad) sendCmd(text string) tea.Cmd {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return toastCmd(messages.ToastInfo, "Nothing to send")
+	}
+	ws := m.workspace
+	return func() tea.Msg { return messages.SendToAgent{Workspace: ws, Text: text} }
+}
+
+func toastCmd(level messages.ToastLevel, text string) tea.Cmd {
+	return func() tea.Msg { return messages.Toast{Message: text, Level: level} }
+}
+
+// Value returns the scratchpad's text.
+func (m *Scratchpad) Value() string {
+	return m.editor.Value()
+}
+
+// SetValue replaces ws's notes with text and returns the command saving
+// them. It reports false, changing nothing, when ws's notes aren't the ones
+// showing or haven't loaded yet.
+func (m *Scratchpad) SetValue(ws *data.Workspace, text string) (tea.Cmd, bool) {
+	if ws == nil || !m.isCurrent(ws.ID()) || !m.loaded {
+		return nil, false
+	}
+	m.editor.SetValue(text)
+	m.editor.MoveToBegin()
+	m.mark = -1
+	m.edit++
+	return m.saveCmd(), true
+}
+
+// View renders the scratchpad.
+func (m *Scratchpad) View() string {
+	var content string
+	switch {
+	case m.workspace == nil:
+		content = m.styles.Muted.Render("No workspace selected")
+	case !m.loaded && m.err != nil:
+		content = m.styles.Error.Render("Could not load notes: " + m.err.Error())
+	case !m.loaded:
+		content = m.styles.Muted.Render("Loading notes...")
+	default:
+		content = m.editor.View() + "\n" + m.statusLine()
+	}
+	lines := strings.Split(content, "\n")
+	var help []string
+	if m.showKeymapHints {
+		help = m.helpLines(max(m.width, 1))
+	}
+	for len(lines) < m.height-len(help) {
+		lines = append(lines, "")
+	}
+	lines = append(lines, help...)
+	if m.height > 0 && len(lines) > m.height {
+		lines = lines[:m.height]
+	}
+	return strings.Join(lines, "\n")
+}
+
+func (m *Scratchpad) statusLine() string {
+	switch {
+	case m.err != nil:
+		return m.styles.Error.Render("Not saved: " + m.err.Error())
+	case m.mark >= 0:
+		first, last := m.selectedLines()
+		if first == last {
+			return m.styles.Info.Render(fmt.Sprintf("Line %d selected", first+1))
+		}
+		return m.styles.Info.Render(fmt.Sprintf("Lines %d-%d selected", first+1, last+1))
+	case m.edit != m.saved:
+		return m.styles.Muted.Render("Editing...")
+	}
+	return ""
+}
+
+func (m *Scratchpad) helpLines(contentWidth int) []string {
+	items := []string{
+		common.RenderHelpItem(m.styles, "ctrl+x", "select"),
+		common.RenderHelpItem(m.styles, "ctrl+r", "send selection"),
+		common.RenderHelpItem(m.styles, "ctrl+s", "send all"),
+	}
+	return common.WrapHelpItems(items, contentWidth)
+}
+
+func (m *Scratchpad) helpLineCount() int {
+	if !m.showKeymapHints {
+		return 0
+	}
+	return len(m.helpLines(max(m.width, 1)))
+}
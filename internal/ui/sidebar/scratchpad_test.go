@@ -0,0 +1,152 @@
+package sidebar
+
+import (
+	"strings"
+	"testing"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/charmbracelet/x/ansi"
+
+	"github.com/andyrewlee/amux/internal/data"
+	"github.com/andyrewlee/amux/internal/messages"
+)
+
+type fakeScratchpadStore struct {
+	notes map[data.WorkspaceID]string
+	saves int
+}
+
+func (s *fakeScratchpadStore) LoadScratchpad(id data.WorkspaceID) (string, error) {
+	return s.notes[id], nil
+}
+
+func (s *fakeScratchpadStore) SaveScratchpad(id data.WorkspaceID, text string) error {
+	s.notes[id] = text
+	s.saves++
+	return nil
+}
+
+func typeInto(s *TabbedSidebar, text string) {
+	for _, r := range text {
+		s.Update(tea.KeyPressMsg{Code: r, Text: string(r)})
+	}
+}
+
+// runCmd runs cmd and feeds the scratchpad messages it produces back into s,
+// returning any other messages.
+func runCmd(s *TabbedSidebar, cmd tea.Cmd) []tea.Msg {
+	if cmd == nil {
+		return nil
+	}
+	var out []tea.Msg
+	switch msg := cmd().(type) {
+	case tea.BatchMsg:
+		for _, c := range msg {
+			out = append(out, runCmd(s, c)...)
+		}
+	case ScratchpadLoaded, ScratchpadSaved:
+		s.Update(msg)
+	default:
+		out = append(out, msg)
+	}
+	return out
+}
+
+func newNotesSidebar(t *testing.T, store *fakeScratchpadStore, ws *data.Workspace) *TabbedSidebar {
+	t.Helper()
+	s := newTestTabbedSidebar(t)
+	s.SetScratchpadStore(store)
+	s.SetSize(40, 20)
+	runCmd(s, s.SetWorkspace(ws))
+	s.SetActiveTab(TabNotes)
+	s.Focus()
+	return s
+}
+
+func TestScratchpadLoadsEditsAndAutosaves(t *testing.T) {
+	ws := &data.Workspace{Name: "feature", Repo: "/repo", Root: "/repo/ws"}
+	other := &data.Workspace{Name: "other", Repo: "/repo", Root: "/repo/other"}
+	store := &fakeScratchpadStore{notes: map[data.WorkspaceID]string{ws.ID(): "saved"}}
+	s := newNotesSidebar(t, store, ws)
+	if got := s.Scratchpad().Value(); got != "saved" {
+		t.Fatalf("loaded notes = %q, want %q", got, "saved")
+	}
+
+	s.Scratchpad().editor.MoveToEnd()
+	typeInto(s, " 12")
+	if s.ActiveTab() != TabNotes || s.Scratchpad().Value() != "saved 12" {
+		t.Fatalf("digits should be typed into the notes, got tab %d value %q", s.ActiveTab(), s.Scratchpad().Value())
+	}
+
+	// A save due for an earlier edit is skipped; the latest one saves.
+	s.Update(ScratchpadSaveDue{WorkspaceID: ws.ID(), Edit: s.Scratchpad().edit - 1})
+	_, cmd := s.Update(ScratchpadSaveDue{WorkspaceID: ws.ID(), Edit: s.Scratchpad().edit})
+	runCmd(s, cmd)
+	if store.saves != 1 || store.notes[ws.ID()] != "saved 12" {
+		t.Fatalf("saves = %d, notes = %q", store.saves, store.notes[ws.ID()])
+	}
+
+	// Switching workspaces saves unsaved edits before loading the next.
+	typeInto(s, "3")
+	runCmd(s, s.SetWorkspace(other))
+	if store.notes[ws.ID()] != "saved 123" || s.Scratchpad().Value() != "" {
+		t.Fatalf("notes = %q, buffer = %q", store.notes[ws.ID()], s.Scratchpad().Value())
+	}
+}
+
+func TestScratchpadSendsSelectionOrWholeBuffer(t *testing.T) {
+	ws := &data.Workspace{Name: "feature", Repo: "/repo", Root: "/repo/ws"}
+	store := &fakeScratchpadStore{notes: map[data.WorkspaceID]string{ws.ID(): "intro\nfix the cache\nadd a test\noutro"}}
+	s := newNotesSidebar(t, store, ws)
+
+	send := func(msg tea.KeyPressMsg) tea.Msg {
+		t.Helper()
+		_, cmd := s.Update(msg)
+		if cmd == nil {
+			t.Fatal("expected a command")
+		}
+		return cmd()
+	}
+
+	if got := send(tea.KeyPressMsg{Code: 'r', Mod: tea.ModCtrl}); got.(messages.Toast).Level != messages.ToastInfo {
+		t.Fatalf("sending with no selection = %#v, want a hint", got)
+	}
+
+	s.Update(tea.KeyPressMsg{Code: tea.KeyDown})
+	s.Update(tea.KeyPressMsg{Code: 'x', Mod: tea.ModCtrl})
+	s.Update(tea.KeyPressMsg{Code: tea.KeyDown})
+	if view := ansi.Strip(s.Scratchpad().View()); !strings.Contains(view, "Lines 2-3 selected") {
+		t.Fatalf("view missing selection status:\n%s", view)
+	}
+	got := send(tea.KeyPressMsg{Code: 'r', Mod: tea.ModCtrl}).(messages.SendToAgent)
+	if got.Workspace != ws || got.Text != "fix the cache\nadd a test" {
+		t.Fatalf("sent %#v", got)
+	}
+	if s.Scratchpad().mark != -1 {
+		t.Fatal("sending should clear the selection")
+	}
+
+	got = send(tea.KeyPressMsg{Code: 's', Mod: tea.ModCtrl}).(messages.SendToAgent)
+	if got.Text != "intro\nfix the cache\nadd a test\noutro" {
+		t.Fatalf("sent %q, want the whole buffer", got.Text)
+	}
+}
+
+func TestScratchpadSetValueReplacesAndSaves(t *testing.T) {
+	ws := &data.Workspace{Name: "feature", Repo: "/repo", Root: "/repo/ws"}
+	other := &data.Workspace{Name: "other", Repo: "/repo", Root: "/repo/other"}
+	store := &fakeScratchpadStore{notes: map[data.WorkspaceID]string{ws.ID(): "saved"}}
+	s := newNotesSidebar(t, store, ws)
+
+	if _, ok := s.Scratchpad().SetValue(other, "drafted"); ok {
+		t.Fatal("SetValue should refuse a workspace whose notes aren't showing")
+	}
+	cmd, ok := s.Scratchpad().SetValue(ws, "drafted")
+	if !ok {
+		t.Fatal("SetValue refused the current workspace")
+	}
+	runCmd(s, cmd)
+	if s.Scratchpad().Value() != "drafted" || store.notes[ws.ID()] != "drafted" {
+		t.Fatalf("buffer = %q, notes = %q", s.Scratchpad().Value(), store.notes[ws.ID()])
+	}
+}
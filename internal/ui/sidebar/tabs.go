@@ -18,6 +18,7 @@ type SidebarTab int
 const (
 	TabChanges SidebarTab = iota
 	TabProject
+	TabNotes
 )
 
 // tabHitKind identifies the type of tab bar click target
@@ -26,6 +27,7 @@ type tabHitKind int
 const (
 	tabHitChanges tabHitKind = iota
 	tabHitProject
+	tabHitNotes
 )
 
 // tabHit represents a clickable region in the tab bar
@@ -34,11 +36,25 @@ type tabHit struct {
 	region common.HitRegion
 }
 
-// TabbedSidebar wraps the Changes and Project views with tabs
+// sidebarTabs lists the tabs in tab-bar order with their labels and the
+// digit key that selects them.
+var sidebarTabs = []struct {
+	tab   SidebarTab
+	hit   tabHitKind
+	label string
+	key   string
+}{
+	{TabChanges, tabHitChanges, "Changes", "1"},
+	{TabProject, tabHitProject, "Project", "2"},
+	{TabNotes, tabHitNotes, "Notes", "3"},
+}
+
+// TabbedSidebar wraps the Changes, Project, and Notes views with tabs
 type TabbedSidebar struct {
 	activeTab   SidebarTab
 	changes     *Model
 	projectTree *ProjectTree
+	scratchpad  *Scratchpad
 	tabHits     []tabHit
 	// tabBarVersion is a monotonic version of every input that shapes the
 	// tab bar render (active tab, styles/theme). INVARIANT: every update path
@@ -65,6 +81,7 @@ func NewTabbedSidebar() *TabbedSidebar {
 		activeTab:   TabChanges,
 		changes:     New(),
 		projectTree: NewProjectTree(),
+		scratchpad:  NewScratchpad(),
 		styles:      common.DefaultStyles(),
 	}
 }
@@ -74,6 +91,7 @@ func (m *TabbedSidebar) SetShowKeymapHints(show bool) {
 	m.showKeymapHints = show
 	m.changes.SetShowKeymapHints(show)
 	m.projectTree.SetShowKeymapHints(show)
+	m.scratchpad.SetShowKeymapHints(show)
 }
 
 // SetStyles updates the component's styles (for theme changes).
@@ -82,6 +100,7 @@ func (m *TabbedSidebar) SetStyles(styles common.Styles) {
 	m.markTabBarDirty()
 	m.changes.SetStyles(styles)
 	m.projectTree.SetStyles(styles)
+	m.scratchpad.SetStyles(styles)
 }
 
 // Init initializes the tabbed sidebar
@@ -94,8 +113,6 @@ func (m *TabbedSidebar) Init() tea.Cmd {
 
 // Update handles messages
 func (m *TabbedSidebar) Update(msg tea.Msg) (*TabbedSidebar, tea.Cmd) {
-	var cmds []tea.Cmd
-
 	// Handle tab switching on mouse click
 	switch msg := msg.(type) {
 	case BranchChangesLoaded, AheadBehindLoaded:
@@ -108,20 +125,21 @@ func (m *TabbedSidebar) Update(msg tea.Msg) (*TabbedSidebar, tea.Cmd) {
 		m.changes, cmd = m.changes.Update(msg)
 		return m, cmd
 
+	case ScratchpadLoaded, ScratchpadSaveDue, ScratchpadSaved:
+		// Loads and autosaves land even while another tab is active.
+		var cmd tea.Cmd
+		m.scratchpad, cmd = m.scratchpad.Update(msg)
+		return m, cmd
+
 	case tea.MouseClickMsg:
 		if msg.Button == tea.MouseLeft && msg.Y == 0 {
 			// Check if click is in tab bar
 			for _, hit := range m.tabHits {
 				if hit.region.Contains(msg.X, msg.Y) {
-					switch hit.kind {
-					case tabHitChanges:
-						m.activeTab = TabChanges
-						m.markTabBarDirty()
-						m.updateFocus()
-					case tabHitProject:
-						m.activeTab = TabProject
-						m.markTabBarDirty()
-						m.updateFocus()
+					for _, t := range sidebarTabs {
+						if t.hit == hit.kind {
+							m.SetActiveTab(t.tab)
+						}
 					}
 					return m, nil
 				}
@@ -134,17 +152,7 @@ func (m *TabbedSidebar) Update(msg tea.Msg) (*TabbedSidebar, tea.Cmd) {
 			X:      msg.X,
 			Y:      msg.Y - 1, // Subtract tab bar height
 		}
-		switch m.activeTab {
-		case TabChanges:
-			var cmd tea.Cmd
-			m.changes, cmd = m.changes.Update(adjustedMsg)
-			cmds = append(cmds, cmd)
-		case TabProject:
-			var cmd tea.Cmd
-			m.projectTree, cmd = m.projectTree.Update(adjustedMsg)
-			cmds = append(cmds, cmd)
-		}
-		return m, common.SafeBatch(cmds...)
+		return m, m.updateActiveTab(adjustedMsg)
 
 	case tea.MouseWheelMsg:
 		// Adjust Y coordinate for tab bar before forwarding
@@ -153,67 +161,55 @@ func (m *TabbedSidebar) Update(msg tea.Msg) (*TabbedSidebar, tea.Cmd) {
 			X:      msg.X,
 			Y:      msg.Y - 1,
 		}
-		switch m.activeTab {
-		case TabChanges:
-			var cmd tea.Cmd
-			m.changes, cmd = m.changes.Update(adjustedMsg)
-			cmds = append(cmds, cmd)
-		case TabProject:
-			var cmd tea.Cmd
-			m.projectTree, cmd = m.projectTree.Update(adjustedMsg)
-			cmds = append(cmds, cmd)
-		}
-		return m, common.SafeBatch(cmds...)
+		return m, m.updateActiveTab(adjustedMsg)
 
 	case tea.KeyPressMsg:
 		// Tab switching with number keys when focused, but not while the Changes
-		// view is in filter mode (so digits get typed into the filter instead of
-		// silently switching tabs).
-		if m.focused && !(m.activeTab == TabChanges && m.changes.FilterActive()) {
-			switch {
-			case key.Matches(msg, key.NewBinding(key.WithKeys("1"))):
-				m.activeTab = TabChanges
-				m.markTabBarDirty()
-				m.updateFocus()
-				return m, nil
-			case key.Matches(msg, key.NewBinding(key.WithKeys("2"))):
-				m.activeTab = TabProject
-				m.markTabBarDirty()
-				m.updateFocus()
-				return m, nil
+		// view is in filter mode or in the Notes editor (so digits get typed
+		// instead of silently switching tabs).
+		typing := m.activeTab == TabNotes || (m.activeTab == TabChanges && m.changes.FilterActive())
+		if m.focused && !typing {
+			for _, t := range sidebarTabs {
+				if key.Matches(msg, key.NewBinding(key.WithKeys(t.key))) {
+					m.SetActiveTab(t.tab)
+					return m, nil
+				}
 			}
 		}
 	}
 
-	// Forward messages to active tab
+	return m, m.updateActiveTab(msg)
+}
+
+// updateActiveTab forwards msg to the active tab's view.
+func (m *TabbedSidebar) updateActiveTab(msg tea.Msg) tea.Cmd {
+	var cmd tea.Cmd
 	switch m.activeTab {
 	case TabChanges:
-		var cmd tea.Cmd
 		m.changes, cmd = m.changes.Update(msg)
-		cmds = append(cmds, cmd)
 	case TabProject:
-		var cmd tea.Cmd
 		m.projectTree, cmd = m.projectTree.Update(msg)
-		cmds = append(cmds, cmd)
+	case TabNotes:
+		m.scratchpad, cmd = m.scratchpad.Update(msg)
 	}
-
-	return m, common.SafeBatch(cmds...)
+	return cmd
 }
 
 // updateFocus ensures only the active tab is focused
 func (m *TabbedSidebar) updateFocus() {
-	if m.focused {
-		switch m.activeTab {
-		case TabChanges:
-			m.changes.Focus()
-			m.projectTree.Blur()
-		case TabProject:
-			m.changes.Blur()
-			m.projectTree.Focus()
-		}
-	} else {
-		m.changes.Blur()
-		m.projectTree.Blur()
+	m.changes.Blur()
+	m.projectTree.Blur()
+	m.scratchpad.Blur()
+	if !m.focused {
+		return
+	}
+	switch m.activeTab {
+	case TabChanges:
+		m.changes.Focus()
+	case TabProject:
+		m.projectTree.Focus()
+	case TabNotes:
+		m.scratchpad.Focus()
 	}
 }
 
@@ -230,47 +226,26 @@ func (m *TabbedSidebar) renderTabBar() string {
 
 	var tabs []string
 	x := 0
-
-	// Changes tab
-	changesLabel := "Changes"
-	var changesRendered string
-	if m.activeTab == TabChanges {
-		changesRendered = activeTabStyle.Render(changesLabel)
-	} else {
-		changesRendered = inactiveStyle.Render(m.styles.Muted.Render(changesLabel))
-	}
-	changesWidth := lipgloss.Width(changesRendered)
-	m.tabHits = append(m.tabHits, tabHit{
-		kind: tabHitChanges,
-		region: common.HitRegion{
-			X:      x,
-			Y:      0,
-			Width:  changesWidth,
-			Height: 1,
-		},
-	})
-	tabs = append(tabs, changesRendered)
-	x += changesWidth
-
-	// Project tab
-	projectLabel := "Project"
-	var projectRendered string
-	if m.activeTab == TabProject {
-		projectRendered = activeTabStyle.Render(projectLabel)
-	} else {
-		projectRendered = inactiveStyle.Render(m.styles.Muted.Render(projectLabel))
+	for _, t := range sidebarTabs {
+		var rendered string
+		if m.activeTab == t.tab {
+			rendered = activeTabStyle.Render(t.label)
+		} else {
+			rendered = inactiveStyle.Render(m.styles.Muted.Render(t.label))
+		}
+		width := lipgloss.Width(rendered)
+		m.tabHits = append(m.tabHits, tabHit{
+			kind: t.hit,
+			region: common.HitRegion{
+				X:      x,
+				Y:      0,
+				Width:  width,
+				Height: 1,
+			},
+		})
+		tabs = append(tabs, rendered)
+		x += width
 	}
-	projectWidth := lipgloss.Width(projectRendered)
-	m.tabHits = append(m.tabHits, tabHit{
-		kind: tabHitProject,
-		region: common.HitRegion{
-			X:      x,
-			Y:      0,
-			Width:  projectWidth,
-			Height: 1,
-		},
-	})
-	tabs = append(tabs, projectRendered)
 
 	return lipgloss.JoinHorizontal(lipgloss.Bottom, tabs...)
 }
@@ -293,17 +268,7 @@ func (m *TabbedSidebar) View() string {
 	b.WriteString(tabBar)
 	b.WriteString("\n")
 
-	var content string
-	switch m.activeTab {
-	case TabChanges:
-		m.changes.SetSize(m.width, contentHeight)
-		content = m.changes.View()
-	case TabProject:
-		m.projectTree.SetSize(m.width, contentHeight)
-		content = m.projectTree.View()
-	}
-
-	b.WriteString(content)
+	b.WriteString(m.ContentView())
 	return b.String()
 }
 
@@ -347,6 +312,9 @@ func (m *TabbedSidebar) ContentView() string {
 	case TabProject:
 		m.projectTree.SetSize(m.width, contentHeight)
 		return m.projectTree.View()
+	case TabNotes:
+		m.scratchpad.SetSize(m.width, contentHeight)
+		return m.scratchpad.View()
 	}
 	return ""
 }
@@ -362,6 +330,7 @@ func (m *TabbedSidebar) SetSize(width, height int) {
 	}
 	m.changes.SetSize(width, contentHeight)
 	m.projectTree.SetSize(width, contentHeight)
+	m.scratchpad.SetSize(width, contentHeight)
 }
 
 // Focus sets the focus state
@@ -373,8 +342,7 @@ func (m *TabbedSidebar) Focus() {
 // Blur removes focus
 func (m *TabbedSidebar) Blur() {
 	m.focused = false
-	m.changes.Blur()
-	m.projectTree.Blur()
+	m.updateFocus()
 }
 
 // Focused returns whether the sidebar is focused
@@ -383,12 +351,18 @@ func (m *TabbedSidebar) Focused() bool {
 }
 
 // SetWorkspace sets the active workspace. It returns the Changes view's
-// ahead/behind refresh command (nil for a no-op rebind); see Model.SetWorkspace.
+// ahead/behind refresh and the Notes view's save and load commands (nil for
+// a no-op rebind); see Model.SetWorkspace and Scratchpad.SetWorkspace.
 func (m *TabbedSidebar) SetWorkspace(ws *data.Workspace) tea.Cmd {
 	m.workspace = ws
 	cmd := m.changes.SetWorkspace(ws)
 	m.projectTree.SetWorkspace(ws)
-	return cmd
+	return common.SafeBatch(cmd, m.scratchpad.SetWorkspace(ws))
+}
+
+// SetScratchpadStore sets where the Notes view keeps each workspace's notes.
+func (m *TabbedSidebar) SetScratchpadStore(store ScratchpadStore) {
+	m.scratchpad.SetStore(store)
 }
 
 // SetGitStatus sets the git status (forwards to changes view)
@@ -416,24 +390,13 @@ func (m *TabbedSidebar) SetActiveTab(tab SidebarTab) {
 
 // NextTab switches to the next tab (circular)
 func (m *TabbedSidebar) NextTab() {
-	if m.activeTab == TabChanges {
-		m.activeTab = TabProject
-	} else {
-		m.activeTab = TabChanges
-	}
-	m.markTabBarDirty()
-	m.updateFocus()
+	m.SetActiveTab((m.activeTab + 1) % SidebarTab(len(sidebarTabs)))
 }
 
 // PrevTab switches to the previous tab (circular)
 func (m *TabbedSidebar) PrevTab() {
-	if m.activeTab == TabChanges {
-		m.activeTab = TabProject
-	} else {
-		m.activeTab = TabChanges
-	}
-	m.markTabBarDirty()
-	m.updateFocus()
+	n := SidebarTab(len(sidebarTabs))
+	m.SetActiveTab((m.activeTab + n - 1) % n)
 }
 
 // Changes returns the changes model (for direct access if needed)
@@ -445,3 +408,8 @@ func (m *TabbedSidebar) Changes() *Model {
 func (m *TabbedSidebar) ProjectTree() *ProjectTree {
 	return m.projectTree
 }
+
+// Scratchpad returns the Notes view's model (for direct access if needed)
+func (m *TabbedSidebar) Scratchpad() *Scratchpad {
+	return m.scratchpad
+}
@@ -195,17 +195,21 @@ func TestNextAndPrevTabCircular(t *testing.T) {
 		t.Fatalf("after NextTab want TabProject, got %d", s.ActiveTab())
 	}
 	s.NextTab()
+	if s.ActiveTab() != TabNotes {
+		t.Fatalf("after second NextTab want TabNotes, got %d", s.ActiveTab())
+	}
+	s.NextTab()
 	if s.ActiveTab() != TabChanges {
-		t.Fatalf("after second NextTab want wrap to TabChanges, got %d", s.ActiveTab())
+		t.Fatalf("after third NextTab want wrap to TabChanges, got %d", s.ActiveTab())
 	}
 
 	s.PrevTab()
-	if s.ActiveTab() != TabProject {
-		t.Fatalf("after PrevTab want TabProject, got %d", s.ActiveTab())
+	if s.ActiveTab() != TabNotes {
+		t.Fatalf("after PrevTab want wrap to TabNotes, got %d", s.ActiveTab())
 	}
 	s.PrevTab()
-	if s.ActiveTab() != TabChanges {
-		t.Fatalf("after second PrevTab want wrap to TabChanges, got %d", s.ActiveTab())
+	if s.ActiveTab() != TabProject {
+		t.Fatalf("after second PrevTab want TabProject, got %d", s.ActiveTab())
 	}
 }
 
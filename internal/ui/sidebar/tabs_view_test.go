@@ -14,6 +14,7 @@ func TestRenderTabBarHighlightsActiveTab(t *testing.T) {
 	}{
 		{name: "changes active", active: TabChanges},
 		{name: "project active", active: TabProject},
+		{name: "notes active", active: TabNotes},
 	}
 	for _, tc := range tests {
 		t.Run(tc.name, func(t *testing.T) {
@@ -28,9 +29,9 @@ func TestRenderTabBarHighlightsActiveTab(t *testing.T) {
 			if !strings.Contains(bar, "Project") {
 				t.Fatalf("tab bar missing Project label: %q", bar)
 			}
-			// renderTabBar must register exactly two clickable hit regions.
-			if len(s.tabHits) != 2 {
-				t.Fatalf("expected 2 tab hits, got %d", len(s.tabHits))
+			// renderTabBar must register exactly one clickable hit region per tab.
+			if len(s.tabHits) != 3 {
+				t.Fatalf("expected 3 tab hits, got %d", len(s.tabHits))
 			}
 			if s.tabHits[0].kind != tabHitChanges {
 				t.Fatalf("first hit kind = %d, want tabHitChanges", s.tabHits[0].kind)
@@ -38,6 +39,9 @@ func TestRenderTabBarHighlightsActiveTab(t *testing.T) {
 			if s.tabHits[1].kind != tabHitProject {
 				t.Fatalf("second hit kind = %d, want tabHitProject", s.tabHits[1].kind)
 			}
+			if s.tabHits[2].kind != tabHitNotes {
+				t.Fatalf("third hit kind = %d, want tabHitNotes", s.tabHits[2].kind)
+			}
 			// Hit regions must be laid out left-to-right without gaps that
 			// would make the Project tab unclickable.
 			c, p := s.tabHits[0].region, s.tabHits[1].region
@@ -47,6 +51,9 @@ func TestRenderTabBarHighlightsActiveTab(t *testing.T) {
 			if p.X != c.X+c.Width {
 				t.Fatalf("Project hit x=%d should follow Changes (x=%d w=%d)", p.X, c.X, c.Width)
 			}
+			if n := s.tabHits[2].region; n.X != p.X+p.Width {
+				t.Fatalf("Notes hit x=%d should follow Project (x=%d w=%d)", n.X, p.X, p.Width)
+			}
 			if c.Width <= 0 || p.Width <= 0 {
 				t.Fatalf("hit widths must be positive, got changes=%d project=%d", c.Width, p.Width)
 			}
@@ -63,8 +70,8 @@ func TestRenderTabBarResetsHitsAcrossCalls(t *testing.T) {
 
 	// Hits are reset (sliced to zero) each call, so repeated renders must not
 	// accumulate stale regions.
-	if len(s.tabHits) != 2 {
-		t.Fatalf("expected 2 tab hits after repeated renders, got %d", len(s.tabHits))
+	if len(s.tabHits) != 3 {
+		t.Fatalf("expected 3 tab hits after repeated renders, got %d", len(s.tabHits))
 	}
 }
 
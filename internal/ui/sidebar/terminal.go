@@ -33,6 +33,9 @@ type TerminalTab struct {
 	ID    TerminalTabID
 	Name  string // "Terminal 1", "Terminal 2", etc.
 	State *TerminalState
+	// GitTool is set for the tab running the git tool instead of a shell
+	// (terminal_git_tool.go).
+	GitTool bool
 }
 
 // TerminalState holds the terminal state for a workspace
@@ -91,6 +94,7 @@ type TerminalModel struct {
 	tabs            common.TabSet[*TerminalTab]
 	tabHits         []terminalTabHit // for mouse click handling
 	pendingCreation map[string]bool  // tracks workspaces with tab creation in progress
+	pendingGitTool  map[string]bool  // tracks workspaces with the git tool starting
 
 	// Current workspace
 	workspace *data.Workspace
@@ -115,6 +119,10 @@ type TerminalModel struct {
 	// tmux config
 	tmuxOpts   tmux.Options
 	instanceID string
+
+	// Pre-rendered chrome, rebuilt only when the segment's inputs change.
+	tabBarCache common.SegmentCache[renderedTerminalTabBar]
+	helpCache   common.SegmentCache[[]string]
 }
 
 // NewTerminalModel creates a new sidebar terminal model
@@ -122,6 +130,7 @@ func NewTerminalModel() *TerminalModel {
 	return &TerminalModel{
 		tabs:            common.NewTabSet[*TerminalTab](),
 		pendingCreation: make(map[string]bool),
+		pendingGitTool:  make(map[string]bool),
 		lastActiveAt:    make(map[string]time.Time),
 		styles:          common.DefaultStyles(),
 		tmuxOpts:        tmux.DefaultOptions(),
@@ -150,6 +159,8 @@ func (m *TerminalModel) SetShowKeymapHints(show bool) {
 // SetStyles updates the component's styles (for theme changes).
 func (m *TerminalModel) SetStyles(styles common.Styles) {
 	m.styles = styles
+	m.tabBarCache.Invalidate()
+	m.helpCache.Invalidate()
 }
 
 // SetMsgSink sets a callback for PTY messages.
@@ -166,7 +177,11 @@ func (m *TerminalModel) workspaceID() string {
 }
 
 // setWorkspace sets the current workspace reference.
+// Switching away from a workspace closes its git tool.
 func (m *TerminalModel) setWorkspace(ws *data.Workspace) {
+	if prev := m.workspaceID(); prev != "" && (ws == nil || string(ws.ID()) != prev) {
+		m.removeGitTool(prev)
+	}
 	m.workspace = ws
 	if ws != nil {
 		m.lastActiveAt[string(ws.ID())] = time.Now()
@@ -0,0 +1,134 @@
+package sidebar
+
+import (
+	"fmt"
+	"os/exec"
+
+	tea "charm.land/bubbletea/v2"
+
+	"github.com/andyrewlee/amux/internal/data"
+	"github.com/andyrewlee/amux/internal/safego"
+)
+
+// GitTool is the interactive git tool the sidebar terminal can embed in
+// place of the changes list.
+const GitTool = "lazygit"
+
+const (
+	// gitToolTabType tags the git tool's tmux session, so discovery of a
+	// workspace's terminal sessions leaves it out.
+	gitToolTabType = "git_tool"
+	// gitToolRestarts bounds restarting the git tool after it crashes.
+	gitToolRestarts = 3
+)
+
+// gitToolLookPath finds the git tool on PATH; a test seam.
+var gitToolLookPath = exec.LookPath
+
+// GitToolExited is sent when the git tool is quit and its tab closed, so
+// the changes list can take its place again.
+type GitToolExited struct {
+	WorkspaceID string
+}
+
+// gitToolCommand runs the git tool, restarting it when it exits with an
+// error, up to gitToolRestarts times; quitting it ends the session.
+func gitToolCommand() string {
+	return fmt.Sprintf(`n=0; until %s; do n=$((n+1)); [ "$n" -ge %d ] && break; sleep 1; done`, GitTool, gitToolRestarts)
+}
+
+// gitToolIdx returns the index of the workspace's git tool tab, or -1.
+func (m *TerminalModel) gitToolIdx(wsID string) int {
+	for i, tab := range m.tabs.ByWorkspace[wsID] {
+		if tab.GitTool {
+			return i
+		}
+	}
+	return -1
+}
+
+// GitToolActive reports whether the active tab runs the git tool.
+func (m *TerminalModel) GitToolActive() bool {
+	tab := m.getActiveTab()
+	return tab != nil && tab.GitTool
+}
+
+// LaunchGitTool makes the workspace's git tool tab active, starting the git
+// tool in the workspace's worktree when it is not running.
+func (m *TerminalModel) LaunchGitTool(ws *data.Workspace) tea.Cmd {
+	if ws == nil {
+		return nil
+	}
+	if m.workspace == nil || m.workspace.ID() != ws.ID() {
+		m.setWorkspace(ws)
+	}
+	wsID := string(ws.ID())
+	if idx := m.gitToolIdx(wsID); idx >= 0 {
+		ts := m.tabs.ByWorkspace[wsID][idx].State
+		ts.mu.Lock()
+		running := ts.Running
+		ts.mu.Unlock()
+		if running {
+			m.setActiveTabIdx(idx)
+			m.refreshTerminalSize()
+			return nil
+		}
+		m.removeGitTool(wsID)
+	}
+	if m.pendingGitTool[wsID] {
+		return nil
+	}
+	m.pendingGitTool[wsID] = true
+	return m.createTab(ws, true)
+}
+
+// markGitTool marks the tab just created for the git tool.
+func (m *TerminalModel) markGitTool(wsID string, tabID TerminalTabID) {
+	delete(m.pendingGitTool, wsID)
+	if tab := m.getTabByID(wsID, tabID); tab != nil {
+		tab.GitTool = true
+		tab.Name = GitTool
+	}
+}
+
+// removeGitTool closes the workspace's git tool tab and ends its session.
+func (m *TerminalModel) removeGitTool(wsID string) {
+	idx := m.gitToolIdx(wsID)
+	if idx < 0 {
+		return
+	}
+	tabs := m.tabs.ByWorkspace[wsID]
+	ts := tabs[idx].State
+	sessionName := ""
+	if ts != nil {
+		m.stopPTYReader(ts)
+		ts.mu.Lock()
+		sessionName = ts.SessionName
+		if ts.Terminal != nil {
+			closeTerminalForSidebar(ts.Terminal, "git tool close")
+		}
+		ts.Running = false
+		ts.RestartBackoff = 0
+		ts.mu.Unlock()
+	}
+	m.tabs.ByWorkspace[wsID] = append(tabs[:idx], tabs[idx+1:]...)
+	active := m.tabs.ActiveByWorkspace[wsID]
+	if active > idx {
+		active--
+	}
+	m.tabs.ActiveByWorkspace[wsID] = max(0, min(active, len(m.tabs.ByWorkspace[wsID])-1))
+	m.refreshTerminalSize()
+	if sessionName != "" {
+		opts := m.tmuxOpts
+		safego.Go("sidebar.git_tool_close", func() {
+			_ = closeKillSessionFn(sessionName, opts)
+		})
+	}
+}
+
+// handleGitToolStopped closes the git tool's tab once it was quit, which
+// ends its session and so its client.
+func (m *TerminalModel) handleGitToolStopped(wsID string) tea.Cmd {
+	m.removeGitTool(wsID)
+	return func() tea.Msg { return GitToolExited{WorkspaceID: wsID} }
+}
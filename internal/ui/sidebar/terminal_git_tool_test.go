@@ -0,0 +1,125 @@
+package sidebar
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/andyrewlee/amux/internal/data"
+	"github.com/andyrewlee/amux/internal/messages"
+	"github.com/andyrewlee/amux/internal/pty"
+	"github.com/andyrewlee/amux/internal/tmux"
+)
+
+func stubGitToolSeams(t *testing.T, lookErr error) (*string, *tmux.SessionTags, chan string) {
+	t.Helper()
+	oldEnsure, oldState, oldNewPTY := ensureTmuxAvailableFn, sessionStateForFn, newPTYWithSizeFn
+	oldVerify, oldLook, oldKill := verifyTerminalSessionTagsFn, gitToolLookPath, closeKillSessionFn
+	t.Cleanup(func() {
+		ensureTmuxAvailableFn, sessionStateForFn, newPTYWithSizeFn = oldEnsure, oldState, oldNewPTY
+		verifyTerminalSessionTagsFn, gitToolLookPath, closeKillSessionFn = oldVerify, oldLook, oldKill
+	})
+	var command string
+	var tags tmux.SessionTags
+	killed := make(chan string, 4)
+	ensureTmuxAvailableFn = func() error { return nil }
+	sessionStateForFn = func(string, tmux.Options) (tmux.SessionState, error) { return tmux.SessionState{}, nil }
+	newPTYWithSizeFn = func(cmd, dir string, env []string, rows, cols uint16) (*pty.Terminal, error) {
+		command = cmd
+		return nil, nil
+	}
+	verifyTerminalSessionTagsFn = func(_ string, got tmux.SessionTags, _ tmux.Options) error {
+		tags = got
+		return nil
+	}
+	gitToolLookPath = func(file string) (string, error) { return "/usr/bin/" + file, lookErr }
+	closeKillSessionFn = func(name string, _ tmux.Options) error {
+		killed <- name
+		return nil
+	}
+	return &command, &tags, killed
+}
+
+func TestLaunchGitTool(t *testing.T) {
+	command, tags, killed := stubGitToolSeams(t, nil)
+	m := NewTerminalModel()
+	ws := data.NewWorkspace("ws", "main", "main", "/repo", "/repo/ws")
+	m.AddTerminalForHarness(ws)
+
+	cmd := m.LaunchGitTool(ws)
+	if cmd == nil || m.LaunchGitTool(ws) != nil {
+		t.Fatal("expected one git tool to start")
+	}
+	created, ok := cmd().(SidebarTerminalCreated)
+	if !ok || !created.GitTool {
+		t.Fatalf("launch = %+v, want the git tool's tab created", created)
+	}
+	if !strings.Contains(*command, "until "+GitTool) || tags.Type != gitToolTabType {
+		t.Fatalf("command = %q, tags = %+v", *command, *tags)
+	}
+	m, _ = m.Update(created)
+	if tabs := m.getTabs(); len(tabs) != 2 || tabs[1].Name != GitTool || !m.GitToolActive() {
+		t.Fatalf("tabs = %+v, want the git tool active beside the shell", tabs)
+	}
+
+	// Launching again switches back to the running tool.
+	m.setActiveTabIdx(0)
+	if m.LaunchGitTool(ws) != nil || !m.GitToolActive() {
+		t.Fatal("expected the running git tool to be made active")
+	}
+
+	// Quitting the tool closes its tab and ends its session.
+	m, cmd = m.Update(messages.SidebarPTYStopped{WorkspaceID: string(ws.ID()), TabID: string(created.TabID)})
+	if cmd == nil {
+		t.Fatal("expected the git tool's exit to be reported")
+	}
+	if exited, ok := cmd().(GitToolExited); !ok || exited.WorkspaceID != string(ws.ID()) {
+		t.Fatalf("exit = %+v", exited)
+	}
+	if len(m.getTabs()) != 1 || m.GitToolActive() {
+		t.Fatalf("tabs = %+v, want just the shell", m.getTabs())
+	}
+	select {
+	case name := <-killed:
+		if name != created.SessionName {
+			t.Fatalf("killed %q, want %q", name, created.SessionName)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("the git tool's session was not ended")
+	}
+}
+
+func TestGitToolClosedOnWorkspaceSwitch(t *testing.T) {
+	_, _, killed := stubGitToolSeams(t, nil)
+	m := NewTerminalModel()
+	ws := data.NewWorkspace("ws", "main", "main", "/repo", "/repo/ws")
+	m, _ = m.Update(m.LaunchGitTool(ws)())
+	if !m.GitToolActive() {
+		t.Fatal("expected the git tool to start")
+	}
+	m.SetWorkspacePreview(data.NewWorkspace("other", "other", "main", "/repo", "/repo/other"))
+	m.SetWorkspacePreview(ws)
+	if len(m.getTabs()) != 0 {
+		t.Fatalf("tabs = %+v, want the git tool closed", m.getTabs())
+	}
+	select {
+	case <-killed:
+	case <-time.After(time.Second):
+		t.Fatal("the git tool's session was not ended")
+	}
+}
+
+func TestLaunchGitToolNotInstalled(t *testing.T) {
+	stubGitToolSeams(t, errors.New("not found"))
+	m := NewTerminalModel()
+	ws := data.NewWorkspace("ws", "main", "main", "/repo", "/repo/ws")
+	failed, ok := m.LaunchGitTool(ws)().(SidebarTerminalCreateFailed)
+	if !ok || !failed.GitTool || !strings.Contains(failed.Err.Error(), GitTool+" is not installed") {
+		t.Fatalf("launch = %+v, want it to fail", failed)
+	}
+	m.Update(failed)
+	if m.pendingGitTool[string(ws.ID())] {
+		t.Fatal("a failed launch should allow retrying")
+	}
+}
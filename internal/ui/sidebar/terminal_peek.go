@@ -0,0 +1,17 @@
+package sidebar
+
+// TogglePrimaryPeek shows or hides the primary screen behind the active
+// terminal tab's full-screen program. ok is false when the tab is not on the
+// alternate screen.
+func (m *TerminalModel) TogglePrimaryPeek() (peeking, ok bool) {
+	ts := m.getTerminal()
+	if ts == nil {
+		return false, false
+	}
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+	if ts.VTerm == nil || (!ts.VTerm.AltScreen && !ts.VTerm.PeekingPrimary()) {
+		return false, false
+	}
+	return ts.VTerm.TogglePrimaryPeek(), true
+}
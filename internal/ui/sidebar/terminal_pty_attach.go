@@ -2,6 +2,7 @@ package sidebar
 
 import (
 	"errors"
+	"fmt"
 	"time"
 
 	tea "charm.land/bubbletea/v2"
@@ -86,6 +87,12 @@ func (m *TerminalModel) sessionBootstrapViewportSize() (int, int) {
 
 // createTerminalTab creates a new terminal tab for the workspace
 func (m *TerminalModel) createTerminalTab(ws *data.Workspace) tea.Cmd {
+	return m.createTab(ws, false)
+}
+
+// createTab creates a new tab for the workspace running its login shell, or
+// the git tool when gitTool is set.
+func (m *TerminalModel) createTab(ws *data.Workspace, gitTool bool) tea.Cmd {
 	wsID := string(ws.ID())
 	tabID := generateTerminalTabID()
 	termWidth, termHeight := m.sessionBootstrapViewportSize()
@@ -93,14 +100,24 @@ func (m *TerminalModel) createTerminalTab(ws *data.Workspace) tea.Cmd {
 	opts := m.tmuxOpts
 	instanceID := m.instanceID
 	root := ws.Root
+	loginShellCommand, shellErr := pty.WorkspaceShellCommand(ws)
+	tabType := "terminal"
+	if gitTool {
+		loginShellCommand, shellErr = gitToolCommand(), nil
+		tabType = gitToolTabType
+	}
 
 	return func() tea.Msg {
-		loginShellCommand, err := pty.LoginShellCommandFromEnv()
-		if err != nil {
-			return SidebarTerminalCreateFailed{WorkspaceID: wsID, Err: err}
+		if shellErr != nil {
+			return SidebarTerminalCreateFailed{WorkspaceID: wsID, Err: shellErr, GitTool: gitTool}
 		}
 		if err := ensureTmuxAvailableFn(); err != nil {
-			return SidebarTerminalCreateFailed{WorkspaceID: wsID, Err: err}
+			return SidebarTerminalCreateFailed{WorkspaceID: wsID, Err: err, GitTool: gitTool}
+		}
+		if gitTool {
+			if _, err := gitToolLookPath(GitTool); err != nil {
+				return SidebarTerminalCreateFailed{WorkspaceID: wsID, Err: fmt.Errorf("%s is not installed: %w", GitTool, err), GitTool: true}
+			}
 		}
 
 		var scrollback []byte
@@ -126,8 +143,8 @@ func (m *TerminalModel) createTerminalTab(ws *data.Workspace) tea.Cmd {
 		tags := tmux.SessionTags{
 			WorkspaceID:  wsID,
 			TabID:        string(tabID),
-			Type:         "terminal",
-			Assistant:    "terminal",
+			Type:         tabType,
+			Assistant:    tabType,
 			CreatedAt:    time.Now().Unix(),
 			InstanceID:   instanceID,
 			SessionOwner: instanceID,
@@ -146,7 +163,7 @@ func (m *TerminalModel) createTerminalTab(ws *data.Workspace) tea.Cmd {
 			if reuseExistingSession {
 				rollbackExistingSessionBootstrap(sessionName, bootstrap, opts)
 			}
-			return SidebarTerminalCreateFailed{WorkspaceID: wsID, Err: err}
+			return SidebarTerminalCreateFailed{WorkspaceID: wsID, Err: err, GitTool: gitTool}
 		}
 		if reuseExistingSession {
 			if captureFullPane && bootstrapSnapshotStillMatchesSession(sessionName, bootstrap, opts) {
@@ -167,6 +184,7 @@ func (m *TerminalModel) createTerminalTab(ws *data.Workspace) tea.Cmd {
 		return SidebarTerminalCreated{
 			WorkspaceID: wsID,
 			TabID:       tabID,
+			GitTool:     gitTool,
 			Terminal:    term,
 			SessionName: sessionName,
 			CaptureCols: captureCols,
@@ -236,6 +254,10 @@ func (m *TerminalModel) RestartActiveTab() tea.Cmd {
 		}
 	}
 	ws := m.workspace
+	if tab.GitTool {
+		m.removeGitTool(string(ws.ID()))
+		return m.LaunchGitTool(ws)
+	}
 	if sessionName == "" {
 		sessionName = tmux.SessionName("amux", string(ws.ID()), string(tab.ID))
 	}
@@ -252,7 +274,7 @@ func (m *TerminalModel) attachToSession(ws *data.Workspace, tabID TerminalTabID,
 	opts := m.tmuxOpts
 	termWidth, termHeight := m.sessionBootstrapViewportSize()
 	attachWidth, attachHeight := m.terminalContentSize()
-	loginShellCommand, shellErr := pty.LoginShellCommandFromEnv()
+	loginShellCommand, shellErr := pty.WorkspaceShellCommand(ws)
 	env := []string{"COLORTERM=truecolor"}
 	wsID := string(ws.ID())
 	root := ws.Root
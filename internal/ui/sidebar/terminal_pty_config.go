@@ -44,6 +44,8 @@ const (
 type SidebarTerminalCreated struct {
 	WorkspaceID string
 	TabID       TerminalTabID
+	// GitTool is set for the tab running the git tool (terminal_git_tool.go).
+	GitTool     bool
 	Terminal    *pty.Terminal
 	SessionName string
 	CaptureCols int
@@ -55,6 +57,7 @@ type SidebarTerminalCreated struct {
 type SidebarTerminalCreateFailed struct {
 	WorkspaceID string
 	Err         error
+	GitTool     bool
 }
 
 type SidebarTerminalReattachResult struct {
@@ -160,6 +160,7 @@ func (m *TerminalModel) StartPTYReaders() tea.Cmd {
 
 // CloseTerminal closes all terminal tabs for the given workspace
 func (m *TerminalModel) CloseTerminal(wsID string) {
+	m.removeGitTool(wsID)
 	tabs := m.tabs.ByWorkspace[wsID]
 	for _, tab := range tabs {
 		if tab.State != nil {
@@ -175,6 +176,7 @@ func (m *TerminalModel) CloseTerminal(wsID string) {
 	}
 	m.tabs.DeleteWorkspace(wsID)
 	delete(m.pendingCreation, wsID)
+	delete(m.pendingGitTool, wsID)
 	delete(m.lastActiveAt, wsID)
 }
 
@@ -234,3 +236,38 @@ func (m *TerminalModel) SendToTerminal(s string) {
 		}
 	}
 }
+
+// ActivePasteSink returns a writer bound to the current terminal's PTY, for
+// pastes written in chunks off the UI goroutine. It reports false once a write
+// fails. Nil when there is no running terminal.
+func (m *TerminalModel) ActivePasteSink() func(string) bool {
+	ts := m.getTerminal()
+	if ts == nil || ts.Terminal == nil {
+		return nil
+	}
+	term := ts.Terminal
+	return func(s string) bool {
+		if err := term.SendString(s); err != nil {
+			logging.Warn("Sidebar paste failed: %v", err)
+			ts.mu.Lock()
+			ts.Running = false
+			ts.Detached = true
+			ts.UserDetached = false
+			ts.mu.Unlock()
+			return false
+		}
+		return true
+	}
+}
+
+// ActiveSessionName returns the tmux session of the current terminal, or ""
+// when there is none.
+func (m *TerminalModel) ActiveSessionName() string {
+	ts := m.getTerminal()
+	if ts == nil {
+		return ""
+	}
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+	return ts.SessionName
+}
@@ -9,19 +9,66 @@ import (
 	"github.com/andyrewlee/amux/internal/pty"
 	"github.com/andyrewlee/amux/internal/ui/common"
 	"github.com/andyrewlee/amux/internal/ui/compositor"
+	"github.com/andyrewlee/amux/internal/ui/theme"
 )
 
-// renderTabBar renders the terminal tab bar (compact single-line, no borders)
+// terminalTabBarEntry is the per-tab state the terminal tab bar renders from.
+type terminalTabBarEntry struct {
+	name         string
+	disconnected bool
+}
+
+// renderedTerminalTabBar is a cached tab bar render plus its hit regions.
+type renderedTerminalTabBar struct {
+	view string
+	hits []terminalTabHit
+}
+
+// renderTabBar renders the terminal tab bar (compact single-line, no borders).
+// The render is memoized on its inputs so unchanged frames reuse the previous
+// string and hit regions.
 func (m *TerminalModel) renderTabBar() string {
-	m.tabHits = m.tabHits[:0]
 	tabs := m.getTabs()
 	activeIdx := m.getActiveTabIdx()
+	entries := make([]terminalTabBarEntry, len(tabs))
+	for i, tab := range tabs {
+		name := tab.Name
+		if name == "" {
+			name = fmt.Sprintf("Terminal %d", i+1)
+		}
+		disconnected := false
+		if tab.State != nil {
+			tab.State.mu.Lock()
+			disconnected = tab.State.Detached || !tab.State.Running
+			tab.State.mu.Unlock()
+		}
+		entries[i] = terminalTabBarEntry{name: name, disconnected: disconnected}
+	}
+
+	var key strings.Builder
+	key.WriteString(string(common.GetCurrentTheme().ID))
+	fmt.Fprintf(&key, "\x00%d\x00%t", activeIdx, m.workspace != nil)
+	for _, e := range entries {
+		fmt.Fprintf(&key, "\x00%s\x00%t", e.name, e.disconnected)
+	}
+	bar := m.tabBarCache.Get(key.String(), func() renderedTerminalTabBar {
+		view := m.buildTabBar(entries, activeIdx)
+		return renderedTerminalTabBar{view: view, hits: append([]terminalTabHit(nil), m.tabHits...)}
+	})
+	m.tabHits = append(m.tabHits[:0], bar.hits...)
+	return bar.view
+}
+
+// buildTabBar renders the tab bar from entries, recording hit regions into
+// m.tabHits.
+func (m *TerminalModel) buildTabBar(entries []terminalTabBarEntry, activeIdx int) string {
+	m.tabHits = m.tabHits[:0]
 
 	// Compact tab styles
 	inactiveStyle := m.styles.Tab
 	plusStyle := m.styles.TabPlus
 
-	if len(tabs) == 0 {
+	if len(entries) == 0 {
 		// No workspace selected - show non-interactive message
 		if m.workspace == nil {
 			return m.styles.Muted.Render("No terminal")
@@ -47,30 +94,20 @@ func (m *TerminalModel) renderTabBar() string {
 	var renderedTabs []string
 	x := 0
 
-	for i, tab := range tabs {
-		name := tab.Name
-		if name == "" {
-			name = fmt.Sprintf("Terminal %d", i+1)
-		}
-		disconnected := false
-		if tab.State != nil {
-			tab.State.mu.Lock()
-			disconnected = tab.State.Detached || !tab.State.Running
-			tab.State.mu.Unlock()
-		}
+	for i, entry := range entries {
+		name := entry.name
+		disconnected := entry.disconnected
 
 		// Build tab content with close affordance
 		closeLabel := m.styles.Muted.Render("×")
 		var rendered string
 		if i == activeIdx {
 			// Active tab - single unified style for clean background
-			tabStyle := lipgloss.NewStyle().
-				Padding(0, 1).
-				Foreground(common.ColorForeground()).
-				Background(common.ColorSurface2())
+			fg := common.ColorForeground()
 			if disconnected {
-				tabStyle = tabStyle.Foreground(common.ColorMuted())
+				fg = common.ColorMuted()
 			}
+			tabStyle := theme.CachedStyle(fg, common.ColorSurface2(), false).Padding(0, 1)
 			content := name + " ×"
 			rendered = tabStyle.Render(content)
 		} else {
@@ -177,24 +214,15 @@ func (m *TerminalModel) StatusLine() string {
 	defer ts.mu.Unlock()
 	if ts.VTerm.IsScrolled() {
 		offset, total := ts.VTerm.GetScrollInfo()
-		scrollStyle := lipgloss.NewStyle().
-			Bold(true).
-			Foreground(common.ColorBackground()).
-			Background(common.ColorInfo())
+		scrollStyle := theme.CachedStyle(common.ColorBackground(), common.ColorInfo(), true)
 		return scrollStyle.Render(" SCROLL: " + formatScrollPos(offset, total) + " ")
 	}
 	if ts.Detached {
-		statusStyle := lipgloss.NewStyle().
-			Bold(true).
-			Foreground(common.ColorBackground()).
-			Background(common.ColorWarning())
+		statusStyle := theme.CachedStyle(common.ColorBackground(), common.ColorWarning(), true)
 		return statusStyle.Render(" DETACHED ")
 	}
 	if !ts.Running {
-		statusStyle := lipgloss.NewStyle().
-			Bold(true).
-			Foreground(common.ColorBackground()).
-			Background(common.ColorError())
+		statusStyle := theme.CachedStyle(common.ColorBackground(), common.ColorError(), true)
 		return statusStyle.Render(" STOPPED ")
 	}
 	return ""
@@ -209,15 +237,23 @@ func (m *TerminalModel) helpItem(key, desc string) string {
 	return common.RenderHelpItem(m.styles, key, desc)
 }
 
+// helpLines returns the wrapped help bar, memoized on the inputs that shape it.
 func (m *TerminalModel) helpLines(contentWidth int) []string {
-	items := []string{}
-
 	ts := m.getTerminal()
 	hasTerm := ts != nil && ts.VTerm != nil
+	multipleTabs := m.HasMultipleTabs()
+	key := fmt.Sprintf("%s\x00%d\x00%t\x00%t", common.GetCurrentTheme().ID, contentWidth, hasTerm, multipleTabs)
+	return m.helpCache.Get(key, func() []string {
+		return m.buildHelpLines(contentWidth, hasTerm, multipleTabs)
+	})
+}
+
+func (m *TerminalModel) buildHelpLines(contentWidth int, hasTerm, multipleTabs bool) []string {
+	items := []string{}
 
 	// Tab management hints
 	items = append(items, m.helpItem("C-Spc t t", "new term"))
-	if m.HasMultipleTabs() {
+	if multipleTabs {
 		items = append(
 			items,
 			m.helpItem("C-Spc t n", "next"),
@@ -314,8 +350,9 @@ func (m *TerminalModel) View() string {
 		// Keep cursor state in sync at render time too; Focus/Blur also set
 		// this eagerly to avoid stale frames during fast pane switches.
 		ts.VTerm.ShowCursor = m.focused
-		// Use VTerm.Render() directly - it uses dirty line caching and delta styles
-		content := ts.VTerm.Render()
+		// Stream VTerm output straight into the builder - RenderTo copies
+		// cached lines without re-encoding or an intermediate frame string.
+		_, _ = ts.VTerm.RenderTo(&b)
 		isScrolled := ts.VTerm.IsScrolled()
 		var scrollInfo string
 		if isScrolled {
@@ -324,8 +361,6 @@ func (m *TerminalModel) View() string {
 		}
 		ts.mu.Unlock()
 
-		b.WriteString(content)
-
 		if isScrolled {
 			b.WriteString("\n")
 			scrollStyle := lipgloss.NewStyle().
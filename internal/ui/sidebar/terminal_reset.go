@@ -0,0 +1,98 @@
+package sidebar
+
+import (
+	"time"
+
+	tea "charm.land/bubbletea/v2"
+
+	"github.com/andyrewlee/amux/internal/logging"
+	"github.com/andyrewlee/amux/internal/pty"
+	"github.com/andyrewlee/amux/internal/ui/common"
+)
+
+// redrawRestoreDelay is how long a redraw keeps the PTY one column narrower
+// before restoring it, so tmux sees a real size change and forwards SIGWINCH.
+const redrawRestoreDelay = 150 * time.Millisecond
+
+// SidebarTerminalRedrawRestore restores a terminal tab's PTY size after a
+// redraw nudge.
+type SidebarTerminalRedrawRestore struct {
+	WorkspaceID string
+	TabID       TerminalTabID
+}
+
+// ClearActiveScrollback drops the active terminal tab's local scrollback.
+func (m *TerminalModel) ClearActiveScrollback() tea.Cmd {
+	ts := m.getTerminal()
+	if ts == nil {
+		return nil
+	}
+	ts.mu.Lock()
+	if ts.VTerm != nil {
+		ts.VTerm.ClearScrollback()
+	}
+	ts.mu.Unlock()
+	return nil
+}
+
+// ResetActiveTerminal hard-resets the active terminal tab's emulator (RIS)
+// and asks the shell to redraw onto the clean screen.
+func (m *TerminalModel) ResetActiveTerminal() tea.Cmd {
+	ts := m.getTerminal()
+	if ts == nil {
+		return nil
+	}
+	ts.mu.Lock()
+	if ts.VTerm != nil {
+		ts.VTerm.Reset()
+	}
+	ts.mu.Unlock()
+	return m.RedrawActiveTab()
+}
+
+// RedrawActiveTab makes the hosted program repaint by briefly narrowing the
+// PTY by one column and restoring it after redrawRestoreDelay.
+func (m *TerminalModel) RedrawActiveTab() tea.Cmd {
+	tab := m.getActiveTab()
+	if tab == nil || tab.State == nil {
+		return nil
+	}
+	ts := tab.State
+	ts.mu.Lock()
+	term := ts.Terminal
+	rows, cols := ts.lastHeight, ts.lastWidth
+	ts.mu.Unlock()
+	if term == nil || rows < 1 || cols < 2 {
+		return nil
+	}
+	ptyRows, ptyCols, ok := pty.WinsizeFromInts(rows, cols-1)
+	if !ok {
+		return nil
+	}
+	if err := setTerminalSizeFn(term, ptyRows, ptyCols); err != nil {
+		logging.Warn("Sidebar redraw resize failed: %v", err)
+		return nil
+	}
+	wsID := m.workspaceID()
+	tabID := tab.ID
+	return common.SafeTick(redrawRestoreDelay, func(time.Time) tea.Msg {
+		return SidebarTerminalRedrawRestore{WorkspaceID: wsID, TabID: tabID}
+	})
+}
+
+// handleRedrawRestore puts the PTY back at the tab's current size.
+func (m *TerminalModel) handleRedrawRestore(msg SidebarTerminalRedrawRestore) {
+	tab := m.getTabByID(msg.WorkspaceID, msg.TabID)
+	if tab == nil || tab.State == nil {
+		return
+	}
+	ts := tab.State
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+	if ts.Terminal == nil {
+		return
+	}
+	if ptyRows, ptyCols, ok := pty.WinsizeFromInts(ts.lastHeight, ts.lastWidth); ok {
+		_ = setTerminalSizeFn(ts.Terminal, ptyRows, ptyCols)
+	}
+}
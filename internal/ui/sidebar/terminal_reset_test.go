@@ -0,0 +1,50 @@
+package sidebar
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/andyrewlee/amux/internal/data"
+	"github.com/andyrewlee/amux/internal/pty"
+	"github.com/andyrewlee/amux/internal/vterm"
+)
+
+func TestTerminalRedrawNudgesThenRestoresSize(t *testing.T) {
+	oldSetTerminalSizeFn := setTerminalSizeFn
+	t.Cleanup(func() { setTerminalSizeFn = oldSetTerminalSizeFn })
+	var sizes [][2]uint16
+	setTerminalSizeFn = func(_ *pty.Terminal, rows, cols uint16) error {
+		sizes = append(sizes, [2]uint16{rows, cols})
+		return nil
+	}
+
+	wt := &data.Workspace{Repo: "/repo", Root: "/repo/wt"}
+	m := NewTerminalModel()
+	m.workspace = wt
+	wtID := string(wt.ID())
+	vt := vterm.New(40, 10)
+	for i := 0; i < 20; i++ {
+		vt.Write([]byte(fmt.Sprintf("line-%d\r\n", i)))
+	}
+	m.tabs.ByWorkspace[wtID] = []*TerminalTab{{
+		ID:    "tab-1",
+		Name:  "Terminal 1",
+		State: &TerminalState{Terminal: &pty.Terminal{}, VTerm: vt, lastWidth: 40, lastHeight: 10},
+	}}
+	m.tabs.ActiveByWorkspace[wtID] = 0
+
+	m.ClearActiveScrollback()
+	if len(vt.Scrollback) != 0 {
+		t.Fatalf("scrollback len = %d after clear, want 0", len(vt.Scrollback))
+	}
+
+	if cmd := m.ResetActiveTerminal(); cmd == nil {
+		t.Fatal("expected a restore tick after reset")
+	}
+	m.handleRedrawRestore(SidebarTerminalRedrawRestore{WorkspaceID: wtID, TabID: "tab-1"})
+
+	want := [][2]uint16{{10, 39}, {10, 40}}
+	if fmt.Sprint(sizes) != fmt.Sprint(want) {
+		t.Fatalf("PTY sizes = %v, want %v", sizes, want)
+	}
+}
@@ -0,0 +1,78 @@
+package sidebar
+
+import "math"
+
+// SetActiveSearch searches the active terminal tab's scrollback for query and
+// jumps to the newest match; an empty query clears the search.
+func (m *TerminalModel) SetActiveSearch(query string) (total int, ok bool) {
+	ts := m.getTerminal()
+	if ts == nil {
+		return 0, false
+	}
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+	if ts.VTerm == nil {
+		return 0, false
+	}
+	return ts.VTerm.SetSearch(query), true
+}
+
+// StepActiveSearch moves the active terminal tab's search to the next newer
+// (dir > 0) or older (dir < 0) match and reports the new position.
+func (m *TerminalModel) StepActiveSearch(dir int) (index, total int, ok bool) {
+	ts := m.getTerminal()
+	if ts == nil {
+		return 0, 0, false
+	}
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+	if ts.VTerm == nil || !ts.VTerm.SearchActive() {
+		return 0, 0, false
+	}
+	ts.VTerm.StepSearch(dir)
+	index, total = ts.VTerm.SearchStatus()
+	return index, total, true
+}
+
+// ActiveSearchActive reports whether the active terminal tab has a search
+// query set.
+func (m *TerminalModel) ActiveSearchActive() bool {
+	ts := m.getTerminal()
+	if ts == nil {
+		return false
+	}
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+	return ts.VTerm != nil && ts.VTerm.SearchActive()
+}
+
+// ActiveTerminalText returns the active terminal tab's scrollback and screen
+// as text, with soft-wrapped rows joined.
+func (m *TerminalModel) ActiveTerminalText() (text string, ok bool) {
+	ts := m.getTerminal()
+	if ts == nil {
+		return "", false
+	}
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+	if ts.VTerm == nil {
+		return "", false
+	}
+	return ts.VTerm.GetTextRange(0, 0, ts.VTerm.Width-1, math.MaxInt), true
+}
+
+// ActiveLastOutput returns what the active terminal printed since its last
+// command or submitted input. ok is false when there is no terminal or
+// nothing has been marked.
+func (m *TerminalModel) ActiveLastOutput() (text string, ok bool) {
+	ts := m.getTerminal()
+	if ts == nil {
+		return "", false
+	}
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+	if ts.VTerm == nil {
+		return "", false
+	}
+	return ts.VTerm.LastOutput()
+}
@@ -8,23 +8,21 @@ import (
 	"github.com/andyrewlee/amux/internal/logging"
 	"github.com/andyrewlee/amux/internal/messages"
 	"github.com/andyrewlee/amux/internal/ui/common"
+	"github.com/andyrewlee/amux/internal/ui/ptyio"
 )
 
 // flushTiming returns the appropriate flush timing
 func (m *TerminalModel) flushTiming() (time.Duration, time.Duration) {
-	ts := m.getTerminal()
-	if ts == nil {
-		return ptyFlushQuiet, ptyFlushMaxInterval
-	}
-
-	ts.mu.Lock()
-	defer ts.mu.Unlock()
-
-	// Only use slower Alt timing for true AltScreen mode (full-screen TUIs).
-	if ts.VTerm != nil && ts.VTerm.AltScreen {
-		return ptyFlushQuietAlt, ptyFlushMaxAlt
+	quiet, maxInterval := ptyFlushQuiet, ptyFlushMaxInterval
+	if ts := m.getTerminal(); ts != nil {
+		ts.mu.Lock()
+		// Only use slower Alt timing for true AltScreen mode (full-screen TUIs).
+		if ts.VTerm != nil && ts.VTerm.AltScreen {
+			quiet, maxInterval = ptyFlushQuietAlt, ptyFlushMaxAlt
+		}
+		ts.mu.Unlock()
 	}
-	return ptyFlushQuiet, ptyFlushMaxInterval
+	return ptyio.ScaleFlushInterval(quiet), ptyio.ScaleFlushInterval(maxInterval)
 }
 
 // Init initializes the terminal model
@@ -93,6 +91,9 @@ func (m *TerminalModel) Update(msg tea.Msg) (*TerminalModel, tea.Cmd) {
 			cmds = append(cmds, cmd)
 		}
 
+	case SidebarTerminalRedrawRestore:
+		m.handleRedrawRestore(msg)
+
 	case messages.WorkspaceDeleted:
 		if cmd := m.handleWorkspaceDeleted(msg); cmd != nil {
 			cmds = append(cmds, cmd)
@@ -197,6 +198,10 @@ func (m *TerminalModel) handleKeyPress(msg tea.KeyPressMsg) (*TerminalModel, tea
 		ts.VTerm.ScrollViewToBottom()
 		ts.VTerm.NoteSyncViewportInteraction()
 	}
+	// Output printed after this line is what "copy last output" copies.
+	if ts.VTerm != nil && msg.Code == tea.KeyEnter && msg.Mod == 0 {
+		ts.VTerm.MarkInput()
+	}
 	ts.mu.Unlock()
 
 	// Forward ALL keys to terminal (no Ctrl interceptions)
@@ -118,6 +118,9 @@ func (m *TerminalModel) handlePTYStopped(msg messages.SidebarPTYStopped) tea.Cmd
 	if tab == nil || tab.State == nil {
 		return nil
 	}
+	if tab.GitTool {
+		return m.handleGitToolStopped(wsID)
+	}
 	ts := tab.State
 	termAlive := ts.Terminal != nil && !ts.Terminal.IsClosed()
 	ts.mu.Lock()
@@ -57,6 +57,9 @@ func (m *TerminalModel) handleTerminalCreated(msg SidebarTerminalCreated) tea.Cm
 	if msg.CaptureFullPane {
 		m.refreshTerminalSize()
 	}
+	if msg.GitTool {
+		m.markGitTool(msg.WorkspaceID, msg.TabID)
+	}
 	if msg.Terminal != nil && (initialWidth != currentWidth || initialHeight != currentHeight) {
 		if ptyRows, ptyCols, ok := pty.WinsizeFromInts(currentHeight, currentWidth); ok {
 			_ = setTerminalSizeFn(msg.Terminal, ptyRows, ptyCols)
@@ -156,6 +159,10 @@ func (m *TerminalModel) handleReattachFailed(msg SidebarTerminalReattachFailed)
 
 // handleCreateFailed clears the pending-creation flag so the user can retry.
 func (m *TerminalModel) handleCreateFailed(msg SidebarTerminalCreateFailed) tea.Cmd {
+	if msg.GitTool {
+		delete(m.pendingGitTool, msg.WorkspaceID)
+		return common.ReportError("starting "+GitTool, msg.Err, "")
+	}
 	delete(m.pendingCreation, msg.WorkspaceID)
 	return common.ReportError("creating sidebar terminal", msg.Err, "")
 }
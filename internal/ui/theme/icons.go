@@ -1,5 +1,7 @@
 package theme
 
+import "sync/atomic"
+
 // Icons used throughout the application
 // Uses Unicode characters with fallbacks for broad terminal support
 var Icons = struct {
@@ -8,6 +10,8 @@ var Icons = struct {
 	Dirty   string
 	Running string
 	Idle    string
+	// Favorite marks favorite projects and workspaces in the dashboard.
+	Favorite string
 
 	// Actions
 	Add    string
@@ -37,10 +41,11 @@ var Icons = struct {
 	Spinner []string
 }{
 	// Status indicators
-	Clean:   "✓",
-	Dirty:   "●",
-	Running: "●",
-	Idle:    "○",
+	Clean:    "✓",
+	Dirty:    "●",
+	Running:  "●",
+	Idle:     "○",
+	Favorite: "★",
 
 	// Actions
 	Add:    "+",
@@ -70,7 +75,26 @@ var Icons = struct {
 	Spinner: []string{"⠋", "⠙", "⠹", "⠸", "⠼", "⠴", "⠦", "⠧", "⠇", "⠏"},
 }
 
+// reducedMotionSpinner stands in for the spinner while motion is reduced.
+const reducedMotionSpinner = "…"
+
+var reducedMotion atomic.Bool
+
+// SetReducedMotion turns off animation process-wide: spinners show a static
+// glyph and blinking text and cursors stay solid.
+func SetReducedMotion(on bool) {
+	reducedMotion.Store(on)
+}
+
+// ReducedMotion reports whether animation is turned off.
+func ReducedMotion() bool {
+	return reducedMotion.Load()
+}
+
 // SpinnerFrame returns the spinner character for a given frame index
 func SpinnerFrame(frame int) string {
+	if ReducedMotion() {
+		return reducedMotionSpinner
+	}
 	return Icons.Spinner[frame%len(Icons.Spinner)]
 }
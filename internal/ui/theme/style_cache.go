@@ -0,0 +1,51 @@
+package theme
+
+import (
+	"image/color"
+	"sync"
+
+	"charm.land/lipgloss/v2"
+)
+
+// maxCachedStyles bounds the style cache. Keys include the colors, so every
+// theme switch adds a fresh set of entries; clearing on overflow keeps the
+// cache from growing across many switches.
+const maxCachedStyles = 256
+
+type styleKey struct {
+	fg, bg color.Color
+	bold   bool
+}
+
+var (
+	styleCacheMu sync.Mutex
+	styleCache   = make(map[styleKey]lipgloss.Style)
+)
+
+// CachedStyle returns a memoized style with the given foreground, background,
+// and bold attribute, so hot render paths do not rebuild the same style every
+// frame. A nil color leaves that attribute unset. Colors must be comparable;
+// every theme and agent color is.
+func CachedStyle(fg, bg color.Color, bold bool) lipgloss.Style {
+	key := styleKey{fg: fg, bg: bg, bold: bold}
+	styleCacheMu.Lock()
+	defer styleCacheMu.Unlock()
+	if style, ok := styleCache[key]; ok {
+		return style
+	}
+	style := lipgloss.NewStyle()
+	if fg != nil {
+		style = style.Foreground(fg)
+	}
+	if bg != nil {
+		style = style.Background(bg)
+	}
+	if bold {
+		style = style.Bold(true)
+	}
+	if len(styleCache) >= maxCachedStyles {
+		clear(styleCache)
+	}
+	styleCache[key] = style
+	return style
+}
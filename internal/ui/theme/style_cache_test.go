@@ -0,0 +1,34 @@
+package theme
+
+import (
+	"fmt"
+	"testing"
+
+	"charm.land/lipgloss/v2"
+)
+
+func TestCachedStyleMatchesFreshStyle(t *testing.T) {
+	fg, bg := ColorForeground(), ColorSurface2()
+	want := lipgloss.NewStyle().Foreground(fg).Background(bg).Bold(true).Render("tab")
+	if got := CachedStyle(fg, bg, true).Render("tab"); got != want {
+		t.Fatalf("cached render = %q, want %q", got, want)
+	}
+	if got := CachedStyle(fg, nil, false).Render("tab"); got != lipgloss.NewStyle().Foreground(fg).Render("tab") {
+		t.Fatalf("nil background should leave background unset, got %q", got)
+	}
+}
+
+func TestCachedStyleClearsOnOverflow(t *testing.T) {
+	styleCacheMu.Lock()
+	clear(styleCache)
+	styleCacheMu.Unlock()
+	for i := 0; i <= maxCachedStyles; i++ {
+		CachedStyle(nil, lipgloss.Color(fmt.Sprintf("#%06x", i)), false)
+	}
+	styleCacheMu.Lock()
+	n := len(styleCache)
+	styleCacheMu.Unlock()
+	if n > maxCachedStyles {
+		t.Fatalf("style cache size = %d, want <= %d", n, maxCachedStyles)
+	}
+}
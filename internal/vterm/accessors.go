@@ -32,7 +32,7 @@ func (v *VTerm) LastCursorState() CursorRenderState {
 
 // CursorHiddenForRender returns the effective cursor-hidden state for rendering.
 func (v *VTerm) CursorHiddenForRender() bool {
-	return v.CursorHidden
+	return v.CursorHidden || v.peekPrimary
 }
 
 // SelActive reports whether a selection is active.
@@ -0,0 +1,35 @@
+package vterm
+
+// Primary-screen peek. While a full-screen program owns the alternate screen,
+// the primary screen is parked in altScreenBuf untouched; peeking renders it
+// (with scrollback) in place of the alternate screen without disturbing the
+// program, which keeps drawing to its own buffer. The peek ends when toggled
+// off, when the program leaves the alternate screen, or on reset.
+
+// TogglePrimaryPeek switches between showing the alternate screen and the
+// parked primary screen. It reports whether the primary screen is now shown;
+// outside the alternate screen there is nothing to peek at and it reports
+// false.
+func (v *VTerm) TogglePrimaryPeek() bool {
+	v.setPrimaryPeek(!v.peekPrimary)
+	return v.peekPrimary
+}
+
+// PeekingPrimary reports whether the primary screen is being shown in place
+// of the alternate screen.
+func (v *VTerm) PeekingPrimary() bool {
+	return v.peekPrimary
+}
+
+func (v *VTerm) setPrimaryPeek(on bool) {
+	if on && (!v.AltScreen || v.altScreenBuf == nil) {
+		on = false
+	}
+	if on == v.peekPrimary {
+		return
+	}
+	v.peekPrimary = on
+	v.ViewOffset = 0
+	v.ClearSelection()
+	v.invalidateRenderCache()
+}
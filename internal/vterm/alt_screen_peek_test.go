@@ -0,0 +1,61 @@
+package vterm
+
+import "testing"
+
+func TestPrimaryPeekShowsParkedScreen(t *testing.T) {
+	vt := New(10, 2)
+	vt.Write([]byte("shell\x1b[?1049h\x1b[Hvim"))
+
+	if vt.TogglePrimaryPeek() != true {
+		t.Fatal("expected peek to start on the alternate screen")
+	}
+	screen, _ := vt.RenderBuffers()
+	if got := screenLine(&VTerm{Screen: screen}, 0); got != "shell" {
+		t.Fatalf("peek row 0 = %q, want %q", got, "shell")
+	}
+	if !vt.CursorHiddenForRender() {
+		t.Fatal("cursor should be hidden while peeking")
+	}
+
+	// The program keeps drawing to its own buffer underneath.
+	vt.Write([]byte("\x1b[2Hstatus"))
+	if got := screenLine(vt, 1); got != "status" {
+		t.Fatalf("alt row 1 = %q, want %q", got, "status")
+	}
+
+	if vt.TogglePrimaryPeek() != false {
+		t.Fatal("second toggle should end the peek")
+	}
+	screen, _ = vt.RenderBuffers()
+	if got := screenLine(&VTerm{Screen: screen}, 0); got != "vim" {
+		t.Fatalf("row 0 after peek = %q, want %q", got, "vim")
+	}
+}
+
+func TestPrimaryPeekRequiresAltScreen(t *testing.T) {
+	vt := New(10, 2)
+	if vt.TogglePrimaryPeek() {
+		t.Fatal("peek should not start on the primary screen")
+	}
+}
+
+func TestPrimaryPeekEndsWhenAltScreenExits(t *testing.T) {
+	vt := New(10, 3)
+	vt.Write([]byte("one\r\ntwo\x1b[?1049hfull"))
+	vt.TogglePrimaryPeek()
+	vt.Resize(12, 3)
+	vt.Write([]byte("\x1b[?1049l"))
+
+	if vt.PeekingPrimary() {
+		t.Fatal("peek survived leaving the alternate screen")
+	}
+	if got := screenLine(vt, 0); got != "one" {
+		t.Fatalf("primary row 0 = %q, want %q", got, "one")
+	}
+	if got := screenLine(vt, 1); got != "two" {
+		t.Fatalf("primary row 1 = %q, want %q", got, "two")
+	}
+	if vt.CursorX != 3 || vt.CursorY != 1 {
+		t.Fatalf("cursor = (%d,%d), want (3,1)", vt.CursorX, vt.CursorY)
+	}
+}
@@ -0,0 +1,184 @@
+package vterm
+
+import "unicode"
+
+// Basic bidi support. Terminal applications write right-to-left text (Hebrew,
+// Arabic) in logical order, so the screen buffer holds it that way; this file
+// derives the visual order rows are displayed in. It implements the subset of
+// the Unicode Bidirectional Algorithm that matters for a left-to-right
+// terminal line: runs of right-to-left characters (with the neutrals between
+// them and any embedded numbers) are reversed, and digit sequences inside a
+// run keep their left-to-right order. Explicit embeddings and bracket
+// mirroring are not handled.
+//
+// Buffers, cursor coordinates, and selection anchors stay logical-per-cell at
+// the parser level; only display (Render, compositor snapshots) and selection
+// extraction translate between visual and logical columns.
+
+type bidiClass uint8
+
+const (
+	bidiNeutral bidiClass = iota
+	bidiLTR
+	bidiRTL
+	bidiNumber
+)
+
+// isRTLRune reports whether r is a strong right-to-left character.
+func isRTLRune(r rune) bool {
+	switch {
+	case r < 0x0590:
+		return false
+	case r <= 0x08FF: // Hebrew, Arabic, Syriac, Thaana, NKo, Samaritan, Mandaic
+		return !isArabicIndicDigit(r)
+	case r >= 0xFB1D && r <= 0xFDFF: // Hebrew and Arabic presentation forms A
+		return true
+	case r >= 0xFE70 && r <= 0xFEFF: // Arabic presentation forms B
+		return r != 0xFEFF
+	case r >= 0x10800 && r <= 0x10FFF, r >= 0x1E800 && r <= 0x1EFFF:
+		return true
+	}
+	return false
+}
+
+func isArabicIndicDigit(r rune) bool {
+	return (r >= 0x0660 && r <= 0x0669) || (r >= 0x06F0 && r <= 0x06F9)
+}
+
+func classifyBidi(c Cell) bidiClass {
+	r := c.Rune
+	switch {
+	case r >= '0' && r <= '9', isArabicIndicDigit(r):
+		return bidiNumber
+	case isRTLRune(r):
+		return bidiRTL
+	case unicode.IsLetter(r):
+		return bidiLTR
+	}
+	return bidiNeutral
+}
+
+// rowHasRTL reports whether row holds any right-to-left character; rows
+// without one display in logical order and skip the bidi pass.
+func rowHasRTL(row []Cell) bool {
+	for i := range row {
+		if r := row[i].Rune; r >= 0x0590 && isRTLRune(r) {
+			return true
+		}
+	}
+	return false
+}
+
+// bidiUnit is a cell plus its continuation cells, which move together.
+type bidiUnit struct {
+	start, width int
+	class        bidiClass
+}
+
+// appendVisualOrder appends to dst, for each visual column of row, the logical
+// column displayed there. It appends nothing when row needs no reordering.
+func appendVisualOrder(dst []int, row []Cell) []int {
+	if !rowHasRTL(row) {
+		return dst
+	}
+	units := make([]bidiUnit, 0, len(row))
+	for i := 0; i < len(row); {
+		w := 1
+		if row[i].Width == 2 {
+			for i+w < len(row) && w < 2 && row[i+w].Width == 0 {
+				w++
+			}
+		}
+		units = append(units, bidiUnit{start: i, width: w, class: classifyBidi(row[i])})
+		i += w
+	}
+
+	for i := 0; i < len(units); {
+		if units[i].class != bidiRTL {
+			i++
+			continue
+		}
+		// A run spans from this RTL character to the last RTL character or
+		// number before the next LTR letter; trailing neutrals stay put.
+		end := i
+		for j := i + 1; j < len(units) && units[j].class != bidiLTR; j++ {
+			if units[j].class == bidiRTL || units[j].class == bidiNumber {
+				end = j
+			}
+		}
+		reverseUnits(units[i : end+1])
+		// Numbers inside the run read left to right.
+		for k := i; k <= end; {
+			if units[k].class != bidiNumber {
+				k++
+				continue
+			}
+			n := k
+			for n+1 <= end && units[n+1].class == bidiNumber {
+				n++
+			}
+			reverseUnits(units[k : n+1])
+			k = n + 1
+		}
+		i = end + 1
+	}
+
+	for _, u := range units {
+		for c := 0; c < u.width; c++ {
+			dst = append(dst, u.start+c)
+		}
+	}
+	return dst
+}
+
+func reverseUnits(units []bidiUnit) {
+	for i, j := 0, len(units)-1; i < j; i, j = i+1, j-1 {
+		units[i], units[j] = units[j], units[i]
+	}
+}
+
+// VisualOrder returns, for each visual column of row, the logical column
+// displayed there, or nil when row displays in logical order.
+func VisualOrder(row []Cell) []int {
+	order := appendVisualOrder(nil, row)
+	if len(order) == 0 {
+		return nil
+	}
+	return order
+}
+
+// ReorderVisual rearranges row in place into display order. It reports whether
+// anything moved; rows without right-to-left text are left untouched.
+func ReorderVisual(row []Cell) bool {
+	order := appendVisualOrder(nil, row)
+	if len(order) == 0 {
+		return false
+	}
+	logical := CopyLine(row)
+	for x, src := range order {
+		row[x] = logical[src]
+	}
+	return true
+}
+
+// visualColumn maps logical column x of row to the visual column it displays
+// at. Columns past the row, or rows without right-to-left text, map to
+// themselves.
+func visualColumn(order []int, x int) int {
+	for vx, lx := range order {
+		if lx == x {
+			return vx
+		}
+	}
+	return x
+}
+
+// VisualCursorX returns the column the cursor displays at on the live screen,
+// accounting for bidi reordering of the cursor's row.
+func (v *VTerm) VisualCursorX() int {
+	screen, _ := v.RenderBuffers()
+	if v.CursorY < 0 || v.CursorY >= len(screen) {
+		return v.CursorX
+	}
+	return visualColumn(VisualOrder(screen[v.CursorY]), v.CursorX)
+}
@@ -0,0 +1,106 @@
+package vterm
+
+import (
+	"strings"
+	"testing"
+)
+
+// orderedRowText returns row's text in the given column order (nil = logical).
+func orderedRowText(row []Cell, order []int) string {
+	var b strings.Builder
+	for x := range row {
+		cell := row[x]
+		if order != nil {
+			cell = row[order[x]]
+		}
+		if cell.Width == 0 {
+			continue
+		}
+		b.WriteRune(cell.Rune)
+	}
+	return strings.TrimRight(b.String(), " ")
+}
+
+func writeRow(t *testing.T, text string) *VTerm {
+	t.Helper()
+	vt := New(30, 3)
+	vt.Write([]byte(text))
+	return vt
+}
+
+func TestVisualOrder(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"ltr only", "hello world", ""},
+		{"rtl word in ltr line", "abc שלום def", "abc םולש def"},
+		{"rtl words keep neutrals between them", "שלום עולם!", "םלוע םולש!"},
+		{"numbers inside rtl stay ltr", "שלום 123 עולם", "םלוע 123 םולש"},
+		{"trailing number joins the run", "מחיר 42", "42 ריחמ"},
+		{"wide ltr cell stays whole", "中 שלום", "中 םולש"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			vt := writeRow(t, tt.in)
+			row := vt.Screen[0]
+			order := VisualOrder(row)
+			if tt.want == "" {
+				if order != nil {
+					t.Fatalf("VisualOrder = %v, want nil for a row without rtl text", order)
+				}
+				return
+			}
+			if got := orderedRowText(row, order); got != tt.want {
+				t.Fatalf("visual text = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReorderVisualMatchesVisualOrder(t *testing.T) {
+	vt := writeRow(t, "abc שלום def")
+	row := CopyLine(vt.Screen[0])
+	if !ReorderVisual(row) {
+		t.Fatal("expected ReorderVisual to move an rtl row")
+	}
+	if got := orderedRowText(row, nil); got != "abc םולש def" {
+		t.Fatalf("reordered row = %q", got)
+	}
+	ascii := CopyLine(writeRow(t, "plain").Screen[0])
+	if ReorderVisual(ascii) {
+		t.Fatal("expected an ltr row to be left alone")
+	}
+}
+
+func TestRenderDisplaysRTLInVisualOrder(t *testing.T) {
+	vt := writeRow(t, "abc שלום def")
+	if out := vt.Render(); !strings.Contains(out, "abc םולש def") {
+		t.Fatalf("Render() = %q, want the rtl word in visual order", out)
+	}
+}
+
+func TestSelectedTextReturnsLogicalOrderForRTL(t *testing.T) {
+	vt := writeRow(t, "abc שלום def")
+	// Visual columns 4-7 hold the reversed word; copying returns it as written.
+	if got := vt.GetSelectedText(4, 0, 7, 0); got != "שלום" {
+		t.Fatalf("selected text = %q, want %q", got, "שלום")
+	}
+	if got := vt.GetSelectedText(0, 0, 11, 0); got != "abc שלום def" {
+		t.Fatalf("full-line selection = %q", got)
+	}
+}
+
+func TestVisualCursorXFollowsRTLRun(t *testing.T) {
+	vt := writeRow(t, "שלום")
+	// The cursor sits after the word logically (column 4), which is still
+	// column 4 visually; moving it onto the first letter maps to column 3.
+	if got := vt.VisualCursorX(); got != 4 {
+		t.Fatalf("VisualCursorX() = %d, want 4", got)
+	}
+	vt.Write([]byte("\r"))
+	if got := vt.VisualCursorX(); got != 3 {
+		t.Fatalf("VisualCursorX() at line start = %d, want 3", got)
+	}
+}
@@ -15,7 +15,8 @@ func (v *VTerm) bumpRenderEpoch() uint64 {
 
 func (v *VTerm) ensureRenderCache(height int) {
 	if len(v.renderCache) != height {
-		v.renderCache = make([]string, height)
+		v.renderCache = make([][]byte, height)
+		v.renderLineHash = make([]uint64, height)
 		v.renderLineEpoch = make([]uint64, height)
 		v.renderGlobalEpoch = v.bumpRenderEpoch()
 	}
@@ -56,6 +57,7 @@ func (v *VTerm) markDirtyRange(start, end int) {
 
 func (v *VTerm) invalidateRenderCache() {
 	v.renderCache = nil
+	v.renderLineHash = nil
 	v.renderLineEpoch = nil
 	v.renderGlobalEpoch = v.bumpRenderEpoch()
 	v.bumpVersion()
@@ -134,3 +136,110 @@ func (v *VTerm) RenderAndClear() string {
 	v.ClearDirty()
 	return out
 }
+
+// markCursorLinesDirty invalidates the old and new cursor lines when the
+// cursor state changed since the last cached render.
+func (v *VTerm) markCursorLinesDirty() {
+	if v.ShowCursor == v.lastShowCursor && v.CursorHiddenForRender() == v.lastCursorHidden && v.CursorX == v.lastCursorX && v.CursorY == v.lastCursorY {
+		return
+	}
+	epoch := v.bumpRenderEpoch()
+	// Mark old cursor line dirty
+	if v.lastCursorY >= 0 && v.lastCursorY < len(v.renderLineEpoch) {
+		v.renderLineEpoch[v.lastCursorY] = epoch
+	}
+	// Mark new cursor line dirty
+	if v.CursorY >= 0 && v.CursorY < len(v.renderLineEpoch) {
+		v.renderLineEpoch[v.CursorY] = epoch
+	}
+	v.lastShowCursor = v.ShowCursor
+	v.lastCursorHidden = v.CursorHiddenForRender()
+	v.lastCursorX = v.CursorX
+	v.lastCursorY = v.CursorY
+}
+
+// refreshRenderCache brings every cached live-screen line up to date and
+// clears dirtiness.
+func (v *VTerm) refreshRenderCache(screen [][]Cell) {
+	v.ensureRenderCache(len(screen))
+	v.markCursorLinesDirty()
+	for y := range screen {
+		v.cachedLine(screen, y)
+	}
+	// The cached render pass consumed all outstanding dirtiness.
+	v.ClearDirty()
+}
+
+// cachedLine returns the encoded bytes for live line y, re-encoding only when
+// the line is dirty and its content hash differs from the cached one. A dirty
+// mark whose cells, cursor, and selection ended up unchanged (a redraw of the
+// same text, a cursor blink elsewhere on a clean row) is skipped.
+func (v *VTerm) cachedLine(screen [][]Cell, y int) []byte {
+	cached := v.renderCache[y]
+	if len(cached) > 0 && !v.lineDirty(y) {
+		return cached
+	}
+	hash := v.rowHash(screen[y], y)
+	if len(cached) > 0 && v.renderLineHash[y] == hash {
+		return cached
+	}
+	v.renderCache[y] = v.appendRow(cached[:0], screen[y], y)
+	v.renderLineHash[y] = hash
+	return v.renderCache[y]
+}
+
+// rowHash fingerprints everything appendRow reads for row y: the cells, the
+// pane width, the cursor position when it is drawn on this row, and the
+// selection and search masks. It is an inline FNV-1a so hashing never allocates.
+func (v *VTerm) rowHash(row []Cell, y int) uint64 {
+	const (
+		offset = 14695981039346656037
+		prime  = 1099511628211
+	)
+	h := uint64(offset)
+	mix := func(x uint64) {
+		h ^= x
+		h *= prime
+	}
+	mix(uint64(v.Width))
+	cursorX := -1
+	if v.ShowCursor && !v.CursorHiddenForRender() && y == v.CursorY && v.ViewOffset == 0 {
+		cursorX = v.CursorX
+	}
+	mix(uint64(int64(cursorX)))
+	sel := v.ViewSelection()
+	search := v.rowSearchHighlighter(y)
+	for x := 0; x < v.Width; x++ {
+		cell := DefaultCell()
+		if x < len(row) {
+			cell = row[x]
+		}
+		mix(uint64(cell.Rune))
+		mix(uint64(cell.Width))
+		mix(styleBits(cell.Style))
+		mix(uint64(cell.Style.Fg.Type)<<32 | uint64(cell.Style.Fg.Value))
+		mix(uint64(cell.Style.Bg.Type)<<32 | uint64(cell.Style.Bg.Value))
+		for i := 0; i < len(cell.GraphemeCluster); i++ {
+			mix(uint64(cell.GraphemeCluster[i]))
+		}
+		if sel.Contains(x, y) {
+			mix(1)
+		} else {
+			mix(0)
+		}
+		match := search.Style(Style{}, x, y)
+		mix(styleBits(match) | uint64(match.Bg.Value)<<8)
+	}
+	return h
+}
+
+// styleBits packs a style's boolean attributes into one word for hashing.
+func styleBits(s Style) uint64 {
+	var bits uint64
+	for i, on := range [...]bool{s.Bold, s.Dim, s.Italic, s.Underline, s.Blink, s.Reverse, s.Hidden, s.Strike} {
+		if on {
+			bits |= 1 << i
+		}
+	}
+	return bits
+}
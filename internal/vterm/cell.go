@@ -32,7 +32,10 @@ type Style struct {
 type Cell struct {
 	Rune  rune
 	Style Style
-	Width int // 1 normal, 2 wide, 0 continuation
+	// Wrapped marks the last cell of a row that auto-wrapped into the next
+	// row, so the two belong to one logical line (see reflow.go).
+	Wrapped bool
+	Width   int // 1 normal, 2 wide, 0 continuation
 	// GraphemeCluster, when non-empty, is the full grapheme (base rune plus
 	// combining marks) for this cell. Empty means "use Rune". Readers that emit
 	// text should prefer it; width/layout logic still uses Rune + Width.
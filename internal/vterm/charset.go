@@ -0,0 +1,100 @@
+package vterm
+
+// charset is a character set designated into G0 or G1 (ESC ( / ESC )).
+type charset uint8
+
+const (
+	charsetASCII charset = iota
+	// charsetDECGraphics is the DEC special graphics set (ESC ( 0), which
+	// TUIs use for line-drawing borders.
+	charsetDECGraphics
+	// charsetUK is the UK national set (ESC ( A), identical to ASCII except
+	// '#' is the pound sign.
+	charsetUK
+)
+
+// charsetState is the G0/G1 designations plus which one is invoked into GL
+// (SI selects G0, SO selects G1). DECSC/DECRC save and restore it with the
+// cursor.
+type charsetState struct {
+	g      [2]charset
+	shifts int // 0 = G0, 1 = G1
+}
+
+// charsetFor maps a designation final byte to its charset. Unsupported sets
+// fall back to ASCII, matching what most terminals display for them.
+func charsetFor(final byte) charset {
+	switch final {
+	case '0':
+		return charsetDECGraphics
+	case 'A':
+		return charsetUK
+	}
+	return charsetASCII
+}
+
+// decGraphics maps DEC special graphics codes 0x5f-0x7e to Unicode.
+var decGraphics = [...]rune{
+	' ', // 0x5f blank
+	'◆', // ` diamond
+	'▒', // a checkerboard
+	'␉', // b HT
+	'␌', // c FF
+	'␍', // d CR
+	'␊', // e LF
+	'°', // f degree
+	'±', // g plus/minus
+	'␤', // h NL
+	'␋', // i VT
+	'┘', // j lower right corner
+	'┐', // k upper right corner
+	'┌', // l upper left corner
+	'└', // m lower left corner
+	'┼', // n crossing lines
+	'⎺', // o scan line 1
+	'⎻', // p scan line 3
+	'─', // q horizontal line (scan line 5)
+	'⎼', // r scan line 7
+	'⎽', // s scan line 9
+	'├', // t left tee
+	'┤', // u right tee
+	'┴', // v bottom tee
+	'┬', // w top tee
+	'│', // x vertical line
+	'≤', // y less than or equal
+	'≥', // z greater than or equal
+	'π', // { pi
+	'≠', // | not equal
+	'£', // } pound
+	'·', // ~ centered dot
+}
+
+// translateCharset maps a printable ASCII byte through the charset currently
+// invoked into GL.
+func (v *VTerm) translateCharset(r rune) rune {
+	switch v.charsets.g[v.charsets.shifts] {
+	case charsetDECGraphics:
+		if r >= 0x5f && r <= 0x7e {
+			return decGraphics[r-0x5f]
+		}
+	case charsetUK:
+		if r == '#' {
+			return '£'
+		}
+	}
+	return r
+}
+
+// designateCharset sets G0 (slot 0) or G1 (slot 1) from a designation final
+// byte.
+func (v *VTerm) designateCharset(slot int, final byte) {
+	if slot < 0 || slot > 1 {
+		return
+	}
+	v.charsets.g[slot] = charsetFor(final)
+}
+
+// shiftCharset invokes G0 (SI) or G1 (SO) into GL.
+func (v *VTerm) shiftCharset(slot int) {
+	v.charsets.shifts = slot
+}
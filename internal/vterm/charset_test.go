@@ -0,0 +1,61 @@
+package vterm
+
+import (
+	"strings"
+	"testing"
+)
+
+func screenLine(vt *VTerm, y int) string {
+	var b strings.Builder
+	for _, c := range vt.Screen[y] {
+		if c.Width == 0 {
+			continue
+		}
+		b.WriteRune(c.Rune)
+	}
+	return strings.TrimRight(b.String(), " ")
+}
+
+func TestDECSpecialGraphicsDrawsBoxes(t *testing.T) {
+	vt := New(20, 3)
+	vt.Write([]byte("\x1b(0lqqk\r\nx  x\r\nmqqj\x1b(B ok"))
+
+	want := []string{"┌──┐", "│  │", "└──┘ ok"}
+	for y, line := range want {
+		if got := screenLine(vt, y); got != line {
+			t.Fatalf("line %d = %q, want %q", y, got, line)
+		}
+	}
+}
+
+func TestShiftOutInvokesG1(t *testing.T) {
+	vt := New(20, 1)
+	// G1 holds graphics; SO switches to it and SI switches back to ASCII G0.
+	vt.Write([]byte("\x1b)0q\x0eq\x0fq"))
+	if got := screenLine(vt, 0); got != "q─q" {
+		t.Fatalf("line = %q, want %q", got, "q─q")
+	}
+}
+
+func TestCharsetSavedWithCursorAndClearedByReset(t *testing.T) {
+	vt := New(20, 2)
+	vt.Write([]byte("\x1b(0\x1b7\x1b(Bq\x1b8q"))
+	if got := screenLine(vt, 0); got != "─" {
+		t.Fatalf("line = %q, want DECRC to restore the graphics set", got)
+	}
+
+	vt.Write([]byte("\x1b(0"))
+	vt.Reset()
+	vt.Write([]byte("q"))
+	if got := screenLine(vt, 0); got != "q" {
+		t.Fatalf("line after reset = %q, want ASCII", got)
+	}
+}
+
+func TestUKCharsetPoundSign(t *testing.T) {
+	vt := New(10, 1)
+	vt.Write([]byte("\x1b(A#1"))
+	if got := screenLine(vt, 0); got != "£1" {
+		t.Fatalf("line = %q, want %q", got, "£1")
+	}
+}
@@ -159,8 +159,12 @@ func (p *Parser) executeCSI(final byte) {
 		p.vt.preserveScrollbackOnNextClear3 = false
 	}
 	switch final {
-	case 'A': // CUU - cursor up
-		p.vt.moveCursor(-p.getParam(0, 1), 0)
+	case 'A':
+		if p.csiIntermediate == ' ' { // SR - scroll right
+			p.vt.scrollRight(p.getParam(0, 1))
+		} else { // CUU - cursor up
+			p.vt.moveCursor(-p.getParam(0, 1), 0)
+		}
 	case 'B': // CUD - cursor down
 		p.vt.moveCursor(p.getParam(0, 1), 0)
 	case 'C': // CUF - cursor forward
@@ -205,8 +209,12 @@ func (p *Parser) executeCSI(final byte) {
 		p.vt.scrollDown(p.getParam(0, 1))
 	case 'X': // ECH - erase chars
 		p.vt.eraseChars(p.getParam(0, 1))
-	case '@': // ICH - insert chars
-		p.vt.insertChars(p.getParam(0, 1))
+	case '@':
+		if p.csiIntermediate == ' ' { // SL - scroll left
+			p.vt.scrollLeft(p.getParam(0, 1))
+		} else { // ICH - insert chars
+			p.vt.insertChars(p.getParam(0, 1))
+		}
 	case 'd': // VPA - vertical position absolute
 		oldX, oldY := p.vt.CursorX, p.vt.CursorY
 		row := p.getParam(0, 1)
@@ -225,9 +233,13 @@ func (p *Parser) executeCSI(final byte) {
 		top := p.getParam(0, 1)
 		bottom := p.getParam(1, p.vt.Height)
 		p.vt.setScrollRegion(top, bottom)
-	case 's': // SCP - save cursor position
+	case 's': // SCP - save cursor position, or DECSLRM under DECLRMM
 		if p.intermediate == 0 && p.csiIntermediate == 0 {
-			p.vt.saveCursor()
+			if p.vt.lrMarginMode {
+				p.vt.setLeftRightMargins(p.getParam(0, 1), p.getParam(1, p.vt.Width))
+			} else {
+				p.vt.saveCursor()
+			}
 		}
 	case 'u': // RCP - restore cursor position
 		if p.intermediate == 0 && p.csiIntermediate == 0 {
@@ -247,6 +259,14 @@ func (p *Parser) executeCSI(final byte) {
 		p.executeMode(false)
 	case 't': // Window operations
 		// Ignore
+	case '}': // DECIC - insert columns
+		if p.csiIntermediate == '\'' {
+			p.vt.insertColumns(p.getParam(0, 1))
+		}
+	case '~': // DECDC - delete columns
+		if p.csiIntermediate == '\'' {
+			p.vt.deleteColumns(p.getParam(0, 1))
+		}
 	case 'p': // DECRQM - request mode report
 		if p.intermediate == '?' && p.csiIntermediate == '$' {
 			p.executeDECRQM()
@@ -66,8 +66,9 @@ func (v *VTerm) clampSavedCursor() {
 func (v *VTerm) setCursorPos(row, col int) {
 	prevX, prevY := v.CursorX, v.CursorY
 	if v.OriginMode {
+		left, right := v.horizontalMargins()
 		v.CursorY = v.ScrollTop + row - 1
-		v.CursorX = col - 1
+		v.CursorX = min(left+col-1, right-1)
 		v.clampCursor()
 		v.bumpVersionIfCursorMoved(prevX, prevY)
 		return
@@ -79,11 +80,19 @@ func (v *VTerm) setCursorPos(row, col int) {
 	v.bumpVersionIfCursorMoved(prevX, prevY)
 }
 
-// moveCursor moves cursor relative to current position
+// moveCursor moves cursor relative to current position. Vertical moves stop
+// at the scroll margins when the cursor starts inside the region (CUU/CUD
+// semantics), and at the screen edges otherwise.
 func (v *VTerm) moveCursor(dy, dx int) {
 	prevX, prevY := v.CursorX, v.CursorY
 	v.CursorX += dx
 	v.CursorY += dy
+	if dy < 0 && prevY >= v.ScrollTop && v.CursorY < v.ScrollTop {
+		v.CursorY = v.ScrollTop
+	}
+	if dy > 0 && prevY < v.ScrollBottom && v.CursorY >= v.ScrollBottom {
+		v.CursorY = v.ScrollBottom - 1
+	}
 
 	v.clampCursor()
 	v.bumpVersionIfCursorMoved(prevX, prevY)
@@ -138,6 +147,7 @@ func (v *VTerm) exitAltScreen() {
 	if !v.AltScreen {
 		return
 	}
+	v.setPrimaryPeek(false)
 	v.AltScreen = false
 	v.invalidateAltScreenCapture()
 	v.Screen = v.altScreenBuf
@@ -153,6 +163,8 @@ func (v *VTerm) saveCursor() {
 	v.SavedCursorX = v.CursorX
 	v.SavedCursorY = v.CursorY
 	v.SavedStyle = v.CurrentStyle
+	v.savedOriginMode = v.OriginMode
+	v.savedCharsets = v.charsets
 }
 
 // restoreCursor restores cursor position and attributes
@@ -162,5 +174,8 @@ func (v *VTerm) restoreCursor() {
 	v.CursorX = v.SavedCursorX
 	v.CursorY = v.SavedCursorY
 	v.CurrentStyle = v.SavedStyle
+	v.OriginMode = v.savedOriginMode
+	v.charsets = v.savedCharsets
+	v.clampCursor()
 	v.bumpVersionIfCursorMoved(prevX, prevY)
 }
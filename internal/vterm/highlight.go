@@ -0,0 +1,158 @@
+package vterm
+
+// Highlighting shared by every render path. The live string renderer
+// (Render), compositor snapshots drawn by VTermLayer, and the canvas fallback
+// (DrawScreen) all resolve the selection with ViewSelection and style cells
+// through the helpers below, so block selections, wide characters, search
+// matches, and scrolled views highlight the same way everywhere.
+
+// Search highlight colors. Matches are painted over the cell's own colors so
+// they stand out regardless of the program's palette; the current match uses
+// a brighter background so it is distinguishable at a glance.
+var (
+	SearchMatchBg   = Color{Type: ColorIndexed, Value: 3}
+	SearchCurrentBg = Color{Type: ColorIndexed, Value: 208}
+	SearchMatchFg   = Color{Type: ColorIndexed, Value: 0}
+)
+
+// SelectionBounds is a normalized selection: (StartX, StartY) precedes
+// (EndX, EndY) in reading order. A linear selection runs from the start to
+// the end like text; a Rect (block) selection covers columns StartX..EndX on
+// every row from StartY to EndY. Rows are absolute lines or viewport rows
+// depending on where the bounds came from.
+type SelectionBounds struct {
+	Active                     bool
+	Rect                       bool
+	StartX, StartY, EndX, EndY int
+}
+
+// NewSelectionBounds normalizes an active selection between two endpoints
+// given in any order.
+func NewSelectionBounds(startX, startY, endX, endY int, rect bool) SelectionBounds {
+	startX, startY, endX, endY = NormalizeSelectionRange(startX, startY, endX, endY)
+	if rect && startX > endX {
+		startX, endX = endX, startX
+	}
+	return SelectionBounds{Active: true, Rect: rect, StartX: startX, StartY: startY, EndX: endX, EndY: endY}
+}
+
+// Contains reports whether column x of row y is selected.
+func (b SelectionBounds) Contains(x, y int) bool {
+	if !b.Active {
+		return false
+	}
+	if b.Rect {
+		return y >= b.StartY && y <= b.EndY && x >= b.StartX && x <= b.EndX
+	}
+	return SelectionContains(b.StartX, b.StartY, b.EndX, b.EndY, x, y)
+}
+
+// ContainsCell reports whether cell, drawn at column x of row y, should be
+// highlighted. A wide character highlights as a unit when either of its
+// columns is selected, so an edge landing on its second column still covers
+// the glyph.
+func (b SelectionBounds) ContainsCell(cell Cell, x, y int) bool {
+	if !b.Active {
+		return false
+	}
+	switch cell.Width {
+	case 2:
+		return b.Contains(x, y) || b.Contains(x+1, y)
+	case 0:
+		return b.Contains(x, y) || b.Contains(x-1, y)
+	}
+	return b.Contains(x, y)
+}
+
+// Clip restricts the bounds to the height rows starting at row top and to
+// columns [0, width), rebasing rows so top becomes 0. A linear selection that
+// continues past the window runs to its edge. The result is inactive when no
+// selected row is in the window.
+func (b SelectionBounds) Clip(top, height, width int) SelectionBounds {
+	bottom := top + height - 1
+	if !b.Active || width <= 0 || height <= 0 || b.EndY < top || b.StartY > bottom {
+		return SelectionBounds{}
+	}
+	if b.StartY < top {
+		b.StartY = top
+		if !b.Rect {
+			b.StartX = 0
+		}
+	}
+	if b.EndY > bottom {
+		b.EndY = bottom
+		if !b.Rect {
+			b.EndX = width - 1
+		}
+	}
+	b.StartX = clampInt(b.StartX, 0, width-1)
+	b.EndX = clampInt(b.EndX, 0, width-1)
+	b.StartY -= top
+	b.EndY -= top
+	return b
+}
+
+// Selection returns the selection in absolute lines (0 = oldest scrollback
+// row).
+func (v *VTerm) Selection() SelectionBounds {
+	if !v.selActive {
+		return SelectionBounds{}
+	}
+	return NewSelectionBounds(v.selStartX, v.selStartLine, v.selEndX, v.selEndLine, v.selRect)
+}
+
+// ViewSelection returns the selection in viewport rows (0 = top visible row),
+// clipped to the view.
+func (v *VTerm) ViewSelection() SelectionBounds {
+	return v.Selection().Clip(v.ScreenYToAbsoluteLine(0), v.Height, v.Width)
+}
+
+// HighlightStyle applies the selection and cursor highlights to style. Both
+// reverse the cell, and a selected cell under the cursor reverses once so the
+// cursor stays visible as part of the selection.
+func HighlightStyle(style Style, selected, cursor bool) Style {
+	if selected || cursor {
+		style.Reverse = !style.Reverse
+	}
+	return style
+}
+
+// SearchHighlighter restyles cells covered by search spans. Cells must be
+// visited in draw order (rows top to bottom, columns left to right), which
+// classifies each in amortized constant time.
+type SearchHighlighter struct {
+	spans []SearchSpan
+	i     int
+}
+
+// NewSearchHighlighter returns a highlighter over spans, which must be in row
+// then column order as AppendVisibleSearchMatches produces them.
+func NewSearchHighlighter(spans []SearchSpan) SearchHighlighter {
+	return SearchHighlighter{spans: spans}
+}
+
+// Style returns style restyled for a match covering column x of row y, or
+// style unchanged.
+func (h *SearchHighlighter) Style(style Style, x, y int) Style {
+	for h.i < len(h.spans) {
+		s := h.spans[h.i]
+		if s.Y > y || (s.Y == y && s.StartX > x) {
+			return style
+		}
+		if s.Y == y && x <= s.EndX {
+			style.Fg, style.Bg = SearchMatchFg, SearchMatchBg
+			style.Reverse = false
+			if s.Current {
+				style.Bg = SearchCurrentBg
+				style.Bold = true
+			}
+			return style
+		}
+		h.i++
+	}
+	return style
+}
+
+func clampInt(v, lo, hi int) int {
+	return max(lo, min(v, hi))
+}
@@ -0,0 +1,55 @@
+package vterm
+
+import "testing"
+
+func TestSelectionBoundsClip(t *testing.T) {
+	tests := []struct {
+		name string
+		in   SelectionBounds
+		want SelectionBounds
+	}{
+		{
+			name: "linear runs to the window edges",
+			in:   NewSelectionBounds(5, 2, 3, 9, false),
+			want: SelectionBounds{Active: true, StartX: 0, StartY: 0, EndX: 7, EndY: 3},
+		},
+		{
+			name: "block keeps its columns",
+			in:   NewSelectionBounds(5, 2, 3, 9, true),
+			want: SelectionBounds{Active: true, Rect: true, StartX: 3, StartY: 0, EndX: 5, EndY: 3},
+		},
+		{
+			name: "inside the window is rebased",
+			in:   NewSelectionBounds(1, 5, 2, 6, false),
+			want: SelectionBounds{Active: true, StartX: 1, StartY: 1, EndX: 2, EndY: 2},
+		},
+		{
+			name: "outside the window is inactive",
+			in:   NewSelectionBounds(0, 0, 7, 3, false),
+			want: SelectionBounds{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.in.Clip(4, 4, 8); got != tt.want {
+				t.Fatalf("Clip = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestViewSelectionMatchesIsInSelection(t *testing.T) {
+	vt := New(6, 2)
+	vt.Write([]byte("a\r\nb\r\nc\r\nd"))
+	vt.SetSelection(4, 1, 2, 3, true, true)
+	vt.ScrollViewTo(1)
+
+	sel := vt.ViewSelection()
+	for y := 0; y < vt.Height; y++ {
+		for x := 0; x < vt.Width; x++ {
+			if sel.Contains(x, y) != vt.IsInSelection(x, y) {
+				t.Fatalf("(%d,%d): ViewSelection=%v IsInSelection=%v", x, y, sel.Contains(x, y), vt.IsInSelection(x, y))
+			}
+		}
+	}
+}
@@ -1,10 +1,17 @@
 package vterm
 
-// insertLines inserts n blank lines at cursor, pushing content down
+// insertLines inserts n blank lines at cursor, pushing content down. Like
+// xterm it leaves the cursor at the left margin.
 func (v *VTerm) insertLines(n int) {
-	if v.CursorY < v.ScrollTop || v.CursorY >= v.ScrollBottom {
+	if !v.cursorInScrollRegion() || !v.cursorInMargins() {
 		return
 	}
+	if left, right, ok := v.partialMargins(); ok {
+		v.CursorX = left
+		v.scrollRect(v.CursorY, v.ScrollBottom, left, right, -n)
+		return
+	}
+	v.CursorX = 0
 
 	// Clamp n to remaining space in scroll region
 	maxN := v.ScrollBottom - v.CursorY
@@ -28,11 +35,18 @@ func (v *VTerm) insertLines(n int) {
 	v.markDirtyRange(v.ScrollTop, v.ScrollBottom-1)
 }
 
-// deleteLines deletes n lines at cursor, pulling content up
+// deleteLines deletes n lines at cursor, pulling content up. Like xterm it
+// leaves the cursor at the left margin.
 func (v *VTerm) deleteLines(n int) {
-	if v.CursorY < v.ScrollTop || v.CursorY >= v.ScrollBottom {
+	if !v.cursorInScrollRegion() || !v.cursorInMargins() {
 		return
 	}
+	if left, right, ok := v.partialMargins(); ok {
+		v.CursorX = left
+		v.scrollRect(v.CursorY, v.ScrollBottom, left, right, n)
+		return
+	}
+	v.CursorX = 0
 
 	// Clamp n to remaining space in scroll region
 	maxN := v.ScrollBottom - v.CursorY
@@ -61,6 +75,12 @@ func (v *VTerm) insertChars(n int) {
 	if v.CursorY >= len(v.Screen) {
 		return
 	}
+	if _, right, ok := v.partialMargins(); ok {
+		if v.cursorInMargins() {
+			v.shiftColumns(v.CursorY, v.CursorY+1, v.CursorX, right, n)
+		}
+		return
+	}
 	line := v.Screen[v.CursorY]
 	normalizeLine(line)
 
@@ -86,6 +106,12 @@ func (v *VTerm) deleteChars(n int) {
 	if v.CursorY >= len(v.Screen) {
 		return
 	}
+	if _, right, ok := v.partialMargins(); ok {
+		if v.cursorInMargins() {
+			v.shiftColumns(v.CursorY, v.CursorY+1, v.CursorX, right, -n)
+		}
+		return
+	}
 	// Clamp n to the cells from the cursor to end of line (xterm DCH
 	// semantics: DCH never affects cells left of the cursor).
 	if remaining := v.Width - v.CursorX; n > remaining {
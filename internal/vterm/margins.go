@@ -0,0 +1,176 @@
+package vterm
+
+// Left/right margins (DECSLRM) and the column-oriented edits that honor them.
+// Margins only take effect while DECLRMM (mode ?69) is set, which is how
+// applications probe for support: they query the mode with DECRQM and fall
+// back to full-width redraws when it is not recognized. Without the mode,
+// CSI s keeps meaning "save cursor" and every edit spans the full width.
+
+// horizontalMargins returns the active left margin and exclusive right
+// margin, clamped to the current width.
+func (v *VTerm) horizontalMargins() (left, right int) {
+	if !v.lrMarginMode || v.marginRight <= v.marginLeft {
+		return 0, v.Width
+	}
+	left, right = v.marginLeft, v.marginRight
+	if right > v.Width {
+		right = v.Width
+	}
+	if left >= right {
+		return 0, v.Width
+	}
+	return left, right
+}
+
+// partialMargins reports the margins when they narrow the screen, so callers
+// can keep their whole-row fast paths for the common full-width case.
+func (v *VTerm) partialMargins() (left, right int, ok bool) {
+	left, right = v.horizontalMargins()
+	return left, right, left > 0 || right < v.Width
+}
+
+// cursorInMargins reports whether the cursor column lies inside the
+// horizontal margins.
+func (v *VTerm) cursorInMargins() bool {
+	left, right := v.horizontalMargins()
+	return v.CursorX >= left && v.CursorX < right
+}
+
+// setLRMarginMode handles DECLRMM. Leaving the mode drops the margins.
+func (v *VTerm) setLRMarginMode(set bool) {
+	v.lrMarginMode = set
+	v.marginLeft, v.marginRight = 0, 0
+}
+
+// setLeftRightMargins handles DECSLRM (1-indexed input). Like DECSTBM it
+// ignores empty regions and homes the cursor.
+func (v *VTerm) setLeftRightMargins(left, right int) {
+	l := left - 1
+	r := right
+	if l < 0 {
+		l = 0
+	}
+	if r > v.Width {
+		r = v.Width
+	}
+	if l >= r {
+		return
+	}
+	v.marginLeft, v.marginRight = l, r
+	v.setCursorPos(1, 1)
+}
+
+// insertColumns handles DECIC: blank columns are inserted at the cursor in
+// every row of the scroll region, pushing the rest toward the right margin.
+func (v *VTerm) insertColumns(n int) {
+	if !v.cursorInScrollRegion() || !v.cursorInMargins() {
+		return
+	}
+	_, right := v.horizontalMargins()
+	v.shiftColumns(v.ScrollTop, v.ScrollBottom, v.CursorX, right, n)
+}
+
+// deleteColumns handles DECDC, the inverse of insertColumns.
+func (v *VTerm) deleteColumns(n int) {
+	if !v.cursorInScrollRegion() || !v.cursorInMargins() {
+		return
+	}
+	_, right := v.horizontalMargins()
+	v.shiftColumns(v.ScrollTop, v.ScrollBottom, v.CursorX, right, -n)
+}
+
+// scrollLeft handles SL: the region between the margins moves n columns
+// left, regardless of where the cursor is.
+func (v *VTerm) scrollLeft(n int) {
+	left, right := v.horizontalMargins()
+	v.shiftColumns(v.ScrollTop, v.ScrollBottom, left, right, -n)
+}
+
+// scrollRight handles SR, the inverse of scrollLeft.
+func (v *VTerm) scrollRight(n int) {
+	left, right := v.horizontalMargins()
+	v.shiftColumns(v.ScrollTop, v.ScrollBottom, left, right, n)
+}
+
+func (v *VTerm) cursorInScrollRegion() bool {
+	return v.CursorY >= v.ScrollTop && v.CursorY < v.ScrollBottom
+}
+
+// shiftColumns moves the cells in columns [from, right) of rows [top, bottom)
+// by n columns: right when n > 0, leaving blanks at from, and left when n < 0,
+// leaving blanks before right.
+func (v *VTerm) shiftColumns(top, bottom, from, right, n int) {
+	if n == 0 || from >= right {
+		return
+	}
+	span := right - from
+	if n > span {
+		n = span
+	}
+	if n < -span {
+		n = -span
+	}
+	v.ClearSelection()
+	for y := top; y < bottom && y < len(v.Screen); y++ {
+		line := v.Screen[y]
+		if right > len(line) {
+			continue
+		}
+		normalizeLine(line)
+		if n > 0 {
+			copy(line[from+n:right], line[from:right-n])
+			blankCells(line[from : from+n])
+		} else {
+			copy(line[from:right+n], line[from-n:right])
+			blankCells(line[right+n : right])
+		}
+		normalizeLine(line)
+	}
+	v.markDirtyRange(top, bottom-1)
+}
+
+// scrollRect scrolls rows [top, bottom) between columns [left, right) by n
+// rows: up when n > 0 and down when n < 0. It is the margin-bounded variant
+// of scrollUp/scrollDown; content leaving the rectangle is discarded rather
+// than saved to scrollback, since it never left the physical screen.
+func (v *VTerm) scrollRect(top, bottom, left, right, n int) {
+	if bottom > len(v.Screen) {
+		bottom = len(v.Screen)
+	}
+	height := bottom - top
+	if n == 0 || height <= 0 {
+		return
+	}
+	if n > height {
+		n = height
+	}
+	if n < -height {
+		n = -height
+	}
+	v.ClearSelection()
+	if n > 0 {
+		for y := top; y < bottom-n; y++ {
+			copy(v.Screen[y][left:right], v.Screen[y+n][left:right])
+		}
+		for y := bottom - n; y < bottom; y++ {
+			blankCells(v.Screen[y][left:right])
+		}
+	} else {
+		for y := bottom - 1; y >= top-n; y-- {
+			copy(v.Screen[y][left:right], v.Screen[y+n][left:right])
+		}
+		for y := top; y < top-n; y++ {
+			blankCells(v.Screen[y][left:right])
+		}
+	}
+	for y := top; y < bottom; y++ {
+		normalizeLine(v.Screen[y])
+	}
+	v.markDirtyRange(top, bottom-1)
+}
+
+func blankCells(cells []Cell) {
+	for i := range cells {
+		cells[i] = DefaultCell()
+	}
+}
@@ -0,0 +1,130 @@
+package vterm
+
+import "testing"
+
+// columnsTerm returns a 10x4 terminal whose rows read "0123456789".
+func columnsTerm(t *testing.T) *VTerm {
+	t.Helper()
+	vt := New(10, 4)
+	for y := 0; y < 4; y++ {
+		vt.Write([]byte("\x1b[" + string(rune('1'+y)) + ";1H0123456789"))
+	}
+	return vt
+}
+
+func TestDECSLRMRequiresDECLRMM(t *testing.T) {
+	vt := columnsTerm(t)
+	vt.Write([]byte("\x1b[2;3H\x1b[3;6s\x1b[1;1H\x1b[u"))
+	if vt.CursorX != 2 || vt.CursorY != 1 {
+		t.Fatalf("CSI s without DECLRMM should save the cursor; restored (%d,%d)", vt.CursorX, vt.CursorY)
+	}
+	if _, _, ok := vt.partialMargins(); ok {
+		t.Fatal("margins set without DECLRMM")
+	}
+}
+
+func TestDECLRMMReportedByDECRQM(t *testing.T) {
+	vt := New(10, 4)
+	var got string
+	vt.SetResponseWriter(func(b []byte) { got = string(b) })
+	vt.Write([]byte("\x1b[?69$p"))
+	if got != "\x1b[?69;2$y" {
+		t.Fatalf("DECRQM reset = %q", got)
+	}
+	vt.Write([]byte("\x1b[?69h\x1b[?69$p"))
+	if got != "\x1b[?69;1$y" {
+		t.Fatalf("DECRQM set = %q", got)
+	}
+}
+
+func TestMarginsBoundWrapAndCarriageReturn(t *testing.T) {
+	vt := New(10, 3)
+	vt.Write([]byte("\x1b[?69h\x1b[3;6s\x1b[1;3Habcdefg\rZ"))
+	if got := screenLine(vt, 0); got != "  abcd" {
+		t.Fatalf("row 0 = %q, want %q", got, "  abcd")
+	}
+	if got := screenLine(vt, 1); got != "  Zfg" {
+		t.Fatalf("row 1 = %q, want %q", got, "  Zfg")
+	}
+}
+
+func TestMarginsBoundScrolling(t *testing.T) {
+	vt := columnsTerm(t)
+	vt.Write([]byte("\x1b[?69h\x1b[3;6s\x1b[4;3H\n"))
+	want := []string{"0123456789", "0123456789", "0123456789", "01    6789"}
+	for y, line := range want {
+		if got := screenLine(vt, y); got != line {
+			t.Fatalf("row %d = %q, want %q", y, got, line)
+		}
+	}
+	if len(vt.Scrollback) != 0 {
+		t.Fatalf("margin-bounded scroll fed %d scrollback lines", len(vt.Scrollback))
+	}
+}
+
+func TestMarginsBoundLineAndCharEdits(t *testing.T) {
+	tests := []struct {
+		name string
+		seq  string
+		want []string
+	}{
+		{"IL", "\x1b[2;4H\x1b[L", []string{"0123456789", "01    6789", "0123456789", "0123456789"}},
+		{"DL", "\x1b[3;4H\x1b[M", []string{"0123456789", "0123456789", "0123456789", "01    6789"}},
+		{"ICH", "\x1b[1;4H\x1b[2@", []string{"012  36789", "0123456789", "0123456789", "0123456789"}},
+		{"DCH", "\x1b[1;4H\x1b[2P", []string{"0125  6789", "0123456789", "0123456789", "0123456789"}},
+		{"ICH outside margins", "\x1b[1;9H\x1b[2@", []string{"0123456789", "0123456789", "0123456789", "0123456789"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			vt := columnsTerm(t)
+			vt.Write([]byte("\x1b[?69h\x1b[3;6s" + tt.seq))
+			for y, line := range tt.want {
+				if got := screenLine(vt, y); got != line {
+					t.Fatalf("row %d = %q, want %q", y, got, line)
+				}
+			}
+		})
+	}
+}
+
+func TestInsertDeleteColumns(t *testing.T) {
+	tests := []struct {
+		name string
+		seq  string
+		want string
+	}{
+		{"DECIC full width", "\x1b[1;3H\x1b[2'}", "01  234567"},
+		{"DECDC full width", "\x1b[1;3H\x1b[2'~", "01456789"},
+		{"DECIC within margins", "\x1b[?69h\x1b[3;6s\x1b[1;4H\x1b['}", "012 346789"},
+		{"DECDC within margins", "\x1b[?69h\x1b[3;6s\x1b[1;4H\x1b['~", "01245 6789"},
+		{"SL", "\x1b[?69h\x1b[3;6s\x1b[ @", "01345 6789"},
+		{"SR", "\x1b[?69h\x1b[3;6s\x1b[2 A", "01  236789"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			vt := columnsTerm(t)
+			vt.Write([]byte(tt.seq))
+			for y := 0; y < vt.Height; y++ {
+				if got := screenLine(vt, y); got != tt.want {
+					t.Fatalf("row %d = %q, want %q", y, got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestOriginModeOffsetsByLeftMargin(t *testing.T) {
+	vt := New(10, 4)
+	vt.Write([]byte("\x1b[?69h\x1b[3;6s\x1b[?6h\x1b[1;2H"))
+	if vt.CursorX != 3 {
+		t.Fatalf("CUP col 2 under DECOM = %d, want 3", vt.CursorX)
+	}
+	vt.Write([]byte("\x1b[1;99H"))
+	if vt.CursorX != 5 {
+		t.Fatalf("CUP past right margin = %d, want 5", vt.CursorX)
+	}
+	vt.Write([]byte("\x1b[?69l\x1b[?6l"))
+	if _, _, ok := vt.partialMargins(); ok {
+		t.Fatal("resetting DECLRMM kept the margins")
+	}
+}
@@ -0,0 +1,95 @@
+package vterm
+
+import "strings"
+
+// Output marks locate the latest command's output for "copy last output".
+// Shells that emit OSC 133 semantic prompts mark where output starts (C) and
+// where the next prompt starts (A); otherwise the line the user last
+// submitted input on stands in for the command line. Marks are absolute
+// lines (0 = first scrollback line) and shift with scrollback trims, like
+// the selection.
+type outputMarks struct {
+	afterInput int // line after the one input was last submitted on; -1 when unset
+	output     int // line of the latest OSC 133 C; -1 when unset
+	prompt     int // line of the latest OSC 133 A; -1 when unset
+}
+
+func unsetOutputMarks() outputMarks {
+	return outputMarks{afterInput: -1, output: -1, prompt: -1}
+}
+
+// cursorLine returns the cursor's absolute line, or -1 on an alternate
+// screen that keeps no scrollback, where lines don't persist.
+func (v *VTerm) cursorLine() int {
+	if !v.scrollbackEnabled() {
+		return -1
+	}
+	return len(v.Scrollback) + v.CursorY
+}
+
+// MarkInput records that the user submitted input on the cursor's line, so
+// the output that follows can be copied. Callers must provide external
+// synchronization.
+func (v *VTerm) MarkInput() {
+	if line := v.cursorLine(); line >= 0 {
+		v.marks.afterInput = line + 1
+	}
+}
+
+// markSemanticPrompt applies an OSC 133 mark.
+func (v *VTerm) markSemanticPrompt(kind string) {
+	kind, _, _ = strings.Cut(kind, ";")
+	switch kind {
+	case "A":
+		v.marks.prompt = v.cursorLine()
+	case "C":
+		v.marks.output = v.cursorLine()
+	}
+}
+
+// LastOutput returns the text printed since the latest command started, up
+// to the prompt that followed it or the cursor's line. ok is false when no
+// input or output has been marked, or on an alternate screen. Callers must provide external
+// synchronization.
+func (v *VTerm) LastOutput() (text string, ok bool) {
+	m := v.marks
+	start := max(m.afterInput, m.output)
+	if start < 0 {
+		return "", false
+	}
+	cursor := v.cursorLine()
+	if cursor < 0 {
+		return "", false
+	}
+	var end int
+	switch {
+	case m.prompt >= start:
+		end = m.prompt - 1
+	case cursor > start:
+		// The cursor's line holds the next prompt or input.
+		end = cursor - 1
+	default:
+		end = cursor
+	}
+	if end < start {
+		return "", true
+	}
+	return strings.TrimRight(v.GetTextRange(0, start, v.Width-1, end), "\n"), true
+}
+
+// shiftMarks keeps the marks on their lines when delta lines are added to
+// (delta > 0) or trimmed from (delta < 0) the start of scrollback. Output
+// whose start was trimmed now starts at line 0; a trimmed prompt is dropped.
+func (v *VTerm) shiftMarks(delta int) {
+	for _, line := range []*int{&v.marks.afterInput, &v.marks.output} {
+		if *line >= 0 {
+			*line = max(*line+delta, 0)
+		}
+	}
+	if v.marks.prompt >= 0 {
+		v.marks.prompt += delta
+		if v.marks.prompt < 0 {
+			v.marks.prompt = -1
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package vterm
+
+import "testing"
+
+func TestLastOutputSinceInput(t *testing.T) {
+	t.Parallel()
+
+	v := New(40, 5)
+	if _, ok := v.LastOutput(); ok {
+		t.Fatal("expected no output before any input")
+	}
+	v.Write([]byte("$ old\r\nstale\r\n$ ls"))
+	v.MarkInput()
+	// Enough output to push the command line into scrollback.
+	v.Write([]byte("\r\na\r\nb\r\nc\r\nd\r\ne\r\n$ "))
+
+	got, ok := v.LastOutput()
+	if !ok || got != "a\nb\nc\nd\ne" {
+		t.Fatalf("LastOutput() = %q, %v; want the five output lines", got, ok)
+	}
+}
+
+func TestLastOutputSemanticPrompts(t *testing.T) {
+	t.Parallel()
+
+	v := New(40, 10)
+	v.Write([]byte("\x1b]133;A\x07$ make\r\n\x1b]133;C\x07built\r\nok\r\n"))
+	v.Write([]byte("\x1b]133;D;0\x07\x1b]133;A\x07$ "))
+	got, ok := v.LastOutput()
+	if !ok || got != "built\nok" {
+		t.Fatalf("LastOutput() = %q, %v; want the output between the marks", got, ok)
+	}
+
+	// A command that prints nothing has no output to copy.
+	v.Write([]byte("true\r\n\x1b]133;C\x07\x1b]133;A\x07$ "))
+	if got, ok := v.LastOutput(); !ok || got != "" {
+		t.Fatalf("LastOutput() = %q, %v; want empty output", got, ok)
+	}
+
+	v.ClearScrollback()
+	v.Write([]byte("\x1b[2J"))
+	if _, ok := v.LastOutput(); ok {
+		t.Fatal("clearing scrollback should drop the marks")
+	}
+}
+
+func TestLastOutputMarksFollowTrim(t *testing.T) {
+	t.Parallel()
+
+	v := New(20, 2)
+	v.Write([]byte("$ cmd"))
+	v.MarkInput()
+	for i := 0; i < MaxScrollback+5; i++ {
+		v.Write([]byte("\r\nx"))
+	}
+	if v.marks.afterInput != 0 {
+		t.Fatalf("afterInput = %d, want 0 once its line is trimmed", v.marks.afterInput)
+	}
+	v.Write([]byte("\r\n$ "))
+	got, ok := v.LastOutput()
+	if !ok || len(got) == 0 || got[len(got)-1] != 'x' {
+		t.Fatalf("LastOutput() = %.20q..., %v; want the output still in scrollback", got, ok)
+	}
+}
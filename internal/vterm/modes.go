@@ -11,8 +11,12 @@ func (p *Parser) executeDSR() {
 	case 5: // Status report - respond "OK"
 		p.vt.respond([]byte("\x1b[0n"))
 	case 6: // Cursor position report
-		// Response: ESC [ row ; col R (1-indexed)
+		// Response: ESC [ row ; col R (1-indexed, relative to the scroll
+		// region under origin mode)
 		row := p.vt.CursorY + 1
+		if p.vt.OriginMode {
+			row -= p.vt.ScrollTop
+		}
 		col := p.vt.CursorX + 1
 		response := fmt.Sprintf("\x1b[%d;%dR", row, col)
 		p.vt.respond([]byte(response))
@@ -28,13 +32,9 @@ func (p *Parser) executeMode(set bool) {
 		switch param {
 		case 6: // DECOM - origin mode
 			p.vt.OriginMode = set
-			p.vt.CursorX = 0
-			if set {
-				p.vt.CursorY = p.vt.ScrollTop
-			} else {
-				p.vt.CursorY = 0
-			}
-			p.vt.clampCursor()
+			p.vt.setCursorPos(1, 1)
+		case 69: // DECLRMM - left/right margin mode
+			p.vt.setLRMarginMode(set)
 		case 1: // DECCKM - cursor keys mode
 			// Ignore
 		case 7: // DECAWM - auto-wrap mode
@@ -89,6 +89,18 @@ func (p *Parser) executeDECRQM() {
 	for _, param := range p.params {
 		status := 0
 		switch param {
+		case 6:
+			if p.vt.OriginMode {
+				status = 1
+			} else {
+				status = 2
+			}
+		case 69:
+			if p.vt.lrMarginMode {
+				status = 1
+			} else {
+				status = 2
+			}
 		case 2026:
 			if p.vt.syncActive {
 				status = 1
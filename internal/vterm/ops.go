@@ -64,8 +64,15 @@ func (v *VTerm) putChar(r rune) {
 		return // Don't advance cursor for combining chars
 	}
 
+	// Inside left/right margins, text wraps at the right margin back to the
+	// left one.
+	wrapAt, wrapTo := v.Width, 0
+	if left, right, ok := v.partialMargins(); ok && v.CursorX >= left && v.CursorX <= right {
+		wrapAt, wrapTo = right, left
+	}
+
 	// Wide characters: if at last column, wrap first to avoid splitting
-	if width == 2 && v.CursorX == v.Width-1 {
+	if width == 2 && v.CursorX == wrapAt-1 {
 		// Put a space in the last column and wrap
 		if v.CursorY >= 0 && v.CursorY < len(v.Screen) {
 			v.Screen[v.CursorY][v.CursorX] = Cell{
@@ -75,13 +82,15 @@ func (v *VTerm) putChar(r rune) {
 			}
 			v.markDirtyLine(v.CursorY)
 		}
-		v.CursorX = 0
+		v.markSoftWrap(wrapAt)
+		v.CursorX = wrapTo
 		v.advanceLineFeed()
 	}
 
 	// Normal auto-wrap check
-	if v.CursorX >= v.Width {
-		v.CursorX = 0
+	if v.CursorX >= wrapAt {
+		v.markSoftWrap(wrapAt)
+		v.CursorX = wrapTo
 		v.advanceLineFeed()
 	}
 
@@ -155,10 +164,15 @@ func (v *VTerm) newline() {
 	v.bumpVersionIfCursorMoved(prevX, prevY)
 }
 
-// carriageReturn moves cursor to beginning of line
+// carriageReturn moves cursor to beginning of line, or to the left margin
+// when the cursor is at or right of it.
 func (v *VTerm) carriageReturn() {
 	prevX, prevY := v.CursorX, v.CursorY
-	v.CursorX = 0
+	if left, _ := v.horizontalMargins(); v.CursorX >= left {
+		v.CursorX = left
+	} else {
+		v.CursorX = 0
+	}
 	v.bumpVersionIfCursorMoved(prevX, prevY)
 }
 
@@ -239,6 +253,7 @@ func (v *VTerm) eraseDisplay(mode int) {
 			} else {
 				v.Scrollback = v.Scrollback[:0]
 				v.invalidateAltScreenCapture()
+				v.marks = unsetOutputMarks()
 			}
 		}
 		if mode != 2 {
@@ -0,0 +1,145 @@
+package vterm
+
+import "testing"
+
+// Conformance cases modelled on vttest's cursor-movement and scrolling-region
+// screens: DECSTBM margins, DECOM-relative addressing, and IL/DL confined to
+// the region.
+
+func originTerm(t *testing.T, setup string) *VTerm {
+	t.Helper()
+	vt := New(10, 8)
+	vt.Write([]byte(setup))
+	return vt
+}
+
+func TestOriginModeAddressesRelativeToRegion(t *testing.T) {
+	vt := originTerm(t, "\x1b[3;6r\x1b[?6h")
+	if vt.CursorX != 0 || vt.CursorY != 2 {
+		t.Fatalf("DECOM home = (%d,%d), want (0,2)", vt.CursorX, vt.CursorY)
+	}
+	vt.Write([]byte("\x1b[2;4H"))
+	if vt.CursorX != 3 || vt.CursorY != 3 {
+		t.Fatalf("CUP 2;4 = (%d,%d), want (3,3)", vt.CursorX, vt.CursorY)
+	}
+	vt.Write([]byte("\x1b[99;1H"))
+	if vt.CursorY != 5 {
+		t.Fatalf("CUP past region bottom: row %d, want 5", vt.CursorY)
+	}
+	vt.Write([]byte("\x1b[1d"))
+	if vt.CursorY != 2 {
+		t.Fatalf("VPA 1: row %d, want 2", vt.CursorY)
+	}
+	vt.Write([]byte("\x1b[?6l"))
+	if vt.CursorX != 0 || vt.CursorY != 0 {
+		t.Fatalf("DECOM reset home = (%d,%d), want (0,0)", vt.CursorX, vt.CursorY)
+	}
+}
+
+func TestOriginModeCursorReportIsRegionRelative(t *testing.T) {
+	vt := originTerm(t, "\x1b[3;6r\x1b[?6h\x1b[2;5H")
+	var got string
+	vt.SetResponseWriter(func(b []byte) { got = string(b) })
+	vt.Write([]byte("\x1b[6n"))
+	if got != "\x1b[2;5R" {
+		t.Fatalf("CPR = %q, want %q", got, "\x1b[2;5R")
+	}
+	vt.Write([]byte("\x1b[?6$p"))
+	if got != "\x1b[?6;1$y" {
+		t.Fatalf("DECRQM = %q, want %q", got, "\x1b[?6;1$y")
+	}
+}
+
+func TestCursorUpDownStopAtMargins(t *testing.T) {
+	vt := originTerm(t, "\x1b[3;6r\x1b[4;1H\x1b[10A")
+	if vt.CursorY != 2 {
+		t.Fatalf("CUU inside region: row %d, want top margin 2", vt.CursorY)
+	}
+	vt.Write([]byte("\x1b[10B"))
+	if vt.CursorY != 5 {
+		t.Fatalf("CUD inside region: row %d, want bottom margin 5", vt.CursorY)
+	}
+	// Outside the region the margins do not apply.
+	vt.Write([]byte("\x1b[2;1H\x1b[10A"))
+	if vt.CursorY != 0 {
+		t.Fatalf("CUU above region: row %d, want 0", vt.CursorY)
+	}
+	vt.Write([]byte("\x1b[8;1H\x1b[1A\x1b[10B"))
+	if vt.CursorY != 7 {
+		t.Fatalf("CUD below region: row %d, want 7", vt.CursorY)
+	}
+}
+
+func TestDECSCSavesOriginMode(t *testing.T) {
+	vt := originTerm(t, "\x1b[3;6r\x1b[?6h\x1b[2;2H\x1b7\x1b[?6l\x1b[8;8H\x1b8")
+	if !vt.OriginMode {
+		t.Fatal("DECRC did not restore origin mode")
+	}
+	if vt.CursorX != 1 || vt.CursorY != 3 {
+		t.Fatalf("DECRC cursor = (%d,%d), want (1,3)", vt.CursorX, vt.CursorY)
+	}
+	vt.Write([]byte("\x1b[1;1H"))
+	if vt.CursorY != 2 {
+		t.Fatalf("restored DECOM addressing: row %d, want 2", vt.CursorY)
+	}
+}
+
+func fillRows(vt *VTerm) {
+	for y := 0; y < vt.Height; y++ {
+		vt.Write([]byte("\x1b[" + string(rune('1'+y)) + ";1H" + string(rune('A'+y))))
+	}
+}
+
+func rowsText(vt *VTerm) string {
+	s := ""
+	for y := 0; y < vt.Height; y++ {
+		line := screenLine(vt, y)
+		if line == "" {
+			line = "."
+		}
+		s += line
+	}
+	return s
+}
+
+func TestInsertDeleteLinesWithinMargins(t *testing.T) {
+	tests := []struct {
+		name string
+		seq  string
+		want string
+	}{
+		{"IL at region top", "\x1b[3;6r\x1b[3;4H\x1b[2L", "AB..CDGH"},
+		{"IL clamps to region", "\x1b[3;6r\x1b[5;1H\x1b[9L", "ABCD..GH"},
+		{"DL at region top", "\x1b[3;6r\x1b[3;1H\x1b[1M", "ABDEF.GH"},
+		{"DL clamps to region", "\x1b[3;6r\x1b[4;1H\x1b[9M", "ABC...GH"},
+		{"IL outside region ignored", "\x1b[3;6r\x1b[7;1H\x1b[1L", "ABCDEFGH"},
+		{"DL outside region ignored", "\x1b[3;6r\x1b[1;1H\x1b[1M", "ABCDEFGH"},
+		{"IL under origin mode", "\x1b[3;6r\x1b[?6h\x1b[2;1H\x1b[1L", "ABC.DEGH"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			vt := New(10, 8)
+			fillRows(vt)
+			vt.Write([]byte(tt.seq))
+			if got := rowsText(vt); got != tt.want {
+				t.Fatalf("rows = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestInsertLinesHomesColumn(t *testing.T) {
+	vt := originTerm(t, "\x1b[3;5H\x1b[L")
+	if vt.CursorX != 0 || vt.CursorY != 2 {
+		t.Fatalf("IL cursor = (%d,%d), want (0,2)", vt.CursorX, vt.CursorY)
+	}
+}
+
+func TestLineFeedScrollsOnlyRegion(t *testing.T) {
+	vt := New(10, 8)
+	fillRows(vt)
+	vt.Write([]byte("\x1b[3;6r\x1b[?6h\x1b[4;1H\nx"))
+	if got := rowsText(vt); got != "ABDEFxGH" {
+		t.Fatalf("rows = %q, want %q", got, "ABDEFxGH")
+	}
+}
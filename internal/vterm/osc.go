@@ -31,6 +31,8 @@ func (p *Parser) dispatchOSC() {
 			return
 		}
 		p.vt.setOSCWorkingDir(rest)
+	case "133": // semantic prompt: A prompt start, C output start
+		p.vt.markSemanticPrompt(rest)
 	case "52": // clipboard: <selection>;<base64-or-?>
 		_, data, ok := strings.Cut(rest, ";")
 		if !ok || data == "?" {
@@ -32,6 +32,8 @@ type Parser struct {
 	paramBuf        strings.Builder
 	intermediate    byte
 	csiIntermediate byte
+	// charsetSlot is the G set (0 or 1) an ESC ( / ESC ) designates.
+	charsetSlot int
 
 	// OSC sequence building
 	oscBuf strings.Builder
@@ -259,7 +261,8 @@ func (p *Parser) parseByte(b byte) {
 	case stateDCSEscape:
 		p.parseDCSEscape(b)
 	case stateCharset:
-		// Ignore the charset designation byte (e.g., ESC ( B).
+		// The designation byte, e.g. '0' in ESC ( 0.
+		p.vt.designateCharset(p.charsetSlot, b)
 		p.state = stateGround
 	}
 }
@@ -304,10 +307,12 @@ func (p *Parser) parseGround(b byte) {
 		p.vt.backspace()
 	case b == 0x07: // Bell
 		// Ignore
-	case b == 0x0e, b == 0x0f: // SI/SO (charset switching)
-		// Ignore
+	case b == 0x0e: // SO: invoke G1
+		p.vt.shiftCharset(1)
+	case b == 0x0f: // SI: invoke G0
+		p.vt.shiftCharset(0)
 	case b >= 0x20 && b < 0x7f: // Printable ASCII
-		p.vt.putChar(rune(b))
+		p.vt.putChar(p.vt.translateCharset(rune(b)))
 	case b >= 0xC0 && b <= 0xDF: // 2-byte UTF-8 start
 		p.utf8Buf[0] = b
 		p.utf8Len = 2
@@ -350,7 +355,11 @@ func (p *Parser) parseEscape(b byte) {
 		p.oscBuf.Reset()
 	case 'P': // DCS
 		p.state = stateDCS
-	case '(', ')': // Charset designation
+	case '(', ')': // Charset designation (G0 / G1)
+		p.charsetSlot = 0
+		if b == ')' {
+			p.charsetSlot = 1
+		}
 		p.state = stateCharset
 	case '7': // DECSC - save cursor
 		p.vt.saveCursor()
@@ -373,12 +382,7 @@ func (p *Parser) parseEscape(b byte) {
 		p.vt.newline()
 		p.state = stateGround
 	case 'c': // RIS - reset
-		p.vt.CurrentStyle = Style{}
-		p.vt.CursorX = 0
-		p.vt.CursorY = 0
-		p.vt.mouseTrackingMode = 0
-		p.vt.mouseSGRMode = false
-		p.vt.preserveScrollbackOnNextClear3 = false
+		p.vt.Reset()
 		p.state = stateGround
 	case '=', '>': // DECKPAM/DECKPNM (keypad modes)
 		p.state = stateGround
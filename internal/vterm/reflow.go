@@ -0,0 +1,199 @@
+package vterm
+
+// Reflow on resize. Rows that auto-wrapped carry a Wrapped flag on their last
+// cell, so scrollback plus the main screen can be read back as logical lines
+// and rewrapped at a new width the way modern terminals do, instead of being
+// truncated or padded. Hard line breaks (CR/LF, cursor addressing) never set
+// the flag, so full-screen layouts and tmux pane captures keep their rows;
+// the session-restore reconciliation relies on that.
+//
+// Reflow is skipped while the alternate screen is up, while synchronized
+// output has frozen the viewport, and while an alt-screen frame is reserved in
+// scrollback: each of those tracks scrollback positions that a rewrap would
+// invalidate. Those cases keep the previous truncate/pad behavior.
+
+// markSoftWrap flags the cursor row as continuing onto the next row. Only
+// full-width wraps count; wraps at a right margin stay inside the row.
+func (v *VTerm) markSoftWrap(wrapAt int) {
+	if wrapAt != v.Width || v.CursorY < 0 || v.CursorY >= len(v.Screen) {
+		return
+	}
+	row := v.Screen[v.CursorY]
+	if x := v.Width - 1; x >= 0 && x < len(row) {
+		row[x].Wrapped = true
+	}
+}
+
+// rowWrapped reports whether row, laid out at width, continues onto the next
+// row.
+func rowWrapped(row []Cell, width int) bool {
+	x := min(width, len(row)) - 1
+	return x >= 0 && row[x].Wrapped
+}
+
+// IsWrappedLine reports whether the absolute line (0 = oldest scrollback row)
+// soft-wraps into the following line, so copy and search can treat the pair as
+// one logical line.
+func (v *VTerm) IsWrappedLine(line int) bool {
+	return rowWrapped(v.LineCells(line), v.Width)
+}
+
+func (v *VTerm) canReflow() bool {
+	return !v.AltScreen && !v.syncActive && !v.altCapture.tracked &&
+		len(v.altScreenRestorePending) == 0
+}
+
+// reflowLine is one logical line collected for rewrapping. A line that
+// never wrapped keeps its original row (row != nil) so it retains any hidden
+// width, exactly as a resize without reflow would; only soft-wrapped lines
+// are rebuilt from cells.
+type reflowLine struct {
+	cells []Cell
+	row   []Cell
+}
+
+func hasSoftWrap(rows [][]Cell, width int) bool {
+	for _, row := range rows {
+		if rowWrapped(row, width) {
+			return true
+		}
+	}
+	return false
+}
+
+// reflow rewraps soft-wrapped lines in scrollback and the main screen from the
+// current width to width, keeping the screen at its current height and the
+// cursor on the same character. It reports whether anything was rewrapped.
+func (v *VTerm) reflow(width int) bool {
+	oldWidth := v.Width
+	if width == oldWidth || width < 1 || oldWidth < 1 || !v.canReflow() {
+		return false
+	}
+	height := len(v.Screen)
+	if height == 0 || (!hasSoftWrap(v.Scrollback, oldWidth) && !hasSoftWrap(v.Screen, oldWidth)) {
+		return false
+	}
+
+	cursorAbs := len(v.Scrollback) + v.CursorY
+	cursorLine, cursorOffset := -1, 0
+	var lines []reflowLine
+	var current []Cell
+	abs := 0
+	appendRows := func(rows [][]Cell) {
+		for _, row := range rows {
+			if abs == cursorAbs {
+				cursorLine = len(lines)
+				cursorOffset = len(current) + v.CursorX
+			}
+			switch {
+			case rowWrapped(row, oldWidth):
+				current = append(current, row[:min(oldWidth, len(row))]...)
+				current[len(current)-1].Wrapped = false
+			case current == nil:
+				lines = append(lines, reflowLine{row: row})
+			default:
+				current = append(current, trimBlankTail(row)...)
+				lines = append(lines, reflowLine{cells: current})
+				current = nil
+			}
+			abs++
+		}
+	}
+	appendRows(v.Scrollback)
+	appendRows(v.Screen)
+	if current != nil {
+		lines = append(lines, reflowLine{cells: current})
+	}
+
+	var rows [][]Cell
+	cursorRow, cursorCol := -1, 0
+	for i, line := range lines {
+		if i == cursorLine {
+			cursorRow, cursorCol = len(rows), cursorOffset
+			if line.row == nil {
+				// The cursor may sit past the end of its line's text.
+				cursorRow += cursorCol / width
+				cursorCol %= width
+			}
+		}
+		if line.row != nil {
+			rows = append(rows, line.row)
+		} else {
+			rows = wrapLine(rows, line.cells, width)
+		}
+	}
+	for cursorRow >= len(rows) {
+		rows = append(rows, MakeBlankLine(width))
+	}
+
+	// The screen shows the last height rows, but never scrolls the cursor
+	// off the top: rows below it are dropped instead.
+	end := len(rows)
+	if cursorRow >= 0 && cursorRow < end-height {
+		end = max(cursorRow+1, min(height, len(rows)))
+	}
+	start := max(0, end-height)
+	screen := rows[start:end:end]
+	for len(screen) < height {
+		screen = append(screen, MakeBlankLine(width))
+	}
+
+	v.ClearSelection()
+	v.Scrollback = rows[:start:start]
+	v.Screen = screen
+	if cursorRow >= 0 {
+		v.CursorY = cursorRow - start
+		v.CursorX = cursorCol
+	}
+	v.invalidateAltScreenCapture()
+	v.trimScrollback()
+	return true
+}
+
+// trimBlankTail drops trailing default blank cells from row so padding does
+// not become part of a logical line.
+func trimBlankTail(row []Cell) []Cell {
+	n := len(row)
+	for n > 0 {
+		c := row[n-1]
+		if c.Rune != ' ' || c.Width != 1 || c.GraphemeCluster != "" || c.Style != (Style{}) {
+			break
+		}
+		n--
+	}
+	return row[:n]
+}
+
+// wrapLine appends line laid out at width to rows, flagging every row but
+// the last as wrapped. A wide character that would straddle the edge moves to
+// the next row, as it does when printed.
+func wrapLine(rows [][]Cell, line []Cell, width int) [][]Cell {
+	row := MakeBlankLine(width)
+	x := 0
+	for i := 0; i < len(line); i++ {
+		c := line[i]
+		if c.Width == 0 {
+			continue // written with its base cell
+		}
+		w := 1
+		if c.Width == 2 && width > 1 {
+			w = 2
+		}
+		if x+w > width {
+			row[width-1].Wrapped = true
+			rows = append(rows, row)
+			row = MakeBlankLine(width)
+			x = 0
+		}
+		c.Wrapped = false
+		if w == 1 && c.Width == 2 {
+			c = DefaultCell()
+		}
+		row[x] = c
+		if w == 2 {
+			row[x+1] = Cell{Style: c.Style, Width: 0}
+		}
+		x += w
+	}
+	return append(rows, row)
+}
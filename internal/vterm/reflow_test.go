@@ -0,0 +1,106 @@
+package vterm
+
+import "testing"
+
+func TestReflowNarrowRewrapsSoftWrappedText(t *testing.T) {
+	vt := New(10, 4)
+	vt.Write([]byte("abcdefghijkl\r\nxy"))
+	vt.Resize(6, 4)
+
+	want := []string{"abcdef", "ghijkl", "xy", ""}
+	for y, line := range want {
+		if got := screenLine(vt, y); got != line {
+			t.Fatalf("row %d = %q, want %q", y, got, line)
+		}
+	}
+	if vt.CursorX != 2 || vt.CursorY != 2 {
+		t.Fatalf("cursor = (%d,%d), want (2,2)", vt.CursorX, vt.CursorY)
+	}
+}
+
+func TestReflowWidenJoinsWrappedRows(t *testing.T) {
+	vt := New(6, 4)
+	vt.Write([]byte("abcdefghijkl\r\nxy"))
+	vt.Resize(12, 4)
+
+	want := []string{"abcdefghijkl", "xy"}
+	for y, line := range want {
+		if got := screenLine(vt, y); got != line {
+			t.Fatalf("row %d = %q, want %q", y, got, line)
+		}
+	}
+	if vt.CursorX != 2 || vt.CursorY != 1 {
+		t.Fatalf("cursor = (%d,%d), want (2,1)", vt.CursorX, vt.CursorY)
+	}
+}
+
+func TestReflowKeepsHardLineBreaks(t *testing.T) {
+	vt := New(6, 3)
+	vt.Write([]byte("abc\r\ndef"))
+	vt.Resize(12, 3)
+	if got := screenLine(vt, 0); got != "abc" {
+		t.Fatalf("row 0 = %q, want %q", got, "abc")
+	}
+	if got := screenLine(vt, 1); got != "def" {
+		t.Fatalf("row 1 = %q, want %q", got, "def")
+	}
+}
+
+func TestReflowMovesOverflowIntoScrollback(t *testing.T) {
+	vt := New(8, 2)
+	vt.Write([]byte("12345678abc"))
+	vt.Resize(4, 2)
+
+	if len(vt.Scrollback) != 1 || screenLine(&VTerm{Screen: vt.Scrollback}, 0) != "1234" {
+		t.Fatalf("scrollback = %d rows, want [1234]", len(vt.Scrollback))
+	}
+	if got := screenLine(vt, 0); got != "5678" {
+		t.Fatalf("row 0 = %q, want %q", got, "5678")
+	}
+	if got := screenLine(vt, 1); got != "abc" {
+		t.Fatalf("row 1 = %q, want %q", got, "abc")
+	}
+
+	// Widening again pulls the wrapped history back into one line.
+	vt.Resize(12, 2)
+	if len(vt.Scrollback) != 0 {
+		t.Fatalf("scrollback = %d rows after widening, want 0", len(vt.Scrollback))
+	}
+	if got := screenLine(vt, 0); got != "12345678abc" {
+		t.Fatalf("row 0 = %q, want %q", got, "12345678abc")
+	}
+}
+
+func TestReflowDoesNotSplitWideCharacters(t *testing.T) {
+	vt := New(6, 3)
+	vt.Write([]byte("ab日本語"))
+	vt.Resize(5, 3)
+	if got := screenLine(vt, 0); got != "ab日" {
+		t.Fatalf("row 0 = %q, want %q", got, "ab日")
+	}
+	if got := screenLine(vt, 1); got != "本語" {
+		t.Fatalf("row 1 = %q, want %q", got, "本語")
+	}
+}
+
+func TestReflowSkippedOnAltScreen(t *testing.T) {
+	vt := New(10, 3)
+	vt.Write([]byte("\x1b[?1049habcdefghijkl"))
+	vt.Resize(6, 3)
+	// Rows keep their layout (and hidden width) rather than rewrapping.
+	if got := screenLine(vt, 1); got != "kl" {
+		t.Fatalf("row 1 = %q, want %q", got, "kl")
+	}
+}
+
+func TestCopyJoinsSoftWrappedRows(t *testing.T) {
+	vt := New(5, 3)
+	vt.Write([]byte("hello world\r\nnext"))
+	if !vt.IsWrappedLine(0) || vt.IsWrappedLine(2) {
+		t.Fatal("wrap flags not tracked")
+	}
+	got := vt.GetTextRange(0, 0, 4, 3)
+	if want := "hello world\nnext"; got != want {
+		t.Fatalf("copied %q, want %q", got, want)
+	}
+}
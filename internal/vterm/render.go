@@ -1,19 +1,40 @@
 package vterm
 
 import (
-	"strings"
+	"slices"
+	"unicode/utf8"
 )
 
-// Render returns the terminal content as a string with ANSI codes
+// Render returns the terminal content as a string with ANSI codes. The frame
+// is assembled in a reused buffer; only the returned string is allocated. Use
+// RenderTo or AppendLine to avoid that copy too.
 func (v *VTerm) Render() string {
+	v.renderScratch = v.appendRender(v.renderScratch[:0])
+	return string(v.renderScratch)
+}
+
+var (
+	newlineBytes = []byte("\n")
+	resetBytes   = []byte("\x1b[0m")
+)
+
+// appendRender appends the full frame Render returns to dst.
+func (v *VTerm) appendRender(dst []byte) []byte {
 	screen, scrollbackLen := v.RenderBuffers()
 	if v.ViewOffset > 0 {
-		return v.renderWithScrollbackFrom(screen, scrollbackLen)
+		return v.appendWithScrollbackFrom(dst, screen, scrollbackLen)
 	}
 	if v.syncActive {
-		return v.renderScreenFrom(screen)
+		return v.appendScreenFrom(dst, screen)
+	}
+	v.refreshRenderCache(screen)
+	for y := range screen {
+		dst = append(dst, v.renderCache[y]...)
+		if y < len(screen)-1 {
+			dst = append(dst, '\n')
+		}
 	}
-	return v.renderScreenCached(screen)
+	return append(dst, resetBytes...)
 }
 
 // RenderBuffers returns the current screen buffer and scrollback length.
@@ -22,6 +43,9 @@ func (v *VTerm) Render() string {
 // so a writer that died mid-frame cannot freeze the terminal forever.
 func (v *VTerm) RenderBuffers() ([][]Cell, int) {
 	v.maybeReleaseStaleSync()
+	if v.peekPrimary && v.altScreenBuf != nil {
+		return v.altScreenBuf, len(v.Scrollback)
+	}
 	if v.syncActive && v.syncScreen != nil {
 		scrollbackLen := v.syncScrollbackLen
 		if scrollbackLen > len(v.Scrollback) {
@@ -157,16 +181,14 @@ func (v *VTerm) VisibleScreenWithSelection() [][]Cell {
 		return lines
 	}
 
+	sel := v.ViewSelection()
 	for y := 0; y < len(lines); y++ {
 		row := lines[y]
 		for x := 0; x < len(row); x++ {
-			if v.IsInSelection(x, y) {
-				cell := row[x]
-				cell.Style.Reverse = !cell.Style.Reverse
-				row[x] = cell
+			if sel.ContainsCell(row[x], x, y) {
+				row[x].Style = HighlightStyle(row[x].Style, true, false)
 			}
 		}
-		lines[y] = row
 	}
 
 	return lines
@@ -174,101 +196,77 @@ func (v *VTerm) VisibleScreenWithSelection() [][]Cell {
 
 // renderScreenFrom renders the given screen buffer
 func (v *VTerm) renderScreenFrom(screen [][]Cell) string {
-	var buf strings.Builder
-	buf.Grow(v.Width * v.Height * 2) // Rough estimate
+	return string(v.appendScreenFrom(nil, screen))
+}
+
+// appendScreenFrom appends the given screen buffer, uncached, to dst.
+func (v *VTerm) appendScreenFrom(dst []byte, screen [][]Cell) []byte {
+	dst = slices.Grow(dst, v.Width*v.Height*2) // Rough estimate
 
 	for y, row := range screen {
-		buf.WriteString(v.renderRow(row, y))
+		dst = v.appendRow(dst, row, y)
 
 		if y < len(v.Screen)-1 {
-			buf.WriteString("\n")
+			dst = append(dst, '\n')
 		}
 	}
 
 	// Reset styles at end
-	buf.WriteString("\x1b[0m")
-
-	return buf.String()
+	return append(dst, resetBytes...)
 }
 
-func (v *VTerm) renderScreenCached(screen [][]Cell) string {
-	v.ensureRenderCache(len(screen))
-
-	// Invalidate cursor lines if cursor state changed
-	if v.ShowCursor != v.lastShowCursor || v.CursorHiddenForRender() != v.lastCursorHidden || v.CursorX != v.lastCursorX || v.CursorY != v.lastCursorY {
-		epoch := v.bumpRenderEpoch()
-		// Mark old cursor line dirty
-		if v.lastCursorY >= 0 && v.lastCursorY < len(v.renderLineEpoch) {
-			v.renderLineEpoch[v.lastCursorY] = epoch
-		}
-		// Mark new cursor line dirty
-		if v.CursorY >= 0 && v.CursorY < len(v.renderLineEpoch) {
-			v.renderLineEpoch[v.CursorY] = epoch
-		}
-		v.lastShowCursor = v.ShowCursor
-		v.lastCursorHidden = v.CursorHiddenForRender()
-		v.lastCursorX = v.CursorX
-		v.lastCursorY = v.CursorY
-	}
-
-	lines := make([]string, len(screen))
-	for y, row := range screen {
-		if v.lineDirty(y) || v.renderCache[y] == "" {
-			lines[y] = v.renderRow(row, y)
-			v.renderCache[y] = lines[y]
-		} else {
-			lines[y] = v.renderCache[y]
-		}
-	}
-	// The cached render pass consumed all outstanding dirtiness.
-	v.ClearDirty()
-
-	out := strings.Join(lines, "\n")
-	return out + "\x1b[0m"
+// renderRow renders a single row as a self-contained string.
+func (v *VTerm) renderRow(row []Cell, y int) string {
+	return string(v.appendRow(nil, row, y))
 }
 
-func (v *VTerm) renderRow(row []Cell, y int) string {
-	var buf strings.Builder
-	buf.Grow(v.Width * 2)
+// appendRow appends row y to dst. Each row starts with a reset so cached
+// lines are independent of each other.
+func (v *VTerm) appendRow(dst []byte, row []Cell, y int) []byte {
+	dst = slices.Grow(dst, v.Width*2)
 
 	// Reset per-line to make cached lines independent.
-	buf.WriteString("\x1b[0m")
+	dst = append(dst, resetBytes...)
 	var lastStyle Style
-	var lastReverse bool
+	sel := v.ViewSelection()
+	search := v.rowSearchHighlighter(y)
 
 	// Determine if cursor is on this row and should be shown
 	// Don't show cursor if terminal app hid it via DECTCEM
 	cursorOnRow := v.ShowCursor && !v.CursorHiddenForRender() && y == v.CursorY && v.ViewOffset == 0
 
+	// Rows with right-to-left text display in visual order (bidi.go); the
+	// cursor and cells follow it, selection is already in visual columns.
+	v.bidiScratch = appendVisualOrder(v.bidiScratch[:0], row)
+	order := v.bidiScratch
+	cursorX := v.CursorX
+	if cursorOnRow && len(order) > 0 {
+		cursorX = visualColumn(order, cursorX)
+	}
+
 	for x := 0; x < v.Width; x++ {
 		var cell Cell
-		if x < len(row) {
+		switch {
+		case x < len(order):
+			cell = row[order[x]]
+		case x < len(row):
 			cell = row[x]
-		} else {
+		default:
 			cell = DefaultCell()
 		}
-		// Check if this cell is in selection
-		inSel := v.IsInSelection(x, y)
-
-		// Check if cursor is at this position
-		isCursor := cursorOnRow && x == v.CursorX
-
-		// Apply style changes (toggle reverse for selection or cursor)
-		style := cell.Style
-		if inSel || isCursor {
-			style.Reverse = !style.Reverse
-		}
+		isCursor := cursorOnRow && x == cursorX
+		style := search.Style(cell.Style, x, y)
+		style = HighlightStyle(style, sel.ContainsCell(cell, x, y), isCursor)
 		style = suppressBlankUnderline(cell, style)
 
-		if style != lastStyle || inSel != lastReverse || isCursor {
+		if style != lastStyle || isCursor {
 			// Use delta encoding after the first style (which has reset)
 			if x == 0 {
-				buf.WriteString(style.ANSI())
+				dst = style.AppendANSI(dst)
 			} else {
-				buf.WriteString(lastStyle.DeltaANSI(style))
+				dst = lastStyle.AppendDeltaANSI(dst, style)
 			}
 			lastStyle = style
-			lastReverse = inSel
 		}
 
 		// Skip continuation cells (part of wide character)
@@ -276,24 +274,19 @@ func (v *VTerm) renderRow(row []Cell, y int) string {
 			continue
 		}
 
-		writeCellContent(&buf, cell)
+		dst = appendCellContent(dst, cell)
 	}
 
-	return buf.String()
+	return dst
 }
 
-// renderWithScrollbackFrom renders content from scrollback + screen
-func (v *VTerm) renderWithScrollbackFrom(screen [][]Cell, scrollbackLen int) string {
-	var buf strings.Builder
-	buf.Grow(v.Width * v.Height * 2)
-
-	// Calculate which lines to show
-	// ViewOffset = how many lines scrolled up into history
+// scrolledRow returns the scrollback-or-screen row shown at visible line i
+// while the view is scrolled into history, or nil past the end.
+func (v *VTerm) scrolledRow(screen [][]Cell, scrollbackLen, i int) []Cell {
 	if scrollbackLen > len(v.Scrollback) {
 		scrollbackLen = len(v.Scrollback)
 	}
 	screenLen := len(screen)
-
 	// Start position in the combined buffer (scrollback + screen)
 	// When ViewOffset = scrollbackLen, we show from the start of scrollback
 	// When ViewOffset = 0, we show the screen
@@ -301,20 +294,29 @@ func (v *VTerm) renderWithScrollbackFrom(screen [][]Cell, scrollbackLen int) str
 	if startLine < 0 {
 		startLine = 0
 	}
+	lineIdx := startLine + i
+	if lineIdx < scrollbackLen {
+		return v.Scrollback[lineIdx]
+	} else if lineIdx-scrollbackLen < screenLen {
+		return screen[lineIdx-scrollbackLen]
+	}
+	return nil
+}
+
+// appendWithScrollbackFrom appends content from scrollback + screen to dst.
+// Styles run continuously across lines (no per-line reset), matching what the
+// scrolled view has always emitted.
+func (v *VTerm) appendWithScrollbackFrom(dst []byte, screen [][]Cell, scrollbackLen int) []byte {
+	dst = slices.Grow(dst, v.Width*v.Height*2)
 
 	var lastStyle Style
-	var lastReverse bool
 	firstCell := true
+	sel := v.ViewSelection()
+	search := NewSearchHighlighter(v.AppendVisibleSearchMatches(nil))
 
 	for i := 0; i < v.Height; i++ {
-		lineIdx := startLine + i
-
-		var row []Cell
-		if lineIdx < scrollbackLen {
-			row = v.Scrollback[lineIdx]
-		} else if lineIdx-scrollbackLen < screenLen {
-			row = screen[lineIdx-scrollbackLen]
-		}
+		// ViewOffset = how many lines scrolled up into history
+		row := v.scrolledRow(screen, scrollbackLen, i)
 
 		// Render the row
 		for x := 0; x < v.Width; x++ {
@@ -325,20 +327,14 @@ func (v *VTerm) renderWithScrollbackFrom(screen [][]Cell, scrollbackLen int) str
 				cell = DefaultCell()
 			}
 
-			// Check if this cell is in selection (i is the visible Y coord)
-			inSel := v.IsInSelection(x, i)
-
-			// Apply style changes (toggle reverse for selection)
-			style := cell.Style
-			if inSel {
-				style.Reverse = !style.Reverse
-			}
+			// i is the visible Y coord.
+			style := search.Style(cell.Style, x, i)
+			style = HighlightStyle(style, sel.ContainsCell(cell, x, i), false)
 			style = suppressBlankUnderline(cell, style)
 
-			if firstCell || style != lastStyle || inSel != lastReverse {
-				buf.WriteString(style.ANSI())
+			if firstCell || style != lastStyle {
+				dst = style.AppendANSI(dst)
 				lastStyle = style
-				lastReverse = inSel
 				firstCell = false
 			}
 
@@ -347,30 +343,28 @@ func (v *VTerm) renderWithScrollbackFrom(screen [][]Cell, scrollbackLen int) str
 				continue
 			}
 
-			writeCellContent(&buf, cell)
+			dst = appendCellContent(dst, cell)
 		}
 
 		if i < v.Height-1 {
-			buf.WriteString("\n")
+			dst = append(dst, '\n')
 		}
 	}
 
-	buf.WriteString("\x1b[0m")
-	return buf.String()
+	return append(dst, resetBytes...)
 }
 
 func suppressBlankUnderline(cell Cell, style Style) Style {
 	return SuppressBlankUnderline(cell.Rune, style)
 }
 
-func writeCellContent(buf *strings.Builder, cell Cell) {
+func appendCellContent(dst []byte, cell Cell) []byte {
 	if cell.Rune == 0 {
-		buf.WriteRune(' ')
+		return append(dst, ' ')
 	} else if cell.GraphemeCluster != "" {
-		buf.WriteString(cell.GraphemeCluster)
-	} else {
-		buf.WriteRune(cell.Rune)
+		return append(dst, cell.GraphemeCluster...)
 	}
+	return utf8.AppendRune(dst, cell.Rune)
 }
 
 // SuppressBlankUnderline drops the underline attribute when rune r is blank
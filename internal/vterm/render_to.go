@@ -0,0 +1,68 @@
+package vterm
+
+import "io"
+
+// RenderTo writes the same bytes Render returns to w. On the live screen the
+// cached lines are written straight from the per-line cache, so an unchanged
+// frame costs no encoding and no frame-sized allocation. w must not retain the
+// slices it is given (the io.Writer contract).
+func (v *VTerm) RenderTo(w io.Writer) (int64, error) {
+	screen, scrollbackLen := v.RenderBuffers()
+	if v.ViewOffset > 0 || v.syncActive {
+		if v.ViewOffset > 0 {
+			v.renderScratch = v.appendWithScrollbackFrom(v.renderScratch[:0], screen, scrollbackLen)
+		} else {
+			v.renderScratch = v.appendScreenFrom(v.renderScratch[:0], screen)
+		}
+		n, err := w.Write(v.renderScratch)
+		return int64(n), err
+	}
+
+	v.refreshRenderCache(screen)
+	var total int64
+	for y := range screen {
+		n, err := w.Write(v.renderCache[y])
+		total += int64(n)
+		if err != nil {
+			return total, err
+		}
+		if y < len(screen)-1 {
+			n, err = w.Write(newlineBytes)
+			total += int64(n)
+			if err != nil {
+				return total, err
+			}
+		}
+	}
+	n, err := w.Write(resetBytes)
+	return total + int64(n), err
+}
+
+// AppendLine appends visible line y to dst and returns the extended slice.
+// Out-of-range lines append nothing. On the live screen the line is byte for
+// byte the one in Render, taken from the per-line cache and re-encoded only
+// when its content actually changed; dirtiness is left for the next full
+// render to clear. While scrolled back (ViewOffset > 0) it shows the same
+// scrollback row as Render, but encoded on its own, starting with a reset,
+// where Render's scrolled output carries styles over from the line before.
+func (v *VTerm) AppendLine(dst []byte, y int) []byte {
+	screen, scrollbackLen := v.RenderBuffers()
+	if y < 0 || y >= v.Height {
+		return dst
+	}
+	if v.liveRenderCacheActive() {
+		if y >= len(screen) {
+			return dst
+		}
+		v.ensureRenderCache(len(screen))
+		v.markCursorLinesDirty()
+		return append(dst, v.cachedLine(screen, y)...)
+	}
+	if v.ViewOffset > 0 {
+		return v.appendRow(dst, v.scrolledRow(screen, scrollbackLen, y), y)
+	}
+	if y >= len(screen) {
+		return dst
+	}
+	return v.appendRow(dst, screen[y], y)
+}
@@ -0,0 +1,104 @@
+package vterm
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestRenderToMatchesRender(t *testing.T) {
+	t.Parallel()
+
+	scrolled := writeRows(8, 2, "one", "two", "three", "four")
+	scrolled.ScrollView(2)
+
+	synced := writeRows(8, 2, "aa", "bb")
+	synced.Write([]byte("\x1b[?2026h\x1b[31mcc"))
+
+	styled := writeRows(8, 3, "\x1b[1;32mgreen\x1b[0m", "plain", "\x1b[7mrev")
+	styled.ShowCursor = true
+
+	for name, vt := range map[string]*VTerm{"live": styled, "scrolled": scrolled, "synced": synced} {
+		want := vt.Render()
+		var buf bytes.Buffer
+		n, err := vt.RenderTo(&buf)
+		if err != nil {
+			t.Fatalf("%s: RenderTo error: %v", name, err)
+		}
+		if buf.String() != want || n != int64(len(want)) {
+			t.Fatalf("%s: RenderTo = %q (n=%d), want %q", name, buf.String(), n, want)
+		}
+	}
+}
+
+func TestAppendLineMatchesRenderedLines(t *testing.T) {
+	t.Parallel()
+	vt := writeRows(6, 3, "\x1b[34mab", "cd", "ef")
+	lines := strings.Split(strings.TrimSuffix(vt.Render(), "\x1b[0m"), "\n")
+
+	var dst []byte
+	for y, want := range lines {
+		dst = vt.AppendLine(dst[:0], y)
+		if string(dst) != want {
+			t.Fatalf("AppendLine(%d) = %q, want %q", y, dst, want)
+		}
+	}
+	if got := vt.AppendLine([]byte("x"), vt.Height); string(got) != "x" {
+		t.Fatalf("out-of-range AppendLine = %q, want dst unchanged", got)
+	}
+}
+
+func TestAppendLineShowsScrolledRows(t *testing.T) {
+	t.Parallel()
+	vt := writeRows(8, 2, "\x1b[31mone", "two", "three", "four")
+	vt.ScrollView(2)
+	lines := strings.Split(stripANSI(vt.Render()), "\n")
+
+	var dst []byte
+	for y, want := range lines {
+		dst = vt.AppendLine(dst[:0], y)
+		if got := stripANSI(string(dst)); got != want {
+			t.Fatalf("scrolled AppendLine(%d) = %q, want %q", y, got, want)
+		}
+		if !bytes.HasPrefix(dst, resetBytes) {
+			t.Fatalf("scrolled AppendLine(%d) = %q, want it to start with a reset", y, dst)
+		}
+	}
+	if got := strings.TrimSpace(stripANSI(string(vt.AppendLine(nil, 0)))); got != "one" {
+		t.Fatalf("scrolled AppendLine(0) = %q, want the scrollback row", got)
+	}
+}
+
+func TestCachedLineSkipsUnchangedDirtyLine(t *testing.T) {
+	t.Parallel()
+	vt := writeRows(6, 2, "same", "other")
+	vt.Render()
+
+	// Plant a sentinel in the cache: if the dirty line is re-encoded the
+	// sentinel disappears, if the hash check skips it the sentinel survives.
+	vt.renderCache[0] = append(vt.renderCache[0][:0], "sentinel"...)
+	vt.markDirtyLine(0)
+	if out := vt.Render(); !strings.HasPrefix(out, "sentinel") {
+		t.Fatalf("unchanged dirty line was re-encoded: %q", out)
+	}
+
+	vt.Write([]byte("\x1b[1;1Hdiff"))
+	if out := vt.Render(); strings.HasPrefix(out, "sentinel") {
+		t.Fatal("changed line served from the stale cache")
+	}
+}
+
+func TestRenderToSteadyStateDoesNotAllocate(t *testing.T) {
+	vt := writeRows(40, 10, "\x1b[1mhello\x1b[0m", "\x1b[38;2;1;2;3mrgb", "plain")
+	vt.Render()
+	allocs := testing.AllocsPerRun(50, func() {
+		vt.markDirtyRange(0, vt.Height-1)
+		if _, err := vt.RenderTo(io.Discard); err != nil {
+			t.Fatal(err)
+		}
+	})
+	if allocs != 0 {
+		t.Fatalf("RenderTo allocated %.1f times per frame, want 0", allocs)
+	}
+}
@@ -0,0 +1,56 @@
+package vterm
+
+// ClearScrollback drops all scrollback history while keeping the visible
+// screen. Any open synchronized-output window is ended first so its frozen
+// scrollback length cannot go stale. Callers must provide external
+// synchronization.
+func (v *VTerm) ClearScrollback() {
+	v.setSynchronizedOutput(false)
+	v.Scrollback = v.Scrollback[:0]
+	v.invalidateAltScreenCapture()
+	v.preserveScrollbackOnNextClear3 = false
+	v.ViewOffset = 0
+	v.selActive = false
+	v.selRect = false
+	v.search.current = searchMatch{line: -1}
+	v.marks = unsetOutputMarks()
+	v.invalidateRenderCache()
+}
+
+// Reset performs a hard reset (RIS) of the emulator: the screen is blanked,
+// the alternate screen is dropped, and cursor, style, scroll region, mouse and
+// parser state return to their power-on values. Scrollback is kept; use
+// ClearScrollback to drop it as well. Callers must provide external
+// synchronization.
+func (v *VTerm) Reset() {
+	v.setSynchronizedOutput(false)
+	v.peekPrimary = false
+	v.search = searchState{}
+	v.AltScreen = false
+	v.altScreenBuf = nil
+	v.altCursorX, v.altCursorY = 0, 0
+	v.invalidateAltScreenCapture()
+	v.Screen = v.makeScreen(v.Width, v.Height)
+	v.CursorX, v.CursorY = 0, 0
+	v.CurrentStyle = Style{}
+	v.SavedCursorX, v.SavedCursorY = 0, 0
+	v.SavedStyle = Style{}
+	v.savedOriginMode = false
+	v.charsets = charsetState{}
+	v.savedCharsets = charsetState{}
+	v.ScrollTop = 0
+	v.ScrollBottom = v.Height
+	v.OriginMode = false
+	v.setLRMarginMode(false)
+	v.mouseTrackingMode = 0
+	v.mouseSGRMode = false
+	v.CursorHidden = false
+	v.preserveScrollbackOnNextClear3 = false
+	if v.parser != nil {
+		v.parser.Reset()
+	}
+	v.ViewOffset = 0
+	v.selActive = false
+	v.selRect = false
+	v.invalidateRenderCache()
+}
@@ -0,0 +1,113 @@
+package vterm
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestClearScrollbackKeepsScreen(t *testing.T) {
+	t.Parallel()
+
+	vt := New(20, 3)
+	for i := 0; i < 10; i++ {
+		vt.Write([]byte(fmt.Sprintf("line-%d\r\n", i)))
+	}
+	vt.Write([]byte("prompt"))
+	vt.ScrollView(2)
+	if len(vt.Scrollback) == 0 || vt.ViewOffset == 0 {
+		t.Fatalf("setup: scrollback=%d viewOffset=%d", len(vt.Scrollback), vt.ViewOffset)
+	}
+	before := vt.Version()
+
+	vt.ClearScrollback()
+
+	if len(vt.Scrollback) != 0 {
+		t.Fatalf("scrollback len = %d, want 0", len(vt.Scrollback))
+	}
+	if vt.ViewOffset != 0 {
+		t.Fatalf("ViewOffset = %d, want 0", vt.ViewOffset)
+	}
+	if got := lineText(vt.Screen[vt.CursorY]); got != "prompt" {
+		t.Fatalf("cursor line = %q, want the visible screen kept", got)
+	}
+	if vt.Version() == before {
+		t.Fatal("expected ClearScrollback to bump the render version")
+	}
+}
+
+func TestResetRestoresPowerOnState(t *testing.T) {
+	t.Parallel()
+
+	vt := New(20, 5)
+	vt.Write([]byte("history\r\n"))
+	// Enter the alt screen, set a scroll region, hide the cursor, enable
+	// mouse reporting and leave a dangling CSI in the parser.
+	vt.Write([]byte("\x1b[?1049h\x1b[2;4r\x1b[?25l\x1b[?1000h\x1b[?1006h\x1b[31mred\x1b[3"))
+
+	vt.Reset()
+
+	if vt.AltScreen {
+		t.Fatal("expected Reset to leave the alt screen")
+	}
+	if vt.ScrollTop != 0 || vt.ScrollBottom != vt.Height {
+		t.Fatalf("scroll region = %d..%d, want full screen", vt.ScrollTop, vt.ScrollBottom)
+	}
+	if vt.CursorX != 0 || vt.CursorY != 0 || vt.CursorHidden {
+		t.Fatalf("cursor = (%d,%d) hidden=%v, want visible at origin", vt.CursorX, vt.CursorY, vt.CursorHidden)
+	}
+	if vt.MouseReportingEnabled() || vt.MouseSGRMode() {
+		t.Fatal("expected Reset to disable mouse reporting")
+	}
+	if vt.CurrentStyle != (Style{}) {
+		t.Fatalf("style = %+v, want default", vt.CurrentStyle)
+	}
+	for y, row := range vt.Screen {
+		if got := strings.TrimSpace(lineText(row)); got != "" {
+			t.Fatalf("screen row %d = %q, want blank", y, got)
+		}
+	}
+
+	// The dangling CSI must not swallow the next output.
+	vt.Write([]byte("ok"))
+	if got := lineText(vt.Screen[0]); got != "ok" {
+		t.Fatalf("row 0 after reset = %q, want %q", got, "ok")
+	}
+}
+
+func TestRISRestoresPowerOnState(t *testing.T) {
+	t.Parallel()
+
+	vt := New(20, 5)
+	vt.Write([]byte("history\r\n"))
+	// Set a scroll region, DECOM, DECSLRM margins, a line-drawing G0, SGR
+	// and a saved cursor, then reset from the output stream.
+	vt.Write([]byte("\x1b[2;4r\x1b[?6h\x1b[?69h\x1b[3;10s\x1b(0\x1b[1;31m\x1b[3;5H\x1b7"))
+	vt.Write([]byte("\x1bc"))
+
+	if vt.ScrollTop != 0 || vt.ScrollBottom != vt.Height {
+		t.Fatalf("scroll region = %d..%d, want full screen", vt.ScrollTop, vt.ScrollBottom)
+	}
+	if vt.OriginMode || vt.lrMarginMode || vt.marginLeft != 0 || vt.marginRight != 0 {
+		t.Fatalf("origin=%v lrm=%v margins=%d..%d, want reset", vt.OriginMode, vt.lrMarginMode, vt.marginLeft, vt.marginRight)
+	}
+	if vt.charsets != (charsetState{}) || vt.savedCharsets != (charsetState{}) {
+		t.Fatal("expected RIS to restore the ASCII charsets")
+	}
+	if vt.CurrentStyle != (Style{}) || vt.SavedStyle != (Style{}) {
+		t.Fatalf("style = %+v, want default", vt.CurrentStyle)
+	}
+	if vt.CursorX != 0 || vt.CursorY != 0 {
+		t.Fatalf("cursor = (%d,%d), want origin", vt.CursorX, vt.CursorY)
+	}
+
+	// Tab stops are back at every eighth column, and text prints as ASCII
+	// in the default style.
+	vt.Write([]byte("\tq"))
+	if got := lineText(vt.Screen[0]); got != "        q" {
+		t.Fatalf("row 0 after RIS = %q, want a default tab stop and plain q", got)
+	}
+	if vt.Screen[0][8].Style != (Style{}) {
+		t.Fatalf("cell style = %+v, want default", vt.Screen[0][8].Style)
+	}
+}
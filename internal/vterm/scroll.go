@@ -13,6 +13,10 @@ func (v *VTerm) scrollUp(n int) {
 	if n > regionHeight {
 		n = regionHeight
 	}
+	if left, right, ok := v.partialMargins(); ok {
+		v.scrollRect(v.ScrollTop, v.ScrollBottom, left, right, n)
+		return
+	}
 
 	// Capture lines to scrollback (skip alt screen unless explicitly enabled;
 	// only a top-anchored region feeds scrollback per xterm/DEC semantics —
@@ -75,6 +79,10 @@ func (v *VTerm) scrollDown(n int) {
 	if n > regionHeight {
 		n = regionHeight
 	}
+	if left, right, ok := v.partialMargins(); ok {
+		v.scrollRect(v.ScrollTop, v.ScrollBottom, left, right, -n)
+		return
+	}
 
 	// Shift screen content down within scroll region
 	for i := v.ScrollBottom - 1; i >= v.ScrollTop+n; i-- {
@@ -0,0 +1,261 @@
+package vterm
+
+import "unicode"
+
+// Scrollback search. A query is matched row by row against scrollback plus
+// the visible screen; lowercase queries match case-insensitively (smart
+// case). Only the current match is tracked between calls, by absolute
+// position, so new output never invalidates state: visible matches are
+// recomputed for each snapshot from the rows on screen, and stepping rescans
+// the whole buffer.
+
+// SearchSpan is one visible search match: columns StartX through EndX of
+// viewport row Y. Current marks the match the search is positioned on.
+type SearchSpan struct {
+	Y, StartX, EndX int
+	Current         bool
+}
+
+type searchMatch struct {
+	line, startX, endX int
+}
+
+func (m searchMatch) before(line, x int) bool {
+	return m.line < line || (m.line == line && m.startX < x)
+}
+
+type searchState struct {
+	query   []rune
+	fold    bool
+	current searchMatch // line < 0 when there is no current match
+	index   int         // 1-based position of current among all matches
+	total   int
+}
+
+// SetSearch starts a search for query, positions it on the newest match, and
+// scrolls that match into view. It returns the number of matches. An empty
+// query clears the search.
+func (v *VTerm) SetSearch(query string) int {
+	if query == "" {
+		v.ClearSearch()
+		return 0
+	}
+	runes := []rune(query)
+	fold := true
+	for _, r := range runes {
+		if unicode.IsUpper(r) {
+			fold = false
+			break
+		}
+	}
+	v.search = searchState{query: runes, fold: fold, current: searchMatch{line: -1}}
+	matches := v.allSearchMatches()
+	if len(matches) > 0 {
+		v.setSearchCurrent(matches, len(matches)-1)
+	}
+	v.searchChanged()
+	return len(matches)
+}
+
+// ClearSearch drops the search and its highlights.
+func (v *VTerm) ClearSearch() {
+	if v.search.query == nil {
+		return
+	}
+	v.search = searchState{}
+	v.searchChanged()
+}
+
+// SearchActive reports whether a search query is set.
+func (v *VTerm) SearchActive() bool {
+	return len(v.search.query) > 0
+}
+
+// SearchQuery returns the active query, or "".
+func (v *VTerm) SearchQuery() string {
+	return string(v.search.query)
+}
+
+// SearchStatus returns the 1-based index of the current match and the match
+// count as of the last SetSearch or StepSearch.
+func (v *VTerm) SearchStatus() (index, total int) {
+	return v.search.index, v.search.total
+}
+
+// StepSearch moves to the next newer (dir > 0) or older (dir < 0) match,
+// wrapping around, and scrolls it into view. It reports whether any match
+// exists.
+func (v *VTerm) StepSearch(dir int) bool {
+	if !v.SearchActive() {
+		return false
+	}
+	matches := v.allSearchMatches()
+	if len(matches) == 0 {
+		v.search.current = searchMatch{line: -1}
+		v.search.index, v.search.total = 0, 0
+		v.searchChanged()
+		return false
+	}
+	cur := v.search.current
+	idx := -1
+	if dir >= 0 {
+		idx = 0
+		for i, m := range matches {
+			if cur.line >= 0 && cur.before(m.line, m.startX) {
+				idx = i
+				break
+			}
+		}
+	} else {
+		idx = len(matches) - 1
+		for i := len(matches) - 1; i >= 0; i-- {
+			if cur.line >= 0 && matches[i].before(cur.line, cur.startX) {
+				idx = i
+				break
+			}
+		}
+	}
+	v.setSearchCurrent(matches, idx)
+	v.searchChanged()
+	return true
+}
+
+// searchChanged invalidates every rendered row, since highlights can move
+// anywhere in the view.
+func (v *VTerm) searchChanged() {
+	v.renderGlobalEpoch = v.bumpRenderEpoch()
+	v.bumpVersion()
+}
+
+func (v *VTerm) setSearchCurrent(matches []searchMatch, idx int) {
+	v.search.current = matches[idx]
+	v.search.index = idx + 1
+	v.search.total = len(matches)
+	v.scrollLineIntoView(matches[idx].line)
+}
+
+// scrollLineIntoView adjusts ViewOffset so the absolute line is visible,
+// centering it when it has to scroll.
+func (v *VTerm) scrollLineIntoView(line int) {
+	if v.AbsoluteLineToScreenY(line) >= 0 {
+		return
+	}
+	screen, scrollbackLen := v.RenderBuffers()
+	total := scrollbackLen + len(screen)
+	v.ScrollViewTo(total - v.Height - (line - v.Height/2))
+}
+
+// shiftSearchAfterTrim keeps the current match's absolute line in step with
+// scrollback trimming.
+func (v *VTerm) shiftSearchAfterTrim(trimmed int) {
+	if v.search.current.line < 0 {
+		return
+	}
+	v.search.current.line -= trimmed
+	if v.search.current.line < 0 {
+		v.search.current = searchMatch{line: -1}
+	}
+}
+
+func (v *VTerm) allSearchMatches() []searchMatch {
+	screen, scrollbackLen := v.RenderBuffers()
+	var matches []searchMatch
+	var scratch searchScratch
+	for line := 0; line < scrollbackLen+len(screen); line++ {
+		matches = scratch.appendRowMatches(matches, v.LineCells(line), line, v.search)
+	}
+	return matches
+}
+
+// AppendVisibleSearchMatches appends the search matches on the rows currently
+// in view to dst, in row then column order.
+func (v *VTerm) AppendVisibleSearchMatches(dst []SearchSpan) []SearchSpan {
+	if !v.SearchActive() {
+		return dst
+	}
+	for y := 0; y < v.Height; y++ {
+		dst = v.appendRowSearchMatches(dst, y)
+	}
+	return dst
+}
+
+// appendRowSearchMatches appends the search matches on viewport row y to dst.
+func (v *VTerm) appendRowSearchMatches(dst []SearchSpan, y int) []SearchSpan {
+	if !v.SearchActive() {
+		return dst
+	}
+	s := &v.searchScratch
+	line := v.ScreenYToAbsoluteLine(y)
+	s.found = s.appendRowMatches(s.found[:0], v.LineCells(line), line, v.search)
+	for _, m := range s.found {
+		dst = append(dst, SearchSpan{
+			Y:       y,
+			StartX:  m.startX,
+			EndX:    m.endX,
+			Current: m == v.search.current,
+		})
+	}
+	return dst
+}
+
+// rowSearchHighlighter returns a highlighter for viewport row y backed by the
+// terminal's scratch buffer, which the next call reuses.
+func (v *VTerm) rowSearchHighlighter(y int) SearchHighlighter {
+	v.searchScratch.spans = v.appendRowSearchMatches(v.searchScratch.spans[:0], y)
+	return NewSearchHighlighter(v.searchScratch.spans)
+}
+
+// searchScratch holds the per-row text, column map, and results reused
+// across rows.
+type searchScratch struct {
+	text  []rune
+	cols  []int
+	found []searchMatch
+	spans []SearchSpan
+}
+
+func (s *searchScratch) appendRowMatches(dst []searchMatch, row []Cell, line int, st searchState) []searchMatch {
+	if len(row) == 0 || len(st.query) == 0 {
+		return dst
+	}
+	s.text, s.cols = s.text[:0], s.cols[:0]
+	for x, c := range row {
+		if c.Width == 0 {
+			continue
+		}
+		r := c.Rune
+		if r == 0 {
+			r = ' '
+		}
+		if st.fold {
+			r = unicode.ToLower(r)
+		}
+		s.text = append(s.text, r)
+		s.cols = append(s.cols, x)
+	}
+	n := len(st.query)
+	for i := 0; i+n <= len(s.text); i++ {
+		if !runesEqual(s.text[i:i+n], st.query, st.fold) {
+			continue
+		}
+		end := s.cols[i+n-1]
+		if row[end].Width == 2 && end+1 < len(row) {
+			end++
+		}
+		dst = append(dst, searchMatch{line: line, startX: s.cols[i], endX: end})
+		i += n - 1
+	}
+	return dst
+}
+
+func runesEqual(text, query []rune, fold bool) bool {
+	for i, q := range query {
+		if fold {
+			q = unicode.ToLower(q)
+		}
+		if text[i] != q {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,91 @@
+package vterm
+
+import "testing"
+
+func TestSearchSmartCase(t *testing.T) {
+	vt := New(20, 3)
+	vt.Write([]byte("Foo foo\r\nFOO"))
+
+	if got := vt.SetSearch("foo"); got != 3 {
+		t.Fatalf("lowercase query matched %d, want 3", got)
+	}
+	if got := vt.SetSearch("Foo"); got != 1 {
+		t.Fatalf("mixed-case query matched %d, want 1", got)
+	}
+	if got := vt.SetSearch(""); got != 0 || vt.SearchActive() {
+		t.Fatalf("empty query should clear the search, got %d active=%v", got, vt.SearchActive())
+	}
+}
+
+func TestSearchStepWrapsAround(t *testing.T) {
+	vt := New(20, 3)
+	vt.Write([]byte("ab ab\r\nab"))
+
+	vt.SetSearch("ab")
+	if index, total := vt.SearchStatus(); index != 3 || total != 3 {
+		t.Fatalf("initial status = %d/%d, want 3/3 (newest match)", index, total)
+	}
+	vt.StepSearch(1)
+	if index, _ := vt.SearchStatus(); index != 1 {
+		t.Fatalf("stepping newer from the newest match = %d, want wrap to 1", index)
+	}
+	vt.StepSearch(-1)
+	if index, _ := vt.SearchStatus(); index != 3 {
+		t.Fatalf("stepping older from the oldest match = %d, want wrap to 3", index)
+	}
+	vt.StepSearch(-1)
+	if index, _ := vt.SearchStatus(); index != 2 {
+		t.Fatalf("stepping older = %d, want 2", index)
+	}
+}
+
+func TestSearchVisibleSpans(t *testing.T) {
+	vt := New(12, 2)
+	vt.Write([]byte("x中文x 中文"))
+
+	vt.SetSearch("中文")
+	spans := vt.AppendVisibleSearchMatches(nil)
+	want := []SearchSpan{
+		{Y: 0, StartX: 1, EndX: 4},
+		{Y: 0, StartX: 7, EndX: 10, Current: true},
+	}
+	if len(spans) != len(want) {
+		t.Fatalf("spans = %+v, want %+v", spans, want)
+	}
+	for i := range want {
+		if spans[i] != want[i] {
+			t.Fatalf("span %d = %+v, want %+v", i, spans[i], want[i])
+		}
+	}
+}
+
+func TestSearchScrollsMatchIntoView(t *testing.T) {
+	vt := New(10, 2)
+	vt.Write([]byte("needle\r\n1\r\n2\r\n3\r\n4"))
+
+	if vt.SetSearch("needle") != 1 {
+		t.Fatal("expected one match in scrollback")
+	}
+	if vt.ViewOffset == 0 {
+		t.Fatal("search should scroll the scrollback match into view")
+	}
+	spans := vt.AppendVisibleSearchMatches(nil)
+	if len(spans) != 1 || !spans[0].Current {
+		t.Fatalf("visible spans = %+v, want the current match", spans)
+	}
+}
+
+func TestSearchFollowsScrollbackTrim(t *testing.T) {
+	vt := New(10, 1)
+	vt.Write([]byte("a\r\nhit\r\nb"))
+
+	vt.SetSearch("hit")
+	vt.shiftSearchAfterTrim(1)
+	if vt.search.current.line != 0 {
+		t.Fatalf("current line after trim = %d, want 0", vt.search.current.line)
+	}
+	vt.shiftSearchAfterTrim(1)
+	if vt.search.current.line != -1 {
+		t.Fatalf("current line after trimming the match = %d, want -1", vt.search.current.line)
+	}
+}
@@ -1,6 +1,9 @@
 package vterm
 
-import "strings"
+import (
+	"slices"
+	"strings"
+)
 
 // HasSelection returns true if there is an active selection.
 func (v *VTerm) HasSelection() bool {
@@ -47,26 +50,7 @@ func (v *VTerm) IsInSelection(x, screenY int) bool {
 		return false
 	}
 
-	// Convert screenY to absolute line number
-	absLine := v.ScreenYToAbsoluteLine(screenY)
-
-	// Normalize selection so start is before end.
-	startX, startLine, endX, endLine := NormalizeSelectionRange(
-		v.selStartX, v.selStartLine, v.selEndX, v.selEndLine)
-
-	if v.selRect {
-		// Rectangular selection: every row in range shares the same column span.
-		if absLine < startLine || absLine > endLine {
-			return false
-		}
-		minX := startX
-		maxX := endX
-		if minX > maxX {
-			minX, maxX = maxX, minX
-		}
-		return x >= minX && x <= maxX
-	}
-	return SelectionContains(startX, startLine, endX, endLine, x, absLine)
+	return v.Selection().Contains(x, v.ScreenYToAbsoluteLine(screenY))
 }
 
 // SetSelection stores selection coordinates for rendering with highlight.
@@ -215,6 +199,7 @@ func (v *VTerm) GetTextRange(startX, startLine, endX, endLine int) string {
 	}
 
 	var result strings.Builder
+	var cols []int
 	for line := startLine; line <= endLine; line++ {
 		row := lineAt(line)
 		if row == nil {
@@ -239,24 +224,26 @@ func (v *VTerm) GetTextRange(startX, startLine, endX, endLine int) string {
 			xEnd = xStart
 		}
 
-		for x := xStart; x <= xEnd; x++ {
-			if x < len(row) {
-				if row[x].Width == 0 {
-					continue
-				}
-				if g := row[x].GraphemeCluster; g != "" {
-					result.WriteString(g)
-					continue
-				}
-				r := row[x].Rune
-				if r == 0 {
-					r = ' '
-				}
-				result.WriteRune(r)
+		// Columns are visual; a row with right-to-left text maps the
+		// selected columns back to logical cells and copies those in logical
+		// order, which is the order the text was written in.
+		if order := VisualOrder(row); order != nil {
+			cols = cols[:0]
+			for x := xStart; x <= xEnd && x < len(order); x++ {
+				cols = append(cols, order[x])
+			}
+			slices.Sort(cols)
+			for _, x := range cols {
+				writeSelectedCell(&result, row[x])
+			}
+		} else {
+			for x := xStart; x <= xEnd && x < len(row); x++ {
+				writeSelectedCell(&result, row[x])
 			}
 		}
 
-		if line < endLine {
+		// A soft-wrapped row continues the same logical line.
+		if line < endLine && !rowWrapped(row, width) {
 			result.WriteRune('\n')
 		}
 	}
@@ -268,6 +255,23 @@ func (v *VTerm) GetTextRange(startX, startLine, endX, endLine int) string {
 	return strings.Join(lines, "\n")
 }
 
+// writeSelectedCell appends one cell's text, skipping wide-character
+// continuation cells.
+func writeSelectedCell(b *strings.Builder, cell Cell) {
+	if cell.Width == 0 {
+		return
+	}
+	if g := cell.GraphemeCluster; g != "" {
+		b.WriteString(g)
+		return
+	}
+	r := cell.Rune
+	if r == 0 {
+		r = ' '
+	}
+	b.WriteRune(r)
+}
+
 // LineCells returns the cell slice for an absolute line index in scrollback+screen.
 func (v *VTerm) LineCells(line int) []Cell {
 	if v == nil {
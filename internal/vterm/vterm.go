@@ -46,15 +46,23 @@ type VTerm struct {
 	// the first attached clear-screen redraw so it is not re-captured as new
 	// scrollback.
 	altScreenRestorePending [][]Cell
-	altScreenBuf            [][]Cell
-	altCursorX              int
-	altCursorY              int
+	// peekPrimary shows the parked primary screen instead of the alternate
+	// screen (alt_screen_peek.go).
+	peekPrimary  bool
+	altScreenBuf [][]Cell
+	altCursorX   int
+	altCursorY   int
 
 	// Scrolling region (for DECSTBM)
 	ScrollTop    int
 	ScrollBottom int
 	// Origin mode (DECOM) - cursor positions are relative to scroll region.
 	OriginMode bool
+	// Left/right margins (DECSLRM, margins.go); only honored while
+	// lrMarginMode (DECLRMM) is set. marginRight is exclusive.
+	lrMarginMode bool
+	marginLeft   int
+	marginRight  int
 
 	// Mouse reporting modes requested by the hosted terminal application.
 	mouseTrackingMode int
@@ -67,6 +75,13 @@ type VTerm struct {
 	SavedCursorX int
 	SavedCursorY int
 	SavedStyle   Style
+	// savedOriginMode is the DECOM state DECSC saves with the cursor.
+	savedOriginMode bool
+
+	// G0/G1 charset designations and shift state (charset.go), plus the
+	// copy DECSC saves alongside the cursor.
+	charsets      charsetState
+	savedCharsets charsetState
 
 	// Parser state
 	parser *Parser
@@ -78,6 +93,8 @@ type VTerm struct {
 	oscTitle         string
 	oscWorkingDir    string
 	pendingClipboard []byte
+	// Output marks for copying the latest command's output (marks.go).
+	marks outputMarks
 
 	// Selection state for copy/paste highlighting
 	// Uses absolute line numbers (0 = first scrollback line)
@@ -86,6 +103,10 @@ type VTerm struct {
 	selEndX, selEndLine     int
 	selRect                 bool
 
+	// Scrollback search (search.go).
+	search        searchState
+	searchScratch searchScratch
+
 	// Cursor visibility (controlled externally when pane is focused)
 	ShowCursor     bool
 	lastShowCursor bool
@@ -123,8 +144,14 @@ type VTerm struct {
 	// already scrolled or interacted with scrollback during sync output.
 	syncPreserveViewport bool
 
-	// Render cache for live screen (ViewOffset == 0)
-	renderCache []string
+	// Render cache for live screen (ViewOffset == 0): encoded bytes per line
+	// plus the content hash they were encoded from (see cachedLine).
+	renderCache    [][]byte
+	renderLineHash []uint64
+	// renderScratch is reused across Render calls to assemble the frame.
+	renderScratch []byte
+	// bidiScratch holds the visual column order of the row being rendered.
+	bidiScratch []int
 	// Epoch-based dirty tracking (see cache.go): a line is dirty when its
 	// epoch (or the global epoch) is newer than the last clear.
 	renderEpoch        uint64
@@ -148,6 +175,7 @@ func New(width, height int) *VTerm {
 	}
 	v.Screen = v.makeScreen(width, height)
 	v.Scrollback = make([][]Cell, 0, MaxScrollback)
+	v.marks = unsetOutputMarks()
 	v.parser = NewParser(v)
 	// Initialize dirty tracking for layer-based rendering
 	v.ensureRenderCache(height)
@@ -220,6 +248,9 @@ func (v *VTerm) resize(width, height int, revealHistoryOnGrow bool) {
 		return
 	}
 
+	// Rewrap soft-wrapped text to the new width before adjusting height.
+	v.reflow(width)
+
 	// If height shrinks, move lines to scrollback
 	if height < oldHeight && v.scrollbackEnabled() {
 		overflow := oldHeight - height
@@ -385,6 +416,8 @@ func (v *VTerm) trimScrollback() {
 		trimmed := len(v.Scrollback) - MaxScrollback
 		v.Scrollback = v.Scrollback[len(v.Scrollback)-MaxScrollback:]
 		v.shiftSelectionAfterTrim(trimmed)
+		v.shiftSearchAfterTrim(trimmed)
+		v.shiftMarks(-trimmed)
 	}
 	// Clamp ViewOffset after trim to prevent stale offsets
 	v.clampViewOffsetToCurrentMax()
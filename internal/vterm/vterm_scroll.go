@@ -208,6 +208,7 @@ func (v *VTerm) PrependScrollbackWithSize(data []byte, width, height int) {
 	newScrollback = append(newScrollback, lines...)
 	newScrollback = append(newScrollback, v.Scrollback...)
 	v.Scrollback = newScrollback
+	v.shiftMarks(len(lines))
 	v.trimScrollback()
 }
 
@@ -357,6 +358,7 @@ func (v *VTerm) loadPaneCaptureWithCursor(
 	v.applyPaneModeState(modeState)
 	v.ClearSelection()
 	v.ViewOffset = 0
+	v.marks = unsetOutputMarks()
 	v.Scrollback = v.Scrollback[:0]
 	if tmp != nil {
 		for _, line := range tmp.Scrollback {